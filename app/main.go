@@ -12,14 +12,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/pressly/goose/v3"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
 
+	"request-system/internal/grpcapi"
 	"request-system/internal/listeners"
 	"request-system/internal/repositories"
 	"request-system/internal/routes"
@@ -27,7 +28,11 @@ import (
 	"request-system/pkg/config"
 	"request-system/pkg/database/postgresql"
 	"request-system/pkg/eventbus"
+	"request-system/pkg/eventstream"
+	"request-system/pkg/jobs"
+	"request-system/pkg/ldappool"
 	"request-system/pkg/logger"
+	appmiddleware "request-system/pkg/middleware"
 	"request-system/pkg/service"
 	"request-system/pkg/telegram"
 	"request-system/pkg/validation"
@@ -57,6 +62,7 @@ func main() {
 	importAtms := flag.String("import-atms", "", "Путь к файлу банкоматов .xlsx")
 	importTerms := flag.String("import-terms", "", "Путь к файлу терминалов .xlsx")
 	importPos := flag.String("import-pos", "", "Путь к файлу ПОС-терминалов .xlsx")
+	skipMigrations := flag.Bool("skip-migrations", false, "Не применять миграции при старте (то же, что DB_AUTO_MIGRATE=false)")
 
 	flag.Parse()
 
@@ -66,7 +72,7 @@ func main() {
 	// 3. БЛОК СИДЕРОВ И ИМПОРТА (Работает как сидер, если есть хоть один флаг)
 	if *runCore || *runRoles || *runAll || *importAtms != "" || *importTerms != "" || *importPos != "" {
 		log.Println("🛠️ ЗАПУСК ОПЕРАЦИИ СИДИРОВАНИЯ/ИМПОРТА...")
-		dbPool := postgresql.ConnectDB(cfg.Postgres.DSN)
+		dbPool := postgresql.ConnectDB(cfg.Postgres, nil)
 		defer dbPool.Close()
 
 		// Сидеры (Базовые данные)
@@ -129,21 +135,13 @@ func main() {
 	if err != nil {
 		panic("Не удалось создать логгер")
 	}
+	if err := cfg.Portal.Validate(); err != nil {
+		mainLogger.Fatal("Портал нельзя включить без настоящей капчи", zap.Error(err))
+	}
 
 	// Миграции (Goose)
-	mainLogger.Info("Запуск миграций Goose...")
-	dbGoose, err := sql.Open("pgx", cfg.Postgres.DSN)
-	if err != nil {
-		mainLogger.Fatal("Ошибка соединения для миграций", zap.Error(err))
-	}
-	defer dbGoose.Close()
+	runMigrations(cfg.Postgres, *skipMigrations, mainLogger)
 
-	if err := goose.SetDialect("postgres"); err != nil {
-		mainLogger.Fatal("Goose dialect setup failed", zap.Error(err))
-	}
-	if err := goose.Up(dbGoose, "./database/migrations"); err != nil {
-		mainLogger.Fatal("Goose migrations failed", zap.Error(err))
-	}
 	authLogger, _ := logger.CreateLogger(logLevel, "auth")
 	orderLogger, _ := logger.CreateLogger(logLevel, "orders")
 	userLogger, _ := logger.CreateLogger(logLevel, "users")
@@ -154,7 +152,14 @@ func main() {
 	// Настройка Echo
 	e := echo.New()
 	e.HideBanner = true
+	// IP клиента нужен лимитам запросов, аудиту и порталу; заголовкам верим только от своих прокси
+	ipExtractor, err := appmiddleware.NewIPExtractor(cfg.Server.TrustedProxies)
+	if err != nil {
+		mainLogger.Fatal("Ошибка настройки доверенных прокси", zap.Error(err))
+	}
+	e.IPExtractor = ipExtractor
 	e.Use(middleware.Recover())
+	e.Use(middleware.RequestID())
 	e.GET("/ping", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 	})
@@ -162,15 +167,31 @@ func main() {
 	// CORS: Разрешаем куки и заголовки
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     cfg.Server.AllowedOrigins, // Берется из .env (исправленного на Шаге 1)
-		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodHead},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Requested-With", "ngrok-skip-browser-warning"},
+		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions, http.MethodHead},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderXRequestID, "X-Requested-With", "ngrok-skip-browser-warning"},
+		ExposeHeaders:    []string{echo.HeaderXRequestID, "X-Impersonated-By"},
 		AllowCredentials: true,
 	}))
 
 	e.Validator = validation.New()
 
-	dbConn := postgresql.ConnectDB(cfg.Postgres.DSN)
+	queryMetrics := postgresql.NewQueryMetrics()
+	queryTracer := postgresql.NewQueryTracer(cfg.Postgres.SlowQueryThreshold, queryMetrics, mainLogger.Named("SQL"))
+
+	dbConn := postgresql.ConnectDB(cfg.Postgres, queryTracer)
 	defer dbConn.Close()
+
+	// Реплика для тяжёлых чтений необязательна: без неё или при её сбое всё читается из основной БД
+	var replicaConn *pgxpool.Pool
+	if cfg.Postgres.ReplicaDSN != "" {
+		replicaConn, err = postgresql.ConnectReplica(context.Background(), cfg.Postgres, queryTracer)
+		if err != nil {
+			mainLogger.Error("Реплика БД не подключена, чтения пойдут в основную БД", zap.Error(err))
+		} else {
+			defer replicaConn.Close()
+		}
+	}
+	readRouter := postgresql.NewReadRouter(dbConn, replicaConn, cfg.Postgres.ReplicaMaxLag, mainLogger.Named("ReadRouter"))
 	e.Static("/uploads", "uploads")
 
 	redisClient := redis.NewClient(&redis.Options{Addr: cfg.Redis.Address, Password: cfg.Redis.Password})
@@ -178,31 +199,155 @@ func main() {
 	jwtSvc := service.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL, authLogger)
 	permissionRepo := repositories.NewPermissionRepository(dbConn, mainLogger)
 	cacheRepo := repositories.NewRedisCacheRepository(redisClient)
-	authPermissionService := services.NewAuthPermissionService(permissionRepo, cacheRepo, authLogger, 10*time.Minute)
+	// Один справочник статусов на процесс: его читают бот, слушатели и OrderService
+	statusDirectory := services.NewStatusDirectory(repositories.NewStatusRepository(dbConn), redisClient, mainLogger.Named("StatusDirectory"))
+	authPermissionService := services.NewAuthPermissionService(permissionRepo, repositories.NewUserRepository(dbConn, userLogger), cacheRepo, authLogger, 10*time.Minute)
 
 	bus := eventbus.New(mainLogger)
 	wsHub := websocket.NewHub()
+	wsHub.SetMaxConnectionsPerUser(cfg.WebSocket.MaxConnectionsPerUser)
+	if cfg.WebSocket.ReplayMaxEvents > 0 {
+		wsHub.EnableReplay(redisClient, cfg.WebSocket.ReplayMaxEvents, cfg.WebSocket.ReplayTTL)
+	}
+	if cfg.WebSocket.RedisFanout {
+		wsHub.EnableRedisFanout(redisClient, cfg.WebSocket.RedisChannel)
+		mainLogger.Info("WebSocket: включена рассылка между репликами через Redis", zap.String("channel", cfg.WebSocket.RedisChannel))
+	}
+
+	if cfg.Server.MetricsEnabled {
+		e.GET("/metrics", func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+			if err := queryMetrics.WritePrometheus(c.Response()); err != nil {
+				return err
+			}
+			if err := telegram.WritePrometheus(c.Response()); err != nil {
+				return err
+			}
+			return wsHub.WritePrometheus(c.Response())
+		})
+	}
 
-	tgService := telegram.NewService(cfg.Telegram.BotToken)
+	tgService := telegram.NewService(cfg.Telegram.BotToken, cfg.Telegram.Send)
 	notificationService := services.NewTelegramNotificationService(tgService, mainLogger)
 	wsNotificationService := services.NewWebSocketNotificationService(wsHub, mainLogger.Named("WebSocketNotifier"))
 
-	notificationListener := listeners.NewNotificationListener(
+	notificationOutboxService := services.NewNotificationOutboxService(
+		repositories.NewNotificationOutboxRepository(dbConn, mainLogger),
 		notificationService, wsNotificationService,
 		repositories.NewUserRepository(dbConn, userLogger),
-		repositories.NewStatusRepository(dbConn),
+		mainLogger.Named("NotificationOutbox"),
+	)
+
+	notificationListener := listeners.NewNotificationListener(
+		notificationOutboxService,
+		services.NewNotificationCenterService(repositories.NewUserNotificationRepository(dbConn, mainLogger), mainLogger),
+		repositories.NewUserRepository(dbConn, userLogger),
+		repositories.NewNotificationPreferenceRepository(dbConn, mainLogger),
+		statusDirectory,
 		repositories.NewPriorityRepository(dbConn, mainLogger),
-		cfg.Frontend, cfg.Server, mainLogger.Named("NotificationListener"),
+		cfg.Frontend, cfg.Server, cfg.Notifications, mainLogger.Named("NotificationListener"),
 	)
 	notificationListener.Register(bus)
+	listeners.NewOrderRoomListener(wsNotificationService, cfg.Server.BaseURL, mainLogger.Named("OrderRoomListener")).Register(bus)
+	listeners.NewDashboardCacheListener(cacheRepo, mainLogger.Named("DashboardCacheListener")).Register(bus)
+	orderListViewRepo := repositories.NewOrderListViewRepository(dbConn, mainLogger)
+	listeners.NewOrderListCacheListener(cacheRepo, orderListViewRepo, mainLogger.Named("OrderListCacheListener")).Register(bus)
+	orderListViewService := services.NewOrderListViewService(orderListViewRepo, cacheRepo,
+		cfg.Orders.ListViewReconcileInterval, mainLogger.Named("OrderListView"))
+	listeners.NewPermissionCacheListener(authPermissionService, mainLogger.Named("PermissionCacheListener")).Register(bus)
+
+	webhookAllowedNetworks, err := services.ParseWebhookAllowedNetworks(cfg.Integrations.WebhookAllowedNetworks)
+	if err != nil {
+		mainLogger.Fatal("Ошибка настройки разрешённых сетей webhook", zap.Error(err))
+	}
+	webhookService := services.NewWebhookService(
+		repositories.NewWebhookRepository(dbConn, mainLogger),
+		repositories.NewUserRepository(dbConn, userLogger),
+		webhookAllowedNetworks,
+		mainLogger.Named("Webhooks"),
+	)
+	listeners.NewWebhookListener(webhookService, mainLogger.Named("WebhookListener")).Register(bus)
+
+	chatConnectorService := services.NewChatConnectorService(
+		repositories.NewChatChannelRepository(dbConn, mainLogger),
+		repositories.NewUserRepository(dbConn, userLogger),
+		cfg.Frontend,
+		services.NewBusinessCalendarService(repositories.NewBusinessCalendarRepository(dbConn, mainLogger),
+			repositories.NewUserRepository(dbConn, userLogger), mainLogger.Named("BusinessCalendar")),
+		mainLogger.Named("ChatConnector"),
+	)
+	listeners.NewChatConnectorListener(chatConnectorService, mainLogger.Named("ChatConnectorListener")).Register(bus)
 
-	adService := services.NewADService(&cfg.LDAP, mainLogger)
+	eventOutboxDispatcher := services.NewEventOutboxDispatcher(
+		repositories.NewEventOutboxRepository(dbConn, mainLogger),
+		repositories.NewTxManager(dbConn, mainLogger),
+		repositories.NewUserRepository(dbConn, userLogger),
+		bus, mainLogger.Named("EventOutbox"),
+	)
+
+	orderHistoryPartitionService := services.NewOrderHistoryPartitionService(
+		repositories.NewOrderHistoryPartitionRepository(dbConn, mainLogger),
+		mainLogger.Named("OrderHistoryPartitions"),
+	)
+
+	dailyOrderStatsService := services.NewDailyOrderStatsService(
+		repositories.NewDailyOrderStatsRepository(dbConn, mainLogger),
+		mainLogger.Named("DailyOrderStats"),
+	)
+
+	ldapPool, err := ldappool.New(&cfg.LDAP, mainLogger.Named("LDAP"))
+	if err != nil {
+		mainLogger.Fatal("Ошибка настройки LDAP", zap.Error(err))
+	}
+	adService := services.NewADService(&cfg.LDAP, ldapPool, mainLogger)
 
 	appCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	go wsHub.Run(appCtx)
 
-	routes.InitRouter(e, dbConn, redisClient, jwtSvc, appLoggers, authPermissionService, cfg, bus, wsHub, adService, appCtx)
+	var streamPublisher eventstream.Publisher
+	switch cfg.EventStream.Driver {
+	case "":
+	case eventstream.DriverKafka:
+		streamPublisher = eventstream.NewKafkaPublisher(cfg.EventStream.KafkaBrokers, cfg.EventStream.KafkaAutoCreateTopics)
+	case eventstream.DriverNATS:
+		streamPublisher, err = eventstream.NewNATSPublisher(appCtx, cfg.EventStream.NATSURL, cfg.EventStream.NATSStream, cfg.EventStream.TopicPrefix)
+		if err != nil {
+			mainLogger.Error("Зеркалирование событий в NATS отключено", zap.Error(err))
+		}
+	default:
+		mainLogger.Warn("Неизвестный EVENT_STREAM_DRIVER, зеркалирование событий отключено", zap.String("driver", cfg.EventStream.Driver))
+	}
+	if streamPublisher != nil {
+		mirror := eventstream.NewMirror(streamPublisher, cfg.EventStream.TopicPrefix, cfg.EventStream.BufferSize, mainLogger.Named("EventStream"))
+		mirror.Register(bus)
+		go mirror.Run(appCtx)
+	}
+
+	go wsHub.Run(appCtx)
+	go statusDirectory.Start(appCtx)
+	go orderListViewService.Start(appCtx)
+	go orderHistoryPartitionService.Start(appCtx)
+	go notificationOutboxService.StartWorker(appCtx)
+	go eventOutboxDispatcher.Start(appCtx)
+	go webhookService.StartWorker(appCtx)
+	go chatConnectorService.StartSLAWatcher(appCtx)
+	go dailyOrderStatsService.Start(appCtx)
+	go readRouter.Start(appCtx, cfg.Postgres.ReplicaCheckInterval)
+
+	// Фоновые задания: обработчики регистрируются до Start, очередь общая для всех реплик
+	jobQueue := jobs.New(redisClient, mainLogger.Named("Jobs"))
+
+	appServices := routes.InitRouter(e, dbConn, readRouter, redisClient, jwtSvc, appLoggers, authPermissionService, statusDirectory, cfg, bus, wsHub, adService, jobQueue, appCtx)
+	jobQueue.Start(appCtx)
+
+	if cfg.GRPC.Enabled {
+		grpcServer, err := grpcapi.NewServer(cfg.GRPC, cfg.Server, appServices.Order, appServices.User, jwtSvc, authPermissionService,
+			repositories.NewUserRepository(dbConn, userLogger), mainLogger.Named("gRPC"))
+		if err != nil {
+			mainLogger.Fatal("🔴 Ошибка настройки gRPC", zap.Error(err))
+		}
+		go grpcServer.Start(appCtx)
+	}
 
 	serverAddress := ":" + cfg.Server.Port
 	certPath := cfg.Server.CertFile
@@ -237,7 +382,59 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
-	if err := e.Shutdown(shutdownCtx); err != nil {
+	// HTTP-сервер запущен через s, а не через e.Start, поэтому останавливать нужно его:
+	// e.Shutdown не ждал бы обработчики, которые ещё выполняются
+	if err := s.Shutdown(shutdownCtx); err != nil {
 		mainLogger.Error("Error shutdown", zap.Error(err))
 	}
+	// Сначала дорабатывают начатые обновления Telegram (они порождают события),
+	// затем уходят группы уведомлений, ещё ждущие своего таймера
+	if err := appServices.TelegramBot.Drain(shutdownCtx); err != nil {
+		mainLogger.Warn("Остановка: не все обновления Telegram обработаны", zap.Error(err))
+	}
+	if err := notificationListener.Drain(shutdownCtx); err != nil {
+		mainLogger.Warn("Остановка: не все уведомления отправлены", zap.Error(err))
+	}
+	if err := jobQueue.Shutdown(shutdownCtx); err != nil {
+		mainLogger.Warn("Остановка фоновых заданий", zap.Error(err))
+	}
+}
+
+// runMigrations применяет миграции при старте или, если автомиграция выключена, только
+// сообщает о неприменённых: схему тогда обновляют отдельным шагом деплоя.
+func runMigrations(cfg config.PostgresConfig, skip bool, logger *zap.Logger) {
+	dbGoose, err := sql.Open("pgx", cfg.DSN)
+	if err != nil {
+		logger.Fatal("Ошибка соединения для миграций", zap.Error(err))
+	}
+	defer dbGoose.Close()
+
+	migrator, err := postgresql.NewMigrator(dbGoose, postgresql.MigrationsDir)
+	if err != nil {
+		logger.Fatal("Не удалось прочитать миграции", zap.Error(err))
+	}
+	ctx := context.Background()
+
+	if skip || !cfg.AutoMigrate {
+		current, latest, err := migrator.GetVersions(ctx)
+		if err != nil {
+			logger.Fatal("Не удалось получить версию схемы БД", zap.Error(err))
+		}
+		if current < latest {
+			logger.Warn("Автомиграция выключена, в БД не применены миграции",
+				zap.Int64("version", current), zap.Int64("latest", latest))
+			return
+		}
+		logger.Info("Автомиграция выключена, схема БД актуальна", zap.Int64("version", current))
+		return
+	}
+
+	logger.Info("Запуск миграций Goose...")
+	results, err := migrator.Up(ctx)
+	if err != nil {
+		logger.Fatal("Goose migrations failed", zap.Error(err))
+	}
+	for _, result := range results {
+		logger.Info("Миграция применена", zap.String("file", result.Source.Path), zap.Duration("took", result.Duration))
+	}
 }
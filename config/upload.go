@@ -16,6 +16,17 @@ var UploadContexts = map[string]UploadConfig{
 		MaxSizeMB:        20,
 		PathPrefix:       "avatars",
 	},
+	// Аватар из POST /api/me/avatar: сервер сам уменьшает его и сохраняет копии,
+	// поэтому допустимы только форматы, которые умеет декодировать стандартная библиотека.
+	"avatar": {
+		AllowedMimeTypes: []string{"image/jpeg", "image/png", "image/gif"},
+		MaxSizeMB:        10,
+		MinWidth:         32,
+		MinHeight:        32,
+		MaxWidth:         8000,
+		MaxHeight:        8000,
+		PathPrefix:       "avatars",
+	},
 	"order_document": {
 		AllowedMimeTypes: []string{
 			"image/jpeg", "image/png", "application/pdf", "image/jpg", "application/msword", "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding users.language for bot localization';
+
+-- Язык интерфейса Telegram-бота и уведомлений: tg / ru / en.
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS language VARCHAR(5) NOT NULL DEFAULT 'ru';
+
+ALTER TABLE public.users
+    DROP CONSTRAINT IF EXISTS chk_users_language;
+
+ALTER TABLE public.users
+    ADD CONSTRAINT chk_users_language CHECK (language IN ('ru', 'tg', 'en'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping users.language';
+
+ALTER TABLE public.users DROP CONSTRAINT IF EXISTS chk_users_language;
+ALTER TABLE public.users DROP COLUMN IF EXISTS language;
+-- +goose StatementEnd
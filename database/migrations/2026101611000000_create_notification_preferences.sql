@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating notification_preferences table';
+
+-- Персональные настройки уведомлений. Отсутствие строки = настройки по умолчанию
+-- (все каналы включены, без тихих часов, доставка сразу).
+CREATE TABLE IF NOT EXISTS public.notification_preferences (
+    user_id            BIGINT PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    telegram_enabled   BOOLEAN     NOT NULL DEFAULT TRUE,
+    websocket_enabled  BOOLEAN     NOT NULL DEFAULT TRUE,
+    muted_event_types  TEXT[]      NOT NULL DEFAULT '{}',
+    quiet_hours_start  TIME        NULL,
+    quiet_hours_end    TIME        NULL,
+    delivery_mode      VARCHAR(16) NOT NULL DEFAULT 'realtime',
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_notification_preferences_delivery_mode CHECK (delivery_mode IN ('realtime', 'digest')),
+    CONSTRAINT chk_notification_preferences_quiet_hours CHECK (
+        (quiet_hours_start IS NULL AND quiet_hours_end IS NULL)
+        OR (quiet_hours_start IS NOT NULL AND quiet_hours_end IS NOT NULL)
+    )
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping notification_preferences table';
+
+DROP TABLE IF EXISTS public.notification_preferences;
+-- +goose StatementEnd
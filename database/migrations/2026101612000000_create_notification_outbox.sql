@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating notification_outbox table';
+
+-- Исходящие уведомления (Telegram/WebSocket). Воркер забирает записи pending/processing
+-- с истёкшим next_attempt_at, при ошибке откладывает повтор, после max_attempts — статус dead.
+CREATE TABLE IF NOT EXISTS public.notification_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT      NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    channel         VARCHAR(16) NOT NULL,
+    chat_id         BIGINT      NULL,
+    message_type    VARCHAR(50) NOT NULL DEFAULT 'notification',
+    payload         JSONB       NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    max_attempts    INT         NOT NULL DEFAULT 8,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT        NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ NULL,
+    CONSTRAINT chk_notification_outbox_channel CHECK (channel IN ('telegram', 'websocket')),
+    CONSTRAINT chk_notification_outbox_status CHECK (status IN ('pending', 'processing', 'sent', 'dead'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_due
+    ON public.notification_outbox (next_attempt_at)
+    WHERE status IN ('pending', 'processing');
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_dead
+    ON public.notification_outbox (updated_at DESC)
+    WHERE status = 'dead';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping notification_outbox table';
+
+DROP TABLE IF EXISTS public.notification_outbox;
+-- +goose StatementEnd
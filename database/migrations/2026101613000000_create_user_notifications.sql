@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating user_notifications table';
+
+-- Центр уведомлений ("колокольчик"): каждое уведомление хранится для конкретного получателя,
+-- чтобы список и признак прочтения переживали перезагрузку страницы.
+CREATE TABLE IF NOT EXISTS public.user_notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    event_id   UUID        NOT NULL,
+    order_id   BIGINT      NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    type       VARCHAR(50) NOT NULL,
+    payload    JSONB       NOT NULL,
+    is_read    BOOLEAN     NOT NULL DEFAULT FALSE,
+    read_at    TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_notifications_user_created
+    ON public.user_notifications (user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_user_notifications_user_unread
+    ON public.user_notifications (user_id)
+    WHERE is_read = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping user_notifications table';
+
+DROP TABLE IF EXISTS public.user_notifications;
+-- +goose StatementEnd
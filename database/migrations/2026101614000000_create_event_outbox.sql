@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating event_outbox table';
+
+-- Доменные события пишутся в той же транзакции, что и изменения заявки, и публикуются
+-- в eventbus только после коммита. Откаченная транзакция не оставляет событий.
+CREATE TABLE IF NOT EXISTS public.event_outbox (
+    id           BIGSERIAL PRIMARY KEY,
+    event_name   VARCHAR(100) NOT NULL,
+    aggregate_id BIGINT       NULL,
+    payload      JSONB        NOT NULL,
+    attempts     INT          NOT NULL DEFAULT 0,
+    last_error   TEXT         NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ  NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_unpublished
+    ON public.event_outbox (id)
+    WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_aggregate
+    ON public.event_outbox (event_name, aggregate_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping event_outbox table';
+
+DROP TABLE IF EXISTS public.event_outbox;
+-- +goose StatementEnd
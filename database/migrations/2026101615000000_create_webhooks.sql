@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating webhook tables';
+
+-- Подписки внешних систем на события заявок. event_types — список событий вида order.created,
+-- secret используется для подписи тела запроса (HMAC-SHA256).
+CREATE TABLE IF NOT EXISTS public.webhook_subscriptions (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(255)  NOT NULL,
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(255)  NOT NULL,
+    event_types TEXT[]        NOT NULL DEFAULT '{}',
+    is_active   BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_by  BIGINT        NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- Доставка одного события одной подписке. Воркер забирает pending/processing с истёкшим
+-- next_attempt_at, после max_attempts — статус failed.
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    subscription_id  BIGINT      NOT NULL REFERENCES public.webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id         UUID        NOT NULL,
+    event_type       VARCHAR(64) NOT NULL,
+    payload          JSONB       NOT NULL,
+    status           VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts         INT         NOT NULL DEFAULT 0,
+    max_attempts     INT         NOT NULL DEFAULT 10,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INT         NULL,
+    last_error       TEXT        NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ NULL,
+    CONSTRAINT uq_webhook_deliveries_event UNIQUE (subscription_id, event_id),
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'processing', 'delivered', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON public.webhook_deliveries (next_attempt_at)
+    WHERE status IN ('pending', 'processing');
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON public.webhook_deliveries (subscription_id, created_at DESC);
+
+-- Журнал каждой попытки отправки: код ответа, ошибка, начало ответа и длительность.
+CREATE TABLE IF NOT EXISTS public.webhook_delivery_attempts (
+    id            BIGSERIAL PRIMARY KEY,
+    delivery_id   BIGINT      NOT NULL REFERENCES public.webhook_deliveries(id) ON DELETE CASCADE,
+    attempt       INT         NOT NULL,
+    status_code   INT         NULL,
+    error         TEXT        NULL,
+    response_body TEXT        NULL,
+    duration_ms   INT         NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery
+    ON public.webhook_delivery_attempts (delivery_id, attempt);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping webhook tables';
+
+DROP TABLE IF EXISTS public.webhook_delivery_attempts;
+DROP TABLE IF EXISTS public.webhook_deliveries;
+DROP TABLE IF EXISTS public.webhook_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating audit_log table';
+
+-- Журнал изменяющих API-вызовов (create/update/delete) по пользователям, ролям, правилам и справочникам.
+-- before/after — снимки строки сущности до и после вызова (без паролей и секретов).
+CREATE TABLE IF NOT EXISTS public.audit_log (
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    BIGINT       NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    action      VARCHAR(16)  NOT NULL,
+    entity      VARCHAR(64)  NOT NULL,
+    entity_id   VARCHAR(64)  NULL,
+    method      VARCHAR(10)  NOT NULL,
+    path        VARCHAR(512) NOT NULL,
+    status_code INT          NOT NULL,
+    before      JSONB        NULL,
+    after       JSONB        NULL,
+    ip          VARCHAR(64)  NULL,
+    user_agent  VARCHAR(512) NULL,
+    request_id  VARCHAR(64)  NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_audit_log_action CHECK (action IN ('create', 'update', 'delete'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON public.audit_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON public.audit_log (entity, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON public.audit_log (actor_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping audit_log table';
+
+DROP TABLE IF EXISTS public.audit_log;
+-- +goose StatementEnd
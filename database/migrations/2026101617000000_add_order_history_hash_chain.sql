@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding hash chain to order_history';
+
+-- Каждая запись истории хранит hash = sha256(prev_hash | payload), prev_hash — хэш предыдущей
+-- записи той же заявки. Голова цепочки хранится в orders.history_hash, чтобы заметить и удаление
+-- последних записей. Записи, созданные до этой миграции, остаются без хэша.
+ALTER TABLE public.order_history
+    ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64) NULL,
+    ADD COLUMN IF NOT EXISTS hash      VARCHAR(64) NULL;
+
+ALTER TABLE public.orders
+    ADD COLUMN IF NOT EXISTS history_hash VARCHAR(64) NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping hash chain from order_history';
+
+ALTER TABLE public.orders DROP COLUMN IF EXISTS history_hash;
+
+ALTER TABLE public.order_history
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating daily_order_stats tables';
+
+-- Суточные агрегаты по заявкам для тяжёлых виджетов дашборда. Строка — один день и одна
+-- комбинация подразделения, типа и приоритета. created_* считаются по дате создания,
+-- closed_*/resolution_*/sla_* — по дате последнего перевода в статус CLOSED.
+CREATE TABLE IF NOT EXISTS public.daily_order_stats (
+    stat_date              DATE    NOT NULL,
+    department_id          BIGINT  NULL,
+    branch_id              BIGINT  NULL,
+    otdel_id               BIGINT  NULL,
+    office_id              BIGINT  NULL,
+    order_type_id          BIGINT  NULL,
+    priority_id            BIGINT  NULL,
+    created_count          BIGINT  NOT NULL DEFAULT 0,
+    closed_count           BIGINT  NOT NULL DEFAULT 0,
+    resolution_seconds_sum BIGINT  NOT NULL DEFAULT 0,
+    resolution_count       BIGINT  NOT NULL DEFAULT 0,
+    sla_eligible           BIGINT  NOT NULL DEFAULT 0,
+    sla_on_time            BIGINT  NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_daily_order_stats_date ON public.daily_order_stats (stat_date);
+CREATE INDEX IF NOT EXISTS idx_daily_order_stats_department ON public.daily_order_stats (department_id, stat_date);
+CREATE INDEX IF NOT EXISTS idx_daily_order_stats_branch ON public.daily_order_stats (branch_id, stat_date);
+
+-- По какую дату включительно агрегаты посчитаны. Дни без заявок строк не дают,
+-- поэтому покрытие хранится отдельно.
+CREATE TABLE IF NOT EXISTS public.daily_order_stats_state (
+    id              SMALLINT    PRIMARY KEY DEFAULT 1,
+    covered_through DATE        NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_daily_order_stats_state_single CHECK (id = 1)
+);
+
+-- Для поиска закрытий за день при пересчёте.
+CREATE INDEX IF NOT EXISTS idx_order_history_status_change_created_at
+    ON public.order_history (created_at)
+    WHERE event_type = 'STATUS_CHANGE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping daily_order_stats tables';
+
+DROP INDEX IF EXISTS public.idx_order_history_status_change_created_at;
+DROP TABLE IF EXISTS public.daily_order_stats_state;
+DROP TABLE IF EXISTS public.daily_order_stats;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating dictionary_versions table';
+
+-- Момент последнего изменения каждого справочника. Обновляется триггерами на любую вставку,
+-- изменение и удаление, поэтому по нему строятся ETag/Last-Modified для GET-справочников
+-- без чтения самих таблиц.
+CREATE TABLE IF NOT EXISTS public.dictionary_versions (
+    name       VARCHAR(64) PRIMARY KEY,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO public.dictionary_versions (name)
+VALUES ('statuses'), ('priorities'), ('departments'), ('branches'), ('order_types')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION public.bump_dictionary_version() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO public.dictionary_versions (name, updated_at)
+    VALUES (TG_TABLE_NAME, clock_timestamp())
+    ON CONFLICT (name) DO UPDATE SET updated_at = EXCLUDED.updated_at;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_statuses_dictionary_version
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public.statuses
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_dictionary_version();
+CREATE TRIGGER trg_priorities_dictionary_version
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public.priorities
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_dictionary_version();
+CREATE TRIGGER trg_departments_dictionary_version
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public.departments
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_dictionary_version();
+CREATE TRIGGER trg_branches_dictionary_version
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public.branches
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_dictionary_version();
+CREATE TRIGGER trg_order_types_dictionary_version
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON public.order_types
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_dictionary_version();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping dictionary_versions table';
+
+DROP TRIGGER IF EXISTS trg_order_types_dictionary_version ON public.order_types;
+DROP TRIGGER IF EXISTS trg_branches_dictionary_version ON public.branches;
+DROP TRIGGER IF EXISTS trg_departments_dictionary_version ON public.departments;
+DROP TRIGGER IF EXISTS trg_priorities_dictionary_version ON public.priorities;
+DROP TRIGGER IF EXISTS trg_statuses_dictionary_version ON public.statuses;
+DROP FUNCTION IF EXISTS public.bump_dictionary_version();
+DROP TABLE IF EXISTS public.dictionary_versions;
+-- +goose StatementEnd
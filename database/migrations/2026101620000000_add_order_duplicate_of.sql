@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding orders.duplicate_of_id';
+
+-- Заявка, закрытая как дубликат, ссылается на основную заявку, в которую перенесены её
+-- вложения, комментарии и участники.
+ALTER TABLE public.orders
+    ADD COLUMN IF NOT EXISTS duplicate_of_id BIGINT NULL REFERENCES public.orders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_duplicate_of_id ON public.orders (duplicate_of_id)
+    WHERE duplicate_of_id IS NOT NULL;
+
+-- Подсказка о возможных дублях при создании ищет свежие заявки по тому же оборудованию.
+CREATE INDEX IF NOT EXISTS idx_orders_equipment_created_at ON public.orders (equipment_id, created_at DESC)
+    WHERE equipment_id IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping orders.duplicate_of_id';
+
+DROP INDEX IF EXISTS public.idx_orders_equipment_created_at;
+DROP INDEX IF EXISTS public.idx_orders_duplicate_of_id;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS duplicate_of_id;
+-- +goose StatementEnd
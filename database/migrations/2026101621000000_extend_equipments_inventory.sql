@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: extending equipments into inventory';
+
+-- Инвентарные данные оборудования: серийный номер, за кем закреплено, даты покупки и гарантии,
+-- состояние жизненного цикла (в работе / в ремонте / списано).
+ALTER TABLE public.equipments
+    ADD COLUMN IF NOT EXISTS serial_number    VARCHAR(128) NULL,
+    ADD COLUMN IF NOT EXISTS assigned_user_id BIGINT       NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS purchase_date    DATE         NULL,
+    ADD COLUMN IF NOT EXISTS warranty_until   DATE         NULL,
+    ADD COLUMN IF NOT EXISTS state            VARCHAR(16)  NOT NULL DEFAULT 'in_service',
+    ADD COLUMN IF NOT EXISTS state_changed_at TIMESTAMPTZ  NULL;
+
+ALTER TABLE public.equipments
+    ADD CONSTRAINT chk_equipments_state CHECK (state IN ('in_service', 'repair', 'written_off'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_equipments_serial_number_unique ON public.equipments (serial_number)
+    WHERE serial_number IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_equipments_assigned_user_id ON public.equipments (assigned_user_id)
+    WHERE assigned_user_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping equipments inventory columns';
+
+DROP INDEX IF EXISTS public.idx_equipments_assigned_user_id;
+DROP INDEX IF EXISTS public.idx_equipments_serial_number_unique;
+ALTER TABLE public.equipments DROP CONSTRAINT IF EXISTS chk_equipments_state;
+ALTER TABLE public.equipments
+    DROP COLUMN IF EXISTS state_changed_at,
+    DROP COLUMN IF EXISTS state,
+    DROP COLUMN IF EXISTS warranty_until,
+    DROP COLUMN IF EXISTS purchase_date,
+    DROP COLUMN IF EXISTS assigned_user_id,
+    DROP COLUMN IF EXISTS serial_number;
+-- +goose StatementEnd
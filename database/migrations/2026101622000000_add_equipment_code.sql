@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding equipments.code for QR stickers';
+
+-- Код наклейки: не совпадает с ID, его нельзя подобрать перебором и можно перевыпустить.
+-- Volatile DEFAULT вычисляется для каждой строки, так что существующее оборудование тоже получит коды.
+ALTER TABLE public.equipments
+    ADD COLUMN IF NOT EXISTS code VARCHAR(32) NOT NULL
+        DEFAULT upper(substr(md5(random()::text || clock_timestamp()::text), 1, 10));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_equipments_code_unique ON public.equipments (code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping equipments.code';
+
+DROP INDEX IF EXISTS public.idx_equipments_code_unique;
+ALTER TABLE public.equipments DROP COLUMN IF EXISTS code;
+-- +goose StatementEnd
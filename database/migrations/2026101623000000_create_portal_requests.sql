@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating portal_requests';
+
+-- Обращения с публичного портала: контакт клиента и код отслеживания для заявки,
+-- созданной от имени служебного пользователя портала.
+CREATE TABLE IF NOT EXISTS public.portal_requests (
+    id            BIGSERIAL PRIMARY KEY,
+    order_id      BIGINT       NOT NULL REFERENCES public.orders (id) ON DELETE CASCADE,
+    tracking_code VARCHAR(16)  NOT NULL,
+    customer_name VARCHAR(150) NOT NULL,
+    phone         VARCHAR(32)  NOT NULL,
+    client_ip     VARCHAR(64)  NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portal_requests_tracking_code ON public.portal_requests (tracking_code);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portal_requests_order_id ON public.portal_requests (order_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping portal_requests';
+
+DROP TABLE IF EXISTS public.portal_requests;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating chat channel tables';
+
+-- Входящие вебхуки Microsoft Teams и Slack, куда публикуются события заявок.
+-- department_id = NULL — канал получает события всех департаментов;
+-- only_critical — только заявки с приоритетом CRITICAL.
+CREATE TABLE IF NOT EXISTS public.chat_channels (
+    id            BIGSERIAL PRIMARY KEY,
+    name          VARCHAR(255)  NOT NULL,
+    kind          VARCHAR(16)   NOT NULL,
+    webhook_url   VARCHAR(2048) NOT NULL,
+    department_id BIGINT        NULL REFERENCES public.departments(id) ON DELETE CASCADE,
+    event_types   TEXT[]        NOT NULL DEFAULT '{}',
+    only_critical BOOLEAN       NOT NULL DEFAULT FALSE,
+    is_active     BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_by    BIGINT        NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_chat_channels_kind CHECK (kind IN ('teams', 'slack'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_channels_department
+    ON public.chat_channels (department_id)
+    WHERE is_active;
+
+-- Какие нарушения срока уже отправлены в каналы. Ключ включает срок: если срок продлили
+-- и он снова истёк, заявка попадёт в каналы ещё раз.
+CREATE TABLE IF NOT EXISTS public.chat_sla_breach_notices (
+    order_id    BIGINT      NOT NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    duration    TIMESTAMPTZ NOT NULL,
+    notified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, duration)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping chat channel tables';
+
+DROP TABLE IF EXISTS public.chat_sla_breach_notices;
+DROP TABLE IF EXISTS public.chat_channels;
+-- +goose StatementEnd
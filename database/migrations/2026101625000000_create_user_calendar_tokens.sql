@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating user_calendar_tokens table';
+
+-- Ключ персональной iCal-подписки. Ссылка подписывается HMAC от user_id и nonce;
+-- смена nonce или удаление строки отзывает все выданные ранее ссылки.
+CREATE TABLE IF NOT EXISTS public.user_calendar_tokens (
+    user_id      BIGINT      PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    nonce        VARCHAR(64) NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping user_calendar_tokens table';
+
+DROP TABLE IF EXISTS public.user_calendar_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating sync_jobs table';
+
+-- Очередь заданий синхронизации справочников. Выгрузка хранится в payload до завершения задания;
+-- progress и errors обновляются воркером по ходу обработки, heartbeat_at — признак живого воркера.
+CREATE TABLE IF NOT EXISTS public.sync_jobs (
+    id           BIGSERIAL PRIMARY KEY,
+    source       VARCHAR(32) NOT NULL DEFAULT '1c',
+    status       VARCHAR(16) NOT NULL DEFAULT 'queued',
+    payload      JSONB       NULL,
+    progress     JSONB       NOT NULL DEFAULT '{}',
+    summary      JSONB       NOT NULL DEFAULT '{}',
+    errors       JSONB       NOT NULL DEFAULT '[]',
+    error        TEXT        NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at   TIMESTAMPTZ NULL,
+    heartbeat_at TIMESTAMPTZ NULL,
+    finished_at  TIMESTAMPTZ NULL,
+    CONSTRAINT chk_sync_jobs_status CHECK (status IN ('queued', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_pending
+    ON public.sync_jobs (id)
+    WHERE status IN ('queued', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping sync_jobs table';
+
+DROP TABLE IF EXISTS public.sync_jobs;
+-- +goose StatementEnd
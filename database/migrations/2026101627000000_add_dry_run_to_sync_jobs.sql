@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding dry run to sync_jobs';
+
+-- dry_run: задание проверяет выгрузку в откатываемой транзакции; changes — что изменилось бы по записям.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS changes JSONB   NOT NULL DEFAULT '[]';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping dry run from sync_jobs';
+
+ALTER TABLE public.sync_jobs
+    DROP COLUMN IF EXISTS changes,
+    DROP COLUMN IF EXISTS dry_run;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding snapshot mode to sync_jobs';
+
+-- snapshot: выгрузка полная, записи 1С, которых в ней нет, переводятся в INACTIVE;
+-- deactivated — список таких записей для отчёта.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS snapshot    BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS deactivated JSONB   NOT NULL DEFAULT '[]';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping snapshot mode from sync_jobs';
+
+ALTER TABLE public.sync_jobs
+    DROP COLUMN IF EXISTS deactivated,
+    DROP COLUMN IF EXISTS snapshot;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding best effort mode to sync_jobs';
+
+-- best_effort: ошибочные записи пропускаются и попадают в errors, остальные сохраняются.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS best_effort BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping best effort mode from sync_jobs';
+
+ALTER TABLE public.sync_jobs
+    DROP COLUMN IF EXISTS best_effort;
+-- +goose StatementEnd
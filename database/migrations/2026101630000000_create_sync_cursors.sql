@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating sync_cursors table';
+
+-- Курсоры инкрементальной загрузки из 1С: с какой отметки запрашивать изменения каждого справочника.
+CREATE TABLE IF NOT EXISTS public.sync_cursors (
+    source     VARCHAR(32)  NOT NULL,
+    entity     VARCHAR(32)  NOT NULL,
+    cursor     VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, entity)
+);
+
+-- Курсоры, полученные вместе с выгрузкой; сохраняются в sync_cursors только после успешного задания.
+ALTER TABLE public.sync_jobs
+    ADD COLUMN IF NOT EXISTS cursors JSONB NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping sync_cursors table';
+
+ALTER TABLE public.sync_jobs
+    DROP COLUMN IF EXISTS cursors;
+
+DROP TABLE IF EXISTS public.sync_cursors;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating ad_group_role_mappings table';
+
+-- Сопоставление групп Active Directory с ролями. ad_group — полный DN группы или её CN,
+-- сравнение без учёта регистра. Роли из этой таблицы назначаются и снимаются синхронизацией AD.
+CREATE TABLE IF NOT EXISTS public.ad_group_role_mappings (
+    id         BIGSERIAL PRIMARY KEY,
+    ad_group   VARCHAR(512) NOT NULL,
+    role_id    BIGINT       NOT NULL REFERENCES public.roles (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ad_group_role_mappings_group_role
+    ON public.ad_group_role_mappings (LOWER(ad_group), role_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping ad_group_role_mappings table';
+
+DROP TABLE IF EXISTS public.ad_group_role_mappings;
+-- +goose StatementEnd
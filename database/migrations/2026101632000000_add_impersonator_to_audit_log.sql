@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding impersonator_id to audit_log';
+
+-- Администратор, работавший под учётной записью actor_id (вход по токену имперсонации).
+ALTER TABLE public.audit_log
+    ADD COLUMN IF NOT EXISTS impersonator_id BIGINT NULL REFERENCES public.users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_impersonator
+    ON public.audit_log (impersonator_id, created_at DESC)
+    WHERE impersonator_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping impersonator_id from audit_log';
+
+DROP INDEX IF EXISTS public.idx_audit_log_impersonator;
+
+ALTER TABLE public.audit_log
+    DROP COLUMN IF EXISTS impersonator_id;
+-- +goose StatementEnd
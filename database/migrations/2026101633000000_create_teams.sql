@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating teams and team assignment for orders';
+
+-- Команды (группы исполнителей): заявку можно назначить на команду,
+-- и любой её участник забирает заявку себе.
+CREATE TABLE IF NOT EXISTS public.teams (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    lead_id     BIGINT NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_teams_name ON public.teams (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS public.team_members (
+    team_id    BIGINT NOT NULL REFERENCES public.teams (id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON public.team_members (user_id);
+
+ALTER TABLE public.orders
+    ADD COLUMN IF NOT EXISTS team_id BIGINT NULL REFERENCES public.teams (id) ON DELETE SET NULL;
+
+-- Очередь команды: заявки, которые ещё никто не забрал
+CREATE INDEX IF NOT EXISTS idx_orders_team_unclaimed
+    ON public.orders (team_id)
+    WHERE team_id IS NOT NULL AND executor_id IS NULL AND deleted_at IS NULL;
+
+ALTER TABLE public.order_routing_rules
+    ADD COLUMN IF NOT EXISTS assign_to_team_id BIGINT NULL REFERENCES public.teams (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping teams';
+
+ALTER TABLE public.order_routing_rules
+    DROP COLUMN IF EXISTS assign_to_team_id;
+
+DROP INDEX IF EXISTS public.idx_orders_team_unclaimed;
+
+ALTER TABLE public.orders
+    DROP COLUMN IF EXISTS team_id;
+
+DROP TABLE IF EXISTS public.team_members;
+DROP TABLE IF EXISTS public.teams;
+-- +goose StatementEnd
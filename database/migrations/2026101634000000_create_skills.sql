@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating skills for skill-based routing';
+
+-- Навыки исполнителей ("network", "1C", "ATM"): маршрутизация предпочитает исполнителя,
+-- у которого есть все навыки, требуемые типом заявки.
+CREATE TABLE IF NOT EXISTS public.skills (
+    id         BIGSERIAL PRIMARY KEY,
+    code       VARCHAR(64) NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_skills_code ON public.skills (LOWER(code));
+
+CREATE TABLE IF NOT EXISTS public.user_skills (
+    user_id  BIGINT NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    skill_id BIGINT NOT NULL REFERENCES public.skills (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, skill_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_skills_skill ON public.user_skills (skill_id);
+
+CREATE TABLE IF NOT EXISTS public.order_type_skills (
+    order_type_id BIGINT NOT NULL REFERENCES public.order_types (id) ON DELETE CASCADE,
+    skill_id      BIGINT NOT NULL REFERENCES public.skills (id) ON DELETE CASCADE,
+    PRIMARY KEY (order_type_id, skill_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping skills';
+
+DROP TABLE IF EXISTS public.order_type_skills;
+DROP TABLE IF EXISTS public.user_skills;
+DROP TABLE IF EXISTS public.skills;
+-- +goose StatementEnd
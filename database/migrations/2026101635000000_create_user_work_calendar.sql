@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating user_work_calendar for shifts and days off';
+
+-- Рабочий календарь сотрудника. SHIFT — смена; DAY_OFF, VACATION и SICK_LEAVE — время,
+-- когда сотрудник не работает. Автоназначение пропускает тех, кто сейчас не на смене.
+CREATE TABLE IF NOT EXISTS public.user_work_calendar (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    kind       VARCHAR(20) NOT NULL,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    comment    TEXT,
+    created_by BIGINT REFERENCES public.users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_user_work_calendar_kind CHECK (kind IN ('SHIFT', 'DAY_OFF', 'VACATION', 'SICK_LEAVE')),
+    CONSTRAINT chk_user_work_calendar_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_work_calendar_user_period
+    ON public.user_work_calendar (user_id, starts_at, ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping user_work_calendar';
+
+DROP TABLE IF EXISTS public.user_work_calendar;
+-- +goose StatementEnd
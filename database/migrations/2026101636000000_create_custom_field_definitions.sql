@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating custom_field_definitions and orders.custom_fields';
+
+-- Дополнительные поля заявки, свои для каждого типа. Значения лежат в orders.custom_fields
+-- под ключом code; field_type: text, number, date, boolean, select (варианты в options).
+CREATE TABLE IF NOT EXISTS public.custom_field_definitions (
+    id            BIGSERIAL PRIMARY KEY,
+    order_type_id BIGINT NOT NULL REFERENCES public.order_types (id) ON DELETE CASCADE,
+    code          VARCHAR(64) NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    field_type    VARCHAR(20) NOT NULL,
+    is_required   BOOLEAN NOT NULL DEFAULT FALSE,
+    options       JSONB NOT NULL DEFAULT '[]'::jsonb,
+    sort_order    INT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_custom_field_definitions_type CHECK (field_type IN ('text', 'number', 'date', 'boolean', 'select'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_custom_field_definitions_code
+    ON public.custom_field_definitions (order_type_id, LOWER(code));
+
+ALTER TABLE public.orders
+    ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping custom fields';
+
+ALTER TABLE public.orders DROP COLUMN IF EXISTS custom_fields;
+DROP TABLE IF EXISTS public.custom_field_definitions;
+-- +goose StatementEnd
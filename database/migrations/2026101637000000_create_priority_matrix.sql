@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating priority_matrix and orders.impact/urgency';
+
+-- Матрица «влияние × срочность» → приоритет. Приоритет новой заявки считается по ней,
+-- если автор не выбрал его вручную (для этого нужно право order:create:priority_id).
+CREATE TABLE IF NOT EXISTS public.priority_matrix (
+    impact      VARCHAR(10) NOT NULL,
+    urgency     VARCHAR(10) NOT NULL,
+    priority_id BIGINT NOT NULL REFERENCES public.priorities (id),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (impact, urgency),
+    CONSTRAINT chk_priority_matrix_impact CHECK (impact IN ('low', 'medium', 'high')),
+    CONSTRAINT chk_priority_matrix_urgency CHECK (urgency IN ('low', 'medium', 'high'))
+);
+
+ALTER TABLE public.orders
+    ADD COLUMN IF NOT EXISTS impact VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS urgency VARCHAR(10),
+    ADD CONSTRAINT chk_orders_impact CHECK (impact IN ('low', 'medium', 'high')),
+    ADD CONSTRAINT chk_orders_urgency CHECK (urgency IN ('low', 'medium', 'high'));
+
+-- Начальная матрица по стандартным кодам приоритетов; на чистой базе её заполняет сидер.
+INSERT INTO public.priority_matrix (impact, urgency, priority_id)
+SELECT m.impact, m.urgency, p.id
+FROM (VALUES
+    ('high', 'high', 'CRITICAL'), ('high', 'medium', 'HIGH'), ('high', 'low', 'MEDIUM'),
+    ('medium', 'high', 'HIGH'), ('medium', 'medium', 'MEDIUM'), ('medium', 'low', 'LOW'),
+    ('low', 'high', 'MEDIUM'), ('low', 'medium', 'LOW'), ('low', 'low', 'LOW')
+) AS m (impact, urgency, code)
+JOIN public.priorities p ON p.code = m.code
+ON CONFLICT (impact, urgency) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping priority matrix';
+
+ALTER TABLE public.orders
+    DROP CONSTRAINT IF EXISTS chk_orders_urgency,
+    DROP CONSTRAINT IF EXISTS chk_orders_impact,
+    DROP COLUMN IF EXISTS urgency,
+    DROP COLUMN IF EXISTS impact;
+DROP TABLE IF EXISTS public.priority_matrix;
+-- +goose StatementEnd
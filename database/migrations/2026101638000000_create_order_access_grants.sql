@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating order_access_grants';
+
+-- Разовый доступ к конкретной заявке для сотрудника вне её области видимости
+-- (например, офицера безопасности). level: view — просмотр, edit — просмотр и изменение.
+-- Просроченная выдача (expires_at в прошлом) не действует.
+CREATE TABLE IF NOT EXISTS public.order_access_grants (
+    id         BIGSERIAL PRIMARY KEY,
+    order_id   BIGINT NOT NULL REFERENCES public.orders (id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    level      VARCHAR(10) NOT NULL,
+    granted_by BIGINT REFERENCES public.users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_access_grants_order_user UNIQUE (order_id, user_id),
+    CONSTRAINT chk_order_access_grants_level CHECK (level IN ('view', 'edit'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_access_grants_user ON public.order_access_grants (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping order_access_grants';
+
+DROP TABLE IF EXISTS public.order_access_grants;
+-- +goose StatementEnd
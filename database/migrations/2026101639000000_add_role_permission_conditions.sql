@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding role_permissions.conditions';
+
+-- Условия (ABAC), при которых право роли действует: JSON-массив условий, достаточно
+-- выполнения любого из них. NULL — право действует без ограничений, как раньше.
+ALTER TABLE public.role_permissions
+    ADD COLUMN IF NOT EXISTS conditions JSONB,
+    ADD CONSTRAINT chk_role_permissions_conditions CHECK (conditions IS NULL OR jsonb_typeof(conditions) = 'array');
+
+COMMENT ON COLUMN public.role_permissions.conditions IS 'Условия действия права (max_priority, same_branch, business_hours, ...); NULL — без условий';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping role_permissions.conditions';
+ALTER TABLE public.role_permissions
+    DROP CONSTRAINT IF EXISTS chk_role_permissions_conditions,
+    DROP COLUMN IF EXISTS conditions;
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating tenants and tenant_id columns';
+
+-- Организации (дочерние банки), работающие на одной установке. Все существующие
+-- данные относятся к организации по умолчанию с id = 1.
+CREATE TABLE IF NOT EXISTS public.tenants (
+    id         BIGSERIAL PRIMARY KEY,
+    code       VARCHAR(50)  NOT NULL UNIQUE,
+    name       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO public.tenants (id, code, name) VALUES (1, 'default', 'Основная организация')
+ON CONFLICT (id) DO NOTHING;
+SELECT setval('public.tenants_id_seq', GREATEST((SELECT MAX(id) FROM public.tenants), 1));
+
+-- Пользователи и заявки всегда принадлежат одной организации.
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 1 REFERENCES public.tenants (id);
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 1 REFERENCES public.tenants (id);
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON public.users (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_at ON public.orders (tenant_id, created_at DESC);
+
+-- Справочники: NULL — общий для всех организаций. Оргструктура существующей установки
+-- относится к организации по умолчанию, типы заявок остаются общими.
+ALTER TABLE public.departments ADD COLUMN IF NOT EXISTS tenant_id BIGINT NULL REFERENCES public.tenants (id);
+ALTER TABLE public.branches ADD COLUMN IF NOT EXISTS tenant_id BIGINT NULL REFERENCES public.tenants (id);
+ALTER TABLE public.otdels ADD COLUMN IF NOT EXISTS tenant_id BIGINT NULL REFERENCES public.tenants (id);
+ALTER TABLE public.offices ADD COLUMN IF NOT EXISTS tenant_id BIGINT NULL REFERENCES public.tenants (id);
+ALTER TABLE public.order_types ADD COLUMN IF NOT EXISTS tenant_id BIGINT NULL REFERENCES public.tenants (id);
+
+UPDATE public.departments SET tenant_id = 1 WHERE tenant_id IS NULL;
+UPDATE public.branches SET tenant_id = 1 WHERE tenant_id IS NULL;
+UPDATE public.otdels SET tenant_id = 1 WHERE tenant_id IS NULL;
+UPDATE public.offices SET tenant_id = 1 WHERE tenant_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_departments_tenant_id ON public.departments (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_branches_tenant_id ON public.branches (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_otdels_tenant_id ON public.otdels (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_offices_tenant_id ON public.offices (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_order_types_tenant_id ON public.order_types (tenant_id);
+
+-- Суточные агрегаты дашборда считаются отдельно по каждой организации.
+ALTER TABLE public.daily_order_stats ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_daily_order_stats_tenant ON public.daily_order_stats (tenant_id, stat_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping tenants and tenant_id columns';
+
+DROP INDEX IF EXISTS public.idx_daily_order_stats_tenant;
+ALTER TABLE public.daily_order_stats DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE public.order_types DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE public.offices DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE public.otdels DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE public.branches DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE public.departments DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE public.users DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS public.tenants;
+-- +goose StatementEnd
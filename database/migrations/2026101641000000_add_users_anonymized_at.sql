@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding users.anonymized_at';
+
+-- Время обезличивания уволенного сотрудника; у обезличенного пользователя не осталось личных данных.
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping users.anonymized_at';
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS anonymized_at;
+-- +goose StatementEnd
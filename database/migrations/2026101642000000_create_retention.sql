@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating retention tables';
+
+-- Правила хранения: что удалять у заявок, закрытых больше closed_older_than_days дней назад.
+-- purge_attachments — вложения вместе с файлами; compact_history — записи истории, не нужные
+-- статистике, сворачиваются в одну запись-сводку.
+CREATE TABLE IF NOT EXISTS public.retention_rules (
+    id                     BIGSERIAL PRIMARY KEY,
+    name                   VARCHAR(255) NOT NULL,
+    action                 VARCHAR(32)  NOT NULL,
+    closed_older_than_days INT          NOT NULL,
+    is_active              BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_by             BIGINT       NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_retention_rules_action CHECK (action IN ('purge_attachments', 'compact_history')),
+    CONSTRAINT chk_retention_rules_days CHECK (closed_older_than_days > 0)
+);
+
+-- Примеры правил; выключены, пока администратор их не проверит.
+INSERT INTO public.retention_rules (name, action, closed_older_than_days, is_active) VALUES
+    ('Вложения заявок, закрытых больше 3 лет назад', 'purge_attachments', 1095, FALSE),
+    ('Сжатие истории заявок, закрытых больше 3 лет назад', 'compact_history', 1095, FALSE);
+
+-- Заявки под юридическим удержанием: правила хранения их не трогают.
+CREATE TABLE IF NOT EXISTS public.retention_holds (
+    order_id   BIGINT      PRIMARY KEY REFERENCES public.orders (id) ON DELETE CASCADE,
+    reason     TEXT        NOT NULL,
+    created_by BIGINT      NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Запуски правил хранения; report — что удалено по каждому правилу.
+CREATE TABLE IF NOT EXISTS public.retention_runs (
+    id           BIGSERIAL PRIMARY KEY,
+    dry_run      BOOLEAN     NOT NULL DEFAULT FALSE,
+    status       VARCHAR(16) NOT NULL DEFAULT 'running',
+    triggered_by BIGINT      NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    report       JSONB       NOT NULL DEFAULT '[]',
+    error        TEXT        NULL,
+    started_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ NULL,
+    CONSTRAINT chk_retention_runs_status CHECK (status IN ('running', 'completed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON public.retention_runs (started_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping retention tables';
+
+DROP TABLE IF EXISTS public.retention_runs;
+DROP TABLE IF EXISTS public.retention_holds;
+DROP TABLE IF EXISTS public.retention_rules;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating backups table';
+
+-- Резервные копии важных таблиц (pg_dump -Fc). file_path — путь в хранилище копий;
+-- у удалённых по BACKUP_KEEP копий он обнуляется, а запись остаётся в журнале.
+-- verify_* — последняя проверка восстановления во временную схему: сколько строк
+-- каждой таблицы удалось загрузить (verify_report).
+CREATE TABLE IF NOT EXISTS public.backups (
+    id            BIGSERIAL PRIMARY KEY,
+    status        VARCHAR(16)  NOT NULL DEFAULT 'running',
+    tables        TEXT[]       NOT NULL,
+    file_path     VARCHAR(512) NULL,
+    size_bytes    BIGINT       NOT NULL DEFAULT 0,
+    sha256        VARCHAR(64)  NULL,
+    triggered_by  BIGINT       NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    error         TEXT         NULL,
+    started_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    finished_at   TIMESTAMPTZ  NULL,
+    verify_status VARCHAR(16)  NULL,
+    verify_report JSONB        NULL,
+    verify_error  TEXT         NULL,
+    verified_at   TIMESTAMPTZ  NULL,
+    CONSTRAINT chk_backups_status CHECK (status IN ('running', 'completed', 'failed', 'pruned')),
+    CONSTRAINT chk_backups_verify_status CHECK (verify_status IN ('passed', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_backups_started_at ON public.backups (started_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping backups table';
+
+DROP TABLE IF EXISTS public.backups;
+-- +goose StatementEnd
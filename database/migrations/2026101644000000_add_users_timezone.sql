@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding users.timezone';
+
+-- Часовой пояс пользователя (имя IANA, например Asia/Dushanbe). NULL — пояс сервера (APP_TIMEZONE).
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping users.timezone';
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
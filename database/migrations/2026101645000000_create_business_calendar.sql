@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating business calendar';
+
+-- Рабочий календарь организации для SLA: рабочие дни недели (ISO, 1 — понедельник) и часы
+-- рабочего дня в поясе сервера (APP_TIMEZONE); конец дня 24:00 — до полуночи. Строка всегда одна.
+CREATE TABLE IF NOT EXISTS public.business_calendar (
+    id         SMALLINT    PRIMARY KEY DEFAULT 1,
+    work_days  INT[]       NOT NULL DEFAULT '{1,2,3,4,5}',
+    day_start  TIME        NOT NULL DEFAULT '08:00',
+    day_end    TIME        NOT NULL DEFAULT '17:00',
+    is_enabled BOOLEAN     NOT NULL DEFAULT TRUE,
+    updated_by BIGINT      NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_business_calendar_single CHECK (id = 1),
+    CONSTRAINT chk_business_calendar_hours CHECK (day_start < day_end)
+);
+
+INSERT INTO public.business_calendar (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
+
+-- Праздники и переносы. is_recurring — каждый год в тот же день (год в дате не важен);
+-- is_working_day — перенесённый рабочий день, который выпадает на выходной.
+CREATE TABLE IF NOT EXISTS public.business_holidays (
+    id             BIGSERIAL PRIMARY KEY,
+    holiday_date   DATE         NOT NULL UNIQUE,
+    name           VARCHAR(255) NOT NULL,
+    is_recurring   BOOLEAN      NOT NULL DEFAULT FALSE,
+    is_working_day BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_by     BIGINT       NULL REFERENCES public.users (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_business_holidays_kind CHECK (NOT (is_recurring AND is_working_day))
+);
+
+-- Государственные праздники Таджикистана с постоянной датой. Иди Рамазон и Иди Курбон
+-- переходящие: их, как и переносы выходных, администратор добавляет на каждый год.
+INSERT INTO public.business_holidays (holiday_date, name, is_recurring) VALUES
+    ('2000-01-01', 'Новый год', TRUE),
+    ('2000-03-08', 'День матери', TRUE),
+    ('2000-03-21', 'Навруз', TRUE),
+    ('2000-03-22', 'Навруз', TRUE),
+    ('2000-03-23', 'Навруз', TRUE),
+    ('2000-03-24', 'Навруз', TRUE),
+    ('2000-05-01', 'Международный день трудящихся', TRUE),
+    ('2000-05-09', 'День Победы', TRUE),
+    ('2000-06-27', 'День национального единства', TRUE),
+    ('2000-09-09', 'День независимости', TRUE),
+    ('2000-11-06', 'День Конституции', TRUE)
+ON CONFLICT (holiday_date) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping business calendar';
+
+DROP TABLE IF EXISTS public.business_holidays;
+DROP TABLE IF EXISTS public.business_calendar;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating order_type_validation_rules';
+
+-- Обязательные поля заявки по типам. on_create — поле проверяется при создании; on_update —
+-- при изменении: комментарий нужен к каждой правке, остальные поля нельзя очистить.
+CREATE TABLE IF NOT EXISTS public.order_type_validation_rules (
+    id            BIGSERIAL PRIMARY KEY,
+    order_type_id BIGINT NOT NULL REFERENCES public.order_types (id) ON DELETE CASCADE,
+    field_name    VARCHAR(64) NOT NULL,
+    error_message VARCHAR(255) NOT NULL,
+    on_create     BOOLEAN NOT NULL DEFAULT TRUE,
+    on_update     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_order_type_validation_rules_field UNIQUE (order_type_id, field_name),
+    CONSTRAINT chk_order_type_validation_rules_stage CHECK (on_create OR on_update)
+);
+
+-- Правила, которые раньше были зашиты в код: у оборудования — оборудование, его тип и приоритет,
+-- у остальных типов — комментарий при создании и при каждом изменении.
+INSERT INTO public.order_type_validation_rules (order_type_id, field_name, error_message, on_create, on_update)
+SELECT ot.id, r.field_name, r.error_message, TRUE, FALSE
+FROM public.order_types ot
+CROSS JOIN (VALUES
+    ('equipment_id', 'Пожалуйста, укажите оборудование.'),
+    ('equipment_type_id', 'Пожалуйста, выберите тип оборудования.'),
+    ('priority_id', 'Пожалуйста, укажите приоритет.')
+) AS r (field_name, error_message)
+WHERE ot.code = 'EQUIPMENT'
+ON CONFLICT (order_type_id, field_name) DO NOTHING;
+
+INSERT INTO public.order_type_validation_rules (order_type_id, field_name, error_message, on_create, on_update)
+SELECT ot.id, 'comment', 'Для данного типа заявки необходимо заполнить поле «Комментарий».', TRUE, TRUE
+FROM public.order_types ot
+WHERE ot.code <> 'EQUIPMENT'
+ON CONFLICT (order_type_id, field_name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping order_type_validation_rules';
+
+DROP TABLE IF EXISTS public.order_type_validation_rules;
+-- +goose StatementEnd
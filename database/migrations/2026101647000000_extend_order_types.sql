@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding icon, description and visibility to order_types';
+
+-- icon — имя иконки или эмодзи для формы создания и Telegram; активность типа по-прежнему
+-- задаёт status_id (ACTIVE / INACTIVE).
+ALTER TABLE public.order_types
+    ADD COLUMN IF NOT EXISTS icon        VARCHAR(64) NULL,
+    ADD COLUMN IF NOT EXISTS description TEXT NULL;
+
+-- Видимость типа при создании заявки: без строк в обеих таблицах тип виден всем, иначе —
+-- сотрудникам перечисленных департаментов или филиалов.
+CREATE TABLE IF NOT EXISTS public.order_type_departments (
+    order_type_id BIGINT NOT NULL REFERENCES public.order_types (id) ON DELETE CASCADE,
+    department_id BIGINT NOT NULL REFERENCES public.departments (id) ON DELETE CASCADE,
+    PRIMARY KEY (order_type_id, department_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.order_type_branches (
+    order_type_id BIGINT NOT NULL REFERENCES public.order_types (id) ON DELETE CASCADE,
+    branch_id     BIGINT NOT NULL REFERENCES public.branches (id) ON DELETE CASCADE,
+    PRIMARY KEY (order_type_id, branch_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping order type visibility';
+
+DROP TABLE IF EXISTS public.order_type_branches;
+DROP TABLE IF EXISTS public.order_type_departments;
+ALTER TABLE public.order_types DROP COLUMN IF EXISTS description, DROP COLUMN IF EXISTS icon;
+-- +goose StatementEnd
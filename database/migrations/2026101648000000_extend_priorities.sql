@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding color, sort_order and sla_hours to priorities';
+
+-- color — цвет приоритета в интерфейсе (#RRGGBB); sort_order — порядок в списках и фильтрах;
+-- sla_hours — подсказка клиентам, какой срок выполнения предложить для заявки с этим приоритетом.
+ALTER TABLE public.priorities
+    ADD COLUMN IF NOT EXISTS color      VARCHAR(7) NULL,
+    ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS sla_hours  INT NULL;
+
+ALTER TABLE public.priorities
+    ADD CONSTRAINT chk_priorities_color CHECK (color IS NULL OR color ~ '^#[0-9A-F]{6}$'),
+    ADD CONSTRAINT chk_priorities_sla_hours CHECK (sla_hours IS NULL OR sla_hours > 0);
+
+-- Порядок списка прежний: раньше приоритеты сортировались по rate по убыванию.
+UPDATE public.priorities p
+SET sort_order = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY rate DESC NULLS LAST, id) AS position
+    FROM public.priorities
+) AS ordered
+WHERE ordered.id = p.id;
+
+UPDATE public.priorities p
+SET color = d.color, sla_hours = d.sla_hours
+FROM (VALUES
+    ('LOW', '#2E7D32', 168),
+    ('MEDIUM', '#F9A825', 72),
+    ('HIGH', '#EF6C00', 24),
+    ('CRITICAL', '#C62828', 4)
+) AS d (code, color, sla_hours)
+WHERE p.code = d.code AND p.color IS NULL AND p.sla_hours IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping color, sort_order and sla_hours from priorities';
+
+ALTER TABLE public.priorities
+    DROP CONSTRAINT IF EXISTS chk_priorities_sla_hours,
+    DROP CONSTRAINT IF EXISTS chk_priorities_color,
+    DROP COLUMN IF EXISTS sla_hours,
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS color;
+-- +goose StatementEnd
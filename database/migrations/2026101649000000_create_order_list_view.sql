@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating order_list_view';
+
+-- Проекция для списка заявок: названия справочников, ФИО, последний комментарий и число
+-- вложений уже посчитаны. Строку заявки пересобирает слушатель событий истории заявки.
+CREATE TABLE IF NOT EXISTS public.order_list_view (
+    order_id            BIGINT PRIMARY KEY REFERENCES public.orders (id) ON DELETE CASCADE,
+    status_name         TEXT NULL,
+    status_code         TEXT NULL,
+    priority_name       TEXT NULL,
+    priority_color      VARCHAR(7) NULL,
+    priority_sort_order INT NULL,
+    creator_name        TEXT NOT NULL DEFAULT '',
+    executor_name       TEXT NULL,
+    team_name           TEXT NULL,
+    last_comment        TEXT NULL,
+    last_comment_at     TIMESTAMPTZ NULL,
+    attachments_count   INT NOT NULL DEFAULT 0,
+    refreshed_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO public.order_list_view (
+    order_id, status_name, status_code, priority_name, priority_color, priority_sort_order,
+    creator_name, executor_name, team_name, last_comment, last_comment_at, attachments_count
+)
+SELECT o.id, st.name, st.code, pr.name, pr.color, pr.sort_order,
+    COALESCE(creator.fio, ''), executor.fio, team.name, lc.comment, lc.created_at,
+    (SELECT COUNT(*) FROM public.attachments a WHERE a.order_id = o.id)
+FROM public.orders o
+LEFT JOIN public.statuses st ON st.id = o.status_id
+LEFT JOIN public.priorities pr ON pr.id = o.priority_id
+LEFT JOIN public.users creator ON creator.id = o.user_id
+LEFT JOIN public.users executor ON executor.id = o.executor_id
+LEFT JOIN public.teams team ON team.id = o.team_id
+LEFT JOIN LATERAL (
+    SELECT LEFT(h.comment, 200) AS comment, h.created_at
+    FROM public.order_history h
+    WHERE h.order_id = o.id AND h.event_type = 'COMMENT' AND COALESCE(h.comment, '') <> ''
+    ORDER BY h.created_at DESC, h.id DESC
+    LIMIT 1
+) lc ON TRUE
+ON CONFLICT (order_id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping order_list_view';
+
+DROP TABLE IF EXISTS public.order_list_view;
+-- +goose StatementEnd
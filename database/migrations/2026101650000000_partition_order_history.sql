@@ -0,0 +1,126 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: partitioning order_history by year';
+
+-- order_history секционируется по годам created_at (границы — 1 января по UTC). Прежняя таблица
+-- становится секцией order_history_legacy за всё время до текущего года: переносить её целиком
+-- в миграции слишком долго. Записи текущего года переносятся сразу, прошлые годы разносит по
+-- своим секциям команда «seed history-partitions move-legacy».
+--
+-- orders не секционируется: на orders(id) ссылаются внешние ключи десятка таблиц, а PostgreSQL
+-- требует, чтобы ключ секционирования входил в каждый уникальный ключ, на который есть ссылки.
+UPDATE public.order_history h
+SET created_at = o.created_at
+FROM public.orders o
+WHERE h.created_at IS NULL AND o.id = h.order_id;
+
+UPDATE public.order_history SET created_at = NOW() WHERE created_at IS NULL;
+
+ALTER TABLE public.order_history ALTER COLUMN created_at SET NOT NULL;
+
+DO $$
+DECLARE
+    year_start   TIMESTAMPTZ := date_trunc('year', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC';
+    pk_name      TEXT;
+    seq_name     TEXT;
+    item         RECORD;
+    y            INT;
+BEGIN
+    -- Первичный ключ секционированной таблицы обязан включать ключ секционирования.
+    SELECT conname INTO pk_name
+    FROM pg_constraint
+    WHERE conrelid = 'public.order_history'::regclass AND contype = 'p';
+    IF pk_name IS NOT NULL THEN
+        EXECUTE format('ALTER TABLE public.order_history DROP CONSTRAINT %I', pk_name);
+    END IF;
+    ALTER TABLE public.order_history ADD CONSTRAINT order_history_legacy_pkey PRIMARY KEY (id, created_at);
+
+    seq_name := pg_get_serial_sequence('public.order_history', 'id');
+    ALTER TABLE public.order_history RENAME TO order_history_legacy;
+
+    CREATE TABLE public.order_history (
+        LIKE public.order_history_legacy INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING STORAGE INCLUDING COMMENTS
+    ) PARTITION BY RANGE (created_at);
+    ALTER TABLE public.order_history ADD CONSTRAINT order_history_pkey PRIMARY KEY (id, created_at);
+    IF seq_name IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY public.order_history.id', seq_name);
+    END IF;
+
+    -- Индексы переезжают на родительскую таблицу под прежними именами; при подключении секции
+    -- PostgreSQL привяжет к ним такие же индексы order_history_legacy без перестроения.
+    FOR item IN
+        SELECT c.relname AS name, pg_get_indexdef(i.indexrelid) AS def
+        FROM pg_index i
+        JOIN pg_class c ON c.oid = i.indexrelid
+        WHERE i.indrelid = 'public.order_history_legacy'::regclass AND NOT i.indisprimary
+    LOOP
+        EXECUTE format('ALTER INDEX public.%I RENAME TO %I', item.name, left(item.name, 56) || '_legacy');
+        EXECUTE replace(item.def, ' ON public.order_history_legacy ', ' ON public.order_history ');
+    END LOOP;
+
+    FOR item IN
+        SELECT conname AS name, pg_get_constraintdef(oid) AS def
+        FROM pg_constraint
+        WHERE conrelid = 'public.order_history_legacy'::regclass AND contype = 'f'
+    LOOP
+        EXECUTE format('ALTER TABLE public.order_history ADD CONSTRAINT %I %s', item.name, item.def);
+    END LOOP;
+
+    FOR y IN SELECT generate_series(EXTRACT(YEAR FROM year_start)::int, EXTRACT(YEAR FROM year_start)::int + 1)
+    LOOP
+        EXECUTE format('CREATE TABLE public.%I PARTITION OF public.order_history FOR VALUES FROM (%L) TO (%L)',
+            'order_history_y' || y,
+            make_timestamptz(y, 1, 1, 0, 0, 0, 'UTC'),
+            make_timestamptz(y + 1, 1, 1, 0, 0, 0, 'UTC'));
+    END LOOP;
+    -- Сюда попадают записи за годы без своей секции, если фоновое создание секций не успело.
+    CREATE TABLE public.order_history_default PARTITION OF public.order_history DEFAULT;
+
+    INSERT INTO public.order_history SELECT * FROM public.order_history_legacy WHERE created_at >= year_start;
+    DELETE FROM public.order_history_legacy WHERE created_at >= year_start;
+
+    EXECUTE format('ALTER TABLE public.order_history ATTACH PARTITION public.order_history_legacy FOR VALUES FROM (MINVALUE) TO (%L)', year_start);
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: merging order_history partitions back into one table';
+
+DO $$
+DECLARE
+    seq_name TEXT := pg_get_serial_sequence('public.order_history', 'id');
+    item     RECORD;
+BEGIN
+    CREATE TABLE public.order_history_flat (
+        LIKE public.order_history INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING STORAGE INCLUDING COMMENTS
+    );
+    INSERT INTO public.order_history_flat SELECT * FROM public.order_history;
+    ALTER TABLE public.order_history_flat ADD CONSTRAINT order_history_flat_pkey PRIMARY KEY (id);
+    IF seq_name IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY public.order_history_flat.id', seq_name);
+    END IF;
+
+    CREATE TEMP TABLE order_history_definitions ON COMMIT DROP AS
+        SELECT 'i' AS kind, c.relname AS name, pg_get_indexdef(i.indexrelid) AS def
+        FROM pg_index i
+        JOIN pg_class c ON c.oid = i.indexrelid
+        WHERE i.indrelid = 'public.order_history'::regclass AND NOT i.indisprimary
+        UNION ALL
+        SELECT 'f', conname, pg_get_constraintdef(oid)
+        FROM pg_constraint
+        WHERE conrelid = 'public.order_history'::regclass AND contype = 'f';
+
+    DROP TABLE public.order_history CASCADE;
+    ALTER TABLE public.order_history_flat RENAME TO order_history;
+    ALTER TABLE public.order_history RENAME CONSTRAINT order_history_flat_pkey TO order_history_pkey;
+
+    FOR item IN SELECT * FROM order_history_definitions LOOP
+        IF item.kind = 'i' THEN
+            EXECUTE item.def;
+        ELSE
+            EXECUTE format('ALTER TABLE public.order_history ADD CONSTRAINT %I %s', item.name, item.def);
+        END IF;
+    END LOOP;
+END $$;
+-- +goose StatementEnd
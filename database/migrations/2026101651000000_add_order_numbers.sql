@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding human-friendly order numbers';
+
+-- Номер заявки для людей: ПРЕФИКС-ГОД-000123. Префикс берётся из филиала (branches.order_prefix),
+-- без него — REQ. Счётчик свой для каждой организации, префикса и года, поэтому номера не выдают
+-- общий объём заявок и не зависят от последовательности orders.id.
+ALTER TABLE public.branches ADD COLUMN IF NOT EXISTS order_prefix VARCHAR(10);
+
+CREATE TABLE IF NOT EXISTS public.order_number_counters (
+    tenant_id  BIGINT      NOT NULL REFERENCES public.tenants (id),
+    prefix     VARCHAR(10) NOT NULL,
+    year       INT         NOT NULL,
+    last_value BIGINT      NOT NULL,
+    PRIMARY KEY (tenant_id, prefix, year)
+);
+
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS number VARCHAR(32);
+
+-- Существующие заявки нумеруются по порядку создания.
+WITH numbered AS (
+    SELECT o.id, o.tenant_id,
+        COALESCE(b.order_prefix, 'REQ') AS prefix,
+        EXTRACT(YEAR FROM o.created_at)::int AS year,
+        ROW_NUMBER() OVER (
+            PARTITION BY o.tenant_id, COALESCE(b.order_prefix, 'REQ'), EXTRACT(YEAR FROM o.created_at)
+            ORDER BY o.created_at, o.id
+        ) AS seq
+    FROM public.orders o
+    LEFT JOIN public.branches b ON b.id = o.branch_id
+    WHERE o.number IS NULL
+)
+UPDATE public.orders o
+SET number = n.prefix || '-' || n.year || '-' || CASE WHEN n.seq < 1000000 THEN lpad(n.seq::text, 6, '0') ELSE n.seq::text END
+FROM numbered n
+WHERE o.id = n.id;
+
+INSERT INTO public.order_number_counters (tenant_id, prefix, year, last_value)
+SELECT tenant_id, split_part(number, '-', 1), split_part(number, '-', 2)::int, MAX(split_part(number, '-', 3)::bigint)
+FROM public.orders
+GROUP BY tenant_id, split_part(number, '-', 1), split_part(number, '-', 2)
+ON CONFLICT (tenant_id, prefix, year) DO UPDATE SET last_value = GREATEST(order_number_counters.last_value, EXCLUDED.last_value);
+
+ALTER TABLE public.orders ALTER COLUMN number SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_orders_tenant_number ON public.orders (tenant_id, number);
+CREATE INDEX IF NOT EXISTS idx_orders_number_trgm ON public.orders USING gin (number gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping order numbers';
+
+DROP INDEX IF EXISTS public.idx_orders_number_trgm;
+DROP INDEX IF EXISTS public.uq_orders_tenant_number;
+ALTER TABLE public.orders DROP COLUMN IF EXISTS number;
+DROP TABLE IF EXISTS public.order_number_counters;
+ALTER TABLE public.branches DROP COLUMN IF EXISTS order_prefix;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating resolution codes';
+
+-- Код решения — чем закончилась заявка. Ставится при выполнении или закрытии, по нему
+-- строится отчёт о самых частых причинах обращений.
+CREATE TABLE IF NOT EXISTS public.resolution_codes (
+    id         BIGSERIAL PRIMARY KEY,
+    code       VARCHAR(64) NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_resolution_codes_code ON public.resolution_codes (LOWER(code));
+
+INSERT INTO public.resolution_codes (code, name, sort_order) VALUES
+    ('FIXED', 'Исправлено', 10),
+    ('CONFIGURATION', 'Изменена настройка', 20),
+    ('USER_ERROR', 'Ошибка пользователя', 30),
+    ('HARDWARE_REPLACED', 'Заменено оборудование', 40)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS resolution_code_id BIGINT;
+ALTER TABLE public.orders ADD CONSTRAINT fk_orders_resolution_code_id
+    FOREIGN KEY (resolution_code_id) REFERENCES public.resolution_codes (id);
+CREATE INDEX IF NOT EXISTS idx_orders_resolution_code_id ON public.orders (resolution_code_id)
+    WHERE resolution_code_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping resolution codes';
+
+ALTER TABLE public.orders DROP COLUMN IF EXISTS resolution_code_id;
+DROP TABLE IF EXISTS public.resolution_codes;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding users.telegram_last_seen_at';
+
+-- Последнее обращение пользователя к боту (сообщение или нажатие кнопки). Поддержка по нему видит,
+-- живая ли привязка; при отвязке чата время сбрасывается.
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS telegram_last_seen_at TIMESTAMPTZ NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping users.telegram_last_seen_at';
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS telegram_last_seen_at;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating sla escalation tables';
+
+-- Пороги эскалации по департаментам. Департамент без строки использует значения по
+-- умолчанию из SLA_ESCALATION_THRESHOLD и SLA_ESCALATION_WINDOW_HOURS.
+CREATE TABLE IF NOT EXISTS public.sla_escalation_settings (
+    department_id    BIGINT      PRIMARY KEY REFERENCES public.departments(id) ON DELETE CASCADE,
+    breach_threshold INT         NOT NULL,
+    window_hours     INT         NOT NULL,
+    is_enabled       BOOLEAN     NOT NULL DEFAULT TRUE,
+    updated_by       BIGINT      NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_sla_escalation_threshold CHECK (breach_threshold > 0),
+    CONSTRAINT chk_sla_escalation_window CHECK (window_hours > 0)
+);
+
+-- Нарушения срока исполнителями. Исполнитель запоминается на момент нарушения; ключ
+-- включает срок, поэтому продлённый и снова сорванный срок — ещё одно нарушение.
+CREATE TABLE IF NOT EXISTS public.sla_breaches (
+    order_id    BIGINT      NOT NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    duration    TIMESTAMPTZ NOT NULL,
+    executor_id BIGINT      NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, duration)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_executor
+    ON public.sla_breaches (executor_id, duration);
+
+-- Отправленные руководителям эскалации. last_breach_at — самое позднее учтённое нарушение:
+-- следующая эскалация считает только нарушения после него. Уникальность не даёт двум
+-- репликам отправить одну и ту же эскалацию.
+CREATE TABLE IF NOT EXISTS public.sla_escalations (
+    id             BIGSERIAL PRIMARY KEY,
+    executor_id    BIGINT      NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    department_id  BIGINT      NOT NULL REFERENCES public.departments(id) ON DELETE CASCADE,
+    breach_count   INT         NOT NULL,
+    last_breach_at TIMESTAMPTZ NOT NULL,
+    notified_ids   BIGINT[]    NOT NULL DEFAULT '{}',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_sla_escalations_executor
+    ON public.sla_escalations (executor_id, last_breach_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping sla escalation tables';
+
+DROP TABLE IF EXISTS public.sla_escalations;
+DROP TABLE IF EXISTS public.sla_breaches;
+DROP TABLE IF EXISTS public.sla_escalation_settings;
+-- +goose StatementEnd
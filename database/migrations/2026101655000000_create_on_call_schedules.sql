@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: creating on-call schedules';
+
+-- График дежурств департамента. Участники дежурят по очереди сменами по shift_hours часов,
+-- отсчёт смен идёт от rotation_start.
+CREATE TABLE IF NOT EXISTS public.on_call_schedules (
+    id             BIGSERIAL PRIMARY KEY,
+    name           VARCHAR(255) NOT NULL,
+    department_id  BIGINT       NOT NULL REFERENCES public.departments(id) ON DELETE CASCADE,
+    rotation_start TIMESTAMPTZ  NOT NULL,
+    shift_hours    INT          NOT NULL,
+    is_active      BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_on_call_shift_hours CHECK (shift_hours > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_on_call_schedules_department
+    ON public.on_call_schedules (department_id)
+    WHERE is_active;
+
+-- Очередь дежурных: position задаёт порядок ротации.
+CREATE TABLE IF NOT EXISTS public.on_call_members (
+    schedule_id BIGINT NOT NULL REFERENCES public.on_call_schedules(id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    position    INT    NOT NULL,
+    PRIMARY KEY (schedule_id, user_id)
+);
+
+-- Замены: на период дежурит указанный сотрудник вместо очередного по ротации.
+CREATE TABLE IF NOT EXISTS public.on_call_overrides (
+    id          BIGSERIAL PRIMARY KEY,
+    schedule_id BIGINT       NOT NULL REFERENCES public.on_call_schedules(id) ON DELETE CASCADE,
+    user_id     BIGINT       NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    starts_at   TIMESTAMPTZ  NOT NULL,
+    ends_at     TIMESTAMPTZ  NOT NULL,
+    reason      VARCHAR(500) NULL,
+    created_by  BIGINT       NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_on_call_override_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_on_call_overrides_schedule
+    ON public.on_call_overrides (schedule_id, ends_at);
+
+ALTER TABLE public.order_routing_rules
+    ADD COLUMN IF NOT EXISTS assign_to_on_call_schedule_id BIGINT NULL REFERENCES public.on_call_schedules (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping on-call schedules';
+
+ALTER TABLE public.order_routing_rules
+    DROP COLUMN IF EXISTS assign_to_on_call_schedule_id;
+
+DROP TABLE IF EXISTS public.on_call_overrides;
+DROP TABLE IF EXISTS public.on_call_members;
+DROP TABLE IF EXISTS public.on_call_schedules;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding first response sla to priorities';
+
+-- first_response_minutes — срок первого отклика исполнителя по заявке с этим приоритетом;
+-- NULL — приоритет без срока отклика. Считается по рабочему календарю, как и сама метрика.
+ALTER TABLE public.priorities
+    ADD COLUMN IF NOT EXISTS first_response_minutes INT NULL;
+
+ALTER TABLE public.priorities
+    ADD CONSTRAINT chk_priorities_first_response_minutes CHECK (first_response_minutes IS NULL OR first_response_minutes > 0);
+
+UPDATE public.priorities p
+SET first_response_minutes = d.minutes
+FROM (VALUES
+    ('LOW', 480),
+    ('MEDIUM', 240),
+    ('HIGH', 60),
+    ('CRITICAL', 15)
+) AS d (code, minutes)
+WHERE p.code = d.code AND p.first_response_minutes IS NULL;
+
+-- Отправленные напоминания о первом отклике. Ключ включает исполнителя: новый исполнитель
+-- после переназначения тоже получит напоминание, а две реплики не отправят одно и то же.
+CREATE TABLE IF NOT EXISTS public.first_response_reminders (
+    order_id    BIGINT      NOT NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    executor_id BIGINT      NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    sent_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, executor_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping first response sla from priorities';
+
+DROP TABLE IF EXISTS public.first_response_reminders;
+
+ALTER TABLE public.priorities
+    DROP CONSTRAINT IF EXISTS chk_priorities_first_response_minutes,
+    DROP COLUMN IF EXISTS first_response_minutes;
+-- +goose StatementEnd
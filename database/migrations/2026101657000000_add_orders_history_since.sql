@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up: adding orders.history_since lower bound of order history';
+
+-- history_since — момент не позже самой ранней записи истории заявки. Запросы к order_history
+-- ограничивают по нему created_at, чтобы PostgreSQL не читал секции за годы до заявки. Граница по
+-- orders.created_at для этого не годится: запись истории может оказаться старше заявки (перенос
+-- данных, расхождение часов) и тогда молча пропадала бы из отчётов. history_since держит триггер,
+-- поэтому условие по нему отсекает только секции, но не записи.
+ALTER TABLE public.orders ADD COLUMN history_since TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE OR REPLACE FUNCTION public.order_history_extend_since() RETURNS trigger
+LANGUAGE plpgsql AS $fn$
+BEGIN
+    UPDATE public.orders SET history_since = NEW.created_at
+    WHERE id = NEW.order_id AND history_since > NEW.created_at;
+    RETURN NULL;
+END
+$fn$;
+
+CREATE TRIGGER order_history_extend_since
+    AFTER INSERT OR UPDATE OF created_at, order_id ON public.order_history
+    FOR EACH ROW EXECUTE FUNCTION public.order_history_extend_since();
+
+UPDATE public.orders o
+SET history_since = COALESCE(LEAST(
+    o.created_at,
+    (SELECT MIN(h.created_at) FROM public.order_history h WHERE h.order_id = o.id)
+), o.history_since);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down: dropping orders.history_since';
+
+DROP TRIGGER IF EXISTS order_history_extend_since ON public.order_history;
+DROP FUNCTION IF EXISTS public.order_history_extend_since();
+ALTER TABLE public.orders DROP COLUMN IF EXISTS history_since;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+// Package apidocs хранит сгенерированную OpenAPI спецификацию и страницу Swagger UI.
+// После изменения аннотаций контроллеров или DTO спецификацию нужно пересобрать:
+//
+//	go generate ./internal/apidocs
+package apidocs
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+)
+
+//go:generate go run ../../tools/openapi_gen -root ../.. -out openapi.json
+
+//go:embed openapi.json
+var Spec []byte
+
+// SwaggerUIPage — страница Swagger UI; скрипты и стили берутся из assetsURL (swagger-ui-dist).
+func SwaggerUIPage(specURL, assetsURL string) string {
+	specURL, assetsURL = html.EscapeString(specURL), html.EscapeString(assetsURL)
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>Request System API</title>
+  <link rel="stylesheet" href="%[2]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[2]s/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "%[1]s", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>`, specURL, assetsURL)
+}
@@ -0,0 +1,163 @@
+package authz
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"request-system/internal/entities"
+)
+
+// Condition — условие, при котором право роли действует (ABAC). Заполненные поля
+// объединяются через И; у одного права может быть несколько условий — тогда хватает любого.
+// Поля, относящиеся к цели (приоритет, тип, подразделение), проверяются только когда
+// цель известна: без цели, например при создании или в списке, они не ограничивают.
+type Condition struct {
+	// Код приоритета: право действует для заявок не срочнее него (по rate), например HIGH
+	MaxPriority string `json:"max_priority,omitempty"`
+	// Допустимые приоритеты. При загрузке прав сюда же подставляются приоритеты по MaxPriority
+	PriorityIDs  []uint64 `json:"priority_ids,omitempty"`
+	OrderTypeIDs []uint64 `json:"order_type_ids,omitempty"`
+
+	// Цель должна быть в том же подразделении, что и пользователь
+	SameDepartment bool `json:"same_department,omitempty"`
+	SameBranch     bool `json:"same_branch,omitempty"`
+	SameOtdel      bool `json:"same_otdel,omitempty"`
+	SameOffice     bool `json:"same_office,omitempty"`
+
+	BusinessHours *BusinessHours `json:"business_hours,omitempty"`
+}
+
+// BusinessHours — окно времени по часам сервера. Weekdays — ISO-номера дней (1 — понедельник),
+// пусто — с понедельника по пятницу. To раньше From означает окно через полночь.
+type BusinessHours struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Weekdays []int  `json:"weekdays,omitempty"`
+}
+
+// Validate проверяет условие перед сохранением в роль.
+func (c Condition) Validate() error {
+	if c.isEmpty() {
+		return fmt.Errorf("условие не задаёт ни одного ограничения")
+	}
+	if c.BusinessHours != nil {
+		if _, err := parseClock(c.BusinessHours.From); err != nil {
+			return fmt.Errorf("business_hours.from: %w", err)
+		}
+		if _, err := parseClock(c.BusinessHours.To); err != nil {
+			return fmt.Errorf("business_hours.to: %w", err)
+		}
+		if c.BusinessHours.From == c.BusinessHours.To {
+			return fmt.Errorf("business_hours: начало и конец окна совпадают")
+		}
+		for _, day := range c.BusinessHours.Weekdays {
+			if day < 1 || day > 7 {
+				return fmt.Errorf("business_hours.weekdays: день недели должен быть от 1 до 7")
+			}
+		}
+	}
+	return nil
+}
+
+func (c Condition) isEmpty() bool {
+	return c.MaxPriority == "" && len(c.PriorityIDs) == 0 && len(c.OrderTypeIDs) == 0 &&
+		!c.SameDepartment && !c.SameBranch && !c.SameOtdel && !c.SameOffice && c.BusinessHours == nil
+}
+
+// HasPriorityLimit — ограничивает ли условие приоритет цели.
+func (c Condition) HasPriorityLimit() bool {
+	return c.MaxPriority != "" || len(c.PriorityIDs) > 0
+}
+
+// HoldsAt — выполняются ли условия, не зависящие от цели.
+func (c Condition) HoldsAt(now time.Time) bool {
+	return c.BusinessHours == nil || c.BusinessHours.contains(now)
+}
+
+func (c Condition) matches(ctx Context, now time.Time) bool {
+	if !c.HoldsAt(now) {
+		return false
+	}
+	switch target := ctx.Target.(type) {
+	case *entities.Order:
+		if c.HasPriorityLimit() && (target.PriorityID == nil || !slices.Contains(c.PriorityIDs, *target.PriorityID)) {
+			return false
+		}
+		if len(c.OrderTypeIDs) > 0 && (target.OrderTypeID == nil || !slices.Contains(c.OrderTypeIDs, *target.OrderTypeID)) {
+			return false
+		}
+		return c.sameUnits(ctx.Actor, target.DepartmentID, target.BranchID, target.OtdelID, target.OfficeID)
+	case *entities.User:
+		return c.sameUnits(ctx.Actor, target.DepartmentID, target.BranchID, target.OtdelID, target.OfficeID)
+	}
+	return true
+}
+
+func (c Condition) sameUnits(actor *entities.User, departmentID, branchID, otdelID, officeID *uint64) bool {
+	if actor == nil {
+		return !c.SameDepartment && !c.SameBranch && !c.SameOtdel && !c.SameOffice
+	}
+	return (!c.SameDepartment || sameUnit(actor.DepartmentID, departmentID)) &&
+		(!c.SameBranch || sameUnit(actor.BranchID, branchID)) &&
+		(!c.SameOtdel || sameUnit(actor.OtdelID, otdelID)) &&
+		(!c.SameOffice || sameUnit(actor.OfficeID, officeID))
+}
+
+func sameUnit(a, b *uint64) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+func (h BusinessHours) contains(now time.Time) bool {
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	days := h.Weekdays
+	if len(days) == 0 {
+		days = []int{1, 2, 3, 4, 5}
+	}
+
+	from, errFrom := parseClock(h.From)
+	to, errTo := parseClock(h.To)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if from < to {
+		return slices.Contains(days, weekday) && minute >= from && minute < to
+	}
+	// Окно через полночь: хвост после полуночи относится к предыдущему дню
+	if minute >= from {
+		return slices.Contains(days, weekday)
+	}
+	previous := weekday - 1
+	if previous == 0 {
+		previous = 7
+	}
+	return minute < to && slices.Contains(days, previous)
+}
+
+// parseClock переводит «ЧЧ:ММ» в минуты от начала суток.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("ожидается время в формате ЧЧ:ММ")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// conditionsMet — выполняется ли хотя бы одно из условий права; право без условий действует всегда.
+func (c *Context) conditionsMet(permission string) bool {
+	conditions := c.Conditions[permission]
+	if len(conditions) == 0 {
+		return true
+	}
+	now := time.Now()
+	for _, condition := range conditions {
+		if condition.matches(*c, now) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,10 +7,14 @@ import (
 )
 
 type Context struct {
-	Actor             *entities.User
-	Permissions       map[string]bool
-	Target            interface{}
-	IsParticipant     bool
+	Actor         *entities.User
+	Permissions   map[string]bool
+	Target        interface{}
+	IsParticipant bool
+	// AccessGrant — уровень разового доступа к заявке-цели (entities.OrderAccessView/Edit), "" — нет
+	AccessGrant string
+	// Conditions — условия прав из ролей пользователя (permission → условия, хватает любого)
+	Conditions        map[string][]Condition
 	CurrentPermission string
 }
 
@@ -66,7 +70,9 @@ func canAccessOrder(ctx Context, target *entities.Order) bool {
 				return true
 			}
 		}
-		return false
+
+		// Разовый доступ к этой заявке, выданный вне области видимости
+		return ctx.AccessGrant != ""
 	}
 
 	// ======================== 2. ИЗМЕНЕНИЕ (UPDATE, DELETE) ========================
@@ -102,6 +108,11 @@ func canAccessOrder(ctx Context, target *entities.Order) bool {
 		}
 	}
 
+	// Разовый доступ на изменение; удалять заявку он не позволяет
+	if action == "update" && ctx.AccessGrant == entities.OrderAccessEdit {
+		return true
+	}
+
 	// Доступ запрещен
 	return false
 }
@@ -150,6 +161,11 @@ func CanDo(permission string, ctx Context) bool {
 		return false
 	}
 
+	// 2.1. Условия, с которыми право выдано ролью (время, приоритет, подразделение цели)
+	if !ctx.conditionsMet(permission) {
+		return false
+	}
+
 	// 3. Без цели — разрешено (например создание)
 	if ctx.Target == nil {
 		return true
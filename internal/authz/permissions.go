@@ -6,6 +6,10 @@ const (
 	OrdersView   = "order:view"
 	OrdersUpdate = "order:update"
 	OrdersDelete = "order:delete"
+	// Закрытие заявки как дубликата с переносом вложений и комментариев в основную
+	OrdersMerge = "order:merge"
+	// Выдача и отзыв разового доступа к заявке сотруднику вне её области видимости
+	OrdersGrantAccess = "order:grant_access"
 
 	// ПОЛЬЗОВАТЕЛИ
 	UsersCreate        = "user:create"
@@ -116,6 +120,8 @@ const (
 	PermissionsUpdate = "permission:update"
 	PermissionsDelete = "permission:delete"
 	PermissionsView   = "permission:view"
+	// PermissionsFlushCache — ручной сброс кэша прав пользователей
+	PermissionsFlushCache = "permission:flush_cache"
 
 	// МАРШРУТИЗАЦИЯ ЗАЯВОК
 	OrderRuleCreate = "order_rule:create"
@@ -123,6 +129,22 @@ const (
 	OrderRuleDelete = "order_rule:delete"
 	OrderRuleView   = "order_rule:view"
 
+	// КОМАНДЫ ИСПОЛНИТЕЛЕЙ
+	TeamsView   = "team:view"
+	TeamsManage = "team:manage"
+
+	// ГРАФИКИ ДЕЖУРСТВ
+	OnCallView   = "on_call:view"
+	OnCallManage = "on_call:manage"
+
+	// НАВЫКИ ИСПОЛНИТЕЛЕЙ (маршрутизация по навыкам)
+	SkillsView   = "skill:view"
+	SkillsManage = "skill:manage"
+
+	// КОДЫ РЕШЕНИЯ ЗАЯВОК
+	ResolutionCodesView   = "resolution_code:view"
+	ResolutionCodesManage = "resolution_code:manage"
+
 	// ДОЛЖНОСТИ
 	PositionsCreate = "position:create"
 	PositionsView   = "position:view"
@@ -140,6 +162,10 @@ const (
 	// DASHBOARD
 	DashboardView = "dashboard:view"
 
+	// УВЕДОМЛЕНИЯ
+	// Просмотр и повторная отправка уведомлений из dead-letter очереди
+	NotificationsManage = "notification:manage"
+
 	// ИНТЕГРАЦИИ
 	IntegrationsView = "integration:view"
 
@@ -149,8 +175,47 @@ const (
 	// Дает право изменять настройки интеграций (в будущем)
 	IntegrationsUpdate = "integration:update"
 
+	// Управление webhook-подписками внешних систем и просмотр журнала доставок
+	WebhooksManage = "webhook:manage"
+
+	// Просмотр фоновых заданий и перезапуск упавших (GET /admin/jobs)
+	JobsManage = "job:manage"
+
+	// Просмотр и перечитывание настроек, которые меняются без перезапуска (GET /admin/config)
+	ConfigManage = "config:manage"
+
+	// Импорт пользователей, филиалов, офисов и оборудования из CSV (POST /admin/import/:entity)
+	DataImport = "import:run"
+
+	// Повторная публикация событий истории заявок в шину (догон слушателей после сбоя)
+	EventsReplay = "event:replay"
+
+	// Просмотр журнала аудита изменяющих API-вызовов
+	AuditView = "audit:view"
+
+	// Чтение плоской выгрузки заявок для BI (Power BI, Metabase)
+	AnalyticsRead = "analytics:read"
+
 	// Active Directory
 	UserManageADLink = "user:manage_ad_link"
 
+	// Вход под другим пользователем для разбора проблем с правами (POST /admin/impersonate/:userID)
+	UsersImpersonate = "user:impersonate"
+
+	// Необратимое обезличивание уволенного сотрудника (POST /admin/users/:id/anonymize)
+	UsersAnonymize = "user:anonymize"
+
+	// Правила хранения, удержание заявок и запуск очистки
+	RetentionManage = "retention:manage"
+
+	// Резервные копии БД: запуск pg_dump и проверка восстановления
+	BackupManage = "backup:manage"
+
+	// Рабочий календарь для SLA: рабочие дни, часы и праздники
+	BusinessCalendarManage = "business_calendar:manage"
+
+	// Статистика WebSocket-хаба, список соединений пользователя и принудительное отключение
+	WebSocketManage = "websocket:manage"
+
 	EquipmentsImport = "equipment:import"
 )
@@ -0,0 +1,153 @@
+package authz
+
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"request-system/internal/entities"
+)
+
+// ScopeQueryBuilder переводит области видимости (scope:*) и условия права в SQL-фильтр
+// по таблице заявок. Один и тот же построитель используют список заявок, выгрузка,
+// отчёт и дашборд, чтобы правила видимости не расходились между ними.
+type ScopeQueryBuilder struct {
+	alias         string
+	participation bool
+}
+
+// NewScopeQueryBuilder создаёт построитель для таблицы orders под псевдонимом alias.
+func NewScopeQueryBuilder(alias string) ScopeQueryBuilder {
+	return ScopeQueryBuilder{alias: alias}
+}
+
+// WithParticipation расширяет область «свои»: кроме созданных и назначенных видны заявки,
+// в истории которых пользователь участвовал, и заявки его команд.
+func (b ScopeQueryBuilder) WithParticipation() ScopeQueryBuilder {
+	b.participation = true
+	return b
+}
+
+func (b ScopeQueryBuilder) column(name string) string {
+	if b.alias == "" {
+		return name
+	}
+	return b.alias + "." + name
+}
+
+// ScopeCondition — условие одной области видимости; nil, если у пользователя нет
+// подразделения, к которому она привязана, или область не ограничивает заявки.
+func (b ScopeQueryBuilder) ScopeCondition(scope string, actor *entities.User) sq.Sqlizer {
+	if actor == nil {
+		return nil
+	}
+	unit := func(column string, id *uint64) sq.Sqlizer {
+		if id == nil {
+			return nil
+		}
+		return sq.Eq{b.column(column): *id}
+	}
+
+	switch scope {
+	case ScopeDepartment:
+		return unit("department_id", actor.DepartmentID)
+	case ScopeBranch:
+		return unit("branch_id", actor.BranchID)
+	case ScopeOtdel:
+		return unit("otdel_id", actor.OtdelID)
+	case ScopeOffice:
+		return unit("office_id", actor.OfficeID)
+	case ScopeOwn:
+		own := sq.Or{
+			sq.Eq{b.column("user_id"): actor.ID},
+			sq.Eq{b.column("executor_id"): actor.ID},
+		}
+		if b.participation {
+			own = append(own,
+				sq.Expr(b.column("id")+" IN (SELECT DISTINCT order_id FROM order_history WHERE user_id = ?)", actor.ID),
+				sq.Expr(b.column("team_id")+" IN (SELECT team_id FROM team_members WHERE user_id = ?)", actor.ID),
+			)
+		}
+		return own
+	}
+	return nil
+}
+
+// Scopes объединяет через ИЛИ все области пользователя. nil — пользователь видит все заявки;
+// пустой sq.Or{} — ни одной.
+func (b ScopeQueryBuilder) Scopes(ctx Context) sq.Sqlizer {
+	if ctx.HasPermission(ScopeAll) || ctx.HasPermission(ScopeAllView) {
+		return nil
+	}
+	scopes := sq.Or{}
+	for _, scope := range []string{ScopeDepartment, ScopeBranch, ScopeOtdel, ScopeOffice, ScopeOwn} {
+		if !ctx.HasPermission(scope) {
+			continue
+		}
+		if condition := b.ScopeCondition(scope, ctx.Actor); condition != nil {
+			scopes = append(scopes, condition)
+		}
+	}
+	return scopes
+}
+
+// Conditions переводит условия права (ABAC) в фильтр заявок; nil — условий нет или одно
+// из действующих сейчас условий не ограничивает заявки.
+func (b ScopeQueryBuilder) Conditions(ctx Context, permission string, now time.Time) sq.Sqlizer {
+	conditions := ctx.Conditions[permission]
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	alternatives := sq.Or{}
+	for _, condition := range conditions {
+		if !condition.HoldsAt(now) {
+			continue
+		}
+		filter := sq.And{}
+		if condition.HasPriorityLimit() {
+			filter = append(filter, sq.Eq{b.column("priority_id"): condition.PriorityIDs})
+		}
+		if len(condition.OrderTypeIDs) > 0 {
+			filter = append(filter, sq.Eq{b.column("order_type_id"): condition.OrderTypeIDs})
+		}
+		units := []struct {
+			enabled bool
+			column  string
+			actorID func(*entities.User) *uint64
+		}{
+			{condition.SameDepartment, "department_id", func(u *entities.User) *uint64 { return u.DepartmentID }},
+			{condition.SameBranch, "branch_id", func(u *entities.User) *uint64 { return u.BranchID }},
+			{condition.SameOtdel, "otdel_id", func(u *entities.User) *uint64 { return u.OtdelID }},
+			{condition.SameOffice, "office_id", func(u *entities.User) *uint64 { return u.OfficeID }},
+		}
+		for _, unit := range units {
+			if !unit.enabled {
+				continue
+			}
+			if ctx.Actor == nil || unit.actorID(ctx.Actor) == nil {
+				filter = append(filter, sq.Expr("FALSE"))
+				continue
+			}
+			filter = append(filter, sq.Eq{b.column(unit.column): *unit.actorID(ctx.Actor)})
+		}
+		if len(filter) == 0 {
+			return nil
+		}
+		alternatives = append(alternatives, filter)
+	}
+	return alternatives
+}
+
+// Build — области видимости И условия права permission; nil — без ограничений.
+func (b ScopeQueryBuilder) Build(ctx Context, permission string, now time.Time) sq.Sqlizer {
+	scopes := b.Scopes(ctx)
+	conditions := b.Conditions(ctx, permission, now)
+	switch {
+	case scopes == nil:
+		return conditions
+	case conditions == nil:
+		return scopes
+	}
+	return sq.And{scopes, conditions}
+}
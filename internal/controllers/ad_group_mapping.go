@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type ADGroupMappingController struct {
+	service services.ADGroupSyncServiceInterface
+	logger  *zap.Logger
+}
+
+func NewADGroupMappingController(service services.ADGroupSyncServiceInterface, logger *zap.Logger) *ADGroupMappingController {
+	return &ADGroupMappingController{service: service, logger: logger}
+}
+
+func (c *ADGroupMappingController) GetAll(ctx echo.Context) error {
+	result, err := c.service.ListMappings(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Сопоставления групп AD получены", http.StatusOK)
+}
+
+func (c *ADGroupMappingController) Create(ctx echo.Context) error {
+	var d dto.CreateADGroupMappingDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateMapping(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Сопоставление группы AD создано", http.StatusCreated)
+}
+
+func (c *ADGroupMappingController) Delete(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+	if err := c.service.DeleteMapping(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Сопоставление группы AD удалено", http.StatusOK)
+}
+
+func (c *ADGroupMappingController) Sync(ctx echo.Context) error {
+	result, err := c.service.RunSync(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Синхронизация групп AD выполнена", http.StatusOK)
+}
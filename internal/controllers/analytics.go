@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type AnalyticsController struct {
+	service services.AnalyticsServiceInterface
+	logger  *zap.Logger
+}
+
+func NewAnalyticsController(service services.AnalyticsServiceInterface, logger *zap.Logger) *AnalyticsController {
+	return &AnalyticsController{service: service, logger: logger}
+}
+
+// GetOrderFacts — плоская выгрузка заявок для BI. Параметры: after_id, limit,
+// created_from/created_to, updated_from/updated_to (RFC3339 или YYYY-MM-DD; дата *_to включается целиком).
+// @Summary     Плоская выгрузка заявок для BI
+// @Description Постраничная выборка по возрастанию id: передавайте after_id=next_after_id, пока has_more=true.
+// @Tags        integrations
+// @Param       after_id query int false "Вернуть заявки с id больше этого"
+// @Param       limit query int false "Размер страницы (по умолчанию 1000, максимум 10000)"
+// @Param       created_from query string false "RFC3339 или YYYY-MM-DD"
+// @Param       created_to query string false "Дата включается целиком"
+// @Param       updated_from query string false "RFC3339 или YYYY-MM-DD"
+// @Param       updated_to query string false "Дата включается целиком"
+// @Success     200 {object} dto.AnalyticsOrdersPageDTO
+// @Permission  analytics:read
+// @Security    ApiKeyAuth
+// @Security    BearerAuth
+// @Router      /analytics/orders [get]
+func (c *AnalyticsController) GetOrderFacts(ctx echo.Context) error {
+	filter := dto.AnalyticsOrderFilterDTO{}
+
+	for name, target := range map[string]*uint64{"after_id": &filter.AfterID, "limit": &filter.Limit} {
+		raw := strings.TrimSpace(ctx.QueryParam(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат "+name, err, nil), c.logger)
+		}
+		*target = value
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseAuditDate(ctx.QueryParam("created_from"), false); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат created_from", err, nil), c.logger)
+	}
+	if filter.CreatedTo, err = parseAuditDate(ctx.QueryParam("created_to"), true); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат created_to", err, nil), c.logger)
+	}
+	if filter.UpdatedFrom, err = parseAuditDate(ctx.QueryParam("updated_from"), false); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат updated_from", err, nil), c.logger)
+	}
+	if filter.UpdatedTo, err = parseAuditDate(ctx.QueryParam("updated_to"), true); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат updated_to", err, nil), c.logger)
+	}
+
+	page, err := c.service.GetOrderFacts(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, page, "Выгрузка заявок получена", http.StatusOK)
+}
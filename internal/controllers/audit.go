@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type AuditController struct {
+	service services.AuditServiceInterface
+	logger  *zap.Logger
+}
+
+func NewAuditController(service services.AuditServiceInterface, logger *zap.Logger) *AuditController {
+	return &AuditController{service: service, logger: logger}
+}
+
+// GetAuditLog — журнал аудита. Фильтры: actor_id, entity, entity_id, action,
+// date_from, date_to (RFC3339 или YYYY-MM-DD; дата date_to включается целиком),
+// impersonated=true и impersonator_id — запросы, сделанные под чужой учётной записью.
+func (c *AuditController) GetAuditLog(ctx echo.Context) error {
+	pagination := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	filter := repositories.AuditLogFilter{
+		Entity:       ctx.QueryParam("entity"),
+		EntityID:     ctx.QueryParam("entity_id"),
+		Action:       ctx.QueryParam("action"),
+		Impersonated: ctx.QueryParam("impersonated") == "true",
+		Limit:        pagination.Limit,
+		Offset:       pagination.Offset,
+	}
+
+	if raw := ctx.QueryParam("actor_id"); raw != "" {
+		actorID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат actor_id", err, nil), c.logger)
+		}
+		filter.ActorID = &actorID
+	}
+	if raw := ctx.QueryParam("impersonator_id"); raw != "" {
+		impersonatorID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат impersonator_id", err, nil), c.logger)
+		}
+		filter.ImpersonatorID = &impersonatorID
+	}
+	switch filter.Action {
+	case "", "create", "update", "delete", "impersonate", "read":
+	default:
+		return utils.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверное значение action"), c.logger)
+	}
+
+	var err error
+	if filter.From, err = parseAuditDate(ctx.QueryParam("date_from"), false); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат date_from", err, nil), c.logger)
+	}
+	if filter.To, err = parseAuditDate(ctx.QueryParam("date_to"), true); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат date_to", err, nil), c.logger)
+	}
+
+	result, err := c.service.GetAuditLog(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Журнал аудита получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func parseAuditDate(raw string, endOfDay bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"time"
 
@@ -47,6 +48,15 @@ func (ctrl *AuthController) errorResponse(c echo.Context, err error) error {
 	return utils.ErrorResponse(c, err, ctrl.logger)
 }
 
+// @Summary     Вход по логину и паролю
+// @Description Refresh-токен приходит в httpOnly cookie refreshToken.
+// @Tags        auth
+// @Param       body body dto.LoginDTO true "Учётные данные"
+// @Success     200 {object} dto.AuthResponseDTO
+// @Failure     401 "Неверные учётные данные"
+// @Failure     429 "Превышен лимит запросов"
+// @Security    none
+// @Router      /auth/login [post]
 func (ctrl *AuthController) Login(c echo.Context) error {
 	var payload dto.LoginDTO
 
@@ -72,9 +82,13 @@ func (ctrl *AuthController) Login(c echo.Context) error {
 		permissions = []string{}
 	}
 
-	return ctrl.generateTokensAndRespond(c, user.ID, permissions, "Авторизация прошла успешно", payload.RememberMe)
+	return ctrl.generateTokensAndRespond(c, user.ID, user.TenantID, permissions, "Авторизация прошла успешно", payload.RememberMe)
 }
 
+// @Summary     Выход: сбрасывает cookie refreshToken
+// @Tags        auth
+// @Success     200
+// @Router      /auth/logout [post]
 func (ctrl *AuthController) Logout(c echo.Context) error {
 	cookie := &http.Cookie{
 		Name:     "refreshToken",
@@ -92,6 +106,13 @@ func (ctrl *AuthController) Logout(c echo.Context) error {
 	return utils.SuccessResponse(c, nil, "Вы успешно вышли из системы.", http.StatusOK)
 }
 
+// @Summary     Обновление access-токена
+// @Description Refresh-токен берётся из cookie refreshToken.
+// @Tags        auth
+// @Success     200 {object} dto.AuthResponseDTO
+// @Failure     401 "Нет или недействителен refresh-токен"
+// @Security    none
+// @Router      /auth/refresh_token [post]
 func (ctrl *AuthController) RefreshToken(c echo.Context) error {
 	cookie, err := c.Cookie("refreshToken")
 	if err != nil {
@@ -126,12 +147,17 @@ func (ctrl *AuthController) RefreshToken(c echo.Context) error {
 	return ctrl.generateTokensAndRespond(
 		c,
 		claims.UserID,
+		claims.TenantID,
 		permissions,
 		"Токены успешно обновлены",
 		true,
 	)
 }
 
+// @Summary     Профиль текущего пользователя
+// @Tags        auth
+// @Success     200 {object} dto.UserProfileDTO
+// @Router      /auth/me [get]
 func (ctrl *AuthController) Me(c echo.Context) error {
 	userID, ok := c.Request().Context().Value(contextkeys.UserIDKey).(uint64)
 	if !ok || userID == 0 {
@@ -148,6 +174,12 @@ func (ctrl *AuthController) Me(c echo.Context) error {
 	return utils.SuccessResponse(c, userProfile, "Профиль пользователя успешно получен", http.StatusOK)
 }
 
+// @Summary     Запрос на сброс пароля
+// @Tags        auth
+// @Param       body body dto.ResetPasswordRequestDTO true "Логин или телефон"
+// @Success     200
+// @Security    none
+// @Router      /auth/password/request [post]
 func (ctrl *AuthController) RequestPasswordReset(c echo.Context) error {
 	var payload dto.ResetPasswordRequestDTO
 	if err := c.Bind(&payload); err != nil {
@@ -167,6 +199,12 @@ func (ctrl *AuthController) RequestPasswordReset(c echo.Context) error {
 	return utils.SuccessResponse(c, nil, "Если пользователь существует, инструкция будет отправлена.", http.StatusOK)
 }
 
+// @Summary     Проверка кода сброса пароля
+// @Tags        auth
+// @Param       body body dto.VerifyCodeDTO true "Код подтверждения"
+// @Success     200 {object} dto.VerifyCodeResponseDTO
+// @Security    none
+// @Router      /auth/password/verify_phone [post]
 func (ctrl *AuthController) VerifyCode(c echo.Context) error {
 	var payload dto.VerifyCodeDTO
 	if err := c.Bind(&payload); err != nil {
@@ -184,6 +222,12 @@ func (ctrl *AuthController) VerifyCode(c echo.Context) error {
 	return utils.SuccessResponse(c, response, "Код подтвержден.", http.StatusOK)
 }
 
+// @Summary     Установка нового пароля
+// @Tags        auth
+// @Param       body body dto.ResetPasswordDTO true "Токен сброса и новый пароль"
+// @Success     200
+// @Security    none
+// @Router      /auth/password/reset [post]
 func (ctrl *AuthController) ResetPassword(c echo.Context) error {
 	var payload dto.ResetPasswordDTO
 	if err := c.Bind(&payload); err != nil {
@@ -199,7 +243,7 @@ func (ctrl *AuthController) ResetPassword(c echo.Context) error {
 	return utils.SuccessResponse(c, nil, "Пароль успешно изменен.", http.StatusOK)
 }
 
-func (ctrl *AuthController) generateTokensAndRespond(c echo.Context, userID uint64, permissions []string, message string, rememberMe bool) error {
+func (ctrl *AuthController) generateTokensAndRespond(c echo.Context, userID, tenantID uint64, permissions []string, message string, rememberMe bool) error {
 	accessTokenTTL := ctrl.jwtSvc.GetAccessTokenTTL()
 	var refreshTokenTTL time.Duration
 
@@ -209,7 +253,7 @@ func (ctrl *AuthController) generateTokensAndRespond(c echo.Context, userID uint
 		refreshTokenTTL = time.Hour * 8
 	}
 
-	accessToken, refreshToken, err := ctrl.jwtSvc.GenerateTokens(userID, 0, accessTokenTTL, refreshTokenTTL)
+	accessToken, refreshToken, err := ctrl.jwtSvc.GenerateTokens(userID, 0, tenantID, accessTokenTTL, refreshTokenTTL)
 	if err != nil {
 		ctrl.logger.Error("Не удалось сгенерировать токены", zap.Error(err), zap.Uint64("userID", userID))
 		return ctrl.errorResponse(c, err)
@@ -237,6 +281,12 @@ func (ctrl *AuthController) generateTokensAndRespond(c echo.Context, userID uint
 	return utils.SuccessResponse(c, response, message, http.StatusOK)
 }
 
+// @Summary     Обновление своего профиля
+// @Tags        auth
+// @Param       data formData dto.UpdateMyProfileDTO false "JSON с изменяемыми полями"
+// @Param       photoFile formData file false "Фото профиля"
+// @Success     200 {object} dto.UserDTO
+// @Router      /auth/me [put]
 func (ctrl *AuthController) UpdateMe(c echo.Context) error {
 	reqCtx := c.Request().Context()
 	var payload dto.UpdateMyProfileDTO
@@ -283,6 +333,36 @@ func (ctrl *AuthController) UpdateMe(c echo.Context) error {
 	return utils.SuccessResponse(c, updatedUser, "Профиль обновлен", http.StatusOK)
 }
 
+// @Summary     Загрузка аватара
+// @Tags        auth
+// @Param       avatar formData file true "Изображение JPEG, PNG или GIF"
+// @Success     200 {object} dto.AvatarDTO
+// @Router      /me/avatar [post]
+func (ctrl *AuthController) UploadAvatar(c echo.Context) error {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return ctrl.errorResponse(c, apperrors.NewHttpError(http.StatusBadRequest, "Файл avatar не передан", err, nil))
+	}
+	src, err := file.Open()
+	if err != nil {
+		return ctrl.errorResponse(c, apperrors.ErrInternalServer)
+	}
+	defer src.Close()
+	if err := validation.ValidateFile(file, src, "avatar"); err != nil {
+		return ctrl.errorResponse(c, apperrors.NewHttpError(http.StatusBadRequest, "Файл не прошел валидацию", err, nil))
+	}
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return ctrl.errorResponse(c, apperrors.NewHttpError(http.StatusBadRequest, "Ошибка при чтении файла", err, nil))
+	}
+
+	result, err := ctrl.authService.UploadMyAvatar(c.Request().Context(), content)
+	if err != nil {
+		return ctrl.errorResponse(c, err)
+	}
+	return utils.SuccessResponse(c, result, "Аватар обновлен", http.StatusOK)
+}
+
 func (c *AuthController) handlePhotoUpload(ctx echo.Context, uploadContext string) (*string, error) {
 	file, err := ctx.FormFile("photoFile")
 	if err != nil {
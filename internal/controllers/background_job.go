@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	"request-system/pkg/utils"
+)
+
+type BackgroundJobController struct {
+	service services.BackgroundJobServiceInterface
+	logger  *zap.Logger
+}
+
+func NewBackgroundJobController(service services.BackgroundJobServiceInterface, logger *zap.Logger) *BackgroundJobController {
+	return &BackgroundJobController{service: service, logger: logger}
+}
+
+// List — GET /admin/jobs?state=dead|scheduled|running (по умолчанию dead).
+func (c *BackgroundJobController) List(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.List(ctx.Request().Context(), ctx.QueryParam("state"), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	return utils.SuccessResponse(
+		ctx,
+		result.List,
+		"Список фоновых заданий получен",
+		http.StatusOK,
+		result.Pagination.TotalCount,
+	)
+}
+
+func (c *BackgroundJobController) Retry(ctx echo.Context) error {
+	job, err := c.service.Retry(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, job, "Задание поставлено в очередь повторно", http.StatusOK)
+}
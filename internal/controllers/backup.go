@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type BackupController struct {
+	service services.BackupServiceInterface
+	logger  *zap.Logger
+}
+
+func NewBackupController(service services.BackupServiceInterface, logger *zap.Logger) *BackupController {
+	return &BackupController{service: service, logger: logger}
+}
+
+func (c *BackupController) GetBackups(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetBackups(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Журнал резервных копий получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func (c *BackupController) GetBackup(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+	result, err := c.service.GetBackup(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Резервная копия получена", http.StatusOK)
+}
+
+// Trigger ставит копию в очередь; результат появится в GET /admin/backups.
+func (c *BackupController) Trigger(ctx echo.Context) error {
+	result, err := c.service.Trigger(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Резервное копирование поставлено в очередь", http.StatusAccepted)
+}
+
+// Verify ставит в очередь проверку восстановления последней успешной копии.
+func (c *BackupController) Verify(ctx echo.Context) error {
+	result, err := c.service.TriggerVerify(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Проверка резервной копии поставлена в очередь", http.StatusAccepted)
+}
@@ -22,6 +22,17 @@ func NewBranchController(service services.BranchServiceInterface, logger *zap.Lo
 	return &BranchController{branchService: service, logger: logger}
 }
 
+// @Summary     Список: филиалы
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        branches
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.BranchListResponseDTO
+// @Success     304 "Не изменился"
+// @Permission  branch:view
+// @Router      /branch [get]
 func (c *BranchController) GetBranches(ctx echo.Context) error {
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
 	branches, total, err := c.branchService.GetBranches(ctx.Request().Context(), filter)
@@ -32,6 +43,15 @@ func (c *BranchController) GetBranches(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, branches, "Список филиалов успешно получен", http.StatusOK, total)
 }
 
+// @Summary     Получение по ID: филиалы
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        branches
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.BranchDTO
+// @Success     304 "Не изменился"
+// @Failure     404 "Не найдено"
+// @Permission  branch:view
+// @Router      /branch/{id} [get]
 func (c *BranchController) FindBranch(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -55,6 +75,12 @@ func (c *BranchController) FindBranch(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Филиал успешно найден", http.StatusOK)
 }
 
+// @Summary     Создание: филиалы
+// @Tags        branches
+// @Param       body body dto.CreateBranchDTO true "Данные"
+// @Success     201 {object} dto.BranchDTO
+// @Permission  branch:create
+// @Router      /branch [post]
 func (c *BranchController) CreateBranch(ctx echo.Context) error {
 	var dto dto.CreateBranchDTO
 	if err := ctx.Bind(&dto); err != nil {
@@ -82,6 +108,13 @@ func (c *BranchController) CreateBranch(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Филиал успешно создан", http.StatusCreated)
 }
 
+// @Summary     Обновление: филиалы
+// @Tags        branches
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateBranchDTO true "Изменяемые поля"
+// @Success     200 {object} dto.BranchDTO
+// @Permission  branch:update
+// @Router      /branch/{id} [put]
 func (c *BranchController) UpdateBranch(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -123,6 +156,12 @@ func (c *BranchController) UpdateBranch(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Филиал успешно обновлен", http.StatusOK)
 }
 
+// @Summary     Удаление: филиалы
+// @Tags        branches
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  branch:delete
+// @Router      /branch/{id} [delete]
 func (c *BranchController) DeleteBranch(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type BusinessCalendarController struct {
+	service services.BusinessCalendarServiceInterface
+	logger  *zap.Logger
+}
+
+func NewBusinessCalendarController(service services.BusinessCalendarServiceInterface, logger *zap.Logger) *BusinessCalendarController {
+	return &BusinessCalendarController{service: service, logger: logger}
+}
+
+func (c *BusinessCalendarController) GetCalendar(ctx echo.Context) error {
+	result, err := c.service.GetCalendar(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Рабочий календарь получен", http.StatusOK)
+}
+
+func (c *BusinessCalendarController) UpdateCalendar(ctx echo.Context) error {
+	var d dto.UpdateBusinessCalendarDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateCalendar(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Рабочий календарь обновлён", http.StatusOK)
+}
+
+func (c *BusinessCalendarController) GetHolidays(ctx echo.Context) error {
+	year := 0
+	if raw := ctx.QueryParam("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный год", err, nil), c.logger)
+		}
+		year = parsed
+	}
+	result, err := c.service.GetHolidays(ctx.Request().Context(), year)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Праздники получены", http.StatusOK)
+}
+
+func (c *BusinessCalendarController) CreateHoliday(ctx echo.Context) error {
+	var d dto.CreateBusinessHolidayDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateHoliday(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "День добавлен в календарь", http.StatusCreated)
+}
+
+func (c *BusinessCalendarController) UpdateHoliday(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+	var d dto.UpdateBusinessHolidayDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateHoliday(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "День календаря обновлён", http.StatusOK)
+}
+
+func (c *BusinessCalendarController) DeleteHoliday(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+	if err := c.service.DeleteHoliday(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "День удалён из календаря", http.StatusOK)
+}
+
+// Measure — сколько рабочего времени между from и to (RFC3339 или ГГГГ-ММ-ДД).
+func (c *BusinessCalendarController) Measure(ctx echo.Context) error {
+	from, err := parseAuditDate(ctx.QueryParam("from"), false)
+	if err != nil || from == nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат from", err, nil), c.logger)
+	}
+	to, err := parseAuditDate(ctx.QueryParam("to"), true)
+	if err != nil || to == nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат to", err, nil), c.logger)
+	}
+	result, err := c.service.Measure(ctx.Request().Context(), *from, *to)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Рабочее время рассчитано", http.StatusOK)
+}
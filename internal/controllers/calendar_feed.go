@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	"request-system/pkg/utils"
+)
+
+type CalendarFeedController struct {
+	service services.CalendarFeedServiceInterface
+	logger  *zap.Logger
+}
+
+func NewCalendarFeedController(service services.CalendarFeedServiceInterface, logger *zap.Logger) *CalendarFeedController {
+	return &CalendarFeedController{service: service, logger: logger}
+}
+
+func (c *CalendarFeedController) GetMy(ctx echo.Context) error {
+	result, err := c.service.GetMyFeed(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Ссылка на календарь получена", http.StatusOK)
+}
+
+// RotateMy выпускает новую ссылку; прежняя перестаёт работать.
+func (c *CalendarFeedController) RotateMy(ctx echo.Context) error {
+	result, err := c.service.RotateMyToken(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Выпущена новая ссылка на календарь", http.StatusOK)
+}
+
+func (c *CalendarFeedController) RevokeMy(ctx echo.Context) error {
+	if err := c.service.RevokeMyToken(ctx.Request().Context()); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Ссылка на календарь отозвана", http.StatusOK)
+}
+
+// Feed — сам календарь. Авторизация по токену из ссылки, JWT не нужен.
+func (c *CalendarFeedController) Feed(ctx echo.Context) error {
+	body, err := c.service.RenderFeed(ctx.Request().Context(), ctx.QueryParam("token"))
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	h := ctx.Response().Header()
+	h.Set(echo.HeaderContentDisposition, `inline; filename="orders.ics"`)
+	h.Set(echo.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", int(services.CalendarRefreshInterval.Seconds())))
+	h.Set("X-Robots-Tag", "noindex")
+	return ctx.Blob(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
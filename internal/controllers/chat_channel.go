@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type ChatChannelController struct {
+	service services.ChatConnectorServiceInterface
+	logger  *zap.Logger
+}
+
+func NewChatChannelController(service services.ChatConnectorServiceInterface, logger *zap.Logger) *ChatChannelController {
+	return &ChatChannelController{service: service, logger: logger}
+}
+
+func (c *ChatChannelController) parseID(ctx echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil)
+	}
+	return id, nil
+}
+
+func (c *ChatChannelController) Create(ctx echo.Context) error {
+	var d dto.CreateChatChannelDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateChannel(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Канал оповещений создан", http.StatusCreated)
+}
+
+func (c *ChatChannelController) Update(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateChatChannelDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateChannel(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Канал оповещений обновлён", http.StatusOK)
+}
+
+func (c *ChatChannelController) Delete(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteChannel(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Канал оповещений удалён", http.StatusOK)
+}
+
+func (c *ChatChannelController) GetAll(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetChannels(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Список каналов оповещений получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func (c *ChatChannelController) GetByID(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetChannel(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Канал оповещений получен", http.StatusOK)
+}
+
+// GetEventTypes — события, которые можно включить для канала.
+func (c *ChatChannelController) GetEventTypes(ctx echo.Context) error {
+	return utils.SuccessResponse(ctx, services.ChatEventTypes, "Список событий получен", http.StatusOK)
+}
+
+func (c *ChatChannelController) Test(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.TestChannel(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Пробное сообщение отправлено", http.StatusOK)
+}
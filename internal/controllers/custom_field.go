@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type CustomFieldController struct {
+	service services.CustomFieldServiceInterface
+	logger  *zap.Logger
+}
+
+func NewCustomFieldController(service services.CustomFieldServiceInterface, logger *zap.Logger) *CustomFieldController {
+	return &CustomFieldController{service: service, logger: logger}
+}
+
+func (c *CustomFieldController) GetAll(ctx echo.Context) error {
+	orderTypeID, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetFields(ctx.Request().Context(), orderTypeID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Дополнительные поля получены", http.StatusOK)
+}
+
+func (c *CustomFieldController) Create(ctx echo.Context) error {
+	orderTypeID, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.CreateCustomFieldDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateField(ctx.Request().Context(), orderTypeID, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Дополнительное поле создано", http.StatusCreated)
+}
+
+func (c *CustomFieldController) Update(ctx echo.Context) error {
+	orderTypeID, fieldID, err := parseCustomFieldIDs(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateCustomFieldDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateField(ctx.Request().Context(), orderTypeID, fieldID, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Дополнительное поле обновлено", http.StatusOK)
+}
+
+func (c *CustomFieldController) Delete(ctx echo.Context) error {
+	orderTypeID, fieldID, err := parseCustomFieldIDs(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteField(ctx.Request().Context(), orderTypeID, fieldID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Дополнительное поле удалено", http.StatusOK)
+}
+
+func parseCustomFieldIDs(ctx echo.Context) (uint64, uint64, error) {
+	orderTypeID, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	fieldID, err := strconv.ParseUint(ctx.Param("fieldID"), 10, 64)
+	if err != nil {
+		return 0, 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID поля", err, nil)
+	}
+	return orderTypeID, fieldID, nil
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -9,6 +10,7 @@ import (
 
 	"request-system/internal/dto"
 	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
 	"request-system/pkg/utils"
 )
 
@@ -24,10 +26,78 @@ func NewDashboardController(ds *services.DashboardService, logger *zap.Logger) *
 	}
 }
 
+// @Summary     Дашборд
+// @Tags        dashboard
+// @Param       period query string false "Период: today, 7d, 14d, 30d, month (по умолчанию), custom"
+// @Param       dateFrom query string false "Начало периода для custom (YYYY-MM-DD)"
+// @Param       dateTo query string false "Конец периода для custom (YYYY-MM-DD)"
+// @Param       granularity query string false "Шаг графиков: day, week, month"
+// @Param       widgets query string false "Список виджетов через запятую"
+// @Param       scope query string false "Сузить область: own, office, otdel, branch, department"
+// @Success     200 {object} dto.DashboardStatsDTO
+// @Permission  dashboard:view
+// @Router      /dashboard [get]
 func (ctrl *DashboardController) GetDashboardStats(c echo.Context) error {
+	filter := parseDashboardFilter(c)
+
+	stats, err := ctrl.dashboardService.GetDashboardStats(c.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(c, err, ctrl.logger)
+	}
+
+	return utils.SuccessResponse(c, stats, "Статистика для дашборда получена", http.StatusOK)
+}
+
+// @Summary     Рейтинг исполнителей
+// @Tags        dashboard
+// @Param       period query string false "Период: today, 7d, 14d, 30d, month (по умолчанию), custom"
+// @Param       dateFrom query string false "Начало периода для custom (YYYY-MM-DD)"
+// @Param       dateTo query string false "Конец периода для custom (YYYY-MM-DD)"
+// @Param       sort query string false "closed, resolution, sla, reopen или load"
+// @Param       limit query int false "По умолчанию 20, максимум 100"
+// @Success     200 {object} dto.DashboardExecutorsDTO
+// @Permission  dashboard:view
+// @Router      /dashboard/executors [get]
+func (ctrl *DashboardController) GetExecutorLeaderboard(c echo.Context) error {
+	filter := dto.DashboardExecutorFilterDTO{
+		DashboardFilterDTO: parseDashboardFilter(c),
+		SortBy:             strings.TrimSpace(c.QueryParam("sort")),
+	}
+	if rawLimit := strings.TrimSpace(c.QueryParam("limit")); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			return utils.ErrorResponse(c, apperrors.NewBadRequestError("Некорректный limit"), ctrl.logger)
+		}
+		filter.Limit = limit
+	}
+
+	result, err := ctrl.dashboardService.GetExecutorLeaderboard(c.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(c, err, ctrl.logger)
+	}
+
+	return utils.SuccessResponse(c, result, "Показатели исполнителей получены", http.StatusOK)
+}
+
+// @Summary     Возраст открытых заявок
+// @Tags        dashboard
+// @Success     200 {object} dto.DashboardBacklogAgingDTO
+// @Permission  dashboard:view
+// @Router      /dashboard/aging [get]
+func (ctrl *DashboardController) GetBacklogAging(c echo.Context) error {
+	result, err := ctrl.dashboardService.GetBacklogAging(c.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(c, err, ctrl.logger)
+	}
+
+	return utils.SuccessResponse(c, result, "Возраст бэклога получен", http.StatusOK)
+}
+
+func parseDashboardFilter(c echo.Context) dto.DashboardFilterDTO {
 	filter := dto.DashboardFilterDTO{}
 	filter.Period = strings.TrimSpace(c.QueryParam("period"))
 	filter.Granularity = strings.TrimSpace(c.QueryParam("granularity"))
+	filter.Scope = strings.TrimSpace(c.QueryParam("scope"))
 
 	if widgets := strings.TrimSpace(c.QueryParam("widgets")); widgets != "" {
 		for _, widget := range strings.Split(widgets, ",") {
@@ -50,10 +120,5 @@ func (ctrl *DashboardController) GetDashboardStats(c echo.Context) error {
 		filter.DateTo = dateTo
 	}
 
-	stats, err := ctrl.dashboardService.GetDashboardStats(c.Request().Context(), filter)
-	if err != nil {
-		return utils.ErrorResponse(c, err, ctrl.logger)
-	}
-
-	return utils.SuccessResponse(c, stats, "Статистика для дашборда получена", http.StatusOK)
+	return filter
 }
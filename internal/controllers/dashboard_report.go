@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	"request-system/pkg/utils"
+)
+
+type DashboardReportController struct {
+	service services.DashboardReportServiceInterface
+	logger  *zap.Logger
+}
+
+func NewDashboardReportController(service services.DashboardReportServiceInterface, logger *zap.Logger) *DashboardReportController {
+	return &DashboardReportController{service: service, logger: logger}
+}
+
+// GetDashboardReport отдаёт основные блоки дашборда одним файлом в оформлении организации.
+// @Summary     Отчёт по дашборду в PDF или PNG
+// @Description KPI, поступление заявок, статусы, время решения, исполнители, категории, департаменты и филиалы за период и в области, как у GET /dashboard.
+// @Tags        dashboard
+// @Param       format query string false "pdf (по умолчанию) или png"
+// @Param       period query string false "Период: today, 7d, 14d, 30d, month (по умолчанию), custom"
+// @Param       dateFrom query string false "Начало периода для custom (YYYY-MM-DD)"
+// @Param       dateTo query string false "Конец периода для custom (YYYY-MM-DD)"
+// @Param       granularity query string false "Шаг графика поступления: day, week, month"
+// @Param       scope query string false "Сузить область: own, office, otdel, branch, department"
+// @Success     200 {file} application/pdf
+// @Permission  dashboard:view
+// @Router      /dashboard/report [get]
+func (c *DashboardReportController) GetDashboardReport(ctx echo.Context) error {
+	report, err := c.service.RenderReport(ctx.Request().Context(), parseDashboardFilter(ctx), ctx.QueryParam("format"))
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, "inline; filename="+report.FileName)
+	return ctx.Blob(http.StatusOK, report.ContentType, report.Content)
+}
@@ -23,6 +23,17 @@ func NewDepartmentController(service services.DepartmentServiceInterface, logger
 	return &DepartmentController{departmentService: service, logger: logger}
 }
 
+// @Summary     Список: департаменты
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        departments
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.DepartmentDTO
+// @Success     304 "Не изменился"
+// @Permission  department:view
+// @Router      /department [get]
 func (c *DepartmentController) GetDepartments(ctx echo.Context) error {
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
 
@@ -35,6 +46,15 @@ func (c *DepartmentController) GetDepartments(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, departments, "Список департаментов успешно получен", http.StatusOK, total)
 }
 
+// @Summary     Получение по ID: департаменты
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        departments
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.DepartmentDTO
+// @Success     304 "Не изменился"
+// @Failure     404 "Не найдено"
+// @Permission  department:view
+// @Router      /department/{id} [get]
 func (c *DepartmentController) FindDepartment(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -69,6 +89,12 @@ func (c *DepartmentController) FindDepartment(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, dept, "Департамент успешно найден", http.StatusOK)
 }
 
+// @Summary     Создание: департаменты
+// @Tags        departments
+// @Param       body body dto.CreateDepartmentDTO true "Данные"
+// @Success     201 {object} dto.DepartmentDTO
+// @Permission  department:create
+// @Router      /department [post]
 func (c *DepartmentController) CreateDepartment(ctx echo.Context) error {
 	var dto dto.CreateDepartmentDTO
 	if err := ctx.Bind(&dto); err != nil {
@@ -96,6 +122,13 @@ func (c *DepartmentController) CreateDepartment(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Департамент успешно создан", http.StatusCreated)
 }
 
+// @Summary     Обновление: департаменты
+// @Tags        departments
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateDepartmentDTO true "Изменяемые поля"
+// @Success     200 {object} dto.DepartmentDTO
+// @Permission  department:update
+// @Router      /department/{id} [put]
 func (c *DepartmentController) UpdateDepartment(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -138,6 +171,12 @@ func (c *DepartmentController) UpdateDepartment(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Департамент успешно обновлен", http.StatusOK)
 }
 
+// @Summary     Удаление: департаменты
+// @Tags        departments
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  department:delete
+// @Router      /department/{id} [delete]
 func (c *DepartmentController) DeleteDepartment(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -164,6 +203,14 @@ func (c *DepartmentController) DeleteDepartment(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, nil, "Департамент успешно удален", http.StatusOK)
 }
 
+// @Summary     Департаменты со счётчиками заявок (главная страница)
+// @Tags        departments
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Success     200 {list} dto.DepartmentStatsDTO
+// @Permission  department:view
+// @Router      /main [get]
 func (c *DepartmentController) GetDepartmentStats(ctx echo.Context) error {
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
 	stats, total, err := c.departmentService.GetDepartmentStats(ctx.Request().Context(), filter)
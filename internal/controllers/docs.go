@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"request-system/internal/apidocs"
+)
+
+type DocsController struct {
+	swaggerUIAssetsURL string
+}
+
+func NewDocsController(swaggerUIAssetsURL string) *DocsController {
+	return &DocsController{swaggerUIAssetsURL: swaggerUIAssetsURL}
+}
+
+// GetSpec отдаёт OpenAPI спецификацию, собранную go generate ./internal/apidocs.
+func (c *DocsController) GetSpec(ctx echo.Context) error {
+	return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, apidocs.Spec)
+}
+
+func (c *DocsController) GetSwaggerUI(ctx echo.Context) error {
+	return ctx.HTML(http.StatusOK, apidocs.SwaggerUIPage("/api/docs/openapi.json", c.swaggerUIAssetsURL))
+}
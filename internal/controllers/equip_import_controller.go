@@ -30,7 +30,7 @@ func (c *EquipImportController) Import(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 
 	permissionsMap, err := utils.GetPermissionsMapFromCtx(reqCtx)
-	if err != nil || !authz.CanDo(authz.EquipmentsImport, authz.Context{Permissions: permissionsMap}) {
+	if err != nil || !authz.CanDo(authz.EquipmentsImport, authz.Context{Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(reqCtx)}) {
 		return utils.ErrorResponse(ctx, apperrors.ErrForbidden, c.logger)
 	}
 
@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"request-system/internal/dto"
 	"request-system/internal/services"
@@ -30,27 +31,36 @@ func NewEquipmentController(
 
 // ----- РАБОЧИЕ МЕТОДЫ КОНТРОЛЛЕРА -----
 
+// @Summary     Список: оборудование
+// @Tags        equipment
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск по названию, адресу и серийному номеру"
+// @Param       filter[state] query string false "Состояние: in_service, repair, written_off"
+// @Param       filter[assigned_user_id] query int false "Закреплено за пользователем"
+// @Success     200 {list} dto.EquipmentListResponseDTO
+// @Permission  equipment:view
+// @Router      /equipment [get]
 func (c *EquipmentController) GetEquipments(ctx echo.Context) error {
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
 
 	res, total, err := c.equipmentService.GetEquipments(ctx.Request().Context(), filter)
 	if err != nil {
 		c.logger.Error("GetEquipments: ошибка при получении списка оборудования", zap.Error(err))
-		return utils.ErrorResponse(
-			ctx,
-			apperrors.NewHttpError(
-				http.StatusInternalServerError,
-				"Не удалось получить список оборудования",
-				err,
-				nil,
-			),
-			c.logger,
-		)
+		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
 	return utils.SuccessResponse(ctx, res, "Список оборудования успешно получен", http.StatusOK, total)
 }
 
+// @Summary     Получение по ID: оборудование
+// @Description Карточка оборудования. При праве order:view включает все заявки по нему (новые первыми).
+// @Tags        equipment
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.EquipmentDTO
+// @Failure     404 "Не найдено"
+// @Permission  equipment:view
+// @Router      /equipment/{id} [get]
 func (c *EquipmentController) FindEquipment(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -70,21 +80,19 @@ func (c *EquipmentController) FindEquipment(ctx echo.Context) error {
 	res, err := c.equipmentService.FindEquipment(ctx.Request().Context(), id)
 	if err != nil {
 		c.logger.Error("FindEquipment: ошибка при поиске оборудования", zap.Uint64("id", id), zap.Error(err))
-		return utils.ErrorResponse(
-			ctx,
-			apperrors.NewHttpError(
-				http.StatusInternalServerError,
-				"Не удалось найти оборудование",
-				err,
-				nil,
-			),
-			c.logger,
-		)
+		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
 	return utils.SuccessResponse(ctx, res, "Оборудование успешно найдено", http.StatusOK)
 }
 
+// @Summary     Создание: оборудование
+// @Tags        equipment
+// @Param       body body dto.CreateEquipmentDTO true "Данные"
+// @Success     201 {object} dto.EquipmentDTO
+// @Failure     409 "Серийный номер уже занят"
+// @Permission  equipment:create
+// @Router      /equipment [post]
 func (c *EquipmentController) CreateEquipment(ctx echo.Context) error {
 	var dto dto.CreateEquipmentDTO
 	if err := ctx.Bind(&dto); err != nil {
@@ -109,21 +117,21 @@ func (c *EquipmentController) CreateEquipment(ctx echo.Context) error {
 	res, err := c.equipmentService.CreateEquipment(ctx.Request().Context(), dto)
 	if err != nil {
 		c.logger.Error("CreateEquipment: ошибка при создании оборудования", zap.Any("payload", dto), zap.Error(err))
-		return utils.ErrorResponse(
-			ctx,
-			apperrors.NewHttpError(
-				http.StatusInternalServerError,
-				"Не удалось создать оборудование",
-				err,
-				nil,
-			),
-			c.logger,
-		)
+		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
 	return utils.SuccessResponse(ctx, res, "Оборудование успешно создано", http.StatusCreated)
 }
 
+// @Summary     Обновление: оборудование
+// @Description Пустая строка в serial_number/датах и assigned_user_id=0 очищают значение. Состояние written_off окончательное; при списании закрепление снимается.
+// @Tags        equipment
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateEquipmentDTO true "Изменяемые поля"
+// @Success     200 {object} dto.EquipmentDTO
+// @Failure     409 "Недопустимая смена состояния"
+// @Permission  equipment:update
+// @Router      /equipment/{id} [put]
 func (c *EquipmentController) UpdateEquipment(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -163,21 +171,19 @@ func (c *EquipmentController) UpdateEquipment(ctx echo.Context) error {
 	res, err := c.equipmentService.UpdateEquipment(ctx.Request().Context(), id, dto)
 	if err != nil {
 		c.logger.Error("UpdateEquipment: ошибка при обновлении оборудования", zap.Uint64("id", id), zap.Any("payload", dto), zap.Error(err))
-		return utils.ErrorResponse(
-			ctx,
-			apperrors.NewHttpError(
-				http.StatusInternalServerError,
-				"Не удалось обновить оборудование",
-				err,
-				nil,
-			),
-			c.logger,
-		)
+		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
 	return utils.SuccessResponse(ctx, res, "Оборудование успешно обновлено", http.StatusOK)
 }
 
+// @Summary     Удаление: оборудование
+// @Tags        equipment
+// @Param       id path int true "ID"
+// @Success     200
+// @Failure     409 "Оборудование используется в заявках"
+// @Permission  equipment:delete
+// @Router      /equipment/{id} [delete]
 func (c *EquipmentController) DeleteEquipment(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -196,17 +202,79 @@ func (c *EquipmentController) DeleteEquipment(ctx echo.Context) error {
 
 	if err := c.equipmentService.DeleteEquipment(ctx.Request().Context(), id); err != nil {
 		c.logger.Error("DeleteEquipment: ошибка при удалении оборудования", zap.Uint64("id", id), zap.Error(err))
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	return utils.SuccessResponse(ctx, struct{}{}, "Оборудование успешно удалено", http.StatusOK)
+}
+
+// @Summary     Сканирование QR-наклейки оборудования
+// @Description Возвращает карточку оборудования, его открытые заявки (при праве order:view) и черновик новой заявки с подставленными оборудованием, типом, филиалом, офисом и адресом. Для списанного оборудования черновика нет.
+// @Tags        equipment
+// @Param       code path string true "Код с наклейки"
+// @Success     200 {object} dto.EquipmentScanDTO
+// @Failure     404 "Не найдено"
+// @Permission  equipment:view, order:create
+// @Router      /equipment/by-code/{code} [get]
+func (c *EquipmentController) FindEquipmentByCode(ctx echo.Context) error {
+	code := ctx.Param("code")
+
+	res, err := c.equipmentService.FindEquipmentByCode(ctx.Request().Context(), code)
+	if err != nil {
+		c.logger.Error("FindEquipmentByCode: ошибка при поиске оборудования по коду", zap.String("code", code), zap.Error(err))
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	return utils.SuccessResponse(ctx, res, "Оборудование успешно найдено", http.StatusOK)
+}
+
+// @Summary     QR-код наклейки оборудования
+// @Description Картинка с deep link: target=web — страница сканирования на сайте, target=telegram — старт бота с этим оборудованием.
+// @Tags        equipment
+// @Param       id path int true "ID"
+// @Param       format query string false "png (по умолчанию) или svg"
+// @Param       target query string false "web (по умолчанию) или telegram"
+// @Param       scale query int false "Пикселей на модуль для PNG (по умолчанию 8, максимум 40)"
+// @Success     200 {file} image/png
+// @Failure     400 "Неверный формат или цель"
+// @Permission  equipment:view
+// @Router      /equipment/{id}/qr [get]
+func (c *EquipmentController) GetEquipmentQRCode(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		c.logger.Error("GetEquipmentQRCode: неверный формат ID", zap.String("id", ctx.Param("id")), zap.Error(err))
 		return utils.ErrorResponse(
 			ctx,
 			apperrors.NewHttpError(
-				http.StatusInternalServerError,
-				"Не удалось удалить оборудование",
+				http.StatusBadRequest,
+				"Неверный формат ID оборудования",
 				err,
-				nil,
+				map[string]interface{}{"param": ctx.Param("id")},
 			),
 			c.logger,
 		)
 	}
 
-	return utils.SuccessResponse(ctx, struct{}{}, "Оборудование успешно удалено", http.StatusOK)
+	scale := 0
+	if raw := ctx.QueryParam("scale"); raw != "" {
+		if scale, err = strconv.Atoi(raw); err != nil {
+			return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Параметр scale должен быть числом", err, nil), c.logger)
+		}
+	}
+
+	qr, err := c.equipmentService.GetEquipmentQRCode(
+		ctx.Request().Context(),
+		id,
+		strings.ToLower(ctx.QueryParam("target")),
+		strings.ToLower(ctx.QueryParam("format")),
+		scale,
+	)
+	if err != nil {
+		c.logger.Error("GetEquipmentQRCode: ошибка при построении QR-кода", zap.Uint64("id", id), zap.Error(err))
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, "inline; filename="+qr.FileName)
+	ctx.Response().Header().Set("X-QR-Link", qr.Link)
+	return ctx.Blob(http.StatusOK, qr.ContentType, qr.Content)
 }
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type EventReplayController struct {
+	service services.EventReplayServiceInterface
+	logger  *zap.Logger
+}
+
+func NewEventReplayController(service services.EventReplayServiceInterface, logger *zap.Logger) *EventReplayController {
+	return &EventReplayController{service: service, logger: logger}
+}
+
+func (c *EventReplayController) ReplayOrderHistory(ctx echo.Context) error {
+	var d dto.EventReplayRequestDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	result, err := c.service.ReplayOrderHistory(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	message := "События истории переиграны"
+	if result.DryRun {
+		message = "Пробный запуск: события не публиковались"
+	}
+	return utils.SuccessResponse(ctx, result, message, http.StatusOK)
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/graphqlapi"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type GraphQLController struct {
+	executor *graphqlapi.Executor
+	logger   *zap.Logger
+}
+
+func NewGraphQLController(executor *graphqlapi.Executor, logger *zap.Logger) *GraphQLController {
+	return &GraphQLController{executor: executor, logger: logger}
+}
+
+// Query выполняет GraphQL-запрос. Ответ в формате GraphQL ({data, errors}), без общей обёртки API:
+// этого ждут клиентские библиотеки. Ошибки отдельных полей не меняют HTTP-статус.
+func (c *GraphQLController) Query(ctx echo.Context) error {
+	var req graphqlapi.Request
+	if err := ctx.Bind(&req); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат GraphQL-запроса", err, nil), c.logger)
+	}
+	if req.Query == "" {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Пустой GraphQL-запрос", nil, nil), c.logger)
+	}
+
+	return ctx.JSON(http.StatusOK, c.executor.Execute(ctx.Request().Context(), req))
+}
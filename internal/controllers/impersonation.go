@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/service"
+	"request-system/pkg/utils"
+)
+
+type ImpersonationController struct {
+	service services.ImpersonationServiceInterface
+	jwtSvc  service.JWTService
+	ttl     time.Duration
+	logger  *zap.Logger
+}
+
+func NewImpersonationController(
+	service services.ImpersonationServiceInterface,
+	jwtSvc service.JWTService,
+	ttl time.Duration,
+	logger *zap.Logger,
+) *ImpersonationController {
+	return &ImpersonationController{service: service, jwtSvc: jwtSvc, ttl: ttl, logger: logger}
+}
+
+// Impersonate выдаёт короткоживущий токен для работы под пользователем :userID.
+// Куки с refresh-токеном администратора не трогаются, чтобы он мог вернуться к своей сессии.
+func (c *ImpersonationController) Impersonate(ctx echo.Context) error {
+	userID, err := strconv.ParseUint(ctx.Param("userID"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID пользователя", err, nil), c.logger)
+	}
+
+	reqCtx := ctx.Request().Context()
+	impersonatorID, err := utils.GetUserIDFromCtx(reqCtx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.ErrUnauthorized, c.logger)
+	}
+
+	target, permissions, err := c.service.Impersonate(reqCtx, userID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	token, expiresAt, err := c.jwtSvc.GenerateImpersonationToken(target.ID, impersonatorID, target.TenantID, c.ttl)
+	if err != nil {
+		c.logger.Error("Не удалось выпустить токен имперсонации", zap.Uint64("userID", target.ID), zap.Error(err))
+		return utils.ErrorResponse(ctx, apperrors.ErrInternalServer, c.logger)
+	}
+
+	return utils.SuccessResponse(ctx, dto.ImpersonationResponseDTO{
+		AccessToken: token,
+		ExpiresAt:   expiresAt.Format(time.RFC3339),
+		UserID:      target.ID,
+		Fio:         target.Fio,
+		Permissions: permissions,
+	}, "Токен имперсонации выдан", http.StatusOK)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// maxImportFileSize — предел CSV-файла импорта.
+const maxImportFileSize = 10 * 1024 * 1024
+
+type ImportController struct {
+	service services.ImportServiceInterface
+	logger  *zap.Logger
+}
+
+func NewImportController(service services.ImportServiceInterface, logger *zap.Logger) *ImportController {
+	return &ImportController{service: service, logger: logger}
+}
+
+// @Summary     Проверка CSV перед импортом
+// @Description Ничего не сохраняет: показывает, сколько записей будет создано и обновлено, ошибки по строкам и изменения полей. Справочник entity — users, branches, offices или equipment; колонки пользователей, филиалов и офисов совпадают с полями выгрузки 1С.
+// @Tags        import
+// @Param       entity path string true "users | branches | offices | equipment"
+// @Param       file formData file true "CSV с заголовком, разделитель «,» или «;»"
+// @Success     200 {object} dto.ImportResultDTO
+// @Permission  import:run
+// @Router      /admin/import/{entity}/preview [post]
+func (c *ImportController) Preview(ctx echo.Context) error {
+	return c.run(ctx, true)
+}
+
+// @Summary     Импорт CSV
+// @Description Строки с ошибками пропускаются и перечислены в errors, остальные сохраняются. Пользователи, филиалы и офисы сохраняются так же, как из 1С: статус по isActive, роли по умолчанию для новых пользователей.
+// @Tags        import
+// @Param       entity path string true "users | branches | offices | equipment"
+// @Param       file formData file true "CSV с заголовком, разделитель «,» или «;»"
+// @Success     200 {object} dto.ImportResultDTO
+// @Permission  import:run
+// @Router      /admin/import/{entity} [post]
+func (c *ImportController) Import(ctx echo.Context) error {
+	return c.run(ctx, false)
+}
+
+func (c *ImportController) run(ctx echo.Context, preview bool) error {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Файл file не передан", err, nil), c.logger)
+	}
+	if file.Size > maxImportFileSize {
+		msg := fmt.Sprintf("Файл слишком большой. Максимальный размер: %d MB", maxImportFileSize/1024/1024)
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, msg, nil, nil), c.logger)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.ErrInternalServer, c.logger)
+	}
+	defer src.Close()
+
+	result, err := c.service.Import(ctx.Request().Context(), ctx.Param("entity"), io.LimitReader(src, maxImportFileSize), preview)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	msg := "Импорт выполнен"
+	if preview {
+		msg = "Файл проверен, изменения не сохранены"
+	}
+	return utils.SuccessResponse(ctx, result, msg, http.StatusOK)
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type NotificationCenterController struct {
+	service services.NotificationCenterServiceInterface
+	logger  *zap.Logger
+}
+
+func NewNotificationCenterController(service services.NotificationCenterServiceInterface, logger *zap.Logger) *NotificationCenterController {
+	return &NotificationCenterController{service: service, logger: logger}
+}
+
+// GetMy — список уведомлений текущего пользователя. ?unread=true — только непрочитанные.
+func (c *NotificationCenterController) GetMy(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	unreadOnly := ctx.QueryParam("unread") == "true"
+
+	result, err := c.service.GetMyNotifications(ctx.Request().Context(), filter, unreadOnly)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	return utils.SuccessResponse(
+		ctx,
+		result.List,
+		"Список уведомлений получен",
+		http.StatusOK,
+		result.Pagination.TotalCount,
+	)
+}
+
+func (c *NotificationCenterController) GetUnreadCount(ctx echo.Context) error {
+	result, err := c.service.GetMyUnreadCount(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Количество непрочитанных уведомлений получено", http.StatusOK)
+}
+
+func (c *NotificationCenterController) MarkRead(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+
+	if err := c.service.MarkRead(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Уведомление отмечено как прочитанное", http.StatusOK)
+}
+
+func (c *NotificationCenterController) MarkAllRead(ctx echo.Context) error {
+	result, err := c.service.MarkAllRead(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Все уведомления отмечены как прочитанные", http.StatusOK)
+}
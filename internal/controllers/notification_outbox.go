@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type NotificationOutboxController struct {
+	service services.NotificationOutboxServiceInterface
+	logger  *zap.Logger
+}
+
+func NewNotificationOutboxController(service services.NotificationOutboxServiceInterface, logger *zap.Logger) *NotificationOutboxController {
+	return &NotificationOutboxController{service: service, logger: logger}
+}
+
+func (c *NotificationOutboxController) GetDeadLetters(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetDeadLetters(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	return utils.SuccessResponse(
+		ctx,
+		result.List,
+		"Список недоставленных уведомлений получен",
+		http.StatusOK,
+		result.Pagination.TotalCount,
+	)
+}
+
+func (c *NotificationOutboxController) Requeue(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+
+	if err := c.service.Requeue(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Уведомление поставлено в очередь повторно", http.StatusOK)
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type NotificationPreferenceController struct {
+	service services.NotificationPreferenceServiceInterface
+	logger  *zap.Logger
+}
+
+func NewNotificationPreferenceController(service services.NotificationPreferenceServiceInterface, logger *zap.Logger) *NotificationPreferenceController {
+	return &NotificationPreferenceController{service: service, logger: logger}
+}
+
+func (c *NotificationPreferenceController) GetMy(ctx echo.Context) error {
+	result, err := c.service.GetMyPreferences(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Настройки уведомлений получены", http.StatusOK)
+}
+
+func (c *NotificationPreferenceController) UpdateMy(ctx echo.Context) error {
+	var d dto.UpdateNotificationPreferenceDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	result, err := c.service.UpdateMyPreferences(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Настройки уведомлений обновлены", http.StatusOK)
+}
@@ -25,6 +25,15 @@ func NewOfficeController(service services.OfficeServiceInterface, logger *zap.Lo
 	return &OfficeController{officeService: service, logger: logger}
 }
 
+// @Summary     Список: офисы
+// @Tags        offices
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.OfficeListResponseDTO
+// @Permission  office:view
+// @Router      /office [get]
 func (c *OfficeController) GetOffices(ctx echo.Context) error {
 	// 1. Получаем единый объект фильтра из URL
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
@@ -49,6 +58,13 @@ func (c *OfficeController) GetOffices(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, offices, "Список офисов успешно получен", http.StatusOK, total)
 }
 
+// @Summary     Получение по ID: офисы
+// @Tags        offices
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.OfficeDTO
+// @Failure     404 "Не найдено"
+// @Permission  office:view
+// @Router      /office/{id} [get]
 func (c *OfficeController) FindOffice(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -83,6 +99,12 @@ func (c *OfficeController) FindOffice(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Офис успешно найден", http.StatusOK)
 }
 
+// @Summary     Создание: офисы
+// @Tags        offices
+// @Param       body body dto.CreateOfficeDTO true "Данные"
+// @Success     201 {object} dto.OfficeDTO
+// @Permission  office:create
+// @Router      /office [post]
 func (c *OfficeController) CreateOffice(ctx echo.Context) error {
 	var dto dto.CreateOfficeDTO
 
@@ -123,6 +145,13 @@ func (c *OfficeController) CreateOffice(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Офис успешно создан", http.StatusCreated)
 }
 
+// @Summary     Обновление: офисы
+// @Tags        offices
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateOfficeDTO true "Изменяемые поля"
+// @Success     200 {object} dto.OfficeDTO
+// @Permission  office:update
+// @Router      /office/{id} [put]
 func (c *OfficeController) UpdateOffice(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -177,6 +206,12 @@ func (c *OfficeController) UpdateOffice(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Офис успешно обновлен", http.StatusOK)
 }
 
+// @Summary     Удаление: офисы
+// @Tags        offices
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  office:delete
+// @Router      /office/{id} [delete]
 func (c *OfficeController) DeleteOffice(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type OnCallController struct {
+	service services.OnCallServiceInterface
+	logger  *zap.Logger
+}
+
+func NewOnCallController(service services.OnCallServiceInterface, logger *zap.Logger) *OnCallController {
+	return &OnCallController{service: service, logger: logger}
+}
+
+func (c *OnCallController) GetAll(ctx echo.Context) error {
+	departmentID, err := parseOnCallDepartmentFilter(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetSchedules(ctx.Request().Context(), departmentID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Графики дежурств получены", http.StatusOK)
+}
+
+func (c *OnCallController) GetByID(ctx echo.Context) error {
+	id, err := parseOnCallID(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetSchedule(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "График дежурств получен", http.StatusOK)
+}
+
+func (c *OnCallController) Create(ctx echo.Context) error {
+	var d dto.CreateOnCallScheduleDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateSchedule(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "График дежурств создан", http.StatusCreated)
+}
+
+func (c *OnCallController) Update(ctx echo.Context) error {
+	id, err := parseOnCallID(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateOnCallScheduleDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateSchedule(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "График дежурств обновлён", http.StatusOK)
+}
+
+func (c *OnCallController) Delete(ctx echo.Context) error {
+	id, err := parseOnCallID(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteSchedule(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "График дежурств удалён", http.StatusOK)
+}
+
+func (c *OnCallController) GetOverrides(ctx echo.Context) error {
+	id, err := parseOnCallID(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetOverrides(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Замены получены", http.StatusOK)
+}
+
+func (c *OnCallController) CreateOverride(ctx echo.Context) error {
+	id, err := parseOnCallID(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.CreateOnCallOverrideDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateOverride(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Замена добавлена", http.StatusCreated)
+}
+
+func (c *OnCallController) DeleteOverride(ctx echo.Context) error {
+	id, err := parseOnCallID(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	overrideID, err := parseOnCallID(ctx, "overrideID")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteOverride(ctx.Request().Context(), id, overrideID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Замена удалена", http.StatusOK)
+}
+
+func (c *OnCallController) GetNow(ctx echo.Context) error {
+	departmentID, err := parseOnCallDepartmentFilter(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetOnCallNow(ctx.Request().Context(), departmentID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Текущие дежурные получены", http.StatusOK)
+}
+
+func parseOnCallID(ctx echo.Context, param string) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param(param), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil)
+	}
+	return id, nil
+}
+
+func parseOnCallDepartmentFilter(ctx echo.Context) (*uint64, error) {
+	raw := ctx.QueryParam("department_id")
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат department_id", err, nil)
+	}
+	return &id, nil
+}
@@ -1,11 +1,12 @@
 package controllers
 
 import (
-	"bytes"
 	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -30,6 +31,21 @@ func NewOrderController(service services.OrderServiceInterface, logger *zap.Logg
 }
 
 // UpdateOrder - Обновление
+// @Summary     Обновление заявки
+// @Description Тело — RFC 7386 JSON Merge Patch (application/merge-patch+json или application/json) либо multipart с тем же JSON в поле data и файлом.
+// @Description Меняются только присланные поля; null очищает значение.
+// @Tags        orders
+// @Accept      application/merge-patch+json,application/json
+// @Param       id path int true "ID заявки"
+// @Param       patch body dto.UpdateOrderDTO false "Изменяемые поля"
+// @Param       data formData dto.UpdateOrderDTO false "JSON с изменяемыми полями"
+// @Param       file formData file false "Вложение"
+// @Success     200 {object} dto.OrderResponseDTO
+// @Failure     400 "Тело не JSON-объект"
+// @Failure     403 "Нет прав на заявку или поле"
+// @Permission  order:update
+// @Router      /order/{id} [patch]
+// @Router      /order/{id} [put]
 func (c *OrderController) UpdateOrder(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -37,61 +53,29 @@ func (c *OrderController) UpdateOrder(ctx echo.Context) error {
 	}
 
 	var d dto.UpdateOrderDTO
-	var explicitFields map[string]interface{}
+	explicitFields := make(map[string]interface{})
 
 	// Поддержка и Multipart (с файлом), и JSON Body
-	contentType := ctx.Request().Header.Get("Content-Type")
-
-	if contentType == "application/json" {
-		// JSON Body
+	if isMergePatchRequest(ctx.Request().Header.Get(echo.HeaderContentType)) {
 		body, err := io.ReadAll(ctx.Request().Body)
 		if err != nil {
 			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Ошибка чтения тела запроса"))
 		}
-
-		// Восстанавливаем Body для повторного чтения
-		ctx.Request().Body = io.NopCloser(bytes.NewBuffer(body))
-
-		// Парсим в структуру DTO
-		if err := json.Unmarshal(body, &d); err != nil {
-			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Invalid JSON body"))
-		}
-
-		// Парсим в map для отслеживания явных полей
-		explicitFields = make(map[string]interface{})
-		if err := json.Unmarshal(body, &explicitFields); err != nil {
-			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Invalid JSON structure"))
+		if explicitFields, err = utils.ParseMergePatch(body, &d); err != nil {
+			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Тело запроса должно быть JSON-объектом"))
 		}
-
-		c.logger.Debug("UpdateOrder JSON parsed",
-			zap.Any("explicitFields", explicitFields),
-			zap.Any("dto", d))
-
-	} else {
-		// Multipart Form
-		dataStr := ctx.FormValue("data")
-		if dataStr != "" {
-			// Парсим в DTO
-			if err := json.Unmarshal([]byte(dataStr), &d); err != nil {
-				return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Invalid JSON in 'data'"))
-			}
-
-			// Парсим в map для отслеживания явных полей
-			explicitFields = make(map[string]interface{})
-			if err := json.Unmarshal([]byte(dataStr), &explicitFields); err != nil {
-				return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Invalid JSON structure"))
-			}
-		} else {
-			explicitFields = make(map[string]interface{})
+	} else if dataStr := ctx.FormValue("data"); dataStr != "" {
+		if explicitFields, err = utils.ParseMergePatch([]byte(dataStr), &d); err != nil {
+			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Поле data должно быть JSON-объектом"))
 		}
-
-		c.logger.Debug("UpdateOrder Multipart parsed",
-			zap.Any("explicitFields", explicitFields),
-			zap.Any("dto", d))
 	}
 
+	c.logger.Debug("UpdateOrder: разобран merge patch",
+		zap.Any("explicitFields", explicitFields),
+		zap.Any("dto", d))
+
 	if err := ctx.Validate(&d); err != nil {
-		return api.ErrorResponse(ctx, apperrors.NewBadRequestError(err.Error()))
+		return api.ErrorResponse(ctx, err)
 	}
 
 	// Получаем файл
@@ -109,12 +93,28 @@ func (c *OrderController) UpdateOrder(ctx echo.Context) error {
 	return api.SuccessOne(ctx, http.StatusOK, "Заявка обновлена", res)
 }
 
+// @Summary     Список заявок
+// @Description Учитывает область видимости пользователя (scope:*).
+// @Tags        orders
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       q query string false "Строка фильтров: status:OPEN priority:CRITICAL executor:me created:>2024-01-01 text:\"принтер\""
+// @Param       participant query string false "me — только созданные мной"
+// @Param       assigned query string false "me — только назначенные мне"
+// @Param       involved query string false "me — где я участник"
+// @Success     200 {list} dto.OrderResponseDTO
+// @Permission  order:view
+// @Router      /order [get]
 func (c *OrderController) GetOrders(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
 	onlyCreated := ctx.QueryParam("participant") == "me" || ctx.QueryParam("created") == "me"
 	onlyAssigned := ctx.QueryParam("assigned") == "me"
 	onlyInvolved := ctx.QueryParam("involved") == "me"
+	if err := c.orderService.ApplyOrderQuery(reqCtx, &filter, ctx.QueryParam("q")); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
 
 	result, err := c.orderService.GetOrders(reqCtx, filter, onlyCreated, onlyAssigned, onlyInvolved)
 	if err != nil {
@@ -126,6 +126,15 @@ func (c *OrderController) GetOrders(ctx echo.Context) error {
 }
 
 // FindOrder - Получение одной заявки
+// @Summary     Карточка заявки
+// @Description Отдаёт ETag; с If-None-Match вернёт 304, если заявка не менялась.
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Success     200 {object} dto.OrderResponseDTO
+// @Success     304 "Не изменилась"
+// @Failure     404 "Заявка не найдена"
+// @Permission  order:view
+// @Router      /order/{id} [get]
 func (c *OrderController) FindOrder(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -137,10 +146,27 @@ func (c *OrderController) FindOrder(ctx echo.Context) error {
 		return api.ErrorResponse(ctx, err)
 	}
 
+	// ETag считается по самой карточке (в ней есть updated_at и вложения): updated_at
+	// не меняется при удалении вложения, поэтому Last-Modified для заявки не отдаём.
+	if body, err := json.Marshal(order); err == nil {
+		userID, _ := utils.GetUserIDFromCtx(ctx.Request().Context())
+		etag := utils.BuildETag("order", strconv.FormatUint(userID, 10), string(body))
+		if done, err := utils.RespondNotModified(ctx, etag, time.Time{}); done {
+			return err
+		}
+	}
+
 	return api.SuccessOne(ctx, http.StatusOK, "Заявка найдена", order)
 }
 
 // CreateOrder - Создание
+// @Summary     Создание заявки
+// @Tags        orders
+// @Param       data formData dto.CreateOrderDTO true "JSON заявки"
+// @Param       file formData file false "Вложение"
+// @Success     201 {object} dto.OrderResponseDTO
+// @Permission  order:create
+// @Router      /order [post]
 func (c *OrderController) CreateOrder(ctx echo.Context) error {
 	dataStr := ctx.FormValue("data")
 	if dataStr == "" {
@@ -153,7 +179,7 @@ func (c *OrderController) CreateOrder(ctx echo.Context) error {
 	}
 
 	if err := ctx.Validate(&d); err != nil {
-		return api.ErrorResponse(ctx, apperrors.NewBadRequestError(err.Error()))
+		return api.ErrorResponse(ctx, err)
 	}
 
 	// Получаем файл
@@ -171,6 +197,12 @@ func (c *OrderController) CreateOrder(ctx echo.Context) error {
 }
 
 // DeleteOrder - Удаление
+// @Summary     Удаление заявки
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Success     200
+// @Permission  order:delete
+// @Router      /order/{id} [delete]
 func (c *OrderController) DeleteOrder(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -183,3 +215,232 @@ func (c *OrderController) DeleteOrder(ctx echo.Context) error {
 
 	return api.SuccessOne[any](ctx, http.StatusOK, "Заявка удалена", nil)
 }
+
+// MergeOrder - Объединение дубликата с основной заявкой
+// @Summary     Закрыть заявку как дубликат
+// @Description Заявка получает статус DUPLICATE и ссылку duplicate_of_id. Её вложения переносятся в основную заявку, комментарии копируются в историю основной заявки, участники добавляются к ней.
+// @Tags        orders
+// @Param       id path int true "ID заявки-дубликата"
+// @Param       body body dto.MergeOrderDTO true "Основная заявка и комментарий"
+// @Success     200 {object} dto.OrderResponseDTO
+// @Failure     400 "Заявка закрыта или уже объединена"
+// @Failure     403 "Нет прав на одну из заявок"
+// @Permission  order:merge
+// @Router      /order/{id}/merge [post]
+func (c *OrderController) MergeOrder(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID"))
+	}
+
+	var d dto.MergeOrderDTO
+	if err := ctx.Bind(&d); err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Некорректный JSON"))
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	res, err := c.orderService.MergeOrder(ctx.Request().Context(), id, d)
+	if err != nil {
+		c.logger.Error("MergeOrder failed", zap.Uint64("order_id", id), zap.Uint64("target_id", d.TargetID), zap.Error(err))
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Заявка закрыта как дубликат", res)
+}
+
+// ClaimOrder - Участник команды забирает заявку из очереди
+// @Summary     Забрать заявку команды
+// @Description Назначает текущего пользователя исполнителем заявки, назначенной на команду. Доступно только участникам команды; если заявку уже забрали, вернётся 409.
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Success     200 {object} dto.OrderResponseDTO
+// @Failure     400 "Заявка не назначена на команду или закрыта"
+// @Failure     403 "Пользователь не состоит в команде"
+// @Failure     409 "Заявку уже забрал другой сотрудник"
+// @Permission  order:view
+// @Router      /order/{id}/claim [post]
+func (c *OrderController) ClaimOrder(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID"))
+	}
+
+	res, err := c.orderService.ClaimOrder(ctx.Request().Context(), id)
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Заявка назначена на вас", res)
+}
+
+// ClaimNextOrder - Сотрудник берёт следующую заявку из очереди департамента
+// @Summary     Забрать следующую заявку
+// @Description Назначает текущего пользователя исполнителем самой старой открытой заявки его департамента без исполнителя. Заявки команд, в которых пользователь не состоит, и заявки, для типа которых у него нет всех навыков, пропускаются. Параллельные запросы не получают одну и ту же заявку. Если свободных заявок нет, вернётся 204.
+// @Tags        orders
+// @Success     200 {object} dto.OrderResponseDTO
+// @Success     204 "Свободных заявок нет"
+// @Failure     400 "Пользователь не привязан к департаменту"
+// @Permission  order:update
+// @Router      /orders/claim-next [post]
+func (c *OrderController) ClaimNextOrder(ctx echo.Context) error {
+	res, err := c.orderService.ClaimNextOrder(ctx.Request().Context())
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+	if res == nil {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Заявка назначена на вас", res)
+}
+
+// FindPossibleDuplicates - Подсказка о дублях
+// @Summary     Похожие заявки
+// @Description Свежие заявки по тому же оборудованию с похожим названием (за ORDER_DUPLICATE_HINT_DAYS дней), самые похожие первыми. Тот же список приходит в possible_duplicates ответа на создание.
+// @Tags        orders
+// @Param       name query string true "Название заявки"
+// @Param       equipment_id query int true "ID оборудования"
+// @Param       exclude_id query int false "ID заявки, которую не показывать"
+// @Success     200 {array} dto.OrderDuplicateCandidateDTO
+// @Permission  order:view
+// @Router      /order/similar [get]
+func (c *OrderController) FindPossibleDuplicates(ctx echo.Context) error {
+	equipmentID, err := strconv.ParseUint(ctx.QueryParam("equipment_id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный equipment_id"))
+	}
+	var excludeID uint64
+	if raw := ctx.QueryParam("exclude_id"); raw != "" {
+		if excludeID, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный exclude_id"))
+		}
+	}
+
+	res, err := c.orderService.FindPossibleDuplicates(ctx.Request().Context(), ctx.QueryParam("name"), equipmentID, excludeID)
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Похожие заявки", res)
+}
+
+// SuggestedExecutors - Подбор исполнителя с учётом нагрузки
+// @Summary     Рекомендуемые исполнители
+// @Description Активные сотрудники подразделения заявки, отсортированные по оценке: навыки, нужные типу заявки (вес 0.5), текущее число открытых заявок (0.3) и среднее время решения за 90 дней (0.2).
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Param       limit query int false "Сколько кандидатов вернуть (по умолчанию 10, максимум 50)"
+// @Success     200 {array} dto.ExecutorSuggestionDTO
+// @Failure     403 "Нет прав назначать исполнителя"
+// @Permission  order:update:executor_id
+// @Router      /orders/{id}/suggested-executors [get]
+func (c *OrderController) SuggestedExecutors(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID"))
+	}
+	limit := 0
+	if raw := ctx.QueryParam("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil {
+			return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный limit"))
+		}
+	}
+
+	res, err := c.orderService.SuggestExecutors(ctx.Request().Context(), id, limit)
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Рекомендуемые исполнители", res)
+}
+
+// GetAccessGrants - Разовые доступы к заявке
+// @Summary     Разовые доступы к заявке
+// @Description Сотрудники, которым выдан доступ к заявке вне их области видимости, включая просроченные выдачи (is_active = false).
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Success     200 {array} dto.OrderAccessGrantDTO
+// @Failure     403 "Нет прав управлять доступом к заявке"
+// @Permission  order:grant_access
+// @Router      /order/{id}/access-grants [get]
+func (c *OrderController) GetAccessGrants(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID"))
+	}
+
+	res, err := c.orderService.GetAccessGrants(ctx.Request().Context(), id)
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Доступы к заявке", res)
+}
+
+// GrantAccess - Выдача разового доступа
+// @Summary     Выдать доступ к заявке
+// @Description level: view — просмотр, edit — просмотр и изменение (без удаления). Без expires_at доступ действует до отзыва. Повторная выдача тому же сотруднику меняет уровень и срок.
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Param       body body dto.GrantOrderAccessDTO true "Кому и какой доступ"
+// @Success     200 {object} dto.OrderAccessGrantDTO
+// @Failure     403 "Нет прав управлять доступом к заявке"
+// @Permission  order:grant_access
+// @Router      /order/{id}/access-grants [post]
+func (c *OrderController) GrantAccess(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID"))
+	}
+	var d dto.GrantOrderAccessDTO
+	if err := ctx.Bind(&d); err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный формат запроса"))
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	res, err := c.orderService.GrantAccess(ctx.Request().Context(), id, d)
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne(ctx, http.StatusOK, "Доступ к заявке выдан", res)
+}
+
+// RevokeAccess - Отзыв разового доступа
+// @Summary     Отозвать доступ к заявке
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Param       userID path int true "ID сотрудника"
+// @Success     200
+// @Failure     404 "Доступ не выдавался"
+// @Permission  order:grant_access
+// @Router      /order/{id}/access-grants/{userID} [delete]
+func (c *OrderController) RevokeAccess(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID"))
+	}
+	userID, err := strconv.ParseUint(ctx.Param("userID"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный ID сотрудника"))
+	}
+
+	if err := c.orderService.RevokeAccess(ctx.Request().Context(), id, userID); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	return api.SuccessOne[any](ctx, http.StatusOK, "Доступ к заявке отозван", nil)
+}
+
+// isMergePatchRequest — тело целиком JSON: merge patch по RFC 7386 или обычный application/json.
+func isMergePatchRequest(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == utils.MIMEMergePatchJSON || mediaType == echo.MIMEApplicationJSON
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type OrderActController struct {
+	service services.OrderActServiceInterface
+	logger  *zap.Logger
+}
+
+func NewOrderActController(service services.OrderActServiceInterface, logger *zap.Logger) *OrderActController {
+	return &OrderActController{service: service, logger: logger}
+}
+
+// GetOrderAct отдаёт печатный акт по заявке в PDF.
+// @Summary     Печатный акт по заявке
+// @Description Сведения о заявке, ход работ по истории и место для подписей. Даты — в часовом поясе пользователя.
+// @Tags        orders
+// @Param       id path int true "ID заявки"
+// @Success     200 {file} application/pdf
+// @Failure     403 "Нет прав на заявку"
+// @Permission  order:view
+// @Router      /orders/{id}/pdf [get]
+func (c *OrderActController) GetOrderAct(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID заявки", err, nil), c.logger)
+	}
+
+	act, err := c.service.RenderOrderAct(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, "inline; filename="+act.FileName)
+	return ctx.Blob(http.StatusOK, "application/pdf", act.Content)
+}
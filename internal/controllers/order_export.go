@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/pkg/api"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+	"request-system/pkg/utils"
+)
+
+var orderExportHeaders = []string{
+	"№", "ID", "Название", "Статус", "Приоритет", "Тип заявки", "Департамент", "Отдел", "Филиал", "Офис",
+	"Тип оборудования", "Оборудование", "Адрес", "Создатель", "Исполнитель",
+	"Дата создания", "Срок выполнения", "Дата выполнения", "Дополнительные поля",
+}
+
+// orderExportRecord — строка выгрузки; даты переводятся в часовой пояс пользователя loc.
+func orderExportRecord(row dto.OrderExportRowDTO, loc *time.Location) []string {
+	const dateFmt = "02.01.2006 15:04"
+	optionalTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format(dateFmt)
+	}
+	return []string{
+		row.Number, strconv.FormatUint(row.ID, 10), row.Name, row.Status, row.Priority, row.OrderType,
+		row.Department, row.Otdel, row.Branch, row.Office, row.EquipmentType, row.Equipment,
+		row.Address, row.Creator, row.Executor,
+		row.CreatedAt.In(loc).Format(dateFmt), optionalTime(row.Duration), optionalTime(row.CompletedAt),
+		row.CustomFields,
+	}
+}
+
+// ExportOrders выгружает список заявок в CSV (по умолчанию) или XLSX (?format=xlsx).
+// Принимает те же параметры, что и GetOrders, кроме пагинации.
+// @Summary     Выгрузка списка заявок в CSV или XLSX
+// @Description Фильтры те же, что у GET /order; выгружаются все страницы.
+// @Tags        orders
+// @Param       format query string false "csv (по умолчанию) или xlsx"
+// @Param       search query string false "Поиск"
+// @Param       q query string false "Строка фильтров, как у GET /order"
+// @Param       participant query string false "me — только созданные мной"
+// @Param       assigned query string false "me — только назначенные мне"
+// @Param       involved query string false "me — где я участник"
+// @Success     200 {file} application/octet-stream
+// @Permission  order:view
+// @Router      /orders/export [get]
+func (c *OrderController) ExportOrders(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	onlyCreated := ctx.QueryParam("participant") == "me" || ctx.QueryParam("created") == "me"
+	onlyAssigned := ctx.QueryParam("assigned") == "me"
+	onlyInvolved := ctx.QueryParam("involved") == "me"
+	if err := c.orderService.ApplyOrderQuery(reqCtx, &filter, ctx.QueryParam("q")); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	format := strings.ToLower(ctx.QueryParam("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		return api.ErrorResponse(ctx, apperrors.NewBadRequestError("Формат выгрузки: csv или xlsx"))
+	}
+	fileName := fmt.Sprintf("orders_%s.%s", time.Now().Format("2006-01-02"), format)
+
+	if format == "xlsx" {
+		return c.exportOrdersXLSX(ctx, filter, onlyCreated, onlyAssigned, onlyInvolved, fileName)
+	}
+
+	// Заголовки ответа отправляются с первой пачкой: до неё ещё можно вернуть обычную ошибку.
+	var w *csv.Writer
+	start := func() error {
+		ctx.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+fileName)
+		ctx.Response().WriteHeader(http.StatusOK)
+		// BOM и ';' — чтобы Excel с русской локалью открыл файл без мастера импорта.
+		if _, err := ctx.Response().Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return err
+		}
+		w = csv.NewWriter(ctx.Response())
+		w.Comma = ';'
+		return w.Write(orderExportHeaders)
+	}
+
+	_, err := c.orderService.ExportOrders(reqCtx, filter, onlyCreated, onlyAssigned, onlyInvolved, func(rows []dto.OrderExportRowDTO) error {
+		if w == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for _, row := range rows {
+			if err := w.Write(orderExportRecord(row, utils.LocationFromCtx(reqCtx))); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		ctx.Response().Flush()
+		return w.Error()
+	})
+	if err != nil {
+		if w == nil {
+			c.logger.Error("ExportOrders failed", zap.Error(err))
+			return api.ErrorResponse(ctx, err)
+		}
+		// Часть файла уже ушла клиенту — статус поменять нельзя, только обрываем выгрузку.
+		c.logger.Error("Выгрузка заявок прервана", zap.Error(err))
+		return nil
+	}
+	if w == nil {
+		if err := start(); err != nil {
+			return err
+		}
+		w.Flush()
+	}
+	return w.Error()
+}
+
+func (c *OrderController) exportOrdersXLSX(ctx echo.Context, filter types.Filter, onlyCreated, onlyAssigned, onlyInvolved bool, fileName string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Заявки"
+	f.SetSheetName("Sheet1", sheet)
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+	_ = sw.SetColWidth(2, 2, 40)
+	_ = sw.SetColWidth(3, 14, 20)
+	_ = sw.SetColWidth(15, 17, 18)
+	_ = sw.SetColWidth(18, 18, 50)
+
+	style, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err := sw.SetRow("A1", toCells(orderExportHeaders), excelize.RowOpts{StyleID: style}); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	rowNum := 2
+	_, err = c.orderService.ExportOrders(ctx.Request().Context(), filter, onlyCreated, onlyAssigned, onlyInvolved, func(rows []dto.OrderExportRowDTO) error {
+		for _, row := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+			if err := sw.SetRow(cell, toCells(orderExportRecord(row, utils.LocationFromCtx(ctx.Request().Context())))); err != nil {
+				return err
+			}
+			rowNum++
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("ExportOrders failed", zap.Error(err))
+		return api.ErrorResponse(ctx, err)
+	}
+	if err := sw.Flush(); err != nil {
+		return api.ErrorResponse(ctx, err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+fileName)
+	ctx.Response().WriteHeader(http.StatusOK)
+	return f.Write(ctx.Response().Writer)
+}
+
+func toCells(values []string) []interface{} {
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
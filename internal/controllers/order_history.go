@@ -3,7 +3,9 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
+	"request-system/internal/repositories"
 	"request-system/internal/services"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/utils"
@@ -65,3 +67,46 @@ func (c *OrderHistoryController) GetHistoryForOrder(ctx echo.Context) error {
 	c.logger.Info("История заявки успешно получена", zap.Uint64("orderID", orderID), zap.Int("events", len(timeline)))
 	return utils.SuccessResponse(ctx, timeline, "История заявки успешно получена", http.StatusOK)
 }
+
+// GetHistoryEntries возвращает историю заявки постранично, по записи на событие, с готовым diff.
+// Фильтры: event_type (через запятую или несколько раз), date_from, date_to; sort[created_at]=desc.
+func (c *OrderHistoryController) GetHistoryEntries(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID заявки", err, nil), c.logger)
+	}
+
+	pagination := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	filter := repositories.HistoryPageFilter{
+		Desc:   pagination.Sort["created_at"] == "desc",
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	}
+	for _, raw := range ctx.QueryParams()["event_type"] {
+		for _, eventType := range strings.Split(raw, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				filter.EventTypes = append(filter.EventTypes, strings.ToUpper(eventType))
+			}
+		}
+	}
+	if filter.From, err = parseAuditDate(ctx.QueryParam("date_from"), false); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат date_from", err, nil), c.logger)
+	}
+	if filter.To, err = parseAuditDate(ctx.QueryParam("date_to"), true); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат date_to", err, nil), c.logger)
+	}
+
+	// Проверяем доступ к заявке
+	if _, err := c.orderService.FindOrderByID(reqCtx, orderID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	result, err := c.historyService.GetHistoryEntries(reqCtx, orderID, filter)
+	if err != nil {
+		c.logger.Error("Не удалось получить историю заявки", zap.Uint64("orderID", orderID), zap.Error(err))
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "История заявки успешно получена", http.StatusOK, result.Pagination.TotalCount)
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type OrderHistoryIntegrityController struct {
+	service services.OrderHistoryIntegrityServiceInterface
+	logger  *zap.Logger
+}
+
+func NewOrderHistoryIntegrityController(service services.OrderHistoryIntegrityServiceInterface, logger *zap.Logger) *OrderHistoryIntegrityController {
+	return &OrderHistoryIntegrityController{service: service, logger: logger}
+}
+
+// VerifyHistory проверяет, не менялась ли история заявки задним числом.
+func (c *OrderHistoryIntegrityController) VerifyHistory(ctx echo.Context) error {
+	orderID, err := strconv.ParseUint(ctx.Param("orderID"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID заявки", err, nil), c.logger)
+	}
+
+	result, err := c.service.VerifyOrderChain(ctx.Request().Context(), orderID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	message := "История заявки не изменялась"
+	if !result.Valid {
+		message = "Обнаружено изменение истории заявки"
+	}
+	return utils.SuccessResponse(ctx, result, message, http.StatusOK)
+}
@@ -27,6 +27,12 @@ func NewOrderTypeController(service services.OrderTypeServiceInterface, logger *
 }
 
 // Create обрабатывает запрос на создание нового типа заявки (POST /order-types).
+// @Summary     Создание: типы заявок
+// @Tags        order-types
+// @Param       body body dto.CreateOrderTypeDTO true "Данные"
+// @Success     201 {object} dto.OrderTypeResponseDTO
+// @Permission  order_type:create
+// @Router      /order_type [post]
 func (c *OrderTypeController) Create(ctx echo.Context) error {
 	var createDTO dto.CreateOrderTypeDTO
 	// ctx.Bind() автоматически распарсит JSON из тела запроса в нашу структуру DTO.
@@ -52,6 +58,13 @@ func (c *OrderTypeController) Create(ctx echo.Context) error {
 }
 
 // Update обрабатывает запрос на обновление типа заявки (PUT /order-types/:id).
+// @Summary     Обновление: типы заявок
+// @Tags        order-types
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateOrderTypeDTO true "Изменяемые поля"
+// @Success     200 {object} dto.OrderTypeResponseDTO
+// @Permission  order_type:update
+// @Router      /order_type/{id} [put]
 func (c *OrderTypeController) Update(ctx echo.Context) error {
 	// Считываем ID из параметра URL.
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
@@ -76,6 +89,12 @@ func (c *OrderTypeController) Update(ctx echo.Context) error {
 }
 
 // Delete обрабатывает запрос на удаление типа заявки (DELETE /order-types/:id).
+// @Summary     Удаление: типы заявок
+// @Tags        order-types
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  order_type:delete
+// @Router      /order_type/{id} [delete]
 func (c *OrderTypeController) Delete(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -92,6 +111,15 @@ func (c *OrderTypeController) Delete(ctx echo.Context) error {
 }
 
 // GetByID обрабатывает запрос на получение одного типа заявки по ID (GET /order-types/:id).
+// @Summary     Получение по ID: типы заявок
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        order-types
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.OrderTypeResponseDTO
+// @Success     304 "Не изменился"
+// @Failure     404 "Не найдено"
+// @Permission  order_type:view
+// @Router      /order_type/{id} [get]
 func (c *OrderTypeController) GetByID(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -107,6 +135,17 @@ func (c *OrderTypeController) GetByID(ctx echo.Context) error {
 }
 
 // GetAll обрабатывает запрос на получение списка типов заявок (GET /order-types).
+// @Summary     Список: типы заявок
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        order-types
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.OrderTypeResponseDTO
+// @Success     304 "Не изменился"
+// @Permission  order_type:view
+// @Router      /order_type [get]
 func (c *OrderTypeController) GetAll(ctx echo.Context) error {
 	// Парсим параметры для пагинации и поиска (?limit=10&offset=0&search=...) из URL.
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
@@ -119,6 +158,12 @@ func (c *OrderTypeController) GetAll(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, result.List, "Список типов заявок успешно получен", http.StatusOK, result.Pagination.TotalCount)
 }
 
+// @Summary     Настройки формы создания заявки для типа
+// @Tags        order-types
+// @Param       id path int true "ID типа заявки"
+// @Success     200 {object} object
+// @Permission  order:create
+// @Router      /order_type/{id}/config [get]
 func (c *OrderTypeController) GetConfig(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -132,3 +177,18 @@ func (c *OrderTypeController) GetConfig(ctx echo.Context) error {
 
 	return utils.SuccessResponse(ctx, result, "Конфигурация получена", http.StatusOK)
 }
+
+// GetAvailable отдаёт типы для формы создания заявки: активные и видимые департаменту
+// и филиалу текущего пользователя.
+// @Summary     Типы заявок, доступные для создания
+// @Tags        order-types
+// @Success     200 {list} dto.OrderTypeResponseDTO
+// @Permission  order:create
+// @Router      /order_type/available [get]
+func (c *OrderTypeController) GetAvailable(ctx echo.Context) error {
+	result, err := c.service.GetAvailable(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Список доступных типов заявок получен", http.StatusOK)
+}
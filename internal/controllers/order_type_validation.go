@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type OrderTypeValidationController struct {
+	service services.OrderTypeValidationServiceInterface
+	logger  *zap.Logger
+}
+
+func NewOrderTypeValidationController(service services.OrderTypeValidationServiceInterface, logger *zap.Logger) *OrderTypeValidationController {
+	return &OrderTypeValidationController{service: service, logger: logger}
+}
+
+func (c *OrderTypeValidationController) GetAll(ctx echo.Context) error {
+	orderTypeID, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetRules(ctx.Request().Context(), orderTypeID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Правила проверки получены", http.StatusOK)
+}
+
+func (c *OrderTypeValidationController) Create(ctx echo.Context) error {
+	orderTypeID, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.CreateOrderTypeValidationRuleDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateRule(ctx.Request().Context(), orderTypeID, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Правило проверки создано", http.StatusCreated)
+}
+
+func (c *OrderTypeValidationController) Update(ctx echo.Context) error {
+	orderTypeID, ruleID, err := parseValidationRuleIDs(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateOrderTypeValidationRuleDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateRule(ctx.Request().Context(), orderTypeID, ruleID, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Правило проверки обновлено", http.StatusOK)
+}
+
+func (c *OrderTypeValidationController) Delete(ctx echo.Context) error {
+	orderTypeID, ruleID, err := parseValidationRuleIDs(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteRule(ctx.Request().Context(), orderTypeID, ruleID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Правило проверки удалено", http.StatusOK)
+}
+
+func parseValidationRuleIDs(ctx echo.Context) (uint64, uint64, error) {
+	orderTypeID, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	ruleID, err := strconv.ParseUint(ctx.Param("ruleID"), 10, 64)
+	if err != nil {
+		return 0, 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID правила", err, nil)
+	}
+	return orderTypeID, ruleID, nil
+}
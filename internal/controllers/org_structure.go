@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	"request-system/pkg/utils"
+)
+
+type OrgStructureController struct {
+	service services.OrgStructureServiceInterface
+	logger  *zap.Logger
+}
+
+func NewOrgStructureController(service services.OrgStructureServiceInterface, logger *zap.Logger) *OrgStructureController {
+	return &OrgStructureController{service: service, logger: logger}
+}
+
+// GetTree
+// @Summary     Оргструктура деревом
+// @Description Департаменты с отделами и филиалы с офисами одним запросом. У каждого узла руководитель (или заместитель) и число незакрытых заявок. Виды подразделений без права просмотра в ответ не попадают.
+// @Tags        org-structure
+// @Success     200 {object} dto.OrgStructureDTO
+// @Failure     403 "Нет доступа ни к одному справочнику подразделений"
+// @Permission  department:view, otdel:view, branch:view, office:view
+// @Router      /org-structure [get]
+func (c *OrgStructureController) GetTree(ctx echo.Context) error {
+	result, err := c.service.GetTree(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Оргструктура получена", http.StatusOK)
+}
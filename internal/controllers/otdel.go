@@ -22,6 +22,15 @@ func NewOtdelController(service services.OtdelServiceInterface, logger *zap.Logg
 	return &OtdelController{otdelService: service, logger: logger}
 }
 
+// @Summary     Список: отделы
+// @Tags        otdels
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.OtdelDTO
+// @Permission  otdel:view
+// @Router      /otdel [get]
 func (c *OtdelController) GetOtdels(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
@@ -49,6 +58,13 @@ func (c *OtdelController) GetOtdels(ctx echo.Context) error {
 	)
 }
 
+// @Summary     Получение по ID: отделы
+// @Tags        otdels
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.OtdelDTO
+// @Failure     404 "Не найдено"
+// @Permission  otdel:view
+// @Router      /otdel/{id} [get]
 func (c *OtdelController) FindOtdel(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -79,6 +95,12 @@ func (c *OtdelController) FindOtdel(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Успешно", http.StatusOK)
 }
 
+// @Summary     Создание: отделы
+// @Tags        otdels
+// @Param       body body dto.CreateOtdelDTO true "Данные"
+// @Success     201 {object} dto.OtdelDTO
+// @Permission  otdel:create
+// @Router      /otdel [post]
 func (c *OtdelController) CreateOtdel(ctx echo.Context) error {
 	var dto dto.CreateOtdelDTO
 	if err := ctx.Bind(&dto); err != nil {
@@ -114,6 +136,13 @@ func (c *OtdelController) CreateOtdel(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Успешно создан", http.StatusCreated)
 }
 
+// @Summary     Обновление: отделы
+// @Tags        otdels
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateOtdelDTO true "Изменяемые поля"
+// @Success     200 {object} dto.OtdelDTO
+// @Permission  otdel:update
+// @Router      /otdel/{id} [put]
 func (c *OtdelController) UpdateOtdel(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -163,6 +192,12 @@ func (c *OtdelController) UpdateOtdel(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Успешно обновлен", http.StatusOK)
 }
 
+// @Summary     Удаление: отделы
+// @Tags        otdels
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  otdel:delete
+// @Router      /otdel/{id} [delete]
 func (c *OtdelController) DeleteOtdel(ctx echo.Context) error {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -99,6 +99,23 @@ func (c *PermissionController) UpdatePermission(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Привилегия успешно обновлена", http.StatusOK)
 }
 
+// FlushPermissionsCache сбрасывает кэш прав: по user_ids и role_ids из тела или всем, если тело пустое.
+func (c *PermissionController) FlushPermissionsCache(ctx echo.Context) error {
+	reqCtx := ctx.Request().Context()
+	var payload dto.FlushPermissionsCacheDTO
+	if err := ctx.Bind(&payload); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный формат запроса"), nil)
+	}
+	if err := ctx.Validate(&payload); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	res, err := c.permService.FlushPermissionsCache(reqCtx, payload)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, res, "Кэш привилегий сброшен", http.StatusOK)
+}
+
 func (c *PermissionController) DeletePermission(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
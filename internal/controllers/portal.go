@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// PortalController — публичный портал обращений клиентов филиалов, без авторизации.
+type PortalController struct {
+	service services.PortalServiceInterface
+	logger  *zap.Logger
+}
+
+func NewPortalController(service services.PortalServiceInterface, logger *zap.Logger) *PortalController {
+	return &PortalController{service: service, logger: logger}
+}
+
+// @Summary     Капча для формы портала
+// @Description builtin — SVG-картинка с примером и captcha_id; hcaptcha/recaptcha — site_key для виджета.
+// @Tags        portal
+// @Success     200 {object} dto.PortalCaptchaDTO
+// @Security    none
+// @Router      /portal/captcha [get]
+func (c *PortalController) GetCaptcha(ctx echo.Context) error {
+	res, err := c.service.NewCaptcha(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return utils.SuccessResponse(ctx, res, "Капча получена", http.StatusOK)
+}
+
+// @Summary     Отправка обращения с портала
+// @Description Создаёт заявку в очереди портала и возвращает код отслеживания. Для builtin нужны captcha_id и captcha_answer, для внешних провайдеров — captcha_token.
+// @Tags        portal
+// @Param       body body dto.PortalSubmitDTO true "Обращение"
+// @Success     201 {object} dto.PortalRequestStatusDTO
+// @Failure     400 "Капча не пройдена или данные неверны"
+// @Failure     429 "Слишком много обращений с этого адреса"
+// @Security    none
+// @Router      /portal/requests [post]
+func (c *PortalController) SubmitRequest(ctx echo.Context) error {
+	var d dto.PortalSubmitDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	res, err := c.service.SubmitRequest(ctx.Request().Context(), d, ctx.RealIP())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, res, "Обращение принято. Сохраните код отслеживания.", http.StatusCreated)
+}
+
+// @Summary     Статус обращения по коду отслеживания
+// @Tags        portal
+// @Param       code path string true "Код отслеживания"
+// @Success     200 {object} dto.PortalRequestStatusDTO
+// @Failure     404 "Не найдено"
+// @Security    none
+// @Router      /portal/requests/{code} [get]
+func (c *PortalController) GetRequestStatus(ctx echo.Context) error {
+	res, err := c.service.GetRequestStatus(ctx.Request().Context(), ctx.Param("code"))
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, res, "Статус обращения получен", http.StatusOK)
+}
@@ -23,6 +23,17 @@ func NewPriorityController(priorityService services.PriorityServiceInterface, lo
 	return &PriorityController{priorityService: priorityService, logger: logger}
 }
 
+// @Summary     Список: приоритеты
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        priorities
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.PriorityDTO
+// @Success     304 "Не изменился"
+// @Permission  priority:view
+// @Router      /priority [get]
 func (c *PriorityController) GetPriorities(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
@@ -38,6 +49,15 @@ func (c *PriorityController) GetPriorities(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res.List, "Список приоритетов успешно получен", http.StatusOK, res.Pagination.TotalCount)
 }
 
+// @Summary     Получение по ID: приоритеты
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        priorities
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.PriorityDTO
+// @Success     304 "Не изменился"
+// @Failure     404 "Не найдено"
+// @Permission  priority:view
+// @Router      /priority/{id} [get]
 func (c *PriorityController) FindPriority(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	idStr := ctx.Param("id")
@@ -61,6 +81,12 @@ func (c *PriorityController) FindPriority(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, res, "Приоритет успешно найден", http.StatusOK)
 }
 
+// @Summary     Создание: приоритеты
+// @Tags        priorities
+// @Param       body body dto.CreatePriorityDTO true "Данные"
+// @Success     201 {object} dto.PriorityDTO
+// @Permission  priority:create
+// @Router      /priority [post]
 func (c *PriorityController) CreatePriority(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	c.logger.Debug("CreatePriority: начало обработки JSON запроса")
@@ -88,6 +114,13 @@ func (c *PriorityController) CreatePriority(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, createdPriority, "Приоритет успешно создан", http.StatusCreated)
 }
 
+// @Summary     Обновление: приоритеты
+// @Tags        priorities
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdatePriorityDTO true "Изменяемые поля"
+// @Success     200 {object} dto.PriorityDTO
+// @Permission  priority:update
+// @Router      /priority/{id} [put]
 func (c *PriorityController) UpdatePriority(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
@@ -120,6 +153,12 @@ func (c *PriorityController) UpdatePriority(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, updatedPriority, "Приоритет успешно обновлен", http.StatusOK)
 }
 
+// @Summary     Удаление: приоритеты
+// @Tags        priorities
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  priority:delete
+// @Router      /priority/{id} [delete]
 func (c *PriorityController) DeletePriority(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
@@ -139,3 +178,42 @@ func (c *PriorityController) DeletePriority(ctx echo.Context) error {
 
 	return utils.SuccessResponse(ctx, struct{}{}, "Приоритет успешно удален", http.StatusOK)
 }
+
+// @Summary     Матрица приоритетов
+// @Description Приоритет заявки по влиянию (impact) и срочности (urgency).
+// @Tags        priorities
+// @Success     200 {object} dto.PriorityMatrixDTO
+// @Permission  priority:view
+// @Router      /priority/matrix [get]
+func (c *PriorityController) GetMatrix(ctx echo.Context) error {
+	res, err := c.priorityService.GetMatrix(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, res, "Матрица приоритетов получена", http.StatusOK)
+}
+
+// @Summary     Обновление матрицы приоритетов
+// @Description Матрица задаётся целиком: все сочетания low/medium/high.
+// @Tags        priorities
+// @Param       body body dto.UpdatePriorityMatrixDTO true "Клетки матрицы"
+// @Success     200 {object} dto.PriorityMatrixDTO
+// @Permission  priority:update
+// @Router      /priority/matrix [put]
+func (c *PriorityController) UpdateMatrix(ctx echo.Context) error {
+	var dto dto.UpdatePriorityMatrixDTO
+	if err := ctx.Bind(&dto); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(
+			http.StatusBadRequest, "Неверный формат JSON в теле запроса", err, nil,
+		), c.logger)
+	}
+	if err := ctx.Validate(&dto); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	res, err := c.priorityService.UpdateMatrix(ctx.Request().Context(), dto)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, res, "Матрица приоритетов обновлена", http.StatusOK)
+}
@@ -93,7 +93,8 @@ var reportHeaders = []string{
 	"Время решения (часы)", "SLA (выполнен/нет)", "Источник", "Комментарий",
 }
 
-func rowToSlice(item entities.ReportItem) []interface{} {
+// rowToSlice — строка отчёта; даты переводятся в часовой пояс пользователя loc.
+func rowToSlice(item entities.ReportItem, loc *time.Location) []interface{} {
 	dateFmt, timeFmt := "02.01.2006", "15:04"
 
 	nullStr := func(s sql.NullString) string {
@@ -104,29 +105,29 @@ func rowToSlice(item entities.ReportItem) []interface{} {
 	}
 	nullTime := func(t sql.NullTime, format string) string {
 		if t.Valid {
-			return t.Time.Format(format)
+			return t.Time.In(loc).Format(format)
 		}
 		return ""
 	}
 
 	return []interface{}{
-		item.OrderID,                        //
-		nullStr(item.CreatorFio),            // Заявитель
-		item.CreatedAt.Format(dateFmt),      // Дата обращения
-		item.CreatedAt.Format(timeFmt),      // Время обращения
-		item.OrderID,                        // ID заявки
-		nullStr(item.OrderTypeName),         // Категория
-		nullStr(item.PriorityName),          // Приоритет
-		nullStr(item.StatusName),            // Статус
-		nullStr(item.OrderName),             // Описание проблемы
-		nullStr(item.ResponsibleFio),        // Ответственный
-		nullTime(item.DelegatedAt, dateFmt), // Дата назначения
-		nullStr(item.ExecutorFio),           // Исполнитель
-		nullTime(item.CompletedAt, dateFmt), // Дата решения
-		nullStr(item.ResolutionTimeStr),     // Время решения (часы)
-		nullStr(item.SLAStatus),             // SLA
-		nullStr(item.SourceDepartment),      // Источник
-		nullStr(item.Comment),               // Комментарий
+		item.OrderID,                           //
+		nullStr(item.CreatorFio),               // Заявитель
+		item.CreatedAt.In(loc).Format(dateFmt), // Дата обращения
+		item.CreatedAt.In(loc).Format(timeFmt), // Время обращения
+		item.OrderID,                           // ID заявки
+		nullStr(item.OrderTypeName),            // Категория
+		nullStr(item.PriorityName),             // Приоритет
+		nullStr(item.StatusName),               // Статус
+		nullStr(item.OrderName),                // Описание проблемы
+		nullStr(item.ResponsibleFio),           // Ответственный
+		nullTime(item.DelegatedAt, dateFmt),    // Дата назначения
+		nullStr(item.ExecutorFio),              // Исполнитель
+		nullTime(item.CompletedAt, dateFmt),    // Дата решения
+		nullStr(item.ResolutionTimeStr),        // Время решения (часы)
+		nullStr(item.SLAStatus),                // SLA
+		nullStr(item.SourceDepartment),         // Источник
+		nullStr(item.Comment),                  // Комментарий
 	}
 }
 
@@ -140,7 +141,7 @@ func (c *ReportController) respondWithXLSX(ctx echo.Context, data []entities.Rep
 
 	for i, item := range data {
 		cell, _ := excelize.CoordinatesToCellName(1, i+2)
-		row := rowToSlice(item)
+		row := rowToSlice(item, utils.LocationFromCtx(ctx.Request().Context()))
 		f.SetSheetRow(sheet, cell, &row)
 	}
 	// Авто-ширина колонок для красоты
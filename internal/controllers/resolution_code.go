@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type ResolutionCodeController struct {
+	service services.ResolutionCodeServiceInterface
+	logger  *zap.Logger
+}
+
+func NewResolutionCodeController(service services.ResolutionCodeServiceInterface, logger *zap.Logger) *ResolutionCodeController {
+	return &ResolutionCodeController{service: service, logger: logger}
+}
+
+func (c *ResolutionCodeController) GetAll(ctx echo.Context) error {
+	includeInactive, _ := strconv.ParseBool(ctx.QueryParam("include_inactive"))
+	result, err := c.service.ListResolutionCodes(ctx.Request().Context(), includeInactive)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Коды решения получены", http.StatusOK)
+}
+
+func (c *ResolutionCodeController) Create(ctx echo.Context) error {
+	var d dto.CreateResolutionCodeDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateResolutionCode(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Код решения создан", http.StatusCreated)
+}
+
+func (c *ResolutionCodeController) Update(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+	var d dto.UpdateResolutionCodeDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateResolutionCode(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Код решения обновлён", http.StatusOK)
+}
+
+func (c *ResolutionCodeController) Delete(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+	if err := c.service.DeleteResolutionCode(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Код решения удалён", http.StatusOK)
+}
+
+func (c *ResolutionCodeController) GetStats(ctx echo.Context) error {
+	result, err := c.service.GetResolutionStats(ctx.Request().Context(), ctx.QueryParam("from"), ctx.QueryParam("to"))
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Статистика кодов решения получена", http.StatusOK)
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type RetentionController struct {
+	service services.RetentionServiceInterface
+	logger  *zap.Logger
+}
+
+func NewRetentionController(service services.RetentionServiceInterface, logger *zap.Logger) *RetentionController {
+	return &RetentionController{service: service, logger: logger}
+}
+
+func (c *RetentionController) parseUintParam(ctx echo.Context, name string) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil)
+	}
+	return id, nil
+}
+
+func (c *RetentionController) GetRules(ctx echo.Context) error {
+	result, err := c.service.GetRules(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Правила хранения получены", http.StatusOK)
+}
+
+func (c *RetentionController) CreateRule(ctx echo.Context) error {
+	var d dto.CreateRetentionRuleDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateRule(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Правило хранения создано", http.StatusCreated)
+}
+
+func (c *RetentionController) UpdateRule(ctx echo.Context) error {
+	id, err := c.parseUintParam(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateRetentionRuleDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateRule(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Правило хранения обновлено", http.StatusOK)
+}
+
+func (c *RetentionController) DeleteRule(ctx echo.Context) error {
+	id, err := c.parseUintParam(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteRule(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Правило хранения удалено", http.StatusOK)
+}
+
+func (c *RetentionController) GetHolds(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetHolds(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Список удержанных заявок получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func (c *RetentionController) SetHold(ctx echo.Context) error {
+	var d dto.CreateRetentionHoldDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.SetHold(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Заявка поставлена на удержание", http.StatusOK)
+}
+
+func (c *RetentionController) RemoveHold(ctx echo.Context) error {
+	orderID, err := c.parseUintParam(ctx, "orderID")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.RemoveHold(ctx.Request().Context(), orderID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Удержание заявки снято", http.StatusOK)
+}
+
+func (c *RetentionController) GetRuns(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetRuns(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Журнал запусков правил хранения получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func (c *RetentionController) GetRun(ctx echo.Context) error {
+	id, err := c.parseUintParam(ctx, "id")
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetRun(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Отчёт запуска правил хранения получен", http.StatusOK)
+}
+
+// Preview — пробный запуск: отчёт о том, что удалили бы активные правила, без изменений.
+func (c *RetentionController) Preview(ctx echo.Context) error {
+	result, err := c.service.Preview(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Пробный запуск выполнен, изменения не сохранены", http.StatusOK)
+}
+
+// Trigger ставит настоящий запуск в очередь; отчёт появится в GET /admin/retention/runs.
+func (c *RetentionController) Trigger(ctx echo.Context) error {
+	result, err := c.service.Trigger(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Запуск правил хранения поставлен в очередь", http.StatusAccepted)
+}
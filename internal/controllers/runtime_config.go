@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	"request-system/pkg/utils"
+)
+
+type RuntimeConfigController struct {
+	service services.RuntimeConfigServiceInterface
+	logger  *zap.Logger
+}
+
+func NewRuntimeConfigController(service services.RuntimeConfigServiceInterface, logger *zap.Logger) *RuntimeConfigController {
+	return &RuntimeConfigController{service: service, logger: logger}
+}
+
+func (c *RuntimeConfigController) Get(ctx echo.Context) error {
+	result, err := c.service.Get(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Действующие настройки получены", http.StatusOK)
+}
+
+func (c *RuntimeConfigController) Reload(ctx echo.Context) error {
+	result, err := c.service.Reload(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Настройки перечитаны", http.StatusOK)
+}
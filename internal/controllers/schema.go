@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/services"
+	"request-system/pkg/utils"
+)
+
+type SchemaController struct {
+	service services.SchemaServiceInterface
+	logger  *zap.Logger
+}
+
+func NewSchemaController(service services.SchemaServiceInterface, logger *zap.Logger) *SchemaController {
+	return &SchemaController{service: service, logger: logger}
+}
+
+func (c *SchemaController) GetVersion(ctx echo.Context) error {
+	result, err := c.service.GetVersion(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Версия схемы БД получена", http.StatusOK)
+}
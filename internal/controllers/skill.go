@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type SkillController struct {
+	service services.SkillServiceInterface
+	logger  *zap.Logger
+}
+
+func NewSkillController(service services.SkillServiceInterface, logger *zap.Logger) *SkillController {
+	return &SkillController{service: service, logger: logger}
+}
+
+func (c *SkillController) GetAll(ctx echo.Context) error {
+	result, err := c.service.ListSkills(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Навыки получены", http.StatusOK)
+}
+
+func (c *SkillController) Create(ctx echo.Context) error {
+	var d dto.CreateSkillDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateSkill(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Навык создан", http.StatusCreated)
+}
+
+func (c *SkillController) Delete(ctx echo.Context) error {
+	id, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteSkill(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Навык удалён", http.StatusOK)
+}
+
+func (c *SkillController) GetUserSkills(ctx echo.Context) error {
+	id, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetUserSkills(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Навыки пользователя получены", http.StatusOK)
+}
+
+func (c *SkillController) SetUserSkills(ctx echo.Context) error {
+	id, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.SetSkillsDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	result, err := c.service.SetUserSkills(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Навыки пользователя обновлены", http.StatusOK)
+}
+
+func (c *SkillController) GetOrderTypeSkills(ctx echo.Context) error {
+	id, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetOrderTypeSkills(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Навыки типа заявки получены", http.StatusOK)
+}
+
+func (c *SkillController) SetOrderTypeSkills(ctx echo.Context) error {
+	id, err := parseSkillOwnerID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.SetSkillsDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	result, err := c.service.SetOrderTypeSkills(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Навыки типа заявки обновлены", http.StatusOK)
+}
+
+func parseSkillOwnerID(ctx echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil)
+	}
+	return id, nil
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type SLAEscalationController struct {
+	service services.SLAEscalationServiceInterface
+	logger  *zap.Logger
+}
+
+func NewSLAEscalationController(service services.SLAEscalationServiceInterface, logger *zap.Logger) *SLAEscalationController {
+	return &SLAEscalationController{service: service, logger: logger}
+}
+
+func (c *SLAEscalationController) parseDepartmentID(ctx echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil)
+	}
+	return id, nil
+}
+
+func (c *SLAEscalationController) GetSettings(ctx echo.Context) error {
+	result, err := c.service.GetSettings(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Пороги эскалации получены", http.StatusOK)
+}
+
+func (c *SLAEscalationController) GetDepartmentSetting(ctx echo.Context) error {
+	id, err := c.parseDepartmentID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetDepartmentSetting(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Порог эскалации получен", http.StatusOK)
+}
+
+func (c *SLAEscalationController) UpdateDepartmentSetting(ctx echo.Context) error {
+	id, err := c.parseDepartmentID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateSLAEscalationSettingDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateDepartmentSetting(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Порог эскалации сохранён", http.StatusOK)
+}
+
+func (c *SLAEscalationController) ResetDepartmentSetting(ctx echo.Context) error {
+	id, err := c.parseDepartmentID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.ResetDepartmentSetting(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Порог эскалации сброшен", http.StatusOK)
+}
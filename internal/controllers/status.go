@@ -24,6 +24,17 @@ func NewStatusController(statusService services.StatusServiceInterface, logger *
 	return &StatusController{statusService: statusService, logger: logger}
 }
 
+// @Summary     Список: статусы
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        statuses
+// @Param       page query int false "Страница (с 1)"
+// @Param       limit query int false "Размер страницы"
+// @Param       search query string false "Поиск"
+// @Param       withPagination query bool false "false — вернуть массив без пагинации"
+// @Success     200 {list} dto.StatusDTO
+// @Success     304 "Не изменился"
+// @Permission  status:view
+// @Router      /status [get]
 func (c *StatusController) GetStatuses(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
@@ -42,6 +53,15 @@ func (c *StatusController) GetStatuses(ctx echo.Context) error {
 	)
 }
 
+// @Summary     Получение по ID: статусы
+// @Description Отдаёт ETag и Last-Modified; с If-None-Match/If-Modified-Since вернёт 304, если справочник не менялся.
+// @Tags        statuses
+// @Param       id path int true "ID"
+// @Success     200 {object} dto.StatusDTO
+// @Success     304 "Не изменился"
+// @Failure     404 "Не найдено"
+// @Permission  status:view
+// @Router      /status/{id} [get]
 func (c *StatusController) FindStatus(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
@@ -79,6 +99,12 @@ func (c *StatusController) FindByCode(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, map[string]any{"id": id}, "Статус найден", http.StatusOK)
 }
 
+// @Summary     Создание: статусы
+// @Tags        statuses
+// @Param       body body dto.CreateStatusDTO true "Данные"
+// @Success     201 {object} dto.StatusDTO
+// @Permission  status:create
+// @Router      /status [post]
 func (c *StatusController) CreateStatus(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	contentType := ctx.Request().Header.Get("Content-Type")
@@ -122,6 +148,13 @@ func (c *StatusController) CreateStatus(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, createdStatus, "Статус успешно создан", http.StatusCreated)
 }
 
+// @Summary     Обновление: статусы
+// @Tags        statuses
+// @Param       id path int true "ID"
+// @Param       body body dto.UpdateStatusDTO true "Изменяемые поля"
+// @Success     200 {object} dto.StatusDTO
+// @Permission  status:update
+// @Router      /status/{id} [put]
 func (c *StatusController) UpdateStatus(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
@@ -167,6 +200,12 @@ func (c *StatusController) UpdateStatus(ctx echo.Context) error {
 	return utils.SuccessResponse(ctx, updatedStatus, "Статус успешно обновлен", http.StatusOK)
 }
 
+// @Summary     Удаление: статусы
+// @Tags        statuses
+// @Param       id path int true "ID"
+// @Success     200
+// @Permission  status:delete
+// @Router      /status/{id} [delete]
 func (c *StatusController) DeleteStatus(ctx echo.Context) error {
 	reqCtx := ctx.Request().Context()
 
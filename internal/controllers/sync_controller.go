@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
 	"request-system/internal/dto"
 	"request-system/internal/services"
@@ -24,6 +25,17 @@ func NewSyncController(service services.SyncServiceInterface, logger *zap.Logger
 	}
 }
 
+// @Summary     Приём справочников из 1С
+// @Description Данные ставятся в очередь заданий; ответ 202 с id задания приходит до окончания синхронизации. Ход обработки — GET /sync/jobs/{id}. С dry_run=true выгрузка проверяется без сохранения: в задании будут счётчики и изменения полей по записям. С snapshot=true выгрузка считается полной: записи 1С переданных справочников, которых в ней нет, переводятся в INACTIVE. С best_effort=true ошибочные записи пропускаются (каждая в своей точке сохранения) и попадают в errors, остальные сохраняются.
+// @Tags        integrations
+// @Param       dry_run query bool false "Только показать изменения"
+// @Param       snapshot query bool false "Полная выгрузка: деактивировать отсутствующие записи"
+// @Param       best_effort query bool false "Пропускать ошибочные записи"
+// @Param       body body dto.Webhook1CPayloadDTO true "Справочники 1С"
+// @Success     202 {object} dto.SyncJobDTO
+// @Failure     401 "Неверный ключ"
+// @Security    OneCKeyAuth
+// @Router      /sync/1c [post]
 func (c *SyncController) HandleSyncFrom1C(ctx echo.Context) error {
 	var payload dto.Webhook1CPayloadDTO
 
@@ -34,18 +46,39 @@ func (c *SyncController) HandleSyncFrom1C(ctx echo.Context) error {
 		return utils.ErrorResponse(ctx, apiErr, c.logger)
 	}
 
-	accepted, err := c.syncService.Enqueue1CReferences(ctx.Request().Context(), payload)
+	job, err := c.syncService.Enqueue1CReferences(ctx.Request().Context(), payload, services.SyncJobOptions{
+		DryRun:     ctx.QueryParam("dry_run") == "true",
+		Snapshot:   ctx.QueryParam("snapshot") == "true",
+		BestEffort: ctx.QueryParam("best_effort") == "true",
+	})
 	if err != nil {
 		c.logger.Error("Не удалось поставить синхронизацию 1С в обработку", zap.Error(err))
 		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
-	message := "Запрос принят в обработку"
-	if !accepted {
-		message = "Синхронизация 1С уже выполняется"
+	return utils.SuccessResponse(ctx, job, "Запрос принят в обработку", http.StatusAccepted)
+}
+
+// @Summary     Статус задания синхронизации
+// @Description Прогресс по справочникам, ошибки отдельных записей (индекс в массиве выгрузки, поле, причина) и итог.
+// @Tags        integrations
+// @Param       id path int true "ID задания"
+// @Success     200 {object} dto.SyncJobDTO
+// @Failure     401 "Неверный ключ"
+// @Failure     404 "Задание не найдено"
+// @Security    OneCKeyAuth
+// @Router      /sync/jobs/{id} [get]
+func (c *SyncController) GetJob(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
 	}
 
-	return utils.SuccessResponse(ctx, nil, message, http.StatusAccepted)
+	job, err := c.syncService.GetJob(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, job, "Задание синхронизации получено", http.StatusOK)
 }
 
 func (c *SyncController) HandleSyncAll(ctx echo.Context) error {
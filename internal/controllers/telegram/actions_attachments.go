@@ -0,0 +1,148 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"request-system/config"
+	apperrors "request-system/pkg/errors"
+	tgapi "request-system/pkg/telegram"
+	"request-system/pkg/validation"
+)
+
+const orderAttachmentUploadContext = "order_document"
+
+// telegramAttachment — файл из сообщения Telegram, который нужно прикрепить к заявке.
+type telegramAttachment struct {
+	FileID   string
+	FileName string
+	MimeType string
+	FileSize int64
+}
+
+func hasTelegramAttachment(msg *TelegramMessage) bool {
+	return msg != nil && (len(msg.Photo) > 0 || msg.Document != nil)
+}
+
+// extractTelegramAttachment выбирает файл из сообщения: документ или фото в максимальном разрешении.
+func extractTelegramAttachment(msg *TelegramMessage) *telegramAttachment {
+	if msg.Document != nil {
+		name := strings.TrimSpace(msg.Document.FileName)
+		if name == "" {
+			name = fmt.Sprintf("telegram_document_%s", msg.Document.FileUniqueID)
+		}
+		return &telegramAttachment{
+			FileID:   msg.Document.FileID,
+			FileName: name,
+			MimeType: msg.Document.MimeType,
+			FileSize: msg.Document.FileSize,
+		}
+	}
+
+	if len(msg.Photo) == 0 {
+		return nil
+	}
+
+	// Telegram присылает несколько размеров одного фото, берём самый большой.
+	best := msg.Photo[0]
+	for _, p := range msg.Photo[1:] {
+		if p.Width*p.Height > best.Width*best.Height {
+			best = p
+		}
+	}
+	return &telegramAttachment{
+		FileID:   best.FileID,
+		FileName: fmt.Sprintf("telegram_photo_%s.jpg", best.FileUniqueID),
+		MimeType: "image/jpeg",
+		FileSize: best.FileSize,
+	}
+}
+
+func (c *TelegramController) handleAttachmentMessage(ctx context.Context, chatID int64, msg *TelegramMessage) error {
+	user, userCtx, err := c.prepareUserContext(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	state, err := c.getUserState(ctx, chatID)
+	if err != nil || state == nil || state.Mode != "editing_order" || state.OrderID == 0 {
+		return c.tgService.SendMessageEx(ctx, chatID, c.t(ctx, "tg.order.attach_no_card"), tgapi.WithMarkdownV2())
+	}
+
+	attachment := extractTelegramAttachment(msg)
+	if attachment == nil {
+		return nil
+	}
+
+	rules := config.UploadContexts[orderAttachmentUploadContext]
+	maxBytes := rules.MaxSizeMB * 1024 * 1024
+	if maxBytes > 0 && attachment.FileSize > maxBytes {
+		return c.renderAttachmentResult(ctx, chatID, state.OrderID, userCtx, user.ID,
+			fmt.Sprintf("❌ Файл слишком большой \\(макс\\. %d МБ\\)\\.", rules.MaxSizeMB))
+	}
+
+	file, err := c.tgService.GetFile(ctx, attachment.FileID)
+	if err != nil {
+		c.logger.Error("Не удалось получить файл из Telegram", zap.Error(err), zap.Int64("chat_id", chatID))
+		return c.renderAttachmentResult(ctx, chatID, state.OrderID, userCtx, user.ID, "❌ Не удалось получить файл из Telegram\\.")
+	}
+
+	content, err := c.tgService.DownloadFile(ctx, file.FilePath, maxBytes)
+	if err != nil {
+		c.logger.Error("Не удалось скачать файл из Telegram", zap.Error(err), zap.Int64("chat_id", chatID))
+		return c.renderAttachmentResult(ctx, chatID, state.OrderID, userCtx, user.ID, "❌ Не удалось скачать файл\\.")
+	}
+
+	mimeType, err := validation.ValidateContent(content, attachment.MimeType, orderAttachmentUploadContext)
+	if err != nil {
+		return c.renderAttachmentResult(ctx, chatID, state.OrderID, userCtx, user.ID,
+			"❌ "+tgapi.EscapeTextForMarkdownV2(err.Error()))
+	}
+
+	fileName := filepath.Base(attachment.FileName)
+	if _, err := c.orderService.AttachFileToOrder(userCtx, state.OrderID, fileName, mimeType, content); err != nil {
+		c.logger.Error("Ошибка прикрепления файла через Telegram",
+			zap.Error(err),
+			zap.Uint64("order_id", state.OrderID),
+			zap.Uint64("user_id", user.ID))
+
+		reason := "Не удалось прикрепить файл."
+		var httpErr *apperrors.HttpError
+		if errors.As(err, &httpErr) && strings.TrimSpace(httpErr.Message) != "" {
+			reason = httpErr.Message
+		} else if errors.Is(err, apperrors.ErrForbidden) {
+			reason = "Недостаточно прав для этой операции."
+		}
+		return c.renderAttachmentResult(ctx, chatID, state.OrderID, userCtx, user.ID, "❌ "+tgapi.EscapeTextForMarkdownV2(reason))
+	}
+
+	return c.renderAttachmentResult(ctx, chatID, state.OrderID, userCtx, user.ID,
+		fmt.Sprintf("📎 Файл *%s* прикреплён к заявке\\.", tgapi.EscapeTextForMarkdownV2(fileName)))
+}
+
+// renderAttachmentResult перерисовывает меню редактирования заявки с уведомлением о результате.
+func (c *TelegramController) renderAttachmentResult(ctx context.Context, chatID int64, orderID uint64, userCtx context.Context, userID uint64, notice string) error {
+	state, err := c.getUserState(ctx, chatID)
+	if err != nil {
+		return c.sendStaleStateError(ctx, chatID, 0)
+	}
+
+	order, err := c.orderService.FindOrderByIDForTelegram(userCtx, userID, orderID)
+	if err != nil {
+		return c.renderStateScreen(
+			ctx,
+			chatID,
+			state,
+			"❌ Ошибка: заявка не найдена\\.",
+			tgapi.WithKeyboard(c.orderBackKeyboard(orderID)),
+			tgapi.WithMarkdownV2(),
+		)
+	}
+
+	return c.showEditMenuForState(ctx, chatID, state, order, notice)
+}
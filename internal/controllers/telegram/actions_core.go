@@ -3,7 +3,9 @@ package telegram
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -12,6 +14,8 @@ import (
 	"request-system/internal/authz"
 	"request-system/internal/dto"
 	"request-system/internal/entities"
+	"request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
 	"request-system/pkg/telegram"
 	"request-system/pkg/utils"
 )
@@ -48,6 +52,17 @@ func (c *TelegramController) handleSelectOrderAction(ctx context.Context, chatID
 	return c.showEditMenuForState(ctx, chatID, state, order)
 }
 
+// orderEditSnapshot — поля заявки, которые меняет меню бота. Разница двух снимков
+// даёт JSON Merge Patch для UpdateOrder, без ручного учёта изменённых полей.
+type orderEditSnapshot struct {
+	StatusID   uint64     `json:"status_id"`
+	ExecutorID *uint64    `json:"executor_id,omitempty"`
+	Duration   *time.Time `json:"duration,omitempty"`
+	Comment    *string    `json:"comment,omitempty"`
+	// Код решения выбирается вместе с закрывающим статусом
+	ResolutionCodeID *uint64 `json:"resolution_code_id,omitempty"`
+}
+
 func (c *TelegramController) handleSaveChanges(ctx context.Context, chatID int64, messageID int) error {
 	user, userCtx, err := c.prepareUserContext(ctx, chatID)
 	if err != nil {
@@ -81,8 +96,12 @@ func (c *TelegramController) handleSaveChanges(ctx context.Context, chatID int64
 		return c.tgService.EditMessageText(ctx, chatID, messageID, "❌ Ошибка при получении данных заявки\\.", telegram.WithMarkdownV2())
 	}
 
-	orderTypeCode, _ := c.orderTypeRepo.FindCodeByID(ctx, *currentOrder.OrderTypeID)
-	if orderTypeCode != "EQUIPMENT" {
+	commentRequired, err := c.orderService.IsCommentRequiredOnUpdate(ctx, *currentOrder.OrderTypeID)
+	if err != nil {
+		c.logger.Error("Не удалось получить правила проверки типа заявки", zap.Error(err), zap.Uint64("order_id", state.OrderID))
+		return c.sendInternalError(ctx, chatID)
+	}
+	if commentRequired {
 		comment, exists := state.GetComment()
 		if !exists || strings.TrimSpace(comment) == "" {
 			return c.tgService.EditMessageText(
@@ -95,39 +114,48 @@ func (c *TelegramController) handleSaveChanges(ctx context.Context, chatID int64
 		}
 	}
 
-	updateDTO := dto.UpdateOrderDTO{}
-	changesMap := make(map[string]interface{})
-
-	sid, sidExists, _ := state.GetStatusID()
-	if sidExists && currentOrder.StatusID != sid {
-		updateDTO.StatusID = &sid
-		changesMap["status_id"] = sid
+	dur, durExists, durErr := state.GetDuration()
+	if durErr != nil {
+		return c.sendInternalError(ctx, chatID)
 	}
 
-	eid, eidExists, _ := state.GetExecutorID()
-	if eidExists {
-		if eid == 0 {
-			changesMap["executor_id"] = nil
-			updateDTO.ExecutorID = nil
-		} else if currentOrder.ExecutorID == nil || *currentOrder.ExecutorID != eid {
-			updateDTO.ExecutorID = &eid
-			changesMap["executor_id"] = eid
+	original := orderEditSnapshot{
+		StatusID:         currentOrder.StatusID,
+		ExecutorID:       currentOrder.ExecutorID,
+		Duration:         currentOrder.Duration,
+		ResolutionCodeID: currentOrder.ResolutionCodeID,
+	}
+	modified := original
+	if sid, exists, _ := state.GetStatusID(); exists {
+		modified.StatusID = sid
+	}
+	if eid, exists, _ := state.GetExecutorID(); exists {
+		modified.ExecutorID = nil
+		if eid != 0 {
+			modified.ExecutorID = &eid
 		}
 	}
-
-	com, comExists := state.GetComment()
-	if comExists && strings.TrimSpace(com) != "" {
-		v := com
-		updateDTO.Comment = &v
+	if rid, exists, _ := state.GetResolutionCodeID(); exists {
+		modified.ResolutionCodeID = &rid
+	}
+	if com, exists := state.GetComment(); exists && strings.TrimSpace(com) != "" {
+		modified.Comment = &com
+	}
+	if durExists {
+		modified.Duration = dur
 	}
 
-	dur, durExists, durErr := state.GetDuration()
-	if durErr != nil {
+	// Изменения уходят в сервис тем же merge patch, что присылает веб-клиент.
+	patch, err := utils.CreateMergePatch(original, modified)
+	if err != nil {
+		c.logger.Error("Не удалось собрать merge patch", zap.Error(err), zap.Uint64("order_id", state.OrderID))
 		return c.sendInternalError(ctx, chatID)
 	}
-	if durExists {
-		updateDTO.Duration = dur
-		changesMap["duration"] = dur
+	var updateDTO dto.UpdateOrderDTO
+	changesMap, err := utils.ParseMergePatch(patch, &updateDTO)
+	if err != nil {
+		c.logger.Error("Не удалось разобрать merge patch", zap.Error(err), zap.ByteString("patch", patch))
+		return c.sendInternalError(ctx, chatID)
 	}
 
 	c.logger.Info("Сохранение через Telegram", zap.Uint64("order_id", state.OrderID), zap.Uint64("user_id", user.ID), zap.Any("updateDTO", updateDTO), zap.Any("changesMap", changesMap))
@@ -139,7 +167,11 @@ func (c *TelegramController) handleSaveChanges(ctx context.Context, chatID int64
 		errorMsg := "❌ *Ошибка сохранения*\n\n"
 		errStr := err.Error()
 
+		var httpErr *apperrors.HttpError
 		switch {
+		case errors.As(err, &httpErr) && httpErr.Code == http.StatusForbidden && httpErr.Details != nil:
+			// Сервис назвал поле, на которое нет права: кнопки могли устареть после смены роли.
+			errorMsg += "_" + telegram.EscapeTextForMarkdownV2(httpErr.LocalizedMessage(languageFromCtx(ctx))) + "_"
 		case strings.Contains(errStr, "Forbidden") || strings.Contains(errStr, "прав"):
 			errorMsg += "_Недостаточно прав для этой операции\\._"
 		case strings.Contains(errStr, "закрыта") || strings.Contains(errStr, "CLOSED"):
@@ -227,12 +259,23 @@ func (c *TelegramController) handleCallbackQuery(ctx context.Context, query *Tel
 	case "main_stats":
 		_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramStateKey, chatID))
 		return c.handleStatsCommand(ctx, chatID, msgID)
+	case "main_unit_report":
+		_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramStateKey, chatID))
+		return c.handleUnitReportCommand(ctx, chatID, msgID)
+	case "main_new_order":
+		_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramStateKey, chatID))
+		return c.handleNewOrderCommand(ctx, chatID, msgID)
 	case "main_status":
 		_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramStateKey, chatID))
 		return c.handleLinkStatusCommand(ctx, chatID)
 	case "main_help":
 		_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramStateKey, chatID))
 		return c.handleHelpCommand(ctx, chatID)
+	case "main_language":
+		return c.handleLanguageCommand(ctx, chatID, msgID)
+	case "set_language":
+		lang, _ := data["lang"].(string)
+		return c.handleSetLanguageAction(ctx, chatID, msgID, lang)
 	case "list_page":
 		page := 1
 		if pageRaw, ok := data["page"].(float64); ok {
@@ -246,6 +289,10 @@ func (c *TelegramController) handleCallbackQuery(ctx context.Context, query *Tel
 		return c.handleUnlinkCommand(ctx, chatID)
 	case "unlink_confirm":
 		return c.handleConfirmUnlinkAction(ctx, chatID)
+	case "rebind_confirm":
+		return c.handleConfirmRebindAction(ctx, chatID)
+	case "rebind_cancel":
+		return c.handleCancelRebindAction(ctx, chatID)
 	case "show_my_tasks":
 		return c.handleMyTasksCommand(ctx, chatID, msgID)
 	case "sel", "select_order":
@@ -272,8 +319,14 @@ func (c *TelegramController) handleCallbackQuery(ctx context.Context, query *Tel
 		return c.handleDelegateStart(ctx, chatID, msgID)
 	case "set_status":
 		if id, ok := data["status_id"].(float64); ok {
-			return c.handleSetSomething(ctx, chatID, "status_id", uint64(id), "Статус обновлён")
+			return c.handleSetStatusAction(ctx, chatID, uint64(id))
+		}
+	case "set_resolution":
+		if id, ok := data["code_id"].(float64); ok {
+			return c.handleSetResolutionAction(ctx, chatID, uint64(id))
 		}
+	case "resolution_skip":
+		return c.handleSkipResolutionComment(ctx, chatID)
 	case "set_duration":
 		if val, ok := data["value"].(string); ok {
 			return c.handleSetDuration(ctx, chatID, val)
@@ -289,8 +342,8 @@ func (c *TelegramController) handleCallbackQuery(ctx context.Context, query *Tel
 	return nil
 }
 
-func (c *TelegramController) sendEditMenu(ctx context.Context, chatID int64, messageID int, order *entities.Order) error {
-	status, err := c.statusRepo.FindStatus(ctx, order.StatusID)
+func (c *TelegramController) sendEditMenu(ctx context.Context, chatID int64, messageID int, order *entities.Order, notice ...string) error {
+	status, err := c.statuses.FindByID(ctx, order.StatusID)
 	if err != nil {
 		c.logger.Error("Не удалось получить статус", zap.Error(err))
 		return c.sendInternalError(ctx, chatID)
@@ -311,6 +364,7 @@ func (c *TelegramController) sendEditMenu(ctx context.Context, chatID int64, mes
 	authCtx := authz.Context{
 		Actor:         user,
 		Permissions:   perms,
+		Conditions:    utils.GetPermissionConditionsFromCtx(userCtx),
 		Target:        order,
 		IsParticipant: order.CreatorID == user.ID || (order.ExecutorID != nil && *order.ExecutorID == user.ID),
 	}
@@ -322,40 +376,44 @@ func (c *TelegramController) sendEditMenu(ctx context.Context, chatID int64, mes
 	canEdit := canStatus || canDuration || canComment || canDelegate
 
 	var text strings.Builder
-	text.WriteString(fmt.Sprintf("📋 *Заявка №%d*\n\n", order.ID))
-	text.WriteString(fmt.Sprintf("📝 *Описание:*\n%s\n\n", telegram.EscapeTextForMarkdownV2(order.Name)))
+	if len(notice) > 0 && strings.TrimSpace(notice[0]) != "" {
+		text.WriteString(notice[0] + "\n\n")
+	}
+	text.WriteString(c.t(ctx, "tg.order.title", telegram.EscapeTextForMarkdownV2(order.DisplayNumber())) + "\n\n")
+	text.WriteString(fmt.Sprintf("%s\n%s\n\n", c.t(ctx, "tg.order.description"), telegram.EscapeTextForMarkdownV2(order.Name)))
 
 	statusEmoji := getStatusEmoji(status)
-	text.WriteString(fmt.Sprintf("%s *Статус:* %s\n", statusEmoji, telegram.EscapeTextForMarkdownV2(status.Name)))
+	text.WriteString(fmt.Sprintf("%s %s %s\n", statusEmoji, c.t(ctx, "tg.order.status"), telegram.EscapeTextForMarkdownV2(status.Name)))
 
 	if creator != nil {
-		text.WriteString(fmt.Sprintf("👤 *Создатель:* %s\n", telegram.EscapeTextForMarkdownV2(creator.Fio)))
+		text.WriteString(fmt.Sprintf("%s %s\n", c.t(ctx, "tg.order.creator"), telegram.EscapeTextForMarkdownV2(creator.Fio)))
 	}
 	if executor != nil {
-		text.WriteString(fmt.Sprintf("👨‍💼 *Исполнитель:* %s\n", telegram.EscapeTextForMarkdownV2(executor.Fio)))
+		text.WriteString(fmt.Sprintf("%s %s\n", c.t(ctx, "tg.order.executor"), telegram.EscapeTextForMarkdownV2(executor.Fio)))
 	} else {
-		text.WriteString("👨‍💼 *Исполнитель:* _не назначен_\n")
+		text.WriteString(c.t(ctx, "tg.order.executor") + " " + c.t(ctx, "tg.order.not_assigned") + "\n")
 	}
 
 	if order.Duration != nil {
-		durationStr := order.Duration.Format("02.01.2006 15:04")
-		if order.Duration.Before(time.Now()) {
-			text.WriteString(fmt.Sprintf("⏰ *Срок:* ~%s~ ⚠️ _просрочено_\n", telegram.EscapeTextForMarkdownV2(durationStr)))
+		durationStr := order.Duration.In(utils.LocationFromCtx(ctx)).Format("02.01.2006 15:04")
+		// Просрочка — как в filter[overdue]: у финальных статусов срока уже нет.
+		if order.Duration.Before(time.Now()) && (status.Code == nil || !constants.IsFinalStatus(*status.Code)) {
+			text.WriteString(fmt.Sprintf("%s ~%s~ ⚠️ %s\n", c.t(ctx, "tg.order.deadline"), telegram.EscapeTextForMarkdownV2(durationStr), c.t(ctx, "tg.order.overdue")))
 		} else {
-			text.WriteString(fmt.Sprintf("⏰ *Срок:* %s\n", telegram.EscapeTextForMarkdownV2(durationStr)))
+			text.WriteString(fmt.Sprintf("%s %s\n", c.t(ctx, "tg.order.deadline"), telegram.EscapeTextForMarkdownV2(durationStr)))
 		}
 
 		history, historyErr := c.orderHistoryRepo.FindByOrderID(ctx, order.ID, 1, 0)
 		if historyErr == nil && len(history) > 0 {
 			for i := len(history) - 1; i >= 0; i-- {
 				if history[i].Comment.Valid && strings.TrimSpace(history[i].Comment.String) != "" {
-					text.WriteString(fmt.Sprintf("\n💬 *Последний комментарий:*\n_%s_\n", telegram.EscapeTextForMarkdownV2(history[i].Comment.String)))
+					text.WriteString(fmt.Sprintf("\n%s\n_%s_\n", c.t(ctx, "tg.order.last_comment"), telegram.EscapeTextForMarkdownV2(history[i].Comment.String)))
 					break
 				}
 			}
 		}
 	} else {
-		text.WriteString("⏰ *Срок:* _не задан_\n")
+		text.WriteString(c.t(ctx, "tg.order.deadline") + " " + c.t(ctx, "tg.order.not_set") + "\n")
 	}
 
 	var keyboard [][]telegram.InlineKeyboardButton
@@ -363,20 +421,20 @@ func (c *TelegramController) sendEditMenu(ctx context.Context, chatID int64, mes
 
 	if isClosed || !canEdit {
 		if isClosed {
-			text.WriteString("\n🔒 *Заявка закрыта*\\.\n_Карточка доступна только для просмотра\\. Редактирование недоступно\\._")
+			text.WriteString("\n" + c.t(ctx, "tg.order.closed"))
 		} else {
-			text.WriteString("\n👁️ *Режим просмотра*\\.\n_У вас есть доступ к просмотру заявки, но нет прав на её редактирование\\._")
+			text.WriteString("\n" + c.t(ctx, "tg.order.view_only"))
 		}
-		keyboard = append(keyboard, []telegram.InlineKeyboardButton{{Text: "◀️ К списку", CallbackData: `{"action":"edit_cancel"}`}})
+		keyboard = append(keyboard, []telegram.InlineKeyboardButton{{Text: c.t(ctx, "tg.btn.to_list"), CallbackData: `{"action":"edit_cancel"}`}})
 	} else {
-		text.WriteString("\n_Выберите действие:_")
+		text.WriteString("\n" + c.t(ctx, "tg.order.choose_action"))
 
 		row1 := []telegram.InlineKeyboardButton{}
 		if canStatus {
-			row1 = append(row1, telegram.InlineKeyboardButton{Text: "🔄 Статус", CallbackData: `{"action":"edit_status_start"}`})
+			row1 = append(row1, telegram.InlineKeyboardButton{Text: c.t(ctx, "tg.btn.edit_status"), CallbackData: `{"action":"edit_status_start"}`})
 		}
 		if canDuration {
-			row1 = append(row1, telegram.InlineKeyboardButton{Text: "⏰ Срок", CallbackData: `{"action":"edit_duration_start"}`})
+			row1 = append(row1, telegram.InlineKeyboardButton{Text: c.t(ctx, "tg.btn.edit_duration"), CallbackData: `{"action":"edit_duration_start"}`})
 		}
 		if len(row1) > 0 {
 			keyboard = append(keyboard, row1)
@@ -384,18 +442,18 @@ func (c *TelegramController) sendEditMenu(ctx context.Context, chatID int64, mes
 
 		row2 := []telegram.InlineKeyboardButton{}
 		if canComment {
-			row2 = append(row2, telegram.InlineKeyboardButton{Text: "💬 Комментарий", CallbackData: `{"action":"edit_comment_start"}`})
+			row2 = append(row2, telegram.InlineKeyboardButton{Text: c.t(ctx, "tg.btn.edit_comment"), CallbackData: `{"action":"edit_comment_start"}`})
 		}
 		if canDelegate {
-			row2 = append(row2, telegram.InlineKeyboardButton{Text: "👤 Делегировать", CallbackData: `{"action":"edit_delegate_start"}`})
+			row2 = append(row2, telegram.InlineKeyboardButton{Text: c.t(ctx, "tg.btn.edit_delegate"), CallbackData: `{"action":"edit_delegate_start"}`})
 		}
 		if len(row2) > 0 {
 			keyboard = append(keyboard, row2)
 		}
 
 		keyboard = append(keyboard, []telegram.InlineKeyboardButton{
-			{Text: "✅ Сохранить", CallbackData: `{"action":"edit_save"}`},
-			{Text: menuBackButton, CallbackData: `{"action":"edit_cancel"}`},
+			{Text: c.t(ctx, "tg.btn.save"), CallbackData: `{"action":"edit_save"}`},
+			{Text: c.t(ctx, "tg.btn.back"), CallbackData: `{"action":"edit_cancel"}`},
 		})
 	}
 
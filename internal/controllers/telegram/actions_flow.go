@@ -2,11 +2,17 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/orderquery"
 	tgapi "request-system/pkg/telegram"
 	"request-system/pkg/types"
 	"request-system/pkg/utils"
@@ -46,7 +52,7 @@ func (c *TelegramController) handleEditStatusStart(ctx context.Context, chatID i
 		return c.sendInternalError(ctx, chatID)
 	}
 
-	currentStatus, err := c.statusRepo.FindStatus(ctx, order.StatusID)
+	currentStatus, err := c.statuses.FindByID(ctx, order.StatusID)
 	if err != nil {
 		return c.sendInternalError(ctx, chatID)
 	}
@@ -80,6 +86,88 @@ func (c *TelegramController) handleEditStatusStart(ctx context.Context, chatID i
 	return c.renderStateScreen(ctx, chatID, state, "Выберите новый статус:", tgapi.WithKeyboard(keyboard))
 }
 
+// handleSetStatusAction запоминает новый статус. Если он закрывает заявку, бот следом спрашивает
+// код решения и необязательный комментарий, прежде чем вернуться в меню заявки.
+func (c *TelegramController) handleSetStatusAction(ctx context.Context, chatID int64, statusID uint64) error {
+	state, err := c.getUserState(ctx, chatID)
+	if err != nil {
+		return c.sendStaleStateError(ctx, chatID, 0)
+	}
+
+	status, err := c.statuses.FindByID(ctx, statusID)
+	if err != nil {
+		return c.sendInternalError(ctx, chatID)
+	}
+
+	var codes []entities.ResolutionCode
+	if status.Code != nil && (*status.Code == constants.StatusCompleted || *status.Code == constants.StatusClosed) {
+		codes, err = c.resolutionCodes.FindAll(ctx, true)
+		if err != nil {
+			// Без справочника заявку всё равно можно закрыть, просто без кода.
+			c.logger.Warn("Не удалось получить коды решения", zap.Error(err), zap.Int64("chat_id", chatID))
+		}
+	}
+	if len(codes) == 0 {
+		state.ClearResolutionCodeID()
+		if err := c.setUserState(ctx, chatID, state); err != nil {
+			return c.sendInternalError(ctx, chatID)
+		}
+		return c.handleSetSomething(ctx, chatID, "status_id", statusID, "Статус обновлён")
+	}
+
+	state.SetStatusID(statusID)
+	state.Mode = "awaiting_resolution"
+	if err := c.setUserState(ctx, chatID, state); err != nil {
+		return c.sendInternalError(ctx, chatID)
+	}
+	_ = c.answerCallback(ctx, "Укажите код решения")
+
+	var keyboard [][]tgapi.InlineKeyboardButton
+	for _, code := range codes {
+		cb := fmt.Sprintf(`{"action":"set_resolution","code_id":%d}`, code.ID)
+		keyboard = append(keyboard, []tgapi.InlineKeyboardButton{{Text: code.Name, CallbackData: cb}})
+	}
+	keyboard = append(keyboard, c.orderBackKeyboard(state.OrderID)...)
+
+	return c.renderStateScreen(
+		ctx,
+		chatID,
+		state,
+		fmt.Sprintf("✅ *%s*\n\nЧем решена заявка?", tgapi.EscapeTextForMarkdownV2(status.Name)),
+		tgapi.WithKeyboard(keyboard),
+		tgapi.WithMarkdownV2(),
+	)
+}
+
+func (c *TelegramController) handleSetResolutionAction(ctx context.Context, chatID int64, codeID uint64) error {
+	state, err := c.getUserState(ctx, chatID)
+	if err != nil || state.Mode != "awaiting_resolution" {
+		return c.sendStaleStateError(ctx, chatID, 0)
+	}
+
+	state.SetResolutionCodeID(codeID)
+	state.Mode = "awaiting_resolution_comment"
+	if err := c.setUserState(ctx, chatID, state); err != nil {
+		return c.sendInternalError(ctx, chatID)
+	}
+	_ = c.answerCallback(ctx, "Код решения выбран")
+
+	return c.renderResolutionCommentPrompt(ctx, chatID, state, "")
+}
+
+// handleSkipResolutionComment закрывает шаг комментария: код решения уже в состоянии.
+func (c *TelegramController) handleSkipResolutionComment(ctx context.Context, chatID int64) error {
+	state, err := c.getUserState(ctx, chatID)
+	if err != nil {
+		return c.sendStaleStateError(ctx, chatID, 0)
+	}
+	codeID, exists, _ := state.GetResolutionCodeID()
+	if !exists {
+		return c.sendStaleStateError(ctx, chatID, 0)
+	}
+	return c.handleSetSomething(ctx, chatID, "resolution_code_id", codeID, "Код решения сохранён")
+}
+
 func (c *TelegramController) handleEditDurationStart(ctx context.Context, chatID int64, messageID int) error {
 	state, err := c.ensureStateMessage(ctx, chatID, messageID)
 	if err != nil {
@@ -111,10 +199,12 @@ func (c *TelegramController) handleSetDuration(ctx context.Context, chatID int64
 	if strings.EqualFold(text, "clear") {
 		value = nil
 	} else {
+		// Дата вводится по часам пользователя, а не сервера.
+		loc := utils.LocationFromCtx(ctx)
 		formats := []string{"2006-01-02 15:04", "02.01.2006 15:04", "02.01.2006"}
 		var parseErr error
 		for _, format := range formats {
-			parsedTime, parseErr = time.ParseInLocation(format, text, c.loc)
+			parsedTime, parseErr = time.ParseInLocation(format, text, loc)
 			if parseErr == nil {
 				break
 			}
@@ -128,7 +218,7 @@ func (c *TelegramController) handleSetDuration(ctx context.Context, chatID int64
 			)
 		}
 
-		now := time.Now().In(c.loc)
+		now := time.Now().In(loc)
 		if parsedTime.Before(now) {
 			return c.renderDurationPrompt(ctx, chatID, state, "❌ Дата не может быть в прошлом\\.")
 		}
@@ -163,12 +253,17 @@ func (c *TelegramController) handleSetComment(ctx context.Context, chatID int64,
 		return c.sendStaleStateError(ctx, chatID, 0)
 	}
 
+	prompt := c.renderCommentPrompt
+	if state.Mode == "awaiting_resolution_comment" {
+		prompt = c.renderResolutionCommentPrompt
+	}
+
 	text = strings.TrimSpace(text)
 	if text == "" {
-		return c.renderCommentPrompt(ctx, chatID, state, "❌ Комментарий не может быть пустым\\.")
+		return prompt(ctx, chatID, state, "❌ Комментарий не может быть пустым\\.")
 	}
 	if len(text) > maxCommentLength {
-		return c.renderCommentPrompt(
+		return prompt(
 			ctx,
 			chatID,
 			state,
@@ -203,6 +298,17 @@ func (c *TelegramController) handleDelegateStart(ctx context.Context, chatID int
 	}
 
 	const maxDelegateCandidates = 9
+	if rows := c.suggestedExecutorRows(userCtx, order, user.ID, maxDelegateCandidates); len(rows) > 0 {
+		state.Mode = "awaiting_executor"
+		if err := c.setUserState(ctx, chatID, state); err != nil {
+			return c.sendInternalError(ctx, chatID)
+		}
+		return c.renderExecutorSelection(ctx, chatID, state,
+			"👤 *Рекомендуемые исполнители:*\n_Сначала наименее загруженные, можно ввести ФИО для поиска_",
+			rows,
+		)
+	}
+
 	filter := types.Filter{
 		Filter:         make(map[string]interface{}),
 		Limit:          maxDelegateCandidates,
@@ -275,6 +381,27 @@ func (c *TelegramController) handleDelegateStart(ctx context.Context, chatID int
 	return c.renderExecutorSelection(ctx, chatID, state, text, rows)
 }
 
+// suggestedExecutorRows — кнопки с кандидатами из подбора по нагрузке; пусто, если подбор
+// ничего не дал, тогда показываются коллеги пользователя.
+func (c *TelegramController) suggestedExecutorRows(ctx context.Context, order *entities.Order, selfID uint64, limit int) [][]tgapi.InlineKeyboardButton {
+	suggestions, err := c.orderService.SuggestExecutors(ctx, order.ID, limit)
+	if err != nil {
+		c.logger.Debug("Подбор исполнителей недоступен, показываю коллег", zap.Uint64("order_id", order.ID), zap.Error(err))
+		return nil
+	}
+
+	var rows [][]tgapi.InlineKeyboardButton
+	for _, candidate := range suggestions {
+		if candidate.UserID == selfID || candidate.IsCurrentExecutor {
+			continue
+		}
+		label := fmt.Sprintf("%s · в работе: %d", candidate.Fio, candidate.OpenOrders)
+		cb := fmt.Sprintf(`{"action":"set_executor","user_id":%d}`, candidate.UserID)
+		rows = append(rows, []tgapi.InlineKeyboardButton{{Text: label, CallbackData: cb}})
+	}
+	return rows
+}
+
 func (c *TelegramController) handleSetExecutorFromText(ctx context.Context, chatID int64, text string) error {
 	state, err := c.getUserState(ctx, chatID)
 	if err != nil {
@@ -364,6 +491,10 @@ func (c *TelegramController) handleSetSomething(ctx context.Context, chatID int6
 		default:
 			return c.sendInternalError(ctx, chatID)
 		}
+	case "resolution_code_id":
+		if id, ok := value.(uint64); ok {
+			state.SetResolutionCodeID(id)
+		}
 	case "comment":
 		if comment, ok := value.(string); ok {
 			state.SetComment(comment)
@@ -461,7 +592,19 @@ func (c *TelegramController) renderSearchResults(ctx context.Context, chatID int
 	}
 
 	filter := c.newTelegramOrderFilter("search", page)
-	filter.Search = query
+	if orderquery.LooksLikeQuery(query) {
+		if err := c.orderService.ApplyOrderQuery(userCtx, &filter, query); err != nil {
+			var httpErr *apperrors.HttpError
+			if errors.As(err, &httpErr) && httpErr.Code == http.StatusBadRequest {
+				notice := "❌ " + tgapi.EscapeTextForMarkdownV2(httpErr.LocalizedMessage(languageFromCtx(ctx)))
+				return c.renderSearchPrompt(ctx, chatID, messageID, notice)
+			}
+			c.logger.Error("Telegram search query failed", zap.Error(err), zap.Int64("chat_id", chatID))
+			return c.renderSearchPrompt(ctx, chatID, messageID, "❌ Ошибка поиска\\.")
+		}
+	} else {
+		filter.Search = query
+	}
 	resp, err := c.orderService.GetOrders(userCtx, filter, false, false, false)
 	if err != nil {
 		c.logger.Error("Telegram search failed", zap.Error(err), zap.Int64("chat_id", chatID))
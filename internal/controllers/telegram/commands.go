@@ -10,23 +10,25 @@ import (
 
 	"go.uber.org/zap"
 
+	"request-system/internal/authz"
 	"request-system/internal/dto"
+	"request-system/internal/services"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/sanitize"
 	"request-system/pkg/telegram"
 	"request-system/pkg/types"
+	"request-system/pkg/utils"
 )
 
-const menuAllOrdersButton = "📚 Все заявки"
-
 func (c *TelegramController) handleCommand(ctx context.Context, chatID int64, text string) error {
 	switch {
 	case strings.HasPrefix(text, "/start"):
 		return c.handleStartCommand(ctx, chatID, text)
 	case strings.HasPrefix(text, "/menu"):
 		return c.sendMainMenu(ctx, chatID)
-	case strings.HasPrefix(text, "/my_tasks") && c.cfg.AdvancedMode:
+	case strings.HasPrefix(text, "/my_tasks") && c.cfg.AdvancedModeActive():
 		return c.handleMyTasksCommand(ctx, chatID)
-	case strings.HasPrefix(text, "/stats") && c.cfg.AdvancedMode:
+	case strings.HasPrefix(text, "/stats") && c.cfg.AdvancedModeActive():
 		return c.handleStatsCommand(ctx, chatID)
 	case strings.HasPrefix(text, "/status"):
 		return c.handleLinkStatusCommand(ctx, chatID)
@@ -34,11 +36,13 @@ func (c *TelegramController) handleCommand(ctx context.Context, chatID int64, te
 		return c.handleUnlinkCommand(ctx, chatID)
 	case strings.HasPrefix(text, "/help"):
 		return c.handleHelpCommand(ctx, chatID)
+	case strings.HasPrefix(text, "/language"):
+		return c.handleLanguageCommand(ctx, chatID, 0)
 	default:
 		return c.tgService.SendMessageEx(
 			ctx,
 			chatID,
-			"❌ Неизвестная команда. Используйте /menu или /help.",
+			c.t(ctx, "tg.unknown_command"),
 			telegram.WithMarkdownV2(),
 		)
 	}
@@ -46,32 +50,20 @@ func (c *TelegramController) handleCommand(ctx context.Context, chatID int64, te
 
 func (c *TelegramController) handleStartCommand(ctx context.Context, chatID int64, text string) error {
 	if token := extractStartToken(text); token != "" {
+		if code, ok := strings.CutPrefix(token, services.EquipmentStartPrefix); ok {
+			return c.handleEquipmentScan(ctx, chatID, code)
+		}
 		return c.handleTokenLink(ctx, chatID, token)
 	}
 
 	existingUser, _, err := c.prepareUserContext(ctx, chatID)
 	if err == nil && existingUser != nil {
-		msg := fmt.Sprintf(
-			"👤 *Вы уже авторизованы как:* %s\n\n"+
-				"Используйте меню ниже для работы с заявками\\.\n\n"+
-				"*Команды:*\n"+
-				"/status \\- показать, к какому аккаунту привязан этот Telegram\n"+
-				"/unlink \\- отвязать этот Telegram от текущего аккаунта",
-			telegram.EscapeTextForMarkdownV2(existingUser.Fio),
-		)
-		return c.renderScreen(ctx, chatID, 0, msg, c.mainMenuScreenOptions()...)
+		msg := c.t(ctx, "tg.start.linked", telegram.EscapeTextForMarkdownV2(existingUser.Fio)) +
+			"\n\n" + c.t(ctx, "tg.commands_footer")
+		return c.renderScreen(ctx, chatID, 0, msg, c.mainMenuScreenOptions(ctx)...)
 	}
 
-	welcomeMsg := "Добро пожаловать в Telegram-бот HelpDesk.\n\n" +
-		"Этот бот позволяет работать с заявками банка прямо со смартфона.\n\n" +
-		"Как привязать Telegram:\n" +
-		"1. Откройте сайт HelpDesk и зайдите в профиль.\n" +
-		"2. Нажмите «Привязать Telegram».\n" +
-		"3. На сайте появятся ссылка, QR-код и короткий одноразовый код.\n" +
-		"4. Откройте бота со смартфона через QR-код или ссылку.\n" +
-		"5. Отправьте короткий код прямо сообщением в этот чат.\n" +
-		"6. Также работает команда /start <код>.\n\n" +
-		"Код действует ограниченное время. После привязки вам будут доступны список заявок, поиск, статистика, комментарии, изменение срока и делегирование."
+	welcomeMsg := c.t(ctx, "tg.start.welcome")
 
 	return c.renderScreen(ctx, chatID, 0, welcomeMsg)
 }
@@ -86,39 +78,9 @@ func extractStartToken(text string) string {
 }
 
 func (c *TelegramController) handleHelpCommand(ctx context.Context, chatID int64) error {
-	helpText := "📖 *Справка по боту*\n\n" +
-		"Бот работает с теми же правами доступа, что и веб\\-проект\\. Если у вас нет доступа к заявке на сайте, бот тоже её не покажет\\.\n\n" +
-		"*Основные команды:*\n" +
-		"/start \\- начало работы и привязка аккаунта по коду из профиля\n" +
-		"/menu \\- открыть главное меню\n" +
-		"/my\\_tasks \\- показать ваши последние заявки\n" +
-		"/stats \\- показать личную статистику за последние 30 дней\n" +
-		"/status \\- показать, к какому аккаунту привязан этот Telegram\n" +
-		"/unlink \\- отвязать этот Telegram от текущего аккаунта\n" +
-		"/help \\- открыть эту справку\n\n" +
-		"*Кнопки меню:*\n" +
-		"📋 *Мои заявки* \\- ваши последние активные заявки\n" +
-		"👨‍💼 *Назначены мне* \\- заявки, где вы указаны исполнителем\n" +
-		"🗂 *Участвовал* \\- заявки, где вы участвовали в истории, но не являетесь создателем или текущим исполнителем\n" +
-		"⏰ *На сегодня* \\- заявки, созданные сегодня\n" +
-		"🔴 *Просроченные* \\- заявки с просроченным сроком\n" +
-		"🔍 *Поиск* \\- найти заявку по номеру или по тексту\n" +
-		"📊 *Статистика* \\- ваша краткая сводка по заявкам\n" +
-		"🔐 *Статус* \\- проверить текущую привязку Telegram\n" +
-		"📖 *Справка* \\- снова открыть эту подсказку\n\n" +
-		"*Что можно делать в карточке заявки:*\n" +
-		"• открыть заявку из списка\n" +
-		"• изменить статус \\(если у вас есть права\\)\n" +
-		"• изменить срок\n" +
-		"• добавить комментарий\n" +
-		"• делегировать другому сотруднику\n" +
-		"• сохранить изменения\n\n" +
-		"*Важно:*\n" +
-		"• все действия зависят от ваших прав и текущего статуса заявки\n" +
-		"• критические действия требуют подтверждения\n" +
-		"• если потеряли навигацию, используйте /menu"
-
-	return c.renderScreen(ctx, chatID, 0, helpText, c.mainMenuScreenOptions()...)
+	helpText := c.t(ctx, "tg.help")
+
+	return c.renderScreen(ctx, chatID, 0, helpText, c.mainMenuScreenOptions(ctx)...)
 }
 
 func (c *TelegramController) handleTextMessage(ctx context.Context, chatID int64, text string) error {
@@ -140,20 +102,24 @@ func (c *TelegramController) sendTelegramLinkError(ctx context.Context, chatID i
 	return c.tgService.SendMessageEx(
 		ctx,
 		chatID,
-		"❌ *Ошибка привязки*\n\n"+telegram.EscapeTextForMarkdownV2(errMessage),
+		c.t(ctx, "tg.link.error", telegram.EscapeTextForMarkdownV2(errMessage)),
 		telegram.WithMarkdownV2(),
 	)
 }
 
 func (c *TelegramController) handleTokenLink(ctx context.Context, chatID int64, token string) error {
-	err := c.userService.ConfirmTelegramLink(ctx, token, chatID)
+	err := c.userService.ConfirmTelegramLink(ctx, token, chatID, false)
+	var rebindErr *services.TelegramRebindRequiredError
+	if errors.As(err, &rebindErr) {
+		return c.askRebindConfirmation(ctx, chatID, token, rebindErr.LinkedUserFio)
+	}
 	if err != nil {
 		c.logger.Warn("Неверный токен привязки", zap.Int64("chat_id", chatID), zap.Error(err))
 
-		errMessage := "Код неверный или устарел. Получите новый код на сайте."
+		errMessage := c.t(ctx, "tg.link.invalid_code")
 		var httpErr *apperrors.HttpError
 		if errors.As(err, &httpErr) && strings.TrimSpace(httpErr.Message) != "" {
-			errMessage = httpErr.Message
+			errMessage = httpErr.LocalizedMessage(languageFromCtx(ctx))
 		}
 		return c.sendTelegramLinkError(ctx, chatID, errMessage)
 	}
@@ -166,13 +132,13 @@ func (c *TelegramController) handleTokenLink(ctx context.Context, chatID int64,
 		chatID,
 		0,
 		"✅ *Аккаунт успешно привязан\\!*\n\nТеперь вы можете работать с заявками через меню ниже\\.",
-		c.mainMenuScreenOptions()...,
+		c.mainMenuScreenOptions(ctx)...,
 	)
 }
 
 func (c *TelegramController) handleStateInput(ctx context.Context, chatID int64, text string, state *dto.TelegramState) error {
 	switch state.Mode {
-	case "awaiting_comment":
+	case "awaiting_comment", "awaiting_resolution_comment":
 		return c.handleSetComment(ctx, chatID, text)
 	case "awaiting_duration":
 		return c.handleSetDuration(ctx, chatID, text)
@@ -186,28 +152,28 @@ func (c *TelegramController) handleStateInput(ctx context.Context, chatID int64,
 }
 
 func (c *TelegramController) handleMenuButton(ctx context.Context, chatID int64, text string) error {
-	switch text {
-	case menuAllOrdersButton:
+	switch menuButtonKey(text) {
+	case "tg.btn.all_orders":
 		return c.handleAllOrdersCommand(ctx, chatID)
-	case menuMyTasksButton:
+	case "tg.btn.my_tasks":
 		return c.handleMyTasksCommand(ctx, chatID)
-	case menuAssignedButton:
+	case "tg.btn.assigned":
 		return c.handleAssignedToMeCommand(ctx, chatID)
-	case menuInvolvedButton:
+	case "tg.btn.involved":
 		return c.handleInvolvedCommand(ctx, chatID)
-	case menuTodayButton:
+	case "tg.btn.today":
 		return c.handleTodayTasksCommand(ctx, chatID)
-	case menuOverdueButton:
+	case "tg.btn.overdue":
 		return c.handleOverdueTasksCommand(ctx, chatID)
-	case menuStatsButton:
+	case "tg.btn.stats":
 		return c.handleStatsCommand(ctx, chatID)
-	case menuSearchButton:
+	case "tg.btn.search":
 		return c.handleSearchStart(ctx, chatID, 0)
-	case menuStatusButton:
+	case "tg.btn.status":
 		return c.handleLinkStatusCommand(ctx, chatID)
-	case menuHelpButton:
+	case "tg.btn.help":
 		return c.handleHelpCommand(ctx, chatID)
-	case menuMainButton:
+	case "tg.btn.main_menu":
 		return c.sendMainMenu(ctx, chatID)
 	default:
 		return nil
@@ -233,7 +199,7 @@ func (c *TelegramController) handleAllOrdersPage(ctx context.Context, chatID int
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка загрузки заявок\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.list.load_error"))
 	}
 
 	return c.renderOrderList(
@@ -242,8 +208,8 @@ func (c *TelegramController) handleAllOrdersPage(ctx context.Context, chatID int
 		resp.List,
 		resp.TotalCount,
 		page,
-		"📚 *Все заявки*",
-		"✅ Нет заявок, доступных для просмотра\\.",
+		c.t(ctx, "tg.list.all.title"),
+		c.t(ctx, "tg.list.all.empty"),
 		"all",
 		"",
 		messageID...,
@@ -270,7 +236,7 @@ func (c *TelegramController) handleMyTasksPage(ctx context.Context, chatID int64
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка загрузки заявок\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.list.load_error"))
 	}
 
 	return c.renderOrderList(
@@ -279,8 +245,8 @@ func (c *TelegramController) handleMyTasksPage(ctx context.Context, chatID int64
 		resp.List,
 		resp.TotalCount,
 		page,
-		"📋 *Мои заявки*",
-		"✅ У вас нет активных заявок\\.",
+		c.t(ctx, "tg.list.my_tasks.title"),
+		c.t(ctx, "tg.list.my_tasks.empty"),
 		"my_tasks",
 		"",
 		messageID...,
@@ -307,7 +273,7 @@ func (c *TelegramController) handleAssignedToMePage(ctx context.Context, chatID
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка загрузки заявок\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.list.load_error"))
 	}
 
 	return c.renderOrderList(
@@ -316,8 +282,8 @@ func (c *TelegramController) handleAssignedToMePage(ctx context.Context, chatID
 		resp.List,
 		resp.TotalCount,
 		page,
-		"👨‍💼 *Назначены мне*",
-		"✅ На вас сейчас нет назначенных заявок\\.",
+		c.t(ctx, "tg.list.assigned.title"),
+		c.t(ctx, "tg.list.assigned.empty"),
 		"assigned",
 		"",
 		messageID...,
@@ -343,7 +309,7 @@ func (c *TelegramController) handleInvolvedPage(ctx context.Context, chatID int6
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка загрузки заявок\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.list.load_error"))
 	}
 
 	return c.renderOrderList(
@@ -352,8 +318,8 @@ func (c *TelegramController) handleInvolvedPage(ctx context.Context, chatID int6
 		resp.List,
 		resp.TotalCount,
 		page,
-		"🗂 *Участвовал*",
-		"✅ У вас нет заявок, где вы участвовали отдельно от роли создателя или исполнителя\\.",
+		c.t(ctx, "tg.list.involved.title"),
+		c.t(ctx, "tg.list.involved.empty"),
 		"involved",
 		"",
 		messageID...,
@@ -371,8 +337,9 @@ func (c *TelegramController) handleTodayTasksPage(ctx context.Context, chatID in
 	}
 
 	page = normalizeTelegramListPage(page)
-	now := time.Now().In(c.loc)
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.loc)
+	loc := utils.LocationFromCtx(ctx)
+	now := time.Now().In(loc)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	filter := c.newTelegramOrderFilter("today", page)
@@ -386,7 +353,7 @@ func (c *TelegramController) handleTodayTasksPage(ctx context.Context, chatID in
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка загрузки заявок\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.list.load_error"))
 	}
 
 	return c.renderOrderList(
@@ -395,8 +362,8 @@ func (c *TelegramController) handleTodayTasksPage(ctx context.Context, chatID in
 		resp.List,
 		resp.TotalCount,
 		page,
-		"⏰ *На сегодня*",
-		"✅ Сегодня новых заявок нет\\.",
+		c.t(ctx, "tg.list.today.title"),
+		c.t(ctx, "tg.list.today.empty"),
 		"today",
 		"",
 		messageID...,
@@ -423,7 +390,7 @@ func (c *TelegramController) handleOverdueTasksPage(ctx context.Context, chatID
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка загрузки заявок\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.list.load_error"))
 	}
 
 	return c.renderOrderList(
@@ -432,8 +399,8 @@ func (c *TelegramController) handleOverdueTasksPage(ctx context.Context, chatID
 		resp.List,
 		resp.TotalCount,
 		page,
-		"🔴 *Просроченные заявки*",
-		"✅ Просроченных заявок нет\\.",
+		c.t(ctx, "tg.list.overdue.title"),
+		c.t(ctx, "tg.list.overdue.empty"),
 		"overdue",
 		"",
 		messageID...,
@@ -452,81 +419,115 @@ func (c *TelegramController) handleStatsCommand(ctx context.Context, chatID int6
 		if len(messageID) > 0 {
 			mid = messageID[0]
 		}
-		return c.renderHomeScreen(ctx, chatID, mid, "❌ Ошибка получения статистики\\.")
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.stats.error"))
 	}
 
-	avgHours := int(stats.AvgResolutionSeconds / 3600)
-	avgMinutes := int((stats.AvgResolutionSeconds - float64(avgHours*3600)) / 60)
+	mid := 0
+	if len(messageID) > 0 {
+		mid = messageID[0]
+	}
+	return c.renderHomeScreen(ctx, chatID, mid, c.formatOrderStats(ctx, c.t(ctx, "tg.stats.title"), stats))
+}
 
-	var text strings.Builder
-	text.WriteString("📊 *Ваша статистика за 30 дней*\n\n")
-	text.WriteString(fmt.Sprintf("📌 *Всего заявок:* %d\n", stats.TotalCount))
-	text.WriteString(fmt.Sprintf("⚙️ *В работе:* %d\n", stats.InProgressCount))
-	text.WriteString(fmt.Sprintf("✅ *Выполнено:* %d\n", stats.CompletedCount))
-	text.WriteString(fmt.Sprintf("🔴 *Просрочено:* %d\n", stats.OverdueCount))
-	text.WriteString(fmt.Sprintf("📁 *Закрыто:* %d\n", stats.ClosedCount))
-	if avgHours > 0 || avgMinutes > 0 {
-		text.WriteString(fmt.Sprintf("\n⏱ *Среднее время решения:* %d ч %d мин\n", avgHours, avgMinutes))
+// handleUnitReportCommand — сводка по отделу руководителя за те же 30 дней, что и личная статистика.
+func (c *TelegramController) handleUnitReportCommand(ctx context.Context, chatID int64, messageID ...int) error {
+	user, userCtx, err := c.prepareUserContext(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	mid := 0
+	if len(messageID) > 0 {
+		mid = messageID[0]
+	}
+	stats, err := c.orderService.GetUnitStats(userCtx)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrForbidden) {
+			return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.unit_report.forbidden"))
+		}
+		c.logger.Error("GetUnitStats failed", zap.Error(err), zap.Int64("chat_id", chatID))
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.stats.error"))
+	}
+
+	title := c.t(ctx, "tg.unit_report.title")
+	switch {
+	case user.OtdelName != nil && *user.OtdelName != "":
+		title = c.t(ctx, "tg.unit_report.title_otdel", telegram.EscapeTextForMarkdownV2(*user.OtdelName))
+	case user.OtdelID == nil && user.DepartmentName != nil && *user.DepartmentName != "":
+		title = c.t(ctx, "tg.unit_report.title_department", telegram.EscapeTextForMarkdownV2(*user.DepartmentName))
+	}
+	return c.renderHomeScreen(ctx, chatID, mid, c.formatOrderStats(ctx, title, stats))
+}
+
+// handleNewOrderCommand ведёт на форму создания заявки на сайте: в боте своей формы нет.
+func (c *TelegramController) handleNewOrderCommand(ctx context.Context, chatID int64, messageID ...int) error {
+	_, userCtx, err := c.prepareUserContext(ctx, chatID)
+	if err != nil {
+		return err
 	}
 
 	mid := 0
 	if len(messageID) > 0 {
 		mid = messageID[0]
 	}
-	return c.renderHomeScreen(ctx, chatID, mid, text.String())
-}
-
-func (c *TelegramController) mainMenuKeyboard() [][]telegram.InlineKeyboardButton {
-	return [][]telegram.InlineKeyboardButton{
-		{
-			{Text: menuAllOrdersButton, CallbackData: `{"action":"main_all"}`},
-			{Text: menuMyTasksButton, CallbackData: `{"action":"main_my_tasks"}`},
-		},
-		{
-			{Text: menuAssignedButton, CallbackData: `{"action":"main_assigned"}`},
-			{Text: menuInvolvedButton, CallbackData: `{"action":"main_involved"}`},
-		},
-		{
-			{Text: menuTodayButton, CallbackData: `{"action":"main_today"}`},
-			{Text: menuOverdueButton, CallbackData: `{"action":"main_overdue"}`},
-		},
-		{
-			{Text: menuSearchButton, CallbackData: `{"action":"main_search"}`},
-			{Text: menuStatsButton, CallbackData: `{"action":"main_stats"}`},
-		},
-		{
-			{Text: menuStatusButton, CallbackData: `{"action":"main_status"}`},
-			{Text: menuHelpButton, CallbackData: `{"action":"main_help"}`},
-		},
-	}
-}
-
-func (c *TelegramController) mainMenuScreenOptions() []telegram.MessageOption {
+	if !currentMenuAccess(userCtx).permissions[authz.OrdersCreate] {
+		return c.renderHomeScreen(ctx, chatID, mid, c.t(ctx, "tg.new_order.forbidden"))
+	}
+
+	formURL := c.frontendBaseURL + "/orders/new"
+	text := c.t(ctx, "tg.new_order.text")
+	keyboard := c.mainMenuKeyboard(userCtx)
+	if strings.HasPrefix(formURL, "https://") {
+		keyboard = append([][]telegram.InlineKeyboardButton{{{Text: c.t(ctx, "tg.btn.create_order"), URL: formURL}}}, keyboard...)
+	} else {
+		// Telegram не принимает http- и localhost-ссылки в кнопках — показываем ссылку текстом.
+		text += "\n\n" + telegram.EscapeTextForMarkdownV2(formURL)
+	}
+	return c.renderScreen(ctx, chatID, mid, text, telegram.WithKeyboard(keyboard), telegram.WithMarkdownV2())
+}
+
+func (c *TelegramController) formatOrderStats(ctx context.Context, title string, stats *types.UserOrderStats) string {
+	avgHours := int(stats.AvgResolutionSeconds / 3600)
+	avgMinutes := int((stats.AvgResolutionSeconds - float64(avgHours*3600)) / 60)
+
+	var text strings.Builder
+	text.WriteString(title + "\n\n")
+	text.WriteString(c.t(ctx, "tg.stats.total", stats.TotalCount) + "\n")
+	text.WriteString(c.t(ctx, "tg.stats.in_progress", stats.InProgressCount) + "\n")
+	text.WriteString(c.t(ctx, "tg.stats.completed", stats.CompletedCount) + "\n")
+	text.WriteString(c.t(ctx, "tg.stats.overdue", stats.OverdueCount) + "\n")
+	text.WriteString(c.t(ctx, "tg.stats.closed", stats.ClosedCount) + "\n")
+	if avgHours > 0 || avgMinutes > 0 {
+		text.WriteString("\n" + c.t(ctx, "tg.stats.avg_resolution", c.t(ctx, "notify.duration", avgHours, avgMinutes)) + "\n")
+	}
+	return text.String()
+}
+
+func (c *TelegramController) mainMenuKeyboard(ctx context.Context) [][]telegram.InlineKeyboardButton {
+	return buildMainMenu(currentMenuAccess(ctx), func(key string) string { return c.t(ctx, key) })
+}
+
+func (c *TelegramController) mainMenuScreenOptions(ctx context.Context) []telegram.MessageOption {
 	return []telegram.MessageOption{
-		telegram.WithKeyboard(c.mainMenuKeyboard()),
+		telegram.WithKeyboard(c.mainMenuKeyboard(ctx)),
 		telegram.WithMarkdownV2(),
 	}
 }
 
 func (c *TelegramController) renderHomeScreen(ctx context.Context, chatID int64, messageID int, text string) error {
-	return c.renderScreen(ctx, chatID, messageID, text, c.mainMenuScreenOptions()...)
+	return c.renderScreen(ctx, chatID, messageID, text, c.mainMenuScreenOptions(ctx)...)
 }
 
 func (c *TelegramController) sendMainMenu(ctx context.Context, chatID int64) error {
-	if !c.cfg.AdvancedMode {
-		return c.tgService.SendMessageEx(ctx, chatID, "✅ Подключение к боту активно\\.", telegram.WithMarkdownV2())
+	if !c.cfg.AdvancedModeActive() {
+		return c.tgService.SendMessageEx(ctx, chatID, c.t(ctx, "tg.connection_active"), telegram.WithMarkdownV2())
 	}
 	if _, _, err := c.prepareUserContext(ctx, chatID); err != nil {
 		return c.handlePrepareUserContextError(ctx, chatID, err)
 	}
-	text := "🏠 *Главное меню*\n\n" +
-		"Система заявок банка\\.\n" +
-		"Выберите действие из меню ниже\\.\n\n" +
-		"*Команды:*\n" +
-		"/status \\- показать, к какому аккаунту привязан этот Telegram\n" +
-		"/unlink \\- отвязать этот Telegram от текущего аккаунта"
+	text := c.t(ctx, "tg.menu.title") + "\n\n" + c.t(ctx, "tg.commands_footer")
 
-	return c.renderScreen(ctx, chatID, 0, text, c.mainMenuScreenOptions()...)
+	return c.renderScreen(ctx, chatID, 0, text, c.mainMenuScreenOptions(ctx)...)
 }
 
 func (c *TelegramController) renderOrderList(
@@ -560,21 +561,17 @@ func (c *TelegramController) renderOrderList(
 	} else {
 		text.WriteString(title)
 		if totalPages > 1 {
-			text.WriteString(fmt.Sprintf("\n\n_Страница %d из %d • всего %d_", page, totalPages, totalCount))
+			text.WriteString("\n\n" + c.t(ctx, "tg.list.page", page, totalPages, totalCount))
 		} else {
 			text.WriteString(fmt.Sprintf(" \\(%d\\)", totalCount))
 		}
 		text.WriteString("\n\n")
-		text.WriteString("_Нажмите на заявку:_")
+		text.WriteString(c.t(ctx, "tg.list.tap_order"))
 
 		statusMap := c.getStatusMap(ctx)
 		for _, order := range orders {
 			emoji := getStatusEmoji(statusMap[order.StatusID])
-			buttonText := order.Name
-			if len(buttonText) > 30 {
-				buttonText = buttonText[:27] + "..."
-			}
-			buttonText = fmt.Sprintf("%s №%d • %s", emoji, order.ID, buttonText)
+			buttonText := fmt.Sprintf("%s %s • %s", emoji, order.Number, sanitize.Truncate(order.Name, 30))
 			cb := fmt.Sprintf(`{"action":"select_order","order_id":%d}`, order.ID)
 			keyboard = append(keyboard, []telegram.InlineKeyboardButton{{Text: buttonText, CallbackData: cb}})
 		}
@@ -584,7 +581,7 @@ func (c *TelegramController) renderOrderList(
 		navRow := make([]telegram.InlineKeyboardButton, 0, 3)
 		if page > 1 {
 			navRow = append(navRow, telegram.InlineKeyboardButton{
-				Text:         "⬅️ Назад",
+				Text:         c.t(ctx, "tg.list.prev"),
 				CallbackData: fmt.Sprintf(`{"action":"list_page","page":%d}`, page-1),
 			})
 		}
@@ -594,14 +591,14 @@ func (c *TelegramController) renderOrderList(
 		})
 		if page < totalPages {
 			navRow = append(navRow, telegram.InlineKeyboardButton{
-				Text:         "Вперёд ➡️",
+				Text:         c.t(ctx, "tg.list.next"),
 				CallbackData: fmt.Sprintf(`{"action":"list_page","page":%d}`, page+1),
 			})
 		}
 		keyboard = append(keyboard, navRow)
 	}
 
-	keyboard = append(keyboard, []telegram.InlineKeyboardButton{{Text: menuMainButton, CallbackData: `{"action":"main_menu"}`}})
+	keyboard = append(keyboard, []telegram.InlineKeyboardButton{{Text: c.t(ctx, "tg.btn.main_menu"), CallbackData: `{"action":"main_menu"}`}})
 
 	mid := 0
 	if len(messageID) > 0 {
@@ -631,7 +628,7 @@ func (c *TelegramController) handleListPageAction(ctx context.Context, chatID in
 		return c.sendStaleStateError(ctx, chatID, messageID)
 	}
 	if state.MessageID > 0 && state.MessageID != messageID {
-		_ = c.answerCallback(ctx, "Меню уже обновлено")
+		_ = c.answerCallback(ctx, c.t(ctx, "tg.menu.already_updated"))
 		return nil
 	}
 	return c.showListPage(ctx, chatID, state.Source, state.SearchQuery, page, messageID)
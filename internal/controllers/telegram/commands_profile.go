@@ -2,18 +2,24 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"go.uber.org/zap"
+
+	apperrors "request-system/pkg/errors"
 	tgapi "request-system/pkg/telegram"
 )
 
-func (c *TelegramController) statusScreenOptions() []tgapi.MessageOption {
-	keyboard := make([][]tgapi.InlineKeyboardButton, 0, len(c.mainMenuKeyboard())+1)
+func (c *TelegramController) statusScreenOptions(ctx context.Context) []tgapi.MessageOption {
+	mainMenu := c.mainMenuKeyboard(ctx)
+	keyboard := make([][]tgapi.InlineKeyboardButton, 0, len(mainMenu)+1)
 	keyboard = append(keyboard, []tgapi.InlineKeyboardButton{{
 		Text:         unlinkButton,
 		CallbackData: `{"action":"unlink_prompt"}`,
 	}})
-	keyboard = append(keyboard, c.mainMenuKeyboard()...)
+	keyboard = append(keyboard, mainMenu...)
 
 	return []tgapi.MessageOption{
 		tgapi.WithKeyboard(keyboard),
@@ -61,7 +67,7 @@ func (c *TelegramController) handleLinkStatusCommand(ctx context.Context, chatID
 		chatID,
 	)
 
-	return c.renderScreen(ctx, chatID, 0, text, c.statusScreenOptions()...)
+	return c.renderScreen(ctx, chatID, 0, text, c.statusScreenOptions(ctx)...)
 }
 
 func (c *TelegramController) handleUnlinkCommand(ctx context.Context, chatID int64) error {
@@ -110,3 +116,52 @@ func (c *TelegramController) handleConfirmUnlinkAction(ctx context.Context, chat
 
 	return c.renderScreen(ctx, chatID, 0, text, tgapi.WithMarkdownV2())
 }
+
+// askRebindConfirmation спрашивает, заменить ли аккаунт, к которому уже привязан чат. Код до
+// подтверждения не расходуется и ждёт ответа в кеше не дольше rebindExpiration.
+func (c *TelegramController) askRebindConfirmation(ctx context.Context, chatID int64, token, linkedUserFio string) error {
+	if err := c.cacheRepo.Set(ctx, fmt.Sprintf(telegramRebindKey, chatID), token, rebindExpiration); err != nil {
+		return c.sendInternalError(ctx, chatID)
+	}
+
+	text := fmt.Sprintf(
+		"⚠️ *Этот чат уже привязан*\n\n"+
+			"Сейчас Telegram связан с аккаунтом *%s*\\. Заменить его аккаунтом, для которого выдан код?\n\n"+
+			"_Уведомления прежнего аккаунта перестанут приходить в этот чат\\._",
+		tgapi.EscapeTextForMarkdownV2(linkedUserFio),
+	)
+	return c.renderScreen(ctx, chatID, 0, text,
+		tgapi.WithKeyboard([][]tgapi.InlineKeyboardButton{{
+			{Text: confirmRebindButton, CallbackData: `{"action":"rebind_confirm"}`},
+			{Text: cancelButton, CallbackData: `{"action":"rebind_cancel"}`},
+		}}),
+		tgapi.WithMarkdownV2(),
+	)
+}
+
+func (c *TelegramController) handleConfirmRebindAction(ctx context.Context, chatID int64) error {
+	token, err := c.cacheRepo.GetDel(ctx, fmt.Sprintf(telegramRebindKey, chatID))
+	if err != nil || token == "" {
+		return c.sendTelegramLinkError(ctx, chatID, "Запрос на замену устарел. Отправьте код привязки ещё раз.")
+	}
+
+	if err := c.userService.ConfirmTelegramLink(ctx, token, chatID, true); err != nil {
+		c.logger.Warn("Не удалось заменить привязку Telegram", zap.Int64("chat_id", chatID), zap.Error(err))
+		errMessage := "Код неверный или устарел. Получите новый код на сайте."
+		var httpErr *apperrors.HttpError
+		if errors.As(err, &httpErr) && strings.TrimSpace(httpErr.Message) != "" {
+			errMessage = httpErr.Message
+		}
+		return c.sendTelegramLinkError(ctx, chatID, errMessage)
+	}
+
+	_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramStateKey, chatID))
+	_ = c.answerCallback(ctx, "Аккаунт заменён")
+	return c.handleLinkStatusCommand(ctx, chatID)
+}
+
+func (c *TelegramController) handleCancelRebindAction(ctx context.Context, chatID int64) error {
+	_ = c.cacheRepo.Del(ctx, fmt.Sprintf(telegramRebindKey, chatID))
+	_ = c.answerCallback(ctx, "Привязка не изменена")
+	return c.handleLinkStatusCommand(ctx, chatID)
+}
@@ -23,12 +23,17 @@ import (
 	"request-system/pkg/config"
 	"request-system/pkg/contextkeys"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
+	"request-system/pkg/ratelimit"
 	"request-system/pkg/telegram"
 	"request-system/pkg/utils"
 )
 
 const (
-	telegramStateKey     = "tg_user_state:%d"
+	telegramStateKey = "tg_user_state:%d"
+	// telegramRebindKey — код привязки, ждущий подтверждения замены уже привязанного аккаунта
+	telegramRebindKey    = "tg_rebind_pending:%d"
+	rebindExpiration     = 5 * time.Minute
 	maxMessageAgeSeconds = 600
 	commandCooldown      = 1000 * time.Millisecond // 1 секунда между командами
 	callbackCooldown     = 500 * time.Millisecond  // 0.5 секунды между кликами
@@ -47,6 +52,7 @@ type telegramContextKey string
 
 const callbackQueryIDContextKey telegramContextKey = "telegram_callback_query_id"
 const callbackAnswerStateContextKey telegramContextKey = "telegram_callback_answer_state"
+const languageContextKey telegramContextKey = "telegram_language"
 
 var errTelegramAccountNotLinked = errors.New("telegram account not linked")
 
@@ -58,56 +64,64 @@ type callbackAnswerState struct {
 type TelegramController struct {
 	userService           services.UserServiceInterface
 	orderService          services.OrderServiceInterface
+	equipmentService      services.EquipmentServiceInterface
 	integrationService    services.TelegramIntegrationServiceInterface
-	statusRepo            repositories.StatusRepositoryInterface
+	statuses              services.StatusDirectoryInterface
 	userRepo              repositories.UserRepositoryInterface
 	orderHistoryRepo      repositories.OrderHistoryRepositoryInterface
 	tgService             telegram.ServiceInterface
 	cacheRepo             repositories.CacheRepositoryInterface
 	authPermissionService services.AuthPermissionServiceInterface
-	deduplicator          *RequestDeduplicator
+	limiter               *ratelimit.Limiter
 	logger                *zap.Logger
 	orderTypeRepo         repositories.OrderTypeRepositoryInterface
+	resolutionCodes       repositories.ResolutionCodeRepositoryInterface
 	cfg                   config.TelegramConfig
-	loc                   *time.Location
-
-	statusCache      map[uint64]*entities.Status
-	statusCacheMutex sync.RWMutex
-	statusCacheTime  time.Time
+	frontendBaseURL       string
 
 	sem chan struct{}
+
+	// inflight — начатые обработки обновлений и фоновые вызовы Telegram API; их ждёт Drain при остановке
+	inflight     sync.WaitGroup
+	inflightMu   sync.Mutex
+	drainStarted bool
 }
 
 func NewTelegramController(
 	userService services.UserServiceInterface,
 	orderService services.OrderServiceInterface,
+	equipmentService services.EquipmentServiceInterface,
 	integrationService services.TelegramIntegrationServiceInterface,
 	tgService telegram.ServiceInterface,
 	cacheRepo repositories.CacheRepositoryInterface,
-	statusRepo repositories.StatusRepositoryInterface,
+	statuses services.StatusDirectoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	orderHistoryRepo repositories.OrderHistoryRepositoryInterface,
 	authPermissionService services.AuthPermissionServiceInterface,
 	logger *zap.Logger,
 	orderTypeRepo repositories.OrderTypeRepositoryInterface,
+	resolutionCodes repositories.ResolutionCodeRepositoryInterface,
 	cfg config.TelegramConfig,
+	frontendCfg config.FrontendConfig,
+	limiter *ratelimit.Limiter,
 ) *TelegramController {
 	return &TelegramController{
 		userService:           userService,
 		orderService:          orderService,
+		equipmentService:      equipmentService,
 		integrationService:    integrationService,
 		tgService:             tgService,
 		cacheRepo:             cacheRepo,
-		statusRepo:            statusRepo,
+		statuses:              statuses,
 		userRepo:              userRepo,
 		orderHistoryRepo:      orderHistoryRepo,
 		authPermissionService: authPermissionService,
-		deduplicator:          NewRequestDeduplicator(),
+		limiter:               limiter,
 		logger:                logger,
 		orderTypeRepo:         orderTypeRepo,
+		resolutionCodes:       resolutionCodes,
 		cfg:                   cfg,
-		loc:                   time.Local,
-		statusCache:           make(map[uint64]*entities.Status),
+		frontendBaseURL:       strings.TrimRight(frontendCfg.BaseURL, "/"),
 		sem:                   make(chan struct{}, maxConcurrentRequests),
 	}
 }
@@ -141,14 +155,20 @@ func (c *TelegramController) HandleTelegramWebhook(ctx echo.Context) error {
 		return ctx.NoContent(http.StatusOK)
 	}
 
-	if !c.isMessageRecent(&update) {
+	c.dispatchUpdate(&update)
+	return ctx.NoContent(http.StatusOK)
+}
+
+// dispatchUpdate — общий путь обработки обновления для webhook и long-polling.
+func (c *TelegramController) dispatchUpdate(update *TelegramUpdate) {
+	if !c.isMessageRecent(update) {
 		if update.Message != nil {
 			c.logger.Info("Telegram stale message dropped",
 				zap.Int("message_id", update.Message.MessageID),
 				zap.Int64("chat_id", update.Message.Chat.ID),
 				zap.Int64("message_date", update.Message.Date))
 		}
-		return ctx.NoContent(http.StatusOK)
+		return
 	}
 
 	if update.CallbackQuery != nil {
@@ -156,23 +176,23 @@ func (c *TelegramController) HandleTelegramWebhook(ctx echo.Context) error {
 			zap.String("callback_id", update.CallbackQuery.ID),
 			zap.Bool("has_message", update.CallbackQuery.Message != nil))
 
-		if !c.cfg.AdvancedMode {
-			return ctx.NoContent(http.StatusOK)
+		if !c.cfg.AdvancedModeActive() {
+			return
 		}
 
 		if update.CallbackQuery.Message == nil {
-			go c.tgService.AnswerCallbackQuery(context.Background(), update.CallbackQuery.ID, "")
-			return ctx.NoContent(http.StatusOK)
+			c.goTracked(func() { c.tgService.AnswerCallbackQuery(context.Background(), update.CallbackQuery.ID, "") })
+			return
 		}
 
 		chatID := update.CallbackQuery.Message.Chat.ID
-		if !c.deduplicator.TryAcquire(chatID, "cb", callbackCooldown) {
-			go c.tgService.AnswerCallbackQuery(context.Background(), update.CallbackQuery.ID, "")
-			return ctx.NoContent(http.StatusOK)
+		if !c.tryAcquire(chatID, "cb", callbackCooldown) {
+			c.goTracked(func() { c.tgService.AnswerCallbackQuery(context.Background(), update.CallbackQuery.ID, "") })
+			return
 		}
 
-		go c.handleCallbackQueryAsync(update.CallbackQuery)
-		return ctx.NoContent(http.StatusOK)
+		c.goTracked(func() { c.handleCallbackQueryAsync(update.CallbackQuery) })
+		return
 	}
 
 	if update.Message != nil {
@@ -180,15 +200,40 @@ func (c *TelegramController) HandleTelegramWebhook(ctx echo.Context) error {
 			zap.Int("message_id", update.Message.MessageID),
 			zap.Int64("chat_id", update.Message.Chat.ID),
 			zap.Bool("is_command", strings.HasPrefix(strings.TrimSpace(update.Message.Text), "/")))
-		go c.handleMessageAsync(update.Message)
-		return ctx.NoContent(http.StatusOK)
+		c.goTracked(func() { c.handleMessageAsync(update.Message) })
+		return
 	}
 
 	c.logger.Info("Telegram update ignored: unsupported type",
 		zap.Int("update_id", update.UpdateID),
 		zap.Bool("has_message", update.Message != nil),
 		zap.Bool("has_callback_query", update.CallbackQuery != nil))
-	return ctx.NoContent(http.StatusOK)
+}
+
+// goTracked запускает fn в горутине, которую дождётся Drain. После начала остановки
+// fn выполняется синхронно, чтобы не добавлять в WaitGroup во время ожидания.
+func (c *TelegramController) goTracked(fn func()) {
+	c.inflightMu.Lock()
+	if c.drainStarted {
+		c.inflightMu.Unlock()
+		fn()
+		return
+	}
+	c.inflight.Add(1)
+	c.inflightMu.Unlock()
+
+	go func() {
+		defer c.inflight.Done()
+		fn()
+	}()
+}
+
+// Drain ждёт завершения начатых обработок обновлений, но не дольше ctx.
+func (c *TelegramController) Drain(ctx context.Context) error {
+	c.inflightMu.Lock()
+	c.drainStarted = true
+	c.inflightMu.Unlock()
+	return utils.WaitWithContext(ctx, &c.inflight)
 }
 
 // ==================== Обработка callback ====================
@@ -200,6 +245,9 @@ func (c *TelegramController) handleCallbackQueryAsync(query *TelegramCallbackQue
 	bgCtx := withCallbackQueryState(context.Background(), query.ID)
 	bgCtx, cancel := context.WithTimeout(bgCtx, goroutineTimeout)
 	defer cancel()
+	bgCtx = c.withUserLocale(bgCtx, query.Message.Chat.ID)
+	bgCtx = c.withMenuAccess(bgCtx, query.Message.Chat.ID)
+	c.touchLastSeen(bgCtx, query.Message.Chat.ID)
 
 	go c.ensureCallbackAnswered(bgCtx, 1200*time.Millisecond)
 	defer func() {
@@ -228,28 +276,48 @@ func (c *TelegramController) handleMessageAsync(msg *TelegramMessage) {
 	isMenu := isTelegramMenuButton(text)
 
 	if isCommand {
-		if !c.deduplicator.TryAcquire(chatID, "cmd", commandCooldown) {
-			go c.tgService.DeleteMessage(context.Background(), chatID, msgID)
+		if !c.tryAcquire(chatID, "cmd", commandCooldown) {
+			c.goTracked(func() { c.tgService.DeleteMessage(context.Background(), chatID, msgID) })
 			return
 		}
-	} else if c.cfg.AdvancedMode && isMenu {
-		if !c.deduplicator.TryAcquire(chatID, "menu", menuCooldown) {
-			go c.tgService.DeleteMessage(context.Background(), chatID, msgID)
+	} else if c.cfg.AdvancedModeActive() && isMenu {
+		if !c.tryAcquire(chatID, "menu", menuCooldown) {
+			c.goTracked(func() { c.tgService.DeleteMessage(context.Background(), chatID, msgID) })
 			return
 		}
 	}
 
 	// Удаляем сообщение пользователя в отдельной горутине.
-	go func() {
+	c.goTracked(func() {
 		time.Sleep(500 * time.Millisecond)
 		_ = c.tgService.DeleteMessage(context.Background(), chatID, msgID)
-	}()
+	})
 
 	c.sem <- struct{}{}
 	defer func() { <-c.sem }()
 
 	bgCtx, cancel := context.WithTimeout(context.Background(), goroutineTimeout)
 	defer cancel()
+	bgCtx = c.withUserLocale(bgCtx, chatID)
+	bgCtx = c.withMenuAccess(bgCtx, chatID)
+	c.touchLastSeen(bgCtx, chatID)
+
+	if c.cfg.AdvancedModeActive() && hasTelegramAttachment(msg) {
+		if err := c.handleAttachmentMessage(bgCtx, chatID, msg); err != nil {
+			if isTelegramAccountNotLinkedError(err) {
+				if renderErr := c.renderNotLinkedScreen(bgCtx, chatID); renderErr != nil {
+					c.logger.Error("Telegram not-linked screen render failed",
+						zap.Int64("chat_id", chatID),
+						zap.Error(renderErr))
+				}
+				return
+			}
+			c.logger.Error("Telegram attachment failed",
+				zap.Int64("chat_id", chatID),
+				zap.Error(err))
+		}
+		return
+	}
 
 	if isCommand {
 		if err := c.handleCommand(bgCtx, chatID, text); err != nil {
@@ -281,7 +349,7 @@ func (c *TelegramController) handleMessageAsync(msg *TelegramMessage) {
 		}
 	}
 
-	if c.cfg.AdvancedMode {
+	if c.cfg.AdvancedModeActive() {
 		if err := c.handleTextMessage(bgCtx, chatID, text); err != nil {
 			if isTelegramAccountNotLinkedError(err) {
 				if renderErr := c.renderNotLinkedScreen(bgCtx, chatID); renderErr != nil {
@@ -444,12 +512,15 @@ func (c *TelegramController) prepareUserContext(ctx context.Context, chatID int6
 		return nil, nil, err
 	}
 	userCtx := context.WithValue(ctx, contextkeys.UserIDKey, user.ID)
+	userCtx = utils.WithTenantID(userCtx, user.TenantID)
 	perms, _ := c.authPermissionService.GetAllUserPermissions(userCtx, user.ID)
 	permMap := make(map[string]bool)
 	for _, p := range perms {
 		permMap[p] = true
 	}
 	userCtx = context.WithValue(userCtx, contextkeys.UserPermissionsMapKey, permMap)
+	conditions, _ := c.authPermissionService.GetUserPermissionConditions(userCtx, user.ID)
+	userCtx = utils.WithPermissionConditions(userCtx, conditions)
 	userCtx = context.WithValue(userCtx, contextkeys.UserEntityKey, user)
 	return user, userCtx, nil
 }
@@ -464,7 +535,7 @@ func (c *TelegramController) renderNotLinkedScreen(ctx context.Context, chatID i
 		ctx,
 		chatID,
 		0,
-		"❌ *Аккаунт не привязан*\n\nИспользуйте /start для получения инструкций\\.",
+		c.t(ctx, "tg.not_linked"),
 		telegram.WithMarkdownV2(),
 	)
 }
@@ -477,29 +548,18 @@ func (c *TelegramController) handlePrepareUserContextError(ctx context.Context,
 }
 
 func (c *TelegramController) getStatusMap(ctx context.Context) map[uint64]*entities.Status {
-	c.statusCacheMutex.RLock()
-	// Оптимизация: кэш статусов обновляем раз в 10 минут.
-	if time.Since(c.statusCacheTime) < 10*time.Minute && len(c.statusCache) > 0 {
-		defer c.statusCacheMutex.RUnlock()
-		return c.statusCache
-	}
-	c.statusCacheMutex.RUnlock()
-	c.statusCacheMutex.Lock()
-	defer c.statusCacheMutex.Unlock()
 	statusMap := make(map[uint64]*entities.Status)
-	statuses, err := c.statusRepo.FindAll(ctx)
+	statuses, err := c.statuses.List(ctx)
 	if err == nil {
 		for i := range statuses {
 			statusMap[statuses[i].ID] = &statuses[i]
 		}
-		c.statusCache = statusMap
-		c.statusCacheTime = time.Now()
 	}
 	return statusMap
 }
 
 func (c *TelegramController) getAllowedStatuses(ctx context.Context, currentStatus *entities.Status, currentStatusID uint64) []entities.Status {
-	allStatuses, err := c.statusRepo.FindAll(ctx)
+	allStatuses, err := c.statuses.List(ctx)
 	if err != nil {
 		return nil
 	}
@@ -551,6 +611,7 @@ func (c *TelegramController) HandleGenerateLinkToken(ctx echo.Context) error {
 	response := map[string]interface{}{
 		"short_code":         linkData.ShortCode,
 		"expires_in_seconds": linkData.ExpiresInSeconds,
+		"expires_at":         linkData.ExpiresAt,
 	}
 	if botLink := c.integrationService.BuildBotStartLink(linkData.Token); botLink != "" {
 		response["bot_link"] = botLink
@@ -565,12 +626,21 @@ func (c *TelegramController) RegisterWebhook(baseURL string) error {
 	return err
 }
 
-func (c *TelegramController) StartCleanup(ctx context.Context) {
-	if c.deduplicator != nil {
-		c.logger.Info("Запуск фоновой очистки дедупликатора")
-		c.deduplicator.Cleanup(ctx, 2*time.Minute)
-		c.logger.Info("Фоновая очистка остановлена")
+// tryAcquire пропускает не больше одного действия вида kind из чата за cooldown.
+// Счётчик живёт в Redis, поэтому повторные клики не проходят и через другую реплику.
+func (c *TelegramController) tryAcquire(chatID int64, kind string, cooldown time.Duration) bool {
+	if c.limiter == nil {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	result, err := c.limiter.Allow(ctx, fmt.Sprintf("tg:%d:%s", chatID, kind), ratelimit.Rule{Limit: 1, Period: cooldown})
+	if err != nil {
+		c.logger.Warn("Лимитер Telegram недоступен, действие пропущено без проверки", zap.Int64("chat_id", chatID), zap.Error(err))
+		return true
 	}
+	return result.Allowed
 }
 
 // ==================== Telegram DTO ====================
@@ -581,11 +651,30 @@ type TelegramUpdate struct {
 }
 
 type TelegramMessage struct {
-	MessageID int          `json:"message_id"`
-	From      TelegramUser `json:"from"`
-	Chat      TelegramChat `json:"chat"`
-	Text      string       `json:"text"`
-	Date      int64        `json:"date"`
+	MessageID int                 `json:"message_id"`
+	From      TelegramUser        `json:"from"`
+	Chat      TelegramChat        `json:"chat"`
+	Text      string              `json:"text"`
+	Caption   string              `json:"caption,omitempty"`
+	Photo     []TelegramPhotoSize `json:"photo,omitempty"`
+	Document  *TelegramDocument   `json:"document,omitempty"`
+	Date      int64               `json:"date"`
+}
+
+type TelegramPhotoSize struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+type TelegramDocument struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileName     string `json:"file_name,omitempty"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int64  `json:"file_size,omitempty"`
 }
 
 type TelegramUser struct {
@@ -603,6 +692,36 @@ type TelegramCallbackQuery struct {
 	Data    string           `json:"data"`
 }
 
+// withUserLocale кладёт в контекст язык и часовой пояс пользователя, привязанного к чату.
+// Для непривязанных чатов используются язык по умолчанию и пояс сервера.
+func (c *TelegramController) withUserLocale(ctx context.Context, chatID int64) context.Context {
+	lang := i18n.DefaultLang
+	if user, err := c.userService.FindUserByTelegramChatID(ctx, chatID); err == nil && user != nil {
+		lang = i18n.Normalize(user.Language)
+		ctx = utils.WithLocation(ctx, utils.UserLocation(user.Timezone))
+	}
+	return context.WithValue(ctx, languageContextKey, lang)
+}
+
+// touchLastSeen запоминает время обращения к боту для профиля; сбой записи не мешает ответу.
+func (c *TelegramController) touchLastSeen(ctx context.Context, chatID int64) {
+	if err := c.userRepo.TouchTelegramLastSeen(ctx, chatID); err != nil {
+		c.logger.Warn("Не удалось обновить время обращения к боту", zap.Int64("chat_id", chatID), zap.Error(err))
+	}
+}
+
+func languageFromCtx(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageContextKey).(string); ok && lang != "" {
+		return lang
+	}
+	return i18n.DefaultLang
+}
+
+// t — перевод строки каталога на язык текущего пользователя.
+func (c *TelegramController) t(ctx context.Context, key string, args ...interface{}) string {
+	return i18n.T(languageFromCtx(ctx), key, args...)
+}
+
 func withCallbackQueryState(ctx context.Context, callbackQueryID string) context.Context {
 	ctx = context.WithValue(ctx, callbackQueryIDContextKey, callbackQueryID)
 	return context.WithValue(ctx, callbackAnswerStateContextKey, &callbackAnswerState{})
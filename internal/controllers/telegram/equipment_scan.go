@@ -0,0 +1,75 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+	tgapi "request-system/pkg/telegram"
+)
+
+// handleEquipmentScan открывает карточку оборудования по deep link с QR-наклейки (/start eq_<код>).
+func (c *TelegramController) handleEquipmentScan(ctx context.Context, chatID int64, code string) error {
+	_, userCtx, err := c.prepareUserContext(ctx, chatID)
+	if err != nil {
+		return c.handlePrepareUserContextError(ctx, chatID, err)
+	}
+
+	scan, err := c.equipmentService.FindEquipmentByCode(userCtx, code)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) || errors.Is(err, apperrors.ErrForbidden) {
+			return c.renderScreen(ctx, chatID, 0, c.t(ctx, "tg.equipment.not_found"), c.mainMenuScreenOptions(ctx)...)
+		}
+		c.logger.Error("Не удалось найти оборудование по коду наклейки", zap.String("code", code), zap.Error(err))
+		return c.sendInternalError(ctx, chatID)
+	}
+
+	text, keyboard := c.equipmentScanScreen(ctx, scan)
+	keyboard = append(keyboard, c.mainMenuKeyboard(ctx)...)
+	return c.renderScreen(ctx, chatID, 0, text, tgapi.WithKeyboard(keyboard), tgapi.WithMarkdownV2())
+}
+
+func (c *TelegramController) equipmentScanScreen(ctx context.Context, scan *dto.EquipmentScanDTO) (string, [][]tgapi.InlineKeyboardButton) {
+	eq := scan.Equipment
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "🖨 *%s*\n", tgapi.EscapeTextForMarkdownV2(eq.Name))
+	if eq.Address != "" {
+		sb.WriteString("📍 " + tgapi.EscapeTextForMarkdownV2(eq.Address) + "\n")
+	}
+	if eq.SerialNumber != nil {
+		sb.WriteString(c.t(ctx, "tg.equipment.serial", tgapi.EscapeTextForMarkdownV2(*eq.SerialNumber)) + "\n")
+	}
+	if eq.State != "" {
+		sb.WriteString(c.t(ctx, "tg.equipment.state."+eq.State) + "\n")
+	}
+	sb.WriteString("\n")
+
+	if len(scan.OpenOrders) == 0 {
+		sb.WriteString(c.t(ctx, "tg.equipment.no_open_orders"))
+	} else {
+		sb.WriteString(c.t(ctx, "tg.equipment.open_orders", len(scan.OpenOrders)))
+		for _, o := range scan.OpenOrders {
+			sb.WriteString("\n" + tgapi.EscapeTextForMarkdownV2(fmt.Sprintf("• №%s %s (%s)", o.Number, o.Name, o.StatusName)))
+		}
+	}
+
+	var keyboard [][]tgapi.InlineKeyboardButton
+	switch {
+	case scan.OrderDraft == nil || eq.State == entities.EquipmentStateWrittenOff:
+		sb.WriteString("\n\n" + c.t(ctx, "tg.equipment.written_off"))
+	case strings.HasPrefix(scan.ScanURL, "https://"):
+		keyboard = append(keyboard, []tgapi.InlineKeyboardButton{{Text: c.t(ctx, "tg.btn.create_order"), URL: scan.ScanURL}})
+	case scan.ScanURL != "":
+		// Telegram не принимает http- и localhost-ссылки в кнопках — показываем ссылку текстом.
+		sb.WriteString("\n\n" + c.t(ctx, "tg.equipment.create_link", tgapi.EscapeTextForMarkdownV2(scan.ScanURL)))
+	}
+
+	return sb.String(), keyboard
+}
@@ -0,0 +1,69 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"request-system/pkg/i18n"
+	tgapi "request-system/pkg/telegram"
+)
+
+func (c *TelegramController) languageKeyboard(ctx context.Context) [][]tgapi.InlineKeyboardButton {
+	current := languageFromCtx(ctx)
+
+	row := make([]tgapi.InlineKeyboardButton, 0, len(i18n.SupportedLangs))
+	for _, lang := range i18n.SupportedLangs {
+		label := i18n.LangNames[lang]
+		if lang == current {
+			label = "✅ " + label
+		}
+		row = append(row, tgapi.InlineKeyboardButton{
+			Text:         label,
+			CallbackData: fmt.Sprintf(`{"action":"set_language","lang":"%s"}`, lang),
+		})
+	}
+
+	return [][]tgapi.InlineKeyboardButton{
+		row,
+		{{Text: c.t(ctx, "tg.btn.back"), CallbackData: `{"action":"main_menu"}`}},
+	}
+}
+
+func (c *TelegramController) handleLanguageCommand(ctx context.Context, chatID int64, messageID int) error {
+	if _, _, err := c.prepareUserContext(ctx, chatID); err != nil {
+		return c.handlePrepareUserContextError(ctx, chatID, err)
+	}
+
+	return c.renderScreen(
+		ctx,
+		chatID,
+		messageID,
+		c.t(ctx, "tg.language.prompt"),
+		tgapi.WithKeyboard(c.languageKeyboard(ctx)),
+		tgapi.WithMarkdownV2(),
+	)
+}
+
+func (c *TelegramController) handleSetLanguageAction(ctx context.Context, chatID int64, messageID int, lang string) error {
+	if !i18n.IsSupported(lang) {
+		c.logger.Warn("Неизвестный язык в callback", zap.String("lang", lang))
+		return nil
+	}
+
+	user, _, err := c.prepareUserContext(ctx, chatID)
+	if err != nil {
+		return c.handlePrepareUserContextError(ctx, chatID, err)
+	}
+
+	if err := c.userRepo.UpdateLanguage(ctx, user.ID, lang); err != nil {
+		c.logger.Error("Не удалось сохранить язык пользователя", zap.Error(err), zap.Uint64("user_id", user.ID))
+		return c.sendInternalError(ctx, chatID)
+	}
+
+	ctx = context.WithValue(ctx, languageContextKey, lang)
+	_ = c.answerCallback(ctx, c.t(ctx, "tg.language.changed"))
+
+	return c.sendMainMenu(ctx, chatID)
+}
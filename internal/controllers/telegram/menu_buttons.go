@@ -1,36 +1,43 @@
 package telegram
 
+import "request-system/pkg/i18n"
+
 const (
-	menuMyTasksButton   = "📋 Мои заявки"
-	menuAssignedButton  = "👨‍💼 Назначены мне"
-	menuInvolvedButton  = "🗂 Участвовал"
-	menuTodayButton     = "⏰ На сегодня"
-	menuOverdueButton   = "🔴 Просроченные"
-	menuStatsButton     = "📊 Статистика"
-	menuSearchButton    = "🔍 Поиск"
-	menuStatusButton    = "🔐 Статус"
-	menuHelpButton      = "📖 Справка"
-	menuMainButton      = "🏠 Главное меню"
 	menuBackButton      = "◀️ Назад"
 	unlinkButton        = "🔓 Отвязать Telegram"
 	confirmUnlinkButton = "✅ Да, отвязать"
+	confirmRebindButton = "🔄 Да, заменить"
 	cancelButton        = "↩️ Отмена"
 )
 
-func isTelegramMenuButton(text string) bool {
-	switch text {
-	case menuMyTasksButton,
-		menuAssignedButton,
-		menuInvolvedButton,
-		menuTodayButton,
-		menuOverdueButton,
-		menuStatsButton,
-		menuSearchButton,
-		menuStatusButton,
-		menuHelpButton,
-		menuMainButton:
-		return true
-	default:
-		return false
+// menuButtonKeys — кнопки, нажатие которых приходит обычным текстом. Текст зависит от языка,
+// на котором была отправлена клавиатура, поэтому он сверяется со всеми языками каталога.
+var menuButtonKeys = []string{
+	"tg.btn.all_orders",
+	"tg.btn.my_tasks",
+	"tg.btn.assigned",
+	"tg.btn.involved",
+	"tg.btn.today",
+	"tg.btn.overdue",
+	"tg.btn.stats",
+	"tg.btn.search",
+	"tg.btn.status",
+	"tg.btn.help",
+	"tg.btn.main_menu",
+}
+
+// menuButtonKey возвращает ключ каталога для текста кнопки или "", если это не кнопка меню.
+func menuButtonKey(text string) string {
+	for _, key := range menuButtonKeys {
+		for _, lang := range i18n.SupportedLangs {
+			if i18n.T(lang, key) == text {
+				return key
+			}
+		}
 	}
+	return ""
+}
+
+func isTelegramMenuButton(text string) bool {
+	return menuButtonKey(text) != ""
 }
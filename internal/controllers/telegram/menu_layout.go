@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	"request-system/pkg/contextkeys"
+	"request-system/pkg/telegram"
+)
+
+// menuAccess — то, от чего зависит набор кнопок главного меню.
+type menuAccess struct {
+	permissions map[string]bool
+	isHead      bool
+}
+
+// menuItem — кнопка главного меню; visible == nil означает «видна всем».
+type menuItem struct {
+	labelKey string
+	action   string
+	visible  func(menuAccess) bool
+}
+
+func requiresPermission(permission string) func(menuAccess) bool {
+	return func(a menuAccess) bool { return a.permissions[permission] }
+}
+
+func headsOnly(a menuAccess) bool { return a.isHead }
+
+// mainMenuLayout — единственное место, где описано главное меню: порядок рядов и кнопок
+// и права, без которых кнопка не показывается. Пустые ряды пропускаются.
+var mainMenuLayout = [][]menuItem{
+	{
+		{labelKey: "tg.btn.new_order", action: "main_new_order", visible: requiresPermission(authz.OrdersCreate)},
+	},
+	{
+		{labelKey: "tg.btn.all_orders", action: "main_all", visible: requiresPermission(authz.OrdersView)},
+		{labelKey: "tg.btn.my_tasks", action: "main_my_tasks", visible: requiresPermission(authz.OrdersView)},
+	},
+	{
+		{labelKey: "tg.btn.assigned", action: "main_assigned", visible: requiresPermission(authz.OrdersView)},
+		{labelKey: "tg.btn.involved", action: "main_involved", visible: requiresPermission(authz.OrdersView)},
+	},
+	{
+		{labelKey: "tg.btn.today", action: "main_today", visible: requiresPermission(authz.OrdersView)},
+		{labelKey: "tg.btn.overdue", action: "main_overdue", visible: requiresPermission(authz.OrdersView)},
+	},
+	{
+		{labelKey: "tg.btn.search", action: "main_search", visible: requiresPermission(authz.OrdersView)},
+		{labelKey: "tg.btn.stats", action: "main_stats"},
+	},
+	{
+		{labelKey: "tg.btn.unit_report", action: "main_unit_report", visible: headsOnly},
+	},
+	{
+		{labelKey: "tg.btn.status", action: "main_status"},
+		{labelKey: "tg.btn.help", action: "main_help"},
+	},
+	{
+		{labelKey: "tg.btn.language", action: "main_language"},
+	},
+}
+
+func buildMainMenu(access menuAccess, label func(key string) string) [][]telegram.InlineKeyboardButton {
+	keyboard := make([][]telegram.InlineKeyboardButton, 0, len(mainMenuLayout))
+	for _, row := range mainMenuLayout {
+		buttons := make([]telegram.InlineKeyboardButton, 0, len(row))
+		for _, item := range row {
+			if item.visible != nil && !item.visible(access) {
+				continue
+			}
+			buttons = append(buttons, telegram.InlineKeyboardButton{
+				Text:         label(item.labelKey),
+				CallbackData: `{"action":"` + item.action + `"}`,
+			})
+		}
+		if len(buttons) > 0 {
+			keyboard = append(keyboard, buttons)
+		}
+	}
+	return keyboard
+}
+
+type menuAccessContextKey struct{}
+
+// withMenuAccess откладывает загрузку прав до первой отрисовки меню: большинство
+// обновлений меню не показывают, а права пользователя — лишний запрос.
+func (c *TelegramController) withMenuAccess(ctx context.Context, chatID int64) context.Context {
+	var (
+		once   sync.Once
+		access menuAccess
+	)
+	load := func() menuAccess {
+		once.Do(func() {
+			user, userCtx, err := c.prepareUserContext(ctx, chatID)
+			if err == nil {
+				access = menuAccessFromContext(userCtx, user)
+			}
+		})
+		return access
+	}
+	return context.WithValue(ctx, menuAccessContextKey{}, load)
+}
+
+func menuAccessFromContext(ctx context.Context, user *entities.User) menuAccess {
+	permissions, _ := ctx.Value(contextkeys.UserPermissionsMapKey).(map[string]bool)
+	return menuAccess{permissions: permissions, isHead: user != nil && user.IsHead != nil && *user.IsHead}
+}
+
+// currentMenuAccess берёт права из контекста пользователя, если он уже собран, иначе
+// загружает их через withMenuAccess. Без того и другого видны только общие кнопки.
+func currentMenuAccess(ctx context.Context) menuAccess {
+	if user, ok := ctx.Value(contextkeys.UserEntityKey).(*entities.User); ok {
+		return menuAccessFromContext(ctx, user)
+	}
+	if load, ok := ctx.Value(menuAccessContextKey{}).(func() menuAccess); ok {
+		return load()
+	}
+	return menuAccess{}
+}
@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	pollingRetryDelay    = 3 * time.Second
+	pollingMaxRetryDelay = time.Minute
+	pollingAckTimeout    = 5 * time.Second
+)
+
+// StartPolling забирает обновления через getUpdates вместо webhook.
+// Используется там, где входящие запросы от Telegram блокируются прокси.
+// Останавливается при отмене ctx.
+func (c *TelegramController) StartPolling(ctx context.Context) {
+	c.logger.Info("Запуск Telegram long-polling", zap.Duration("timeout", c.cfg.PollingTimeout))
+
+	// Пока установлен webhook, getUpdates возвращает ошибку 409.
+	if err := c.integrationService.DeleteWebhook(ctx); err != nil {
+		c.logger.Warn("Не удалось снять Telegram webhook перед long-polling", zap.Error(err))
+	}
+
+	offset := 0
+	retryDelay := pollingRetryDelay
+
+	for {
+		if ctx.Err() != nil {
+			c.ackPolledUpdates(offset)
+			c.logger.Info("Telegram long-polling остановлен", zap.Int("offset", offset))
+			return
+		}
+
+		updates, err := c.integrationService.GetUpdates(ctx, offset, c.cfg.PollingTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			c.logger.Warn("Ошибка получения обновлений Telegram", zap.Error(err), zap.Duration("retry_in", retryDelay))
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+			retryDelay = min(retryDelay*2, pollingMaxRetryDelay)
+			continue
+		}
+		retryDelay = pollingRetryDelay
+
+		for _, raw := range updates {
+			var update TelegramUpdate
+			if err := json.Unmarshal(raw, &update); err != nil {
+				c.logger.Warn("Telegram polling payload decode failed", zap.Error(err))
+				continue
+			}
+			if update.UpdateID >= offset {
+				offset = update.UpdateID + 1
+			}
+			c.dispatchUpdate(&update)
+		}
+	}
+}
+
+// ackPolledUpdates подтверждает Telegram уже обработанные обновления,
+// чтобы после перезапуска они не пришли повторно.
+func (c *TelegramController) ackPolledUpdates(offset int) {
+	if offset == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pollingAckTimeout)
+	defer cancel()
+
+	if _, err := c.integrationService.GetUpdates(ctx, offset, 0); err != nil {
+		c.logger.Warn("Не удалось подтвердить обновления Telegram при остановке", zap.Error(err), zap.Int("offset", offset))
+	}
+}
@@ -1,10 +1,15 @@
 package telegram
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/telegram"
 	"request-system/pkg/utils"
 )
 
@@ -23,6 +28,9 @@ func (c *TelegramController) HandleTelegramLinkStatus(ctx echo.Context) error {
 	if status.TelegramChatID != nil {
 		response["telegram_chat_id"] = *status.TelegramChatID
 	}
+	if status.LastInteractionAt != nil {
+		response["last_interaction_at"] = *status.LastInteractionAt
+	}
 
 	return utils.SuccessResponse(ctx, response, "Статус Telegram получен", http.StatusOK)
 }
@@ -41,3 +49,32 @@ func (c *TelegramController) HandleUnlinkTelegram(ctx echo.Context) error {
 
 	return utils.SuccessResponse(ctx, response, "Telegram отвязан", http.StatusOK)
 }
+
+// HandleForceUnlinkTelegram — администратор отвязывает чат пользователя, например если
+// телефон с Telegram потерян или сотрудник сменил аккаунт.
+func (c *TelegramController) HandleForceUnlinkTelegram(ctx echo.Context) error {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+
+	reqCtx := ctx.Request().Context()
+	chatID, err := c.userService.ForceUnlinkTelegram(reqCtx, userID)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	if chatID != 0 {
+		_ = c.cacheRepo.Del(reqCtx, fmt.Sprintf(telegramStateKey, chatID), fmt.Sprintf(telegramRebindKey, chatID))
+		if c.integrationService.Enabled() {
+			if err := c.tgService.SendMessageEx(reqCtx, chatID,
+				"ℹ️ *Telegram отвязан администратором*\n\nЧтобы снова получать уведомления, получите новый код на сайте и отправьте `/start <код>`\\.",
+				telegram.WithMarkdownV2(),
+			); err != nil {
+				c.logger.Warn("Не удалось уведомить чат об отвязке", zap.Int64("chat_id", chatID), zap.Error(err))
+			}
+		}
+	}
+
+	return utils.SuccessResponse(ctx, map[string]interface{}{"user_id": userID, "linked": false}, "Telegram пользователя отвязан", http.StatusOK)
+}
@@ -9,6 +9,7 @@ import (
 	"request-system/internal/dto"
 	"request-system/internal/entities"
 	tgapi "request-system/pkg/telegram"
+	"request-system/pkg/utils"
 )
 
 func (c *TelegramController) orderBackKeyboard(orderID uint64) [][]tgapi.InlineKeyboardButton {
@@ -50,13 +51,13 @@ func (c *TelegramController) syncStateMessageID(ctx context.Context, chatID int6
 	return c.setUserState(ctx, chatID, state)
 }
 
-func (c *TelegramController) showEditMenuForState(ctx context.Context, chatID int64, state *dto.TelegramState, order *entities.Order) error {
+func (c *TelegramController) showEditMenuForState(ctx context.Context, chatID int64, state *dto.TelegramState, order *entities.Order, notice ...string) error {
 	messageID := 0
 	if state != nil {
 		messageID = state.MessageID
 	}
 
-	if err := c.sendEditMenu(ctx, chatID, messageID, order); err != nil {
+	if err := c.sendEditMenu(ctx, chatID, messageID, order, notice...); err != nil {
 		return err
 	}
 
@@ -78,6 +79,16 @@ func (c *TelegramController) renderCommentPrompt(ctx context.Context, chatID int
 	)
 }
 
+func (c *TelegramController) renderResolutionCommentPrompt(ctx context.Context, chatID int64, state *dto.TelegramState, notice string) error {
+	text := "💬 *Опишите, что было сделано:*\n\n_Необязательно, макс\\. 500 символов_"
+	if strings.TrimSpace(notice) != "" {
+		text = notice + "\n\n" + text
+	}
+	keyboard := [][]tgapi.InlineKeyboardButton{{{Text: "Без комментария", CallbackData: `{"action":"resolution_skip"}`}}}
+	keyboard = append(keyboard, c.orderBackKeyboard(state.OrderID)...)
+	return c.renderStateScreen(ctx, chatID, state, text, tgapi.WithKeyboard(keyboard), tgapi.WithMarkdownV2())
+}
+
 func (c *TelegramController) renderDurationPrompt(ctx context.Context, chatID int64, state *dto.TelegramState, notice string) error {
 	quickDurations := []struct {
 		Label    string
@@ -91,7 +102,7 @@ func (c *TelegramController) renderDurationPrompt(ctx context.Context, chatID in
 
 	var keyboard [][]tgapi.InlineKeyboardButton
 	row := []tgapi.InlineKeyboardButton{}
-	now := time.Now().In(c.loc)
+	now := time.Now().In(utils.LocationFromCtx(ctx))
 	for _, qd := range quickDurations {
 		futureTime := now.Add(qd.Duration).Round(30 * time.Minute)
 		callbackValue := futureTime.Format("02.01.2006 15:04")
@@ -134,13 +145,14 @@ func (c *TelegramController) renderSearchPrompt(ctx context.Context, chatID int6
 	text := "🔍 *Поиск заявки*\n\n" +
 		"Введите:\n" +
 		"• номер заявки \\(например: `123`\\)\n" +
-		"• или текст из описания"
+		"• текст из описания\n" +
+		"• или фильтры: `status:OPEN priority:HIGH executor:me created:>2024-01-01`"
 	if strings.TrimSpace(notice) != "" {
 		text = notice + "\n\n" + text
 	}
 
 	keyboard := [][]tgapi.InlineKeyboardButton{
-		{{Text: menuMainButton, CallbackData: `{"action":"main_menu"}`}},
+		{{Text: c.t(ctx, "tg.btn.main_menu"), CallbackData: `{"action":"main_menu"}`}},
 	}
 
 	return c.renderScreen(ctx, chatID, messageID, text,
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type UserAnonymizationController struct {
+	service services.UserAnonymizationServiceInterface
+	logger  *zap.Logger
+}
+
+func NewUserAnonymizationController(service services.UserAnonymizationServiceInterface, logger *zap.Logger) *UserAnonymizationController {
+	return &UserAnonymizationController{service: service, logger: logger}
+}
+
+// @Summary     Обезличивание уволенного сотрудника
+// @Description ФИО, email, телефон и фото стираются, привязки к Telegram и 1С снимаются, доступ отзывается. В истории заявок ФИО заменяется на «Бывший сотрудник #id», заявки и статистика остаются. С dry_run=true ничего не меняет и показывает, что будет затронуто; иначе в confirm нужно передать текущее ФИО — действие необратимо.
+// @Tags        users
+// @Param       id path int true "ID пользователя"
+// @Param       dry_run query bool false "Только предпросмотр"
+// @Param       body body dto.AnonymizeUserDTO false "Подтверждение"
+// @Success     200 {object} dto.UserAnonymizationDTO
+// @Permission  user:anonymize
+// @Router      /admin/users/{id}/anonymize [post]
+func (c *UserAnonymizationController) Anonymize(ctx echo.Context) error {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID пользователя", err, nil), c.logger)
+	}
+	dryRun := ctx.QueryParam("dry_run") == "true"
+
+	var d dto.AnonymizeUserDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+
+	result, err := c.service.Anonymize(ctx.Request().Context(), userID, d.Confirm, dryRun)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	msg := "Пользователь обезличен"
+	if dryRun {
+		msg = "Предпросмотр обезличивания, изменения не сохранены"
+	}
+	return utils.SuccessResponse(ctx, result, msg, http.StatusOK)
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/middleware"
+	"request-system/pkg/utils"
+)
+
+type UserLanguageController struct {
+	service services.UserLanguageServiceInterface
+	logger  *zap.Logger
+}
+
+func NewUserLanguageController(service services.UserLanguageServiceInterface, logger *zap.Logger) *UserLanguageController {
+	return &UserLanguageController{service: service, logger: logger}
+}
+
+func (c *UserLanguageController) GetMy(ctx echo.Context) error {
+	result, err := c.service.GetMyLanguage(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Язык интерфейса получен", http.StatusOK)
+}
+
+func (c *UserLanguageController) UpdateMy(ctx echo.Context) error {
+	var d dto.UpdateUserLanguageDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	result, err := c.service.UpdateMyLanguage(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	middleware.SetLanguage(ctx, result.Language)
+	return utils.SuccessResponse(ctx, result, "Язык интерфейса сохранён", http.StatusOK)
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type UserTimezoneController struct {
+	service services.UserTimezoneServiceInterface
+	logger  *zap.Logger
+}
+
+func NewUserTimezoneController(service services.UserTimezoneServiceInterface, logger *zap.Logger) *UserTimezoneController {
+	return &UserTimezoneController{service: service, logger: logger}
+}
+
+func (c *UserTimezoneController) GetMy(ctx echo.Context) error {
+	result, err := c.service.GetMyTimezone(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Часовой пояс получен", http.StatusOK)
+}
+
+func (c *UserTimezoneController) UpdateMy(ctx echo.Context) error {
+	var d dto.UpdateUserTimezoneDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	result, err := c.service.UpdateMyTimezone(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Часовой пояс сохранён", http.StatusOK)
+}
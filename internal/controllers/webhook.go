@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type WebhookController struct {
+	service services.WebhookServiceInterface
+	logger  *zap.Logger
+}
+
+func NewWebhookController(service services.WebhookServiceInterface, logger *zap.Logger) *WebhookController {
+	return &WebhookController{service: service, logger: logger}
+}
+
+func (c *WebhookController) parseID(ctx echo.Context) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil)
+	}
+	return id, nil
+}
+
+func (c *WebhookController) Create(ctx echo.Context) error {
+	var d dto.CreateWebhookSubscriptionDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.CreateSubscription(ctx.Request().Context(), d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Webhook-подписка создана", http.StatusCreated)
+}
+
+func (c *WebhookController) Update(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.UpdateWebhookSubscriptionDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.UpdateSubscription(ctx.Request().Context(), id, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Webhook-подписка обновлена", http.StatusOK)
+}
+
+func (c *WebhookController) Delete(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.DeleteSubscription(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Webhook-подписка удалена", http.StatusOK)
+}
+
+func (c *WebhookController) GetAll(ctx echo.Context) error {
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetSubscriptions(ctx.Request().Context(), filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Список webhook-подписок получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func (c *WebhookController) GetByID(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetSubscription(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Webhook-подписка получена", http.StatusOK)
+}
+
+// GetDeliveries — журнал доставок подписки, ?status=pending|processing|delivered|failed.
+func (c *WebhookController) GetDeliveries(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	status := ctx.QueryParam("status")
+	switch status {
+	case "", "pending", "processing", "delivered", "failed":
+	default:
+		return utils.ErrorResponse(ctx, apperrors.NewBadRequestError("Неверный статус доставки"), c.logger)
+	}
+
+	filter := utils.ParseFilterFromQuery(ctx.Request().URL.Query())
+	result, err := c.service.GetDeliveries(ctx.Request().Context(), id, status, filter)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result.List, "Журнал webhook-доставок получен", http.StatusOK, result.Pagination.TotalCount)
+}
+
+func (c *WebhookController) GetDelivery(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.GetDelivery(ctx.Request().Context(), id)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Webhook-доставка получена", http.StatusOK)
+}
+
+func (c *WebhookController) Redeliver(ctx echo.Context) error {
+	id, err := c.parseID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	if err := c.service.Redeliver(ctx.Request().Context(), id); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Webhook-доставка поставлена в очередь повторно", http.StatusOK)
+}
@@ -1,11 +1,19 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"request-system/internal/repositories"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
 	"request-system/pkg/service"
+	"request-system/pkg/utils"
 	appwebsocket "request-system/pkg/websocket"
 
 	"github.com/gorilla/websocket"
@@ -38,12 +46,23 @@ var (
 )
 
 type WebSocketController struct {
-	hub        *appwebsocket.Hub
-	jwtService service.JWTService
-	logger     *zap.Logger
+	hub           *appwebsocket.Hub
+	jwtService    service.JWTService
+	ticketService services.WebSocketTicketServiceInterface
+	orderService  services.OrderServiceInterface
+	userRepo      repositories.UserRepositoryInterface
+	logger        *zap.Logger
 }
 
-func NewWebSocketController(hub *appwebsocket.Hub, jwtService service.JWTService, logger *zap.Logger, allowedOrigins []string) *WebSocketController {
+func NewWebSocketController(
+	hub *appwebsocket.Hub,
+	jwtService service.JWTService,
+	ticketService services.WebSocketTicketServiceInterface,
+	orderService services.OrderServiceInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+	allowedOrigins []string,
+) *WebSocketController {
 	websocketAllowedOrigins = map[string]struct{}{}
 	websocketAllowAnyOrigin = false
 
@@ -58,26 +77,55 @@ func NewWebSocketController(hub *appwebsocket.Hub, jwtService service.JWTService
 		}
 	}
 
-	return &WebSocketController{
-		hub:        hub,
-		jwtService: jwtService,
-		logger:     logger,
+	c := &WebSocketController{
+		hub:           hub,
+		jwtService:    jwtService,
+		ticketService: ticketService,
+		orderService:  orderService,
+		userRepo:      userRepo,
+		logger:        logger,
 	}
+	hub.SetRoomAuthorizer(c.authorizeOrderRoom)
+	return c
 }
 
+// authorizeOrderRoom — подписаться на комнату заявки можно только при праве на её просмотр.
+func (c *WebSocketController) authorizeOrderRoom(ctx context.Context, userID uint64, orderID uint64) error {
+	if _, err := c.orderService.FindOrderByIDForTelegram(ctx, userID, orderID); err != nil {
+		c.logger.Debug("WebSocket: отказ в подписке на заявку", zap.Uint64("userID", userID), zap.Uint64("orderID", orderID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// IssueTicket выдаёт одноразовый билет для подключения к /api/ws?ticket=..., чтобы не передавать JWT в адресе.
+// @Summary     Билет для подключения к WebSocket
+// @Description Билет действует WS_TICKET_TTL_SECONDS секунд и гасится при первом подключении.
+// @Tags        websocket
+// @Success     200 {object} dto.WebSocketTicketDTO
+// @Router      /ws-ticket [post]
+func (c *WebSocketController) IssueTicket(ctx echo.Context) error {
+	ticket, err := c.ticketService.IssueTicket(ctx.Request().Context())
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, ticket, "Билет для WebSocket выдан", http.StatusOK)
+}
+
+// ServeWs подключает клиента по билету (?ticket=) или по JWT в заголовке Authorization
+// либо в подпротоколе "bearer, <token>". JWT в адресе не принимается.
 func (c *WebSocketController) ServeWs(ctx echo.Context) error {
 	if ctx.QueryParam("token") != "" {
 		return ctx.String(http.StatusUnauthorized, "Token in query string is not allowed")
 	}
 
-	tokenString, err := c.extractToken(ctx.Request())
+	userID, impersonatorID, err := c.authenticate(ctx)
 	if err != nil {
-		return ctx.String(http.StatusUnauthorized, "Missing token")
+		return ctx.String(http.StatusUnauthorized, err.Error())
 	}
-
-	claims, err := c.jwtService.ValidateToken(tokenString)
-	if err != nil || claims.IsRefreshToken {
-		return ctx.String(http.StatusUnauthorized, "Invalid token")
+	if !c.hub.CanConnect(userID) {
+		c.logger.Warn("WebSocket: превышен предел соединений пользователя", zap.Uint64("userID", userID))
+		return ctx.String(http.StatusTooManyRequests, "Too many connections")
 	}
 
 	conn, err := upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
@@ -86,16 +134,140 @@ func (c *WebSocketController) ServeWs(ctx echo.Context) error {
 		return err
 	}
 
-	client := appwebsocket.NewClient(c.hub, conn, claims.UserID)
+	client := appwebsocket.NewClient(c.hub, conn, userID)
+	client.RemoteAddr = ctx.RealIP()
+	client.UserAgent = ctx.Request().UserAgent()
+	client.ImpersonatorID = impersonatorID
+	if user, err := c.userRepo.FindUserByID(ctx.Request().Context(), userID); err == nil && user != nil {
+		client.Profile = &appwebsocket.ActorInfo{Name: user.Fio, AvatarURL: user.PhotoURL}
+	}
 	client.Hub.Register <- client
 
 	go client.WritePump()
 	go client.ReadPump()
 
-	c.logger.Info("WebSocket: клиент успешно подключен", zap.Uint64("userID", claims.UserID))
+	// ?since=<seq> — клиент переподключился и хочет получить пропущенные сообщения.
+	if sinceRaw := ctx.QueryParam("since"); sinceRaw != "" {
+		if since, err := strconv.ParseUint(sinceRaw, 10, 64); err == nil {
+			go func(userID uint64) {
+				replayCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if err := c.hub.Replay(replayCtx, client, since); err != nil {
+					c.logger.Warn("WebSocket: не удалось дослать пропущенные сообщения", zap.Uint64("userID", userID), zap.Error(err))
+				}
+			}(userID)
+		}
+	}
+
+	fields := []zap.Field{zap.Uint64("userID", userID)}
+	if impersonatorID != 0 {
+		fields = append(fields, zap.Uint64("impersonatorID", impersonatorID))
+	}
+	c.logger.Info("WebSocket: клиент успешно подключен", fields...)
 	return nil
 }
 
+// GetOrderPresence — кто сейчас держит открытой карточку заявки.
+func (c *WebSocketController) GetOrderPresence(ctx echo.Context) error {
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID", err, nil), c.logger)
+	}
+
+	reqCtx := ctx.Request().Context()
+	if _, err := c.orderService.FindOrderByID(reqCtx, orderID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+
+	viewers, err := c.hub.RoomPresence(reqCtx, appwebsocket.OrderRoom(orderID))
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, appwebsocket.PresencePayload{OrderID: orderID, Viewers: viewers}, "Список просматривающих заявку получен", http.StatusOK)
+}
+
+// GetHubStats — состояние WebSocket-хаба этой реплики.
+// @Summary     Статистика WebSocket
+// @Tags        websocket
+// @Success     200 {object} appwebsocket.HubStats
+// @Permission  websocket:manage
+// @Router      /admin/ws/stats [get]
+func (c *WebSocketController) GetHubStats(ctx echo.Context) error {
+	return utils.SuccessResponse(ctx, c.hub.Stats(), "Статистика WebSocket получена", http.StatusOK)
+}
+
+// GetConnections — открытые соединения пользователя на этой реплике; без user_id — все.
+// @Summary     WebSocket-соединения
+// @Tags        websocket
+// @Param       user_id query int false "ID пользователя"
+// @Success     200 {array} appwebsocket.ConnectionInfo
+// @Permission  websocket:manage
+// @Router      /admin/ws/connections [get]
+func (c *WebSocketController) GetConnections(ctx echo.Context) error {
+	var userID uint64
+	if raw := strings.TrimSpace(ctx.QueryParam("user_id")); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат user_id", err, nil), c.logger)
+		}
+		userID = id
+	}
+	return utils.SuccessResponse(ctx, c.hub.Connections(userID), "Соединения WebSocket получены", http.StatusOK)
+}
+
+// DisconnectConnection принудительно закрывает одно соединение, в том числе на другой реплике.
+// @Summary     Отключить WebSocket-соединение
+// @Tags        websocket
+// @Param       id path string true "ID соединения"
+// @Success     200 {object} map[string]int
+// @Permission  websocket:manage
+// @Router      /admin/ws/connections/{id} [delete]
+func (c *WebSocketController) DisconnectConnection(ctx echo.Context) error {
+	id := strings.TrimSpace(ctx.Param("id"))
+	closed := c.hub.Disconnect(id, 0)
+	c.logger.Info("WebSocket: соединение закрыто администратором", zap.String("connectionID", id), zap.Int("closedHere", closed))
+	return utils.SuccessResponse(ctx, map[string]int{"closed": closed}, "Команда на отключение отправлена", http.StatusOK)
+}
+
+// DisconnectUser принудительно закрывает все соединения пользователя на всех репликах.
+// @Summary     Отключить все WebSocket-соединения пользователя
+// @Tags        websocket
+// @Param       userID path int true "ID пользователя"
+// @Success     200 {object} map[string]int
+// @Permission  websocket:manage
+// @Router      /admin/ws/users/{userID}/connections [delete]
+func (c *WebSocketController) DisconnectUser(ctx echo.Context) error {
+	userID, err := strconv.ParseUint(ctx.Param("userID"), 10, 64)
+	if err != nil || userID == 0 {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID пользователя", err, nil), c.logger)
+	}
+	closed := c.hub.Disconnect("", userID)
+	c.logger.Info("WebSocket: соединения пользователя закрыты администратором", zap.Uint64("userID", userID), zap.Int("closedHere", closed))
+	return utils.SuccessResponse(ctx, map[string]int{"closed": closed}, "Команда на отключение отправлена", http.StatusOK)
+}
+
+// authenticate определяет пользователя по билету или по access-токену. Второе значение —
+// администратор, если подключение сделано в режиме имперсонации.
+func (c *WebSocketController) authenticate(ctx echo.Context) (uint64, uint64, error) {
+	if ticket := strings.TrimSpace(ctx.QueryParam("ticket")); ticket != "" {
+		userID, impersonatorID, err := c.ticketService.ConsumeTicket(ctx.Request().Context(), ticket)
+		if err != nil {
+			return 0, 0, errors.New("Invalid ticket")
+		}
+		return userID, impersonatorID, nil
+	}
+
+	tokenString, err := c.extractToken(ctx.Request())
+	if err != nil {
+		return 0, 0, errors.New("Missing token")
+	}
+	claims, err := c.jwtService.ValidateToken(tokenString)
+	if err != nil || claims.IsRefreshToken {
+		return 0, 0, errors.New("Invalid token")
+	}
+	return claims.UserID, claims.ImpersonatorID, nil
+}
+
 func (c *WebSocketController) extractToken(r *http.Request) (string, error) {
 	authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
 	if authHeader != "" {
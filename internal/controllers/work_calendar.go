@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type WorkCalendarController struct {
+	service services.WorkCalendarServiceInterface
+	logger  *zap.Logger
+}
+
+func NewWorkCalendarController(service services.WorkCalendarServiceInterface, logger *zap.Logger) *WorkCalendarController {
+	return &WorkCalendarController{service: service, logger: logger}
+}
+
+// GetTeamCalendar — ?date_from=&date_to= (RFC3339 или YYYY-MM-DD), по умолчанию месяц от сегодня.
+func (c *WorkCalendarController) GetTeamCalendar(ctx echo.Context) error {
+	teamID, err := parseTeamID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	from, err := parseAuditDate(ctx.QueryParam("date_from"), false)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат date_from", err, nil), c.logger)
+	}
+	to, err := parseAuditDate(ctx.QueryParam("date_to"), true)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат date_to", err, nil), c.logger)
+	}
+
+	result, err := c.service.GetTeamCalendar(ctx.Request().Context(), teamID, from, to)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Календарь команды получен", http.StatusOK)
+}
+
+func (c *WorkCalendarController) AddTeamEntry(ctx echo.Context) error {
+	teamID, err := parseTeamID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	var d dto.CreateWorkCalendarEntryDTO
+	if err := ctx.Bind(&d); err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверные данные", err, nil), c.logger)
+	}
+	if err := ctx.Validate(&d); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	result, err := c.service.AddTeamEntry(ctx.Request().Context(), teamID, d)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, result, "Запись календаря добавлена", http.StatusCreated)
+}
+
+func (c *WorkCalendarController) DeleteTeamEntry(ctx echo.Context) error {
+	teamID, err := parseTeamID(ctx)
+	if err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	entryID, err := strconv.ParseUint(ctx.Param("entryID"), 10, 64)
+	if err != nil {
+		return utils.ErrorResponse(ctx, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат ID записи", err, nil), c.logger)
+	}
+	if err := c.service.DeleteTeamEntry(ctx.Request().Context(), teamID, entryID); err != nil {
+		return utils.ErrorResponse(ctx, err, c.logger)
+	}
+	return utils.SuccessResponse(ctx, nil, "Запись календаря удалена", http.StatusOK)
+}
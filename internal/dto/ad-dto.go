@@ -14,3 +14,27 @@ type ADUserDTO struct {
 	Username string `json:"username"` // Логин
 	FIO      string `json:"fio"`      // ФИО для отображения
 }
+
+// ADGroupMappingDTO — сопоставление группы AD с ролью.
+type ADGroupMappingDTO struct {
+	ID        uint64 `json:"id"`
+	ADGroup   string `json:"ad_group"`
+	RoleID    uint64 `json:"role_id"`
+	RoleName  string `json:"role_name"`
+	CreatedAt string `json:"created_at"`
+}
+
+// CreateADGroupMappingDTO — ad_group принимает полный DN группы или её CN.
+type CreateADGroupMappingDTO struct {
+	ADGroup string `json:"ad_group" validate:"required,max=512"`
+	RoleID  uint64 `json:"role_id" validate:"required"`
+}
+
+// ADGroupSyncResultDTO — итог синхронизации ролей по группам AD.
+type ADGroupSyncResultDTO struct {
+	Scanned     int `json:"scanned"`
+	Updated     int `json:"updated"`
+	Provisioned int `json:"provisioned"`
+	Skipped     int `json:"skipped"`
+	Failed      int `json:"failed"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"request-system/pkg/types"
+)
+
+type AnalyticsOrderFilterDTO struct {
+	AfterID     uint64
+	Limit       uint64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+}
+
+// AnalyticsOrdersPageDTO — страница фактов. Следующую страницу запрашивают с after_id = next_after_id,
+// пока has_more = true.
+type AnalyticsOrdersPageDTO struct {
+	List        []types.AnalyticsOrderFact `json:"list"`
+	Limit       uint64                     `json:"limit"`
+	HasMore     bool                       `json:"has_more"`
+	NextAfterID *uint64                    `json:"next_after_id"`
+}
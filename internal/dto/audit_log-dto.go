@@ -0,0 +1,21 @@
+package dto
+
+import "encoding/json"
+
+type AuditLogEntryDTO struct {
+	ID             uint64          `json:"id"`
+	ActorID        *uint64         `json:"actor_id"`
+	Action         string          `json:"action"`
+	Entity         string          `json:"entity"`
+	EntityID       *string         `json:"entity_id"`
+	Method         string          `json:"method"`
+	Path           string          `json:"path"`
+	StatusCode     int             `json:"status_code"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	IP             *string         `json:"ip"`
+	UserAgent      *string         `json:"user_agent"`
+	RequestID      *string         `json:"request_id"`
+	ImpersonatorID *uint64         `json:"impersonator_id"`
+	CreatedAt      string          `json:"created_at"`
+}
@@ -55,6 +55,8 @@ type UserProfileDTO struct {
 	RoleIDs     []uint64 `json:"role_ids"`
 	PositionIDs []uint64 `json:"position_ids"`
 	OtdelIDs    []uint64 `json:"otdel_ids"`
+
+	Telegram TelegramChannelDTO `json:"telegram"`
 }
 type ChangePasswordRequiredDTO struct {
 	ResetToken string `json:"reset_token"`
@@ -67,3 +69,20 @@ type UpdateMyProfileDTO struct {
 	Email       *string `json:"email" validate:"omitempty,email"`
 	PhotoURL    *string `json:"photo_url,omitempty"`
 }
+
+// AvatarDTO — загруженный аватар: photo_url — основное изображение (не больше 1024 px),
+// variants — уменьшенные копии по размеру большей стороны ("256", "64").
+type AvatarDTO struct {
+	PhotoURL string            `json:"photo_url"`
+	Variants map[string]string `json:"variants"`
+}
+
+// ImpersonationResponseDTO — токен для работы под другим пользователем. Refresh-токена нет:
+// после expires_at нужно вернуться к своему токену.
+type ImpersonationResponseDTO struct {
+	AccessToken string   `json:"accessToken"`
+	ExpiresAt   string   `json:"expiresAt"`
+	UserID      uint64   `json:"userId"`
+	Fio         string   `json:"fio"`
+	Permissions []string `json:"permissions"`
+}
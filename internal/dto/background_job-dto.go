@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BackgroundJobDTO — фоновое задание из очереди pkg/jobs для админки.
+type BackgroundJobDTO struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	State       string          `json:"state"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// BackupTableReportDTO — сколько строк таблицы загружено при проверке восстановления.
+type BackupTableReportDTO struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+type BackupDTO struct {
+	ID           uint64                 `json:"id"`
+	Status       string                 `json:"status"`
+	Tables       []string               `json:"tables"`
+	FilePath     *string                `json:"file_path"`
+	SizeBytes    int64                  `json:"size_bytes"`
+	SHA256       *string                `json:"sha256"`
+	TriggeredBy  *uint64                `json:"triggered_by"`
+	Error        *string                `json:"error,omitempty"`
+	StartedAt    string                 `json:"started_at"`
+	FinishedAt   *string                `json:"finished_at"`
+	VerifyStatus *string                `json:"verify_status"`
+	VerifyReport []BackupTableReportDTO `json:"verify_report,omitempty"`
+	VerifyError  *string                `json:"verify_error,omitempty"`
+	VerifiedAt   *string                `json:"verified_at"`
+}
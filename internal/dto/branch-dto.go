@@ -9,6 +9,8 @@ type CreateBranchDTO struct {
 	EmailIndex  string `json:"email_index"`
 	OpenDate    string `json:"open_date" validate:"required"`
 	StatusID    uint64 `json:"status_id" validate:"required"`
+	// Префикс номеров заявок филиала, например HQ; пустой — общий REQ
+	OrderPrefix string `json:"order_prefix"`
 }
 
 type UpdateBranchDTO struct {
@@ -20,6 +22,8 @@ type UpdateBranchDTO struct {
 	EmailIndex  *string `json:"email_index"`
 	OpenDate    *string `json:"open_date" validate:"omitempty"`
 	StatusID    *uint64 `json:"status_id" validate:"omitempty"`
+	// Пустая строка сбрасывает префикс на общий
+	OrderPrefix *string `json:"order_prefix"`
 }
 
 type BranchListResponseDTO struct {
@@ -32,6 +36,7 @@ type BranchListResponseDTO struct {
 	Email       string `json:"email"`
 	EmailIndex  string `json:"email_index"`
 	OpenDate    string `json:"open_date"`
+	OrderPrefix string `json:"order_prefix,omitempty"`
 	CreatedAt   string `json:"created_at"`
 }
 
@@ -44,6 +49,7 @@ type BranchDTO struct {
 	Email       string          `json:"email"`
 	EmailIndex  string          `json:"email_index"`
 	OpenDate    string          `json:"open_date"`
+	OrderPrefix string          `json:"order_prefix,omitempty"`
 	Status      *ShortStatusDTO `json:"status"`
 	CreatedAt   string          `json:"created_at"`
 	UpdatedAt   string          `json:"updated_at"`
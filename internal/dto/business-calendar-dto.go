@@ -0,0 +1,52 @@
+package dto
+
+// UpdateBusinessCalendarDTO — рабочие дни недели (1 — понедельник, 7 — воскресенье) и часы «ЧЧ:ММ».
+type UpdateBusinessCalendarDTO struct {
+	WorkDays  []int   `json:"work_days,omitempty" validate:"omitempty,min=1,max=7,unique,dive,min=1,max=7"`
+	DayStart  *string `json:"day_start,omitempty" validate:"omitempty,len=5"`
+	DayEnd    *string `json:"day_end,omitempty" validate:"omitempty,len=5"`
+	IsEnabled *bool   `json:"is_enabled,omitempty"`
+}
+
+type BusinessCalendarDTO struct {
+	WorkDays  []int   `json:"work_days"`
+	DayStart  string  `json:"day_start"`
+	DayEnd    string  `json:"day_end"`
+	IsEnabled bool    `json:"is_enabled"`
+	Timezone  string  `json:"timezone"`
+	UpdatedBy *uint64 `json:"updated_by"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// CreateBusinessHolidayDTO — праздник или перенос; дата в формате ГГГГ-ММ-ДД.
+type CreateBusinessHolidayDTO struct {
+	Date         string `json:"date" validate:"required,len=10"`
+	Name         string `json:"name" validate:"required,max=255"`
+	IsRecurring  bool   `json:"is_recurring"`
+	IsWorkingDay bool   `json:"is_working_day"`
+}
+
+type UpdateBusinessHolidayDTO struct {
+	Date         *string `json:"date,omitempty" validate:"omitempty,len=10"`
+	Name         *string `json:"name,omitempty" validate:"omitempty,max=255"`
+	IsRecurring  *bool   `json:"is_recurring,omitempty"`
+	IsWorkingDay *bool   `json:"is_working_day,omitempty"`
+}
+
+type BusinessHolidayDTO struct {
+	ID           uint64  `json:"id"`
+	Date         string  `json:"date"`
+	Name         string  `json:"name"`
+	IsRecurring  bool    `json:"is_recurring"`
+	IsWorkingDay bool    `json:"is_working_day"`
+	CreatedBy    *uint64 `json:"created_by"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// BusinessDurationDTO — сколько рабочего времени между двумя моментами (для проверки календаря).
+type BusinessDurationDTO struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	BusinessSeconds int64  `json:"business_seconds"`
+	TotalSeconds    int64  `json:"total_seconds"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// CalendarFeedDTO — персональная iCal-подписка. URL содержит секрет: кто его знает, видит сроки заявок пользователя.
+type CalendarFeedDTO struct {
+	Enabled    bool    `json:"enabled"`
+	URL        string  `json:"url,omitempty"`
+	WebcalURL  string  `json:"webcal_url,omitempty"`
+	CreatedAt  *string `json:"created_at,omitempty"`
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+}
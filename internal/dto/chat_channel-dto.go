@@ -0,0 +1,37 @@
+package dto
+
+type CreateChatChannelDTO struct {
+	Name         string   `json:"name" validate:"required,max=255"`
+	Kind         string   `json:"kind" validate:"required,oneof=teams slack"`
+	WebhookURL   string   `json:"webhook_url" validate:"required,url,max=2048"`
+	DepartmentID *uint64  `json:"department_id,omitempty"`
+	EventTypes   []string `json:"event_types" validate:"required,min=1,dive,required"`
+	OnlyCritical bool     `json:"only_critical,omitempty"`
+	IsActive     *bool    `json:"is_active,omitempty"`
+}
+
+// UpdateChatChannelDTO — department_id: 0 снимает привязку к департаменту.
+type UpdateChatChannelDTO struct {
+	Name         *string   `json:"name,omitempty" validate:"omitempty,max=255"`
+	Kind         *string   `json:"kind,omitempty" validate:"omitempty,oneof=teams slack"`
+	WebhookURL   *string   `json:"webhook_url,omitempty" validate:"omitempty,url,max=2048"`
+	DepartmentID *uint64   `json:"department_id,omitempty"`
+	EventTypes   *[]string `json:"event_types,omitempty" validate:"omitempty,min=1,dive,required"`
+	OnlyCritical *bool     `json:"only_critical,omitempty"`
+	IsActive     *bool     `json:"is_active,omitempty"`
+}
+
+// ChatChannelDTO — URL вебхука сам по себе даёт право писать в канал, поэтому наружу отдаётся замаскированным.
+type ChatChannelDTO struct {
+	ID           uint64   `json:"id"`
+	Name         string   `json:"name"`
+	Kind         string   `json:"kind"`
+	WebhookURL   string   `json:"webhook_url"`
+	DepartmentID *uint64  `json:"department_id"`
+	EventTypes   []string `json:"event_types"`
+	OnlyCritical bool     `json:"only_critical"`
+	IsActive     bool     `json:"is_active"`
+	CreatedBy    *uint64  `json:"created_by"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+// CustomFieldDefinitionDTO — дополнительное поле типа заявки.
+type CustomFieldDefinitionDTO struct {
+	ID          uint64   `json:"id"`
+	OrderTypeID uint64   `json:"order_type_id"`
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	FieldType   string   `json:"field_type"`
+	IsRequired  bool     `json:"is_required"`
+	Options     []string `json:"options"`
+	SortOrder   int      `json:"sort_order"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// CreateCustomFieldDTO — options обязательны только для field_type = select.
+type CreateCustomFieldDTO struct {
+	Code       string   `json:"code" validate:"required,max=64"`
+	Name       string   `json:"name" validate:"required,max=255"`
+	FieldType  string   `json:"field_type" validate:"required,oneof=text number date boolean select"`
+	IsRequired bool     `json:"is_required"`
+	Options    []string `json:"options"`
+	SortOrder  int      `json:"sort_order"`
+}
+
+// UpdateCustomFieldDTO — код и тип поля не меняются: на них завязаны уже сохранённые значения.
+type UpdateCustomFieldDTO struct {
+	Name       *string  `json:"name" validate:"omitempty,max=255"`
+	IsRequired *bool    `json:"is_required"`
+	Options    []string `json:"options"`
+	SortOrder  *int     `json:"sort_order"`
+}
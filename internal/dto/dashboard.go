@@ -17,3 +17,15 @@ type DashboardStatsDTO struct {
 	Branches        []types.DashboardDepartmentStat `json:"branches"`
 	LastActivity    []types.DashboardActivityItem   `json:"last_activity"`
 }
+
+type DashboardExecutorsDTO struct {
+	Meta      *types.DashboardMeta          `json:"meta,omitempty"`
+	SortBy    string                        `json:"sort_by"`
+	Executors []types.DashboardExecutorStat `json:"executors"`
+}
+
+type DashboardBacklogAgingDTO struct {
+	GeneratedAt    string `json:"generated_at"`
+	EffectiveScope string `json:"effective_scope"`
+	types.DashboardBacklogAging
+}
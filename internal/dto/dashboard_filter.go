@@ -8,4 +8,13 @@ type DashboardFilterDTO struct {
 	DateTo      *time.Time `json:"date_to,omitempty"`
 	Widgets     []string   `json:"widgets,omitempty"`
 	Granularity string     `json:"granularity,omitempty"`
+	// Scope сужает область: own, office, otdel, branch, department или all; пусто — самая широкая доступная
+	Scope string `json:"scope,omitempty"`
+}
+
+// DashboardExecutorFilterDTO — параметры рейтинга исполнителей поверх общего фильтра дашборда.
+type DashboardExecutorFilterDTO struct {
+	DashboardFilterDTO
+	SortBy string `json:"sort_by,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
 }
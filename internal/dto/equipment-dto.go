@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type CreateEquipmentDTO struct {
 	Name    string `json:"name" validate:"required"`
 	Address string `json:"address" validate:"required"`
@@ -8,6 +10,12 @@ type CreateEquipmentDTO struct {
 	OfficeID        *uint64 `json:"office_id" validate:"omitempty"`
 	StatusID        uint64  `json:"status_id" validate:"required"`
 	EquipmentTypeID uint64  `json:"equipment_type_id" validate:"required"`
+
+	SerialNumber   *string `json:"serial_number" validate:"omitempty,max=128"`
+	AssignedUserID *uint64 `json:"assigned_user_id" validate:"omitempty,gt=0"`
+	PurchaseDate   *string `json:"purchase_date" validate:"omitempty,datetime=2006-01-02"`
+	WarrantyUntil  *string `json:"warranty_until" validate:"omitempty,datetime=2006-01-02"`
+	State          *string `json:"state" validate:"omitempty,oneof=in_service repair written_off"`
 }
 
 type UpdateEquipmentDTO struct {
@@ -17,18 +25,50 @@ type UpdateEquipmentDTO struct {
 	OfficeID        *uint64 `json:"office_id,omitempty"      validate:"omitempty,gt=0"`
 	StatusID        *uint64 `json:"status_id,omitempty"      validate:"omitempty,gt=0"`
 	EquipmentTypeID *uint64 `json:"equipment_type_id,omitempty" validate:"omitempty,gt=0"`
+
+	// Пустая строка / 0 очищают значение.
+	SerialNumber   *string `json:"serial_number,omitempty"    validate:"omitempty,max=128"`
+	AssignedUserID *uint64 `json:"assigned_user_id,omitempty" validate:"omitempty"`
+	PurchaseDate   *string `json:"purchase_date,omitempty"    validate:"omitempty,datetime=2006-01-02"`
+	WarrantyUntil  *string `json:"warranty_until,omitempty"   validate:"omitempty,datetime=2006-01-02"`
+	State          *string `json:"state,omitempty"            validate:"omitempty,oneof=in_service repair written_off"`
 }
 
 type EquipmentDTO struct {
 	ID            uint64                `json:"id"`
 	Name          string                `json:"name"`
 	Address       string                `json:"address"`
+	Code          string                `json:"code"`
 	Branch        ShortBranchDTO        `json:"branch"`
 	Office        ShortOfficeDTO        `json:"office"`
 	EquipmentType ShortEquipmentTypeDTO `json:"equipment"`
 	StatusID      uint64                `json:"status_id"`
 	CreatedAt     string                `json:"created_at"`
 	UpdatedAt     string                `json:"updated_at"`
+
+	SerialNumber   *string       `json:"serial_number"`
+	AssignedUser   *ShortUserDTO `json:"assigned_user"`
+	PurchaseDate   *string       `json:"purchase_date"`
+	WarrantyUntil  *string       `json:"warranty_until"`
+	UnderWarranty  bool          `json:"under_warranty"`
+	State          string        `json:"state"`
+	StateChangedAt *string       `json:"state_changed_at"`
+
+	// Заявки по оборудованию; заполняется только в карточке и при праве order:view.
+	Orders []EquipmentOrderDTO `json:"orders,omitempty"`
+}
+
+// EquipmentOrderDTO — краткая строка заявки в истории оборудования.
+type EquipmentOrderDTO struct {
+	ID           uint64     `json:"id"`
+	Number       string     `json:"number"`
+	Name         string     `json:"name"`
+	StatusID     uint64     `json:"status_id"`
+	StatusName   string     `json:"status_name"`
+	StatusCode   string     `json:"status_code"`
+	ExecutorName *string    `json:"executor_name"`
+	CreatedAt    string     `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
 }
 type EquipmentListResponseDTO struct {
 	ID              uint64  `json:"id"`
@@ -38,6 +78,11 @@ type EquipmentListResponseDTO struct {
 	OfficeID        *uint64 `json:"office_id"`
 	EquipmentTypeID uint64  `json:"equipment_type_id"`
 	StatusID        uint64  `json:"status_id"`
+	Code            string  `json:"code"`
+	SerialNumber    *string `json:"serial_number"`
+	AssignedUserID  *uint64 `json:"assigned_user_id"`
+	WarrantyUntil   *string `json:"warranty_until"`
+	State           string  `json:"state"`
 	CreatedAt       string  `json:"created_at"`
 	UpdatedAt       string  `json:"updated_at"`
 }
@@ -45,3 +90,14 @@ type ShortEquipmentDTO struct {
 	ID   uint64 `json:"id"`
 	Name string `json:"name"`
 }
+
+// EquipmentScanDTO — ответ на сканирование QR-наклейки: карточка, открытые заявки
+// и черновик новой заявки с уже подставленным оборудованием.
+type EquipmentScanDTO struct {
+	Equipment  *EquipmentDTO       `json:"equipment"`
+	OpenOrders []EquipmentOrderDTO `json:"open_orders"`
+	// OrderDraft отсутствует, если оборудование списано.
+	OrderDraft *CreateOrderDTO `json:"order_draft,omitempty"`
+	// ScanURL — страница сканирования на сайте, бот отдаёт её кнопкой «Создать заявку».
+	ScanURL string `json:"scan_url"`
+}
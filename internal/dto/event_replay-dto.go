@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// EventReplayRequestDTO — что переиграть. Нужно указать order_id или date_from,
+// чтобы случайно не переиграть всю историю. Продолжение большой выборки — через after_id.
+type EventReplayRequestDTO struct {
+	OrderID    *uint64    `json:"order_id,omitempty"`
+	DateFrom   *time.Time `json:"date_from,omitempty"`
+	DateTo     *time.Time `json:"date_to,omitempty"`
+	EventTypes []string   `json:"event_types,omitempty" validate:"omitempty,dive,required,max=50"`
+	AfterID    uint64     `json:"after_id,omitempty"`
+	Limit      int        `json:"limit,omitempty" validate:"omitempty,min=1,max=10000"`
+	DryRun     bool       `json:"dry_run,omitempty"`
+}
+
+type EventReplayResultDTO struct {
+	Matched       int    `json:"matched"`
+	Published     int    `json:"published"`
+	Skipped       int    `json:"skipped"`
+	LastHistoryID uint64 `json:"last_history_id"`
+	HasMore       bool   `json:"has_more"`
+	DryRun        bool   `json:"dry_run"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+// ImportRowErrorDTO — ошибка строки CSV. Row — номер строки в файле (заголовок — строка 1),
+// Key — externalId или имя записи.
+type ImportRowErrorDTO struct {
+	Row    int    `json:"row"`
+	Key    string `json:"key,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// ImportChangeDTO — что импорт изменит в записи: action create|update, поля с новыми значениями.
+type ImportChangeDTO struct {
+	Row    int                           `json:"row"`
+	Key    string                        `json:"key,omitempty"`
+	Action string                        `json:"action"`
+	Fields map[string]SyncFieldChangeDTO `json:"fields,omitempty"`
+}
+
+// ImportResultDTO — итог импорта CSV. С preview=true ничего не сохранено: счётчики, ошибки и
+// changes показывают, что сделал бы импорт. Ошибочные строки пропускаются, остальные сохраняются.
+type ImportResultDTO struct {
+	Entity  string              `json:"entity"`
+	Preview bool                `json:"preview"`
+	Rows    int                 `json:"rows"`
+	Created int                 `json:"created"`
+	Updated int                 `json:"updated"`
+	Failed  int                 `json:"failed"`
+	Errors  []ImportRowErrorDTO `json:"errors"`
+	Changes []ImportChangeDTO   `json:"changes,omitempty"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type UnreadNotificationsCountDTO struct {
+	UnreadCount uint64 `json:"unread_count"`
+}
+
+type MarkAllNotificationsReadDTO struct {
+	Updated int64 `json:"updated"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "encoding/json"
+
+type NotificationOutboxItemDTO struct {
+	ID            uint64          `json:"id"`
+	UserID        *uint64         `json:"user_id"`
+	Channel       string          `json:"channel"`
+	ChatID        *int64          `json:"chat_id,omitempty"`
+	MessageType   string          `json:"message_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	MaxAttempts   int             `json:"max_attempts"`
+	NextAttemptAt string          `json:"next_attempt_at"`
+	LastError     *string         `json:"last_error"`
+	CreatedAt     string          `json:"created_at"`
+	UpdatedAt     string          `json:"updated_at"`
+}
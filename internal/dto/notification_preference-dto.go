@@ -0,0 +1,23 @@
+package dto
+
+// NotificationPreferenceDTO — настройки уведомлений текущего пользователя.
+// Тихие часы передаются в формате "HH:MM" в локальном времени сервера.
+type NotificationPreferenceDTO struct {
+	TelegramEnabled  bool     `json:"telegram_enabled"`
+	WebSocketEnabled bool     `json:"websocket_enabled"`
+	MutedEventTypes  []string `json:"muted_event_types"`
+	QuietHoursStart  *string  `json:"quiet_hours_start"`
+	QuietHoursEnd    *string  `json:"quiet_hours_end"`
+	DeliveryMode     string   `json:"delivery_mode"`
+	UpdatedAt        string   `json:"updated_at,omitempty"`
+}
+
+type UpdateNotificationPreferenceDTO struct {
+	TelegramEnabled   *bool     `json:"telegram_enabled,omitempty"`
+	WebSocketEnabled  *bool     `json:"websocket_enabled,omitempty"`
+	MutedEventTypes   *[]string `json:"muted_event_types,omitempty" validate:"omitempty,dive,required,max=50"`
+	QuietHoursStart   *string   `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd     *string   `json:"quiet_hours_end,omitempty"`
+	DisableQuietHours bool      `json:"disable_quiet_hours,omitempty"`
+	DeliveryMode      *string   `json:"delivery_mode,omitempty" validate:"omitempty,oneof=realtime digest"`
+}
@@ -0,0 +1,78 @@
+package dto
+
+import "time"
+
+// OnCallScheduleDTO — график дежурств вместе с очередью участников.
+type OnCallScheduleDTO struct {
+	ID             uint64            `json:"id"`
+	Name           string            `json:"name"`
+	DepartmentID   uint64            `json:"department_id"`
+	DepartmentName *string           `json:"department_name,omitempty"`
+	RotationStart  string            `json:"rotation_start"`
+	ShiftHours     int               `json:"shift_hours"`
+	IsActive       bool              `json:"is_active"`
+	Members        []OnCallMemberDTO `json:"members"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
+}
+
+type OnCallMemberDTO struct {
+	UserID   uint64 `json:"user_id"`
+	Fio      string `json:"fio"`
+	Position int    `json:"position"`
+}
+
+// CreateOnCallScheduleDTO — member_ids перечисляют дежурных в порядке ротации;
+// первая смена начинается в rotation_start.
+type CreateOnCallScheduleDTO struct {
+	Name          string    `json:"name" validate:"required,max=255"`
+	DepartmentID  uint64    `json:"department_id" validate:"required"`
+	RotationStart time.Time `json:"rotation_start" validate:"required"`
+	ShiftHours    int       `json:"shift_hours" validate:"required,min=1,max=720"`
+	IsActive      *bool     `json:"is_active"`
+	MemberIDs     []uint64  `json:"member_ids" validate:"required,min=1"`
+}
+
+// UpdateOnCallScheduleDTO — переданный member_ids заменяет очередь целиком.
+type UpdateOnCallScheduleDTO struct {
+	Name          *string    `json:"name" validate:"omitempty,max=255"`
+	DepartmentID  *uint64    `json:"department_id" validate:"omitempty,min=1"`
+	RotationStart *time.Time `json:"rotation_start"`
+	ShiftHours    *int       `json:"shift_hours" validate:"omitempty,min=1,max=720"`
+	IsActive      *bool      `json:"is_active"`
+	MemberIDs     []uint64   `json:"member_ids" validate:"omitempty,min=1"`
+}
+
+// OnCallOverrideDTO — замена в графике: в [starts_at, ends_at) дежурит user_id.
+type OnCallOverrideDTO struct {
+	ID         uint64  `json:"id"`
+	ScheduleID uint64  `json:"schedule_id"`
+	UserID     uint64  `json:"user_id"`
+	Fio        string  `json:"fio"`
+	StartsAt   string  `json:"starts_at"`
+	EndsAt     string  `json:"ends_at"`
+	Reason     *string `json:"reason,omitempty"`
+	CreatedBy  *uint64 `json:"created_by,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type CreateOnCallOverrideDTO struct {
+	UserID   uint64    `json:"user_id" validate:"required"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	Reason   *string   `json:"reason" validate:"omitempty,max=500"`
+}
+
+// OnCallNowDTO — кто дежурит по графику сейчас. source: rotation — очередной по ротации,
+// override — замена. Пустой user_id — в очереди графика нет участников.
+type OnCallNowDTO struct {
+	ScheduleID     uint64  `json:"schedule_id"`
+	ScheduleName   string  `json:"schedule_name"`
+	DepartmentID   uint64  `json:"department_id"`
+	DepartmentName *string `json:"department_name,omitempty"`
+	UserID         *uint64 `json:"user_id"`
+	Fio            *string `json:"fio,omitempty"`
+	Source         string  `json:"source,omitempty"`
+	Reason         *string `json:"reason,omitempty"`
+	Until          *string `json:"until,omitempty"`
+}
@@ -2,36 +2,82 @@ package dto
 
 import (
 	"time"
+
+	"request-system/pkg/sanitize"
 )
 
 type OrderResponseDTO struct {
-	ID              uint64                  `json:"id"`
-	Name            string                  `json:"name"`
-	OrderTypeID     *uint64                 `json:"order_type_id,omitempty"`
-	Address         *string                 `json:"address,omitempty"`
-	CreatorID       uint64                  `json:"creator_id"`
-	ExecutorID      *uint64                 `json:"executor_id,omitempty"`
-	DepartmentID    *uint64                 `json:"department_id"`
-	OtdelID         *uint64                 `json:"otdel_id,omitempty"`
-	BranchID        *uint64                 `json:"branch_id,omitempty"`
-	OfficeID        *uint64                 `json:"office_id,omitempty"`
-	EquipmentID     *uint64                 `json:"equipment_id,omitempty"`
-	EquipmentTypeID *uint64                 `json:"equipment_type_id,omitempty"`
-	StatusID        uint64                  `json:"status_id"`
-	PriorityID      *uint64                 `json:"priority_id,omitempty"`
-	Attachments     []AttachmentResponseDTO `json:"attachments"`
-	Duration        *time.Time              `json:"duration,omitempty"`
-	CreatorName     string                  `json:"creator_name"`
-	ExecutorName    *string                 `json:"executor_name,omitempty"`
-	CreatedAt       string                  `json:"created_at"`
-	UpdatedAt       string                  `json:"updated_at"`
-	CompletedAt     *time.Time              `json:"completed_at,omitempty"`
+	ID              uint64  `json:"id"`
+	Number          string  `json:"number"`
+	Name            string  `json:"name"`
+	OrderTypeID     *uint64 `json:"order_type_id,omitempty"`
+	Address         *string `json:"address,omitempty"`
+	CreatorID       uint64  `json:"creator_id"`
+	ExecutorID      *uint64 `json:"executor_id,omitempty"`
+	DepartmentID    *uint64 `json:"department_id"`
+	OtdelID         *uint64 `json:"otdel_id,omitempty"`
+	BranchID        *uint64 `json:"branch_id,omitempty"`
+	OfficeID        *uint64 `json:"office_id,omitempty"`
+	EquipmentID     *uint64 `json:"equipment_id,omitempty"`
+	EquipmentTypeID *uint64 `json:"equipment_type_id,omitempty"`
+	StatusID        uint64  `json:"status_id"`
+	PriorityID      *uint64 `json:"priority_id,omitempty"`
+	// Название, цвет и порядок приоритета — чтобы клиенты рисовали его одинаково без справочника
+	PriorityName      *string                 `json:"priority_name,omitempty"`
+	PriorityColor     *string                 `json:"priority_color,omitempty"`
+	PrioritySortOrder *int                    `json:"priority_sort_order,omitempty"`
+	Impact            *string                 `json:"impact,omitempty"`
+	Urgency           *string                 `json:"urgency,omitempty"`
+	Attachments       []AttachmentResponseDTO `json:"attachments"`
+	Duration          *time.Time              `json:"duration,omitempty"`
+	CreatorName       string                  `json:"creator_name"`
+	ExecutorName      *string                 `json:"executor_name,omitempty"`
+	TeamID            *uint64                 `json:"team_id,omitempty"`
+	TeamName          *string                 `json:"team_name,omitempty"`
+	CreatedAt         string                  `json:"created_at"`
+	UpdatedAt         string                  `json:"updated_at"`
+	CompletedAt       *time.Time              `json:"completed_at,omitempty"`
+	DuplicateOfID     *uint64                 `json:"duplicate_of_id,omitempty"`
+	ResolutionCodeID  *uint64                 `json:"resolution_code_id,omitempty"`
+	CustomFields      map[string]any          `json:"custom_fields"`
 
 	// Метрики (показатели)
 	ResolutionTimeSeconds      *uint64 `json:"resolution_time_seconds,omitempty"`
 	ResolutionTimeFormatted    string  `json:"resolution_time_formatted,omitempty"`
 	FirstResponseTimeSeconds   *uint64 `json:"first_response_time_seconds,omitempty"`
 	FirstResponseTimeFormatted string  `json:"first_response_time_formatted,omitempty"`
+
+	// Обратный отсчёт до срока первого отклика по приоритету — пока исполнитель не отреагировал
+	// и заявка не в финальном статусе. Секунды рабочие; после срока значение отрицательное
+	FirstResponseDueAt       *time.Time `json:"first_response_due_at,omitempty"`
+	FirstResponseSecondsLeft *int64     `json:"first_response_seconds_left,omitempty"`
+
+	// Только в списке заявок (из проекции order_list_view): название статуса, последний
+	// комментарий (до 200 символов) и число вложений — без отдельной загрузки вложений
+	StatusName       *string    `json:"status_name,omitempty"`
+	LastComment      *string    `json:"last_comment,omitempty"`
+	LastCommentAt    *time.Time `json:"last_comment_at,omitempty"`
+	AttachmentsCount *int       `json:"attachments_count,omitempty"`
+
+	// Заполняется только в ответе на создание: похожие свежие заявки по тому же оборудованию
+	PossibleDuplicates []OrderDuplicateCandidateDTO `json:"possible_duplicates,omitempty"`
+}
+
+// OrderDuplicateCandidateDTO — заявка, которая может оказаться дублем создаваемой.
+// Similarity — похожесть названий от 0 до 1.
+type OrderDuplicateCandidateDTO struct {
+	ID          uint64  `json:"id"`
+	Name        string  `json:"name"`
+	StatusID    uint64  `json:"status_id"`
+	CreatorName string  `json:"creator_name"`
+	CreatedAt   string  `json:"created_at"`
+	Similarity  float64 `json:"similarity"`
+}
+
+// MergeOrderDTO — закрыть заявку как дубликат TargetID. Comment попадает в историю обеих заявок.
+type MergeOrderDTO struct {
+	TargetID uint64  `json:"target_id" validate:"required"`
+	Comment  *string `json:"comment,omitempty"`
 }
 
 type CreateOrderDTO struct {
@@ -47,11 +93,19 @@ type CreateOrderDTO struct {
 	BranchID     *uint64 `json:"branch_id,omitempty"`
 	OfficeID     *uint64 `json:"office_id,omitempty"`
 
+	// Влияние и срочность (low/medium/high): по ним приоритет считается из матрицы.
+	// priority_id — ручной выбор, нужен order:create:priority_id
+	Impact  *string `json:"impact,omitempty" validate:"omitempty,oneof=low medium high"`
+	Urgency *string `json:"urgency,omitempty" validate:"omitempty,oneof=low medium high"`
+
 	// Специфика заявки
 	PriorityID      *uint64 `json:"priority_id,omitempty"`
 	ExecutorID      *uint64 `json:"executor_id,omitempty"`
 	EquipmentID     *uint64 `json:"equipment_id,omitempty"`
 	EquipmentTypeID *uint64 `json:"equipment_type_id,omitempty"`
+
+	// Дополнительные поля типа заявки: code → значение
+	CustomFields map[string]any `json:"custom_fields,omitempty"`
 }
 
 type UpdateOrderDTO struct {
@@ -70,9 +124,97 @@ type UpdateOrderDTO struct {
 	ExecutorID      *uint64 `json:"executor_id,omitempty"`
 	StatusID        *uint64 `json:"status_id,omitempty"`
 	PriorityID      *uint64 `json:"priority_id,omitempty"`
+	Impact          *string `json:"impact,omitempty" validate:"omitempty,oneof=low medium high"`
+	Urgency         *string `json:"urgency,omitempty" validate:"omitempty,oneof=low medium high"`
+	// Код решения из справочника; принимаются только действующие коды
+	ResolutionCodeID *uint64 `json:"resolution_code_id,omitempty"`
+
+	// Сливается с текущими значениями; null у ключа очищает поле
+	CustomFields map[string]any `json:"custom_fields,omitempty"`
+}
+
+// Sanitize чистит текстовые поля ввода: однострочные название и адрес, многострочный комментарий.
+func (d *CreateOrderDTO) Sanitize() {
+	d.Name = sanitize.Line(d.Name)
+	d.Address = sanitize.LinePtr(d.Address)
+	d.Comment = sanitize.TextPtr(d.Comment)
+}
+
+// Sanitize чистит текстовые поля и те же ключи merge patch: заявка обновляется по patch,
+// комментарий в историю берётся из DTO.
+func (d *UpdateOrderDTO) Sanitize(patch map[string]interface{}) {
+	d.Name = sanitize.LinePtr(d.Name)
+	d.Address = sanitize.LinePtr(d.Address)
+	d.Comment = sanitize.TextPtr(d.Comment)
+	for key, clean := range map[string]func(string) string{"name": sanitize.Line, "address": sanitize.Line, "comment": sanitize.Text} {
+		if value, ok := patch[key].(string); ok {
+			patch[key] = clean(value)
+		}
+	}
+}
+
+func (d *MergeOrderDTO) Sanitize() {
+	d.Comment = sanitize.TextPtr(d.Comment)
 }
 
 type OrderListResponseDTO struct {
 	List       []OrderResponseDTO `json:"list"`
 	TotalCount uint64             `json:"total_count"`
 }
+
+// OrderExportRowDTO — строка выгрузки списка заявок: вместо ID уже подставлены названия.
+type OrderExportRowDTO struct {
+	ID            uint64
+	Number        string
+	Name          string
+	Status        string
+	Priority      string
+	OrderType     string
+	Department    string
+	Otdel         string
+	Branch        string
+	Office        string
+	EquipmentType string
+	Equipment     string
+	Address       string
+	Creator       string
+	Executor      string
+	CreatedAt     time.Time
+	Duration      *time.Time
+	CompletedAt   *time.Time
+	// «Название: значение; ...» по дополнительным полям типа заявки
+	CustomFields string
+}
+
+// ExecutorSuggestionDTO — кандидат в исполнители заявки с показателями, по которым он ранжирован.
+type ExecutorSuggestionDTO struct {
+	UserID                 uint64  `json:"user_id"`
+	Fio                    string  `json:"fio"`
+	PositionName           *string `json:"position_name,omitempty"`
+	OpenOrders             int     `json:"open_orders"`
+	AvgResolutionSeconds   *uint64 `json:"avg_resolution_seconds,omitempty"`
+	AvgResolutionFormatted string  `json:"avg_resolution_formatted,omitempty"`
+	MatchedSkills          int     `json:"matched_skills"`
+	RequiredSkills         int     `json:"required_skills"`
+	Score                  float64 `json:"score"`
+	IsCurrentExecutor      bool    `json:"is_current_executor"`
+}
+
+// OrderAccessGrantDTO — разовый доступ сотрудника к заявке. IsActive = false, если срок истёк.
+type OrderAccessGrantDTO struct {
+	UserID       uint64  `json:"user_id"`
+	UserFio      string  `json:"user_fio"`
+	Level        string  `json:"level"`
+	GrantedBy    *uint64 `json:"granted_by,omitempty"`
+	GrantedByFio *string `json:"granted_by_fio,omitempty"`
+	ExpiresAt    *string `json:"expires_at,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+	IsActive     bool    `json:"is_active"`
+}
+
+// GrantOrderAccessDTO — level: view или edit; без expires_at доступ действует до отзыва.
+type GrantOrderAccessDTO struct {
+	UserID    uint64     `json:"user_id" validate:"required"`
+	Level     string     `json:"level" validate:"required,oneof=view edit"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -3,25 +3,43 @@
 package dto
 
 // CreateOrderTypeDTO используется для создания нового типа заявки.
+// Активность задаётся либо status_id, либо is_active; без них тип создаётся активным.
 type CreateOrderTypeDTO struct {
-	Name     string  `json:"name" validate:"required"`
-	Code     *string `json:"code" validate:"omitempty,uppercase,min=2"`
-	StatusID int     `json:"status_id" validate:"required"`
+	Name        string  `json:"name" validate:"required"`
+	Code        *string `json:"code" validate:"omitempty,uppercase,min=2"`
+	StatusID    int     `json:"status_id"`
+	IsActive    *bool   `json:"is_active"`
+	Icon        *string `json:"icon" validate:"omitempty,max=64"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	// Пустые списки — тип виден всем
+	DepartmentIDs []uint64 `json:"department_ids"`
+	BranchIDs     []uint64 `json:"branch_ids"`
 }
 
 // UpdateOrderTypeDTO используется для обновления существующего типа заявки.
+// department_ids и branch_ids заменяют списки целиком; не переданный список не меняется.
 type UpdateOrderTypeDTO struct {
-	Name     *string `json:"name,omitempty" validate:"omitempty,min=1"`
-	Code     *string `json:"code,omitempty" validate:"omitempty,uppercase"`
-	StatusID *int    `json:"status_id,omitempty"`
+	Name          *string   `json:"name,omitempty" validate:"omitempty,min=1"`
+	Code          *string   `json:"code,omitempty" validate:"omitempty,uppercase"`
+	StatusID      *int      `json:"status_id,omitempty"`
+	IsActive      *bool     `json:"is_active,omitempty"`
+	Icon          *string   `json:"icon,omitempty" validate:"omitempty,max=64"`
+	Description   *string   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	DepartmentIDs *[]uint64 `json:"department_ids,omitempty"`
+	BranchIDs     *[]uint64 `json:"branch_ids,omitempty"`
 }
 
 // OrderTypeResponseDTO используется для отправки данных о типе заявки клиенту.
 type OrderTypeResponseDTO struct {
-	ID        uint64 `json:"id"`
-	Name      string `json:"name"`
-	Code      string `json:"code,omitempty"`
-	StatusID  int    `json:"status_id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at,omitempty"`
+	ID            uint64   `json:"id"`
+	Name          string   `json:"name"`
+	Code          string   `json:"code,omitempty"`
+	StatusID      int      `json:"status_id"`
+	IsActive      bool     `json:"is_active"`
+	Icon          *string  `json:"icon"`
+	Description   *string  `json:"description"`
+	DepartmentIDs []uint64 `json:"department_ids"`
+	BranchIDs     []uint64 `json:"branch_ids"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at,omitempty"`
 }
@@ -0,0 +1,28 @@
+package dto
+
+// OrderTypeValidationRuleDTO — обязательное поле типа заявки.
+type OrderTypeValidationRuleDTO struct {
+	ID           uint64 `json:"id"`
+	OrderTypeID  uint64 `json:"order_type_id"`
+	FieldName    string `json:"field_name"`
+	ErrorMessage string `json:"error_message"`
+	OnCreate     bool   `json:"on_create"`
+	OnUpdate     bool   `json:"on_update"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// CreateOrderTypeValidationRuleDTO — on_create по умолчанию true; хотя бы один этап должен быть включён.
+type CreateOrderTypeValidationRuleDTO struct {
+	FieldName    string `json:"field_name" validate:"required,max=64"`
+	ErrorMessage string `json:"error_message" validate:"required,max=255"`
+	OnCreate     *bool  `json:"on_create"`
+	OnUpdate     bool   `json:"on_update"`
+}
+
+// UpdateOrderTypeValidationRuleDTO — поле правила не меняется: для другого поля создаётся новое правило.
+type UpdateOrderTypeValidationRuleDTO struct {
+	ErrorMessage *string `json:"error_message" validate:"omitempty,max=255"`
+	OnCreate     *bool   `json:"on_create"`
+	OnUpdate     *bool   `json:"on_update"`
+}
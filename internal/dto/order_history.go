@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // TimelineEventDTO - структура ответа для timeline (с Role для UI/отчётов)
 type TimelineEventDTO struct {
 	Lines      []string               `json:"lines"`                // Список строк события
@@ -19,3 +21,20 @@ type CreateOrderHistoryDTO struct {
 	Comment      *string `json:"comment,omitempty"`              // Комментарий
 	AttachmentID *uint64 `json:"attachment_id,omitempty"`        // ID вложения
 }
+
+// OrderHistoryEntryDTO — одна запись истории с уже разрешёнными названиями:
+// клиенту не нужно разбирать old_value/new_value самостоятельно.
+type OrderHistoryEntryDTO struct {
+	ID         uint64                 `json:"id"`
+	EventType  string                 `json:"event_type"`
+	Actor      ShortUserDTO           `json:"actor"`
+	CreatedAt  time.Time              `json:"created_at"`
+	Field      string                 `json:"field,omitempty"`     // Изменённое поле (status, priority, executor, ...)
+	OldValue   *string                `json:"old_value,omitempty"` // Сырое значение, как в истории
+	NewValue   *string                `json:"new_value,omitempty"`
+	OldLabel   *string                `json:"old_label,omitempty"` // Название вместо ID
+	NewLabel   *string                `json:"new_label,omitempty"`
+	Diff       string                 `json:"diff"` // Готовая строка изменения
+	Comment    *string                `json:"comment,omitempty"`
+	Attachment *AttachmentResponseDTO `json:"attachment,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// OrderHistoryChainDTO — результат проверки цепочки хэшей истории заявки.
+// Legacy — записи, созданные до включения цепочки: их содержимое проверить нельзя.
+type OrderHistoryChainDTO struct {
+	OrderID       uint64  `json:"order_id"`
+	Valid         bool    `json:"valid"`
+	Checked       int     `json:"checked"`
+	Legacy        int     `json:"legacy"`
+	HeadHash      string  `json:"head_hash,omitempty"`
+	FirstBrokenID *uint64 `json:"first_broken_id,omitempty"`
+	Reason        string  `json:"reason,omitempty"`
+}
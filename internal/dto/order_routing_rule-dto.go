@@ -12,8 +12,12 @@ type CreateOrderRoutingRuleDTO struct {
 	OtdelID      *int   `json:"otdel_id"`
 	BranchID     *int   `json:"branch_id"`
 	OfficeID     *int   `json:"office_id"`
-	PositionType string `json:"position_type" validate:"required"`
-	StatusID     int    `json:"status_id" validate:"required"`
+	PositionType string `json:"position_type" validate:"required_without_all=TeamID OnCallScheduleID"`
+	// Заявки уходят в очередь команды; position_type тогда необязателен и служит запасным вариантом
+	TeamID *int `json:"team_id"`
+	// Заявки назначаются текущему дежурному графика; команда и должность — запасные варианты
+	OnCallScheduleID *int `json:"on_call_schedule_id"`
+	StatusID         int  `json:"status_id" validate:"required"`
 }
 
 type UpdateOrderRoutingRuleDTO struct {
@@ -24,7 +28,10 @@ type UpdateOrderRoutingRuleDTO struct {
 	BranchID     null.Int    `json:"branch_id,omitempty"`
 	OfficeID     null.Int    `json:"office_id,omitempty"`
 	PositionType null.String `json:"position_type,omitempty"`
+	TeamID       null.Int    `json:"team_id"`
 	StatusID     null.Int    `json:"status_id,omitempty"`
+
+	OnCallScheduleID null.Int `json:"on_call_schedule_id"`
 }
 
 type OrderRoutingRuleResponseDTO struct {
@@ -38,8 +45,13 @@ type OrderRoutingRuleResponseDTO struct {
 	PositionID       *int     `json:"position_id,omitempty"`
 	PositionType     string   `json:"position_type,omitempty"`
 	PositionTypeName string   `json:"position_type_name,omitempty"`
+	TeamID           *int     `json:"team_id,omitempty"`
+	TeamName         *string  `json:"team_name,omitempty"`
 	RequiredFields   []string `json:"required_fields,omitempty"`
 	StatusID         int      `json:"status_id"`
 	CreatedAt        string   `json:"created_at"`
 	UpdatedAt        string   `json:"updated_at,omitempty"`
+
+	OnCallScheduleID   *int    `json:"on_call_schedule_id,omitempty"`
+	OnCallScheduleName *string `json:"on_call_schedule_name,omitempty"`
 }
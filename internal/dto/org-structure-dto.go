@@ -0,0 +1,23 @@
+package dto
+
+// OrgStructureDTO — оргструктура целиком: департаменты с отделами и филиалы с офисами.
+type OrgStructureDTO struct {
+	Departments []*OrgNodeDTO `json:"departments"`
+	Branches    []*OrgNodeDTO `json:"branches"`
+}
+
+// OrgNodeDTO — узел дерева. type: department, otdel, branch или office.
+type OrgNodeDTO struct {
+	ID           uint64        `json:"id"`
+	Type         string        `json:"type"`
+	Name         string        `json:"name"`
+	StatusID     *uint64       `json:"status_id,omitempty"`
+	Head         *OrgHeadDTO   `json:"head,omitempty"`
+	ActiveOrders int           `json:"active_orders"`
+	Children     []*OrgNodeDTO `json:"children"`
+}
+
+type OrgHeadDTO struct {
+	UserID uint64 `json:"user_id"`
+	Fio    string `json:"fio"`
+}
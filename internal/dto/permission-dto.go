@@ -24,6 +24,18 @@ type UpdatePermissionDTO struct {
 	Description string `json:"description" validate:"omitempty"`
 }
 
+// FlushPermissionsCacheDTO — чей кэш прав сбросить; пустой запрос сбрасывает кэш всем.
+type FlushPermissionsCacheDTO struct {
+	UserIDs []uint64 `json:"user_ids" validate:"omitempty,dive,gte=1"`
+	RoleIDs []uint64 `json:"role_ids" validate:"omitempty,dive,gte=1"`
+}
+
+type FlushPermissionsCacheResultDTO struct {
+	All bool `json:"all"`
+	// Сколько пользователей затронуто; при сбросе всем — 0
+	Users int `json:"users"`
+}
+
 type PermissionListResponseDTO struct {
 	List       []PermissionDTO `json:"list"`
 	TotalCount int64           `json:"total_count"`
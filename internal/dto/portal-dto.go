@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// PortalCaptchaDTO — проверка для формы портала. Для builtin заполнены CaptchaID и Image (SVG),
+// для внешних провайдеров фронтенд рисует виджет по SiteKey и присылает его токен.
+type PortalCaptchaDTO struct {
+	Provider  string `json:"provider"`
+	SiteKey   string `json:"site_key,omitempty"`
+	CaptchaID string `json:"captcha_id,omitempty"`
+	Image     string `json:"image,omitempty"`
+	ExpiresIn int    `json:"expires_in,omitempty"`
+}
+
+// PortalSubmitDTO — обращение клиента с публичного портала.
+type PortalSubmitDTO struct {
+	Name        string  `json:"name" validate:"required,max=150"`
+	Phone       string  `json:"phone" validate:"required,max=32"`
+	Description string  `json:"description" validate:"required,min=10,max=2000"`
+	BranchID    *uint64 `json:"branch_id,omitempty" validate:"omitempty,gt=0"`
+
+	CaptchaID     string `json:"captcha_id,omitempty"`
+	CaptchaAnswer string `json:"captcha_answer,omitempty"`
+	CaptchaToken  string `json:"captcha_token,omitempty"`
+}
+
+// PortalRequestStatusDTO — что клиент видит по коду отслеживания.
+type PortalRequestStatusDTO struct {
+	TrackingCode string     `json:"tracking_code"`
+	Status       string     `json:"status"`
+	StatusCode   string     `json:"status_code"`
+	IsClosed     bool       `json:"is_closed"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
+}
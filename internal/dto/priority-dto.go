@@ -1,27 +1,74 @@
 package dto
 
+// CreatePriorityDTO — Color в формате #RRGGBB; SLAHours — подсказка клиентам, какой срок
+// выполнения предложить для заявки с этим приоритетом; FirstResponseMinutes — срок первого
+// отклика исполнителя в рабочих минутах.
 type CreatePriorityDTO struct {
-	Name string `json:"name" validate:"required,max=50"`
-	Code string `json:"code" validate:"omitempty,uppercase"`
-	Rate int    `json:"rate" validate:"omitempty,gte=0"`
+	Name      string  `json:"name" validate:"required,max=50"`
+	Code      string  `json:"code" validate:"omitempty,uppercase"`
+	Rate      int     `json:"rate" validate:"omitempty,gte=0"`
+	Color     *string `json:"color,omitempty"`
+	SortOrder int     `json:"sort_order" validate:"omitempty,gte=0"`
+	SLAHours  *int    `json:"sla_hours,omitempty" validate:"omitempty,gt=0"`
+
+	FirstResponseMinutes *int `json:"first_response_minutes,omitempty" validate:"omitempty,gt=0"`
 }
 
+// UpdatePriorityDTO — пустой Color, SLAHours = 0 и FirstResponseMinutes = 0 очищают
+// соответствующее поле.
 type UpdatePriorityDTO struct {
-	Code *string `json:"code,omitempty" validate:"omitempty"`
-	Name *string `json:"name,omitempty" validate:"omitempty,max=50"`
-	Rate *int    `json:"rate,omitempty"`
+	Code      *string `json:"code,omitempty" validate:"omitempty"`
+	Name      *string `json:"name,omitempty" validate:"omitempty,max=50"`
+	Rate      *int    `json:"rate,omitempty"`
+	Color     *string `json:"color,omitempty"`
+	SortOrder *int    `json:"sort_order,omitempty" validate:"omitempty,gte=0"`
+	SLAHours  *int    `json:"sla_hours,omitempty" validate:"omitempty,gte=0"`
+
+	FirstResponseMinutes *int `json:"first_response_minutes,omitempty" validate:"omitempty,gte=0"`
 }
 
 type PriorityDTO struct {
-	ID        uint64 `json:"id"`
-	Name      string `json:"name"`
-	Code      string `json:"code"`
-	Rate      int    `json:"rate"`
+	ID        uint64  `json:"id"`
+	Name      string  `json:"name"`
+	Code      string  `json:"code"`
+	Rate      int     `json:"rate"`
+	Color     *string `json:"color"`
+	SortOrder int     `json:"sort_order"`
+	SLAHours  *int    `json:"sla_hours"`
+	// Protected — на код опираются дашборд и коннекторы чатов: код не меняется, приоритет не удаляется.
+	Protected bool   `json:"protected"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+
+	FirstResponseMinutes *int `json:"first_response_minutes"`
 }
 
 type ShortPriorityDTO struct {
 	ID   uint64 `json:"id"`
 	Name string `json:"name"`
 }
+
+// PriorityMatrixDTO — матрица «влияние × срочность» → приоритет. Levels — допустимые уровни
+// по возрастанию, Cells — по одной клетке на каждое сочетание.
+type PriorityMatrixDTO struct {
+	Levels []string                `json:"levels"`
+	Cells  []PriorityMatrixCellDTO `json:"cells"`
+}
+
+type PriorityMatrixCellDTO struct {
+	Impact       string `json:"impact"`
+	Urgency      string `json:"urgency"`
+	PriorityID   uint64 `json:"priority_id"`
+	PriorityName string `json:"priority_name,omitempty"`
+}
+
+// UpdatePriorityMatrixDTO — матрица задаётся целиком, все сочетания уровней.
+type UpdatePriorityMatrixDTO struct {
+	Cells []UpdatePriorityMatrixCellDTO `json:"cells" validate:"required,dive"`
+}
+
+type UpdatePriorityMatrixCellDTO struct {
+	Impact     string `json:"impact" validate:"required,oneof=low medium high"`
+	Urgency    string `json:"urgency" validate:"required,oneof=low medium high"`
+	PriorityID uint64 `json:"priority_id" validate:"required"`
+}
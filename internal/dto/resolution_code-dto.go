@@ -0,0 +1,45 @@
+package dto
+
+type ResolutionCodeDTO struct {
+	ID        uint64 `json:"id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	SortOrder int    `json:"sort_order"`
+	IsActive  bool   `json:"is_active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type CreateResolutionCodeDTO struct {
+	Code      string `json:"code" validate:"required,max=64"`
+	Name      string `json:"name" validate:"required,max=255"`
+	SortOrder int    `json:"sort_order"`
+	// По умолчанию код сразу действует
+	IsActive *bool `json:"is_active"`
+}
+
+// UpdateResolutionCodeDTO меняет только переданные поля; is_active=false убирает код из выбора,
+// не трогая заявки, где он уже указан.
+type UpdateResolutionCodeDTO struct {
+	Code      *string `json:"code" validate:"omitempty,max=64"`
+	Name      *string `json:"name" validate:"omitempty,max=255"`
+	SortOrder *int    `json:"sort_order"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+// ResolutionCodeStatsDTO — заявки, выполненные за период, по кодам решения: частые первыми.
+type ResolutionCodeStatsDTO struct {
+	From        string                  `json:"from"`
+	To          string                  `json:"to"`
+	Total       uint64                  `json:"total"`
+	WithoutCode uint64                  `json:"without_code"`
+	Codes       []ResolutionCodeStatDTO `json:"codes"`
+}
+
+type ResolutionCodeStatDTO struct {
+	ID     uint64  `json:"id"`
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Orders uint64  `json:"orders"`
+	Share  float64 `json:"share"`
+}
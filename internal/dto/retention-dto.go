@@ -0,0 +1,85 @@
+package dto
+
+type CreateRetentionRuleDTO struct {
+	Name                string `json:"name" validate:"required,max=255"`
+	Action              string `json:"action" validate:"required,oneof=purge_attachments compact_history"`
+	ClosedOlderThanDays int    `json:"closed_older_than_days" validate:"required,min=1,max=36500"`
+	IsActive            *bool  `json:"is_active,omitempty"`
+}
+
+type UpdateRetentionRuleDTO struct {
+	Name                *string `json:"name,omitempty" validate:"omitempty,max=255"`
+	Action              *string `json:"action,omitempty" validate:"omitempty,oneof=purge_attachments compact_history"`
+	ClosedOlderThanDays *int    `json:"closed_older_than_days,omitempty" validate:"omitempty,min=1,max=36500"`
+	IsActive            *bool   `json:"is_active,omitempty"`
+}
+
+type RetentionRuleDTO struct {
+	ID                  uint64  `json:"id"`
+	Name                string  `json:"name"`
+	Action              string  `json:"action"`
+	ClosedOlderThanDays int     `json:"closed_older_than_days"`
+	IsActive            bool    `json:"is_active"`
+	CreatedBy           *uint64 `json:"created_by"`
+	CreatedAt           string  `json:"created_at"`
+	UpdatedAt           string  `json:"updated_at"`
+}
+
+// CreateRetentionHoldDTO — юридическое удержание заявки: правила хранения её не трогают.
+type CreateRetentionHoldDTO struct {
+	OrderID uint64 `json:"order_id" validate:"required"`
+	Reason  string `json:"reason" validate:"required,max=2000"`
+}
+
+type RetentionHoldDTO struct {
+	OrderID   uint64  `json:"order_id"`
+	Reason    string  `json:"reason"`
+	CreatedBy *uint64 `json:"created_by"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// RetentionOrderResultDTO — что правило удалило (или удалит при пробном запуске) у одной заявки.
+type RetentionOrderResultDTO struct {
+	OrderID     uint64 `json:"order_id"`
+	Attachments int    `json:"attachments,omitempty"`
+	Bytes       int64  `json:"bytes,omitempty"`
+	HistoryRows int64  `json:"history_rows,omitempty"`
+	// PrevHistoryHash — хэш цепочки истории до пересчёта: удаление меняет записи истории.
+	PrevHistoryHash string `json:"prev_history_hash,omitempty"`
+	// ChainValid — цепочка истории была целой до изменения. Нарушенная цепочка не пересчитывается,
+	// ChainBroken описывает первую испорченную запись.
+	ChainValid  bool   `json:"chain_valid"`
+	ChainBroken string `json:"chain_broken,omitempty"`
+}
+
+// RetentionRuleReportDTO — итог одного правила в запуске. Items перечисляет заявки
+// (не больше первой тысячи), итоговые счётчики считаются по всем.
+type RetentionRuleReportDTO struct {
+	RuleID       uint64                    `json:"rule_id"`
+	Name         string                    `json:"name"`
+	Action       string                    `json:"action"`
+	ClosedBefore string                    `json:"closed_before"`
+	Orders       int                       `json:"orders"`
+	Attachments  int                       `json:"attachments"`
+	Bytes        int64                     `json:"bytes"`
+	HistoryRows  int64                     `json:"history_rows"`
+	HeldOrders   int64                     `json:"held_orders"`
+	Failed       int                       `json:"failed"`
+	Errors       []string                  `json:"errors,omitempty"`
+	Items        []RetentionOrderResultDTO `json:"items"`
+	Truncated    bool                      `json:"truncated,omitempty"`
+
+	// BrokenChainOrders — заявки с нарушенной цепочкой истории, по всем заявкам правила.
+	BrokenChainOrders []uint64 `json:"broken_chain_orders,omitempty"`
+}
+
+type RetentionRunDTO struct {
+	ID          uint64                   `json:"id"`
+	DryRun      bool                     `json:"dry_run"`
+	Status      string                   `json:"status"`
+	TriggeredBy *uint64                  `json:"triggered_by"`
+	Report      []RetentionRuleReportDTO `json:"report,omitempty"`
+	Error       *string                  `json:"error,omitempty"`
+	StartedAt   string                   `json:"started_at"`
+	FinishedAt  *string                  `json:"finished_at"`
+}
@@ -1,12 +1,18 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"request-system/internal/authz"
+)
 
 type CreateRoleDTO struct {
 	Name          string   `json:"name" validate:"required,max=50"`
 	Description   string   `json:"description" validate:"omitempty,max=255"`
 	StatusID      *uint64  `json:"status_id" validate:"omitempty,gte=1"`
 	PermissionIDs []uint64 `json:"permissions" validate:"omitempty,dive,gte=1"`
+	// Условия (ABAC) по ID привилегии; привилегия должна входить в permissions
+	PermissionConditions map[uint64][]authz.Condition `json:"permission_conditions"`
 }
 
 type UpdateRoleDTO struct {
@@ -14,16 +20,20 @@ type UpdateRoleDTO struct {
 	Description   *string   `json:"description" validate:"omitempty,max=255"`
 	StatusID      *uint64   `json:"status_id" validate:"omitempty,gte=1"`
 	PermissionIDs *[]uint64 `json:"permissions" validate:"omitempty,dive,gte=1"`
+	// Заменяет условия перечисленных привилегий ([] или null снимает их); остальные не меняются
+	PermissionConditions map[uint64][]authz.Condition `json:"permission_conditions"`
 }
 
 type RoleDTO struct {
-	ID          uint64    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	StatusID    uint64    `json:"status_id"`
-	Permissions []uint64  `json:"permissions"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint64   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	StatusID    uint64   `json:"status_id"`
+	Permissions []uint64 `json:"permissions"`
+	// Условия прав роли; заполняется только при чтении одной роли
+	PermissionConditions map[uint64][]authz.Condition `json:"permission_conditions,omitempty"`
+	CreatedAt            time.Time                    `json:"created_at"`
+	UpdatedAt            time.Time                    `json:"updated_at"`
 }
 type ShortRoleDTO struct {
 	ID   uint64 `json:"id"`
@@ -0,0 +1,19 @@
+package dto
+
+// RuntimeConfigDTO — настройки, которые меняются без перезапуска, по именам переменных окружения.
+type RuntimeConfigDTO struct {
+	Settings map[string]string `json:"settings"`
+	// WatchIntervalSeconds — как часто сервер сам проверяет .env; 0 — только по запросу
+	WatchIntervalSeconds int `json:"watch_interval_seconds"`
+}
+
+type RuntimeConfigChangeDTO struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// RuntimeConfigReloadDTO — результат перечитывания: действующие настройки и что изменилось.
+type RuntimeConfigReloadDTO struct {
+	Settings map[string]string                 `json:"settings"`
+	Changed  map[string]RuntimeConfigChangeDTO `json:"changed"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// SchemaVersionDTO — версия схемы БД и состояние миграций.
+type SchemaVersionDTO struct {
+	CurrentVersion int64                `json:"current_version"`
+	LatestVersion  int64                `json:"latest_version"`
+	UpToDate       bool                 `json:"up_to_date"`
+	AutoMigrate    bool                 `json:"auto_migrate"`
+	Pending        []SchemaMigrationDTO `json:"pending"`
+	LastApplied    *SchemaMigrationDTO  `json:"last_applied,omitempty"`
+}
+
+type SchemaMigrationDTO struct {
+	Version   int64      `json:"version"`
+	File      string     `json:"file"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type SkillDTO struct {
+	ID        uint64 `json:"id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+}
+
+type CreateSkillDTO struct {
+	Code string `json:"code" validate:"required,max=64"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// SetSkillsDTO заменяет набор навыков целиком; пустой список снимает все навыки.
+type SetSkillsDTO struct {
+	SkillIDs []uint64 `json:"skill_ids"`
+}
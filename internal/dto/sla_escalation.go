@@ -0,0 +1,25 @@
+package dto
+
+// SLAEscalationSettingDTO — действующий порог эскалации департамента. is_default=true —
+// своего порога у департамента нет и действуют значения по умолчанию.
+type SLAEscalationSettingDTO struct {
+	DepartmentID    uint64  `json:"department_id"`
+	BreachThreshold int     `json:"breach_threshold"`
+	WindowHours     int     `json:"window_hours"`
+	IsEnabled       bool    `json:"is_enabled"`
+	IsDefault       bool    `json:"is_default"`
+	UpdatedAt       *string `json:"updated_at,omitempty"`
+}
+
+// SLAEscalationSettingsDTO — значения по умолчанию и департаменты со своими порогами.
+type SLAEscalationSettingsDTO struct {
+	Default     SLAEscalationSettingDTO   `json:"default"`
+	Departments []SLAEscalationSettingDTO `json:"departments"`
+}
+
+type UpdateSLAEscalationSettingDTO struct {
+	BreachThreshold int `json:"breach_threshold" validate:"required,min=1,max=1000"`
+	WindowHours     int `json:"window_hours" validate:"required,min=1,max=8784"`
+	// По умолчанию эскалация включена; false выключает её для департамента
+	IsEnabled *bool `json:"is_enabled"`
+}
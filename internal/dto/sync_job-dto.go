@@ -0,0 +1,79 @@
+package dto
+
+// SyncEntityStatsDTO — прогресс синхронизации одного справочника.
+type SyncEntityStatsDTO struct {
+	Total     int `json:"total"`
+	Processed int `json:"processed"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Failed    int `json:"failed"`
+	// Deactivated — сколько активных записей выгрузка переводит в неактивные.
+	Deactivated int     `json:"deactivated"`
+	Error       *string `json:"error,omitempty"`
+}
+
+// SyncRecordErrorDTO — ошибка конкретной записи выгрузки. Index — позиция записи в массиве справочника.
+type SyncRecordErrorDTO struct {
+	Entity     string `json:"entity"`
+	Index      int    `json:"index"`
+	ExternalID string `json:"external_id,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+type SyncJobSummaryDTO struct {
+	Total       int `json:"total"`
+	Processed   int `json:"processed"`
+	Created     int `json:"created"`
+	Updated     int `json:"updated"`
+	Failed      int `json:"failed"`
+	Deactivated int `json:"deactivated"`
+	Errors      int `json:"errors"`
+	// Changes — число записей с изменениями; заполняется только для dry run.
+	Changes int `json:"changes"`
+}
+
+// SyncFieldChangeDTO — значение поля до и после синхронизации (null — поле пустое).
+type SyncFieldChangeDTO struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// SyncChangeDTO — что dry run изменил бы в записи: action create|update, поля с новыми значениями.
+type SyncChangeDTO struct {
+	Entity     string                        `json:"entity"`
+	Index      int                           `json:"index"`
+	ExternalID string                        `json:"external_id,omitempty"`
+	Action     string                        `json:"action"`
+	Fields     map[string]SyncFieldChangeDTO `json:"fields"`
+}
+
+// SyncDeactivatedDTO — запись 1С, которой нет в полной выгрузке; переведена в INACTIVE.
+type SyncDeactivatedDTO struct {
+	Entity     string `json:"entity"`
+	ID         uint64 `json:"id"`
+	ExternalID string `json:"external_id"`
+	Name       string `json:"name"`
+}
+
+// SyncJobDTO — задание синхронизации: status queued|running|completed|failed. Задание с dry_run
+// ничего не сохраняет, а в changes показывает, что изменила бы синхронизация. Задание со snapshot
+// деактивирует записи 1С, которых нет в выгрузке, и перечисляет их в deactivated. Задание с
+// best_effort пропускает ошибочные записи вместо отката всего справочника.
+type SyncJobDTO struct {
+	ID          uint64                        `json:"id"`
+	Source      string                        `json:"source"`
+	Status      string                        `json:"status"`
+	DryRun      bool                          `json:"dry_run"`
+	Snapshot    bool                          `json:"snapshot"`
+	BestEffort  bool                          `json:"best_effort"`
+	Progress    map[string]SyncEntityStatsDTO `json:"progress"`
+	Summary     SyncJobSummaryDTO             `json:"summary"`
+	Errors      []SyncRecordErrorDTO          `json:"errors"`
+	Changes     []SyncChangeDTO               `json:"changes,omitempty"`
+	Deactivated []SyncDeactivatedDTO          `json:"deactivated,omitempty"`
+	Error       *string                       `json:"error,omitempty"`
+	CreatedAt   string                        `json:"created_at"`
+	StartedAt   *string                       `json:"started_at,omitempty"`
+	FinishedAt  *string                       `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+// TeamDTO — команда исполнителей.
+type TeamDTO struct {
+	ID          uint64  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	LeadID      *uint64 `json:"lead_id,omitempty"`
+	LeadName    *string `json:"lead_name,omitempty"`
+	MemberCount int     `json:"member_count"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// TeamDetailsDTO — команда вместе со списком участников.
+type TeamDetailsDTO struct {
+	TeamDTO
+	Members []TeamMemberDTO `json:"members"`
+}
+
+type TeamMemberDTO struct {
+	UserID   uint64 `json:"user_id"`
+	Fio      string `json:"fio"`
+	Email    string `json:"email,omitempty"`
+	IsLead   bool   `json:"is_lead"`
+	JoinedAt string `json:"joined_at"`
+}
+
+// CreateTeamDTO — руководитель автоматически становится участником команды.
+type CreateTeamDTO struct {
+	Name        string   `json:"name" validate:"required,max=255"`
+	Description *string  `json:"description"`
+	LeadID      *uint64  `json:"lead_id"`
+	MemberIDs   []uint64 `json:"member_ids"`
+}
+
+// UpdateTeamDTO — lead_id = 0 снимает руководителя.
+type UpdateTeamDTO struct {
+	Name        *string `json:"name" validate:"omitempty,max=255"`
+	Description *string `json:"description"`
+	LeadID      *uint64 `json:"lead_id"`
+}
+
+type TeamMembersDTO struct {
+	UserIDs []uint64 `json:"user_ids" validate:"required,min=1"`
+}
@@ -92,6 +92,28 @@ func (s *TelegramState) GetExecutorID() (uint64, bool, error) {
 	return id, true, nil
 }
 
+// SetResolutionCodeID запоминает код решения, выбранный при закрытии заявки.
+func (s *TelegramState) SetResolutionCodeID(id uint64) {
+	s.Changes["resolution_code_id"] = strconv.FormatUint(id, 10)
+}
+
+func (s *TelegramState) GetResolutionCodeID() (uint64, bool, error) {
+	val, ok := s.Changes["resolution_code_id"]
+	if !ok || val == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid resolution_code_id format: %w", err)
+	}
+	return id, true, nil
+}
+
+// ClearResolutionCodeID — код решения больше не нужен: выбран статус, который не закрывает заявку.
+func (s *TelegramState) ClearResolutionCodeID() {
+	delete(s.Changes, "resolution_code_id")
+}
+
 func (s *TelegramState) SetComment(comment string) {
 	s.Changes["comment"] = comment
 }
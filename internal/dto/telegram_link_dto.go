@@ -1,12 +1,23 @@
 package dto
 
+import "time"
+
 type TelegramLinkStatusDTO struct {
-	Linked         bool   `json:"linked"`
-	TelegramChatID *int64 `json:"telegram_chat_id,omitempty"`
+	Linked            bool       `json:"linked"`
+	TelegramChatID    *int64     `json:"telegram_chat_id,omitempty"`
+	LastInteractionAt *time.Time `json:"last_interaction_at,omitempty"`
+}
+
+// TelegramChannelDTO — состояние привязки бота в профиле: есть ли чат и когда
+// пользователь последний раз писал боту или нажимал кнопку.
+type TelegramChannelDTO struct {
+	Linked            bool       `json:"linked"`
+	LastInteractionAt *time.Time `json:"last_interaction_at,omitempty"`
 }
 
 type TelegramLinkTokenDTO struct {
-	Token            string `json:"token"`
-	ShortCode        string `json:"short_code"`
-	ExpiresInSeconds int    `json:"expires_in_seconds"`
+	Token            string    `json:"token"`
+	ShortCode        string    `json:"short_code"`
+	ExpiresInSeconds int       `json:"expires_in_seconds"`
+	ExpiresAt        time.Time `json:"expires_at"`
 }
@@ -75,6 +75,8 @@ type UserDTO struct {
 	MustChangePassword bool     `json:"must_change_password"`
 	IsHead             bool     `json:"is_head"`
 
+	Telegram TelegramChannelDTO `json:"telegram"`
+
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -0,0 +1,11 @@
+package dto
+
+type UpdateUserLanguageDTO struct {
+	Language string `json:"language" validate:"required,oneof=ru tg en"`
+}
+
+// UserLanguageDTO — язык интерфейса пользователя и языки, из которых можно выбрать.
+type UserLanguageDTO struct {
+	Language  string            `json:"language"`
+	Supported map[string]string `json:"supported"`
+}
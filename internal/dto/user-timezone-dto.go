@@ -0,0 +1,12 @@
+package dto
+
+// UpdateUserTimezoneDTO — пояс IANA ("Asia/Dushanbe"); null возвращает пояс сервера.
+type UpdateUserTimezoneDTO struct {
+	Timezone *string `json:"timezone" validate:"omitempty,max=64"`
+}
+
+type UserTimezoneDTO struct {
+	Timezone *string `json:"timezone"`
+	// Effective — пояс, в котором пользователю показываются даты (свой или пояс сервера).
+	Effective string `json:"effective"`
+}
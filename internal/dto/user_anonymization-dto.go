@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// AnonymizeUserDTO — подтверждение обезличивания: Confirm должен совпасть с текущим ФИО пользователя.
+type AnonymizeUserDTO struct {
+	Confirm string `json:"confirm"`
+}
+
+// UserAnonymizationDTO — что обезличивание затронуло (или затронет при dry_run). ФИО отдаётся только
+// в предпросмотре — для подтверждения.
+type UserAnonymizationDTO struct {
+	UserID           uint64     `json:"user_id"`
+	DryRun           bool       `json:"dry_run"`
+	Fio              string     `json:"fio,omitempty"`
+	Label            string     `json:"label"`
+	HistoryRows      int64      `json:"history_rows"`
+	Orders           int        `json:"orders"`
+	PhotoRemoved     bool       `json:"photo_removed"`
+	TelegramUnlinked bool       `json:"telegram_unlinked"`
+	AnonymizedAt     *time.Time `json:"anonymized_at,omitempty"`
+}
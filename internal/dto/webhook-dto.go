@@ -0,0 +1,62 @@
+package dto
+
+import "encoding/json"
+
+type CreateWebhookSubscriptionDTO struct {
+	Name       string   `json:"name" validate:"required,max=255"`
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	Secret     string   `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required"`
+	IsActive   *bool    `json:"is_active,omitempty"`
+}
+
+type UpdateWebhookSubscriptionDTO struct {
+	Name         *string   `json:"name,omitempty" validate:"omitempty,max=255"`
+	URL          *string   `json:"url,omitempty" validate:"omitempty,http_url,max=2048"`
+	EventTypes   *[]string `json:"event_types,omitempty" validate:"omitempty,min=1,dive,required"`
+	IsActive     *bool     `json:"is_active,omitempty"`
+	RotateSecret bool      `json:"rotate_secret,omitempty"`
+}
+
+// WebhookSubscriptionDTO — секрет отдаётся только при создании и ротации.
+type WebhookSubscriptionDTO struct {
+	ID         uint64   `json:"id"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+	CreatedBy  *uint64  `json:"created_by"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type WebhookDeliveryDTO struct {
+	ID             uint64          `json:"id"`
+	SubscriptionID uint64          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"max_attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    *string         `json:"delivered_at"`
+}
+
+type WebhookDeliveryAttemptDTO struct {
+	Attempt      int     `json:"attempt"`
+	StatusCode   *int    `json:"status_code"`
+	Error        *string `json:"error"`
+	ResponseBody *string `json:"response_body"`
+	DurationMs   int     `json:"duration_ms"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type WebhookDeliveryDetailsDTO struct {
+	WebhookDeliveryDTO
+	AttemptLog []WebhookDeliveryAttemptDTO `json:"attempt_log"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// WebSocketTicketDTO — одноразовый билет для подключения к /api/ws?ticket=...
+type WebSocketTicketDTO struct {
+	Ticket           string    `json:"ticket"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	ExpiresInSeconds int       `json:"expires_in_seconds"`
+}
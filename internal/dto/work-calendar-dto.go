@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// WorkCalendarEntryDTO — смена или период отсутствия сотрудника.
+type WorkCalendarEntryDTO struct {
+	ID        uint64  `json:"id"`
+	UserID    uint64  `json:"user_id"`
+	UserFio   string  `json:"user_fio"`
+	Kind      string  `json:"kind"`
+	StartsAt  string  `json:"starts_at"`
+	EndsAt    string  `json:"ends_at"`
+	Comment   *string `json:"comment,omitempty"`
+	CreatedBy *uint64 `json:"created_by,omitempty"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// CreateWorkCalendarEntryDTO — kind: SHIFT, DAY_OFF, VACATION или SICK_LEAVE.
+type CreateWorkCalendarEntryDTO struct {
+	UserID   uint64    `json:"user_id" validate:"required"`
+	Kind     string    `json:"kind" validate:"required,oneof=SHIFT DAY_OFF VACATION SICK_LEAVE"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+	Comment  *string   `json:"comment" validate:"omitempty,max=500"`
+}
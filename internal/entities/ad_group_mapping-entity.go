@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// ADGroupRoleMapping — роль, которую получают участники группы Active Directory.
+type ADGroupRoleMapping struct {
+	ID        uint64    `db:"id"`
+	ADGroup   string    `db:"ad_group"`
+	RoleID    uint64    `db:"role_id"`
+	RoleName  string    `db:"role_name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ADProvisionedUser — данные для создания локального пользователя из учётной записи AD.
+type ADProvisionedUser struct {
+	Username string
+	Fio      string
+	Email    string
+	StatusID uint64
+}
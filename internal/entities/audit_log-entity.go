@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	// Начало имперсонации и запросы на чтение, сделанные под чужой учётной записью.
+	AuditActionImpersonate = "impersonate"
+	AuditActionRead        = "read"
+)
+
+// AuditLogEntry — одна запись журнала изменяющих API-вызовов.
+type AuditLogEntry struct {
+	ID         uint64          `db:"id"`
+	ActorID    *uint64         `db:"actor_id"`
+	Action     string          `db:"action"`
+	Entity     string          `db:"entity"`
+	EntityID   *string         `db:"entity_id"`
+	Method     string          `db:"method"`
+	Path       string          `db:"path"`
+	StatusCode int             `db:"status_code"`
+	Before     json.RawMessage `db:"before"`
+	After      json.RawMessage `db:"after"`
+	IP         *string         `db:"ip"`
+	UserAgent  *string         `db:"user_agent"`
+	RequestID  *string         `db:"request_id"`
+	// ImpersonatorID — администратор, действовавший от имени ActorID.
+	ImpersonatorID *uint64   `db:"impersonator_id"`
+	CreatedAt      time.Time `db:"created_at"`
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	BackupRunning   = "running"
+	BackupCompleted = "completed"
+	BackupFailed    = "failed"
+	// BackupPruned — файл копии удалён по BACKUP_KEEP, запись осталась в журнале.
+	BackupPruned = "pruned"
+)
+
+const (
+	BackupVerifyPassed = "passed"
+	BackupVerifyFailed = "failed"
+)
+
+// Backup — резервная копия важных таблиц; VerifyReport — JSON-массив строк по таблицам
+// из последней проверки восстановления.
+type Backup struct {
+	ID           uint64          `db:"id"`
+	Status       string          `db:"status"`
+	Tables       []string        `db:"tables"`
+	FilePath     *string         `db:"file_path"`
+	SizeBytes    int64           `db:"size_bytes"`
+	SHA256       *string         `db:"sha256"`
+	TriggeredBy  *uint64         `db:"triggered_by"`
+	Error        *string         `db:"error"`
+	StartedAt    time.Time       `db:"started_at"`
+	FinishedAt   *time.Time      `db:"finished_at"`
+	VerifyStatus *string         `db:"verify_status"`
+	VerifyReport json.RawMessage `db:"verify_report"`
+	VerifyError  *string         `db:"verify_error"`
+	VerifiedAt   *time.Time      `db:"verified_at"`
+}
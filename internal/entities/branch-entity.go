@@ -18,7 +18,9 @@ type Branch struct {
 	Email        *string
 	EmailIndex   *string
 	OpenDate     *time.Time
-	StatusID     uint64
-	Status       *Status
+	// Префикс номеров заявок филиала; без него — repositories.DefaultOrderNumberPrefix
+	OrderPrefix *string
+	StatusID    uint64
+	Status      *Status
 	types.BaseEntity
 }
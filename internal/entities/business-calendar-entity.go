@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// BusinessCalendar — рабочие дни недели (ISO, 1 — понедельник) и часы рабочего дня «ЧЧ:ММ»
+// для расчёта SLA. Выключенный календарь — отсчёт круглосуточно.
+type BusinessCalendar struct {
+	WorkDays  []int32   `db:"work_days"`
+	DayStart  string    `db:"day_start"`
+	DayEnd    string    `db:"day_end"`
+	IsEnabled bool      `db:"is_enabled"`
+	UpdatedBy *uint64   `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// BusinessHoliday — нерабочий день; IsRecurring — каждый год, IsWorkingDay — перенесённый рабочий день.
+type BusinessHoliday struct {
+	ID           uint64    `db:"id"`
+	HolidayDate  time.Time `db:"holiday_date"`
+	Name         string    `db:"name"`
+	IsRecurring  bool      `db:"is_recurring"`
+	IsWorkingDay bool      `db:"is_working_day"`
+	CreatedBy    *uint64   `db:"created_by"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
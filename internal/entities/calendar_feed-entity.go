@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// UserCalendarToken — ключ персональной iCal-подписки пользователя.
+type UserCalendarToken struct {
+	UserID     uint64     `db:"user_id"`
+	Nonce      string     `db:"nonce"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+// CalendarDeadline — открытая заявка исполнителя со сроком для календаря.
+type CalendarDeadline struct {
+	ID           uint64    `db:"id"`
+	Number       string    `db:"number"`
+	Name         string    `db:"name"`
+	Address      *string   `db:"address"`
+	Duration     time.Time `db:"duration"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	StatusName   string    `db:"status_name"`
+	PriorityName string    `db:"priority_name"`
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+const (
+	ChatChannelTeams = "teams"
+	ChatChannelSlack = "slack"
+)
+
+// ChatChannel — входящий вебхук Teams или Slack, в который публикуются события заявок.
+type ChatChannel struct {
+	ID           uint64    `db:"id"`
+	Name         string    `db:"name"`
+	Kind         string    `db:"kind"`
+	WebhookURL   string    `db:"webhook_url"`
+	DepartmentID *uint64   `db:"department_id"`
+	EventTypes   []string  `db:"event_types"`
+	OnlyCritical bool      `db:"only_critical"`
+	IsActive     bool      `db:"is_active"`
+	CreatedBy    *uint64   `db:"created_by"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// ChatOrderCard — данные заявки для карточки сообщения в канале.
+type ChatOrderCard struct {
+	ID             uint64     `db:"id"`
+	Number         string     `db:"number"`
+	Name           string     `db:"name"`
+	DepartmentID   *uint64    `db:"department_id"`
+	DepartmentName string     `db:"department_name"`
+	StatusName     string     `db:"status_name"`
+	PriorityName   string     `db:"priority_name"`
+	PriorityCode   string     `db:"priority_code"`
+	ExecutorName   string     `db:"executor_name"`
+	Duration       *time.Time `db:"duration"`
+}
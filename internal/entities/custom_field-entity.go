@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Типы дополнительных полей заявки.
+const (
+	CustomFieldText    = "text"
+	CustomFieldNumber  = "number"
+	CustomFieldDate    = "date"
+	CustomFieldBoolean = "boolean"
+	CustomFieldSelect  = "select"
+)
+
+// CustomFieldDefinition — дополнительное поле, которое заполняется в заявках одного типа.
+// Значение хранится в Order.CustomFields под ключом Code.
+type CustomFieldDefinition struct {
+	ID          uint64    `db:"id"`
+	OrderTypeID uint64    `db:"order_type_id"`
+	Code        string    `db:"code"`
+	Name        string    `db:"name"`
+	FieldType   string    `db:"field_type"`
+	IsRequired  bool      `db:"is_required"`
+	Options     []string  `db:"options"`
+	SortOrder   int       `db:"sort_order"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
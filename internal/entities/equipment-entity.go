@@ -1,9 +1,18 @@
 package entities
 
 import (
+	"time"
+
 	"request-system/pkg/types"
 )
 
+// Состояния жизненного цикла оборудования. Списанное оборудование обратно не возвращается.
+const (
+	EquipmentStateInService  = "in_service"
+	EquipmentStateRepair     = "repair"
+	EquipmentStateWrittenOff = "written_off"
+)
+
 type Equipment struct {
 	ID              uint64  `json:"id"`
 	Name            string  `json:"name"`
@@ -12,6 +21,15 @@ type Equipment struct {
 	OfficeID        *uint64 `json:"office_id"`
 	StatusID        uint64  `json:"status_id"`
 	EquipmentTypeID uint64  `json:"equipment_type_id"`
+	// Code — код QR-наклейки, генерируется базой.
+	Code string `json:"code"`
+
+	SerialNumber   *string    `json:"serial_number"`
+	AssignedUserID *uint64    `json:"assigned_user_id"`
+	PurchaseDate   *time.Time `json:"purchase_date"`
+	WarrantyUntil  *time.Time `json:"warranty_until"`
+	State          string     `json:"state"`
+	StateChangedAt *time.Time `json:"state_changed_at"`
 
 	types.BaseEntity
 
@@ -19,4 +37,5 @@ type Equipment struct {
 	Office        *Office        `db:"-"`
 	EquipmentType *EquipmentType `db:"-"`
 	Status        *Status        `db:"-"`
+	AssignedUser  *User          `db:"-"`
 }
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventOutboxRecord — доменное событие, сохранённое в транзакции для публикации после коммита.
+type EventOutboxRecord struct {
+	ID          uint64          `db:"id"`
+	EventName   string          `db:"event_name"`
+	AggregateID *uint64         `db:"aggregate_id"`
+	Payload     json.RawMessage `db:"payload"`
+	Attempts    int             `db:"attempts"`
+	LastError   *string         `db:"last_error"`
+	CreatedAt   time.Time       `db:"created_at"`
+	PublishedAt *time.Time      `db:"published_at"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+// ExecutorCandidate — сотрудник подразделения заявки с показателями для подбора исполнителя.
+type ExecutorCandidate struct {
+	UserID       uint64  `db:"user_id"`
+	Fio          string  `db:"fio"`
+	PositionName *string `db:"position_name"`
+	OpenOrders   int     `db:"open_orders"`
+	// Среднее время решения за последние 90 дней; nil — закрытых заявок не было
+	AvgResolutionSeconds *float64 `db:"avg_resolution_seconds"`
+	MatchedSkills        int      `db:"matched_skills"`
+	RequiredSkills       int      `db:"required_skills"`
+}
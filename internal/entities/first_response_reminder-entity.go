@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+// FirstResponseCandidate — открытая заявка, по которой исполнитель ещё не откликнулся
+// и не получал напоминания. FirstResponseMinutes — срок отклика по её приоритету.
+type FirstResponseCandidate struct {
+	OrderID              uint64    `db:"order_id"`
+	OrderNumber          string    `db:"order_number"`
+	OrderName            string    `db:"order_name"`
+	CreatedAt            time.Time `db:"created_at"`
+	FirstResponseMinutes int       `db:"first_response_minutes"`
+	ExecutorID           uint64    `db:"executor_id"`
+}
+
+func (c *FirstResponseCandidate) DisplayNumber() string {
+	if c.OrderNumber != "" {
+		return c.OrderNumber
+	}
+	return strconv.FormatUint(c.OrderID, 10)
+}
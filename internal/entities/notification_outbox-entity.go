@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	NotificationChannelTelegram  = "telegram"
+	NotificationChannelWebSocket = "websocket"
+
+	OutboxStatusPending    = "pending"
+	OutboxStatusProcessing = "processing"
+	OutboxStatusSent       = "sent"
+	OutboxStatusDead       = "dead"
+)
+
+// OutboxMessageDigest — Telegram-сообщение, отложенное тихими часами или режимом дайджеста.
+// Такие записи одного получателя, ставшие готовыми вместе, отправляются одной сводкой.
+const OutboxMessageDigest = "digest"
+
+// NotificationOutboxItem — исходящее уведомление, ожидающее доставки.
+// Для Telegram в Payload лежит {"text": "..."} (для дайджеста ещё и "lang"), для WebSocket — готовый payload сообщения.
+type NotificationOutboxItem struct {
+	ID            uint64          `db:"id"`
+	UserID        *uint64         `db:"user_id"`
+	Channel       string          `db:"channel"`
+	ChatID        *int64          `db:"chat_id"`
+	MessageType   string          `db:"message_type"`
+	Payload       json.RawMessage `db:"payload"`
+	Status        string          `db:"status"`
+	Attempts      int             `db:"attempts"`
+	MaxAttempts   int             `db:"max_attempts"`
+	NextAttemptAt time.Time       `db:"next_attempt_at"`
+	LastError     *string         `db:"last_error"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+	SentAt        *time.Time      `db:"sent_at"`
+}
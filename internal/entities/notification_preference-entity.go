@@ -0,0 +1,70 @@
+package entities
+
+import "time"
+
+const (
+	NotificationDeliveryRealtime = "realtime"
+	NotificationDeliveryDigest   = "digest"
+)
+
+// NotificationPreference — персональные настройки уведомлений пользователя.
+// QuietHoursStart/End хранятся как минуты от начала суток (локальное время сервера).
+type NotificationPreference struct {
+	UserID           uint64
+	TelegramEnabled  bool
+	WebSocketEnabled bool
+	MutedEventTypes  []string
+	QuietHoursStart  *int
+	QuietHoursEnd    *int
+	DeliveryMode     string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// DefaultNotificationPreference — настройки для пользователя, который их ещё не менял.
+func DefaultNotificationPreference(userID uint64) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:           userID,
+		TelegramEnabled:  true,
+		WebSocketEnabled: true,
+		MutedEventTypes:  []string{},
+		DeliveryMode:     NotificationDeliveryRealtime,
+	}
+}
+
+// IsEventMuted сообщает, отключены ли уведомления для данного типа события.
+func (p *NotificationPreference) IsEventMuted(eventType string) bool {
+	for _, muted := range p.MutedEventTypes {
+		if muted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// InQuietHours проверяет, попадает ли момент t в тихие часы. Интервал может переходить через полночь (22:00–08:00).
+func (p *NotificationPreference) InQuietHours(t time.Time) bool {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil || *p.QuietHoursStart == *p.QuietHoursEnd {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	start, end := *p.QuietHoursStart, *p.QuietHoursEnd
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// QuietHoursOver возвращает момент окончания тихих часов, в которые попадает t, или сам t вне тихих часов.
+func (p *NotificationPreference) QuietHoursOver(t time.Time) time.Time {
+	if !p.InQuietHours(t) {
+		return t
+	}
+	end := *p.QuietHoursEnd
+	over := time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location())
+	if !over.After(t) {
+		over = over.AddDate(0, 0, 1)
+	}
+	return over
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// OnCallSchedule — график дежурств департамента: участники дежурят по очереди сменами
+// по ShiftHours часов начиная с RotationStart.
+type OnCallSchedule struct {
+	ID             uint64    `db:"id"`
+	Name           string    `db:"name"`
+	DepartmentID   uint64    `db:"department_id"`
+	DepartmentName *string   `db:"department_name"`
+	RotationStart  time.Time `db:"rotation_start"`
+	ShiftHours     int       `db:"shift_hours"`
+	IsActive       bool      `db:"is_active"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// OnCallMember — участник ротации; Position задаёт очередь.
+type OnCallMember struct {
+	UserID   uint64 `db:"user_id"`
+	Fio      string `db:"fio"`
+	Position int    `db:"position"`
+}
+
+// OnCallOverride — замена: в [StartsAt, EndsAt) дежурит UserID вместо очередного по ротации.
+type OnCallOverride struct {
+	ID         uint64    `db:"id"`
+	ScheduleID uint64    `db:"schedule_id"`
+	UserID     uint64    `db:"user_id"`
+	Fio        string    `db:"fio"`
+	StartsAt   time.Time `db:"starts_at"`
+	EndsAt     time.Time `db:"ends_at"`
+	Reason     *string   `db:"reason"`
+	CreatedBy  *uint64   `db:"created_by"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -1,11 +1,15 @@
 package entities
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // Order — структура для заявки
 // ВАЖНО: Добавлены теги `json`, чтобы SmartUpdate мог сопоставить map и структуру
 type Order struct {
 	ID              uint64     `db:"id" json:"id"`
+	Number          string     `db:"number" json:"number"`
 	Name            string     `db:"name" json:"name"`
 	DepartmentID    *uint64    `db:"department_id" json:"department_id"`
 	StatusID        uint64     `db:"status_id" json:"status_id"`
@@ -24,6 +28,17 @@ type Order struct {
 	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
 	DeletedAt       *time.Time `db:"deleted_at" json:"-"`
 	CompletedAt     *time.Time `db:"completed_at" json:"completed_at"`
+	DuplicateOfID   *uint64    `db:"duplicate_of_id" json:"duplicate_of_id"`
+	// Влияние и срочность (low/medium/high); по ним из матрицы считается PriorityID
+	Impact  *string `db:"impact" json:"impact"`
+	Urgency *string `db:"urgency" json:"urgency"`
+	// Команда, на которую назначена заявка; executor_id пуст, пока её не забрал участник
+	TeamID *uint64 `db:"team_id" json:"team_id"`
+	// Код решения из справочника resolution_codes; ставится при выполнении или закрытии
+	ResolutionCodeID *uint64 `db:"resolution_code_id" json:"resolution_code_id"`
+	// Значения дополнительных полей типа заявки по их code. SmartUpdate их не трогает:
+	// изменения сливаются и проверяются в OrderService
+	CustomFields map[string]any `db:"custom_fields" json:"-"`
 
 	// Метрики
 	FirstResponseTimeSeconds *uint64 `db:"first_response_time_seconds" json:"first_response_time_seconds"`
@@ -33,4 +48,22 @@ type Order struct {
 	// Поля для Join (Read Only) - их не обновляем через SmartUpdate, тег json можно не ставить или ставить для выдачи
 	CreatorName  string  `db:"creator_name" json:"creator_name,omitempty"`
 	ExecutorName *string `db:"executor_name" json:"executor_name,omitempty"`
+	TeamName     *string `db:"team_name" json:"team_name,omitempty"`
+	// Оформление приоритета для клиентов; SmartUpdate его не трогает
+	PriorityName      *string `db:"priority_name" json:"-"`
+	PriorityColor     *string `db:"priority_color" json:"-"`
+	PrioritySortOrder *int    `db:"priority_sort_order" json:"-"`
+	// Срок первого отклика по приоритету заявки, в рабочих минутах
+	PriorityFirstResponseMinutes *int `db:"priority_first_response_minutes" json:"-"`
+
+	// Заполняется только в списке заявок из order_list_view
+	ListView *OrderListView `db:"-" json:"-"`
+}
+
+// DisplayNumber — номер заявки для людей; у заявки, ещё не получившей номер, это её ID.
+func (o *Order) DisplayNumber() string {
+	if o.Number != "" {
+		return o.Number
+	}
+	return strconv.FormatUint(o.ID, 10)
 }
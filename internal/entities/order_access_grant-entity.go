@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Уровни разового доступа к заявке.
+const (
+	OrderAccessView = "view"
+	OrderAccessEdit = "edit"
+)
+
+// OrderAccessGrant — доступ к одной заявке, выданный сотруднику в обход областей видимости.
+type OrderAccessGrant struct {
+	ID           uint64     `db:"id"`
+	OrderID      uint64     `db:"order_id"`
+	UserID       uint64     `db:"user_id"`
+	UserFio      string     `db:"user_fio"`
+	Level        string     `db:"level"`
+	GrantedBy    *uint64    `db:"granted_by"`
+	GrantedByFio *string    `db:"granted_by_fio"`
+	ExpiresAt    *time.Time `db:"expires_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// IsActive — выдача без срока или срок ещё не истёк.
+func (g OrderAccessGrant) IsActive(now time.Time) bool {
+	return g.ExpiresAt == nil || g.ExpiresAt.After(now)
+}
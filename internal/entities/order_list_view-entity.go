@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// OrderListView — поля заявки, которые список берёт готовыми из проекции order_list_view.
+type OrderListView struct {
+	StatusName       *string
+	LastComment      *string
+	LastCommentAt    *time.Time
+	AttachmentsCount int
+}
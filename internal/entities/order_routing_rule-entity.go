@@ -11,7 +11,13 @@ type OrderRoutingRule struct {
 	BranchID     *int   `json:"branch_id" db:"branch_id"`
 	OfficeID     *int   `json:"office_id" db:"office_id"`
 	PositionID   *int   `json:"position_id" db:"assign_to_position_id"`
-	StatusID     int    `json:"status_id" db:"status_id"`
+	// Команда-получатель; если в ней есть участники, правило важнее должности
+	TeamID   *int    `json:"team_id" db:"assign_to_team_id"`
+	TeamName *string `json:"team_name" db:"team_name"`
+	// График дежурств; текущий дежурный важнее команды и должности
+	OnCallScheduleID   *int    `json:"on_call_schedule_id" db:"assign_to_on_call_schedule_id"`
+	OnCallScheduleName *string `json:"on_call_schedule_name" db:"on_call_schedule_name"`
+	StatusID           int     `json:"status_id" db:"status_id"`
 
 	types.BaseEntity
 }
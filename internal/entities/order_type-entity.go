@@ -5,10 +5,18 @@ package entities
 import "request-system/pkg/types"
 
 type OrderType struct {
-	ID       int     `json:"id"`
-	Name     string  `json:"name"`
-	Code     *string `json:"code"`
-	StatusID int     `json:"status_id"`
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Code        *string `json:"code"`
+	StatusID    int     `json:"status_id"`
+	Icon        *string `json:"icon"`
+	Description *string `json:"description"`
+	// IsActive — статус типа ACTIVE; считается запросом, отдельно не хранится.
+	IsActive bool `json:"is_active"`
+	// DepartmentIDs и BranchIDs ограничивают, кому тип доступен при создании заявки;
+	// оба пустые — тип виден всем.
+	DepartmentIDs []uint64 `json:"department_ids"`
+	BranchIDs     []uint64 `json:"branch_ids"`
 
 	types.BaseEntity
 }
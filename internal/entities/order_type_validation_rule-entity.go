@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// OrderTypeValidationRule — обязательное поле заявки определённого типа. OnCreate проверяется
+// при создании, OnUpdate — при изменении (см. OrderService.validateUpdateRules).
+type OrderTypeValidationRule struct {
+	ID           uint64    `db:"id"`
+	OrderTypeID  uint64    `db:"order_type_id"`
+	FieldName    string    `db:"field_name"`
+	ErrorMessage string    `db:"error_message"`
+	OnCreate     bool      `db:"on_create"`
+	OnUpdate     bool      `db:"on_update"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+// Виды узлов оргструктуры.
+const (
+	OrgUnitDepartment = "department"
+	OrgUnitOtdel      = "otdel"
+	OrgUnitBranch     = "branch"
+	OrgUnitOffice     = "office"
+)
+
+// OrgUnit — подразделение любого уровня одной строкой, из таких строк собирается дерево.
+// ParentID указывает на родителя того же вида (вложенные отделы и офисы).
+type OrgUnit struct {
+	Kind         string  `db:"kind"`
+	ID           uint64  `db:"id"`
+	Name         string  `db:"name"`
+	StatusID     *uint64 `db:"status_id"`
+	ParentID     *uint64 `db:"parent_id"`
+	DepartmentID *uint64 `db:"department_id"`
+	BranchID     *uint64 `db:"branch_id"`
+	HeadID       *uint64 `db:"head_id"`
+	HeadFio      *string `db:"head_fio"`
+	ActiveOrders int     `db:"active_orders"`
+}
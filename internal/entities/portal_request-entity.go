@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// PortalRequest — обращение с публичного портала: контакт клиента и код отслеживания заявки.
+type PortalRequest struct {
+	ID           uint64    `json:"id" db:"id"`
+	OrderID      uint64    `json:"order_id" db:"order_id"`
+	TrackingCode string    `json:"tracking_code" db:"tracking_code"`
+	CustomerName string    `json:"customer_name" db:"customer_name"`
+	Phone        string    `json:"phone" db:"phone"`
+	ClientIP     *string   `json:"client_ip" db:"client_ip"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// PortalRequestStatus — то, что клиент видит по коду отслеживания. Внутренние поля заявки не раскрываются.
+type PortalRequestStatus struct {
+	TrackingCode string
+	StatusName   string
+	StatusCode   string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	CompletedAt  *time.Time
+}
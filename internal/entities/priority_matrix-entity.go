@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Уровни влияния и срочности заявки для матрицы приоритетов.
+const (
+	PriorityLevelLow    = "low"
+	PriorityLevelMedium = "medium"
+	PriorityLevelHigh   = "high"
+)
+
+// PriorityLevels — уровни в порядке возрастания.
+var PriorityLevels = []string{PriorityLevelLow, PriorityLevelMedium, PriorityLevelHigh}
+
+// PriorityMatrixCell — приоритет, который получает заявка с данными влиянием и срочностью.
+type PriorityMatrixCell struct {
+	Impact       string    `db:"impact"`
+	Urgency      string    `db:"urgency"`
+	PriorityID   uint64    `db:"priority_id"`
+	PriorityName string    `db:"priority_name"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
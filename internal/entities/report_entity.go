@@ -6,15 +6,13 @@ import (
 )
 
 type ReportFilter struct {
-	DateFrom       *time.Time
-	DateTo         *time.Time
-	ExecutorIDs    []uint64
-	OrderTypeIDs   []uint64
-	PriorityIDs    []uint64
-	Page           int
-	PerPage        int
-	Actor          *User
-	PermissionsMap map[string]bool
+	DateFrom     *time.Time
+	DateTo       *time.Time
+	ExecutorIDs  []uint64
+	OrderTypeIDs []uint64
+	PriorityIDs  []uint64
+	Page         int
+	PerPage      int
 }
 
 type ReportItem struct {
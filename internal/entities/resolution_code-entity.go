@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// ResolutionCode — код решения заявки из справочника: чем закончилась работа по ней.
+type ResolutionCode struct {
+	ID        uint64    `db:"id"`
+	Code      string    `db:"code"`
+	Name      string    `db:"name"`
+	SortOrder int       `db:"sort_order"`
+	IsActive  bool      `db:"is_active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ResolutionCodeStat — сколько заявок выполнено с кодом решения за период.
+type ResolutionCodeStat struct {
+	ID     uint64 `db:"id"`
+	Code   string `db:"code"`
+	Name   string `db:"name"`
+	Orders uint64 `db:"orders"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	RetentionPurgeAttachments = "purge_attachments"
+	RetentionCompactHistory   = "compact_history"
+)
+
+const (
+	RetentionRunRunning   = "running"
+	RetentionRunCompleted = "completed"
+	RetentionRunFailed    = "failed"
+)
+
+// RetentionRule — что удалять у заявок, закрытых больше ClosedOlderThanDays дней назад.
+type RetentionRule struct {
+	ID                  uint64    `db:"id"`
+	Name                string    `db:"name"`
+	Action              string    `db:"action"`
+	ClosedOlderThanDays int       `db:"closed_older_than_days"`
+	IsActive            bool      `db:"is_active"`
+	CreatedBy           *uint64   `db:"created_by"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
+}
+
+// RetentionHold — заявка под юридическим удержанием, которую правила хранения не трогают.
+type RetentionHold struct {
+	OrderID   uint64    `db:"order_id"`
+	Reason    string    `db:"reason"`
+	CreatedBy *uint64   `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// RetentionRun — запуск правил хранения; Report — JSON-массив отчётов по правилам.
+type RetentionRun struct {
+	ID          uint64          `db:"id"`
+	DryRun      bool            `db:"dry_run"`
+	Status      string          `db:"status"`
+	TriggeredBy *uint64         `db:"triggered_by"`
+	Report      json.RawMessage `db:"report"`
+	Error       *string         `db:"error"`
+	StartedAt   time.Time       `db:"started_at"`
+	FinishedAt  *time.Time      `db:"finished_at"`
+}
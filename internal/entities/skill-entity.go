@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// Skill — навык исполнителя, который может требоваться типу заявки.
+type Skill struct {
+	ID        uint64    `db:"id"`
+	Code      string    `db:"code"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+// SLAEscalationSetting — порог эскалации департамента: сколько нарушений срока одним
+// исполнителем за окно приводят к уведомлению руководителя.
+type SLAEscalationSetting struct {
+	DepartmentID    uint64    `db:"department_id"`
+	BreachThreshold int       `db:"breach_threshold"`
+	WindowHours     int       `db:"window_hours"`
+	IsEnabled       bool      `db:"is_enabled"`
+	UpdatedBy       *uint64   `db:"updated_by"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// SLABreach — нарушение срока заявки исполнителем, ещё не вошедшее в эскалацию.
+// Департамент и отдел — текущие у исполнителя.
+type SLABreach struct {
+	OrderID              uint64     `db:"order_id"`
+	OrderNumber          string     `db:"order_number"`
+	OrderName            string     `db:"order_name"`
+	Duration             time.Time  `db:"duration"`
+	CompletedAt          *time.Time `db:"completed_at"`
+	ExecutorID           uint64     `db:"executor_id"`
+	ExecutorName         string     `db:"executor_name"`
+	ExecutorDepartmentID *uint64    `db:"executor_department_id"`
+	ExecutorOtdelID      *uint64    `db:"executor_otdel_id"`
+}
+
+func (b *SLABreach) DisplayNumber() string {
+	if b.OrderNumber != "" {
+		return b.OrderNumber
+	}
+	return strconv.FormatUint(b.OrderID, 10)
+}
+
+// SLAEscalation — отправленная руководителям сводка о повторных нарушениях исполнителя.
+type SLAEscalation struct {
+	ID           uint64    `db:"id"`
+	ExecutorID   uint64    `db:"executor_id"`
+	DepartmentID uint64    `db:"department_id"`
+	BreachCount  int       `db:"breach_count"`
+	LastBreachAt time.Time `db:"last_breach_at"`
+	NotifiedIDs  []uint64  `db:"notified_ids"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	SyncJobQueued    = "queued"
+	SyncJobRunning   = "running"
+	SyncJobCompleted = "completed"
+	SyncJobFailed    = "failed"
+)
+
+// SyncJob — задание синхронизации справочников из внешней системы.
+type SyncJob struct {
+	ID          uint64          `db:"id"`
+	Source      string          `db:"source"`
+	Status      string          `db:"status"`
+	DryRun      bool            `db:"dry_run"`
+	Snapshot    bool            `db:"snapshot"`
+	BestEffort  bool            `db:"best_effort"`
+	Payload     json.RawMessage `db:"payload"`
+	Cursors     json.RawMessage `db:"cursors"`
+	Progress    json.RawMessage `db:"progress"`
+	Summary     json.RawMessage `db:"summary"`
+	Errors      json.RawMessage `db:"errors"`
+	Changes     json.RawMessage `db:"changes"`
+	Deactivated json.RawMessage `db:"deactivated"`
+	Error       *string         `db:"error"`
+	CreatedAt   time.Time       `db:"created_at"`
+	StartedAt   *time.Time      `db:"started_at"`
+	HeartbeatAt *time.Time      `db:"heartbeat_at"`
+	FinishedAt  *time.Time      `db:"finished_at"`
+}
+
+// SyncJobResult — отчёт воркера, сохраняемый в задание (JSON-поля sync_jobs).
+type SyncJobResult struct {
+	Progress    json.RawMessage
+	Summary     json.RawMessage
+	Errors      json.RawMessage
+	Changes     json.RawMessage
+	Deactivated json.RawMessage
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Team — группа исполнителей, на которую можно назначить заявку.
+type Team struct {
+	ID          uint64    `db:"id"`
+	Name        string    `db:"name"`
+	Description *string   `db:"description"`
+	LeadID      *uint64   `db:"lead_id"`
+	LeadName    *string   `db:"lead_name"`
+	MemberCount int       `db:"member_count"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// TeamMember — участник команды.
+type TeamMember struct {
+	UserID   uint64    `db:"user_id"`
+	Fio      string    `db:"fio"`
+	Email    string    `db:"email"`
+	IsLead   bool      `db:"is_lead"`
+	JoinedAt time.Time `db:"joined_at"`
+}
@@ -18,6 +18,7 @@ type User struct {
 	Password string `json:"-" db:"password"`
 
 	StatusID uint64 `json:"status_id" db:"status_id"`
+	TenantID uint64 `json:"tenant_id" db:"tenant_id"`
 
 	BranchID           *uint64  `json:"branch_id" db:"branch_id"`
 	OfficeID           *uint64  `json:"office_id" db:"office_id"`
@@ -39,6 +40,13 @@ type User struct {
 	SourceSystem *string `json:"source_system,omitempty" db:"source_system"`
 
 	TelegramChatID sql.NullInt64 `json:"telegram_chat_id,omitempty" db:"telegram_chat_id"`
+	// TelegramLastSeenAt — последнее сообщение или нажатие кнопки в боте из привязанного чата.
+	TelegramLastSeenAt *time.Time `json:"telegram_last_seen_at,omitempty" db:"telegram_last_seen_at"`
+	Language           string     `json:"language" db:"language"`
+	// Timezone — пояс IANA для дат в боте, уведомлениях и выгрузках; nil — пояс сервера.
+	Timezone *string `json:"timezone" db:"timezone"`
+
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty" db:"anonymized_at"`
 
 	TelegramLinkToken       string    `db:"-" json:"-"`
 	TelegramLinkTokenExpiry time.Time `db:"-" json:"-"`
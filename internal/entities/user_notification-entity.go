@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UserNotification — уведомление из центра уведомлений конкретного пользователя.
+type UserNotification struct {
+	ID        uint64          `db:"id"`
+	UserID    uint64          `db:"user_id"`
+	EventID   string          `db:"event_id"`
+	OrderID   *uint64         `db:"order_id"`
+	Type      string          `db:"type"`
+	Payload   json.RawMessage `db:"payload"`
+	IsRead    bool            `db:"is_read"`
+	ReadAt    *time.Time      `db:"read_at"`
+	CreatedAt time.Time       `db:"created_at"`
+}
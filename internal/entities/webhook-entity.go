@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryProcessing = "processing"
+	WebhookDeliveryDelivered  = "delivered"
+	WebhookDeliveryFailed     = "failed"
+)
+
+// WebhookSubscription — URL внешней системы, на который отправляются события заявок.
+type WebhookSubscription struct {
+	ID         uint64    `db:"id"`
+	Name       string    `db:"name"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []string  `db:"event_types"`
+	IsActive   bool      `db:"is_active"`
+	CreatedBy  *uint64   `db:"created_by"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// WebhookDelivery — доставка одного события одной подписке.
+type WebhookDelivery struct {
+	ID             uint64          `db:"id"`
+	SubscriptionID uint64          `db:"subscription_id"`
+	EventID        uuid.UUID       `db:"event_id"`
+	EventType      string          `db:"event_type"`
+	Payload        json.RawMessage `db:"payload"`
+	Status         string          `db:"status"`
+	Attempts       int             `db:"attempts"`
+	MaxAttempts    int             `db:"max_attempts"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at"`
+	LastStatusCode *int            `db:"last_status_code"`
+	LastError      *string         `db:"last_error"`
+	CreatedAt      time.Time       `db:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at"`
+	DeliveredAt    *time.Time      `db:"delivered_at"`
+}
+
+// WebhookDeliveryAttempt — результат одной попытки отправки.
+type WebhookDeliveryAttempt struct {
+	ID           uint64    `db:"id"`
+	DeliveryID   uint64    `db:"delivery_id"`
+	Attempt      int       `db:"attempt"`
+	StatusCode   *int      `db:"status_code"`
+	Error        *string   `db:"error"`
+	ResponseBody *string   `db:"response_body"`
+	DurationMs   int       `db:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Виды записей рабочего календаря.
+const (
+	WorkCalendarShift     = "SHIFT"
+	WorkCalendarDayOff    = "DAY_OFF"
+	WorkCalendarVacation  = "VACATION"
+	WorkCalendarSickLeave = "SICK_LEAVE"
+)
+
+// WorkCalendarEntry — смена или период отсутствия сотрудника.
+type WorkCalendarEntry struct {
+	ID        uint64    `db:"id"`
+	UserID    uint64    `db:"user_id"`
+	UserFio   string    `db:"user_fio"`
+	Kind      string    `db:"kind"`
+	StartsAt  time.Time `db:"starts_at"`
+	EndsAt    time.Time `db:"ends_at"`
+	Comment   *string   `db:"comment"`
+	CreatedBy *uint64   `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
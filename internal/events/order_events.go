@@ -1,15 +1,55 @@
 package events
 
-import "request-system/internal/repositories"
+import (
+	"strconv"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+)
 
 // OrderHistoryCreatedEvent - событие, которое возникает после создания новой записи в истории.
 type OrderHistoryCreatedEvent struct {
 	HistoryItem repositories.OrderHistoryItem
 	Order       interface{} // Мы можем передать сюда полную сущность Order
 	Actor       interface{} // ... и сущность User, который совершил действие
+	// Replayed — событие переиграно из истории администратором, а не возникло только что.
+	Replayed bool
 }
 
 // Name - реализуем интерфейс eventbus.Event
 func (e OrderHistoryCreatedEvent) Name() string {
 	return "order.history.created"
 }
+
+// OrderHistoryCreatedRecord — сериализуемая форма OrderHistoryCreatedEvent для event_outbox.
+// Заявка сохраняется снимком на момент события, исполнитель действия — только ID.
+type OrderHistoryCreatedRecord struct {
+	HistoryItem repositories.OrderHistoryItem `json:"history_item"`
+	Order       *entities.Order               `json:"order"`
+	ActorID     uint64                        `json:"actor_id"`
+}
+
+func NewOrderHistoryCreatedRecord(item repositories.OrderHistoryItem, order *entities.Order, actor *entities.User) OrderHistoryCreatedRecord {
+	record := OrderHistoryCreatedRecord{HistoryItem: item, Order: order}
+	if actor != nil {
+		record.ActorID = actor.ID
+	}
+	return record
+}
+
+// StreamID — стабильный id события для внешнего брокера: одна запись истории всегда даёт один id.
+func (e OrderHistoryCreatedEvent) StreamID() string {
+	return "order_history:" + strconv.FormatUint(e.HistoryItem.ID, 10)
+}
+
+// StreamKey — события одной заявки публикуются с одним ключом (одна партиция Kafka).
+func (e OrderHistoryCreatedEvent) StreamKey() string {
+	return strconv.FormatUint(e.HistoryItem.OrderID, 10)
+}
+
+// StreamPayload отдаёт наружу ту же форму, что хранится в event_outbox, без сущности пользователя.
+func (e OrderHistoryCreatedEvent) StreamPayload() interface{} {
+	order, _ := e.Order.(*entities.Order)
+	actor, _ := e.Actor.(*entities.User)
+	return NewOrderHistoryCreatedRecord(e.HistoryItem, order, actor)
+}
@@ -0,0 +1,15 @@
+package events
+
+// PermissionsChangedEvent — изменились права ролей или отдельных пользователей.
+// По нему сбрасывается кэш прав затронутых пользователей; All — затронуты все
+// (например, удалена или переименована привилегия).
+type PermissionsChangedEvent struct {
+	Reason  string   `json:"reason"`
+	RoleIDs []uint64 `json:"role_ids,omitempty"`
+	UserIDs []uint64 `json:"user_ids,omitempty"`
+	All     bool     `json:"all,omitempty"`
+}
+
+func (e PermissionsChangedEvent) Name() string {
+	return "auth.permissions.changed"
+}
@@ -0,0 +1,84 @@
+package graphqlapi
+
+import (
+	"github.com/graph-gophers/graphql-go"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+)
+
+// optionalError — для необязательных связей: отсутствие записи даёт null, ошибка загрузки — ошибку поля.
+func (r *Resolver) optionalError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return r.toResolverError(err)
+}
+
+type statusResolver struct {
+	status entities.Status
+}
+
+func (r *statusResolver) ID() graphql.ID {
+	return toID(r.status.ID)
+}
+
+func (r *statusResolver) Name() string {
+	return r.status.Name
+}
+
+func (r *statusResolver) Code() *string {
+	return r.status.Code
+}
+
+func (r *statusResolver) Type() int32 {
+	return int32(r.status.Type)
+}
+
+type priorityResolver struct {
+	priority dto.PriorityDTO
+}
+
+func (r *priorityResolver) ID() graphql.ID {
+	return toID(r.priority.ID)
+}
+
+func (r *priorityResolver) Name() string {
+	return r.priority.Name
+}
+
+func (r *priorityResolver) Code() string {
+	return r.priority.Code
+}
+
+func (r *priorityResolver) Rate() int32 {
+	return int32(r.priority.Rate)
+}
+
+type departmentResolver struct {
+	department entities.Department
+}
+
+func (r *departmentResolver) ID() graphql.ID {
+	return toID(r.department.ID)
+}
+
+func (r *departmentResolver) Name() string {
+	return r.department.Name
+}
+
+type orderTypeResolver struct {
+	orderType *entities.OrderType
+}
+
+func (r *orderTypeResolver) ID() graphql.ID {
+	return toID(uint64(r.orderType.ID))
+}
+
+func (r *orderTypeResolver) Name() string {
+	return r.orderType.Name
+}
+
+func (r *orderTypeResolver) Code() *string {
+	return r.orderType.Code
+}
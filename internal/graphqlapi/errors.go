@@ -0,0 +1,50 @@
+package graphqlapi
+
+import (
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	apperrors "request-system/pkg/errors"
+)
+
+// resolverError попадает в ответ как {message, extensions: {code}}: клиент различает ошибки по коду,
+// внутренние детали остаются в логе.
+type resolverError struct {
+	message string
+	code    string
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func (r *Resolver) toResolverError(err error) error {
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) {
+		r.logger.Error("GraphQL: необработанная ошибка", zap.Error(err))
+		return &resolverError{message: "внутренняя ошибка сервера", code: "INTERNAL"}
+	}
+
+	switch httpErr.Code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return &resolverError{message: httpErr.Message, code: "BAD_REQUEST"}
+	case http.StatusUnauthorized:
+		return &resolverError{message: httpErr.Message, code: "UNAUTHENTICATED"}
+	case http.StatusForbidden:
+		return &resolverError{message: httpErr.Message, code: "FORBIDDEN"}
+	case http.StatusNotFound:
+		return &resolverError{message: httpErr.Message, code: "NOT_FOUND"}
+	case http.StatusConflict:
+		return &resolverError{message: httpErr.Message, code: "CONFLICT"}
+	case http.StatusTooManyRequests:
+		return &resolverError{message: httpErr.Message, code: "TOO_MANY_REQUESTS"}
+	}
+	r.logger.Error("GraphQL: внутренняя ошибка", zap.Error(err))
+	return &resolverError{message: "внутренняя ошибка сервера", code: "INTERNAL"}
+}
@@ -0,0 +1,191 @@
+package graphqlapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/types"
+)
+
+const dictionaryLimit = 1000
+
+type loadersKey struct{}
+
+// idSet — ID, уже встреченные в ответе. Резолверы списков регистрируют их заранее,
+// и первый же вызов загрузчика забирает все одним запросом.
+type idSet struct {
+	mu  sync.Mutex
+	ids map[uint64]struct{}
+}
+
+func newIDSet() *idSet {
+	return &idSet{ids: make(map[uint64]struct{})}
+}
+
+func (s *idSet) add(ids ...uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if id != 0 {
+			s.ids[id] = struct{}{}
+		}
+	}
+}
+
+func (s *idSet) snapshot() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uint64, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// batchLoader загружает значения по ID пачками: запрошенный ID вместе со всеми известными, но ещё не загруженными.
+type batchLoader[V any] struct {
+	mu     sync.Mutex
+	known  *idSet
+	fetch  func(ctx context.Context, ids []uint64) (map[uint64]V, error)
+	values map[uint64]V
+	done   map[uint64]bool
+}
+
+func newBatchLoader[V any](known *idSet, fetch func(ctx context.Context, ids []uint64) (map[uint64]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{known: known, fetch: fetch, values: make(map[uint64]V), done: make(map[uint64]bool)}
+}
+
+func (l *batchLoader[V]) load(ctx context.Context, id uint64) (V, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.done[id] {
+		ids := []uint64{id}
+		for _, known := range l.known.snapshot() {
+			if known != id && !l.done[known] {
+				ids = append(ids, known)
+			}
+		}
+		fetched, err := l.fetch(ctx, ids)
+		if err != nil {
+			var zero V
+			return zero, false, err
+		}
+		for _, key := range ids {
+			l.done[key] = true
+			if value, ok := fetched[key]; ok {
+				l.values[key] = value
+			}
+		}
+	}
+
+	value, ok := l.values[id]
+	return value, ok, nil
+}
+
+// dictionary загружает справочник целиком один раз за запрос.
+type dictionary[V any] struct {
+	once  sync.Once
+	fetch func(ctx context.Context) ([]V, error)
+	id    func(V) uint64
+	list  []V
+	byID  map[uint64]V
+	err   error
+}
+
+func newDictionary[V any](fetch func(ctx context.Context) ([]V, error), id func(V) uint64) *dictionary[V] {
+	return &dictionary[V]{fetch: fetch, id: id}
+}
+
+func (d *dictionary[V]) all(ctx context.Context) ([]V, error) {
+	d.once.Do(func() {
+		d.list, d.err = d.fetch(ctx)
+		if d.err != nil {
+			return
+		}
+		sort.Slice(d.list, func(i, j int) bool { return d.id(d.list[i]) < d.id(d.list[j]) })
+		d.byID = make(map[uint64]V, len(d.list))
+		for _, item := range d.list {
+			d.byID[d.id(item)] = item
+		}
+	})
+	return d.list, d.err
+}
+
+func (d *dictionary[V]) get(ctx context.Context, id uint64) (V, bool, error) {
+	if _, err := d.all(ctx); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	value, ok := d.byID[id]
+	return value, ok, nil
+}
+
+type loaders struct {
+	deps Dependencies
+
+	orderIDs *idSet
+	userIDs  *idSet
+
+	users       *batchLoader[entities.User]
+	attachments *batchLoader[[]entities.Attachment]
+
+	commentsMu sync.Mutex
+	comments   map[int]*batchLoader[[]repositories.OrderHistoryItem]
+
+	statuses    *dictionary[entities.Status]
+	priorities  *dictionary[dto.PriorityDTO]
+	departments *dictionary[entities.Department]
+	orderTypes  *dictionary[*entities.OrderType]
+}
+
+func newLoaders(deps Dependencies) *loaders {
+	l := &loaders{
+		deps:     deps,
+		orderIDs: newIDSet(),
+		userIDs:  newIDSet(),
+		comments: make(map[int]*batchLoader[[]repositories.OrderHistoryItem]),
+	}
+
+	l.users = newBatchLoader(l.userIDs, deps.UserRepo.FindUsersByIDs)
+	l.attachments = newBatchLoader(l.orderIDs, deps.AttachmentRepo.FindAttachmentsByOrderIDs)
+
+	l.statuses = newDictionary(deps.StatusRepo.FindAll, func(s entities.Status) uint64 { return s.ID })
+	l.priorities = newDictionary(func(ctx context.Context) ([]dto.PriorityDTO, error) {
+		list, _, err := deps.PriorityRepo.GetPriorities(ctx, dictionaryLimit, 0, "")
+		return list, err
+	}, func(p dto.PriorityDTO) uint64 { return p.ID })
+	l.departments = newDictionary(func(ctx context.Context) ([]entities.Department, error) {
+		list, _, err := deps.DepartmentRepo.GetDepartments(ctx, types.Filter{Limit: dictionaryLimit})
+		return list, err
+	}, func(d entities.Department) uint64 { return d.ID })
+	l.orderTypes = newDictionary(func(ctx context.Context) ([]*entities.OrderType, error) {
+		list, _, err := deps.OrderTypeRepo.GetAll(ctx, dictionaryLimit, 0, "")
+		return list, err
+	}, func(o *entities.OrderType) uint64 { return uint64(o.ID) })
+
+	return l
+}
+
+// lastComments — свой загрузчик на каждое значение limit, ID заявок общие.
+func (l *loaders) lastComments(limit int) *batchLoader[[]repositories.OrderHistoryItem] {
+	l.commentsMu.Lock()
+	defer l.commentsMu.Unlock()
+
+	loader, ok := l.comments[limit]
+	if !ok {
+		loader = newBatchLoader(l.orderIDs, func(ctx context.Context, ids []uint64) (map[uint64][]repositories.OrderHistoryItem, error) {
+			return l.deps.HistoryRepo.FindLastCommentsByOrderIDs(ctx, ids, limit)
+		})
+		l.comments[limit] = loader
+	}
+	return loader
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
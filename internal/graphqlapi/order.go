@@ -0,0 +1,344 @@
+package graphqlapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+)
+
+const (
+	maxLastComments  = 20
+	maxHistoryLimit  = 200
+	attachmentPrefix = "/uploads/"
+)
+
+type orderPageResolver struct {
+	totalCount uint64
+	items      []*orderResolver
+}
+
+func (p *orderPageResolver) TotalCount() int32 {
+	return int32(p.totalCount)
+}
+
+func (p *orderPageResolver) Items() []*orderResolver {
+	return p.items
+}
+
+type orderResolver struct {
+	root  *Resolver
+	order *dto.OrderResponseDTO
+}
+
+// newOrderResolver регистрирует заявку и её участников в загрузчиках запроса.
+func newOrderResolver(ctx context.Context, root *Resolver, order *dto.OrderResponseDTO) *orderResolver {
+	l := loadersFrom(ctx)
+	l.orderIDs.add(order.ID)
+	l.userIDs.add(order.CreatorID)
+	if order.ExecutorID != nil {
+		l.userIDs.add(*order.ExecutorID)
+	}
+	return &orderResolver{root: root, order: order}
+}
+
+func (r *orderResolver) ID() graphql.ID {
+	return toID(r.order.ID)
+}
+
+func (r *orderResolver) Name() string {
+	return r.order.Name
+}
+
+func (r *orderResolver) Address() *string {
+	return r.order.Address
+}
+
+func (r *orderResolver) Status(ctx context.Context) (*statusResolver, error) {
+	status, ok, err := loadersFrom(ctx).statuses.get(ctx, r.order.StatusID)
+	if err != nil || !ok {
+		return nil, r.root.optionalError(err)
+	}
+	return &statusResolver{status: status}, nil
+}
+
+func (r *orderResolver) Priority(ctx context.Context) (*priorityResolver, error) {
+	if r.order.PriorityID == nil {
+		return nil, nil
+	}
+	priority, ok, err := loadersFrom(ctx).priorities.get(ctx, *r.order.PriorityID)
+	if err != nil || !ok {
+		return nil, r.root.optionalError(err)
+	}
+	return &priorityResolver{priority: priority}, nil
+}
+
+func (r *orderResolver) OrderType(ctx context.Context) (*orderTypeResolver, error) {
+	if r.order.OrderTypeID == nil {
+		return nil, nil
+	}
+	orderType, ok, err := loadersFrom(ctx).orderTypes.get(ctx, *r.order.OrderTypeID)
+	if err != nil || !ok {
+		return nil, r.root.optionalError(err)
+	}
+	return &orderTypeResolver{orderType: orderType}, nil
+}
+
+func (r *orderResolver) Department(ctx context.Context) (*departmentResolver, error) {
+	if r.order.DepartmentID == nil {
+		return nil, nil
+	}
+	department, ok, err := loadersFrom(ctx).departments.get(ctx, *r.order.DepartmentID)
+	if err != nil || !ok {
+		return nil, r.root.optionalError(err)
+	}
+	return &departmentResolver{department: department}, nil
+}
+
+func (r *orderResolver) Creator(ctx context.Context) (*userResolver, error) {
+	return r.root.loadUser(ctx, r.order.CreatorID)
+}
+
+func (r *orderResolver) Executor(ctx context.Context) (*userResolver, error) {
+	if r.order.ExecutorID == nil {
+		return nil, nil
+	}
+	return r.root.loadUser(ctx, *r.order.ExecutorID)
+}
+
+func (r *orderResolver) Duration() *graphql.Time {
+	return timePtr(r.order.Duration)
+}
+
+func (r *orderResolver) CreatedAt() *graphql.Time {
+	return parseTime(r.order.CreatedAt)
+}
+
+func (r *orderResolver) UpdatedAt() *graphql.Time {
+	return parseTime(r.order.UpdatedAt)
+}
+
+func (r *orderResolver) CompletedAt() *graphql.Time {
+	return timePtr(r.order.CompletedAt)
+}
+
+func (r *orderResolver) ResolutionTimeSeconds() *int32 {
+	return uint64Ptr(r.order.ResolutionTimeSeconds)
+}
+
+func (r *orderResolver) FirstResponseTimeSeconds() *int32 {
+	return uint64Ptr(r.order.FirstResponseTimeSeconds)
+}
+
+func (r *orderResolver) Attachments(ctx context.Context) ([]*attachmentResolver, error) {
+	attachments, _, err := loadersFrom(ctx).attachments.load(ctx, r.order.ID)
+	if err != nil {
+		return nil, r.root.toResolverError(err)
+	}
+	result := make([]*attachmentResolver, 0, len(attachments))
+	for _, a := range attachments {
+		result = append(result, newAttachmentResolver(a))
+	}
+	return result, nil
+}
+
+func (r *orderResolver) LastComments(ctx context.Context, args struct{ Limit int32 }) ([]*commentResolver, error) {
+	limit := int(min(max(args.Limit, 0), maxLastComments))
+	if limit == 0 {
+		return []*commentResolver{}, nil
+	}
+
+	l := loadersFrom(ctx)
+	comments, _, err := l.lastComments(limit).load(ctx, r.order.ID)
+	if err != nil {
+		return nil, r.root.toResolverError(err)
+	}
+	result := make([]*commentResolver, 0, len(comments))
+	for _, comment := range comments {
+		l.userIDs.add(comment.UserID)
+		result = append(result, &commentResolver{root: r.root, item: comment})
+	}
+	return result, nil
+}
+
+func (r *orderResolver) History(ctx context.Context, args struct {
+	Page       int32
+	Limit      int32
+	EventTypes *[]string
+}) (*historyPageResolver, error) {
+	limit := int(min(max(args.Limit, 1), maxHistoryLimit))
+	page := int(max(args.Page, 1))
+
+	filter := repositories.HistoryPageFilter{Limit: limit, Offset: (page - 1) * limit}
+	if args.EventTypes != nil {
+		filter.EventTypes = *args.EventTypes
+	}
+
+	result, err := r.root.deps.HistoryService.GetHistoryEntries(ctx, r.order.ID, filter)
+	if err != nil {
+		return nil, r.root.toResolverError(err)
+	}
+
+	l := loadersFrom(ctx)
+	entries := &historyPageResolver{totalCount: result.Pagination.TotalCount, items: make([]*historyEntryResolver, 0, len(result.List))}
+	for _, entry := range result.List {
+		l.userIDs.add(entry.Actor.ID)
+		entries.items = append(entries.items, &historyEntryResolver{root: r.root, entry: entry})
+	}
+	return entries, nil
+}
+
+type attachmentResolver struct {
+	attachment dto.AttachmentResponseDTO
+}
+
+func newAttachmentResolver(a entities.Attachment) *attachmentResolver {
+	return &attachmentResolver{attachment: dto.AttachmentResponseDTO{ID: a.ID, FileName: a.FileName, URL: attachmentPrefix + a.FilePath}}
+}
+
+func (r *attachmentResolver) ID() graphql.ID {
+	return toID(r.attachment.ID)
+}
+
+func (r *attachmentResolver) FileName() string {
+	return r.attachment.FileName
+}
+
+func (r *attachmentResolver) URL() string {
+	return r.attachment.URL
+}
+
+type commentResolver struct {
+	root *Resolver
+	item repositories.OrderHistoryItem
+}
+
+func (r *commentResolver) ID() graphql.ID {
+	return toID(r.item.ID)
+}
+
+func (r *commentResolver) Text() string {
+	return r.item.Comment.String
+}
+
+func (r *commentResolver) Author(ctx context.Context) (*userResolver, error) {
+	return r.root.loadUser(ctx, r.item.UserID)
+}
+
+func (r *commentResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.item.CreatedAt}
+}
+
+func (r *commentResolver) Attachment() *attachmentResolver {
+	if r.item.Attachment == nil {
+		return nil
+	}
+	return newAttachmentResolver(*r.item.Attachment)
+}
+
+type historyPageResolver struct {
+	totalCount uint64
+	items      []*historyEntryResolver
+}
+
+func (p *historyPageResolver) TotalCount() int32 {
+	return int32(p.totalCount)
+}
+
+func (p *historyPageResolver) Items() []*historyEntryResolver {
+	return p.items
+}
+
+type historyEntryResolver struct {
+	root  *Resolver
+	entry dto.OrderHistoryEntryDTO
+}
+
+func (r *historyEntryResolver) ID() graphql.ID {
+	return toID(r.entry.ID)
+}
+
+func (r *historyEntryResolver) EventType() string {
+	return r.entry.EventType
+}
+
+func (r *historyEntryResolver) Actor(ctx context.Context) (*userResolver, error) {
+	if r.entry.Actor.ID == 0 {
+		return nil, nil
+	}
+	return r.root.loadUser(ctx, r.entry.Actor.ID)
+}
+
+func (r *historyEntryResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.entry.CreatedAt}
+}
+
+func (r *historyEntryResolver) Field() *string {
+	return stringPtr(r.entry.Field)
+}
+
+func (r *historyEntryResolver) OldValue() *string {
+	return r.entry.OldValue
+}
+
+func (r *historyEntryResolver) NewValue() *string {
+	return r.entry.NewValue
+}
+
+func (r *historyEntryResolver) OldLabel() *string {
+	return r.entry.OldLabel
+}
+
+func (r *historyEntryResolver) NewLabel() *string {
+	return r.entry.NewLabel
+}
+
+func (r *historyEntryResolver) Diff() string {
+	return r.entry.Diff
+}
+
+func (r *historyEntryResolver) Comment() *string {
+	return r.entry.Comment
+}
+
+func (r *historyEntryResolver) Attachment() *attachmentResolver {
+	if r.entry.Attachment == nil {
+		return nil
+	}
+	return &attachmentResolver{attachment: *r.entry.Attachment}
+}
+
+func timePtr(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+// parseTime — даты в DTO уже отформатированы строкой RFC3339 для JSON.
+func parseTime(value string) *graphql.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &graphql.Time{Time: t}
+}
+
+func uint64Ptr(value *uint64) *int32 {
+	if value == nil {
+		return nil
+	}
+	v := int32(*value)
+	return &v
+}
+
+func stringPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
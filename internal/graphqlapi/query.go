@@ -0,0 +1,199 @@
+package graphqlapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+	"request-system/pkg/utils"
+)
+
+const maxPageLimit = 100
+
+// Resolver — корневой резолвер Query.
+type Resolver struct {
+	deps   Dependencies
+	logger *zap.Logger
+}
+
+type filterInput struct {
+	Field string
+	Value string
+}
+
+// Аргументы со значением по умолчанию в схеме приходят всегда, поэтому без указателей.
+type ordersArgs struct {
+	Page         int32
+	Limit        int32
+	Search       *string
+	Filter       *[]filterInput
+	OnlyCreated  bool
+	OnlyAssigned bool
+	OnlyInvolved bool
+}
+
+type usersArgs struct {
+	Page   int32
+	Limit  int32
+	Search *string
+	Filter *[]filterInput
+}
+
+func (r *Resolver) Order(ctx context.Context, args struct{ ID graphql.ID }) (*orderResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	order, err := r.deps.OrderService.FindOrderByID(ctx, id)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	return newOrderResolver(ctx, r, order), nil
+}
+
+func (r *Resolver) Orders(ctx context.Context, args ordersArgs) (*orderPageResolver, error) {
+	filter := listFilter(args.Page, args.Limit, args.Search, args.Filter)
+	result, err := r.deps.OrderService.GetOrders(ctx, filter, args.OnlyCreated, args.OnlyAssigned, args.OnlyInvolved)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+
+	page := &orderPageResolver{totalCount: result.TotalCount, items: make([]*orderResolver, 0, len(result.List))}
+	for i := range result.List {
+		page.items = append(page.items, newOrderResolver(ctx, r, &result.List[i]))
+	}
+	return page, nil
+}
+
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	user, err := r.deps.UserService.FindUser(ctx, id)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	return &userResolver{user: *user}, nil
+}
+
+func (r *Resolver) Users(ctx context.Context, args usersArgs) (*userPageResolver, error) {
+	filter := listFilter(args.Page, args.Limit, args.Search, args.Filter)
+	list, total, err := r.deps.UserService.GetUsers(ctx, filter)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+
+	page := &userPageResolver{totalCount: total, items: make([]*userResolver, 0, len(list))}
+	for i := range list {
+		page.items = append(page.items, &userResolver{user: list[i]})
+	}
+	return page, nil
+}
+
+func (r *Resolver) Statuses(ctx context.Context) ([]*statusResolver, error) {
+	if err := requirePermission(ctx, authz.StatusesView); err != nil {
+		return nil, r.toResolverError(err)
+	}
+	list, err := loadersFrom(ctx).statuses.all(ctx)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	result := make([]*statusResolver, 0, len(list))
+	for _, status := range list {
+		result = append(result, &statusResolver{status: status})
+	}
+	return result, nil
+}
+
+func (r *Resolver) Priorities(ctx context.Context) ([]*priorityResolver, error) {
+	if err := requirePermission(ctx, authz.PrioritiesView); err != nil {
+		return nil, r.toResolverError(err)
+	}
+	list, err := loadersFrom(ctx).priorities.all(ctx)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	result := make([]*priorityResolver, 0, len(list))
+	for _, priority := range list {
+		result = append(result, &priorityResolver{priority: priority})
+	}
+	return result, nil
+}
+
+func (r *Resolver) Departments(ctx context.Context) ([]*departmentResolver, error) {
+	if err := requirePermission(ctx, authz.DepartmentsView); err != nil {
+		return nil, r.toResolverError(err)
+	}
+	list, err := loadersFrom(ctx).departments.all(ctx)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	result := make([]*departmentResolver, 0, len(list))
+	for _, department := range list {
+		result = append(result, &departmentResolver{department: department})
+	}
+	return result, nil
+}
+
+func (r *Resolver) OrderTypes(ctx context.Context) ([]*orderTypeResolver, error) {
+	if err := requirePermission(ctx, authz.OrderTypesView); err != nil {
+		return nil, r.toResolverError(err)
+	}
+	list, err := loadersFrom(ctx).orderTypes.all(ctx)
+	if err != nil {
+		return nil, r.toResolverError(err)
+	}
+	result := make([]*orderTypeResolver, 0, len(list))
+	for _, orderType := range list {
+		result = append(result, &orderTypeResolver{orderType: orderType})
+	}
+	return result, nil
+}
+
+func requirePermission(ctx context.Context, permission string) error {
+	permissions, err := utils.GetPermissionsMapFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	if !permissions[permission] {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
+
+func parseID(id graphql.ID) (uint64, error) {
+	value, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil || value == 0 {
+		return 0, apperrors.ErrBadRequest
+	}
+	return value, nil
+}
+
+// listFilter собирает те же query-параметры, что у REST-списков, чтобы фильтры разбирались одним кодом.
+func listFilter(page, limit int32, search *string, filters *[]filterInput) types.Filter {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(int(page)))
+	}
+	query.Set("limit", strconv.Itoa(int(min(max(limit, 1), maxPageLimit))))
+	if search != nil && *search != "" {
+		query.Set("search", *search)
+	}
+	if filters != nil {
+		for _, f := range *filters {
+			key, value := "filter["+f.Field+"]", f.Value
+			if existing := query.Get(key); existing != "" {
+				value = existing + "," + value
+			}
+			query.Set(key, value)
+		}
+	}
+	return utils.ParseFilterFromQuery(query)
+}
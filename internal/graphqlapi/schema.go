@@ -0,0 +1,73 @@
+// Package graphqlapi — GraphQL-эндпоинт /api/graphql для веб-клиента. Схема в schema.graphql,
+// данные берутся из тех же сервисов, что и у REST API, связанные сущности — через загрузчики
+// из loaders.go, чтобы список заявок с исполнителями и вложениями не превращался в N+1 запросов.
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+	gqllog "github.com/graph-gophers/graphql-go/log"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/internal/services"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	maxQueryDepth  = 8
+	maxQueryLength = 20000
+	maxParallelism = 16
+)
+
+type Dependencies struct {
+	OrderService   services.OrderServiceInterface
+	UserService    services.UserServiceInterface
+	HistoryService services.OrderHistoryServiceInterface
+
+	UserRepo       repositories.UserRepositoryInterface
+	AttachmentRepo repositories.AttachmentRepositoryInterface
+	HistoryRepo    repositories.OrderHistoryRepositoryInterface
+	StatusRepo     repositories.StatusRepositoryInterface
+	PriorityRepo   repositories.PriorityRepositoryInterface
+	DepartmentRepo repositories.DepartmentRepositoryInterface
+	OrderTypeRepo  repositories.OrderTypeRepositoryInterface
+}
+
+// Request — тело POST /api/graphql.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Executor struct {
+	schema *graphql.Schema
+	deps   Dependencies
+}
+
+func NewExecutor(deps Dependencies, logger *zap.Logger) (*Executor, error) {
+	root := &Resolver{deps: deps, logger: logger}
+	schema, err := graphql.ParseSchema(schemaSDL, root,
+		graphql.MaxDepth(maxQueryDepth),
+		graphql.MaxQueryLength(maxQueryLength),
+		graphql.MaxParallelism(maxParallelism),
+		graphql.Logger(gqllog.LoggerFunc(func(_ context.Context, value interface{}) {
+			logger.Error("GraphQL: паника в резолвере", zap.Any("panic", value))
+		})),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, deps: deps}, nil
+}
+
+// Execute выполняет запрос. Загрузчики живут один запрос: кэш не переживает его и не смешивает права разных пользователей.
+func (e *Executor) Execute(ctx context.Context, req Request) *graphql.Response {
+	ctx = context.WithValue(ctx, loadersKey{}, newLoaders(e.deps))
+	return e.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
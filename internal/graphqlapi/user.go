@@ -0,0 +1,115 @@
+package graphqlapi
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+)
+
+type userPageResolver struct {
+	totalCount uint64
+	items      []*userResolver
+}
+
+func (p *userPageResolver) TotalCount() int32 {
+	return int32(p.totalCount)
+}
+
+func (p *userPageResolver) Items() []*userResolver {
+	return p.items
+}
+
+type userResolver struct {
+	user dto.UserDTO
+}
+
+// loadUser — участник заявки или комментария. Его видно всем, кому видна сама заявка,
+// поэтому загрузка идёт из репозитория без проверки user:view.
+func (r *Resolver) loadUser(ctx context.Context, id uint64) (*userResolver, error) {
+	user, ok, err := loadersFrom(ctx).users.load(ctx, id)
+	if err != nil || !ok {
+		return nil, r.optionalError(err)
+	}
+	return &userResolver{user: userEntityToDTO(user)}, nil
+}
+
+func userEntityToDTO(e entities.User) dto.UserDTO {
+	d := dto.UserDTO{
+		ID:             e.ID,
+		Fio:            e.Fio,
+		Email:          e.Email,
+		PhoneNumber:    e.PhoneNumber,
+		Username:       e.Username,
+		StatusCode:     e.StatusCode,
+		PositionName:   e.PositionName,
+		DepartmentName: e.DepartmentName,
+		BranchName:     e.BranchName,
+		OtdelName:      e.OtdelName,
+		OfficeName:     e.OfficeName,
+		PhotoURL:       e.PhotoURL,
+	}
+	if e.IsHead != nil {
+		d.IsHead = *e.IsHead
+	}
+	return d
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return toID(r.user.ID)
+}
+
+func (r *userResolver) Fio() string {
+	return r.user.Fio
+}
+
+func (r *userResolver) Email() string {
+	return r.user.Email
+}
+
+func (r *userResolver) PhoneNumber() string {
+	return r.user.PhoneNumber
+}
+
+func (r *userResolver) Username() *string {
+	return r.user.Username
+}
+
+func (r *userResolver) StatusCode() *string {
+	return stringPtr(r.user.StatusCode)
+}
+
+func (r *userResolver) PositionName() *string {
+	return r.user.PositionName
+}
+
+func (r *userResolver) DepartmentName() *string {
+	return r.user.DepartmentName
+}
+
+func (r *userResolver) BranchName() *string {
+	return r.user.BranchName
+}
+
+func (r *userResolver) OtdelName() *string {
+	return r.user.OtdelName
+}
+
+func (r *userResolver) OfficeName() *string {
+	return r.user.OfficeName
+}
+
+func (r *userResolver) PhotoURL() *string {
+	return r.user.PhotoURL
+}
+
+func (r *userResolver) IsHead() bool {
+	return r.user.IsHead
+}
+
+func toID(id uint64) graphql.ID {
+	return graphql.ID(strconv.FormatUint(id, 10))
+}
@@ -0,0 +1,144 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"request-system/internal/repositories"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/service"
+	"request-system/pkg/utils"
+)
+
+const apiKeyMetadata = "x-api-key"
+
+type serviceToken struct {
+	key    []byte
+	userID uint64
+}
+
+// authenticator превращает метаданные вызова в тот же контекст пользователя, что и HTTP-авторизация:
+// JWT из authorization или сервисный ключ из x-api-key, за которым закреплён пользователь.
+type authenticator struct {
+	tokens                []serviceToken
+	jwtService            service.JWTService
+	authPermissionService services.AuthPermissionServiceInterface
+	userRepo              repositories.UserRepositoryInterface
+	logger                *zap.Logger
+}
+
+func newAuthenticator(rawTokens []string, jwtSvc service.JWTService, authPermissionService services.AuthPermissionServiceInterface, userRepo repositories.UserRepositoryInterface, logger *zap.Logger) (*authenticator, error) {
+	tokens := make([]serviceToken, 0, len(rawTokens))
+	for _, raw := range rawTokens {
+		if raw == "" {
+			continue
+		}
+		key, rawUserID, ok := strings.Cut(raw, ":")
+		key = strings.TrimSpace(key)
+		userID, err := strconv.ParseUint(strings.TrimSpace(rawUserID), 10, 64)
+		if !ok || key == "" || err != nil || userID == 0 {
+			return nil, fmt.Errorf("GRPC_SERVICE_TOKENS: ожидается формат ключ:userID")
+		}
+		tokens = append(tokens, serviceToken{key: []byte(key), userID: userID})
+	}
+	return &authenticator{tokens: tokens, jwtService: jwtSvc, authPermissionService: authPermissionService, userRepo: userRepo, logger: logger}, nil
+}
+
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	userID, roleID, tenantID, err := a.identify(ctx, md)
+	if err != nil {
+		a.logger.Warn("gRPC: ошибка аутентификации", zap.String("method", info.FullMethod), zap.Error(err))
+		return nil, toStatusError(err, a.logger)
+	}
+
+	permissions, err := a.authPermissionService.GetAllUserPermissions(ctx, userID)
+	if err != nil {
+		a.logger.Error("gRPC: ошибка получения прав пользователя", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "внутренняя ошибка сервера")
+	}
+
+	conditions, err := a.authPermissionService.GetUserPermissionConditions(ctx, userID)
+	if err != nil {
+		a.logger.Error("gRPC: ошибка получения условий прав пользователя", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "внутренняя ошибка сервера")
+	}
+
+	userCtx := utils.WithUserContext(ctx, userID, roleID, permissions)
+	userCtx = utils.WithTenantID(userCtx, tenantID)
+	return handler(utils.WithPermissionConditions(userCtx, conditions), req)
+}
+
+// identify возвращает пользователя, роль и организацию. Сервисный ключ работает в организации
+// закреплённого за ним пользователя; роль 0, как в токене после входа: права берутся из ролей пользователя.
+func (a *authenticator) identify(ctx context.Context, md metadata.MD) (uint64, uint64, uint64, error) {
+	if keys := md.Get(apiKeyMetadata); len(keys) > 0 && strings.TrimSpace(keys[0]) != "" {
+		provided := []byte(strings.TrimSpace(keys[0]))
+		var userID uint64
+		for _, token := range a.tokens {
+			if subtle.ConstantTimeCompare(provided, token.key) == 1 {
+				userID = token.userID
+			}
+		}
+		if userID == 0 {
+			return 0, 0, 0, status.Error(codes.Unauthenticated, "неверный сервисный ключ")
+		}
+		user, err := a.userRepo.FindUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, apperrors.ErrNotFound) {
+				return 0, 0, 0, status.Error(codes.Unauthenticated, "пользователь сервисного ключа не найден")
+			}
+			return 0, 0, 0, err
+		}
+		return user.ID, 0, user.TenantID, nil
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return 0, 0, 0, status.Error(codes.Unauthenticated, "не передан authorization или x-api-key")
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return 0, 0, 0, status.Error(codes.Unauthenticated, "неверный формат authorization")
+	}
+
+	claims, err := a.jwtService.ValidateToken(token)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if claims.IsRefreshToken {
+		return 0, 0, 0, status.Error(codes.Unauthenticated, "нужен access токен")
+	}
+	return claims.UserID, claims.RoleID, claims.TenantID, nil
+}
+
+// recoveryInterceptor не даёт панике в обработчике уронить процесс — как middleware.Recover у Echo.
+func recoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC: паника в обработчике",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				err = status.Error(codes.Internal, "внутренняя ошибка сервера")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,47 @@
+package grpcapi
+
+import (
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apperrors "request-system/pkg/errors"
+)
+
+// toStatusError переводит ошибки сервисов (apperrors.HttpError) в коды gRPC.
+// Внутренние детали наружу не отдаются, как и в utils.ErrorResponse.
+func toStatusError(err error, logger *zap.Logger) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) {
+		logger.Error("gRPC: необработанная ошибка", zap.Error(err))
+		return status.Error(codes.Internal, "внутренняя ошибка сервера")
+	}
+
+	code := codes.Internal
+	switch httpErr.Code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	}
+	if code == codes.Internal {
+		logger.Error("gRPC: внутренняя ошибка", zap.Error(err))
+		return status.Error(code, "внутренняя ошибка сервера")
+	}
+	return status.Error(code, httpErr.Message)
+}
@@ -0,0 +1,113 @@
+package grpcapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	requestsystemv1 "request-system/pkg/grpcapi/requestsystem/v1"
+	"request-system/pkg/utils"
+)
+
+type orderServer struct {
+	requestsystemv1.UnimplementedOrderServiceServer
+	orderService services.OrderServiceInterface
+	logger       *zap.Logger
+}
+
+func (s *orderServer) GetOrder(ctx context.Context, req *requestsystemv1.GetOrderRequest) (*requestsystemv1.Order, error) {
+	order, err := s.orderService.FindOrderByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatusError(err, s.logger)
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderServer) ListOrders(ctx context.Context, req *requestsystemv1.ListOrdersRequest) (*requestsystemv1.ListOrdersResponse, error) {
+	filter := utils.ParseFilterFromQuery(listQuery(req.GetPage(), req.GetLimit(), req.GetSearch(), req.GetFilters()))
+
+	result, err := s.orderService.GetOrders(ctx, filter, req.GetOnlyCreated(), req.GetOnlyAssigned(), req.GetOnlyInvolved())
+	if err != nil {
+		return nil, toStatusError(err, s.logger)
+	}
+
+	orders := make([]*requestsystemv1.Order, 0, len(result.List))
+	for i := range result.List {
+		orders = append(orders, orderToProto(&result.List[i]))
+	}
+	return &requestsystemv1.ListOrdersResponse{Orders: orders, TotalCount: result.TotalCount}, nil
+}
+
+// listQuery собирает из запроса те же query-параметры, что приходят в HTTP API,
+// чтобы фильтрация и пагинация разбирались одним кодом.
+func listQuery(page, limit int32, search string, filters map[string]string) url.Values {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(int(page)))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(int(limit)))
+	}
+	if search != "" {
+		query.Set("search", search)
+	}
+	for field, value := range filters {
+		query.Set("filter["+field+"]", value)
+	}
+	return query
+}
+
+func orderToProto(o *dto.OrderResponseDTO) *requestsystemv1.Order {
+	attachments := make([]*requestsystemv1.Attachment, 0, len(o.Attachments))
+	for _, a := range o.Attachments {
+		attachments = append(attachments, &requestsystemv1.Attachment{Id: a.ID, FileName: a.FileName, Url: a.URL})
+	}
+
+	return &requestsystemv1.Order{
+		Id:                       o.ID,
+		Name:                     o.Name,
+		StatusId:                 o.StatusID,
+		PriorityId:               o.PriorityID,
+		OrderTypeId:              o.OrderTypeID,
+		Address:                  o.Address,
+		CreatorId:                o.CreatorID,
+		CreatorName:              o.CreatorName,
+		ExecutorId:               o.ExecutorID,
+		ExecutorName:             o.ExecutorName,
+		DepartmentId:             o.DepartmentID,
+		OtdelId:                  o.OtdelID,
+		BranchId:                 o.BranchID,
+		OfficeId:                 o.OfficeID,
+		EquipmentId:              o.EquipmentID,
+		EquipmentTypeId:          o.EquipmentTypeID,
+		Duration:                 timePtrToProto(o.Duration),
+		CreatedAt:                rfc3339ToProto(o.CreatedAt),
+		UpdatedAt:                rfc3339ToProto(o.UpdatedAt),
+		CompletedAt:              timePtrToProto(o.CompletedAt),
+		ResolutionTimeSeconds:    o.ResolutionTimeSeconds,
+		FirstResponseTimeSeconds: o.FirstResponseTimeSeconds,
+		Attachments:              attachments,
+	}
+}
+
+func timePtrToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// rfc3339ToProto — даты в DTO уже отформатированы строкой для JSON.
+func rfc3339ToProto(value string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,84 @@
+// Package grpcapi — gRPC-вход для внутренних сервисов банка: чтение заявок и пользователей.
+// Контракт описан в proto/requestsystem/v1, бизнес-логика и проверки прав — те же сервисы, что у HTTP API.
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	"request-system/internal/repositories"
+	"request-system/internal/services"
+	"request-system/pkg/config"
+	requestsystemv1 "request-system/pkg/grpcapi/requestsystem/v1"
+	"request-system/pkg/service"
+)
+
+type Server struct {
+	server *grpc.Server
+	port   string
+	logger *zap.Logger
+}
+
+func NewServer(
+	cfg config.GRPCConfig,
+	serverCfg config.ServerConfig,
+	orderService services.OrderServiceInterface,
+	userService services.UserServiceInterface,
+	jwtSvc service.JWTService,
+	authPermissionService services.AuthPermissionServiceInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) (*Server, error) {
+	auth, err := newAuthenticator(cfg.ServiceTokens, jwtSvc, authPermissionService, userRepo, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(recoveryInterceptor(logger), auth.unaryInterceptor),
+	}
+	if cfg.TLS {
+		cert, err := tls.LoadX509KeyPair(serverCfg.CertFile, serverCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("gRPC: не удалось загрузить сертификат: %w", err)
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})))
+	}
+
+	server := grpc.NewServer(options...)
+	requestsystemv1.RegisterOrderServiceServer(server, &orderServer{orderService: orderService, logger: logger})
+	requestsystemv1.RegisterUserServiceServer(server, &userServer{userService: userService, logger: logger})
+	reflection.Register(server)
+
+	return &Server{server: server, port: cfg.Port, logger: logger}, nil
+}
+
+// Start слушает порт до отмены ctx, затем дожидается текущих вызовов.
+func (s *Server) Start(ctx context.Context) {
+	lis, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		s.logger.Error("gRPC: не удалось открыть порт", zap.String("port", s.port), zap.Error(err))
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.logger.Info("gRPC: остановка сервера")
+		s.server.GracefulStop()
+	}()
+
+	s.logger.Info("gRPC сервер запущен", zap.String("port", s.port))
+	if err := s.server.Serve(lis); err != nil {
+		s.logger.Error("gRPC: сервер завершился с ошибкой", zap.Error(err))
+	}
+}
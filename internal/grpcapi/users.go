@@ -0,0 +1,67 @@
+package grpcapi
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/services"
+	requestsystemv1 "request-system/pkg/grpcapi/requestsystem/v1"
+	"request-system/pkg/utils"
+)
+
+type userServer struct {
+	requestsystemv1.UnimplementedUserServiceServer
+	userService services.UserServiceInterface
+	logger      *zap.Logger
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *requestsystemv1.GetUserRequest) (*requestsystemv1.User, error) {
+	user, err := s.userService.FindUser(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatusError(err, s.logger)
+	}
+	return userToProto(user), nil
+}
+
+func (s *userServer) ListUsers(ctx context.Context, req *requestsystemv1.ListUsersRequest) (*requestsystemv1.ListUsersResponse, error) {
+	filter := utils.ParseFilterFromQuery(listQuery(req.GetPage(), req.GetLimit(), req.GetSearch(), req.GetFilters()))
+
+	list, total, err := s.userService.GetUsers(ctx, filter)
+	if err != nil {
+		return nil, toStatusError(err, s.logger)
+	}
+
+	users := make([]*requestsystemv1.User, 0, len(list))
+	for i := range list {
+		users = append(users, userToProto(&list[i]))
+	}
+	return &requestsystemv1.ListUsersResponse{Users: users, TotalCount: total}, nil
+}
+
+func userToProto(u *dto.UserDTO) *requestsystemv1.User {
+	return &requestsystemv1.User{
+		Id:             u.ID,
+		Fio:            u.Fio,
+		Email:          u.Email,
+		PhoneNumber:    u.PhoneNumber,
+		Username:       u.Username,
+		StatusId:       u.StatusID,
+		StatusCode:     u.StatusCode,
+		PositionId:     u.PositionID,
+		PositionName:   u.PositionName,
+		BranchId:       u.BranchID,
+		BranchName:     u.BranchName,
+		DepartmentId:   u.DepartmentID,
+		DepartmentName: u.DepartmentName,
+		OtdelId:        u.OtdelID,
+		OtdelName:      u.OtdelName,
+		OfficeId:       u.OfficeID,
+		OfficeName:     u.OfficeName,
+		RoleIds:        u.RoleIDs,
+		IsHead:         u.IsHead,
+		CreatedAt:      rfc3339ToProto(u.CreatedAt),
+		UpdatedAt:      rfc3339ToProto(u.UpdatedAt),
+	}
+}
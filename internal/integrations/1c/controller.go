@@ -31,16 +31,15 @@ func (c *Controller) HandleReferencesWebhook(ctx echo.Context) error {
 		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
-	accepted, err := c.syncService.Enqueue1CReferences(ctx.Request().Context(), payload)
+	job, err := c.syncService.Enqueue1CReferences(ctx.Request().Context(), payload, services.SyncJobOptions{
+		DryRun:     ctx.QueryParam("dry_run") == "true",
+		Snapshot:   ctx.QueryParam("snapshot") == "true",
+		BestEffort: ctx.QueryParam("best_effort") == "true",
+	})
 	if err != nil {
 		c.logger.Error("Не удалось поставить синхронизацию 1С в обработку", zap.Error(err))
 		return utils.ErrorResponse(ctx, err, c.logger)
 	}
 
-	message := "Запрос принят в обработку"
-	if !accepted {
-		message = "Синхронизация 1С уже выполняется"
-	}
-
-	return utils.SuccessResponse(ctx, nil, message, http.StatusAccepted)
+	return utils.SuccessResponse(ctx, job, "Запрос принят в обработку", http.StatusAccepted)
 }
@@ -0,0 +1,53 @@
+package listeners
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/events"
+	"request-system/internal/services"
+	"request-system/pkg/eventbus"
+)
+
+// ChatConnectorListener публикует события заявок в каналы Teams и Slack.
+type ChatConnectorListener struct {
+	chatService services.ChatConnectorServiceInterface
+	logger      *zap.Logger
+}
+
+func NewChatConnectorListener(chatService services.ChatConnectorServiceInterface, logger *zap.Logger) *ChatConnectorListener {
+	return &ChatConnectorListener{chatService: chatService, logger: logger}
+}
+
+func (l *ChatConnectorListener) Register(bus *eventbus.Bus) {
+	bus.Subscribe("order.history.created", l.handleOrderHistoryCreated)
+	l.logger.Info("ChatConnectorListener подписан на событие 'order.history.created'")
+}
+
+func (l *ChatConnectorListener) handleOrderHistoryCreated(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(events.OrderHistoryCreatedEvent)
+	// Переигранные администратором события людям в чат повторно не отправляем.
+	if !ok || e.Replayed {
+		return nil
+	}
+	eventType, ok := services.WebhookEventForHistory(e.HistoryItem.EventType)
+	if !ok {
+		return nil
+	}
+
+	chatEvent := services.ChatOrderEvent{EventType: eventType, OrderID: e.HistoryItem.OrderID}
+	if e.HistoryItem.EventType == "COMMENT" && e.HistoryItem.Comment.Valid {
+		chatEvent.Comment = e.HistoryItem.Comment.String
+	}
+	if actor, ok := e.Actor.(*entities.User); ok && actor != nil {
+		chatEvent.ActorName = actor.Fio
+	}
+
+	if err := l.chatService.NotifyOrderEvent(ctx, chatEvent); err != nil {
+		l.logger.Error("Не удалось отправить событие заявки в каналы",
+			zap.Uint64("orderID", chatEvent.OrderID), zap.String("event", eventType), zap.Error(err))
+	}
+	return nil
+}
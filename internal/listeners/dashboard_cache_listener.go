@@ -0,0 +1,52 @@
+package listeners
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/events"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/eventbus"
+)
+
+// dashboardActivityOnlyEvents не меняют агрегаты дашборда, только ленту последних действий.
+var dashboardActivityOnlyEvents = map[string]bool{
+	"COMMENT":        true,
+	"ATTACHMENT_ADD": true,
+}
+
+// DashboardCacheListener сбрасывает кеш дашборда по каждому событию истории заявки:
+// так блоки обновляются и после изменений, прошедших мимо OrderService (Telegram, интеграции).
+type DashboardCacheListener struct {
+	cache  repositories.CacheRepositoryInterface
+	logger *zap.Logger
+}
+
+func NewDashboardCacheListener(cache repositories.CacheRepositoryInterface, logger *zap.Logger) *DashboardCacheListener {
+	return &DashboardCacheListener{cache: cache, logger: logger}
+}
+
+func (l *DashboardCacheListener) Register(bus *eventbus.Bus) {
+	bus.Subscribe("order.history.created", l.handleOrderHistoryCreated)
+	l.logger.Info("DashboardCacheListener подписан на событие 'order.history.created'")
+}
+
+func (l *DashboardCacheListener) handleOrderHistoryCreated(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(events.OrderHistoryCreatedEvent)
+	if !ok || e.Replayed {
+		return nil
+	}
+
+	keys := []string{pkgconstants.DashboardCacheVersionActivityKey}
+	if !dashboardActivityOnlyEvents[e.HistoryItem.EventType] {
+		keys = append(keys, pkgconstants.DashboardCacheVersionSummaryKey)
+	}
+	for _, key := range keys {
+		if _, err := l.cache.Incr(ctx, key); err != nil {
+			l.logger.Warn("Не удалось сбросить кеш дашборда", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,7 +19,10 @@ import (
 	"request-system/internal/services"
 	"request-system/pkg/config"
 	"request-system/pkg/eventbus"
+	"request-system/pkg/i18n"
+	"request-system/pkg/sanitize"
 	"request-system/pkg/telegram"
+	"request-system/pkg/utils"
 	"request-system/pkg/websocket"
 )
 
@@ -32,39 +36,52 @@ type eventGroup struct {
 	timer  *time.Timer
 }
 
+// digestFlushInterval — период дайджеста: сообщения копятся до начала следующего часа.
+const digestFlushInterval = time.Hour
+
 type NotificationListener struct {
-	notificationService   services.NotificationServiceInterface
-	wsNotificationService services.WebSocketNotificationServiceInterface
-	userRepo              repositories.UserRepositoryInterface
-	statusRepo            repositories.StatusRepositoryInterface
-	priorityRepo          repositories.PriorityRepositoryInterface
-	frontendCfg           config.FrontendConfig
-	serverCfg             config.ServerConfig
-	logger                *zap.Logger
-	groups                map[eventGroupKey]*eventGroup
-	groupsMu              sync.Mutex
+	outbox             services.NotificationOutboxServiceInterface
+	notificationCenter services.NotificationCenterServiceInterface
+	userRepo           repositories.UserRepositoryInterface
+	preferenceRepo     repositories.NotificationPreferenceRepositoryInterface
+	statuses           services.StatusDirectoryInterface
+	priorityRepo       repositories.PriorityRepositoryInterface
+	frontendCfg        config.FrontendConfig
+	serverCfg          config.ServerConfig
+	notifyCfg          config.NotificationsConfig
+	logger             *zap.Logger
+	groups             map[eventGroupKey]*eventGroup
+	groupsMu           sync.Mutex
+
+	// inflight считает группы, ожидающие таймера или отправляемые прямо сейчас; draining — идёт остановка
+	inflight sync.WaitGroup
+	draining bool
 }
 
 func NewNotificationListener(
-	notificationService services.NotificationServiceInterface,
-	wsNotificationService services.WebSocketNotificationServiceInterface,
+	outbox services.NotificationOutboxServiceInterface,
+	notificationCenter services.NotificationCenterServiceInterface,
 	userRepo repositories.UserRepositoryInterface,
-	statusRepo repositories.StatusRepositoryInterface,
+	preferenceRepo repositories.NotificationPreferenceRepositoryInterface,
+	statuses services.StatusDirectoryInterface,
 	priorityRepo repositories.PriorityRepositoryInterface,
 	frontendCfg config.FrontendConfig,
 	serverCfg config.ServerConfig,
+	notifyCfg config.NotificationsConfig,
 	logger *zap.Logger,
 ) *NotificationListener {
 	return &NotificationListener{
-		notificationService:   notificationService,
-		wsNotificationService: wsNotificationService,
-		userRepo:              userRepo,
-		statusRepo:            statusRepo,
-		priorityRepo:          priorityRepo,
-		frontendCfg:           frontendCfg,
-		serverCfg:             serverCfg,
-		logger:                logger,
-		groups:                make(map[eventGroupKey]*eventGroup),
+		outbox:             outbox,
+		notificationCenter: notificationCenter,
+		userRepo:           userRepo,
+		preferenceRepo:     preferenceRepo,
+		statuses:           statuses,
+		priorityRepo:       priorityRepo,
+		frontendCfg:        frontendCfg,
+		serverCfg:          serverCfg,
+		notifyCfg:          notifyCfg,
+		logger:             logger,
+		groups:             make(map[eventGroupKey]*eventGroup),
 	}
 }
 
@@ -84,14 +101,43 @@ func (l *NotificationListener) handleOrderHistoryCreated(ctx context.Context, ev
 		TxID:    e.HistoryItem.TxID.String(),
 	}
 
+	immediate := l.notifyCfg.GroupingWindow <= 0 || l.bypassesGrouping(ctx, e)
+
 	l.groupsMu.Lock()
+	if l.draining {
+		// Приложение останавливается: таймер уже некому дождаться, отправляем событие сразу
+		l.groupsMu.Unlock()
+		l.deliverGroup(context.WithoutCancel(ctx), key, &eventGroup{events: []events.OrderHistoryCreatedEvent{e}})
+		return nil
+	}
+	if immediate {
+		// Срочное событие не ждёт окна и забирает с собой уже накопленные события той же правки
+		batch := &eventGroup{}
+		if group, exists := l.groups[key]; exists {
+			delete(l.groups, key)
+			batch.events = group.events
+			// Если таймер уже сработал, он найдёт группу удалённой и сам снимет свой inflight
+			if group.timer.Stop() {
+				l.inflight.Done()
+			}
+		}
+		batch.events = append(batch.events, e)
+		l.inflight.Add(1)
+		l.groupsMu.Unlock()
+
+		defer l.inflight.Done()
+		l.deliverGroup(context.WithoutCancel(ctx), key, batch)
+		return nil
+	}
 	defer l.groupsMu.Unlock()
 
 	group, exists := l.groups[key]
 	if !exists {
 		group = &eventGroup{}
 		l.groups[key] = group
-		group.timer = time.AfterFunc(2*time.Second, func() {
+		l.inflight.Add(1)
+		group.timer = time.AfterFunc(l.notifyCfg.GroupingWindow, func() {
+			defer l.inflight.Done()
 			l.sendGroupedNotification(context.Background(), key)
 		})
 	}
@@ -102,6 +148,48 @@ func (l *NotificationListener) handleOrderHistoryCreated(ctx context.Context, ev
 	return nil
 }
 
+// bypassesGrouping — событие из NOTIFY_IMMEDIATE_EVENTS или смена приоритета на один из
+// NOTIFY_IMMEDIATE_PRIORITIES: о них сообщаем без окна группировки.
+func (l *NotificationListener) bypassesGrouping(ctx context.Context, e events.OrderHistoryCreatedEvent) bool {
+	item := e.HistoryItem
+	if slices.Contains(l.notifyCfg.ImmediateEvents, item.EventType) {
+		return true
+	}
+	if item.EventType != "PRIORITY_CHANGE" || len(l.notifyCfg.ImmediatePriorities) == 0 || !item.NewValue.Valid {
+		return false
+	}
+	prioID, err := strconv.ParseUint(item.NewValue.String, 10, 64)
+	if err != nil {
+		return false
+	}
+	prio, _ := l.priorityRepo.FindByID(ctx, prioID)
+	return prio != nil && slices.Contains(l.notifyCfg.ImmediatePriorities, strings.ToUpper(prio.Code))
+}
+
+// Drain вызывается при остановке приложения: сразу отправляет группы, ожидающие таймера,
+// и ждёт уже начатые отправки, но не дольше ctx.
+func (l *NotificationListener) Drain(ctx context.Context) error {
+	l.groupsMu.Lock()
+	l.draining = true
+	keys := make([]eventGroupKey, 0, len(l.groups))
+	for key, group := range l.groups {
+		// Если таймер уже сработал, группу отправит он сам
+		if group.timer.Stop() {
+			keys = append(keys, key)
+		}
+	}
+	l.groupsMu.Unlock()
+
+	if len(keys) > 0 {
+		l.logger.Info("Остановка: отправка накопленных групп уведомлений", zap.Int("groups", len(keys)))
+	}
+	for _, key := range keys {
+		l.sendGroupedNotification(ctx, key)
+		l.inflight.Done()
+	}
+	return utils.WaitWithContext(ctx, &l.inflight)
+}
+
 func (l *NotificationListener) sendGroupedNotification(ctx context.Context, key eventGroupKey) {
 	l.groupsMu.Lock()
 	group, exists := l.groups[key]
@@ -112,6 +200,10 @@ func (l *NotificationListener) sendGroupedNotification(ctx context.Context, key
 	delete(l.groups, key)
 	l.groupsMu.Unlock()
 
+	l.deliverGroup(ctx, key, group)
+}
+
+func (l *NotificationListener) deliverGroup(ctx context.Context, key eventGroupKey, group *eventGroup) {
 	if len(group.events) == 0 {
 		return
 	}
@@ -125,33 +217,101 @@ func (l *NotificationListener) sendGroupedNotification(ctx context.Context, key
 		return
 	}
 
+	prefs := l.loadPreferences(ctx, recipients)
+	now := time.Now()
+
 	for _, user := range recipients {
-		message := l.formatGroupedMessage(ctx, group.events, &user)
-		if message == "" {
+		pref := prefs[user.ID]
+		visible := filterMutedEvents(group.events, pref)
+		if len(visible) == 0 {
 			continue
 		}
 
-		if !user.TelegramChatID.Valid || user.TelegramChatID.Int64 == 0 {
-			continue
+		// Глобальные выключатели каналов (NOTIFY_*_ENABLED) перечитываются без перезапуска
+		if pref.TelegramEnabled && l.notifyCfg.TelegramActive() && user.TelegramChatID.Valid && user.TelegramChatID.Int64 != 0 {
+			message := l.formatGroupedMessage(ctx, visible, &user)
+			if message != "" {
+				if deliverAt := deferredUntil(pref, now); deliverAt.After(now) {
+					if err := l.outbox.DeferTelegram(ctx, user.ID, user.TelegramChatID.Int64, user.Language, message, deliverAt); err != nil {
+						l.logger.Error("Не удалось отложить сгруппированное уведомление", zap.Uint64("userID", user.ID), zap.Error(err))
+					}
+				} else if err := l.outbox.EnqueueTelegram(ctx, user.ID, user.TelegramChatID.Int64, message); err != nil {
+					l.logger.Error("Не удалось поставить в очередь сгруппированное уведомление", zap.Uint64("userID", user.ID), zap.Error(err))
+				}
+			}
 		}
 
-		if err := l.notificationService.SendFormattedMessage(ctx, user.TelegramChatID.Int64, message); err != nil {
-			l.logger.Error("Не удалось отправить сгруппированное уведомление", zap.Uint64("userID", user.ID), zap.Error(err))
+		// WebSocket доставляется только в открытое приложение, поэтому тихие часы и дайджест на него не влияют.
+		if !pref.WebSocketEnabled {
+			continue
 		}
-		payload, err := l.formatWebSocketPayload(ctx, group.events, &user)
+		payload, err := l.formatWebSocketPayload(ctx, visible, &user)
 		if err != nil {
 			l.logger.Error("Не удалось сформировать WebSocket payload", zap.Uint64("userID", user.ID), zap.Error(err))
 			continue
 		}
 		if payload != nil {
-			err := l.wsNotificationService.SendNotification(user.ID, payload, "notification")
+			// Сначала сохраняем в центр уведомлений, чтобы в push ушёл ID записи для отметки "прочитано".
+			orderID := group.events[0].HistoryItem.OrderID
+			if err := l.notificationCenter.Save(ctx, user.ID, &orderID, payload); err != nil {
+				l.logger.Error("Не удалось сохранить уведомление в центр уведомлений", zap.Uint64("userID", user.ID), zap.Error(err))
+			}
+			if !l.notifyCfg.WebSocketActive() {
+				continue
+			}
+			err := l.outbox.EnqueueWebSocket(ctx, user.ID, payload, "notification")
 			if err != nil {
-				l.logger.Error("Не удалось отправить WebSocket-уведомление", zap.Uint64("userID", user.ID), zap.Error(err))
+				l.logger.Error("Не удалось поставить в очередь WebSocket-уведомление", zap.Uint64("userID", user.ID), zap.Error(err))
 			}
 		}
 	}
 }
 
+// loadPreferences загружает настройки уведомлений получателей. При ошибке используются настройки по умолчанию,
+// чтобы сбой чтения настроек не останавливал доставку.
+func (l *NotificationListener) loadPreferences(ctx context.Context, recipients []entities.User) map[uint64]*entities.NotificationPreference {
+	ids := make([]uint64, 0, len(recipients))
+	for _, user := range recipients {
+		ids = append(ids, user.ID)
+	}
+
+	prefs, err := l.preferenceRepo.FindByUserIDs(ctx, ids)
+	if err != nil {
+		l.logger.Warn("Не удалось загрузить настройки уведомлений, используются значения по умолчанию", zap.Error(err))
+		prefs = make(map[uint64]*entities.NotificationPreference, len(ids))
+	}
+	for _, id := range ids {
+		if _, ok := prefs[id]; !ok {
+			prefs[id] = entities.DefaultNotificationPreference(id)
+		}
+	}
+	return prefs
+}
+
+func filterMutedEvents(groupEvents []events.OrderHistoryCreatedEvent, pref *entities.NotificationPreference) []events.OrderHistoryCreatedEvent {
+	if len(pref.MutedEventTypes) == 0 {
+		return groupEvents
+	}
+	visible := make([]events.OrderHistoryCreatedEvent, 0, len(groupEvents))
+	for _, e := range groupEvents {
+		if !pref.IsEventMuted(e.HistoryItem.EventType) {
+			visible = append(visible, e)
+		}
+	}
+	return visible
+}
+
+// deferredUntil — когда отправить Telegram-сообщение. В режиме дайджеста это начало следующего часа:
+// слот одинаков на всех репликах, и outbox соберёт их сообщения в одну сводку. Тихие часы сдвигают
+// отправку на их окончание. Результат, равный now, означает «без задержки».
+func deferredUntil(pref *entities.NotificationPreference, now time.Time) time.Time {
+	at := now
+	if pref.DeliveryMode == entities.NotificationDeliveryDigest {
+		at = now.Truncate(digestFlushInterval).Add(digestFlushInterval)
+	}
+	return pref.QuietHoursOver(at)
+}
+
 func (l *NotificationListener) determineRecipients(ctx context.Context, groupEvents []events.OrderHistoryCreatedEvent) ([]entities.User, error) {
 	if len(groupEvents) == 0 {
 		return nil, nil
@@ -222,9 +382,10 @@ func (l *NotificationListener) formatGroupedMessage(ctx context.Context, events
 		return ""
 	}
 
+	lang := i18n.Normalize(recipient.Language)
 	actorName := escape(actor.Fio)
 	orderName := escape(order.Name)
-	orderLink := fmt.Sprintf("[Посмотреть мои заявки](%s/order?participant=me)", l.frontendCfg.BaseURL)
+	orderLink := i18n.T(lang, "notify.view_orders", l.frontendCfg.BaseURL)
 
 	var sb strings.Builder
 	var mainAction string
@@ -235,10 +396,10 @@ func (l *NotificationListener) formatGroupedMessage(ctx context.Context, events
 		item := e.HistoryItem
 		switch item.EventType {
 		case "CREATE":
-			mainAction = fmt.Sprintf("✅ %s создал\\(а\\) новую заявку №%d\n*%s*", actorName, order.ID, orderName)
+			mainAction = i18n.T(lang, "notify.created", actorName, escape(order.DisplayNumber()), orderName)
 		case "STATUS_CHANGE":
 			if statusID, err := strconv.ParseUint(item.NewValue.String, 10, 64); err == nil {
-				if status, _ := l.statusRepo.FindStatus(ctx, statusID); status != nil {
+				if status, _ := l.statuses.FindByID(ctx, statusID); status != nil {
 					details["Статус"] = escape(status.Name)
 				}
 			}
@@ -252,7 +413,7 @@ func (l *NotificationListener) formatGroupedMessage(ctx context.Context, events
 			if execID, err := strconv.ParseUint(item.NewValue.String, 10, 64); err == nil {
 				if newExecutor, _ := l.userRepo.FindUserByID(ctx, execID); newExecutor != nil {
 					if newExecutor.ID == recipient.ID {
-						details["Назначено"] = "Вам"
+						details["Назначено"] = i18n.T(lang, "notify.assigned_to_you")
 					} else {
 						details["Назначено"] = escape(newExecutor.Fio)
 					}
@@ -264,18 +425,18 @@ func (l *NotificationListener) formatGroupedMessage(ctx context.Context, events
 			}
 		case "DURATION_CHANGE":
 			if parsedTime, err := time.Parse(time.RFC3339, item.NewValue.String); err == nil {
-				details["Срок"] = escape(parsedTime.Format("02.01.2006 15:04"))
+				details["Срок"] = escape(parsedTime.In(utils.UserLocation(recipient.Timezone)).Format("02.01.2006 15:04"))
 			}
 		case "ATTACHMENT_ADD":
 			if item.Attachment != nil {
 				fileURL := l.serverCfg.BaseURL + "/uploads/" + item.Attachment.FilePath
-				attachmentText = fmt.Sprintf("📎 Прикреплен файл: [%s](%s)", escape(item.Attachment.FileName), fileURL)
+				attachmentText = i18n.T(lang, "notify.attachment", escape(item.Attachment.FileName), fileURL)
 			}
 		}
 	}
 
 	if mainAction == "" {
-		mainAction = fmt.Sprintf("🔄 %s обновил\\(а\\) заявку №%d\n*%s*", actorName, order.ID, orderName)
+		mainAction = i18n.T(lang, "notify.updated", actorName, escape(order.DisplayNumber()), orderName)
 	}
 
 	sb.WriteString(mainAction + "\n\n")
@@ -284,10 +445,10 @@ func (l *NotificationListener) formatGroupedMessage(ctx context.Context, events
 		var detailLines []string
 		orderOfKeys := []string{"Статус", "Приоритет", "Назначено", "Срок"}
 		labelMap := map[string]string{
-			"Статус":    "Статус",
-			"Приоритет": "Приоритет",
-			"Назначено": "Исполнитель",
-			"Срок":      "Срок выполнения",
+			"Статус":    i18n.T(lang, "notify.status"),
+			"Приоритет": i18n.T(lang, "notify.priority"),
+			"Назначено": i18n.T(lang, "notify.executor"),
+			"Срок":      i18n.T(lang, "notify.deadline"),
 		}
 
 		for _, key := range orderOfKeys {
@@ -328,9 +489,12 @@ func (l *NotificationListener) formatWebSocketPayload(ctx context.Context, event
 		return nil, fmt.Errorf("сущность Order не была передана в событии")
 	}
 
-	mainMessage := fmt.Sprintf("<strong>%s</strong> обновил(а) заявку <strong>%s №%d</strong>", actor.Fio, order.Name, order.ID)
+	// Message и Changes — HTML: всё, что ввёл пользователь, экранируется.
+	escape := sanitize.HTML
+	lang := recipient.Language
+	mainMessage := i18n.T(lang, "notify.ws.updated", escape(actor.Fio), escape(order.Name), escape(order.DisplayNumber()))
 	if len(events) == 1 && events[0].HistoryItem.EventType == "CREATE" {
-		mainMessage = fmt.Sprintf("<strong>%s</strong> создал(а) новую заявку <strong>%s №%d</strong>", actor.Fio, order.Name, order.ID)
+		mainMessage = i18n.T(lang, "notify.ws.created", escape(actor.Fio), escape(order.Name), escape(order.DisplayNumber()))
 	}
 
 	var changes []websocket.ChangeInfo
@@ -341,26 +505,26 @@ func (l *NotificationListener) formatWebSocketPayload(ctx context.Context, event
 		switch item.EventType {
 		case "STATUS_CHANGE":
 			if statusID, err := strconv.ParseUint(item.NewValue.String, 10, 64); err == nil {
-				if status, _ := l.statusRepo.FindStatus(ctx, statusID); status != nil {
-					changes = append(changes, websocket.ChangeInfo{Type: "STATUS_CHANGE", Text: fmt.Sprintf("Статус: <strong>%s</strong>", status.Name)})
+				if status, _ := l.statuses.FindByID(ctx, statusID); status != nil {
+					changes = append(changes, websocket.ChangeInfo{Type: "STATUS_CHANGE", Text: i18n.T(lang, "notify.ws.field", i18n.Key("notify.status"), escape(status.Name))})
 				}
 			}
 		case "PRIORITY_CHANGE":
 			if prioID, err := strconv.ParseUint(item.NewValue.String, 10, 64); err == nil {
 				if prio, _ := l.priorityRepo.FindByID(ctx, prioID); prio != nil {
-					changes = append(changes, websocket.ChangeInfo{Type: "PRIORITY_CHANGE", Text: fmt.Sprintf("Приоритет: <strong>%s</strong>", prio.Name)})
+					changes = append(changes, websocket.ChangeInfo{Type: "PRIORITY_CHANGE", Text: i18n.T(lang, "notify.ws.field", i18n.Key("notify.priority"), escape(prio.Name))})
 				}
 			}
 		case "COMMENT":
 			if item.Comment.Valid {
-				changes = append(changes, websocket.ChangeInfo{Type: "COMMENT", Text: fmt.Sprintf("Комментарий: \"%s\"", item.Comment.String)})
+				changes = append(changes, websocket.ChangeInfo{Type: "COMMENT", Text: i18n.T(lang, "notify.ws.comment", escape(item.Comment.String))})
 			}
 		case "DELEGATION":
 			if execID, err := strconv.ParseUint(item.NewValue.String, 10, 64); err == nil {
 				if newExecutor, _ := l.userRepo.FindUserByID(ctx, execID); newExecutor != nil {
-					text := fmt.Sprintf("Исполнитель: <strong>%s</strong>", newExecutor.Fio)
+					text := i18n.T(lang, "notify.ws.field", i18n.Key("notify.executor"), escape(newExecutor.Fio))
 					if newExecutor.ID == recipient.ID {
-						text = "Заявка назначена на <strong>Вас</strong>"
+						text = i18n.T(lang, "notify.ws.assigned_to_you")
 					}
 					changes = append(changes, websocket.ChangeInfo{Type: "DELEGATION", Text: text})
 				}
@@ -368,13 +532,13 @@ func (l *NotificationListener) formatWebSocketPayload(ctx context.Context, event
 		case "DURATION_CHANGE":
 			parsedTime, err := time.Parse(time.RFC3339, item.NewValue.String)
 			if err == nil {
-				changes = append(changes, websocket.ChangeInfo{Type: "DURATION_CHANGE", Text: fmt.Sprintf("Срок выполнения: <strong>%s</strong>", parsedTime.Format("02.01.2006 15:04"))})
+				changes = append(changes, websocket.ChangeInfo{Type: "DURATION_CHANGE", Text: i18n.T(lang, "notify.ws.field", i18n.Key("notify.deadline"), parsedTime.In(utils.UserLocation(recipient.Timezone)).Format("02.01.2006 15:04"))})
 			}
 		case "ATTACHMENT_ADD":
 			if item.Attachment != nil {
 				link := "/uploads/" + item.Attachment.FilePath
 				attachmentLink = &link
-				changes = append(changes, websocket.ChangeInfo{Type: "ATTACHMENT_ADD", Text: fmt.Sprintf("Прикреплен файл: %s", item.Attachment.FileName)})
+				changes = append(changes, websocket.ChangeInfo{Type: "ATTACHMENT_ADD", Text: i18n.T(lang, "notify.ws.attachment", escape(item.Attachment.FileName))})
 			}
 		}
 	}
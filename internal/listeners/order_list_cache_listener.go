@@ -0,0 +1,44 @@
+package listeners
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/events"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/eventbus"
+)
+
+// OrderListCacheListener по каждому событию истории заявки, в том числе пришедшему из Telegram
+// и интеграций, пересобирает строку заявки в order_list_view и сбрасывает кэш первой страницы
+// списка. Порядок важен: иначе кэш успел бы заполниться из старой строки проекции.
+type OrderListCacheListener struct {
+	cache    repositories.CacheRepositoryInterface
+	listView repositories.OrderListViewRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewOrderListCacheListener(cache repositories.CacheRepositoryInterface, listView repositories.OrderListViewRepositoryInterface, logger *zap.Logger) *OrderListCacheListener {
+	return &OrderListCacheListener{cache: cache, listView: listView, logger: logger}
+}
+
+func (l *OrderListCacheListener) Register(bus *eventbus.Bus) {
+	bus.Subscribe("order.history.created", l.handleOrderHistoryCreated)
+	l.logger.Info("OrderListCacheListener подписан на событие 'order.history.created'")
+}
+
+func (l *OrderListCacheListener) handleOrderHistoryCreated(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(events.OrderHistoryCreatedEvent)
+	if !ok || e.Replayed {
+		return nil
+	}
+	if _, err := l.listView.Refresh(ctx, []uint64{e.HistoryItem.OrderID}); err != nil {
+		l.logger.Warn("Не удалось обновить проекцию списка заявок", zap.Uint64("orderID", e.HistoryItem.OrderID), zap.Error(err))
+	}
+	if _, err := l.cache.Incr(ctx, pkgconstants.OrderListCacheVersionKey); err != nil {
+		l.logger.Warn("Не удалось сбросить кэш списка заявок", zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package listeners
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/events"
+	"request-system/internal/services"
+	"request-system/pkg/eventbus"
+	"request-system/pkg/websocket"
+)
+
+// orderPatchFields — какое поле заявки меняет событие истории.
+var orderPatchFields = map[string]string{
+	"STATUS_CHANGE":         "status_id",
+	"PRIORITY_CHANGE":       "priority_id",
+	"DELEGATION":            "executor_id",
+	"DURATION_CHANGE":       "duration",
+	"NAME_CHANGE":           "name",
+	"ADDRESS_CHANGE":        "address",
+	"DEPARTMENT_CHANGE":     "department_id",
+	"OTDEL_CHANGE":          "otdel_id",
+	"EQUIPMENT_CHANGE":      "equipment_id",
+	"EQUIPMENT_TYPE_CHANGE": "equipment_type_id",
+	"ORDER_TYPE_CHANGE":     "order_type_id",
+	"IMPACT_CHANGE":         "impact",
+	"URGENCY_CHANGE":        "urgency",
+	"RESOLUTION_CHANGE":     "resolution_code_id",
+}
+
+// OrderRoomListener сразу, без группировки, отправляет изменения заявки тем,
+// кто держит открытой её карточку. Права проверены при подписке на комнату.
+type OrderRoomListener struct {
+	wsNotificationService services.WebSocketNotificationServiceInterface
+	serverBaseURL         string
+	logger                *zap.Logger
+}
+
+func NewOrderRoomListener(
+	wsNotificationService services.WebSocketNotificationServiceInterface,
+	serverBaseURL string,
+	logger *zap.Logger,
+) *OrderRoomListener {
+	return &OrderRoomListener{
+		wsNotificationService: wsNotificationService,
+		serverBaseURL:         serverBaseURL,
+		logger:                logger,
+	}
+}
+
+func (l *OrderRoomListener) Register(bus *eventbus.Bus) {
+	bus.Subscribe("order.history.created", l.handleOrderHistoryCreated)
+	l.logger.Info("OrderRoomListener подписан на событие 'order.history.created'")
+}
+
+func (l *OrderRoomListener) handleOrderHistoryCreated(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(events.OrderHistoryCreatedEvent)
+	if !ok || e.Replayed {
+		// Переигранные события старые — открытые карточки их уже показывают.
+		return nil
+	}
+
+	patch := l.buildPatch(e)
+	if err := l.wsNotificationService.SendToOrderRoom(patch.OrderID, patch, "order_patch"); err != nil {
+		l.logger.Error("Не удалось отправить изменение заявки в комнату", zap.Uint64("orderID", patch.OrderID), zap.Error(err))
+	}
+	return nil
+}
+
+func (l *OrderRoomListener) buildPatch(e events.OrderHistoryCreatedEvent) *websocket.OrderPatchPayload {
+	item := e.HistoryItem
+	patch := &websocket.OrderPatchPayload{
+		OrderID:   item.OrderID,
+		HistoryID: item.ID,
+		EventType: item.EventType,
+		Field:     orderPatchFields[item.EventType],
+		ActorID:   item.UserID,
+		CreatedAt: item.CreatedAt,
+	}
+
+	if item.OldValue.Valid {
+		v := item.OldValue.String
+		patch.OldValue = &v
+	}
+	if item.NewValue.Valid {
+		v := item.NewValue.String
+		patch.NewValue = &v
+	}
+	if item.Comment.Valid {
+		v := item.Comment.String
+		patch.Comment = &v
+	}
+	if item.Attachment != nil {
+		patch.Attachment = &websocket.AttachmentInfo{
+			ID:       item.Attachment.ID,
+			FileName: item.Attachment.FileName,
+			URL:      l.serverBaseURL + "/uploads/" + item.Attachment.FilePath,
+		}
+	}
+	if actor, ok := e.Actor.(*entities.User); ok && actor != nil {
+		patch.Actor = &websocket.ActorInfo{Name: actor.Fio, AvatarURL: actor.PhotoURL}
+	}
+	return patch
+}
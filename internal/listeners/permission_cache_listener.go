@@ -0,0 +1,55 @@
+package listeners
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/events"
+	"request-system/internal/services"
+	"request-system/pkg/eventbus"
+)
+
+// PermissionCacheListener сбрасывает кэш прав после изменения ролей и привилегий:
+// пользователей роли находит по user_roles, остальных берёт из события.
+type PermissionCacheListener struct {
+	authPermissionService services.AuthPermissionServiceInterface
+	logger                *zap.Logger
+}
+
+func NewPermissionCacheListener(authPermissionService services.AuthPermissionServiceInterface, logger *zap.Logger) *PermissionCacheListener {
+	return &PermissionCacheListener{authPermissionService: authPermissionService, logger: logger}
+}
+
+func (l *PermissionCacheListener) Register(bus *eventbus.Bus) {
+	bus.Subscribe(events.PermissionsChangedEvent{}.Name(), l.handlePermissionsChanged)
+	l.logger.Info("PermissionCacheListener подписан на событие 'auth.permissions.changed'")
+}
+
+func (l *PermissionCacheListener) handlePermissionsChanged(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(events.PermissionsChangedEvent)
+	if !ok {
+		return nil
+	}
+	if e.All {
+		return l.authPermissionService.FlushPermissionsCache(ctx)
+	}
+
+	affected := len(e.UserIDs)
+	for _, roleID := range e.RoleIDs {
+		count, err := l.authPermissionService.InvalidateRolePermissionsCache(ctx, roleID)
+		if err != nil {
+			// Не знаем, кого затронуло, — надёжнее сбросить кэш всем
+			l.logger.Warn("Не удалось сбросить кэш прав роли, сбрасываем всем", zap.Uint64("roleID", roleID), zap.Error(err))
+			return l.authPermissionService.FlushPermissionsCache(ctx)
+		}
+		affected += count
+	}
+	for _, userID := range e.UserIDs {
+		if err := l.authPermissionService.InvalidateUserPermissionsCache(ctx, userID); err != nil {
+			l.logger.Error("Не удалось сбросить кэш прав пользователя", zap.Uint64("userID", userID), zap.Error(err))
+		}
+	}
+	l.logger.Info("Кэш прав сброшен после изменения", zap.String("reason", e.Reason), zap.Int("users", affected))
+	return nil
+}
@@ -0,0 +1,99 @@
+package listeners
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/events"
+	"request-system/internal/services"
+	"request-system/pkg/eventbus"
+)
+
+// webhookEventNamespace — пространство имён для event_id: одна запись истории всегда даёт
+// один и тот же id, поэтому повторная публикация из event_outbox не создаёт дублей доставки.
+var webhookEventNamespace = uuid.MustParse("5b0b7c1e-3f7a-4d55-9a57-8f1f2f4c6a10")
+
+type webhookActor struct {
+	ID  uint64 `json:"id"`
+	Fio string `json:"fio,omitempty"`
+}
+
+type webhookChange struct {
+	HistoryID uint64    `json:"history_id"`
+	EventType string    `json:"event_type"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	Comment   *string   `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type webhookOrderEventData struct {
+	Order  *entities.Order `json:"order"`
+	Change webhookChange   `json:"change"`
+	Actor  webhookActor    `json:"actor"`
+}
+
+// WebhookListener ставит события заявок в очередь доставки внешним подписчикам.
+type WebhookListener struct {
+	webhookService services.WebhookServiceInterface
+	logger         *zap.Logger
+}
+
+func NewWebhookListener(webhookService services.WebhookServiceInterface, logger *zap.Logger) *WebhookListener {
+	return &WebhookListener{webhookService: webhookService, logger: logger}
+}
+
+func (l *WebhookListener) Register(bus *eventbus.Bus) {
+	bus.Subscribe("order.history.created", l.handleOrderHistoryCreated)
+	l.logger.Info("WebhookListener подписан на событие 'order.history.created'")
+}
+
+func (l *WebhookListener) handleOrderHistoryCreated(ctx context.Context, event eventbus.Event) error {
+	e, ok := event.(events.OrderHistoryCreatedEvent)
+	if !ok {
+		return nil
+	}
+	eventType, ok := services.WebhookEventForHistory(e.HistoryItem.EventType)
+	if !ok {
+		return nil
+	}
+
+	item := e.HistoryItem
+	order, _ := e.Order.(*entities.Order)
+	data := webhookOrderEventData{
+		Order: order,
+		Change: webhookChange{
+			HistoryID: item.ID,
+			EventType: item.EventType,
+			CreatedAt: item.CreatedAt,
+		},
+		Actor: webhookActor{ID: item.UserID},
+	}
+	if item.OldValue.Valid {
+		v := item.OldValue.String
+		data.Change.OldValue = &v
+	}
+	if item.NewValue.Valid {
+		v := item.NewValue.String
+		data.Change.NewValue = &v
+	}
+	if item.Comment.Valid {
+		v := item.Comment.String
+		data.Change.Comment = &v
+	}
+	if actor, ok := e.Actor.(*entities.User); ok && actor != nil {
+		data.Actor.Fio = actor.Fio
+	}
+
+	eventID := uuid.NewSHA1(webhookEventNamespace, []byte("order_history:"+strconv.FormatUint(item.ID, 10)))
+	if err := l.webhookService.EnqueueEvent(ctx, eventID, eventType, data); err != nil {
+		l.logger.Error("Не удалось поставить webhook в очередь",
+			zap.Uint64("orderID", item.OrderID), zap.String("event", eventType), zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+// Пароль пользователей, созданных из AD: вход для них идёт только через LDAP.
+const adProvisionedUserPassword = "AD_USER_NO_PASSWORD"
+
+type ADGroupMappingRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]entities.ADGroupRoleMapping, error)
+	Create(ctx context.Context, m *entities.ADGroupRoleMapping) error
+	Delete(ctx context.Context, id uint64) error
+
+	// ApplyUserRoles приводит роли пользователя, управляемые через AD (те, что есть в таблице
+	// сопоставлений), к набору granted. Роли, выданные вручную, не затрагиваются.
+	// Возвращает true, если состав ролей изменился.
+	ApplyUserRoles(ctx context.Context, userID uint64, granted []uint64) (bool, error)
+	// CreateUser создаёт локального пользователя для учётной записи AD
+	// с техническим номером телефона D_<id>.
+	CreateUser(ctx context.Context, u entities.ADProvisionedUser) (uint64, error)
+}
+
+type ADGroupMappingRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewADGroupMappingRepository(storage *pgxpool.Pool, logger *zap.Logger) ADGroupMappingRepositoryInterface {
+	return &ADGroupMappingRepository{storage: storage, logger: logger}
+}
+
+func (r *ADGroupMappingRepository) FindAll(ctx context.Context) ([]entities.ADGroupRoleMapping, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT m.id, m.ad_group, m.role_id, r.name AS role_name, m.created_at
+		FROM ad_group_role_mappings m
+		JOIN roles r ON r.id = m.role_id
+		ORDER BY LOWER(m.ad_group), r.name`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.ADGroupRoleMapping])
+}
+
+func (r *ADGroupMappingRepository) Create(ctx context.Context, m *entities.ADGroupRoleMapping) error {
+	query := `
+		WITH inserted AS (
+			INSERT INTO ad_group_role_mappings (ad_group, role_id)
+			VALUES ($1, $2)
+			RETURNING id, role_id, created_at
+		)
+		SELECT inserted.id, r.name, inserted.created_at
+		FROM inserted
+		JOIN roles r ON r.id = inserted.role_id`
+
+	if err := r.storage.QueryRow(ctx, query, m.ADGroup, m.RoleID).Scan(&m.ID, &m.RoleName, &m.CreatedAt); err != nil {
+		return apperrors.WrapDBError(err)
+	}
+	return nil
+}
+
+func (r *ADGroupMappingRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM ad_group_role_mappings WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *ADGroupMappingRepository) ApplyUserRoles(ctx context.Context, userID uint64, granted []uint64) (bool, error) {
+	if granted == nil {
+		granted = []uint64{}
+	}
+
+	var changed int64
+	err := pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		removed, err := tx.Exec(ctx, `
+			DELETE FROM user_roles
+			WHERE user_id = $1
+				AND role_id IN (SELECT role_id FROM ad_group_role_mappings)
+				AND NOT role_id = ANY($2::bigint[])`, userID, granted)
+		if err != nil {
+			return err
+		}
+		added, err := tx.Exec(ctx, `
+			INSERT INTO user_roles (user_id, role_id)
+			SELECT $1, role_id FROM UNNEST($2::bigint[]) AS role_id
+			ON CONFLICT DO NOTHING`, userID, granted)
+		if err != nil {
+			return err
+		}
+		changed = removed.RowsAffected() + added.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return false, apperrors.WrapDBError(err)
+	}
+	return changed > 0, nil
+}
+
+func (r *ADGroupMappingRepository) CreateUser(ctx context.Context, u entities.ADProvisionedUser) (uint64, error) {
+	query := `
+		WITH next AS (SELECT nextval(pg_get_serial_sequence('users', 'id')) AS id)
+		INSERT INTO users (id, fio, email, phone_number, password, status_id, username,
+			must_change_password, is_head, created_at, updated_at)
+		SELECT next.id, $1, $2, 'D_' || next.id, $3, $4, $5, false, false, NOW(), NOW()
+		FROM next
+		RETURNING id`
+
+	var id uint64
+	err := r.storage.QueryRow(ctx, query, u.Fio, u.Email, adProvisionedUserPassword, u.StatusID, u.Username).Scan(&id)
+	if err != nil {
+		return 0, apperrors.WrapDBError(err)
+	}
+	return id, nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/types"
+)
+
+// AnalyticsOrderFilter — выборка фактов для BI. Страницы идут по возрастанию id
+// (keyset-пагинация через AfterID), чтобы выгрузка не замедлялась на больших смещениях.
+type AnalyticsOrderFilter struct {
+	AfterID     uint64
+	Limit       uint64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+}
+
+type AnalyticsRepositoryInterface interface {
+	FindOrderFacts(ctx context.Context, filter AnalyticsOrderFilter) ([]types.AnalyticsOrderFact, error)
+}
+
+type AnalyticsRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewAnalyticsRepository(storage *pgxpool.Pool, logger *zap.Logger) AnalyticsRepositoryInterface {
+	return &AnalyticsRepository{storage: storage, logger: logger}
+}
+
+func (r *AnalyticsRepository) FindOrderFacts(ctx context.Context, filter AnalyticsOrderFilter) ([]types.AnalyticsOrderFact, error) {
+	query, args, err := analyticsOrderFactsQuery(ctx, filter).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowToStructByName[types.AnalyticsOrderFact])
+}
+
+// analyticsOrderFactsQuery — выборка фактов, ограниченная организацией из контекста.
+func analyticsOrderFactsQuery(ctx context.Context, filter AnalyticsOrderFilter) sq.SelectBuilder {
+	builder := sq.Select(
+		"o.id AS order_id",
+		"o.name AS order_name",
+		"COALESCE(st.code, '') AS status_code",
+		"COALESCE(st.name, '') AS status_name",
+		"COALESCE(pr.code, '') AS priority_code",
+		"COALESCE(pr.name, '') AS priority_name",
+		"COALESCE(ot.name, '') AS order_type_name",
+		"o.department_id",
+		"COALESCE(dep.name, '') AS department_name",
+		"o.otdel_id",
+		"COALESCE(otd.name, '') AS otdel_name",
+		"o.branch_id",
+		"COALESCE(brn.name, '') AS branch_name",
+		"o.office_id",
+		"COALESCE(ofc.name, '') AS office_name",
+		"COALESCE(eqt.name, '') AS equipment_type_name",
+		"COALESCE(eq.name, '') AS equipment_name",
+		"o.user_id AS creator_id",
+		"COALESCE(creator.fio, '') AS creator_name",
+		"o.executor_id",
+		"COALESCE(executor.fio, '') AS executor_name",
+		"o.created_at",
+		"COALESCE(o.updated_at, o.created_at) AS updated_at",
+		"o.completed_at",
+		"o.duration AS deadline",
+		"COALESCE(st.code = '"+pkgconstants.StatusClosed+"', false) AS is_closed",
+		"(o.duration IS NOT NULL AND COALESCE(o.completed_at, NOW()) > o.duration) AS is_overdue",
+		"CASE WHEN o.duration IS NULL OR o.completed_at IS NULL THEN NULL ELSE o.completed_at <= o.duration END AS sla_met",
+		"o.first_response_time_seconds",
+		"o.resolution_time_seconds",
+		"o.is_first_contact_resolution",
+	).
+		From("orders o").
+		LeftJoin("statuses st ON st.id = o.status_id").
+		LeftJoin("priorities pr ON pr.id = o.priority_id").
+		LeftJoin("order_types ot ON ot.id = o.order_type_id").
+		LeftJoin("departments dep ON dep.id = o.department_id").
+		LeftJoin("otdels otd ON otd.id = o.otdel_id").
+		LeftJoin("branches brn ON brn.id = o.branch_id").
+		LeftJoin("offices ofc ON ofc.id = o.office_id").
+		LeftJoin("equipment_types eqt ON eqt.id = o.equipment_type_id").
+		LeftJoin("equipments eq ON eq.id = o.equipment_id").
+		LeftJoin("users creator ON creator.id = o.user_id").
+		LeftJoin("users executor ON executor.id = o.executor_id").
+		Where(sq.Eq{"o.deleted_at": nil}).
+		Where(tenantCondition(ctx, "o.tenant_id")).
+		Where(sq.Gt{"o.id": filter.AfterID}).
+		OrderBy("o.id ASC").
+		Limit(filter.Limit)
+
+	if filter.CreatedFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"o.created_at": *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		builder = builder.Where(sq.Lt{"o.created_at": *filter.CreatedTo})
+	}
+	if filter.UpdatedFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"o.updated_at": *filter.UpdatedFrom})
+	}
+	if filter.UpdatedTo != nil {
+		builder = builder.Where(sq.Lt{"o.updated_at": *filter.UpdatedTo})
+	}
+	return builder
+}
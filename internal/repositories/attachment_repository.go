@@ -18,6 +18,7 @@ type AttachmentRepositoryInterface interface {
 	FindByID(ctx context.Context, id uint64) (*entities.Attachment, error)
 	DeleteAttachment(ctx context.Context, id uint64) error
 	FindAttachmentsByOrderIDs(ctx context.Context, orderIDs []uint64) (map[uint64][]entities.Attachment, error)
+	MoveToOrderInTx(ctx context.Context, tx pgx.Tx, fromOrderID, toOrderID uint64) ([]entities.Attachment, error)
 }
 
 type attachmentRepository struct {
@@ -115,3 +116,26 @@ func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id uint64)
 	}
 	return nil
 }
+
+// MoveToOrderInTx переносит все вложения заявки fromOrderID в заявку toOrderID и возвращает перенесённые.
+func (r *attachmentRepository) MoveToOrderInTx(ctx context.Context, tx pgx.Tx, fromOrderID, toOrderID uint64) ([]entities.Attachment, error) {
+	query := `
+		UPDATE attachments SET order_id = $2
+		WHERE order_id = $1
+		RETURNING id, order_id, user_id, file_name, file_path, file_type, file_size, created_at`
+	rows, err := tx.Query(ctx, query, fromOrderID, toOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var moved []entities.Attachment
+	for rows.Next() {
+		var a entities.Attachment
+		if err := rows.Scan(&a.ID, &a.OrderID, &a.UserID, &a.FileName, &a.FilePath, &a.FileType, &a.FileSize, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		moved = append(moved, a)
+	}
+	return moved, rows.Err()
+}
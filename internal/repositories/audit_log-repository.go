@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+)
+
+const auditLogFields = `
+	id, actor_id, action, entity, entity_id, method, path, status_code, before, after,
+	ip, user_agent, request_id, impersonator_id, created_at`
+
+// auditRedactedColumns вырезаются из снимков, чтобы в журнал не попадали хэши паролей и секреты.
+var auditRedactedColumns = []string{"password", "secret"}
+
+// AuditLogFilter — фильтры журнала; пустые поля не ограничивают выборку.
+type AuditLogFilter struct {
+	ActorID  *uint64
+	Entity   string
+	EntityID string
+	Action   string
+	// Impersonated оставляет только запросы, сделанные в режиме имперсонации.
+	Impersonated   bool
+	ImpersonatorID *uint64
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	Offset         int
+}
+
+type AuditLogRepositoryInterface interface {
+	Create(ctx context.Context, entry *entities.AuditLogEntry) error
+	Find(ctx context.Context, filter AuditLogFilter) ([]entities.AuditLogEntry, uint64, error)
+	// Snapshot возвращает строку таблицы как JSON (nil, если строки нет).
+	// table должна приходить из фиксированного списка, а не от клиента.
+	Snapshot(ctx context.Context, table string, id uint64) (json.RawMessage, error)
+}
+
+type AuditLogRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewAuditLogRepository(storage *pgxpool.Pool, logger *zap.Logger) AuditLogRepositoryInterface {
+	return &AuditLogRepository{storage: storage, logger: logger}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, entry *entities.AuditLogEntry) error {
+	query := `
+		INSERT INTO audit_log (actor_id, action, entity, entity_id, method, path, status_code, before, after, ip, user_agent, request_id, impersonator_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, created_at`
+
+	return r.storage.QueryRow(ctx, query,
+		entry.ActorID, entry.Action, entry.Entity, entry.EntityID, entry.Method, entry.Path, entry.StatusCode,
+		entry.Before, entry.After, entry.IP, entry.UserAgent, entry.RequestID, entry.ImpersonatorID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *AuditLogRepository) Find(ctx context.Context, filter AuditLogFilter) ([]entities.AuditLogEntry, uint64, error) {
+	where := sq.And{}
+	if filter.ActorID != nil {
+		where = append(where, sq.Eq{"actor_id": *filter.ActorID})
+	}
+	if filter.Entity != "" {
+		where = append(where, sq.Eq{"entity": filter.Entity})
+	}
+	if filter.EntityID != "" {
+		where = append(where, sq.Eq{"entity_id": filter.EntityID})
+	}
+	if filter.Action != "" {
+		where = append(where, sq.Eq{"action": filter.Action})
+	}
+	if filter.Impersonated {
+		where = append(where, sq.NotEq{"impersonator_id": nil})
+	}
+	if filter.ImpersonatorID != nil {
+		where = append(where, sq.Eq{"impersonator_id": *filter.ImpersonatorID})
+	}
+	if filter.From != nil {
+		where = append(where, sq.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		where = append(where, sq.Lt{"created_at": *filter.To})
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	countSQL, countArgs, err := psql.Select("COUNT(*)").From("audit_log").Where(where).ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	var total uint64
+	if err := r.storage.QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.AuditLogEntry{}, 0, nil
+	}
+
+	selectQuery := psql.Select(auditLogFields).From("audit_log").Where(where).OrderBy("created_at DESC", "id DESC")
+	if filter.Limit > 0 {
+		selectQuery = selectQuery.Limit(uint64(filter.Limit)).Offset(uint64(filter.Offset))
+	}
+	sqlStr, args, err := selectQuery.ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.storage.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.AuditLogEntry])
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *AuditLogRepository) Snapshot(ctx context.Context, table string, id uint64) (json.RawMessage, error) {
+	query := fmt.Sprintf("SELECT to_jsonb(t) - $2::text[] FROM %s t WHERE t.id = $1", pgx.Identifier{table}.Sanitize())
+
+	var snapshot json.RawMessage
+	err := r.storage.QueryRow(ctx, query, id, auditRedactedColumns).Scan(&snapshot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return snapshot, err
+}
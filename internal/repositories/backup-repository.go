@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const backupFields = `
+	id, status, tables, file_path, size_bytes, sha256, triggered_by, error, started_at, finished_at,
+	verify_status, verify_report, verify_error, verified_at`
+
+// BackupScratch — временная база, в которую pg_restore восстанавливает копию при проверке.
+type BackupScratch interface {
+	// Database — имя временной базы для pg_restore --dbname.
+	Database() string
+	// CountRows считает строки таблицы схемы public во временной базе.
+	CountRows(ctx context.Context, table string) (int64, error)
+}
+
+type BackupRepositoryInterface interface {
+	// CreateBackup начинает копию. Возвращает nil, пока идёт другая копия, начатая не раньше
+	// staleAfter назад: pg_dump не должен идти в две реплики.
+	CreateBackup(ctx context.Context, tables []string, triggeredBy *uint64, staleAfter time.Duration) (*entities.Backup, error)
+	// FinishBackup сохраняет статус, путь, размер, контрольную сумму и ошибку копии.
+	FinishBackup(ctx context.Context, backup *entities.Backup) error
+	SaveVerification(ctx context.Context, id uint64, status string, report json.RawMessage, errMsg *string) error
+	FindByID(ctx context.Context, id uint64) (*entities.Backup, error)
+	FindBackups(ctx context.Context, limit, offset int) ([]entities.Backup, uint64, error)
+	// FindLatestCompleted — последняя успешная копия, файл которой ещё хранится.
+	FindLatestCompleted(ctx context.Context) (*entities.Backup, error)
+	// FindPrunable — успешные копии старше keep последних.
+	FindPrunable(ctx context.Context, keep int) ([]entities.Backup, error)
+	MarkPruned(ctx context.Context, id uint64) error
+	// FindAlertRecipients — активные пользователи, у которых есть право permission
+	// через роль или напрямую и оно не запрещено.
+	FindAlertRecipients(ctx context.Context, permission string) ([]uint64, error)
+	// RestoreInScratch создаёт пустую базу database, передаёт её fn и затем удаляет: после
+	// проверки ничего не остаётся. Пользователю базы нужно право CREATEDB.
+	RestoreInScratch(ctx context.Context, database string, fn func(scratch BackupScratch) error) error
+}
+
+type BackupRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewBackupRepository(storage *pgxpool.Pool, logger *zap.Logger) BackupRepositoryInterface {
+	return &BackupRepository{storage: storage, logger: logger}
+}
+
+func (r *BackupRepository) CreateBackup(ctx context.Context, tables []string, triggeredBy *uint64, staleAfter time.Duration) (*entities.Backup, error) {
+	rows, err := r.storage.Query(ctx, `
+		INSERT INTO backups (tables, triggered_by)
+		SELECT $1, $2
+		WHERE NOT EXISTS (
+			SELECT 1 FROM backups
+			WHERE status = 'running' AND started_at > NOW() - ($3::int * INTERVAL '1 second')
+		)
+		RETURNING `+backupFields, tables, triggeredBy, int(staleAfter.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	backup, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.Backup])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return backup, err
+}
+
+func (r *BackupRepository) FinishBackup(ctx context.Context, backup *entities.Backup) error {
+	return r.storage.QueryRow(ctx, `
+		UPDATE backups
+		SET status = $2, file_path = $3, size_bytes = $4, sha256 = $5, error = $6, finished_at = NOW()
+		WHERE id = $1
+		RETURNING finished_at`,
+		backup.ID, backup.Status, backup.FilePath, backup.SizeBytes, backup.SHA256, backup.Error,
+	).Scan(&backup.FinishedAt)
+}
+
+func (r *BackupRepository) SaveVerification(ctx context.Context, id uint64, status string, report json.RawMessage, errMsg *string) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE backups
+		SET verify_status = $2, verify_report = $3, verify_error = $4, verified_at = NOW()
+		WHERE id = $1`, id, status, report, errMsg)
+	return err
+}
+
+func (r *BackupRepository) FindByID(ctx context.Context, id uint64) (*entities.Backup, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+backupFields+" FROM backups WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.Backup])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return backup, err
+}
+
+func (r *BackupRepository) FindBackups(ctx context.Context, limit, offset int) ([]entities.Backup, uint64, error) {
+	var total uint64
+	if err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM backups`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.Backup{}, 0, nil
+	}
+	rows, err := r.storage.Query(ctx, "SELECT "+backupFields+`
+		FROM backups
+		ORDER BY id DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	backups, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.Backup])
+	return backups, total, err
+}
+
+func (r *BackupRepository) FindLatestCompleted(ctx context.Context) (*entities.Backup, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+backupFields+`
+		FROM backups
+		WHERE status = 'completed' AND file_path IS NOT NULL
+		ORDER BY id DESC
+		LIMIT 1`)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.Backup])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return backup, err
+}
+
+func (r *BackupRepository) FindPrunable(ctx context.Context, keep int) ([]entities.Backup, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+backupFields+`
+		FROM backups
+		WHERE status = 'completed'
+		ORDER BY id DESC
+		OFFSET $1`, keep)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Backup])
+}
+
+func (r *BackupRepository) MarkPruned(ctx context.Context, id uint64) error {
+	_, err := r.storage.Exec(ctx, `UPDATE backups SET status = 'pruned', file_path = NULL WHERE id = $1`, id)
+	return err
+}
+
+func (r *BackupRepository) FindAlertRecipients(ctx context.Context, permission string) ([]uint64, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT u.id
+		FROM users u
+		JOIN statuses s ON s.id = u.status_id
+		JOIN permissions p ON p.name = $1
+		WHERE s.code = 'ACTIVE' AND u.deleted_at IS NULL
+			AND (
+				EXISTS (SELECT 1 FROM user_permissions up WHERE up.user_id = u.id AND up.permission_id = p.id)
+				OR EXISTS (
+					SELECT 1 FROM user_roles ur
+					JOIN role_permissions rp ON rp.role_id = ur.role_id
+					WHERE ur.user_id = u.id AND rp.permission_id = p.id
+				)
+			)
+			AND NOT EXISTS (SELECT 1 FROM user_permission_denials d WHERE d.user_id = u.id AND d.permission_id = p.id)
+		ORDER BY u.id`, permission)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uint64])
+}
+
+func (r *BackupRepository) RestoreInScratch(ctx context.Context, database string, fn func(scratch BackupScratch) error) error {
+	name := pgx.Identifier{database}.Sanitize()
+	// База могла остаться от проверки, прерванной вместе с процессом
+	if _, err := r.storage.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+		return fmt.Errorf("не удалось удалить старую временную базу: %w", err)
+	}
+	if _, err := r.storage.Exec(ctx, "CREATE DATABASE "+name+" TEMPLATE template0"); err != nil {
+		return fmt.Errorf("не удалось создать временную базу: %w", err)
+	}
+	defer func() {
+		if _, err := r.storage.Exec(context.WithoutCancel(ctx), "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			r.logger.Warn("Не удалось удалить временную базу проверки резервной копии", zap.String("database", database), zap.Error(err))
+		}
+	}()
+
+	connConfig := r.storage.Config().ConnConfig.Copy()
+	connConfig.Database = database
+	scratch := &backupScratchDB{database: database, connConfig: connConfig}
+	defer scratch.close(context.WithoutCancel(ctx))
+	return fn(scratch)
+}
+
+// backupScratchDB подключается к временной базе при первом подсчёте строк.
+type backupScratchDB struct {
+	database   string
+	connConfig *pgx.ConnConfig
+	conn       *pgx.Conn
+}
+
+func (s *backupScratchDB) close(ctx context.Context) {
+	if s.conn != nil {
+		s.conn.Close(ctx)
+	}
+}
+
+func (s *backupScratchDB) Database() string {
+	return s.database
+}
+
+func (s *backupScratchDB) CountRows(ctx context.Context, table string) (int64, error) {
+	if s.conn == nil {
+		conn, err := pgx.ConnectConfig(ctx, s.connConfig)
+		if err != nil {
+			return 0, fmt.Errorf("не удалось подключиться к временной базе: %w", err)
+		}
+		s.conn = conn
+	}
+
+	var count int64
+	err := s.conn.QueryRow(ctx, "SELECT COUNT(*) FROM "+pgx.Identifier{"public", table}.Sanitize()).Scan(&count)
+	return count, err
+}
@@ -16,6 +16,7 @@ import (
 
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
+	"request-system/pkg/utils"
 )
 
 const branchTable = "branches"
@@ -54,12 +55,12 @@ func scanBranch(row pgx.Row) (*entities.Branch, error) {
 	var b entities.Branch
 	var s entities.Status
 	// Для nullable полей
-	var externalId, sourceSystem, address, phone, email, emailIndex sql.NullString
+	var externalId, sourceSystem, address, phone, email, emailIndex, orderPrefix sql.NullString
 	var openDate sql.NullTime
 
 	err := row.Scan(
 		&b.ID, &b.Name, &b.ShortName, &address, &phone, &email, &emailIndex,
-		&openDate, &b.StatusID, &externalId, &sourceSystem,
+		&openDate, &orderPrefix, &b.StatusID, &externalId, &sourceSystem,
 		&b.CreatedAt, &b.UpdatedAt,
 		&s.ID, &s.Name,
 	)
@@ -86,6 +87,9 @@ func scanBranch(row pgx.Row) (*entities.Branch, error) {
 	if openDate.Valid {
 		b.OpenDate = &openDate.Time
 	}
+	if orderPrefix.Valid {
+		b.OrderPrefix = &orderPrefix.String
+	}
 	if externalId.Valid {
 		b.ExternalID = &externalId.String
 	}
@@ -116,7 +120,7 @@ func (r *BranchRepository) GetBranches(ctx context.Context, filter types.Filter)
 	}
 
 	// 1. COUNT
-	countBuilder := psql.Select("COUNT(b.id)").From("branches AS b")
+	countBuilder := psql.Select("COUNT(b.id)").From("branches AS b").Where(sharedTenantCondition(ctx, "b.tenant_id"))
 
 	countBuilder = applySearch(countBuilder)
 
@@ -140,10 +144,11 @@ func (r *BranchRepository) GetBranches(ctx context.Context, filter types.Filter)
 	}
 	baseBuilder := psql.Select(
 		"b.id", "b.name", "b.short_name", "b.address", "b.phone_number", "b.email", "b.email_index",
-		"b.open_date", "b.status_id", "b.external_id", "b.source_system",
+		"b.open_date", "b.order_prefix", "b.status_id", "b.external_id", "b.source_system",
 		"b.created_at", "b.updated_at",
 		"COALESCE(s.id, 0)", "COALESCE(s.name, '')",
-	).From("branches AS b").LeftJoin("statuses s ON b.status_id = s.id")
+	).From("branches AS b").LeftJoin("statuses s ON b.status_id = s.id").
+		Where(sharedTenantCondition(ctx, "b.tenant_id"))
 
 	baseBuilder = applySearch(baseBuilder)
 
@@ -181,10 +186,12 @@ func (r *BranchRepository) findOne(ctx context.Context, querier Querier, where s
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	queryBuilder := psql.Select(
 		"b.id", "b.name", "b.short_name", "b.address", "b.phone_number", "b.email", "b.email_index",
-		"b.open_date", "b.status_id", "b.external_id", "b.source_system",
+		"b.open_date", "b.order_prefix", "b.status_id", "b.external_id", "b.source_system",
 		"b.created_at", "b.updated_at",
 		"COALESCE(s.id, 0)", "COALESCE(s.name, '')",
-	).From("branches b").LeftJoin("statuses s ON b.status_id = s.id").Where(where)
+	).From("branches b").LeftJoin("statuses s ON b.status_id = s.id").
+		Where(where).
+		Where(sharedTenantCondition(ctx, "b.tenant_id"))
 
 	sql, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -208,15 +215,15 @@ func (r *BranchRepository) FindByExternalID(ctx context.Context, tx pgx.Tx, exte
 
 func (r *BranchRepository) CreateBranch(ctx context.Context, tx pgx.Tx, branch entities.Branch) (uint64, error) {
 	query := `
-		INSERT INTO branches (name, short_name, address, phone_number, email, email_index, open_date, status_id, external_id, source_system, created_at, updated_at)
-		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		INSERT INTO branches (name, short_name, address, phone_number, email, email_index, open_date, status_id, external_id, source_system, tenant_id, order_prefix, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
 		RETURNING id
 	`
 	var newID uint64
 	err := tx.QueryRow(ctx, query,
 		branch.Name, branch.ShortName, branch.Address, branch.PhoneNumber,
 		branch.Email, branch.EmailIndex, branch.OpenDate, branch.StatusID,
-		branch.ExternalID, branch.SourceSystem,
+		branch.ExternalID, branch.SourceSystem, utils.TenantIDOrDefault(ctx), branch.OrderPrefix,
 	).Scan(&newID)
 
 	return newID, err
@@ -226,12 +233,12 @@ func (r *BranchRepository) UpdateBranch(ctx context.Context, tx pgx.Tx, id uint6
 	query := `
 		UPDATE branches
 		SET name = $1, short_name = $2, address = $3, phone_number = $4, email = $5,
-		    email_index = $6, open_date = $7, status_id = $8, updated_at = NOW()
-		WHERE id = $9
+		    email_index = $6, open_date = $7, status_id = $8, order_prefix = $9, updated_at = NOW()
+		WHERE id = $10
 	`
 	result, err := tx.Exec(ctx, query,
 		branch.Name, branch.ShortName, branch.Address, branch.PhoneNumber,
-		branch.Email, branch.EmailIndex, branch.OpenDate, branch.StatusID, id,
+		branch.Email, branch.EmailIndex, branch.OpenDate, branch.StatusID, branch.OrderPrefix, id,
 	)
 	if err != nil {
 		return err
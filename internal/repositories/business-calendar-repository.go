@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const businessCalendarFields = `
+	work_days, to_char(day_start, 'HH24:MI') AS day_start, to_char(day_end, 'HH24:MI') AS day_end,
+	is_enabled, updated_by, updated_at`
+
+const businessHolidayFields = `
+	id, holiday_date, name, is_recurring, is_working_day, created_by, created_at, updated_at`
+
+type BusinessCalendarRepositoryInterface interface {
+	GetCalendar(ctx context.Context) (*entities.BusinessCalendar, error)
+	UpdateCalendar(ctx context.Context, calendar *entities.BusinessCalendar) error
+
+	CreateHoliday(ctx context.Context, holiday *entities.BusinessHoliday) error
+	UpdateHoliday(ctx context.Context, holiday *entities.BusinessHoliday) error
+	DeleteHoliday(ctx context.Context, id uint64) error
+	FindHolidayByID(ctx context.Context, id uint64) (*entities.BusinessHoliday, error)
+	// FindHolidays возвращает ежегодные праздники и даты из периода [from, to).
+	FindHolidays(ctx context.Context, from, to time.Time) ([]entities.BusinessHoliday, error)
+}
+
+type BusinessCalendarRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewBusinessCalendarRepository(storage *pgxpool.Pool, logger *zap.Logger) BusinessCalendarRepositoryInterface {
+	return &BusinessCalendarRepository{storage: storage, logger: logger}
+}
+
+func (r *BusinessCalendarRepository) GetCalendar(ctx context.Context) (*entities.BusinessCalendar, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+businessCalendarFields+" FROM business_calendar WHERE id = 1")
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.BusinessCalendar])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return calendar, err
+}
+
+func (r *BusinessCalendarRepository) UpdateCalendar(ctx context.Context, calendar *entities.BusinessCalendar) error {
+	return r.storage.QueryRow(ctx, `
+		UPDATE business_calendar
+		SET work_days = $1, day_start = $2::time, day_end = $3::time, is_enabled = $4, updated_by = $5, updated_at = NOW()
+		WHERE id = 1
+		RETURNING updated_at`,
+		calendar.WorkDays, calendar.DayStart, calendar.DayEnd, calendar.IsEnabled, calendar.UpdatedBy,
+	).Scan(&calendar.UpdatedAt)
+}
+
+func (r *BusinessCalendarRepository) CreateHoliday(ctx context.Context, holiday *entities.BusinessHoliday) error {
+	err := r.storage.QueryRow(ctx, `
+		INSERT INTO business_holidays (holiday_date, name, is_recurring, is_working_day, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		holiday.HolidayDate, holiday.Name, holiday.IsRecurring, holiday.IsWorkingDay, holiday.CreatedBy,
+	).Scan(&holiday.ID, &holiday.CreatedAt, &holiday.UpdatedAt)
+	return businessHolidayError(err)
+}
+
+func (r *BusinessCalendarRepository) UpdateHoliday(ctx context.Context, holiday *entities.BusinessHoliday) error {
+	err := r.storage.QueryRow(ctx, `
+		UPDATE business_holidays
+		SET holiday_date = $2, name = $3, is_recurring = $4, is_working_day = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		holiday.ID, holiday.HolidayDate, holiday.Name, holiday.IsRecurring, holiday.IsWorkingDay,
+	).Scan(&holiday.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	return businessHolidayError(err)
+}
+
+func (r *BusinessCalendarRepository) DeleteHoliday(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM business_holidays WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *BusinessCalendarRepository) FindHolidayByID(ctx context.Context, id uint64) (*entities.BusinessHoliday, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+businessHolidayFields+" FROM business_holidays WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	holiday, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.BusinessHoliday])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return holiday, err
+}
+
+func (r *BusinessCalendarRepository) FindHolidays(ctx context.Context, from, to time.Time) ([]entities.BusinessHoliday, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+businessHolidayFields+`
+		FROM business_holidays
+		WHERE is_recurring OR (holiday_date >= $1::date AND holiday_date < $2::date)
+		ORDER BY is_recurring DESC, to_char(holiday_date, 'MM-DD'), holiday_date`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.BusinessHoliday])
+}
+
+// businessHolidayError — на одну дату бывает только одна запись календаря.
+func businessHolidayError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return apperrors.NewHttpError(http.StatusConflict, "На эту дату уже есть запись в календаре", err, nil)
+	}
+	return err
+}
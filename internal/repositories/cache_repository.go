@@ -9,6 +9,8 @@ type CacheRepositoryInterface interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Del(ctx context.Context, keys ...string) error
+	// GetDel атомарно читает и удаляет ключ: значение достаётся только одному из конкурирующих вызовов.
+	GetDel(ctx context.Context, key string) (string, error)
 	Incr(ctx context.Context, key string) (int64, error)
 	Expire(ctx context.Context, key string, expiration time.Duration) (bool, error)
 }
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+type CalendarFeedRepositoryInterface interface {
+	FindToken(ctx context.Context, userID uint64) (*entities.UserCalendarToken, error)
+	// UpsertToken записывает новый nonce, отзывая ссылку со старым.
+	UpsertToken(ctx context.Context, userID uint64, nonce string) (*entities.UserCalendarToken, error)
+	DeleteToken(ctx context.Context, userID uint64) error
+	TouchToken(ctx context.Context, userID uint64) error
+	FindExecutorDeadlines(ctx context.Context, userID uint64, limit int) ([]entities.CalendarDeadline, error)
+}
+
+type CalendarFeedRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewCalendarFeedRepository(storage *pgxpool.Pool, logger *zap.Logger) CalendarFeedRepositoryInterface {
+	return &CalendarFeedRepository{storage: storage, logger: logger}
+}
+
+func (r *CalendarFeedRepository) FindToken(ctx context.Context, userID uint64) (*entities.UserCalendarToken, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT user_id, nonce, created_at, last_used_at
+		FROM user_calendar_tokens
+		WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.UserCalendarToken])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return token, err
+}
+
+func (r *CalendarFeedRepository) UpsertToken(ctx context.Context, userID uint64, nonce string) (*entities.UserCalendarToken, error) {
+	rows, err := r.storage.Query(ctx, `
+		INSERT INTO user_calendar_tokens (user_id, nonce)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET nonce = EXCLUDED.nonce, created_at = NOW(), last_used_at = NULL
+		RETURNING user_id, nonce, created_at, last_used_at`, userID, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.UserCalendarToken])
+}
+
+func (r *CalendarFeedRepository) DeleteToken(ctx context.Context, userID uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM user_calendar_tokens WHERE user_id = $1`, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *CalendarFeedRepository) TouchToken(ctx context.Context, userID uint64) error {
+	_, err := r.storage.Exec(ctx, `UPDATE user_calendar_tokens SET last_used_at = NOW() WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *CalendarFeedRepository) FindExecutorDeadlines(ctx context.Context, userID uint64, limit int) ([]entities.CalendarDeadline, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT o.id, o.number, o.name, o.address, o.duration, o.updated_at,
+			COALESCE(st.name, '') AS status_name,
+			COALESCE(pr.name, '') AS priority_name
+		FROM orders o
+		JOIN statuses st ON st.id = o.status_id
+		LEFT JOIN priorities pr ON pr.id = o.priority_id
+		WHERE o.executor_id = $1
+			AND o.deleted_at IS NULL
+			AND o.duration IS NOT NULL
+			AND st.code NOT IN ('CLOSED', 'COMPLETED', 'REJECTED', 'DUPLICATE')
+		ORDER BY o.duration
+		LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.CalendarDeadline])
+}
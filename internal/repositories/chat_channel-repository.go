@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const chatChannelFields = `
+	id, name, kind, webhook_url, department_id, event_types, only_critical, is_active, created_by, created_at, updated_at`
+
+const chatOrderCardQuery = `
+	SELECT o.id, o.number, o.name, o.department_id, o.duration,
+		COALESCE(dep.name, '') AS department_name,
+		COALESCE(st.name, '') AS status_name,
+		COALESCE(pr.name, '') AS priority_name,
+		COALESCE(pr.code, '') AS priority_code,
+		COALESCE(ex.fio, '') AS executor_name
+	FROM orders o
+	LEFT JOIN statuses st ON st.id = o.status_id
+	LEFT JOIN priorities pr ON pr.id = o.priority_id
+	LEFT JOIN departments dep ON dep.id = o.department_id
+	LEFT JOIN users ex ON ex.id = o.executor_id`
+
+type ChatChannelRepositoryInterface interface {
+	Create(ctx context.Context, ch *entities.ChatChannel) error
+	Update(ctx context.Context, ch *entities.ChatChannel) error
+	Delete(ctx context.Context, id uint64) error
+	FindByID(ctx context.Context, id uint64) (*entities.ChatChannel, error)
+	FindAll(ctx context.Context, limit, offset int) ([]entities.ChatChannel, uint64, error)
+
+	// FindActiveForEvent — активные каналы, подписанные на eventType, для департамента заявки
+	// (включая каналы без департамента).
+	FindActiveForEvent(ctx context.Context, eventType string, departmentID *uint64) ([]entities.ChatChannel, error)
+	FindOrderCard(ctx context.Context, orderID uint64) (*entities.ChatOrderCard, error)
+	// ClaimSLABreaches отмечает и возвращает заявки, срок которых истёк не раньше since
+	// и о которых каналы ещё не оповещались.
+	ClaimSLABreaches(ctx context.Context, since time.Time, limit int) ([]entities.ChatOrderCard, error)
+}
+
+type ChatChannelRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewChatChannelRepository(storage *pgxpool.Pool, logger *zap.Logger) ChatChannelRepositoryInterface {
+	return &ChatChannelRepository{storage: storage, logger: logger}
+}
+
+func (r *ChatChannelRepository) Create(ctx context.Context, ch *entities.ChatChannel) error {
+	query := `
+		INSERT INTO chat_channels (name, kind, webhook_url, department_id, event_types, only_critical, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	err := r.storage.QueryRow(ctx, query,
+		ch.Name, ch.Kind, ch.WebhookURL, ch.DepartmentID, ch.EventTypes, ch.OnlyCritical, ch.IsActive, ch.CreatedBy,
+	).Scan(&ch.ID, &ch.CreatedAt, &ch.UpdatedAt)
+	if err != nil {
+		return apperrors.WrapDBError(err)
+	}
+	return nil
+}
+
+func (r *ChatChannelRepository) Update(ctx context.Context, ch *entities.ChatChannel) error {
+	query := `
+		UPDATE chat_channels
+		SET name = $2, kind = $3, webhook_url = $4, department_id = $5, event_types = $6,
+			only_critical = $7, is_active = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.storage.QueryRow(ctx, query,
+		ch.ID, ch.Name, ch.Kind, ch.WebhookURL, ch.DepartmentID, ch.EventTypes, ch.OnlyCritical, ch.IsActive,
+	).Scan(&ch.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	if err != nil {
+		return apperrors.WrapDBError(err)
+	}
+	return nil
+}
+
+func (r *ChatChannelRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM chat_channels WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *ChatChannelRepository) FindByID(ctx context.Context, id uint64) (*entities.ChatChannel, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+chatChannelFields+" FROM chat_channels WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.ChatChannel])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return ch, err
+}
+
+func (r *ChatChannelRepository) FindAll(ctx context.Context, limit, offset int) ([]entities.ChatChannel, uint64, error) {
+	var total uint64
+	if err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM chat_channels`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.ChatChannel{}, 0, nil
+	}
+
+	query := "SELECT " + chatChannelFields + `
+		FROM chat_channels
+		ORDER BY id
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.storage.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	channels, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.ChatChannel])
+	if err != nil {
+		return nil, 0, err
+	}
+	return channels, total, nil
+}
+
+func (r *ChatChannelRepository) FindActiveForEvent(ctx context.Context, eventType string, departmentID *uint64) ([]entities.ChatChannel, error) {
+	query := "SELECT " + chatChannelFields + `
+		FROM chat_channels
+		WHERE is_active AND $1::text = ANY(event_types)
+			AND (department_id IS NULL OR department_id = $2)
+		ORDER BY id`
+
+	rows, err := r.storage.Query(ctx, query, eventType, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.ChatChannel])
+}
+
+func (r *ChatChannelRepository) FindOrderCard(ctx context.Context, orderID uint64) (*entities.ChatOrderCard, error) {
+	rows, err := r.storage.Query(ctx, chatOrderCardQuery+" WHERE o.id = $1", orderID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.ChatOrderCard])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return card, err
+}
+
+func (r *ChatChannelRepository) ClaimSLABreaches(ctx context.Context, since time.Time, limit int) ([]entities.ChatOrderCard, error) {
+	query := `
+		WITH claimed AS (
+			INSERT INTO chat_sla_breach_notices (order_id, duration)
+			SELECT o.id, o.duration
+			FROM orders o
+			JOIN statuses s ON s.id = o.status_id
+			WHERE o.deleted_at IS NULL
+				AND o.duration < NOW() AND o.duration >= $1
+				AND s.code NOT IN ('CLOSED', 'COMPLETED', 'REJECTED', 'DUPLICATE')
+				AND NOT EXISTS (
+					SELECT 1 FROM chat_sla_breach_notices n
+					WHERE n.order_id = o.id AND n.duration = o.duration
+				)
+			ORDER BY o.duration
+			LIMIT $2
+			ON CONFLICT DO NOTHING
+			RETURNING order_id
+		)` + chatOrderCardQuery + `
+		JOIN claimed c ON c.order_id = o.id
+		ORDER BY o.duration`
+
+	rows, err := r.storage.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.ChatOrderCard])
+}
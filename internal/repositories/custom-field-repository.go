@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const customFieldSelectQuery = `
+	SELECT id, order_type_id, code, name, field_type, is_required, options, sort_order, created_at, updated_at
+	FROM custom_field_definitions`
+
+type CustomFieldRepositoryInterface interface {
+	FindByOrderType(ctx context.Context, orderTypeID uint64) ([]entities.CustomFieldDefinition, error)
+	// FindByOrderTypes — определения для нескольких типов сразу (для выгрузки).
+	FindByOrderTypes(ctx context.Context, orderTypeIDs []uint64) ([]entities.CustomFieldDefinition, error)
+	FindByID(ctx context.Context, id uint64) (*entities.CustomFieldDefinition, error)
+	Create(ctx context.Context, field *entities.CustomFieldDefinition) error
+	Update(ctx context.Context, field *entities.CustomFieldDefinition) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+type CustomFieldRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewCustomFieldRepository(storage *pgxpool.Pool, logger *zap.Logger) CustomFieldRepositoryInterface {
+	return &CustomFieldRepository{storage: storage, logger: logger}
+}
+
+func (r *CustomFieldRepository) FindByOrderType(ctx context.Context, orderTypeID uint64) ([]entities.CustomFieldDefinition, error) {
+	return r.FindByOrderTypes(ctx, []uint64{orderTypeID})
+}
+
+func (r *CustomFieldRepository) FindByOrderTypes(ctx context.Context, orderTypeIDs []uint64) ([]entities.CustomFieldDefinition, error) {
+	if len(orderTypeIDs) == 0 {
+		return []entities.CustomFieldDefinition{}, nil
+	}
+	rows, err := r.storage.Query(ctx, customFieldSelectQuery+`
+		WHERE order_type_id = ANY($1)
+		ORDER BY order_type_id, sort_order, id`, orderTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.CustomFieldDefinition])
+}
+
+func (r *CustomFieldRepository) FindByID(ctx context.Context, id uint64) (*entities.CustomFieldDefinition, error) {
+	rows, err := r.storage.Query(ctx, customFieldSelectQuery+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	field, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.CustomFieldDefinition])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *CustomFieldRepository) Create(ctx context.Context, field *entities.CustomFieldDefinition) error {
+	err := r.storage.QueryRow(ctx, `
+		INSERT INTO custom_field_definitions (order_type_id, code, name, field_type, is_required, options, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`,
+		field.OrderTypeID, field.Code, field.Name, field.FieldType, field.IsRequired, customFieldOptions(field.Options), field.SortOrder,
+	).Scan(&field.ID, &field.CreatedAt, &field.UpdatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *CustomFieldRepository) Update(ctx context.Context, field *entities.CustomFieldDefinition) error {
+	err := r.storage.QueryRow(ctx, `
+		UPDATE custom_field_definitions
+		SET name = $2, is_required = $3, options = $4, sort_order = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		field.ID, field.Name, field.IsRequired, customFieldOptions(field.Options), field.SortOrder,
+	).Scan(&field.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	return apperrors.WrapDBError(err)
+}
+
+func (r *CustomFieldRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM custom_field_definitions WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+// customFieldOptions — пустой массив вместо NULL: колонка options NOT NULL.
+func customFieldOptions(options []string) []string {
+	if options == nil {
+		return []string{}
+	}
+	return options
+}
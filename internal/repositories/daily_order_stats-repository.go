@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	pkgconstants "request-system/pkg/constants"
+)
+
+// dailyOrderStatsLockKey — ключ advisory-блокировки, чтобы несколько реплик не пересчитывали
+// один и тот же день одновременно.
+const dailyOrderStatsLockKey int64 = 3570_0001
+
+const dailyOrderStatsDateLayout = "2006-01-02"
+
+type DailyOrderStatsRepositoryInterface interface {
+	// GetCoveredThrough возвращает последний посчитанный день (полночь по локальному времени).
+	GetCoveredThrough(ctx context.Context) (time.Time, bool, error)
+	GetEarliestOrderDate(ctx context.Context) (time.Time, bool, error)
+	// RebuildDay пересчитывает агрегаты за день и продвигает покрытие.
+	RebuildDay(ctx context.Context, day time.Time) (int64, error)
+}
+
+type DailyOrderStatsRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewDailyOrderStatsRepository(storage *pgxpool.Pool, logger *zap.Logger) DailyOrderStatsRepositoryInterface {
+	return &DailyOrderStatsRepository{storage: storage, logger: logger}
+}
+
+func (r *DailyOrderStatsRepository) GetCoveredThrough(ctx context.Context) (time.Time, bool, error) {
+	var raw string
+	err := r.storage.QueryRow(ctx, `SELECT to_char(covered_through, 'YYYY-MM-DD') FROM daily_order_stats_state WHERE id = 1`).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	day, err := time.ParseInLocation(dailyOrderStatsDateLayout, raw, time.Local)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return day, true, nil
+}
+
+func (r *DailyOrderStatsRepository) GetEarliestOrderDate(ctx context.Context) (time.Time, bool, error) {
+	var earliest *time.Time
+	if err := r.storage.QueryRow(ctx, `SELECT MIN(created_at) FROM orders WHERE deleted_at IS NULL`).Scan(&earliest); err != nil {
+		return time.Time{}, false, err
+	}
+	if earliest == nil {
+		return time.Time{}, false, nil
+	}
+	local := earliest.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local), true, nil
+}
+
+func (r *DailyOrderStatsRepository) RebuildDay(ctx context.Context, day time.Time) (int64, error) {
+	day = day.In(time.Local)
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 1)
+	statDate := from.Format(dailyOrderStatsDateLayout)
+
+	tx, err := r.storage.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, dailyOrderStatsLockKey); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM daily_order_stats WHERE stat_date = $1::date`, statDate); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, dailyOrderStatsRebuildQuery(), statDate, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO daily_order_stats_state (id, covered_through, updated_at)
+		VALUES (1, $1::date, NOW())
+		ON CONFLICT (id) DO UPDATE
+		SET covered_through = GREATEST(daily_order_stats_state.covered_through, EXCLUDED.covered_through),
+		    updated_at = NOW()`, statDate); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// dailyOrderStatsRebuildQuery повторяет правила живых запросов дашборда: создание считается
+// по created_at, закрытие — по последнему переходу в CLOSED, среди заявок, закрытых сейчас.
+func dailyOrderStatsRebuildQuery() string {
+	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
+	return fmt.Sprintf(`
+		INSERT INTO daily_order_stats (
+			stat_date, tenant_id, department_id, branch_id, otdel_id, office_id, order_type_id, priority_id,
+			created_count, closed_count, resolution_seconds_sum, resolution_count, sla_eligible, sla_on_time
+		)
+		SELECT
+			$1::date, tenant_id, department_id, branch_id, otdel_id, office_id, order_type_id, priority_id,
+			SUM(created_count), SUM(closed_count), SUM(resolution_seconds_sum),
+			SUM(resolution_count), SUM(sla_eligible), SUM(sla_on_time)
+		FROM (
+			SELECT
+				o.tenant_id, o.department_id, o.branch_id, o.otdel_id, o.office_id, o.order_type_id, o.priority_id,
+				1 AS created_count, 0 AS closed_count, 0 AS resolution_seconds_sum,
+				0 AS resolution_count, 0 AS sla_eligible, 0 AS sla_on_time
+			FROM orders o
+			WHERE o.deleted_at IS NULL
+			  AND o.created_at >= $2
+			  AND o.created_at < $3
+
+			UNION ALL
+
+			SELECT
+				o.tenant_id, o.department_id, o.branch_id, o.otdel_id, o.office_id, o.order_type_id, o.priority_id,
+				0, 1, COALESCE(o.resolution_time_seconds, 0),
+				CASE WHEN o.resolution_time_seconds IS NOT NULL THEN 1 ELSE 0 END,
+				CASE WHEN %s THEN 1 ELSE 0 END,
+				CASE WHEN %s THEN 1 ELSE 0 END
+			FROM orders o
+			JOIN statuses s ON o.status_id = s.id
+			WHERE o.deleted_at IS NULL
+			  AND %s
+			  AND o.id IN (
+				SELECT h.order_id
+				FROM order_history h
+				JOIN statuses target_status ON target_status.code = '%s'
+				WHERE h.event_type = 'STATUS_CHANGE'
+				  AND h.new_value = target_status.id::text
+				  AND h.created_at >= $2
+				  AND h.created_at < $3
+			  )
+			  AND %s >= $2
+			  AND %s < $3
+		) x
+		GROUP BY tenant_id, department_id, branch_id, otdel_id, office_id, order_type_id, priority_id`,
+		dashboardSLAEligibleCheck("o.duration"),
+		dashboardSLAOnTimeCheck("o.duration", "o.completed_at"),
+		dashboardResolvedCheck,
+		pkgconstants.StatusClosed,
+		closedAtExpr, closedAtExpr,
+	)
+}
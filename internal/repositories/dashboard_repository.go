@@ -9,10 +9,10 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/database/postgresql"
 	"request-system/pkg/types"
 )
 
@@ -37,6 +37,8 @@ type DashboardRepositoryInterface interface {
 	GetAvgTimeByOrderType(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardTimeByGroup, error)
 	GetCountByStatus(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardCountByGroup, error)
 	GetCountByExecutor(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardExecutorCount, error)
+	GetExecutorStats(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardExecutorStat, error)
+	GetBacklogAging(ctx context.Context, securityCondition sq.Sqlizer) (*types.DashboardBacklogAging, error)
 	GetWeeklyVolume(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardChartData, error)
 	GetTopCategories(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardCountByGroup, error)
 	GetDepartmentStats(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardDepartmentStat, error)
@@ -44,26 +46,27 @@ type DashboardRepositoryInterface interface {
 	GetBranchStats(ctx context.Context, securityCondition sq.Sqlizer, query types.DashboardQuery) ([]types.DashboardDepartmentStat, error)
 }
 
+// DashboardRepository только читает, поэтому storage может быть репликой (postgresql.ReadRouter).
 type DashboardRepository struct {
-	storage *pgxpool.Pool
+	storage postgresql.Reader
 	logger  *zap.Logger
 }
 
-func NewDashboardRepository(storage *pgxpool.Pool, logger *zap.Logger) DashboardRepositoryInterface {
+func NewDashboardRepository(storage postgresql.Reader, logger *zap.Logger) DashboardRepositoryInterface {
 	return &DashboardRepository{storage: storage, logger: logger}
 }
 
 func (r *DashboardRepository) GetAlerts(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) (*types.DashboardAlerts, error) {
 	builder := sq.Select(
-		"COUNT(CASE WHEN p.code = 'CRITICAL' AND "+dashboardOpenCheck+" THEN 1 END)",
-		"COUNT(CASE WHEN o.duration IS NOT NULL AND o.duration < NOW() AND "+dashboardOpenCheck+" THEN 1 END)",
+		"COUNT(CASE WHEN p.code = '"+pkgconstants.PriorityCritical+"' AND "+dashboardOpenCheck+" THEN 1 END)",
+		"COUNT(CASE WHEN "+OrderOverdueSQL("o")+" THEN 1 END)",
 	).
 		From("orders o").
 		LeftJoin("statuses s ON o.status_id = s.id").
 		LeftJoin("priorities p ON o.priority_id = p.id").
 		Where(sq.Eq{"o.deleted_at": nil})
 
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardRange(builder, "o.created_at", queryOptions.Range)
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
@@ -92,7 +95,7 @@ func (r *DashboardRepository) GetKPIsWithUser(ctx context.Context, securityCondi
 		From("orders o").
 		LeftJoin("statuses s ON o.status_id = s.id").
 		Where(sq.Eq{"o.deleted_at": nil})
-	base = applyDashboardSecurity(base, securityCondition)
+	base = applyDashboardSecurity(ctx, base, securityCondition)
 
 	baseSQL, baseArgs, err := base.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
@@ -282,6 +285,9 @@ func (r *DashboardRepository) GetKPIsWithUser(ctx context.Context, securityCondi
 }
 
 func (r *DashboardRepository) GetSLAStats(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) (*types.DashboardSLAStats, error) {
+	if rollup, ok := dashboardRollupRange(queryOptions); ok {
+		return r.getSLAStatsWithRollup(ctx, securityCondition, queryOptions.Range, rollup)
+	}
 	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
 	slaOnTimeCheck := dashboardSLAOnTimeCheck("o.duration", "o.completed_at")
 	builder := sq.Select(
@@ -292,7 +298,7 @@ func (r *DashboardRepository) GetSLAStats(ctx context.Context, securityCondition
 		Join("statuses s ON o.status_id = s.id").
 		Where(sq.Eq{"o.deleted_at": nil}).
 		Where(dashboardResolvedCheck)
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardExprRange(builder, closedAtExpr, queryOptions.Range)
 
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
@@ -306,6 +312,9 @@ func (r *DashboardRepository) GetSLAStats(ctx context.Context, securityCondition
 }
 
 func (r *DashboardRepository) GetAvgTimeByPriority(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) ([]types.DashboardTimeByGroup, error) {
+	if rollup, ok := dashboardRollupRange(queryOptions); ok {
+		return r.getAvgTimeByGroupWithRollup(ctx, "p.name", "priorities p ON o.priority_id = p.id", securityCondition, queryOptions.Range, rollup)
+	}
 	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
 	builder := sq.Select(
 		"p.name AS group_name",
@@ -317,12 +326,15 @@ func (r *DashboardRepository) GetAvgTimeByPriority(ctx context.Context, security
 		Where(dashboardResolvedCheck).
 		Where(sq.Eq{"o.deleted_at": nil}).
 		GroupBy("p.name")
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardExprRange(builder, closedAtExpr, queryOptions.Range)
 	return collectDashboardTimeGroups(ctx, r.storage, builder)
 }
 
 func (r *DashboardRepository) GetAvgTimeByOrderType(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) ([]types.DashboardTimeByGroup, error) {
+	if rollup, ok := dashboardRollupRange(queryOptions); ok {
+		return r.getAvgTimeByGroupWithRollup(ctx, "ot.name", "order_types ot ON o.order_type_id = ot.id", securityCondition, queryOptions.Range, rollup)
+	}
 	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
 	builder := sq.Select(
 		"ot.name AS group_name",
@@ -334,7 +346,7 @@ func (r *DashboardRepository) GetAvgTimeByOrderType(ctx context.Context, securit
 		Where(dashboardResolvedCheck).
 		Where(sq.Eq{"o.deleted_at": nil}).
 		GroupBy("ot.name")
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardExprRange(builder, closedAtExpr, queryOptions.Range)
 	return collectDashboardTimeGroups(ctx, r.storage, builder)
 }
@@ -346,7 +358,7 @@ func (r *DashboardRepository) GetCountByStatus(ctx context.Context, securityCond
 		Where(sq.Eq{"o.deleted_at": nil}).
 		GroupBy("s.name").
 		OrderBy("count DESC")
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardRange(builder, "o.created_at", queryOptions.Range)
 	return collectDashboardCountGroups(ctx, r.storage, builder)
 }
@@ -365,12 +377,84 @@ func (r *DashboardRepository) GetCountByExecutor(ctx context.Context, securityCo
 		GroupBy("u.id", "u.fio").
 		OrderBy("count DESC").
 		Limit(15)
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardRange(builder, "o.created_at", queryOptions.Range)
 	return collectDashboardExecutorCounts(ctx, r.storage, builder)
 }
 
+// GetExecutorStats собирает показатели по исполнителям: закрытые за период заявки, среднее время
+// решения, соблюдение SLA, повторные открытия и текущую нагрузку. Нагрузка считается
+// на текущий момент и периодом не ограничивается.
+func (r *DashboardRepository) GetExecutorStats(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) ([]types.DashboardExecutorStat, error) {
+	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
+	reopenedCheck := fmt.Sprintf(`EXISTS (
+		SELECT 1
+		FROM order_history rh
+		JOIN statuses old_status ON old_status.id::text = rh.old_value
+		JOIN statuses new_status ON new_status.id::text = rh.new_value
+		WHERE rh.order_id = o.id
+		  AND rh.event_type = 'STATUS_CHANGE'
+		  AND %s
+		  AND %s
+		  AND rh.created_at >= ?
+		  AND rh.created_at < ?
+	)`,
+		dashboardStatusInCheck("old_status.code", dashboardResolvedStatuses),
+		dashboardStatusNotInCheck("new_status.code", dashboardResolvedStatuses),
+	)
+
+	base := sq.Select(
+		"o.executor_id",
+		"o.resolution_time_seconds",
+		"("+dashboardOpenCheck+") AS is_open",
+		OrderOverdueSQL("o")+" AS is_overdue",
+		"("+dashboardSLAEligibleCheck("o.duration")+") AS is_sla_eligible",
+		"("+dashboardSLAOnTimeCheck("o.duration", "o.completed_at")+") AS is_sla_on_time",
+	).
+		Column(sq.Expr(
+			"("+dashboardResolvedCheck+" AND "+closedAtExpr+" >= ? AND "+closedAtExpr+" < ?) AS is_closed",
+			queryOptions.Range.From, queryOptions.Range.To,
+		)).
+		Column(sq.Expr(reopenedCheck+" AS is_reopened", queryOptions.Range.From, queryOptions.Range.To)).
+		From("orders o").
+		Join("statuses s ON o.status_id = s.id").
+		Where(sq.Eq{"o.deleted_at": nil}).
+		Where(sq.NotEq{"o.executor_id": nil})
+	base = applyDashboardSecurity(ctx, base, securityCondition)
+
+	builder := sq.Select(
+		"x.executor_id",
+		"COALESCE(u.fio, '') AS executor_name",
+		"COUNT(*) FILTER (WHERE x.is_closed) AS closed_count",
+		"COALESCE(AVG(x.resolution_time_seconds) FILTER (WHERE x.is_closed AND x.resolution_time_seconds >= 0), 0)::float8 AS avg_resolution_seconds",
+		"COUNT(*) FILTER (WHERE x.is_closed AND x.is_sla_eligible) AS sla_eligible",
+		"COUNT(*) FILTER (WHERE x.is_closed AND x.is_sla_on_time) AS sla_on_time",
+		"COUNT(*) FILTER (WHERE x.is_reopened) AS reopened_count",
+		"COUNT(*) FILTER (WHERE x.is_open) AS open_count",
+		"COUNT(*) FILTER (WHERE x.is_open AND x.is_overdue) AS overdue_open_count",
+	).
+		FromSelect(base, "x").
+		LeftJoin("users u ON u.id = x.executor_id").
+		Where("x.is_closed OR x.is_open OR x.is_reopened").
+		GroupBy("x.executor_id", "u.fio").
+		OrderBy("closed_count DESC", "open_count ASC", "x.executor_id ASC")
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardExecutorStat])
+}
+
 func (r *DashboardRepository) GetWeeklyVolume(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) ([]types.DashboardChartData, error) {
+	if rollup, ok := dashboardRollupRange(queryOptions); ok {
+		return r.getWeeklyVolumeWithRollup(ctx, securityCondition, queryOptions, rollup)
+	}
 	bucketExpr := dashboardBucketExpression(queryOptions.Granularity)
 	builder := sq.Select(
 		fmt.Sprintf("to_char(%s, 'YYYY-MM-DD') AS label", bucketExpr),
@@ -380,7 +464,7 @@ func (r *DashboardRepository) GetWeeklyVolume(ctx context.Context, securityCondi
 		Where(sq.Eq{"o.deleted_at": nil}).
 		GroupBy(bucketExpr).
 		OrderBy(bucketExpr + " ASC")
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardRange(builder, "o.created_at", queryOptions.Range)
 
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
@@ -406,14 +490,14 @@ func (r *DashboardRepository) GetTopCategories(ctx context.Context, securityCond
 		GroupBy("ot.name").
 		OrderBy("count DESC").
 		Limit(5)
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardRange(builder, "o.created_at", queryOptions.Range)
 	return collectDashboardCountGroups(ctx, r.storage, builder)
 }
 
 func (r *DashboardRepository) GetDepartmentStats(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) ([]types.DashboardDepartmentStat, error) {
 	builder := buildDashboardOrgStatsBuilder("d.name", "departments d ON o.department_id = d.id", queryOptions)
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	return collectDashboardDepartmentStats(ctx, r.storage, builder)
 }
 
@@ -434,7 +518,7 @@ func (r *DashboardRepository) GetLastActivity(ctx context.Context, securityCondi
 		Where(sq.Eq{"o.deleted_at": nil}).
 		OrderBy("h.created_at DESC").
 		Limit(10)
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	builder = applyDashboardRange(builder, "h.created_at", queryOptions.Range)
 
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
@@ -480,11 +564,76 @@ func (r *DashboardRepository) GetLastActivity(ctx context.Context, securityCondi
 func (r *DashboardRepository) GetBranchStats(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery) ([]types.DashboardDepartmentStat, error) {
 	builder := buildDashboardOrgStatsBuilder("b.name", "branches b ON o.branch_id = b.id", queryOptions).
 		Where(sq.Eq{"o.department_id": nil})
-	builder = applyDashboardSecurity(builder, securityCondition)
+	builder = applyDashboardSecurity(ctx, builder, securityCondition)
 	return collectDashboardDepartmentStats(ctx, r.storage, builder)
 }
 
-func applyDashboardSecurity(builder sq.SelectBuilder, securityCondition sq.Sqlizer) sq.SelectBuilder {
+// GetBacklogAging раскладывает открытые заявки по возрасту: в целом, по департаментам и по
+// филиалам. Как и в GetBranchStats, к филиалам относятся только заявки без департамента.
+func (r *DashboardRepository) GetBacklogAging(ctx context.Context, securityCondition sq.Sqlizer) (*types.DashboardBacklogAging, error) {
+	overallBuilder := applyDashboardSecurity(ctx, dashboardBacklogAgingBuilder(), securityCondition)
+	query, args, err := overallBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	overall, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[types.DashboardAgingBuckets])
+	if err != nil {
+		return nil, err
+	}
+
+	departmentsBuilder := dashboardBacklogAgingBuilder("d.id", "d.name").
+		Join("departments d ON o.department_id = d.id").
+		GroupBy("d.id", "d.name").
+		OrderBy("total DESC", "d.name ASC")
+	departments, err := collectDashboardAgingGroups(ctx, r.storage, applyDashboardSecurity(ctx, departmentsBuilder, securityCondition))
+	if err != nil {
+		return nil, err
+	}
+
+	branchesBuilder := dashboardBacklogAgingBuilder("b.id", "b.name").
+		Join("branches b ON o.branch_id = b.id").
+		Where(sq.Eq{"o.department_id": nil}).
+		GroupBy("b.id", "b.name").
+		OrderBy("total DESC", "b.name ASC")
+	branches, err := collectDashboardAgingGroups(ctx, r.storage, applyDashboardSecurity(ctx, branchesBuilder, securityCondition))
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DashboardBacklogAging{
+		Overall:     overall,
+		Departments: departments,
+		Branches:    branches,
+	}, nil
+}
+
+func dashboardBacklogAgingBuilder(groupColumns ...string) sq.SelectBuilder {
+	columns := make([]string, 0, len(groupColumns)+5)
+	if len(groupColumns) == 2 {
+		columns = append(columns, groupColumns[0]+" AS id", groupColumns[1]+" AS name")
+	}
+	columns = append(columns,
+		"COUNT(*) FILTER (WHERE o.created_at > NOW() - INTERVAL '1 day') AS lt_1d",
+		"COUNT(*) FILTER (WHERE o.created_at <= NOW() - INTERVAL '1 day' AND o.created_at > NOW() - INTERVAL '3 days') AS d1_3",
+		"COUNT(*) FILTER (WHERE o.created_at <= NOW() - INTERVAL '3 days' AND o.created_at > NOW() - INTERVAL '7 days') AS d3_7",
+		"COUNT(*) FILTER (WHERE o.created_at <= NOW() - INTERVAL '7 days') AS gt_7d",
+		"COUNT(*) AS total",
+	)
+	return sq.Select(columns...).
+		From("orders o").
+		Join("statuses s ON o.status_id = s.id").
+		Where(sq.Eq{"o.deleted_at": nil}).
+		Where(dashboardOpenCheck)
+}
+
+// applyDashboardSecurity добавляет условие видимости и организацию из контекста. Таблица заявок
+// и суточные агрегаты в запросах дашборда всегда идут под псевдонимом o.
+func applyDashboardSecurity(ctx context.Context, builder sq.SelectBuilder, securityCondition sq.Sqlizer) sq.SelectBuilder {
+	builder = builder.Where(tenantCondition(ctx, "o.tenant_id"))
 	if securityCondition == nil {
 		return builder
 	}
@@ -540,9 +689,10 @@ func dashboardLatestStatusChangeTimestampScalarExpr(orderAlias, statusCode strin
 		WHERE h.order_id = %s.id
 		  AND h.event_type = 'STATUS_CHANGE'
 		  AND h.new_value = target_status.id::text
+		  AND %s
 		ORDER BY h.created_at DESC
 		LIMIT 1
-	)`, strings.ReplaceAll(statusCode, "'", "''"), orderAlias)
+	)`, strings.ReplaceAll(statusCode, "'", "''"), orderAlias, OrderHistorySinceOrderSQL("h", orderAlias))
 }
 
 func dashboardLatestDelegationAssigneeCheck(historyAlias string) string {
@@ -558,13 +708,17 @@ func dashboardLatestDelegationAssigneeCheck(historyAlias string) string {
 }
 
 func dashboardBucketExpression(granularity string) string {
+	return dashboardBucketExpressionFor("o.created_at", granularity)
+}
+
+func dashboardBucketExpressionFor(column, granularity string) string {
 	switch granularity {
 	case types.DashboardGranularityMonth:
-		return "date_trunc('month', o.created_at)"
+		return "date_trunc('month', " + column + ")"
 	case types.DashboardGranularityWeek:
-		return "date_trunc('week', o.created_at)"
+		return "date_trunc('week', " + column + ")"
 	default:
-		return "date_trunc('day', o.created_at)"
+		return "date_trunc('day', " + column + ")"
 	}
 }
 
@@ -573,7 +727,7 @@ func buildDashboardOrgStatsBuilder(groupColumn, joinClause string, queryOptions
 		groupColumn,
 		"COUNT(CASE WHEN "+dashboardOpenCheck+" THEN 1 END) AS open_count",
 		"COUNT(CASE WHEN "+dashboardResolvedCheck+" THEN 1 END) AS resolved_count",
-		"COUNT(CASE WHEN p.code = 'CRITICAL' AND "+dashboardOpenCheck+" THEN 1 END) AS critical_count",
+		"COUNT(CASE WHEN p.code = '"+pkgconstants.PriorityCritical+"' AND "+dashboardOpenCheck+" THEN 1 END) AS critical_count",
 		"COUNT(*) AS total_count",
 	).
 		From("orders o").
@@ -586,7 +740,7 @@ func buildDashboardOrgStatsBuilder(groupColumn, joinClause string, queryOptions
 	return applyDashboardRange(builder, "o.created_at", queryOptions.Range)
 }
 
-func collectDashboardTimeGroups(ctx context.Context, storage *pgxpool.Pool, builder sq.SelectBuilder) ([]types.DashboardTimeByGroup, error) {
+func collectDashboardTimeGroups(ctx context.Context, storage postgresql.Reader, builder sq.SelectBuilder) ([]types.DashboardTimeByGroup, error) {
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return nil, err
@@ -599,7 +753,7 @@ func collectDashboardTimeGroups(ctx context.Context, storage *pgxpool.Pool, buil
 	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardTimeByGroup])
 }
 
-func collectDashboardCountGroups(ctx context.Context, storage *pgxpool.Pool, builder sq.SelectBuilder) ([]types.DashboardCountByGroup, error) {
+func collectDashboardCountGroups(ctx context.Context, storage postgresql.Reader, builder sq.SelectBuilder) ([]types.DashboardCountByGroup, error) {
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return nil, err
@@ -612,7 +766,7 @@ func collectDashboardCountGroups(ctx context.Context, storage *pgxpool.Pool, bui
 	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardCountByGroup])
 }
 
-func collectDashboardExecutorCounts(ctx context.Context, storage *pgxpool.Pool, builder sq.SelectBuilder) ([]types.DashboardExecutorCount, error) {
+func collectDashboardExecutorCounts(ctx context.Context, storage postgresql.Reader, builder sq.SelectBuilder) ([]types.DashboardExecutorCount, error) {
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return nil, err
@@ -625,7 +779,7 @@ func collectDashboardExecutorCounts(ctx context.Context, storage *pgxpool.Pool,
 	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardExecutorCount])
 }
 
-func collectDashboardDepartmentStats(ctx context.Context, storage *pgxpool.Pool, builder sq.SelectBuilder) ([]types.DashboardDepartmentStat, error) {
+func collectDashboardDepartmentStats(ctx context.Context, storage postgresql.Reader, builder sq.SelectBuilder) ([]types.DashboardDepartmentStat, error) {
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return nil, err
@@ -638,6 +792,19 @@ func collectDashboardDepartmentStats(ctx context.Context, storage *pgxpool.Pool,
 	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardDepartmentStat])
 }
 
+func collectDashboardAgingGroups(ctx context.Context, storage postgresql.Reader, builder sq.SelectBuilder) ([]types.DashboardAgingGroup, error) {
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardAgingGroup])
+}
+
 type dashboardActivityReferenceResolver struct {
 	ctx  context.Context
 	repo *DashboardRepository
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/types"
+)
+
+// dashboardRollupRange выделяет из периода целые дни, уже посчитанные в daily_order_stats.
+// Остаток периода (неполные дни по краям и всё, что позже RollupUntil) читается живыми запросами.
+func dashboardRollupRange(query types.DashboardQuery) (types.DashboardDateRange, bool) {
+	if query.RollupUntil.IsZero() {
+		return types.DashboardDateRange{}, false
+	}
+
+	loc := query.Range.From.Location()
+	from := dashboardStartOfDay(query.Range.From, loc)
+	if from.Before(query.Range.From) {
+		from = from.AddDate(0, 0, 1)
+	}
+	// Конец custom-периода — 23:59:59.999999999, такой день считаем целым.
+	to := dashboardStartOfDay(query.Range.To.Add(time.Nanosecond), loc)
+	if until := query.RollupUntil.In(loc); until.Before(to) {
+		to = dashboardStartOfDay(until, loc)
+	}
+
+	if !from.Before(to) {
+		return types.DashboardDateRange{}, false
+	}
+	return types.DashboardDateRange{From: from, To: to}, true
+}
+
+func dashboardStartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// dashboardLiveExprRange — живая часть периода вокруг дней из агрегатов.
+func dashboardLiveExprRange(expr string, full, rollup types.DashboardDateRange) sq.Sqlizer {
+	return sq.Or{
+		sq.And{sq.Expr(expr+" >= ?", full.From), sq.Expr(expr+" < ?", rollup.From)},
+		sq.And{sq.Expr(expr+" >= ?", rollup.To), sq.Expr(expr+" < ?", full.To)},
+	}
+}
+
+func applyDashboardRollupDates(builder sq.SelectBuilder, rollup types.DashboardDateRange) sq.SelectBuilder {
+	return builder.
+		Where(sq.Expr("o.stat_date >= ?::date", rollup.From.Format(dailyOrderStatsDateLayout))).
+		Where(sq.Expr("o.stat_date < ?::date", rollup.To.Format(dailyOrderStatsDateLayout)))
+}
+
+// dashboardUnionSQL склеивает подзапросы через UNION ALL и оборачивает их внешним запросом.
+// outer должен содержать ровно один %s — место для объединённого подзапроса.
+func dashboardUnionSQL(outer string, parts ...sq.SelectBuilder) (string, []interface{}, error) {
+	sqlParts := make([]string, 0, len(parts))
+	args := make([]interface{}, 0)
+	for _, part := range parts {
+		partSQL, partArgs, err := part.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		sqlParts = append(sqlParts, "("+partSQL+")")
+		args = append(args, partArgs...)
+	}
+
+	query, err := sq.Dollar.ReplacePlaceholders(fmt.Sprintf(outer, strings.Join(sqlParts, " UNION ALL ")))
+	if err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+func (r *DashboardRepository) getSLAStatsWithRollup(ctx context.Context, securityCondition sq.Sqlizer, full, rollup types.DashboardDateRange) (*types.DashboardSLAStats, error) {
+	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
+	live := sq.Select(
+		"COUNT(CASE WHEN o.duration IS NOT NULL THEN 1 END) AS total",
+		"COUNT(CASE WHEN "+dashboardSLAOnTimeCheck("o.duration", "o.completed_at")+" THEN 1 END) AS on_time",
+	).
+		From("orders o").
+		Join("statuses s ON o.status_id = s.id").
+		Where(sq.Eq{"o.deleted_at": nil}).
+		Where(dashboardResolvedCheck).
+		Where(dashboardLiveExprRange(closedAtExpr, full, rollup))
+	live = applyDashboardSecurity(ctx, live, securityCondition)
+
+	aggregated := sq.Select(
+		"COALESCE(SUM(o.sla_eligible), 0)::bigint AS total",
+		"COALESCE(SUM(o.sla_on_time), 0)::bigint AS on_time",
+	).
+		From("daily_order_stats o")
+	aggregated = applyDashboardRollupDates(applyDashboardSecurity(ctx, aggregated, securityCondition), rollup)
+
+	query, args, err := dashboardUnionSQL(`SELECT COALESCE(SUM(total), 0)::bigint, COALESCE(SUM(on_time), 0)::bigint FROM (%s) t`, live, aggregated)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.DashboardSLAStats{}
+	err = r.storage.QueryRow(ctx, query, args...).Scan(&result.TotalCompleted, &result.OnTime)
+	return result, err
+}
+
+func (r *DashboardRepository) getAvgTimeByGroupWithRollup(
+	ctx context.Context,
+	groupColumn, joinClause string,
+	securityCondition sq.Sqlizer,
+	full, rollup types.DashboardDateRange,
+) ([]types.DashboardTimeByGroup, error) {
+	closedAtExpr := dashboardLatestStatusChangeTimestampScalarExpr("o", pkgconstants.StatusClosed)
+	live := sq.Select(
+		groupColumn+" AS group_name",
+		"COALESCE(SUM(o.resolution_time_seconds), 0)::float8 AS seconds_sum",
+		"COUNT(o.resolution_time_seconds) AS seconds_count",
+	).
+		From("orders o").
+		Join(joinClause).
+		Join("statuses s ON o.status_id = s.id").
+		Where(dashboardResolvedCheck).
+		Where(sq.Eq{"o.deleted_at": nil}).
+		Where(dashboardLiveExprRange(closedAtExpr, full, rollup)).
+		GroupBy(groupColumn)
+	live = applyDashboardSecurity(ctx, live, securityCondition)
+
+	aggregated := sq.Select(
+		groupColumn+" AS group_name",
+		"SUM(o.resolution_seconds_sum)::float8 AS seconds_sum",
+		"SUM(o.resolution_count)::bigint AS seconds_count",
+	).
+		From("daily_order_stats o").
+		Join(joinClause).
+		Where(sq.Gt{"o.closed_count": 0}).
+		GroupBy(groupColumn)
+	aggregated = applyDashboardRollupDates(applyDashboardSecurity(ctx, aggregated, securityCondition), rollup)
+
+	query, args, err := dashboardUnionSQL(`
+		SELECT group_name, COALESCE(SUM(seconds_sum) / NULLIF(SUM(seconds_count), 0), 0)::float8 AS avg_seconds
+		FROM (%s) t
+		GROUP BY group_name`, live, aggregated)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardTimeByGroup])
+}
+
+func (r *DashboardRepository) getWeeklyVolumeWithRollup(ctx context.Context, securityCondition sq.Sqlizer, queryOptions types.DashboardQuery, rollup types.DashboardDateRange) ([]types.DashboardChartData, error) {
+	liveBucket := dashboardBucketExpressionFor("o.created_at", queryOptions.Granularity)
+	live := sq.Select(
+		fmt.Sprintf("to_char(%s, 'YYYY-MM-DD') AS label", liveBucket),
+		"COUNT(*) AS value",
+	).
+		From("orders o").
+		Where(sq.Eq{"o.deleted_at": nil}).
+		Where(dashboardLiveExprRange("o.created_at", queryOptions.Range, rollup)).
+		GroupBy(liveBucket)
+	live = applyDashboardSecurity(ctx, live, securityCondition)
+
+	rollupBucket := dashboardBucketExpressionFor("o.stat_date", queryOptions.Granularity)
+	aggregated := sq.Select(
+		fmt.Sprintf("to_char(%s, 'YYYY-MM-DD') AS label", rollupBucket),
+		"SUM(o.created_count)::bigint AS value",
+	).
+		From("daily_order_stats o").
+		Where(sq.Gt{"o.created_count": 0}).
+		GroupBy(rollupBucket)
+	aggregated = applyDashboardRollupDates(applyDashboardSecurity(ctx, aggregated, securityCondition), rollup)
+
+	query, args, err := dashboardUnionSQL(`
+		SELECT label, SUM(value)::bigint AS value
+		FROM (%s) t
+		GROUP BY label
+		ORDER BY label ASC`, live, aggregated)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowToStructByName[types.DashboardChartData])
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"request-system/pkg/types"
+)
+
+func TestDashboardRollupRange(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*3600)
+	now := time.Date(2026, 10, 16, 14, 30, 0, 0, loc)
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, loc)
+
+	query := types.DashboardQuery{
+		Range:       types.DashboardDateRange{From: today.AddDate(0, 0, -6), To: now},
+		RollupUntil: today,
+	}
+	rollup, ok := dashboardRollupRange(query)
+	if !ok || !rollup.From.Equal(today.AddDate(0, 0, -6)) || !rollup.To.Equal(today) {
+		t.Fatalf("unexpected rollup range: %+v ok=%v", rollup, ok)
+	}
+
+	// Покрытие отстаёт на два дня — они считаются живыми запросами.
+	query.RollupUntil = today.AddDate(0, 0, -2)
+	rollup, ok = dashboardRollupRange(query)
+	if !ok || !rollup.To.Equal(today.AddDate(0, 0, -2)) {
+		t.Fatalf("expected rollup to stop at coverage, got %+v", rollup)
+	}
+
+	// Custom-период до конца дня включает этот день целиком.
+	query.Range.To = time.Date(2026, 10, 12, 23, 59, 59, int(time.Second-time.Nanosecond), loc)
+	query.RollupUntil = today
+	rollup, ok = dashboardRollupRange(query)
+	if !ok || !rollup.To.Equal(time.Date(2026, 10, 13, 0, 0, 0, 0, loc)) {
+		t.Fatalf("expected end of custom period to be a whole day, got %+v", rollup)
+	}
+
+	if _, ok := dashboardRollupRange(types.DashboardQuery{Range: types.DashboardDateRange{From: today, To: now}, RollupUntil: today}); ok {
+		t.Fatalf("did not expect rollup for today only")
+	}
+	if _, ok := dashboardRollupRange(types.DashboardQuery{Range: query.Range}); ok {
+		t.Fatalf("did not expect rollup without coverage")
+	}
+}
+
+func TestDashboardUnionSQL_NumbersPlaceholders(t *testing.T) {
+	first := sq.Select("1").From("orders o").Where(sq.Eq{"o.id": 1})
+	second := sq.Select("2").From("daily_order_stats o").Where(sq.Expr("o.stat_date >= ?::date", "2026-10-01"))
+
+	query, args, err := dashboardUnionSQL("SELECT * FROM (%s) t", first, second)
+	if err != nil {
+		t.Fatalf("union failed: %v", err)
+	}
+	if len(args) != 2 || !strings.Contains(query, "o.id = $1") || !strings.Contains(query, "$2::date") || !strings.Contains(query, "UNION ALL") {
+		t.Fatalf("unexpected union: %s %v", query, args)
+	}
+}
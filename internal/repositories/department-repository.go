@@ -16,6 +16,7 @@ import (
 	"request-system/internal/entities"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
+	"request-system/pkg/utils"
 )
 
 const departmentTable = "departments"
@@ -72,8 +73,8 @@ func scanDepartment(row pgx.Row) (*entities.Department, error) {
 func (r *DepartmentRepository) Create(ctx context.Context, tx pgx.Tx, department entities.Department) (uint64, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err := psql.Insert(departmentTable).
-		Columns("name", "status_id", "external_id", "source_system", "created_at", "updated_at").
-		Values(department.Name, department.StatusID, department.ExternalID, department.SourceSystem, sq.Expr("NOW()"), sq.Expr("NOW()")).
+		Columns("name", "status_id", "external_id", "source_system", "tenant_id", "created_at", "updated_at").
+		Values(department.Name, department.StatusID, department.ExternalID, department.SourceSystem, utils.TenantIDOrDefault(ctx), sq.Expr("NOW()"), sq.Expr("NOW()")).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
@@ -107,7 +108,10 @@ func (r *DepartmentRepository) Update(ctx context.Context, tx pgx.Tx, id uint64,
 
 func (r *DepartmentRepository) findOne(ctx context.Context, querier Querier, where sq.Eq) (*entities.Department, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	query, args, err := psql.Select(departmentSelectFields...).From(departmentTable).Where(where).ToSql()
+	query, args, err := psql.Select(departmentSelectFields...).From(departmentTable).
+		Where(where).
+		Where(sharedTenantCondition(ctx, "tenant_id")).
+		ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -126,10 +130,15 @@ func (r *DepartmentRepository) FindDepartment(ctx context.Context, id uint64) (*
 	return r.findOne(ctx, r.storage, sq.Eq{"id": id})
 }
 
-func (r *DepartmentRepository) buildFilterQuery(filter types.Filter, tableAlias string) (string, []interface{}) {
+func (r *DepartmentRepository) buildFilterQuery(ctx context.Context, filter types.Filter, tableAlias string) (string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
 	argCounter := 1
+	if tenantID := tenantIDArg(ctx); tenantID != nil {
+		conditions = append(conditions, fmt.Sprintf("(%[1]s.tenant_id IS NULL OR %[1]s.tenant_id = $%[2]d)", tableAlias, argCounter))
+		args = append(args, *tenantID)
+		argCounter++
+	}
 	if filter.Search != "" {
 		conditions = append(conditions, fmt.Sprintf("%s.name ILIKE $%d", tableAlias, argCounter))
 		args = append(args, "%"+filter.Search+"%")
@@ -160,7 +169,7 @@ func (r *DepartmentRepository) buildFilterQuery(filter types.Filter, tableAlias
 }
 
 func (r *DepartmentRepository) CountDepartments(ctx context.Context, filter types.Filter, tableAlias string) (uint64, error) {
-	whereClause, args := r.buildFilterQuery(filter, tableAlias)
+	whereClause, args := r.buildFilterQuery(ctx, filter, tableAlias)
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s AS %s %s", departmentTable, tableAlias, whereClause)
 	var total uint64
 	if err := r.storage.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
@@ -171,7 +180,7 @@ func (r *DepartmentRepository) CountDepartments(ctx context.Context, filter type
 
 func (r *DepartmentRepository) GetDepartments(ctx context.Context, filter types.Filter) ([]entities.Department, uint64, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	baseBuilder := psql.Select().From(departmentTable + " AS d")
+	baseBuilder := psql.Select().From(departmentTable + " AS d").Where(sharedTenantCondition(ctx, "d.tenant_id"))
 
 	if filter.Search != "" {
 		baseBuilder = baseBuilder.Where(sq.ILike{"d.name": "%" + filter.Search + "%"})
@@ -243,10 +252,10 @@ func (r *DepartmentRepository) GetDepartments(ctx context.Context, filter types.
 
 func (r *DepartmentRepository) CreateDepartment(ctx context.Context, department entities.Department) (*entities.Department, error) {
 	query := `
-		INSERT INTO departments (name, status_id, external_id, source_system) 
-		VALUES($1, $2, $3, $4) 
+		INSERT INTO departments (name, status_id, external_id, source_system, tenant_id) 
+		VALUES($1, $2, $3, $4, $5) 
 		RETURNING id, name, status_id, created_at, updated_at, external_id, source_system`
-	return scanDepartment(r.storage.QueryRow(ctx, query, department.Name, department.StatusID, department.ExternalID, department.SourceSystem))
+	return scanDepartment(r.storage.QueryRow(ctx, query, department.Name, department.StatusID, department.ExternalID, department.SourceSystem, utils.TenantIDOrDefault(ctx)))
 }
 
 func (r *DepartmentRepository) UpdateDepartment(ctx context.Context, id uint64, dto dto.UpdateDepartmentDTO) (*entities.Department, error) {
@@ -294,7 +303,10 @@ func (r *DepartmentRepository) DeleteDepartment(ctx context.Context, id uint64)
 func (r *DepartmentRepository) FindIDByName(ctx context.Context, name string) (uint64, error) {
 	var id uint64
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	query, args, err := psql.Select("id").From(departmentTable).Where(sq.ILike{"name": name}).Limit(1).ToSql()
+	query, args, err := psql.Select("id").From(departmentTable).
+		Where(sq.ILike{"name": name}).
+		Where(sharedTenantCondition(ctx, "tenant_id")).
+		Limit(1).ToSql()
 	if err != nil {
 		return 0, err
 	}
@@ -307,7 +319,7 @@ func (r *DepartmentRepository) FindIDByName(ctx context.Context, name string) (u
 
 func (r *DepartmentRepository) GetDepartmentsWithStats(ctx context.Context, filter types.Filter) ([]dto.DepartmentStatsDTO, uint64, error) {
 	// Здесь тоже надо добавить deleted_at IS NULL в WHERE, если ты его используешь для orders
-	whereClause, args := r.buildFilterQuery(filter, "d")
+	whereClause, args := r.buildFilterQuery(ctx, filter, "d")
 	total, err := r.CountDepartments(ctx, filter, "d")
 	if err != nil || total == 0 {
 		return []dto.DepartmentStatsDTO{}, total, err
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type DictionaryVersionRepositoryInterface interface {
+	// GetVersion возвращает момент последнего изменения любого из справочников names.
+	// Нулевое время — версия неизвестна (например, миграция ещё не применена).
+	GetVersion(ctx context.Context, names []string) (time.Time, error)
+}
+
+type DictionaryVersionRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewDictionaryVersionRepository(storage *pgxpool.Pool, logger *zap.Logger) DictionaryVersionRepositoryInterface {
+	return &DictionaryVersionRepository{storage: storage, logger: logger}
+}
+
+func (r *DictionaryVersionRepository) GetVersion(ctx context.Context, names []string) (time.Time, error) {
+	var version *time.Time
+	err := r.storage.QueryRow(ctx, `SELECT MAX(updated_at) FROM dictionary_versions WHERE name = ANY($1)`, names).Scan(&version)
+	if err != nil || version == nil {
+		return time.Time{}, err
+	}
+	return *version, nil
+}
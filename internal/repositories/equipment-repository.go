@@ -11,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
+	"request-system/internal/dto"
 	"request-system/internal/entities"
 	// Подключаем наш БД-хелпер
 	"request-system/internal/infrastructure/bd"
@@ -30,6 +31,10 @@ var equipmentMap = map[string]string{
 	"branch_id":         "e.branch_id",
 	"office_id":         "e.office_id",
 	"equipment_type_id": "e.equipment_type_id",
+	"serial_number":     "e.serial_number",
+	"assigned_user_id":  "e.assigned_user_id",
+	"state":             "e.state",
+	"warranty_until":    "e.warranty_until",
 	"created_at":        "e.created_at",
 	"updated_at":        "e.updated_at",
 }
@@ -41,6 +46,29 @@ type EquipmentRepositoryInterface interface {
 	UpdateEquipment(ctx context.Context, id uint64, eq entities.Equipment) (*entities.Equipment, error)
 	DeleteEquipment(ctx context.Context, id uint64) error
 	CountOrdersByEquipmentID(ctx context.Context, id uint64) (int, error)
+	FindOrdersByEquipmentID(ctx context.Context, id uint64) ([]dto.EquipmentOrderDTO, error)
+	FindEquipmentByCode(ctx context.Context, code string) (*entities.Equipment, error)
+}
+
+// equipmentSelectColumns — список полей строго в порядке scanEquipment.
+var equipmentSelectColumns = []string{
+	"e.id", "e.name", "e.address", "e.branch_id", "e.office_id", "e.status_id", "e.equipment_type_id", "e.created_at", "e.updated_at",
+	"e.code", "e.serial_number", "e.assigned_user_id", "e.purchase_date", "e.warranty_until", "e.state", "e.state_changed_at",
+	"COALESCE(b.id, 0)", "COALESCE(b.name, '')", "COALESCE(b.short_name, '')",
+	"COALESCE(o.id, 0)", "COALESCE(o.name, '')",
+	"COALESCE(et.id, 0)", "COALESCE(et.name, '')",
+	"COALESCE(s.id, 0)", "COALESCE(s.name, '')",
+	"COALESCE(u.fio, '')",
+}
+
+func selectEquipments(psql sq.StatementBuilderType) sq.SelectBuilder {
+	return psql.Select(equipmentSelectColumns...).
+		From(equipmentTable + " e").
+		LeftJoin("branches b ON e.branch_id = b.id").
+		LeftJoin("offices o ON e.office_id = o.id").
+		LeftJoin("equipment_types et ON e.equipment_type_id = et.id").
+		LeftJoin("statuses s ON e.status_id = s.id").
+		LeftJoin("users u ON e.assigned_user_id = u.id")
 }
 
 type EquipmentRepository struct {
@@ -59,6 +87,7 @@ func (r *EquipmentRepository) scanEquipment(row pgx.Row) (*entities.Equipment, e
 	var o entities.Office
 	var et entities.EquipmentType
 	var s entities.Status
+	var assignedFio string
 	var createdAt, updatedAt time.Time
 
 	// ВАЖНО: Используем переменные-указатели для сканирования NULL значений
@@ -68,10 +97,12 @@ func (r *EquipmentRepository) scanEquipment(row pgx.Row) (*entities.Equipment, e
 		&e.ID, &e.Name, &e.Address,
 		&e.BranchID, &e.OfficeID, // Сканируем прямо в указатели структуры
 		&e.StatusID, &e.EquipmentTypeID, &createdAt, &updatedAt,
+		&e.Code, &e.SerialNumber, &e.AssignedUserID, &e.PurchaseDate, &e.WarrantyUntil, &e.State, &e.StateChangedAt,
 		&b.ID, &b.Name, &b.ShortName,
 		&o.ID, &o.Name,
 		&et.ID, &et.Name,
 		&s.ID, &s.Name,
+		&assignedFio,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, apperrors.ErrNotFound
@@ -93,6 +124,9 @@ func (r *EquipmentRepository) scanEquipment(row pgx.Row) (*entities.Equipment, e
 		e.EquipmentType = &et
 	}
 	e.Status = &s
+	if e.AssignedUserID != nil {
+		e.AssignedUser = &entities.User{ID: *e.AssignedUserID, Fio: assignedFio}
+	}
 
 	return &e, nil
 }
@@ -107,6 +141,7 @@ func (r *EquipmentRepository) GetEquipments(ctx context.Context, filter types.Fi
 			return b.Where(sq.Or{
 				sq.ILike{"e.name": pat},
 				sq.ILike{"e.address": pat},
+				sq.ILike{"e.serial_number": pat},
 			})
 		}
 		return b
@@ -138,18 +173,7 @@ func (r *EquipmentRepository) GetEquipments(ctx context.Context, filter types.Fi
 
 	// 2. SELECT
 	// Список полей строго как в scanEquipment
-	baseBuilder := psql.Select(
-		"e.id", "e.name", "e.address", "e.branch_id", "e.office_id", "e.status_id", "e.equipment_type_id", "e.created_at", "e.updated_at",
-		"COALESCE(b.id, 0)", "COALESCE(b.name, '')", "COALESCE(b.short_name, '')",
-		"COALESCE(o.id, 0)", "COALESCE(o.name, '')",
-		"COALESCE(et.id, 0)", "COALESCE(et.name, '')",
-		"COALESCE(s.id, 0)", "COALESCE(s.name, '')",
-	).
-		From(equipmentTable + " e").
-		LeftJoin("branches b ON e.branch_id = b.id").
-		LeftJoin("offices o ON e.office_id = o.id").
-		LeftJoin("equipment_types et ON e.equipment_type_id = et.id").
-		LeftJoin("statuses s ON e.status_id = s.id")
+	baseBuilder := selectEquipments(psql)
 
 	baseBuilder = applySearch(baseBuilder)
 
@@ -185,19 +209,7 @@ func (r *EquipmentRepository) GetEquipments(ctx context.Context, filter types.Fi
 
 func (r *EquipmentRepository) FindEquipment(ctx context.Context, id uint64) (*entities.Equipment, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	queryBuilder := psql.Select(
-		"e.id", "e.name", "e.address", "e.branch_id", "e.office_id", "e.status_id", "e.equipment_type_id", "e.created_at", "e.updated_at",
-		"COALESCE(b.id, 0)", "COALESCE(b.name, '')", "COALESCE(b.short_name, '')",
-		"COALESCE(o.id, 0)", "COALESCE(o.name, '')",
-		"COALESCE(et.id, 0)", "COALESCE(et.name, '')",
-		"COALESCE(s.id, 0)", "COALESCE(s.name, '')",
-	).
-		From(equipmentTable + " e").
-		LeftJoin("branches b ON e.branch_id = b.id").
-		LeftJoin("offices o ON e.office_id = o.id").
-		LeftJoin("equipment_types et ON e.equipment_type_id = et.id").
-		LeftJoin("statuses s ON e.status_id = s.id").
-		Where(sq.Eq{"e.id": id})
+	queryBuilder := selectEquipments(psql).Where(sq.Eq{"e.id": id})
 
 	sqlStr, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -207,12 +219,25 @@ func (r *EquipmentRepository) FindEquipment(ctx context.Context, id uint64) (*en
 	return r.scanEquipment(r.storage.QueryRow(ctx, sqlStr, args...))
 }
 
+// FindEquipmentByCode ищет оборудование по коду QR-наклейки.
+func (r *EquipmentRepository) FindEquipmentByCode(ctx context.Context, code string) (*entities.Equipment, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sqlStr, args, err := selectEquipments(psql).Where(sq.Eq{"e.code": code}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.scanEquipment(r.storage.QueryRow(ctx, sqlStr, args...))
+}
+
 func (r *EquipmentRepository) CreateEquipment(ctx context.Context, eq entities.Equipment) (*entities.Equipment, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query, args, err := psql.Insert(equipmentTable).
-		Columns("name", "address", "branch_id", "office_id", "status_id", "equipment_type_id").
-		Values(eq.Name, eq.Address, eq.BranchID, eq.OfficeID, eq.StatusID, eq.EquipmentTypeID).
+		Columns("name", "address", "branch_id", "office_id", "status_id", "equipment_type_id",
+			"serial_number", "assigned_user_id", "purchase_date", "warranty_until", "state", "state_changed_at").
+		Values(eq.Name, eq.Address, eq.BranchID, eq.OfficeID, eq.StatusID, eq.EquipmentTypeID,
+			eq.SerialNumber, eq.AssignedUserID, eq.PurchaseDate, eq.WarrantyUntil, eq.State, eq.StateChangedAt).
 		Suffix("RETURNING id").
 		ToSql()
 
@@ -222,7 +247,7 @@ func (r *EquipmentRepository) CreateEquipment(ctx context.Context, eq entities.E
 
 	var createdID uint64
 	if err := r.storage.QueryRow(ctx, query, args...).Scan(&createdID); err != nil {
-		return nil, err
+		return nil, apperrors.WrapDBError(err)
 	}
 	return r.FindEquipment(ctx, createdID)
 }
@@ -237,6 +262,12 @@ func (r *EquipmentRepository) UpdateEquipment(ctx context.Context, id uint64, eq
 		Set("office_id", eq.OfficeID).
 		Set("status_id", eq.StatusID).
 		Set("equipment_type_id", eq.EquipmentTypeID).
+		Set("serial_number", eq.SerialNumber).
+		Set("assigned_user_id", eq.AssignedUserID).
+		Set("purchase_date", eq.PurchaseDate).
+		Set("warranty_until", eq.WarrantyUntil).
+		Set("state", eq.State).
+		Set("state_changed_at", eq.StateChangedAt).
 		Set("updated_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -247,7 +278,7 @@ func (r *EquipmentRepository) UpdateEquipment(ctx context.Context, id uint64, eq
 
 	result, err := r.storage.Exec(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.WrapDBError(err)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -283,3 +314,31 @@ func (r *EquipmentRepository) CountOrdersByEquipmentID(ctx context.Context, id u
 	}
 	return count, nil
 }
+
+// FindOrdersByEquipmentID возвращает все заявки по оборудованию, новые первыми.
+func (r *EquipmentRepository) FindOrdersByEquipmentID(ctx context.Context, id uint64) ([]dto.EquipmentOrderDTO, error) {
+	query := `
+		SELECT o.id, o.number, o.name, o.status_id, COALESCE(st.name, ''), COALESCE(st.code, ''), executor.fio, o.created_at, o.completed_at
+		FROM orders o
+		LEFT JOIN statuses st ON st.id = o.status_id
+		LEFT JOIN users executor ON executor.id = o.executor_id
+		WHERE o.equipment_id = $1 AND o.deleted_at IS NULL
+		ORDER BY o.created_at DESC, o.id DESC`
+	rows, err := r.storage.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]dto.EquipmentOrderDTO, 0)
+	for rows.Next() {
+		var o dto.EquipmentOrderDTO
+		var createdAt time.Time
+		if err := rows.Scan(&o.ID, &o.Number, &o.Name, &o.StatusID, &o.StatusName, &o.StatusCode, &o.ExecutorName, &createdAt, &o.CompletedAt); err != nil {
+			return nil, err
+		}
+		o.CreatedAt = createdAt.Format(time.RFC3339)
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+)
+
+// eventOutboxChannel — канал LISTEN/NOTIFY. NOTIFY внутри транзакции доставляется только после COMMIT,
+// поэтому диспетчер просыпается ровно тогда, когда событие можно публиковать.
+const eventOutboxChannel = "event_outbox"
+
+const eventOutboxFields = `id, event_name, aggregate_id, payload, attempts, last_error, created_at, published_at`
+
+type EventOutboxRepositoryInterface interface {
+	CreateInTx(ctx context.Context, tx pgx.Tx, eventName string, aggregateID *uint64, payload []byte) error
+	// LockUnpublishedInTx блокирует пачку неопубликованных событий (SKIP LOCKED — несколько реплик не мешают друг другу).
+	LockUnpublishedInTx(ctx context.Context, tx pgx.Tx, limit int, maxAttempts int) ([]entities.EventOutboxRecord, error)
+	MarkPublishedInTx(ctx context.Context, tx pgx.Tx, ids []uint64) error
+	MarkFailedInTx(ctx context.Context, tx pgx.Tx, id uint64, lastError string) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+	// ListenForNewEvents сигналит о каждом закоммиченном событии. Канал закрывается вместе с ctx.
+	ListenForNewEvents(ctx context.Context) <-chan struct{}
+}
+
+type EventOutboxRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewEventOutboxRepository(storage *pgxpool.Pool, logger *zap.Logger) EventOutboxRepositoryInterface {
+	return &EventOutboxRepository{storage: storage, logger: logger}
+}
+
+func (r *EventOutboxRepository) CreateInTx(ctx context.Context, tx pgx.Tx, eventName string, aggregateID *uint64, payload []byte) error {
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO event_outbox (event_name, aggregate_id, payload) VALUES ($1, $2, $3)`,
+		eventName, aggregateID, payload,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `SELECT pg_notify($1, '')`, eventOutboxChannel)
+	return err
+}
+
+func (r *EventOutboxRepository) LockUnpublishedInTx(ctx context.Context, tx pgx.Tx, limit int, maxAttempts int) ([]entities.EventOutboxRecord, error) {
+	query := "SELECT " + eventOutboxFields + `
+		FROM event_outbox
+		WHERE published_at IS NULL AND attempts < $2
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, query, limit, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.EventOutboxRecord])
+}
+
+func (r *EventOutboxRepository) MarkPublishedInTx(ctx context.Context, tx pgx.Tx, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `UPDATE event_outbox SET published_at = NOW() WHERE id = ANY($1)`, ids)
+	return err
+}
+
+func (r *EventOutboxRepository) MarkFailedInTx(ctx context.Context, tx pgx.Tx, id uint64, lastError string) error {
+	_, err := tx.Exec(ctx, `UPDATE event_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`, id, lastError)
+	return err
+}
+
+func (r *EventOutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM event_outbox WHERE published_at IS NOT NULL AND published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *EventOutboxRepository) ListenForNewEvents(ctx context.Context) <-chan struct{} {
+	signals := make(chan struct{}, 1)
+
+	go func() {
+		defer close(signals)
+		for ctx.Err() == nil {
+			if err := r.listen(ctx, signals); err != nil && ctx.Err() == nil {
+				r.logger.Warn("LISTEN event_outbox прерван, переподключение", zap.Error(err))
+				select {
+				case <-ctx.Done():
+				case <-time.After(5 * time.Second):
+				}
+			}
+		}
+	}()
+
+	return signals
+}
+
+func (r *EventOutboxRepository) listen(ctx context.Context, signals chan<- struct{}) error {
+	conn, err := r.storage.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventOutboxChannel); err != nil {
+		return err
+	}
+	defer func() {
+		// Соединение вернётся в пул — снимаем подписку, чтобы оно не копило уведомления.
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+eventOutboxChannel)
+	}()
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		select {
+		case signals <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/pkg/constants"
+)
+
+type FirstResponseReminderRepositoryInterface interface {
+	// FindCandidates — открытые заявки с исполнителем и сроком отклика, созданные не раньше
+	// since, по которым отклика ещё не было, а текущий исполнитель не получал напоминания.
+	// Отбираются заявки, у которых по обычному времени прошло не меньше percent процентов
+	// срока: по рабочему календарю их проверяет сервис.
+	FindCandidates(ctx context.Context, since time.Time, percent int, limit int) ([]entities.FirstResponseCandidate, error)
+	// MarkReminded записывает напоминание; false — его уже отправила другая реплика.
+	MarkReminded(ctx context.Context, orderID, executorID uint64) (bool, error)
+}
+
+type FirstResponseReminderRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewFirstResponseReminderRepository(storage *pgxpool.Pool, logger *zap.Logger) FirstResponseReminderRepositoryInterface {
+	return &FirstResponseReminderRepository{storage: storage, logger: logger}
+}
+
+func (r *FirstResponseReminderRepository) FindCandidates(ctx context.Context, since time.Time, percent int, limit int) ([]entities.FirstResponseCandidate, error) {
+	query := fmt.Sprintf(`
+		SELECT o.id AS order_id, o.number AS order_number, o.name AS order_name, o.created_at,
+			pr.first_response_minutes, o.executor_id
+		FROM orders o
+		JOIN priorities pr ON pr.id = o.priority_id
+		WHERE o.deleted_at IS NULL
+			AND o.executor_id IS NOT NULL
+			AND o.first_response_time_seconds IS NULL
+			AND o.created_at >= $1
+			AND pr.first_response_minutes IS NOT NULL
+			AND o.created_at + pr.first_response_minutes * $2::int / 100.0 * INTERVAL '1 minute' <= NOW()
+			AND NOT EXISTS (SELECT 1 FROM statuses st WHERE st.id = o.status_id AND st.code IN (%s))
+			AND NOT EXISTS (
+				SELECT 1 FROM first_response_reminders fr
+				WHERE fr.order_id = o.id AND fr.executor_id = o.executor_id)
+		ORDER BY o.created_at
+		LIMIT $3`, dashboardQuotedStatusList(constants.FinalStatuses))
+
+	rows, err := r.storage.Query(ctx, query, since, percent, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.FirstResponseCandidate])
+}
+
+func (r *FirstResponseReminderRepository) MarkReminded(ctx context.Context, orderID, executorID uint64) (bool, error) {
+	tag, err := r.storage.Exec(ctx, `
+		INSERT INTO first_response_reminders (order_id, executor_id)
+		VALUES ($1, $2)
+		ON CONFLICT (order_id, executor_id) DO NOTHING`, orderID, executorID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
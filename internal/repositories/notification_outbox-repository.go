@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const notificationOutboxFields = `
+	id, user_id, channel, chat_id, message_type, payload, status, attempts, max_attempts,
+	next_attempt_at, last_error, created_at, updated_at, sent_at`
+
+type NotificationOutboxRepositoryInterface interface {
+	// Enqueue добавляет запись; с заполненным NextAttemptAt она не будет отправлена раньше этого момента.
+	Enqueue(ctx context.Context, item *entities.NotificationOutboxItem) error
+	// ClaimDue забирает готовые к отправке записи и продлевает им next_attempt_at на lease,
+	// чтобы запись, зависшая из-за падения воркера, была подхвачена повторно.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.NotificationOutboxItem, error)
+	MarkSent(ctx context.Context, id uint64) error
+	MarkRetry(ctx context.Context, id uint64, nextAttemptAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id uint64, lastError string) error
+	FindDead(ctx context.Context, limit, offset int) ([]entities.NotificationOutboxItem, uint64, error)
+	Requeue(ctx context.Context, id uint64) error
+	// RequeueDead возвращает в очередь все записи dead-letter канала (пустой — любого),
+	// упавшие не раньше since, и возвращает их число.
+	RequeueDead(ctx context.Context, channel string, since time.Time) (int64, error)
+}
+
+type NotificationOutboxRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewNotificationOutboxRepository(storage *pgxpool.Pool, logger *zap.Logger) NotificationOutboxRepositoryInterface {
+	return &NotificationOutboxRepository{storage: storage, logger: logger}
+}
+
+func (r *NotificationOutboxRepository) Enqueue(ctx context.Context, item *entities.NotificationOutboxItem) error {
+	query := `
+		INSERT INTO notification_outbox (user_id, channel, chat_id, message_type, payload, max_attempts, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::timestamptz, NOW()))
+		RETURNING id, status, attempts, next_attempt_at, created_at, updated_at`
+
+	var nextAttemptAt *time.Time
+	if !item.NextAttemptAt.IsZero() {
+		nextAttemptAt = &item.NextAttemptAt
+	}
+	return r.storage.QueryRow(ctx, query,
+		item.UserID, item.Channel, item.ChatID, item.MessageType, item.Payload, item.MaxAttempts, nextAttemptAt,
+	).Scan(&item.ID, &item.Status, &item.Attempts, &item.NextAttemptAt, &item.CreatedAt, &item.UpdatedAt)
+}
+
+func (r *NotificationOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.NotificationOutboxItem, error) {
+	query := `
+		UPDATE notification_outbox
+		SET status = 'processing',
+			attempts = attempts + 1,
+			next_attempt_at = NOW() + ($2::int * INTERVAL '1 second'),
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE status IN ('pending', 'processing') AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationOutboxFields
+
+	rows, err := r.storage.Query(ctx, query, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.NotificationOutboxItem])
+}
+
+func (r *NotificationOutboxRepository) MarkSent(ctx context.Context, id uint64) error {
+	_, err := r.storage.Exec(ctx,
+		`UPDATE notification_outbox SET status = 'sent', sent_at = NOW(), updated_at = NOW(), last_error = NULL WHERE id = $1`, id)
+	return err
+}
+
+func (r *NotificationOutboxRepository) MarkRetry(ctx context.Context, id uint64, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.storage.Exec(ctx,
+		`UPDATE notification_outbox SET status = 'pending', next_attempt_at = $2, last_error = $3, updated_at = NOW() WHERE id = $1`,
+		id, nextAttemptAt, lastError)
+	return err
+}
+
+func (r *NotificationOutboxRepository) MarkDead(ctx context.Context, id uint64, lastError string) error {
+	_, err := r.storage.Exec(ctx,
+		`UPDATE notification_outbox SET status = 'dead', last_error = $2, updated_at = NOW() WHERE id = $1`,
+		id, lastError)
+	return err
+}
+
+func (r *NotificationOutboxRepository) FindDead(ctx context.Context, limit, offset int) ([]entities.NotificationOutboxItem, uint64, error) {
+	var total uint64
+	if err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM notification_outbox WHERE status = 'dead'`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.NotificationOutboxItem{}, 0, nil
+	}
+
+	query := "SELECT " + notificationOutboxFields + `
+		FROM notification_outbox
+		WHERE status = 'dead'
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.storage.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.NotificationOutboxItem])
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Requeue возвращает запись из dead-letter в очередь с обнулённым счётчиком попыток.
+func (r *NotificationOutboxRepository) Requeue(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `
+		UPDATE notification_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'dead'`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *NotificationOutboxRepository) RequeueDead(ctx context.Context, channel string, since time.Time) (int64, error) {
+	tag, err := r.storage.Exec(ctx, `
+		UPDATE notification_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		WHERE status = 'dead' AND ($1 = '' OR channel = $1) AND updated_at >= $2`, channel, since)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
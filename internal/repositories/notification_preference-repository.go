@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+)
+
+type NotificationPreferenceRepositoryInterface interface {
+	// FindByUserID возвращает настройки пользователя или настройки по умолчанию, если строки ещё нет.
+	FindByUserID(ctx context.Context, userID uint64) (*entities.NotificationPreference, error)
+	FindByUserIDs(ctx context.Context, userIDs []uint64) (map[uint64]*entities.NotificationPreference, error)
+	Upsert(ctx context.Context, pref *entities.NotificationPreference) error
+}
+
+// Тихие часы хранятся в колонках TIME, наружу отдаём минуты от начала суток.
+const notificationPreferenceFields = `
+	user_id, telegram_enabled, websocket_enabled, muted_event_types,
+	(EXTRACT(EPOCH FROM quiet_hours_start) / 60)::int,
+	(EXTRACT(EPOCH FROM quiet_hours_end) / 60)::int,
+	delivery_mode, created_at, updated_at`
+
+type NotificationPreferenceRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewNotificationPreferenceRepository(storage *pgxpool.Pool, logger *zap.Logger) NotificationPreferenceRepositoryInterface {
+	return &NotificationPreferenceRepository{storage: storage, logger: logger}
+}
+
+func scanNotificationPreference(row pgx.Row) (*entities.NotificationPreference, error) {
+	var p entities.NotificationPreference
+	err := row.Scan(
+		&p.UserID, &p.TelegramEnabled, &p.WebSocketEnabled, &p.MutedEventTypes,
+		&p.QuietHoursStart, &p.QuietHoursEnd,
+		&p.DeliveryMode, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if p.MutedEventTypes == nil {
+		p.MutedEventTypes = []string{}
+	}
+	return &p, nil
+}
+
+func (r *NotificationPreferenceRepository) FindByUserID(ctx context.Context, userID uint64) (*entities.NotificationPreference, error) {
+	query := "SELECT " + notificationPreferenceFields + " FROM notification_preferences WHERE user_id = $1"
+
+	pref, err := scanNotificationPreference(r.storage.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.DefaultNotificationPreference(userID), nil
+		}
+		return nil, err
+	}
+	return pref, nil
+}
+
+func (r *NotificationPreferenceRepository) FindByUserIDs(ctx context.Context, userIDs []uint64) (map[uint64]*entities.NotificationPreference, error) {
+	result := make(map[uint64]*entities.NotificationPreference, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	query := "SELECT " + notificationPreferenceFields + " FROM notification_preferences WHERE user_id = ANY($1)"
+	rows, err := r.storage.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pref, err := scanNotificationPreference(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[pref.UserID] = pref
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range userIDs {
+		if _, ok := result[id]; !ok {
+			result[id] = entities.DefaultNotificationPreference(id)
+		}
+	}
+	return result, nil
+}
+
+func (r *NotificationPreferenceRepository) Upsert(ctx context.Context, pref *entities.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (
+			user_id, telegram_enabled, websocket_enabled, muted_event_types,
+			quiet_hours_start, quiet_hours_end, delivery_mode, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4,
+			($5::int * INTERVAL '1 minute')::time, ($6::int * INTERVAL '1 minute')::time, $7, NOW(), NOW()
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			telegram_enabled  = EXCLUDED.telegram_enabled,
+			websocket_enabled = EXCLUDED.websocket_enabled,
+			muted_event_types = EXCLUDED.muted_event_types,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end   = EXCLUDED.quiet_hours_end,
+			delivery_mode     = EXCLUDED.delivery_mode,
+			updated_at        = NOW()
+		RETURNING created_at, updated_at`
+
+	muted := pref.MutedEventTypes
+	if muted == nil {
+		muted = []string{}
+	}
+
+	return r.storage.QueryRow(ctx, query,
+		pref.UserID, pref.TelegramEnabled, pref.WebSocketEnabled, muted,
+		pref.QuietHoursStart, pref.QuietHoursEnd, pref.DeliveryMode,
+	).Scan(&pref.CreatedAt, &pref.UpdatedAt)
+}
@@ -18,6 +18,7 @@ import (
 
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
+	"request-system/pkg/utils"
 )
 
 const officeTable = "offices"
@@ -134,7 +135,7 @@ func (r *OfficeRepository) GetOffices(ctx context.Context, filter types.Filter)
 	}
 
 	// 1. COUNT
-	countBuilder := psql.Select("COUNT(o.id)").From(officeTable + " AS o")
+	countBuilder := psql.Select("COUNT(o.id)").From(officeTable + " AS o").Where(sharedTenantCondition(ctx, "o.tenant_id"))
 
 	countBuilder = applySearch(countBuilder)
 
@@ -167,7 +168,8 @@ func (r *OfficeRepository) GetOffices(ctx context.Context, filter types.Filter)
 		From(officeTable + " AS o").
 		LeftJoin("branches b ON o.branch_id = b.id").
 		LeftJoin("statuses s ON o.status_id = s.id").
-		LeftJoin(officeTable + " p_o ON o.parent_id = p_o.id")
+		LeftJoin(officeTable + " p_o ON o.parent_id = p_o.id").
+		Where(sharedTenantCondition(ctx, "o.tenant_id"))
 
 	baseBuilder = applySearch(baseBuilder)
 
@@ -214,6 +216,7 @@ func (r *OfficeRepository) FindOffice(ctx context.Context, id uint64) (*entities
 		LeftJoin("statuses s ON o.status_id = s.id").
 		LeftJoin(officeTable + " p_o ON o.parent_id = p_o.id").
 		Where(sq.Eq{"o.id": id}).
+		Where(sharedTenantCondition(ctx, "o.tenant_id")).
 		ToSql()
 	if err != nil {
 		return nil, err
@@ -223,11 +226,12 @@ func (r *OfficeRepository) FindOffice(ctx context.Context, id uint64) (*entities
 }
 
 func (r *OfficeRepository) CreateOffice(ctx context.Context, tx pgx.Tx, office entities.Office) (uint64, error) {
-	query := `INSERT INTO offices (name, address, open_date, branch_id, parent_id, status_id, external_id, source_system, created_at, updated_at) 
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()) RETURNING id`
+	query := `INSERT INTO offices (name, address, open_date, branch_id, parent_id, status_id, external_id, source_system, tenant_id, created_at, updated_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW()) RETURNING id`
 	var newID uint64
 	err := tx.QueryRow(ctx, query,
-		office.Name, office.Address, office.OpenDate, office.BranchID, office.ParentID, office.StatusID, office.ExternalID, office.SourceSystem).Scan(&newID)
+		office.Name, office.Address, office.OpenDate, office.BranchID, office.ParentID, office.StatusID, office.ExternalID, office.SourceSystem,
+		utils.TenantIDOrDefault(ctx)).Scan(&newID)
 	return newID, err
 }
 
@@ -313,7 +317,7 @@ func (r *OfficeRepository) DeleteOffice(ctx context.Context, id uint64) error {
 func (r *OfficeRepository) findOneOffice(ctx context.Context, querier Querier, where sq.Eq) (*entities.Office, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	query, args, err := psql.Select("id, name, address, open_date, branch_id, parent_id, status_id, created_at, updated_at, external_id, source_system").
-		From(officeTable).Where(where).ToSql()
+		From(officeTable).Where(where).Where(sharedTenantCondition(ctx, "tenant_id")).ToSql()
 	if err != nil {
 		return nil, err
 	}
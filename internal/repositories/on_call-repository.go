@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const onCallScheduleSelectQuery = `
+	SELECT s.id, s.name, s.department_id, d.name AS department_name, s.rotation_start, s.shift_hours,
+		s.is_active, s.created_at, s.updated_at
+	FROM on_call_schedules s
+	LEFT JOIN departments d ON d.id = s.department_id`
+
+const onCallOverrideSelectQuery = `
+	SELECT o.id, o.schedule_id, o.user_id, u.fio, o.starts_at, o.ends_at, o.reason, o.created_by, o.created_at
+	FROM on_call_overrides o
+	JOIN users u ON u.id = o.user_id`
+
+type OnCallRepositoryInterface interface {
+	// FindSchedules — графики департамента (или все при departmentID == nil).
+	FindSchedules(ctx context.Context, departmentID *uint64, onlyActive bool) ([]entities.OnCallSchedule, error)
+	FindScheduleByID(ctx context.Context, id uint64) (*entities.OnCallSchedule, error)
+	// CreateSchedule сохраняет график; memberIDs — очередь дежурных по порядку.
+	CreateSchedule(ctx context.Context, schedule *entities.OnCallSchedule, memberIDs []uint64) error
+	// UpdateSchedule меняет реквизиты графика; memberIDs != nil заменяет очередь целиком.
+	UpdateSchedule(ctx context.Context, schedule *entities.OnCallSchedule, memberIDs []uint64) error
+	DeleteSchedule(ctx context.Context, id uint64) error
+
+	FindMembers(ctx context.Context, scheduleID uint64) ([]entities.OnCallMember, error)
+	// FindOverrides — замены графика, которые заканчиваются позже from.
+	FindOverrides(ctx context.Context, scheduleID uint64, from time.Time) ([]entities.OnCallOverride, error)
+	CreateOverride(ctx context.Context, override *entities.OnCallOverride) error
+	DeleteOverride(ctx context.Context, scheduleID, id uint64) error
+}
+
+type OnCallRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewOnCallRepository(storage *pgxpool.Pool, logger *zap.Logger) OnCallRepositoryInterface {
+	return &OnCallRepository{storage: storage, logger: logger}
+}
+
+func (r *OnCallRepository) FindSchedules(ctx context.Context, departmentID *uint64, onlyActive bool) ([]entities.OnCallSchedule, error) {
+	query := onCallScheduleSelectQuery + ` WHERE ($1::bigint IS NULL OR s.department_id = $1)`
+	if onlyActive {
+		query += ` AND s.is_active`
+	}
+	query += ` ORDER BY s.department_id, LOWER(s.name)`
+
+	rows, err := r.storage.Query(ctx, query, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OnCallSchedule])
+}
+
+func (r *OnCallRepository) FindScheduleByID(ctx context.Context, id uint64) (*entities.OnCallSchedule, error) {
+	rows, err := r.storage.Query(ctx, onCallScheduleSelectQuery+` WHERE s.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.OnCallSchedule])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *OnCallRepository) CreateSchedule(ctx context.Context, schedule *entities.OnCallSchedule, memberIDs []uint64) error {
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO on_call_schedules (name, department_id, rotation_start, shift_hours, is_active)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at, updated_at`,
+			schedule.Name, schedule.DepartmentID, schedule.RotationStart, schedule.ShiftHours, schedule.IsActive,
+		).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+		if err != nil {
+			return apperrors.WrapDBError(err)
+		}
+		return replaceOnCallMembers(ctx, tx, schedule.ID, memberIDs)
+	})
+}
+
+func (r *OnCallRepository) UpdateSchedule(ctx context.Context, schedule *entities.OnCallSchedule, memberIDs []uint64) error {
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE on_call_schedules
+			SET name = $1, department_id = $2, rotation_start = $3, shift_hours = $4, is_active = $5, updated_at = NOW()
+			WHERE id = $6`,
+			schedule.Name, schedule.DepartmentID, schedule.RotationStart, schedule.ShiftHours, schedule.IsActive, schedule.ID,
+		)
+		if err != nil {
+			return apperrors.WrapDBError(err)
+		}
+		if tag.RowsAffected() == 0 {
+			return apperrors.ErrNotFound
+		}
+		if memberIDs == nil {
+			return nil
+		}
+		return replaceOnCallMembers(ctx, tx, schedule.ID, memberIDs)
+	})
+}
+
+func (r *OnCallRepository) DeleteSchedule(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM on_call_schedules WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *OnCallRepository) FindMembers(ctx context.Context, scheduleID uint64) ([]entities.OnCallMember, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT m.user_id, u.fio, m.position
+		FROM on_call_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.schedule_id = $1 AND u.deleted_at IS NULL
+		ORDER BY m.position`, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OnCallMember])
+}
+
+func (r *OnCallRepository) FindOverrides(ctx context.Context, scheduleID uint64, from time.Time) ([]entities.OnCallOverride, error) {
+	rows, err := r.storage.Query(ctx, onCallOverrideSelectQuery+`
+		WHERE o.schedule_id = $1 AND o.ends_at > $2
+		ORDER BY o.starts_at, o.id`, scheduleID, from)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OnCallOverride])
+}
+
+func (r *OnCallRepository) CreateOverride(ctx context.Context, override *entities.OnCallOverride) error {
+	err := r.storage.QueryRow(ctx, `
+		INSERT INTO on_call_overrides (schedule_id, user_id, starts_at, ends_at, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		override.ScheduleID, override.UserID, override.StartsAt, override.EndsAt, override.Reason, override.CreatedBy,
+	).Scan(&override.ID, &override.CreatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *OnCallRepository) DeleteOverride(ctx context.Context, scheduleID, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM on_call_overrides WHERE id = $1 AND schedule_id = $2`, id, scheduleID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+// replaceOnCallMembers записывает очередь заново: порядок userIDs становится порядком ротации.
+func replaceOnCallMembers(ctx context.Context, tx pgx.Tx, scheduleID uint64, userIDs []uint64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM on_call_members WHERE schedule_id = $1`, scheduleID); err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO on_call_members (schedule_id, user_id, position)
+		SELECT $1, m.user_id, m.position
+		FROM UNNEST($2::bigint[]) WITH ORDINALITY AS m(user_id, position)`,
+		scheduleID, userIDs,
+	)
+	return apperrors.WrapDBError(err)
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+// ActiveOrderAccessGrantCondition — заявки, к которым пользователю (?) выдан действующий доступ.
+const ActiveOrderAccessGrantCondition = `o.id IN (
+	SELECT order_id FROM order_access_grants
+	WHERE user_id = ? AND (expires_at IS NULL OR expires_at > NOW()))`
+
+const orderAccessGrantSelectQuery = `
+	SELECT g.id, g.order_id, g.user_id, u.fio AS user_fio, g.level, g.granted_by,
+	       gb.fio AS granted_by_fio, g.expires_at, g.created_at
+	FROM order_access_grants g
+	JOIN users u ON u.id = g.user_id
+	LEFT JOIN users gb ON gb.id = g.granted_by`
+
+type OrderAccessGrantRepositoryInterface interface {
+	// FindActiveLevel возвращает уровень действующего доступа или "", если его нет.
+	FindActiveLevel(ctx context.Context, orderID, userID uint64) (string, error)
+	FindByOrder(ctx context.Context, orderID uint64) ([]entities.OrderAccessGrant, error)
+	FindByOrderAndUser(ctx context.Context, orderID, userID uint64) (*entities.OrderAccessGrant, error)
+	// UpsertInTx выдаёт доступ; повторная выдача тому же сотруднику меняет уровень и срок.
+	UpsertInTx(ctx context.Context, tx pgx.Tx, grant *entities.OrderAccessGrant) error
+	DeleteInTx(ctx context.Context, tx pgx.Tx, orderID, userID uint64) error
+}
+
+type OrderAccessGrantRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewOrderAccessGrantRepository(storage *pgxpool.Pool, logger *zap.Logger) OrderAccessGrantRepositoryInterface {
+	return &OrderAccessGrantRepository{storage: storage, logger: logger}
+}
+
+func (r *OrderAccessGrantRepository) FindActiveLevel(ctx context.Context, orderID, userID uint64) (string, error) {
+	var level string
+	err := r.storage.QueryRow(ctx, `
+		SELECT level FROM order_access_grants
+		WHERE order_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > NOW())`,
+		orderID, userID,
+	).Scan(&level)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return level, err
+}
+
+func (r *OrderAccessGrantRepository) FindByOrder(ctx context.Context, orderID uint64) ([]entities.OrderAccessGrant, error) {
+	rows, err := r.storage.Query(ctx, orderAccessGrantSelectQuery+`
+		WHERE g.order_id = $1
+		ORDER BY g.created_at DESC`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OrderAccessGrant])
+}
+
+func (r *OrderAccessGrantRepository) FindByOrderAndUser(ctx context.Context, orderID, userID uint64) (*entities.OrderAccessGrant, error) {
+	rows, err := r.storage.Query(ctx, orderAccessGrantSelectQuery+` WHERE g.order_id = $1 AND g.user_id = $2`, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	grant, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.OrderAccessGrant])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (r *OrderAccessGrantRepository) UpsertInTx(ctx context.Context, tx pgx.Tx, grant *entities.OrderAccessGrant) error {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO order_access_grants (order_id, user_id, level, granted_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id, user_id) DO UPDATE
+		SET level = EXCLUDED.level, granted_by = EXCLUDED.granted_by,
+		    expires_at = EXCLUDED.expires_at, created_at = NOW()
+		RETURNING id, created_at`,
+		grant.OrderID, grant.UserID, grant.Level, grant.GrantedBy, grant.ExpiresAt,
+	).Scan(&grant.ID, &grant.CreatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *OrderAccessGrantRepository) DeleteInTx(ctx context.Context, tx pgx.Tx, orderID, userID uint64) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM order_access_grants WHERE order_id = $1 AND user_id = $2`, orderID, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
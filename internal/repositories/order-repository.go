@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"time"
 
@@ -15,6 +17,8 @@ import (
 
 	"request-system/internal/infrastructure/bd"
 
+	"request-system/pkg/constants"
+	"request-system/pkg/database/postgresql"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
 )
@@ -24,24 +28,27 @@ const (
 )
 
 var orderMap = map[string]string{
-	"id":                "o.id",
-	"name":              "o.name",
-	"status_id":         "o.status_id",
-	"priority_id":       "o.priority_id",
-	"department_id":     "o.department_id",
-	"branch_id":         "o.branch_id",
-	"otdel_id":          "o.otdel_id",
-	"office_id":         "o.office_id",
-	"executor_id":       "o.executor_id",
-	"creator_id":        "o.user_id",
-	"user_id":           "o.user_id",
-	"created_at":        "o.created_at",
-	"updated_at":        "o.updated_at",
-	"order_type_id":     "o.order_type_id",
-	"address":           "o.address",
-	"duration":          "o.duration",
-	"equipment_id":      "o.equipment_id",
-	"equipment_type_id": "o.equipment_type_id",
+	"id":                 "o.id",
+	"number":             "o.number",
+	"name":               "o.name",
+	"status_id":          "o.status_id",
+	"priority_id":        "o.priority_id",
+	"department_id":      "o.department_id",
+	"branch_id":          "o.branch_id",
+	"otdel_id":           "o.otdel_id",
+	"office_id":          "o.office_id",
+	"executor_id":        "o.executor_id",
+	"creator_id":         "o.user_id",
+	"user_id":            "o.user_id",
+	"created_at":         "o.created_at",
+	"updated_at":         "o.updated_at",
+	"order_type_id":      "o.order_type_id",
+	"address":            "o.address",
+	"duration":           "o.duration",
+	"equipment_id":       "o.equipment_id",
+	"equipment_type_id":  "o.equipment_type_id",
+	"team_id":            "o.team_id",
+	"resolution_code_id": "o.resolution_code_id",
 }
 
 type OrderRepositoryInterface interface {
@@ -55,57 +62,124 @@ type OrderRepositoryInterface interface {
 	GetOrders(ctx context.Context, filter types.Filter, securityCondition sq.Sqlizer) ([]entities.Order, uint64, error)
 
 	GetUserOrderStats(ctx context.Context, userID uint64, fromDate time.Time) (*types.UserOrderStats, error)
+	// GetUnitOrderStats считает заявки департамента, а при otdelID — только этого отдела.
+	GetUnitOrderStats(ctx context.Context, departmentID uint64, otdelID *uint64, fromDate time.Time) (*types.UserOrderStats, error)
+	FindExportNames(ctx context.Context, orderIDs []uint64) (map[uint64]OrderExportNames, error)
+
+	FindRecentByEquipment(ctx context.Context, equipmentID uint64, since time.Time, excludeID uint64, limit uint64, securityCondition sq.Sqlizer) ([]entities.Order, error)
+	MarkDuplicateInTx(ctx context.Context, tx pgx.Tx, orderID, duplicateOfID uint64) error
+
+	// ClaimTeamOrderInTx назначает userID исполнителем заявки команды, если её ещё никто
+	// не забрал и userID состоит в команде. false — заявку уже забрали или пользователь не в команде.
+	ClaimTeamOrderInTx(ctx context.Context, tx pgx.Tx, orderID, userID uint64) (bool, error)
+	// ClaimNextOrderInTx назначает userID исполнителем самой старой открытой заявки департамента
+	// без исполнителя, на которую у него хватает навыков, и возвращает её уже с исполнителем.
+	// nil — подходящих свободных заявок нет.
+	ClaimNextOrderInTx(ctx context.Context, tx pgx.Tx, userID, departmentID uint64) (*entities.Order, error)
+	IsTeamMember(ctx context.Context, teamID, userID uint64) (bool, error)
+
+	// FindExecutorCandidates возвращает активных сотрудников, подходящих под structure,
+	// с текущей нагрузкой, средним временем решения и навыками для типа заявки orderTypeID.
+	FindExecutorCandidates(ctx context.Context, structure sq.Sqlizer, orderTypeID uint64, limit uint64) ([]entities.ExecutorCandidate, error)
 }
 
+// OrderExportNames — названия справочников заявки для выгрузки в CSV/XLSX.
+type OrderExportNames struct {
+	Status        string
+	Priority      string
+	OrderType     string
+	Department    string
+	Otdel         string
+	Branch        string
+	Office        string
+	EquipmentType string
+	Equipment     string
+}
+
+// OrderRepository — reader обслуживает тяжёлые чтения (список и выгрузка заявок) и может
+// указывать на реплику; всё остальное идёт через storage.
 type OrderRepository struct {
 	storage *pgxpool.Pool
+	reader  postgresql.Reader
 	logger  *zap.Logger
 }
 
-func NewOrderRepository(storage *pgxpool.Pool, logger *zap.Logger) OrderRepositoryInterface {
-	return &OrderRepository{storage: storage, logger: logger}
+func NewOrderRepository(storage *pgxpool.Pool, reader postgresql.Reader, logger *zap.Logger) OrderRepositoryInterface {
+	return &OrderRepository{storage: storage, reader: reader, logger: logger}
 }
 
 func (r *OrderRepository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return r.storage.Begin(ctx)
 }
 
-func (r *OrderRepository) buildOrderSelectQuery() sq.SelectBuilder {
-	return sq.Select(
-		"o.id",
-		"o.name",
-		"o.address",
-		"o.department_id",
-		"o.otdel_id",
-		"o.branch_id",
-		"o.office_id",
-		"o.equipment_id",
-		"o.equipment_type_id",
-		"o.order_type_id",
-		"o.status_id",
-		"o.priority_id",
-		"o.user_id",
-		"o.executor_id",
-		"o.duration",
-		"o.created_at",
-		"o.updated_at",
-		"o.deleted_at",
-		"o.completed_at",
-		"o.first_response_time_seconds",
-		"o.resolution_time_seconds",
-		"o.is_first_contact_resolution",
+// orderSelectColumns — собственные поля заявки, общие для всех выборок.
+var orderSelectColumns = []string{
+	"o.id",
+	"o.number",
+	"o.name",
+	"o.address",
+	"o.department_id",
+	"o.otdel_id",
+	"o.branch_id",
+	"o.office_id",
+	"o.equipment_id",
+	"o.equipment_type_id",
+	"o.order_type_id",
+	"o.status_id",
+	"o.priority_id",
+	"o.impact",
+	"o.urgency",
+	"o.user_id",
+	"o.executor_id",
+	"o.duration",
+	"o.created_at",
+	"o.updated_at",
+	"o.deleted_at",
+	"o.completed_at",
+	"o.duplicate_of_id",
+	"o.first_response_time_seconds",
+	"o.resolution_time_seconds",
+	"o.is_first_contact_resolution",
+	"o.team_id",
+	"o.resolution_code_id",
+	"o.custom_fields",
+}
+
+// buildOrderSelectQuery — общий SELECT заявок; выборка ограничена организацией из контекста.
+func (r *OrderRepository) buildOrderSelectQuery(ctx context.Context) sq.SelectBuilder {
+	columns := append(append([]string(nil), orderSelectColumns...),
 		// JOIN для FIO
 		"creator.fio as creator_name",
 		"executor.fio as executor_name",
-	).
+		"team.name as team_name",
+		"pr.name as priority_name",
+		"pr.color as priority_color",
+		"pr.sort_order as priority_sort_order",
+		"pr.first_response_minutes as priority_first_response_minutes",
+	)
+	return sq.Select(columns...).
 		From(orderTable + " o").
 		LeftJoin("users creator ON o.user_id = creator.id").
 		LeftJoin("users executor ON o.executor_id = executor.id").
+		LeftJoin("teams team ON o.team_id = team.id").
+		LeftJoin("priorities pr ON pr.id = o.priority_id").
+		Where(tenantCondition(ctx, "o.tenant_id")).
+		PlaceholderFormat(sq.Dollar)
+}
+
+// buildOrderListQuery — SELECT для списка заявок: названия берутся из проекции order_list_view
+// одним JOIN вместо справочников и пользователей.
+func (r *OrderRepository) buildOrderListQuery(ctx context.Context) sq.SelectBuilder {
+	columns := append(append([]string(nil), orderSelectColumns...), orderListViewColumns...)
+	return sq.Select(columns...).
+		From(orderTable + " o").
+		LeftJoin("order_list_view v ON v.order_id = o.id").
+		Where(tenantCondition(ctx, "o.tenant_id")).
 		PlaceholderFormat(sq.Dollar)
 }
 
 func (r *OrderRepository) FindByID(ctx context.Context, orderID uint64) (*entities.Order, error) {
-	queryBuilder := r.buildOrderSelectQuery().Where(sq.Eq{"o.id": orderID, "o.deleted_at": nil})
+	queryBuilder := r.buildOrderSelectQuery(ctx).Where(sq.Eq{"o.id": orderID, "o.deleted_at": nil})
 
 	sqlStr, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -128,6 +202,27 @@ func (r *OrderRepository) FindByID(ctx context.Context, orderID uint64) (*entiti
 	return &order, nil
 }
 
+// OrderOverdueSQL — условие «заявка просрочена» для таблицы orders под псевдонимом alias: срок
+// прошёл, а статус не финальный (constants.FinalStatuses). Одно условие используют список заявок
+// (filter[overdue]), бот, личная статистика и дашборд.
+func OrderOverdueSQL(alias string) string {
+	return fmt.Sprintf(`(%[1]s.duration IS NOT NULL AND %[1]s.duration < NOW()
+		AND NOT EXISTS (SELECT 1 FROM statuses final_st WHERE final_st.id = %[1]s.status_id AND final_st.code IN (%[2]s)))`,
+		alias, dashboardQuotedStatusList(constants.FinalStatuses))
+}
+
+// parseOverdueFilter разбирает filter[overdue]: true — только просроченные, false — без них.
+func parseOverdueFilter(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		return parsed, err == nil
+	}
+	return false, false
+}
+
 func (r *OrderRepository) GetOrders(ctx context.Context, filter types.Filter, securityCondition sq.Sqlizer) ([]entities.Order, uint64, error) {
 	return r.getOrdersRefactored(ctx, filter, securityCondition)
 }
@@ -141,6 +236,7 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 	createdFrom, _ := filter.Filter["created_from"] // 🔥 ДОБАВЛЕНО
 	createdTo, _ := filter.Filter["created_to"]     // 🔥 ДОБАВЛЕНО
 	overdueVal, _ := filter.Filter["overdue"]
+	customFieldConditions := extractCustomFieldFilters(filter.Filter)
 
 	// 🔥 УДАЛЯЕМ ИХ ИЗ MAP
 	delete(filter.Filter, "duration_from")
@@ -151,6 +247,10 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 
 	// 🔥 ФУНКЦИЯ ПРИМЕНЕНИЯ СПЕЦИАЛЬНЫХ ФИЛЬТРОВ
 	applySpecials := func(b sq.SelectBuilder) sq.SelectBuilder {
+		for _, cond := range customFieldConditions {
+			b = b.Where(cond)
+		}
+
 		// Duration фильтры (срок выполнения)
 		if durationFrom != nil {
 			b = b.Where(sq.GtOrEq{"o.duration": durationFrom})
@@ -167,18 +267,11 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 			b = b.Where(sq.LtOrEq{"o.created_at": createdTo})
 		}
 
-		if overdueVal != nil {
-			isOverdue := false
-			switch v := overdueVal.(type) {
-			case bool:
-				isOverdue = v
-			case string:
-				isOverdue = v == "true"
-			}
+		if isOverdue, ok := parseOverdueFilter(overdueVal); ok {
 			if isOverdue {
-				b = b.Join("statuses s_ovr ON o.status_id = s_ovr.id").
-					Where("o.duration < NOW()").
-					Where("s_ovr.code NOT IN ('CLOSED', 'COMPLETED', 'REJECTED')")
+				b = b.Where(OrderOverdueSQL("o"))
+			} else {
+				b = b.Where("NOT " + OrderOverdueSQL("o"))
 			}
 		}
 		return b
@@ -190,6 +283,7 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 			return b.Where(sq.Or{
 				sq.ILike{"o.name": match},
 				sq.ILike{"o.address": match},
+				sq.ILike{"o.number": match},
 			})
 		}
 		return b
@@ -199,7 +293,9 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 
 	// COUNT
 	if filter.WithPagination {
-		countBuilder := psql.Select("count(o.id)").From(orderTable + " o").Where(sq.Eq{"o.deleted_at": nil})
+		countBuilder := psql.Select("count(o.id)").From(orderTable + " o").
+			Where(sq.Eq{"o.deleted_at": nil}).
+			Where(tenantCondition(ctx, "o.tenant_id"))
 
 		if securityCondition != nil {
 			countBuilder = countBuilder.Where(securityCondition)
@@ -218,7 +314,7 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 		if err != nil {
 			return nil, 0, fmt.Errorf("ошибка построения запроса подсчёта: %w", err)
 		}
-		if err := r.storage.QueryRow(ctx, sqlCount, argsCount...).Scan(&totalCount); err != nil {
+		if err := r.reader.QueryRow(ctx, sqlCount, argsCount...).Scan(&totalCount); err != nil {
 			return nil, 0, err
 		}
 		if totalCount == 0 {
@@ -227,7 +323,7 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 	}
 
 	// SELECT
-	selectBuilder := r.buildOrderSelectQuery().Where(sq.Eq{"o.deleted_at": nil})
+	selectBuilder := r.buildOrderListQuery(ctx).Where(sq.Eq{"o.deleted_at": nil})
 
 	if securityCondition != nil {
 		selectBuilder = selectBuilder.Where(securityCondition)
@@ -237,7 +333,7 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 	selectBuilder = applySpecials(selectBuilder)
 
 	if len(filter.Sort) == 0 {
-		selectBuilder = selectBuilder.OrderBy("o.created_at DESC")
+		selectBuilder = selectBuilder.OrderBy("o.created_at DESC", "o.id DESC")
 	}
 
 	selectBuilder = bd.ApplyListParams(selectBuilder, filter, orderMap)
@@ -247,16 +343,22 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 		return nil, 0, err
 	}
 
-	rows, err := r.storage.Query(ctx, sqlSelect, argsSelect...)
+	rows, err := r.reader.Query(ctx, sqlSelect, argsSelect...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	orders, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.Order])
+	listRows, err := pgx.CollectRows(rows, pgx.RowToStructByName[orderListRow])
 	if err != nil {
 		return nil, 0, err
 	}
+	r.fillMissingListView(ctx, listRows)
+
+	orders := make([]entities.Order, 0, len(listRows))
+	for i := range listRows {
+		orders = append(orders, listRows[i].toOrder())
+	}
 
 	if !filter.WithPagination {
 		totalCount = uint64(len(orders))
@@ -265,21 +367,108 @@ func (r *OrderRepository) getOrdersRefactored(ctx context.Context, filter types.
 	return orders, totalCount, nil
 }
 
+// CustomFieldFilterPrefix — фильтр по дополнительному полю: filter[cf.<code>]=значение,
+// несколько значений через запятую.
+const CustomFieldFilterPrefix = "cf."
+
+// extractCustomFieldFilters забирает из filter фильтры по дополнительным полям и превращает
+// их в условия; значение сравнивается как текст. code передаётся параметром, в SQL не попадает.
+func extractCustomFieldFilters(filter map[string]interface{}) []sq.Sqlizer {
+	var conditions []sq.Sqlizer
+	for key, raw := range filter {
+		code, ok := strings.CutPrefix(key, CustomFieldFilterPrefix)
+		if !ok {
+			continue
+		}
+		delete(filter, key)
+		if code == "" || raw == nil {
+			continue
+		}
+		values := strings.Split(fmt.Sprint(raw), ",")
+		if len(values) == 1 {
+			conditions = append(conditions, sq.Expr("o.custom_fields ->> ? = ?", code, values[0]))
+			continue
+		}
+		conditions = append(conditions, sq.Expr("o.custom_fields ->> ? = ANY(?)", code, values))
+	}
+	return conditions
+}
+
+// customFieldsValue — пустой объект вместо NULL: колонка custom_fields NOT NULL.
+func customFieldsValue(fields map[string]any) map[string]any {
+	if fields == nil {
+		return map[string]any{}
+	}
+	return fields
+}
+
+// FindExportNames одним запросом подтягивает названия справочников для пачки заявок.
+func (r *OrderRepository) FindExportNames(ctx context.Context, orderIDs []uint64) (map[uint64]OrderExportNames, error) {
+	result := make(map[uint64]OrderExportNames, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT o.id,
+			COALESCE(st.name, ''), COALESCE(pr.name, ''), COALESCE(ot.name, ''),
+			COALESCE(dep.name, ''), COALESCE(otd.name, ''), COALESCE(brn.name, ''), COALESCE(ofc.name, ''),
+			COALESCE(eqt.name, ''), COALESCE(eq.name, '')
+		FROM orders o
+		LEFT JOIN statuses st ON st.id = o.status_id
+		LEFT JOIN priorities pr ON pr.id = o.priority_id
+		LEFT JOIN order_types ot ON ot.id = o.order_type_id
+		LEFT JOIN departments dep ON dep.id = o.department_id
+		LEFT JOIN otdels otd ON otd.id = o.otdel_id
+		LEFT JOIN branches brn ON brn.id = o.branch_id
+		LEFT JOIN offices ofc ON ofc.id = o.office_id
+		LEFT JOIN equipment_types eqt ON eqt.id = o.equipment_type_id
+		LEFT JOIN equipments eq ON eq.id = o.equipment_id
+		WHERE o.id = ANY($1)
+	`
+	rows, err := r.reader.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var n OrderExportNames
+		if err := rows.Scan(&id, &n.Status, &n.Priority, &n.OrderType, &n.Department, &n.Otdel, &n.Branch, &n.Office, &n.EquipmentType, &n.Equipment); err != nil {
+			return nil, err
+		}
+		result[id] = n
+	}
+	return result, rows.Err()
+}
+
 func (r *OrderRepository) Create(ctx context.Context, tx pgx.Tx, order *entities.Order) (uint64, error) {
+	// Организация берётся из контекста, а в фоновых задачах без неё — у автора заявки
+	tenantID, number, err := nextOrderNumber(ctx, tx, order)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось выдать номер заявке: %w", err)
+	}
+
 	query := `INSERT INTO orders 
 		(name, address, department_id, otdel_id, branch_id, office_id, 
-		 equipment_id, equipment_type_id, order_type_id, status_id, priority_id, 
-		 user_id, executor_id, duration, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW(), NOW())
+		 equipment_id, equipment_type_id, order_type_id, status_id, priority_id, impact, urgency,
+		 user_id, executor_id, team_id, duration, custom_fields, tenant_id, number, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, NOW(), NOW())
 		RETURNING id`
 
-	err := tx.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		order.Name, order.Address, order.DepartmentID, order.OtdelID,
 		order.BranchID, order.OfficeID, order.EquipmentID, order.EquipmentTypeID,
-		order.OrderTypeID, order.StatusID, order.PriorityID, order.CreatorID,
-		order.ExecutorID, order.Duration,
+		order.OrderTypeID, order.StatusID, order.PriorityID, order.Impact, order.Urgency, order.CreatorID,
+		order.ExecutorID, order.TeamID, order.Duration, customFieldsValue(order.CustomFields),
+		tenantID, number,
 	).Scan(&order.ID)
-	return order.ID, err
+	if err != nil {
+		return 0, err
+	}
+	order.Number = number
+	return order.ID, nil
 }
 
 func (r *OrderRepository) Update(ctx context.Context, tx pgx.Tx, order *entities.Order) error {
@@ -290,7 +479,12 @@ func (r *OrderRepository) Update(ctx context.Context, tx pgx.Tx, order *entities
 		Set("duration", order.Duration).
 		Set("status_id", order.StatusID).
 		Set("priority_id", order.PriorityID).
+		Set("impact", order.Impact).
+		Set("urgency", order.Urgency).
 		Set("executor_id", order.ExecutorID).
+		Set("team_id", order.TeamID).
+		Set("resolution_code_id", order.ResolutionCodeID).
+		Set("custom_fields", customFieldsValue(order.CustomFields)).
 		Set("department_id", order.DepartmentID).
 		Set("otdel_id", order.OtdelID).
 		Set("branch_id", order.BranchID).
@@ -313,6 +507,162 @@ func (r *OrderRepository) Update(ctx context.Context, tx pgx.Tx, order *entities
 	return err
 }
 
+// FindRecentByEquipment возвращает заявки по тому же оборудованию, созданные не раньше since,
+// новые первыми. Заявки, уже закрытые как дубликаты, не попадают в выборку.
+func (r *OrderRepository) FindRecentByEquipment(ctx context.Context, equipmentID uint64, since time.Time, excludeID uint64, limit uint64, securityCondition sq.Sqlizer) ([]entities.Order, error) {
+	b := r.buildOrderSelectQuery(ctx).
+		Where(sq.Eq{"o.equipment_id": equipmentID, "o.deleted_at": nil, "o.duplicate_of_id": nil}).
+		Where(sq.GtOrEq{"o.created_at": since}).
+		Where(sq.NotEq{"o.id": excludeID}).
+		OrderBy("o.created_at DESC", "o.id DESC").
+		Limit(limit)
+	if securityCondition != nil {
+		b = b.Where(securityCondition)
+	}
+
+	sqlStr, args, err := b.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("FindRecentByEquipment SQL error: %w", err)
+	}
+	rows, err := r.storage.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Order])
+}
+
+// MarkDuplicateInTx отмечает заявку как дубликат duplicateOfID.
+func (r *OrderRepository) MarkDuplicateInTx(ctx context.Context, tx pgx.Tx, orderID, duplicateOfID uint64) error {
+	query := `UPDATE orders SET duplicate_of_id = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL`
+	cmd, err := tx.Exec(ctx, query, duplicateOfID, orderID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepository) ClaimTeamOrderInTx(ctx context.Context, tx pgx.Tx, orderID, userID uint64) (bool, error) {
+	// Условие executor_id IS NULL внутри UPDATE не даёт двум участникам забрать заявку одновременно
+	query := `
+		UPDATE orders o SET executor_id = $2, updated_at = NOW()
+		WHERE o.id = $1
+		  AND o.deleted_at IS NULL
+		  AND o.executor_id IS NULL
+		  AND EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = o.team_id AND tm.user_id = $2)`
+	cmd, err := tx.Exec(ctx, query, orderID, userID)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (r *OrderRepository) ClaimNextOrderInTx(ctx context.Context, tx pgx.Tx, userID, departmentID uint64) (*entities.Order, error) {
+	// SKIP LOCKED: параллельные запросы не ждут друг друга и не получают одну и ту же заявку
+	next := sq.Select("o.id").
+		From("orders o").
+		Join("statuses st ON st.id = o.status_id").
+		Where("o.deleted_at IS NULL").
+		Where("o.executor_id IS NULL").
+		Where(sq.Eq{"o.department_id": departmentID}).
+		// Финальные статусы берутся из того же списка, что и constants.IsFinalStatus, и идут параметром
+		Where(sq.NotEq{"st.code": constants.FinalStatuses}).
+		Where("(o.team_id IS NULL OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = o.team_id AND tm.user_id = ?))", userID).
+		Where(`NOT EXISTS (SELECT 1 FROM order_type_skills ots
+			WHERE ots.order_type_id = o.order_type_id
+			  AND ots.skill_id NOT IN (SELECT us.skill_id FROM user_skills us WHERE us.user_id = ?))`, userID).
+		Where(tenantCondition(ctx, "o.tenant_id")).
+		OrderBy("o.created_at", "o.id").
+		Limit(1).
+		Suffix("FOR UPDATE OF o SKIP LOCKED")
+
+	nextSQL, args, err := next.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ClaimNextOrderInTx SQL error: %w", err)
+	}
+	sqlStr, args, err := sq.Update("orders").
+		Set("executor_id", userID).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Expr("id = ("+nextSQL+")", args...)).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ClaimNextOrderInTx SQL error: %w", err)
+	}
+
+	var orderID uint64
+	if err := tx.QueryRow(ctx, sqlStr, args...).Scan(&orderID); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Читаем той же транзакцией: снаружи исполнитель появится только после коммита
+	sqlStr, args, err = r.buildOrderSelectQuery(ctx).Where(sq.Eq{"o.id": orderID}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ClaimNextOrderInTx SQL error: %w", err)
+	}
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	order, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.Order])
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) IsTeamMember(ctx context.Context, teamID, userID uint64) (bool, error) {
+	var exists bool
+	err := r.storage.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM team_members WHERE team_id = $1 AND user_id = $2)`,
+		teamID, userID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *OrderRepository) FindExecutorCandidates(ctx context.Context, structure sq.Sqlizer, orderTypeID uint64, limit uint64) ([]entities.ExecutorCandidate, error) {
+	b := sq.Select("u.id AS user_id", "u.fio", "p.name AS position_name").
+		Column(`(SELECT COUNT(*) FROM orders o JOIN statuses os ON os.id = o.status_id
+			WHERE o.executor_id = u.id AND o.deleted_at IS NULL
+			  AND os.code NOT IN ('CLOSED', 'COMPLETED', 'REJECTED', 'DUPLICATE'))::int AS open_orders`).
+		Column(`(SELECT AVG(o.resolution_time_seconds)::float8 FROM orders o
+			WHERE o.executor_id = u.id AND o.deleted_at IS NULL AND o.resolution_time_seconds > 0
+			  AND o.completed_at > NOW() - INTERVAL '90 days') AS avg_resolution_seconds`).
+		Column(sq.Expr(`(SELECT COUNT(*) FROM order_type_skills ots
+			JOIN user_skills us ON us.skill_id = ots.skill_id AND us.user_id = u.id
+			WHERE ots.order_type_id = ?)::int AS matched_skills`, orderTypeID)).
+		Column(sq.Expr(`(SELECT COUNT(*) FROM order_type_skills WHERE order_type_id = ?)::int AS required_skills`, orderTypeID)).
+		From("users u").
+		Join("statuses s ON s.id = u.status_id").
+		LeftJoin("positions p ON p.id = u.position_id").
+		Where("u.deleted_at IS NULL").
+		Where("UPPER(s.code) = 'ACTIVE'").
+		Where(tenantCondition(ctx, "u.tenant_id")).
+		Where(structure).
+		OrderBy("u.id").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar)
+
+	sqlStr, args, err := b.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("FindExecutorCandidates SQL error: %w", err)
+	}
+	rows, err := r.storage.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.ExecutorCandidate])
+}
+
 func (r *OrderRepository) DeleteOrder(ctx context.Context, orderID uint64) error {
 	query := `UPDATE orders SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 	cmd, err := r.storage.Exec(ctx, query, orderID)
@@ -331,7 +681,7 @@ func (r *OrderRepository) GetUserOrderStats(ctx context.Context, userID uint64,
 			COUNT(CASE WHEN s.code IN ('IN_PROGRESS', 'CLARIFICATION', 'REFINEMENT') THEN 1 END),
 			COUNT(CASE WHEN s.code = 'COMPLETED' THEN 1 END),
 			COUNT(CASE WHEN s.code = 'CLOSED' THEN 1 END),
-			COUNT(CASE WHEN o.duration IS NOT NULL AND o.duration < NOW() AND s.code NOT IN ('COMPLETED', 'CLOSED', 'REJECTED') THEN 1 END),
+			COUNT(CASE WHEN ` + OrderOverdueSQL("o") + ` THEN 1 END),
 			COALESCE(AVG(CASE WHEN s.code IN ('COMPLETED', 'CLOSED') AND o.resolution_time_seconds > 0 THEN o.resolution_time_seconds END), 0)
 		FROM orders o
 		JOIN statuses s ON o.status_id = s.id
@@ -339,8 +689,30 @@ func (r *OrderRepository) GetUserOrderStats(ctx context.Context, userID uint64,
 		  AND o.deleted_at IS NULL
 		  AND o.created_at >= $2
 	`
+	return r.scanOrderStats(r.storage.QueryRow(ctx, query, userID, fromDate))
+}
+
+func (r *OrderRepository) GetUnitOrderStats(ctx context.Context, departmentID uint64, otdelID *uint64, fromDate time.Time) (*types.UserOrderStats, error) {
+	query := `
+		SELECT 
+			COUNT(CASE WHEN s.code IN ('IN_PROGRESS', 'CLARIFICATION', 'REFINEMENT') THEN 1 END),
+			COUNT(CASE WHEN s.code = 'COMPLETED' THEN 1 END),
+			COUNT(CASE WHEN s.code = 'CLOSED' THEN 1 END),
+			COUNT(CASE WHEN ` + OrderOverdueSQL("o") + ` THEN 1 END),
+			COALESCE(AVG(CASE WHEN s.code IN ('COMPLETED', 'CLOSED') AND o.resolution_time_seconds > 0 THEN o.resolution_time_seconds END), 0)
+		FROM orders o
+		JOIN statuses s ON o.status_id = s.id
+		WHERE o.department_id = $1
+		  AND ($2::bigint IS NULL OR o.otdel_id = $2)
+		  AND o.deleted_at IS NULL
+		  AND o.created_at >= $3
+	`
+	return r.scanOrderStats(r.storage.QueryRow(ctx, query, departmentID, otdelID, fromDate))
+}
+
+func (r *OrderRepository) scanOrderStats(row pgx.Row) (*types.UserOrderStats, error) {
 	var stats types.UserOrderStats
-	err := r.storage.QueryRow(ctx, query, userID, fromDate).Scan(
+	err := row.Scan(
 		&stats.InProgressCount,
 		&stats.CompletedCount,
 		&stats.ClosedCount,
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +13,7 @@ import (
 	"go.uber.org/zap"
 
 	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
 )
 
@@ -30,6 +33,8 @@ type OrderHistoryItem struct {
 	CreatorFio    sql.NullString       `json:"creator_fio"`
 	DelegatorFio  sql.NullString       `json:"delegator_fio"`
 	ExecutorFio   sql.NullString       `json:"executor_fio"`
+	PrevHash      sql.NullString       `json:"prev_hash"`
+	Hash          sql.NullString       `json:"hash"`
 }
 
 // OrderHistoryRepositoryInterface определяет методы для работы с историей заявок
@@ -38,8 +43,45 @@ type OrderHistoryRepositoryInterface interface {
 	CreateInTx(ctx context.Context, tx pgx.Tx, item *OrderHistoryItem) error
 	IsUserParticipant(ctx context.Context, orderID, userID uint64) (bool, error)
 	GetOrderHistory(ctx context.Context, orderID uint64, filter types.Filter) ([]OrderHistoryItem, error)
+	FindForReplay(ctx context.Context, filter HistoryReplayFilter, afterID uint64, limit int) ([]OrderHistoryItem, error)
+	FindPageByOrderID(ctx context.Context, orderID uint64, filter HistoryPageFilter) ([]OrderHistoryItem, uint64, error)
+	FindChainByOrderID(ctx context.Context, orderID uint64) ([]OrderHistoryItem, error)
+	FindChainHead(ctx context.Context, orderID uint64) (sql.NullString, error)
+	// VerifyChainInTx блокирует заявку до конца транзакции и проверяет её цепочку против
+	// orders.history_hash. Вызывается до намеренной правки записей истории.
+	VerifyChainInTx(ctx context.Context, tx pgx.Tx, orderID uint64) (*HistoryChainCheck, error)
+	ResealChainInTx(ctx context.Context, tx pgx.Tx, orderID uint64, verified *HistoryChainCheck) (string, error)
+	FindLastCommentsByOrderIDs(ctx context.Context, orderIDs []uint64, perOrder int) (map[uint64][]OrderHistoryItem, error)
 }
 
+// HistoryReplayFilter — какие записи истории переиграть: по заявке, интервалу [From, To) и типам событий.
+type HistoryReplayFilter struct {
+	OrderID    *uint64
+	From       *time.Time
+	To         *time.Time
+	EventTypes []string
+}
+
+// HistoryPageFilter — страница истории одной заявки с фильтром по типам событий и интервалу [From, To).
+type HistoryPageFilter struct {
+	EventTypes []string
+	From       *time.Time
+	To         *time.Time
+	Desc       bool
+	Limit      int
+	Offset     int
+}
+
+// OrderHistorySinceOrderSQL — нижняя граница created_at записей истории заявки. orders.history_since
+// не позже самой ранней записи истории (его поддерживает триггер на order_history), поэтому условие
+// ничего не отсекает, а PostgreSQL с ним не читает секции за годы до заявки.
+func OrderHistorySinceOrderSQL(historyAlias, orderAlias string) string {
+	return fmt.Sprintf("%s.created_at >= %s.history_since", historyAlias, orderAlias)
+}
+
+// orderHistorySinceOrderParam — то же условие для истории заявки из параметра $1.
+const orderHistorySinceOrderParam = `h.created_at >= (SELECT history_since FROM orders WHERE id = $1)`
+
 // OrderHistoryRepository реализует доступ к таблице order_history
 type OrderHistoryRepository struct {
 	storage *pgxpool.Pool
@@ -55,16 +97,36 @@ func (r *OrderHistoryRepository) GetOrderHistory(ctx context.Context, orderID ui
 	return r.FindByOrderID(ctx, orderID, uint64(filter.Limit), uint64(filter.Offset))
 }
 
-// CreateInTx создает запись в истории в рамках транзакции
+// CreateInTx создает запись в истории в рамках транзакции.
+// Запись сцепляется с предыдущей записью заявки хэшем; строка заявки блокируется до конца
+// транзакции, чтобы параллельные изменения не построили две ветки цепочки.
 func (r *OrderHistoryRepository) CreateInTx(ctx context.Context, tx pgx.Tx, item *OrderHistoryItem) error {
+	var prevHash sql.NullString
+	err := tx.QueryRow(ctx, `SELECT history_hash FROM orders WHERE id = $1 FOR UPDATE`, item.OrderID).Scan(&prevHash)
+	if err != nil {
+		r.logger.Error("Ошибка при чтении хэша истории заявки",
+			zap.Uint64("orderID", item.OrderID),
+			zap.Error(err))
+		return err
+	}
+
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	// PostgreSQL хранит время с точностью до микросекунд — хэшируем ровно то, что будет сохранено.
+	item.CreatedAt = item.CreatedAt.Truncate(time.Microsecond)
+	item.PrevHash = prevHash
+	item.Hash = sql.NullString{String: ComputeHistoryHash(prevHash.String, item), Valid: true}
+
 	query := `
 		INSERT INTO order_history (
 			order_id, user_id, event_type, old_value, new_value, comment, attachment_id,
-			created_at, tx_id, creator_fio, delegator_fio, executor_fio
+			created_at, tx_id, creator_fio, delegator_fio, executor_fio, prev_hash, hash
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id
 	`
-	_, err := tx.Exec(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		item.OrderID,
 		item.UserID,
 		item.EventType,
@@ -77,7 +139,9 @@ func (r *OrderHistoryRepository) CreateInTx(ctx context.Context, tx pgx.Tx, item
 		item.CreatorFio,
 		item.DelegatorFio,
 		item.ExecutorFio,
-	)
+		item.PrevHash,
+		item.Hash,
+	).Scan(&item.ID)
 	if err != nil {
 		r.logger.Error("Ошибка при создании записи в истории",
 			zap.Uint64("orderID", item.OrderID),
@@ -85,6 +149,13 @@ func (r *OrderHistoryRepository) CreateInTx(ctx context.Context, tx pgx.Tx, item
 			zap.Error(err))
 		return err
 	}
+
+	if _, err := tx.Exec(ctx, `UPDATE orders SET history_hash = $1 WHERE id = $2`, item.Hash, item.OrderID); err != nil {
+		r.logger.Error("Ошибка при обновлении хэша истории заявки",
+			zap.Uint64("orderID", item.OrderID),
+			zap.Error(err))
+		return err
+	}
 	r.logger.Debug("Запись в истории создана",
 		zap.Uint64("orderID", item.OrderID),
 		zap.String("eventType", item.EventType),
@@ -100,11 +171,11 @@ func (r *OrderHistoryRepository) FindByOrderID(ctx context.Context, orderID uint
 			s.name AS new_status_name,
 			h.creator_fio, h.delegator_fio, h.executor_fio,
 			a.file_name, a.file_path, a.file_type, a.file_size,
-			h.tx_id
+			h.tx_id, h.prev_hash, h.hash
 		FROM order_history h
 		LEFT JOIN statuses s ON h.new_value = s.id::text AND h.event_type = 'STATUS_CHANGE'
 		LEFT JOIN attachments a ON h.attachment_id = a.id
-		WHERE h.order_id = $1
+		WHERE h.order_id = $1 AND ` + orderHistorySinceOrderParam + `
 		ORDER BY h.created_at ASC
 		LIMIT $2 OFFSET $3
 	`
@@ -117,9 +188,265 @@ func (r *OrderHistoryRepository) FindByOrderID(ctx context.Context, orderID uint
 			zap.Error(err))
 		return nil, err
 	}
+	history, err := scanHistoryItems(rows, int(limit))
+	if err != nil {
+		r.logger.Error("Ошибка при чтении истории заявки",
+			zap.Uint64("orderID", orderID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	r.logger.Debug("История заявки получена",
+		zap.Uint64("orderID", orderID),
+		zap.Int("count", len(history)))
+	return history, nil
+}
+
+// FindLastCommentsByOrderIDs возвращает до perOrder последних комментариев каждой заявки одним запросом,
+// новые первыми.
+func (r *OrderHistoryRepository) FindLastCommentsByOrderIDs(ctx context.Context, orderIDs []uint64, perOrder int) (map[uint64][]OrderHistoryItem, error) {
+	result := make(map[uint64][]OrderHistoryItem, len(orderIDs))
+	if len(orderIDs) == 0 || perOrder <= 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT
+			h.id, h.order_id, h.user_id, h.event_type, h.old_value, h.new_value, h.comment, h.created_at, h.attachment_id,
+			NULL::text AS new_status_name,
+			h.creator_fio, h.delegator_fio, h.executor_fio,
+			a.file_name, a.file_path, a.file_type, a.file_size,
+			h.tx_id, h.prev_hash, h.hash
+		FROM (
+			SELECT h.*, ROW_NUMBER() OVER (PARTITION BY h.order_id ORDER BY h.created_at DESC, h.id DESC) AS rn
+			FROM order_history h
+			WHERE h.order_id = ANY($1) AND h.event_type = 'COMMENT'
+				AND h.created_at >= (SELECT MIN(history_since) FROM orders WHERE id = ANY($1))
+		) h
+		LEFT JOIN attachments a ON h.attachment_id = a.id
+		WHERE h.rn <= $2
+		ORDER BY h.order_id, h.created_at DESC, h.id DESC
+	`
+	rows, err := r.storage.Query(ctx, query, orderIDs, perOrder)
+	if err != nil {
+		r.logger.Error("Ошибка при получении последних комментариев", zap.Int("orders", len(orderIDs)), zap.Error(err))
+		return nil, err
+	}
+	items, err := scanHistoryItems(rows, len(orderIDs)*perOrder)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		result[item.OrderID] = append(result[item.OrderID], item)
+	}
+	return result, nil
+}
+
+// FindPageByOrderID возвращает страницу истории заявки и общее число записей под фильтром.
+func (r *OrderHistoryRepository) FindPageByOrderID(ctx context.Context, orderID uint64, filter HistoryPageFilter) ([]OrderHistoryItem, uint64, error) {
+	eventTypes := filter.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	where := `
+		WHERE h.order_id = $1 AND ` + orderHistorySinceOrderParam + `
+			AND (cardinality($2::text[]) = 0 OR h.event_type = ANY($2))
+			AND ($3::timestamptz IS NULL OR h.created_at >= $3)
+			AND ($4::timestamptz IS NULL OR h.created_at < $4)
+	`
+
+	var total uint64
+	err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM order_history h`+where,
+		orderID, eventTypes, filter.From, filter.To).Scan(&total)
+	if err != nil {
+		r.logger.Error("Ошибка при подсчёте истории заявки", zap.Uint64("orderID", orderID), zap.Error(err))
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []OrderHistoryItem{}, 0, nil
+	}
+
+	order := "ASC"
+	if filter.Desc {
+		order = "DESC"
+	}
+	query := `
+		SELECT
+			h.id, h.order_id, h.user_id, h.event_type, h.old_value, h.new_value, h.comment, h.created_at, h.attachment_id,
+			s.name AS new_status_name,
+			h.creator_fio, h.delegator_fio, h.executor_fio,
+			a.file_name, a.file_path, a.file_type, a.file_size,
+			h.tx_id, h.prev_hash, h.hash
+		FROM order_history h
+		LEFT JOIN statuses s ON h.new_value = s.id::text AND h.event_type = 'STATUS_CHANGE'
+		LEFT JOIN attachments a ON h.attachment_id = a.id
+	` + where + `
+		ORDER BY h.created_at ` + order + `, h.id ` + order + `
+		LIMIT $5 OFFSET $6
+	`
+	rows, err := r.storage.Query(ctx, query, orderID, eventTypes, filter.From, filter.To, filter.Limit, filter.Offset)
+	if err != nil {
+		r.logger.Error("Ошибка при получении страницы истории заявки", zap.Uint64("orderID", orderID), zap.Error(err))
+		return nil, 0, err
+	}
+	items, err := scanHistoryItems(rows, filter.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// FindForReplay возвращает записи истории по возрастанию id начиная после afterID —
+// для повторной публикации событий в шину. Пустые фильтры не ограничивают выборку.
+func (r *OrderHistoryRepository) FindForReplay(ctx context.Context, filter HistoryReplayFilter, afterID uint64, limit int) ([]OrderHistoryItem, error) {
+	query := `
+		SELECT
+			h.id, h.order_id, h.user_id, h.event_type, h.old_value, h.new_value, h.comment, h.created_at, h.attachment_id,
+			s.name AS new_status_name,
+			h.creator_fio, h.delegator_fio, h.executor_fio,
+			a.file_name, a.file_path, a.file_type, a.file_size,
+			h.tx_id, h.prev_hash, h.hash
+		FROM order_history h
+		LEFT JOIN statuses s ON h.new_value = s.id::text AND h.event_type = 'STATUS_CHANGE'
+		LEFT JOIN attachments a ON h.attachment_id = a.id
+		WHERE h.id > $1
+			AND ($2::bigint IS NULL OR h.order_id = $2)
+			AND ($3::timestamptz IS NULL OR h.created_at >= $3)
+			AND ($4::timestamptz IS NULL OR h.created_at < $4)
+			AND (cardinality($5::text[]) = 0 OR h.event_type = ANY($5))
+		ORDER BY h.id ASC
+		LIMIT $6
+	`
+	eventTypes := filter.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	rows, err := r.storage.Query(ctx, query, afterID, filter.OrderID, filter.From, filter.To, eventTypes, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanHistoryItems(rows, limit)
+}
+
+// FindChainByOrderID возвращает всю историю заявки в порядке вставки — для проверки цепочки хэшей.
+// Нижней границы по created_at здесь нет: проверка должна видеть каждую запись.
+func (r *OrderHistoryRepository) FindChainByOrderID(ctx context.Context, orderID uint64) ([]OrderHistoryItem, error) {
+	query := `
+		SELECT
+			h.id, h.order_id, h.user_id, h.event_type, h.old_value, h.new_value, h.comment, h.created_at, h.attachment_id,
+			s.name AS new_status_name,
+			h.creator_fio, h.delegator_fio, h.executor_fio,
+			a.file_name, a.file_path, a.file_type, a.file_size,
+			h.tx_id, h.prev_hash, h.hash
+		FROM order_history h
+		LEFT JOIN statuses s ON h.new_value = s.id::text AND h.event_type = 'STATUS_CHANGE'
+		LEFT JOIN attachments a ON h.attachment_id = a.id
+		WHERE h.order_id = $1
+		ORDER BY h.id ASC
+	`
+	rows, err := r.storage.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanHistoryItems(rows, 0)
+}
+
+// FindChainHead возвращает хэш последней записи истории, сохранённый в заявке.
+func (r *OrderHistoryRepository) FindChainHead(ctx context.Context, orderID uint64) (sql.NullString, error) {
+	var head sql.NullString
+	err := r.storage.QueryRow(ctx, `SELECT history_hash FROM orders WHERE id = $1`, orderID).Scan(&head)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return head, apperrors.ErrNotFound
+	}
+	return head, err
+}
+
+// ErrHistoryChainBroken — цепочку не проверили перед правкой или она уже была нарушена.
+// Пересчёт такой цепочки подписал бы чужую правку заново, и её стало бы не найти.
+var ErrHistoryChainBroken = errors.New("цепочка истории заявки нарушена, пересчёт запрещён")
+
+func (r *OrderHistoryRepository) VerifyChainInTx(ctx context.Context, tx pgx.Tx, orderID uint64) (*HistoryChainCheck, error) {
+	var head sql.NullString
+	if err := tx.QueryRow(ctx, `SELECT history_hash FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&head); err != nil {
+		return nil, err
+	}
+	items, err := r.findChainInTx(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	check := CheckHistoryChain(items, head.String)
+	return &check, nil
+}
+
+// ResealChainInTx пересчитывает цепочку хэшей заявки после намеренной правки записей истории
+// (обезличивание, хранение) и возвращает прежний хэш заявки, чтобы правку можно было записать в аудит.
+// verified — результат VerifyChainInTx той же транзакции до правки; без целой цепочки
+// возвращается ErrHistoryChainBroken. Записи без хэша в начале истории остаются как есть.
+func (r *OrderHistoryRepository) ResealChainInTx(ctx context.Context, tx pgx.Tx, orderID uint64, verified *HistoryChainCheck) (string, error) {
+	if verified == nil || !verified.Valid {
+		return "", ErrHistoryChainBroken
+	}
+	var oldHead sql.NullString
+	if err := tx.QueryRow(ctx, `SELECT history_hash FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&oldHead); err != nil {
+		return "", err
+	}
+	// Заявка заблокирована с момента проверки, поэтому хэш мог измениться только в этой транзакции.
+	if oldHead.String != verified.Head {
+		return "", ErrHistoryChainBroken
+	}
+
+	items, err := r.findChainInTx(ctx, tx, orderID)
+	if err != nil {
+		return "", err
+	}
+
+	prev := ""
+	for i := range items {
+		item := &items[i]
+		if !item.Hash.Valid {
+			continue
+		}
+		hash := ComputeHistoryHash(prev, item)
+		if item.PrevHash.String != prev || item.Hash.String != hash {
+			if _, err := tx.Exec(ctx, `UPDATE order_history SET prev_hash = $1, hash = $2 WHERE id = $3`,
+				sql.NullString{String: prev, Valid: prev != ""}, hash, item.ID); err != nil {
+				return "", err
+			}
+		}
+		prev = hash
+	}
+
+	if prev != "" && prev != oldHead.String {
+		if _, err := tx.Exec(ctx, `UPDATE orders SET history_hash = $1 WHERE id = $2`, prev, orderID); err != nil {
+			return "", err
+		}
+	}
+	return oldHead.String, nil
+}
+
+func (r *OrderHistoryRepository) findChainInTx(ctx context.Context, tx pgx.Tx, orderID uint64) ([]OrderHistoryItem, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, order_id, user_id, event_type, old_value, new_value, comment, attachment_id,
+		       created_at, tx_id, creator_fio, delegator_fio, executor_fio, prev_hash, hash
+		FROM order_history
+		WHERE order_id = $1
+		ORDER BY id ASC`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (OrderHistoryItem, error) {
+		var item OrderHistoryItem
+		err := row.Scan(&item.ID, &item.OrderID, &item.UserID, &item.EventType, &item.OldValue, &item.NewValue,
+			&item.Comment, &item.AttachmentID, &item.CreatedAt, &item.TxID, &item.CreatorFio, &item.DelegatorFio,
+			&item.ExecutorFio, &item.PrevHash, &item.Hash)
+		return item, err
+	})
+}
+
+func scanHistoryItems(rows pgx.Rows, capacity int) ([]OrderHistoryItem, error) {
 	defer rows.Close()
 
-	history := make([]OrderHistoryItem, 0, limit)
+	history := make([]OrderHistoryItem, 0, capacity)
 	for rows.Next() {
 		var item OrderHistoryItem
 		var fileName, filePath, fileType sql.NullString
@@ -144,11 +471,10 @@ func (r *OrderHistoryRepository) FindByOrderID(ctx context.Context, orderID uint
 			&fileType,
 			&fileSize,
 			&item.TxID,
+			&item.PrevHash,
+			&item.Hash,
 		)
 		if err != nil {
-			r.logger.Error("Ошибка при сканировании строки истории",
-				zap.Uint64("orderID", orderID),
-				zap.Error(err))
 			return nil, err
 		}
 
@@ -160,29 +486,16 @@ func (r *OrderHistoryRepository) FindByOrderID(ctx context.Context, orderID uint
 				FileType: fileType.String,
 				FileSize: fileSize.Int64,
 			}
-		} else {
-			item.Attachment = nil
 		}
 
 		history = append(history, item)
 	}
-
-	if err := rows.Err(); err != nil {
-		r.logger.Error("Ошибка при итерации строк истории",
-			zap.Uint64("orderID", orderID),
-			zap.Error(err))
-		return nil, err
-	}
-
-	r.logger.Debug("История заявки получена",
-		zap.Uint64("orderID", orderID),
-		zap.Int("count", len(history)))
-	return history, nil
+	return history, rows.Err()
 }
 
 // IsUserParticipant проверяет, участвовал ли пользователь в истории заявки
 func (r *OrderHistoryRepository) IsUserParticipant(ctx context.Context, orderID, userID uint64) (bool, error) {
-	query := `SELECT EXISTS (SELECT 1 FROM order_history WHERE order_id = $1 AND user_id = $2)`
+	query := `SELECT EXISTS (SELECT 1 FROM order_history h WHERE h.order_id = $1 AND h.user_id = $2 AND ` + orderHistorySinceOrderParam + `)`
 	var exists bool
 	err := r.storage.QueryRow(ctx, query, orderID, userID).Scan(&exists)
 	if err != nil {
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// historyHashPayload — канонический вид записи истории для хэширования.
+// Порядок и набор полей менять нельзя: иначе все существующие цепочки перестанут сходиться.
+type historyHashPayload struct {
+	OrderID      uint64  `json:"order_id"`
+	UserID       uint64  `json:"user_id"`
+	EventType    string  `json:"event_type"`
+	OldValue     *string `json:"old_value"`
+	NewValue     *string `json:"new_value"`
+	Comment      *string `json:"comment"`
+	AttachmentID *int64  `json:"attachment_id"`
+	CreatedAt    string  `json:"created_at"`
+	TxID         *string `json:"tx_id"`
+	CreatorFio   *string `json:"creator_fio"`
+	DelegatorFio *string `json:"delegator_fio"`
+	ExecutorFio  *string `json:"executor_fio"`
+}
+
+// ComputeHistoryHash считает хэш записи истории с учётом хэша предыдущей записи заявки.
+// created_at берётся с точностью до микросекунд — так, как его хранит PostgreSQL.
+func ComputeHistoryHash(prevHash string, item *OrderHistoryItem) string {
+	payload := historyHashPayload{
+		OrderID:   item.OrderID,
+		UserID:    item.UserID,
+		EventType: item.EventType,
+		CreatedAt: item.CreatedAt.Truncate(time.Microsecond).UTC().Format(time.RFC3339Nano),
+	}
+	if item.OldValue.Valid {
+		payload.OldValue = &item.OldValue.String
+	}
+	if item.NewValue.Valid {
+		payload.NewValue = &item.NewValue.String
+	}
+	if item.Comment.Valid {
+		payload.Comment = &item.Comment.String
+	}
+	if item.AttachmentID.Valid {
+		payload.AttachmentID = &item.AttachmentID.Int64
+	}
+	if item.TxID != nil {
+		txID := item.TxID.String()
+		payload.TxID = &txID
+	}
+	if item.CreatorFio.Valid {
+		payload.CreatorFio = &item.CreatorFio.String
+	}
+	if item.DelegatorFio.Valid {
+		payload.DelegatorFio = &item.DelegatorFio.String
+	}
+	if item.ExecutorFio.Valid {
+		payload.ExecutorFio = &item.ExecutorFio.String
+	}
+
+	// Ошибки быть не может: в структуре только строки и числа.
+	raw, _ := json.Marshal(payload)
+	sum := sha256.Sum256(append([]byte(prevHash+"|"), raw...))
+	return hex.EncodeToString(sum[:])
+}
+
+// HistoryChainCheck — результат проверки цепочки хэшей истории одной заявки.
+// Legacy — записи, созданные до включения цепочки: их содержимое проверить нельзя.
+type HistoryChainCheck struct {
+	Head          string
+	Valid         bool
+	Checked       int
+	Legacy        int
+	FirstBrokenID uint64
+	Reason        string
+}
+
+// CheckHistoryChain проверяет записи в порядке вставки против хэша head, сохранённого в заявке.
+// Записи без хэша допустимы только в начале истории — это данные, созданные до включения цепочки.
+func CheckHistoryChain(items []OrderHistoryItem, head string) HistoryChainCheck {
+	check := HistoryChainCheck{Head: head, Valid: true}
+	broken := func(id uint64, reason string) HistoryChainCheck {
+		check.Valid = false
+		check.FirstBrokenID = id
+		check.Reason = reason
+		return check
+	}
+
+	prev := ""
+	for i := range items {
+		item := &items[i]
+		if !item.Hash.Valid {
+			if check.Checked > 0 {
+				return broken(item.ID, "У записи удалён хэш")
+			}
+			check.Legacy++
+			continue
+		}
+		if item.PrevHash.String != prev {
+			return broken(item.ID, "Запись не ссылается на предыдущую: записи удалены или вставлены задним числом")
+		}
+		if ComputeHistoryHash(prev, item) != item.Hash.String {
+			return broken(item.ID, "Содержимое записи изменено")
+		}
+		prev = item.Hash.String
+		check.Checked++
+	}
+
+	if prev != head {
+		var lastID uint64
+		if len(items) > 0 {
+			lastID = items[len(items)-1].ID
+		}
+		return broken(lastID, "Последняя запись не совпадает с хэшем заявки: удалены последние записи")
+	}
+	return check
+}
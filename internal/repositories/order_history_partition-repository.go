@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// OrderHistoryLegacyPartition — секция с историей до секционирования, ещё не разнесённой по годам.
+	OrderHistoryLegacyPartition = "order_history_legacy"
+	// OrderHistoryDefaultPartition — секция для записей за годы, у которых нет своей секции.
+	OrderHistoryDefaultPartition = "order_history_default"
+)
+
+// OrderHistoryPartition — секция order_history. Rows — оценка PostgreSQL по последнему ANALYZE.
+type OrderHistoryPartition struct {
+	Name  string
+	Bound string
+	Rows  int64
+}
+
+type OrderHistoryPartitionRepositoryInterface interface {
+	FindPartitions(ctx context.Context) ([]OrderHistoryPartition, error)
+	// EnsureYear создаёт секцию года и переносит в неё записи этого года из секции по умолчанию.
+	// true — секция создана сейчас.
+	EnsureYear(ctx context.Context, year int) (bool, error)
+	// LegacyYears — годы, записи которых лежат в order_history_legacy, по убыванию.
+	LegacyYears(ctx context.Context) ([]int, error)
+	// MoveLegacyYear переносит последний год order_history_legacy в свою секцию и возвращает
+	// число перенесённых записей. Опустевшая order_history_legacy удаляется.
+	MoveLegacyYear(ctx context.Context, year int) (int64, error)
+}
+
+type OrderHistoryPartitionRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewOrderHistoryPartitionRepository(storage *pgxpool.Pool, logger *zap.Logger) OrderHistoryPartitionRepositoryInterface {
+	return &OrderHistoryPartitionRepository{storage: storage, logger: logger}
+}
+
+// OrderHistoryYearPartition — имя секции order_history за год.
+func OrderHistoryYearPartition(year int) string {
+	return fmt.Sprintf("order_history_y%d", year)
+}
+
+// orderHistoryYearBounds — границы секции года: 1 января по UTC включительно и следующего года не включая.
+func orderHistoryYearBounds(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// orderHistoryBoundLiteral — граница секции для DDL, где нельзя передать параметр.
+func orderHistoryBoundLiteral(t time.Time) string {
+	return "'" + t.UTC().Format(time.RFC3339) + "'::timestamptz"
+}
+
+func (r *OrderHistoryPartitionRepository) FindPartitions(ctx context.Context) ([]OrderHistoryPartition, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT c.relname, pg_get_expr(c.relpartbound, c.oid), GREATEST(c.reltuples, 0)::bigint
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'public.order_history'::regclass
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (OrderHistoryPartition, error) {
+		var p OrderHistoryPartition
+		return p, row.Scan(&p.Name, &p.Bound, &p.Rows)
+	})
+}
+
+func (r *OrderHistoryPartitionRepository) partitionExists(ctx context.Context, q pgx.Tx, name string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+			WHERE i.inhparent = 'public.order_history'::regclass AND c.relname = $1
+		)`, name).Scan(&exists)
+	return exists, err
+}
+
+func (r *OrderHistoryPartitionRepository) EnsureYear(ctx context.Context, year int) (bool, error) {
+	created := false
+	err := r.inMaintenanceTx(ctx, func(tx pgx.Tx) error {
+		name := OrderHistoryYearPartition(year)
+		exists, err := r.partitionExists(ctx, tx, name)
+		if err != nil || exists {
+			return err
+		}
+		from, to := orderHistoryYearBounds(year)
+		// Подключить секцию можно, только если в секции по умолчанию нет записей её года.
+		if err := r.prepareYearTable(ctx, tx, name, OrderHistoryDefaultPartition, from, to); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM public.order_history_default WHERE created_at >= $1 AND created_at < $2`, from, to); err != nil {
+			return err
+		}
+		if err := r.attachYear(ctx, tx, name, from, to); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *OrderHistoryPartitionRepository) LegacyYears(ctx context.Context) ([]int, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT DISTINCT EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')::int AS year
+		FROM public.order_history_legacy
+		ORDER BY year DESC`)
+	if err != nil {
+		// order_history_legacy удаляется, когда из неё перенесён последний год
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+			return []int{}, nil
+		}
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// MoveLegacyYear копирует записи года в новую таблицу и проверяет её до того, как трогать
+// order_history. Затем в одной транзакции order_history_legacy отключается, теряет записи года и
+// подключается обратно с укороченным диапазоном — на это время запись истории ждёт.
+func (r *OrderHistoryPartitionRepository) MoveLegacyYear(ctx context.Context, year int) (int64, error) {
+	var moved int64
+	err := r.inMaintenanceTx(ctx, func(tx pgx.Tx) error {
+		var latest *time.Time
+		if err := tx.QueryRow(ctx, `SELECT MAX(created_at) FROM public.order_history_legacy`).Scan(&latest); err != nil {
+			return err
+		}
+		if latest == nil {
+			return r.dropLegacy(ctx, tx)
+		}
+		if latest.UTC().Year() != year {
+			return fmt.Errorf("из %s переносится только последний год (%d), а не %d", OrderHistoryLegacyPartition, latest.UTC().Year(), year)
+		}
+
+		name := OrderHistoryYearPartition(year)
+		from, to := orderHistoryYearBounds(year)
+		if err := r.prepareYearTable(ctx, tx, name, OrderHistoryLegacyPartition, from, to); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `DELETE FROM public.order_history_legacy WHERE created_at >= $1`, from)
+		if err != nil {
+			return err
+		}
+		moved = tag.RowsAffected()
+
+		if _, err := tx.Exec(ctx, `ALTER TABLE public.order_history DETACH PARTITION public.order_history_legacy`); err != nil {
+			return err
+		}
+		if err := r.attachYear(ctx, tx, name, from, to); err != nil {
+			return err
+		}
+
+		var empty bool
+		if err := tx.QueryRow(ctx, `SELECT NOT EXISTS (SELECT 1 FROM public.order_history_legacy)`).Scan(&empty); err != nil {
+			return err
+		}
+		if empty {
+			_, err = tx.Exec(ctx, `DROP TABLE public.order_history_legacy`)
+			return err
+		}
+		_, err = tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE public.order_history ATTACH PARTITION public.order_history_legacy FOR VALUES FROM (MINVALUE) TO (%s)`,
+			orderHistoryBoundLiteral(from)))
+		return err
+	})
+	if err == nil {
+		r.logger.Info("Год истории перенесён в свою секцию", zap.Int("year", year), zap.Int64("rows", moved))
+	}
+	return moved, err
+}
+
+func (r *OrderHistoryPartitionRepository) dropLegacy(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `ALTER TABLE public.order_history DETACH PARTITION public.order_history_legacy`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `DROP TABLE public.order_history_legacy`)
+	return err
+}
+
+// prepareYearTable создаёт отдельную таблицу секции с индексами, ключами и CHECK по диапазону и
+// копирует в неё записи года из source. Пока таблица не подключена, order_history не блокируется,
+// а при подключении PostgreSQL не перепроверяет строки и не строит индексы заново.
+func (r *OrderHistoryPartitionRepository) prepareYearTable(ctx context.Context, tx pgx.Tx, name, source string, from, to time.Time) error {
+	table := pgx.Identifier{"public", name}.Sanitize()
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE TABLE %s (LIKE public.order_history INCLUDING ALL)`, table)); err != nil {
+		return fmt.Errorf("секция %s: %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`INSERT INTO %s SELECT * FROM %s WHERE created_at >= $1 AND created_at < $2`,
+		table, pgx.Identifier{"public", source}.Sanitize()), from, to); err != nil {
+		return fmt.Errorf("секция %s: %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s CHECK (created_at >= %s AND created_at < %s)`,
+		table, pgx.Identifier{name + "_range"}.Sanitize(), orderHistoryBoundLiteral(from), orderHistoryBoundLiteral(to))); err != nil {
+		return fmt.Errorf("секция %s: %w", name, err)
+	}
+	return r.copyForeignKeys(ctx, tx, table)
+}
+
+// copyForeignKeys повторяет на таблице внешние ключи order_history, чтобы подключение секции
+// не проверяло их под блокировкой.
+func (r *OrderHistoryPartitionRepository) copyForeignKeys(ctx context.Context, tx pgx.Tx, table string) error {
+	rows, err := tx.Query(ctx, `
+		SELECT conname, pg_get_constraintdef(oid)
+		FROM pg_constraint
+		WHERE conrelid = 'public.order_history'::regclass AND contype = 'f'`)
+	if err != nil {
+		return err
+	}
+	type foreignKey struct{ name, def string }
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (foreignKey, error) {
+		var fk foreignKey
+		return fk, row.Scan(&fk.name, &fk.def)
+	})
+	if err != nil {
+		return err
+	}
+	for _, fk := range keys {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s %s`, table, pgx.Identifier{fk.name}.Sanitize(), fk.def)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *OrderHistoryPartitionRepository) attachYear(ctx context.Context, tx pgx.Tx, name string, from, to time.Time) error {
+	table := pgx.Identifier{"public", name}.Sanitize()
+	_, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE public.order_history ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)`,
+		table, orderHistoryBoundLiteral(from), orderHistoryBoundLiteral(to)))
+	if err != nil {
+		return fmt.Errorf("секция %s: %w", name, err)
+	}
+	_, err = tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s`, table, pgx.Identifier{name + "_range"}.Sanitize()))
+	return err
+}
+
+// inMaintenanceTx снимает statement_timeout пула: перенос года может идти долго. lock_timeout
+// не даёт очереди за блокировкой order_history остановить запись истории.
+func (r *OrderHistoryPartitionRepository) inMaintenanceTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.storage.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, setting := range []string{`SET LOCAL statement_timeout = 0`, `SET LOCAL lock_timeout = '10s'`} {
+		if _, err := tx.Exec(ctx, setting); err != nil {
+			return err
+		}
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
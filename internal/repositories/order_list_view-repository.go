@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+)
+
+// orderListViewRefreshQuery пересобирает строки order_list_view для заявок из $1. Строка
+// переписывается, только если что-то изменилось; RETURNING отдаёт вставленные и изменённые строки.
+const orderListViewRefreshQuery = `
+	INSERT INTO order_list_view (
+		order_id, status_name, status_code, priority_name, priority_color, priority_sort_order,
+		creator_name, executor_name, team_name, last_comment, last_comment_at, attachments_count, refreshed_at
+	)
+	SELECT o.id, st.name, st.code, pr.name, pr.color, pr.sort_order,
+		COALESCE(creator.fio, ''), executor.fio, team.name, lc.comment, lc.created_at,
+		(SELECT COUNT(*) FROM attachments a WHERE a.order_id = o.id), NOW()
+	FROM orders o
+	LEFT JOIN statuses st ON st.id = o.status_id
+	LEFT JOIN priorities pr ON pr.id = o.priority_id
+	LEFT JOIN users creator ON creator.id = o.user_id
+	LEFT JOIN users executor ON executor.id = o.executor_id
+	LEFT JOIN teams team ON team.id = o.team_id
+	LEFT JOIN LATERAL (
+		SELECT LEFT(h.comment, 200) AS comment, h.created_at
+		FROM order_history h
+		WHERE h.order_id = o.id AND h.event_type = 'COMMENT' AND COALESCE(h.comment, '') <> ''
+			AND h.created_at >= o.history_since
+		ORDER BY h.created_at DESC, h.id DESC
+		LIMIT 1
+	) lc ON TRUE
+	WHERE o.id = ANY($1)
+	ON CONFLICT (order_id) DO UPDATE SET
+		status_name = EXCLUDED.status_name,
+		status_code = EXCLUDED.status_code,
+		priority_name = EXCLUDED.priority_name,
+		priority_color = EXCLUDED.priority_color,
+		priority_sort_order = EXCLUDED.priority_sort_order,
+		creator_name = EXCLUDED.creator_name,
+		executor_name = EXCLUDED.executor_name,
+		team_name = EXCLUDED.team_name,
+		last_comment = EXCLUDED.last_comment,
+		last_comment_at = EXCLUDED.last_comment_at,
+		attachments_count = EXCLUDED.attachments_count,
+		refreshed_at = EXCLUDED.refreshed_at
+	WHERE (order_list_view.status_name, order_list_view.status_code, order_list_view.priority_name,
+		order_list_view.priority_color, order_list_view.priority_sort_order, order_list_view.creator_name,
+		order_list_view.executor_name, order_list_view.team_name, order_list_view.last_comment,
+		order_list_view.last_comment_at, order_list_view.attachments_count)
+		IS DISTINCT FROM
+		(EXCLUDED.status_name, EXCLUDED.status_code, EXCLUDED.priority_name, EXCLUDED.priority_color,
+		EXCLUDED.priority_sort_order, EXCLUDED.creator_name, EXCLUDED.executor_name, EXCLUDED.team_name,
+		EXCLUDED.last_comment, EXCLUDED.last_comment_at, EXCLUDED.attachments_count)
+	RETURNING order_id, creator_name, executor_name, team_name, priority_name, priority_color, priority_sort_order,
+		status_name, last_comment, last_comment_at, attachments_count`
+
+// orderListViewColumns — поля заявки в списке, которые берутся из order_list_view под псевдонимом v.
+var orderListViewColumns = []string{
+	"COALESCE(v.creator_name, '') AS creator_name",
+	"v.executor_name",
+	"v.team_name",
+	"v.priority_name",
+	"v.priority_color",
+	"v.priority_sort_order",
+	"v.status_name",
+	"v.last_comment",
+	"v.last_comment_at",
+	"COALESCE(v.attachments_count, 0) AS attachments_count",
+	"v.order_id IS NOT NULL AS in_list_view",
+	// Срока отклика в проекции нет: он нужен только для обратного отсчёта в ответе
+	"(SELECT pr.first_response_minutes FROM priorities pr WHERE pr.id = o.priority_id) AS priority_first_response_minutes",
+}
+
+// orderListRow — строка списка заявок: заявка и её поля из order_list_view.
+type orderListRow struct {
+	entities.Order
+	StatusName       *string    `db:"status_name"`
+	LastComment      *string    `db:"last_comment"`
+	LastCommentAt    *time.Time `db:"last_comment_at"`
+	AttachmentsCount int        `db:"attachments_count"`
+	InListView       bool       `db:"in_list_view"`
+}
+
+func (row *orderListRow) toOrder() entities.Order {
+	order := row.Order
+	order.ListView = &entities.OrderListView{
+		StatusName:       row.StatusName,
+		LastComment:      row.LastComment,
+		LastCommentAt:    row.LastCommentAt,
+		AttachmentsCount: row.AttachmentsCount,
+	}
+	return order
+}
+
+// orderListViewRow — строка, которую вернул orderListViewRefreshQuery.
+type orderListViewRow struct {
+	OrderID           uint64     `db:"order_id"`
+	CreatorName       string     `db:"creator_name"`
+	ExecutorName      *string    `db:"executor_name"`
+	TeamName          *string    `db:"team_name"`
+	PriorityName      *string    `db:"priority_name"`
+	PriorityColor     *string    `db:"priority_color"`
+	PrioritySortOrder *int       `db:"priority_sort_order"`
+	StatusName        *string    `db:"status_name"`
+	LastComment       *string    `db:"last_comment"`
+	LastCommentAt     *time.Time `db:"last_comment_at"`
+	AttachmentsCount  int        `db:"attachments_count"`
+}
+
+func (v orderListViewRow) applyTo(row *orderListRow) {
+	row.CreatorName, row.ExecutorName, row.TeamName = v.CreatorName, v.ExecutorName, v.TeamName
+	row.PriorityName, row.PriorityColor, row.PrioritySortOrder = v.PriorityName, v.PriorityColor, v.PrioritySortOrder
+	row.StatusName, row.LastComment, row.LastCommentAt = v.StatusName, v.LastComment, v.LastCommentAt
+	row.AttachmentsCount, row.InListView = v.AttachmentsCount, true
+}
+
+type orderListViewQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func refreshOrderListView(ctx context.Context, db orderListViewQuerier, orderIDs []uint64) ([]orderListViewRow, error) {
+	rows, err := db.Query(ctx, orderListViewRefreshQuery, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[orderListViewRow])
+}
+
+type OrderListViewRepositoryInterface interface {
+	// Refresh пересобирает строки проекции для заявок и возвращает, сколько строк изменилось.
+	Refresh(ctx context.Context, orderIDs []uint64) (int64, error)
+	// NextOrderIDs — ID заявок больше afterID по возрастанию, для обхода всех заявок пачками.
+	NextOrderIDs(ctx context.Context, afterID uint64, limit int) ([]uint64, error)
+}
+
+type OrderListViewRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewOrderListViewRepository(storage *pgxpool.Pool, logger *zap.Logger) OrderListViewRepositoryInterface {
+	return &OrderListViewRepository{storage: storage, logger: logger}
+}
+
+func (r *OrderListViewRepository) Refresh(ctx context.Context, orderIDs []uint64) (int64, error) {
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+	tag, err := r.storage.Exec(ctx, orderListViewRefreshQuery, orderIDs)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *OrderListViewRepository) NextOrderIDs(ctx context.Context, afterID uint64, limit int) ([]uint64, error) {
+	rows, err := r.storage.Query(ctx, `SELECT id FROM orders WHERE id > $1 ORDER BY id LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uint64])
+}
+
+// fillMissingListView достраивает строки проекции для заявок, которых в ней ещё нет (слушатель
+// не успел или упал), и подставляет их в страницу. Ошибка не валит список: названия останутся пустыми.
+func (r *OrderRepository) fillMissingListView(ctx context.Context, rows []orderListRow) {
+	var missing []uint64
+	for _, row := range rows {
+		if !row.InListView {
+			missing = append(missing, row.ID)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	refreshed, err := refreshOrderListView(ctx, r.storage, missing)
+	if err != nil {
+		r.logger.Warn("Не удалось достроить проекцию списка заявок", zap.Uint64s("orderIDs", missing), zap.Error(err))
+		return
+	}
+	byID := make(map[uint64]orderListViewRow, len(refreshed))
+	for _, view := range refreshed {
+		byID[view.OrderID] = view
+	}
+	for i := range rows {
+		if view, ok := byID[rows[i].ID]; ok {
+			view.applyTo(&rows[i])
+		}
+	}
+}
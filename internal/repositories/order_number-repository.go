@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"request-system/internal/entities"
+)
+
+// DefaultOrderNumberPrefix — префикс номера заявки, если у филиала свой не задан.
+const DefaultOrderNumberPrefix = "REQ"
+
+// orderNumberNextQuery определяет организацию заявки (из контекста, без неё — у автора) и занимает
+// следующее значение счётчика организации, префикса и текущего года.
+// Строка счётчика блокируется до конца транзакции создания заявки, так что номера идут без дублей;
+// при откате транзакции значение возвращается.
+const orderNumberNextQuery = `
+	WITH target AS (
+		SELECT COALESCE($1::bigint, (SELECT tenant_id FROM users WHERE id = $2), 1) AS tenant_id,
+			COALESCE((SELECT order_prefix FROM branches WHERE id = $3), $4::varchar) AS prefix
+	)
+	INSERT INTO order_number_counters (tenant_id, prefix, year, last_value)
+	SELECT tenant_id, prefix, EXTRACT(YEAR FROM NOW())::int, 1 FROM target
+	ON CONFLICT (tenant_id, prefix, year) DO UPDATE SET last_value = order_number_counters.last_value + 1
+	RETURNING tenant_id, prefix, year, last_value`
+
+// FormatOrderNumber собирает номер вида REQ-2026-000123.
+func FormatOrderNumber(prefix string, year int, seq int64) string {
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, seq)
+}
+
+// nextOrderNumber выдаёт номер новой заявке в транзакции её создания и возвращает организацию заявки.
+func nextOrderNumber(ctx context.Context, tx pgx.Tx, order *entities.Order) (uint64, string, error) {
+	var (
+		tenantID uint64
+		prefix   string
+		year     int
+		seq      int64
+	)
+	err := tx.QueryRow(ctx, orderNumberNextQuery, tenantIDArg(ctx), order.CreatorID, order.BranchID, DefaultOrderNumberPrefix).
+		Scan(&tenantID, &prefix, &year, &seq)
+	if err != nil {
+		return 0, "", err
+	}
+	return tenantID, FormatOrderNumber(prefix, year, seq), nil
+}
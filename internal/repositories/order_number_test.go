@@ -0,0 +1,13 @@
+package repositories
+
+import "testing"
+
+func TestFormatOrderNumber(t *testing.T) {
+	if got := FormatOrderNumber("REQ", 2026, 123); got != "REQ-2026-000123" {
+		t.Fatalf("номер: %s", got)
+	}
+	// Номер за миллионом не обрезается
+	if got := FormatOrderNumber("HQ", 2026, 1234567); got != "HQ-2026-1234567" {
+		t.Fatalf("длинный номер: %s", got)
+	}
+}
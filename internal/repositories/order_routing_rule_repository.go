@@ -18,7 +18,10 @@ const (
 	ruleTable = "order_routing_rules"
 	// ВАЖНО: Список полей должен совпадать со структурой базы данных
 	// и порядком сканирования в методе scanRow
-	ruleFields = "id, rule_name, order_type_id, department_id, otdel_id, branch_id, office_id, assign_to_position_id, status_id, created_at, updated_at"
+	ruleFields = "id, rule_name, order_type_id, department_id, otdel_id, branch_id, office_id, assign_to_position_id, " +
+		"assign_to_team_id, (SELECT t.name FROM teams t WHERE t.id = assign_to_team_id) AS team_name, " +
+		"assign_to_on_call_schedule_id, (SELECT s.name FROM on_call_schedules s WHERE s.id = assign_to_on_call_schedule_id) AS on_call_schedule_name, " +
+		"status_id, created_at, updated_at"
 )
 
 type OrderRoutingRuleRepositoryInterface interface {
@@ -51,6 +54,10 @@ func (r *orderRoutingRuleRepository) scanRow(row pgx.Row) (*entities.OrderRoutin
 		&rule.BranchID,   // Новое поле
 		&rule.OfficeID,   // Новое поле
 		&rule.PositionID, // В БД это assign_to_position_id
+		&rule.TeamID,     // В БД это assign_to_team_id
+		&rule.TeamName,
+		&rule.OnCallScheduleID, // В БД это assign_to_on_call_schedule_id
+		&rule.OnCallScheduleName,
 		&rule.StatusID,
 		&rule.CreatedAt, // BaseEntity поле
 		&rule.UpdatedAt, // BaseEntity поле
@@ -67,8 +74,8 @@ func (r *orderRoutingRuleRepository) scanRow(row pgx.Row) (*entities.OrderRoutin
 func (r *orderRoutingRuleRepository) Create(ctx context.Context, tx pgx.Tx, rule *entities.OrderRoutingRule) (uint64, error) {
 	// Добавляем branch_id и office_id в INSERT
 	query := `INSERT INTO order_routing_rules 
-		(rule_name, order_type_id, department_id, otdel_id, branch_id, office_id, assign_to_position_id, status_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+		(rule_name, order_type_id, department_id, otdel_id, branch_id, office_id, assign_to_position_id, status_id, assign_to_team_id, assign_to_on_call_schedule_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
 		RETURNING id`
 
 	var id uint64
@@ -81,6 +88,8 @@ func (r *orderRoutingRuleRepository) Create(ctx context.Context, tx pgx.Tx, rule
 		rule.OfficeID,
 		rule.PositionID,
 		rule.StatusID,
+		rule.TeamID,
+		rule.OnCallScheduleID,
 	).Scan(&id)
 	if err != nil {
 		return 0, apperrors.WrapDBError(err)
@@ -98,6 +107,8 @@ func (r *orderRoutingRuleRepository) Update(ctx context.Context, tx pgx.Tx, rule
 		office_id = $6,
 		assign_to_position_id = $7, 
 		status_id = $8, 
+		assign_to_team_id = $10,
+		assign_to_on_call_schedule_id = $11,
 		updated_at = NOW() 
 		WHERE id = $9`
 
@@ -111,6 +122,8 @@ func (r *orderRoutingRuleRepository) Update(ctx context.Context, tx pgx.Tx, rule
 		rule.PositionID,
 		rule.StatusID,
 		rule.ID,
+		rule.TeamID,
+		rule.OnCallScheduleID,
 	)
 	if err != nil {
 		return apperrors.WrapDBError(err)
@@ -11,6 +11,7 @@ import (
 
 	"request-system/internal/entities"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -20,7 +21,19 @@ import (
 
 const (
 	orderTypeTable  = "order_types"
-	orderTypeFields = "id, name, code, status_id, created_at, updated_at"
+	orderTypeFields = `id, name, code, status_id, icon, description,
+		EXISTS (SELECT 1 FROM statuses s WHERE s.id = order_types.status_id AND s.code = 'ACTIVE') AS is_active,
+		ARRAY(SELECT department_id FROM order_type_departments WHERE order_type_id = order_types.id ORDER BY department_id) AS department_ids,
+		ARRAY(SELECT branch_id FROM order_type_branches WHERE order_type_id = order_types.id ORDER BY branch_id) AS branch_ids,
+		created_at, updated_at`
+	// orderTypeAvailableCondition — тип активен и виден сотруднику департамента $2 / филиала $3.
+	orderTypeAvailableCondition = `EXISTS (SELECT 1 FROM statuses s WHERE s.id = order_types.status_id AND s.code = 'ACTIVE')
+		AND (
+			(NOT EXISTS (SELECT 1 FROM order_type_departments WHERE order_type_id = order_types.id)
+				AND NOT EXISTS (SELECT 1 FROM order_type_branches WHERE order_type_id = order_types.id))
+			OR EXISTS (SELECT 1 FROM order_type_departments WHERE order_type_id = order_types.id AND department_id = $2)
+			OR EXISTS (SELECT 1 FROM order_type_branches WHERE order_type_id = order_types.id AND branch_id = $3)
+		)`
 )
 
 // OrderTypeRepositoryInterface определяет контракт для работы с типами заявок в БД.
@@ -30,10 +43,16 @@ type OrderTypeRepositoryInterface interface {
 	Delete(ctx context.Context, tx pgx.Tx, id uint64) error
 	FindByID(ctx context.Context, id uint64) (*entities.OrderType, error)
 	FindCodeByID(ctx context.Context, id uint64) (string, error)
+	FindIDByCode(ctx context.Context, code string) (uint64, error)
 	GetAll(ctx context.Context, limit, offset uint64, search string) ([]*entities.OrderType, uint64, error)
 	FindCodesByIDs(ctx context.Context, ids []uint64) (map[uint64]string, error)
 	ExistsByName(ctx context.Context, tx pgx.Tx, name string, excludeID uint64) (bool, error)
 	ExistsByCode(ctx context.Context, tx pgx.Tx, code *string, excludeID uint64) (bool, error)
+	// SetVisibility заменяет департаменты и филиалы, которым виден тип; nil-список не меняется.
+	SetVisibility(ctx context.Context, tx pgx.Tx, id uint64, departmentIDs, branchIDs []uint64) error
+	// FindAvailable — активные типы, видимые сотруднику департамента и филиала (nil — не указан).
+	FindAvailable(ctx context.Context, departmentID, branchID *uint64) ([]*entities.OrderType, error)
+	IsAvailable(ctx context.Context, id uint64, departmentID, branchID *uint64) (bool, error)
 }
 
 type orderTypeRepository struct {
@@ -48,8 +67,10 @@ func NewOrderTypeRepository(storage *pgxpool.Pool) OrderTypeRepositoryInterface
 func (r *orderTypeRepository) scanRow(row pgx.Row) (*entities.OrderType, error) {
 	var ot entities.OrderType
 	var code sql.NullString
+	var departmentIDs, branchIDs []int64
 
-	err := row.Scan(&ot.ID, &ot.Name, &code, &ot.StatusID, &ot.CreatedAt, &ot.UpdatedAt)
+	err := row.Scan(&ot.ID, &ot.Name, &code, &ot.StatusID, &ot.Icon, &ot.Description, &ot.IsActive,
+		&departmentIDs, &branchIDs, &ot.CreatedAt, &ot.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
@@ -60,19 +81,30 @@ func (r *orderTypeRepository) scanRow(row pgx.Row) (*entities.OrderType, error)
 	if code.Valid {
 		ot.Code = &code.String
 	}
+	ot.DepartmentIDs = toUint64IDs(departmentIDs)
+	ot.BranchIDs = toUint64IDs(branchIDs)
 
 	return &ot, nil
 }
 
+func toUint64IDs(ids []int64) []uint64 {
+	result := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uint64(id))
+	}
+	return result
+}
+
 // Create создает новый тип заявки в транзакции.
 func (r *orderTypeRepository) Create(ctx context.Context, tx pgx.Tx, orderType *entities.OrderType) (uint64, error) {
 	query := fmt.Sprintf(`
-		INSERT INTO %s (name, code, status_id) 
-		VALUES ($1, $2, $3) 
+		INSERT INTO %s (name, code, status_id, icon, description, tenant_id) 
+		VALUES ($1, $2, $3, $4, $5, $6) 
 		RETURNING id`, orderTypeTable)
 
 	var id uint64
-	err := tx.QueryRow(ctx, query, orderType.Name, orderType.Code, orderType.StatusID).Scan(&id)
+	err := tx.QueryRow(ctx, query, orderType.Name, orderType.Code, orderType.StatusID, orderType.Icon, orderType.Description,
+		utils.TenantIDOrDefault(ctx)).Scan(&id)
 	if err != nil {
 		return 0, apperrors.WrapDBError(err)
 	}
@@ -84,10 +116,10 @@ func (r *orderTypeRepository) Create(ctx context.Context, tx pgx.Tx, orderType *
 func (r *orderTypeRepository) Update(ctx context.Context, tx pgx.Tx, orderType *entities.OrderType) error {
 	query := fmt.Sprintf(`
 		UPDATE %s 
-		SET name = $1, code = $2, status_id = $3, updated_at = NOW() 
-		WHERE id = $4`, orderTypeTable)
+		SET name = $1, code = $2, status_id = $3, icon = $4, description = $5, updated_at = NOW() 
+		WHERE id = $6`, orderTypeTable)
 
-	result, err := tx.Exec(ctx, query, orderType.Name, orderType.Code, orderType.StatusID, orderType.ID)
+	result, err := tx.Exec(ctx, query, orderType.Name, orderType.Code, orderType.StatusID, orderType.Icon, orderType.Description, orderType.ID)
 	if err != nil {
 		return apperrors.WrapDBError(err)
 	}
@@ -121,19 +153,20 @@ func (r *orderTypeRepository) Delete(ctx context.Context, tx pgx.Tx, id uint64)
 
 // FindByID находит тип заявки по ID.
 func (r *orderTypeRepository) FindByID(ctx context.Context, id uint64) (*entities.OrderType, error) {
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", orderTypeFields, orderTypeTable)
-	row := r.storage.QueryRow(ctx, query, id)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND ($2::bigint IS NULL OR tenant_id IS NULL OR tenant_id = $2)", orderTypeFields, orderTypeTable)
+	row := r.storage.QueryRow(ctx, query, id, tenantIDArg(ctx))
 	return r.scanRow(row)
 }
 
 // GetAll получает список типов заявок с пагинацией и поиском.
 func (r *orderTypeRepository) GetAll(ctx context.Context, limit, offset uint64, search string) ([]*entities.OrderType, uint64, error) {
 	var total uint64
-	var args []interface{}
-	whereClause := ""
+	// Общие типы (tenant_id IS NULL) видны всем организациям
+	args := []interface{}{tenantIDArg(ctx)}
+	whereClause := "WHERE ($1::bigint IS NULL OR tenant_id IS NULL OR tenant_id = $1)"
 
 	if search != "" {
-		whereClause = "WHERE name ILIKE $1 OR code ILIKE $1"
+		whereClause += " AND (name ILIKE $2 OR code ILIKE $2)"
 		args = append(args, "%"+search+"%")
 	}
 
@@ -171,6 +204,18 @@ func (r *orderTypeRepository) GetAll(ctx context.Context, limit, offset uint64,
 	return orderTypes, total, nil
 }
 
+func (r *orderTypeRepository) FindIDByCode(ctx context.Context, code string) (uint64, error) {
+	var id uint64
+	err := r.storage.QueryRow(ctx, "SELECT id FROM order_types WHERE code = $1", code).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperrors.ErrNotFound
+		}
+		return 0, fmt.Errorf("ошибка поиска order_type по коду: %w", err)
+	}
+	return id, nil
+}
+
 func (r *orderTypeRepository) FindCodeByID(ctx context.Context, id uint64) (string, error) {
 	query := "SELECT code FROM order_types WHERE id = $1"
 	var code sql.NullString
@@ -251,3 +296,57 @@ func (r *orderTypeRepository) ExistsByCode(ctx context.Context, tx pgx.Tx, code
 	err := tx.QueryRow(ctx, query, *code, excludeID).Scan(&exists)
 	return exists, err
 }
+
+func (r *orderTypeRepository) SetVisibility(ctx context.Context, tx pgx.Tx, id uint64, departmentIDs, branchIDs []uint64) error {
+	links := []struct {
+		table, column string
+		ids           []uint64
+	}{
+		{"order_type_departments", "department_id", departmentIDs},
+		{"order_type_branches", "branch_id", branchIDs},
+	}
+	for _, link := range links {
+		if link.ids == nil {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM `+link.table+` WHERE order_type_id = $1`, id); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO `+link.table+` (order_type_id, `+link.column+`) SELECT $1, UNNEST($2::bigint[]) ON CONFLICT DO NOTHING`,
+			id, link.ids,
+		)
+		if err != nil {
+			return apperrors.WrapDBError(err)
+		}
+	}
+	return nil
+}
+
+func (r *orderTypeRepository) FindAvailable(ctx context.Context, departmentID, branchID *uint64) ([]*entities.OrderType, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s
+		WHERE ($1::bigint IS NULL OR tenant_id IS NULL OR tenant_id = $1) AND %s
+		ORDER BY name`, orderTypeFields, orderTypeTable, orderTypeAvailableCondition)
+	rows, err := r.storage.Query(ctx, query, tenantIDArg(ctx), departmentID, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения доступных order_types: %w", err)
+	}
+	defer rows.Close()
+
+	orderTypes := make([]*entities.OrderType, 0)
+	for rows.Next() {
+		orderType, err := r.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		orderTypes = append(orderTypes, orderType)
+	}
+	return orderTypes, rows.Err()
+}
+
+func (r *orderTypeRepository) IsAvailable(ctx context.Context, id uint64, departmentID, branchID *uint64) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND %s)`, orderTypeTable, orderTypeAvailableCondition)
+	var available bool
+	err := r.storage.QueryRow(ctx, query, id, departmentID, branchID).Scan(&available)
+	return available, err
+}
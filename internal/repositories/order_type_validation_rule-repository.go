@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const orderTypeValidationRuleSelectQuery = `
+	SELECT id, order_type_id, field_name, error_message, on_create, on_update, created_at, updated_at
+	FROM order_type_validation_rules`
+
+type OrderTypeValidationRuleRepositoryInterface interface {
+	// FindAll — правила всех типов заявок (для кэша).
+	FindAll(ctx context.Context) ([]entities.OrderTypeValidationRule, error)
+	FindByOrderType(ctx context.Context, orderTypeID uint64) ([]entities.OrderTypeValidationRule, error)
+	FindByID(ctx context.Context, id uint64) (*entities.OrderTypeValidationRule, error)
+	Create(ctx context.Context, rule *entities.OrderTypeValidationRule) error
+	Update(ctx context.Context, rule *entities.OrderTypeValidationRule) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+type OrderTypeValidationRuleRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewOrderTypeValidationRuleRepository(storage *pgxpool.Pool, logger *zap.Logger) OrderTypeValidationRuleRepositoryInterface {
+	return &OrderTypeValidationRuleRepository{storage: storage, logger: logger}
+}
+
+func (r *OrderTypeValidationRuleRepository) FindAll(ctx context.Context) ([]entities.OrderTypeValidationRule, error) {
+	rows, err := r.storage.Query(ctx, orderTypeValidationRuleSelectQuery+` ORDER BY order_type_id, id`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OrderTypeValidationRule])
+}
+
+func (r *OrderTypeValidationRuleRepository) FindByOrderType(ctx context.Context, orderTypeID uint64) ([]entities.OrderTypeValidationRule, error) {
+	rows, err := r.storage.Query(ctx, orderTypeValidationRuleSelectQuery+`
+		WHERE order_type_id = $1
+		ORDER BY id`, orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OrderTypeValidationRule])
+}
+
+func (r *OrderTypeValidationRuleRepository) FindByID(ctx context.Context, id uint64) (*entities.OrderTypeValidationRule, error) {
+	rows, err := r.storage.Query(ctx, orderTypeValidationRuleSelectQuery+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.OrderTypeValidationRule])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *OrderTypeValidationRuleRepository) Create(ctx context.Context, rule *entities.OrderTypeValidationRule) error {
+	err := r.storage.QueryRow(ctx, `
+		INSERT INTO order_type_validation_rules (order_type_id, field_name, error_message, on_create, on_update)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		rule.OrderTypeID, rule.FieldName, rule.ErrorMessage, rule.OnCreate, rule.OnUpdate,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *OrderTypeValidationRuleRepository) Update(ctx context.Context, rule *entities.OrderTypeValidationRule) error {
+	err := r.storage.QueryRow(ctx, `
+		UPDATE order_type_validation_rules
+		SET error_message = $2, on_create = $3, on_update = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		rule.ID, rule.ErrorMessage, rule.OnCreate, rule.OnUpdate,
+	).Scan(&rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	return apperrors.WrapDBError(err)
+}
+
+func (r *OrderTypeValidationRuleRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM order_type_validation_rules WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
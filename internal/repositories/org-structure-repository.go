@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+)
+
+// orgUnitHeadJoin — руководитель подразделения (при его отсутствии заместитель) по должности.
+// userColumn, unitColumn и должности приходят только из констант этого файла.
+func orgUnitHeadJoin(userColumn, unitColumn, head, deputy string) string {
+	return fmt.Sprintf(`LEFT JOIN LATERAL (
+		SELECT u.id, u.fio
+		FROM users u
+		JOIN user_positions up ON up.user_id = u.id
+		JOIN positions p ON p.id = up.position_id
+		JOIN statuses s ON s.id = u.status_id
+		WHERE u.deleted_at IS NULL AND UPPER(s.code) = 'ACTIVE'
+		  AND u.%s = %s AND p.type IN ('%s', '%s')
+		ORDER BY (p.type = '%s') DESC, u.id
+		LIMIT 1
+	) h ON TRUE`, userColumn, unitColumn, head, deputy, head)
+}
+
+var orgStructureQuery = `
+	WITH active_orders AS (
+		SELECT o.department_id, o.otdel_id, o.branch_id, o.office_id
+		FROM orders o
+		JOIN statuses st ON st.id = o.status_id
+		WHERE o.deleted_at IS NULL
+		  AND st.code NOT IN ('CLOSED', 'COMPLETED', 'REJECTED', 'DUPLICATE')
+	),
+	department_orders AS (SELECT department_id AS unit_id, COUNT(*) AS cnt FROM active_orders WHERE department_id IS NOT NULL GROUP BY department_id),
+	otdel_orders AS (SELECT otdel_id AS unit_id, COUNT(*) AS cnt FROM active_orders WHERE otdel_id IS NOT NULL GROUP BY otdel_id),
+	branch_orders AS (SELECT branch_id AS unit_id, COUNT(*) AS cnt FROM active_orders WHERE branch_id IS NOT NULL GROUP BY branch_id),
+	office_orders AS (SELECT office_id AS unit_id, COUNT(*) AS cnt FROM active_orders WHERE office_id IS NOT NULL GROUP BY office_id)
+
+	SELECT 'department' AS kind, d.id::bigint AS id, d.name, d.status_id::bigint AS status_id,
+		NULL::bigint AS parent_id, NULL::bigint AS department_id, NULL::bigint AS branch_id,
+		h.id AS head_id, h.fio AS head_fio, COALESCE(c.cnt, 0)::int AS active_orders
+	FROM departments d
+	` + orgUnitHeadJoin("department_id", "d.id", "HEAD_OF_DEPARTMENT", "DEPUTY_HEAD_OF_DEPARTMENT") + `
+	LEFT JOIN department_orders c ON c.unit_id = d.id
+
+	UNION ALL
+	SELECT 'otdel', ot.id::bigint, ot.name, ot.status_id::bigint,
+		ot.parent_id::bigint, ot.departments_id::bigint, ot.branch_id::bigint,
+		h.id, h.fio, COALESCE(c.cnt, 0)::int
+	FROM otdels ot
+	` + orgUnitHeadJoin("otdel_id", "ot.id", "HEAD_OF_OTDEL", "DEPUTY_HEAD_OF_OTDEL") + `
+	LEFT JOIN otdel_orders c ON c.unit_id = ot.id
+
+	UNION ALL
+	SELECT 'branch', b.id::bigint, b.name, b.status_id::bigint,
+		NULL::bigint, NULL::bigint, NULL::bigint,
+		h.id, h.fio, COALESCE(c.cnt, 0)::int
+	FROM branches b
+	` + orgUnitHeadJoin("branch_id", "b.id", "BRANCH_DIRECTOR", "DEPUTY_BRANCH_DIRECTOR") + `
+	LEFT JOIN branch_orders c ON c.unit_id = b.id
+
+	UNION ALL
+	SELECT 'office', ofc.id::bigint, ofc.name, ofc.status_id::bigint,
+		ofc.parent_id::bigint, NULL::bigint, ofc.branch_id::bigint,
+		h.id, h.fio, COALESCE(c.cnt, 0)::int
+	FROM offices ofc
+	` + orgUnitHeadJoin("office_id", "ofc.id", "HEAD_OF_OFFICE", "DEPUTY_HEAD_OF_OFFICE") + `
+	LEFT JOIN office_orders c ON c.unit_id = ofc.id
+
+	ORDER BY kind, name`
+
+type OrgStructureRepositoryInterface interface {
+	// FindUnits возвращает все департаменты, отделы, филиалы и офисы одним запросом
+	// вместе с руководителями и числом незакрытых заявок.
+	FindUnits(ctx context.Context) ([]entities.OrgUnit, error)
+}
+
+type OrgStructureRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewOrgStructureRepository(storage *pgxpool.Pool, logger *zap.Logger) OrgStructureRepositoryInterface {
+	return &OrgStructureRepository{storage: storage, logger: logger}
+}
+
+func (r *OrgStructureRepository) FindUnits(ctx context.Context) ([]entities.OrgUnit, error) {
+	rows, err := r.storage.Query(ctx, orgStructureQuery)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.OrgUnit])
+}
@@ -17,6 +17,7 @@ import (
 	"request-system/internal/entities"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
+	"request-system/pkg/utils"
 )
 
 const otdelTable = "otdels"
@@ -100,14 +101,14 @@ func scanOtdel(row pgx.Row) (*entities.Otdel, error) {
 
 func (r *OtdelRepository) CreateOtdel(ctx context.Context, tx pgx.Tx, otdel entities.Otdel) (uint64, error) {
 	query := `
-		INSERT INTO otdels (name, status_id, departments_id, branch_id, parent_id, external_id, source_system, created_at, updated_at)
-		VALUES($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		INSERT INTO otdels (name, status_id, departments_id, branch_id, parent_id, external_id, source_system, tenant_id, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
 		RETURNING id
 	`
 	var newID uint64
 	err := tx.QueryRow(ctx, query,
 		otdel.Name, otdel.StatusID, otdel.DepartmentsID, otdel.BranchID, otdel.ParentID,
-		otdel.ExternalID, otdel.SourceSystem,
+		otdel.ExternalID, otdel.SourceSystem, utils.TenantIDOrDefault(ctx),
 	).Scan(&newID)
 	return newID, err
 }
@@ -135,7 +136,8 @@ func (r *OtdelRepository) findOneOtdel(ctx context.Context, querier Querier, whe
 
 	queryBuilder := psql.Select("id, name, status_id, departments_id, branch_id, parent_id, created_at, updated_at, external_id, source_system").
 		From(otdelTable).
-		Where(where)
+		Where(where).
+		Where(sharedTenantCondition(ctx, "tenant_id"))
 
 	sql, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -173,7 +175,7 @@ func (r *OtdelRepository) GetOtdels(ctx context.Context, filter types.Filter) ([
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	// --- 1. COUNT ---
-	countBuilder := psql.Select("COUNT(id)").From(otdelTable)
+	countBuilder := psql.Select("COUNT(id)").From(otdelTable).Where(sharedTenantCondition(ctx, "tenant_id"))
 
 	// Ручной поиск по тексту оставляем здесь (это не точное совпадение)
 	if filter.Search != "" {
@@ -201,7 +203,8 @@ func (r *OtdelRepository) GetOtdels(ctx context.Context, filter types.Filter) ([
 	}
 
 	// --- 2. SELECT ---
-	baseBuilder := psql.Select("id, name, status_id, departments_id, branch_id, parent_id, created_at, updated_at, external_id, source_system").From(otdelTable)
+	baseBuilder := psql.Select("id, name, status_id, departments_id, branch_id, parent_id, created_at, updated_at, external_id, source_system").From(otdelTable).
+		Where(sharedTenantCondition(ctx, "tenant_id"))
 
 	if filter.Search != "" {
 		baseBuilder = baseBuilder.Where(sq.ILike{"name": "%" + filter.Search + "%"})
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"request-system/internal/authz"
 	"request-system/internal/dto"
 	apperrors "request-system/pkg/errors"
 
@@ -32,6 +34,10 @@ type PermissionRepositoryInterface interface {
 	GetFinalUserPermissionIDs(ctx context.Context, userID uint64) ([]uint64, error)
 	GetDetailedPermissionsForUI(ctx context.Context, userID uint64) (*dto.UIPermissionsResponseDTO, error)
 	GetRolePermissionIDsForUser(ctx context.Context, userID uint64) ([]uint64, error)
+	// GetUserPermissionConditions возвращает условия прав пользователя. В результат попадают
+	// только права, выданные исключительно с условиями: если хотя бы один источник
+	// (роль без условий или индивидуальное право) даёт право без них, ограничений нет.
+	GetUserPermissionConditions(ctx context.Context, userID uint64) (map[string][]authz.Condition, error)
 }
 
 type PermissionRepository struct {
@@ -266,6 +272,91 @@ func (r *PermissionRepository) GetFinalUserPermissionIDs(ctx context.Context, us
 	return pgx.CollectRows(rows, pgx.RowTo[uint64])
 }
 
+func (r *PermissionRepository) GetUserPermissionConditions(ctx context.Context, userID uint64) (map[string][]authz.Condition, error) {
+	query := `
+		SELECT p.name, rp.conditions
+		FROM role_permissions rp
+		JOIN user_roles ur ON ur.role_id = rp.role_id AND ur.user_id = $1
+		JOIN permissions p ON p.id = rp.permission_id
+		UNION ALL
+		SELECT p.name, NULL FROM user_permissions up
+		JOIN permissions p ON p.id = up.permission_id
+		WHERE up.user_id = $1
+	`
+	rows, err := r.storage.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Ошибка в SQL GetUserPermissionConditions", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]authz.Condition)
+	unconditional := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var conditions []authz.Condition
+		if err := rows.Scan(&name, &conditions); err != nil {
+			return nil, err
+		}
+		if len(conditions) == 0 {
+			unconditional[name] = true
+			continue
+		}
+		result[name] = append(result[name], conditions...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for name := range unconditional {
+		delete(result, name)
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+	return result, r.resolveMaxPriorities(ctx, result)
+}
+
+// resolveMaxPriorities подставляет в условия с max_priority ID приоритетов не срочнее
+// указанного (у более срочных приоритетов rate меньше). Неизвестный код не пропускает ни одного.
+func (r *PermissionRepository) resolveMaxPriorities(ctx context.Context, conditions map[string][]authz.Condition) error {
+	rows, err := r.storage.Query(ctx, `SELECT id, code, rate FROM priorities`)
+	if err != nil {
+		return err
+	}
+	type priorityRate struct {
+		ID   uint64
+		Code string
+		Rate int
+	}
+	priorities, err := pgx.CollectRows(rows, pgx.RowToStructByPos[priorityRate])
+	if err != nil {
+		return err
+	}
+
+	for name, list := range conditions {
+		for i, condition := range list {
+			if condition.MaxPriority == "" {
+				continue
+			}
+			limit := -1
+			for _, p := range priorities {
+				if strings.EqualFold(p.Code, condition.MaxPriority) {
+					limit = p.Rate
+				}
+			}
+			allowed := make([]uint64, 0, len(priorities))
+			for _, p := range priorities {
+				if limit >= 0 && p.Rate >= limit && (len(condition.PriorityIDs) == 0 || slices.Contains(condition.PriorityIDs, p.ID)) {
+					allowed = append(allowed, p.ID)
+				}
+			}
+			list[i].PriorityIDs = allowed
+		}
+		conditions[name] = list
+	}
+	return nil
+}
+
 func (r *PermissionRepository) GetDetailedPermissionsForUI(ctx context.Context, userID uint64) (*dto.UIPermissionsResponseDTO, error) {
 	query := `
 		WITH user_role_perms AS (
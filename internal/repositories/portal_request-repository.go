@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+type PortalRequestRepositoryInterface interface {
+	Create(ctx context.Context, req *entities.PortalRequest) error
+	FindStatusByTrackingCode(ctx context.Context, code string) (*entities.PortalRequestStatus, error)
+	// IsBranchActive — есть ли действующий филиал с таким id; портал принимает обращения только в них.
+	IsBranchActive(ctx context.Context, branchID uint64) (bool, error)
+}
+
+type PortalRequestRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewPortalRequestRepository(storage *pgxpool.Pool, logger *zap.Logger) PortalRequestRepositoryInterface {
+	return &PortalRequestRepository{storage: storage, logger: logger}
+}
+
+func (r *PortalRequestRepository) Create(ctx context.Context, req *entities.PortalRequest) error {
+	query := `
+		INSERT INTO portal_requests (order_id, tracking_code, customer_name, phone, client_ip)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := r.storage.QueryRow(ctx, query,
+		req.OrderID, req.TrackingCode, req.CustomerName, req.Phone, req.ClientIP,
+	).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return apperrors.WrapDBError(err)
+	}
+	return nil
+}
+
+// FindStatusByTrackingCode возвращает статус заявки по коду; удалённые заявки считаются ненайденными.
+func (r *PortalRequestRepository) FindStatusByTrackingCode(ctx context.Context, code string) (*entities.PortalRequestStatus, error) {
+	query := `
+		SELECT pr.tracking_code, COALESCE(s.name, ''), COALESCE(s.code, ''), o.created_at, o.updated_at, o.completed_at
+		FROM portal_requests pr
+		JOIN orders o ON o.id = pr.order_id AND o.deleted_at IS NULL
+		LEFT JOIN statuses s ON s.id = o.status_id
+		WHERE pr.tracking_code = $1`
+
+	var st entities.PortalRequestStatus
+	err := r.storage.QueryRow(ctx, query, code).Scan(
+		&st.TrackingCode, &st.StatusName, &st.StatusCode, &st.CreatedAt, &st.UpdatedAt, &st.CompletedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (r *PortalRequestRepository) IsBranchActive(ctx context.Context, branchID uint64) (bool, error) {
+	var active bool
+	err := r.storage.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM branches b
+			JOIN statuses s ON s.id = b.status_id
+			WHERE b.id = $1 AND s.code = 'ACTIVE'
+		)`, branchID).Scan(&active)
+	return active, err
+}
@@ -10,6 +10,7 @@ import (
 
 	"request-system/internal/dto"
 	"request-system/internal/entities"
+	"request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/utils"
 
@@ -29,12 +30,18 @@ type PriorityRepositoryInterface interface {
 	FindByCode(ctx context.Context, code string) (*entities.Priority, error)
 	FindByID(ctx context.Context, id uint64) (*entities.Priority, error)
 	FindByIDInTx(ctx context.Context, tx pgx.Tx, id uint64) (*entities.Priority, error)
+
+	FindMatrix(ctx context.Context) ([]entities.PriorityMatrixCell, error)
+	// ReplaceMatrix заменяет всю матрицу «влияние × срочность» переданными клетками.
+	ReplaceMatrix(ctx context.Context, cells []entities.PriorityMatrixCell) error
+	// FindIDByMatrix возвращает ErrNotFound, если клетка матрицы не настроена.
+	FindIDByMatrix(ctx context.Context, impact, urgency string) (uint64, error)
 }
 
 // Глобальные константы без полей иконок
 const (
 	priorityTable  = "priorities"
-	priorityFields = "id, name, rate, code, created_at, updated_at"
+	priorityFields = "id, name, rate, code, color, sort_order, sla_hours, created_at, updated_at, first_response_minutes"
 )
 
 type dbPriority struct {
@@ -42,19 +49,37 @@ type dbPriority struct {
 	Name      string
 	Rate      sql.Null[int32]
 	Code      sql.Null[string]
+	Color     *string
+	SortOrder int
+	SLAHours  *int
 	CreatedAt time.Time
 	UpdatedAt sql.Null[time.Time]
+
+	// FirstResponseMinutes — срок первого отклика; NULL — без срока
+	FirstResponseMinutes *int
+}
+
+// scanTargets — поля в порядке priorityFields.
+func (db *dbPriority) scanTargets() []interface{} {
+	return []interface{}{&db.ID, &db.Name, &db.Rate, &db.Code, &db.Color, &db.SortOrder, &db.SLAHours, &db.CreatedAt, &db.UpdatedAt, &db.FirstResponseMinutes}
 }
 
 // toDTO - конвертер без полей иконок.
 func (db *dbPriority) toDTO() dto.PriorityDTO {
+	code := utils.NullToValue(db.Code)
 	return dto.PriorityDTO{
 		ID:        db.ID,
 		Name:      db.Name,
 		Rate:      int(utils.NullToValue(db.Rate)),
-		Code:      utils.NullToValue(db.Code),
+		Code:      code,
+		Color:     db.Color,
+		SortOrder: db.SortOrder,
+		SLAHours:  db.SLAHours,
+		Protected: constants.IsProtectedPriorityCode(code),
 		CreatedAt: db.CreatedAt.Local().Format("2006-01-02 15:04:05"),
 		UpdatedAt: utils.FormatNullTime(db.UpdatedAt),
+
+		FirstResponseMinutes: db.FirstResponseMinutes,
 	}
 }
 
@@ -93,7 +118,7 @@ func (r *PriorityRepository) GetPriorities(ctx context.Context, limit, offset ui
 
 	// Код для постраничной навигации и сортировки остается без изменений
 	queryArgs := append(args, limit, offset)
-	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY rate DESC, id LIMIT $%d OFFSET $%d",
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY sort_order, rate DESC, id LIMIT $%d OFFSET $%d",
 		priorityFields, priorityTable, whereClause, len(args)+1, len(args)+2)
 
 	rows, err := r.storage.Query(ctx, query, queryArgs...)
@@ -106,7 +131,7 @@ func (r *PriorityRepository) GetPriorities(ctx context.Context, limit, offset ui
 	priorities := make([]dto.PriorityDTO, 0)
 	for rows.Next() {
 		var dbRow dbPriority
-		if err := rows.Scan(&dbRow.ID, &dbRow.Name, &dbRow.Rate, &dbRow.Code, &dbRow.CreatedAt, &dbRow.UpdatedAt); err != nil {
+		if err := rows.Scan(dbRow.scanTargets()...); err != nil {
 			r.logger.Error("GetPriorities (repo): ошибка при сканировании строки", zap.Error(err))
 			return nil, 0, err
 		}
@@ -119,7 +144,7 @@ func (r *PriorityRepository) GetPriorities(ctx context.Context, limit, offset ui
 func (r *PriorityRepository) FindPriority(ctx context.Context, id uint64) (*dto.PriorityDTO, error) {
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", priorityFields, priorityTable)
 	var dbRow dbPriority
-	err := r.storage.QueryRow(ctx, query, id).Scan(&dbRow.ID, &dbRow.Name, &dbRow.Rate, &dbRow.Code, &dbRow.CreatedAt, &dbRow.UpdatedAt)
+	err := r.storage.QueryRow(ctx, query, id).Scan(dbRow.scanTargets()...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
@@ -132,9 +157,9 @@ func (r *PriorityRepository) FindPriority(ctx context.Context, id uint64) (*dto.
 
 // CreatePriority: изменен INSERT и Scan
 func (r *PriorityRepository) CreatePriority(ctx context.Context, dto dto.CreatePriorityDTO) (*dto.PriorityDTO, error) {
-	query := fmt.Sprintf(`INSERT INTO %s (name, rate, code) VALUES ($1, $2, $3) RETURNING %s`, priorityTable, priorityFields)
+	query := fmt.Sprintf(`INSERT INTO %s (name, rate, code, color, sort_order, sla_hours, first_response_minutes) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING %s`, priorityTable, priorityFields)
 	var dbRow dbPriority
-	err := r.storage.QueryRow(ctx, query, dto.Name, dto.Rate, dto.Code).Scan(&dbRow.ID, &dbRow.Name, &dbRow.Rate, &dbRow.Code, &dbRow.CreatedAt, &dbRow.UpdatedAt)
+	err := r.storage.QueryRow(ctx, query, dto.Name, dto.Rate, dto.Code, dto.Color, dto.SortOrder, dto.SLAHours, dto.FirstResponseMinutes).Scan(dbRow.scanTargets()...)
 	if err != nil {
 		r.logger.Error("Ошибка при создании приоритета в БД", zap.Error(err))
 		return nil, apperrors.WrapDBError(err)
@@ -164,6 +189,27 @@ func (r *PriorityRepository) UpdatePriority(ctx context.Context, id uint64, dto
 		args = append(args, *dto.Code)
 		argId++
 	}
+	// Пустой цвет и нулевой срок сохраняются как NULL.
+	if dto.Color != nil {
+		setClauses = append(setClauses, fmt.Sprintf("color = NULLIF($%d, '')", argId))
+		args = append(args, *dto.Color)
+		argId++
+	}
+	if dto.SortOrder != nil {
+		setClauses = append(setClauses, fmt.Sprintf("sort_order = $%d", argId))
+		args = append(args, *dto.SortOrder)
+		argId++
+	}
+	if dto.SLAHours != nil {
+		setClauses = append(setClauses, fmt.Sprintf("sla_hours = NULLIF($%d::int, 0)", argId))
+		args = append(args, *dto.SLAHours)
+		argId++
+	}
+	if dto.FirstResponseMinutes != nil {
+		setClauses = append(setClauses, fmt.Sprintf("first_response_minutes = NULLIF($%d::int, 0)", argId))
+		args = append(args, *dto.FirstResponseMinutes)
+		argId++
+	}
 
 	if len(setClauses) == 0 {
 		return r.FindPriority(ctx, id)
@@ -176,7 +222,7 @@ func (r *PriorityRepository) UpdatePriority(ctx context.Context, id uint64, dto
 	args = append(args, id)
 
 	var dbRow dbPriority
-	err := r.storage.QueryRow(ctx, query, args...).Scan(&dbRow.ID, &dbRow.Name, &dbRow.Rate, &dbRow.Code, &dbRow.CreatedAt, &dbRow.UpdatedAt)
+	err := r.storage.QueryRow(ctx, query, args...).Scan(dbRow.scanTargets()...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
@@ -246,3 +292,43 @@ func (r *PriorityRepository) FindByIDInTx(ctx context.Context, tx pgx.Tx, id uin
 
 	return &priority, nil
 }
+
+func (r *PriorityRepository) FindMatrix(ctx context.Context) ([]entities.PriorityMatrixCell, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT m.impact, m.urgency, m.priority_id, p.name AS priority_name, m.updated_at
+		FROM priority_matrix m
+		JOIN priorities p ON p.id = m.priority_id
+		ORDER BY m.impact, m.urgency`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.PriorityMatrixCell])
+}
+
+func (r *PriorityRepository) ReplaceMatrix(ctx context.Context, cells []entities.PriorityMatrixCell) error {
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM priority_matrix`); err != nil {
+			return err
+		}
+		for _, cell := range cells {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO priority_matrix (impact, urgency, priority_id) VALUES ($1, $2, $3)`,
+				cell.Impact, cell.Urgency, cell.PriorityID,
+			); err != nil {
+				return apperrors.WrapDBError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PriorityRepository) FindIDByMatrix(ctx context.Context, impact, urgency string) (uint64, error) {
+	var id uint64
+	err := r.storage.QueryRow(ctx,
+		`SELECT priority_id FROM priority_matrix WHERE impact = $1 AND urgency = $2`, impact, urgency,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, apperrors.ErrNotFound
+	}
+	return id, err
+}
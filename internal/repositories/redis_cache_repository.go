@@ -33,6 +33,11 @@ func (r *RedisCacheRepository) Del(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
 
+// GetDel читает значение и удаляет ключ одной командой.
+func (r *RedisCacheRepository) GetDel(ctx context.Context, key string) (string, error) {
+	return r.client.GetDel(ctx, key).Result()
+}
+
 // Incr атомарно увеличивает значение ключа на 1.
 func (r *RedisCacheRepository) Incr(ctx context.Context, key string) (int64, error) {
 	return r.client.Incr(ctx, key).Result()
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const resolutionCodeFields = "id, code, name, sort_order, is_active, created_at, updated_at"
+
+type ResolutionCodeRepositoryInterface interface {
+	// FindAll возвращает справочник по sort_order; onlyActive оставляет только действующие коды.
+	FindAll(ctx context.Context, onlyActive bool) ([]entities.ResolutionCode, error)
+	FindByID(ctx context.Context, id uint64) (*entities.ResolutionCode, error)
+	Create(ctx context.Context, code *entities.ResolutionCode) error
+	Update(ctx context.Context, code *entities.ResolutionCode) error
+	Delete(ctx context.Context, id uint64) error
+	// Stats считает заявки, выполненные в [from, to), по кодам решения (частые первыми)
+	// и отдельно — выполненные без кода.
+	Stats(ctx context.Context, from, to time.Time) ([]entities.ResolutionCodeStat, uint64, error)
+}
+
+type ResolutionCodeRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewResolutionCodeRepository(storage *pgxpool.Pool, logger *zap.Logger) ResolutionCodeRepositoryInterface {
+	return &ResolutionCodeRepository{storage: storage, logger: logger}
+}
+
+func (r *ResolutionCodeRepository) FindAll(ctx context.Context, onlyActive bool) ([]entities.ResolutionCode, error) {
+	query := "SELECT " + resolutionCodeFields + " FROM resolution_codes"
+	if onlyActive {
+		query += " WHERE is_active"
+	}
+	query += " ORDER BY sort_order, LOWER(name)"
+
+	rows, err := r.storage.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.ResolutionCode])
+}
+
+func (r *ResolutionCodeRepository) FindByID(ctx context.Context, id uint64) (*entities.ResolutionCode, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+resolutionCodeFields+" FROM resolution_codes WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	code, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.ResolutionCode])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return code, err
+}
+
+func (r *ResolutionCodeRepository) Create(ctx context.Context, code *entities.ResolutionCode) error {
+	err := r.storage.QueryRow(ctx,
+		`INSERT INTO resolution_codes (code, name, sort_order, is_active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		code.Code, code.Name, code.SortOrder, code.IsActive,
+	).Scan(&code.ID, &code.CreatedAt, &code.UpdatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *ResolutionCodeRepository) Update(ctx context.Context, code *entities.ResolutionCode) error {
+	err := r.storage.QueryRow(ctx,
+		`UPDATE resolution_codes
+		SET code = $2, name = $3, sort_order = $4, is_active = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		code.ID, code.Code, code.Name, code.SortOrder, code.IsActive,
+	).Scan(&code.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	return apperrors.WrapDBError(err)
+}
+
+func (r *ResolutionCodeRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM resolution_codes WHERE id = $1`, id)
+	if err != nil {
+		return apperrors.WrapDBError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ResolutionCodeRepository) Stats(ctx context.Context, from, to time.Time) ([]entities.ResolutionCodeStat, uint64, error) {
+	completedIn := func(b sq.SelectBuilder) sq.SelectBuilder {
+		return b.From(orderTable + " o").
+			Where(sq.Eq{"o.deleted_at": nil}).
+			Where(sq.GtOrEq{"o.completed_at": from}).
+			Where(sq.Lt{"o.completed_at": to}).
+			Where(tenantCondition(ctx, "o.tenant_id")).
+			PlaceholderFormat(sq.Dollar)
+	}
+
+	query, args, err := completedIn(sq.Select("rc.id", "rc.code", "rc.name", "COUNT(*) AS orders")).
+		Join("resolution_codes rc ON rc.id = o.resolution_code_id").
+		GroupBy("rc.id", "rc.code", "rc.name", "rc.sort_order").
+		OrderBy("orders DESC", "rc.sort_order").
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.storage.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	stats, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.ResolutionCodeStat])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args, err = completedIn(sq.Select("COUNT(*)")).
+		Where(sq.Eq{"o.resolution_code_id": nil}).
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	var withoutCode uint64
+	if err := r.storage.QueryRow(ctx, query, args...).Scan(&withoutCode); err != nil {
+		return nil, 0, err
+	}
+	return stats, withoutCode, nil
+}
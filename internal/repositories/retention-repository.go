@@ -0,0 +1,384 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+)
+
+const retentionRuleFields = `
+	id, name, action, closed_older_than_days, is_active, created_by, created_at, updated_at`
+
+const retentionRunFields = `
+	id, dry_run, status, triggered_by, report, error, started_at, finished_at`
+
+// HistoryCompactedEvent — запись-сводка, в которую правило хранения сворачивает историю заявки.
+const HistoryCompactedEvent = "HISTORY_COMPACTED"
+
+// retentionKeptHistoryEvents — записи истории, которые сжатие не трогает: по ним считаются
+// сроки, переходы статусов и нагрузка исполнителей.
+var retentionKeptHistoryEvents = []string{
+	"CREATE", "STATUS_CHANGE", "DELEGATION", "PRIORITY_CHANGE", "DURATION_CHANGE",
+	"MARKED_DUPLICATE", "MERGED_FROM", HistoryCompactedEvent,
+}
+
+// retentionCompactableCondition — записи истории, которые сжатие сворачивает.
+var retentionCompactableCondition = "h.event_type NOT IN ('" + strings.Join(retentionKeptHistoryEvents, "', '") + "')"
+
+// retentionExpiredOrderCondition — заявка в финальном статусе, закрытая раньше $1 и не удержанная.
+const retentionExpiredOrderCondition = `
+	s.code = ANY($2)
+	AND COALESCE(o.completed_at, o.updated_at) < $1
+	AND NOT EXISTS (SELECT 1 FROM retention_holds rh WHERE rh.order_id = o.id)`
+
+// retentionActionCondition — у заявки есть что удалять по правилу.
+var retentionActionCondition = map[string]string{
+	entities.RetentionPurgeAttachments: `EXISTS (SELECT 1 FROM attachments a WHERE a.order_id = o.id)`,
+	entities.RetentionCompactHistory:   `EXISTS (SELECT 1 FROM order_history h WHERE h.order_id = o.id AND ` + retentionCompactableCondition + `)`,
+}
+
+type RetentionRepositoryInterface interface {
+	CreateRule(ctx context.Context, rule *entities.RetentionRule) error
+	UpdateRule(ctx context.Context, rule *entities.RetentionRule) error
+	DeleteRule(ctx context.Context, id uint64) error
+	FindRuleByID(ctx context.Context, id uint64) (*entities.RetentionRule, error)
+	FindRules(ctx context.Context, onlyActive bool) ([]entities.RetentionRule, error)
+
+	// SaveHold ставит заявку на удержание или меняет причину уже поставленного.
+	SaveHold(ctx context.Context, hold *entities.RetentionHold) error
+	DeleteHold(ctx context.Context, orderID uint64) error
+	FindHolds(ctx context.Context, limit, offset int) ([]entities.RetentionHold, uint64, error)
+
+	// CreateRun начинает запуск. Для настоящего запуска возвращает nil, пока идёт другой настоящий
+	// запуск, начатый не раньше staleAfter назад: удаление не должно идти в две реплики.
+	CreateRun(ctx context.Context, dryRun bool, triggeredBy *uint64, staleAfter time.Duration) (*entities.RetentionRun, error)
+	FinishRun(ctx context.Context, id uint64, status string, report json.RawMessage, errMsg *string) error
+	FindRunByID(ctx context.Context, id uint64) (*entities.RetentionRun, error)
+	FindRuns(ctx context.Context, limit, offset int) ([]entities.RetentionRun, uint64, error)
+
+	// FindExpiredOrders возвращает следующую страницу заявок (id > afterID), закрытых раньше cutoff,
+	// у которых есть что удалять по действию правила. Удержанные заявки не возвращаются.
+	FindExpiredOrders(ctx context.Context, action string, cutoff time.Time, afterID uint64, limit int) ([]uint64, error)
+	// CountHeldOrders — сколько удержанных заявок попали бы под правило.
+	CountHeldOrders(ctx context.Context, action string, cutoff time.Time) (int64, error)
+	// PreviewOrders считает, что действие удалит у заявок, ничего не меняя.
+	PreviewOrders(ctx context.Context, action string, orderIDs []uint64) ([]dto.RetentionOrderResultDTO, error)
+	// PurgeAttachmentsInTx удаляет вложения заявки и возвращает их, чтобы после фиксации удалить файлы.
+	// Записи истории о вложениях остаются без ссылки на файл; цепочку хэшей нужно пересчитать.
+	PurgeAttachmentsInTx(ctx context.Context, tx pgx.Tx, orderID uint64) ([]entities.Attachment, error)
+	// CompactHistoryInTx сворачивает записи истории, не нужные статистике, в одну запись-сводку
+	// и возвращает число свёрнутых записей. Цепочку хэшей после этого нужно пересчитать.
+	CompactHistoryInTx(ctx context.Context, tx pgx.Tx, orderID uint64) (int64, error)
+}
+
+type RetentionRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewRetentionRepository(storage *pgxpool.Pool, logger *zap.Logger) RetentionRepositoryInterface {
+	return &RetentionRepository{storage: storage, logger: logger}
+}
+
+func (r *RetentionRepository) CreateRule(ctx context.Context, rule *entities.RetentionRule) error {
+	return r.storage.QueryRow(ctx, `
+		INSERT INTO retention_rules (name, action, closed_older_than_days, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		rule.Name, rule.Action, rule.ClosedOlderThanDays, rule.IsActive, rule.CreatedBy,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *RetentionRepository) UpdateRule(ctx context.Context, rule *entities.RetentionRule) error {
+	err := r.storage.QueryRow(ctx, `
+		UPDATE retention_rules
+		SET name = $2, action = $3, closed_older_than_days = $4, is_active = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		rule.ID, rule.Name, rule.Action, rule.ClosedOlderThanDays, rule.IsActive,
+	).Scan(&rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *RetentionRepository) DeleteRule(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM retention_rules WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *RetentionRepository) FindRuleByID(ctx context.Context, id uint64) (*entities.RetentionRule, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+retentionRuleFields+" FROM retention_rules WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.RetentionRule])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return rule, err
+}
+
+func (r *RetentionRepository) FindRules(ctx context.Context, onlyActive bool) ([]entities.RetentionRule, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+retentionRuleFields+`
+		FROM retention_rules
+		WHERE is_active OR NOT $1
+		ORDER BY id`, onlyActive)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.RetentionRule])
+}
+
+func (r *RetentionRepository) SaveHold(ctx context.Context, hold *entities.RetentionHold) error {
+	err := r.storage.QueryRow(ctx, `
+		INSERT INTO retention_holds (order_id, reason, created_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (order_id) DO UPDATE SET reason = EXCLUDED.reason
+		RETURNING created_by, created_at`,
+		hold.OrderID, hold.Reason, hold.CreatedBy,
+	).Scan(&hold.CreatedBy, &hold.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *RetentionRepository) DeleteHold(ctx context.Context, orderID uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM retention_holds WHERE order_id = $1`, orderID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *RetentionRepository) FindHolds(ctx context.Context, limit, offset int) ([]entities.RetentionHold, uint64, error) {
+	var total uint64
+	if err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM retention_holds`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.RetentionHold{}, 0, nil
+	}
+	rows, err := r.storage.Query(ctx, `
+		SELECT order_id, reason, created_by, created_at
+		FROM retention_holds
+		ORDER BY created_at DESC, order_id
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	holds, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.RetentionHold])
+	return holds, total, err
+}
+
+func (r *RetentionRepository) CreateRun(ctx context.Context, dryRun bool, triggeredBy *uint64, staleAfter time.Duration) (*entities.RetentionRun, error) {
+	rows, err := r.storage.Query(ctx, `
+		INSERT INTO retention_runs (dry_run, triggered_by)
+		SELECT $1, $2
+		WHERE $1 OR NOT EXISTS (
+			SELECT 1 FROM retention_runs
+			WHERE NOT dry_run AND status = 'running' AND started_at > NOW() - ($3::int * INTERVAL '1 second')
+		)
+		RETURNING `+retentionRunFields, dryRun, triggeredBy, int(staleAfter.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	run, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.RetentionRun])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return run, err
+}
+
+func (r *RetentionRepository) FinishRun(ctx context.Context, id uint64, status string, report json.RawMessage, errMsg *string) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE retention_runs
+		SET status = $2, report = $3, error = $4, finished_at = NOW()
+		WHERE id = $1`, id, status, report, errMsg)
+	return err
+}
+
+func (r *RetentionRepository) FindRunByID(ctx context.Context, id uint64) (*entities.RetentionRun, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+retentionRunFields+" FROM retention_runs WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	run, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.RetentionRun])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return run, err
+}
+
+// FindRuns возвращает запуски без отчётов: отчёт бывает большим и отдаётся по одному запуску.
+func (r *RetentionRepository) FindRuns(ctx context.Context, limit, offset int) ([]entities.RetentionRun, uint64, error) {
+	var total uint64
+	if err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM retention_runs`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.RetentionRun{}, 0, nil
+	}
+	rows, err := r.storage.Query(ctx, `
+		SELECT id, dry_run, status, triggered_by, NULL::jsonb AS report, error, started_at, finished_at
+		FROM retention_runs
+		ORDER BY id DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	runs, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.RetentionRun])
+	return runs, total, err
+}
+
+func (r *RetentionRepository) FindExpiredOrders(ctx context.Context, action string, cutoff time.Time, afterID uint64, limit int) ([]uint64, error) {
+	condition, ok := retentionActionCondition[action]
+	if !ok {
+		return nil, fmt.Errorf("неизвестное действие правила хранения: %s", action)
+	}
+	rows, err := r.storage.Query(ctx, `
+		SELECT o.id
+		FROM orders o
+		JOIN statuses s ON s.id = o.status_id
+		WHERE `+retentionExpiredOrderCondition+` AND `+condition+` AND o.id > $3
+		ORDER BY o.id
+		LIMIT $4`, cutoff, constants.FinalStatuses, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uint64])
+}
+
+func (r *RetentionRepository) CountHeldOrders(ctx context.Context, action string, cutoff time.Time) (int64, error) {
+	condition, ok := retentionActionCondition[action]
+	if !ok {
+		return 0, fmt.Errorf("неизвестное действие правила хранения: %s", action)
+	}
+	var count int64
+	err := r.storage.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM retention_holds rh
+		JOIN orders o ON o.id = rh.order_id
+		JOIN statuses s ON s.id = o.status_id
+		WHERE s.code = ANY($2) AND COALESCE(o.completed_at, o.updated_at) < $1 AND `+condition,
+		cutoff, constants.FinalStatuses).Scan(&count)
+	return count, err
+}
+
+func (r *RetentionRepository) PreviewOrders(ctx context.Context, action string, orderIDs []uint64) ([]dto.RetentionOrderResultDTO, error) {
+	var query string
+	switch action {
+	case entities.RetentionPurgeAttachments:
+		query = `
+			SELECT order_id, COUNT(*), COALESCE(SUM(file_size), 0), 0
+			FROM attachments
+			WHERE order_id = ANY($1)
+			GROUP BY order_id
+			ORDER BY order_id`
+	case entities.RetentionCompactHistory:
+		query = `
+			SELECT order_id, 0, 0, COUNT(*)
+			FROM order_history h
+			WHERE order_id = ANY($1) AND ` + retentionCompactableCondition + `
+			GROUP BY order_id
+			ORDER BY order_id`
+	default:
+		return nil, fmt.Errorf("неизвестное действие правила хранения: %s", action)
+	}
+
+	rows, err := r.storage.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.RetentionOrderResultDTO, error) {
+		var result dto.RetentionOrderResultDTO
+		err := row.Scan(&result.OrderID, &result.Attachments, &result.Bytes, &result.HistoryRows)
+		return result, err
+	})
+}
+
+func (r *RetentionRepository) PurgeAttachmentsInTx(ctx context.Context, tx pgx.Tx, orderID uint64) ([]entities.Attachment, error) {
+	rows, err := tx.Query(ctx, `
+		DELETE FROM attachments
+		WHERE order_id = $1
+		RETURNING id, order_id, user_id, file_name, file_path, file_type, file_size, created_at`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось удалить вложения заявки %d: %w", orderID, err)
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Attachment])
+}
+
+func (r *RetentionRepository) CompactHistoryInTx(ctx context.Context, tx pgx.Tx, orderID uint64) (int64, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, event_type, created_at
+		FROM order_history h
+		WHERE order_id = $1 AND `+retentionCompactableCondition+`
+		ORDER BY id`, orderID)
+	if err != nil {
+		return 0, err
+	}
+	type compactedRow struct {
+		ID        uint64
+		EventType string
+		CreatedAt time.Time
+	}
+	folded, err := pgx.CollectRows(rows, pgx.RowToStructByPos[compactedRow])
+	if err != nil || len(folded) == 0 {
+		return 0, err
+	}
+
+	byType := make(map[string]int)
+	ids := make([]uint64, 0, len(folded)-1)
+	for i, row := range folded {
+		byType[row.EventType]++
+		if i > 0 {
+			ids = append(ids, row.ID)
+		}
+	}
+	eventTypes := make([]string, 0, len(byType))
+	for eventType := range byType {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	parts := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		parts = append(parts, fmt.Sprintf("%s — %d", eventType, byType[eventType]))
+	}
+	summary := fmt.Sprintf("История сжата по правилу хранения: %d записей (%s) с %s по %s",
+		len(folded), strings.Join(parts, ", "),
+		folded[0].CreatedAt.Format("02.01.2006"), folded[len(folded)-1].CreatedAt.Format("02.01.2006"))
+
+	// Сводка занимает место первой свёрнутой записи, чтобы остаться на своём месте в ленте.
+	if _, err := tx.Exec(ctx, `
+		UPDATE order_history
+		SET event_type = $2, old_value = NULL, new_value = NULL, comment = $3, attachment_id = NULL
+		WHERE id = $1`, folded[0].ID, HistoryCompactedEvent, summary); err != nil {
+		return 0, fmt.Errorf("не удалось записать сводку истории заявки %d: %w", orderID, err)
+	}
+	if len(ids) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM order_history WHERE id = ANY($1)`, ids); err != nil {
+			return 0, fmt.Errorf("не удалось удалить свёрнутую историю заявки %d: %w", orderID, err)
+		}
+	}
+	return int64(len(folded)), nil
+}
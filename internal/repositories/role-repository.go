@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"request-system/internal/authz"
 	"request-system/internal/entities"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
@@ -37,6 +38,10 @@ type RoleRepositoryInterface interface {
 	UpdateRoleInTx(ctx context.Context, tx pgx.Tx, role entities.Role) error
 	LinkPermissionsToRoleInTx(ctx context.Context, tx pgx.Tx, roleID uint64, permissionIDs []uint64) error
 	UnlinkAllPermissionsFromRoleInTx(ctx context.Context, tx pgx.Tx, roleID uint64) error
+	// FindPermissionConditions — условия прав роли по ID привилегии; права без условий не попадают.
+	FindPermissionConditions(ctx context.Context, roleID uint64) (map[uint64][]authz.Condition, error)
+	// SetPermissionConditionsInTx задаёт условия права роли; пустой список снимает условия.
+	SetPermissionConditionsInTx(ctx context.Context, tx pgx.Tx, roleID, permissionID uint64, conditions []authz.Condition) error
 	DeleteRole(ctx context.Context, id uint64) error
 	BeginTx(ctx context.Context) (pgx.Tx, error)
 	FindByName(ctx context.Context, tx pgx.Tx, name string) (*entities.Role, error)
@@ -254,6 +259,49 @@ func (r *RoleRepository) UnlinkAllPermissionsFromRoleInTx(ctx context.Context, t
 	return err
 }
 
+func (r *RoleRepository) FindPermissionConditions(ctx context.Context, roleID uint64) (map[uint64][]authz.Condition, error) {
+	rows, err := r.storage.Query(ctx,
+		`SELECT permission_id, conditions FROM role_permissions WHERE role_id = $1 AND conditions IS NOT NULL`, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uint64][]authz.Condition)
+	for rows.Next() {
+		var permissionID uint64
+		var conditions []authz.Condition
+		if err := rows.Scan(&permissionID, &conditions); err != nil {
+			return nil, err
+		}
+		if len(conditions) > 0 {
+			result[permissionID] = conditions
+		}
+	}
+	return result, rows.Err()
+}
+
+func (r *RoleRepository) SetPermissionConditionsInTx(ctx context.Context, tx pgx.Tx, roleID, permissionID uint64, conditions []authz.Condition) error {
+	var value interface{}
+	if len(conditions) > 0 {
+		encoded, err := json.Marshal(conditions)
+		if err != nil {
+			return err
+		}
+		value = encoded
+	}
+	result, err := tx.Exec(ctx,
+		`UPDATE role_permissions SET conditions = $3 WHERE role_id = $1 AND permission_id = $2`,
+		roleID, permissionID, value)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
 func (r *RoleRepository) DeleteRole(ctx context.Context, id uint64) error {
 	query := `DELETE FROM roles WHERE id = $1`
 	result, err := r.storage.Exec(ctx, query, id)
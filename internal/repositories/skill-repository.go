@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+type SkillRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]entities.Skill, error)
+	Create(ctx context.Context, skill *entities.Skill) error
+	Delete(ctx context.Context, id uint64) error
+
+	FindByUser(ctx context.Context, userID uint64) ([]entities.Skill, error)
+	// SetUserSkills заменяет навыки пользователя набором skillIDs.
+	SetUserSkills(ctx context.Context, userID uint64, skillIDs []uint64) error
+	FindByOrderType(ctx context.Context, orderTypeID uint64) ([]entities.Skill, error)
+	// SetOrderTypeSkills заменяет навыки, обязательные для типа заявки.
+	SetOrderTypeSkills(ctx context.Context, orderTypeID uint64, skillIDs []uint64) error
+}
+
+type SkillRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewSkillRepository(storage *pgxpool.Pool, logger *zap.Logger) SkillRepositoryInterface {
+	return &SkillRepository{storage: storage, logger: logger}
+}
+
+func (r *SkillRepository) FindAll(ctx context.Context) ([]entities.Skill, error) {
+	rows, err := r.storage.Query(ctx, `SELECT id, code, name, created_at FROM skills ORDER BY LOWER(name)`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Skill])
+}
+
+func (r *SkillRepository) Create(ctx context.Context, skill *entities.Skill) error {
+	err := r.storage.QueryRow(ctx,
+		`INSERT INTO skills (code, name) VALUES ($1, $2) RETURNING id, created_at`,
+		skill.Code, skill.Name,
+	).Scan(&skill.ID, &skill.CreatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *SkillRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM skills WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *SkillRepository) FindByUser(ctx context.Context, userID uint64) ([]entities.Skill, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT s.id, s.code, s.name, s.created_at
+		FROM user_skills us
+		JOIN skills s ON s.id = us.skill_id
+		WHERE us.user_id = $1
+		ORDER BY LOWER(s.name)`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Skill])
+}
+
+func (r *SkillRepository) SetUserSkills(ctx context.Context, userID uint64, skillIDs []uint64) error {
+	return r.replaceLinks(ctx, "user_skills", "user_id", userID, skillIDs)
+}
+
+func (r *SkillRepository) FindByOrderType(ctx context.Context, orderTypeID uint64) ([]entities.Skill, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT s.id, s.code, s.name, s.created_at
+		FROM order_type_skills ots
+		JOIN skills s ON s.id = ots.skill_id
+		WHERE ots.order_type_id = $1
+		ORDER BY LOWER(s.name)`, orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Skill])
+}
+
+func (r *SkillRepository) SetOrderTypeSkills(ctx context.Context, orderTypeID uint64, skillIDs []uint64) error {
+	return r.replaceLinks(ctx, "order_type_skills", "order_type_id", orderTypeID, skillIDs)
+}
+
+// replaceLinks — table и ownerColumn приходят только из констант этого файла.
+func (r *SkillRepository) replaceLinks(ctx context.Context, table, ownerColumn string, ownerID uint64, skillIDs []uint64) error {
+	if skillIDs == nil {
+		skillIDs = []uint64{}
+	}
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE `+ownerColumn+` = $1`, ownerID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO `+table+` (`+ownerColumn+`, skill_id) SELECT $1, UNNEST($2::bigint[]) ON CONFLICT DO NOTHING`,
+			ownerID, skillIDs,
+		)
+		return apperrors.WrapDBError(err)
+	})
+}
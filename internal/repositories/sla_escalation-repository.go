@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const slaEscalationSettingFields = "department_id, breach_threshold, window_hours, is_enabled, updated_by, updated_at"
+
+type SLAEscalationRepositoryInterface interface {
+	FindSettings(ctx context.Context) ([]entities.SLAEscalationSetting, error)
+	UpsertSetting(ctx context.Context, setting *entities.SLAEscalationSetting) error
+	DeleteSetting(ctx context.Context, departmentID uint64) error
+
+	// RecordBreaches запоминает нарушения срока, случившиеся не раньше since: открытые
+	// просроченные заявки и заявки, выполненные позже срока. Возвращает число новых записей.
+	RecordBreaches(ctx context.Context, since time.Time, limit int) (int, error)
+	// FindPendingBreaches — нарушения не раньше since, которые ещё не вошли в эскалацию
+	// по своему исполнителю.
+	FindPendingBreaches(ctx context.Context, since time.Time) ([]entities.SLABreach, error)
+	// CreateEscalation записывает эскалацию; false — такую же уже записала другая реплика.
+	CreateEscalation(ctx context.Context, escalation *entities.SLAEscalation) (bool, error)
+}
+
+type SLAEscalationRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewSLAEscalationRepository(storage *pgxpool.Pool, logger *zap.Logger) SLAEscalationRepositoryInterface {
+	return &SLAEscalationRepository{storage: storage, logger: logger}
+}
+
+func (r *SLAEscalationRepository) FindSettings(ctx context.Context) ([]entities.SLAEscalationSetting, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+slaEscalationSettingFields+" FROM sla_escalation_settings ORDER BY department_id")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.SLAEscalationSetting])
+}
+
+func (r *SLAEscalationRepository) UpsertSetting(ctx context.Context, setting *entities.SLAEscalationSetting) error {
+	query := `
+		INSERT INTO sla_escalation_settings (department_id, breach_threshold, window_hours, is_enabled, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (department_id) DO UPDATE
+		SET breach_threshold = EXCLUDED.breach_threshold, window_hours = EXCLUDED.window_hours,
+			is_enabled = EXCLUDED.is_enabled, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING updated_at`
+
+	err := r.storage.QueryRow(ctx, query,
+		setting.DepartmentID, setting.BreachThreshold, setting.WindowHours, setting.IsEnabled, setting.UpdatedBy,
+	).Scan(&setting.UpdatedAt)
+	if err != nil {
+		return apperrors.WrapDBError(err)
+	}
+	return nil
+}
+
+func (r *SLAEscalationRepository) DeleteSetting(ctx context.Context, departmentID uint64) error {
+	tag, err := r.storage.Exec(ctx, "DELETE FROM sla_escalation_settings WHERE department_id = $1", departmentID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SLAEscalationRepository) RecordBreaches(ctx context.Context, since time.Time, limit int) (int, error) {
+	query := `
+		INSERT INTO sla_breaches (order_id, duration, executor_id)
+		SELECT o.id, o.duration, o.executor_id
+		FROM orders o
+		JOIN statuses s ON s.id = o.status_id
+		WHERE o.deleted_at IS NULL
+			AND o.executor_id IS NOT NULL
+			AND o.duration < NOW() AND o.duration >= $1
+			AND s.code NOT IN ('REJECTED', 'DUPLICATE')
+			AND (s.code NOT IN ('CLOSED', 'COMPLETED') OR o.completed_at > o.duration)
+			AND NOT EXISTS (
+				SELECT 1 FROM sla_breaches b
+				WHERE b.order_id = o.id AND b.duration = o.duration
+			)
+		ORDER BY o.duration
+		LIMIT $2
+		ON CONFLICT DO NOTHING`
+
+	tag, err := r.storage.Exec(ctx, query, since, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *SLAEscalationRepository) FindPendingBreaches(ctx context.Context, since time.Time) ([]entities.SLABreach, error) {
+	query := `
+		SELECT b.order_id, o.number AS order_number, o.name AS order_name, b.duration, o.completed_at,
+			b.executor_id, COALESCE(ex.fio, '') AS executor_name,
+			ex.department_id AS executor_department_id, ex.otdel_id AS executor_otdel_id
+		FROM sla_breaches b
+		JOIN orders o ON o.id = b.order_id
+		JOIN users ex ON ex.id = b.executor_id
+		WHERE b.duration >= $1
+			AND o.deleted_at IS NULL
+			AND ex.deleted_at IS NULL
+			AND b.duration > COALESCE(
+				(SELECT MAX(e.last_breach_at) FROM sla_escalations e WHERE e.executor_id = b.executor_id),
+				'-infinity'::timestamptz)
+		ORDER BY b.executor_id, b.duration`
+
+	rows, err := r.storage.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.SLABreach])
+}
+
+func (r *SLAEscalationRepository) CreateEscalation(ctx context.Context, escalation *entities.SLAEscalation) (bool, error) {
+	query := `
+		INSERT INTO sla_escalations (executor_id, department_id, breach_count, last_breach_at, notified_ids)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (executor_id, last_breach_at) DO NOTHING
+		RETURNING id, created_at`
+
+	err := r.storage.QueryRow(ctx, query,
+		escalation.ExecutorID, escalation.DepartmentID, escalation.BreachCount, escalation.LastBreachAt, escalation.NotifiedIDs,
+	).Scan(&escalation.ID, &escalation.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+// Без payload: выгрузка может весить мегабайты, а для статуса она не нужна.
+const syncJobFields = `
+	id, source, status, dry_run, snapshot, best_effort, NULL::jsonb AS payload, cursors, progress, summary, errors, changes, deactivated, error,
+	created_at, started_at, heartbeat_at, finished_at`
+
+type SyncJobRepositoryInterface interface {
+	// Create ставит в очередь задание с полями Source, DryRun, Snapshot, BestEffort, Payload и Cursors.
+	Create(ctx context.Context, job entities.SyncJob) (*entities.SyncJob, error)
+	FindByID(ctx context.Context, id uint64) (*entities.SyncJob, error)
+	// ClaimNext берёт следующее задание в работу, если никакое другое сейчас не выполняется.
+	// Задание с heartbeat старше staleAfter считается брошенным упавшим воркером и берётся заново.
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*entities.SyncJob, error)
+	// SaveProgress сохраняет промежуточные Progress, Summary и Errors и продлевает heartbeat.
+	SaveProgress(ctx context.Context, id uint64, result entities.SyncJobResult) error
+	Finish(ctx context.Context, id uint64, status string, result entities.SyncJobResult, errMsg *string) error
+	// HasPending — есть ли у источника задание в очереди или в работе.
+	HasPending(ctx context.Context, source string) (bool, error)
+	FindCursors(ctx context.Context, source string) (map[string]string, error)
+	SaveCursors(ctx context.Context, source string, cursors map[string]string) error
+}
+
+type SyncJobRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewSyncJobRepository(storage *pgxpool.Pool, logger *zap.Logger) SyncJobRepositoryInterface {
+	return &SyncJobRepository{storage: storage, logger: logger}
+}
+
+func (r *SyncJobRepository) Create(ctx context.Context, job entities.SyncJob) (*entities.SyncJob, error) {
+	rows, err := r.storage.Query(ctx, `
+		INSERT INTO sync_jobs (source, dry_run, snapshot, best_effort, payload, cursors)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+syncJobFields, job.Source, job.DryRun, job.Snapshot, job.BestEffort, job.Payload, job.Cursors)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.SyncJob])
+}
+
+func (r *SyncJobRepository) FindByID(ctx context.Context, id uint64) (*entities.SyncJob, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+syncJobFields+" FROM sync_jobs WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	job, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.SyncJob])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return job, err
+}
+
+func (r *SyncJobRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*entities.SyncJob, error) {
+	tx, err := r.storage.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка сериализует выбор задания между репликами: синхронизации не должны идти параллельно.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('sync_jobs'))`); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE sync_jobs
+		SET status = 'running', started_at = NOW(), heartbeat_at = NOW()
+		WHERE id = (
+			SELECT id FROM sync_jobs
+			WHERE status = 'queued'
+				OR (status = 'running' AND heartbeat_at <= NOW() - ($1::int * INTERVAL '1 second'))
+			ORDER BY id
+			LIMIT 1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM sync_jobs
+			WHERE status = 'running' AND heartbeat_at > NOW() - ($1::int * INTERVAL '1 second')
+		)
+		RETURNING id, source, status, dry_run, snapshot, best_effort, payload, cursors, progress, summary, errors, changes, deactivated, error,
+			created_at, started_at, heartbeat_at, finished_at`, int(staleAfter.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	job, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.SyncJob])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, tx.Commit(ctx)
+}
+
+func (r *SyncJobRepository) SaveProgress(ctx context.Context, id uint64, result entities.SyncJobResult) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE sync_jobs
+		SET progress = $2, summary = $3, errors = $4, heartbeat_at = NOW()
+		WHERE id = $1 AND status = 'running'`, id, result.Progress, result.Summary, result.Errors)
+	return err
+}
+
+func (r *SyncJobRepository) Finish(ctx context.Context, id uint64, status string, result entities.SyncJobResult, errMsg *string) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE sync_jobs
+		SET status = $2, progress = $3, summary = $4, errors = $5, changes = $6, deactivated = $7, error = $8,
+			payload = NULL, finished_at = NOW(), heartbeat_at = NOW()
+		WHERE id = $1`, id, status, result.Progress, result.Summary, result.Errors, result.Changes, result.Deactivated, errMsg)
+	return err
+}
+
+func (r *SyncJobRepository) HasPending(ctx context.Context, source string) (bool, error) {
+	var pending bool
+	err := r.storage.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM sync_jobs WHERE source = $1 AND status IN ('queued', 'running'))`,
+		source).Scan(&pending)
+	return pending, err
+}
+
+func (r *SyncJobRepository) FindCursors(ctx context.Context, source string) (map[string]string, error) {
+	rows, err := r.storage.Query(ctx, "SELECT entity, cursor FROM sync_cursors WHERE source = $1", source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cursors := make(map[string]string)
+	for rows.Next() {
+		var entity, cursor string
+		if err := rows.Scan(&entity, &cursor); err != nil {
+			return nil, err
+		}
+		cursors[entity] = cursor
+	}
+	return cursors, rows.Err()
+}
+
+func (r *SyncJobRepository) SaveCursors(ctx context.Context, source string, cursors map[string]string) error {
+	if len(cursors) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for entity, cursor := range cursors {
+		batch.Queue(`
+			INSERT INTO sync_cursors (source, entity, cursor, updated_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (source, entity) DO UPDATE SET cursor = EXCLUDED.cursor, updated_at = NOW()`,
+			source, entity, cursor)
+	}
+	return r.storage.SendBatch(ctx, batch).Close()
+}
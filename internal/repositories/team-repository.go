@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const teamSelectQuery = `
+	SELECT t.id, t.name, t.description, t.lead_id, lead.fio AS lead_name,
+		(SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = t.id)::int AS member_count,
+		t.created_at, t.updated_at
+	FROM teams t
+	LEFT JOIN users lead ON lead.id = t.lead_id`
+
+type TeamRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]entities.Team, error)
+	FindByID(ctx context.Context, id uint64) (*entities.Team, error)
+	// Create сохраняет команду; руководитель и memberIDs сразу становятся участниками.
+	Create(ctx context.Context, team *entities.Team, memberIDs []uint64) error
+	Update(ctx context.Context, team *entities.Team) error
+	Delete(ctx context.Context, id uint64) error
+
+	FindMembers(ctx context.Context, teamID uint64) ([]entities.TeamMember, error)
+	AddMembers(ctx context.Context, teamID uint64, userIDs []uint64) error
+	RemoveMember(ctx context.Context, teamID, userID uint64) error
+	IsMember(ctx context.Context, teamID, userID uint64) (bool, error)
+}
+
+type TeamRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewTeamRepository(storage *pgxpool.Pool, logger *zap.Logger) TeamRepositoryInterface {
+	return &TeamRepository{storage: storage, logger: logger}
+}
+
+func (r *TeamRepository) FindAll(ctx context.Context) ([]entities.Team, error) {
+	rows, err := r.storage.Query(ctx, teamSelectQuery+` ORDER BY LOWER(t.name)`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.Team])
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id uint64) (*entities.Team, error) {
+	rows, err := r.storage.Query(ctx, teamSelectQuery+` WHERE t.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	team, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.Team])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *entities.Team, memberIDs []uint64) error {
+	if team.LeadID != nil {
+		memberIDs = append(memberIDs, *team.LeadID)
+	}
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO teams (name, description, lead_id)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at, updated_at`,
+			team.Name, team.Description, team.LeadID,
+		).Scan(&team.ID, &team.CreatedAt, &team.UpdatedAt)
+		if err != nil {
+			return apperrors.WrapDBError(err)
+		}
+		return addTeamMembers(ctx, tx, team.ID, memberIDs)
+	})
+}
+
+// Update меняет реквизиты команды; новый руководитель добавляется в участники.
+func (r *TeamRepository) Update(ctx context.Context, team *entities.Team) error {
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE teams SET name = $1, description = $2, lead_id = $3, updated_at = NOW()
+			WHERE id = $4`,
+			team.Name, team.Description, team.LeadID, team.ID,
+		)
+		if err != nil {
+			return apperrors.WrapDBError(err)
+		}
+		if tag.RowsAffected() == 0 {
+			return apperrors.ErrNotFound
+		}
+		if team.LeadID == nil {
+			return nil
+		}
+		return addTeamMembers(ctx, tx, team.ID, []uint64{*team.LeadID})
+	})
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM teams WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *TeamRepository) FindMembers(ctx context.Context, teamID uint64) ([]entities.TeamMember, error) {
+	rows, err := r.storage.Query(ctx, `
+		SELECT u.id AS user_id, u.fio, COALESCE(u.email, '') AS email,
+			(t.lead_id IS NOT DISTINCT FROM u.id) AS is_lead,
+			tm.created_at AS joined_at
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = $1 AND u.deleted_at IS NULL
+		ORDER BY is_lead DESC, u.fio`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.TeamMember])
+}
+
+func (r *TeamRepository) AddMembers(ctx context.Context, teamID uint64, userIDs []uint64) error {
+	return pgx.BeginFunc(ctx, r.storage, func(tx pgx.Tx) error {
+		return addTeamMembers(ctx, tx, teamID, userIDs)
+	})
+}
+
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, userID uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *TeamRepository) IsMember(ctx context.Context, teamID, userID uint64) (bool, error) {
+	var exists bool
+	err := r.storage.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM team_members WHERE team_id = $1 AND user_id = $2)`,
+		teamID, userID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func addTeamMembers(ctx context.Context, tx pgx.Tx, teamID uint64, userIDs []uint64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT $1, UNNEST($2::bigint[])
+		ON CONFLICT DO NOTHING`,
+		teamID, userIDs,
+	)
+	return apperrors.WrapDBError(err)
+}
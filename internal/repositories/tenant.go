@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"request-system/pkg/utils"
+)
+
+// tenantCondition ограничивает выборку организацией из контекста запроса. Без организации
+// в контексте (фоновые задачи, вход в систему) возвращает nil — squirrel пропускает такое условие.
+func tenantCondition(ctx context.Context, column string) sq.Sqlizer {
+	tenantID, ok := utils.GetTenantIDFromCtx(ctx)
+	if !ok {
+		return nil
+	}
+	return sq.Eq{column: tenantID}
+}
+
+// sharedTenantCondition — то же для справочников, где tenant_id IS NULL означает запись,
+// общую для всех организаций.
+func sharedTenantCondition(ctx context.Context, column string) sq.Sqlizer {
+	tenantID, ok := utils.GetTenantIDFromCtx(ctx)
+	if !ok {
+		return nil
+	}
+	return sq.Or{sq.Eq{column: nil}, sq.Eq{column: tenantID}}
+}
+
+// tenantIDArg — организация из контекста для сырых запросов: nil, если организации нет,
+// поэтому условие пишется как ($N::bigint IS NULL OR tenant_id = $N).
+func tenantIDArg(ctx context.Context) *uint64 {
+	tenantID, ok := utils.GetTenantIDFromCtx(ctx)
+	if !ok {
+		return nil
+	}
+	return &tenantID
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"request-system/pkg/utils"
+)
+
+func TestTenantScoping(t *testing.T) {
+	base := sq.Select("o.id").From("orders o")
+
+	sql, _, err := applyDashboardSecurity(context.Background(), base, nil).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sql != "SELECT o.id FROM orders o" {
+		t.Fatalf("без организации в контексте выборка не должна ограничиваться: %s", sql)
+	}
+
+	ctx := utils.WithTenantID(context.Background(), 2)
+	sql, args, err := applyDashboardSecurity(ctx, base, sq.Eq{"o.user_id": 7}).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT o.id FROM orders o WHERE o.tenant_id = ? AND o.user_id = ?"; sql != want || len(args) != 2 || args[0] != uint64(2) {
+		t.Fatalf("условие организации: %s %v", sql, args)
+	}
+
+	sql, _, err = sq.Select("d.id").From("departments d").Where(sharedTenantCondition(ctx, "d.tenant_id")).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT d.id FROM departments d WHERE (d.tenant_id IS NULL OR d.tenant_id = ?)"; sql != want {
+		t.Fatalf("общие справочники должны быть видны всем организациям: %s", sql)
+	}
+
+	sql, args, err = analyticsOrderFactsQuery(ctx, AnalyticsOrderFilter{Limit: 10}).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql, "o.tenant_id = ?") || !slices.Contains(args, any(uint64(2))) {
+		t.Fatalf("выгрузка для BI должна ограничиваться организацией: %s %v", sql, args)
+	}
+	if sql, _, _ = analyticsOrderFactsQuery(context.Background(), AnalyticsOrderFilter{Limit: 10}).ToSql(); strings.Contains(sql, "tenant_id") {
+		t.Fatalf("без организации в контексте выгрузка не должна ограничиваться: %s", sql)
+	}
+
+	if tenantIDArg(context.Background()) != nil || *tenantIDArg(ctx) != 2 {
+		t.Fatal("tenantIDArg должен отдавать организацию из контекста или nil")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
+	"request-system/pkg/utils"
 )
 
 const userTable = "users"
@@ -73,6 +74,7 @@ type UserRepositoryInterface interface {
 	FindUserByEmailOrLogin(ctx context.Context, login string) (*entities.User, error)
 	FindUserByUsername(ctx context.Context, username string) (*entities.User, error)
 	FindAnyUserByUsername(ctx context.Context, username string) (*entities.User, error)
+	FindAnyUserByID(ctx context.Context, id uint64) (*entities.User, error)
 	FindAnyUserByUsernameInTx(ctx context.Context, tx pgx.Tx, username string) (*entities.User, error)
 	FindAnyUserByEmailInTx(ctx context.Context, tx pgx.Tx, email string) (*entities.User, error)
 	FindAnyUserByPhoneInTx(ctx context.Context, tx pgx.Tx, phone string) (*entities.User, error)
@@ -91,11 +93,18 @@ type UserRepositoryInterface interface {
 
 	FindUsersByIDs(ctx context.Context, userIDs []uint64) (map[uint64]entities.User, error)
 	IsHeadExistsInDepartment(ctx context.Context, departmentID uint64, excludeUserID uint64) (bool, error)
+	// FindUnitHeads — активные руководители департамента и, если указан, его отдела.
+	FindUnitHeads(ctx context.Context, departmentID uint64, otdelID *uint64) ([]entities.User, error)
 
 	UpdateTelegramChatID(ctx context.Context, userID uint64, chatID int64) error
 	UpdateTelegramChatIDTx(ctx context.Context, tx pgx.Tx, userID uint64, chatID int64) error
 	ClearTelegramChatID(ctx context.Context, tx pgx.Tx, userID uint64) error
 	FindUserByTelegramChatID(ctx context.Context, chatID int64) (*entities.User, error)
+	// TouchTelegramLastSeen отмечает обращение к боту из чата; чаще раза в минуту не пишет.
+	TouchTelegramLastSeen(ctx context.Context, chatID int64) error
+	UpdateLanguage(ctx context.Context, userID uint64, lang string) error
+	// UpdateTimezone сохраняет часовой пояс пользователя; nil возвращает пояс сервера.
+	UpdateTimezone(ctx context.Context, userID uint64, timezone *string) error
 	FindActiveUsersByBranch(ctx context.Context, tx pgx.Tx, posType string, branchID uint64, officeID *uint64) ([]entities.User, error)
 
 	FindFirstActiveUserByPositionID(ctx context.Context, tx pgx.Tx, positionID uint64) (*entities.User, error)
@@ -129,7 +138,8 @@ func (r *UserRepository) getQuerier(tx pgx.Tx) Querier {
 	return r.storage
 }
 
-func (r *UserRepository) buildBaseSelect() sq.SelectBuilder {
+// buildBaseSelect — общий SELECT пользователей; выборка ограничена организацией из контекста.
+func (r *UserRepository) buildBaseSelect(ctx context.Context) sq.SelectBuilder {
 	return sq.Select(
 		"u.*",
 		"s.code as status_code",
@@ -146,7 +156,8 @@ func (r *UserRepository) buildBaseSelect() sq.SelectBuilder {
 		LeftJoin("branches b ON u.branch_id = b.id").
 		LeftJoin("departments d ON u.department_id = d.id").
 		LeftJoin("otdels ot ON u.otdel_id = ot.id").
-		LeftJoin("offices off ON u.office_id = off.id")
+		LeftJoin("offices off ON u.office_id = off.id").
+		Where(tenantCondition(ctx, "u.tenant_id"))
 }
 
 // FindPositionIDByStructureAndType возвращает ID должности, проверяя Тип в таблице positions
@@ -234,7 +245,8 @@ func (r *UserRepository) GetUsers(ctx context.Context, filter types.Filter) ([]e
 	countBuilder := psql.Select("count(*)").From("users u").
 		LeftJoin("statuses s ON u.status_id = s.id").
 		LeftJoin("positions p ON u.position_id = p.id").
-		Where(sq.Eq{"u.deleted_at": nil}) // Исключаем удаленных
+		Where(sq.Eq{"u.deleted_at": nil}). // Исключаем удаленных
+		Where(tenantCondition(ctx, "u.tenant_id"))
 
 	// Применяем поиск по тексту
 	countBuilder = applySearch(countBuilder)
@@ -263,7 +275,7 @@ func (r *UserRepository) GetUsers(ctx context.Context, filter types.Filter) ([]e
 
 	// --- 2. SELECT (Получаем данные) ---
 	// buildBaseSelect - это твой метод, который уже был в репозитории (с JOIN-ами)
-	selectBuilder := r.buildBaseSelect().
+	selectBuilder := r.buildBaseSelect(ctx).
 		Where(sq.Eq{"u.deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
@@ -309,15 +321,15 @@ func (r *UserRepository) CreateUser(ctx context.Context, tx pgx.Tx, u *entities.
 
 	// --- (2) Вставка самого Юзера ---
 	q := `INSERT INTO users (fio, email, phone_number, password, position_id, status_id, branch_id, 
-		department_id, office_id, otdel_id, photo_url, must_change_password, is_head, username, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW(), NOW()) RETURNING id`
+		department_id, office_id, otdel_id, photo_url, must_change_password, is_head, username, tenant_id, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW()) RETURNING id`
 
 	var id uint64
 	err := tx.QueryRow(ctx, q,
 		u.Fio, u.Email, u.PhoneNumber, u.Password, u.PositionID,
 		u.StatusID, u.BranchID, u.DepartmentID, u.OfficeID,
 		u.OtdelID,
-		u.PhotoURL, u.MustChangePassword, u.IsHead, u.Username,
+		u.PhotoURL, u.MustChangePassword, u.IsHead, u.Username, utils.TenantIDOrDefault(ctx),
 	).Scan(&id)
 
 	if err != nil {
@@ -439,6 +451,11 @@ func (r *UserRepository) FindUserByIDInTx(ctx context.Context, tx pgx.Tx, id uin
 	return r.findOneUser(ctx, tx, sq.Eq{"u.id": id, "u.deleted_at": nil})
 }
 
+// FindAnyUserByID находит пользователя и среди удалённых.
+func (r *UserRepository) FindAnyUserByID(ctx context.Context, id uint64) (*entities.User, error) {
+	return r.findOneUser(ctx, r.storage, sq.Eq{"u.id": id})
+}
+
 func (r *UserRepository) DeleteUser(ctx context.Context, id uint64) error {
 	_, err := r.storage.Exec(ctx, "UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
 	return err
@@ -639,7 +656,7 @@ func (r *UserRepository) FindUsersByIDs(ctx context.Context, userIDs []uint64) (
 	if len(userIDs) == 0 {
 		return map[uint64]entities.User{}, nil
 	}
-	q := r.buildBaseSelect().
+	q := r.buildBaseSelect(ctx).
 		Where(sq.Eq{"u.id": userIDs, "u.deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
@@ -672,8 +689,31 @@ func (r *UserRepository) IsHeadExistsInDepartment(ctx context.Context, dID, excl
 	return exists, err
 }
 
+func (r *UserRepository) FindUnitHeads(ctx context.Context, departmentID uint64, otdelID *uint64) ([]entities.User, error) {
+	unit := sq.Or{sq.Eq{"u.otdel_id": nil}}
+	if otdelID != nil {
+		unit = append(unit, sq.Eq{"u.otdel_id": *otdelID})
+	}
+	q := r.buildBaseSelect(ctx).
+		Where(sq.Eq{"u.department_id": departmentID, "u.is_head": true, "u.deleted_at": nil}).
+		Where("UPPER(s.code) = 'ACTIVE'").
+		Where(unit).
+		OrderBy("u.id").
+		PlaceholderFormat(sq.Dollar)
+
+	sqlStr, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.storage.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.User])
+}
+
 func (r *UserRepository) UpdateTelegramChatID(ctx context.Context, userID uint64, chatID int64) error {
-	tag, err := r.storage.Exec(ctx, "UPDATE users SET telegram_chat_id=$1, updated_at=NOW() WHERE id=$2", chatID, userID)
+	tag, err := r.storage.Exec(ctx, "UPDATE users SET telegram_chat_id=$1, telegram_last_seen_at=NOW(), updated_at=NOW() WHERE id=$2", chatID, userID)
 	if err == nil && tag.RowsAffected() == 0 {
 		return apperrors.ErrNotFound
 	}
@@ -681,7 +721,7 @@ func (r *UserRepository) UpdateTelegramChatID(ctx context.Context, userID uint64
 }
 
 func (r *UserRepository) UpdateTelegramChatIDTx(ctx context.Context, tx pgx.Tx, userID uint64, chatID int64) error {
-	tag, err := tx.Exec(ctx, "UPDATE users SET telegram_chat_id=$1, updated_at=NOW() WHERE id=$2", chatID, userID)
+	tag, err := tx.Exec(ctx, "UPDATE users SET telegram_chat_id=$1, telegram_last_seen_at=NOW(), updated_at=NOW() WHERE id=$2", chatID, userID)
 	if err != nil {
 		return err
 	}
@@ -692,7 +732,7 @@ func (r *UserRepository) UpdateTelegramChatIDTx(ctx context.Context, tx pgx.Tx,
 }
 
 func (r *UserRepository) ClearTelegramChatID(ctx context.Context, tx pgx.Tx, userID uint64) error {
-	tag, err := tx.Exec(ctx, "UPDATE users SET telegram_chat_id=NULL, updated_at=NOW() WHERE id=$1", userID)
+	tag, err := tx.Exec(ctx, "UPDATE users SET telegram_chat_id=NULL, telegram_last_seen_at=NULL, updated_at=NOW() WHERE id=$1", userID)
 	if err != nil {
 		return err
 	}
@@ -702,6 +742,30 @@ func (r *UserRepository) ClearTelegramChatID(ctx context.Context, tx pgx.Tx, use
 	return nil
 }
 
+func (r *UserRepository) TouchTelegramLastSeen(ctx context.Context, chatID int64) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE users SET telegram_last_seen_at = NOW()
+		WHERE telegram_chat_id = $1 AND deleted_at IS NULL
+			AND (telegram_last_seen_at IS NULL OR telegram_last_seen_at < NOW() - INTERVAL '1 minute')`, chatID)
+	return err
+}
+
+func (r *UserRepository) UpdateLanguage(ctx context.Context, userID uint64, lang string) error {
+	tag, err := r.storage.Exec(ctx, "UPDATE users SET language=$1, updated_at=NOW() WHERE id=$2", lang, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *UserRepository) UpdateTimezone(ctx context.Context, userID uint64, timezone *string) error {
+	tag, err := r.storage.Exec(ctx, "UPDATE users SET timezone=$1, updated_at=NOW() WHERE id=$2", timezone, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
 func (r *UserRepository) FindUserByTelegramChatID(ctx context.Context, chatID int64) (*entities.User, error) {
 	return r.findOneUser(ctx, r.storage, sq.Eq{"u.telegram_chat_id": chatID, "u.deleted_at": nil})
 }
@@ -898,7 +962,7 @@ func (r *UserRepository) UserExistsByOrgAndType(ctx context.Context, tx pgx.Tx,
 }
 
 func (r *UserRepository) findOneUser(ctx context.Context, querier Querier, where interface{}) (*entities.User, error) {
-	q := r.buildBaseSelect().Where(where).PlaceholderFormat(sq.Dollar)
+	q := r.buildBaseSelect(ctx).Where(where).PlaceholderFormat(sq.Dollar)
 
 	sqlStr, args, err := q.ToSql()
 	if err != nil {
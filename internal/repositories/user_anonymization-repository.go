@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// UserHistoryReferences — записи истории, в которых упоминается пользователь.
+type UserHistoryReferences struct {
+	Rows     int64
+	OrderIDs []uint64
+}
+
+// UserAnonymizationRepositoryInterface — обезличивание уволенных сотрудников. Заявки и счётчики
+// продолжают ссылаться на пользователя по id, стирается только то, что указывает на человека.
+type UserAnonymizationRepositoryInterface interface {
+	IsAnonymized(ctx context.Context, userID uint64) (bool, error)
+	FindHistoryReferences(ctx context.Context, userID uint64, fio string) (*UserHistoryReferences, error)
+	FindHistoryReferencesInTx(ctx context.Context, tx pgx.Tx, userID uint64, fio string) (*UserHistoryReferences, error)
+	RelabelHistoryInTx(ctx context.Context, tx pgx.Tx, userID uint64, fio, label string) (*UserHistoryReferences, error)
+	AnonymizeUserInTx(ctx context.Context, tx pgx.Tx, userID uint64, label string, inactiveStatusID uint64, at time.Time) error
+}
+
+type UserAnonymizationRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewUserAnonymizationRepository(storage *pgxpool.Pool, logger *zap.Logger) UserAnonymizationRepositoryInterface {
+	return &UserAnonymizationRepository{storage: storage, logger: logger}
+}
+
+// historyReferenceCondition — записи истории, относящиеся к пользователю: он автор события или
+// заявку передали ему. ФИО сравнивается только в них, чтобы не задеть однофамильцев.
+const historyReferenceCondition = `
+	(user_id = $1 OR (event_type = 'DELEGATION' AND new_value = $1::text))
+	AND (creator_fio = $2 OR delegator_fio = $2 OR executor_fio = $2 OR strpos(comment, $2) > 0)`
+
+func (r *UserAnonymizationRepository) IsAnonymized(ctx context.Context, userID uint64) (bool, error) {
+	var anonymized bool
+	err := r.storage.QueryRow(ctx, `SELECT anonymized_at IS NOT NULL FROM users WHERE id = $1`, userID).Scan(&anonymized)
+	return anonymized, err
+}
+
+func (r *UserAnonymizationRepository) FindHistoryReferences(ctx context.Context, userID uint64, fio string) (*UserHistoryReferences, error) {
+	return r.findReferences(ctx, r.storage, userID, fio)
+}
+
+func (r *UserAnonymizationRepository) FindHistoryReferencesInTx(ctx context.Context, tx pgx.Tx, userID uint64, fio string) (*UserHistoryReferences, error) {
+	return r.findReferences(ctx, tx, userID, fio)
+}
+
+func (r *UserAnonymizationRepository) findReferences(ctx context.Context, q Querier, userID uint64, fio string) (*UserHistoryReferences, error) {
+	refs := &UserHistoryReferences{OrderIDs: []uint64{}}
+	if fio == "" {
+		return refs, nil
+	}
+	rows, err := q.Query(ctx, `
+		SELECT order_id, COUNT(*)
+		FROM order_history
+		WHERE `+historyReferenceCondition+`
+		GROUP BY order_id
+		ORDER BY order_id`, userID, fio)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var orderID uint64
+		var count int64
+		if err := rows.Scan(&orderID, &count); err != nil {
+			return nil, err
+		}
+		refs.OrderIDs = append(refs.OrderIDs, orderID)
+		refs.Rows += count
+	}
+	return refs, rows.Err()
+}
+
+// RelabelHistoryInTx заменяет ФИО пользователя на label в ФИО-полях и комментариях его записей истории.
+// Цепочки хэшей затронутых заявок после этого нужно пересчитать.
+func (r *UserAnonymizationRepository) RelabelHistoryInTx(ctx context.Context, tx pgx.Tx, userID uint64, fio, label string) (*UserHistoryReferences, error) {
+	refs, err := r.findReferences(ctx, tx, userID, fio)
+	if err != nil || refs.Rows == 0 {
+		return refs, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE order_history SET
+			creator_fio   = CASE WHEN creator_fio = $2 THEN $3 ELSE creator_fio END,
+			delegator_fio = CASE WHEN delegator_fio = $2 THEN $3 ELSE delegator_fio END,
+			executor_fio  = CASE WHEN executor_fio = $2 THEN $3 ELSE executor_fio END,
+			comment       = replace(comment, $2, $3)
+		WHERE `+historyReferenceCondition, userID, fio, label)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось обезличить историю: %w", err)
+	}
+	return refs, nil
+}
+
+// AnonymizeUserInTx стирает личные данные пользователя и отзывает доступ. Оргструктура и должности
+// остаются — по ним строится статистика. external_id тоже стирается, иначе следующая выгрузка 1С
+// вернула бы ФИО и контакты.
+func (r *UserAnonymizationRepository) AnonymizeUserInTx(ctx context.Context, tx pgx.Tx, userID uint64, label string, inactiveStatusID uint64, at time.Time) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE users SET
+			fio = $2,
+			email = 'anonymized_' || id || '@anonymized.local',
+			phone_number = 'ANON' || id,
+			username = NULL,
+			password = '',
+			photo_url = NULL,
+			telegram_chat_id = NULL,
+			telegram_last_seen_at = NULL,
+			external_id = NULL,
+			source_system = NULL,
+			status_id = $3,
+			must_change_password = false,
+			anonymized_at = $4,
+			updated_at = $4
+		WHERE id = $1`, userID, label, inactiveStatusID, at)
+	if err != nil {
+		return fmt.Errorf("не удалось обезличить пользователя: %w", err)
+	}
+
+	for _, table := range []string{"user_roles", "user_permissions", "user_permission_denials", "user_calendar_tokens"} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", table), userID); err != nil {
+			return fmt.Errorf("не удалось отозвать доступ (%s): %w", table, err)
+		}
+	}
+	return nil
+}
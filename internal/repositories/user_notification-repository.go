@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const userNotificationFields = `id, user_id, event_id::text AS event_id, order_id, type, payload, is_read, read_at, created_at`
+
+type UserNotificationRepositoryInterface interface {
+	Create(ctx context.Context, n *entities.UserNotification) error
+	FindByUser(ctx context.Context, userID uint64, unreadOnly bool, limit, offset int) ([]entities.UserNotification, uint64, error)
+	CountUnread(ctx context.Context, userID uint64) (uint64, error)
+	MarkRead(ctx context.Context, userID, id uint64) error
+	MarkAllRead(ctx context.Context, userID uint64) (int64, error)
+}
+
+type UserNotificationRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewUserNotificationRepository(storage *pgxpool.Pool, logger *zap.Logger) UserNotificationRepositoryInterface {
+	return &UserNotificationRepository{storage: storage, logger: logger}
+}
+
+func (r *UserNotificationRepository) Create(ctx context.Context, n *entities.UserNotification) error {
+	query := `
+		INSERT INTO user_notifications (user_id, event_id, order_id, type, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, is_read, created_at`
+
+	return r.storage.QueryRow(ctx, query, n.UserID, n.EventID, n.OrderID, n.Type, n.Payload).
+		Scan(&n.ID, &n.IsRead, &n.CreatedAt)
+}
+
+func (r *UserNotificationRepository) FindByUser(ctx context.Context, userID uint64, unreadOnly bool, limit, offset int) ([]entities.UserNotification, uint64, error) {
+	where := "WHERE user_id = $1"
+	if unreadOnly {
+		where += " AND is_read = FALSE"
+	}
+
+	var total uint64
+	if err := r.storage.QueryRow(ctx, "SELECT COUNT(*) FROM user_notifications "+where, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.UserNotification{}, 0, nil
+	}
+
+	query := "SELECT " + userNotificationFields + " FROM user_notifications " + where +
+		" ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3"
+	rows, err := r.storage.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.UserNotification])
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *UserNotificationRepository) CountUnread(ctx context.Context, userID uint64) (uint64, error) {
+	var count uint64
+	err := r.storage.QueryRow(ctx,
+		`SELECT COUNT(*) FROM user_notifications WHERE user_id = $1 AND is_read = FALSE`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead отмечает уведомление прочитанным. Чужое или несуществующее уведомление — ErrNotFound.
+func (r *UserNotificationRepository) MarkRead(ctx context.Context, userID, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `
+		UPDATE user_notifications
+		SET is_read = TRUE, read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2`, id, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *UserNotificationRepository) MarkAllRead(ctx context.Context, userID uint64) (int64, error) {
+	tag, err := r.storage.Exec(ctx, `
+		UPDATE user_notifications
+		SET is_read = TRUE, read_at = NOW()
+		WHERE user_id = $1 AND is_read = FALSE`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
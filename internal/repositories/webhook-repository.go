@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const webhookSubscriptionFields = `
+	id, name, url, secret, event_types, is_active, created_by, created_at, updated_at`
+
+const webhookDeliveryFields = `
+	id, subscription_id, event_id, event_type, payload, status, attempts, max_attempts,
+	next_attempt_at, last_status_code, last_error, created_at, updated_at, delivered_at`
+
+const webhookAttemptFields = `
+	id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at`
+
+type WebhookRepositoryInterface interface {
+	CreateSubscription(ctx context.Context, sub *entities.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, sub *entities.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id uint64) error
+	FindSubscriptionByID(ctx context.Context, id uint64) (*entities.WebhookSubscription, error)
+	FindSubscriptions(ctx context.Context, limit, offset int) ([]entities.WebhookSubscription, uint64, error)
+
+	// EnqueueForEvent создаёт доставки для всех активных подписок на eventType.
+	// Повторная постановка того же eventID игнорируется.
+	EnqueueForEvent(ctx context.Context, eventID uuid.UUID, eventType string, payload json.RawMessage, maxAttempts int) (int64, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id uint64, statusCode int) error
+	MarkRetry(ctx context.Context, id uint64, nextAttemptAt time.Time, statusCode *int, lastError string) error
+	MarkFailed(ctx context.Context, id uint64, statusCode *int, lastError string) error
+	Redeliver(ctx context.Context, id uint64) error
+	FindDeliveryByID(ctx context.Context, id uint64) (*entities.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, subscriptionID uint64, status string, limit, offset int) ([]entities.WebhookDelivery, uint64, error)
+
+	CreateAttempt(ctx context.Context, attempt *entities.WebhookDeliveryAttempt) error
+	FindAttempts(ctx context.Context, deliveryID uint64) ([]entities.WebhookDeliveryAttempt, error)
+}
+
+type WebhookRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewWebhookRepository(storage *pgxpool.Pool, logger *zap.Logger) WebhookRepositoryInterface {
+	return &WebhookRepository{storage: storage, logger: logger}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *entities.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (name, url, secret, event_types, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	return r.storage.QueryRow(ctx, query,
+		sub.Name, sub.URL, sub.Secret, sub.EventTypes, sub.IsActive, sub.CreatedBy,
+	).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, sub *entities.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET name = $2, url = $3, secret = $4, event_types = $5, is_active = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.storage.QueryRow(ctx, query,
+		sub.ID, sub.Name, sub.URL, sub.Secret, sub.EventTypes, sub.IsActive,
+	).Scan(&sub.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *WebhookRepository) FindSubscriptionByID(ctx context.Context, id uint64) (*entities.WebhookSubscription, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+webhookSubscriptionFields+" FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.WebhookSubscription])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return sub, err
+}
+
+func (r *WebhookRepository) FindSubscriptions(ctx context.Context, limit, offset int) ([]entities.WebhookSubscription, uint64, error) {
+	var total uint64
+	if err := r.storage.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_subscriptions`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.WebhookSubscription{}, 0, nil
+	}
+
+	query := "SELECT " + webhookSubscriptionFields + `
+		FROM webhook_subscriptions
+		ORDER BY id
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.storage.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	subs, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.WebhookSubscription])
+	if err != nil {
+		return nil, 0, err
+	}
+	return subs, total, nil
+}
+
+func (r *WebhookRepository) EnqueueForEvent(ctx context.Context, eventID uuid.UUID, eventType string, payload json.RawMessage, maxAttempts int) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, max_attempts)
+		SELECT id, $1, $2::text, $3, $4
+		FROM webhook_subscriptions
+		WHERE is_active AND $2::text = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	tag, err := r.storage.Exec(ctx, query, eventID, eventType, payload, maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'processing',
+			attempts = attempts + 1,
+			next_attempt_at = NOW() + ($2::int * INTERVAL '1 second'),
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status IN ('pending', 'processing') AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryFields
+
+	rows, err := r.storage.Query(ctx, query, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.WebhookDelivery])
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id uint64, statusCode int) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', last_status_code = $2, last_error = NULL, delivered_at = NOW(), updated_at = NOW()
+		WHERE id = $1`, id, statusCode)
+	return err
+}
+
+func (r *WebhookRepository) MarkRetry(ctx context.Context, id uint64, nextAttemptAt time.Time, statusCode *int, lastError string) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'pending', next_attempt_at = $2, last_status_code = $3, last_error = $4, updated_at = NOW()
+		WHERE id = $1`, id, nextAttemptAt, statusCode, lastError)
+	return err
+}
+
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id uint64, statusCode *int, lastError string) error {
+	_, err := r.storage.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'failed', last_status_code = $2, last_error = $3, updated_at = NOW()
+		WHERE id = $1`, id, statusCode, lastError)
+	return err
+}
+
+// Redeliver ставит доставку в очередь заново (в том числе уже доставленную) с обнулённым счётчиком попыток.
+func (r *WebhookRepository) Redeliver(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status IN ('delivered', 'failed')`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
+
+func (r *WebhookRepository) FindDeliveryByID(ctx context.Context, id uint64) (*entities.WebhookDelivery, error) {
+	rows, err := r.storage.Query(ctx, "SELECT "+webhookDeliveryFields+" FROM webhook_deliveries WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[entities.WebhookDelivery])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperrors.ErrNotFound
+	}
+	return delivery, err
+}
+
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, subscriptionID uint64, status string, limit, offset int) ([]entities.WebhookDelivery, uint64, error) {
+	where := " WHERE subscription_id = $1 AND ($2::text = '' OR status = $2::text)"
+
+	var total uint64
+	if err := r.storage.QueryRow(ctx, "SELECT COUNT(*) FROM webhook_deliveries"+where, subscriptionID, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []entities.WebhookDelivery{}, 0, nil
+	}
+
+	query := "SELECT " + webhookDeliveryFields + " FROM webhook_deliveries" + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.storage.Query(ctx, query, subscriptionID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[entities.WebhookDelivery])
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *WebhookRepository) CreateAttempt(ctx context.Context, attempt *entities.WebhookDeliveryAttempt) error {
+	query := `
+		INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, response_body, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return r.storage.QueryRow(ctx, query,
+		attempt.DeliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.ResponseBody, attempt.DurationMs,
+	).Scan(&attempt.ID, &attempt.CreatedAt)
+}
+
+func (r *WebhookRepository) FindAttempts(ctx context.Context, deliveryID uint64) ([]entities.WebhookDeliveryAttempt, error) {
+	rows, err := r.storage.Query(ctx,
+		"SELECT "+webhookAttemptFields+" FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY id", deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.WebhookDeliveryAttempt])
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+// UserOnDutyCondition — SQL-условие для пользователя с алиасом u: сейчас он не в отпуске,
+// не на больничном и не в выходном, а если на ближайшие сутки у него расписаны смены —
+// одна из них идёт прямо сейчас. Сотрудник без смен в календаре считается работающим.
+const UserOnDutyCondition = `(
+	NOT EXISTS (
+		SELECT 1 FROM user_work_calendar wc
+		WHERE wc.user_id = u.id AND wc.kind <> 'SHIFT'
+		  AND wc.starts_at <= NOW() AND wc.ends_at > NOW()
+	)
+	AND (
+		EXISTS (
+			SELECT 1 FROM user_work_calendar wc
+			WHERE wc.user_id = u.id AND wc.kind = 'SHIFT'
+			  AND wc.starts_at <= NOW() AND wc.ends_at > NOW()
+		)
+		OR NOT EXISTS (
+			SELECT 1 FROM user_work_calendar wc
+			WHERE wc.user_id = u.id AND wc.kind = 'SHIFT'
+			  AND wc.starts_at < NOW() + INTERVAL '1 day' AND wc.ends_at > NOW() - INTERVAL '1 day'
+		)
+	)
+)`
+
+const workCalendarSelectQuery = `
+	SELECT wc.id, wc.user_id, u.fio AS user_fio, wc.kind, wc.starts_at, wc.ends_at,
+		wc.comment, wc.created_by, wc.created_at
+	FROM user_work_calendar wc
+	JOIN users u ON u.id = wc.user_id`
+
+type WorkCalendarRepositoryInterface interface {
+	// FindByUsers возвращает записи пользователей, пересекающиеся с периодом [from, to).
+	FindByUsers(ctx context.Context, userIDs []uint64, from, to time.Time) ([]entities.WorkCalendarEntry, error)
+	FindByID(ctx context.Context, id uint64) (*entities.WorkCalendarEntry, error)
+	Create(ctx context.Context, entry *entities.WorkCalendarEntry) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+type WorkCalendarRepository struct {
+	storage *pgxpool.Pool
+	logger  *zap.Logger
+}
+
+func NewWorkCalendarRepository(storage *pgxpool.Pool, logger *zap.Logger) WorkCalendarRepositoryInterface {
+	return &WorkCalendarRepository{storage: storage, logger: logger}
+}
+
+func (r *WorkCalendarRepository) FindByUsers(ctx context.Context, userIDs []uint64, from, to time.Time) ([]entities.WorkCalendarEntry, error) {
+	if len(userIDs) == 0 {
+		return []entities.WorkCalendarEntry{}, nil
+	}
+	rows, err := r.storage.Query(ctx, workCalendarSelectQuery+`
+		WHERE wc.user_id = ANY($1) AND wc.starts_at < $3 AND wc.ends_at > $2
+		ORDER BY wc.starts_at, LOWER(u.fio)`, userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[entities.WorkCalendarEntry])
+}
+
+func (r *WorkCalendarRepository) FindByID(ctx context.Context, id uint64) (*entities.WorkCalendarEntry, error) {
+	rows, err := r.storage.Query(ctx, workCalendarSelectQuery+` WHERE wc.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[entities.WorkCalendarEntry])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *WorkCalendarRepository) Create(ctx context.Context, entry *entities.WorkCalendarEntry) error {
+	err := r.storage.QueryRow(ctx, `
+		INSERT INTO user_work_calendar (user_id, kind, starts_at, ends_at, comment, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		entry.UserID, entry.Kind, entry.StartsAt, entry.EndsAt, entry.Comment, entry.CreatedBy,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	return apperrors.WrapDBError(err)
+}
+
+func (r *WorkCalendarRepository) Delete(ctx context.Context, id uint64) error {
+	tag, err := r.storage.Exec(ctx, `DELETE FROM user_work_calendar WHERE id = $1`, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return err
+}
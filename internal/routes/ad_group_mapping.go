@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runADGroupMappingRouter(
+	secureGroup *echo.Group,
+	adGroupSyncService services.ADGroupSyncServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewADGroupMappingController(adGroupSyncService, logger)
+
+	mappings := secureGroup.Group("/ad-group-mappings")
+	{
+		mappings.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.RolesView))
+		mappings.POST("", ctrl.Create, authMW.AuthorizeAny(authz.RolesUpdate))
+		mappings.DELETE("/:id", ctrl.Delete, authMW.AuthorizeAny(authz.RolesUpdate))
+		mappings.POST("/sync", ctrl.Sync, authMW.AuthorizeAny(authz.IntegrationsSyncRun))
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+// runAnalyticsRouter подключает выгрузку для BI к /api без общей JWT-группы: BI-системы
+// ходят с ключом из ANALYTICS_API_KEYS в заголовке X-API-Key, пользователи — с обычным токеном.
+func runAnalyticsRouter(
+	api *echo.Group,
+	analyticsService services.AnalyticsServiceInterface,
+	apiKeys []string,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	analyticsCtrl := controllers.NewAnalyticsController(analyticsService, logger)
+
+	analyticsGroup := api.Group("/analytics", authMW.APIKeyOrAuth(apiKeys, authz.AnalyticsRead))
+	analyticsGroup.GET("/orders", analyticsCtrl.GetOrderFacts, authMW.AuthorizeAny(authz.AnalyticsRead))
+}
@@ -21,15 +21,17 @@ func runAttachmentRouter(
 	authMW *middleware.AuthMiddleware,
 ) {
 	attachmentRepo := repositories.NewAttachmentRepository(dbConn)
-	orderRepo := repositories.NewOrderRepository(dbConn, logger)
+	orderRepo := repositories.NewOrderRepository(dbConn, dbConn, logger)
 	userRepo := repositories.NewUserRepository(dbConn, logger)
 	historyRepo := repositories.NewOrderHistoryRepository(dbConn, logger)
+	grantRepo := repositories.NewOrderAccessGrantRepository(dbConn, logger)
 
 	attachmentService := services.NewAttachmentService(
 		attachmentRepo,
 		orderRepo,
 		userRepo,
 		historyRepo,
+		grantRepo,
 		fileStorage,
 		logger,
 	)
@@ -0,0 +1,231 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/entities"
+	"request-system/internal/services"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/middleware"
+	"request-system/pkg/utils"
+)
+
+// Максимум ответа, который буферизуется для определения ID созданной сущности.
+const auditResponseCaptureLimit = 64 * 1024
+
+// auditSkippedPaths — изменяющие вызовы, которые не являются изменением данных системы.
+var auditSkippedPaths = map[string]bool{
+	"/api/notifications/:id/read": true,
+	"/api/notifications/read-all": true,
+	// Перечитывание настроек пишет в журнал сам, с изменившимися значениями
+	"/api/admin/config/reload": true,
+	// Обезличивание пишет в журнал само — снимок пользователя «до» сохранил бы стираемые данные
+	"/api/admin/users/:id/anonymize": true,
+	// Билет WebSocket ничего не меняет, а ответ с ним не должен попасть в журнал
+	"/api/ws-ticket": true,
+}
+
+// auditRoute задаёт действие, сущность и параметр с ID для маршрутов, которые
+// не укладываются в общее правило «метод + первый сегмент пути».
+type auditRoute struct {
+	action  string
+	entity  string
+	idParam string
+}
+
+var auditRouteOverrides = map[string]auditRoute{
+	"/api/admin/impersonate/:userID": {action: entities.AuditActionImpersonate, entity: "users", idParam: "userID"},
+}
+
+func runAuditRouter(
+	secureGroup *echo.Group,
+	auditService services.AuditServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	auditCtrl := controllers.NewAuditController(auditService, logger)
+
+	secureGroup.GET("/audit", auditCtrl.GetAuditLog, authMW.AuthorizeAny(authz.AuditView))
+}
+
+// auditBodyWriter дублирует начало ответа в буфер, не мешая отправке клиенту.
+type auditBodyWriter struct {
+	http.ResponseWriter
+	buf *bytes.Buffer
+}
+
+func (w *auditBodyWriter) Write(b []byte) (int, error) {
+	if remaining := auditResponseCaptureLimit - w.buf.Len(); remaining > 0 {
+		w.buf.Write(b[:min(len(b), remaining)])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditBodyWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// auditMiddleware пишет в audit_log каждый успешный POST/PUT/PATCH/DELETE защищённого API.
+// Сущность — первый сегмент пути после /api, ID — параметр :id или поле id в ответе.
+// Для сущностей из services.AuditedEntities сохраняются снимки строки до и после вызова.
+// Под токеном имперсонации пишется каждый запрос, включая чтение и неуспешные вызовы,
+// с impersonator_id администратора.
+// Должен подключаться к группе до регистрации маршрутов, чтобы c.Path() уже был известен.
+func auditMiddleware(auditService services.AuditServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			action := auditActionForMethod(c.Request().Method)
+			impersonatorID, impersonating := utils.GetImpersonatorIDFromCtx(c.Request().Context())
+			if action == "" && impersonating {
+				action = entities.AuditActionRead
+			}
+			if action == "" || (auditSkippedPaths[c.Path()] && !impersonating) {
+				return next(c)
+			}
+
+			entity := auditEntityFromPath(c.Path())
+			idParam := "id"
+			if route, ok := auditRouteOverrides[c.Path()]; ok {
+				action, entity, idParam = route.action, route.entity, route.idParam
+			}
+			_, snapshotted := services.AuditedEntities[entity]
+			if action == entities.AuditActionRead || action == entities.AuditActionImpersonate {
+				snapshotted = false
+			}
+			entityID, hasID := parseAuditEntityID(c.Param(idParam))
+
+			var before json.RawMessage
+			if snapshotted && hasID && action != entities.AuditActionCreate {
+				before = auditService.Snapshot(c.Request().Context(), entity, entityID)
+			}
+
+			capture := &auditBodyWriter{ResponseWriter: c.Response().Writer, buf: &bytes.Buffer{}}
+			c.Response().Writer = capture
+			err := next(c)
+			c.Response().Writer = capture.ResponseWriter
+
+			status := c.Response().Status
+			if err != nil || status >= http.StatusBadRequest {
+				if !impersonating {
+					return err
+				}
+				if err != nil {
+					status = auditErrorStatus(err)
+				}
+			}
+
+			if !hasID && action == entities.AuditActionCreate {
+				entityID, hasID = auditIDFromResponse(capture.buf.Bytes())
+			}
+
+			// Вложенный POST (например, комментарий к заявке) меняет существующую сущность.
+			if action == entities.AuditActionCreate && c.Param("id") != "" {
+				action = entities.AuditActionUpdate
+			}
+
+			entry := &entities.AuditLogEntry{
+				Action:     action,
+				Entity:     entity,
+				Method:     c.Request().Method,
+				Path:       c.Request().URL.Path,
+				StatusCode: status,
+				Before:     before,
+			}
+			if userID, err := utils.GetUserIDFromCtx(c.Request().Context()); err == nil {
+				entry.ActorID = &userID
+			}
+			if impersonating {
+				entry.ImpersonatorID = &impersonatorID
+			}
+			if hasID {
+				id := strconv.FormatUint(entityID, 10)
+				entry.EntityID = &id
+				if snapshotted && action != entities.AuditActionDelete {
+					entry.After = auditService.Snapshot(c.Request().Context(), entity, entityID)
+				}
+			}
+			if ip := c.RealIP(); ip != "" {
+				entry.IP = &ip
+			}
+			if ua := c.Request().UserAgent(); ua != "" {
+				ua = ua[:min(len(ua), 512)]
+				entry.UserAgent = &ua
+			}
+			if requestID := c.Response().Header().Get(echo.HeaderXRequestID); requestID != "" {
+				entry.RequestID = &requestID
+			}
+
+			auditService.Record(c.Request().Context(), entry)
+			return err
+		}
+	}
+}
+
+// auditErrorStatus — код ответа для ошибки, которую ещё не обработал echo.
+func auditErrorStatus(err error) int {
+	var httpErr *apperrors.HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	var echoErr *echo.HTTPError
+	if errors.As(err, &echoErr) {
+		return echoErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+func auditActionForMethod(method string) string {
+	switch method {
+	case http.MethodPost:
+		return entities.AuditActionCreate
+	case http.MethodPut, http.MethodPatch:
+		return entities.AuditActionUpdate
+	case http.MethodDelete:
+		return entities.AuditActionDelete
+	default:
+		return ""
+	}
+}
+
+// auditEntityFromPath: "/api/order_rule/:id" -> "order_rule".
+func auditEntityFromPath(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/api"), "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+func parseAuditEntityID(raw string) (uint64, bool) {
+	if raw == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	return id, err == nil
+}
+
+// auditIDFromResponse достаёт body.id из стандартного ответа utils.SuccessResponse.
+func auditIDFromResponse(raw []byte) (uint64, bool) {
+	var response struct {
+		Body struct {
+			ID json.Number `json:"id"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil || response.Body.ID == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(response.Body.ID.String(), 10, 64)
+	return id, err == nil
+}
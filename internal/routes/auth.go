@@ -7,6 +7,7 @@ import (
 	"request-system/pkg/config"
 	"request-system/pkg/filestorage"
 	"request-system/pkg/middleware"
+	"request-system/pkg/ratelimit"
 	"request-system/pkg/service"
 	"request-system/pkg/telegram"
 
@@ -27,6 +28,9 @@ func runAuthRouter(
 	fileStorage filestorage.FileStorageInterface,
 	authPermissionService services.AuthPermissionServiceInterface,
 	cfg *config.Config,
+	limiter *ratelimit.Limiter,
+	adService services.ADServiceInterface,
+	adGroupSyncService services.ADGroupSyncServiceInterface,
 
 	positionService services.PositionServiceInterface,
 	branchService services.BranchServiceInterface,
@@ -38,7 +42,7 @@ func runAuthRouter(
 	cacheRepository := repositories.NewRedisCacheRepository(redisClient)
 
 	// 2. Включаем реализацию через Telegram
-	tgService := telegram.NewService(cfg.Telegram.BotToken)
+	tgService := telegram.NewService(cfg.Telegram.BotToken, cfg.Telegram.Send)
 	notificationService := services.NewTelegramNotificationService(tgService, logger)
 	txManager := repositories.NewTxManager(dbConn, logger)
 
@@ -51,6 +55,8 @@ func runAuthRouter(
 		&cfg.Auth,
 		&cfg.LDAP,
 		notificationService,
+		adService,
+		adGroupSyncService,
 		positionService,
 		branchService,
 		departmentService,
@@ -66,12 +72,20 @@ func runAuthRouter(
 		logger,
 	)
 
+	// Вход и восстановление пароля — строгий лимит по IP против перебора.
+	strictLimit := middleware.RateLimit(limiter, middleware.RateLimitPolicy{
+		Name: "auth", Rules: authRateRule(cfg.RateLimit),
+	}, logger)
+	apiLimit := middleware.RateLimit(limiter, middleware.RateLimitPolicy{
+		Name: "api", Rules: apiRateRules(cfg.RateLimit),
+	}, logger)
+
 	authGroup := api.Group("/auth")
-	secureAuthGroup := authGroup.Group("", authMW.Auth)
-	authGroup.POST("/login", authCtrl.Login)
-	authGroup.POST("/refresh_token", authCtrl.RefreshToken)
+	secureAuthGroup := authGroup.Group("", authMW.Auth, apiLimit)
+	authGroup.POST("/login", authCtrl.Login, strictLimit)
+	authGroup.POST("/refresh_token", authCtrl.RefreshToken, strictLimit)
 
-	passwordGroup := authGroup.Group("/password")
+	passwordGroup := authGroup.Group("/password", strictLimit)
 	passwordGroup.POST("/request", authCtrl.RequestPasswordReset)
 	passwordGroup.POST("/verify_phone", authCtrl.VerifyCode)
 	passwordGroup.POST("/reset", authCtrl.ResetPassword)
@@ -79,4 +93,5 @@ func runAuthRouter(
 	secureAuthGroup.GET("/me", authCtrl.Me)
 	secureAuthGroup.POST("/logout", authCtrl.Logout)
 	secureAuthGroup.PUT("/me", authCtrl.UpdateMe, authMW.Auth)
+	api.POST("/me/avatar", authCtrl.UploadAvatar, authMW.Auth, apiLimit)
 }
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runBackgroundJobRouter(
+	secureGroup *echo.Group,
+	jobService services.BackgroundJobServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	jobCtrl := controllers.NewBackgroundJobController(jobService, logger)
+
+	adminJobs := secureGroup.Group("/admin/jobs")
+	{
+		adminJobs.GET("", jobCtrl.List, authMW.AuthorizeAny(authz.JobsManage))
+		adminJobs.POST("/:id/retry", jobCtrl.Retry, authMW.AuthorizeAny(authz.JobsManage))
+	}
+}
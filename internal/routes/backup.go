@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runBackupRouter(
+	secureGroup *echo.Group,
+	backupService services.BackupServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewBackupController(backupService, logger)
+
+	backups := secureGroup.Group("/admin/backups")
+	{
+		backups.GET("", ctrl.GetBackups, authMW.AuthorizeAny(authz.BackupManage))
+		backups.GET("/:id", ctrl.GetBackup, authMW.AuthorizeAny(authz.BackupManage))
+		backups.POST("", ctrl.Trigger, authMW.AuthorizeAny(authz.BackupManage))
+		backups.POST("/verify", ctrl.Verify, authMW.AuthorizeAny(authz.BackupManage))
+	}
+}
@@ -25,11 +25,12 @@ func runBranchRouter(
 	branchService := services.NewBranchService(txManager, branchRepository, userRepository, logger)
 
 	branchCtrl := controllers.NewBranchController(branchService, logger)
+	notModified := conditionalDictionary(repositories.NewDictionaryVersionRepository(dbConn, logger), logger, "branches", "statuses")
 
 	branches := secureGroup.Group("/branch")
 
-	branches.GET("", branchCtrl.GetBranches, authMW.AuthorizeAny(authz.BranchesView))
-	branches.GET("/:id", branchCtrl.FindBranch, authMW.AuthorizeAny(authz.BranchesView))
+	branches.GET("", branchCtrl.GetBranches, authMW.AuthorizeAny(authz.BranchesView), notModified)
+	branches.GET("/:id", branchCtrl.FindBranch, authMW.AuthorizeAny(authz.BranchesView), notModified)
 	branches.POST("", branchCtrl.CreateBranch, authMW.AuthorizeAny(authz.BranchesCreate))
 	branches.PUT("/:id", branchCtrl.UpdateBranch, authMW.AuthorizeAny(authz.BranchesUpdate))
 	branches.DELETE("/:id", branchCtrl.DeleteBranch, authMW.AuthorizeAny(authz.BranchesDelete))
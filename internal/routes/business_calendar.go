@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runBusinessCalendarRouter(
+	secureGroup *echo.Group,
+	businessCalendarService services.BusinessCalendarServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewBusinessCalendarController(businessCalendarService, logger)
+
+	calendar := secureGroup.Group("/admin/business-calendar")
+	{
+		calendar.GET("", ctrl.GetCalendar, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+		calendar.PUT("", ctrl.UpdateCalendar, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+		calendar.GET("/holidays", ctrl.GetHolidays, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+		calendar.POST("/holidays", ctrl.CreateHoliday, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+		calendar.PUT("/holidays/:id", ctrl.UpdateHoliday, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+		calendar.DELETE("/holidays/:id", ctrl.DeleteHoliday, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+		calendar.GET("/measure", ctrl.Measure, authMW.AuthorizeAny(authz.BusinessCalendarManage))
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/config"
+	"request-system/pkg/middleware"
+	"request-system/pkg/ratelimit"
+)
+
+// runCalendarFeedRouter — управление ссылкой идёт под JWT, сам .ics отдаётся по токену из ссылки:
+// Outlook и Google Calendar не умеют передавать заголовок Authorization.
+func runCalendarFeedRouter(
+	api *echo.Group,
+	secureGroup *echo.Group,
+	calendarService services.CalendarFeedServiceInterface,
+	limiter *ratelimit.Limiter,
+	rateCfg config.RateLimitConfig,
+	logger *zap.Logger,
+) {
+	calendarCtrl := controllers.NewCalendarFeedController(calendarService, logger)
+
+	secureGroup.GET("/me/calendar", calendarCtrl.GetMy)
+	secureGroup.POST("/me/calendar/token", calendarCtrl.RotateMy)
+	secureGroup.DELETE("/me/calendar/token", calendarCtrl.RevokeMy)
+
+	api.GET("/me/calendar.ics", calendarCtrl.Feed, middleware.RateLimit(limiter, middleware.RateLimitPolicy{
+		Name: "calendar", Rules: apiRateRules(rateCfg),
+	}, logger))
+}
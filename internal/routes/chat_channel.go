@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runChatChannelRouter(
+	secureGroup *echo.Group,
+	chatService services.ChatConnectorServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	chatCtrl := controllers.NewChatChannelController(chatService, logger)
+
+	channels := secureGroup.Group("/chat-channels")
+	{
+		channels.POST("", chatCtrl.Create, authMW.AuthorizeAny(authz.WebhooksManage))
+		channels.GET("", chatCtrl.GetAll, authMW.AuthorizeAny(authz.WebhooksManage))
+		channels.GET("/event-types", chatCtrl.GetEventTypes, authMW.AuthorizeAny(authz.WebhooksManage))
+		channels.GET("/:id", chatCtrl.GetByID, authMW.AuthorizeAny(authz.WebhooksManage))
+		channels.PUT("/:id", chatCtrl.Update, authMW.AuthorizeAny(authz.WebhooksManage))
+		channels.DELETE("/:id", chatCtrl.Delete, authMW.AuthorizeAny(authz.WebhooksManage))
+		channels.POST("/:id/test", chatCtrl.Test, authMW.AuthorizeAny(authz.WebhooksManage))
+	}
+}
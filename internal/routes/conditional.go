@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/pkg/utils"
+)
+
+// conditionalDictionary отвечает 304 на GET справочника, если с прошлой выгрузки клиента
+// справочники names не менялись. Версия берётся из dictionary_versions, так что сами
+// таблицы не читаются. Подключается после проверки прав, чтобы 304 не отдавался без доступа.
+func conditionalDictionary(repo repositories.DictionaryVersionRepositoryInterface, logger *zap.Logger, names ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			version, err := repo.GetVersion(c.Request().Context(), names)
+			if err != nil {
+				logger.Warn("Не удалось получить версию справочника, ETag не выставлен", zap.Strings("dictionaries", names), zap.Error(err))
+				return next(c)
+			}
+			if version.IsZero() {
+				return next(c)
+			}
+
+			etag := utils.BuildETag(
+				strings.Join(names, ","),
+				c.Request().URL.RequestURI(),
+				conditionalViewerSignature(c),
+				c.Request().Header.Get("Accept-Language"),
+				version.UTC().Format(time.RFC3339Nano),
+			)
+			if done, err := utils.RespondNotModified(c, etag, version); done {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// conditionalViewerSignature — пользователь и его права: состав ответа может от них зависеть.
+func conditionalViewerSignature(c echo.Context) string {
+	userID, _ := utils.GetUserIDFromCtx(c.Request().Context())
+	permissions, _ := utils.GetPermissionsMapFromCtx(c.Request().Context())
+
+	granted := make([]string, 0, len(permissions))
+	for permission, ok := range permissions {
+		if ok {
+			granted = append(granted, permission)
+		}
+	}
+	sort.Strings(granted)
+	return strconv.FormatUint(userID, 10) + ":" + strings.Join(granted, ",")
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runCustomFieldRouter(
+	secureGroup *echo.Group,
+	customFieldService services.CustomFieldServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewCustomFieldController(customFieldService, logger)
+
+	fields := secureGroup.Group("/order_type/:id/custom_fields")
+	{
+		fields.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.OrderTypesView))
+		fields.POST("", ctrl.Create, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+		fields.PUT("/:fieldID", ctrl.Update, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+		fields.DELETE("/:fieldID", ctrl.Delete, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+	}
+}
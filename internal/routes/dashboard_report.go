@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runDashboardReportRouter(
+	secureGroup *echo.Group,
+	reportService services.DashboardReportServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewDashboardReportController(reportService, logger)
+
+	secureGroup.GET("/dashboard/report", ctrl.GetDashboardReport, authMW.AuthorizeAny(authz.DashboardView))
+}
@@ -18,11 +18,12 @@ func runDepartmentRouter(secureGroup *echo.Group, dbConn *pgxpool.Pool, logger *
 	userRepo := repositories.NewUserRepository(dbConn, logger)
 	departmentService := services.NewDepartmentService(txManager, departmentRepo, userRepo, logger)
 	departmentCtrl := controllers.NewDepartmentController(departmentService, logger)
+	notModified := conditionalDictionary(repositories.NewDictionaryVersionRepository(dbConn, logger), logger, "departments", "statuses")
 
 	secureGroup.GET("/main", departmentCtrl.GetDepartmentStats, authMW.AuthorizeAny(authz.DepartmentsView))
 	departmentsGroup := secureGroup.Group("/department")
-	departmentsGroup.GET("", departmentCtrl.GetDepartments, authMW.AuthorizeAny(authz.DepartmentsView))
-	departmentsGroup.GET("/:id", departmentCtrl.FindDepartment, authMW.AuthorizeAny(authz.DepartmentsView))
+	departmentsGroup.GET("", departmentCtrl.GetDepartments, authMW.AuthorizeAny(authz.DepartmentsView), notModified)
+	departmentsGroup.GET("/:id", departmentCtrl.FindDepartment, authMW.AuthorizeAny(authz.DepartmentsView), notModified)
 	departmentsGroup.POST("", departmentCtrl.CreateDepartment, authMW.AuthorizeAny(authz.DepartmentsCreate))
 	departmentsGroup.PUT("/:id", departmentCtrl.UpdateDepartment, authMW.AuthorizeAny(authz.DepartmentsUpdate))
 	departmentsGroup.DELETE("/:id", departmentCtrl.DeleteDepartment, authMW.AuthorizeAny(authz.DepartmentsDelete))
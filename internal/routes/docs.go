@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/pkg/config"
+)
+
+// runDocsRouter — публичная документация API: фронтенду и интеграторам 1С не нужен токен, чтобы её открыть.
+func runDocsRouter(api *echo.Group, cfg config.DocsConfig, logger *zap.Logger) {
+	if !cfg.Enabled {
+		logger.Info("Документация API отключена (API_DOCS_ENABLED=false)")
+		return
+	}
+
+	docsCtrl := controllers.NewDocsController(cfg.SwaggerUIAssetsURL)
+	api.GET("/docs", docsCtrl.GetSwaggerUI)
+	api.GET("/docs/openapi.json", docsCtrl.GetSpec)
+}
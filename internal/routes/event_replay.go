@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runEventReplayRouter(
+	secureGroup *echo.Group,
+	replayService services.EventReplayServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	replayCtrl := controllers.NewEventReplayController(replayService, logger)
+
+	secureGroup.POST("/events/replay/order-history", replayCtrl.ReplayOrderHistory, authMW.AuthorizeAny(authz.EventsReplay))
+}
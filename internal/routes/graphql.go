@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/graphqlapi"
+)
+
+// runGraphQLRouter — POST /api/graphql. Права проверяют резолверы через те же сервисы, что у REST.
+func runGraphQLRouter(secureGroup *echo.Group, deps graphqlapi.Dependencies, logger *zap.Logger) {
+	executor, err := graphqlapi.NewExecutor(deps, logger)
+	if err != nil {
+		logger.Fatal("не удалось собрать GraphQL-схему", zap.Error(err))
+	}
+
+	graphqlCtrl := controllers.NewGraphQLController(executor, logger)
+	secureGroup.POST("/graphql", graphqlCtrl.Query)
+}
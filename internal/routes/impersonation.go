@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/config"
+	"request-system/pkg/middleware"
+	"request-system/pkg/service"
+)
+
+func runImpersonationRouter(
+	secureGroup *echo.Group,
+	impersonationService services.ImpersonationServiceInterface,
+	jwtSvc service.JWTService,
+	authCfg config.AuthConfig,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewImpersonationController(impersonationService, jwtSvc, authCfg.ImpersonationTTL, logger)
+
+	secureGroup.POST("/admin/impersonate/:userID", ctrl.Impersonate, authMW.AuthorizeAny(authz.UsersImpersonate))
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runImportRouter(
+	secureGroup *echo.Group,
+	importService services.ImportServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	importCtrl := controllers.NewImportController(importService, logger)
+
+	adminImport := secureGroup.Group("/admin/import")
+	{
+		adminImport.POST("/:entity/preview", importCtrl.Preview, authMW.AuthorizeAny(authz.DataImport))
+		adminImport.POST("/:entity", importCtrl.Import, authMW.AuthorizeAny(authz.DataImport))
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+)
+
+// Центр уведомлений: пользователь видит и отмечает только свои уведомления, отдельные права не нужны.
+func runNotificationCenterRouter(
+	secureGroup *echo.Group,
+	centerService services.NotificationCenterServiceInterface,
+	logger *zap.Logger,
+) {
+	centerCtrl := controllers.NewNotificationCenterController(centerService, logger)
+
+	notifications := secureGroup.Group("/notifications")
+	{
+		notifications.GET("", centerCtrl.GetMy)
+		notifications.GET("/unread-count", centerCtrl.GetUnreadCount)
+		notifications.POST("/read-all", centerCtrl.MarkAllRead)
+		notifications.PATCH("/:id/read", centerCtrl.MarkRead)
+	}
+}
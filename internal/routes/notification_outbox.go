@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runNotificationOutboxRouter(
+	secureGroup *echo.Group,
+	outboxService services.NotificationOutboxServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	outboxCtrl := controllers.NewNotificationOutboxController(outboxService, logger)
+
+	outbox := secureGroup.Group("/notifications/outbox")
+	{
+		outbox.GET("/dead", outboxCtrl.GetDeadLetters, authMW.AuthorizeAny(authz.NotificationsManage))
+		outbox.POST("/:id/requeue", outboxCtrl.Requeue, authMW.AuthorizeAny(authz.NotificationsManage))
+	}
+}
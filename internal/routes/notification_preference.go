@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+)
+
+// Настройки уведомлений доступны любому авторизованному пользователю и касаются только его самого.
+func runNotificationPreferenceRouter(
+	secureGroup *echo.Group,
+	prefService services.NotificationPreferenceServiceInterface,
+	logger *zap.Logger,
+) {
+	prefCtrl := controllers.NewNotificationPreferenceController(prefService, logger)
+
+	secureGroup.GET("/me/notification-preferences", prefCtrl.GetMy)
+	secureGroup.PUT("/me/notification-preferences", prefCtrl.UpdateMy)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runOnCallRouter(
+	secureGroup *echo.Group,
+	onCallService services.OnCallServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewOnCallController(onCallService, logger)
+
+	secureGroup.GET("/on-call/now", ctrl.GetNow, authMW.AuthorizeAny(authz.OnCallView))
+
+	schedules := secureGroup.Group("/on-call-schedules")
+	{
+		schedules.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.OnCallView))
+		schedules.GET("/:id", ctrl.GetByID, authMW.AuthorizeAny(authz.OnCallView))
+		schedules.POST("", ctrl.Create, authMW.AuthorizeAny(authz.OnCallManage))
+		schedules.PATCH("/:id", ctrl.Update, authMW.AuthorizeAny(authz.OnCallManage))
+		schedules.DELETE("/:id", ctrl.Delete, authMW.AuthorizeAny(authz.OnCallManage))
+		schedules.GET("/:id/overrides", ctrl.GetOverrides, authMW.AuthorizeAny(authz.OnCallView))
+		schedules.POST("/:id/overrides", ctrl.CreateOverride, authMW.AuthorizeAny(authz.OnCallManage))
+		schedules.DELETE("/:id/overrides/:overrideID", ctrl.DeleteOverride, authMW.AuthorizeAny(authz.OnCallManage))
+	}
+}
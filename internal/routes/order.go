@@ -25,8 +25,18 @@ func runOrderRouter(
 	{
 		orders.POST("", orderController.CreateOrder, authMW.AuthorizeAny(authz.OrdersCreate))
 		orders.GET("", orderController.GetOrders, authMW.AuthorizeAny(authz.OrdersView))
+		orders.GET("/similar", orderController.FindPossibleDuplicates, authMW.AuthorizeAny(authz.OrdersView))
 		orders.GET("/:id", orderController.FindOrder, authMW.AuthorizeAny(authz.OrdersView))
+		orders.PATCH("/:id", orderController.UpdateOrder, authMW.AuthorizeAny(authz.OrdersUpdate))
 		orders.PUT("/:id", orderController.UpdateOrder, authMW.AuthorizeAny(authz.OrdersUpdate))
 		orders.DELETE("/:id", orderController.DeleteOrder, authMW.AuthorizeAny(authz.OrdersDelete))
+		orders.POST("/:id/merge", orderController.MergeOrder, authMW.AuthorizeAny(authz.OrdersMerge))
+		orders.POST("/:id/claim", orderController.ClaimOrder, authMW.AuthorizeAny(authz.OrdersView))
+		orders.GET("/:id/access-grants", orderController.GetAccessGrants, authMW.AuthorizeAny(authz.OrdersGrantAccess))
+		orders.POST("/:id/access-grants", orderController.GrantAccess, authMW.AuthorizeAny(authz.OrdersGrantAccess))
+		orders.DELETE("/:id/access-grants/:userID", orderController.RevokeAccess, authMW.AuthorizeAny(authz.OrdersGrantAccess))
 	}
+	secureGroup.POST("/orders/claim-next", orderController.ClaimNextOrder, authMW.AuthorizeAny(authz.OrdersUpdate))
+	secureGroup.GET("/orders/export", orderController.ExportOrders, authMW.AuthorizeAny(authz.OrdersView))
+	secureGroup.GET("/orders/:id/suggested-executors", orderController.SuggestedExecutors, authMW.AuthorizeAny(authz.OrdersUpdateExecutorID))
 }
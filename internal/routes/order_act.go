@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runOrderActRouter(
+	secureGroup *echo.Group,
+	actService services.OrderActServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewOrderActController(actService, logger)
+
+	secureGroup.GET("/orders/:id/pdf", ctrl.GetOrderAct, authMW.AuthorizeAny(authz.OrdersView))
+}
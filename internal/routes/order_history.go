@@ -22,4 +22,6 @@ func runOrderHistoryRouter(
 	secureGroup.GET("/order/:orderID/history", historyController.GetHistoryForOrder,
 		binder,
 		authMW.AuthorizeAny(authz.OrdersView))
+	secureGroup.GET("/orders/:id/history", historyController.GetHistoryEntries,
+		authMW.AuthorizeAny(authz.OrdersView))
 }
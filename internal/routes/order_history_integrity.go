@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runOrderHistoryIntegrityRouter(
+	secureGroup *echo.Group,
+	integrityService services.OrderHistoryIntegrityServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	integrityCtrl := controllers.NewOrderHistoryIntegrityController(integrityService, logger)
+
+	secureGroup.GET("/order/:orderID/history/verify", integrityCtrl.VerifyHistory, authMW.AuthorizeAny(authz.AuditView))
+}
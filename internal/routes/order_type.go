@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 
 	"request-system/internal/controllers"
+	"request-system/internal/repositories"
 	"request-system/internal/services"
 	"request-system/pkg/middleware"
 )
@@ -14,16 +15,19 @@ import (
 func runOrderTypeRouter(
 	secureGroup *echo.Group,
 	orderTypeService services.OrderTypeServiceInterface,
+	dictionaryVersions repositories.DictionaryVersionRepositoryInterface,
 	logger *zap.Logger,
 	authMW *middleware.AuthMiddleware,
 ) {
 	orderTypeCtrl := controllers.NewOrderTypeController(orderTypeService, logger)
+	notModified := conditionalDictionary(dictionaryVersions, logger, "order_types", "statuses")
 
 	orderType := secureGroup.Group("/order_type")
 	{
 		orderType.POST("", orderTypeCtrl.Create, authMW.AuthorizeAny("order_type:create"))
-		orderType.GET("", orderTypeCtrl.GetAll, authMW.AuthorizeAny("order_type:view"))
-		orderType.GET("/:id", orderTypeCtrl.GetByID, authMW.AuthorizeAny("order_type:view"))
+		orderType.GET("", orderTypeCtrl.GetAll, authMW.AuthorizeAny("order_type:view"), notModified)
+		orderType.GET("/available", orderTypeCtrl.GetAvailable, authMW.AuthorizeAny("order:create"))
+		orderType.GET("/:id", orderTypeCtrl.GetByID, authMW.AuthorizeAny("order_type:view"), notModified)
 		orderType.PUT("/:id", orderTypeCtrl.Update, authMW.AuthorizeAny("order_type:update"))
 		orderType.DELETE("/:id", orderTypeCtrl.Delete, authMW.AuthorizeAny("order_type:delete"))
 
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runOrderTypeValidationRouter(
+	secureGroup *echo.Group,
+	validationService services.OrderTypeValidationServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewOrderTypeValidationController(validationService, logger)
+
+	rules := secureGroup.Group("/order_type/:id/validation_rules")
+	{
+		rules.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.OrderTypesView))
+		rules.POST("", ctrl.Create, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+		rules.PUT("/:ruleID", ctrl.Update, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+		rules.DELETE("/:ruleID", ctrl.Delete, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runOrgStructureRouter(
+	secureGroup *echo.Group,
+	orgStructureService services.OrgStructureServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewOrgStructureController(orgStructureService, logger)
+
+	secureGroup.GET("/org-structure", ctrl.GetTree,
+		authMW.AuthorizeAny(authz.DepartmentsView, authz.OtdelsView, authz.BranchesView, authz.OfficesView))
+}
@@ -22,6 +22,7 @@ func runPermissionRouter(
 	perms := secureGroup.Group("/permission")
 
 	perms.GET("", permCtrl.GetPermissions, authMW.AuthorizeAny(authz.PermissionsView))
+	perms.POST("/cache/flush", permCtrl.FlushPermissionsCache, authMW.AuthorizeAny(authz.PermissionsFlushCache))
 	perms.GET("/:id", permCtrl.FindPermission, authMW.AuthorizeAny(authz.PermissionsView))
 	perms.POST("", permCtrl.CreatePermission, authMW.AuthorizeAny(authz.PermissionsCreate))
 	perms.PUT("/:id", permCtrl.UpdatePermission, authMW.AuthorizeAny(authz.PermissionsUpdate))
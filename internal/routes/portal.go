@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/config"
+	"request-system/pkg/middleware"
+	"request-system/pkg/ratelimit"
+)
+
+// runPortalRouter — публичные маршруты портала. Лимит по IP действует всегда,
+// даже при RATE_LIMIT_ENABLED=false: это единственная запись в систему без авторизации.
+func runPortalRouter(
+	api *echo.Group,
+	portalService services.PortalServiceInterface,
+	limiter *ratelimit.Limiter,
+	cfg config.PortalConfig,
+	logger *zap.Logger,
+) {
+	if !cfg.Enabled {
+		return
+	}
+	if cfg.UserID == 0 {
+		logger.Error("Портал обращений не запущен: не задан PORTAL_USER_ID")
+		return
+	}
+
+	portalCtrl := controllers.NewPortalController(portalService, logger)
+
+	portal := api.Group("/portal", middleware.RateLimit(limiter, middleware.RateLimitPolicy{
+		Name: "portal", Read: cfg.ReadLimit, Write: cfg.SubmitLimit,
+	}, logger))
+	portal.GET("/captcha", portalCtrl.GetCaptcha)
+	portal.POST("/requests", portalCtrl.SubmitRequest)
+	portal.GET("/requests/:code", portalCtrl.GetRequestStatus)
+}
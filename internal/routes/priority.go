@@ -25,10 +25,13 @@ func RunPriorityRouter(
 	// Внедряем FileStorage в сервис
 	priorityService := services.NewPriorityService(priorityRepository, userRepository, logger)
 	priorityCtrl := controllers.NewPriorityController(priorityService, logger)
+	notModified := conditionalDictionary(repositories.NewDictionaryVersionRepository(dbConn, logger), logger, "priorities")
 
 	priorities := secureGroup.Group("/priority")
-	priorities.GET("", priorityCtrl.GetPriorities, authMW.AuthorizeAny(authz.PrioritiesView))
-	priorities.GET("/:id", priorityCtrl.FindPriority, authMW.AuthorizeAny(authz.PrioritiesView))
+	priorities.GET("", priorityCtrl.GetPriorities, authMW.AuthorizeAny(authz.PrioritiesView), notModified)
+	priorities.GET("/matrix", priorityCtrl.GetMatrix, authMW.AuthorizeAny(authz.PrioritiesView))
+	priorities.PUT("/matrix", priorityCtrl.UpdateMatrix, authMW.AuthorizeAny(authz.PrioritiesUpdate))
+	priorities.GET("/:id", priorityCtrl.FindPriority, authMW.AuthorizeAny(authz.PrioritiesView), notModified)
 	priorities.POST("", priorityCtrl.CreatePriority, authMW.AuthorizeAny(authz.PrioritiesCreate))
 	priorities.PUT("/:id", priorityCtrl.UpdatePriority, authMW.AuthorizeAny(authz.PrioritiesUpdate))
 	priorities.DELETE("/:id", priorityCtrl.DeletePriority, authMW.AuthorizeAny(authz.PrioritiesDelete))
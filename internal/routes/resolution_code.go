@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runResolutionCodeRouter(
+	secureGroup *echo.Group,
+	resolutionCodeService services.ResolutionCodeServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewResolutionCodeController(resolutionCodeService, logger)
+
+	codes := secureGroup.Group("/resolution-codes")
+	{
+		codes.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.ResolutionCodesView))
+		codes.GET("/stats", ctrl.GetStats, authMW.AuthorizeAny(authz.ReportView))
+		codes.POST("", ctrl.Create, authMW.AuthorizeAny(authz.ResolutionCodesManage))
+		codes.PUT("/:id", ctrl.Update, authMW.AuthorizeAny(authz.ResolutionCodesManage))
+		codes.DELETE("/:id", ctrl.Delete, authMW.AuthorizeAny(authz.ResolutionCodesManage))
+	}
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runRetentionRouter(
+	secureGroup *echo.Group,
+	retentionService services.RetentionServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewRetentionController(retentionService, logger)
+
+	retention := secureGroup.Group("/admin/retention")
+	{
+		retention.GET("/rules", ctrl.GetRules, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.POST("/rules", ctrl.CreateRule, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.PUT("/rules/:id", ctrl.UpdateRule, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.DELETE("/rules/:id", ctrl.DeleteRule, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.GET("/holds", ctrl.GetHolds, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.POST("/holds", ctrl.SetHold, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.DELETE("/holds/:orderID", ctrl.RemoveHold, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.GET("/runs", ctrl.GetRuns, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.GET("/runs/:id", ctrl.GetRun, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.POST("/runs/preview", ctrl.Preview, authMW.AuthorizeAny(authz.RetentionManage))
+		retention.POST("/runs", ctrl.Trigger, authMW.AuthorizeAny(authz.RetentionManage))
+	}
+}
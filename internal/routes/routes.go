@@ -5,17 +5,23 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
 	"request-system/internal/authz"
 	"request-system/internal/controllers"
+	tgCtrl "request-system/internal/controllers/telegram"
+	"request-system/internal/graphqlapi"
 	"request-system/internal/repositories"
 	"request-system/internal/services"
 	"request-system/pkg/config"
+	"request-system/pkg/database/postgresql"
 	"request-system/pkg/eventbus"
 	"request-system/pkg/filestorage"
+	"request-system/pkg/jobs"
 	"request-system/pkg/middleware"
+	"request-system/pkg/ratelimit"
 	"request-system/pkg/service"
 	"request-system/pkg/telegram"
 	"request-system/pkg/websocket"
@@ -29,112 +35,314 @@ type Loggers struct {
 	OrderHistory *zap.Logger
 }
 
+// Services — сервисы, которые помимо HTTP-маршрутов нужны другим входам в приложение (gRPC)
+// и main: TelegramBot дожидается начатой обработки обновлений при остановке.
+type Services struct {
+	Order       services.OrderServiceInterface
+	User        services.UserServiceInterface
+	TelegramBot *tgCtrl.TelegramController
+}
+
 func InitRouter(
 	e *echo.Echo,
 	dbConn *pgxpool.Pool,
+	readDB postgresql.Reader,
 	redisClient *redis.Client,
 	jwtSvc service.JWTService,
 	loggers *Loggers,
 	authPermissionService services.AuthPermissionServiceInterface,
+	statusDirectory services.StatusDirectoryInterface,
 	cfg *config.Config,
 	bus *eventbus.Bus,
 	wsHub *websocket.Hub,
 	adService services.ADServiceInterface,
+	jobQueue *jobs.Queue,
 	appCtx context.Context,
-) {
+) *Services {
 	loggers.Main.Info("InitRouter: Начало создания маршрутов")
 
 	// --- 0. ОБЩИЕ КОМПОНЕНТЫ ---
-	api := e.Group("/api")
+	api := e.Group("/api", middleware.Language())
 	authMW := middleware.NewAuthMiddleware(jwtSvc, authPermissionService, loggers.Auth)
 	fileStorage, err := filestorage.NewLocalFileStorage("uploads")
 	if err != nil {
 		loggers.Main.Fatal("не удалось создать файловое хранилище", zap.Error(err))
 	}
 	txManager := repositories.NewTxManager(dbConn, loggers.Main)
+	limiter := ratelimit.NewLimiter(redisClient, "ratelimit")
+	// HTTP-лимиты можно выключить (и включить обратно на ходу), кулдауны Telegram-бота работают всегда.
+	if !cfg.RateLimit.Enabled {
+		loggers.Main.Warn("Ограничение частоты запросов к API выключено (RATE_LIMIT_ENABLED=false)")
+	}
 
 	// --- 1. РЕПОЗИТОРИИ (создаем все в одном месте) ---
+	// Тяжёлые чтения (список, выгрузка, дашборд, отчёт) ограничены по времени, чтобы не исчерпать пул
+	heavyReader := postgresql.WithQueryTimeout(readDB, cfg.Postgres.Pool.HeavyQueryTimeout)
 	userRepo := repositories.NewUserRepository(dbConn, loggers.User)
 	cacheRepo := repositories.NewRedisCacheRepository(redisClient)
+	eventOutboxRepo := repositories.NewEventOutboxRepository(dbConn, loggers.Main)
 	roleRepo := repositories.NewRoleRepository(dbConn, loggers.Main)
 	permissionRepo := repositories.NewPermissionRepository(dbConn, loggers.Main)
 	statusRepo := repositories.NewStatusRepository(dbConn)
 	rpRepo := repositories.NewRolePermissionRepository(dbConn)
-	orderRepo := repositories.NewOrderRepository(dbConn, loggers.Order)
+	orderRepo := repositories.NewOrderRepository(dbConn, heavyReader, loggers.Order)
 	priorityRepo := repositories.NewPriorityRepository(dbConn, loggers.Main)
 	attachRepo := repositories.NewAttachmentRepository(dbConn)
 	historyRepo := repositories.NewOrderHistoryRepository(dbConn, loggers.OrderHistory)
 	positionRepo := repositories.NewPositionRepository(dbConn, loggers.Main)
 	orderTypeRepo := repositories.NewOrderTypeRepository(dbConn)
 	ruleRepo := repositories.NewOrderRoutingRuleRepository(dbConn)
-	reportRepo := repositories.NewReportRepository(dbConn, loggers.Main)
+	reportRepo := repositories.NewReportRepository(heavyReader, loggers.Main)
 	branchRepo := repositories.NewBranchRepository(dbConn, loggers.Main)
 	departmentRepo := repositories.NewDepartmentRepository(dbConn, loggers.Main)
 	otdelRepo := repositories.NewOtdelRepository(dbConn, loggers.Main)
 	officeRepo := repositories.NewOfficeRepository(dbConn, loggers.Main)
-	dashboardRepo := repositories.NewDashboardRepository(dbConn, loggers.Main)
+	dashboardRepo := repositories.NewDashboardRepository(heavyReader, loggers.Main)
+	notificationPrefRepo := repositories.NewNotificationPreferenceRepository(dbConn, loggers.Main)
+	notificationOutboxRepo := repositories.NewNotificationOutboxRepository(dbConn, loggers.Main)
+	userNotificationRepo := repositories.NewUserNotificationRepository(dbConn, loggers.Main)
+	webhookRepo := repositories.NewWebhookRepository(dbConn, loggers.Main)
+	chatChannelRepo := repositories.NewChatChannelRepository(dbConn, loggers.Main)
+	calendarFeedRepo := repositories.NewCalendarFeedRepository(dbConn, loggers.Main)
+	auditLogRepo := repositories.NewAuditLogRepository(dbConn, loggers.Main)
+	analyticsRepo := repositories.NewAnalyticsRepository(dbConn, loggers.Main)
+	equipmentRepo := repositories.NewEquipmentRepository(dbConn, loggers.Main)
+	portalRequestRepo := repositories.NewPortalRequestRepository(dbConn, loggers.Main)
+	adGroupMappingRepo := repositories.NewADGroupMappingRepository(dbConn, loggers.Main)
+	teamRepo := repositories.NewTeamRepository(dbConn, loggers.Main)
+	onCallRepo := repositories.NewOnCallRepository(dbConn, loggers.Main)
+	skillRepo := repositories.NewSkillRepository(dbConn, loggers.Main)
+	resolutionCodeRepo := repositories.NewResolutionCodeRepository(dbConn, loggers.Main)
+	workCalendarRepo := repositories.NewWorkCalendarRepository(dbConn, loggers.Main)
+	orgStructureRepo := repositories.NewOrgStructureRepository(dbConn, loggers.Main)
+	customFieldRepo := repositories.NewCustomFieldRepository(dbConn, loggers.Main)
+	accessGrantRepo := repositories.NewOrderAccessGrantRepository(dbConn, loggers.Main)
 
 	// --- 2. СЕРВИСЫ ---
-	ruleEngineService := services.NewRuleEngineService(ruleRepo, userRepo, loggers.Main)
-	roleService := services.NewRoleService(roleRepo, userRepo, statusRepo, authPermissionService, loggers.Main)
-	permissionService := services.NewPermissionService(permissionRepo, userRepo, loggers.Main)
-	rpService := services.NewRolePermissionService(rpRepo, userRepo, authPermissionService, loggers.Main)
-	orderTypeService := services.NewOrderTypeService(orderTypeRepo, userRepo, txManager, ruleEngineService, loggers.Main)
+	ruleEngineService := services.NewRuleEngineService(ruleRepo, userRepo, onCallRepo, loggers.Main)
+	roleService := services.NewRoleService(roleRepo, userRepo, statusRepo, bus, loggers.Main)
+	permissionService := services.NewPermissionService(permissionRepo, userRepo, authPermissionService, bus, loggers.Main)
+	rpService := services.NewRolePermissionService(rpRepo, bus, loggers.Main)
+	orderTypeService := services.NewOrderTypeService(orderTypeRepo, userRepo, statusRepo, txManager, ruleEngineService, loggers.Main)
 	positionService := services.NewPositionService(positionRepo, userRepo, txManager, loggers.Main)
-	userService := services.NewUserService(txManager, userRepo, otdelRepo, roleRepo, permissionRepo, statusRepo, cacheRepo, authPermissionService, loggers.User)
+	userService := services.NewUserService(txManager, userRepo, otdelRepo, roleRepo, permissionRepo, statusRepo, cacheRepo, authPermissionService, cfg.Telegram, loggers.User)
 	departmentService := services.NewDepartmentService(txManager, departmentRepo, userRepo, loggers.Main)
 	otdelService := services.NewOtdelService(txManager, otdelRepo, userRepo, loggers.Main)
 	orderRuleService := services.NewOrderRoutingRuleService(ruleRepo, userRepo, positionRepo, txManager, loggers.Main, orderTypeRepo)
-	tgService := telegram.NewService(cfg.Telegram.BotToken)
+	tgService := telegram.NewService(cfg.Telegram.BotToken, cfg.Telegram.Send)
 	notificationService := services.NewTelegramNotificationService(tgService, loggers.Main)
-	orderService := services.NewOrderService(txManager, orderRepo, userRepo, statusRepo, priorityRepo, attachRepo, ruleEngineService,
-		historyRepo, fileStorage, bus, loggers.Order, orderTypeRepo, authPermissionService, notificationService, cacheRepo)
+	businessCalendarService := services.NewBusinessCalendarService(repositories.NewBusinessCalendarRepository(dbConn, loggers.Main), userRepo, loggers.Main)
+	orderTypeValidationService := services.NewOrderTypeValidationService(repositories.NewOrderTypeValidationRuleRepository(dbConn, loggers.Main),
+		orderTypeRepo, userRepo, loggers.Main)
+	orderService := services.NewOrderService(txManager, orderRepo, userRepo, statusRepo, statusDirectory, priorityRepo, attachRepo, ruleEngineService,
+		historyRepo, fileStorage, bus, loggers.Order, orderTypeRepo, authPermissionService, notificationService, cacheRepo, eventOutboxRepo, customFieldRepo, accessGrantRepo,
+		businessCalendarService, orderTypeValidationService, resolutionCodeRepo, cfg.Orders)
 	historyService := services.NewOrderHistoryService(historyRepo, userRepo, departmentRepo, otdelRepo, branchRepo, officeRepo, statusRepo, priorityRepo, loggers.OrderHistory)
 	reportService := services.NewReportService(reportRepo, userRepo, loggers.Main)
 	_ = reportService
 	branchService := services.NewBranchService(txManager, branchRepo, userRepo, loggers.Main)
 	officeService := services.NewOfficeService(officeRepo, userRepo, txManager, loggers.Main)
-	dashboardService := services.NewDashboardService(dashboardRepo, userRepo, cacheRepo, loggers.Main)
+	dashboardService := services.NewDashboardService(dashboardRepo, userRepo, cacheRepo, repositories.NewDailyOrderStatsRepository(dbConn, loggers.Main), loggers.Main)
+	notificationPrefService := services.NewNotificationPreferenceService(notificationPrefRepo, loggers.Main)
+	userLanguageService := services.NewUserLanguageService(userRepo, cacheRepo, loggers.User)
+	userTimezoneService := services.NewUserTimezoneService(userRepo, cacheRepo, loggers.User)
+	notificationOutboxService := services.NewNotificationOutboxService(notificationOutboxRepo, notificationService,
+		services.NewWebSocketNotificationService(wsHub, loggers.Main.Named("WebSocketNotifier")), userRepo, loggers.Main)
+	notificationCenterService := services.NewNotificationCenterService(userNotificationRepo, loggers.Main)
+	webhookAllowedNetworks, err := services.ParseWebhookAllowedNetworks(cfg.Integrations.WebhookAllowedNetworks)
+	if err != nil {
+		loggers.Main.Fatal("Ошибка настройки разрешённых сетей webhook", zap.Error(err))
+	}
+	webhookService := services.NewWebhookService(webhookRepo, userRepo, webhookAllowedNetworks, loggers.Main)
+	chatConnectorService := services.NewChatConnectorService(chatChannelRepo, userRepo, cfg.Frontend, businessCalendarService, loggers.Main)
+	slaEscalationService := services.NewSLAEscalationService(repositories.NewSLAEscalationRepository(dbConn, loggers.Main), userRepo,
+		notificationOutboxService, notificationCenterService, businessCalendarService, cfg.Notifications, cfg.Frontend, loggers.Main.Named("SLAEscalation"))
+	go slaEscalationService.Start(appCtx)
+	firstResponseReminderService := services.NewFirstResponseReminderService(repositories.NewFirstResponseReminderRepository(dbConn, loggers.Main),
+		userRepo, notificationOutboxService, notificationCenterService, businessCalendarService, cfg.Notifications, cfg.Frontend,
+		loggers.Main.Named("FirstResponse"))
+	go firstResponseReminderService.Start(appCtx)
+	calendarFeedService := services.NewCalendarFeedService(calendarFeedRepo, userRepo, cfg.JWT, cfg.Server, cfg.Frontend, loggers.Main)
+	eventReplayService := services.NewEventReplayService(historyRepo, orderRepo, userRepo, bus, loggers.Main)
+	auditService := services.NewAuditService(auditLogRepo, userRepo, loggers.Main)
+	historyIntegrityService := services.NewOrderHistoryIntegrityService(historyRepo, userRepo, loggers.OrderHistory)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, loggers.Main.Named("Analytics"))
+	portalService := services.NewPortalService(cfg.Portal, orderService, portalRequestRepo, userRepo, orderTypeRepo,
+		authPermissionService, cacheRepo, loggers.Main.Named("Portal"))
+	equipmentService := services.NewEquipmentService(equipmentRepo, userRepo, cfg.Frontend, cfg.Telegram, loggers.Main)
+	impersonationService := services.NewImpersonationService(userRepo, authPermissionService, &cfg.Auth, loggers.Auth)
+	teamService := services.NewTeamService(teamRepo, userRepo, loggers.Main)
+	onCallService := services.NewOnCallService(onCallRepo, userRepo, loggers.Main)
+	skillService := services.NewSkillService(skillRepo, userRepo, orderTypeRepo, loggers.Main)
+	resolutionCodeService := services.NewResolutionCodeService(resolutionCodeRepo, userRepo, loggers.Main)
+	workCalendarService := services.NewWorkCalendarService(workCalendarRepo, teamRepo, userRepo, loggers.Main)
+	orgStructureService := services.NewOrgStructureService(orgStructureRepo, userRepo, loggers.Main)
+	customFieldService := services.NewCustomFieldService(customFieldRepo, orderTypeRepo, userRepo, loggers.Main)
+	backgroundJobService := services.NewBackgroundJobService(jobQueue, userRepo, loggers.Main.Named("Jobs"))
+	runtimeConfigService := services.NewRuntimeConfigService(cfg.Runtime, cfg.Server.ConfigWatchInterval, auditService, userRepo, redisClient, loggers.Main.Named("RuntimeConfig"))
+	go runtimeConfigService.Start(appCtx)
+	schemaMigrator, err := postgresql.NewMigrator(stdlib.OpenDBFromPool(dbConn), postgresql.MigrationsDir)
+	if err != nil {
+		loggers.Main.Fatal("не удалось прочитать миграции", zap.Error(err))
+	}
+	schemaService := services.NewSchemaService(schemaMigrator, cfg.Postgres.AutoMigrate, userRepo, loggers.Main.Named("Schema"))
+	importService := services.NewImportService(newSyncHandler(dbConn, cfg, loggers), services.NewEquipImportService(dbConn, loggers.Main),
+		userRepo, loggers.Main)
+	retentionService := services.NewRetentionService(repositories.NewRetentionRepository(dbConn, loggers.Main), historyRepo, txManager,
+		userRepo, fileStorage, jobQueue, cfg.Retention.BatchSize, loggers.Main.Named("Retention"))
+	jobQueue.Register(services.RetentionJobType, retentionService.HandleJob, jobs.Options{MaxAttempts: 1, Timeout: services.RetentionJobTimeout})
+	if cfg.Retention.Interval > 0 {
+		jobQueue.Every(services.RetentionJobType, cfg.Retention.Interval, nil)
+	}
+	backupStorage, err := filestorage.NewLocalFileStorage(cfg.Backup.Dir)
+	if err != nil {
+		loggers.Main.Fatal("не удалось подготовить хранилище резервных копий", zap.Error(err))
+	}
+	backupService := services.NewBackupService(repositories.NewBackupRepository(dbConn, loggers.Main), backupStorage, userRepo, jobQueue,
+		notificationCenterService, notificationOutboxService, cfg.Backup, cfg.Postgres.DSN, loggers.Main.Named("Backup"))
+	if err := backupService.CheckTools(appCtx); err != nil {
+		if cfg.Backup.Interval > 0 {
+			loggers.Main.Fatal("резервное копирование недоступно", zap.Error(err))
+		}
+		loggers.Main.Error("резервное копирование недоступно, ручной запуск отключён", zap.Error(err))
+	}
+	jobQueue.Register(services.BackupJobType, backupService.HandleJob, jobs.Options{MaxAttempts: 1, Timeout: services.BackupJobTimeout})
+	if cfg.Backup.Interval > 0 {
+		jobQueue.Every(services.BackupJobType, cfg.Backup.Interval, nil)
+	}
+	userAnonymizationService := services.NewUserAnonymizationService(txManager, repositories.NewUserAnonymizationRepository(dbConn, loggers.User),
+		userRepo, statusRepo, historyRepo, authPermissionService, auditService, fileStorage, loggers.User)
+	adGroupSyncService := services.NewADGroupSyncService(adGroupMappingRepo, userRepo, statusRepo, adService,
+		authPermissionService, &cfg.LDAP, loggers.Auth.Named("ADGroupSync"))
+	// LDAP_ENABLED перечитывается на ходу, поэтому цикл нужен, даже если LDAP сейчас выключен
+	if cfg.LDAP.GroupSyncEnabled {
+		go adGroupSyncService.Start(appCtx)
+	}
 
 	// --- 3. КОНТРОЛЛЕРЫ ---
 	userController := controllers.NewUserController(userService, adService, fileStorage, loggers.User)
 	historyController := controllers.NewOrderHistoryController(historyService, orderService, loggers.OrderHistory)
-	wsController := controllers.NewWebSocketController(wsHub, jwtSvc, loggers.Main, cfg.Server.AllowedOrigins)
+	wsController := controllers.NewWebSocketController(wsHub, jwtSvc,
+		services.NewWebSocketTicketService(cacheRepo, cfg.WebSocket.TicketTTL, loggers.Main.Named("WebSocketTicket")), orderService, userRepo, loggers.Main, cfg.Server.AllowedOrigins)
 	dashboardController := controllers.NewDashboardController(dashboardService, loggers.Main.Named("Dashboard"))
 
 	// --- 4. РОУТЕРЫ ---
-	secureGroup := api.Group("", authMW.Auth)
+	secureGroup := api.Group("", authMW.Auth,
+		middleware.UserLanguage(userLanguageService.Resolve, loggers.Main),
+		middleware.UserTimezone(userTimezoneService.Resolve, loggers.Main))
+	secureGroup.Use(middleware.RateLimit(limiter, middleware.RateLimitPolicy{
+		Name: "api", Rules: apiRateRules(cfg.RateLimit),
+	}, loggers.Main))
+	secureGroup.Use(auditMiddleware(auditService))
 
 	runEquipImportRouter(secureGroup, dbConn, loggers.Main, authMW)
-	runEquipmentRouter(secureGroup, dbConn, loggers.Main, authMW)
-	runAuthRouter(api, dbConn, redisClient, jwtSvc, loggers.Auth, authMW, fileStorage, authPermissionService, cfg,
+	runEquipmentRouter(secureGroup, equipmentService, loggers.Main, authMW)
+	runAuthRouter(api, dbConn, redisClient, jwtSvc, loggers.Auth, authMW, fileStorage, authPermissionService, cfg, limiter, adService, adGroupSyncService,
 		positionService, branchService, departmentService, otdelService, officeService)
 
 	api.GET("/ws", wsController.ServeWs)
+	secureGroup.POST("/ws-ticket", wsController.IssueTicket)
+	secureGroup.GET("/admin/ws/stats", wsController.GetHubStats, authMW.AuthorizeAny(authz.WebSocketManage))
+	secureGroup.GET("/admin/ws/connections", wsController.GetConnections, authMW.AuthorizeAny(authz.WebSocketManage))
+	secureGroup.DELETE("/admin/ws/connections/:id", wsController.DisconnectConnection, authMW.AuthorizeAny(authz.WebSocketManage))
+	secureGroup.DELETE("/admin/ws/users/:userID/connections", wsController.DisconnectUser, authMW.AuthorizeAny(authz.WebSocketManage))
+	secureGroup.GET("/order/:id/presence", wsController.GetOrderPresence, authMW.AuthorizeAny(authz.OrdersView))
 
 	runUserRouter(secureGroup, userController, authMW)
 	runRoleRouter(secureGroup, roleService, loggers.Main, authMW)
 	runPermissionRouter(secureGroup, permissionService, loggers.Main, authMW)
 	runRolePermissionRouter(secureGroup, rpService, loggers.Main, authMW)
 	runOrderRouter(secureGroup, orderService, loggers.Order, authMW)
-	runOrderTypeRouter(secureGroup, orderTypeService, loggers.Main, authMW)
+	runOrderTypeRouter(secureGroup, orderTypeService, repositories.NewDictionaryVersionRepository(dbConn, loggers.Main), loggers.Main, authMW)
 	runPositionRouter(secureGroup, positionService, loggers.Main, authMW)
 	runOrderRoutingRuleRouter(secureGroup, orderRuleService, loggers.Main, authMW)
 	runAttachmentRouter(secureGroup, dbConn, fileStorage, loggers.Main, authMW)
-	runStatusRouter(secureGroup, dbConn, loggers.Main, authMW, fileStorage)
+	runStatusRouter(secureGroup, dbConn, statusDirectory, loggers.Main, authMW, fileStorage)
 	runOrderHistoryRouter(secureGroup, historyController, authMW)
+	runOrderActRouter(secureGroup, services.NewOrderActService(orderService, historyService, cfg.Orders, loggers.Order), loggers.Order, authMW)
+	runOrderHistoryIntegrityRouter(secureGroup, historyIntegrityService, loggers.OrderHistory, authMW)
 	RunPriorityRouter(secureGroup, dbConn, loggers.Main, authMW)
 	runDepartmentRouter(secureGroup, dbConn, loggers.Main, authMW, txManager)
 	runOtdelRouter(secureGroup, dbConn, loggers.Main, authMW, txManager)
 	runEquipmentTypeRouter(secureGroup, dbConn, loggers.Main, authMW)
 	runBranchRouter(secureGroup, dbConn, loggers.Main, txManager, authMW)
 	runOfficeRouter(secureGroup, officeService, loggers.Main, authMW)
-	runTelegramRouter(e, userService, orderService, tgService, cacheRepo, statusRepo, userRepo, historyRepo, authPermissionService, orderTypeRepo, authMW, cfg, loggers.Main, appCtx)
+	runNotificationPreferenceRouter(secureGroup, notificationPrefService, loggers.Main)
+	runUserLanguageRouter(secureGroup, userLanguageService, loggers.User)
+	runUserTimezoneRouter(secureGroup, userTimezoneService, loggers.User)
+	runNotificationOutboxRouter(secureGroup, notificationOutboxService, loggers.Main, authMW)
+	runNotificationCenterRouter(secureGroup, notificationCenterService, loggers.Main)
+	runWebhookRouter(secureGroup, webhookService, loggers.Main, authMW)
+	runBackgroundJobRouter(secureGroup, backgroundJobService, loggers.Main, authMW)
+	runRuntimeConfigRouter(secureGroup, runtimeConfigService, loggers.Main, authMW)
+	runSchemaRouter(secureGroup, schemaService, loggers.Main, authMW)
+	runImportRouter(secureGroup, importService, loggers.Main, authMW)
+	runChatChannelRouter(secureGroup, chatConnectorService, loggers.Main, authMW)
+	runSLAEscalationRouter(secureGroup, slaEscalationService, loggers.Main, authMW)
+	runADGroupMappingRouter(secureGroup, adGroupSyncService, loggers.Main, authMW)
+	runTeamRouter(secureGroup, teamService, loggers.Main, authMW)
+	runOnCallRouter(secureGroup, onCallService, loggers.Main, authMW)
+	runSkillRouter(secureGroup, skillService, loggers.Main, authMW)
+	runResolutionCodeRouter(secureGroup, resolutionCodeService, loggers.Main, authMW)
+	runWorkCalendarRouter(secureGroup, workCalendarService, loggers.Main, authMW)
+	runOrgStructureRouter(secureGroup, orgStructureService, loggers.Main, authMW)
+	runCustomFieldRouter(secureGroup, customFieldService, loggers.Main, authMW)
+	runOrderTypeValidationRouter(secureGroup, orderTypeValidationService, loggers.Main, authMW)
+	runCalendarFeedRouter(api, secureGroup, calendarFeedService, limiter, cfg.RateLimit, loggers.Main)
+	runEventReplayRouter(secureGroup, eventReplayService, loggers.Main, authMW)
+	runAuditRouter(secureGroup, auditService, loggers.Main, authMW)
+	runRetentionRouter(secureGroup, retentionService, loggers.Main, authMW)
+	runBackupRouter(secureGroup, backupService, loggers.Main, authMW)
+	runBusinessCalendarRouter(secureGroup, businessCalendarService, loggers.Main, authMW)
+	runUserAnonymizationRouter(secureGroup, userAnonymizationService, loggers.User, authMW)
+	runImpersonationRouter(secureGroup, impersonationService, jwtSvc, cfg.Auth, loggers.Auth, authMW)
+	runGraphQLRouter(secureGroup, graphqlapi.Dependencies{
+		OrderService:   orderService,
+		UserService:    userService,
+		HistoryService: historyService,
+		UserRepo:       userRepo,
+		AttachmentRepo: attachRepo,
+		HistoryRepo:    historyRepo,
+		StatusRepo:     statusRepo,
+		PriorityRepo:   priorityRepo,
+		DepartmentRepo: departmentRepo,
+		OrderTypeRepo:  orderTypeRepo,
+	}, loggers.Main.Named("GraphQL"))
+	telegramBot := runTelegramRouter(e, userService, orderService, equipmentService, tgService, cacheRepo, statusDirectory, userRepo, historyRepo, authPermissionService, orderTypeRepo, resolutionCodeRepo, authMW, limiter, cfg, loggers.Main, appCtx)
 
 	// для интеграции
-	runSyncRouter(api, dbConn, cfg, loggers)
+	runSyncRouter(api, dbConn, cfg, loggers, appCtx)
+	runAnalyticsRouter(api, analyticsService, cfg.Integrations.AnalyticsApiKeys, loggers.Main, authMW)
+	runDocsRouter(api, cfg.Docs, loggers.Main)
+	runPortalRouter(api, portalService, limiter, cfg.Portal, loggers.Main)
 	// Dashboard
 	secureGroup.GET("/dashboard", dashboardController.GetDashboardStats, authMW.AuthorizeAny(authz.DashboardView))
+	secureGroup.GET("/dashboard/executors", dashboardController.GetExecutorLeaderboard, authMW.AuthorizeAny(authz.DashboardView))
+	secureGroup.GET("/dashboard/aging", dashboardController.GetBacklogAging, authMW.AuthorizeAny(authz.DashboardView))
+	runDashboardReportRouter(secureGroup, services.NewDashboardReportService(dashboardService, cfg.Reports, loggers.Main), loggers.Main.Named("Dashboard"), authMW)
 
 	loggers.Main.Info("INIT_ROUTER: Создание маршрутов завершено")
+	return &Services{Order: orderService, User: userService, TelegramBot: telegramBot}
+}
+
+// apiRateRules — лимиты чтения и записи API из действующих настроек (RATE_LIMIT_* перечитываются на ходу).
+func apiRateRules(rateCfg config.RateLimitConfig) func() (ratelimit.Rule, ratelimit.Rule) {
+	return func() (ratelimit.Rule, ratelimit.Rule) {
+		active := rateCfg.Active()
+		if !active.Enabled {
+			return ratelimit.Rule{}, ratelimit.Rule{}
+		}
+		return active.Read, active.Write
+	}
+}
+
+// authRateRule — строгий лимит входа и восстановления пароля из действующих настроек.
+func authRateRule(rateCfg config.RateLimitConfig) func() (ratelimit.Rule, ratelimit.Rule) {
+	return func() (ratelimit.Rule, ratelimit.Rule) {
+		active := rateCfg.Active()
+		if !active.Enabled {
+			return ratelimit.Rule{}, ratelimit.Rule{}
+		}
+		return active.Auth, active.Auth
+	}
 }
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runRuntimeConfigRouter(
+	secureGroup *echo.Group,
+	configService services.RuntimeConfigServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	configCtrl := controllers.NewRuntimeConfigController(configService, logger)
+
+	adminConfig := secureGroup.Group("/admin/config")
+	{
+		adminConfig.GET("", configCtrl.Get, authMW.AuthorizeAny(authz.ConfigManage))
+		adminConfig.POST("/reload", configCtrl.Reload, authMW.AuthorizeAny(authz.ConfigManage))
+	}
+}
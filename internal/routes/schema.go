@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runSchemaRouter(
+	secureGroup *echo.Group,
+	schemaService services.SchemaServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	schemaCtrl := controllers.NewSchemaController(schemaService, logger)
+	secureGroup.GET("/admin/schema", schemaCtrl.GetVersion, authMW.AuthorizeAny(authz.ConfigManage))
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runSkillRouter(
+	secureGroup *echo.Group,
+	skillService services.SkillServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewSkillController(skillService, logger)
+
+	skills := secureGroup.Group("/skills")
+	{
+		skills.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.SkillsView))
+		skills.POST("", ctrl.Create, authMW.AuthorizeAny(authz.SkillsManage))
+		skills.DELETE("/:id", ctrl.Delete, authMW.AuthorizeAny(authz.SkillsManage))
+	}
+
+	secureGroup.GET("/user/:id/skills", ctrl.GetUserSkills, authMW.AuthorizeAny(authz.UsersView))
+	secureGroup.PUT("/user/:id/skills", ctrl.SetUserSkills, authMW.AuthorizeAny(authz.UsersUpdate))
+	secureGroup.GET("/order_type/:id/skills", ctrl.GetOrderTypeSkills, authMW.AuthorizeAny(authz.OrderTypesView))
+	secureGroup.PUT("/order_type/:id/skills", ctrl.SetOrderTypeSkills, authMW.AuthorizeAny(authz.OrderTypesUpdate))
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runSLAEscalationRouter(
+	secureGroup *echo.Group,
+	escalationService services.SLAEscalationServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	escalationCtrl := controllers.NewSLAEscalationController(escalationService, logger)
+
+	secureGroup.GET("/sla-escalation-settings", escalationCtrl.GetSettings, authMW.AuthorizeAny(authz.DepartmentsView))
+	departments := secureGroup.Group("/department")
+	{
+		departments.GET("/:id/sla-escalation", escalationCtrl.GetDepartmentSetting, authMW.AuthorizeAny(authz.DepartmentsView))
+		departments.PUT("/:id/sla-escalation", escalationCtrl.UpdateDepartmentSetting, authMW.AuthorizeAny(authz.DepartmentsUpdate))
+		departments.DELETE("/:id/sla-escalation", escalationCtrl.ResetDepartmentSetting, authMW.AuthorizeAny(authz.DepartmentsUpdate))
+	}
+}
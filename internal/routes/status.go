@@ -16,6 +16,7 @@ import (
 func runStatusRouter(
 	secureGroup *echo.Group,
 	dbConn *pgxpool.Pool,
+	statusDirectory services.StatusDirectoryInterface,
 	logger *zap.Logger,
 	authMW *middleware.AuthMiddleware,
 	fileStorage filestorage.FileStorageInterface,
@@ -24,14 +25,15 @@ func runStatusRouter(
 	statusRepository := repositories.NewStatusRepository(dbConn)
 	userRepository := repositories.NewUserRepository(dbConn, logger)
 
-	statusService := services.NewStatusService(statusRepository, userRepository, fileStorage, logger)
+	statusService := services.NewStatusService(statusRepository, statusDirectory, userRepository, fileStorage, logger)
 
 	statusCtrl := controllers.NewStatusController(statusService, logger)
+	notModified := conditionalDictionary(repositories.NewDictionaryVersionRepository(dbConn, logger), logger, "statuses")
 
 	statuses := secureGroup.Group("/status")
 	{
-		statuses.GET("", statusCtrl.GetStatuses, authMW.AuthorizeAny(authz.StatusesView))
-		statuses.GET("/:id", statusCtrl.FindStatus, authMW.AuthorizeAny(authz.StatusesView))
+		statuses.GET("", statusCtrl.GetStatuses, authMW.AuthorizeAny(authz.StatusesView), notModified)
+		statuses.GET("/:id", statusCtrl.FindStatus, authMW.AuthorizeAny(authz.StatusesView), notModified)
 		statuses.POST("", statusCtrl.CreateStatus, authMW.AuthorizeAny(authz.StatusesCreate))
 		statuses.PUT("/:id", statusCtrl.UpdateStatus, authMW.AuthorizeAny(authz.StatusesUpdate))
 		statuses.DELETE("/:id", statusCtrl.DeleteStatus, authMW.AuthorizeAny(authz.StatusesDelete))
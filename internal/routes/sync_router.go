@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"context"
 	"strings"
 
 	"request-system/internal/controllers"
@@ -19,9 +20,41 @@ func runSyncRouter(
 	dbConn *pgxpool.Pool,
 	cfg *config.Config,
 	loggers *Loggers,
+	appCtx context.Context,
 ) {
 	loggers.Main.Info("Инициализация роутера для синхронизации c 1С...")
 
+	dbHandler := newSyncHandler(dbConn, cfg, loggers)
+	syncService := services.NewSyncService(dbHandler, repositories.NewSyncJobRepository(dbConn, loggers.Main), loggers.Main)
+	syncController := controllers.NewSyncController(syncService, loggers.Main)
+
+	// Воркер нужен, если задания приходят хотя бы одним путём: через API или периодической загрузкой.
+	workerNeeded := false
+	if pullCfg := cfg.Integrations.OneCPull; pullCfg.URL != "" {
+		go syncService.StartPuller(appCtx, sync.NewPullClient(pullCfg, loggers.Main), pullCfg.Interval)
+		workerNeeded = true
+	}
+
+	if apiKey := strings.TrimSpace(cfg.Integrations.OneCApiKey); apiKey == "" {
+		loggers.Main.Error("ONE_C_API_KEY не установлен: роут /api/sync/1c отключен")
+	} else {
+		syncGroup := apiGroup.Group("/sync")
+		syncGroup.Use(middleware.KeyAuth(func(key string, c echo.Context) (bool, error) {
+			return key == apiKey, nil
+		}))
+
+		syncGroup.POST("/1c", syncController.HandleSyncFrom1C)
+		syncGroup.GET("/jobs/:id", syncController.GetJob)
+		workerNeeded = true
+	}
+
+	if workerNeeded {
+		go syncService.StartWorker(appCtx)
+	}
+}
+
+// newSyncHandler — обработчик справочников 1С; им же пользуется импорт CSV.
+func newSyncHandler(dbConn *pgxpool.Pool, cfg *config.Config, loggers *Loggers) sync.HandlerInterface {
 	txManager := repositories.NewTxManager(dbConn, loggers.Main)
 	branchRepo := repositories.NewBranchRepository(dbConn, loggers.Main)
 	officeRepo := repositories.NewOfficeRepository(dbConn, loggers.Main)
@@ -32,7 +65,7 @@ func runSyncRouter(
 	userRepo := repositories.NewUserRepository(dbConn, loggers.User)
 	roleRepo := repositories.NewRoleRepository(dbConn, loggers.Main)
 
-	dbHandler := sync.NewDBHandler(
+	return sync.NewDBHandler(
 		txManager,
 		branchRepo,
 		officeRepo,
@@ -45,20 +78,4 @@ func runSyncRouter(
 		&cfg.Integrations,
 		loggers.Main,
 	)
-
-	syncService := services.NewSyncService(dbHandler, loggers.Main)
-	syncController := controllers.NewSyncController(syncService, loggers.Main)
-
-	syncGroup := apiGroup.Group("/sync")
-	if strings.TrimSpace(cfg.Integrations.OneCApiKey) == "" {
-		loggers.Main.Error("ONE_C_API_KEY не установлен: роут /api/sync/1c отключен")
-		return
-	}
-
-	apiKey := strings.TrimSpace(cfg.Integrations.OneCApiKey)
-	syncGroup.Use(middleware.KeyAuth(func(key string, c echo.Context) (bool, error) {
-		return key == apiKey, nil
-	}))
-
-	syncGroup.POST("/1c", syncController.HandleSyncFrom1C)
 }
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runTeamRouter(
+	secureGroup *echo.Group,
+	teamService services.TeamServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewTeamController(teamService, logger)
+
+	teams := secureGroup.Group("/teams")
+	{
+		teams.GET("", ctrl.GetAll, authMW.AuthorizeAny(authz.TeamsView))
+		teams.GET("/:id", ctrl.GetByID, authMW.AuthorizeAny(authz.TeamsView))
+		teams.POST("", ctrl.Create, authMW.AuthorizeAny(authz.TeamsManage))
+		teams.PATCH("/:id", ctrl.Update, authMW.AuthorizeAny(authz.TeamsManage))
+		teams.DELETE("/:id", ctrl.Delete, authMW.AuthorizeAny(authz.TeamsManage))
+		teams.POST("/:id/members", ctrl.AddMembers, authMW.AuthorizeAny(authz.TeamsManage))
+		teams.DELETE("/:id/members/:userID", ctrl.RemoveMember, authMW.AuthorizeAny(authz.TeamsManage))
+	}
+}
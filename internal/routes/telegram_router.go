@@ -6,11 +6,13 @@ import (
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
+	"request-system/internal/authz"
 	tgCtrl "request-system/internal/controllers/telegram"
 	"request-system/internal/repositories"
 	"request-system/internal/services"
 	"request-system/pkg/config"
 	"request-system/pkg/middleware"
+	"request-system/pkg/ratelimit"
 	"request-system/pkg/telegram"
 )
 
@@ -18,48 +20,61 @@ func runTelegramRouter(
 	e *echo.Echo,
 	userService services.UserServiceInterface,
 	orderService services.OrderServiceInterface,
+	equipmentService services.EquipmentServiceInterface,
 	tgService telegram.ServiceInterface,
 	cacheRepo repositories.CacheRepositoryInterface,
-	statusRepo repositories.StatusRepositoryInterface,
+	statusDirectory services.StatusDirectoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	historyRepo repositories.OrderHistoryRepositoryInterface,
 
 	authPermissionService services.AuthPermissionServiceInterface,
 	orderTypeRepo repositories.OrderTypeRepositoryInterface,
+	resolutionCodeRepo repositories.ResolutionCodeRepositoryInterface,
 	authMW *middleware.AuthMiddleware,
+	limiter *ratelimit.Limiter,
 	cfg *config.Config,
 	logger *zap.Logger,
 	appCtx context.Context,
-) {
+) *tgCtrl.TelegramController {
 	tgIntegrationService := services.NewTelegramIntegrationService(cfg.Telegram, logger)
 
 	tgController := tgCtrl.NewTelegramController(
 		userService,
 		orderService,
+		equipmentService,
 		tgIntegrationService,
 		tgService,
 		cacheRepo,
-		statusRepo,
+		statusDirectory,
 		userRepo,
 		historyRepo,
 		authPermissionService,
 		logger,
 		orderTypeRepo,
+		resolutionCodeRepo,
 		cfg.Telegram,
+		cfg.Frontend,
+		limiter,
 	)
 
-	go tgController.StartCleanup(appCtx)
-
 	api := e.Group("/api")
 	secureGroup := api.Group("", authMW.Auth)
 
 	secureGroup.GET("/profile/telegram", tgController.HandleTelegramLinkStatus)
 	secureGroup.DELETE("/profile/telegram", tgController.HandleUnlinkTelegram)
+	secureGroup.DELETE("/me/telegram-link", tgController.HandleUnlinkTelegram)
 	secureGroup.POST("/profile/telegram/generate-token", tgController.HandleGenerateLinkToken)
+	secureGroup.DELETE("/admin/users/:id/telegram", tgController.HandleForceUnlinkTelegram, authMW.AuthorizeAny(authz.UsersUpdate))
 
 	if !tgIntegrationService.Enabled() {
 		logger.Warn("Telegram integration disabled: TELEGRAM_BOT_TOKEN is empty")
-		return
+		return tgController
+	}
+
+	if cfg.Telegram.PollingEnabled() {
+		// Webhook недоступен из внутренней сети — забираем обновления сами.
+		go tgController.StartPolling(appCtx)
+		return tgController
 	}
 
 	api.POST("/webhooks/telegram", tgController.HandleTelegramWebhook)
@@ -71,4 +86,6 @@ func runTelegramRouter(
 			logger.Error("Не удалось зарегистрировать Telegram Webhook", zap.Error(err))
 		}
 	}()
+
+	return tgController
 }
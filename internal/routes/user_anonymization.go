@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runUserAnonymizationRouter(
+	secureGroup *echo.Group,
+	anonymizationService services.UserAnonymizationServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewUserAnonymizationController(anonymizationService, logger)
+
+	secureGroup.POST("/admin/users/:id/anonymize", ctrl.Anonymize, authMW.AuthorizeAny(authz.UsersAnonymize))
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+)
+
+// Язык ответов API выбирает сам пользователь; отдельного права не нужно.
+func runUserLanguageRouter(
+	secureGroup *echo.Group,
+	languageService services.UserLanguageServiceInterface,
+	logger *zap.Logger,
+) {
+	languageCtrl := controllers.NewUserLanguageController(languageService, logger)
+
+	secureGroup.GET("/me/language", languageCtrl.GetMy)
+	secureGroup.PUT("/me/language", languageCtrl.UpdateMy)
+}
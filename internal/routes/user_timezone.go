@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+)
+
+// Часовой пояс пользователь выбирает сам; отдельного права не нужно.
+func runUserTimezoneRouter(
+	secureGroup *echo.Group,
+	timezoneService services.UserTimezoneServiceInterface,
+	logger *zap.Logger,
+) {
+	timezoneCtrl := controllers.NewUserTimezoneController(timezoneService, logger)
+
+	secureGroup.GET("/me/timezone", timezoneCtrl.GetMy)
+	secureGroup.PUT("/me/timezone", timezoneCtrl.UpdateMy)
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+func runWebhookRouter(
+	secureGroup *echo.Group,
+	webhookService services.WebhookServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	webhookCtrl := controllers.NewWebhookController(webhookService, logger)
+
+	webhooks := secureGroup.Group("/webhooks")
+	{
+		webhooks.POST("", webhookCtrl.Create, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.GET("", webhookCtrl.GetAll, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.GET("/:id", webhookCtrl.GetByID, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.PUT("/:id", webhookCtrl.Update, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.DELETE("/:id", webhookCtrl.Delete, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.GET("/:id/deliveries", webhookCtrl.GetDeliveries, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.GET("/deliveries/:id", webhookCtrl.GetDelivery, authMW.AuthorizeAny(authz.WebhooksManage))
+		webhooks.POST("/deliveries/:id/redeliver", webhookCtrl.Redeliver, authMW.AuthorizeAny(authz.WebhooksManage))
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/controllers"
+	"request-system/internal/services"
+	"request-system/pkg/middleware"
+)
+
+// runWorkCalendarRouter — менять календарь может руководитель команды без team:manage,
+// поэтому на маршрутах проверяется только team:view, остальное решает сервис.
+func runWorkCalendarRouter(
+	secureGroup *echo.Group,
+	workCalendarService services.WorkCalendarServiceInterface,
+	logger *zap.Logger,
+	authMW *middleware.AuthMiddleware,
+) {
+	ctrl := controllers.NewWorkCalendarController(workCalendarService, logger)
+
+	calendar := secureGroup.Group("/teams/:id/calendar")
+	{
+		calendar.GET("", ctrl.GetTeamCalendar, authMW.AuthorizeAny(authz.TeamsView))
+		calendar.POST("", ctrl.AddTeamEntry, authMW.AuthorizeAny(authz.TeamsView))
+		calendar.DELETE("/:entryID", ctrl.DeleteTeamEntry, authMW.AuthorizeAny(authz.TeamsView))
+	}
+}
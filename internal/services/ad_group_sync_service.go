@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	ldap "github.com/go-ldap/ldap/v3"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+)
+
+// ADGroupSyncServiceInterface назначает роли по группам Active Directory: при входе
+// пользователя и раз в сутки для всех учётных записей. Управляются только роли,
+// указанные в сопоставлениях; роли, выданные вручную, остаются как есть.
+type ADGroupSyncServiceInterface interface {
+	ListMappings(ctx context.Context) ([]dto.ADGroupMappingDTO, error)
+	CreateMapping(ctx context.Context, payload dto.CreateADGroupMappingDTO) (*dto.ADGroupMappingDTO, error)
+	DeleteMapping(ctx context.Context, id uint64) error
+	// RunSync — ручной запуск полной синхронизации (право integration:sync:run).
+	RunSync(ctx context.Context) (*dto.ADGroupSyncResultDTO, error)
+
+	Enabled() bool
+	ProvisioningEnabled() bool
+	// SyncUser обновляет роли пользователя по его текущим группам в AD.
+	SyncUser(ctx context.Context, userID uint64, username string) error
+	// ProvisionUser создаёт локального пользователя для учётной записи AD,
+	// если она входит хотя бы в одну сопоставленную группу.
+	ProvisionUser(ctx context.Context, username string) (*entities.User, error)
+	// Start запускает ежедневную синхронизацию в час LDAP_GROUP_SYNC_HOUR.
+	Start(ctx context.Context)
+}
+
+type ADGroupSyncService struct {
+	repo                  repositories.ADGroupMappingRepositoryInterface
+	userRepo              repositories.UserRepositoryInterface
+	statusRepo            repositories.StatusRepositoryInterface
+	adService             ADServiceInterface
+	authPermissionService AuthPermissionServiceInterface
+	ldapCfg               *config.LDAPConfig
+	logger                *zap.Logger
+}
+
+func NewADGroupSyncService(
+	repo repositories.ADGroupMappingRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	statusRepo repositories.StatusRepositoryInterface,
+	adService ADServiceInterface,
+	authPermissionService AuthPermissionServiceInterface,
+	ldapCfg *config.LDAPConfig,
+	logger *zap.Logger,
+) ADGroupSyncServiceInterface {
+	return &ADGroupSyncService{
+		repo:                  repo,
+		userRepo:              userRepo,
+		statusRepo:            statusRepo,
+		adService:             adService,
+		authPermissionService: authPermissionService,
+		ldapCfg:               ldapCfg,
+		logger:                logger,
+	}
+}
+
+func (s *ADGroupSyncService) Enabled() bool {
+	return s.ldapCfg.Active() && s.ldapCfg.GroupSyncEnabled
+}
+
+func (s *ADGroupSyncService) ProvisioningEnabled() bool {
+	return s.Enabled() && s.ldapCfg.ProvisionUsers
+}
+
+func (s *ADGroupSyncService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func toADGroupMappingDTO(m entities.ADGroupRoleMapping) dto.ADGroupMappingDTO {
+	return dto.ADGroupMappingDTO{
+		ID:        m.ID,
+		ADGroup:   m.ADGroup,
+		RoleID:    m.RoleID,
+		RoleName:  m.RoleName,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *ADGroupSyncService) ListMappings(ctx context.Context) ([]dto.ADGroupMappingDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.RolesView); err != nil {
+		return nil, err
+	}
+	mappings, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.ADGroupMappingDTO, 0, len(mappings))
+	for _, m := range mappings {
+		result = append(result, toADGroupMappingDTO(m))
+	}
+	return result, nil
+}
+
+func (s *ADGroupSyncService) CreateMapping(ctx context.Context, payload dto.CreateADGroupMappingDTO) (*dto.ADGroupMappingDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.RolesUpdate)
+	if err != nil {
+		return nil, err
+	}
+	group := strings.TrimSpace(payload.ADGroup)
+	if group == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указана группа AD", nil, nil)
+	}
+
+	m := &entities.ADGroupRoleMapping{ADGroup: group, RoleID: payload.RoleID}
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Добавлено сопоставление группы AD с ролью",
+		zap.String("group", group), zap.Uint64("roleID", m.RoleID), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toADGroupMappingDTO(*m)
+	return &result, nil
+}
+
+func (s *ADGroupSyncService) DeleteMapping(ctx context.Context, id uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.RolesUpdate)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалено сопоставление группы AD с ролью", zap.Uint64("mappingID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *ADGroupSyncService) RunSync(ctx context.Context) (*dto.ADGroupSyncResultDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.IntegrationsSyncRun); err != nil {
+		return nil, err
+	}
+	if !s.Enabled() {
+		return nil, apperrors.NewHttpError(http.StatusServiceUnavailable, "Синхронизация групп AD отключена в конфигурации.", nil, nil)
+	}
+	return s.syncAll(ctx)
+}
+
+func (s *ADGroupSyncService) SyncUser(ctx context.Context, userID uint64, username string) error {
+	if !s.Enabled() {
+		return nil
+	}
+	account, err := s.adService.FindAccount(username)
+	if err != nil || account == nil {
+		return err
+	}
+	mappings, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.applyRoles(ctx, userID, grantedADRoles(mappings, account.Groups))
+	return err
+}
+
+func (s *ADGroupSyncService) ProvisionUser(ctx context.Context, username string) (*entities.User, error) {
+	if !s.ProvisioningEnabled() {
+		return nil, apperrors.ErrUserNotFound
+	}
+	account, err := s.adService.FindAccount(username)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+	mappings, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := s.provisionAccount(ctx, *account, grantedADRoles(mappings, account.Groups))
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindUserByID(ctx, userID)
+}
+
+// provisionAccount создаёт пользователя из AD или привязывает логин к существующему
+// пользователю с тем же email, затем назначает роли по группам.
+func (s *ADGroupSyncService) provisionAccount(ctx context.Context, account ADAccount, granted []uint64) (uint64, error) {
+	if len(granted) == 0 {
+		s.logger.Info("Учётная запись AD не входит в сопоставленные группы, пользователь не создан", zap.String("username", account.Username))
+		return 0, apperrors.ErrUserNotFound
+	}
+	email := strings.ToLower(account.Email)
+	if email == "" {
+		s.logger.Warn("У учётной записи AD нет email, пользователь не создан", zap.String("username", account.Username))
+		return 0, apperrors.ErrUserNotFound
+	}
+
+	var userID uint64
+	existing, err := s.userRepo.FindUserByEmailOrLogin(ctx, email)
+	switch {
+	case err == nil:
+		if existing.Username != nil && *existing.Username != "" && !strings.EqualFold(*existing.Username, account.Username) {
+			s.logger.Warn("Email учётной записи AD занят другим пользователем",
+				zap.String("username", account.Username), zap.Uint64("userID", existing.ID))
+			return 0, apperrors.ErrConflict
+		}
+		username := account.Username
+		if err := s.userRepo.UpdateUsernameDirect(ctx, existing.ID, &username); err != nil {
+			return 0, err
+		}
+		userID = existing.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		statusID, err := s.statusRepo.FindIDByCode(ctx, constants.UserStatusActiveCode)
+		if err != nil {
+			return 0, err
+		}
+		fio := account.FIO
+		if fio == "" {
+			fio = account.Username
+		}
+		userID, err = s.repo.CreateUser(ctx, entities.ADProvisionedUser{
+			Username: account.Username,
+			Fio:      fio,
+			Email:    email,
+			StatusID: statusID,
+		})
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, err
+	}
+
+	if _, err := s.applyRoles(ctx, userID, granted); err != nil {
+		return 0, err
+	}
+	s.logger.Info("Пользователь создан из AD", zap.String("username", account.Username), zap.Uint64("userID", userID))
+	return userID, nil
+}
+
+func (s *ADGroupSyncService) applyRoles(ctx context.Context, userID uint64, granted []uint64) (bool, error) {
+	changed, err := s.repo.ApplyUserRoles(ctx, userID, granted)
+	if err != nil || !changed {
+		return changed, err
+	}
+	if err := s.authPermissionService.InvalidateUserPermissionsCache(ctx, userID); err != nil {
+		s.logger.Warn("Не удалось сбросить кэш прав после синхронизации ролей AD", zap.Uint64("userID", userID), zap.Error(err))
+	}
+	return true, nil
+}
+
+func (s *ADGroupSyncService) syncAll(ctx context.Context) (*dto.ADGroupSyncResultDTO, error) {
+	mappings, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ADGroupSyncResultDTO{}
+	err = s.adService.ForEachAccount(func(account ADAccount) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result.Scanned++
+		granted := grantedADRoles(mappings, account.Groups)
+
+		user, err := s.userRepo.FindUserByUsername(ctx, account.Username)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if !s.ldapCfg.ProvisionUsers || len(granted) == 0 {
+				result.Skipped++
+				return nil
+			}
+			if _, err := s.provisionAccount(ctx, account, granted); err != nil {
+				s.logger.Warn("Не удалось создать пользователя из AD", zap.String("username", account.Username), zap.Error(err))
+				result.Failed++
+				return nil
+			}
+			result.Provisioned++
+			return nil
+		}
+		if err != nil {
+			s.logger.Warn("Ошибка поиска пользователя при синхронизации AD", zap.String("username", account.Username), zap.Error(err))
+			result.Failed++
+			return nil
+		}
+
+		changed, err := s.applyRoles(ctx, user.ID, granted)
+		if err != nil {
+			s.logger.Warn("Не удалось обновить роли по группам AD", zap.Uint64("userID", user.ID), zap.Error(err))
+			result.Failed++
+			return nil
+		}
+		if changed {
+			result.Updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Синхронизация групп AD завершена",
+		zap.Int("scanned", result.Scanned), zap.Int("updated", result.Updated),
+		zap.Int("provisioned", result.Provisioned), zap.Int("failed", result.Failed))
+	return result, nil
+}
+
+func (s *ADGroupSyncService) Start(ctx context.Context) {
+	s.logger.Info("Ежедневная синхронизация групп AD запущена", zap.Int("hour", s.ldapCfg.GroupSyncHour))
+
+	for {
+		timer := time.NewTimer(time.Until(nextDailyOrderStatsRun(time.Now().In(time.Local), s.ldapCfg.GroupSyncHour)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.logger.Info("Ежедневная синхронизация групп AD остановлена")
+			return
+		case <-timer.C:
+			// LDAP могли выключить на ходу (перечитывание настроек) — тогда пропускаем запуск
+			if !s.Enabled() {
+				continue
+			}
+			if _, err := s.syncAll(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("Ошибка синхронизации групп AD", zap.Error(err))
+			}
+		}
+	}
+}
+
+// adGroupKeys — ключи, по которым группа пользователя сопоставляется с таблицей:
+// полный DN и CN первой компоненты, в нижнем регистре.
+func adGroupKeys(group string) []string {
+	clean := strings.ToLower(strings.TrimSpace(group))
+	if clean == "" {
+		return nil
+	}
+	keys := []string{clean}
+	dn, err := ldap.ParseDN(group)
+	if err != nil || len(dn.RDNs) == 0 {
+		return keys
+	}
+	for _, attr := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(attr.Type, "CN") {
+			if cn := strings.ToLower(strings.TrimSpace(attr.Value)); cn != clean {
+				keys = append(keys, cn)
+			}
+		}
+	}
+	return keys
+}
+
+// grantedADRoles возвращает отсортированные ID ролей, положенных по группам.
+func grantedADRoles(mappings []entities.ADGroupRoleMapping, groups []string) []uint64 {
+	byGroup := make(map[string][]uint64, len(mappings))
+	for _, m := range mappings {
+		key := strings.ToLower(strings.TrimSpace(m.ADGroup))
+		byGroup[key] = append(byGroup[key], m.RoleID)
+	}
+
+	granted := make([]uint64, 0)
+	for _, group := range groups {
+		for _, key := range adGroupKeys(group) {
+			for _, roleID := range byGroup[key] {
+				if !slices.Contains(granted, roleID) {
+					granted = append(granted, roleID)
+				}
+			}
+		}
+	}
+	slices.Sort(granted)
+	return granted
+}
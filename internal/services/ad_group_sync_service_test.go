@@ -0,0 +1,31 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"request-system/internal/entities"
+)
+
+func TestGrantedADRolesMatchesDNAndCN(t *testing.T) {
+	mappings := []entities.ADGroupRoleMapping{
+		{ADGroup: "CN=IT-Admins,OU=Groups,DC=corp,DC=local", RoleID: 3},
+		{ADGroup: "helpdesk", RoleID: 2},
+		{ADGroup: "HelpDesk", RoleID: 5},
+		{ADGroup: "Accounting", RoleID: 7},
+	}
+	groups := []string{
+		"cn=it-admins,ou=groups,dc=corp,dc=local",
+		"CN=HelpDesk,OU=Groups,DC=corp,DC=local",
+		"CN=Everyone,OU=Groups,DC=corp,DC=local",
+	}
+
+	got := grantedADRoles(mappings, groups)
+	if want := []uint64{2, 3, 5}; !slices.Equal(got, want) {
+		t.Fatalf("grantedADRoles = %v, want %v", got, want)
+	}
+
+	if got := grantedADRoles(mappings, nil); got == nil || len(got) != 0 {
+		t.Fatalf("без групп ожидался пустой список, а не nil: %v", got)
+	}
+}
@@ -12,22 +12,41 @@ import (
 	"request-system/internal/dto"
 	"request-system/pkg/config"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/ldappool"
 )
 
 type ADServiceInterface interface {
 	SearchUsers(searchQuery string) ([]dto.ADUserDTO, error)
 	FindExactUsernames(localParts []string) (map[string]string, error)
+	// Authenticate проверяет пароль пользователя привязкой DOMAIN\username.
+	Authenticate(username, password string) error
+	// FindAccount возвращает учётную запись с группами; nil, если пользователя нет в AD.
+	FindAccount(username string) (*ADAccount, error)
+	// ForEachAccount постранично обходит все учётные записи из SearchBaseDN.
+	ForEachAccount(fn func(ADAccount) error) error
+}
+
+// ADAccount — учётная запись AD с группами (значения атрибута GroupAttribute, обычно DN).
+type ADAccount struct {
+	Username string
+	FIO      string
+	Email    string
+	Groups   []string
 }
 
 type ADService struct {
 	ldapCfg *config.LDAPConfig
+	pool    *ldappool.Pool
 	logger  *zap.Logger
 }
 
-const adExactSearchBatchSize = 100
+const (
+	adExactSearchBatchSize = 100
+	adAccountsPageSize     = 500
+)
 
-func NewADService(ldapCfg *config.LDAPConfig, logger *zap.Logger) ADServiceInterface {
-	return &ADService{ldapCfg: ldapCfg, logger: logger}
+func NewADService(ldapCfg *config.LDAPConfig, pool *ldappool.Pool, logger *zap.Logger) ADServiceInterface {
+	return &ADService{ldapCfg: ldapCfg, pool: pool, logger: logger}
 }
 
 func (s *ADService) ldapTimeout() time.Duration {
@@ -76,21 +95,56 @@ func (s *ADService) searchAttributes(extra ...string) []string {
 	return attrs
 }
 
+// dialAndBind берёт соединение из пула и привязывается сервисной учётной записью.
+// Соединение из пула могло устареть, поэтому при обрыве сети пробуем ещё раз с новым.
+// Вернуть соединение нужно через s.pool.Put.
 func (s *ADService) dialAndBind(logPrefix string) (*ldap.Conn, error) {
-	conn, err := ldap.DialURL(fmt.Sprintf("ldap://%s:%d", s.ldapCfg.Host, s.ldapCfg.Port))
-	if err != nil {
-		s.logger.Error(logPrefix+" LDAP dial failed", zap.Error(err))
-		return nil, apperrors.ErrInternalServer
-	}
+	for attempt := 0; ; attempt++ {
+		conn, err := s.pool.Get()
+		if err != nil {
+			s.logger.Error(logPrefix+" LDAP dial failed", zap.Error(err))
+			return nil, apperrors.ErrInternalServer
+		}
 
-	conn.SetTimeout(s.ldapTimeout())
-	if err := conn.Bind(s.ldapCfg.BindDN, s.ldapCfg.BindPassword); err != nil {
-		s.logger.Error(logPrefix+" LDAP bind failed", zap.String("bind_dn", s.ldapCfg.BindDN), zap.Error(err))
+		conn.SetTimeout(s.ldapTimeout())
+		err = conn.Bind(s.ldapCfg.BindDN, s.ldapCfg.BindPassword)
+		if err == nil {
+			return conn, nil
+		}
 		conn.Close()
+		if ldappool.IsNetworkError(err) && attempt == 0 {
+			continue
+		}
+		s.logger.Error(logPrefix+" LDAP bind failed", zap.String("bind_dn", s.ldapCfg.BindDN), zap.Error(err))
 		return nil, apperrors.ErrInternalServer
 	}
+}
+
+func (s *ADService) Authenticate(username, password string) error {
+	userRDN := fmt.Sprintf(`%s\%s`, s.ldapCfg.Domain, username)
+	for attempt := 0; ; attempt++ {
+		conn, err := s.pool.Get()
+		if err != nil {
+			s.logger.Error("Не удалось подключиться к LDAP-серверу", zap.Error(err), zap.Duration("timeout", s.ldapTimeout()))
+			return apperrors.NewHttpError(http.StatusInternalServerError, "Ошибка подключения к сервису аутентификации", err, nil)
+		}
 
-	return conn, nil
+		conn.SetTimeout(s.ldapTimeout())
+		err = conn.Bind(userRDN, password)
+		if err == nil || ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			s.pool.Put(conn)
+			if err != nil {
+				return apperrors.ErrInvalidCredentials
+			}
+			return nil
+		}
+		conn.Close()
+		if ldappool.IsNetworkError(err) && attempt == 0 {
+			continue
+		}
+		s.logger.Error("LDAP bind failed", zap.String("username", username), zap.Error(err))
+		return apperrors.NewHttpError(http.StatusInternalServerError, "Системная ошибка аутентификации", err, nil)
+	}
 }
 
 func buildExactUsernamesBatchFilter(usernameAttribute string, localParts []string) string {
@@ -176,7 +230,7 @@ func (s *ADService) SearchUsers(searchQuery string) ([]dto.ADUserDTO, error) {
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
+	defer s.pool.Put(conn)
 
 	// Собираем фильтр из шаблона в конфиге
 	filter := buildSearchFilter(s.ldapCfg.SearchFilterPattern, searchQuery)
@@ -248,7 +302,7 @@ func (s *ADService) FindExactUsernames(localParts []string) (map[string]string,
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
+	defer s.pool.Put(conn)
 	conn.SetTimeout(s.ldapTimeout())
 
 	for start := 0; start < len(orderedLocalParts); start += adExactSearchBatchSize {
@@ -284,3 +338,88 @@ func (s *ADService) FindExactUsernames(localParts []string) (map[string]string,
 
 	return result, nil
 }
+
+func (s *ADService) groupAttribute() string {
+	if clean := strings.TrimSpace(s.ldapCfg.GroupAttribute); clean != "" {
+		return clean
+	}
+	return "memberOf"
+}
+
+func (s *ADService) emailAttribute() string {
+	if clean := strings.TrimSpace(s.ldapCfg.EmailAttribute); clean != "" {
+		return clean
+	}
+	return "mail"
+}
+
+func (s *ADService) accountSearchRequest(filter string) *ldap.SearchRequest {
+	return ldap.NewSearchRequest(
+		s.ldapCfg.SearchBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter,
+		[]string{s.usernameAttribute(), s.ldapCfg.FIOAttribute, s.emailAttribute(), s.groupAttribute()},
+		nil,
+	)
+}
+
+func (s *ADService) accountFromEntry(entry *ldap.Entry) ADAccount {
+	return ADAccount{
+		Username: strings.TrimSpace(entry.GetAttributeValue(s.usernameAttribute())),
+		FIO:      strings.TrimSpace(entry.GetAttributeValue(s.ldapCfg.FIOAttribute)),
+		Email:    strings.TrimSpace(entry.GetAttributeValue(s.emailAttribute())),
+		Groups:   entry.GetAttributeValues(s.groupAttribute()),
+	}
+}
+
+func (s *ADService) FindAccount(username string) (*ADAccount, error) {
+	clean := strings.TrimSpace(username)
+	if clean == "" {
+		return nil, nil
+	}
+
+	conn, err := s.dialAndBind("[AD_ACCOUNT]")
+	if err != nil {
+		return nil, err
+	}
+	defer s.pool.Put(conn)
+
+	filter := fmt.Sprintf("(&(objectClass=person)(%s=%s))", s.usernameAttribute(), ldap.EscapeFilter(clean))
+	sr, err := conn.Search(s.accountSearchRequest(filter))
+	if err != nil {
+		s.logger.Error("[AD_ACCOUNT] Ошибка поиска учётной записи", zap.String("username", clean), zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
+	if len(sr.Entries) == 0 {
+		return nil, nil
+	}
+
+	account := s.accountFromEntry(sr.Entries[0])
+	return &account, nil
+}
+
+func (s *ADService) ForEachAccount(fn func(ADAccount) error) error {
+	conn, err := s.dialAndBind("[AD_ACCOUNTS]")
+	if err != nil {
+		return err
+	}
+	defer s.pool.Put(conn)
+
+	filter := fmt.Sprintf("(&(objectClass=person)(%s=*))", s.usernameAttribute())
+	sr, err := conn.SearchWithPaging(s.accountSearchRequest(filter), adAccountsPageSize)
+	if err != nil {
+		s.logger.Error("[AD_ACCOUNTS] Ошибка выгрузки учётных записей", zap.Error(err))
+		return apperrors.ErrInternalServer
+	}
+
+	for _, entry := range sr.Entries {
+		account := s.accountFromEntry(entry)
+		if account.Username == "" {
+			continue
+		}
+		if err := fn(account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
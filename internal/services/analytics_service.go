@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+	"request-system/pkg/utils"
+)
+
+const (
+	AnalyticsDefaultLimit uint64 = 1000
+	AnalyticsMaxLimit     uint64 = 10000
+)
+
+type AnalyticsServiceInterface interface {
+	GetOrderFacts(ctx context.Context, filter dto.AnalyticsOrderFilterDTO) (*dto.AnalyticsOrdersPageDTO, error)
+}
+
+// AnalyticsService отдаёт заявки всей организации для BI. Доступ — по праву analytics:read
+// (у пользователя или у API-ключа); области видимости здесь не применяются.
+type AnalyticsService struct {
+	repo   repositories.AnalyticsRepositoryInterface
+	logger *zap.Logger
+}
+
+func NewAnalyticsService(repo repositories.AnalyticsRepositoryInterface, logger *zap.Logger) AnalyticsServiceInterface {
+	return &AnalyticsService{repo: repo, logger: logger}
+}
+
+func (s *AnalyticsService) GetOrderFacts(ctx context.Context, filter dto.AnalyticsOrderFilterDTO) (*dto.AnalyticsOrdersPageDTO, error) {
+	permissionsMap, err := utils.GetPermissionsMapFromCtx(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+	if !authz.CanDo(authz.AnalyticsRead, authz.Context{Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = AnalyticsDefaultLimit
+	}
+	if limit > AnalyticsMaxLimit {
+		limit = AnalyticsMaxLimit
+	}
+
+	// Берём на одну строку больше, чтобы понять, есть ли следующая страница, без COUNT(*).
+	facts, err := s.repo.FindOrderFacts(ctx, repositories.AnalyticsOrderFilter{
+		AfterID:     filter.AfterID,
+		Limit:       limit + 1,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		UpdatedFrom: filter.UpdatedFrom,
+		UpdatedTo:   filter.UpdatedTo,
+	})
+	if err != nil {
+		s.logger.Error("Не удалось получить выгрузку заявок для аналитики", zap.Error(err))
+		return nil, err
+	}
+
+	page := &dto.AnalyticsOrdersPageDTO{Limit: limit}
+	if uint64(len(facts)) > limit {
+		facts = facts[:limit]
+		page.HasMore = true
+	}
+	if facts == nil {
+		facts = make([]types.AnalyticsOrderFact, 0)
+	}
+	page.List = facts
+	if page.HasMore {
+		lastID := facts[len(facts)-1].OrderID
+		page.NextAfterID = &lastID
+	}
+	return page, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+)
+
+type analyticsRepoStub struct {
+	facts      []types.AnalyticsOrderFact
+	lastFilter repositories.AnalyticsOrderFilter
+}
+
+func (s *analyticsRepoStub) FindOrderFacts(_ context.Context, filter repositories.AnalyticsOrderFilter) ([]types.AnalyticsOrderFact, error) {
+	s.lastFilter = filter
+	result := make([]types.AnalyticsOrderFact, 0)
+	for _, fact := range s.facts {
+		if fact.OrderID > filter.AfterID && uint64(len(result)) < filter.Limit {
+			result = append(result, fact)
+		}
+	}
+	return result, nil
+}
+
+func TestAnalyticsGetOrderFacts_KeysetPages(t *testing.T) {
+	repo := &analyticsRepoStub{}
+	for id := uint64(1); id <= 5; id++ {
+		repo.facts = append(repo.facts, types.AnalyticsOrderFact{OrderID: id})
+	}
+	service := NewAnalyticsService(repo, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserPermissionsMapKey, map[string]bool{authz.AnalyticsRead: true})
+
+	first, err := service.GetOrderFacts(ctx, dto.AnalyticsOrderFilterDTO{Limit: 3})
+	if err != nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	if len(first.List) != 3 || !first.HasMore || first.NextAfterID == nil || *first.NextAfterID != 3 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	if repo.lastFilter.Limit != 4 {
+		t.Fatalf("expected one extra row to be requested, got limit %d", repo.lastFilter.Limit)
+	}
+
+	second, err := service.GetOrderFacts(ctx, dto.AnalyticsOrderFilterDTO{Limit: 3, AfterID: *first.NextAfterID})
+	if err != nil {
+		t.Fatalf("second page failed: %v", err)
+	}
+	if len(second.List) != 2 || second.HasMore || second.NextAfterID != nil {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+
+	if _, err := service.GetOrderFacts(ctx, dto.AnalyticsOrderFilterDTO{Limit: AnalyticsMaxLimit * 2}); err != nil || repo.lastFilter.Limit != AnalyticsMaxLimit+1 {
+		t.Fatalf("expected limit to be capped, got %d (%v)", repo.lastFilter.Limit, err)
+	}
+}
+
+func TestAnalyticsGetOrderFacts_RequiresPermission(t *testing.T) {
+	service := NewAnalyticsService(&analyticsRepoStub{}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserPermissionsMapKey, map[string]bool{authz.DashboardView: true})
+
+	if _, err := service.GetOrderFacts(ctx, dto.AnalyticsOrderFilterDTO{}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+}
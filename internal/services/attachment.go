@@ -25,6 +25,7 @@ type AttachmentService struct {
 	orderRepo   repositories.OrderRepositoryInterface
 	userRepo    repositories.UserRepositoryInterface
 	historyRepo repositories.OrderHistoryRepositoryInterface
+	grantRepo   repositories.OrderAccessGrantRepositoryInterface
 	fileStorage filestorage.FileStorageInterface
 	logger      *zap.Logger
 }
@@ -34,6 +35,7 @@ func NewAttachmentService(
 	orderRepo repositories.OrderRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	historyRepo repositories.OrderHistoryRepositoryInterface,
+	grantRepo repositories.OrderAccessGrantRepositoryInterface,
 	fileStorage filestorage.FileStorageInterface,
 	logger *zap.Logger,
 ) AttachmentServiceInterface {
@@ -42,6 +44,7 @@ func NewAttachmentService(
 		orderRepo:   orderRepo,
 		userRepo:    userRepo,
 		historyRepo: historyRepo,
+		grantRepo:   grantRepo,
 		fileStorage: fileStorage,
 		logger:      logger,
 	}
@@ -171,10 +174,14 @@ func (s *AttachmentService) buildOrderAuthzContext(ctx context.Context, order *e
 		}
 	}
 
+	accessGrant, _ := s.grantRepo.FindActiveLevel(ctx, order.ID, userID)
+
 	return &authz.Context{
 		Actor:         actor,
 		Permissions:   permissionsMap,
+		Conditions:    utils.GetPermissionConditionsFromCtx(ctx),
 		Target:        order,
 		IsParticipant: isParticipant,
+		AccessGrant:   accessGrant,
 	}, nil
 }
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+// AuditedEntities — сущности (первый сегмент пути /api/<entity>), для которых в журнал
+// пишутся снимки строки до и после вызова, и их таблицы.
+var AuditedEntities = map[string]string{
+	"user":           "users",
+	"role":           "roles",
+	"permission":     "permissions",
+	"order_rule":     "order_routing_rules",
+	"order_type":     "order_types",
+	"status":         "statuses",
+	"priority":       "priorities",
+	"department":     "departments",
+	"otdel":          "otdels",
+	"branch":         "branches",
+	"office":         "offices",
+	"position":       "positions",
+	"equipment":      "equipments",
+	"equipment_type": "equipment_types",
+	"webhooks":       "webhook_subscriptions",
+}
+
+type AuditServiceInterface interface {
+	Record(ctx context.Context, entry *entities.AuditLogEntry)
+	Snapshot(ctx context.Context, entity string, id uint64) json.RawMessage
+	GetAuditLog(ctx context.Context, filter repositories.AuditLogFilter) (*dto.PaginatedResponse[dto.AuditLogEntryDTO], error)
+}
+
+type AuditService struct {
+	repo     repositories.AuditLogRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewAuditService(
+	repo repositories.AuditLogRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) AuditServiceInterface {
+	return &AuditService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+// Record сохраняет запись журнала. Ошибка только логируется: сбой аудита не должен
+// превращать уже выполненное изменение в ошибку для клиента.
+func (s *AuditService) Record(ctx context.Context, entry *entities.AuditLogEntry) {
+	if err := s.repo.Create(ctx, entry); err != nil {
+		s.logger.Error("Не удалось записать событие в журнал аудита",
+			zap.String("entity", entry.Entity),
+			zap.String("action", entry.Action),
+			zap.String("path", entry.Path),
+			zap.Error(err))
+	}
+}
+
+// Snapshot возвращает текущее состояние сущности или nil, если для неё снимки не ведутся.
+func (s *AuditService) Snapshot(ctx context.Context, entity string, id uint64) json.RawMessage {
+	table, ok := AuditedEntities[entity]
+	if !ok {
+		return nil
+	}
+	snapshot, err := s.repo.Snapshot(ctx, table, id)
+	if err != nil {
+		s.logger.Warn("Не удалось получить снимок сущности для аудита",
+			zap.String("entity", entity), zap.Uint64("id", id), zap.Error(err))
+		return nil
+	}
+	return snapshot
+}
+
+func (s *AuditService) GetAuditLog(ctx context.Context, filter repositories.AuditLogFilter) (*dto.PaginatedResponse[dto.AuditLogEntryDTO], error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.AuditView, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	items, total, err := s.repo.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.AuditLogEntryDTO, 0, len(items))
+	for _, item := range items {
+		list = append(list, dto.AuditLogEntryDTO{
+			ID:             item.ID,
+			ActorID:        item.ActorID,
+			Action:         item.Action,
+			Entity:         item.Entity,
+			EntityID:       item.EntityID,
+			Method:         item.Method,
+			Path:           item.Path,
+			StatusCode:     item.StatusCode,
+			Before:         item.Before,
+			After:          item.After,
+			IP:             item.IP,
+			UserAgent:      item.UserAgent,
+			RequestID:      item.RequestID,
+			ImpersonatorID: item.ImpersonatorID,
+			CreatedAt:      item.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+	return &dto.PaginatedResponse[dto.AuditLogEntryDTO]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"net"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -16,7 +15,6 @@ import (
 	"request-system/internal/entities"
 	"request-system/internal/repositories"
 
-	ldap "github.com/go-ldap/ldap/v3"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
@@ -38,6 +36,8 @@ type AuthServiceInterface interface {
 	VerifyResetCode(ctx context.Context, payload dto.VerifyCodeDTO) (*dto.VerifyCodeResponseDTO, error)
 	ResetPassword(ctx context.Context, payload dto.ResetPasswordDTO) error
 	UpdateMyProfile(ctx context.Context, payload dto.UpdateMyProfileDTO) (*dto.UserDTO, error)
+	// UploadMyAvatar уменьшает изображение, сохраняет копии и заменяет фото профиля.
+	UploadMyAvatar(ctx context.Context, content []byte) (*dto.AvatarDTO, error)
 }
 
 type AuthService struct {
@@ -49,6 +49,8 @@ type AuthService struct {
 	cfg         *config.AuthConfig
 	ldapCfg     *config.LDAPConfig
 	notifySvc   NotificationServiceInterface
+	adService   ADServiceInterface
+	adGroupSync ADGroupSyncServiceInterface
 }
 
 func NewAuthService(
@@ -60,6 +62,8 @@ func NewAuthService(
 	cfg *config.AuthConfig,
 	ldapCfg *config.LDAPConfig,
 	notifySvc NotificationServiceInterface,
+	adService ADServiceInterface,
+	adGroupSync ADGroupSyncServiceInterface,
 
 	_ PositionServiceInterface,
 	_ BranchServiceInterface,
@@ -76,32 +80,15 @@ func NewAuthService(
 		cfg:         cfg,
 		ldapCfg:     ldapCfg,
 		notifySvc:   notifySvc,
+		adService:   adService,
+		adGroupSync: adGroupSync,
 	}
 }
 
-// ... метод authenticateInAD остается без изменений ...
+// authenticateInAD проверяет пароль через пул соединений ADService
+// (TLS и переключение на резервные контроллеры домена).
 func (s *AuthService) authenticateInAD(username, password string) error {
-	dialer := &net.Dialer{Timeout: s.ldapCfg.Timeout}
-	l, err := ldap.DialURL(
-		fmt.Sprintf("ldap://%s:%d", s.ldapCfg.Host, s.ldapCfg.Port),
-		ldap.DialWithDialer(dialer),
-	)
-	if err != nil {
-		s.logger.Error("Не удалось подключиться к LDAP-серверу", zap.Error(err), zap.Duration("timeout", s.ldapCfg.Timeout))
-		return apperrors.NewHttpError(http.StatusInternalServerError, "Ошибка подключения к сервису аутентификации", err, nil)
-	}
-	defer l.Close()
-
-	userRDN := fmt.Sprintf(`%s\%s`, s.ldapCfg.Domain, username)
-	err = l.Bind(userRDN, password)
-	if err != nil {
-		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
-			return apperrors.ErrInvalidCredentials
-		}
-		s.logger.Error("LDAP bind failed", zap.String("username", username), zap.Error(err))
-		return apperrors.NewHttpError(http.StatusInternalServerError, "Системная ошибка аутентификации", err, nil)
-	}
-	return nil
+	return s.adService.Authenticate(username, password)
 }
 
 func isInvalidCredentialsError(err error) bool {
@@ -118,6 +105,9 @@ func (s *AuthService) Login(ctx context.Context, payload dto.LoginDTO) (*entitie
 	systemRootEmail := strings.ToLower(s.cfg.SystemRootLogin)
 
 	user, err := s.userRepo.FindUserByEmailOrLogin(ctx, loginInput)
+	if errors.Is(err, pgx.ErrNoRows) && s.canProvisionFromAD(loginInput) {
+		return s.loginProvisionedFromAD(ctx, loginInput, payload.Password)
+	}
 	if err != nil {
 		s.logger.Error("Ошибка при поиске пользователя (FindUserByEmailOrLogin)",
 			zap.String("login", loginInput),
@@ -137,13 +127,14 @@ func (s *AuthService) Login(ctx context.Context, payload dto.LoginDTO) (*entitie
 			authenticated = true
 		}
 	} else {
-		if s.ldapCfg.Enabled {
+		if s.ldapCfg.Active() {
 			adUsername := loginInput
 			if user.Username != nil && *user.Username != "" {
 				adUsername = *user.Username
 			}
 			if err := s.authenticateInAD(adUsername, payload.Password); err == nil {
 				authenticated = true
+				s.syncADGroups(ctx, user.ID, adUsername)
 			} else if !isInvalidCredentialsError(err) {
 				s.logger.Error("LDAP authentication system error", zap.String("login", loginInput), zap.String("ad_username", adUsername), zap.Error(err))
 				return nil, err
@@ -171,6 +162,42 @@ func (s *AuthService) Login(ctx context.Context, payload dto.LoginDTO) (*entitie
 	return user, nil
 }
 
+// canProvisionFromAD — неизвестного пользователя можно создать из AD, если включено
+// автосоздание и логин похож на имя учётной записи, а не на email.
+func (s *AuthService) canProvisionFromAD(login string) bool {
+	return s.ldapCfg.Active() && s.adGroupSync != nil && s.adGroupSync.ProvisioningEnabled() &&
+		login != "" && !strings.Contains(login, "@")
+}
+
+// loginProvisionedFromAD проверяет пароль в AD и только после этого создаёт локального пользователя.
+func (s *AuthService) loginProvisionedFromAD(ctx context.Context, login, password string) (*entities.User, error) {
+	if err := s.authenticateInAD(login, password); err != nil {
+		if isInvalidCredentialsError(err) {
+			return nil, apperrors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	user, err := s.adGroupSync.ProvisionUser(ctx, login)
+	if err != nil {
+		s.logger.Warn("Не удалось создать пользователя из AD при входе", zap.String("login", login), zap.Error(err))
+		return nil, apperrors.ErrInvalidCredentials
+	}
+	if user.StatusCode != constants.UserStatusActiveCode {
+		return nil, apperrors.ErrUserDisabled
+	}
+	return user, nil
+}
+
+// syncADGroups обновляет роли по группам AD; ошибка не мешает входу.
+func (s *AuthService) syncADGroups(ctx context.Context, userID uint64, adUsername string) {
+	if s.adGroupSync == nil {
+		return
+	}
+	if err := s.adGroupSync.SyncUser(ctx, userID, adUsername); err != nil {
+		s.logger.Warn("Не удалось обновить роли по группам AD при входе", zap.Uint64("userID", userID), zap.Error(err))
+	}
+}
+
 // === ОБНОВЛЕННЫЙ МЕТОД GetUserByID ДЛЯ /auth/me ===
 func (s *AuthService) GetUserByID(ctx context.Context, userID uint64) (*dto.UserProfileDTO, error) {
 	// 1. Базовые данные из User Repo (он джойнит таблицы имен Branch/Otdel)
@@ -231,6 +258,8 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID uint64) (*dto.User
 		RoleIDs:     roleIDs,
 		PositionIDs: positionIDs,
 		OtdelIDs:    otdelIDs,
+
+		Telegram: telegramChannelOf(user),
 	}
 
 	return res, nil
@@ -289,9 +318,7 @@ func (s *AuthService) UpdateMyProfile(ctx context.Context, payload dto.UpdateMyP
 	}
 
 	if shouldDeleteOldPhoto(oldPhotoURL, updatedUser.PhotoURL) {
-		if err := s.fileStorage.Delete(*oldPhotoURL); err != nil {
-			s.logger.Warn("Не удалось удалить старое фото профиля", zap.Uint64("user_id", userID), zap.String("photo_url", *oldPhotoURL), zap.Error(err))
-		}
+		s.deletePhotoFiles(userID, *oldPhotoURL)
 	}
 
 	return &dto.UserDTO{
@@ -311,6 +338,19 @@ func (s *AuthService) UpdateMyProfile(ctx context.Context, payload dto.UpdateMyP
 	}, nil
 }
 
+// deletePhotoFiles удаляет фото вместе с уменьшенными копиями аватара (если их нет — ничего страшного).
+func (s *AuthService) deletePhotoFiles(userID uint64, photoURL string) {
+	urls := []string{photoURL}
+	for _, size := range avatarVariantSizes {
+		urls = append(urls, filestorage.VariantPath(photoURL, strconv.Itoa(size)))
+	}
+	for _, url := range urls {
+		if err := s.fileStorage.Delete(url); err != nil {
+			s.logger.Warn("Не удалось удалить старое фото профиля", zap.Uint64("user_id", userID), zap.String("photo_url", url), zap.Error(err))
+		}
+	}
+}
+
 func shouldDeleteOldPhoto(oldPhotoURL *string, newPhotoURL *string) bool {
 	if oldPhotoURL == nil || *oldPhotoURL == "" {
 		return false
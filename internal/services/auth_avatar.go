@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	_ "image/gif"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"request-system/config"
+	"request-system/internal/dto"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/filestorage"
+	"request-system/pkg/utils"
+)
+
+const (
+	avatarUploadContext = "avatar"
+	avatarMaxSide       = 1024
+)
+
+// avatarVariantSizes — уменьшенные копии аватара (большая сторона, px) для списков и чатов.
+var avatarVariantSizes = []int{256, 64}
+
+func (s *AuthService) UploadMyAvatar(ctx context.Context, content []byte) (*dto.AvatarDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := config.UploadContexts[avatarUploadContext]
+	imgCfg, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не удалось прочитать изображение", err, nil)
+	}
+	if imgCfg.Width < rules.MinWidth || imgCfg.Height < rules.MinHeight {
+		return nil, apperrors.NewBadRequestError("Изображение слишком маленькое: нужно не меньше " +
+			strconv.Itoa(rules.MinWidth) + "x" + strconv.Itoa(rules.MinHeight) + " px")
+	}
+	if imgCfg.Width > rules.MaxWidth || imgCfg.Height > rules.MaxHeight {
+		return nil, apperrors.NewBadRequestError("Изображение слишком большое: не больше " +
+			strconv.Itoa(rules.MaxWidth) + "x" + strconv.Itoa(rules.MaxHeight) + " px")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не удалось прочитать изображение", err, nil)
+	}
+	// JPEG остаётся JPEG, PNG и GIF сохраняются в PNG, чтобы не потерять прозрачность.
+	asPNG := format != "jpeg"
+
+	encoded, ext, err := filestorage.EncodeImage(filestorage.ResizeToFit(img, avatarMaxSide), asPNG)
+	if err != nil {
+		return nil, err
+	}
+	savedPath, err := s.fileStorage.Save(bytes.NewReader(encoded), "avatar"+ext, rules.PathPrefix)
+	if err != nil {
+		s.logger.Error("Не удалось сохранить аватар", zap.Uint64("user_id", userID), zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
+	photoURL := "/uploads/" + savedPath
+
+	result := &dto.AvatarDTO{PhotoURL: photoURL, Variants: make(map[string]string, len(avatarVariantSizes))}
+	for _, size := range avatarVariantSizes {
+		variant, _, err := filestorage.EncodeImage(filestorage.ResizeToFit(img, size), asPNG)
+		if err == nil {
+			_, err = s.fileStorage.SaveVariant(bytes.NewReader(variant), savedPath, strconv.Itoa(size))
+		}
+		if err != nil {
+			s.logger.Error("Не удалось сохранить уменьшенную копию аватара", zap.Uint64("user_id", userID), zap.Int("size", size), zap.Error(err))
+			s.deletePhotoFiles(userID, photoURL)
+			return nil, apperrors.ErrInternalServer
+		}
+		result.Variants[strconv.Itoa(size)] = filestorage.VariantPath(photoURL, strconv.Itoa(size))
+	}
+
+	// Старое фото и его копии удаляет UpdateMyProfile.
+	if _, err := s.UpdateMyProfile(ctx, dto.UpdateMyProfileDTO{PhotoURL: &photoURL}); err != nil {
+		s.deletePhotoFiles(userID, photoURL)
+		return nil, err
+	}
+	return result, nil
+}
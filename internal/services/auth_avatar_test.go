@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	"request-system/pkg/filestorage"
+)
+
+type avatarTxManagerStub struct{}
+
+func (avatarTxManagerStub) RunInTransaction(_ context.Context, fn func(tx pgx.Tx) error) error {
+	return fn(nil)
+}
+
+type avatarUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	user *entities.User
+}
+
+func (s *avatarUserRepoStub) FindUserByID(context.Context, uint64) (*entities.User, error) {
+	copied := *s.user
+	return &copied, nil
+}
+
+func (s *avatarUserRepoStub) FindUserByIDInTx(ctx context.Context, _ pgx.Tx, id uint64) (*entities.User, error) {
+	return s.FindUserByID(ctx, id)
+}
+
+func (s *avatarUserRepoStub) UpdateUser(_ context.Context, _ pgx.Tx, user *entities.User) error {
+	s.user = user
+	return nil
+}
+
+type avatarFileStorageStub struct {
+	files   map[string][]byte
+	deleted []string
+}
+
+func (s *avatarFileStorageStub) Save(file io.Reader, name, prefix string) (string, error) {
+	data, _ := io.ReadAll(file)
+	filePath := prefix + "/new-" + name
+	s.files[filePath] = data
+	return filePath, nil
+}
+
+func (s *avatarFileStorageStub) SaveVariant(file io.Reader, filePath, variant string) (string, error) {
+	data, _ := io.ReadAll(file)
+	variantPath := filestorage.VariantPath(filePath, variant)
+	s.files[variantPath] = data
+	return variantPath, nil
+}
+
+func (s *avatarFileStorageStub) Open(filePath string) (io.ReadCloser, error) {
+	data, ok := s.files[filePath]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *avatarFileStorageStub) Delete(fileURL string) error {
+	s.deleted = append(s.deleted, fileURL)
+	return nil
+}
+
+func TestUploadMyAvatarStoresVariantsAndRemovesOldPhoto(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2000, 1000))
+	for y := 0; y < 1000; y++ {
+		for x := 0; x < 2000; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 80, A: 255})
+		}
+	}
+	var upload bytes.Buffer
+	if err := png.Encode(&upload, src); err != nil {
+		t.Fatal(err)
+	}
+
+	oldPhoto := "/uploads/avatars/old.png"
+	storage := &avatarFileStorageStub{files: map[string][]byte{}}
+	userRepo := &avatarUserRepoStub{user: &entities.User{ID: 5, PhotoURL: &oldPhoto}}
+	service := &AuthService{txManager: avatarTxManagerStub{}, userRepo: userRepo, fileStorage: storage, logger: zap.NewNop()}
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(5))
+	result, err := service.UploadMyAvatar(ctx, upload.Bytes())
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if result.PhotoURL != "/uploads/avatars/new-avatar.png" || *userRepo.user.PhotoURL != result.PhotoURL {
+		t.Fatalf("unexpected photo url %q (user has %q)", result.PhotoURL, *userRepo.user.PhotoURL)
+	}
+	for size, want := range map[string]image.Point{"": {1024, 512}, "256": {256, 128}, "64": {64, 32}} {
+		filePath := "avatars/new-avatar.png"
+		if size != "" {
+			filePath = filestorage.VariantPath(filePath, size)
+			if result.Variants[size] != "/uploads/"+filePath {
+				t.Fatalf("variant %s url = %q", size, result.Variants[size])
+			}
+		}
+		img, err := png.Decode(bytes.NewReader(storage.files[filePath]))
+		if err != nil {
+			t.Fatalf("decode %s: %v", filePath, err)
+		}
+		if got := img.Bounds().Size(); got != want {
+			t.Fatalf("%s size = %v, want %v", filePath, got, want)
+		}
+	}
+
+	if strings.Join(storage.deleted, ",") != "/uploads/avatars/old.png,/uploads/avatars/old_256.png,/uploads/avatars/old_64.png" {
+		t.Fatalf("unexpected deleted files: %v", storage.deleted)
+	}
+}
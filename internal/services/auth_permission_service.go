@@ -4,21 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"request-system/internal/authz"
+	"request-system/internal/events"
 	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/eventbus"
 
 	"go.uber.org/zap"
 )
 
 type AuthPermissionServiceInterface interface {
 	GetAllUserPermissions(ctx context.Context, userID uint64) ([]string, error)
+	// GetUserPermissionConditions — условия, с которыми права выданы ролями; кэшируется вместе с правами.
+	GetUserPermissionConditions(ctx context.Context, userID uint64) (map[string][]authz.Condition, error)
 	InvalidateUserPermissionsCache(ctx context.Context, userID uint64) error
+	// CheckImpersonator проверяет, что администратор, выпустивший токен имперсонации, всё ещё активен
+	// и сохранил право user:impersonate. Вызывается на каждом запросе с таким токеном.
+	CheckImpersonator(ctx context.Context, impersonatorID uint64) error
+	// InvalidateRolePermissionsCache сбрасывает кэш прав всех пользователей роли и возвращает их число.
+	InvalidateRolePermissionsCache(ctx context.Context, roleID uint64) (int, error)
+	// FlushPermissionsCache сбрасывает кэш прав всех пользователей.
+	FlushPermissionsCache(ctx context.Context) error
 }
 
 type AuthPermissionService struct {
 	permissionRepo repositories.PermissionRepositoryInterface
+	userRepo       repositories.UserRepositoryInterface
 	cacheRepo      repositories.CacheRepositoryInterface
 	logger         *zap.Logger
 	cacheTTL       time.Duration
@@ -26,20 +42,32 @@ type AuthPermissionService struct {
 
 func NewAuthPermissionService(
 	permissionRepo repositories.PermissionRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
 	cacheRepo repositories.CacheRepositoryInterface,
 	logger *zap.Logger,
 	cacheTTL time.Duration,
 ) AuthPermissionServiceInterface {
 	return &AuthPermissionService{
 		permissionRepo: permissionRepo,
+		userRepo:       userRepo,
 		cacheRepo:      cacheRepo,
 		logger:         logger,
 		cacheTTL:       cacheTTL,
 	}
 }
 
+// userCacheKeys — ключи кэша прав и условий пользователя в текущей версии кэша.
+func (s *AuthPermissionService) userCacheKeys(ctx context.Context, userID uint64) (permissionsKey, conditionsKey string) {
+	version, err := s.cacheRepo.Get(ctx, pkgconstants.PermissionsCacheVersionKey)
+	if err != nil || strings.TrimSpace(version) == "" {
+		version = "0"
+	}
+	return fmt.Sprintf("auth:permissions:v%s:user:%d", version, userID),
+		fmt.Sprintf("auth:permission_conditions:v%s:user:%d", version, userID)
+}
+
 func (s *AuthPermissionService) GetAllUserPermissions(ctx context.Context, userID uint64) ([]string, error) {
-	cacheKey := fmt.Sprintf("auth:permissions:user:%d", userID)
+	cacheKey, _ := s.userCacheKeys(ctx, userID)
 
 	cachedData, err := s.cacheRepo.Get(ctx, cacheKey)
 	if err == nil {
@@ -70,13 +98,92 @@ func (s *AuthPermissionService) GetAllUserPermissions(ctx context.Context, userI
 	return permissions, nil
 }
 
+func (s *AuthPermissionService) GetUserPermissionConditions(ctx context.Context, userID uint64) (map[string][]authz.Condition, error) {
+	_, cacheKey := s.userCacheKeys(ctx, userID)
+
+	cachedData, err := s.cacheRepo.Get(ctx, cacheKey)
+	if err == nil {
+		var conditions map[string][]authz.Condition
+		if err := json.Unmarshal([]byte(cachedData), &conditions); err == nil {
+			return conditions, nil
+		}
+		s.logger.Warn("Не удалось распарсить кэш условий привилегий", zap.Error(err))
+	}
+
+	conditions, err := s.permissionRepo.GetUserPermissionConditions(ctx, userID)
+	if err != nil {
+		s.logger.Error("Ошибка загрузки условий прав из БД", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
+
+	encoded, err := json.Marshal(conditions)
+	if err != nil {
+		s.logger.Error("Ошибка JSON", zap.Error(err))
+	} else if err := s.cacheRepo.Set(ctx, cacheKey, string(encoded), s.cacheTTL); err != nil {
+		s.logger.Error("Ошибка записи кэша", zap.Error(err))
+	}
+	return conditions, nil
+}
+
+func (s *AuthPermissionService) CheckImpersonator(ctx context.Context, impersonatorID uint64) error {
+	impersonator, err := s.userRepo.FindUserByID(ctx, impersonatorID)
+	if err != nil {
+		return apperrors.ErrUnauthorized
+	}
+	if impersonator.StatusCode != pkgconstants.UserStatusActiveCode {
+		return apperrors.ErrUserDisabled
+	}
+	permissions, err := s.GetAllUserPermissions(ctx, impersonatorID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(permissions, authz.UsersImpersonate) {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
+
 func (s *AuthPermissionService) InvalidateUserPermissionsCache(ctx context.Context, userID uint64) error {
-	cacheKey := fmt.Sprintf("auth:permissions:user:%d", userID)
+	cacheKey, conditionsKey := s.userCacheKeys(ctx, userID)
 	s.logger.Info("Попытка удаления кэша по ключу.", zap.String("cacheKey", cacheKey))
-	if err := s.cacheRepo.Del(ctx, cacheKey); err != nil {
+	if err := s.cacheRepo.Del(ctx, cacheKey, conditionsKey); err != nil {
 		s.logger.Error("Не удалось удалить кэш привилегий", zap.Uint64("userID", userID), zap.Error(err))
 		return err
 	}
 	s.logger.Info("Кэш привилегий успешно удален", zap.Uint64("userID", userID))
 	return nil
 }
+
+func (s *AuthPermissionService) InvalidateRolePermissionsCache(ctx context.Context, roleID uint64) (int, error) {
+	userIDs, err := s.userRepo.FindUserIDsByRoleID(ctx, roleID)
+	if err != nil {
+		s.logger.Error("Не удалось получить ID пользователей для инвалидации кеша", zap.Uint64("roleID", roleID), zap.Error(err))
+		return 0, err
+	}
+	for _, userID := range userIDs {
+		if err := s.InvalidateUserPermissionsCache(ctx, userID); err != nil {
+			return 0, err
+		}
+	}
+	s.logger.Info("Сброшен кэш привилегий пользователей роли", zap.Uint64("roleID", roleID), zap.Int("userCount", len(userIDs)))
+	return len(userIDs), nil
+}
+
+func (s *AuthPermissionService) FlushPermissionsCache(ctx context.Context) error {
+	version, err := s.cacheRepo.Incr(ctx, pkgconstants.PermissionsCacheVersionKey)
+	if err != nil {
+		s.logger.Error("Не удалось сбросить кэш привилегий", zap.Error(err))
+		return err
+	}
+	s.logger.Warn("Сброшен кэш привилегий всех пользователей", zap.Int64("version", version))
+	return nil
+}
+
+// publishPermissionsChanged сообщает об изменении прав; вызывается после коммита,
+// чтобы PermissionCacheListener не закэшировал заново старые права.
+func publishPermissionsChanged(ctx context.Context, bus *eventbus.Bus, event events.PermissionsChangedEvent) {
+	if bus == nil {
+		return
+	}
+	bus.Publish(context.WithoutCancel(ctx), event)
+}
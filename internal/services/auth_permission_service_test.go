@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+)
+
+type permissionNamesRepoStub struct {
+	repositories.PermissionRepositoryInterface
+	permissions map[uint64][]string
+	loads       int
+}
+
+func (r *permissionNamesRepoStub) GetAllUserPermissionsNames(_ context.Context, userID uint64) ([]string, error) {
+	r.loads++
+	return r.permissions[userID], nil
+}
+
+type roleUsersRepoStub struct {
+	repositories.UserRepositoryInterface
+	users map[uint64][]uint64
+}
+
+func (r *roleUsersRepoStub) FindUserIDsByRoleID(_ context.Context, roleID uint64) ([]uint64, error) {
+	return r.users[roleID], nil
+}
+
+func TestPermissionCacheInvalidation(t *testing.T) {
+	repo := &permissionNamesRepoStub{permissions: map[uint64][]string{1: {"order:view"}, 2: {"order:view"}}}
+	service := NewAuthPermissionService(repo, &roleUsersRepoStub{users: map[uint64][]uint64{10: {1}}},
+		&memoryCache{values: map[string]string{}}, zap.NewNop(), 10*time.Minute)
+	ctx := context.Background()
+
+	load := func(userID uint64) []string {
+		t.Helper()
+		permissions, err := service.GetAllUserPermissions(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return permissions
+	}
+
+	load(1)
+	load(2)
+	load(1)
+	if repo.loads != 2 {
+		t.Fatalf("повторное чтение должно идти из кэша, загрузок из БД: %d", repo.loads)
+	}
+
+	// Роль 10 отозвана у пользователя 1: сбрасывается только его кэш
+	repo.permissions[1] = nil
+	if count, err := service.InvalidateRolePermissionsCache(ctx, 10); err != nil || count != 1 {
+		t.Fatalf("InvalidateRolePermissionsCache: %d, %v", count, err)
+	}
+	if got := load(1); len(got) != 0 {
+		t.Fatalf("после сброса должны вернуться новые права, получено %v", got)
+	}
+	load(2)
+	if repo.loads != 3 {
+		t.Fatalf("кэш пользователя вне роли не должен сбрасываться, загрузок из БД: %d", repo.loads)
+	}
+
+	if err := service.FlushPermissionsCache(ctx); err != nil {
+		t.Fatal(err)
+	}
+	load(1)
+	load(2)
+	if repo.loads != 5 {
+		t.Fatalf("после полного сброса права всех читаются из БД, загрузок: %d", repo.loads)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/jobs"
+	"request-system/pkg/types"
+)
+
+type BackgroundJobServiceInterface interface {
+	List(ctx context.Context, state string, filter types.Filter) (*dto.PaginatedResponse[dto.BackgroundJobDTO], error)
+	Retry(ctx context.Context, id string) (*dto.BackgroundJobDTO, error)
+}
+
+// backgroundJobQueue — часть *jobs.Queue, нужная админке.
+type backgroundJobQueue interface {
+	List(ctx context.Context, state jobs.State, limit, offset int) ([]jobs.Job, uint64, error)
+	Retry(ctx context.Context, id string) (*jobs.Job, error)
+}
+
+// BackgroundJobService — просмотр очереди фоновых заданий и ручной перезапуск упавших.
+type BackgroundJobService struct {
+	queue    backgroundJobQueue
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewBackgroundJobService(queue backgroundJobQueue, userRepo repositories.UserRepositoryInterface, logger *zap.Logger) BackgroundJobServiceInterface {
+	return &BackgroundJobService{queue: queue, userRepo: userRepo, logger: logger}
+}
+
+func (s *BackgroundJobService) List(ctx context.Context, state string, filter types.Filter) (*dto.PaginatedResponse[dto.BackgroundJobDTO], error) {
+	if _, err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	jobState := jobs.State(state)
+	switch jobState {
+	case "":
+		jobState = jobs.StateDead
+	case jobs.StateScheduled, jobs.StateRunning, jobs.StateDead:
+	default:
+		return nil, apperrors.NewBadRequestError("state: допустимы scheduled, running, dead")
+	}
+
+	items, total, err := s.queue.List(ctx, jobState, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.BackgroundJobDTO, 0, len(items))
+	for i := range items {
+		list = append(list, toBackgroundJobDTO(&items[i]))
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+
+	return &dto.PaginatedResponse[dto.BackgroundJobDTO]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
+
+func (s *BackgroundJobService) Retry(ctx context.Context, id string) (*dto.BackgroundJobDTO, error) {
+	authContext, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.queue.Retry(ctx, id)
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		return nil, apperrors.NewHttpError(http.StatusNotFound, "Задание не найдено или уже выполнено", err, nil)
+	case errors.Is(err, jobs.ErrNotRetryable):
+		return nil, apperrors.NewHttpError(http.StatusConflict, "Задание сейчас выполняется", err, nil)
+	case err != nil:
+		return nil, err
+	}
+
+	s.logger.Info("Фоновое задание перезапущено вручную", zap.String("jobID", id), zap.String("type", job.Type), zap.Uint64("by", authContext.Actor.ID))
+	result := toBackgroundJobDTO(job)
+	return &result, nil
+}
+
+func (s *BackgroundJobService) authorize(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.JobsManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func toBackgroundJobDTO(job *jobs.Job) dto.BackgroundJobDTO {
+	return dto.BackgroundJobDTO{
+		ID:          job.ID,
+		Type:        job.Type,
+		State:       string(job.State),
+		Payload:     job.Payload,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/jobs"
+	"request-system/pkg/types"
+)
+
+type jobQueueStub struct {
+	listedState jobs.State
+	retryErr    error
+}
+
+func (q *jobQueueStub) List(_ context.Context, state jobs.State, limit, offset int) ([]jobs.Job, uint64, error) {
+	q.listedState = state
+	return []jobs.Job{{ID: "a", Type: "digest", State: state}}, 1, nil
+}
+
+func (q *jobQueueStub) Retry(_ context.Context, id string) (*jobs.Job, error) {
+	if q.retryErr != nil {
+		return nil, q.retryErr
+	}
+	return &jobs.Job{ID: id, Type: "digest", State: jobs.StateScheduled}, nil
+}
+
+func jobsAdminCtx(perms map[string]bool) context.Context {
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	return context.WithValue(ctx, contextkeys.UserPermissionsMapKey, perms)
+}
+
+func TestBackgroundJobList_DefaultsToDeadAndRequiresPermission(t *testing.T) {
+	queue := &jobQueueStub{}
+	service := NewBackgroundJobService(queue, &replayUserRepoStub{}, zap.NewNop())
+
+	if _, err := service.List(jobsAdminCtx(map[string]bool{}), "", types.Filter{}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.JobsManage, err)
+	}
+
+	result, err := service.List(jobsAdminCtx(map[string]bool{authz.JobsManage: true}), "", types.Filter{Limit: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queue.listedState != jobs.StateDead || len(result.List) != 1 || result.Pagination.TotalCount != 1 {
+		t.Fatalf("unexpected listing: state=%s result=%+v", queue.listedState, result)
+	}
+
+	if _, err := service.List(jobsAdminCtx(map[string]bool{authz.JobsManage: true}), "done", types.Filter{}); err == nil {
+		t.Fatal("expected an error for an unknown state")
+	}
+}
+
+func TestBackgroundJobRetry_MapsQueueErrors(t *testing.T) {
+	ctx := jobsAdminCtx(map[string]bool{authz.JobsManage: true})
+
+	cases := map[error]int{
+		jobs.ErrJobNotFound:  http.StatusNotFound,
+		jobs.ErrNotRetryable: http.StatusConflict,
+	}
+	for queueErr, code := range cases {
+		service := NewBackgroundJobService(&jobQueueStub{retryErr: queueErr}, &replayUserRepoStub{}, zap.NewNop())
+		_, err := service.Retry(ctx, "x")
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != code {
+			t.Fatalf("%v: expected HTTP %d, got %v", queueErr, code, err)
+		}
+	}
+
+	service := NewBackgroundJobService(&jobQueueStub{}, &replayUserRepoStub{}, zap.NewNop())
+	job, err := service.Retry(ctx, "x")
+	if err != nil || job.State != string(jobs.StateScheduled) {
+		t.Fatalf("unexpected retry result: %+v, %v", job, err)
+	}
+}
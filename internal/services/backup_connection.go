@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// backupMinPgVersion — --table-and-children и --load-via-partition-root есть в pg_dump с 16 версии.
+const backupMinPgVersion = 16
+
+var backupToolVersion = regexp.MustCompile(`\(PostgreSQL\) (\d+)`)
+
+// backupConnection — параметры подключения pg_dump и pg_restore. В аргументы попадают только
+// хост, порт, пользователь и база, пароль передаётся через PGPASSWORD в окружении процесса:
+// командную строку видит любой пользователь машины через ps и /proc.
+type backupConnection struct {
+	host     string
+	port     uint16
+	user     string
+	password string
+	database string
+	sslMode  string
+}
+
+func parseBackupConnection(dsn string) (backupConnection, error) {
+	cfg, err := pgconn.ParseConfig(dsn)
+	if err != nil {
+		return backupConnection{}, fmt.Errorf("не удалось разобрать строку подключения к базе: %w", err)
+	}
+	return backupConnection{
+		host:     cfg.Host,
+		port:     cfg.Port,
+		user:     cfg.User,
+		password: cfg.Password,
+		database: cfg.Database,
+		sslMode:  dsnSSLMode(dsn),
+	}, nil
+}
+
+// dsnSSLMode достаёт sslmode из строки подключения в виде URL или «ключ=значение»: pgconn
+// переводит его в tls.Config, а утилитам нужно исходное значение.
+func dsnSSLMode(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if u, err := url.Parse(dsn); err == nil {
+			return u.Query().Get("sslmode")
+		}
+		return ""
+	}
+	for _, field := range strings.Fields(dsn) {
+		if value, ok := strings.CutPrefix(field, "sslmode="); ok {
+			return strings.Trim(value, `'`)
+		}
+	}
+	return ""
+}
+
+// args — аргументы подключения к базе database (пустая — база из строки подключения).
+func (c backupConnection) args(database string) []string {
+	if database == "" {
+		database = c.database
+	}
+	return []string{
+		"--host=" + c.host,
+		"--port=" + strconv.Itoa(int(c.port)),
+		"--username=" + c.user,
+		"--dbname=" + database,
+	}
+}
+
+// env — переменные окружения для утилит поверх окружения процесса.
+func (c backupConnection) env() []string {
+	env := os.Environ()
+	if c.password != "" {
+		env = append(env, "PGPASSWORD="+c.password)
+	}
+	if c.sslMode != "" {
+		env = append(env, "PGSSLMODE="+c.sslMode)
+	}
+	return env
+}
+
+// checkBackupTool проверяет, что утилита запускается и её версия не ниже backupMinPgVersion.
+func checkBackupTool(ctx context.Context, runner backupCommandRunner, path string) error {
+	var out bytes.Buffer
+	if err := runner.Run(ctx, path, []string{"--version"}, nil, &out); err != nil {
+		return fmt.Errorf("не удалось запустить %s: %w", path, err)
+	}
+	match := backupToolVersion.FindStringSubmatch(out.String())
+	if match == nil {
+		return fmt.Errorf("не удалось определить версию %s: %q", path, strings.TrimSpace(out.String()))
+	}
+	major, _ := strconv.Atoi(match[1])
+	if major < backupMinPgVersion {
+		return fmt.Errorf("%s версии %d, для резервного копирования нужна %d или новее", path, major, backupMinPgVersion)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// countRestoreRows считает строки данных каждой таблицы в скрипте «pg_restore --data-only --file=-».
+// Скрипт только читается: восстанавливает копию сам pg_restore, а здесь берётся, сколько строк
+// в ней должно оказаться. У секционированной таблицы по блоку COPY на каждую секцию.
+func countRestoreRows(script io.Reader) (map[string]int64, error) {
+	reader := bufio.NewReaderSize(script, 64*1024)
+	rows := make(map[string]int64)
+	table := ""
+
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, readErr
+		}
+		trimmed := bytes.TrimRight(line, "\r\n")
+
+		switch {
+		case table != "" && string(trimmed) == `\.`:
+			table = ""
+		case table != "":
+			rows[table]++
+		default:
+			if name, ok := restoreCopyTable(string(trimmed)); ok {
+				table = name
+				// Пустая таблица тоже есть в копии
+				rows[table] += 0
+			}
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+	if table != "" {
+		return nil, fmt.Errorf("скрипт pg_restore оборвался в данных таблицы %s", table)
+	}
+	return rows, nil
+}
+
+// restoreCopyTable возвращает таблицу строки «COPY public.table (...) FROM stdin;».
+func restoreCopyTable(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[0] != "COPY" || !strings.HasSuffix(line, "FROM stdin;") {
+		return "", false
+	}
+	table := strings.TrimPrefix(fields[1], "public.")
+	return strings.Trim(table, `"`), true
+}
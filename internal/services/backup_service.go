@@ -0,0 +1,546 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/filestorage"
+	"request-system/pkg/jobs"
+	"request-system/pkg/types"
+	"request-system/pkg/websocket"
+)
+
+// BackupJobType — задание очереди pkg/jobs: резервная копия и её проверка или только проверка.
+const BackupJobType = "backup.run"
+
+const (
+	// BackupJobTimeout — сколько даётся одной копии; столько же другая копия считается идущей.
+	BackupJobTimeout = 2 * time.Hour
+	// backupFilePrefix — каталог копий внутри хранилища BACKUP_DIR.
+	backupFilePrefix = "pg_dump"
+	// backupStderrLimit — сколько последних байт stderr pg_dump/pg_restore попадает в ошибку.
+	backupStderrLimit = 2048
+)
+
+// backupJobQueue — часть *jobs.Queue, нужная для ручного запуска.
+type backupJobQueue interface {
+	Enqueue(ctx context.Context, jobType string, payload any, opts ...jobs.EnqueueOption) (*jobs.Job, error)
+}
+
+// backupCommandRunner запускает pg_dump и pg_restore; в тестах подменяется.
+type backupCommandRunner interface {
+	// env — окружение процесса; nil — окружение текущего процесса.
+	Run(ctx context.Context, name string, args []string, env []string, stdout io.Writer) error
+}
+
+type backupJobPayload struct {
+	TriggeredBy *uint64 `json:"triggered_by,omitempty"`
+	// VerifyOnly — только проверить последнюю копию, не снимая новую.
+	VerifyOnly bool `json:"verify_only,omitempty"`
+}
+
+type BackupServiceInterface interface {
+	GetBackups(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.BackupDTO], error)
+	GetBackup(ctx context.Context, id uint64) (*dto.BackupDTO, error)
+	// Trigger ставит в очередь новую копию (и её проверку, если BACKUP_VERIFY включён).
+	Trigger(ctx context.Context) (*dto.BackgroundJobDTO, error)
+	// TriggerVerify ставит в очередь проверку восстановления последней копии.
+	TriggerVerify(ctx context.Context) (*dto.BackgroundJobDTO, error)
+	// CheckTools проверяет при запуске строку подключения и версии pg_dump и pg_restore;
+	// пока проверка не пройдена, копии не ставятся в очередь.
+	CheckTools(ctx context.Context) error
+
+	// HandleJob — обработчик задания BackupJobType.
+	HandleJob(ctx context.Context, job jobs.Job) error
+}
+
+// BackupService снимает копии важных таблиц через pg_dump в хранилище копий и проверяет, что
+// последняя копия восстанавливается: pg_restore загружает её во временную базу, которая затем
+// удаляется. О сбоях копии и проверки узнают все, у кого есть backup:manage.
+type BackupService struct {
+	repo          repositories.BackupRepositoryInterface
+	storage       filestorage.FileStorageInterface
+	userRepo      repositories.UserRepositoryInterface
+	queue         backupJobQueue
+	centerService NotificationCenterServiceInterface
+	outboxService NotificationOutboxServiceInterface
+	runner        backupCommandRunner
+	cfg           config.BackupConfig
+	conn          backupConnection
+	logger        *zap.Logger
+
+	// toolsErr — почему копирование недоступно: ошибка строки подключения или CheckTools.
+	toolsErr error
+}
+
+func NewBackupService(
+	repo repositories.BackupRepositoryInterface,
+	storage filestorage.FileStorageInterface,
+	userRepo repositories.UserRepositoryInterface,
+	queue backupJobQueue,
+	centerService NotificationCenterServiceInterface,
+	outboxService NotificationOutboxServiceInterface,
+	cfg config.BackupConfig,
+	dsn string,
+	logger *zap.Logger,
+) BackupServiceInterface {
+	conn, connErr := parseBackupConnection(dsn)
+	return &BackupService{
+		repo:          repo,
+		storage:       storage,
+		userRepo:      userRepo,
+		queue:         queue,
+		centerService: centerService,
+		outboxService: outboxService,
+		runner:        execBackupRunner{},
+		cfg:           cfg,
+		conn:          conn,
+		logger:        logger,
+		toolsErr:      connErr,
+	}
+}
+
+func (s *BackupService) checkManage(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.BackupManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func toBackupDTO(e *entities.Backup) (dto.BackupDTO, error) {
+	result := dto.BackupDTO{
+		ID:           e.ID,
+		Status:       e.Status,
+		Tables:       e.Tables,
+		FilePath:     e.FilePath,
+		SizeBytes:    e.SizeBytes,
+		SHA256:       e.SHA256,
+		TriggeredBy:  e.TriggeredBy,
+		Error:        e.Error,
+		StartedAt:    e.StartedAt.Format(time.RFC3339),
+		VerifyStatus: e.VerifyStatus,
+		VerifyError:  e.VerifyError,
+	}
+	if len(e.VerifyReport) > 0 {
+		if err := json.Unmarshal(e.VerifyReport, &result.VerifyReport); err != nil {
+			return result, err
+		}
+	}
+	if e.FinishedAt != nil {
+		finishedAt := e.FinishedAt.Format(time.RFC3339)
+		result.FinishedAt = &finishedAt
+	}
+	if e.VerifiedAt != nil {
+		verifiedAt := e.VerifiedAt.Format(time.RFC3339)
+		result.VerifiedAt = &verifiedAt
+	}
+	return result, nil
+}
+
+func (s *BackupService) GetBackups(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.BackupDTO], error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	backups, total, err := s.repo.FindBackups(ctx, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dto.BackupDTO, 0, len(backups))
+	for i := range backups {
+		backup, err := toBackupDTO(&backups[i])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, backup)
+	}
+	return retentionPage(list, total, filter), nil
+}
+
+func (s *BackupService) GetBackup(ctx context.Context, id uint64) (*dto.BackupDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	backup, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := toBackupDTO(backup)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *BackupService) Trigger(ctx context.Context) (*dto.BackgroundJobDTO, error) {
+	return s.enqueue(ctx, false)
+}
+
+func (s *BackupService) TriggerVerify(ctx context.Context) (*dto.BackgroundJobDTO, error) {
+	return s.enqueue(ctx, true)
+}
+
+func (s *BackupService) CheckTools(ctx context.Context) error {
+	if s.toolsErr != nil {
+		return s.toolsErr
+	}
+	for _, path := range []string{s.cfg.PgDumpPath, s.cfg.PgRestorePath} {
+		if err := checkBackupTool(ctx, s.runner, path); err != nil {
+			s.toolsErr = err
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BackupService) enqueue(ctx context.Context, verifyOnly bool) (*dto.BackgroundJobDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.toolsErr != nil {
+		return nil, apperrors.NewHttpError(http.StatusServiceUnavailable, "Резервное копирование недоступно: "+s.toolsErr.Error(), s.toolsErr, nil)
+	}
+	if verifyOnly {
+		if _, err := s.repo.FindLatestCompleted(ctx); err != nil {
+			if apperrors.IsNotFound(err) {
+				return nil, apperrors.NewHttpError(http.StatusNotFound, "Нет ни одной успешной резервной копии", err, nil)
+			}
+			return nil, err
+		}
+	}
+	job, err := s.queue.Enqueue(ctx, BackupJobType, backupJobPayload{TriggeredBy: &authContext.Actor.ID, VerifyOnly: verifyOnly})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Резервное копирование поставлено в очередь", zap.String("jobID", job.ID),
+		zap.Bool("verifyOnly", verifyOnly), zap.Uint64("by", authContext.Actor.ID))
+	result := toBackgroundJobDTO(job)
+	return &result, nil
+}
+
+func (s *BackupService) HandleJob(ctx context.Context, job jobs.Job) error {
+	var payload backupJobPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.VerifyOnly {
+		backup, err := s.repo.FindLatestCompleted(ctx)
+		if err != nil {
+			return err
+		}
+		return s.verify(ctx, backup)
+	}
+
+	backup, err := s.backup(ctx, payload.TriggeredBy)
+	if err != nil {
+		return err
+	}
+	if backup == nil {
+		s.logger.Info("Резервная копия уже снимается, запуск пропущен", zap.String("jobID", job.ID))
+		return nil
+	}
+	if s.cfg.Verify {
+		if err := s.verify(ctx, backup); err != nil {
+			return err
+		}
+	}
+	s.prune(ctx)
+	return nil
+}
+
+// backup снимает копию таблиц BACKUP_TABLES. Дамп сначала пишется во временный файл: в хранилище
+// попадает только целая копия, а размер и контрольная сумма считаются по пути.
+func (s *BackupService) backup(ctx context.Context, triggeredBy *uint64) (*entities.Backup, error) {
+	backup, err := s.repo.CreateBackup(ctx, s.cfg.Tables, triggeredBy, BackupJobTimeout)
+	if err != nil || backup == nil {
+		return nil, err
+	}
+
+	filePath, size, sum, dumpErr := s.dump(ctx, backup.ID)
+	backup.Status = entities.BackupCompleted
+	if dumpErr != nil {
+		backup.Status = entities.BackupFailed
+		msg := dumpErr.Error()
+		backup.Error = &msg
+	} else {
+		backup.FilePath, backup.SizeBytes, backup.SHA256 = &filePath, size, &sum
+	}
+	// Результат сохраняется и тогда, когда контекст задания истёк.
+	if err := s.repo.FinishBackup(context.WithoutCancel(ctx), backup); err != nil {
+		s.logger.Error("Не удалось сохранить результат резервной копии", zap.Uint64("backupID", backup.ID), zap.Error(err))
+	}
+
+	if dumpErr != nil {
+		s.alert(ctx, backup, fmt.Sprintf("Резервная копия #%d не создана: %v", backup.ID, dumpErr))
+		return nil, dumpErr
+	}
+	s.logger.Info("Резервная копия создана", zap.Uint64("backupID", backup.ID),
+		zap.String("path", filePath), zap.Int64("bytes", size), zap.Int("tables", len(backup.Tables)))
+	return backup, nil
+}
+
+func (s *BackupService) dump(ctx context.Context, backupID uint64) (filePath string, size int64, sum string, err error) {
+	tmp, err := os.CreateTemp("", "backup-*.dump")
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	// order_history секционирована: секции попадают в копию вместе с родительской таблицей, а их
+	// данные загружаются через неё (pg_dump 16+)
+	args := append([]string{"--format=custom", "--no-owner", "--no-privileges", "--load-via-partition-root"}, s.conn.args("")...)
+	for _, table := range s.cfg.Tables {
+		args = append(args, "--table-and-children=public."+table)
+	}
+	hash := sha256.New()
+	counter := &backupByteCounter{}
+	if err := s.runner.Run(ctx, s.cfg.PgDumpPath, args, s.conn.env(), io.MultiWriter(tmp, hash, counter)); err != nil {
+		return "", 0, "", err
+	}
+	if counter.n == 0 {
+		return "", 0, "", errors.New("pg_dump не вернул данных")
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, "", err
+	}
+	filePath, err = s.storage.Save(tmp, fmt.Sprintf("backup-%d.dump", backupID), backupFilePrefix)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("не удалось сохранить копию в хранилище: %w", err)
+	}
+	return filePath, counter.n, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verify проверяет копию: файл из хранилища сверяется с контрольной суммой, pg_restore
+// (таблицы, последовательности и данные, без индексов и ключей) загружает её во временную базу,
+// и в каждой таблице копии должно оказаться столько строк, сколько было в её блоках COPY.
+func (s *BackupService) verify(ctx context.Context, backup *entities.Backup) error {
+	report, verifyErr := s.restore(ctx, backup)
+
+	status := entities.BackupVerifyPassed
+	var errMsg *string
+	if verifyErr != nil {
+		status = entities.BackupVerifyFailed
+		msg := verifyErr.Error()
+		errMsg = &msg
+	}
+	raw, _ := json.Marshal(report)
+	if err := s.repo.SaveVerification(context.WithoutCancel(ctx), backup.ID, status, raw, errMsg); err != nil {
+		s.logger.Error("Не удалось сохранить результат проверки резервной копии", zap.Uint64("backupID", backup.ID), zap.Error(err))
+	}
+
+	if verifyErr != nil {
+		s.alert(ctx, backup, fmt.Sprintf("Резервная копия #%d не прошла проверку восстановления: %v", backup.ID, verifyErr))
+		return verifyErr
+	}
+	s.logger.Info("Резервная копия проверена восстановлением", zap.Uint64("backupID", backup.ID), zap.Int("tables", len(report)))
+	return nil
+}
+
+func (s *BackupService) restore(ctx context.Context, backup *entities.Backup) ([]dto.BackupTableReportDTO, error) {
+	if backup.FilePath == nil {
+		return nil, errors.New("файл копии не сохранён")
+	}
+	tmp, err := s.fetch(backup)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	expected, err := s.countDumpRows(ctx, tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Временная база, а не схема: pg_dump пишет все имена со схемой public, и загрузить копию в
+	// другую схему можно только правкой текста скрипта.
+	database := fmt.Sprintf("backup_verify_%d", backup.ID)
+	report := make([]dto.BackupTableReportDTO, 0, len(backup.Tables))
+	err = s.repo.RestoreInScratch(ctx, database, func(scratch repositories.BackupScratch) error {
+		args := append([]string{"--no-owner", "--no-privileges", "--section=pre-data", "--section=data",
+			"--single-transaction", "--exit-on-error"}, s.conn.args(scratch.Database())...)
+		if err := s.runner.Run(ctx, s.cfg.PgRestorePath, append(args, tmp), s.conn.env(), io.Discard); err != nil {
+			return err
+		}
+		for _, table := range backup.Tables {
+			rows, ok := expected[table]
+			if !ok {
+				return fmt.Errorf("в копии нет данных таблицы %s", table)
+			}
+			count, err := scratch.CountRows(ctx, table)
+			if err != nil {
+				return fmt.Errorf("таблица %s: %w", table, err)
+			}
+			if count != rows {
+				return fmt.Errorf("таблица %s: в копии %d строк, восстановлено %d", table, rows, count)
+			}
+			report = append(report, dto.BackupTableReportDTO{Table: table, Rows: count})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// countDumpRows считает строки каждой таблицы в файле копии по скрипту данных pg_restore,
+// не подключаясь к базе.
+func (s *BackupService) countDumpRows(ctx context.Context, file string) (map[string]int64, error) {
+	script, output := io.Pipe()
+	restoreDone := make(chan error, 1)
+	go func() {
+		err := s.runner.Run(ctx, s.cfg.PgRestorePath, []string{"--data-only", "--file=-", file}, nil, output)
+		output.CloseWithError(err)
+		restoreDone <- err
+	}()
+
+	rows, err := countRestoreRows(script)
+	// pg_restore мог ещё писать: закрытие канала завершит его с ошибкой записи.
+	script.CloseWithError(errors.New("подсчёт завершён"))
+	restoreErr := <-restoreDone
+	if err != nil {
+		return nil, err
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return rows, nil
+}
+
+// fetch копирует файл копии из хранилища во временный файл, сверяя контрольную сумму:
+// pg_restore нужен файл, а повреждённую копию нет смысла восстанавливать.
+func (s *BackupService) fetch(backup *entities.Backup) (string, error) {
+	src, err := s.storage.Open(*backup.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("не удалось открыть файл копии: %w", err)
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp("", "backup-verify-*.dump")
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), src)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && backup.SHA256 != nil && hex.EncodeToString(hash.Sum(nil)) != *backup.SHA256 {
+		err = errors.New("контрольная сумма файла копии не совпадает")
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// prune удаляет файлы успешных копий старше BACKUP_KEEP последних.
+func (s *BackupService) prune(ctx context.Context) {
+	if s.cfg.Keep <= 0 {
+		return
+	}
+	backups, err := s.repo.FindPrunable(ctx, s.cfg.Keep)
+	if err != nil {
+		s.logger.Warn("Не удалось найти устаревшие резервные копии", zap.Error(err))
+		return
+	}
+	for _, backup := range backups {
+		if backup.FilePath != nil {
+			if err := s.storage.Delete(*backup.FilePath); err != nil {
+				s.logger.Warn("Не удалось удалить файл устаревшей копии", zap.Uint64("backupID", backup.ID), zap.Error(err))
+				continue
+			}
+		}
+		if err := s.repo.MarkPruned(ctx, backup.ID); err != nil {
+			s.logger.Warn("Не удалось отметить копию удалённой", zap.Uint64("backupID", backup.ID), zap.Error(err))
+		}
+	}
+}
+
+// alert пишет сбой в журнал и отправляет уведомление в центр уведомлений всем, у кого есть
+// backup:manage. Сбой оповещения не скрывает исходную ошибку — она уже в журнале.
+func (s *BackupService) alert(ctx context.Context, backup *entities.Backup, message string) {
+	ctx = context.WithoutCancel(ctx)
+	s.logger.Error("Сбой резервного копирования", zap.Uint64("backupID", backup.ID), zap.String("message", message))
+
+	recipients, err := s.repo.FindAlertRecipients(ctx, authz.BackupManage)
+	if err != nil {
+		s.logger.Error("Не удалось найти получателей оповещения о резервной копии", zap.Error(err))
+		return
+	}
+	for _, userID := range recipients {
+		payload := &websocket.NotificationPayload{
+			EventID:   uuid.New().String(),
+			Type:      "BACKUP_FAILED",
+			Actor:     websocket.ActorInfo{Name: "Система"},
+			Message:   message,
+			Changes:   []websocket.ChangeInfo{},
+			Links:     websocket.LinkInfo{Primary: fmt.Sprintf("/admin/backups/%d", backup.ID)},
+			CreatedAt: time.Now(),
+		}
+		if err := s.centerService.Save(ctx, userID, nil, payload); err != nil {
+			s.logger.Error("Не удалось сохранить оповещение о резервной копии", zap.Uint64("userID", userID), zap.Error(err))
+			continue
+		}
+		if err := s.outboxService.EnqueueWebSocket(ctx, userID, payload, "notification"); err != nil {
+			s.logger.Warn("Не удалось отправить оповещение о резервной копии", zap.Uint64("userID", userID), zap.Error(err))
+		}
+	}
+}
+
+type backupByteCounter struct{ n int64 }
+
+func (c *backupByteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+type execBackupRunner struct{}
+
+func (execBackupRunner) Run(ctx context.Context, name string, args []string, env []string, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = env
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > backupStderrLimit {
+			msg = msg[len(msg)-backupStderrLimit:]
+		}
+		if msg != "" {
+			return fmt.Errorf("%s: %w: %s", filepath.Base(name), err, msg)
+		}
+		return fmt.Errorf("%s: %w", filepath.Base(name), err)
+	}
+	return nil
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/jobs"
+	"request-system/pkg/websocket"
+)
+
+type backupRepoStub struct {
+	repositories.BackupRepositoryInterface
+	nextID       uint64
+	finished     []entities.Backup
+	verifyStatus string
+	verifyReport []dto.BackupTableReportDTO
+	verifyError  *string
+	prunable     []entities.Backup
+	pruned       []uint64
+	scratch      *backupScratchStub
+	restoredRows map[string]int64
+}
+
+func (r *backupRepoStub) CreateBackup(_ context.Context, tables []string, triggeredBy *uint64, _ time.Duration) (*entities.Backup, error) {
+	r.nextID++
+	return &entities.Backup{ID: r.nextID, Status: entities.BackupRunning, Tables: tables, TriggeredBy: triggeredBy, StartedAt: time.Now()}, nil
+}
+
+func (r *backupRepoStub) FinishBackup(_ context.Context, backup *entities.Backup) error {
+	r.finished = append(r.finished, *backup)
+	return nil
+}
+
+func (r *backupRepoStub) SaveVerification(_ context.Context, _ uint64, status string, report json.RawMessage, errMsg *string) error {
+	r.verifyStatus, r.verifyError = status, errMsg
+	return json.Unmarshal(report, &r.verifyReport)
+}
+
+func (r *backupRepoStub) FindLatestCompleted(context.Context) (*entities.Backup, error) {
+	for i := len(r.finished) - 1; i >= 0; i-- {
+		if r.finished[i].Status == entities.BackupCompleted {
+			backup := r.finished[i]
+			return &backup, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *backupRepoStub) FindPrunable(context.Context, int) ([]entities.Backup, error) {
+	return r.prunable, nil
+}
+
+func (r *backupRepoStub) MarkPruned(_ context.Context, id uint64) error {
+	r.pruned = append(r.pruned, id)
+	return nil
+}
+
+func (r *backupRepoStub) FindAlertRecipients(_ context.Context, permission string) ([]uint64, error) {
+	if permission != authz.BackupManage {
+		return nil, errors.New("unexpected permission")
+	}
+	return []uint64{1, 5}, nil
+}
+
+func (r *backupRepoStub) RestoreInScratch(_ context.Context, database string, fn func(scratch repositories.BackupScratch) error) error {
+	r.scratch = &backupScratchStub{database: database, rows: r.restoredRows}
+	return fn(r.scratch)
+}
+
+type backupScratchStub struct {
+	database string
+	rows     map[string]int64
+	missing  map[string]int64
+}
+
+func (s *backupScratchStub) Database() string {
+	return s.database
+}
+
+func (s *backupScratchStub) CountRows(_ context.Context, table string) (int64, error) {
+	return s.rows[table] - s.missing[table], nil
+}
+
+// backupRunnerStub вместо pg_dump отдаёт dump, вместо pg_restore --file=- — script.
+type backupRunnerStub struct {
+	dump       string
+	script     string
+	version    string
+	dumpErr    error
+	restoreErr error
+	calls      [][]string
+	envs       [][]string
+}
+
+func (r *backupRunnerStub) Run(_ context.Context, name string, args []string, env []string, stdout io.Writer) error {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	r.envs = append(r.envs, env)
+	switch {
+	case len(args) == 1 && args[0] == "--version":
+		version := r.version
+		if version == "" {
+			version = name + " (PostgreSQL) 17.2"
+		}
+		_, err := io.WriteString(stdout, version+"\n")
+		return err
+	case name == "pg_dump":
+		if r.dumpErr != nil {
+			return r.dumpErr
+		}
+		_, err := io.WriteString(stdout, r.dump)
+		return err
+	case slices.Contains(args, "--file=-"):
+		_, err := io.WriteString(stdout, r.script)
+		return err
+	}
+	return r.restoreErr
+}
+
+type backupCenterStub struct {
+	NotificationCenterServiceInterface
+	saved map[uint64][]string
+}
+
+func (c *backupCenterStub) Save(_ context.Context, userID uint64, _ *uint64, payload *websocket.NotificationPayload) error {
+	c.saved[userID] = append(c.saved[userID], payload.Message)
+	return nil
+}
+
+type backupOutboxStub struct {
+	NotificationOutboxServiceInterface
+	pushed []uint64
+}
+
+func (o *backupOutboxStub) EnqueueWebSocket(_ context.Context, userID uint64, _ interface{}, _ string) error {
+	o.pushed = append(o.pushed, userID)
+	return nil
+}
+
+const backupTestScript = `--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+
+COPY public.statuses (id, name) FROM stdin;
+1	Открыта
+2	Закрыта; без ошибок
+\.
+
+COPY public.orders (id, name) FROM stdin;
+1	public.orders в данных не меняется
+\.
+
+SELECT pg_catalog.setval('public.statuses_id_seq', 2, true);
+`
+
+func newBackupServiceForTest(repo *backupRepoStub, files *avatarFileStorageStub, runner *backupRunnerStub) (*BackupService, *backupCenterStub, *backupOutboxStub) {
+	center := &backupCenterStub{saved: map[uint64][]string{}}
+	outbox := &backupOutboxStub{}
+	cfg := config.BackupConfig{
+		Tables: []string{"statuses", "orders"}, PgDumpPath: "pg_dump", PgRestorePath: "pg_restore", Verify: true, Keep: 2,
+	}
+	service := NewBackupService(repo, files, &replayUserRepoStub{}, &retentionQueueStub{}, center, outbox, cfg,
+		"postgres://app:s3cret@db:5433/requests?sslmode=require", zap.NewNop()).(*BackupService)
+	service.runner = runner
+	return service, center, outbox
+}
+
+func TestBackup_RequiresPermission(t *testing.T) {
+	service, _, _ := newBackupServiceForTest(&backupRepoStub{}, &avatarFileStorageStub{files: map[string][]byte{}}, &backupRunnerStub{})
+
+	if _, err := service.Trigger(jobsAdminCtx(map[string]bool{})); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.BackupManage, err)
+	}
+	// Проверять нечего, пока нет ни одной успешной копии
+	_, err := service.TriggerVerify(jobsAdminCtx(map[string]bool{authz.BackupManage: true}))
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != 404 {
+		t.Fatalf("expected 404 without backups, got %v", err)
+	}
+}
+
+func TestBackup_JobDumpsVerifiesAndPrunes(t *testing.T) {
+	old := "pg_dump/old.dump"
+	repo := &backupRepoStub{
+		prunable:     []entities.Backup{{ID: 40, Status: entities.BackupCompleted, FilePath: &old}},
+		restoredRows: map[string]int64{"statuses": 2, "orders": 1},
+	}
+	files := &avatarFileStorageStub{files: map[string][]byte{}}
+	runner := &backupRunnerStub{dump: "PGDMP-archive", script: backupTestScript}
+	service, center, _ := newBackupServiceForTest(repo, files, runner)
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-1", Type: BackupJobType}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.finished) != 1 {
+		t.Fatalf("expected one finished backup, got %+v", repo.finished)
+	}
+	backup := repo.finished[0]
+	if backup.Status != entities.BackupCompleted || backup.SizeBytes != int64(len("PGDMP-archive")) || backup.SHA256 == nil {
+		t.Fatalf("unexpected backup: %+v", backup)
+	}
+	if string(files.files[*backup.FilePath]) != "PGDMP-archive" {
+		t.Fatalf("dump must be stored as is, got %q", files.files[*backup.FilePath])
+	}
+	dumpArgs := strings.Join(runner.calls[0], " ")
+	if !strings.Contains(dumpArgs, "--format=custom") || !strings.Contains(dumpArgs, "--table-and-children=public.statuses") || !strings.Contains(dumpArgs, "--table-and-children=public.orders") {
+		t.Fatalf("unexpected pg_dump call: %s", dumpArgs)
+	}
+	// Пароль не попадает в командную строку, только в окружение процесса
+	for _, arg := range []string{"--host=db", "--port=5433", "--username=app", "--dbname=requests"} {
+		if !slices.Contains(runner.calls[0], arg) {
+			t.Fatalf("pg_dump call must contain %s: %s", arg, dumpArgs)
+		}
+	}
+	for _, call := range runner.calls {
+		if strings.Contains(strings.Join(call, " "), "s3cret") {
+			t.Fatalf("password must not be passed in arguments: %v", call)
+		}
+	}
+	if !slices.Contains(runner.envs[0], "PGPASSWORD=s3cret") || !slices.Contains(runner.envs[0], "PGSSLMODE=require") {
+		t.Fatal("pg_dump must get the password and sslmode through the environment")
+	}
+
+	if repo.scratch.database != "backup_verify_1" {
+		t.Fatalf("unexpected scratch database: %s", repo.scratch.database)
+	}
+	restoreCall := runner.calls[2]
+	restoreArgs := strings.Join(restoreCall, " ")
+	if !slices.Contains(restoreCall, "--dbname=backup_verify_1") || !slices.Contains(restoreCall, "--single-transaction") ||
+		!strings.Contains(restoreArgs, "--section=data") || strings.Contains(restoreArgs, "post-data") {
+		t.Fatalf("unexpected pg_restore call: %s", restoreArgs)
+	}
+	if !slices.Contains(runner.envs[2], "PGPASSWORD=s3cret") {
+		t.Fatal("pg_restore must get the password through the environment")
+	}
+	if repo.verifyStatus != entities.BackupVerifyPassed || len(repo.verifyReport) != 2 || repo.verifyReport[0].Rows != 2 || repo.verifyReport[1].Rows != 1 {
+		t.Fatalf("unexpected verification: status=%s report=%+v err=%v", repo.verifyStatus, repo.verifyReport, repo.verifyError)
+	}
+
+	if len(files.deleted) != 1 || files.deleted[0] != old || len(repo.pruned) != 1 || repo.pruned[0] != 40 {
+		t.Fatalf("old backup must be pruned: deleted=%v pruned=%v", files.deleted, repo.pruned)
+	}
+	if len(center.saved) != 0 {
+		t.Fatalf("no alerts expected, got %+v", center.saved)
+	}
+}
+
+func TestBackup_VerifyFailureAlerts(t *testing.T) {
+	repo := &backupRepoStub{}
+	files := &avatarFileStorageStub{files: map[string][]byte{}}
+	runner := &backupRunnerStub{dump: "PGDMP-archive", script: backupTestScript}
+	service, center, outbox := newBackupServiceForTest(repo, files, runner)
+	service.cfg.Verify = false
+	repo.restoredRows = map[string]int64{"statuses": 2, "orders": 1}
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.scratch != nil {
+		t.Fatal("verification must not run when BACKUP_VERIFY is off")
+	}
+
+	// Строки потерялись при загрузке: проверка последней копии не пройдена
+	payload, _ := json.Marshal(backupJobPayload{VerifyOnly: true})
+	service.repo = &backupLossyRepoStub{backupRepoStub: repo}
+	err := service.HandleJob(context.Background(), jobs.Job{ID: "job-2", Payload: payload})
+	if err == nil || !strings.Contains(err.Error(), "orders") {
+		t.Fatalf("expected a row count error for orders, got %v", err)
+	}
+	if repo.verifyStatus != entities.BackupVerifyFailed || repo.verifyError == nil {
+		t.Fatalf("failed verification must be recorded: %s %v", repo.verifyStatus, repo.verifyError)
+	}
+	if len(center.saved[1]) != 1 || len(center.saved[5]) != 1 || len(outbox.pushed) != 2 {
+		t.Fatalf("both recipients must be alerted: saved=%+v pushed=%v", center.saved, outbox.pushed)
+	}
+}
+
+// backupLossyRepoStub теряет одну строку orders при загрузке во временную базу.
+type backupLossyRepoStub struct {
+	*backupRepoStub
+}
+
+func (r *backupLossyRepoStub) RestoreInScratch(ctx context.Context, database string, fn func(scratch repositories.BackupScratch) error) error {
+	return r.backupRepoStub.RestoreInScratch(ctx, database, func(scratch repositories.BackupScratch) error {
+		r.scratch.missing = map[string]int64{"orders": 1}
+		return fn(scratch)
+	})
+}
+
+func TestBackup_DumpFailureAlerts(t *testing.T) {
+	repo := &backupRepoStub{}
+	files := &avatarFileStorageStub{files: map[string][]byte{}}
+	runner := &backupRunnerStub{dumpErr: errors.New("pg_dump: connection refused")}
+	service, center, _ := newBackupServiceForTest(repo, files, runner)
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-1"}); err == nil {
+		t.Fatal("expected the dump error")
+	}
+	if len(repo.finished) != 1 || repo.finished[0].Status != entities.BackupFailed || repo.finished[0].Error == nil || repo.finished[0].FilePath != nil {
+		t.Fatalf("failed backup must be recorded: %+v", repo.finished)
+	}
+	if len(files.files) != 0 || repo.scratch != nil {
+		t.Fatal("nothing must be stored or verified after a failed dump")
+	}
+	if len(center.saved[1]) != 1 || !strings.Contains(center.saved[1][0], "connection refused") {
+		t.Fatalf("expected an alert with the cause, got %+v", center.saved)
+	}
+}
+
+func TestBackup_CheckToolsRejectsOldPgDump(t *testing.T) {
+	runner := &backupRunnerStub{version: "pg_dump (PostgreSQL) 15.6"}
+	service, _, _ := newBackupServiceForTest(&backupRepoStub{}, &avatarFileStorageStub{files: map[string][]byte{}}, runner)
+
+	if err := service.CheckTools(context.Background()); err == nil || !strings.Contains(err.Error(), "15") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, err := service.Trigger(jobsAdminCtx(map[string]bool{authz.BackupManage: true}))
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != 503 {
+		t.Fatalf("expected 503 while tools are unavailable, got %v", err)
+	}
+
+	runner.version = ""
+	service.toolsErr = nil
+	if err := service.CheckTools(context.Background()); err != nil {
+		t.Fatalf("pg_dump 17 must pass: %v", err)
+	}
+}
+
+func TestBackup_RestoreFailureFailsVerification(t *testing.T) {
+	repo := &backupRepoStub{}
+	runner := &backupRunnerStub{dump: "PGDMP-archive", script: backupTestScript, restoreErr: errors.New("pg_restore: ошибка: relation exists")}
+	service, _, _ := newBackupServiceForTest(repo, &avatarFileStorageStub{files: map[string][]byte{}}, runner)
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-1"}); err == nil || !strings.Contains(err.Error(), "relation exists") {
+		t.Fatalf("expected the pg_restore error, got %v", err)
+	}
+	if repo.verifyStatus != entities.BackupVerifyFailed {
+		t.Fatalf("verification must fail, got %s", repo.verifyStatus)
+	}
+}
+
+func TestCountRestoreRows_RejectsTruncatedCopy(t *testing.T) {
+	script := "COPY public.orders (id) FROM stdin;\n1\n2\n"
+	if _, err := countRestoreRows(strings.NewReader(script)); err == nil || !strings.Contains(err.Error(), "orders") {
+		t.Fatalf("expected an error for a COPY block without \\., got %v", err)
+	}
+}
+
+func TestCountRestoreRows_SumsPartitionCopies(t *testing.T) {
+	// pg_dump --load-via-partition-root пишет по блоку COPY в родительскую таблицу на каждую секцию
+	script := "COPY public.order_history (id) FROM stdin;\n1\n2\n\\.\n\nCOPY public.order_history (id) FROM stdin;\n3\n\\.\n" +
+		"COPY public.empty (id) FROM stdin;\n\\.\n"
+	rows, err := countRestoreRows(strings.NewReader(script))
+	if err != nil {
+		t.Fatalf("countRestoreRows: %v", err)
+	}
+	if rows["order_history"] != 3 {
+		t.Errorf("rows of all partitions must be counted, got %d", rows["order_history"])
+	}
+	if count, ok := rows["empty"]; !ok || count != 0 {
+		t.Errorf("an empty table must be reported with 0 rows, got %d, %v", count, ok)
+	}
+}
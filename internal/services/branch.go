@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"request-system/internal/authz"
@@ -21,6 +23,22 @@ const (
 	dateTimeLayout = "2006-01-02 15:04:05"
 )
 
+// orderPrefixPattern — префикс номера заявки: латиница и цифры, начинается с буквы.
+var orderPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// normalizeOrderPrefix приводит префикс к верхнему регистру; пустой префикс означает общий.
+// Новый префикс действует только для новых заявок, выданные номера не меняются.
+func normalizeOrderPrefix(raw string) (*string, error) {
+	prefix := strings.ToUpper(strings.TrimSpace(raw))
+	if prefix == "" {
+		return nil, nil
+	}
+	if !orderPrefixPattern.MatchString(prefix) {
+		return nil, apperrors.NewBadRequestError("Префикс номера заявки: от 2 до 10 латинских букв и цифр, начиная с буквы")
+	}
+	return &prefix, nil
+}
+
 type BranchServiceInterface interface {
 	GetBranches(ctx context.Context, filter types.Filter) ([]dto.BranchListResponseDTO, uint64, error)
 	FindBranch(ctx context.Context, id uint64) (*dto.BranchDTO, error)
@@ -59,7 +77,7 @@ func (s *BranchService) buildAuthzContext(ctx context.Context) (*authz.Context,
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
 
 // "Переводчик" из Entity в детальный DTO
@@ -84,6 +102,7 @@ func branchEntityToDTO(entity *entities.Branch) *dto.BranchDTO {
 		PhoneNumber: utils.GetStringFromPtr(entity.PhoneNumber),
 		Email:       utils.GetStringFromPtr(entity.Email),
 		EmailIndex:  utils.GetStringFromPtr(entity.EmailIndex),
+		OrderPrefix: utils.GetStringFromPtr(entity.OrderPrefix),
 		Status:      dtoStatus,
 	}
 
@@ -125,6 +144,7 @@ func (s *BranchService) GetBranches(ctx context.Context, filter types.Filter) ([
 			PhoneNumber: utils.GetStringFromPtr(b.PhoneNumber),
 			Email:       utils.GetStringFromPtr(b.Email),
 			EmailIndex:  utils.GetStringFromPtr(b.EmailIndex),
+			OrderPrefix: utils.GetStringFromPtr(b.OrderPrefix),
 			StatusID:    b.StatusID,
 		}
 		if b.OpenDate != nil {
@@ -172,6 +192,10 @@ func (s *BranchService) CreateBranch(ctx context.Context, payload dto.CreateBran
 		}
 		openDatePtr = &parsedDate
 	}
+	orderPrefix, err := normalizeOrderPrefix(payload.OrderPrefix)
+	if err != nil {
+		return nil, err
+	}
 	entity := entities.Branch{
 		Name:        payload.Name,
 		ShortName:   payload.ShortName,
@@ -180,6 +204,7 @@ func (s *BranchService) CreateBranch(ctx context.Context, payload dto.CreateBran
 		Email:       utils.StringToPtr(payload.Email),
 		EmailIndex:  utils.StringToPtr(payload.EmailIndex),
 		OpenDate:    openDatePtr,
+		OrderPrefix: orderPrefix,
 		StatusID:    payload.StatusID,
 	}
 
@@ -242,6 +267,13 @@ func (s *BranchService) UpdateBranch(ctx context.Context, id uint64, payload dto
 	if payload.StatusID != nil {
 		existingEntity.StatusID = *payload.StatusID
 	}
+	if payload.OrderPrefix != nil {
+		orderPrefix, err := normalizeOrderPrefix(*payload.OrderPrefix)
+		if err != nil {
+			return nil, err
+		}
+		existingEntity.OrderPrefix = orderPrefix
+	}
 	if payload.OpenDate != nil {
 		openDate, err := time.Parse(timeLayout, *payload.OpenDate)
 		if err != nil {
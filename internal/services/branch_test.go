@@ -0,0 +1,21 @@
+package services
+
+import "testing"
+
+func TestNormalizeOrderPrefix(t *testing.T) {
+	prefix, err := normalizeOrderPrefix(" hq2 ")
+	if err != nil || prefix == nil || *prefix != "HQ2" {
+		t.Fatalf("префикс приводится к верхнему регистру: %v %v", prefix, err)
+	}
+
+	prefix, err = normalizeOrderPrefix("")
+	if err != nil || prefix != nil {
+		t.Fatalf("пустой префикс сбрасывается на общий: %v %v", prefix, err)
+	}
+
+	for _, raw := range []string{"A", "1HQ", "HQ-1", "ФИЛИАЛ", "TOOLONGPREFIX"} {
+		if _, err := normalizeOrderPrefix(raw); err == nil {
+			t.Fatalf("префикс %q должен отклоняться", raw)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/businesshours"
+	apperrors "request-system/pkg/errors"
+)
+
+const (
+	// businessCalendarTTL — как долго реплика считает календарь из памяти; изменения на этой
+	// реплике сбрасывают его сразу, на остальных — через TTL.
+	businessCalendarTTL = time.Minute
+	// businessMeasureMaxPeriod — самый длинный период для проверочного расчёта.
+	businessMeasureMaxPeriod = 5 * 366 * 24 * time.Hour
+)
+
+// BusinessCalendarProvider отдаёт рабочий календарь для расчёта SLA, сроков и эскалаций.
+type BusinessCalendarProvider interface {
+	// Calendar возвращает действующий календарь; nil — календарь выключен, время идёт круглосуточно.
+	Calendar(ctx context.Context) *businesshours.Calendar
+}
+
+type BusinessCalendarServiceInterface interface {
+	BusinessCalendarProvider
+
+	GetCalendar(ctx context.Context) (*dto.BusinessCalendarDTO, error)
+	UpdateCalendar(ctx context.Context, d dto.UpdateBusinessCalendarDTO) (*dto.BusinessCalendarDTO, error)
+
+	// GetHolidays возвращает ежегодные праздники и даты указанного года.
+	GetHolidays(ctx context.Context, year int) ([]dto.BusinessHolidayDTO, error)
+	CreateHoliday(ctx context.Context, d dto.CreateBusinessHolidayDTO) (*dto.BusinessHolidayDTO, error)
+	UpdateHoliday(ctx context.Context, id uint64, d dto.UpdateBusinessHolidayDTO) (*dto.BusinessHolidayDTO, error)
+	DeleteHoliday(ctx context.Context, id uint64) error
+
+	// Measure считает рабочее время между двумя моментами по действующему календарю.
+	Measure(ctx context.Context, from, to time.Time) (*dto.BusinessDurationDTO, error)
+}
+
+// BusinessCalendarService ведёт рабочий календарь организации: рабочие дни недели, часы
+// и праздники. Время первого отклика и решения заявок считается только в рабочие часы.
+type BusinessCalendarService struct {
+	repo     repositories.BusinessCalendarRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	calendar *businesshours.Calendar
+	loadedAt time.Time
+}
+
+func NewBusinessCalendarService(
+	repo repositories.BusinessCalendarRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) BusinessCalendarServiceInterface {
+	return &BusinessCalendarService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+func (s *BusinessCalendarService) checkManage(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.BusinessCalendarManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+// Calendar читает календарь из памяти и перечитывает его раз в businessCalendarTTL. Если база
+// недоступна, остаётся прежний календарь; если его ещё не было — отсчёт идёт круглосуточно.
+func (s *BusinessCalendarService) Calendar(ctx context.Context) *businesshours.Calendar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < businessCalendarTTL {
+		return s.calendar
+	}
+
+	calendar, err := s.loadCalendar(ctx)
+	if err != nil {
+		s.logger.Warn("Не удалось загрузить рабочий календарь, используется прежний", zap.Error(err))
+		return s.calendar
+	}
+	s.calendar = calendar
+	s.loadedAt = time.Now()
+	return calendar
+}
+
+func (s *BusinessCalendarService) resetCalendar() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *BusinessCalendarService) loadCalendar(ctx context.Context) (*businesshours.Calendar, error) {
+	settings, err := s.repo.GetCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.IsEnabled {
+		return nil, nil
+	}
+	holidays, err := s.repo.FindHolidays(ctx, time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	return buildBusinessCalendar(settings, holidays)
+}
+
+func buildBusinessCalendar(settings *entities.BusinessCalendar, holidays []entities.BusinessHoliday) (*businesshours.Calendar, error) {
+	start, err := parseBusinessClock(settings.DayStart)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseBusinessClock(settings.DayEnd)
+	if err != nil {
+		return nil, err
+	}
+	workDays := make([]int, 0, len(settings.WorkDays))
+	for _, d := range settings.WorkDays {
+		workDays = append(workDays, int(d))
+	}
+
+	calendar := businesshours.New(time.Local, workDays, start, end)
+	for _, h := range holidays {
+		if h.IsWorkingDay {
+			calendar.AddWorkingDay(h.HolidayDate)
+		} else {
+			calendar.AddHoliday(h.HolidayDate, h.IsRecurring)
+		}
+	}
+	return calendar, nil
+}
+
+// parseBusinessClock переводит «ЧЧ:ММ» в минуты от полуночи; «24:00» — конец суток.
+func parseBusinessClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if !ok || errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, apperrors.NewHttpError(http.StatusBadRequest, fmt.Sprintf("Неверное время «%s», ожидается ЧЧ:ММ", value), nil, nil)
+	}
+	return h*60 + m, nil
+}
+
+func parseBusinessDate(value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат даты", err, nil)
+	}
+	return date, nil
+}
+
+func toBusinessCalendarDTO(e *entities.BusinessCalendar) *dto.BusinessCalendarDTO {
+	workDays := make([]int, 0, len(e.WorkDays))
+	for _, d := range e.WorkDays {
+		workDays = append(workDays, int(d))
+	}
+	return &dto.BusinessCalendarDTO{
+		WorkDays:  workDays,
+		DayStart:  e.DayStart,
+		DayEnd:    e.DayEnd,
+		IsEnabled: e.IsEnabled,
+		Timezone:  time.Local.String(),
+		UpdatedBy: e.UpdatedBy,
+		UpdatedAt: e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toBusinessHolidayDTO(e *entities.BusinessHoliday) dto.BusinessHolidayDTO {
+	return dto.BusinessHolidayDTO{
+		ID:           e.ID,
+		Date:         e.HolidayDate.Format("2006-01-02"),
+		Name:         e.Name,
+		IsRecurring:  e.IsRecurring,
+		IsWorkingDay: e.IsWorkingDay,
+		CreatedBy:    e.CreatedBy,
+		CreatedAt:    e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *BusinessCalendarService) GetCalendar(ctx context.Context) (*dto.BusinessCalendarDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.GetCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toBusinessCalendarDTO(settings), nil
+}
+
+func (s *BusinessCalendarService) UpdateCalendar(ctx context.Context, d dto.UpdateBusinessCalendarDTO) (*dto.BusinessCalendarDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.GetCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.WorkDays != nil {
+		settings.WorkDays = make([]int32, 0, len(d.WorkDays))
+		for _, day := range d.WorkDays {
+			settings.WorkDays = append(settings.WorkDays, int32(day))
+		}
+	}
+	if d.DayStart != nil {
+		settings.DayStart = strings.TrimSpace(*d.DayStart)
+	}
+	if d.DayEnd != nil {
+		settings.DayEnd = strings.TrimSpace(*d.DayEnd)
+	}
+	if d.IsEnabled != nil {
+		settings.IsEnabled = *d.IsEnabled
+	}
+
+	start, err := parseBusinessClock(settings.DayStart)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseBusinessClock(settings.DayEnd)
+	if err != nil {
+		return nil, err
+	}
+	if start >= end {
+		return nil, apperrors.NewBadRequestError("Начало рабочего дня должно быть раньше конца")
+	}
+	if settings.IsEnabled && len(settings.WorkDays) == 0 {
+		return nil, apperrors.NewBadRequestError("В календаре должен быть хотя бы один рабочий день")
+	}
+
+	settings.UpdatedBy = &authContext.Actor.ID
+	if err := s.repo.UpdateCalendar(ctx, settings); err != nil {
+		return nil, err
+	}
+	s.resetCalendar()
+	s.logger.Info("Рабочий календарь изменён",
+		zap.Uint64("actor_id", authContext.Actor.ID),
+		zap.Any("work_days", settings.WorkDays),
+		zap.String("day_start", settings.DayStart),
+		zap.String("day_end", settings.DayEnd),
+		zap.Bool("enabled", settings.IsEnabled))
+	return toBusinessCalendarDTO(settings), nil
+}
+
+func (s *BusinessCalendarService) GetHolidays(ctx context.Context, year int) ([]dto.BusinessHolidayDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	if year <= 0 {
+		year = time.Now().In(time.Local).Year()
+	}
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	holidays, err := s.repo.FindHolidays(ctx, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.BusinessHolidayDTO, 0, len(holidays))
+	for i := range holidays {
+		result = append(result, toBusinessHolidayDTO(&holidays[i]))
+	}
+	return result, nil
+}
+
+func (s *BusinessCalendarService) CreateHoliday(ctx context.Context, d dto.CreateBusinessHolidayDTO) (*dto.BusinessHolidayDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseBusinessDate(d.Date)
+	if err != nil {
+		return nil, err
+	}
+	holiday := &entities.BusinessHoliday{
+		HolidayDate:  date,
+		Name:         strings.TrimSpace(d.Name),
+		IsRecurring:  d.IsRecurring,
+		IsWorkingDay: d.IsWorkingDay,
+		CreatedBy:    &authContext.Actor.ID,
+	}
+	if err := validateBusinessHoliday(holiday); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateHoliday(ctx, holiday); err != nil {
+		return nil, err
+	}
+	s.resetCalendar()
+	result := toBusinessHolidayDTO(holiday)
+	return &result, nil
+}
+
+func (s *BusinessCalendarService) UpdateHoliday(ctx context.Context, id uint64, d dto.UpdateBusinessHolidayDTO) (*dto.BusinessHolidayDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	holiday, err := s.repo.FindHolidayByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Date != nil {
+		if holiday.HolidayDate, err = parseBusinessDate(*d.Date); err != nil {
+			return nil, err
+		}
+	}
+	if d.Name != nil {
+		holiday.Name = strings.TrimSpace(*d.Name)
+	}
+	if d.IsRecurring != nil {
+		holiday.IsRecurring = *d.IsRecurring
+	}
+	if d.IsWorkingDay != nil {
+		holiday.IsWorkingDay = *d.IsWorkingDay
+	}
+	if err := validateBusinessHoliday(holiday); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateHoliday(ctx, holiday); err != nil {
+		return nil, err
+	}
+	s.resetCalendar()
+	result := toBusinessHolidayDTO(holiday)
+	return &result, nil
+}
+
+func (s *BusinessCalendarService) DeleteHoliday(ctx context.Context, id uint64) error {
+	if _, err := s.checkManage(ctx); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteHoliday(ctx, id); err != nil {
+		return err
+	}
+	s.resetCalendar()
+	return nil
+}
+
+func validateBusinessHoliday(h *entities.BusinessHoliday) error {
+	if h.Name == "" {
+		return apperrors.NewBadRequestError("Название дня обязательно")
+	}
+	// Перенос рабочего дня привязан к конкретному году, ежегодным он быть не может.
+	if h.IsRecurring && h.IsWorkingDay {
+		return apperrors.NewBadRequestError("Перенесённый рабочий день не может повторяться ежегодно")
+	}
+	return nil
+}
+
+func (s *BusinessCalendarService) Measure(ctx context.Context, from, to time.Time) (*dto.BusinessDurationDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	if to.Before(from) || to.Sub(from) > businessMeasureMaxPeriod {
+		return nil, apperrors.NewBadRequestError("Период задан неверно или длиннее 5 лет")
+	}
+	calendar := s.Calendar(ctx)
+	return &dto.BusinessDurationDTO{
+		From:            from.In(time.Local).Format(time.RFC3339),
+		To:              to.In(time.Local).Format(time.RFC3339),
+		BusinessSeconds: int64(calendar.Between(from, to).Seconds()),
+		TotalSeconds:    int64(to.Sub(from).Seconds()),
+	}, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/businesshours"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type businessCalendarRepoStub struct {
+	repositories.BusinessCalendarRepositoryInterface
+	settings entities.BusinessCalendar
+	holidays []entities.BusinessHoliday
+	loads    int
+}
+
+func (s *businessCalendarRepoStub) GetCalendar(context.Context) (*entities.BusinessCalendar, error) {
+	s.loads++
+	settings := s.settings
+	return &settings, nil
+}
+
+func (s *businessCalendarRepoStub) UpdateCalendar(_ context.Context, calendar *entities.BusinessCalendar) error {
+	s.settings = *calendar
+	return nil
+}
+
+func (s *businessCalendarRepoStub) FindHolidays(context.Context, time.Time, time.Time) ([]entities.BusinessHoliday, error) {
+	return s.holidays, nil
+}
+
+func (s *businessCalendarRepoStub) CreateHoliday(_ context.Context, holiday *entities.BusinessHoliday) error {
+	holiday.ID = uint64(len(s.holidays) + 1)
+	s.holidays = append(s.holidays, *holiday)
+	return nil
+}
+
+type businessCalendarUserRepoStub struct {
+	repositories.UserRepositoryInterface
+}
+
+func (s *businessCalendarUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	return &entities.User{ID: id}, nil
+}
+
+func businessCalendarCtx() context.Context {
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	return context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.BusinessCalendarManage: true})
+}
+
+func newBusinessCalendarStub() *businessCalendarRepoStub {
+	return &businessCalendarRepoStub{
+		settings: entities.BusinessCalendar{WorkDays: []int32{1, 2, 3, 4, 5}, DayStart: "08:00", DayEnd: "17:00", IsEnabled: true},
+		holidays: []entities.BusinessHoliday{
+			{HolidayDate: time.Date(2000, 3, 21, 0, 0, 0, 0, time.UTC), Name: "Навруз", IsRecurring: true},
+			{HolidayDate: time.Date(2000, 3, 23, 0, 0, 0, 0, time.UTC), Name: "Навруз", IsRecurring: true},
+			{HolidayDate: time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC), Name: "Перенос", IsWorkingDay: true},
+		},
+	}
+}
+
+func TestBusinessCalendar_CountsOnlyWorkingHours(t *testing.T) {
+	service := NewBusinessCalendarService(newBusinessCalendarStub(), &businessCalendarUserRepoStub{}, zap.NewNop())
+	calendar := service.Calendar(context.Background())
+	if calendar == nil {
+		t.Fatal("enabled calendar must be loaded")
+	}
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, time.Local) }
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"inside one day", at(16, 9, 0), at(16, 11, 30), 150 * time.Minute},
+		{"evening to next morning", at(16, 16, 0), at(17, 9, 0), 2 * time.Hour},
+		{"friday to monday", at(13, 16, 0), at(16, 9, 0), 2 * time.Hour},
+		{"before the working day", at(16, 6, 0), at(16, 7, 59), 0},
+		{"recurring holidays skipped", at(20, 16, 0), at(24, 9, 0), 2 * time.Hour},
+		{"transferred saturday counts", at(27, 16, 0), at(30, 9, 0), 2*time.Hour + 9*time.Hour},
+		{"reversed period", at(17, 9, 0), at(16, 9, 0), 0},
+	}
+	for _, tc := range cases {
+		if got := calendar.Between(tc.from, tc.to); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	if got := calendar.Add(at(20, 16, 0), 3*time.Hour); !got.Equal(at(24, 10, 0)) {
+		t.Errorf("deadline over Navruz = %s", got)
+	}
+
+	var none *businesshours.Calendar
+	if got := none.Between(at(13, 16, 0), at(16, 9, 0)); got != 65*time.Hour {
+		t.Errorf("without a calendar the clock runs around the clock, got %s", got)
+	}
+}
+
+func TestBusinessCalendar_UpdateValidatesAndResetsCache(t *testing.T) {
+	repo := newBusinessCalendarStub()
+	service := NewBusinessCalendarService(repo, &businessCalendarUserRepoStub{}, zap.NewNop())
+	ctx := businessCalendarCtx()
+
+	service.Calendar(ctx)
+	service.Calendar(ctx)
+	if repo.loads != 1 {
+		t.Fatalf("calendar loaded %d times, want 1 (cached)", repo.loads)
+	}
+
+	start, end := "18:00", "09:00"
+	_, err := service.UpdateCalendar(ctx, dto.UpdateBusinessCalendarDTO{DayStart: &start, DayEnd: &end})
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != 400 {
+		t.Fatalf("start after end must be rejected with 400, got %v", err)
+	}
+	bad := "25:00"
+	if _, err := service.UpdateCalendar(ctx, dto.UpdateBusinessCalendarDTO{DayEnd: &bad}); err == nil {
+		t.Fatal("invalid clock must be rejected")
+	}
+
+	disabled := false
+	res, err := service.UpdateCalendar(ctx, dto.UpdateBusinessCalendarDTO{IsEnabled: &disabled})
+	if err != nil || res.IsEnabled || res.UpdatedBy == nil || *res.UpdatedBy != 1 {
+		t.Fatalf("disable: %+v, %v", res, err)
+	}
+	if service.Calendar(ctx) != nil {
+		t.Fatal("disabled calendar must reset the cache and count around the clock")
+	}
+
+	if _, err := service.CreateHoliday(ctx, dto.CreateBusinessHolidayDTO{Date: "2026-04-01", Name: "Перенос", IsRecurring: true, IsWorkingDay: true}); err == nil {
+		t.Fatal("a recurring transferred working day must be rejected")
+	}
+	viewer := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(2))
+	viewer = context.WithValue(viewer, contextkeys.UserPermissionsMapKey, map[string]bool{})
+	if _, err := service.GetCalendar(viewer); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("without the permission want forbidden, got %v", err)
+	}
+}
+
+type staticBusinessCalendar struct{ calendar *businesshours.Calendar }
+
+func (s staticBusinessCalendar) Calendar(context.Context) *businesshours.Calendar { return s.calendar }
+
+func TestCalculateMetrics_UsesBusinessHours(t *testing.T) {
+	statusRepo := &statusRepositoryStub{codesByID: map[uint64]string{1: pkgconstants.StatusOpen, 2: pkgconstants.StatusCompleted}}
+	calendar := businesshours.New(time.Local, []int{1, 2, 3, 4, 5}, 8*60, 17*60)
+	service := &OrderService{statuses: NewStatusDirectory(statusRepo, nil, zap.NewNop()), businessCalendar: staticBusinessCalendar{calendar}, logger: zap.NewNop()}
+
+	executorID := uint64(5)
+	createdAt := time.Date(2026, 10, 16, 16, 0, 0, 0, time.Local) // пятница
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)       // понедельник
+	oldOrder := &entities.Order{ID: 1, StatusID: 1, ExecutorID: &executorID, CreatedAt: createdAt}
+	newOrder := &entities.Order{ID: 1, StatusID: 2, ExecutorID: &executorID, CreatedAt: createdAt}
+	comment := "готово"
+
+	service.calculateMetrics(context.Background(), newOrder, oldOrder, dto.UpdateOrderDTO{Comment: &comment}, executorID, now)
+
+	if newOrder.ResolutionTimeSeconds == nil || *newOrder.ResolutionTimeSeconds != uint64((3*time.Hour).Seconds()) {
+		t.Fatalf("resolution time must skip the weekend, got %v", newOrder.ResolutionTimeSeconds)
+	}
+	if newOrder.FirstResponseTimeSeconds == nil || *newOrder.FirstResponseTimeSeconds != uint64((3*time.Hour).Seconds()) {
+		t.Fatalf("first response time must skip the weekend, got %v", newOrder.FirstResponseTimeSeconds)
+	}
+}
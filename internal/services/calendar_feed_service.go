@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/ical"
+	"request-system/pkg/utils"
+)
+
+const (
+	CalendarFeedPath = "/api/me/calendar.ics"
+	// CalendarRefreshInterval — как часто Outlook перечитывает подписку; он же max-age ответа.
+	CalendarRefreshInterval = 15 * time.Minute
+
+	calendarMaxEvents = 500
+	// Событие в календаре занимает последние полчаса перед сроком.
+	calendarEventLength = 30 * time.Minute
+)
+
+type CalendarFeedServiceInterface interface {
+	GetMyFeed(ctx context.Context) (*dto.CalendarFeedDTO, error)
+	RotateMyToken(ctx context.Context) (*dto.CalendarFeedDTO, error)
+	RevokeMyToken(ctx context.Context) error
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+}
+
+// CalendarFeedService отдаёт сроки заявок исполнителя в формате iCal по подписанной ссылке.
+// Календарные клиенты не умеют передавать JWT, поэтому доступ даёт сам токен в URL:
+// HMAC от user_id и nonce, где nonce хранится в БД и меняется при перевыпуске или отзыве.
+type CalendarFeedService struct {
+	repo            repositories.CalendarFeedRepositoryInterface
+	userRepo        repositories.UserRepositoryInterface
+	signingKey      []byte
+	serverBaseURL   string
+	frontendBaseURL string
+	logger          *zap.Logger
+}
+
+func NewCalendarFeedService(
+	repo repositories.CalendarFeedRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	jwtCfg config.JWTConfig,
+	serverCfg config.ServerConfig,
+	frontendCfg config.FrontendConfig,
+	logger *zap.Logger,
+) CalendarFeedServiceInterface {
+	// Отдельный ключ, производный от секрета JWT: подпись ссылки нельзя использовать как подпись токена и наоборот.
+	mac := hmac.New(sha256.New, []byte(jwtCfg.SecretKey))
+	mac.Write([]byte("calendar-feed"))
+	return &CalendarFeedService{
+		repo:            repo,
+		userRepo:        userRepo,
+		signingKey:      mac.Sum(nil),
+		serverBaseURL:   strings.TrimRight(serverCfg.BaseURL, "/"),
+		frontendBaseURL: strings.TrimRight(frontendCfg.BaseURL, "/"),
+		logger:          logger,
+	}
+}
+
+func (s *CalendarFeedService) sign(userID uint64, nonce string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(strconv.FormatUint(userID, 10) + "." + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *CalendarFeedService) token(userID uint64, nonce string) string {
+	return fmt.Sprintf("%d.%s.%s", userID, nonce, s.sign(userID, nonce))
+}
+
+// parseToken проверяет подпись без обращения к БД, чтобы перебор ссылок не нагружал базу.
+func (s *CalendarFeedService) parseToken(token string) (uint64, string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, "", false
+	}
+	userID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || userID == 0 || parts[1] == "" {
+		return 0, "", false
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(userID, parts[1]))) {
+		return 0, "", false
+	}
+	return userID, parts[1], true
+}
+
+func (s *CalendarFeedService) toFeedDTO(t *entities.UserCalendarToken) *dto.CalendarFeedDTO {
+	feedURL := s.serverBaseURL + CalendarFeedPath + "?token=" + s.token(t.UserID, t.Nonce)
+	createdAt := t.CreatedAt.Format(time.RFC3339)
+	result := &dto.CalendarFeedDTO{
+		Enabled:   true,
+		URL:       feedURL,
+		WebcalURL: "webcal://" + strings.TrimPrefix(strings.TrimPrefix(feedURL, "https://"), "http://"),
+		CreatedAt: &createdAt,
+	}
+	if t.LastUsedAt != nil {
+		lastUsedAt := t.LastUsedAt.Format(time.RFC3339)
+		result.LastUsedAt = &lastUsedAt
+	}
+	return result
+}
+
+func (s *CalendarFeedService) GetMyFeed(ctx context.Context) (*dto.CalendarFeedDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+	t, err := s.repo.FindToken(ctx, userID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return &dto.CalendarFeedDTO{Enabled: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.toFeedDTO(t), nil
+}
+
+func (s *CalendarFeedService) RotateMyToken(ctx context.Context) (*dto.CalendarFeedDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.UpsertToken(ctx, userID, hex.EncodeToString(buf))
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Выпущена ссылка на календарь сроков", zap.Uint64("userID", userID))
+	return s.toFeedDTO(t), nil
+}
+
+func (s *CalendarFeedService) RevokeMyToken(ctx context.Context) error {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return apperrors.ErrUnauthorized
+	}
+	if err := s.repo.DeleteToken(ctx, userID); err != nil {
+		return err
+	}
+	s.logger.Info("Ссылка на календарь сроков отозвана", zap.Uint64("userID", userID))
+	return nil
+}
+
+// RenderFeed на любой неверный, отозванный или чужой токен отвечает 404, не уточняя причину.
+func (s *CalendarFeedService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	userID, nonce, ok := s.parseToken(token)
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	stored, err := s.repo.FindToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored.Nonce), []byte(nonce)) != 1 {
+		return nil, apperrors.ErrNotFound
+	}
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil || user.StatusCode != constants.UserStatusActiveCode {
+		return nil, apperrors.ErrNotFound
+	}
+
+	deadlines, err := s.repo.FindExecutorDeadlines(ctx, userID, calendarMaxEvents)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.TouchToken(ctx, userID); err != nil {
+		s.logger.Warn("Не удалось отметить использование ссылки календаря", zap.Uint64("userID", userID), zap.Error(err))
+	}
+
+	cal := buildDeadlineCalendar(deadlines, s.frontendBaseURL, s.serverBaseURL)
+	return cal.Bytes(), nil
+}
+
+func buildDeadlineCalendar(deadlines []entities.CalendarDeadline, frontendBaseURL, serverBaseURL string) *ical.Calendar {
+	host, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(serverBaseURL, "https://"), "http://"), "/")
+	if host == "" {
+		host = "request-system"
+	}
+
+	cal := &ical.Calendar{
+		ProductID:       "-//request-system//order deadlines//RU",
+		Name:            "Сроки заявок",
+		RefreshInterval: CalendarRefreshInterval,
+		Events:          make([]ical.Event, 0, len(deadlines)),
+	}
+	for _, d := range deadlines {
+		var description strings.Builder
+		fmt.Fprintf(&description, "Статус: %s", d.StatusName)
+		if d.PriorityName != "" {
+			fmt.Fprintf(&description, "\nПриоритет: %s", d.PriorityName)
+		}
+
+		event := ical.Event{
+			UID:     fmt.Sprintf("order-%d-deadline@%s", d.ID, host),
+			Summary: fmt.Sprintf("Срок: №%s %s", d.Number, d.Name),
+			Start:   d.Duration.Add(-calendarEventLength),
+			End:     d.Duration,
+			Updated: d.UpdatedAt,
+		}
+		if d.Address != nil {
+			event.Location = *d.Address
+		}
+		if d.PriorityName != "" {
+			event.Categories = []string{d.PriorityName}
+		}
+		if frontendBaseURL != "" {
+			event.URL = fmt.Sprintf("%s/orders/%d", frontendBaseURL, d.ID)
+			fmt.Fprintf(&description, "\n%s", event.URL)
+		}
+		event.Description = description.String()
+		cal.Events = append(cal.Events, event)
+	}
+	return cal
+}
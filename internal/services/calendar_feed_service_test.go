@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type calendarRepoStub struct {
+	repositories.CalendarFeedRepositoryInterface
+	token     *entities.UserCalendarToken
+	deadlines []entities.CalendarDeadline
+}
+
+func (r *calendarRepoStub) FindToken(_ context.Context, userID uint64) (*entities.UserCalendarToken, error) {
+	if r.token == nil || r.token.UserID != userID {
+		return nil, apperrors.ErrNotFound
+	}
+	return r.token, nil
+}
+
+func (r *calendarRepoStub) UpsertToken(_ context.Context, userID uint64, nonce string) (*entities.UserCalendarToken, error) {
+	r.token = &entities.UserCalendarToken{UserID: userID, Nonce: nonce, CreatedAt: time.Now()}
+	return r.token, nil
+}
+
+func (r *calendarRepoStub) TouchToken(context.Context, uint64) error { return nil }
+
+func (r *calendarRepoStub) FindExecutorDeadlines(context.Context, uint64, int) ([]entities.CalendarDeadline, error) {
+	return r.deadlines, nil
+}
+
+type calendarUserRepoStub struct {
+	repositories.UserRepositoryInterface
+}
+
+func (calendarUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	return &entities.User{ID: id, StatusCode: constants.UserStatusActiveCode}, nil
+}
+
+func TestCalendarFeedTokenRotationRevokesOldLink(t *testing.T) {
+	deadline := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	repo := &calendarRepoStub{deadlines: []entities.CalendarDeadline{{
+		ID: 15, Name: "Замена картриджа; кабинет 4, этаж 2", Duration: deadline, StatusName: "В работе", PriorityName: "Высокий",
+	}}}
+	s := NewCalendarFeedService(repo, calendarUserRepoStub{}, config.JWTConfig{SecretKey: "secret"},
+		config.ServerConfig{BaseURL: "https://helpdesk.example"}, config.FrontendConfig{BaseURL: "https://app.example"}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(7))
+
+	first, err := s.RotateMyToken(ctx)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if !strings.HasPrefix(first.URL, "https://helpdesk.example/api/me/calendar.ics?token=7.") ||
+		!strings.HasPrefix(first.WebcalURL, "webcal://helpdesk.example/api/me/calendar.ics?token=") {
+		t.Fatalf("unexpected feed urls %q, %q", first.URL, first.WebcalURL)
+	}
+	firstToken := first.URL[strings.Index(first.URL, "token=")+len("token="):]
+
+	body, err := s.RenderFeed(context.Background(), firstToken)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	ics := string(body)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n", "REFRESH-INTERVAL;VALUE=DURATION:PT15M\r\n", "X-PUBLISHED-TTL:PT15M\r\n",
+		"DTEND:20261020T090000Z\r\n", `Замена картриджа\; кабинет 4\, этаж 2`, "URL:https://app.example/orders/15\r\n",
+	} {
+		if !strings.Contains(strings.ReplaceAll(ics, "\r\n ", ""), want) {
+			t.Fatalf("feed does not contain %q:\n%s", want, ics)
+		}
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line is not folded: %q", line)
+		}
+	}
+
+	// Подделанная подпись и старая ссылка после перевыпуска не открывают календарь.
+	tampered := []byte(firstToken)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := s.RenderFeed(context.Background(), string(tampered)); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("expected tampered token to be rejected, got %v", err)
+	}
+	if _, err := s.RotateMyToken(ctx); err != nil {
+		t.Fatalf("second rotate: %v", err)
+	}
+	if _, err := s.RenderFeed(context.Background(), firstToken); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("expected rotated token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"request-system/internal/entities"
+	"request-system/pkg/businesshours"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/sanitize"
+)
+
+const (
+	chatColorInfo     = "2F6FDE"
+	chatColorCritical = "D13438"
+	chatColorWarning  = "F2A600"
+	// Slack не показывает больше 10 полей в одном блоке.
+	chatSlackMaxFields = 10
+)
+
+var chatEventTitles = map[string]string{
+	"order.created":          "Новая заявка",
+	"order.status_changed":   "Изменён статус заявки",
+	"order.priority_changed": "Изменён приоритет заявки",
+	"order.delegated":        "Заявка передана исполнителю",
+	"order.commented":        "Новый комментарий к заявке",
+	"order.duration_changed": "Изменён срок заявки",
+	"order.attachment_added": "К заявке добавлен файл",
+	"order.merged":           "Заявка объединена",
+	ChatEventSLABreached:     "Нарушен срок выполнения заявки",
+}
+
+type chatFact struct {
+	Name  string
+	Value string
+}
+
+// chatMessage — сообщение, независимое от мессенджера; buildChatMessage превращает его в карточку Teams или Slack.
+type chatMessage struct {
+	Title string
+	Text  string
+	Facts []chatFact
+	URL   string
+	Color string
+}
+
+// buildOrderChatMessage собирает карточку заявки; просрочка считается в рабочем времени calendar.
+func buildOrderChatMessage(card *entities.ChatOrderCard, event ChatOrderEvent, frontendBaseURL string, calendar *businesshours.Calendar) chatMessage {
+	title := chatEventTitles[event.EventType]
+	if title == "" {
+		title = event.EventType
+	}
+
+	msg := chatMessage{
+		Title: fmt.Sprintf("%s №%s", title, card.Number),
+		Text:  card.Name,
+		Color: chatColorInfo,
+	}
+	if event.Comment != "" {
+		msg.Text += "\n\n" + event.Comment
+	}
+
+	addFact := func(name, value string) {
+		if value != "" {
+			msg.Facts = append(msg.Facts, chatFact{Name: name, Value: value})
+		}
+	}
+	addFact("Статус", card.StatusName)
+	addFact("Приоритет", card.PriorityName)
+	addFact("Департамент", card.DepartmentName)
+	addFact("Исполнитель", card.ExecutorName)
+	if card.Duration != nil {
+		addFact("Срок", card.Duration.Local().Format("02.01.2006 15:04"))
+	}
+	addFact("Автор изменения", event.ActorName)
+
+	if event.EventType == ChatEventSLABreached {
+		msg.Color = chatColorWarning
+		if card.Duration != nil {
+			addFact("Просрочено на", formatChatOverdue(calendar.Between(*card.Duration, time.Now())))
+		}
+	}
+	if card.PriorityCode == chatCriticalPriority {
+		msg.Color = chatColorCritical
+	}
+
+	if frontendBaseURL != "" {
+		msg.URL = fmt.Sprintf("%s/orders/%d", frontendBaseURL, card.ID)
+	}
+	return msg
+}
+
+func formatChatOverdue(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "меньше минуты"
+	}
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	if hours == 0 {
+		return fmt.Sprintf("%d мин", minutes)
+	}
+	return fmt.Sprintf("%d ч %d мин", hours, minutes)
+}
+
+func buildChatMessage(kind string, msg chatMessage) ([]byte, error) {
+	switch kind {
+	case entities.ChatChannelTeams:
+		return json.Marshal(teamsMessageCard(msg))
+	case entities.ChatChannelSlack:
+		return json.Marshal(slackMessage(msg))
+	default:
+		return nil, apperrors.NewBadRequestError(fmt.Sprintf("Неизвестный тип канала: %s", kind))
+	}
+}
+
+// teamsMessageCard — формат MessageCard, который принимают входящие вебхуки Teams (O365 connector).
+func teamsMessageCard(msg chatMessage) map[string]interface{} {
+	facts := make([]map[string]string, 0, len(msg.Facts))
+	for _, f := range msg.Facts {
+		facts = append(facts, map[string]string{"name": f.Name, "value": f.Value})
+	}
+	section := map[string]interface{}{"facts": facts}
+	if msg.Text != "" {
+		section["text"] = msg.Text
+	}
+
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"title":      msg.Title,
+		"themeColor": msg.Color,
+		"sections":   []interface{}{section},
+	}
+	if msg.URL != "" {
+		card["potentialAction"] = []interface{}{map[string]interface{}{
+			"@type":   "OpenUri",
+			"name":    "Открыть заявку",
+			"targets": []map[string]string{{"os": "default", "uri": msg.URL}},
+		}}
+	}
+	return card
+}
+
+// slackMessage — Block Kit; text служит текстом уведомления и запасным вариантом для старых клиентов.
+func slackMessage(msg chatMessage) map[string]interface{} {
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": map[string]string{"type": "plain_text", "text": msg.Title},
+		},
+	}
+	if msg.Text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": sanitize.Slack(msg.Text)},
+		})
+	}
+	if len(msg.Facts) > 0 {
+		fields := make([]map[string]string, 0, len(msg.Facts))
+		for _, f := range msg.Facts {
+			if len(fields) == chatSlackMaxFields {
+				break
+			}
+			fields = append(fields, map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", sanitize.Slack(f.Name), sanitize.Slack(f.Value)),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+	if msg.URL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []interface{}{map[string]interface{}{
+				"type": "button",
+				"text": map[string]string{"type": "plain_text", "text": "Открыть заявку"},
+				"url":  msg.URL,
+			}},
+		})
+	}
+
+	return map[string]interface{}{
+		"text": msg.Title,
+		"attachments": []interface{}{map[string]interface{}{
+			"color":  "#" + msg.Color,
+			"blocks": blocks,
+		}},
+	}
+}
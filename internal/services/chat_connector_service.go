@@ -0,0 +1,437 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/businesshours"
+	"request-system/pkg/config"
+	pkgconstants "request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+)
+
+const (
+	// ChatEventSLABreached — у открытой заявки истёк срок выполнения; событие формирует сам коннектор.
+	ChatEventSLABreached = "order.sla_breached"
+
+	chatRequestTimeout   = 10 * time.Second
+	chatSendAttempts     = 3
+	chatSLAPollInterval  = time.Minute
+	chatSLABatchSize     = 50
+	chatSLALookback      = 24 * time.Hour
+	chatCriticalPriority = pkgconstants.PriorityCritical
+)
+
+// ChatEventTypes — события, которые можно включить для канала Teams/Slack.
+var ChatEventTypes = append(slices.Clone(WebhookEventTypes), ChatEventSLABreached)
+
+// ChatOrderEvent — событие заявки для публикации в каналы.
+type ChatOrderEvent struct {
+	EventType string
+	OrderID   uint64
+	ActorName string
+	Comment   string
+}
+
+type ChatConnectorServiceInterface interface {
+	CreateChannel(ctx context.Context, d dto.CreateChatChannelDTO) (*dto.ChatChannelDTO, error)
+	UpdateChannel(ctx context.Context, id uint64, d dto.UpdateChatChannelDTO) (*dto.ChatChannelDTO, error)
+	DeleteChannel(ctx context.Context, id uint64) error
+	GetChannels(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.ChatChannelDTO], error)
+	GetChannel(ctx context.Context, id uint64) (*dto.ChatChannelDTO, error)
+	TestChannel(ctx context.Context, id uint64) error
+
+	NotifyOrderEvent(ctx context.Context, event ChatOrderEvent) error
+	StartSLAWatcher(ctx context.Context)
+}
+
+// ChatConnectorService публикует события заявок во входящие вебхуки Microsoft Teams и Slack.
+// В отличие от webhook-подписок очереди доставок нет: сообщение в чат — оповещение для людей,
+// после нескольких неудачных попыток оно просто пишется в лог.
+type ChatConnectorService struct {
+	repo            repositories.ChatChannelRepositoryInterface
+	userRepo        repositories.UserRepositoryInterface
+	frontendBaseURL string
+	calendar        BusinessCalendarProvider
+	httpClient      *http.Client
+	logger          *zap.Logger
+}
+
+func NewChatConnectorService(
+	repo repositories.ChatChannelRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	frontendCfg config.FrontendConfig,
+	calendar BusinessCalendarProvider,
+	logger *zap.Logger,
+) ChatConnectorServiceInterface {
+	return &ChatConnectorService{
+		repo:            repo,
+		userRepo:        userRepo,
+		frontendBaseURL: strings.TrimRight(frontendCfg.BaseURL, "/"),
+		calendar:        calendar,
+		httpClient:      &http.Client{Timeout: chatRequestTimeout},
+		logger:          logger,
+	}
+}
+
+func (s *ChatConnectorService) checkManage(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.WebhooksManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func validateChatEventTypes(eventTypes []string) ([]string, error) {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(ChatEventTypes, eventType) {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest,
+				fmt.Sprintf("Неизвестный тип события: %s", eventType), nil, nil)
+		}
+		if !slices.Contains(result, eventType) {
+			result = append(result, eventType)
+		}
+	}
+	return result, nil
+}
+
+// validateChatWebhookURL — Teams и Slack выдают только https-адреса; http почти наверняка опечатка.
+func validateChatWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return apperrors.NewBadRequestError("Адрес входящего вебхука должен начинаться с https://")
+	}
+	return nil
+}
+
+// maskChatWebhookURL оставляет только хост: сам путь вебхука — секрет, дающий право писать в канал.
+func maskChatWebhookURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
+
+func toChatChannelDTO(e *entities.ChatChannel) dto.ChatChannelDTO {
+	return dto.ChatChannelDTO{
+		ID:           e.ID,
+		Name:         e.Name,
+		Kind:         e.Kind,
+		WebhookURL:   maskChatWebhookURL(e.WebhookURL),
+		DepartmentID: e.DepartmentID,
+		EventTypes:   e.EventTypes,
+		OnlyCritical: e.OnlyCritical,
+		IsActive:     e.IsActive,
+		CreatedBy:    e.CreatedBy,
+		CreatedAt:    e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *ChatConnectorService) CreateChannel(ctx context.Context, d dto.CreateChatChannelDTO) (*dto.ChatChannelDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateChatEventTypes(d.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateChatWebhookURL(d.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	ch := &entities.ChatChannel{
+		Name:         d.Name,
+		Kind:         d.Kind,
+		WebhookURL:   d.WebhookURL,
+		DepartmentID: d.DepartmentID,
+		EventTypes:   eventTypes,
+		OnlyCritical: d.OnlyCritical,
+		IsActive:     d.IsActive == nil || *d.IsActive,
+		CreatedBy:    &authContext.Actor.ID,
+	}
+	if err := s.repo.Create(ctx, ch); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Создан канал оповещений", zap.Uint64("channelID", ch.ID), zap.String("kind", ch.Kind), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toChatChannelDTO(ch)
+	return &result, nil
+}
+
+func (s *ChatConnectorService) UpdateChannel(ctx context.Context, id uint64, d dto.UpdateChatChannelDTO) (*dto.ChatChannelDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Name != nil {
+		ch.Name = *d.Name
+	}
+	if d.Kind != nil {
+		ch.Kind = *d.Kind
+	}
+	if d.WebhookURL != nil {
+		if err := validateChatWebhookURL(*d.WebhookURL); err != nil {
+			return nil, err
+		}
+		ch.WebhookURL = *d.WebhookURL
+	}
+	if d.DepartmentID != nil {
+		ch.DepartmentID = d.DepartmentID
+		if *d.DepartmentID == 0 {
+			ch.DepartmentID = nil
+		}
+	}
+	if d.EventTypes != nil {
+		if ch.EventTypes, err = validateChatEventTypes(*d.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if d.OnlyCritical != nil {
+		ch.OnlyCritical = *d.OnlyCritical
+	}
+	if d.IsActive != nil {
+		ch.IsActive = *d.IsActive
+	}
+
+	if err := s.repo.Update(ctx, ch); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Обновлён канал оповещений", zap.Uint64("channelID", id), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toChatChannelDTO(ch)
+	return &result, nil
+}
+
+func (s *ChatConnectorService) DeleteChannel(ctx context.Context, id uint64) error {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалён канал оповещений", zap.Uint64("channelID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *ChatConnectorService) GetChannels(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.ChatChannelDTO], error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+
+	channels, total, err := s.repo.FindAll(ctx, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dto.ChatChannelDTO, 0, len(channels))
+	for i := range channels {
+		list = append(list, toChatChannelDTO(&channels[i]))
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+	return &dto.PaginatedResponse[dto.ChatChannelDTO]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
+
+func (s *ChatConnectorService) GetChannel(ctx context.Context, id uint64) (*dto.ChatChannelDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	ch, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := toChatChannelDTO(ch)
+	return &result, nil
+}
+
+// TestChannel отправляет пробное сообщение, чтобы администратор убедился, что вебхук рабочий.
+func (s *ChatConnectorService) TestChannel(ctx context.Context, id uint64) error {
+	if _, err := s.checkManage(ctx); err != nil {
+		return err
+	}
+	ch, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	body, err := buildChatMessage(ch.Kind, chatMessage{
+		Title: "Проверка канала оповещений",
+		Text:  fmt.Sprintf("Канал «%s» подключён к системе заявок.", ch.Name),
+		Color: chatColorInfo,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.send(ctx, ch, body); err != nil {
+		return apperrors.NewHttpError(http.StatusBadGateway,
+			fmt.Sprintf("Канал не принял сообщение: %s", err.Error()), err, nil)
+	}
+	return nil
+}
+
+func (s *ChatConnectorService) NotifyOrderEvent(ctx context.Context, event ChatOrderEvent) error {
+	card, err := s.repo.FindOrderCard(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+	channels, err := s.repo.FindActiveForEvent(ctx, event.EventType, card.DepartmentID)
+	if err != nil {
+		return err
+	}
+	s.post(ctx, channels, card, event)
+	return nil
+}
+
+func (s *ChatConnectorService) post(ctx context.Context, channels []entities.ChatChannel, card *entities.ChatOrderCard, event ChatOrderEvent) {
+	var calendar *businesshours.Calendar
+	if s.calendar != nil {
+		calendar = s.calendar.Calendar(ctx)
+	}
+	msg := buildOrderChatMessage(card, event, s.frontendBaseURL, calendar)
+	for i := range channels {
+		ch := &channels[i]
+		if !chatChannelAccepts(ch, card) {
+			continue
+		}
+		body, err := buildChatMessage(ch.Kind, msg)
+		if err == nil {
+			err = s.send(ctx, ch, body)
+		}
+		if err != nil {
+			s.logger.Warn("Сообщение в канал не доставлено",
+				zap.Uint64("channelID", ch.ID), zap.Uint64("orderID", card.ID),
+				zap.String("event", event.EventType), zap.Error(err))
+		}
+	}
+}
+
+// chatChannelAccepts — фильтры канала, которые не выражаются запросом: только критичные заявки.
+func chatChannelAccepts(ch *entities.ChatChannel, card *entities.ChatOrderCard) bool {
+	return !ch.OnlyCritical || card.PriorityCode == chatCriticalPriority
+}
+
+// send делает до chatSendAttempts попыток; 4xx кроме 429 не повторяется — вебхук удалён или отключён.
+func (s *ChatConnectorService) send(ctx context.Context, ch *entities.ChatChannel, body []byte) error {
+	var lastErr error
+	for attempt := 1; attempt <= chatSendAttempts; attempt++ {
+		retry, err := s.sendOnce(ctx, ch, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == chatSendAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+	return lastErr
+}
+
+func (s *ChatConnectorService) sendOnce(ctx context.Context, ch *entities.ChatChannel, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ch.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "request-system-chat/1.0")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(answer)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// StartSLAWatcher раз в минуту ищет заявки с истёкшим сроком и публикует их в каналы,
+// подписанные на order.sla_breached. Просрочки старше chatSLALookback не публикуются,
+// чтобы первое включение не завалило канал старыми заявками.
+func (s *ChatConnectorService) StartSLAWatcher(ctx context.Context) {
+	s.logger.Info("Наблюдатель просрочек для каналов оповещений запущен")
+	ticker := time.NewTicker(chatSLAPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Наблюдатель просрочек для каналов оповещений остановлен")
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			processed, err := s.processSLABreaches(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Error("Ошибка поиска просроченных заявок для каналов", zap.Error(err))
+				}
+				break
+			}
+			if processed < chatSLABatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (s *ChatConnectorService) processSLABreaches(ctx context.Context) (int, error) {
+	cards, err := s.repo.ClaimSLABreaches(ctx, time.Now().Add(-chatSLALookback), chatSLABatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range cards {
+		card := &cards[i]
+		channels, err := s.repo.FindActiveForEvent(ctx, ChatEventSLABreached, card.DepartmentID)
+		if err != nil {
+			return i, err
+		}
+		s.post(ctx, channels, card, ChatOrderEvent{EventType: ChatEventSLABreached, OrderID: card.ID})
+	}
+	return len(cards), nil
+}
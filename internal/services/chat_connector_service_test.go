@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+)
+
+type chatRepoStub struct {
+	repositories.ChatChannelRepositoryInterface
+	card     *entities.ChatOrderCard
+	channels []entities.ChatChannel
+}
+
+func (r *chatRepoStub) FindOrderCard(ctx context.Context, orderID uint64) (*entities.ChatOrderCard, error) {
+	return r.card, nil
+}
+
+func (r *chatRepoStub) FindActiveForEvent(ctx context.Context, eventType string, departmentID *uint64) ([]entities.ChatChannel, error) {
+	return r.channels, nil
+}
+
+func TestChatConnectorPostsCardsAndSkipsNonCritical(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("invalid json body: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &chatRepoStub{
+		card: &entities.ChatOrderCard{ID: 42, Number: "REQ-2026-000042", Name: "Не печатает <принтер>", StatusName: "Открыта", PriorityName: "Средний", PriorityCode: "MEDIUM"},
+		channels: []entities.ChatChannel{
+			{ID: 1, Kind: entities.ChatChannelTeams, WebhookURL: server.URL},
+			{ID: 2, Kind: entities.ChatChannelSlack, WebhookURL: server.URL},
+			{ID: 3, Kind: entities.ChatChannelSlack, WebhookURL: server.URL, OnlyCritical: true},
+		},
+	}
+	s := &ChatConnectorService{repo: repo, frontendBaseURL: "https://helpdesk.example", httpClient: server.Client(), logger: zap.NewNop()}
+
+	if err := s.NotifyOrderEvent(context.Background(), ChatOrderEvent{EventType: "order.created", OrderID: 42}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("expected only the two non-critical channels to receive a message, got %d", len(bodies))
+	}
+
+	teams := bodies[0]
+	if teams["@type"] != "MessageCard" || teams["title"] != "Новая заявка №REQ-2026-000042" {
+		t.Fatalf("unexpected teams card: %v", teams)
+	}
+	action := teams["potentialAction"].([]interface{})[0].(map[string]interface{})
+	target := action["targets"].([]interface{})[0].(map[string]interface{})
+	if target["uri"] != "https://helpdesk.example/orders/42" {
+		t.Fatalf("unexpected order link %v", target["uri"])
+	}
+
+	blocks := bodies[1]["attachments"].([]interface{})[0].(map[string]interface{})["blocks"].([]interface{})
+	text := blocks[1].(map[string]interface{})["text"].(map[string]interface{})["text"].(string)
+	if !strings.Contains(text, "&lt;принтер&gt;") {
+		t.Fatalf("expected slack text to be escaped: %q", text)
+	}
+}
+
+func TestChatConnectorRetriesOnlyTransientErrors(t *testing.T) {
+	calls := 0
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	s := &ChatConnectorService{httpClient: server.Client(), logger: zap.NewNop()}
+	ch := &entities.ChatChannel{Kind: entities.ChatChannelSlack, WebhookURL: server.URL}
+
+	if err := s.send(context.Background(), ch, []byte(`{}`)); err == nil || calls != 1 {
+		t.Fatalf("expected a single attempt for a removed webhook, got %d calls, err %v", calls, err)
+	}
+}
+
+func TestChatChannelURLAndEventValidation(t *testing.T) {
+	if got := maskChatWebhookURL("https://hooks.slack.com/services/T000/B000/XXXX"); got != "https://hooks.slack.com/***" {
+		t.Fatalf("unexpected masked url %q", got)
+	}
+	if err := validateChatWebhookURL("http://hooks.slack.com/services/x"); err == nil {
+		t.Fatal("expected plain http webhook to be rejected")
+	}
+	if types, err := validateChatEventTypes([]string{ChatEventSLABreached, "order.created", ChatEventSLABreached}); err != nil || len(types) != 2 {
+		t.Fatalf("unexpected event types %v, %v", types, err)
+	}
+	if _, err := validateChatEventTypes([]string{"order.deleted"}); err == nil {
+		t.Fatal("expected unknown event type to be rejected")
+	}
+}
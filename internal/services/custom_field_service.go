@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+// customFieldCodePattern — code служит ключом в orders.custom_fields и в фильтре filter[cf.<code>].
+var customFieldCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// CustomFieldServiceInterface ведёт дополнительные поля типов заявок. Значения полей
+// проверяет и сохраняет OrderService.
+type CustomFieldServiceInterface interface {
+	GetFields(ctx context.Context, orderTypeID uint64) ([]dto.CustomFieldDefinitionDTO, error)
+	CreateField(ctx context.Context, orderTypeID uint64, payload dto.CreateCustomFieldDTO) (*dto.CustomFieldDefinitionDTO, error)
+	UpdateField(ctx context.Context, orderTypeID, fieldID uint64, payload dto.UpdateCustomFieldDTO) (*dto.CustomFieldDefinitionDTO, error)
+	// DeleteField удаляет определение; уже сохранённые значения остаются в заявках до их правки.
+	DeleteField(ctx context.Context, orderTypeID, fieldID uint64) error
+}
+
+type CustomFieldService struct {
+	repo          repositories.CustomFieldRepositoryInterface
+	orderTypeRepo repositories.OrderTypeRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	logger        *zap.Logger
+}
+
+func NewCustomFieldService(
+	repo repositories.CustomFieldRepositoryInterface,
+	orderTypeRepo repositories.OrderTypeRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) CustomFieldServiceInterface {
+	return &CustomFieldService{repo: repo, orderTypeRepo: orderTypeRepo, userRepo: userRepo, logger: logger}
+}
+
+func (s *CustomFieldService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func (s *CustomFieldService) GetFields(ctx context.Context, orderTypeID uint64) ([]dto.CustomFieldDefinitionDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OrderTypesView); err != nil {
+		return nil, err
+	}
+	fields, err := s.repo.FindByOrderType(ctx, orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.CustomFieldDefinitionDTO, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, toCustomFieldDTO(f))
+	}
+	return result, nil
+}
+
+func (s *CustomFieldService) CreateField(ctx context.Context, orderTypeID uint64, payload dto.CreateCustomFieldDTO) (*dto.CustomFieldDefinitionDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.OrderTypesUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.orderTypeRepo.FindByID(ctx, orderTypeID); err != nil {
+		return nil, err
+	}
+
+	field := &entities.CustomFieldDefinition{
+		OrderTypeID: orderTypeID,
+		Code:        strings.ToLower(strings.TrimSpace(payload.Code)),
+		Name:        strings.TrimSpace(payload.Name),
+		FieldType:   payload.FieldType,
+		IsRequired:  payload.IsRequired,
+		SortOrder:   payload.SortOrder,
+	}
+	if !customFieldCodePattern.MatchString(field.Code) {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Код поля: латинские буквы в нижнем регистре, цифры и _, начинается с буквы", nil, nil)
+	}
+	if field.Name == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указано название поля", nil, nil)
+	}
+	if field.Options, err = customFieldOptionsFor(field.FieldType, payload.Options); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, field); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Добавлено дополнительное поле типа заявки",
+		zap.Uint64("orderTypeID", orderTypeID), zap.String("code", field.Code), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toCustomFieldDTO(*field)
+	return &result, nil
+}
+
+func (s *CustomFieldService) UpdateField(ctx context.Context, orderTypeID, fieldID uint64, payload dto.UpdateCustomFieldDTO) (*dto.CustomFieldDefinitionDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OrderTypesUpdate); err != nil {
+		return nil, err
+	}
+	field, err := s.findOwnField(ctx, orderTypeID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Name != nil {
+		name := strings.TrimSpace(*payload.Name)
+		if name == "" {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указано название поля", nil, nil)
+		}
+		field.Name = name
+	}
+	if payload.IsRequired != nil {
+		field.IsRequired = *payload.IsRequired
+	}
+	if payload.SortOrder != nil {
+		field.SortOrder = *payload.SortOrder
+	}
+	if payload.Options != nil {
+		if field.Options, err = customFieldOptionsFor(field.FieldType, payload.Options); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, field); err != nil {
+		return nil, err
+	}
+	result := toCustomFieldDTO(*field)
+	return &result, nil
+}
+
+func (s *CustomFieldService) DeleteField(ctx context.Context, orderTypeID, fieldID uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.OrderTypesUpdate)
+	if err != nil {
+		return err
+	}
+	field, err := s.findOwnField(ctx, orderTypeID, fieldID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, field.ID); err != nil {
+		return err
+	}
+	s.logger.Info("Удалено дополнительное поле типа заявки",
+		zap.Uint64("orderTypeID", orderTypeID), zap.String("code", field.Code), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+// findOwnField — поле другого типа заявки для этого URL «не существует».
+func (s *CustomFieldService) findOwnField(ctx context.Context, orderTypeID, fieldID uint64) (*entities.CustomFieldDefinition, error) {
+	field, err := s.repo.FindByID(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if field.OrderTypeID != orderTypeID {
+		return nil, apperrors.ErrNotFound
+	}
+	return field, nil
+}
+
+// customFieldOptionsFor — варианты нужны только списку выбора, у остальных типов они не хранятся.
+func customFieldOptionsFor(fieldType string, options []string) ([]string, error) {
+	if fieldType != entities.CustomFieldSelect {
+		return []string{}, nil
+	}
+	result := make([]string, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option != "" && !slices.Contains(result, option) {
+			result = append(result, option)
+		}
+	}
+	if len(result) == 0 {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Для списка выбора укажите хотя бы один вариант", nil, nil)
+	}
+	return result, nil
+}
+
+func toCustomFieldDTO(f entities.CustomFieldDefinition) dto.CustomFieldDefinitionDTO {
+	options := f.Options
+	if options == nil {
+		options = []string{}
+	}
+	return dto.CustomFieldDefinitionDTO{
+		ID:          f.ID,
+		OrderTypeID: f.OrderTypeID,
+		Code:        f.Code,
+		Name:        f.Name,
+		FieldType:   f.FieldType,
+		IsRequired:  f.IsRequired,
+		Options:     options,
+		SortOrder:   f.SortOrder,
+		CreatedAt:   f.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   f.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+)
+
+const (
+	dailyOrderStatsDefaultHour          = 2
+	dailyOrderStatsDefaultRecomputeDays = 7
+)
+
+type DailyOrderStatsServiceInterface interface {
+	Start(ctx context.Context)
+	CatchUp(ctx context.Context) (int, error)
+}
+
+// DailyOrderStatsService раз в сутки сворачивает заявки в daily_order_stats. Кроме новых дней
+// пересчитываются последние recomputeDays: закрытия, переоткрытия и удаления задним числом
+// меняют уже посчитанные дни.
+type DailyOrderStatsService struct {
+	repo          repositories.DailyOrderStatsRepositoryInterface
+	logger        *zap.Logger
+	runHour       int
+	recomputeDays int
+}
+
+func NewDailyOrderStatsService(repo repositories.DailyOrderStatsRepositoryInterface, logger *zap.Logger) DailyOrderStatsServiceInterface {
+	return &DailyOrderStatsService{
+		repo:          repo,
+		logger:        logger,
+		runHour:       loadDailyOrderStatsEnvInt("DAILY_STATS_HOUR", dailyOrderStatsDefaultHour, 0, 23),
+		recomputeDays: loadDailyOrderStatsEnvInt("DAILY_STATS_RECOMPUTE_DAYS", dailyOrderStatsDefaultRecomputeDays, 1, 366),
+	}
+}
+
+func (s *DailyOrderStatsService) Start(ctx context.Context) {
+	s.logger.Info("Пересчёт daily_order_stats запущен", zap.Int("hour", s.runHour), zap.Int("recompute_days", s.recomputeDays))
+
+	// Догоняем пропущенные дни сразу после старта.
+	s.run(ctx)
+
+	for {
+		timer := time.NewTimer(time.Until(nextDailyOrderStatsRun(time.Now().In(time.Local), s.runHour)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.logger.Info("Пересчёт daily_order_stats остановлен")
+			return
+		case <-timer.C:
+			s.run(ctx)
+		}
+	}
+}
+
+func (s *DailyOrderStatsService) run(ctx context.Context) {
+	days, err := s.CatchUp(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("Ошибка пересчёта daily_order_stats", zap.Int("rebuilt_days", days), zap.Error(err))
+		}
+		return
+	}
+	if days > 0 {
+		s.logger.Info("daily_order_stats пересчитаны", zap.Int("days", days))
+	}
+}
+
+// CatchUp пересчитывает дни от последнего покрытия (с запасом recomputeDays) до вчерашнего
+// включительно. Дни обрабатываются по порядку; на первой ошибке пересчёт прерывается,
+// чтобы покрытие оставалось непрерывным.
+func (s *DailyOrderStatsService) CatchUp(ctx context.Context) (int, error) {
+	now := time.Now().In(time.Local)
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+
+	start, ok, err := s.rebuildStart(ctx, yesterday)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	rebuilt := 0
+	for day := start; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return rebuilt, err
+		}
+		if _, err := s.repo.RebuildDay(ctx, day); err != nil {
+			return rebuilt, err
+		}
+		rebuilt++
+	}
+	return rebuilt, nil
+}
+
+func (s *DailyOrderStatsService) rebuildStart(ctx context.Context, yesterday time.Time) (time.Time, bool, error) {
+	recomputeFrom := yesterday.AddDate(0, 0, -(s.recomputeDays - 1))
+
+	coveredThrough, covered, err := s.repo.GetCoveredThrough(ctx)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if covered {
+		start := coveredThrough.AddDate(0, 0, 1)
+		if recomputeFrom.Before(start) {
+			start = recomputeFrom
+		}
+		return start, true, nil
+	}
+
+	earliest, found, err := s.repo.GetEarliestOrderDate(ctx)
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+	return earliest, true, nil
+}
+
+func nextDailyOrderStatsRun(now time.Time, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func loadDailyOrderStatsEnvInt(name string, fallback, minValue, maxValue int) int {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minValue || value > maxValue {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+)
+
+type dailyOrderStatsRepoStub struct {
+	repositories.DailyOrderStatsRepositoryInterface
+	coveredThrough time.Time
+	covered        bool
+	earliest       time.Time
+	rebuilt        []time.Time
+}
+
+func (s *dailyOrderStatsRepoStub) GetCoveredThrough(context.Context) (time.Time, bool, error) {
+	return s.coveredThrough, s.covered, nil
+}
+
+func (s *dailyOrderStatsRepoStub) GetEarliestOrderDate(context.Context) (time.Time, bool, error) {
+	return s.earliest, !s.earliest.IsZero(), nil
+}
+
+func (s *dailyOrderStatsRepoStub) RebuildDay(_ context.Context, day time.Time) (int64, error) {
+	s.rebuilt = append(s.rebuilt, day)
+	return 1, nil
+}
+
+func TestDailyOrderStatsCatchUp(t *testing.T) {
+	now := time.Now().In(time.Local)
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+
+	repo := &dailyOrderStatsRepoStub{coveredThrough: yesterday, covered: true}
+	service := &DailyOrderStatsService{repo: repo, logger: zap.NewNop(), recomputeDays: 3}
+
+	days, err := service.CatchUp(context.Background())
+	if err != nil || days != 3 {
+		t.Fatalf("expected 3 recomputed days, got %d (%v)", days, err)
+	}
+	if !repo.rebuilt[0].Equal(yesterday.AddDate(0, 0, -2)) || !repo.rebuilt[2].Equal(yesterday) {
+		t.Fatalf("unexpected days: %v", repo.rebuilt)
+	}
+
+	// Без покрытия считаем с первой заявки.
+	repo = &dailyOrderStatsRepoStub{earliest: yesterday.AddDate(0, 0, -9)}
+	service.repo = repo
+	if days, err := service.CatchUp(context.Background()); err != nil || days != 10 {
+		t.Fatalf("expected backfill of 10 days, got %d (%v)", days, err)
+	}
+
+	// Заявок нет — пересчитывать нечего.
+	service.repo = &dailyOrderStatsRepoStub{}
+	if days, err := service.CatchUp(context.Background()); err != nil || days != 0 {
+		t.Fatalf("expected nothing to rebuild, got %d (%v)", days, err)
+	}
+}
+
+func TestNextDailyOrderStatsRun(t *testing.T) {
+	loc := time.UTC
+	before := time.Date(2026, 10, 16, 1, 0, 0, 0, loc)
+	if got := nextDailyOrderStatsRun(before, 2); !got.Equal(time.Date(2026, 10, 16, 2, 0, 0, 0, loc)) {
+		t.Fatalf("unexpected next run: %v", got)
+	}
+	after := time.Date(2026, 10, 16, 2, 0, 0, 0, loc)
+	if got := nextDailyOrderStatsRun(after, 2); !got.Equal(time.Date(2026, 10, 17, 2, 0, 0, 0, loc)) {
+		t.Fatalf("unexpected next run: %v", got)
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"request-system/internal/dto"
+	"request-system/pkg/types"
+)
+
+// GetBacklogAging возвращает срез текущего бэклога по возрасту заявок. Период дашборда
+// здесь не применяется: учитываются все открытые заявки в зоне видимости пользователя.
+func (s *DashboardService) GetBacklogAging(ctx context.Context) (*dto.DashboardBacklogAgingDTO, error) {
+	_, actor, authContext, err := s.authorizeDashboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dashboardRequest
+	securityCondition := resolveDashboardSecurity(&authContext, actor, &req)
+
+	aging, err := s.repo.GetBacklogAging(ctx, securityCondition)
+	if err != nil {
+		return nil, err
+	}
+	if aging.Departments == nil {
+		aging.Departments = make([]types.DashboardAgingGroup, 0)
+	}
+	if aging.Branches == nil {
+		aging.Branches = make([]types.DashboardAgingGroup, 0)
+	}
+
+	return &dto.DashboardBacklogAgingDTO{
+		GeneratedAt:           time.Now().In(time.Local).Format(time.RFC3339),
+		EffectiveScope:        req.effectiveScope,
+		DashboardBacklogAging: *aging,
+	}, nil
+}
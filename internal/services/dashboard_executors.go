@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"request-system/internal/dto"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+)
+
+const (
+	dashboardExecutorsDefaultLimit = 20
+	dashboardExecutorsMaxLimit     = 100
+)
+
+const (
+	DashboardExecutorSortClosed     = "closed"
+	DashboardExecutorSortResolution = "resolution"
+	DashboardExecutorSortSLA        = "sla"
+	DashboardExecutorSortReopen     = "reopen"
+	DashboardExecutorSortLoad       = "load"
+)
+
+// GetExecutorLeaderboard возвращает показатели исполнителей за период с учётом тех же
+// ограничений видимости, что и основной дашборд.
+func (s *DashboardService) GetExecutorLeaderboard(ctx context.Context, filter dto.DashboardExecutorFilterDTO) (*dto.DashboardExecutorsDTO, error) {
+	userID, actor, authContext, err := s.authorizeDashboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortBy, err := normalizeDashboardExecutorSort(filter.SortBy)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = dashboardExecutorsDefaultLimit
+	}
+	if limit > dashboardExecutorsMaxLimit {
+		limit = dashboardExecutorsMaxLimit
+	}
+
+	req, err := buildDashboardRequest(filter.DashboardFilterDTO, userID)
+	if err != nil {
+		return nil, err
+	}
+	securityCondition, err := narrowDashboardSecurity(&authContext, actor, &req, resolveDashboardSecurity(&authContext, actor, &req))
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.GetExecutorStats(ctx, securityCondition, req.query)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		decorateDashboardExecutorStat(&stats[i])
+	}
+	sortDashboardExecutorStats(stats, sortBy)
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	return &dto.DashboardExecutorsDTO{
+		Meta:      buildDashboardMeta(req),
+		SortBy:    sortBy,
+		Executors: stats,
+	}, nil
+}
+
+func normalizeDashboardExecutorSort(raw string) (string, error) {
+	sortBy := strings.TrimSpace(strings.ToLower(raw))
+	switch sortBy {
+	case "":
+		return DashboardExecutorSortClosed, nil
+	case DashboardExecutorSortClosed, DashboardExecutorSortResolution, DashboardExecutorSortSLA,
+		DashboardExecutorSortReopen, DashboardExecutorSortLoad:
+		return sortBy, nil
+	default:
+		return "", apperrors.NewBadRequestError("Некорректная сортировка исполнителей")
+	}
+}
+
+func decorateDashboardExecutorStat(stat *types.DashboardExecutorStat) {
+	stat.AvgResolutionFormatted = humanizeSeconds(stat.AvgResolutionSeconds)
+	if stat.SLAEligible > 0 {
+		stat.SLAPercent = math.Round(float64(stat.SLAOnTime) / float64(stat.SLAEligible) * 100)
+	}
+	if stat.ClosedCount > 0 {
+		stat.ReopenRate = math.Round(float64(stat.ReopenedCount)/float64(stat.ClosedCount)*1000) / 10
+	}
+}
+
+// sortDashboardExecutorStats упорядочивает исполнителей так, чтобы лучшие по выбранной
+// метрике шли первыми; для нагрузки первыми идут самые загруженные.
+func sortDashboardExecutorStats(stats []types.DashboardExecutorStat, sortBy string) {
+	less := func(a, b types.DashboardExecutorStat) (bool, bool) {
+		switch sortBy {
+		case DashboardExecutorSortResolution:
+			// Исполнители без закрытых заявок не должны оказываться выше тех, у кого время есть.
+			if (a.ClosedCount > 0) != (b.ClosedCount > 0) {
+				return a.ClosedCount > 0, true
+			}
+			if a.AvgResolutionSeconds != b.AvgResolutionSeconds {
+				return a.AvgResolutionSeconds < b.AvgResolutionSeconds, true
+			}
+		case DashboardExecutorSortSLA:
+			if a.SLAPercent != b.SLAPercent {
+				return a.SLAPercent > b.SLAPercent, true
+			}
+		case DashboardExecutorSortReopen:
+			if a.ReopenRate != b.ReopenRate {
+				return a.ReopenRate > b.ReopenRate, true
+			}
+		case DashboardExecutorSortLoad:
+			if a.OpenCount != b.OpenCount {
+				return a.OpenCount > b.OpenCount, true
+			}
+		}
+		if a.ClosedCount != b.ClosedCount {
+			return a.ClosedCount > b.ClosedCount, true
+		}
+		return false, false
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if result, decided := less(stats[i], stats[j]); decided {
+			return result
+		}
+		return stats[i].ExecutorID < stats[j].ExecutorID
+	})
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"request-system/pkg/pdf"
+)
+
+// reportCanvas — поверхность, на которой раскладывается отчёт по дашборду. Координаты в пунктах
+// от левого верхнего угла, ширина — A4; у текста y — базовая линия.
+type reportCanvas interface {
+	width(bold bool, size float64, text string) float64
+	text(bold bool, size, x, y float64, c color.Color, text string)
+	rect(x, y, width, height float64, c color.Color)
+	image(img image.Image, x, y, width, height float64)
+	// paged — есть ли у поверхности страницы; PNG — одна лента во всю высоту отчёта.
+	paged() bool
+	newPage()
+	encode() ([]byte, error)
+}
+
+// pdfReportCanvas выводит отчёт в PDF: страницы A4 с колонтитулом и нумерацией.
+type pdfReportCanvas struct {
+	doc     *pdf.Document
+	regular *pdf.Font
+	bold    *pdf.Font
+	footer  string
+	color   color.Color
+}
+
+func newPDFReportCanvas(regular, bold *pdf.Font, title, footer string) *pdfReportCanvas {
+	c := &pdfReportCanvas{doc: pdf.New(), regular: regular, bold: bold, footer: footer}
+	c.doc.SetTitle(title)
+	c.newPage()
+	return c
+}
+
+func (c *pdfReportCanvas) font(bold bool) *pdf.Font {
+	if bold {
+		return c.bold
+	}
+	return c.regular
+}
+
+// setColor меняет цвет, только если он отличается от текущего, чтобы не раздувать страницу.
+func (c *pdfReportCanvas) setColor(col color.Color) {
+	if c.color != nil && color.NRGBAModel.Convert(c.color) == color.NRGBAModel.Convert(col) {
+		return
+	}
+	c.doc.SetColor(col)
+	c.color = col
+}
+
+func (c *pdfReportCanvas) width(bold bool, size float64, text string) float64 {
+	return c.font(bold).Width(text, size)
+}
+
+func (c *pdfReportCanvas) text(bold bool, size, x, y float64, col color.Color, text string) {
+	c.setColor(col)
+	c.doc.Text(c.font(bold), size, x, y, text)
+}
+
+func (c *pdfReportCanvas) rect(x, y, width, height float64, col color.Color) {
+	c.setColor(col)
+	c.doc.Rect(x, y, width, height)
+}
+
+func (c *pdfReportCanvas) image(img image.Image, x, y, width, height float64) {
+	c.doc.Image(img, x, y, width, height)
+}
+
+func (c *pdfReportCanvas) paged() bool {
+	return true
+}
+
+func (c *pdfReportCanvas) newPage() {
+	c.doc.AddPage()
+	c.color = color.Black
+}
+
+func (c *pdfReportCanvas) encode() ([]byte, error) {
+	pages := c.doc.PageCount()
+	for i := 0; i < pages; i++ {
+		c.doc.SetPage(i)
+		c.doc.SetColor(reportMutedColor)
+		number := fmt.Sprintf("Стр. %d из %d", i+1, pages)
+		c.doc.Text(c.regular, 8, pdf.A4Width-reportMarginX-c.regular.Width(number, 8), pdf.A4Height-reportMarginBottom/2, number)
+		c.doc.Text(c.regular, 8, reportMarginX, pdf.A4Height-reportMarginBottom/2, c.footer)
+	}
+	return c.doc.Bytes()
+}
+
+// reportPNGScale — пикселей на пункт: ширина картинки около 1190 точек.
+const reportPNGScale = 2.0
+
+// pngReportCanvas копит команды рисования и выполняет их в encode, когда известна высота ленты.
+type pngReportCanvas struct {
+	regular *opentype.Font
+	bold    *opentype.Font
+	faces   map[pngFaceKey]font.Face
+	ops     []func(dst *image.RGBA)
+	bottom  float64
+}
+
+type pngFaceKey struct {
+	bold bool
+	size float64
+}
+
+func newPNGReportCanvas(regular, bold *opentype.Font) *pngReportCanvas {
+	return &pngReportCanvas{regular: regular, bold: bold, faces: make(map[pngFaceKey]font.Face)}
+}
+
+func (c *pngReportCanvas) face(bold bool, size float64) font.Face {
+	key := pngFaceKey{bold: bold, size: size}
+	if face, ok := c.faces[key]; ok {
+		return face
+	}
+	f := c.regular
+	if bold {
+		f = c.bold
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size * reportPNGScale, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		// Шрифт уже разобран, ошибка возможна только при нулевом кегле — такой текст не выводится.
+		return nil
+	}
+	c.faces[key] = face
+	return face
+}
+
+func (c *pngReportCanvas) extend(y float64) {
+	c.bottom = max(c.bottom, y)
+}
+
+func (c *pngReportCanvas) width(bold bool, size float64, text string) float64 {
+	face := c.face(bold, size)
+	if face == nil {
+		return 0
+	}
+	return float64(font.MeasureString(face, text)) / 64 / reportPNGScale
+}
+
+func (c *pngReportCanvas) text(bold bool, size, x, y float64, col color.Color, text string) {
+	face := c.face(bold, size)
+	if face == nil || text == "" {
+		return
+	}
+	c.extend(y + size/3)
+	c.ops = append(c.ops, func(dst *image.RGBA) {
+		d := font.Drawer{
+			Dst:  dst,
+			Src:  image.NewUniform(col),
+			Face: face,
+			Dot:  fixed.Point26_6{X: fixed.Int26_6(x * reportPNGScale * 64), Y: fixed.Int26_6(y * reportPNGScale * 64)},
+		}
+		d.DrawString(text)
+	})
+}
+
+func (c *pngReportCanvas) rect(x, y, width, height float64, col color.Color) {
+	c.extend(y + height)
+	c.ops = append(c.ops, func(dst *image.RGBA) {
+		draw.Draw(dst, pngRect(x, y, width, height), image.NewUniform(col), image.Point{}, draw.Over)
+	})
+}
+
+func (c *pngReportCanvas) image(img image.Image, x, y, width, height float64) {
+	c.extend(y + height)
+	c.ops = append(c.ops, func(dst *image.RGBA) {
+		draw.CatmullRom.Scale(dst, pngRect(x, y, width, height), img, img.Bounds(), draw.Over, nil)
+	})
+}
+
+func (c *pngReportCanvas) paged() bool {
+	return false
+}
+
+func (c *pngReportCanvas) newPage() {}
+
+func (c *pngReportCanvas) encode() ([]byte, error) {
+	defer func() {
+		for _, face := range c.faces {
+			face.Close()
+		}
+	}()
+
+	bounds := pngRect(0, 0, pdf.A4Width, c.bottom+reportMarginBottom/2)
+	dst := image.NewRGBA(bounds)
+	draw.Draw(dst, bounds, image.White, image.Point{}, draw.Src)
+	for _, op := range c.ops {
+		op(dst)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pngRect переводит прямоугольник в пунктах в пиксели, округляя края наружу.
+func pngRect(x, y, width, height float64) image.Rectangle {
+	return image.Rect(
+		int(math.Floor(x*reportPNGScale)), int(math.Floor(y*reportPNGScale)),
+		int(math.Ceil((x+width)*reportPNGScale)), int(math.Ceil((y+height)*reportPNGScale)),
+	)
+}
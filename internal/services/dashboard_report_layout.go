@@ -0,0 +1,358 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"request-system/internal/dto"
+	"request-system/pkg/pdf"
+	"request-system/pkg/types"
+)
+
+// Поля отчёта и размеры блоков, в пунктах.
+const (
+	reportMarginX       = 36.0
+	reportMarginTop     = 40.0
+	reportMarginBottom  = 50.0
+	reportContentWidth  = pdf.A4Width - 2*reportMarginX
+	reportHeaderHeight  = 72.0
+	reportLogoMaxHeight = 44.0
+	reportLogoMaxWidth  = 120.0
+	reportCardHeight    = 58.0
+	reportCardGap       = 8.0
+	reportChartHeight   = 130.0
+	reportBarLabelWidth = 170.0
+	reportBarRowHeight  = 15.0
+	reportTableRow      = 15.0
+	reportTableNumWidth = 62.0
+	// reportBarLimit — столько строк показывают блоки-рейтинги: исполнители, категории
+	reportBarLimit = 10
+)
+
+var (
+	reportTextColor   = color.RGBA{R: 0x1F, G: 0x29, B: 0x37, A: 0xFF}
+	reportMutedColor  = color.RGBA{R: 0x6B, G: 0x72, B: 0x80, A: 0xFF}
+	reportCardColor   = color.RGBA{R: 0xF3, G: 0xF4, B: 0xF6, A: 0xFF}
+	reportRuleColor   = color.RGBA{R: 0xE5, G: 0xE7, B: 0xEB, A: 0xFF}
+	reportAlertColor  = color.RGBA{R: 0xFD, G: 0xEC, B: 0xEC, A: 0xFF}
+	reportDangerColor = color.RGBA{R: 0xB9, G: 0x1C, B: 0x1C, A: 0xFF}
+)
+
+var reportScopeLabels = map[string]string{
+	types.DashboardScopeOwn:        "Мои заявки",
+	types.DashboardScopeOffice:     "Офис",
+	types.DashboardScopeOtdel:      "Отдел",
+	types.DashboardScopeBranch:     "Филиал",
+	types.DashboardScopeDepartment: "Департамент",
+	types.DashboardScopeAll:        "Все заявки",
+}
+
+// reportHeader — то, что печатается в шапке отчёта.
+type reportHeader struct {
+	brand       string
+	color       color.Color
+	logo        image.Image
+	title       string
+	period      string
+	scope       string
+	generatedAt string
+}
+
+// reportLayout раскладывает блоки дашборда сверху вниз; y — верх свободного места.
+type reportLayout struct {
+	c     reportCanvas
+	brand color.Color
+	y     float64
+}
+
+// layoutDashboardReport выводит шапку и блоки дашборда на canvas.
+func layoutDashboardReport(c reportCanvas, header reportHeader, stats *dto.DashboardStatsDTO) {
+	l := &reportLayout{c: c, brand: header.color}
+	l.header(header)
+
+	if stats.Alerts != nil && (stats.Alerts.CriticalCount > 0 || stats.Alerts.OverdueCount > 0) {
+		l.alerts(stats.Alerts)
+	}
+	if stats.KPIs != nil {
+		l.kpis(stats.KPIs, stats.SLA)
+	}
+	if len(stats.WeeklyVolume) > 0 {
+		l.section("Поступление заявок")
+		l.volumeChart(stats.WeeklyVolume)
+	}
+	if len(stats.CountByStatus) > 0 {
+		l.section("Заявки по статусам")
+		rows := make([]reportBar, 0, len(stats.CountByStatus))
+		for _, item := range stats.CountByStatus {
+			rows = append(rows, reportBar{label: item.GroupName, value: float64(item.Count), text: strconv.FormatInt(item.Count, 10)})
+		}
+		l.bars(rows)
+	}
+	if len(stats.TimeByPriority) > 0 {
+		l.section("Среднее время решения по приоритетам")
+		l.bars(reportTimeBars(stats.TimeByPriority))
+	}
+	if len(stats.TimeByOrderType) > 0 {
+		l.section("Среднее время решения по типам заявок")
+		l.bars(reportTimeBars(stats.TimeByOrderType))
+	}
+	if len(stats.CountByExecutor) > 0 {
+		l.section("Заявки по исполнителям")
+		rows := make([]reportBar, 0, len(stats.CountByExecutor))
+		for _, item := range stats.CountByExecutor[:min(len(stats.CountByExecutor), reportBarLimit)] {
+			rows = append(rows, reportBar{label: item.GroupName, value: float64(item.Count), text: strconv.FormatInt(item.Count, 10)})
+		}
+		l.bars(rows)
+	}
+	if len(stats.TopCategories) > 0 {
+		l.section("Частые категории")
+		rows := make([]reportBar, 0, len(stats.TopCategories))
+		for _, item := range stats.TopCategories[:min(len(stats.TopCategories), reportBarLimit)] {
+			rows = append(rows, reportBar{label: item.GroupName, value: float64(item.Count), text: strconv.FormatInt(item.Count, 10)})
+		}
+		l.bars(rows)
+	}
+	if len(stats.Departments) > 0 {
+		l.section("Департаменты")
+		l.structureTable(stats.Departments)
+	}
+	if len(stats.Branches) > 0 {
+		l.section("Филиалы")
+		l.structureTable(stats.Branches)
+	}
+}
+
+// ensure переносит вывод на новую страницу, если height пунктов не помещается на текущей.
+func (l *reportLayout) ensure(height float64) {
+	if l.c.paged() && l.y+height > pdf.A4Height-reportMarginBottom && l.y > reportMarginTop {
+		l.c.newPage()
+		l.y = reportMarginTop
+	}
+}
+
+// fit обрезает текст с многоточием, чтобы он уместился в width.
+func (l *reportLayout) fit(bold bool, size, width float64, text string) string {
+	if l.c.width(bold, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && l.c.width(bold, size, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+func (l *reportLayout) textRight(bold bool, size, right, y float64, c color.Color, text string) {
+	l.c.text(bold, size, right-l.c.width(bold, size, text), y, c, text)
+}
+
+func (l *reportLayout) header(h reportHeader) {
+	l.c.rect(0, 0, pdf.A4Width, reportHeaderHeight, h.color)
+	x := reportMarginX
+	if h.logo != nil {
+		bounds := h.logo.Bounds()
+		height := reportLogoMaxHeight
+		width := height * float64(bounds.Dx()) / float64(bounds.Dy())
+		if width > reportLogoMaxWidth {
+			width = reportLogoMaxWidth
+			height = width * float64(bounds.Dy()) / float64(bounds.Dx())
+		}
+		l.c.image(h.logo, x, (reportHeaderHeight-height)/2, width, height)
+		x += width + 12
+	}
+	generated := "Сформирован " + h.generatedAt
+	brandWidth := reportMarginX + reportContentWidth - x - l.c.width(false, 9, generated) - 12
+	l.c.text(true, 18, x, 38, color.White, l.fit(true, 18, brandWidth, h.brand))
+	l.c.text(false, 11, x, 56, color.White, h.title)
+	l.textRight(false, 9, reportMarginX+reportContentWidth, 38, color.White, generated)
+
+	l.y = reportHeaderHeight + 22
+	l.c.text(false, 10, reportMarginX, l.y, reportTextColor, "Период: "+h.period)
+	l.textRight(false, 10, reportMarginX+reportContentWidth, l.y, reportTextColor, "Область: "+h.scope)
+	l.y += 14
+}
+
+func (l *reportLayout) alerts(alerts *types.DashboardAlerts) {
+	l.y += 4
+	l.c.rect(reportMarginX, l.y, reportContentWidth, 22, reportAlertColor)
+	text := fmt.Sprintf("Критичных заявок в работе: %d    Просроченных: %d", alerts.CriticalCount, alerts.OverdueCount)
+	l.c.text(true, 10, reportMarginX+10, l.y+15, reportDangerColor, text)
+	l.y += 22
+}
+
+func (l *reportLayout) kpis(kpis *types.DashboardKPIs, sla *types.DashboardSLAStats) {
+	cards := []struct {
+		label  string
+		metric *types.DashboardKPIMetric
+		value  string
+	}{
+		{label: "Всего заявок", metric: &kpis.TotalTickets},
+		{label: "Открытые", metric: &kpis.OpenTickets},
+		{label: "Решённые", metric: &kpis.ResolvedTickets},
+		{label: "Соблюдение SLA", metric: &kpis.SLACompliance},
+		{label: "Среднее время реакции", metric: &kpis.AvgResponseTime},
+		{label: "Среднее время решения", metric: &kpis.AvgResolveTime},
+		{label: "Решено с первого раза", metric: &kpis.FCRRate},
+		{label: "Активные исполнители", value: strconv.FormatInt(kpis.ActiveAgents, 10)},
+	}
+
+	const perRow = 4
+	width := (reportContentWidth - reportCardGap*(perRow-1)) / perRow
+	l.y += 10
+	for i, card := range cards {
+		if i%perRow == 0 {
+			if i > 0 {
+				l.y += reportCardHeight + reportCardGap
+			}
+			l.ensure(reportCardHeight)
+		}
+		x := reportMarginX + float64(i%perRow)*(width+reportCardGap)
+		l.c.rect(x, l.y, width, reportCardHeight, reportCardColor)
+		l.c.rect(x, l.y, 3, reportCardHeight, l.brand)
+		l.c.text(false, 8, x+10, l.y+15, reportMutedColor, l.fit(false, 8, width-14, card.label))
+
+		value, trend := card.value, ""
+		if card.metric != nil {
+			value, trend = card.metric.Formatted, card.metric.TrendText
+			if value == "" {
+				value = strconv.FormatFloat(card.metric.Current, 'f', -1, 64)
+			}
+		}
+		l.c.text(true, 16, x+10, l.y+36, reportTextColor, l.fit(true, 16, width-14, value))
+		if trend != "" {
+			l.c.text(false, 7.5, x+10, l.y+50, reportMutedColor, l.fit(false, 7.5, width-14, trend))
+		}
+	}
+	l.y += reportCardHeight
+
+	if sla != nil && sla.TotalCompleted > 0 {
+		l.y += 16
+		l.c.text(false, 9, reportMarginX, l.y, reportMutedColor,
+			fmt.Sprintf("SLA: в срок %d из %d завершённых заявок", sla.OnTime, sla.TotalCompleted))
+	}
+}
+
+func (l *reportLayout) section(title string) {
+	l.y += 22
+	// Заголовок не остаётся последней строкой страницы.
+	l.ensure(70)
+	l.c.rect(reportMarginX, l.y, 4, 14, l.brand)
+	l.c.text(true, 12, reportMarginX+10, l.y+11.5, reportTextColor, title)
+	l.y += 20
+}
+
+// volumeChart — столбцы поступления заявок по дням, неделям или месяцам периода.
+func (l *reportLayout) volumeChart(points []types.DashboardChartData) {
+	l.ensure(reportChartHeight + 24)
+	var top int64
+	for _, p := range points {
+		top = max(top, p.Value)
+	}
+	top = max(top, 1)
+
+	slot := reportContentWidth / float64(len(points))
+	barWidth := min(slot*0.7, 40)
+	labelWidth := 0.0
+	for _, p := range points {
+		labelWidth = max(labelWidth, l.c.width(false, 7, p.Label))
+	}
+	// Подписи, которые не помещаются под каждым столбцом, печатаются через одну, две и т. д.
+	step := max(1, int(math.Ceil((labelWidth+6)/slot)))
+	showValues := l.c.width(false, 7, strconv.FormatInt(top, 10)) <= slot
+
+	baseline := l.y + reportChartHeight
+	l.c.rect(reportMarginX, baseline, reportContentWidth, 0.75, reportRuleColor)
+	for i, p := range points {
+		center := reportMarginX + slot*(float64(i)+0.5)
+		height := (reportChartHeight - 14) * float64(p.Value) / float64(top)
+		l.c.rect(center-barWidth/2, baseline-height, barWidth, height, l.brand)
+		if showValues && p.Value > 0 {
+			value := strconv.FormatInt(p.Value, 10)
+			l.c.text(false, 7, center-l.c.width(false, 7, value)/2, baseline-height-3, reportTextColor, value)
+		}
+		if i%step == 0 {
+			l.c.text(false, 7, center-l.c.width(false, 7, p.Label)/2, baseline+11, reportMutedColor, p.Label)
+		}
+	}
+	l.y = baseline + 16
+}
+
+// reportBar — строка горизонтальной диаграммы: длина по value, подпись справа — text.
+type reportBar struct {
+	label string
+	value float64
+	text  string
+}
+
+func reportTimeBars(items []types.DashboardTimeByGroup) []reportBar {
+	rows := make([]reportBar, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, reportBar{label: item.GroupName, value: item.AvgSeconds, text: item.AvgTimeFormatted})
+	}
+	return rows
+}
+
+func (l *reportLayout) bars(rows []reportBar) {
+	top := 0.0
+	textWidth := 0.0
+	for _, row := range rows {
+		top = max(top, row.value)
+		textWidth = max(textWidth, l.c.width(false, 8, row.text))
+	}
+	if top <= 0 {
+		top = 1
+	}
+	barArea := reportContentWidth - reportBarLabelWidth - textWidth - 8
+
+	for _, row := range rows {
+		l.ensure(reportBarRowHeight)
+		l.c.text(false, 8.5, reportMarginX, l.y+10, reportTextColor, l.fit(false, 8.5, reportBarLabelWidth-8, row.label))
+		width := barArea * row.value / top
+		l.c.rect(reportMarginX+reportBarLabelWidth, l.y+3, barArea, 9, reportCardColor)
+		l.c.rect(reportMarginX+reportBarLabelWidth, l.y+3, width, 9, l.brand)
+		l.textRight(false, 8, reportMarginX+reportContentWidth, l.y+10, reportTextColor, row.text)
+		l.y += reportBarRowHeight
+	}
+}
+
+// structureTable — таблица по департаментам или филиалам.
+func (l *reportLayout) structureTable(rows []types.DashboardDepartmentStat) {
+	headers := []string{"Открыто", "Решено", "Критичных", "Всего", "Решено, %"}
+	nameWidth := reportContentWidth - reportTableNumWidth*float64(len(headers))
+	right := func(i int) float64 {
+		return reportMarginX + nameWidth + reportTableNumWidth*float64(i+1) - 6
+	}
+	header := func() {
+		l.c.rect(reportMarginX, l.y, reportContentWidth, reportTableRow, reportCardColor)
+		l.c.text(true, 8, reportMarginX+6, l.y+10.5, reportTextColor, "Название")
+		for i, h := range headers {
+			l.textRight(true, 8, right(i), l.y+10.5, reportTextColor, h)
+		}
+		l.y += reportTableRow
+	}
+
+	l.ensure(2 * reportTableRow)
+	header()
+	for _, row := range rows {
+		if l.c.paged() && l.y+reportTableRow > pdf.A4Height-reportMarginBottom {
+			l.ensure(reportTableRow)
+			header()
+		}
+		cells := []string{
+			strconv.FormatInt(row.OpenCount, 10),
+			strconv.FormatInt(row.ResolvedCount, 10),
+			strconv.FormatInt(row.CriticalCount, 10),
+			strconv.FormatInt(row.TotalCount, 10),
+			strconv.FormatFloat(row.SolvedPercent, 'f', 1, 64),
+		}
+		l.c.text(false, 8.5, reportMarginX+6, l.y+10.5, reportTextColor, l.fit(false, 8.5, nameWidth-12, row.Name))
+		for i, cell := range cells {
+			l.textRight(false, 8.5, right(i), l.y+10.5, reportTextColor, cell)
+		}
+		l.y += reportTableRow
+		l.c.rect(reportMarginX, l.y-0.5, reportContentWidth, 0.5, reportRuleColor)
+	}
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+
+	"request-system/internal/dto"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/pdf"
+	"request-system/pkg/utils"
+)
+
+// Форматы отчёта по дашборду.
+const (
+	DashboardReportPDF = "pdf"
+	DashboardReportPNG = "png"
+)
+
+const (
+	reportDateLayout    = "02.01.2006"
+	reportDefaultColor  = "#1F4E79"
+	reportDocumentTitle = "Отчёт по заявкам"
+)
+
+// DashboardReport — готовый отчёт по дашборду.
+type DashboardReport struct {
+	Content     []byte
+	ContentType string
+	FileName    string
+}
+
+// DashboardStatsReader — источник данных отчёта; его реализует DashboardService.
+type DashboardStatsReader interface {
+	GetDashboardStats(ctx context.Context, filter dto.DashboardFilterDTO) (*dto.DashboardStatsDTO, error)
+}
+
+type DashboardReportServiceInterface interface {
+	// RenderReport собирает основные блоки дашборда за период и область filter в отчёт
+	// формата pdf или png. Права и область проверяет GetDashboardStats.
+	RenderReport(ctx context.Context, filter dto.DashboardFilterDTO, format string) (*DashboardReport, error)
+}
+
+// DashboardReportService печатает дашборд в оформлении организации: шапка с названием, цветом
+// и логотипом из REPORT_*, KPI, поступление заявок, статусы, время решения, исполнители,
+// категории, департаменты и филиалы. PDF разбит на страницы A4, PNG — одна картинка во всю высоту.
+type DashboardReportService struct {
+	stats   DashboardStatsReader
+	brand   string
+	color   color.Color
+	logo    image.Image
+	regular []byte
+	bold    []byte
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+func NewDashboardReportService(stats DashboardStatsReader, cfg config.ReportsConfig, logger *zap.Logger) DashboardReportServiceInterface {
+	s := &DashboardReportService{
+		stats:   stats,
+		brand:   cfg.BrandName,
+		regular: goregular.TTF,
+		bold:    gobold.TTF,
+		logger:  logger,
+		now:     time.Now,
+	}
+
+	// Ошибки оформления не мешают запуску: отчёт печатается с цветом и шрифтами по умолчанию.
+	brandColor, err := parseReportColor(cfg.BrandColor)
+	if err != nil {
+		logger.Error("Некорректный REPORT_BRAND_COLOR, используется цвет по умолчанию", zap.String("color", cfg.BrandColor), zap.Error(err))
+		brandColor, _ = parseReportColor(reportDefaultColor)
+	}
+	s.color = brandColor
+
+	if cfg.LogoPath != "" {
+		if logo, err := loadReportLogo(cfg.LogoPath); err != nil {
+			logger.Error("Не удалось загрузить логотип отчёта, отчёт печатается без него", zap.String("path", cfg.LogoPath), zap.Error(err))
+		} else {
+			s.logo = logo
+		}
+	}
+
+	for _, f := range []struct {
+		path   string
+		target *[]byte
+	}{{cfg.FontPath, &s.regular}, {cfg.BoldFontPath, &s.bold}} {
+		if f.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(f.path)
+		if err == nil {
+			_, err = pdf.ParseFont(data)
+		}
+		if err != nil {
+			logger.Error("Не удалось загрузить шрифт отчёта, используется встроенный", zap.String("path", f.path), zap.Error(err))
+			continue
+		}
+		*f.target = data
+	}
+	return s
+}
+
+func (s *DashboardReportService) RenderReport(ctx context.Context, filter dto.DashboardFilterDTO, format string) (*DashboardReport, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = DashboardReportPDF
+	}
+	if format != DashboardReportPDF && format != DashboardReportPNG {
+		return nil, apperrors.NewBadRequestError("format: допустимы pdf и png")
+	}
+
+	// В отчёт входят все блоки, выбор виджетов на экране к нему не относится.
+	filter.Widgets = nil
+	stats, err := s.stats.GetDashboardStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := utils.LocationFromCtx(ctx)
+	header := reportHeader{
+		brand:       s.brand,
+		color:       s.color,
+		logo:        s.logo,
+		title:       reportDocumentTitle,
+		generatedAt: s.now().In(loc).Format(orderActDateLayout),
+	}
+	if stats.Meta != nil {
+		header.period = reportPeriod(stats.Meta.DateFrom, stats.Meta.DateTo, loc)
+		header.scope = reportScopeLabels[stats.Meta.EffectiveScope]
+	}
+
+	canvas, err := s.newCanvas(format, header)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось загрузить шрифт отчёта", err, nil)
+	}
+	layoutDashboardReport(canvas, header, stats)
+	content, err := canvas.encode()
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось построить отчёт", err, nil)
+	}
+
+	s.logger.Info("Сформирован отчёт по дашборду", zap.String("format", format), zap.Int("bytes", len(content)))
+	report := &DashboardReport{
+		Content:  content,
+		FileName: fmt.Sprintf("dashboard-report-%s.%s", s.now().In(loc).Format("2006-01-02"), format),
+	}
+	if format == DashboardReportPNG {
+		report.ContentType = "image/png"
+	} else {
+		report.ContentType = "application/pdf"
+	}
+	return report, nil
+}
+
+// newCanvas разбирает шрифты заново на каждый отчёт: pdf.Font и лица opentype не делятся между запросами.
+func (s *DashboardReportService) newCanvas(format string, header reportHeader) (reportCanvas, error) {
+	if format == DashboardReportPNG {
+		regular, err := opentype.Parse(s.regular)
+		if err != nil {
+			return nil, err
+		}
+		bold, err := opentype.Parse(s.bold)
+		if err != nil {
+			return nil, err
+		}
+		return newPNGReportCanvas(regular, bold), nil
+	}
+
+	regular, err := pdf.ParseFont(s.regular)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := pdf.ParseFont(s.bold)
+	if err != nil {
+		return nil, err
+	}
+	footer := header.brand + " · " + header.title
+	if header.period != "" {
+		footer += " · " + header.period
+	}
+	return newPDFReportCanvas(regular, bold, header.title, footer), nil
+}
+
+// reportPeriod — границы периода из meta дашборда датами в часовом поясе пользователя.
+func reportPeriod(from, to string, loc *time.Location) string {
+	start, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return ""
+	}
+	end, err := time.Parse(time.RFC3339, to)
+	if err != nil {
+		return ""
+	}
+	if start.In(loc).Format(reportDateLayout) == end.In(loc).Format(reportDateLayout) {
+		return start.In(loc).Format(reportDateLayout)
+	}
+	return start.In(loc).Format(reportDateLayout) + " — " + end.In(loc).Format(reportDateLayout)
+}
+
+// parseReportColor разбирает цвет вида #RRGGBB.
+func parseReportColor(value string) (color.Color, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("ожидается цвет вида #RRGGBB")
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("ожидается цвет вида #RRGGBB: %w", err)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF}, nil
+}
+
+func loadReportLogo(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	logo, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	if logo.Bounds().Empty() {
+		return nil, fmt.Errorf("пустое изображение")
+	}
+	return logo, nil
+}
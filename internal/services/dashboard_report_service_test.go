@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/pkg/config"
+	"request-system/pkg/types"
+)
+
+type reportStatsStub struct {
+	filter dto.DashboardFilterDTO
+	stats  *dto.DashboardStatsDTO
+}
+
+func (s *reportStatsStub) GetDashboardStats(_ context.Context, filter dto.DashboardFilterDTO) (*dto.DashboardStatsDTO, error) {
+	s.filter = filter
+	return s.stats, nil
+}
+
+func reportTestStats() *dto.DashboardStatsDTO {
+	volume := make([]types.DashboardChartData, 0, 30)
+	for day := 1; day <= 30; day++ {
+		volume = append(volume, types.DashboardChartData{Label: time.Date(2026, 9, day, 0, 0, 0, 0, time.UTC).Format("02.01"), Value: int64(day % 7)})
+	}
+	departments := make([]types.DashboardDepartmentStat, 0, 60)
+	for i := 0; i < 60; i++ {
+		departments = append(departments, types.DashboardDepartmentStat{Name: "Департамент информационных технологий и связи", OpenCount: 3, ResolvedCount: 7, TotalCount: 10, SolvedPercent: 70})
+	}
+	return &dto.DashboardStatsDTO{
+		Meta:   &types.DashboardMeta{EffectiveScope: types.DashboardScopeBranch, DateFrom: "2026-09-01T00:00:00Z", DateTo: "2026-09-30T23:59:59Z"},
+		Alerts: &types.DashboardAlerts{CriticalCount: 2, OverdueCount: 1},
+		KPIs: &types.DashboardKPIs{
+			TotalTickets:  types.DashboardKPIMetric{Current: 120, Formatted: "120", TrendText: "+12% к прошлому периоду"},
+			SLACompliance: types.DashboardKPIMetric{Current: 93.5, Formatted: "93.5%"},
+			ActiveAgents:  8,
+		},
+		SLA:             &types.DashboardSLAStats{TotalCompleted: 100, OnTime: 93},
+		WeeklyVolume:    volume,
+		CountByStatus:   []types.DashboardCountByGroup{{GroupName: "Открыта", Count: 20}, {GroupName: "Закрыта", Count: 100}},
+		TimeByPriority:  []types.DashboardTimeByGroup{{GroupName: "Критический", AvgSeconds: 3600, AvgTimeFormatted: "1ч 0м"}},
+		CountByExecutor: []types.DashboardExecutorCount{{GroupName: "Иванов Иван", Count: 40}},
+		Departments:     departments,
+	}
+}
+
+func TestDashboardReportRendersPagedPDF(t *testing.T) {
+	stats := &reportStatsStub{stats: reportTestStats()}
+	service := NewDashboardReportService(stats, config.ReportsConfig{BrandName: "Банк", BrandColor: "#0A7F3F"}, zap.NewNop())
+
+	report, err := service.RenderReport(context.Background(), dto.DashboardFilterDTO{Scope: "branch", Widgets: []string{"kpis"}}, "")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if report.ContentType != "application/pdf" || filepath.Ext(report.FileName) != ".pdf" {
+		t.Fatalf("unexpected report: %s %s", report.ContentType, report.FileName)
+	}
+	if stats.filter.Scope != "branch" || stats.filter.Widgets != nil {
+		t.Fatalf("the report must keep the scope and request every block, got %+v", stats.filter)
+	}
+	if !bytes.HasPrefix(report.Content, []byte("%PDF-")) || !bytes.HasSuffix(report.Content, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF document")
+	}
+	// Шестьдесят строк таблицы не помещаются на одну страницу с остальными блоками.
+	if !bytes.Contains(report.Content, []byte("/Count 2")) && !bytes.Contains(report.Content, []byte("/Count 3")) {
+		t.Fatalf("expected the report to span several pages")
+	}
+}
+
+func TestDashboardReportRendersPNGWithLogo(t *testing.T) {
+	logo := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			logo.Set(x, y, color.NRGBA{R: 0xFF, A: 0xFF})
+		}
+	}
+	logoPath := filepath.Join(t.TempDir(), "logo.png")
+	var logoFile bytes.Buffer
+	if err := png.Encode(&logoFile, logo); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logoPath, logoFile.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &reportStatsStub{stats: reportTestStats()}
+	service := NewDashboardReportService(stats, config.ReportsConfig{BrandName: "Банк", BrandColor: "не цвет", LogoPath: logoPath}, zap.NewNop())
+
+	report, err := service.RenderReport(context.Background(), dto.DashboardFilterDTO{}, "PNG")
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if report.ContentType != "image/png" {
+		t.Fatalf("unexpected content type %s", report.ContentType)
+	}
+	img, err := png.Decode(bytes.NewReader(report.Content))
+	if err != nil {
+		t.Fatalf("not a PNG: %v", err)
+	}
+	// Лента во всю высоту отчёта, а не одна страница A4.
+	if bounds := img.Bounds(); bounds.Dx() < 1180 || bounds.Dy() <= bounds.Dx()*1414/1000 {
+		t.Fatalf("unexpected image size %v", bounds)
+	}
+	// Логотип в шапке, шапка — цветом по умолчанию вместо некорректного.
+	if r, g, b, _ := img.At(int(reportMarginX*reportPNGScale)+4, int(reportHeaderHeight*reportPNGScale/2)).RGBA(); r>>8 < 0xF0 || g>>8 > 0x10 || b>>8 > 0x10 {
+		t.Fatalf("logo is not drawn: %d %d %d", r>>8, g>>8, b>>8)
+	}
+	if r, g, b, _ := img.At(img.Bounds().Dx()-4, 4).RGBA(); r>>8 != 0x1F || g>>8 != 0x4E || b>>8 != 0x79 {
+		t.Fatalf("header must use the default brand color: %x %x %x", r>>8, g>>8, b>>8)
+	}
+
+	if _, err := service.RenderReport(context.Background(), dto.DashboardFilterDTO{}, "docx"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
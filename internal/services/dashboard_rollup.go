@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/pkg/types"
+)
+
+const dashboardRollupCoverageTTL = time.Minute
+
+// dashboardRollupCoverage кеширует в памяти дату, по которую посчитаны суточные агрегаты,
+// чтобы не спрашивать её у базы на каждый промах кеша дашборда.
+type dashboardRollupCoverage struct {
+	mu        sync.Mutex
+	through   time.Time
+	ok        bool
+	checkedAt time.Time
+}
+
+// dashboardRollupUntil возвращает границу, до которой виджеты могут читать daily_order_stats.
+// Сегодняшний день всегда считается живыми запросами. Агрегаты не хранят автора и исполнителя,
+// поэтому для личной области видимости они не применяются.
+func (s *DashboardService) dashboardRollupUntil(ctx context.Context, req dashboardRequest) time.Time {
+	if s.statsRepo == nil || req.effectiveScope == types.DashboardScopeOwn {
+		return time.Time{}
+	}
+
+	through, ok := s.loadDashboardRollupCoverage(ctx)
+	if !ok {
+		return time.Time{}
+	}
+
+	now := time.Now().In(time.Local)
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	until := through.AddDate(0, 0, 1)
+	if until.After(startOfToday) {
+		until = startOfToday
+	}
+	return until
+}
+
+func (s *DashboardService) loadDashboardRollupCoverage(ctx context.Context) (time.Time, bool) {
+	s.coverage.mu.Lock()
+	defer s.coverage.mu.Unlock()
+
+	if !s.coverage.checkedAt.IsZero() && time.Since(s.coverage.checkedAt) < dashboardRollupCoverageTTL {
+		return s.coverage.through, s.coverage.ok
+	}
+
+	through, ok, err := s.statsRepo.GetCoveredThrough(ctx)
+	if err != nil {
+		s.logger.Warn("Не удалось получить покрытие daily_order_stats, дашборд считается по живым данным", zap.Error(err))
+		return time.Time{}, false
+	}
+
+	s.coverage.through = through
+	s.coverage.ok = ok
+	s.coverage.checkedAt = time.Now()
+	return through, ok
+}
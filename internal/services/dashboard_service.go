@@ -28,7 +28,11 @@ import (
 	"request-system/pkg/utils"
 )
 
-const dashboardCacheTTL = 3 * time.Minute
+const (
+	dashboardCacheTTL         = 3 * time.Minute
+	dashboardAlertsCacheTTL   = time.Minute
+	dashboardActivityCacheTTL = 30 * time.Second
+)
 
 const (
 	dashboardWidgetAlerts          = "alerts"
@@ -61,12 +65,14 @@ var dashboardWidgetSet = map[string]struct{}{
 }
 
 type DashboardService struct {
-	repo     repositories.DashboardRepositoryInterface
-	userRepo repositories.UserRepositoryInterface
-	cache    repositories.CacheRepositoryInterface
-	logger   *zap.Logger
-	flight   singleflight.Group
-	workers  int
+	repo      repositories.DashboardRepositoryInterface
+	userRepo  repositories.UserRepositoryInterface
+	cache     repositories.CacheRepositoryInterface
+	statsRepo repositories.DailyOrderStatsRepositoryInterface
+	logger    *zap.Logger
+	flight    singleflight.Group
+	workers   int
+	coverage  dashboardRollupCoverage
 }
 
 type dashboardRequest struct {
@@ -80,48 +86,64 @@ func NewDashboardService(
 	repo repositories.DashboardRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	cache repositories.CacheRepositoryInterface,
+	statsRepo repositories.DailyOrderStatsRepositoryInterface,
 	logger *zap.Logger,
 ) *DashboardService {
 	return &DashboardService{
-		repo:     repo,
-		userRepo: userRepo,
-		cache:    cache,
-		logger:   logger,
-		workers:  loadDashboardWorkerLimit(),
+		repo:      repo,
+		userRepo:  userRepo,
+		cache:     cache,
+		statsRepo: statsRepo,
+		logger:    logger,
+		workers:   loadDashboardWorkerLimit(),
 	}
 }
 
 func (s *DashboardService) GetDashboardStats(ctx context.Context, filter dto.DashboardFilterDTO) (*dto.DashboardStatsDTO, error) {
-	userID, err := utils.GetUserIDFromCtx(ctx)
+	userID, actor, authContext, err := s.authorizeDashboard(ctx)
 	if err != nil {
-		return nil, apperrors.ErrUnauthorized
+		return nil, err
 	}
 
-	permissionsMap, err := utils.GetPermissionsMapFromCtx(ctx)
+	req, err := buildDashboardRequest(filter, userID)
 	if err != nil {
-		return nil, apperrors.ErrUnauthorized
+		return nil, err
 	}
 
-	actor, err := s.userRepo.FindUserByID(ctx, userID)
+	securityCondition, err := narrowDashboardSecurity(&authContext, actor, &req, resolveDashboardSecurity(&authContext, actor, &req))
 	if err != nil {
-		return nil, apperrors.ErrUserNotFound
+		return nil, err
 	}
+	return s.loadDashboardWithCache(ctx, userID, actor, req, securityCondition)
+}
 
-	authContext := authz.Context{Actor: actor, Permissions: permissionsMap}
-	if !authz.CanDo(authz.DashboardView, authContext) {
-		return nil, apperrors.ErrForbidden
+// authorizeDashboard загружает текущего пользователя и проверяет право на просмотр дашборда.
+func (s *DashboardService) authorizeDashboard(ctx context.Context) (uint64, *entities.User, authz.Context, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return 0, nil, authz.Context{}, apperrors.ErrUnauthorized
 	}
 
-	req, err := buildDashboardRequest(filter, userID)
+	permissionsMap, err := utils.GetPermissionsMapFromCtx(ctx)
 	if err != nil {
-		return nil, err
+		return 0, nil, authz.Context{}, apperrors.ErrUnauthorized
+	}
+
+	actor, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return 0, nil, authz.Context{}, apperrors.ErrUserNotFound
 	}
 
-	securityCondition := resolveDashboardSecurity(&authContext, actor, &req)
-	return s.loadDashboardWithCache(ctx, userID, actor, permissionsMap, req, securityCondition)
+	authContext := authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}
+	if !authz.CanDo(authz.DashboardView, authContext) {
+		return 0, nil, authz.Context{}, apperrors.ErrForbidden
+	}
+	return userID, actor, authContext, nil
 }
 
 func (s *DashboardService) loadDashboardStats(ctx context.Context, req dashboardRequest, securityCondition sq.Sqlizer) (*dto.DashboardStatsDTO, error) {
+	req.query.RollupUntil = s.dashboardRollupUntil(ctx, req)
+
 	var (
 		alerts    *types.DashboardAlerts
 		kpis      *types.DashboardKPIs
@@ -271,7 +293,7 @@ func (s *DashboardService) readDashboardFromCache(ctx context.Context, cacheKey
 	return &result, nil
 }
 
-func (s *DashboardService) writeDashboardToCache(ctx context.Context, cacheKey string, result *dto.DashboardStatsDTO) {
+func (s *DashboardService) writeDashboardToCache(ctx context.Context, cacheKey string, result *dto.DashboardStatsDTO, ttl time.Duration) {
 	if s.cache == nil {
 		return
 	}
@@ -280,7 +302,7 @@ func (s *DashboardService) writeDashboardToCache(ctx context.Context, cacheKey s
 		s.logger.Warn("dashboard cache marshal failed", zap.Error(err))
 		return
 	}
-	if err := s.cache.Set(ctx, cacheKey, payload, dashboardCacheTTL); err != nil {
+	if err := s.cache.Set(ctx, cacheKey, payload, ttl); err != nil {
 		s.logger.Warn("dashboard cache set failed", zap.Error(err))
 	}
 }
@@ -289,13 +311,12 @@ func (s *DashboardService) loadDashboardWithCache(
 	ctx context.Context,
 	userID uint64,
 	actor *entities.User,
-	permissionsMap map[string]bool,
 	req dashboardRequest,
 	securityCondition sq.Sqlizer,
 ) (*dto.DashboardStatsDTO, error) {
 	parts := splitDashboardRequestForCache(req)
 	if len(parts) == 1 {
-		return s.loadDashboardSliceWithCache(ctx, userID, actor, permissionsMap, parts[0], securityCondition)
+		return s.loadDashboardSliceWithCache(ctx, userID, actor, parts[0], securityCondition)
 	}
 
 	results := make([]dashboardSliceResult, len(parts))
@@ -305,7 +326,7 @@ func (s *DashboardService) loadDashboardWithCache(
 	for i, part := range parts {
 		i, part := i, part
 		group.Go(func() error {
-			stats, err := s.loadDashboardSliceWithCache(groupCtx, userID, actor, permissionsMap, part, securityCondition)
+			stats, err := s.loadDashboardSliceWithCache(groupCtx, userID, actor, part, securityCondition)
 			if err != nil {
 				return err
 			}
@@ -325,53 +346,71 @@ func (s *DashboardService) loadDashboardWithCache(
 	return mergeDashboardStats(results), nil
 }
 
+// loadDashboardSliceWithCache собирает блоки дашборда: каждый виджет кешируется отдельно,
+// а недостающие считаются одним проходом и раскладываются по своим ключам.
 func (s *DashboardService) loadDashboardSliceWithCache(
 	ctx context.Context,
 	userID uint64,
 	actor *entities.User,
-	permissionsMap map[string]bool,
 	req dashboardRequest,
 	securityCondition sq.Sqlizer,
 ) (*dto.DashboardStatsDTO, error) {
 	cacheVersion := s.loadDashboardCacheVersion(ctx, req.widgets)
-	cacheKey, err := buildDashboardCacheKey(userID, actor, permissionsMap, req, cacheVersion)
-	if err != nil {
-		s.logger.Warn("dashboard cache key build failed", zap.Uint64("user_id", userID), zap.Error(err))
-		cacheKey = ""
-	}
-
-	if cacheKey != "" {
-		if cached, cacheErr := s.readDashboardFromCache(ctx, cacheKey); cacheErr == nil {
-			return cached, nil
+	result := &dto.DashboardStatsDTO{Meta: buildDashboardMeta(req)}
+
+	missing := make(map[string]struct{})
+	keys := make(map[string]string, len(req.widgets))
+	for _, widget := range sortedDashboardWidgets(req.widgets) {
+		key, err := buildDashboardBlockCacheKey(widget, userID, actor, req, cacheVersion)
+		if err != nil {
+			s.logger.Warn("dashboard cache key build failed", zap.Uint64("user_id", userID), zap.Error(err))
+			missing[widget] = struct{}{}
+			continue
 		}
-	}
+		keys[widget] = key
 
-	loadFn := func() (*dto.DashboardStatsDTO, error) {
-		result, loadErr := s.loadDashboardStats(ctx, req, securityCondition)
-		if loadErr != nil {
-			return nil, loadErr
-		}
-		if cacheKey != "" {
-			s.writeDashboardToCache(ctx, cacheKey, result)
+		if cached, cacheErr := s.readDashboardFromCache(ctx, key); cacheErr == nil {
+			applyDashboardSlice(result, cached, dashboardSingleWidgetRequest(widget))
+			continue
 		}
+		missing[widget] = struct{}{}
+	}
+	if len(missing) == 0 {
 		return result, nil
 	}
 
-	if cacheKey == "" {
-		return loadFn()
+	missingReq := cloneDashboardRequestWithWidgets(req, missing)
+	flightParts := make([]string, 0, len(missing))
+	for _, widget := range sortedDashboardWidgets(missing) {
+		flightParts = append(flightParts, keys[widget])
 	}
+	flightKey := strings.Join(flightParts, ",")
 
-	value, err, _ := s.flight.Do(cacheKey, func() (interface{}, error) {
-		if cached, cacheErr := s.readDashboardFromCache(ctx, cacheKey); cacheErr == nil {
-			return cached, nil
+	value, err, _ := s.flight.Do(flightKey, func() (interface{}, error) {
+		loaded, loadErr := s.loadDashboardStats(ctx, missingReq, securityCondition)
+		if loadErr != nil {
+			return nil, loadErr
 		}
-		return loadFn()
+		for widget := range missing {
+			if keys[widget] == "" {
+				continue
+			}
+			block := &dto.DashboardStatsDTO{}
+			applyDashboardSlice(block, loaded, dashboardSingleWidgetRequest(widget))
+			s.writeDashboardToCache(ctx, keys[widget], block, dashboardBlockTTL(widget))
+		}
+		return loaded, nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return value.(*dto.DashboardStatsDTO), nil
+	applyDashboardSlice(result, value.(*dto.DashboardStatsDTO), missingReq)
+	return result, nil
+}
+
+func dashboardSingleWidgetRequest(widget string) dashboardRequest {
+	return dashboardRequest{widgets: map[string]struct{}{widget: {}}}
 }
 
 func (s *DashboardService) loadDashboardCacheVersion(ctx context.Context, widgets map[string]struct{}) string {
@@ -426,30 +465,72 @@ func buildDashboardRequest(filter dto.DashboardFilterDTO, userID uint64) (dashbo
 	}, nil
 }
 
+// resolveDashboardSecurity выбирает самую широкую область пользователя: по ней же делится кеш
+// блоков между пользователями, поэтому области не объединяются, как в списке заявок.
 func resolveDashboardSecurity(authContext *authz.Context, actor *entities.User, req *dashboardRequest) sq.Sqlizer {
-	switch {
-	case authContext.HasPermission(authz.ScopeAll) || authContext.HasPermission(authz.ScopeAllView):
+	if authContext.HasPermission(authz.ScopeAll) || authContext.HasPermission(authz.ScopeAllView) {
 		req.effectiveScope = types.DashboardScopeAll
 		return nil
-	case authContext.HasPermission(authz.ScopeDepartment) && actor.DepartmentID != nil:
-		req.effectiveScope = types.DashboardScopeDepartment
-		return sq.Eq{"o.department_id": *actor.DepartmentID}
-	case authContext.HasPermission(authz.ScopeBranch) && actor.BranchID != nil:
-		req.effectiveScope = types.DashboardScopeBranch
-		return sq.Eq{"o.branch_id": *actor.BranchID}
-	case authContext.HasPermission(authz.ScopeOtdel) && actor.OtdelID != nil:
-		req.effectiveScope = types.DashboardScopeOtdel
-		return sq.Eq{"o.otdel_id": *actor.OtdelID}
-	case authContext.HasPermission(authz.ScopeOffice) && actor.OfficeID != nil:
-		req.effectiveScope = types.DashboardScopeOffice
-		return sq.Eq{"o.office_id": *actor.OfficeID}
-	default:
-		req.effectiveScope = types.DashboardScopeOwn
-		return sq.Or{
-			sq.Eq{"o.user_id": actor.ID},
-			sq.Eq{"o.executor_id": actor.ID},
+	}
+
+	builder := authz.NewScopeQueryBuilder("o")
+	for _, scope := range []struct{ permission, effective string }{
+		{authz.ScopeDepartment, types.DashboardScopeDepartment},
+		{authz.ScopeBranch, types.DashboardScopeBranch},
+		{authz.ScopeOtdel, types.DashboardScopeOtdel},
+		{authz.ScopeOffice, types.DashboardScopeOffice},
+	} {
+		if !authContext.HasPermission(scope.permission) {
+			continue
+		}
+		if condition := builder.ScopeCondition(scope.permission, actor); condition != nil {
+			req.effectiveScope = scope.effective
+			return condition
 		}
 	}
+
+	req.effectiveScope = types.DashboardScopeOwn
+	return builder.ScopeCondition(authz.ScopeOwn, actor)
+}
+
+// dashboardScopePermissions — право, которое даёт каждую область, кроме own и all.
+var dashboardScopePermissions = map[string]string{
+	types.DashboardScopeDepartment: authz.ScopeDepartment,
+	types.DashboardScopeBranch:     authz.ScopeBranch,
+	types.DashboardScopeOtdel:      authz.ScopeOtdel,
+	types.DashboardScopeOffice:     authz.ScopeOffice,
+}
+
+// narrowDashboardSecurity сужает область до запрошенной в filter.Scope. Своё подразделение можно
+// выбрать при праве на эту область или на все заявки, свои заявки — всегда; шире доступной
+// области запросить нельзя. Подписи кеша строятся по той же effectiveScope, так что кеш не смешивается.
+func narrowDashboardSecurity(authContext *authz.Context, actor *entities.User, req *dashboardRequest, widest sq.Sqlizer) (sq.Sqlizer, error) {
+	requested := req.filter.Scope
+	if requested == "" || requested == req.effectiveScope {
+		return widest, nil
+	}
+
+	builder := authz.NewScopeQueryBuilder("o")
+	if requested == types.DashboardScopeOwn {
+		req.effectiveScope = types.DashboardScopeOwn
+		return builder.ScopeCondition(authz.ScopeOwn, actor), nil
+	}
+	if requested == types.DashboardScopeAll {
+		return nil, apperrors.ErrForbidden
+	}
+	permission, ok := dashboardScopePermissions[requested]
+	if !ok {
+		return nil, apperrors.NewBadRequestError("scope: допустимы own, office, otdel, branch, department, all")
+	}
+	if req.effectiveScope != types.DashboardScopeAll && !authContext.HasPermission(permission) {
+		return nil, apperrors.ErrForbidden
+	}
+	condition := builder.ScopeCondition(permission, actor)
+	if condition == nil {
+		return nil, apperrors.NewBadRequestError("У пользователя не указано подразделение для этой области")
+	}
+	req.effectiveScope = requested
+	return condition, nil
 }
 
 func normalizeDashboardFilter(filter dto.DashboardFilterDTO) dto.DashboardFilterDTO {
@@ -458,6 +539,7 @@ func normalizeDashboardFilter(filter dto.DashboardFilterDTO) dto.DashboardFilter
 		filter.Period = types.DashboardPeriodMonth
 	}
 
+	filter.Scope = strings.TrimSpace(strings.ToLower(filter.Scope))
 	filter.Granularity = strings.TrimSpace(strings.ToLower(filter.Granularity))
 	if filter.Granularity == "" {
 		filter.Granularity = types.DashboardGranularityDay
@@ -556,38 +638,54 @@ func buildDashboardMeta(req dashboardRequest) *types.DashboardMeta {
 	}
 }
 
-func buildDashboardCacheKey(userID uint64, actor *entities.User, permissionsMap map[string]bool, req dashboardRequest, version string) (string, error) {
-	type actorScope struct {
-		DepartmentID *uint64 `json:"department_id,omitempty"`
-		BranchID     *uint64 `json:"branch_id,omitempty"`
-		OtdelID      *uint64 `json:"otdel_id,omitempty"`
-		OfficeID     *uint64 `json:"office_id,omitempty"`
+// dashboardScopeSignature описывает, какие заявки видит пользователь. Пользователи с одинаковой
+// подписью видят одни и те же данные и делят блоки в кеше. KPI содержат личные показатели,
+// поэтому для них в подпись всегда входит пользователь.
+func dashboardScopeSignature(widget string, userID uint64, actor *entities.User, effectiveScope string) string {
+	idOf := func(id *uint64) uint64 {
+		if id == nil {
+			return 0
+		}
+		return *id
+	}
+
+	var signature string
+	switch effectiveScope {
+	case types.DashboardScopeAll:
+		signature = effectiveScope
+	case types.DashboardScopeDepartment:
+		signature = fmt.Sprintf("%s:%d", effectiveScope, idOf(actor.DepartmentID))
+	case types.DashboardScopeBranch:
+		signature = fmt.Sprintf("%s:%d", effectiveScope, idOf(actor.BranchID))
+	case types.DashboardScopeOtdel:
+		signature = fmt.Sprintf("%s:%d", effectiveScope, idOf(actor.OtdelID))
+	case types.DashboardScopeOffice:
+		signature = fmt.Sprintf("%s:%d", effectiveScope, idOf(actor.OfficeID))
+	default:
+		return fmt.Sprintf("%s:%d", types.DashboardScopeOwn, userID)
+	}
+	if widget == dashboardWidgetKPIs {
+		signature += fmt.Sprintf("|user:%d", userID)
 	}
+	return signature
+}
 
-	perms := make([]string, 0, len(permissionsMap))
-	for perm, allowed := range permissionsMap {
-		if allowed {
-			perms = append(perms, perm)
-		}
+func buildDashboardBlockCacheKey(widget string, userID uint64, actor *entities.User, req dashboardRequest, version string) (string, error) {
+	// Скользящие периоды заканчиваются «сейчас»: точное время в ключ не берём,
+	// иначе кеш не переживёт и секунды. Свежесть обеспечивают TTL и версии.
+	dateTo := "now"
+	if req.filter.Period == types.DashboardPeriodCustom || req.filter.DateTo != nil {
+		dateTo = req.query.Range.To.Format(time.RFC3339)
 	}
-	sort.Strings(perms)
 
 	payload := map[string]interface{}{
-		"version":     version,
-		"user_id":     userID,
-		"permissions": perms,
-		"scope": actorScope{
-			DepartmentID: actor.DepartmentID,
-			BranchID:     actor.BranchID,
-			OtdelID:      actor.OtdelID,
-			OfficeID:     actor.OfficeID,
-		},
-		"effective_scope": req.effectiveScope,
-		"period":          req.filter.Period,
-		"date_from":       req.query.Range.From.Format(time.RFC3339),
-		"date_to":         req.query.Range.To.Format(time.RFC3339),
-		"granularity":     req.query.Granularity,
-		"widgets":         sortedDashboardWidgets(req.widgets),
+		"widget":      widget,
+		"tenant":      actor.TenantID,
+		"scope":       dashboardScopeSignature(widget, userID, actor, req.effectiveScope),
+		"period":      req.filter.Period,
+		"date_from":   req.query.Range.From.Format(time.RFC3339),
+		"date_to":     dateTo,
+		"granularity": req.query.Granularity,
 	}
 
 	raw, err := json.Marshal(payload)
@@ -596,7 +694,18 @@ func buildDashboardCacheKey(userID uint64, actor *entities.User, permissionsMap
 	}
 
 	sum := sha256.Sum256(raw)
-	return "dashboard:stats:" + version + ":" + hex.EncodeToString(sum[:]), nil
+	return "dashboard:block:" + widget + ":" + version + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+func dashboardBlockTTL(widget string) time.Duration {
+	switch widget {
+	case dashboardWidgetLastActivity:
+		return dashboardActivityCacheTTL
+	case dashboardWidgetAlerts:
+		return dashboardAlertsCacheTTL
+	default:
+		return dashboardCacheTTL
+	}
 }
 
 func sortedDashboardWidgets(widgets map[string]struct{}) []string {
@@ -1,10 +1,20 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
 	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
 )
 
@@ -106,3 +116,176 @@ func TestFillMissingBuckets_DayGranularity(t *testing.T) {
 		t.Fatalf("unexpected bucket values: %+v", result)
 	}
 }
+
+func TestBuildDashboardBlockCacheKey_SharedByScopeSignature(t *testing.T) {
+	departmentID := uint64(4)
+	first := &entities.User{ID: 1, DepartmentID: &departmentID}
+	second := &entities.User{ID: 2, DepartmentID: &departmentID}
+
+	now := time.Date(2026, 4, 13, 10, 0, 0, 0, time.UTC)
+	req := dashboardRequest{
+		filter:         dto.DashboardFilterDTO{Period: types.DashboardPeriod7Days},
+		query:          types.DashboardQuery{Range: types.DashboardDateRange{From: now.AddDate(0, 0, -6), To: now}},
+		effectiveScope: types.DashboardScopeDepartment,
+	}
+	later := req
+	later.query.Range.To = now.Add(42 * time.Second)
+
+	key := func(widget string, userID uint64, actor *entities.User, r dashboardRequest) string {
+		k, err := buildDashboardBlockCacheKey(widget, userID, actor, r, "v1")
+		if err != nil {
+			t.Fatalf("buildDashboardBlockCacheKey returned error: %v", err)
+		}
+		return k
+	}
+
+	if key(dashboardWidgetCountByStatus, 1, first, req) != key(dashboardWidgetCountByStatus, 2, second, later) {
+		t.Fatal("expected users with the same scope to share a block for a rolling period")
+	}
+	if key(dashboardWidgetKPIs, 1, first, req) == key(dashboardWidgetKPIs, 2, second, req) {
+		t.Fatal("expected personal KPI blocks to differ between users")
+	}
+
+	own := req
+	own.effectiveScope = types.DashboardScopeOwn
+	if key(dashboardWidgetCountByStatus, 1, first, own) == key(dashboardWidgetCountByStatus, 2, second, own) {
+		t.Fatal("expected own-scope blocks to differ between users")
+	}
+}
+
+func TestSortDashboardExecutorStats(t *testing.T) {
+	stats := []types.DashboardExecutorStat{
+		{ExecutorID: 1, ClosedCount: 10, AvgResolutionSeconds: 7200, SLAEligible: 10, SLAOnTime: 5, ReopenedCount: 2, OpenCount: 3},
+		{ExecutorID: 2, ClosedCount: 4, AvgResolutionSeconds: 1800, SLAEligible: 4, SLAOnTime: 4, OpenCount: 9},
+		{ExecutorID: 3, OpenCount: 1},
+	}
+	for i := range stats {
+		decorateDashboardExecutorStat(&stats[i])
+	}
+
+	if stats[0].SLAPercent != 50 || stats[0].ReopenRate != 20 {
+		t.Fatalf("unexpected decorated stat: %+v", stats[0])
+	}
+	if stats[2].SLAPercent != 0 || stats[2].ReopenRate != 0 {
+		t.Fatalf("expected zero rates without closed orders, got %+v", stats[2])
+	}
+
+	order := func() []uint64 {
+		ids := make([]uint64, 0, len(stats))
+		for _, stat := range stats {
+			ids = append(ids, stat.ExecutorID)
+		}
+		return ids
+	}
+
+	cases := map[string][]uint64{
+		DashboardExecutorSortClosed:     {1, 2, 3},
+		DashboardExecutorSortResolution: {2, 1, 3},
+		DashboardExecutorSortSLA:        {2, 1, 3},
+		DashboardExecutorSortLoad:       {2, 1, 3},
+	}
+	for sortBy, expected := range cases {
+		sortDashboardExecutorStats(stats, sortBy)
+		got := order()
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("sort %s: expected %v, got %v", sortBy, expected, got)
+			}
+		}
+	}
+
+	if _, err := normalizeDashboardExecutorSort("unknown"); err == nil {
+		t.Fatalf("expected error for unknown sort")
+	}
+}
+
+type agingDashboardRepoStub struct {
+	repositories.DashboardRepositoryInterface
+	condition sq.Sqlizer
+}
+
+func (s *agingDashboardRepoStub) GetBacklogAging(_ context.Context, securityCondition sq.Sqlizer) (*types.DashboardBacklogAging, error) {
+	s.condition = securityCondition
+	return &types.DashboardBacklogAging{Overall: types.DashboardAgingBuckets{LessThanDay: 2, Total: 2}}, nil
+}
+
+func TestGetBacklogAging_AppliesOwnScope(t *testing.T) {
+	repo := &agingDashboardRepoStub{}
+	service := NewDashboardService(repo, &replayUserRepoStub{}, nil, nil, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(5))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.DashboardView: true})
+
+	result, err := service.GetBacklogAging(ctx)
+	if err != nil {
+		t.Fatalf("aging failed: %v", err)
+	}
+	if result.EffectiveScope != types.DashboardScopeOwn || result.Overall.Total != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Departments == nil || result.Branches == nil {
+		t.Fatalf("expected empty group slices, got nil")
+	}
+
+	sqlPart, args, err := repo.condition.ToSql()
+	if err != nil || len(args) != 2 || args[0] != uint64(5) {
+		t.Fatalf("expected own-scope condition, got %q %v %v", sqlPart, args, err)
+	}
+
+	if _, err := service.GetBacklogAging(context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{})); err == nil {
+		t.Fatalf("expected forbidden without dashboard:view")
+	}
+}
+
+func TestNarrowDashboardSecurity(t *testing.T) {
+	departmentID, branchID := uint64(3), uint64(5)
+	actor := &entities.User{ID: 7, DepartmentID: &departmentID, BranchID: &branchID}
+	authCtx := authz.Context{
+		Actor:       actor,
+		Permissions: map[string]bool{authz.ScopeDepartment: true, authz.ScopeOwn: true},
+	}
+	narrow := func(scope string) (dashboardRequest, sq.Sqlizer, error) {
+		req := dashboardRequest{filter: dto.DashboardFilterDTO{Scope: scope}}
+		condition, err := narrowDashboardSecurity(&authCtx, actor, &req, resolveDashboardSecurity(&authCtx, actor, &req))
+		return req, condition, err
+	}
+
+	req, condition, err := narrow("")
+	if err != nil || req.effectiveScope != types.DashboardScopeDepartment {
+		t.Fatalf("without scope the widest area is expected, got %q %v", req.effectiveScope, err)
+	}
+	if sqlPart, _, _ := condition.ToSql(); sqlPart != "o.department_id = ?" {
+		t.Fatalf("unexpected condition: %s", sqlPart)
+	}
+
+	req, condition, err = narrow(types.DashboardScopeOwn)
+	if err != nil || req.effectiveScope != types.DashboardScopeOwn {
+		t.Fatalf("own must always be allowed, got %q %v", req.effectiveScope, err)
+	}
+	if _, args, _ := condition.ToSql(); len(args) != 2 || args[0] != uint64(7) {
+		t.Fatalf("expected own-scope condition, got %v", args)
+	}
+
+	if _, _, err := narrow(types.DashboardScopeBranch); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("branch without scope:branch must be forbidden, got %v", err)
+	}
+	if _, _, err := narrow(types.DashboardScopeAll); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("all must not widen the area, got %v", err)
+	}
+	if _, _, err := narrow("galaxy"); err == nil || errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("unknown scope must be a bad request, got %v", err)
+	}
+
+	// С полным доступом можно выбрать своё подразделение, даже без отдельного права на эту область.
+	authCtx.Permissions = map[string]bool{authz.ScopeAll: true}
+	req, condition, err = narrow(types.DashboardScopeBranch)
+	if err != nil || req.effectiveScope != types.DashboardScopeBranch {
+		t.Fatalf("full access must narrow to branch, got %q %v", req.effectiveScope, err)
+	}
+	if sqlPart, args, _ := condition.ToSql(); sqlPart != "o.branch_id = ?" || args[0] != branchID {
+		t.Fatalf("unexpected branch condition: %s %v", sqlPart, args)
+	}
+	if _, _, err := narrow(types.DashboardScopeOffice); err == nil {
+		t.Fatalf("office scope without an office must fail")
+	}
+}
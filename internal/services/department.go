@@ -62,7 +62,7 @@ func (s *DepartmentService) buildAuthzContext(ctx context.Context) (*authz.Conte
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
 
 func departmentEntityToDTO(entity *entities.Department) *dto.DepartmentDTO {
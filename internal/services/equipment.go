@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"request-system/internal/authz"
 	"request-system/internal/dto"
 	"request-system/internal/entities"
 	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	pkgconstants "request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/qrcode"
 	"request-system/pkg/types"
 	"request-system/pkg/utils"
 
@@ -24,23 +29,115 @@ type EquipmentServiceInterface interface {
 	CreateEquipment(ctx context.Context, dto dto.CreateEquipmentDTO) (*dto.EquipmentDTO, error)
 	UpdateEquipment(ctx context.Context, id uint64, dto dto.UpdateEquipmentDTO) (*dto.EquipmentDTO, error)
 	DeleteEquipment(ctx context.Context, id uint64) error
+	FindEquipmentByCode(ctx context.Context, code string) (*dto.EquipmentScanDTO, error)
+	GetEquipmentQRCode(ctx context.Context, id uint64, target, format string, scale int) (*EquipmentQRCode, error)
 }
 
 type EquipmentService struct {
-	eqRepository   repositories.EquipmentRepositoryInterface
-	userRepository repositories.UserRepositoryInterface
-	logger         *zap.Logger
+	eqRepository    repositories.EquipmentRepositoryInterface
+	userRepository  repositories.UserRepositoryInterface
+	frontendBaseURL string
+	botUsername     string
+	logger          *zap.Logger
+}
+
+// Форматы и цели QR-кода наклейки.
+const (
+	EquipmentQRFormatPNG = "png"
+	EquipmentQRFormatSVG = "svg"
+
+	EquipmentQRTargetWeb      = "web"
+	EquipmentQRTargetTelegram = "telegram"
+
+	// EquipmentStartPrefix — префикс параметра /start, по которому бот узнаёт отсканированную наклейку.
+	EquipmentStartPrefix = "eq_"
+
+	equipmentQRDefaultScale = 8
+	equipmentQRMaxScale     = 40
+)
+
+// EquipmentQRCode — готовая картинка наклейки.
+type EquipmentQRCode struct {
+	Content     []byte
+	ContentType string
+	FileName    string
+	Link        string
+}
+
+const equipmentDateLayout = "2006-01-02"
+
+// equipmentStateTransitions — разрешённые переходы состояния оборудования.
+// Списание окончательное: из written_off выйти нельзя.
+var equipmentStateTransitions = map[string][]string{
+	entities.EquipmentStateInService: {entities.EquipmentStateRepair, entities.EquipmentStateWrittenOff},
+	entities.EquipmentStateRepair:    {entities.EquipmentStateInService, entities.EquipmentStateWrittenOff},
+}
+
+func validateEquipmentStateTransition(from, to string) error {
+	if from == to {
+		return nil
+	}
+	for _, allowed := range equipmentStateTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return apperrors.NewHttpError(
+		http.StatusConflict,
+		fmt.Sprintf("Недопустимая смена состояния оборудования: %s → %s", from, to),
+		nil,
+		map[string]interface{}{"from": from, "to": to},
+	)
+}
+
+// parseEquipmentDate разбирает дату "YYYY-MM-DD"; пустая строка означает сброс.
+func parseEquipmentDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(equipmentDateLayout, value)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Неверный формат даты, ожидается ГГГГ-ММ-ДД", err, nil)
+	}
+	return &t, nil
+}
+
+func formatEquipmentDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(equipmentDateLayout)
+	return &s
+}
+
+func emptyToNil(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// checkAssignee проверяет, что пользователь, за которым закрепляется оборудование, существует.
+func (s *EquipmentService) checkAssignee(ctx context.Context, userID uint64) error {
+	if _, err := s.userRepository.FindUserByID(ctx, userID); err != nil {
+		return apperrors.NewHttpError(http.StatusBadRequest, "Пользователь для закрепления оборудования не найден", err, nil)
+	}
+	return nil
 }
 
 func NewEquipmentService(
 	eqRepo repositories.EquipmentRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
+	frontendCfg config.FrontendConfig,
+	tgCfg config.TelegramConfig,
 	logger *zap.Logger,
 ) EquipmentServiceInterface {
 	return &EquipmentService{
-		eqRepository:   eqRepo,
-		userRepository: userRepo,
-		logger:         logger,
+		eqRepository:    eqRepo,
+		userRepository:  userRepo,
+		frontendBaseURL: strings.TrimRight(frontendCfg.BaseURL, "/"),
+		botUsername:     strings.TrimPrefix(strings.TrimSpace(tgCfg.BotUsername), "@"),
+		logger:          logger,
 	}
 }
 
@@ -58,7 +155,7 @@ func (s *EquipmentService) buildAuthzContext(ctx context.Context) (*authz.Contex
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
 
 // "Переводчик" из Entity в DTO. Справляется со всеми связанными сущностями.
@@ -76,12 +173,27 @@ func eqEntityToDTO(entity *entities.Equipment) *dto.EquipmentDTO {
 	}
 
 	dtoResponse := &dto.EquipmentDTO{
-		ID:        entity.ID,
-		Name:      entity.Name,
-		Address:   entity.Address,
-		StatusID:  entity.StatusID,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:            entity.ID,
+		Name:          entity.Name,
+		Address:       entity.Address,
+		Code:          entity.Code,
+		StatusID:      entity.StatusID,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		SerialNumber:  entity.SerialNumber,
+		PurchaseDate:  formatEquipmentDate(entity.PurchaseDate),
+		WarrantyUntil: formatEquipmentDate(entity.WarrantyUntil),
+		State:         entity.State,
+	}
+	if entity.WarrantyUntil != nil {
+		dtoResponse.UnderWarranty = !time.Now().After(entity.WarrantyUntil.AddDate(0, 0, 1))
+	}
+	if entity.StateChangedAt != nil {
+		changedAt := entity.StateChangedAt.Format("2006-01-02 15:04:05")
+		dtoResponse.StateChangedAt = &changedAt
+	}
+	if entity.AssignedUser != nil {
+		dtoResponse.AssignedUser = &dto.ShortUserDTO{ID: entity.AssignedUser.ID, Fio: entity.AssignedUser.Fio}
 	}
 
 	// >>> ВОТ ИСПРАВЛЕНИЕ: Мы не пишем 'dto.' перед типами из того же пакета <<<
@@ -144,6 +256,11 @@ func (s *EquipmentService) GetEquipments(ctx context.Context, filter types.Filte
 			OfficeID:        eq.OfficeID,
 			EquipmentTypeID: eq.EquipmentTypeID,
 			StatusID:        eq.StatusID,
+			Code:            eq.Code,
+			SerialNumber:    eq.SerialNumber,
+			AssignedUserID:  eq.AssignedUserID,
+			WarrantyUntil:   formatEquipmentDate(eq.WarrantyUntil),
+			State:           eq.State,
 			CreatedAt:       createdAt,
 			UpdatedAt:       updatedAt,
 		})
@@ -164,7 +281,18 @@ func (s *EquipmentService) FindEquipment(ctx context.Context, id uint64) (*dto.E
 	if err != nil {
 		return nil, err
 	}
-	return eqEntityToDTO(entity), nil
+	res := eqEntityToDTO(entity)
+
+	// История заявок видна только тем, кому вообще разрешено смотреть заявки.
+	if authz.CanDo(authz.OrdersView, *authCtx) {
+		orders, err := s.eqRepository.FindOrdersByEquipmentID(ctx, id)
+		if err != nil {
+			s.logger.Error("Не удалось получить заявки по оборудованию", zap.Uint64("equipmentID", id), zap.Error(err))
+			return nil, err
+		}
+		res.Orders = orders
+	}
+	return res, nil
 }
 
 func (s *EquipmentService) CreateEquipment(ctx context.Context, dto dto.CreateEquipmentDTO) (*dto.EquipmentDTO, error) {
@@ -184,11 +312,38 @@ func (s *EquipmentService) CreateEquipment(ctx context.Context, dto dto.CreateEq
 		OfficeID:        dto.OfficeID,
 		StatusID:        dto.StatusID,
 		EquipmentTypeID: dto.EquipmentTypeID,
+		AssignedUserID:  dto.AssignedUserID,
+		State:           entities.EquipmentStateInService,
 		BaseEntity: types.BaseEntity{
 			CreatedAt: &now,
 			UpdatedAt: &now,
 		},
 	}
+	if dto.SerialNumber != nil {
+		entity.SerialNumber = emptyToNil(*dto.SerialNumber)
+	}
+	if dto.PurchaseDate != nil {
+		if entity.PurchaseDate, err = parseEquipmentDate(*dto.PurchaseDate); err != nil {
+			return nil, err
+		}
+	}
+	if dto.WarrantyUntil != nil {
+		if entity.WarrantyUntil, err = parseEquipmentDate(*dto.WarrantyUntil); err != nil {
+			return nil, err
+		}
+	}
+	if dto.State != nil && *dto.State != entities.EquipmentStateInService {
+		entity.State = *dto.State
+		entity.StateChangedAt = &now
+	}
+	if entity.State == entities.EquipmentStateWrittenOff {
+		entity.AssignedUserID = nil
+	}
+	if entity.AssignedUserID != nil {
+		if err := s.checkAssignee(ctx, *entity.AssignedUserID); err != nil {
+			return nil, err
+		}
+	}
 
 	createdEntity, err := s.eqRepository.CreateEquipment(ctx, entity)
 	if err != nil {
@@ -231,8 +386,46 @@ func (s *EquipmentService) UpdateEquipment(ctx context.Context, id uint64, dto d
 	if dto.EquipmentTypeID != nil {
 		existingEntity.EquipmentTypeID = *dto.EquipmentTypeID
 	}
+	if dto.SerialNumber != nil {
+		existingEntity.SerialNumber = emptyToNil(*dto.SerialNumber)
+	}
+	if dto.PurchaseDate != nil {
+		if existingEntity.PurchaseDate, err = parseEquipmentDate(*dto.PurchaseDate); err != nil {
+			return nil, err
+		}
+	}
+	if dto.WarrantyUntil != nil {
+		if existingEntity.WarrantyUntil, err = parseEquipmentDate(*dto.WarrantyUntil); err != nil {
+			return nil, err
+		}
+	}
 
 	now := time.Now()
+	if dto.State != nil && *dto.State != existingEntity.State {
+		if err := validateEquipmentStateTransition(existingEntity.State, *dto.State); err != nil {
+			return nil, err
+		}
+		existingEntity.State = *dto.State
+		existingEntity.StateChangedAt = &now
+	}
+	if dto.AssignedUserID != nil {
+		if *dto.AssignedUserID == 0 {
+			existingEntity.AssignedUserID = nil
+		} else {
+			if err := s.checkAssignee(ctx, *dto.AssignedUserID); err != nil {
+				return nil, err
+			}
+			existingEntity.AssignedUserID = dto.AssignedUserID
+		}
+	}
+	// Списанное оборудование ни за кем не закрепляется.
+	if existingEntity.State == entities.EquipmentStateWrittenOff {
+		if dto.AssignedUserID != nil && *dto.AssignedUserID != 0 {
+			return nil, apperrors.NewHttpError(http.StatusConflict, "Нельзя закрепить списанное оборудование за пользователем", nil, nil)
+		}
+		existingEntity.AssignedUserID = nil
+	}
+
 	existingEntity.UpdatedAt = &now
 
 	updatedEntity, err := s.eqRepository.UpdateEquipment(ctx, id, *existingEntity)
@@ -282,3 +475,119 @@ func (s *EquipmentService) DeleteEquipment(ctx context.Context, id uint64) error
 	// 5. Если все проверки пройдены - удаляем
 	return s.eqRepository.DeleteEquipment(ctx, id)
 }
+
+// FindEquipmentByCode — сканирование наклейки: карточка оборудования, его открытые заявки
+// и черновик новой заявки. Доступно и тем, кто может только создавать заявки.
+func (s *EquipmentService) FindEquipmentByCode(ctx context.Context, code string) (*dto.EquipmentScanDTO, error) {
+	authCtx, err := s.buildAuthzContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.EquipmentsView, *authCtx) && !authz.CanDo(authz.OrdersCreate, *authCtx) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, apperrors.ErrNotFound
+	}
+	entity, err := s.eqRepository.FindEquipmentByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	scanURL, _ := s.equipmentDeepLink(entity.Code, EquipmentQRTargetWeb)
+	res := &dto.EquipmentScanDTO{Equipment: eqEntityToDTO(entity), OpenOrders: []dto.EquipmentOrderDTO{}, ScanURL: scanURL}
+
+	if authz.CanDo(authz.OrdersView, *authCtx) {
+		orders, err := s.eqRepository.FindOrdersByEquipmentID(ctx, entity.ID)
+		if err != nil {
+			s.logger.Error("Не удалось получить заявки по оборудованию", zap.Uint64("equipmentID", entity.ID), zap.Error(err))
+			return nil, err
+		}
+		for _, o := range orders {
+			if !pkgconstants.IsFinalStatus(o.StatusCode) {
+				res.OpenOrders = append(res.OpenOrders, o)
+			}
+		}
+	}
+
+	if entity.State != entities.EquipmentStateWrittenOff {
+		equipmentID, equipmentTypeID := entity.ID, entity.EquipmentTypeID
+		draft := &dto.CreateOrderDTO{
+			EquipmentID:     &equipmentID,
+			EquipmentTypeID: &equipmentTypeID,
+			BranchID:        entity.BranchID,
+			OfficeID:        entity.OfficeID,
+		}
+		if entity.Address != "" {
+			address := entity.Address
+			draft.Address = &address
+		}
+		res.OrderDraft = draft
+	}
+	return res, nil
+}
+
+// equipmentDeepLink — что зашито в QR: страница сканирования на сайте или старт бота.
+func (s *EquipmentService) equipmentDeepLink(code, target string) (string, error) {
+	switch target {
+	case "", EquipmentQRTargetWeb:
+		return s.frontendBaseURL + "/equipment/scan/" + url.PathEscape(code), nil
+	case EquipmentQRTargetTelegram:
+		if s.botUsername == "" {
+			return "", apperrors.NewHttpError(http.StatusBadRequest, "Telegram-бот не настроен (TELEGRAM_BOT_USERNAME)", nil, nil)
+		}
+		return fmt.Sprintf("https://t.me/%s?start=%s%s", s.botUsername, EquipmentStartPrefix, url.QueryEscape(code)), nil
+	default:
+		return "", apperrors.NewHttpError(http.StatusBadRequest, "Параметр target должен быть web или telegram", nil, nil)
+	}
+}
+
+// GetEquipmentQRCode рисует наклейку с deep link на оборудование в PNG или SVG.
+func (s *EquipmentService) GetEquipmentQRCode(ctx context.Context, id uint64, target, format string, scale int) (*EquipmentQRCode, error) {
+	authCtx, err := s.buildAuthzContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.EquipmentsView, *authCtx) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	entity, err := s.eqRepository.FindEquipment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.equipmentDeepLink(entity.Code, target)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := qrcode.Encode([]byte(link), qrcode.LevelM)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось построить QR-код", err, nil)
+	}
+
+	result := &EquipmentQRCode{Link: link}
+	switch format {
+	case "", EquipmentQRFormatPNG:
+		if scale <= 0 {
+			scale = equipmentQRDefaultScale
+		}
+		if scale > equipmentQRMaxScale {
+			scale = equipmentQRMaxScale
+		}
+		if result.Content, err = code.PNG(scale); err != nil {
+			return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось построить QR-код", err, nil)
+		}
+		result.ContentType = "image/png"
+		result.FileName = fmt.Sprintf("equipment-%s.png", entity.Code)
+	case EquipmentQRFormatSVG:
+		result.Content = []byte(code.SVG())
+		result.ContentType = "image/svg+xml"
+		result.FileName = fmt.Sprintf("equipment-%s.svg", entity.Code)
+	default:
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Параметр format должен быть png или svg", nil, nil)
+	}
+	return result, nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+)
+
+// EquipmentImportRow — строка CSV-импорта оборудования. Branch и Office — externalId или название.
+type EquipmentImportRow struct {
+	Row     int
+	Name    string
+	Address string
+	Branch  string
+	Office  string
+	Type    string
+	Status  string
+}
+
+type EquipmentImportResult struct {
+	Created int
+	Updated int
+	Errors  []dto.ImportRowErrorDTO
+	Changes []dto.ImportChangeDTO
+}
+
+type importRef struct {
+	ID         uint64
+	Name       string
+	ExternalID string
+}
+
+// ImportRows сохраняет оборудование из CSV по имени, как и импорт из Excel, но без удаления
+// отсутствующих в файле записей. Каждая строка идёт в своей точке сохранения: ошибочная попадает
+// в Errors, остальные сохраняются. С dryRun транзакция в конце откатывается.
+func (s *EquipImportService) ImportRows(ctx context.Context, rows []EquipmentImportRow, dryRun bool) (*EquipmentImportResult, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			s.logger.Warn("Не удалось откатить транзакцию импорта оборудования", zap.Error(rollbackErr))
+		}
+	}()
+
+	branches, err := s.loadRefsTx(ctx, tx, "branches")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки филиалов: %w", err)
+	}
+	offices, err := s.loadRefsTx(ctx, tx, "offices")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки офисов: %w", err)
+	}
+	types, err := s.loadNamedIDsTx(ctx, tx, "SELECT id, name FROM equipment_types")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки типов оборудования: %w", err)
+	}
+	statuses, err := s.loadNamedIDsTx(ctx, tx, "SELECT id, code FROM statuses WHERE code IS NOT NULL")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки статусов: %w", err)
+	}
+	activeStatusID, ok := statuses["active"]
+	if !ok {
+		return nil, errors.New("статус ACTIVE не найден")
+	}
+
+	result := &EquipmentImportResult{Errors: []dto.ImportRowErrorDTO{}}
+	rowError := func(row EquipmentImportRow, field, reason string) {
+		result.Errors = append(result.Errors, dto.ImportRowErrorDTO{Row: row.Row, Key: row.Name, Field: field, Reason: reason})
+	}
+
+	const upsertQuery = `
+		INSERT INTO equipments (name, address, branch_id, office_id, status_id, equipment_type_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (name)
+		DO UPDATE SET
+			address = COALESCE(NULLIF(EXCLUDED.address, '-'), equipments.address),
+			equipment_type_id = EXCLUDED.equipment_type_id,
+			updated_at = NOW(),
+			branch_id = CASE WHEN $3 IS NOT NULL THEN $3 ELSE equipments.branch_id END,
+			office_id = CASE WHEN $4 IS NOT NULL THEN $4 ELSE equipments.office_id END,
+			status_id = CASE WHEN $7 THEN EXCLUDED.status_id ELSE equipments.status_id END
+		RETURNING (xmax = 0) AS is_insert`
+
+	for _, row := range rows {
+		typeID, ok := types[strings.ToLower(row.Type)]
+		if !ok {
+			rowError(row, "type", fmt.Sprintf("тип оборудования '%s' не найден", row.Type))
+			continue
+		}
+		statusID := activeStatusID
+		if row.Status != "" {
+			if statusID, ok = statuses[strings.ToLower(row.Status)]; !ok {
+				rowError(row, "status", fmt.Sprintf("статус '%s' не найден", row.Status))
+				continue
+			}
+		}
+		var branchID, officeID any
+		if row.Branch != "" {
+			id := s.findRef(row.Branch, branches)
+			if id == 0 {
+				rowError(row, "branch", fmt.Sprintf("филиал '%s' не найден", row.Branch))
+				continue
+			}
+			branchID = id
+		}
+		if row.Office != "" {
+			id := s.findRef(row.Office, offices)
+			if id == 0 {
+				rowError(row, "office", fmt.Sprintf("офис '%s' не найден", row.Office))
+				continue
+			}
+			officeID = id
+		}
+		address := row.Address
+		if address == "" {
+			address = "-"
+		}
+
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var isInsert bool
+		if err := savepoint.QueryRow(ctx, upsertQuery, row.Name, address, branchID, officeID, statusID, typeID, row.Status != "").Scan(&isInsert); err != nil {
+			_ = savepoint.Rollback(ctx)
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			rowError(row, "", err.Error())
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, err
+		}
+
+		action := "update"
+		if isInsert {
+			action = "create"
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		if dryRun {
+			result.Changes = append(result.Changes, dto.ImportChangeDTO{Row: row.Row, Key: row.Name, Action: action})
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	s.logger.Info("Импорт оборудования из CSV завершен", zap.Int("created", result.Created), zap.Int("updated", result.Updated), zap.Int("failed", len(result.Errors)))
+	return result, nil
+}
+
+// findRef ищет запись сначала по externalId, затем по названию — так же нестрого, как импорт из Excel.
+func (s *EquipImportService) findRef(value string, refs []importRef) uint64 {
+	for _, ref := range refs {
+		if ref.ExternalID != "" && ref.ExternalID == value {
+			return ref.ID
+		}
+	}
+	named := make([]dbEnt, 0, len(refs))
+	for _, ref := range refs {
+		named = append(named, dbEnt{ID: ref.ID, Name: ref.Name})
+	}
+	return s.fuzzyFind(value, named)
+}
+
+func (s *EquipImportService) loadRefsTx(ctx context.Context, tx pgx.Tx, table string) ([]importRef, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf("SELECT id, name, COALESCE(external_id, '') FROM %s", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []importRef
+	for rows.Next() {
+		var ref importRef
+		if err := rows.Scan(&ref.ID, &ref.Name, &ref.ExternalID); err != nil {
+			return nil, err
+		}
+		res = append(res, ref)
+	}
+	return res, rows.Err()
+}
+
+// loadNamedIDsTx — id по имени в нижнем регистре.
+func (s *EquipImportService) loadNamedIDsTx(ctx context.Context, tx pgx.Tx, query string) (map[string]uint64, error) {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]uint64)
+	for rows.Next() {
+		var e dbEnt
+		if err := rows.Scan(&e.ID, &e.Name); err != nil {
+			return nil, err
+		}
+		res[strings.ToLower(strings.TrimSpace(e.Name))] = e.ID
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"request-system/internal/entities"
+	"request-system/pkg/qrcode"
+)
+
+func TestValidateEquipmentStateTransition(t *testing.T) {
+	allowed := [][2]string{
+		{entities.EquipmentStateInService, entities.EquipmentStateRepair},
+		{entities.EquipmentStateRepair, entities.EquipmentStateInService},
+		{entities.EquipmentStateInService, entities.EquipmentStateWrittenOff},
+		{entities.EquipmentStateRepair, entities.EquipmentStateWrittenOff},
+		{entities.EquipmentStateWrittenOff, entities.EquipmentStateWrittenOff},
+	}
+	for _, tr := range allowed {
+		if err := validateEquipmentStateTransition(tr[0], tr[1]); err != nil {
+			t.Fatalf("expected %s -> %s to be allowed, got %v", tr[0], tr[1], err)
+		}
+	}
+
+	for _, to := range []string{entities.EquipmentStateInService, entities.EquipmentStateRepair} {
+		if err := validateEquipmentStateTransition(entities.EquipmentStateWrittenOff, to); err == nil {
+			t.Fatalf("expected written_off -> %s to be rejected", to)
+		}
+	}
+}
+
+func TestParseEquipmentDate(t *testing.T) {
+	if d, err := parseEquipmentDate(""); err != nil || d != nil {
+		t.Fatalf("expected empty string to clear the date, got %v, %v", d, err)
+	}
+	d, err := parseEquipmentDate("2026-03-01")
+	if err != nil || d == nil || *formatEquipmentDate(d) != "2026-03-01" {
+		t.Fatalf("unexpected parse result %v, %v", d, err)
+	}
+	if _, err := parseEquipmentDate("01.03.2026"); err == nil {
+		t.Fatal("expected invalid format to be rejected")
+	}
+}
+
+func TestEquipmentDeepLink(t *testing.T) {
+	s := &EquipmentService{frontendBaseURL: "https://helpdesk.example", botUsername: "helpdesk_bot"}
+
+	if link, err := s.equipmentDeepLink("A1B2C3", ""); err != nil || link != "https://helpdesk.example/equipment/scan/A1B2C3" {
+		t.Fatalf("unexpected web link %q, %v", link, err)
+	}
+	if link, err := s.equipmentDeepLink("A1B2C3", EquipmentQRTargetTelegram); err != nil || link != "https://t.me/helpdesk_bot?start=eq_A1B2C3" {
+		t.Fatalf("unexpected telegram link %q, %v", link, err)
+	}
+	if _, err := s.equipmentDeepLink("A1B2C3", "sms"); err == nil {
+		t.Fatal("expected unknown target to be rejected")
+	}
+
+	s.botUsername = ""
+	if _, err := s.equipmentDeepLink("A1B2C3", EquipmentQRTargetTelegram); err == nil {
+		t.Fatal("expected telegram target without bot username to be rejected")
+	}
+}
+
+func TestEquipmentQRCodeRendering(t *testing.T) {
+	code, err := qrcode.Encode([]byte("https://t.me/helpdesk_bot?start=eq_A1B2C3"), qrcode.LevelM)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// Поисковый узор в левом верхнем углу: тёмная рамка и светлый разделитель.
+	if !code.Black(0, 0) || !code.Black(6, 6) || code.Black(7, 7) || code.Black(1, 1) {
+		t.Fatal("finder pattern is not where it should be")
+	}
+
+	const scale = 4
+	raw, err := code.PNG(scale)
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	side := (code.Size + 2*qrcode.QuietZone) * scale
+	if b := img.Bounds(); b.Dx() != side || b.Dy() != side {
+		t.Fatalf("expected %dx%d image, got %v", side, side, b)
+	}
+
+	if svg := code.SVG(); !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "<path d=\"M") {
+		t.Fatalf("unexpected svg output: %.80s", svg)
+	}
+}
@@ -54,7 +54,7 @@ func (s *EquipmentTypeService) buildAuthzContext(ctx context.Context) (*authz.Co
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
 
 // etEntityToDTO переводит сущность EquipmentType в DTO
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/events"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/eventbus"
+)
+
+const (
+	eventOutboxBatchSize     = 100
+	eventOutboxMaxAttempts   = 5
+	eventOutboxPollInterval  = 5 * time.Second
+	eventOutboxCleanupPeriod = time.Hour
+	// Опубликованные события храним неделю — их можно переиграть при разборе инцидентов.
+	eventOutboxRetention = 7 * 24 * time.Hour
+)
+
+type EventOutboxDispatcherInterface interface {
+	Start(ctx context.Context)
+	PublishPending(ctx context.Context) (int, error)
+}
+
+// EventOutboxDispatcher публикует в eventbus события из event_outbox после коммита транзакции.
+type EventOutboxDispatcher struct {
+	repo      repositories.EventOutboxRepositoryInterface
+	txManager repositories.TxManagerInterface
+	userRepo  repositories.UserRepositoryInterface
+	bus       *eventbus.Bus
+	logger    *zap.Logger
+}
+
+func NewEventOutboxDispatcher(
+	repo repositories.EventOutboxRepositoryInterface,
+	txManager repositories.TxManagerInterface,
+	userRepo repositories.UserRepositoryInterface,
+	bus *eventbus.Bus,
+	logger *zap.Logger,
+) EventOutboxDispatcherInterface {
+	return &EventOutboxDispatcher{repo: repo, txManager: txManager, userRepo: userRepo, bus: bus, logger: logger}
+}
+
+func (d *EventOutboxDispatcher) Start(ctx context.Context) {
+	d.logger.Info("Диспетчер event_outbox запущен")
+	signals := d.repo.ListenForNewEvents(ctx)
+	ticker := time.NewTicker(eventOutboxPollInterval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(eventOutboxCleanupPeriod)
+	defer cleanup.Stop()
+
+	// События, оставшиеся после прошлого запуска.
+	d.drain(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Диспетчер event_outbox остановлен")
+			return
+		case <-cleanup.C:
+			if deleted, err := d.repo.DeletePublishedBefore(ctx, time.Now().Add(-eventOutboxRetention)); err != nil {
+				d.logger.Warn("Не удалось очистить старые события event_outbox", zap.Error(err))
+			} else if deleted > 0 {
+				d.logger.Info("Удалены старые события event_outbox", zap.Int64("count", deleted))
+			}
+			continue
+		case <-signals:
+		case <-ticker.C:
+		}
+		d.drain(ctx)
+	}
+}
+
+func (d *EventOutboxDispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := d.PublishPending(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.logger.Error("Ошибка публикации событий из event_outbox", zap.Error(err))
+			}
+			return
+		}
+		if published < eventOutboxBatchSize {
+			return
+		}
+	}
+}
+
+func (d *EventOutboxDispatcher) PublishPending(ctx context.Context) (int, error) {
+	var processed int
+	var toPublish []eventbus.Event
+
+	err := d.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		records, err := d.repo.LockUnpublishedInTx(ctx, tx, eventOutboxBatchSize, eventOutboxMaxAttempts)
+		if err != nil {
+			return err
+		}
+		processed = len(records)
+
+		ids := make([]uint64, 0, len(records))
+		for _, rec := range records {
+			event, err := d.decode(ctx, rec.EventName, rec.Payload)
+			if err != nil {
+				d.logger.Error("Не удалось восстановить событие из event_outbox",
+					zap.Uint64("outboxID", rec.ID), zap.String("event", rec.EventName), zap.Error(err))
+				if err := d.repo.MarkFailedInTx(ctx, tx, rec.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			ids = append(ids, rec.ID)
+			toPublish = append(toPublish, event)
+		}
+		return d.repo.MarkPublishedInTx(ctx, tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Публикуем только после успешной отметки, иначе при сбое событие ушло бы дважды.
+	for _, event := range toPublish {
+		d.bus.Publish(ctx, event)
+	}
+	return processed, nil
+}
+
+// decode восстанавливает событие eventbus из сохранённого payload.
+func (d *EventOutboxDispatcher) decode(ctx context.Context, name string, payload []byte) (eventbus.Event, error) {
+	switch name {
+	case events.OrderHistoryCreatedEvent{}.Name():
+		var record events.OrderHistoryCreatedRecord
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return nil, err
+		}
+		if record.Order == nil {
+			return nil, fmt.Errorf("в событии нет снимка заявки")
+		}
+		event := events.OrderHistoryCreatedEvent{HistoryItem: record.HistoryItem, Order: record.Order}
+		if record.ActorID > 0 {
+			actor, err := d.userRepo.FindUserByID(ctx, record.ActorID)
+			switch {
+			case err == nil:
+				event.Actor = actor
+			case errors.Is(err, pgx.ErrNoRows), errors.Is(err, apperrors.ErrNotFound):
+				// Автор удалён — публикуем событие без него, слушатели это допускают.
+			default:
+				return nil, fmt.Errorf("загрузка автора события %d: %w", record.ActorID, err)
+			}
+		}
+		return event, nil
+	default:
+		return nil, fmt.Errorf("неизвестный тип события %q", name)
+	}
+}
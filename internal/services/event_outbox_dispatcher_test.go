@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/events"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+type outboxActorRepoStub struct {
+	repositories.UserRepositoryInterface
+	errs map[uint64]error
+}
+
+func (s *outboxActorRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	if err := s.errs[id]; err != nil {
+		return nil, err
+	}
+	return &entities.User{ID: id}, nil
+}
+
+func TestEventOutboxDecode_PublishesWithoutDeletedActor(t *testing.T) {
+	users := &outboxActorRepoStub{errs: map[uint64]error{
+		2: pgx.ErrNoRows,
+		3: apperrors.ErrNotFound,
+		4: errors.New("connection refused"),
+	}}
+	dispatcher := NewEventOutboxDispatcher(nil, nil, users, nil, zap.NewNop()).(*EventOutboxDispatcher)
+	name := events.OrderHistoryCreatedEvent{}.Name()
+	payload := func(actorID uint64) []byte {
+		raw, _ := json.Marshal(events.OrderHistoryCreatedRecord{
+			HistoryItem: repositories.OrderHistoryItem{ID: 10, OrderID: 7},
+			Order:       &entities.Order{ID: 7},
+			ActorID:     actorID,
+		})
+		return raw
+	}
+
+	event, err := dispatcher.decode(context.Background(), name, payload(1))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if actor, _ := event.(events.OrderHistoryCreatedEvent).Actor.(*entities.User); actor == nil || actor.ID != 1 {
+		t.Fatalf("existing actor must be loaded, got %+v", event)
+	}
+
+	for _, actorID := range []uint64{2, 3} {
+		event, err := dispatcher.decode(context.Background(), name, payload(actorID))
+		if err != nil {
+			t.Fatalf("deleted actor %d must not block the event: %v", actorID, err)
+		}
+		if decoded := event.(events.OrderHistoryCreatedEvent); decoded.Actor != nil || decoded.HistoryItem.ID != 10 {
+			t.Fatalf("event must be published without the actor: %+v", decoded)
+		}
+	}
+
+	// Прочие ошибки чтения оставляют событие в outbox для повтора
+	if _, err := dispatcher.decode(context.Background(), name, payload(4)); err == nil {
+		t.Fatal("a lookup failure must fail the decode")
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/events"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/eventbus"
+)
+
+const (
+	eventReplayDefaultLimit = 1000
+	eventReplayBatchSize    = 200
+	// Пауза между пачками, чтобы слушатели (Telegram, webhooks) успевали разгребать события.
+	eventReplayBatchPause = 200 * time.Millisecond
+)
+
+type EventReplayServiceInterface interface {
+	ReplayOrderHistory(ctx context.Context, req dto.EventReplayRequestDTO) (*dto.EventReplayResultDTO, error)
+}
+
+// EventReplayService повторно публикует записи order_history в шину как order.history.created —
+// чтобы исправленный слушатель или новая проекция догнали события, пропущенные во время сбоя.
+type EventReplayService struct {
+	historyRepo repositories.OrderHistoryRepositoryInterface
+	orderRepo   repositories.OrderRepositoryInterface
+	userRepo    repositories.UserRepositoryInterface
+	bus         *eventbus.Bus
+	logger      *zap.Logger
+	batchPause  time.Duration
+}
+
+func NewEventReplayService(
+	historyRepo repositories.OrderHistoryRepositoryInterface,
+	orderRepo repositories.OrderRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	bus *eventbus.Bus,
+	logger *zap.Logger,
+) EventReplayServiceInterface {
+	return &EventReplayService{
+		historyRepo: historyRepo,
+		orderRepo:   orderRepo,
+		userRepo:    userRepo,
+		bus:         bus,
+		logger:      logger,
+		batchPause:  eventReplayBatchPause,
+	}
+}
+
+func (s *EventReplayService) ReplayOrderHistory(ctx context.Context, req dto.EventReplayRequestDTO) (*dto.EventReplayResultDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.EventsReplay, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	if req.OrderID == nil && req.DateFrom == nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Укажите order_id или date_from", nil, nil)
+	}
+	if req.DateFrom != nil && req.DateTo != nil && !req.DateFrom.Before(*req.DateTo) {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "date_from должна быть раньше date_to", nil, nil)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = eventReplayDefaultLimit
+	}
+
+	filter := repositories.HistoryReplayFilter{
+		OrderID:    req.OrderID,
+		From:       req.DateFrom,
+		To:         req.DateTo,
+		EventTypes: req.EventTypes,
+	}
+	result := &dto.EventReplayResultDTO{LastHistoryID: req.AfterID, DryRun: req.DryRun}
+
+	orders := make(map[uint64]*entities.Order)
+	actors := make(map[uint64]*entities.User)
+	for result.Matched < limit {
+		// Берём на одну запись больше, чем осталось, чтобы понять, есть ли продолжение.
+		batchSize := min(eventReplayBatchSize, limit-result.Matched+1)
+		items, err := s.historyRepo.FindForReplay(ctx, filter, result.LastHistoryID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > limit-result.Matched {
+			result.HasMore = true
+			items = items[:limit-result.Matched]
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		for _, item := range items {
+			result.Matched++
+			result.LastHistoryID = item.ID
+			if req.DryRun {
+				continue
+			}
+
+			order, err := s.loadOrder(ctx, orders, item.OrderID)
+			if err != nil {
+				return nil, err
+			}
+			if order == nil {
+				// Заявка удалена — слушателям нечего показывать.
+				result.Skipped++
+				continue
+			}
+			actor, err := s.loadActor(ctx, actors, item.UserID)
+			if err != nil {
+				return nil, err
+			}
+
+			event := events.OrderHistoryCreatedEvent{HistoryItem: item, Order: order, Replayed: true}
+			if actor != nil {
+				event.Actor = actor
+			}
+			s.bus.Publish(context.WithoutCancel(ctx), event)
+			result.Published++
+		}
+
+		if result.HasMore || len(items) < batchSize {
+			break
+		}
+		if !req.DryRun && s.batchPause > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(s.batchPause):
+			}
+		}
+	}
+
+	s.logger.Info("Переиграны события истории заявок",
+		zap.Uint64("by", authContext.Actor.ID),
+		zap.Any("orderID", req.OrderID),
+		zap.Any("dateFrom", req.DateFrom),
+		zap.Any("dateTo", req.DateTo),
+		zap.Int("matched", result.Matched),
+		zap.Int("published", result.Published),
+		zap.Bool("dryRun", req.DryRun))
+	return result, nil
+}
+
+func (s *EventReplayService) loadOrder(ctx context.Context, cache map[uint64]*entities.Order, orderID uint64) (*entities.Order, error) {
+	if order, ok := cache[orderID]; ok {
+		return order, nil
+	}
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		order, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache[orderID] = order
+	return order, nil
+}
+
+func (s *EventReplayService) loadActor(ctx context.Context, cache map[uint64]*entities.User, userID uint64) (*entities.User, error) {
+	if actor, ok := cache[userID]; ok {
+		return actor, nil
+	}
+	actor, err := s.userRepo.FindUserByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, apperrors.ErrNotFound) {
+		actor, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache[userID] = actor
+	return actor, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+)
+
+type replayUserRepoStub struct {
+	repositories.UserRepositoryInterface
+}
+
+func (s *replayUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	return &entities.User{ID: id}, nil
+}
+
+func TestEventReplay_DryRunPagesThroughHistory(t *testing.T) {
+	history := &orderHistoryRepoStub{}
+	for id := uint64(1); id <= 5; id++ {
+		history.events = append(history.events, repositories.OrderHistoryItem{ID: id, OrderID: 7, EventType: "COMMENT"})
+	}
+	service := NewEventReplayService(history, nil, &replayUserRepoStub{}, nil, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.EventsReplay: true})
+	from := time.Now().Add(-time.Hour)
+
+	first, err := service.ReplayOrderHistory(ctx, dto.EventReplayRequestDTO{DateFrom: &from, Limit: 3, DryRun: true})
+	if err != nil {
+		t.Fatalf("first replay failed: %v", err)
+	}
+	if first.Matched != 3 || !first.HasMore || first.LastHistoryID != 3 || first.Published != 0 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+
+	second, err := service.ReplayOrderHistory(ctx, dto.EventReplayRequestDTO{DateFrom: &from, Limit: 3, AfterID: first.LastHistoryID, DryRun: true})
+	if err != nil {
+		t.Fatalf("second replay failed: %v", err)
+	}
+	if second.Matched != 2 || second.HasMore || second.LastHistoryID != 5 {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+}
+
+func TestEventReplay_RequiresOrderOrDateFrom(t *testing.T) {
+	service := NewEventReplayService(&orderHistoryRepoStub{}, nil, &replayUserRepoStub{}, nil, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.EventsReplay: true})
+
+	if _, err := service.ReplayOrderHistory(ctx, dto.EventReplayRequestDTO{DryRun: true}); err == nil {
+		t.Fatal("expected an error for an unbounded replay")
+	}
+}
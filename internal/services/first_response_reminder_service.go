@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/businesshours"
+	"request-system/pkg/config"
+	"request-system/pkg/i18n"
+	"request-system/pkg/sanitize"
+	"request-system/pkg/telegram"
+	"request-system/pkg/websocket"
+)
+
+const (
+	// firstResponseCandidateLookback — заявки старше в проверку не попадают.
+	firstResponseCandidateLookback = 30 * 24 * time.Hour
+	// firstResponseRemindLookback — напоминание, время которого прошло раньше, уже не отправляется:
+	// первое включение и долгий простой не должны засыпать исполнителей старыми заявками.
+	firstResponseRemindLookback = 24 * time.Hour
+	firstResponseBatchSize      = 1000
+)
+
+type FirstResponseReminderServiceInterface interface {
+	Start(ctx context.Context)
+	// RunOnce напоминает исполнителям о заявках, по которым прошла заданная доля срока
+	// первого отклика. Возвращает число отправленных напоминаний.
+	RunOnce(ctx context.Context) (int, error)
+}
+
+// FirstResponseReminderService напоминает исполнителю о заявке без отклика, когда прошло
+// FirstResponseRemindPercent процентов срока первого отклика её приоритета. Срок и доля
+// считаются по рабочему календарю, как и сама метрика. Каждый исполнитель получает не
+// больше одного напоминания по заявке.
+type FirstResponseReminderService struct {
+	repo               repositories.FirstResponseReminderRepositoryInterface
+	userRepo           repositories.UserRepositoryInterface
+	outbox             NotificationOutboxServiceInterface
+	notificationCenter NotificationCenterServiceInterface
+	calendar           BusinessCalendarProvider
+	notifyCfg          config.NotificationsConfig
+	frontendBaseURL    string
+	logger             *zap.Logger
+	now                func() time.Time
+}
+
+func NewFirstResponseReminderService(
+	repo repositories.FirstResponseReminderRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	outbox NotificationOutboxServiceInterface,
+	notificationCenter NotificationCenterServiceInterface,
+	calendar BusinessCalendarProvider,
+	notifyCfg config.NotificationsConfig,
+	frontendCfg config.FrontendConfig,
+	logger *zap.Logger,
+) FirstResponseReminderServiceInterface {
+	return &FirstResponseReminderService{
+		repo:               repo,
+		userRepo:           userRepo,
+		outbox:             outbox,
+		notificationCenter: notificationCenter,
+		calendar:           calendar,
+		notifyCfg:          notifyCfg,
+		frontendBaseURL:    strings.TrimRight(frontendCfg.BaseURL, "/"),
+		logger:             logger,
+		now:                time.Now,
+	}
+}
+
+// Start проверяет заявки раз в FirstResponseInterval; 0 или нулевая доля — напоминаний нет.
+func (s *FirstResponseReminderService) Start(ctx context.Context) {
+	interval := s.notifyCfg.FirstResponseInterval
+	if interval <= 0 || s.notifyCfg.FirstResponseRemindPercent <= 0 {
+		s.logger.Info("Напоминания о первом отклике выключены")
+		return
+	}
+	s.logger.Info("Напоминания о первом отклике запущены",
+		zap.Duration("interval", interval), zap.Int("percent", s.notifyCfg.FirstResponseRemindPercent))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Напоминания о первом отклике остановлены")
+			return
+		case <-ticker.C:
+		}
+
+		sent, err := s.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Ошибка проверки первого отклика", zap.Error(err))
+			}
+			continue
+		}
+		if sent > 0 {
+			s.logger.Info("Отправлены напоминания о первом отклике", zap.Int("count", sent))
+		}
+	}
+}
+
+func (s *FirstResponseReminderService) RunOnce(ctx context.Context) (int, error) {
+	percent := s.notifyCfg.FirstResponseRemindPercent
+	if percent <= 0 {
+		return 0, nil
+	}
+	now := s.now()
+	candidates, err := s.repo.FindCandidates(ctx, now.Add(-firstResponseCandidateLookback), percent, firstResponseBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var calendar *businesshours.Calendar
+	if s.calendar != nil {
+		calendar = s.calendar.Calendar(ctx)
+	}
+
+	sent := 0
+	for _, candidate := range candidates {
+		window := time.Duration(candidate.FirstResponseMinutes) * time.Minute
+		remindAt := calendar.Add(candidate.CreatedAt, window*time.Duration(percent)/100)
+		if remindAt.After(now) || now.Sub(remindAt) > firstResponseRemindLookback {
+			continue
+		}
+
+		// Напоминание записывается до отправки: сбой очереди не должен повторять его на каждой проверке.
+		marked, err := s.repo.MarkReminded(ctx, candidate.OrderID, candidate.ExecutorID)
+		if err != nil {
+			return sent, err
+		}
+		if !marked {
+			continue
+		}
+		executor, err := s.userRepo.FindUserByID(ctx, candidate.ExecutorID)
+		if err != nil {
+			s.logger.Warn("Не удалось загрузить исполнителя для напоминания о первом отклике",
+				zap.Uint64("order_id", candidate.OrderID), zap.Uint64("executor_id", candidate.ExecutorID), zap.Error(err))
+			continue
+		}
+
+		due := calendar.Add(candidate.CreatedAt, window)
+		s.notifyExecutor(ctx, executor, candidate, firstResponseSecondsLeft(due, now, calendar), now)
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *FirstResponseReminderService) notifyExecutor(ctx context.Context, executor *entities.User, candidate entities.FirstResponseCandidate, secondsLeft int64, now time.Time) {
+	lang := executor.Language
+	telegramKey, wsKey := "notify.first_response_reminder", "notify.ws.first_response_reminder"
+	left := time.Duration(secondsLeft) * time.Second
+	if secondsLeft <= 0 {
+		telegramKey, wsKey = "notify.first_response_overdue", "notify.ws.first_response_overdue"
+		left = -left
+	}
+	leftText := formatEscalationDelay(lang, left)
+
+	if s.notifyCfg.TelegramActive() && executor.TelegramChatID.Valid {
+		escape := telegram.EscapeTextForMarkdownV2
+		link := fmt.Sprintf("%s/orders/%d", s.frontendBaseURL, candidate.OrderID)
+		text := i18n.T(lang, telegramKey, escape(candidate.DisplayNumber()), link, escape(candidate.OrderName), escape(leftText))
+		if err := s.outbox.EnqueueTelegram(ctx, executor.ID, executor.TelegramChatID.Int64, text); err != nil {
+			s.logger.Error("Не удалось поставить в очередь напоминание о первом отклике в Telegram", zap.Uint64("userID", executor.ID), zap.Error(err))
+		}
+	}
+
+	escape := sanitize.HTML
+	payload := &websocket.NotificationPayload{
+		EventID:   uuid.New().String(),
+		Type:      "FIRST_RESPONSE_REMINDER",
+		Message:   i18n.T(lang, wsKey, escape(candidate.DisplayNumber()), escape(candidate.OrderName), leftText),
+		Links:     websocket.LinkInfo{Primary: fmt.Sprintf("/orders/%d", candidate.OrderID)},
+		CreatedAt: now,
+	}
+	if err := s.notificationCenter.Save(ctx, executor.ID, &candidate.OrderID, payload); err != nil {
+		s.logger.Error("Не удалось сохранить напоминание о первом отклике в центр уведомлений", zap.Uint64("userID", executor.ID), zap.Error(err))
+	}
+	if !s.notifyCfg.WebSocketActive() {
+		return
+	}
+	if err := s.outbox.EnqueueWebSocket(ctx, executor.ID, payload, "notification"); err != nil {
+		s.logger.Error("Не удалось поставить в очередь WebSocket-напоминание о первом отклике", zap.Uint64("userID", executor.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+)
+
+type firstResponseRepoStub struct {
+	repositories.FirstResponseReminderRepositoryInterface
+	candidates []entities.FirstResponseCandidate
+	reminded   map[uint64]bool
+}
+
+func (s *firstResponseRepoStub) FindCandidates(context.Context, time.Time, int, int) ([]entities.FirstResponseCandidate, error) {
+	return s.candidates, nil
+}
+
+func (s *firstResponseRepoStub) MarkReminded(_ context.Context, orderID, _ uint64) (bool, error) {
+	if s.reminded[orderID] {
+		return false, nil
+	}
+	s.reminded[orderID] = true
+	return true, nil
+}
+
+func TestFirstResponseReminderSendsOnceAtThreshold(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	candidate := func(orderID uint64, minutes int, ago time.Duration) entities.FirstResponseCandidate {
+		return entities.FirstResponseCandidate{OrderID: orderID, OrderName: "Принтер", CreatedAt: now.Add(-ago),
+			FirstResponseMinutes: minutes, ExecutorID: 7}
+	}
+	repo := &firstResponseRepoStub{
+		reminded: map[uint64]bool{},
+		candidates: []entities.FirstResponseCandidate{
+			// Час на отклик, прошло 50 минут: 75% позади, осталось 10 минут.
+			candidate(1, 60, 50*time.Minute),
+			// Прошло 30 минут из 60 — напоминать рано.
+			candidate(2, 60, 30*time.Minute),
+			// Срок истёк давно, точка напоминания позади больше чем на сутки.
+			candidate(3, 60, 72*time.Hour),
+		},
+	}
+	users := map[uint64]*entities.User{7: {ID: 7, TelegramChatID: sql.NullInt64{Int64: 500, Valid: true}}}
+	outbox := &escalationOutboxStub{telegram: map[uint64][]string{}}
+	center := &escalationCenterStub{saved: map[uint64]int{}}
+	service := &FirstResponseReminderService{
+		repo:               repo,
+		userRepo:           &telegramLinkUserRepoStub{users: users},
+		outbox:             outbox,
+		notificationCenter: center,
+		notifyCfg:          config.NotificationsConfig{TelegramEnabled: true, FirstResponseRemindPercent: 75},
+		frontendBaseURL:    "https://sd.example",
+		logger:             zap.NewNop(),
+		now:                func() time.Time { return now },
+	}
+
+	sent, err := service.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if sent != 1 || len(repo.reminded) != 1 || !repo.reminded[1] {
+		t.Fatalf("sent = %d, reminded %v; want only order 1", sent, repo.reminded)
+	}
+	if len(outbox.telegram[7]) != 1 || center.saved[7] != 1 {
+		t.Fatalf("executor must be notified once: telegram %v, center %v", outbox.telegram, center.saved)
+	}
+	if text := outbox.telegram[7][0]; !strings.Contains(text, "https://sd.example/orders/1") || !strings.Contains(text, "10 мин") {
+		t.Errorf("reminder lacks the order link or time left: %q", text)
+	}
+
+	// Следующая проверка не повторяет напоминание.
+	if sent, err := service.RunOnce(context.Background()); err != nil || sent != 0 || len(outbox.telegram[7]) != 1 {
+		t.Fatalf("a reminder must be sent once: %d, %v, %v", sent, err, outbox.telegram)
+	}
+}
+
+func TestFirstResponseCountdownStopsAfterResponse(t *testing.T) {
+	created := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	minutes := 60
+	order := &entities.Order{CreatedAt: created, PriorityFirstResponseMinutes: &minutes}
+
+	due, ok := firstResponseDeadline(order, nil)
+	if !ok || !due.Equal(created.Add(time.Hour)) {
+		t.Fatalf("deadline = %v, %v; want %v", due, ok, created.Add(time.Hour))
+	}
+	if left := firstResponseSecondsLeft(due, created.Add(90*time.Minute), nil); left != -1800 {
+		t.Errorf("seconds left = %d, want -1800", left)
+	}
+
+	responded := uint64(600)
+	order.FirstResponseTimeSeconds = &responded
+	if _, ok := firstResponseDeadline(order, nil); ok {
+		t.Error("no countdown once the executor has responded")
+	}
+}
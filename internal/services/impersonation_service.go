@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// ImpersonationServiceInterface проверяет, можно ли войти под пользователем;
+// сам токен выпускает контроллер, как и при обычном входе.
+type ImpersonationServiceInterface interface {
+	// Impersonate возвращает целевого пользователя и его права.
+	Impersonate(ctx context.Context, userID uint64) (*entities.User, []string, error)
+}
+
+type ImpersonationService struct {
+	userRepo              repositories.UserRepositoryInterface
+	authPermissionService AuthPermissionServiceInterface
+	cfg                   *config.AuthConfig
+	logger                *zap.Logger
+}
+
+func NewImpersonationService(
+	userRepo repositories.UserRepositoryInterface,
+	authPermissionService AuthPermissionServiceInterface,
+	cfg *config.AuthConfig,
+	logger *zap.Logger,
+) ImpersonationServiceInterface {
+	return &ImpersonationService{
+		userRepo:              userRepo,
+		authPermissionService: authPermissionService,
+		cfg:                   cfg,
+		logger:                logger,
+	}
+}
+
+func (s *ImpersonationService) Impersonate(ctx context.Context, userID uint64) (*entities.User, []string, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !authz.CanDo(authz.UsersImpersonate, *authContext) {
+		return nil, nil, apperrors.ErrForbidden
+	}
+	// Цепочки не допускаются: из-под чужой учётной записи войти под третьей нельзя.
+	if _, impersonating := utils.GetImpersonatorIDFromCtx(ctx); impersonating {
+		return nil, nil, apperrors.NewHttpError(http.StatusForbidden, "Нельзя начать имперсонацию из режима имперсонации", nil, nil)
+	}
+	if userID == authContext.Actor.ID {
+		return nil, nil, apperrors.NewBadRequestError("Нельзя войти под собственной учётной записью")
+	}
+
+	target, err := s.userRepo.FindUserByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, apperrors.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.StatusCode != constants.UserStatusActiveCode {
+		return nil, nil, apperrors.ErrUserDisabled
+	}
+
+	permissions, err := s.authPermissionService.GetAllUserPermissions(ctx, target.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Под администраторами с тем же правом и под системной учётной записью не входим: это цепочки
+	// и полный доступ, который имперсонацией не передаётся.
+	if slices.Contains(permissions, authz.UsersImpersonate) ||
+		(s.cfg.SystemRootLogin != "" && strings.EqualFold(target.Email, s.cfg.SystemRootLogin)) {
+		return nil, nil, apperrors.NewHttpError(http.StatusForbidden, "Вход под этим пользователем запрещён", nil, nil)
+	}
+	// Имперсонация не даёт больше прав, чем есть у самого администратора: каждое право цели
+	// должно быть и у него, причём не под более узкими условиями ролей.
+	conditions, err := s.authPermissionService.GetUserPermissionConditions(ctx, target.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if exceeding := permissionsBeyond(authContext, permissions, conditions); len(exceeding) > 0 {
+		s.logger.Warn("Отказ в имперсонации: у цели есть права, которых нет у администратора",
+			zap.Uint64("impersonatorID", authContext.Actor.ID), zap.Uint64("userID", target.ID), zap.Strings("permissions", exceeding))
+		return nil, nil, apperrors.NewHttpError(http.StatusForbidden, "Вход под этим пользователем запрещён: у него есть права, которых нет у вас",
+			nil, map[string]any{"permissions": exceeding})
+	}
+
+	s.logger.Warn("Начата имперсонация пользователя",
+		zap.Uint64("impersonatorID", authContext.Actor.ID), zap.Uint64("userID", target.ID))
+	return target, permissions, nil
+}
+
+// permissionsBeyond возвращает права цели, которых нет у actor. Право, выданное actor с условиями,
+// покрывает право цели только с теми же условиями: безусловное право цели шире.
+func permissionsBeyond(actor *authz.Context, permissions []string, conditions map[string][]authz.Condition) []string {
+	var exceeding []string
+	for _, permission := range permissions {
+		if !actor.Permissions[permission] {
+			exceeding = append(exceeding, permission)
+			continue
+		}
+		actorConditions := actor.Conditions[permission]
+		if len(actorConditions) > 0 && !reflect.DeepEqual(actorConditions, conditions[permission]) {
+			exceeding = append(exceeding, permission)
+		}
+	}
+	return exceeding
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type impersonationUserRepoStub struct {
+	repositories.UserRepositoryInterface
+}
+
+func (s *impersonationUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	return &entities.User{ID: id, Email: "user@local", StatusCode: constants.UserStatusActiveCode}, nil
+}
+
+type impersonationPermissionsStub struct {
+	AuthPermissionServiceInterface
+	permissions map[uint64][]string
+	conditions  map[uint64]map[string][]authz.Condition
+}
+
+func (s *impersonationPermissionsStub) GetAllUserPermissions(_ context.Context, userID uint64) ([]string, error) {
+	return s.permissions[userID], nil
+}
+
+func (s *impersonationPermissionsStub) GetUserPermissionConditions(_ context.Context, userID uint64) (map[string][]authz.Condition, error) {
+	return s.conditions[userID], nil
+}
+
+func TestImpersonateChecksTarget(t *testing.T) {
+	perms := &impersonationPermissionsStub{permissions: map[uint64][]string{
+		2: {authz.OrdersView},
+		3: {authz.OrdersView, authz.UsersImpersonate},
+	}}
+	service := NewImpersonationService(&impersonationUserRepoStub{}, perms, &config.AuthConfig{SystemRootLogin: "admin@local"}, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.UsersImpersonate: true, authz.OrdersView: true})
+
+	target, permissions, err := service.Impersonate(ctx, 2)
+	if err != nil {
+		t.Fatalf("impersonate failed: %v", err)
+	}
+	if target.ID != 2 || len(permissions) != 1 {
+		t.Fatalf("unexpected target %+v with permissions %v", target, permissions)
+	}
+
+	if _, _, err := service.Impersonate(ctx, 1); err == nil {
+		t.Fatal("expected an error when impersonating yourself")
+	}
+	if _, _, err := service.Impersonate(ctx, 3); err == nil {
+		t.Fatal("expected an error when target can impersonate too")
+	}
+	if _, _, err := service.Impersonate(utils.WithImpersonator(ctx, 9), 2); err == nil {
+		t.Fatal("expected an error when already impersonating")
+	}
+}
+
+func TestImpersonateRefusesTargetWithWiderPermissions(t *testing.T) {
+	perms := &impersonationPermissionsStub{
+		permissions: map[uint64][]string{
+			2: {authz.OrdersView, authz.UsersDelete},
+			3: {authz.OrdersView},
+		},
+		conditions: map[uint64]map[string][]authz.Condition{},
+	}
+	service := NewImpersonationService(&impersonationUserRepoStub{}, perms, &config.AuthConfig{}, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.UsersImpersonate: true, authz.OrdersView: true})
+
+	_, _, err := service.Impersonate(ctx, 2)
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a target with %s, got %v", authz.UsersDelete, err)
+	}
+
+	// Право администратора ограничено своим департаментом, у цели — нет.
+	narrowed := utils.WithPermissionConditions(ctx, map[string][]authz.Condition{authz.OrdersView: {{SameDepartment: true}}})
+	if _, _, err := service.Impersonate(narrowed, 3); err == nil {
+		t.Fatal("expected an error when the target holds the permission without the actor's conditions")
+	}
+	perms.conditions[3] = map[string][]authz.Condition{authz.OrdersView: {{SameDepartment: true}}}
+	if _, _, err := service.Impersonate(narrowed, 3); err != nil {
+		t.Fatalf("expected impersonation with the same conditions to pass, got %v", err)
+	}
+}
@@ -0,0 +1,479 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	"request-system/internal/sync"
+	apperrors "request-system/pkg/errors"
+)
+
+// ImportEntityEquipment — оборудование; остальные справочники импорта называются как в выгрузке 1С.
+const ImportEntityEquipment = "equipment"
+
+type ImportServiceInterface interface {
+	// Import загружает CSV справочника entity. С preview=true файл только проверяется: изменения
+	// считаются в откатываемой транзакции и ничего не сохраняется.
+	Import(ctx context.Context, entity string, file io.Reader, preview bool) (*dto.ImportResultDTO, error)
+}
+
+// equipmentRowsImporter — часть *EquipImportService, нужная импорту CSV.
+type equipmentRowsImporter interface {
+	ImportRows(ctx context.Context, rows []EquipmentImportRow, dryRun bool) (*EquipmentImportResult, error)
+}
+
+// importColumn — колонка CSV. Имя совпадает с полем выгрузки 1С; в заголовке регистр, «_» и
+// пробелы не важны: externalId, external_id и EXTERNAL ID — одна колонка.
+type importColumn struct {
+	name     string
+	required bool
+}
+
+var importColumns = map[string][]importColumn{
+	sync.EntityUsers: {
+		{name: "externalId", required: true}, {name: "fio"}, {name: "username"}, {name: "email"},
+		{name: "phoneNumber"}, {name: "isActive"}, {name: "positionExternalId"}, {name: "departmentExternalId"},
+		{name: "otdelExternalId"}, {name: "branchExternalId"}, {name: "officeExternalId"},
+	},
+	sync.EntityBranches: {
+		{name: "externalId", required: true}, {name: "name", required: true}, {name: "shortName"}, {name: "address"},
+		{name: "phoneNumber"}, {name: "email"}, {name: "emailIndex"}, {name: "openDate"}, {name: "isActive"},
+	},
+	sync.EntityOffices: {
+		{name: "externalId", required: true}, {name: "name", required: true}, {name: "address"}, {name: "openDate"},
+		{name: "branchExternalId"}, {name: "parentExternalId"}, {name: "isActive"},
+	},
+	ImportEntityEquipment: {
+		{name: "name", required: true}, {name: "type", required: true}, {name: "address"},
+		{name: "branch"}, {name: "office"}, {name: "status"},
+	},
+}
+
+// ImportService — импорт пользователей и справочников из CSV. Пользователи, филиалы и офисы идут
+// через обработчик синхронизации 1С: статусы, роли по умолчанию и разбор конфликтов те же.
+type ImportService struct {
+	handler   sync.HandlerInterface
+	equipment equipmentRowsImporter
+	userRepo  repositories.UserRepositoryInterface
+	logger    *zap.Logger
+}
+
+func NewImportService(handler sync.HandlerInterface, equipment equipmentRowsImporter, userRepo repositories.UserRepositoryInterface, logger *zap.Logger) ImportServiceInterface {
+	return &ImportService{handler: handler, equipment: equipment, userRepo: userRepo, logger: logger.Named("csv_import")}
+}
+
+func (s *ImportService) Import(ctx context.Context, entity string, file io.Reader, preview bool) (*dto.ImportResultDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.DataImport, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	columns, ok := importColumns[entity]
+	if !ok {
+		return nil, apperrors.NewBadRequestError("Справочник импорта: допустимы users, branches, offices, equipment")
+	}
+
+	table, err := readImportCSV(file, columns)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, err.Error(), err, nil)
+	}
+
+	result := &dto.ImportResultDTO{Entity: entity, Preview: preview, Rows: len(table.rows), Errors: []dto.ImportRowErrorDTO{}}
+	table.rows = dropIncompleteRows(table.rows, columns, result)
+	if entity == ImportEntityEquipment {
+		err = s.importEquipment(ctx, table, preview, result)
+	} else {
+		err = s.importReferences(ctx, entity, table, preview, result)
+	}
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Импорт не выполнен", err, nil)
+	}
+
+	result.Failed = countFailedRows(result.Errors)
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Row < result.Errors[j].Row })
+	s.logger.Info("Импорт CSV выполнен",
+		zap.String("entity", entity),
+		zap.Bool("preview", preview),
+		zap.Int("rows", result.Rows),
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("failed", result.Failed),
+		zap.Uint64("by", authContext.Actor.ID),
+	)
+	return result, nil
+}
+
+// importReferences собирает из строк выгрузку 1С и отдаёт её обработчику синхронизации в режиме
+// best effort. Строки, не прошедшие разбор, в выгрузку не попадают.
+func (s *ImportService) importReferences(ctx context.Context, entity string, table *importTable, preview bool, result *dto.ImportResultDTO) error {
+	var payload dto.Webhook1CPayloadDTO
+	var lines []int
+	seen := make(map[string]int)
+	for _, row := range table.rows {
+		externalID := row.get("externalId")
+		if first, ok := seen[externalID]; ok {
+			result.Errors = append(result.Errors, dto.ImportRowErrorDTO{
+				Row: row.line, Key: externalID, Field: "externalId", Reason: fmt.Sprintf("externalId уже встречался в строке %d", first),
+			})
+			continue
+		}
+		seen[externalID] = row.line
+
+		var rowErr *dto.ImportRowErrorDTO
+		switch entity {
+		case sync.EntityUsers:
+			var user dto.User1CDTO
+			if user, rowErr = row.user(); rowErr == nil {
+				payload.Users = append(payload.Users, user)
+			}
+		case sync.EntityBranches:
+			var branch dto.Branch1CDTO
+			if branch, rowErr = row.branch(); rowErr == nil {
+				payload.Branches = append(payload.Branches, branch)
+			}
+		case sync.EntityOffices:
+			var office dto.Office1CDTO
+			if office, rowErr = row.office(); rowErr == nil {
+				payload.Offices = append(payload.Offices, office)
+			}
+		}
+		if rowErr != nil {
+			result.Errors = append(result.Errors, *rowErr)
+			continue
+		}
+		lines = append(lines, row.line)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	report := sync.NewReport()
+	if preview {
+		report = sync.NewDryRunReport()
+	}
+	runCtx := sync.WithBestEffort(sync.WithReport(ctx, report))
+	run := func(ctx context.Context) error {
+		switch entity {
+		case sync.EntityUsers:
+			return s.handler.ProcessUsers(ctx, payload.Users)
+		case sync.EntityBranches:
+			return s.handler.ProcessBranches(ctx, payload.Branches)
+		default:
+			return s.handler.ProcessOffices(ctx, payload.Offices)
+		}
+	}
+	var err error
+	if preview {
+		err = s.handler.DryRun(runCtx, run)
+	} else {
+		err = run(runCtx)
+	}
+	if err != nil {
+		return err
+	}
+
+	lineOf := func(index int) int {
+		if index >= 0 && index < len(lines) {
+			return lines[index]
+		}
+		return 0
+	}
+	progress, recordErrors, _ := report.Snapshot()
+	stats := progress[entity]
+	result.Created, result.Updated = stats.Created, stats.Updated
+	for _, e := range recordErrors {
+		result.Errors = append(result.Errors, dto.ImportRowErrorDTO{Row: lineOf(e.Index), Key: e.ExternalID, Field: e.Field, Reason: e.Reason})
+	}
+	for _, c := range report.Changes() {
+		result.Changes = append(result.Changes, dto.ImportChangeDTO{Row: lineOf(c.Index), Key: c.ExternalID, Action: c.Action, Fields: c.Fields})
+	}
+	return nil
+}
+
+func (s *ImportService) importEquipment(ctx context.Context, table *importTable, preview bool, result *dto.ImportResultDTO) error {
+	rows := make([]EquipmentImportRow, 0, len(table.rows))
+	seen := make(map[string]int)
+	for _, row := range table.rows {
+		item := EquipmentImportRow{
+			Row:     row.line,
+			Name:    row.get("name"),
+			Address: row.get("address"),
+			Branch:  row.get("branch"),
+			Office:  row.get("office"),
+			Type:    row.get("type"),
+			Status:  row.get("status"),
+		}
+		if first, ok := seen[item.Name]; ok {
+			result.Errors = append(result.Errors, dto.ImportRowErrorDTO{
+				Row: row.line, Key: item.Name, Field: "name", Reason: fmt.Sprintf("название уже встречалось в строке %d", first),
+			})
+			continue
+		}
+		seen[item.Name] = row.line
+		rows = append(rows, item)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	imported, err := s.equipment.ImportRows(ctx, rows, preview)
+	if err != nil {
+		return err
+	}
+	result.Created, result.Updated = imported.Created, imported.Updated
+	result.Errors = append(result.Errors, imported.Errors...)
+	result.Changes = imported.Changes
+	return nil
+}
+
+// dropIncompleteRows убирает строки с незаполненными обязательными колонками и записывает их в ошибки.
+func dropIncompleteRows(rows []importRow, columns []importColumn, result *dto.ImportResultDTO) []importRow {
+	complete := rows[:0]
+	for _, row := range rows {
+		ok := true
+		for _, column := range columns {
+			if column.required && row.get(column.name) == "" {
+				key := row.get("externalId")
+				if key == "" {
+					key = row.get("name")
+				}
+				result.Errors = append(result.Errors, *row.fail(key, column.name, "не заполнено обязательное поле"))
+				ok = false
+			}
+		}
+		if ok {
+			complete = append(complete, row)
+		}
+	}
+	return complete
+}
+
+// countFailedRows — число строк с ошибками: у одной строки их может быть несколько.
+func countFailedRows(errs []dto.ImportRowErrorDTO) int {
+	rows := make(map[int]bool, len(errs))
+	for _, e := range errs {
+		rows[e.Row] = true
+	}
+	return len(rows)
+}
+
+type importTable struct {
+	rows []importRow
+}
+
+type importRow struct {
+	line   int
+	values map[string]string
+}
+
+func (r importRow) get(column string) string {
+	return r.values[importColumnKey(column)]
+}
+
+func (r importRow) optional(column string) *string {
+	if value := r.get(column); value != "" {
+		return &value
+	}
+	return nil
+}
+
+func (r importRow) fail(key, field, reason string) *dto.ImportRowErrorDTO {
+	return &dto.ImportRowErrorDTO{Row: r.line, Key: key, Field: field, Reason: reason}
+}
+
+// flag читает isActive: пустое значение — def.
+func (r importRow) flag(column string, def bool) (bool, bool) {
+	switch strings.ToLower(r.get(column)) {
+	case "":
+		return def, true
+	case "1", "true", "yes", "да":
+		return true, true
+	case "0", "false", "no", "нет":
+		return false, true
+	}
+	return false, false
+}
+
+func (r importRow) date(column string) (time.Time, bool) {
+	value := r.get(column)
+	if value == "" {
+		return time.Time{}, true
+	}
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r importRow) user() (dto.User1CDTO, *dto.ImportRowErrorDTO) {
+	externalID := r.get("externalId")
+	user := dto.User1CDTO{
+		ExternalID:           externalID,
+		Fio:                  r.optional("fio"),
+		Username:             r.optional("username"),
+		Email:                r.optional("email"),
+		PhoneNumber:          r.optional("phoneNumber"),
+		PositionExternalID:   r.optional("positionExternalId"),
+		DepartmentExternalID: r.optional("departmentExternalId"),
+		OtdelExternalID:      r.optional("otdelExternalId"),
+		BranchExternalID:     r.optional("branchExternalId"),
+		OfficeExternalID:     r.optional("officeExternalId"),
+	}
+	// Пустой isActive не меняет статус существующего пользователя — как в частичной выгрузке 1С.
+	if r.get("isActive") != "" {
+		active, ok := r.flag("isActive", true)
+		if !ok {
+			return user, r.fail(externalID, "isActive", "ожидается true/false, 1/0 или да/нет")
+		}
+		user.IsActive = &active
+	}
+	return user, nil
+}
+
+func (r importRow) branch() (dto.Branch1CDTO, *dto.ImportRowErrorDTO) {
+	externalID := r.get("externalId")
+	active, ok := r.flag("isActive", true)
+	if !ok {
+		return dto.Branch1CDTO{}, r.fail(externalID, "isActive", "ожидается true/false, 1/0 или да/нет")
+	}
+	openDate, ok := r.date("openDate")
+	if !ok {
+		return dto.Branch1CDTO{}, r.fail(externalID, "openDate", "ожидается дата ГГГГ-ММ-ДД или ДД.ММ.ГГГГ")
+	}
+	return dto.Branch1CDTO{
+		ExternalID:  externalID,
+		Name:        r.get("name"),
+		ShortName:   r.get("shortName"),
+		Address:     r.get("address"),
+		PhoneNumber: r.get("phoneNumber"),
+		Email:       r.get("email"),
+		EmailIndex:  r.get("emailIndex"),
+		OpenDate:    openDate,
+		IsActive:    active,
+	}, nil
+}
+
+func (r importRow) office() (dto.Office1CDTO, *dto.ImportRowErrorDTO) {
+	externalID := r.get("externalId")
+	active, ok := r.flag("isActive", true)
+	if !ok {
+		return dto.Office1CDTO{}, r.fail(externalID, "isActive", "ожидается true/false, 1/0 или да/нет")
+	}
+	openDate, ok := r.date("openDate")
+	if !ok {
+		return dto.Office1CDTO{}, r.fail(externalID, "openDate", "ожидается дата ГГГГ-ММ-ДД или ДД.ММ.ГГГГ")
+	}
+	return dto.Office1CDTO{
+		ExternalID:       externalID,
+		Name:             r.get("name"),
+		Address:          r.get("address"),
+		OpenDate:         openDate,
+		BranchExternalID: r.get("branchExternalId"),
+		ParentExternalID: r.get("parentExternalId"),
+		IsActive:         active,
+	}, nil
+}
+
+func importColumnKey(name string) string {
+	return strings.NewReplacer("_", "", " ", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// readImportCSV читает CSV с заголовком. Разделитель — «,» или «;» (так сохраняет Excel в русской
+// локали), BOM в начале файла пропускается, пустые строки пропускаются. Ошибка файла — только
+// неизвестная, повторная или недостающая колонка; значения проверяются построчно.
+func readImportCSV(file io.Reader, columns []importColumn) (*importTable, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("файл пуст")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора CSV: %w", err)
+	}
+
+	known := make(map[string]importColumn, len(columns))
+	for _, column := range columns {
+		known[importColumnKey(column.name)] = column
+	}
+	keys := make([]string, len(header))
+	present := make(map[string]bool, len(header))
+	for i, name := range header {
+		key := importColumnKey(name)
+		if key == "" {
+			continue
+		}
+		if _, ok := known[key]; !ok {
+			return nil, fmt.Errorf("неизвестная колонка '%s'", strings.TrimSpace(name))
+		}
+		if present[key] {
+			return nil, fmt.Errorf("колонка '%s' указана дважды", strings.TrimSpace(name))
+		}
+		keys[i] = key
+		present[key] = true
+	}
+	for _, column := range columns {
+		if column.required && !present[importColumnKey(column.name)] {
+			return nil, fmt.Errorf("нет обязательной колонки '%s'", column.name)
+		}
+	}
+
+	table := &importTable{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := importRow{line: line, values: make(map[string]string, len(keys))}
+		empty := true
+		for i, value := range record {
+			if i >= len(keys) || keys[i] == "" {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			row.values[keys[i]] = value
+			if value != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
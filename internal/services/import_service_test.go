@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/sync"
+	apperrors "request-system/pkg/errors"
+)
+
+type importHandlerStub struct {
+	sync.HandlerInterface
+	users    []dto.User1CDTO
+	branches []dto.Branch1CDTO
+	dryRuns  int
+}
+
+func (h *importHandlerStub) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	h.dryRuns++
+	return fn(ctx)
+}
+
+func (h *importHandlerStub) ProcessUsers(_ context.Context, data []dto.User1CDTO) error {
+	h.users = data
+	return nil
+}
+
+func (h *importHandlerStub) ProcessBranches(_ context.Context, data []dto.Branch1CDTO) error {
+	h.branches = data
+	return nil
+}
+
+type equipmentImporterStub struct {
+	rows   []EquipmentImportRow
+	dryRun bool
+}
+
+func (e *equipmentImporterStub) ImportRows(_ context.Context, rows []EquipmentImportRow, dryRun bool) (*EquipmentImportResult, error) {
+	e.rows, e.dryRun = rows, dryRun
+	return &EquipmentImportResult{
+		Created: len(rows) - 1,
+		Errors:  []dto.ImportRowErrorDTO{{Row: rows[len(rows)-1].Row, Key: rows[len(rows)-1].Name, Field: "type", Reason: "тип не найден"}},
+	}, nil
+}
+
+func importCtx() context.Context {
+	return jobsAdminCtx(map[string]bool{authz.DataImport: true})
+}
+
+func TestImport_RequiresPermissionAndKnownEntity(t *testing.T) {
+	service := NewImportService(&importHandlerStub{}, &equipmentImporterStub{}, &replayUserRepoStub{}, zap.NewNop())
+
+	if _, err := service.Import(jobsAdminCtx(map[string]bool{}), sync.EntityUsers, strings.NewReader("externalId\n1\n"), false); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.DataImport, err)
+	}
+	if _, err := service.Import(importCtx(), "positions", strings.NewReader("externalId\n1\n"), false); err == nil {
+		t.Fatal("expected an error for an unsupported entity")
+	}
+}
+
+func TestImport_RejectsBadHeader(t *testing.T) {
+	service := NewImportService(&importHandlerStub{}, &equipmentImporterStub{}, &replayUserRepoStub{}, zap.NewNop())
+
+	for name, file := range map[string]string{
+		"empty":            "",
+		"unknown column":   "externalId,name,color\n1,Филиал,red\n",
+		"missing column":   "externalId,address\n1,ул. Рудаки\n",
+		"duplicate column": "externalId,name,external_id\n1,Филиал,1\n",
+	} {
+		_, err := service.Import(importCtx(), sync.EntityBranches, strings.NewReader(file), false)
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %v", name, err)
+		}
+	}
+}
+
+func TestImport_UsersGoThroughSyncHandlerWithRowErrors(t *testing.T) {
+	handler := &importHandlerStub{}
+	service := NewImportService(handler, &equipmentImporterStub{}, &replayUserRepoStub{}, zap.NewNop())
+
+	// Excel в русской локали: BOM и «;» в качестве разделителя
+	file := "\xef\xbb\xbfExternal_ID;FIO;Email;is_active\n" +
+		"u1;Иванов Иван;ivanov@bank.tj;да\n" +
+		";Без кода;;\n" +
+		"\n" +
+		"u2;Петров Пётр;;возможно\n" +
+		"u1;Дубль;;\n" +
+		"u3;;;\n"
+
+	result, err := service.Import(importCtx(), sync.EntityUsers, strings.NewReader(file), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.dryRuns != 0 {
+		t.Fatal("import must not run in dry run mode")
+	}
+	if len(handler.users) != 2 || handler.users[0].ExternalID != "u1" || handler.users[1].ExternalID != "u3" {
+		t.Fatalf("unexpected users passed to the sync handler: %+v", handler.users)
+	}
+	if u := handler.users[0]; u.Fio == nil || *u.Fio != "Иванов Иван" || u.IsActive == nil || !*u.IsActive {
+		t.Fatalf("unexpected first user: %+v", u)
+	}
+	// Пустые ячейки не затирают данные: поле не передаётся, как в частичной выгрузке 1С
+	if u := handler.users[1]; u.Fio != nil || u.Email != nil || u.IsActive != nil {
+		t.Fatalf("empty cells must stay nil: %+v", u)
+	}
+
+	if result.Rows != 5 || result.Failed != 3 {
+		t.Fatalf("unexpected counters: %+v", result)
+	}
+	wantRows := []int{3, 5, 6}
+	for i, e := range result.Errors {
+		if e.Row != wantRows[i] {
+			t.Fatalf("error %d: expected row %d, got %+v", i, wantRows[i], e)
+		}
+	}
+}
+
+func TestImport_PreviewUsesDryRun(t *testing.T) {
+	handler := &importHandlerStub{}
+	service := NewImportService(handler, &equipmentImporterStub{}, &replayUserRepoStub{}, zap.NewNop())
+
+	file := "externalId,name,openDate,isActive\nb1,Филиал 1,01.02.2020,0\nb2,Филиал 2,вчера,\n"
+	result, err := service.Import(importCtx(), sync.EntityBranches, strings.NewReader(file), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.dryRuns != 1 || !result.Preview {
+		t.Fatalf("preview must run through DryRun: dryRuns=%d result=%+v", handler.dryRuns, result)
+	}
+	if len(handler.branches) != 1 || handler.branches[0].IsActive || handler.branches[0].OpenDate.Year() != 2020 {
+		t.Fatalf("unexpected branches: %+v", handler.branches)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Field != "openDate" || result.Errors[0].Row != 3 {
+		t.Fatalf("expected an openDate error on row 3, got %+v", result.Errors)
+	}
+}
+
+func TestImport_Equipment(t *testing.T) {
+	equipment := &equipmentImporterStub{}
+	service := NewImportService(&importHandlerStub{}, equipment, &replayUserRepoStub{}, zap.NewNop())
+
+	file := "name,type,branch\nATM-1,Банкомат,Душанбе\nATM-1,Банкомат,Худжанд\nATM-2,Нет такого,\n"
+	result, err := service.Import(importCtx(), ImportEntityEquipment, strings.NewReader(file), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !equipment.dryRun || len(equipment.rows) != 2 || equipment.rows[0].Branch != "Душанбе" {
+		t.Fatalf("unexpected rows passed to the importer: dryRun=%v rows=%+v", equipment.dryRun, equipment.rows)
+	}
+	if result.Created != 1 || result.Failed != 2 || result.Errors[0].Row != 3 || result.Errors[1].Row != 4 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/types"
+	"request-system/pkg/utils"
+	"request-system/pkg/websocket"
+)
+
+type NotificationCenterServiceInterface interface {
+	// Save сохраняет уведомление получателя и проставляет payload.ID.
+	Save(ctx context.Context, userID uint64, orderID *uint64, payload *websocket.NotificationPayload) error
+
+	GetMyNotifications(ctx context.Context, filter types.Filter, unreadOnly bool) (*dto.PaginatedResponse[websocket.NotificationPayload], error)
+	GetMyUnreadCount(ctx context.Context) (*dto.UnreadNotificationsCountDTO, error)
+	MarkRead(ctx context.Context, id uint64) error
+	MarkAllRead(ctx context.Context) (*dto.MarkAllNotificationsReadDTO, error)
+}
+
+type NotificationCenterService struct {
+	repo   repositories.UserNotificationRepositoryInterface
+	logger *zap.Logger
+}
+
+func NewNotificationCenterService(repo repositories.UserNotificationRepositoryInterface, logger *zap.Logger) NotificationCenterServiceInterface {
+	return &NotificationCenterService{repo: repo, logger: logger}
+}
+
+func (s *NotificationCenterService) Save(ctx context.Context, userID uint64, orderID *uint64, payload *websocket.NotificationPayload) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	n := &entities.UserNotification{
+		UserID:  userID,
+		EventID: payload.EventID,
+		OrderID: orderID,
+		Type:    payload.Type,
+		Payload: raw,
+	}
+	if err := s.repo.Create(ctx, n); err != nil {
+		return err
+	}
+	payload.ID = n.ID
+	return nil
+}
+
+// toNotificationPayload восстанавливает payload из БД; ID и признак прочтения берутся из строки, а не из JSON.
+func toNotificationPayload(n *entities.UserNotification) (websocket.NotificationPayload, error) {
+	var payload websocket.NotificationPayload
+	if err := json.Unmarshal(n.Payload, &payload); err != nil {
+		return payload, err
+	}
+	payload.ID = n.ID
+	payload.IsRead = n.IsRead
+	if payload.CreatedAt.IsZero() {
+		payload.CreatedAt = n.CreatedAt
+	}
+	return payload, nil
+}
+
+func (s *NotificationCenterService) GetMyNotifications(ctx context.Context, filter types.Filter, unreadOnly bool) (*dto.PaginatedResponse[websocket.NotificationPayload], error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, total, err := s.repo.FindByUser(ctx, userID, unreadOnly, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]websocket.NotificationPayload, 0, len(items))
+	for i := range items {
+		payload, err := toNotificationPayload(&items[i])
+		if err != nil {
+			s.logger.Warn("Повреждённый payload уведомления, пропускаем", zap.Uint64("notificationID", items[i].ID), zap.Error(err))
+			continue
+		}
+		list = append(list, payload)
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+
+	return &dto.PaginatedResponse[websocket.NotificationPayload]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
+
+func (s *NotificationCenterService) GetMyUnreadCount(ctx context.Context) (*dto.UnreadNotificationsCountDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadNotificationsCountDTO{UnreadCount: count}, nil
+}
+
+func (s *NotificationCenterService) MarkRead(ctx context.Context, id uint64) error {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+func (s *NotificationCenterService) MarkAllRead(ctx context.Context) (*dto.MarkAllNotificationsReadDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MarkAllNotificationsReadDTO{Updated: updated}, nil
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
+	"request-system/pkg/telegram"
+	"request-system/pkg/types"
+)
+
+const (
+	outboxPollInterval   = 2 * time.Second
+	outboxBatchSize      = 50
+	outboxLease          = 2 * time.Minute
+	outboxDefaultRetries = 8
+	outboxBaseBackoff    = 5 * time.Second
+	outboxMaxBackoff     = 30 * time.Minute
+
+	// Лимит Telegram — 4096 символов, оставляем запас на заголовок дайджеста.
+	digestMaxMessageLen = 3500
+)
+
+type NotificationOutboxServiceInterface interface {
+	EnqueueTelegram(ctx context.Context, userID uint64, chatID int64, text string) error
+	// DeferTelegram откладывает сообщение до deliverAt; отложенные сообщения получателя,
+	// ставшие готовыми вместе, уходят одной сводкой на языке lang.
+	DeferTelegram(ctx context.Context, userID uint64, chatID int64, lang, text string, deliverAt time.Time) error
+	EnqueueWebSocket(ctx context.Context, userID uint64, payload interface{}, messageType string) error
+	StartWorker(ctx context.Context)
+	ProcessDue(ctx context.Context) (int, error)
+
+	GetDeadLetters(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.NotificationOutboxItemDTO], error)
+	Requeue(ctx context.Context, id uint64) error
+}
+
+type NotificationOutboxService struct {
+	repo                  repositories.NotificationOutboxRepositoryInterface
+	notificationService   NotificationServiceInterface
+	wsNotificationService WebSocketNotificationServiceInterface
+	userRepo              repositories.UserRepositoryInterface
+	logger                *zap.Logger
+	wakeup                chan struct{}
+}
+
+func NewNotificationOutboxService(
+	repo repositories.NotificationOutboxRepositoryInterface,
+	notificationService NotificationServiceInterface,
+	wsNotificationService WebSocketNotificationServiceInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) NotificationOutboxServiceInterface {
+	return &NotificationOutboxService{
+		repo:                  repo,
+		notificationService:   notificationService,
+		wsNotificationService: wsNotificationService,
+		userRepo:              userRepo,
+		logger:                logger,
+		wakeup:                make(chan struct{}, 1),
+	}
+}
+
+type telegramOutboxPayload struct {
+	Text string `json:"text"`
+
+	Lang string `json:"lang,omitempty"`
+}
+
+func (s *NotificationOutboxService) EnqueueTelegram(ctx context.Context, userID uint64, chatID int64, text string) error {
+	payload, err := json.Marshal(telegramOutboxPayload{Text: text})
+	if err != nil {
+		return err
+	}
+	item := &entities.NotificationOutboxItem{
+		UserID:      &userID,
+		Channel:     entities.NotificationChannelTelegram,
+		ChatID:      &chatID,
+		MessageType: "notification",
+		Payload:     payload,
+		MaxAttempts: outboxDefaultRetries,
+	}
+	if err := s.repo.Enqueue(ctx, item); err != nil {
+		// Если БД недоступна, не теряем уведомление молча — пробуем отправить напрямую.
+		s.logger.Error("Не удалось записать Telegram-уведомление в outbox, отправляем напрямую", zap.Uint64("userID", userID), zap.Error(err))
+		return s.notificationService.SendFormattedMessage(ctx, chatID, text)
+	}
+	s.notify()
+	return nil
+}
+
+func (s *NotificationOutboxService) DeferTelegram(ctx context.Context, userID uint64, chatID int64, lang, text string, deliverAt time.Time) error {
+	payload, err := json.Marshal(telegramOutboxPayload{Text: text, Lang: lang})
+	if err != nil {
+		return err
+	}
+	item := &entities.NotificationOutboxItem{
+		UserID:        &userID,
+		Channel:       entities.NotificationChannelTelegram,
+		ChatID:        &chatID,
+		MessageType:   entities.OutboxMessageDigest,
+		Payload:       payload,
+		MaxAttempts:   outboxDefaultRetries,
+		NextAttemptAt: deliverAt,
+	}
+	if err := s.repo.Enqueue(ctx, item); err != nil {
+		// Как и в EnqueueTelegram: лучше отправить раньше срока, чем потерять уведомление.
+		s.logger.Error("Не удалось отложить Telegram-уведомление в outbox, отправляем напрямую", zap.Uint64("userID", userID), zap.Error(err))
+		return s.notificationService.SendFormattedMessage(ctx, chatID, text)
+	}
+	return nil
+}
+
+func (s *NotificationOutboxService) EnqueueWebSocket(ctx context.Context, userID uint64, payload interface{}, messageType string) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	item := &entities.NotificationOutboxItem{
+		UserID:      &userID,
+		Channel:     entities.NotificationChannelWebSocket,
+		MessageType: messageType,
+		Payload:     raw,
+		MaxAttempts: outboxDefaultRetries,
+	}
+	if err := s.repo.Enqueue(ctx, item); err != nil {
+		s.logger.Error("Не удалось записать WebSocket-уведомление в outbox, отправляем напрямую", zap.Uint64("userID", userID), zap.Error(err))
+		return s.wsNotificationService.SendNotification(userID, json.RawMessage(raw), messageType)
+	}
+	s.notify()
+	return nil
+}
+
+// notify будит воркер, чтобы новое уведомление ушло сразу, а не на следующем тике.
+func (s *NotificationOutboxService) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *NotificationOutboxService) StartWorker(ctx context.Context) {
+	s.logger.Info("Воркер outbox уведомлений запущен")
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Воркер outbox уведомлений остановлен")
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+
+		for {
+			processed, err := s.ProcessDue(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Error("Ошибка обработки outbox уведомлений", zap.Error(err))
+				}
+				break
+			}
+			if processed < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (s *NotificationOutboxService) ProcessDue(ctx context.Context) (int, error) {
+	items, err := s.repo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, batch := range groupOutboxDigests(items) {
+		var deliverErr error
+		if batch[0].MessageType == entities.OutboxMessageDigest {
+			deliverErr = s.deliverDigest(ctx, batch)
+		} else {
+			deliverErr = s.deliver(ctx, batch[0])
+		}
+		for _, item := range batch {
+			s.settle(ctx, item, deliverErr)
+		}
+	}
+
+	return len(items), nil
+}
+
+// settle записывает результат доставки: отправлено, повтор с задержкой или dead-letter.
+func (s *NotificationOutboxService) settle(ctx context.Context, item *entities.NotificationOutboxItem, deliverErr error) {
+	if deliverErr == nil {
+		if err := s.repo.MarkSent(ctx, item.ID); err != nil {
+			s.logger.Error("Не удалось отметить уведомление отправленным", zap.Uint64("outboxID", item.ID), zap.Error(err))
+		}
+		return
+	}
+
+	// Упор в лимиты бота не расходует попытки: уведомление дождётся, пока Telegram снова примет его.
+	if telegram.IsPermanentError(deliverErr) || item.Attempts >= item.MaxAttempts && !telegram.IsThrottled(deliverErr) {
+		s.logger.Warn("Уведомление перемещено в dead-letter",
+			zap.Uint64("outboxID", item.ID),
+			zap.String("channel", item.Channel),
+			zap.Int("attempts", item.Attempts),
+			zap.Error(deliverErr))
+		if err := s.repo.MarkDead(ctx, item.ID, deliverErr.Error()); err != nil {
+			s.logger.Error("Не удалось перевести уведомление в dead-letter", zap.Uint64("outboxID", item.ID), zap.Error(err))
+		}
+		return
+	}
+
+	next := time.Now().Add(outboxBackoff(item.Attempts))
+	if err := s.repo.MarkRetry(ctx, item.ID, next, deliverErr.Error()); err != nil {
+		s.logger.Error("Не удалось запланировать повтор уведомления", zap.Uint64("outboxID", item.ID), zap.Error(err))
+	}
+}
+
+// groupOutboxDigests делит выбранные записи на отправки: отложенные сообщения одного чата
+// собираются в одну отправку на месте первого из них, остальные записи идут по одной.
+func groupOutboxDigests(items []entities.NotificationOutboxItem) [][]*entities.NotificationOutboxItem {
+	batches := make([][]*entities.NotificationOutboxItem, 0, len(items))
+	digestIndex := make(map[int64]int)
+	for i := range items {
+		item := &items[i]
+		if item.MessageType != entities.OutboxMessageDigest || item.ChatID == nil {
+			batches = append(batches, []*entities.NotificationOutboxItem{item})
+			continue
+		}
+		if idx, ok := digestIndex[*item.ChatID]; ok {
+			batches[idx] = append(batches[idx], item)
+			continue
+		}
+		digestIndex[*item.ChatID] = len(batches)
+		batches = append(batches, []*entities.NotificationOutboxItem{item})
+	}
+	return batches
+}
+
+// deliverDigest отправляет отложенные сообщения одного чата сводкой. При ошибке повторяется
+// вся сводка: уже ушедшие части могут прийти ещё раз, но ни одно сообщение не теряется.
+func (s *NotificationOutboxService) deliverDigest(ctx context.Context, batch []*entities.NotificationOutboxItem) error {
+	chatID := batch[0].ChatID
+	if chatID == nil || *chatID == 0 {
+		return &telegram.APIError{Method: "sendMessage", Code: 400, Description: "chat id is empty"}
+	}
+	var lang string
+	texts := make([]string, 0, len(batch))
+	for _, item := range batch {
+		var payload telegramOutboxPayload
+		if err := json.Unmarshal(item.Payload, &payload); err != nil {
+			return &telegram.APIError{Method: "sendMessage", Code: 400, Description: "invalid outbox payload"}
+		}
+		lang = payload.Lang
+		texts = append(texts, payload.Text)
+	}
+	for _, chunk := range buildDigestMessages(lang, texts) {
+		if err := s.notificationService.SendFormattedMessage(ctx, *chatID, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDigestMessages склеивает отложенные сообщения, разбивая результат под лимит длины Telegram.
+func buildDigestMessages(lang string, messages []string) []string {
+	if len(messages) == 1 {
+		return messages
+	}
+
+	header := i18n.T(lang, "notify.digest_header", len(messages))
+	var result []string
+	var sb strings.Builder
+	sb.WriteString(header)
+	for _, msg := range messages {
+		if sb.Len() > len(header) && sb.Len()+len(msg)+2 > digestMaxMessageLen {
+			result = append(result, sb.String())
+			sb.Reset()
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(msg)
+	}
+	if sb.Len() > 0 {
+		result = append(result, sb.String())
+	}
+	return result
+}
+
+// outboxBackoff — экспоненциальная задержка: 5с, 10с, 20с ... но не больше 30 минут.
+func outboxBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := outboxBaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+func (s *NotificationOutboxService) deliver(ctx context.Context, item *entities.NotificationOutboxItem) error {
+	switch item.Channel {
+	case entities.NotificationChannelTelegram:
+		if item.ChatID == nil || *item.ChatID == 0 {
+			return &telegram.APIError{Method: "sendMessage", Code: 400, Description: "chat id is empty"}
+		}
+		var payload telegramOutboxPayload
+		if err := json.Unmarshal(item.Payload, &payload); err != nil {
+			return &telegram.APIError{Method: "sendMessage", Code: 400, Description: "invalid outbox payload"}
+		}
+		return s.notificationService.SendFormattedMessage(ctx, *item.ChatID, payload.Text)
+	case entities.NotificationChannelWebSocket:
+		if item.UserID == nil {
+			return fmt.Errorf("outbox %d: не указан получатель WebSocket-уведомления", item.ID)
+		}
+		return s.wsNotificationService.SendNotification(*item.UserID, item.Payload, item.MessageType)
+	default:
+		return fmt.Errorf("outbox %d: неизвестный канал %q", item.ID, item.Channel)
+	}
+}
+
+func toNotificationOutboxItemDTO(e *entities.NotificationOutboxItem) dto.NotificationOutboxItemDTO {
+	return dto.NotificationOutboxItemDTO{
+		ID:            e.ID,
+		UserID:        e.UserID,
+		Channel:       e.Channel,
+		ChatID:        e.ChatID,
+		MessageType:   e.MessageType,
+		Payload:       e.Payload,
+		Status:        e.Status,
+		Attempts:      e.Attempts,
+		MaxAttempts:   e.MaxAttempts,
+		NextAttemptAt: e.NextAttemptAt.Format(time.RFC3339),
+		LastError:     e.LastError,
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *NotificationOutboxService) GetDeadLetters(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.NotificationOutboxItemDTO], error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.NotificationsManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	items, total, err := s.repo.FindDead(ctx, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.NotificationOutboxItemDTO, 0, len(items))
+	for i := range items {
+		list = append(list, toNotificationOutboxItemDTO(&items[i]))
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+
+	return &dto.PaginatedResponse[dto.NotificationOutboxItemDTO]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
+
+func (s *NotificationOutboxService) Requeue(ctx context.Context, id uint64) error {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return err
+	}
+	if !authz.CanDo(authz.NotificationsManage, *authContext) {
+		return apperrors.ErrForbidden
+	}
+
+	if err := s.repo.Requeue(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Уведомление возвращено из dead-letter в очередь", zap.Uint64("outboxID", id), zap.Uint64("by", authContext.Actor.ID))
+	s.notify()
+	return nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/telegram"
+)
+
+type outboxRepoStub struct {
+	repositories.NotificationOutboxRepositoryInterface
+	due     []entities.NotificationOutboxItem
+	sent    []uint64
+	retried []uint64
+	dead    []uint64
+}
+
+func (r *outboxRepoStub) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.NotificationOutboxItem, error) {
+	items := r.due
+	r.due = nil
+	return items, nil
+}
+
+func (r *outboxRepoStub) MarkSent(ctx context.Context, id uint64) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+func (r *outboxRepoStub) MarkRetry(ctx context.Context, id uint64, nextAttemptAt time.Time, lastError string) error {
+	r.retried = append(r.retried, id)
+	return nil
+}
+
+func (r *outboxRepoStub) MarkDead(ctx context.Context, id uint64, lastError string) error {
+	r.dead = append(r.dead, id)
+	return nil
+}
+
+type telegramSenderStub struct {
+	NotificationServiceInterface
+	errs     map[int64]error
+	messages map[int64][]string
+}
+
+func (s *telegramSenderStub) SendFormattedMessage(ctx context.Context, chatID int64, message string) error {
+	if s.messages != nil {
+		s.messages[chatID] = append(s.messages[chatID], message)
+	}
+	return s.errs[chatID]
+}
+
+func TestNotificationOutboxProcessDue_RetriesTransientAndBuriesPermanentErrors(t *testing.T) {
+	payload, _ := json.Marshal(telegramOutboxPayload{Text: "hi"})
+	chat := func(id int64) *int64 { return &id }
+
+	repo := &outboxRepoStub{due: []entities.NotificationOutboxItem{
+		{ID: 1, Channel: entities.NotificationChannelTelegram, ChatID: chat(10), Payload: payload, Attempts: 1, MaxAttempts: 8},
+		{ID: 2, Channel: entities.NotificationChannelTelegram, ChatID: chat(20), Payload: payload, Attempts: 1, MaxAttempts: 8},
+		{ID: 3, Channel: entities.NotificationChannelTelegram, ChatID: chat(30), Payload: payload, Attempts: 1, MaxAttempts: 8},
+		{ID: 4, Channel: entities.NotificationChannelTelegram, ChatID: chat(20), Payload: payload, Attempts: 8, MaxAttempts: 8},
+		{ID: 5, Channel: entities.NotificationChannelTelegram, ChatID: chat(40), Payload: payload, Attempts: 8, MaxAttempts: 8},
+	}}
+	sender := &telegramSenderStub{errs: map[int64]error{
+		20: errors.New("connection reset"),
+		30: &telegram.APIError{Method: "sendMessage", Code: 403, Description: "bot was blocked by the user"},
+		40: telegram.ErrCircuitOpen,
+	}}
+
+	service := NewNotificationOutboxService(repo, sender, nil, nil, zap.NewNop())
+	processed, err := service.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if processed != 5 {
+		t.Fatalf("expected 5 processed items, got %d", processed)
+	}
+	if len(repo.sent) != 1 || repo.sent[0] != 1 {
+		t.Fatalf("expected item 1 to be sent, got %v", repo.sent)
+	}
+	if len(repo.retried) != 2 || repo.retried[0] != 2 || repo.retried[1] != 5 {
+		t.Fatalf("expected items 2 and 5 to be retried, got %v", repo.retried)
+	}
+	if len(repo.dead) != 2 || repo.dead[0] != 3 || repo.dead[1] != 4 {
+		t.Fatalf("expected items 3 and 4 in dead-letter, got %v", repo.dead)
+	}
+}
+
+func TestNotificationOutboxProcessDue_SendsDeferredMessagesAsOneDigest(t *testing.T) {
+	text := func(text, lang string) []byte {
+		payload, _ := json.Marshal(telegramOutboxPayload{Text: text, Lang: lang})
+		return payload
+	}
+	chat := func(id int64) *int64 { return &id }
+
+	repo := &outboxRepoStub{due: []entities.NotificationOutboxItem{
+		{ID: 1, Channel: entities.NotificationChannelTelegram, ChatID: chat(10), MessageType: entities.OutboxMessageDigest, Payload: text("first", "en"), Attempts: 1, MaxAttempts: 8},
+		{ID: 2, Channel: entities.NotificationChannelTelegram, ChatID: chat(10), MessageType: "notification", Payload: text("now", ""), Attempts: 1, MaxAttempts: 8},
+		{ID: 3, Channel: entities.NotificationChannelTelegram, ChatID: chat(20), MessageType: entities.OutboxMessageDigest, Payload: text("other", "en"), Attempts: 1, MaxAttempts: 8},
+		{ID: 4, Channel: entities.NotificationChannelTelegram, ChatID: chat(10), MessageType: entities.OutboxMessageDigest, Payload: text("second", "en"), Attempts: 1, MaxAttempts: 8},
+		{ID: 5, Channel: entities.NotificationChannelTelegram, ChatID: chat(30), MessageType: entities.OutboxMessageDigest, Payload: text("a", "en"), Attempts: 1, MaxAttempts: 8},
+		{ID: 6, Channel: entities.NotificationChannelTelegram, ChatID: chat(30), MessageType: entities.OutboxMessageDigest, Payload: text("b", "en"), Attempts: 1, MaxAttempts: 8},
+	}}
+	sender := &telegramSenderStub{
+		errs:     map[int64]error{30: errors.New("connection reset")},
+		messages: map[int64][]string{},
+	}
+
+	service := NewNotificationOutboxService(repo, sender, nil, nil, zap.NewNop())
+	if _, err := service.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantDigest := "🗂 *Notification digest* \\(2\\)\n\nfirst\n\nsecond"
+	if got := sender.messages[10]; len(got) != 2 || got[0] != wantDigest || got[1] != "now" {
+		t.Fatalf("unexpected messages for chat 10: %q", got)
+	}
+	if got := sender.messages[20]; len(got) != 1 || got[0] != "other" {
+		t.Fatalf("a single deferred message must be sent as is, got %q", got)
+	}
+	if len(repo.sent) != 4 || repo.sent[0] != 1 || repo.sent[1] != 4 || repo.sent[2] != 2 || repo.sent[3] != 3 {
+		t.Fatalf("expected items 1, 4, 2 and 3 to be sent, got %v", repo.sent)
+	}
+	// Сбой сводки откладывает все её записи, чтобы при повторе они снова ушли вместе
+	if len(repo.retried) != 2 || repo.retried[0] != 5 || repo.retried[1] != 6 {
+		t.Fatalf("expected items 5 and 6 to be retried, got %v", repo.retried)
+	}
+}
+
+func TestOutboxBackoff_GrowsExponentiallyUpToCap(t *testing.T) {
+	if got := outboxBackoff(1); got != outboxBaseBackoff {
+		t.Fatalf("expected %v, got %v", outboxBaseBackoff, got)
+	}
+	if got := outboxBackoff(3); got != 4*outboxBaseBackoff {
+		t.Fatalf("expected %v, got %v", 4*outboxBaseBackoff, got)
+	}
+	if got := outboxBackoff(50); got != outboxMaxBackoff {
+		t.Fatalf("expected cap %v, got %v", outboxMaxBackoff, got)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// NotificationEventTypes — типы событий истории, которые пользователь может отключить.
+var NotificationEventTypes = []string{
+	"CREATE", "STATUS_CHANGE", "PRIORITY_CHANGE", "DELEGATION",
+	"COMMENT", "DURATION_CHANGE", "ATTACHMENT_ADD",
+}
+
+type NotificationPreferenceServiceInterface interface {
+	GetMyPreferences(ctx context.Context) (*dto.NotificationPreferenceDTO, error)
+	UpdateMyPreferences(ctx context.Context, d dto.UpdateNotificationPreferenceDTO) (*dto.NotificationPreferenceDTO, error)
+}
+
+type NotificationPreferenceService struct {
+	repo   repositories.NotificationPreferenceRepositoryInterface
+	logger *zap.Logger
+}
+
+func NewNotificationPreferenceService(
+	repo repositories.NotificationPreferenceRepositoryInterface,
+	logger *zap.Logger,
+) NotificationPreferenceServiceInterface {
+	return &NotificationPreferenceService{repo: repo, logger: logger}
+}
+
+func formatMinutesOfDay(minutes *int) *string {
+	if minutes == nil {
+		return nil
+	}
+	s := fmt.Sprintf("%02d:%02d", *minutes/60, *minutes%60)
+	return &s
+}
+
+func parseMinutesOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func toNotificationPreferenceDTO(p *entities.NotificationPreference) *dto.NotificationPreferenceDTO {
+	res := &dto.NotificationPreferenceDTO{
+		TelegramEnabled:  p.TelegramEnabled,
+		WebSocketEnabled: p.WebSocketEnabled,
+		MutedEventTypes:  p.MutedEventTypes,
+		QuietHoursStart:  formatMinutesOfDay(p.QuietHoursStart),
+		QuietHoursEnd:    formatMinutesOfDay(p.QuietHoursEnd),
+		DeliveryMode:     p.DeliveryMode,
+	}
+	if res.MutedEventTypes == nil {
+		res.MutedEventTypes = []string{}
+	}
+	if !p.UpdatedAt.IsZero() {
+		res.UpdatedAt = p.UpdatedAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+func (s *NotificationPreferenceService) GetMyPreferences(ctx context.Context) (*dto.NotificationPreferenceDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pref, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Не удалось получить настройки уведомлений", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, err
+	}
+	return toNotificationPreferenceDTO(pref), nil
+}
+
+func (s *NotificationPreferenceService) UpdateMyPreferences(ctx context.Context, d dto.UpdateNotificationPreferenceDTO) (*dto.NotificationPreferenceDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pref, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.TelegramEnabled != nil {
+		pref.TelegramEnabled = *d.TelegramEnabled
+	}
+	if d.WebSocketEnabled != nil {
+		pref.WebSocketEnabled = *d.WebSocketEnabled
+	}
+	if d.DeliveryMode != nil {
+		pref.DeliveryMode = *d.DeliveryMode
+	}
+
+	if d.MutedEventTypes != nil {
+		muted := make([]string, 0, len(*d.MutedEventTypes))
+		seen := make(map[string]struct{})
+		for _, raw := range *d.MutedEventTypes {
+			eventType := strings.ToUpper(strings.TrimSpace(raw))
+			if !slices.Contains(NotificationEventTypes, eventType) {
+				return nil, apperrors.NewHttpError(http.StatusBadRequest,
+					fmt.Sprintf("Неизвестный тип события: %s", raw), nil, nil)
+			}
+			if _, ok := seen[eventType]; ok {
+				continue
+			}
+			seen[eventType] = struct{}{}
+			muted = append(muted, eventType)
+		}
+		pref.MutedEventTypes = muted
+	}
+
+	if d.DisableQuietHours {
+		pref.QuietHoursStart, pref.QuietHoursEnd = nil, nil
+	} else if d.QuietHoursStart != nil || d.QuietHoursEnd != nil {
+		if d.QuietHoursStart == nil || d.QuietHoursEnd == nil {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest,
+				"Тихие часы задаются парой quiet_hours_start и quiet_hours_end", nil, nil)
+		}
+		start, errStart := parseMinutesOfDay(*d.QuietHoursStart)
+		end, errEnd := parseMinutesOfDay(*d.QuietHoursEnd)
+		if errStart != nil || errEnd != nil {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest,
+				"Время тихих часов должно быть в формате ЧЧ:ММ", nil, nil)
+		}
+		pref.QuietHoursStart, pref.QuietHoursEnd = &start, &end
+	}
+
+	if err := s.repo.Upsert(ctx, pref); err != nil {
+		s.logger.Error("Не удалось сохранить настройки уведомлений", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, err
+	}
+	return toNotificationPreferenceDTO(pref), nil
+}
@@ -59,8 +59,8 @@ func (s *telegramNotificationService) SendFormattedMessage(ctx context.Context,
 	}
 	// Отправляем "как есть", доверяя вызывающему коду
 	err := s.tgService.SendMessageEx(ctx, chatID, message, telegram.WithMarkdownV2())
-	if err == nil {
-		return nil
+	if err == nil || telegram.IsThrottled(err) {
+		return err
 	}
 
 	s.logger.Warn("Telegram formatted notification failed, retrying as plain message",
@@ -63,7 +63,7 @@ func (s *OfficeService) buildAuthzContext(ctx context.Context) (*authz.Context,
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
 
 func officeEntityToDetailDTO(entity *entities.Office) *dto.OfficeDTO {
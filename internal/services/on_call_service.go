@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+const (
+	onCallSourceRotation = "rotation"
+	onCallSourceOverride = "override"
+)
+
+// OnCallServiceInterface ведёт графики дежурств департаментов. Правило маршрутизации
+// с графиком назначает заявку тому, кто дежурит в момент её создания.
+type OnCallServiceInterface interface {
+	GetSchedules(ctx context.Context, departmentID *uint64) ([]dto.OnCallScheduleDTO, error)
+	GetSchedule(ctx context.Context, id uint64) (*dto.OnCallScheduleDTO, error)
+	CreateSchedule(ctx context.Context, payload dto.CreateOnCallScheduleDTO) (*dto.OnCallScheduleDTO, error)
+	UpdateSchedule(ctx context.Context, id uint64, payload dto.UpdateOnCallScheduleDTO) (*dto.OnCallScheduleDTO, error)
+	DeleteSchedule(ctx context.Context, id uint64) error
+
+	// GetOverrides — текущие и будущие замены графика.
+	GetOverrides(ctx context.Context, scheduleID uint64) ([]dto.OnCallOverrideDTO, error)
+	CreateOverride(ctx context.Context, scheduleID uint64, payload dto.CreateOnCallOverrideDTO) (*dto.OnCallOverrideDTO, error)
+	DeleteOverride(ctx context.Context, scheduleID, overrideID uint64) error
+
+	// GetOnCallNow — дежурные по активным графикам департамента (или всех департаментов).
+	GetOnCallNow(ctx context.Context, departmentID *uint64) ([]dto.OnCallNowDTO, error)
+}
+
+type OnCallService struct {
+	repo     repositories.OnCallRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func NewOnCallService(
+	repo repositories.OnCallRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) OnCallServiceInterface {
+	return &OnCallService{repo: repo, userRepo: userRepo, logger: logger, now: time.Now}
+}
+
+// onCallShift — кто дежурит в заданный момент и до какого времени.
+type onCallShift struct {
+	UserID   uint64
+	Fio      string
+	Override *entities.OnCallOverride
+	Until    time.Time
+}
+
+// currentOnCall вычисляет дежурного на момент at. Замена, покрывающая at, важнее ротации;
+// из нескольких пересекающихся замен действует созданная последней. Иначе дежурит участник
+// с номером floor((at - rotation_start) / shift) по модулю длины очереди, а смена
+// заканчивается раньше, если до её конца начинается замена. false — дежурить некому.
+func currentOnCall(schedule entities.OnCallSchedule, members []entities.OnCallMember, overrides []entities.OnCallOverride, at time.Time) (onCallShift, bool) {
+	var active *entities.OnCallOverride
+	for i := range overrides {
+		o := &overrides[i]
+		if at.Before(o.StartsAt) || !at.Before(o.EndsAt) {
+			continue
+		}
+		if active == nil || o.CreatedAt.After(active.CreatedAt) || (o.CreatedAt.Equal(active.CreatedAt) && o.ID > active.ID) {
+			active = o
+		}
+	}
+	if active != nil {
+		return onCallShift{UserID: active.UserID, Fio: active.Fio, Override: active, Until: active.EndsAt}, true
+	}
+	if len(members) == 0 || schedule.ShiftHours <= 0 {
+		return onCallShift{}, false
+	}
+
+	shift := time.Duration(schedule.ShiftHours) * time.Hour
+	elapsed := at.Sub(schedule.RotationStart)
+	index := int64(elapsed / shift)
+	if elapsed < 0 && elapsed%shift != 0 {
+		index--
+	}
+	n := int64(len(members))
+	member := members[((index%n)+n)%n]
+
+	until := schedule.RotationStart.Add(time.Duration(index+1) * shift)
+	for _, o := range overrides {
+		if o.StartsAt.After(at) && o.StartsAt.Before(until) {
+			until = o.StartsAt
+		}
+	}
+	return onCallShift{UserID: member.UserID, Fio: member.Fio, Until: until}, true
+}
+
+// findOnCall загружает очередь и замены графика и вычисляет дежурного на момент at.
+func findOnCall(ctx context.Context, repo repositories.OnCallRepositoryInterface, schedule entities.OnCallSchedule, at time.Time) (onCallShift, bool, error) {
+	members, err := repo.FindMembers(ctx, schedule.ID)
+	if err != nil {
+		return onCallShift{}, false, err
+	}
+	overrides, err := repo.FindOverrides(ctx, schedule.ID, at)
+	if err != nil {
+		return onCallShift{}, false, err
+	}
+	shift, ok := currentOnCall(schedule, members, overrides, at)
+	return shift, ok, nil
+}
+
+func (s *OnCallService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func (s *OnCallService) GetSchedules(ctx context.Context, departmentID *uint64) ([]dto.OnCallScheduleDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OnCallView); err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.FindSchedules(ctx, departmentID, false)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.OnCallScheduleDTO, 0, len(schedules))
+	for _, schedule := range schedules {
+		members, err := s.repo.FindMembers(ctx, schedule.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, toOnCallScheduleDTO(schedule, members))
+	}
+	return result, nil
+}
+
+func (s *OnCallService) GetSchedule(ctx context.Context, id uint64) (*dto.OnCallScheduleDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OnCallView); err != nil {
+		return nil, err
+	}
+	return s.loadSchedule(ctx, id)
+}
+
+func (s *OnCallService) CreateSchedule(ctx context.Context, payload dto.CreateOnCallScheduleDTO) (*dto.OnCallScheduleDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.OnCallManage)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указано название графика", nil, nil)
+	}
+
+	schedule := &entities.OnCallSchedule{
+		Name:          name,
+		DepartmentID:  payload.DepartmentID,
+		RotationStart: payload.RotationStart,
+		ShiftHours:    payload.ShiftHours,
+		IsActive:      payload.IsActive == nil || *payload.IsActive,
+	}
+	if err := s.repo.CreateSchedule(ctx, schedule, uniqueUserIDs(payload.MemberIDs)); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Создан график дежурств",
+		zap.Uint64("scheduleID", schedule.ID), zap.Uint64("departmentID", schedule.DepartmentID), zap.Uint64("by", authContext.Actor.ID))
+	return s.loadSchedule(ctx, schedule.ID)
+}
+
+func (s *OnCallService) UpdateSchedule(ctx context.Context, id uint64, payload dto.UpdateOnCallScheduleDTO) (*dto.OnCallScheduleDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OnCallManage); err != nil {
+		return nil, err
+	}
+	schedule, err := s.repo.FindScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Name != nil {
+		name := strings.TrimSpace(*payload.Name)
+		if name == "" {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указано название графика", nil, nil)
+		}
+		schedule.Name = name
+	}
+	if payload.DepartmentID != nil {
+		schedule.DepartmentID = *payload.DepartmentID
+	}
+	if payload.RotationStart != nil {
+		schedule.RotationStart = *payload.RotationStart
+	}
+	if payload.ShiftHours != nil {
+		schedule.ShiftHours = *payload.ShiftHours
+	}
+	if payload.IsActive != nil {
+		schedule.IsActive = *payload.IsActive
+	}
+
+	var memberIDs []uint64
+	if payload.MemberIDs != nil {
+		memberIDs = uniqueUserIDs(payload.MemberIDs)
+	}
+	if err := s.repo.UpdateSchedule(ctx, schedule, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.loadSchedule(ctx, id)
+}
+
+func (s *OnCallService) DeleteSchedule(ctx context.Context, id uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.OnCallManage)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSchedule(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалён график дежурств", zap.Uint64("scheduleID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *OnCallService) GetOverrides(ctx context.Context, scheduleID uint64) ([]dto.OnCallOverrideDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OnCallView); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindScheduleByID(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	overrides, err := s.repo.FindOverrides(ctx, scheduleID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.OnCallOverrideDTO, 0, len(overrides))
+	for _, o := range overrides {
+		result = append(result, toOnCallOverrideDTO(o))
+	}
+	return result, nil
+}
+
+func (s *OnCallService) CreateOverride(ctx context.Context, scheduleID uint64, payload dto.CreateOnCallOverrideDTO) (*dto.OnCallOverrideDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.OnCallManage)
+	if err != nil {
+		return nil, err
+	}
+	if !payload.EndsAt.After(payload.StartsAt) {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Окончание замены должно быть позже её начала", nil, nil)
+	}
+	if !payload.EndsAt.After(s.now()) {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Замена уже закончилась", nil, nil)
+	}
+	if _, err := s.repo.FindScheduleByID(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindUserByID(ctx, payload.UserID)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Сотрудник для замены не найден", err, nil)
+	}
+
+	actorID := authContext.Actor.ID
+	override := &entities.OnCallOverride{
+		ScheduleID: scheduleID,
+		UserID:     user.ID,
+		Fio:        user.Fio,
+		StartsAt:   payload.StartsAt,
+		EndsAt:     payload.EndsAt,
+		Reason:     payload.Reason,
+		CreatedBy:  &actorID,
+	}
+	if err := s.repo.CreateOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Добавлена замена в графике дежурств",
+		zap.Uint64("scheduleID", scheduleID), zap.Uint64("userID", user.ID), zap.Time("from", payload.StartsAt), zap.Time("to", payload.EndsAt))
+	result := toOnCallOverrideDTO(*override)
+	return &result, nil
+}
+
+func (s *OnCallService) DeleteOverride(ctx context.Context, scheduleID, overrideID uint64) error {
+	if _, err := s.checkPermission(ctx, authz.OnCallManage); err != nil {
+		return err
+	}
+	return s.repo.DeleteOverride(ctx, scheduleID, overrideID)
+}
+
+func (s *OnCallService) GetOnCallNow(ctx context.Context, departmentID *uint64) ([]dto.OnCallNowDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OnCallView); err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.FindSchedules(ctx, departmentID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	location := utils.LocationFromCtx(ctx)
+	result := make([]dto.OnCallNowDTO, 0, len(schedules))
+	for _, schedule := range schedules {
+		item := dto.OnCallNowDTO{
+			ScheduleID:     schedule.ID,
+			ScheduleName:   schedule.Name,
+			DepartmentID:   schedule.DepartmentID,
+			DepartmentName: schedule.DepartmentName,
+		}
+		shift, ok, err := findOnCall(ctx, s.repo, schedule, now)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			userID, fio := shift.UserID, shift.Fio
+			until := shift.Until.In(location).Format(time.RFC3339)
+			item.UserID, item.Fio, item.Until = &userID, &fio, &until
+			item.Source = onCallSourceRotation
+			if shift.Override != nil {
+				item.Source = onCallSourceOverride
+				item.Reason = shift.Override.Reason
+			}
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (s *OnCallService) loadSchedule(ctx context.Context, id uint64) (*dto.OnCallScheduleDTO, error) {
+	schedule, err := s.repo.FindScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.FindMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := toOnCallScheduleDTO(*schedule, members)
+	return &result, nil
+}
+
+// uniqueUserIDs убирает повторы, сохраняя порядок: порядок задаёт очередь ротации.
+func uniqueUserIDs(ids []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(ids))
+	result := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+func toOnCallScheduleDTO(schedule entities.OnCallSchedule, members []entities.OnCallMember) dto.OnCallScheduleDTO {
+	result := dto.OnCallScheduleDTO{
+		ID:             schedule.ID,
+		Name:           schedule.Name,
+		DepartmentID:   schedule.DepartmentID,
+		DepartmentName: schedule.DepartmentName,
+		RotationStart:  schedule.RotationStart.Format(time.RFC3339),
+		ShiftHours:     schedule.ShiftHours,
+		IsActive:       schedule.IsActive,
+		Members:        make([]dto.OnCallMemberDTO, 0, len(members)),
+		CreatedAt:      schedule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      schedule.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, m := range members {
+		result.Members = append(result.Members, dto.OnCallMemberDTO{UserID: m.UserID, Fio: m.Fio, Position: m.Position})
+	}
+	return result
+}
+
+func toOnCallOverrideDTO(o entities.OnCallOverride) dto.OnCallOverrideDTO {
+	return dto.OnCallOverrideDTO{
+		ID:         o.ID,
+		ScheduleID: o.ScheduleID,
+		UserID:     o.UserID,
+		Fio:        o.Fio,
+		StartsAt:   o.StartsAt.Format(time.RFC3339),
+		EndsAt:     o.EndsAt.Format(time.RFC3339),
+		Reason:     o.Reason,
+		CreatedBy:  o.CreatedBy,
+		CreatedAt:  o.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"request-system/internal/entities"
+)
+
+func TestCurrentOnCallRotatesAndHonoursOverrides(t *testing.T) {
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	schedule := entities.OnCallSchedule{ID: 1, RotationStart: start, ShiftHours: 12}
+	members := []entities.OnCallMember{
+		{UserID: 10, Fio: "Первый", Position: 1},
+		{UserID: 20, Fio: "Второй", Position: 2},
+		{UserID: 30, Fio: "Третий", Position: 3},
+	}
+	override := entities.OnCallOverride{
+		ID: 5, UserID: 99, Fio: "Замена",
+		StartsAt:  start.Add(40 * time.Hour),
+		EndsAt:    start.Add(44 * time.Hour),
+		CreatedAt: start,
+	}
+	later := override
+	later.ID, later.UserID, later.CreatedAt = 6, 77, start.Add(time.Hour)
+
+	cases := []struct {
+		name      string
+		at        time.Time
+		overrides []entities.OnCallOverride
+		wantUser  uint64
+		wantUntil time.Time
+		override  bool
+	}{
+		{"first shift", start.Add(time.Hour), nil, 10, start.Add(12 * time.Hour), false},
+		{"shift boundary belongs to the next member", start.Add(12 * time.Hour), nil, 20, start.Add(24 * time.Hour), false},
+		{"queue wraps around", start.Add(37 * time.Hour), nil, 10, start.Add(48 * time.Hour), false},
+		{"before rotation start counts backwards", start.Add(-time.Hour), nil, 30, start, false},
+		{"override wins over rotation", start.Add(41 * time.Hour), []entities.OnCallOverride{override}, 99, start.Add(44 * time.Hour), true},
+		{"shift ends where an override starts", start.Add(37 * time.Hour), []entities.OnCallOverride{override}, 10, start.Add(40 * time.Hour), false},
+		{"override end returns to rotation", start.Add(44 * time.Hour), []entities.OnCallOverride{override}, 10, start.Add(48 * time.Hour), false},
+		{"latest override wins", start.Add(41 * time.Hour), []entities.OnCallOverride{later, override}, 77, start.Add(44 * time.Hour), true},
+	}
+	for _, tc := range cases {
+		shift, ok := currentOnCall(schedule, members, tc.overrides, tc.at)
+		if !ok {
+			t.Fatalf("%s: nobody is on call", tc.name)
+		}
+		if shift.UserID != tc.wantUser || !shift.Until.Equal(tc.wantUntil) || (shift.Override != nil) != tc.override {
+			t.Errorf("%s: got user %d until %s (override %v), want %d until %s", tc.name,
+				shift.UserID, shift.Until, shift.Override != nil, tc.wantUser, tc.wantUntil)
+		}
+	}
+
+	if _, ok := currentOnCall(schedule, nil, nil, start); ok {
+		t.Error("a schedule without members must have nobody on call")
+	}
+}
@@ -3,13 +3,16 @@ package services
 import (
 	"context"
 	"mime/multipart"
+	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
 	"request-system/internal/authz"
 	"request-system/internal/dto"
 	"request-system/internal/entities"
 	"request-system/internal/repositories"
+	"request-system/pkg/config"
 	"request-system/pkg/contextkeys"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/eventbus"
@@ -18,19 +21,13 @@ import (
 	"request-system/pkg/utils"
 )
 
-type ValidationRule struct {
-	FieldName    string
-	ErrorMessage string
-}
-
 type orderFieldPermissionSpec struct {
 	Permission string
 	Label      string
 }
 
-var OrderValidationRules = map[string][]string{
-	"EQUIPMENT": {"equipment_id", "equipment_type_id", "priority_id"},
-}
+// orderCustomFieldsPatchKey — ключ патча с дополнительными полями; отдельного права на него нет.
+const orderCustomFieldsPatchKey = "custom_fields"
 
 var orderUpdateFieldPermissions = map[string]orderFieldPermissionSpec{
 	"name":              {Permission: authz.OrdersUpdateName, Label: "название заявки"},
@@ -44,8 +41,13 @@ var orderUpdateFieldPermissions = map[string]orderFieldPermissionSpec{
 	"executor_id":       {Permission: authz.OrdersUpdateExecutorID, Label: "исполнитель"},
 	"status_id":         {Permission: authz.OrdersUpdateStatusID, Label: "статус"},
 	"priority_id":       {Permission: authz.OrdersUpdatePriorityID, Label: "приоритет"},
+	"impact":            {Permission: authz.OrdersUpdatePriorityID, Label: "влияние"},
+	"urgency":           {Permission: authz.OrdersUpdatePriorityID, Label: "срочность"},
 	"duration":          {Permission: authz.OrdersUpdateDuration, Label: "срок"},
 	"comment":           {Permission: authz.OrdersUpdateComment, Label: "комментарий"},
+
+	// Код решения указывают при закрытии заявки, поэтому право то же, что и на смену статуса.
+	"resolution_code_id": {Permission: authz.OrdersUpdateStatusID, Label: "код решения"},
 }
 
 var orderCreateFieldPermissions = map[string]orderFieldPermissionSpec{
@@ -63,33 +65,49 @@ var orderCreateFieldPermissions = map[string]orderFieldPermissionSpec{
 	"comment":           {Permission: authz.OrdersCreateComment, Label: "комментарий"},
 }
 
-var ValidationRegistry = map[string][]ValidationRule{
-	"EQUIPMENT": {
-		{FieldName: "equipment_id", ErrorMessage: "Пожалуйста, укажите оборудование."},
-		{FieldName: "equipment_type_id", ErrorMessage: "Пожалуйста, выберите тип оборудования."},
-		{FieldName: "priority_id", ErrorMessage: "Пожалуйста, укажите приоритет."},
-	},
-}
-
 type OrderServiceInterface interface {
 	CreateOrder(ctx context.Context, createDTO dto.CreateOrderDTO, file *multipart.FileHeader) (*dto.OrderResponseDTO, error)
 	GetOrders(ctx context.Context, filter types.Filter, onlyCreated bool, onlyAssigned bool, onlyInvolved bool) (*dto.OrderListResponseDTO, error)
+	// ApplyOrderQuery дополняет filter условиями строки поиска вида «status:OPEN executor:me».
+	ApplyOrderQuery(ctx context.Context, filter *types.Filter, query string) error
+	ExportOrders(ctx context.Context, filter types.Filter, onlyCreated, onlyAssigned, onlyInvolved bool, write func([]dto.OrderExportRowDTO) error) (uint64, error)
+	// FindOrderSummary — одна заявка с названиями вместо ID, как строка выгрузки; для печатного акта.
+	FindOrderSummary(ctx context.Context, orderID uint64) (*dto.OrderExportRowDTO, error)
 	FindOrderByID(ctx context.Context, orderID uint64) (*dto.OrderResponseDTO, error)
 	UpdateOrder(ctx context.Context, orderID uint64, updateDTO dto.UpdateOrderDTO, file *multipart.FileHeader, explicitFields map[string]interface{}) (*dto.OrderResponseDTO, error)
 	DeleteOrder(ctx context.Context, orderID uint64) error
+	AttachFileToOrder(ctx context.Context, orderID uint64, fileName, fileType string, content []byte) (*dto.OrderResponseDTO, error)
 
 	GetStatusByID(ctx context.Context, id uint64) (*entities.Status, error)
 	GetPriorityByID(ctx context.Context, id uint64) (*entities.Priority, error)
 	GetUserStats(ctx context.Context, userID uint64) (*types.UserOrderStats, error)
+	GetUnitStats(ctx context.Context) (*types.UserOrderStats, error)
+	// GetValidationConfigForOrderType — обязательные при создании поля типа заявки: поле → текст ошибки.
 	GetValidationConfigForOrderType(ctx context.Context, orderTypeID uint64) (map[string]interface{}, error)
+	// IsCommentRequiredOnUpdate — нужен ли комментарий к каждому изменению заявки этого типа.
+	IsCommentRequiredOnUpdate(ctx context.Context, orderTypeID uint64) (bool, error)
 	FindOrderByIDForTelegram(ctx context.Context, userID uint64, orderID uint64) (*entities.Order, error)
+
+	MergeOrder(ctx context.Context, orderID uint64, mergeDTO dto.MergeOrderDTO) (*dto.OrderResponseDTO, error)
+	FindPossibleDuplicates(ctx context.Context, name string, equipmentID, excludeID uint64) ([]dto.OrderDuplicateCandidateDTO, error)
+	ClaimOrder(ctx context.Context, orderID uint64) (*dto.OrderResponseDTO, error)
+	// ClaimNextOrder забирает на текущего пользователя самую старую свободную заявку его департамента,
+	// подходящую по навыкам. nil без ошибки — свободных заявок нет.
+	ClaimNextOrder(ctx context.Context) (*dto.OrderResponseDTO, error)
+	SuggestExecutors(ctx context.Context, orderID uint64, limit int) ([]dto.ExecutorSuggestionDTO, error)
+
+	GetAccessGrants(ctx context.Context, orderID uint64) ([]dto.OrderAccessGrantDTO, error)
+	GrantAccess(ctx context.Context, orderID uint64, payload dto.GrantOrderAccessDTO) (*dto.OrderAccessGrantDTO, error)
+	RevokeAccess(ctx context.Context, orderID, userID uint64) error
 }
 
 type OrderService struct {
-	txManager             repositories.TxManagerInterface
-	orderRepo             repositories.OrderRepositoryInterface
-	userRepo              repositories.UserRepositoryInterface
+	txManager repositories.TxManagerInterface
+	orderRepo repositories.OrderRepositoryInterface
+	userRepo  repositories.UserRepositoryInterface
+	// statusRepo нужен только для чтений внутри транзакции; остальные берут статусы из statuses
 	statusRepo            repositories.StatusRepositoryInterface
+	statuses              StatusDirectoryInterface
 	priorityRepo          repositories.PriorityRepositoryInterface
 	attachRepo            repositories.AttachmentRepositoryInterface
 	ruleEngine            RuleEngineServiceInterface
@@ -101,6 +119,14 @@ type OrderService struct {
 	authPermissionService AuthPermissionServiceInterface
 	notificationService   NotificationServiceInterface
 	cacheRepo             repositories.CacheRepositoryInterface
+	eventOutbox           repositories.EventOutboxRepositoryInterface
+	customFieldRepo       repositories.CustomFieldRepositoryInterface
+	accessGrantRepo       repositories.OrderAccessGrantRepositoryInterface
+	businessCalendar      BusinessCalendarProvider
+	validationRules       OrderValidationRulesProvider
+	resolutionCodeRepo    repositories.ResolutionCodeRepositoryInterface
+	duplicateHintWindow   time.Duration
+	listFlight            singleflight.Group
 }
 
 func NewOrderService(
@@ -108,6 +134,7 @@ func NewOrderService(
 	orderRepo repositories.OrderRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	statusRepo repositories.StatusRepositoryInterface,
+	statuses StatusDirectoryInterface,
 	priorityRepo repositories.PriorityRepositoryInterface,
 	attachRepo repositories.AttachmentRepositoryInterface,
 	ruleEngine RuleEngineServiceInterface,
@@ -119,12 +146,20 @@ func NewOrderService(
 	authPermissionService AuthPermissionServiceInterface,
 	notificationService NotificationServiceInterface,
 	cacheRepo repositories.CacheRepositoryInterface,
+	eventOutbox repositories.EventOutboxRepositoryInterface,
+	customFieldRepo repositories.CustomFieldRepositoryInterface,
+	accessGrantRepo repositories.OrderAccessGrantRepositoryInterface,
+	businessCalendar BusinessCalendarProvider,
+	validationRules OrderValidationRulesProvider,
+	resolutionCodeRepo repositories.ResolutionCodeRepositoryInterface,
+	orderCfg config.OrdersConfig,
 ) OrderServiceInterface {
 	return &OrderService{
 		txManager:             txManager,
 		orderRepo:             orderRepo,
 		userRepo:              userRepo,
 		statusRepo:            statusRepo,
+		statuses:              statuses,
 		priorityRepo:          priorityRepo,
 		attachRepo:            attachRepo,
 		ruleEngine:            ruleEngine,
@@ -136,6 +171,13 @@ func NewOrderService(
 		authPermissionService: authPermissionService,
 		notificationService:   notificationService,
 		cacheRepo:             cacheRepo,
+		eventOutbox:           eventOutbox,
+		customFieldRepo:       customFieldRepo,
+		accessGrantRepo:       accessGrantRepo,
+		businessCalendar:      businessCalendar,
+		validationRules:       validationRules,
+		resolutionCodeRepo:    resolutionCodeRepo,
+		duplicateHintWindow:   time.Duration(orderCfg.DuplicateHintDays) * 24 * time.Hour,
 	}
 }
 
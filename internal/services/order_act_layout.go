@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"request-system/pkg/pdf"
+)
+
+// Поля страницы акта и колонки таблиц, в пунктах.
+const (
+	actMarginX      = 50.0
+	actMarginTop    = 50.0
+	actMarginBottom = 60.0
+	actContentWidth = pdf.A4Width - 2*actMarginX
+	actLabelWidth   = 150.0
+	actEventAtWidth = 80.0
+	actEventByWidth = 120.0
+	actSignLineFrom = 170.0
+	actSignLineTo   = 320.0
+)
+
+// actLayout раскладывает строки шаблона акта по страницам сверху вниз; y — верх свободного места.
+type actLayout struct {
+	doc     *pdf.Document
+	regular *pdf.Font
+	bold    *pdf.Font
+	y       float64
+}
+
+// renderOrderActPDF превращает вывод шаблона акта в PDF и нумерует страницы.
+func renderOrderActPDF(text string, regular, bold *pdf.Font, title string) ([]byte, error) {
+	l := &actLayout{doc: pdf.New(), regular: regular, bold: bold}
+	l.doc.SetTitle(title)
+	l.newPage()
+
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, orderActDirective) {
+			if line = strings.TrimSpace(line); line != "" {
+				l.paragraph(line)
+			}
+			continue
+		}
+		kind, rest, _ := strings.Cut(strings.TrimPrefix(line, orderActDirective), orderActSeparator)
+		args := strings.Split(rest, orderActSeparator)
+		arg := func(i int) string {
+			if i < len(args) {
+				return args[i]
+			}
+			return ""
+		}
+		switch kind {
+		case "title":
+			l.centered(arg(0), l.bold, 15, 19)
+			l.y += 4
+		case "subtitle":
+			l.centered(arg(0), l.regular, 9, 12)
+			l.y += 6
+		case "heading":
+			l.heading(arg(0))
+		case "field":
+			l.field(arg(0), arg(1))
+		case "text":
+			l.paragraph(arg(0))
+		case "event":
+			l.event(arg(0), arg(1), arg(2))
+		case "sign":
+			l.sign(arg(0), arg(1))
+		}
+	}
+
+	pages := l.doc.PageCount()
+	for i := 0; i < pages; i++ {
+		l.doc.SetPage(i)
+		footer := fmt.Sprintf("Стр. %d из %d", i+1, pages)
+		l.doc.Text(l.regular, 8, pdf.A4Width-actMarginX-l.regular.Width(footer, 8), pdf.A4Height-actMarginBottom/2, footer)
+		l.doc.Text(l.regular, 8, actMarginX, pdf.A4Height-actMarginBottom/2, title)
+	}
+	return l.doc.Bytes()
+}
+
+func (l *actLayout) newPage() {
+	l.doc.AddPage()
+	l.y = actMarginTop
+}
+
+// ensure переносит вывод на новую страницу, если height пунктов не помещается на текущей.
+func (l *actLayout) ensure(height float64) {
+	if l.y+height > pdf.A4Height-actMarginBottom && l.y > actMarginTop {
+		l.newPage()
+	}
+}
+
+func (l *actLayout) centered(text string, font *pdf.Font, size, lineHeight float64) {
+	for _, line := range pdf.Wrap(font, size, actContentWidth, text) {
+		l.ensure(lineHeight)
+		l.doc.Text(font, size, actMarginX+(actContentWidth-font.Width(line, size))/2, l.y+size, line)
+		l.y += lineHeight
+	}
+}
+
+func (l *actLayout) heading(text string) {
+	l.y += 10
+	// Заголовок не остаётся последней строкой страницы.
+	l.ensure(60)
+	l.doc.Text(l.bold, 11, actMarginX, l.y+11, text)
+	l.y += 15
+	l.doc.Line(actMarginX, l.y, actMarginX+actContentWidth, l.y, 0.5)
+	l.y += 6
+}
+
+func (l *actLayout) paragraph(text string) {
+	for _, line := range pdf.Wrap(l.regular, 10, actContentWidth, text) {
+		l.ensure(13)
+		l.doc.Text(l.regular, 10, actMarginX, l.y+10, line)
+		l.y += 13
+	}
+	l.y += 2
+}
+
+// columns печатает строку таблицы: текст каждой колонки переносится в её ширине.
+func (l *actLayout) columns(size, lineHeight float64, cells []string, fonts []*pdf.Font, widths []float64) {
+	wrapped := make([][]string, len(cells))
+	rows := 0
+	for i, cell := range cells {
+		wrapped[i] = pdf.Wrap(fonts[i], size, widths[i]-8, cell)
+		rows = max(rows, len(wrapped[i]))
+	}
+	for row := 0; row < rows; row++ {
+		l.ensure(lineHeight)
+		x := actMarginX
+		for i := range cells {
+			if row < len(wrapped[i]) {
+				l.doc.Text(fonts[i], size, x, l.y+size, wrapped[i][row])
+			}
+			x += widths[i]
+		}
+		l.y += lineHeight
+	}
+}
+
+// field — строка «подпись — значение»; поля без значения в акт не попадают.
+func (l *actLayout) field(label, value string) {
+	if value == "" {
+		return
+	}
+	l.columns(10, 13, []string{label, value}, []*pdf.Font{l.bold, l.regular},
+		[]float64{actLabelWidth, actContentWidth - actLabelWidth})
+	l.y += 2
+}
+
+func (l *actLayout) event(at, actor, text string) {
+	l.columns(9, 11.5, []string{at, actor, text}, []*pdf.Font{l.regular, l.bold, l.regular},
+		[]float64{actEventAtWidth, actEventByWidth, actContentWidth - actEventAtWidth - actEventByWidth})
+	l.y += 3
+}
+
+// sign — роль, линия для подписи с пометкой «подпись» и ФИО, если оно известно.
+func (l *actLayout) sign(role, name string) {
+	l.y += 16
+	l.ensure(30)
+	baseline := l.y + 10
+	l.doc.Text(l.bold, 10, actMarginX, baseline, role)
+	l.doc.Line(actMarginX+actSignLineFrom, baseline+2, actMarginX+actSignLineTo, baseline+2, 0.5)
+	caption := "подпись"
+	l.doc.Text(l.regular, 7, actMarginX+(actSignLineFrom+actSignLineTo-l.regular.Width(caption, 7))/2, baseline+11, caption)
+	lines := 1
+	if name != "" {
+		wrapped := pdf.Wrap(l.regular, 10, actContentWidth-actSignLineTo-10, name)
+		for i, line := range wrapped {
+			l.doc.Text(l.regular, 10, actMarginX+actSignLineTo+10, baseline+float64(i)*13, line)
+		}
+		lines = len(wrapped)
+	}
+	l.y += 9 + 13*float64(lines)
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/pdf"
+	"request-system/pkg/utils"
+)
+
+//go:embed templates/order_act.tmpl
+var defaultOrderActTemplate string
+
+const (
+	// orderActHistoryLimit — столько последних событий попадает в ход работ.
+	orderActHistoryLimit = 100
+	// orderActCommentLimit — длиннее комментарий в акте обрезается.
+	orderActCommentLimit = 300
+	orderActDateLayout   = "02.01.2006 15:04"
+)
+
+// OrderAct — готовый печатный акт заявки.
+type OrderAct struct {
+	Content  []byte
+	FileName string
+}
+
+type OrderActServiceInterface interface {
+	// RenderOrderAct строит PDF-акт по заявке для тех, кому заявка видна.
+	RenderOrderAct(ctx context.Context, orderID uint64) (*OrderAct, error)
+}
+
+// OrderActService печатает акт выполненных работ: сведения о заявке, ход работ по истории
+// и место для подписей. Текст акта задаёт text/template (встроенный или ORDER_ACT_TEMPLATE),
+// шрифты — встроенные Go-шрифты или ORDER_ACT_FONT / ORDER_ACT_BOLD_FONT. В Go-шрифтах есть
+// кириллица, но нет таджикских букв — для актов на таджикском нужен свой шрифт.
+type OrderActService struct {
+	orders   OrderServiceInterface
+	history  OrderHistoryServiceInterface
+	template *template.Template
+	regular  []byte
+	bold     []byte
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func NewOrderActService(
+	orders OrderServiceInterface,
+	history OrderHistoryServiceInterface,
+	cfg config.OrdersConfig,
+	logger *zap.Logger,
+) OrderActServiceInterface {
+	s := &OrderActService{
+		orders:  orders,
+		history: history,
+		regular: goregular.TTF,
+		bold:    gobold.TTF,
+		logger:  logger,
+		now:     time.Now,
+	}
+
+	// Ошибки своих файлов не мешают запуску: акт печатается встроенным шаблоном и шрифтами.
+	source := defaultOrderActTemplate
+	if cfg.ActTemplatePath != "" {
+		if data, err := os.ReadFile(cfg.ActTemplatePath); err != nil {
+			logger.Error("Не удалось прочитать шаблон акта, используется встроенный", zap.String("path", cfg.ActTemplatePath), zap.Error(err))
+		} else if _, err := parseOrderActTemplate(string(data)); err != nil {
+			logger.Error("Ошибка в шаблоне акта, используется встроенный", zap.String("path", cfg.ActTemplatePath), zap.Error(err))
+		} else {
+			source = string(data)
+		}
+	}
+	s.template = template.Must(parseOrderActTemplate(source))
+
+	for _, f := range []struct {
+		path   string
+		target *[]byte
+	}{{cfg.ActFontPath, &s.regular}, {cfg.ActBoldFontPath, &s.bold}} {
+		if f.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(f.path)
+		if err == nil {
+			_, err = pdf.ParseFont(data)
+		}
+		if err != nil {
+			logger.Error("Не удалось загрузить шрифт акта, используется встроенный", zap.String("path", f.path), zap.Error(err))
+			continue
+		}
+		*f.target = data
+	}
+	return s
+}
+
+func (s *OrderActService) RenderOrderAct(ctx context.Context, orderID uint64) (*OrderAct, error) {
+	order, err := s.orders.FindOrderSummary(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.history.GetHistoryEntries(ctx, orderID, repositories.HistoryPageFilter{Desc: true, Limit: orderActHistoryLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	data := newOrderActData(order, history, utils.LocationFromCtx(ctx), s.now())
+	var text bytes.Buffer
+	if err := s.template.Execute(&text, data); err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось заполнить шаблон акта", err, nil)
+	}
+
+	// Шрифт копит использованные глифы, поэтому каждый акт разбирает свою копию.
+	regular, err := pdf.ParseFont(s.regular)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось загрузить шрифт акта", err, nil)
+	}
+	bold, err := pdf.ParseFont(s.bold)
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось загрузить шрифт акта", err, nil)
+	}
+	content, err := renderOrderActPDF(text.String(), regular, bold, fmt.Sprintf("Акт по заявке № %s", order.Number))
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось построить PDF", err, nil)
+	}
+
+	s.logger.Info("Сформирован акт по заявке", zap.Uint64("order_id", orderID), zap.Int("bytes", len(content)))
+	return &OrderAct{Content: content, FileName: fmt.Sprintf("order-%d-act.pdf", order.ID)}, nil
+}
+
+// orderActEvent — строка хода работ в шаблоне.
+type orderActEvent struct {
+	At    string
+	Actor string
+	Text  string
+}
+
+// orderActData — данные шаблона акта. Даты уже переведены в часовой пояс пользователя,
+// пустые значения остаются пустыми строками.
+type orderActData struct {
+	Number        string
+	Name          string
+	Status        string
+	Priority      string
+	OrderType     string
+	Department    string
+	Otdel         string
+	Branch        string
+	Office        string
+	EquipmentType string
+	Equipment     string
+	Address       string
+	Creator       string
+	Executor      string
+	CustomFields  string
+	CreatedAt     string
+	Duration      string
+	CompletedAt   string
+	PrintedAt     string
+	// History — по времени, от ранних к поздним; HistoryTotal — сколько событий всего
+	History      []orderActEvent
+	HistoryTotal int
+}
+
+func newOrderActData(order *dto.OrderExportRowDTO, history *dto.PaginatedResponse[dto.OrderHistoryEntryDTO], loc *time.Location, now time.Time) orderActData {
+	optionalTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format(orderActDateLayout)
+	}
+	data := orderActData{
+		Number:        order.Number,
+		Name:          order.Name,
+		Status:        order.Status,
+		Priority:      order.Priority,
+		OrderType:     order.OrderType,
+		Department:    order.Department,
+		Otdel:         order.Otdel,
+		Branch:        order.Branch,
+		Office:        order.Office,
+		EquipmentType: order.EquipmentType,
+		Equipment:     order.Equipment,
+		Address:       order.Address,
+		Creator:       order.Creator,
+		Executor:      order.Executor,
+		CustomFields:  order.CustomFields,
+		CreatedAt:     order.CreatedAt.In(loc).Format(orderActDateLayout),
+		Duration:      optionalTime(order.Duration),
+		CompletedAt:   optionalTime(order.CompletedAt),
+		PrintedAt:     now.In(loc).Format(orderActDateLayout),
+		HistoryTotal:  int(history.Pagination.TotalCount),
+	}
+
+	for _, entry := range history.List {
+		text := entry.Diff
+		if entry.Comment != nil {
+			comment := *entry.Comment
+			if utf8.RuneCountInString(comment) > orderActCommentLimit {
+				comment = string([]rune(comment)[:orderActCommentLimit]) + "…"
+			}
+			if text == "" || entry.EventType == "COMMENT" {
+				text = comment
+			} else {
+				text += ": " + comment
+			}
+		}
+		data.History = append(data.History, orderActEvent{
+			At:    entry.CreatedAt.In(loc).Format(orderActDateLayout),
+			Actor: entry.Actor.Fio,
+			Text:  text,
+		})
+	}
+	// История читается с конца, чтобы в акт попали последние события, а печатается по порядку.
+	slices.Reverse(data.History)
+	if data.HistoryTotal < len(data.History) {
+		data.HistoryTotal = len(data.History)
+	}
+	return data
+}
+
+// Строка вывода шаблона, начинающаяся с orderActDirective, — команда вёрстки:
+// вид и аргументы через orderActSeparator. Остальные непустые строки — абзацы текста.
+const (
+	orderActDirective = "\x1e"
+	orderActSeparator = "\x1f"
+)
+
+func parseOrderActTemplate(source string) (*template.Template, error) {
+	directive := func(kind string) func(args ...string) string {
+		return func(args ...string) string {
+			clean := make([]string, len(args))
+			for i, arg := range args {
+				clean[i] = strings.Join(strings.Fields(strings.NewReplacer(orderActDirective, "", orderActSeparator, "").Replace(arg)), " ")
+			}
+			return orderActDirective + kind + orderActSeparator + strings.Join(clean, orderActSeparator)
+		}
+	}
+	funcs := template.FuncMap{}
+	for _, kind := range []string{"title", "subtitle", "heading", "field", "text", "event", "sign"} {
+		funcs[kind] = directive(kind)
+	}
+	return template.New("order_act").Funcs(funcs).Parse(source)
+}
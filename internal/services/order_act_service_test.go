@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+)
+
+type actOrderServiceStub struct {
+	OrderServiceInterface
+	summary *dto.OrderExportRowDTO
+}
+
+func (s *actOrderServiceStub) FindOrderSummary(context.Context, uint64) (*dto.OrderExportRowDTO, error) {
+	return s.summary, nil
+}
+
+type actHistoryServiceStub struct {
+	OrderHistoryServiceInterface
+	entries []dto.OrderHistoryEntryDTO
+	total   uint64
+	filter  repositories.HistoryPageFilter
+}
+
+func (s *actHistoryServiceStub) GetHistoryEntries(_ context.Context, _ uint64, filter repositories.HistoryPageFilter) (*dto.PaginatedResponse[dto.OrderHistoryEntryDTO], error) {
+	s.filter = filter
+	return &dto.PaginatedResponse[dto.OrderHistoryEntryDTO]{List: s.entries, Pagination: dto.PaginationObject{TotalCount: s.total}}, nil
+}
+
+func TestOrderActListsHistoryInOrderAndSkipsEmptyFields(t *testing.T) {
+	created := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	comment := "Заменён картридж"
+	history := &actHistoryServiceStub{
+		// Сервис истории отдаёт события от поздних к ранним.
+		entries: []dto.OrderHistoryEntryDTO{
+			{EventType: "COMMENT", Actor: dto.ShortUserDTO{Fio: "Петров П.П."}, CreatedAt: created.Add(2 * time.Hour), Diff: "Добавлен комментарий", Comment: &comment},
+			{EventType: "STATUS_CHANGE", Actor: dto.ShortUserDTO{Fio: "Петров П.П."}, CreatedAt: created.Add(time.Hour), Diff: "Статус: Открыта → В работе"},
+		},
+		total: 150,
+	}
+	orders := &actOrderServiceStub{summary: &dto.OrderExportRowDTO{
+		ID: 42, Number: "2026-000042", Name: "Не печатает принтер", Status: "Выполнена",
+		Creator: "Иванов И.И.", Executor: "Петров П.П.", CreatedAt: created,
+	}}
+	service := NewOrderActService(orders, history, config.OrdersConfig{}, zap.NewNop()).(*OrderActService)
+	service.now = func() time.Time { return created.Add(3 * time.Hour) }
+
+	summary, _ := orders.FindOrderSummary(context.Background(), 42)
+	page, _ := history.GetHistoryEntries(context.Background(), 42, repositories.HistoryPageFilter{})
+	var out bytes.Buffer
+	if err := service.template.Execute(&out, newOrderActData(summary, page, time.UTC, service.now())); err != nil {
+		t.Fatalf("template: %v", err)
+	}
+	text := strings.ReplaceAll(strings.ReplaceAll(out.String(), orderActDirective, "<"), orderActSeparator, "|")
+
+	status := strings.Index(text, "<event|16.10.2026 10:00|Петров П.П.|Статус: Открыта → В работе")
+	commented := strings.Index(text, "<event|16.10.2026 11:00|Петров П.П.|Заменён картридж")
+	if status < 0 || commented < status {
+		t.Errorf("history must be printed oldest first:\n%s", text)
+	}
+	if !strings.Contains(text, "Показаны последние 2 из 150 событий.") {
+		t.Errorf("act must say the history is cut:\n%s", text)
+	}
+	if !strings.Contains(text, "<field|Адрес|\n") || !strings.Contains(text, "<sign|Исполнитель|Петров П.П.") {
+		t.Errorf("unexpected fields or signatures:\n%s", text)
+	}
+
+	act, err := service.RenderOrderAct(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if act.FileName != "order-42-act.pdf" || !bytes.HasPrefix(act.Content, []byte("%PDF-")) || !bytes.HasSuffix(act.Content, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF: %s, %q", act.FileName, act.Content[:min(len(act.Content), 16)])
+	}
+	if !history.filter.Desc || history.filter.Limit != orderActHistoryLimit {
+		t.Errorf("history must be read newest first with a limit: %+v", history.filter)
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/utils"
+)
+
+// historyDiffFields — какое поле заявки меняет событие и как его подписать в diff.
+var historyDiffFields = map[string]struct{ field, label string }{
+	"STATUS_CHANGE":         {"status", "Статус"},
+	"PRIORITY_CHANGE":       {"priority", "Приоритет"},
+	"DELEGATION":            {"executor", "Исполнитель"},
+	"DEPARTMENT_CHANGE":     {"department", "Департамент"},
+	"OTDEL_CHANGE":          {"otdel", "Отдел"},
+	"NAME_CHANGE":           {"name", "Название"},
+	"ADDRESS_CHANGE":        {"address", "Адрес"},
+	"DURATION_CHANGE":       {"duration", "Срок выполнения"},
+	"EQUIPMENT_CHANGE":      {"equipment", "Оборудование"},
+	"EQUIPMENT_TYPE_CHANGE": {"equipment_type", "Тип оборудования"},
+	"ORDER_TYPE_CHANGE":     {"order_type", "Тип заявки"},
+	"IMPACT_CHANGE":         {"impact", "Влияние"},
+	"URGENCY_CHANGE":        {"urgency", "Срочность"},
+}
+
+// GetHistoryEntries возвращает страницу истории заявки по одной записи на событие,
+// с названиями статусов, приоритетов, подразделений и пользователей вместо ID.
+func (s *OrderHistoryService) GetHistoryEntries(ctx context.Context, orderID uint64, filter repositories.HistoryPageFilter) (*dto.PaginatedResponse[dto.OrderHistoryEntryDTO], error) {
+	items, total, err := s.repo.FindPageByOrderID(ctx, orderID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := uint64(1)
+	if filter.Limit > 0 {
+		page = uint64(filter.Offset/filter.Limit) + 1
+	}
+	result := &dto.PaginatedResponse[dto.OrderHistoryEntryDTO]{
+		List:       make([]dto.OrderHistoryEntryDTO, 0, len(items)),
+		Pagination: dto.PaginationObject{TotalCount: total, Page: page, Limit: uint64(filter.Limit)},
+	}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	resolver := newHistoryReferenceResolver(ctx, s, items, buildHistoryMetadata(items))
+	for _, item := range items {
+		result.List = append(result.List, resolver.entryForEvent(item))
+	}
+
+	s.logger.Debug("История заявки получена", zap.Uint64("orderID", orderID), zap.Int("entries", len(result.List)))
+	return result, nil
+}
+
+func (r *historyReferenceResolver) entryForEvent(event repositories.OrderHistoryItem) dto.OrderHistoryEntryDTO {
+	entry := dto.OrderHistoryEntryDTO{
+		ID:        event.ID,
+		EventType: event.EventType,
+		Actor:     r.actorFromEvent(event),
+		CreatedAt: event.CreatedAt,
+		OldValue:  historyNullStringPtr(event.OldValue),
+		NewValue:  historyNullStringPtr(event.NewValue),
+	}
+	if comment := strings.TrimSpace(utils.NullStringToString(event.Comment)); comment != "" {
+		entry.Comment = &comment
+	}
+
+	// lineForEvent заполняет вложение в блоке таймлайна — забираем его оттуда.
+	block := &dto.TimelineEventDTO{}
+	line := r.lineForEvent(block, event)
+	entry.Attachment = block.Attachment
+
+	spec, ok := historyDiffFields[event.EventType]
+	if !ok {
+		entry.Diff = line
+		if entry.Diff == "" && event.EventType == "COMMENT" {
+			entry.Diff = "Добавлен комментарий"
+		}
+		return entry
+	}
+
+	entry.Field = spec.field
+	entry.OldLabel = r.valueLabel(event.EventType, event.OldValue.String)
+	entry.NewLabel = r.valueLabel(event.EventType, event.NewValue.String)
+	if event.EventType == "STATUS_CHANGE" && event.NewStatusName.Valid {
+		entry.NewLabel = &event.NewStatusName.String
+	}
+	entry.Diff = historyDiffLine(spec.label, entry.OldLabel, entry.NewLabel)
+	if entry.Diff == "" {
+		entry.Diff = line
+	}
+	return entry
+}
+
+// valueLabel превращает сырое значение из истории в то, что видит пользователь.
+// Если название не нашлось (справочник удалён), остаётся исходное значение.
+func (r *historyReferenceResolver) valueLabel(eventType, raw string) *string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	label := raw
+	if eventType == "IMPACT_CHANGE" || eventType == "URGENCY_CHANGE" {
+		label = priorityLevelLabel(raw)
+		return &label
+	}
+	if eventType == "DURATION_CHANGE" {
+		if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+			label = parsed.Format("02.01.2006 15:04")
+		}
+		return &label
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return &label
+	}
+	var name string
+	switch eventType {
+	case "STATUS_CHANGE":
+		name = r.statusName(id)
+	case "PRIORITY_CHANGE":
+		name = r.priorityName(id)
+	case "DEPARTMENT_CHANGE":
+		name = r.departmentName(id)
+	case "OTDEL_CHANGE":
+		name = r.otdelName(id)
+	case "DELEGATION":
+		name = r.userName(id)
+	}
+	if name != "" {
+		label = name
+	}
+	return &label
+}
+
+func (r *historyReferenceResolver) userName(id uint64) string {
+	if user, ok := r.users[id]; ok {
+		return user.Fio
+	}
+	user, err := r.service.userRepo.FindUserByID(r.ctx, id)
+	if err != nil || user == nil {
+		r.service.logger.Warn("failed to resolve user for order history", zap.Uint64("userID", id), zap.Error(err))
+		r.users[id] = entities.User{ID: id}
+		return ""
+	}
+	r.users[id] = *user
+	return user.Fio
+}
+
+func historyNullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func historyDiffLine(label string, oldLabel, newLabel *string) string {
+	switch {
+	case oldLabel != nil && newLabel != nil:
+		return fmt.Sprintf("%s: «%s» → «%s»", label, *oldLabel, *newLabel)
+	case newLabel != nil:
+		return fmt.Sprintf("%s: «%s»", label, *newLabel)
+	case oldLabel != nil:
+		return fmt.Sprintf("%s: «%s» → —", label, *oldLabel)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+)
+
+func TestGetHistoryEntries_ResolvesDiffAndFiltersByType(t *testing.T) {
+	repo := &orderHistoryRepoStub{
+		events: []repositories.OrderHistoryItem{
+			{ID: 1, OrderID: 1, UserID: 1, EventType: "CREATE", NewValue: nullString("Заявка"), CreatedAt: historyTime(1)},
+			{ID: 2, OrderID: 1, UserID: 1, EventType: "STATUS_CHANGE", OldValue: nullString("1"), NewValue: nullString("2"), CreatedAt: historyTime(2)},
+			{ID: 3, OrderID: 1, UserID: 1, EventType: "DELEGATION", OldValue: nullString("2"), NewValue: nullString("3"), CreatedAt: historyTime(3)},
+			{ID: 4, OrderID: 1, UserID: 2, EventType: "PRIORITY_CHANGE", NewValue: nullString("5"), CreatedAt: historyTime(4)},
+		},
+	}
+	service := &OrderHistoryService{
+		repo: repo,
+		userRepo: &historyUserLookupStub{users: map[uint64]entities.User{
+			1: {ID: 1, Fio: "Создатель"},
+			2: {ID: 2, Fio: "Исполнитель"},
+			3: {ID: 3, Fio: "Новый исполнитель"},
+		}},
+		departmentRepo: &historyDepartmentLookupStub{},
+		otdelRepo:      &historyOtdelLookupStub{},
+		statusRepo:     &historyStatusLookupStub{names: map[uint64]string{1: "Открыта", 2: "В работе"}},
+		priorityRepo:   &historyPriorityLookupStub{names: map[uint64]string{5: "Высокий"}},
+		logger:         zap.NewNop(),
+	}
+
+	result, err := service.GetHistoryEntries(context.Background(), 1, repositories.HistoryPageFilter{
+		EventTypes: []string{"STATUS_CHANGE", "DELEGATION", "PRIORITY_CHANGE"},
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatalf("GetHistoryEntries returned error: %v", err)
+	}
+	if result.Pagination.TotalCount != 3 || len(result.List) != 2 {
+		t.Fatalf("expected 2 of 3 entries, got %d of %d", len(result.List), result.Pagination.TotalCount)
+	}
+
+	status := result.List[0]
+	if status.Field != "status" || status.Diff != "Статус: «Открыта» → «В работе»" {
+		t.Fatalf("unexpected status entry: %+v", status)
+	}
+	delegation := result.List[1]
+	if delegation.Field != "executor" || delegation.Diff != "Исполнитель: «Исполнитель» → «Новый исполнитель»" {
+		t.Fatalf("unexpected delegation entry: %+v", delegation)
+	}
+	if delegation.NewValue == nil || *delegation.NewValue != "3" {
+		t.Fatalf("expected raw new value to be kept, got %+v", delegation.NewValue)
+	}
+}
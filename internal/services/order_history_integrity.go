@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+type OrderHistoryIntegrityServiceInterface interface {
+	VerifyOrderChain(ctx context.Context, orderID uint64) (*dto.OrderHistoryChainDTO, error)
+}
+
+// OrderHistoryIntegrityService пересчитывает цепочку хэшей истории заявки и находит
+// первую запись, которую изменили, удалили или вставили задним числом.
+type OrderHistoryIntegrityService struct {
+	historyRepo repositories.OrderHistoryRepositoryInterface
+	userRepo    repositories.UserRepositoryInterface
+	logger      *zap.Logger
+}
+
+func NewOrderHistoryIntegrityService(
+	historyRepo repositories.OrderHistoryRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) OrderHistoryIntegrityServiceInterface {
+	return &OrderHistoryIntegrityService{historyRepo: historyRepo, userRepo: userRepo, logger: logger}
+}
+
+func (s *OrderHistoryIntegrityService) VerifyOrderChain(ctx context.Context, orderID uint64) (*dto.OrderHistoryChainDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.AuditView, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	head, err := s.historyRepo.FindChainHead(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.historyRepo.FindChainByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := verifyHistoryChain(orderID, items, head.String)
+	if !result.Valid {
+		s.logger.Warn("Нарушена целостность истории заявки",
+			zap.Uint64("orderID", orderID),
+			zap.Any("firstBrokenID", result.FirstBrokenID),
+			zap.String("reason", result.Reason))
+	}
+	return result, nil
+}
+
+// verifyHistoryChain переводит результат проверки цепочки в ответ API.
+func verifyHistoryChain(orderID uint64, items []repositories.OrderHistoryItem, head string) *dto.OrderHistoryChainDTO {
+	check := repositories.CheckHistoryChain(items, head)
+	result := &dto.OrderHistoryChainDTO{
+		OrderID:  orderID,
+		Valid:    check.Valid,
+		Checked:  check.Checked,
+		Legacy:   check.Legacy,
+		HeadHash: head,
+		Reason:   check.Reason,
+	}
+	if !check.Valid {
+		result.FirstBrokenID = &check.FirstBrokenID
+	}
+	return result
+}
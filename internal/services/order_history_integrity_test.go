@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+)
+
+func chainedHistory(items ...repositories.OrderHistoryItem) ([]repositories.OrderHistoryItem, sql.NullString) {
+	prev := ""
+	for i := range items {
+		items[i].ID = uint64(i + 1)
+		if prev != "" {
+			items[i].PrevHash = nullString(prev)
+		}
+		prev = repositories.ComputeHistoryHash(prev, &items[i])
+		items[i].Hash = nullString(prev)
+	}
+	return items, nullString(prev)
+}
+
+func verifyChain(t *testing.T, repo *orderHistoryRepoStub) (valid bool, brokenID uint64) {
+	t.Helper()
+	service := NewOrderHistoryIntegrityService(repo, &replayUserRepoStub{}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.AuditView: true})
+
+	result, err := service.VerifyOrderChain(ctx, 1)
+	if err != nil {
+		t.Fatalf("VerifyOrderChain returned error: %v", err)
+	}
+	if result.FirstBrokenID != nil {
+		brokenID = *result.FirstBrokenID
+	}
+	return result.Valid, brokenID
+}
+
+func TestVerifyOrderChain_DetectsTampering(t *testing.T) {
+	build := func() *orderHistoryRepoStub {
+		events, head := chainedHistory(
+			repositories.OrderHistoryItem{OrderID: 1, UserID: 1, EventType: "CREATE", NewValue: nullString("Заявка"), CreatedAt: historyTime(1)},
+			repositories.OrderHistoryItem{OrderID: 1, UserID: 2, EventType: "COMMENT", Comment: nullString("Проверено"), CreatedAt: historyTime(2)},
+			repositories.OrderHistoryItem{OrderID: 1, UserID: 2, EventType: "STATUS_CHANGE", NewValue: nullString("3"), CreatedAt: historyTime(3)},
+		)
+		// Запись, созданная до включения цепочки, не ломает проверку.
+		legacy := repositories.OrderHistoryItem{OrderID: 1, UserID: 1, EventType: "CREATE", CreatedAt: historyTime(0)}
+		return &orderHistoryRepoStub{events: append([]repositories.OrderHistoryItem{legacy}, events...), head: head}
+	}
+
+	if valid, _ := verifyChain(t, build()); !valid {
+		t.Fatal("expected an untouched chain to be valid")
+	}
+
+	edited := build()
+	edited.events[2].Comment = nullString("Не проверено")
+	if valid, id := verifyChain(t, edited); valid || id != 2 {
+		t.Fatalf("expected edited record 2 to be reported, got valid=%v id=%d", valid, id)
+	}
+
+	removed := build()
+	removed.events = append(removed.events[:2], removed.events[3:]...)
+	if valid, id := verifyChain(t, removed); valid || id != 3 {
+		t.Fatalf("expected record after the removed one to be reported, got valid=%v id=%d", valid, id)
+	}
+
+	truncated := build()
+	truncated.events = truncated.events[:3]
+	if valid, _ := verifyChain(t, truncated); valid {
+		t.Fatal("expected a truncated chain to be reported")
+	}
+}
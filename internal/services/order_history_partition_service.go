@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+)
+
+// orderHistoryPartitionCheckInterval — как часто проверять, что секции истории на год вперёд созданы.
+const orderHistoryPartitionCheckInterval = 24 * time.Hour
+
+type OrderHistoryPartitionServiceInterface interface {
+	Start(ctx context.Context)
+	// EnsureUpcoming создаёт секции order_history текущего и следующего года, если их ещё нет.
+	EnsureUpcoming(ctx context.Context) error
+}
+
+// OrderHistoryPartitionService заранее создаёт годовые секции order_history. Без секции записи
+// нового года попадут в order_history_default и потом переедут в свою секцию при её создании.
+type OrderHistoryPartitionService struct {
+	repo   repositories.OrderHistoryPartitionRepositoryInterface
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+func NewOrderHistoryPartitionService(repo repositories.OrderHistoryPartitionRepositoryInterface, logger *zap.Logger) OrderHistoryPartitionServiceInterface {
+	return &OrderHistoryPartitionService{repo: repo, logger: logger, now: time.Now}
+}
+
+func (s *OrderHistoryPartitionService) Start(ctx context.Context) {
+	ticker := time.NewTicker(orderHistoryPartitionCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.EnsureUpcoming(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Не удалось создать секции истории заявок", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OrderHistoryPartitionService) EnsureUpcoming(ctx context.Context) error {
+	year := s.now().UTC().Year()
+	for _, y := range []int{year, year + 1} {
+		created, err := s.repo.EnsureYear(ctx, y)
+		if err != nil {
+			return err
+		}
+		if created {
+			s.logger.Info("Создана секция истории заявок", zap.String("partition", repositories.OrderHistoryYearPartition(y)))
+		}
+	}
+	return nil
+}
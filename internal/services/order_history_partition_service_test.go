@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+)
+
+type orderHistoryPartitionRepoStub struct {
+	repositories.OrderHistoryPartitionRepositoryInterface
+	years   map[int]bool
+	failing bool
+}
+
+func (s *orderHistoryPartitionRepoStub) EnsureYear(_ context.Context, year int) (bool, error) {
+	if s.failing {
+		return false, errors.New("lock timeout")
+	}
+	if s.years[year] {
+		return false, nil
+	}
+	s.years[year] = true
+	return true, nil
+}
+
+func TestOrderHistoryPartitionService_EnsuresCurrentAndNextYearInUTC(t *testing.T) {
+	repo := &orderHistoryPartitionRepoStub{years: map[int]bool{2026: true}}
+	service := &OrderHistoryPartitionService{repo: repo, logger: zap.NewNop(), now: func() time.Time {
+		// В Душанбе уже 2026 год, по UTC — ещё 2025
+		return time.Date(2026, time.January, 1, 3, 0, 0, 0, time.FixedZone("UTC+5", 5*3600))
+	}}
+
+	if err := service.EnsureUpcoming(context.Background()); err != nil {
+		t.Fatalf("EnsureUpcoming: %v", err)
+	}
+	if !repo.years[2025] || !repo.years[2026] || repo.years[2027] {
+		t.Errorf("partitions follow UTC years: want 2025 and 2026, got %v", repo.years)
+	}
+
+	repo.failing = true
+	if err := service.EnsureUpcoming(context.Background()); err == nil {
+		t.Error("repository error must be returned")
+	}
+}
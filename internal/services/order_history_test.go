@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
+	"slices"
 	"testing"
 	"time"
 
@@ -110,7 +111,10 @@ func TestGetTimelineByOrderID_RendersStructureChangeComment(t *testing.T) {
 }
 
 type orderHistoryRepoStub struct {
-	events []repositories.OrderHistoryItem
+	events   []repositories.OrderHistoryItem
+	head     sql.NullString
+	resealed []uint64
+	broken   map[uint64]bool
 }
 
 func (s *orderHistoryRepoStub) FindByOrderID(context.Context, uint64, uint64, uint64) ([]repositories.OrderHistoryItem, error) {
@@ -129,6 +133,56 @@ func (s *orderHistoryRepoStub) GetOrderHistory(context.Context, uint64, types.Fi
 	return s.events, nil
 }
 
+func (s *orderHistoryRepoStub) FindForReplay(_ context.Context, _ repositories.HistoryReplayFilter, afterID uint64, limit int) ([]repositories.OrderHistoryItem, error) {
+	result := make([]repositories.OrderHistoryItem, 0, limit)
+	for _, item := range s.events {
+		if item.ID > afterID && len(result) < limit {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func (s *orderHistoryRepoStub) FindPageByOrderID(_ context.Context, _ uint64, filter repositories.HistoryPageFilter) ([]repositories.OrderHistoryItem, uint64, error) {
+	matched := make([]repositories.OrderHistoryItem, 0, len(s.events))
+	for _, item := range s.events {
+		if len(filter.EventTypes) == 0 || slices.Contains(filter.EventTypes, item.EventType) {
+			matched = append(matched, item)
+		}
+	}
+	total := uint64(len(matched))
+	start := min(filter.Offset, len(matched))
+	end := min(start+filter.Limit, len(matched))
+	return matched[start:end], total, nil
+}
+
+func (s *orderHistoryRepoStub) FindChainByOrderID(context.Context, uint64) ([]repositories.OrderHistoryItem, error) {
+	return s.events, nil
+}
+
+func (s *orderHistoryRepoStub) FindChainHead(context.Context, uint64) (sql.NullString, error) {
+	return s.head, nil
+}
+
+func (s *orderHistoryRepoStub) VerifyChainInTx(_ context.Context, _ pgx.Tx, orderID uint64) (*repositories.HistoryChainCheck, error) {
+	if s.broken[orderID] {
+		return &repositories.HistoryChainCheck{Head: s.head.String, FirstBrokenID: 1, Reason: "Содержимое записи изменено"}, nil
+	}
+	return &repositories.HistoryChainCheck{Head: s.head.String, Valid: true}, nil
+}
+
+func (s *orderHistoryRepoStub) ResealChainInTx(_ context.Context, _ pgx.Tx, orderID uint64, verified *repositories.HistoryChainCheck) (string, error) {
+	if verified == nil || !verified.Valid {
+		return "", repositories.ErrHistoryChainBroken
+	}
+	s.resealed = append(s.resealed, orderID)
+	return s.head.String, nil
+}
+
+func (s *orderHistoryRepoStub) FindLastCommentsByOrderIDs(context.Context, []uint64, int) (map[uint64][]repositories.OrderHistoryItem, error) {
+	return map[uint64][]repositories.OrderHistoryItem{}, nil
+}
+
 type historyUserLookupStub struct {
 	users      map[uint64]entities.User
 	batchCalls int
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+)
+
+// orderListViewReconcileBatch — сколько заявок сверяется одним запросом.
+const orderListViewReconcileBatch = 500
+
+type OrderListViewServiceInterface interface {
+	Start(ctx context.Context)
+	// Reconcile сверяет всю проекцию с исходными таблицами и возвращает число исправленных строк.
+	Reconcile(ctx context.Context) (int64, error)
+}
+
+// OrderListViewService периодически сверяет order_list_view с исходными таблицами. Строки
+// заявок обновляются по событиям истории, но переименование статуса, приоритета, команды или
+// сотрудника событий заявки не порождает — такие строки догоняет сверка.
+type OrderListViewService struct {
+	repo     repositories.OrderListViewRepositoryInterface
+	cache    repositories.CacheRepositoryInterface
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewOrderListViewService(
+	repo repositories.OrderListViewRepositoryInterface,
+	cache repositories.CacheRepositoryInterface,
+	interval time.Duration,
+	logger *zap.Logger,
+) OrderListViewServiceInterface {
+	return &OrderListViewService{repo: repo, cache: cache, interval: interval, logger: logger}
+}
+
+func (s *OrderListViewService) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		s.logger.Info("Сверка проекции списка заявок выключена")
+		return
+	}
+	s.logger.Info("Сверка проекции списка заявок запущена", zap.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Сверка проекции списка заявок остановлена")
+			return
+		case <-ticker.C:
+			if _, err := s.Reconcile(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("Ошибка сверки проекции списка заявок", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *OrderListViewService) Reconcile(ctx context.Context) (int64, error) {
+	var (
+		afterID uint64
+		changed int64
+	)
+	for {
+		ids, err := s.repo.NextOrderIDs(ctx, afterID, orderListViewReconcileBatch)
+		if err != nil {
+			return changed, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		n, err := s.repo.Refresh(ctx, ids)
+		if err != nil {
+			return changed, err
+		}
+		changed += n
+		afterID = ids[len(ids)-1]
+	}
+
+	if changed > 0 {
+		if _, err := s.cache.Incr(ctx, pkgconstants.OrderListCacheVersionKey); err != nil {
+			s.logger.Warn("Не удалось сбросить кэш списка заявок", zap.Error(err))
+		}
+		s.logger.Info("Проекция списка заявок сверена", zap.Int64("changed", changed))
+	}
+	return changed, nil
+}
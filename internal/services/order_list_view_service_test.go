@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+)
+
+type orderListViewRepoStub struct {
+	repositories.OrderListViewRepositoryInterface
+	orderIDs  []uint64
+	stale     map[uint64]bool
+	refreshed [][]uint64
+}
+
+func (s *orderListViewRepoStub) NextOrderIDs(_ context.Context, afterID uint64, limit int) ([]uint64, error) {
+	var ids []uint64
+	for _, id := range s.orderIDs {
+		if id > afterID && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *orderListViewRepoStub) Refresh(_ context.Context, orderIDs []uint64) (int64, error) {
+	s.refreshed = append(s.refreshed, orderIDs)
+	var changed int64
+	for _, id := range orderIDs {
+		if s.stale[id] {
+			changed++
+			delete(s.stale, id)
+		}
+	}
+	return changed, nil
+}
+
+type orderListCacheStub struct {
+	repositories.CacheRepositoryInterface
+	incremented []string
+}
+
+func (s *orderListCacheStub) Incr(_ context.Context, key string) (int64, error) {
+	s.incremented = append(s.incremented, key)
+	return int64(len(s.incremented)), nil
+}
+
+func TestOrderListViewService_ReconcileWalksAllOrdersInBatches(t *testing.T) {
+	repo := &orderListViewRepoStub{stale: map[uint64]bool{3: true, orderListViewReconcileBatch + 7: true}}
+	for id := uint64(1); id <= orderListViewReconcileBatch+10; id++ {
+		repo.orderIDs = append(repo.orderIDs, id)
+	}
+	cache := &orderListCacheStub{}
+	service := NewOrderListViewService(repo, cache, time.Hour, zap.NewNop())
+
+	changed, err := service.Reconcile(context.Background())
+	if err != nil || changed != 2 {
+		t.Fatalf("Reconcile = %d, %v; want 2 changed rows", changed, err)
+	}
+	if len(repo.refreshed) != 2 || len(repo.refreshed[1]) != 10 {
+		t.Errorf("orders must be refreshed in batches of %d, got %d batches", orderListViewReconcileBatch, len(repo.refreshed))
+	}
+	if len(cache.incremented) != 1 || cache.incremented[0] != pkgconstants.OrderListCacheVersionKey {
+		t.Errorf("changed rows must reset the order list cache, got %v", cache.incremented)
+	}
+
+	if changed, _ := service.Reconcile(context.Background()); changed != 0 || len(cache.incremented) != 1 {
+		t.Errorf("nothing changed: want no cache reset, changed = %d, resets = %d", changed, len(cache.incremented))
+	}
+}
+
+func TestOrderService_ToResponseDTOUsesListView(t *testing.T) {
+	service := &OrderService{}
+	statusName, comment := "В работе", "Заменили картридж"
+	now := time.Now()
+	order := &entities.Order{ID: 1, CreatedAt: now, UpdatedAt: now}
+
+	if d := service.toResponseDTO(order, nil, nil, nil); d.AttachmentsCount != nil || d.StatusName != nil {
+		t.Errorf("card without list view must not carry list fields, got %+v", d)
+	}
+
+	order.ListView = &entities.OrderListView{StatusName: &statusName, LastComment: &comment, LastCommentAt: &now}
+	d := service.toResponseDTO(order, nil, nil, nil)
+	if d.StatusName == nil || *d.StatusName != statusName || d.LastComment == nil || *d.LastComment != comment {
+		t.Errorf("list fields = %+v", d)
+	}
+	if d.AttachmentsCount == nil || *d.AttachmentsCount != 0 {
+		t.Errorf("attachments_count must be present in the list even when zero, got %v", d.AttachmentsCount)
+	}
+}
@@ -94,9 +94,14 @@ func (s *OrderRoutingRuleService) toResponseDTO(ctx context.Context, entity *ent
 		BranchID:     entity.BranchID,
 		OfficeID:     entity.OfficeID,
 		PositionID:   entity.PositionID,
+		TeamID:       entity.TeamID,
+		TeamName:     entity.TeamName,
 		StatusID:     entity.StatusID,
 		CreatedAt:    entity.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    entity.UpdatedAt.Format(time.RFC3339),
+
+		OnCallScheduleID:   entity.OnCallScheduleID,
+		OnCallScheduleName: entity.OnCallScheduleName,
 	}
 
 	if entity.PositionID != nil {
@@ -170,20 +175,26 @@ func (s *OrderRoutingRuleService) Create(ctx context.Context, d dto.CreateOrderR
 		d.OtdelID = nil
 	}
 
-	realPositionID, err := s.userRepo.FindPositionIDByStructureAndType(ctx, nil, searchBranch, searchOffice, searchDept, searchOtdel, d.PositionType)
-	if err != nil {
-		return nil, err
-	}
+	// Правило на команду или график дежурств может обойтись без должности
+	var positionID *int
+	if d.PositionType != "" {
+		realPositionID, err := s.userRepo.FindPositionIDByStructureAndType(ctx, nil, searchBranch, searchOffice, searchDept, searchOtdel, d.PositionType)
+		if err != nil {
+			return nil, err
+		}
 
-	if realPositionID == 0 && constants.PositionType(d.PositionType) == constants.PositionTypeHeadOfOtdel {
-		realPositionID, _ = s.userRepo.FindPositionIDByStructureAndType(ctx, nil, searchBranch, searchOffice, searchDept, searchOtdel, string(constants.PositionTypeManager))
-	}
+		if realPositionID == 0 && constants.PositionType(d.PositionType) == constants.PositionTypeHeadOfOtdel {
+			realPositionID, _ = s.userRepo.FindPositionIDByStructureAndType(ctx, nil, searchBranch, searchOffice, searchDept, searchOtdel, string(constants.PositionTypeManager))
+		}
+
+		if realPositionID == 0 {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest, "Активный сотрудник с данной должностью в подразделении не найден.", nil, nil)
+		}
 
-	if realPositionID == 0 {
-		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Активный сотрудник с данной должностью в подразделении не найден.", nil, nil)
+		finalPosID := int(realPositionID)
+		positionID = &finalPosID
 	}
 
-	finalPosID := int(realPositionID)
 	rule := &entities.OrderRoutingRule{
 		RuleName:     d.RuleName,
 		OrderTypeID:  d.OrderTypeID,
@@ -191,8 +202,11 @@ func (s *OrderRoutingRuleService) Create(ctx context.Context, d dto.CreateOrderR
 		OtdelID:      d.OtdelID,
 		BranchID:     d.BranchID,
 		OfficeID:     d.OfficeID,
-		PositionID:   &finalPosID,
+		PositionID:   positionID,
+		TeamID:       d.TeamID,
 		StatusID:     d.StatusID,
+
+		OnCallScheduleID: d.OnCallScheduleID,
 	}
 
 	var newID uint64
@@ -236,6 +250,22 @@ func (s *OrderRoutingRuleService) Update(ctx context.Context, id uint64, d dto.U
 	if d.StatusID.Valid {
 		existing.StatusID = d.StatusID.Int
 	}
+	if _, ok := changes["team_id"]; ok {
+		if d.TeamID.Valid {
+			v := d.TeamID.Int
+			existing.TeamID = &v
+		} else {
+			existing.TeamID = nil
+		}
+	}
+	if _, ok := changes["on_call_schedule_id"]; ok {
+		if d.OnCallScheduleID.Valid {
+			v := d.OnCallScheduleID.Int
+			existing.OnCallScheduleID = &v
+		} else {
+			existing.OnCallScheduleID = nil
+		}
+	}
 
 	needsReRouting := false
 	if _, ok := changes["branch_id"]; ok {
@@ -403,5 +433,5 @@ func buildRuleAuthzContext(ctx context.Context, repo repositories.UserRepository
 	if err != nil {
 		return nil, err
 	}
-	return &authz.Context{Actor: user, Permissions: perms}, nil
+	return &authz.Context{Actor: user, Permissions: perms, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+)
+
+func TestOrderAccessGrantLevels(t *testing.T) {
+	departmentID, otherDepartmentID := uint64(1), uint64(2)
+	order := &entities.Order{ID: 10, CreatorID: 100, DepartmentID: &otherDepartmentID}
+	authCtx := authz.Context{
+		Actor: &entities.User{ID: 7, DepartmentID: &departmentID},
+		Permissions: map[string]bool{
+			authz.OrdersView: true, authz.OrdersUpdate: true, authz.OrdersDelete: true,
+			authz.ScopeOwn: true, authz.OrdersGrantAccess: true,
+		},
+		Target: order,
+	}
+
+	if authz.CanDo(authz.OrdersView, authCtx) {
+		t.Fatal("без выдачи заявка чужого департамента не должна быть видна")
+	}
+
+	authCtx.AccessGrant = entities.OrderAccessView
+	if !authz.CanDo(authz.OrdersView, authCtx) || authz.CanDo(authz.OrdersUpdate, authCtx) {
+		t.Fatal("доступ view должен давать только просмотр")
+	}
+
+	authCtx.AccessGrant = entities.OrderAccessEdit
+	if !authz.CanDo(authz.OrdersUpdate, authCtx) {
+		t.Fatal("доступ edit должен разрешать изменение")
+	}
+	if authz.CanDo(authz.OrdersDelete, authCtx) || authz.CanDo(authz.OrdersGrantAccess, authCtx) {
+		t.Fatal("разовый доступ не даёт права удалять заявку и передавать доступ дальше")
+	}
+}
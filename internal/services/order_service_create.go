@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
 
 	"request-system/internal/authz"
 	"request-system/internal/dto"
@@ -16,6 +17,11 @@ import (
 )
 
 func (s *OrderService) CreateOrder(ctx context.Context, createDTO dto.CreateOrderDTO, file *multipart.FileHeader) (*dto.OrderResponseDTO, error) {
+	createDTO.Sanitize()
+	if createDTO.Name == "" {
+		return nil, apperrors.NewBadRequestError("Название заявки не может быть пустым.")
+	}
+
 	authCtx, err := s.buildAuthzContext(ctx, 0)
 	if err != nil {
 		return nil, err
@@ -27,9 +33,20 @@ func (s *OrderService) CreateOrder(ctx context.Context, createDTO dto.CreateOrde
 	if err := s.validateCreateFieldPermissions(authCtx, createDTO, file); err != nil {
 		return nil, err
 	}
+	if err := s.checkOrderTypeAvailable(ctx, authCtx.Actor, createDTO.OrderTypeID); err != nil {
+		return nil, err
+	}
 	if err := s.validateOrderRules(ctx, createDTO); err != nil {
 		return nil, err
 	}
+	customFields, err := s.prepareCreateCustomFields(ctx, createDTO.OrderTypeID, createDTO.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	priorityID, impact, urgency, err := s.resolveCreatePriority(ctx, createDTO)
+	if err != nil {
+		return nil, err
+	}
 
 	hasDepartment := createDTO.DepartmentID != nil
 	hasBranch := createDTO.BranchID != nil
@@ -60,7 +77,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, createDTO dto.CreateOrde
 		if err != nil {
 			return err
 		}
-		if routingResult.Executor.ID == 0 {
+		if routingResult.Team == nil && routingResult.Executor.ID == 0 {
 			return apperrors.NewHttpError(
 				http.StatusBadRequest,
 				"Не найден руководитель для выбранной структуры. Настройте правила маршрутизации или укажите исполнителя вручную.",
@@ -82,13 +99,20 @@ func (s *OrderService) CreateOrder(ctx context.Context, createDTO dto.CreateOrde
 			OtdelID:         createDTO.OtdelID,
 			BranchID:        createDTO.BranchID,
 			OfficeID:        createDTO.OfficeID,
-			PriorityID:      createDTO.PriorityID,
+			PriorityID:      priorityID,
+			Impact:          impact,
+			Urgency:         urgency,
 			EquipmentID:     createDTO.EquipmentID,
 			EquipmentTypeID: createDTO.EquipmentTypeID,
 			StatusID:        uint64(status.ID),
 			CreatorID:       authCtx.Actor.ID,
-			ExecutorID:      &routingResult.Executor.ID,
 			Duration:        createDTO.Duration,
+			CustomFields:    customFields,
+		}
+		if routingResult.Team != nil {
+			orderEntity.TeamID = &routingResult.Team.ID
+		} else {
+			orderEntity.ExecutorID = &routingResult.Executor.ID
 		}
 
 		newID, err := s.orderRepo.Create(ctx, tx, orderEntity)
@@ -112,9 +136,11 @@ func (s *OrderService) CreateOrder(ctx context.Context, createDTO dto.CreateOrde
 			}
 		}
 
-		delegationText := "Назначено на: " + routingResult.Executor.Fio
-		executorIDText := fmt.Sprintf("%d", routingResult.Executor.ID)
-		if err := s.logHistoryEvent(ctx, tx, orderEntity.ID, authCtx.Actor, "DELEGATION", &executorIDText, nil, &delegationText, txID, *orderEntity); err != nil {
+		if err := s.logPriorityLevels(ctx, tx, orderEntity, authCtx.Actor, txID); err != nil {
+			return err
+		}
+
+		if err := s.logRoutingAssignment(ctx, tx, orderEntity, authCtx.Actor, routingResult, txID); err != nil {
 			return err
 		}
 
@@ -136,5 +162,16 @@ func (s *OrderService) CreateOrder(ctx context.Context, createDTO dto.CreateOrde
 	}
 
 	s.invalidateDashboardCache(ctx, true, true)
-	return s.FindOrderByID(ctx, createdID)
+
+	created, err := s.FindOrderByID(ctx, createdID)
+	if err != nil || createDTO.EquipmentID == nil {
+		return created, err
+	}
+	duplicates, err := s.FindPossibleDuplicates(ctx, createDTO.Name, *createDTO.EquipmentID, createdID)
+	if err != nil {
+		s.logger.Warn("Не удалось подобрать возможные дубли заявки", zap.Uint64("order_id", createdID), zap.Error(err))
+		return created, nil
+	}
+	created.PossibleDuplicates = duplicates
+	return created, nil
 }
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// customFieldTextMaxLen — ограничение на длину текстового значения дополнительного поля.
+const customFieldTextMaxLen = 2000
+
+// prepareCreateCustomFields проверяет дополнительные поля новой заявки по определениям её типа.
+func (s *OrderService) prepareCreateCustomFields(ctx context.Context, orderTypeID *uint64, values map[string]any) (map[string]any, error) {
+	if orderTypeID == nil {
+		return map[string]any{}, nil
+	}
+	defs, err := s.customFieldRepo.FindByOrderType(ctx, *orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectUnknownCustomFields(defs, values); err != nil {
+		return nil, err
+	}
+	return normalizeCustomFields(defs, values)
+}
+
+// applyUpdateCustomFields сливает custom_fields из запроса с текущими значениями заявки.
+// Вызывается, только если прислали custom_fields или сменился тип заявки: тогда значения
+// полей, которых у нового типа нет, отбрасываются. Возвращает определения для записи истории.
+func (s *OrderService) applyUpdateCustomFields(ctx context.Context, current, updated *entities.Order, explicitFields map[string]interface{}) ([]entities.CustomFieldDefinition, bool, error) {
+	rawPatch, patched := explicitFields[orderCustomFieldsPatchKey]
+	typeChanged := utils.DiffPtr(current.OrderTypeID, updated.OrderTypeID)
+	if !patched && !typeChanged {
+		return nil, false, nil
+	}
+
+	var defs []entities.CustomFieldDefinition
+	if updated.OrderTypeID != nil {
+		var err error
+		if defs, err = s.customFieldRepo.FindByOrderType(ctx, *updated.OrderTypeID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	merged := make(map[string]any, len(current.CustomFields))
+	for _, def := range defs {
+		if v, ok := current.CustomFields[def.Code]; ok {
+			merged[def.Code] = v
+		}
+	}
+
+	if patched {
+		if rawPatch == nil {
+			clear(merged)
+		} else {
+			patch, ok := rawPatch.(map[string]interface{})
+			if !ok {
+				return nil, false, apperrors.NewBadRequestError("Поле custom_fields должно быть JSON-объектом.")
+			}
+			if err := rejectUnknownCustomFields(defs, patch); err != nil {
+				return nil, false, err
+			}
+			for code, v := range patch {
+				if v == nil {
+					delete(merged, code)
+					continue
+				}
+				merged[code] = v
+			}
+		}
+	}
+
+	normalized, err := normalizeCustomFields(defs, merged)
+	if err != nil {
+		return nil, false, err
+	}
+	updated.CustomFields = normalized
+	return defs, !reflect.DeepEqual(customFieldsValueOrEmpty(current.CustomFields), normalized), nil
+}
+
+// logCustomFieldChanges пишет CUSTOM_FIELD_CHANGE по каждому изменившемуся полю.
+func (s *OrderService) logCustomFieldChanges(ctx context.Context, tx pgx.Tx, old, new *entities.Order, defs []entities.CustomFieldDefinition, actor *entities.User, txID uuid.UUID) error {
+	byCode := make(map[string]entities.CustomFieldDefinition, len(defs))
+	for _, def := range defs {
+		byCode[def.Code] = def
+	}
+
+	codes := slices.Collect(maps.Keys(old.CustomFields))
+	for code := range new.CustomFields {
+		if _, ok := old.CustomFields[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		oldValue, hadOld := old.CustomFields[code]
+		newValue, hasNew := new.CustomFields[code]
+		if hadOld == hasNew && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		def, ok := byCode[code]
+		if !ok {
+			def = entities.CustomFieldDefinition{Code: code, Name: code}
+		}
+		var oldText, newText *string
+		if hadOld {
+			v := formatCustomFieldValue(def, oldValue)
+			oldText = &v
+		}
+		if hasNew {
+			v := formatCustomFieldValue(def, newValue)
+			newText = &v
+		}
+
+		comment := fmt.Sprintf("Изменено поле «%s»: %s → %s", def.Name, customFieldHistoryText(oldText), customFieldHistoryText(newText))
+		if err := s.logHistoryEvent(ctx, tx, new.ID, actor, "CUSTOM_FIELD_CHANGE", newText, oldText, &comment, txID, *new); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func customFieldHistoryText(v *string) string {
+	if v == nil || *v == "" {
+		return "—"
+	}
+	return "«" + *v + "»"
+}
+
+// customFieldsSummary — «Название: значение; ...» в порядке полей типа заявки, для выгрузки.
+func customFieldsSummary(defs []entities.CustomFieldDefinition, values map[string]any) string {
+	parts := make([]string, 0, len(values))
+	for _, def := range defs {
+		if v, ok := values[def.Code]; ok {
+			parts = append(parts, def.Name+": "+formatCustomFieldValue(def, v))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func rejectUnknownCustomFields(defs []entities.CustomFieldDefinition, values map[string]any) error {
+	for code, v := range values {
+		if v == nil {
+			continue
+		}
+		if !slices.ContainsFunc(defs, func(d entities.CustomFieldDefinition) bool { return d.Code == code }) {
+			return apperrors.NewHttpError(http.StatusBadRequest,
+				fmt.Sprintf("У этого типа заявки нет поля «%s».", code), nil,
+				map[string]interface{}{"field": "custom_fields." + code})
+		}
+	}
+	return nil
+}
+
+// normalizeCustomFields приводит значения к типам полей и проверяет обязательные.
+// Пустые значения отбрасываются; ключи без определения должны быть отсеяны заранее.
+func normalizeCustomFields(defs []entities.CustomFieldDefinition, values map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(values))
+	for _, def := range defs {
+		value, present, err := normalizeCustomFieldValue(def, values[def.Code])
+		if err != nil {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest,
+				fmt.Sprintf("Поле «%s»: %s", def.Name, err.Error()), nil,
+				map[string]interface{}{"field": "custom_fields." + def.Code})
+		}
+		if !present {
+			if def.IsRequired {
+				return nil, apperrors.NewHttpError(http.StatusBadRequest,
+					fmt.Sprintf("Заполните поле «%s».", def.Name), nil,
+					map[string]interface{}{"field": "custom_fields." + def.Code})
+			}
+			continue
+		}
+		result[def.Code] = value
+	}
+	return result, nil
+}
+
+func normalizeCustomFieldValue(def entities.CustomFieldDefinition, raw any) (any, bool, error) {
+	if raw == nil {
+		return nil, false, nil
+	}
+	text, isString := raw.(string)
+	if isString {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, false, nil
+		}
+	}
+
+	switch def.FieldType {
+	case entities.CustomFieldText:
+		if !isString {
+			return nil, false, fmt.Errorf("ожидается текст")
+		}
+		if utf8.RuneCountInString(text) > customFieldTextMaxLen {
+			return nil, false, fmt.Errorf("не длиннее %d символов", customFieldTextMaxLen)
+		}
+		return text, true, nil
+	case entities.CustomFieldNumber:
+		switch v := raw.(type) {
+		case float64:
+			return v, true, nil
+		case int:
+			return float64(v), true, nil
+		case int64:
+			return float64(v), true, nil
+		case string:
+			n, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+			if err != nil {
+				return nil, false, fmt.Errorf("ожидается число")
+			}
+			return n, true, nil
+		}
+		return nil, false, fmt.Errorf("ожидается число")
+	case entities.CustomFieldDate:
+		if !isString {
+			return nil, false, fmt.Errorf("ожидается дата в формате ГГГГ-ММ-ДД")
+		}
+		if t, err := time.Parse(time.DateOnly, text); err == nil {
+			return t.Format(time.DateOnly), true, nil
+		}
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return t.Format(time.DateOnly), true, nil
+		}
+		return nil, false, fmt.Errorf("ожидается дата в формате ГГГГ-ММ-ДД")
+	case entities.CustomFieldBoolean:
+		switch v := raw.(type) {
+		case bool:
+			return v, true, nil
+		case string:
+			if b, err := strconv.ParseBool(text); err == nil {
+				return b, true, nil
+			}
+		}
+		return nil, false, fmt.Errorf("ожидается true или false")
+	case entities.CustomFieldSelect:
+		if !isString || !slices.Contains(def.Options, text) {
+			return nil, false, fmt.Errorf("выберите одно из значений: %s", strings.Join(def.Options, ", "))
+		}
+		return text, true, nil
+	}
+	return nil, false, fmt.Errorf("неизвестный тип поля %q", def.FieldType)
+}
+
+func formatCustomFieldValue(def entities.CustomFieldDefinition, v any) string {
+	switch value := v.(type) {
+	case bool:
+		if value {
+			return "Да"
+		}
+		return "Нет"
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case string:
+		if def.FieldType == entities.CustomFieldDate {
+			if t, err := time.Parse(time.DateOnly, value); err == nil {
+				return t.Format("02.01.2006")
+			}
+		}
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func customFieldsValueOrEmpty(fields map[string]any) map[string]any {
+	if fields == nil {
+		return map[string]any{}
+	}
+	return fields
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"request-system/internal/entities"
+)
+
+func TestNormalizeCustomFields(t *testing.T) {
+	defs := []entities.CustomFieldDefinition{
+		{Code: "atm", Name: "Банкомат", FieldType: entities.CustomFieldSelect, IsRequired: true, Options: []string{"NCR", "Wincor"}},
+		{Code: "amount", Name: "Сумма", FieldType: entities.CustomFieldNumber},
+		{Code: "due", Name: "Срок", FieldType: entities.CustomFieldDate},
+		{Code: "urgent", Name: "Срочно", FieldType: entities.CustomFieldBoolean},
+	}
+
+	got, err := normalizeCustomFields(defs, map[string]any{
+		"atm": " NCR ", "amount": "1500,5", "due": "2026-10-20T10:00:00Z", "urgent": "true",
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if got["atm"] != "NCR" || got["amount"] != 1500.5 || got["due"] != "2026-10-20" || got["urgent"] != true {
+		t.Fatalf("значения не приведены к типам полей: %#v", got)
+	}
+
+	if _, err := normalizeCustomFields(defs, map[string]any{"amount": 1.0}); err == nil {
+		t.Fatal("ожидалась ошибка: не заполнено обязательное поле")
+	}
+	if _, err := normalizeCustomFields(defs, map[string]any{"atm": "Diebold"}); err == nil {
+		t.Fatal("ожидалась ошибка: значения нет в списке выбора")
+	}
+	if err := rejectUnknownCustomFields(defs, map[string]any{"serial": "123"}); err == nil {
+		t.Fatal("ожидалась ошибка: у типа заявки нет такого поля")
+	}
+}
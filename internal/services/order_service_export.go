@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+)
+
+const (
+	// OrderExportMaxRows — больше заявок за одну выгрузку не отдаём: пусть уточнят фильтр.
+	OrderExportMaxRows   = 50000
+	orderExportChunkSize = 1000
+)
+
+// ExportOrders выгружает заявки под тем же фильтром и теми же ограничениями видимости, что и GetOrders.
+// Заявки читаются пачками, каждая пачка передаётся в write сразу — весь список в памяти не держим.
+// Если под фильтр попадает больше OrderExportMaxRows, возвращается ошибка до первого вызова write.
+func (s *OrderService) ExportOrders(ctx context.Context, filter types.Filter, onlyCreated, onlyAssigned, onlyInvolved bool, write func([]dto.OrderExportRowDTO) error) (uint64, error) {
+	securityBuilder, visible, err := s.orderListSecurity(ctx, onlyCreated, onlyAssigned, onlyInvolved)
+	if err != nil {
+		return 0, err
+	}
+	if !visible {
+		return 0, nil
+	}
+
+	// Репозиторий удаляет спец-фильтры из filter.Filter, поэтому каждому запросу — своя копия.
+	countFilter := filter
+	countFilter.Filter = maps.Clone(filter.Filter)
+	countFilter.WithPagination = true
+	countFilter.Limit = 1
+	countFilter.Offset = 0
+	_, total, err := s.orderRepo.GetOrders(ctx, countFilter, securityBuilder)
+	if err != nil {
+		return 0, err
+	}
+	if total > OrderExportMaxRows {
+		return 0, apperrors.NewBadRequestError(fmt.Sprintf("Под фильтр попадает %d заявок, выгрузить можно не больше %d. Уточните фильтр.", total, OrderExportMaxRows))
+	}
+
+	var exported uint64
+	for exported < total {
+		chunkFilter := filter
+		chunkFilter.Filter = maps.Clone(filter.Filter)
+		chunkFilter.WithPagination = false
+		chunkFilter.Limit = orderExportChunkSize
+		chunkFilter.Offset = int(exported)
+
+		orders, _, err := s.orderRepo.GetOrders(ctx, chunkFilter, securityBuilder)
+		if err != nil {
+			return exported, err
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		rows, err := s.orderExportRows(ctx, orders)
+		if err != nil {
+			return exported, err
+		}
+		if err := write(rows); err != nil {
+			return exported, err
+		}
+		exported += uint64(len(orders))
+		if len(orders) < orderExportChunkSize {
+			break
+		}
+	}
+
+	s.logger.Info("Выгрузка заявок", zap.Uint64("rows", exported))
+	return exported, nil
+}
+
+// FindOrderSummary проверяет право просмотра так же, как FindOrderByID.
+func (s *OrderService) FindOrderSummary(ctx context.Context, orderID uint64) (*dto.OrderExportRowDTO, error) {
+	authCtx, err := s.buildAuthzContext(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.OrdersView, *authCtx) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	rows, err := s.orderExportRows(ctx, []entities.Order{*authCtx.Target.(*entities.Order)})
+	if err != nil {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+func (s *OrderService) orderExportRows(ctx context.Context, orders []entities.Order) ([]dto.OrderExportRowDTO, error) {
+	ids := make([]uint64, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+	names, err := s.orderRepo.FindExportNames(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	customFieldDefs, err := s.exportCustomFieldDefinitions(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]dto.OrderExportRowDTO, len(orders))
+	for i, o := range orders {
+		n := names[o.ID]
+		rows[i] = dto.OrderExportRowDTO{
+			ID:            o.ID,
+			Number:        o.DisplayNumber(),
+			Name:          o.Name,
+			Status:        n.Status,
+			Priority:      n.Priority,
+			OrderType:     n.OrderType,
+			Department:    n.Department,
+			Otdel:         n.Otdel,
+			Branch:        n.Branch,
+			Office:        n.Office,
+			EquipmentType: n.EquipmentType,
+			Equipment:     n.Equipment,
+			Creator:       o.CreatorName,
+			CreatedAt:     o.CreatedAt,
+			Duration:      o.Duration,
+			CompletedAt:   o.CompletedAt,
+		}
+		if o.Address != nil {
+			rows[i].Address = *o.Address
+		}
+		if o.ExecutorName != nil {
+			rows[i].Executor = *o.ExecutorName
+		}
+		if len(o.CustomFields) > 0 && o.OrderTypeID != nil {
+			rows[i].CustomFields = customFieldsSummary(customFieldDefs[*o.OrderTypeID], o.CustomFields)
+		}
+	}
+	return rows, nil
+}
+
+// exportCustomFieldDefinitions — определения дополнительных полей по типам заявок пачки.
+// Если ни у одной заявки полей нет, в базу не ходим.
+func (s *OrderService) exportCustomFieldDefinitions(ctx context.Context, orders []entities.Order) (map[uint64][]entities.CustomFieldDefinition, error) {
+	var typeIDs []uint64
+	for _, o := range orders {
+		if len(o.CustomFields) > 0 && o.OrderTypeID != nil && !slices.Contains(typeIDs, *o.OrderTypeID) {
+			typeIDs = append(typeIDs, *o.OrderTypeID)
+		}
+	}
+	result := make(map[uint64][]entities.CustomFieldDefinition, len(typeIDs))
+	if len(typeIDs) == 0 {
+		return result, nil
+	}
+	defs, err := s.customFieldRepo.FindByOrderTypes(ctx, typeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range defs {
+		result[def.OrderTypeID] = append(result[def.OrderTypeID], def)
+	}
+	return result, nil
+}
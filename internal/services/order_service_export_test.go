@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	"request-system/pkg/types"
+)
+
+type exportOrderRepoStub struct {
+	repositories.OrderRepositoryInterface
+	orders       []entities.Order
+	overdueSeen  int
+	listRequests int
+}
+
+func (s *exportOrderRepoStub) GetOrders(_ context.Context, filter types.Filter, _ sq.Sqlizer) ([]entities.Order, uint64, error) {
+	s.listRequests++
+	if _, ok := filter.Filter["overdue"]; ok {
+		s.overdueSeen++
+	}
+	// Как и настоящий репозиторий, забираем спец-фильтр из map.
+	delete(filter.Filter, "overdue")
+
+	start := min(filter.Offset, len(s.orders))
+	end := min(start+filter.Limit, len(s.orders))
+	return s.orders[start:end], uint64(len(s.orders)), nil
+}
+
+func (s *exportOrderRepoStub) FindExportNames(_ context.Context, ids []uint64) (map[uint64]repositories.OrderExportNames, error) {
+	names := make(map[uint64]repositories.OrderExportNames, len(ids))
+	for _, id := range ids {
+		names[id] = repositories.OrderExportNames{Status: "Открыта"}
+	}
+	return names, nil
+}
+
+func exportTestContext() context.Context {
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserEntityKey, &entities.User{ID: 1})
+	return context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.OrdersView: true, authz.ScopeAll: true})
+}
+
+func TestExportOrders_StreamsInChunksWithSameFilter(t *testing.T) {
+	repo := &exportOrderRepoStub{}
+	for id := uint64(1); id <= 2500; id++ {
+		repo.orders = append(repo.orders, entities.Order{ID: id, Name: "Заявка"})
+	}
+	service := &OrderService{orderRepo: repo, logger: zap.NewNop()}
+
+	filter := types.Filter{Filter: map[string]interface{}{"overdue": "true"}}
+	var chunks, rows int
+	exported, err := service.ExportOrders(exportTestContext(), filter, false, false, false, func(batch []dto.OrderExportRowDTO) error {
+		chunks++
+		rows += len(batch)
+		if batch[0].Status != "Открыта" {
+			t.Fatalf("expected resolved status name, got %q", batch[0].Status)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportOrders returned error: %v", err)
+	}
+	if exported != 2500 || rows != 2500 || chunks != 3 {
+		t.Fatalf("expected 2500 rows in 3 chunks, got exported=%d rows=%d chunks=%d", exported, rows, chunks)
+	}
+	if repo.overdueSeen != repo.listRequests {
+		t.Fatalf("expected every request to keep the overdue filter, got %d of %d", repo.overdueSeen, repo.listRequests)
+	}
+}
+
+func TestExportOrders_RejectsTooLargeExport(t *testing.T) {
+	repo := &exportOrderRepoStub{orders: make([]entities.Order, OrderExportMaxRows+1)}
+	service := &OrderService{orderRepo: repo, logger: zap.NewNop()}
+
+	called := false
+	_, err := service.ExportOrders(exportTestContext(), types.Filter{}, false, false, false, func([]dto.OrderExportRowDTO) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Fatalf("expected an error before writing, got err=%v called=%v", err, called)
+	}
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"strings"
 	"time"
@@ -94,7 +96,15 @@ func (s *OrderService) detectAndLogChanges(ctx context.Context, tx pgx.Tx, old,
 		hasLoggable = true
 	}
 
-	if utils.DiffPtr(old.ExecutorID, new.ExecutorID) {
+	if utils.DiffPtr(old.TeamID, new.TeamID) && new.TeamID != nil {
+		if err := s.logTeamAssignment(ctx, tx, new, actor, txID); err != nil {
+			return false, err
+		}
+		hasLoggable = true
+	}
+
+	// Заявка ушла в очередь команды: исполнителя ещё нет, событие назначения пишет TEAM_ASSIGN
+	if utils.DiffPtr(old.ExecutorID, new.ExecutorID) && (new.ExecutorID != nil || new.TeamID == nil) {
 		newExName := s.resolveUserName(ctx, new.ExecutorID)
 		txt := "Назначено на: " + newExName
 		valNew := utils.PtrToString(new.ExecutorID)
@@ -122,6 +132,19 @@ func (s *OrderService) detectAndLogChanges(ctx context.Context, tx pgx.Tx, old,
 		hasLoggable = true
 	}
 
+	if utils.DiffPtr(old.ResolutionCodeID, new.ResolutionCodeID) {
+		valNew := utils.PtrToString(new.ResolutionCodeID)
+		valOld := utils.PtrToString(old.ResolutionCodeID)
+		txt := "Код решения снят"
+		if new.ResolutionCodeID != nil {
+			txt = "Код решения: «" + s.resolveResolutionCodeName(ctx, *new.ResolutionCodeID) + "»"
+		}
+		if err := s.logHistoryEvent(ctx, tx, new.ID, actor, "RESOLUTION_CHANGE", &valNew, &valOld, &txt, txID, *new); err != nil {
+			return false, err
+		}
+		hasLoggable = true
+	}
+
 	if utils.DiffPtr(old.PriorityID, new.PriorityID) {
 		valNew := utils.PtrToString(new.PriorityID)
 		valOld := utils.PtrToString(old.PriorityID)
@@ -131,6 +154,19 @@ func (s *OrderService) detectAndLogChanges(ctx context.Context, tx pgx.Tx, old,
 		hasLoggable = true
 	}
 
+	if !utils.StringPtrEqual(old.Impact, new.Impact) {
+		if err := s.logHistoryEvent(ctx, tx, new.ID, actor, "IMPACT_CHANGE", new.Impact, old.Impact, nil, txID, *new); err != nil {
+			return false, err
+		}
+		hasLoggable = true
+	}
+	if !utils.StringPtrEqual(old.Urgency, new.Urgency) {
+		if err := s.logHistoryEvent(ctx, tx, new.ID, actor, "URGENCY_CHANGE", new.Urgency, old.Urgency, nil, txID, *new); err != nil {
+			return false, err
+		}
+		hasLoggable = true
+	}
+
 	if utils.DiffPtr(old.DepartmentID, new.DepartmentID) ||
 		utils.DiffPtr(old.OtdelID, new.OtdelID) ||
 		utils.DiffPtr(old.BranchID, new.BranchID) ||
@@ -160,6 +196,15 @@ func (s *OrderService) detectAndLogChanges(ctx context.Context, tx pgx.Tx, old,
 	return hasLoggable, nil
 }
 
+// resolveResolutionCodeName — название кода решения для истории; если справочник недоступен, пишется ID.
+func (s *OrderService) resolveResolutionCodeName(ctx context.Context, id uint64) string {
+	code, err := s.resolutionCodeRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Sprintf("#%d", id)
+	}
+	return code.Name
+}
+
 func (s *OrderService) attachFileToOrderInTx(ctx context.Context, tx pgx.Tx, orderID, userID uint64, file *multipart.FileHeader, txID *uuid.UUID, order *entities.Order) (uint64, error) {
 	reader, err := file.Open()
 	if err != nil {
@@ -167,14 +212,18 @@ func (s *OrderService) attachFileToOrderInTx(ctx context.Context, tx pgx.Tx, ord
 	}
 	defer reader.Close()
 
-	filePath, err := s.fileStorage.Save(reader, file.Filename, "orders")
+	return s.attachReaderToOrderInTx(ctx, tx, orderID, userID, reader, file.Filename, file.Header.Get("Content-Type"), file.Size, txID, order)
+}
+
+func (s *OrderService) attachReaderToOrderInTx(ctx context.Context, tx pgx.Tx, orderID, userID uint64, reader io.Reader, fileName, fileType string, fileSize int64, txID *uuid.UUID, order *entities.Order) (uint64, error) {
+	filePath, err := s.fileStorage.Save(reader, fileName, "orders")
 	if err != nil {
 		return 0, err
 	}
 
 	attach := &entities.Attachment{
-		OrderID: orderID, UserID: userID, FileName: file.Filename, FilePath: filePath,
-		FileType: file.Header.Get("Content-Type"), FileSize: file.Size, CreatedAt: time.Now(),
+		OrderID: orderID, UserID: userID, FileName: fileName, FilePath: filePath,
+		FileType: fileType, FileSize: fileSize, CreatedAt: time.Now(),
 	}
 	id, err := s.attachRepo.CreateInTx(ctx, tx, attach)
 	if err != nil {
@@ -185,7 +234,7 @@ func (s *OrderService) attachFileToOrderInTx(ctx context.Context, tx pgx.Tx, ord
 
 	historyItem := &repositories.OrderHistoryItem{
 		OrderID: orderID, UserID: userID, EventType: "ATTACHMENT_ADD",
-		NewValue: s.toNullStr(file.Filename), Attachment: attach, AttachmentID: sql.NullInt64{Int64: int64(id), Valid: true},
+		NewValue: s.toNullStr(fileName), Attachment: attach, AttachmentID: sql.NullInt64{Int64: int64(id), Valid: true},
 		TxID: txID, CreatedAt: time.Now(), CreatorFio: s.toNullStr(actor.Fio),
 	}
 	if err := s.addHistoryAndPublish(ctx, tx, historyItem, *order, actor); err != nil {
@@ -198,8 +247,12 @@ func (s *OrderService) addHistoryAndPublish(ctx context.Context, tx pgx.Tx, item
 	if err := s.historyRepo.CreateInTx(ctx, tx, item); err != nil {
 		return err
 	}
-	s.eventBus.Publish(ctx, events.OrderHistoryCreatedEvent{HistoryItem: *item, Order: &o, Actor: a})
-	return nil
+	// Событие пишется в event_outbox той же транзакцией и уходит в eventbus только после коммита.
+	payload, err := json.Marshal(events.NewOrderHistoryCreatedRecord(*item, &o, a))
+	if err != nil {
+		return err
+	}
+	return s.eventOutbox.CreateInTx(ctx, tx, events.OrderHistoryCreatedEvent{}.Name(), &item.OrderID, payload)
 }
 
 func (s *OrderService) logHistoryEvent(ctx context.Context, tx pgx.Tx, oid uint64, actor *entities.User, evtType string, newVal, oldVal, comment *string, txID uuid.UUID, ord entities.Order) error {
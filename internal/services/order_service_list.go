@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"go.uber.org/zap"
@@ -9,61 +10,66 @@ import (
 	"request-system/internal/authz"
 	"request-system/internal/dto"
 	"request-system/internal/entities"
+	"request-system/internal/repositories"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
 	"request-system/pkg/utils"
 )
 
 func (s *OrderService) GetOrders(ctx context.Context, filter types.Filter, onlyCreated bool, onlyAssigned bool, onlyInvolved bool) (*dto.OrderListResponseDTO, error) {
+	securityBuilder, visible, err := s.orderListSecurity(ctx, onlyCreated, onlyAssigned, onlyInvolved)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return &dto.OrderListResponseDTO{List: []dto.OrderResponseDTO{}, TotalCount: 0}, nil
+	}
+
+	return s.getOrdersCached(ctx, filter, securityBuilder, func() (*dto.OrderListResponseDTO, error) {
+		orders, totalCount, err := s.orderRepo.GetOrders(ctx, filter, securityBuilder)
+		if err != nil {
+			return nil, err
+		}
+		if len(orders) == 0 {
+			return &dto.OrderListResponseDTO{List: []dto.OrderResponseDTO{}, TotalCount: 0}, nil
+		}
+
+		dtos := s.mapOrdersToDTOs(ctx, orders, filter.IncludeAttachments)
+		return &dto.OrderListResponseDTO{List: dtos, TotalCount: totalCount}, nil
+	})
+}
+
+// orderListSecurity строит условия видимости списка заявок для текущего пользователя.
+// visible = false, если по своим правам он не видит ни одной заявки (сейчас так не бывает:
+// у любого остаются заявки с разовым доступом).
+func (s *OrderService) orderListSecurity(ctx context.Context, onlyCreated, onlyAssigned, onlyInvolved bool) (securityBuilder sq.And, visible bool, err error) {
 	userID, err := utils.GetUserIDFromCtx(ctx)
 	if err != nil {
-		return nil, apperrors.ErrUserNotFound
+		return nil, false, apperrors.ErrUserNotFound
 	}
 
 	permissionsMap, err := s.resolvePermissionsMap(ctx, userID)
 	if err != nil {
-		return nil, apperrors.ErrUnauthorized
+		return nil, false, apperrors.ErrUnauthorized
 	}
 
 	actor, err := s.resolveActorFromContext(ctx, userID)
 	if err != nil {
-		return nil, apperrors.ErrUserNotFound
+		return nil, false, apperrors.ErrUserNotFound
 	}
 
-	authCtx := authz.Context{Actor: actor, Permissions: permissionsMap}
+	authCtx := authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}
 	if !authz.CanDo(authz.OrdersView, authCtx) {
 		s.logger.Warn("Попытка доступа без прав на просмотр заявок", zap.Uint64("user_id", userID))
-		return nil, apperrors.ErrForbidden
+		return nil, false, apperrors.ErrForbidden
 	}
 
-	securityBuilder := sq.And{}
+	securityBuilder = sq.And{}
 
-	if !authCtx.HasPermission(authz.ScopeAll) && !authCtx.HasPermission(authz.ScopeAllView) {
-		scopeConditions := sq.Or{}
-
-		if authCtx.HasPermission(authz.ScopeDepartment) && actor.DepartmentID != nil {
-			scopeConditions = append(scopeConditions, sq.Eq{"o.department_id": *actor.DepartmentID})
-		}
-		if authCtx.HasPermission(authz.ScopeBranch) && actor.BranchID != nil {
-			scopeConditions = append(scopeConditions, sq.Eq{"o.branch_id": *actor.BranchID})
-		}
-		if authCtx.HasPermission(authz.ScopeOtdel) && actor.OtdelID != nil {
-			scopeConditions = append(scopeConditions, sq.Eq{"o.otdel_id": *actor.OtdelID})
-		}
-		if authCtx.HasPermission(authz.ScopeOffice) && actor.OfficeID != nil {
-			scopeConditions = append(scopeConditions, sq.Eq{"o.office_id": *actor.OfficeID})
-		}
-		if authCtx.HasPermission(authz.ScopeOwn) {
-			scopeConditions = append(scopeConditions, sq.Eq{"o.user_id": actor.ID})
-			scopeConditions = append(scopeConditions, sq.Eq{"o.executor_id": actor.ID})
-			scopeConditions = append(scopeConditions, sq.Expr("o.id IN (SELECT DISTINCT order_id FROM order_history WHERE user_id = ?)", actor.ID))
-		}
-
-		if len(scopeConditions) == 0 {
-			return &dto.OrderListResponseDTO{List: []dto.OrderResponseDTO{}, TotalCount: 0}, nil
-		}
-
-		securityBuilder = append(securityBuilder, scopeConditions)
+	visibility := authz.NewScopeQueryBuilder("o").WithParticipation().Build(authCtx, authz.OrdersView, time.Now())
+	if visibility != nil {
+		// Заявки, к которым выдан разовый доступ, видны независимо от областей и условий права
+		securityBuilder = append(securityBuilder, sq.Or{visibility, sq.Expr(repositories.ActiveOrderAccessGrantCondition, actor.ID)})
 	}
 
 	if onlyCreated {
@@ -86,16 +92,7 @@ func (s *OrderService) GetOrders(ctx context.Context, filter types.Filter, onlyC
 		)
 	}
 
-	orders, totalCount, err := s.orderRepo.GetOrders(ctx, filter, securityBuilder)
-	if err != nil {
-		return nil, err
-	}
-	if len(orders) == 0 {
-		return &dto.OrderListResponseDTO{List: []dto.OrderResponseDTO{}, TotalCount: 0}, nil
-	}
-
-	dtos := s.mapOrdersToDTOs(ctx, orders, filter.IncludeAttachments)
-	return &dto.OrderListResponseDTO{List: dtos, TotalCount: totalCount}, nil
+	return securityBuilder, true, nil
 }
 
 func (s *OrderService) FindOrderByID(ctx context.Context, orderID uint64) (*dto.OrderResponseDTO, error) {
@@ -109,7 +106,9 @@ func (s *OrderService) FindOrderByID(ctx context.Context, orderID uint64) (*dto.
 
 	order := authCtx.Target.(*entities.Order)
 	attachments := s.loadOrderAttachments(ctx, order.ID, 100, 0)
-	return s.toResponseDTO(order, nil, nil, attachments), nil
+	result := s.toResponseDTO(order, nil, nil, attachments)
+	s.applyFirstResponseCountdown(ctx, result, order, s.slaCalendar(ctx), time.Now())
+	return result, nil
 }
 
 func (s *OrderService) FindOrderByIDForTelegram(ctx context.Context, userID uint64, orderID uint64) (*entities.Order, error) {
@@ -140,7 +139,7 @@ func (s *OrderService) FindOrderByIDForTelegram(ctx context.Context, userID uint
 		return nil, apperrors.ErrUserNotFound
 	}
 
-	authCtx := authz.Context{Actor: user, Permissions: permissionsMap, Target: order}
+	authCtx := authz.Context{Actor: user, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx), Target: order}
 	if !authz.CanDo(authz.OrdersView, authCtx) {
 		s.logger.Warn("Попытка доступа к заявке без прав через Telegram", zap.Uint64("user_id", userID), zap.Uint64("order_id", orderID), zap.String("user_fio", user.Fio))
 		return nil, apperrors.ErrForbidden
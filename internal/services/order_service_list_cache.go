@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/types"
+	"request-system/pkg/utils"
+)
+
+// orderListCacheTTL — кэш первой страницы живёт секунды: его задача — пережить наплыв,
+// когда все диспетчеры открывают доску в начале дня, а не экономить на каждом запросе.
+const orderListCacheTTL = 5 * time.Second
+
+// orderListCacheable — кэшируется только первая страница без поиска и фильтров:
+// такие списки одинаковы у всех пользователей с одними и теми же областями видимости.
+func orderListCacheable(filter types.Filter) bool {
+	return filter.Offset == 0 && strings.TrimSpace(filter.Search) == "" && len(filter.Filter) == 0 &&
+		filter.DateFrom == nil && filter.DateTo == nil && len(filter.ExecutorIDs) == 0
+}
+
+// orderListCacheKey — ключ по подписи области видимости: SQL и параметры условия видимости,
+// организация, параметры страницы и версия, которую сбрасывают события истории заявок.
+func orderListCacheKey(ctx context.Context, filter types.Filter, security sq.Sqlizer, version string) (string, error) {
+	securitySQL, securityArgs, err := security.ToSql()
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"security":    securitySQL,
+		"args":        securityArgs,
+		"tenant":      utils.TenantIDOrDefault(ctx),
+		"limit":       filter.Limit,
+		"sort":        filter.Sort,
+		"pagination":  filter.WithPagination,
+		"attachments": filter.IncludeAttachments,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return fmt.Sprintf("orders:list:v%s:%s", version, hex.EncodeToString(sum[:16])), nil
+}
+
+// getOrdersCached отдаёт первую страницу из Redis, а при промахе считает её один раз на все
+// одновременные запросы с той же подписью. Любая ошибка кэша — просто запрос в БД.
+func (s *OrderService) getOrdersCached(ctx context.Context, filter types.Filter, security sq.Sqlizer, load func() (*dto.OrderListResponseDTO, error)) (*dto.OrderListResponseDTO, error) {
+	if s.cacheRepo == nil || !orderListCacheable(filter) {
+		return load()
+	}
+
+	version, err := s.cacheRepo.Get(ctx, pkgconstants.OrderListCacheVersionKey)
+	if err != nil || strings.TrimSpace(version) == "" {
+		version = "0"
+	}
+	key, err := orderListCacheKey(ctx, filter, security, version)
+	if err != nil {
+		s.logger.Warn("Не удалось построить ключ кэша списка заявок", zap.Error(err))
+		return load()
+	}
+
+	if cached, err := s.cacheRepo.Get(ctx, key); err == nil {
+		var result dto.OrderListResponseDTO
+		if err := json.Unmarshal([]byte(cached), &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	value, err, _ := s.listFlight.Do(key, func() (interface{}, error) {
+		result, err := load()
+		if err != nil {
+			return nil, err
+		}
+		if payload, err := json.Marshal(result); err == nil {
+			if err := s.cacheRepo.Set(ctx, key, string(payload), orderListCacheTTL); err != nil {
+				s.logger.Warn("Не удалось сохранить список заявок в кэш", zap.Error(err))
+			}
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*dto.OrderListResponseDTO), nil
+}
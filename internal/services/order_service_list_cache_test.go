@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/types"
+)
+
+func TestGetOrders_CachesFirstUnfilteredPage(t *testing.T) {
+	repo := &exportOrderRepoStub{orders: []entities.Order{{ID: 1, Name: "Заявка"}, {ID: 2, Name: "Заявка"}}}
+	cache := &memoryCache{values: map[string]string{}}
+	service := &OrderService{orderRepo: repo, cacheRepo: cache, logger: zap.NewNop()}
+	ctx := exportTestContext()
+
+	firstPage := types.Filter{Limit: 10, Page: 1, WithPagination: true}
+	for range 3 {
+		list, err := service.GetOrders(ctx, firstPage, false, false, false)
+		if err != nil {
+			t.Fatalf("GetOrders returned error: %v", err)
+		}
+		if list.TotalCount != 2 || len(list.List) != 2 {
+			t.Fatalf("unexpected list: total=%d len=%d", list.TotalCount, len(list.List))
+		}
+	}
+	if repo.listRequests != 1 {
+		t.Fatalf("expected first page to be served from cache, got %d repository calls", repo.listRequests)
+	}
+
+	if _, err := service.GetOrders(ctx, types.Filter{Limit: 10, Offset: 10}, false, false, false); err != nil {
+		t.Fatalf("GetOrders returned error: %v", err)
+	}
+	if _, err := service.GetOrders(ctx, types.Filter{Limit: 10, Search: "принтер"}, false, false, false); err != nil {
+		t.Fatalf("GetOrders returned error: %v", err)
+	}
+	if repo.listRequests != 3 {
+		t.Fatalf("expected later pages and searches to bypass the cache, got %d repository calls", repo.listRequests)
+	}
+
+	if _, err := cache.Incr(ctx, pkgconstants.OrderListCacheVersionKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetOrders(ctx, firstPage, false, false, false); err != nil {
+		t.Fatalf("GetOrders returned error: %v", err)
+	}
+	if repo.listRequests != 4 {
+		t.Fatalf("expected version bump to invalidate the cached page, got %d repository calls", repo.listRequests)
+	}
+}
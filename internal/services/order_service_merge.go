@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+)
+
+const (
+	// Сколько свежих заявок по оборудованию сравнивать с названием и сколько из них показать.
+	duplicateHintScanLimit  = 50
+	duplicateHintMaxResults = 5
+	// Порог похожести названий (доля общих триграмм, как similarity() в pg_trgm).
+	duplicateHintMinSimilarity = 0.4
+)
+
+// MergeOrder закрывает заявку orderID как дубликат заявки mergeDTO.TargetID.
+// Вложения дубликата переносятся в основную заявку, комментарии копируются в её историю
+// (история дубликата не переписывается, цепочка хэшей остаётся целой), участники дубликата
+// становятся участниками основной заявки. Обе заявки ссылаются друг на друга в истории.
+func (s *OrderService) MergeOrder(ctx context.Context, orderID uint64, mergeDTO dto.MergeOrderDTO) (*dto.OrderResponseDTO, error) {
+	mergeDTO.Sanitize()
+	if mergeDTO.TargetID == orderID {
+		return nil, apperrors.NewBadRequestError("Нельзя объединить заявку саму с собой.")
+	}
+
+	source, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.orderRepo.FindByID(ctx, mergeDTO.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.DuplicateOfID != nil {
+		return nil, apperrors.NewBadRequestError(fmt.Sprintf("Заявка уже закрыта как дубликат заявки с ID %d.", *source.DuplicateOfID))
+	}
+	if target.DuplicateOfID != nil {
+		return nil, apperrors.NewBadRequestError(fmt.Sprintf("Заявка №%s сама закрыта как дубликат заявки с ID %d. Объединяйте с ней.", target.DisplayNumber(), *target.DuplicateOfID))
+	}
+	if status, _ := s.statuses.FindByID(ctx, source.StatusID); isOrderLocked(status) {
+		return nil, apperrors.NewBadRequestError("Заявка закрыта. Объединение невозможно.")
+	}
+	if status, _ := s.statuses.FindByID(ctx, target.StatusID); isOrderLocked(status) {
+		return nil, apperrors.NewBadRequestError(fmt.Sprintf("Заявка №%s закрыта. Объединение невозможно.", target.DisplayNumber()))
+	}
+
+	sourceAuth, err := s.buildAuthzContextWithTarget(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	targetAuth, err := s.buildAuthzContextWithTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.OrdersMerge, *sourceAuth) || !authz.CanDo(authz.OrdersMerge, *targetAuth) {
+		return nil, apperrors.ErrForbidden
+	}
+	actor := sourceAuth.Actor
+
+	sourceHistory, err := s.historyRepo.FindChainByOrderID(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	participants := s.participantsToCarryOver(ctx, source, target, sourceHistory, actor.ID)
+
+	var comment *string
+	if mergeDTO.Comment != nil && strings.TrimSpace(*mergeDTO.Comment) != "" {
+		trimmed := strings.TrimSpace(*mergeDTO.Comment)
+		comment = &trimmed
+	}
+
+	err = s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		txID := uuid.New()
+		now := time.Now().In(time.Local)
+		origin := fmt.Sprintf("Из заявки №%s", source.DisplayNumber())
+
+		duplicateStatus, err := s.statusRepo.FindByCodeInTx(ctx, tx, pkgconstants.StatusDuplicate)
+		if err != nil {
+			s.logger.Error("Статус DUPLICATE не найден, объединение невозможно", zap.Error(err))
+			return apperrors.ErrInternalServer
+		}
+
+		// Перенесённые вложения и комментарии пишутся в историю без публикации событий,
+		// иначе участники получили бы уведомление о каждом из них заново.
+		moved, err := s.attachRepo.MoveToOrderInTx(ctx, tx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range moved {
+			item := &repositories.OrderHistoryItem{
+				OrderID: target.ID, UserID: a.UserID, EventType: "ATTACHMENT_ADD",
+				NewValue: s.toNullStr(a.FileName), Comment: s.toNullStr(origin),
+				AttachmentID: sql.NullInt64{Int64: int64(a.ID), Valid: true},
+				TxID:         &txID, CreatedAt: now,
+			}
+			if err := s.historyRepo.CreateInTx(ctx, tx, item); err != nil {
+				return err
+			}
+		}
+
+		for _, h := range sourceHistory {
+			if h.EventType != "COMMENT" || strings.TrimSpace(h.Comment.String) == "" {
+				continue
+			}
+			text := fmt.Sprintf("[%s, %s] %s", origin, h.CreatedAt.In(time.Local).Format("02.01.2006 15:04"), h.Comment.String)
+			item := &repositories.OrderHistoryItem{
+				OrderID: target.ID, UserID: h.UserID, EventType: "COMMENT",
+				Comment: s.toNullStr(text), TxID: &txID, CreatedAt: now, CreatorFio: h.CreatorFio,
+			}
+			if err := s.historyRepo.CreateInTx(ctx, tx, item); err != nil {
+				return err
+			}
+		}
+
+		for _, userID := range participants {
+			item := &repositories.OrderHistoryItem{
+				OrderID: target.ID, UserID: userID, EventType: "PARTICIPANT_ADDED",
+				NewValue: s.toNullStr(strconv.FormatUint(userID, 10)),
+				Comment:  s.toNullStr(fmt.Sprintf("Участник перенесён из заявки №%s", source.DisplayNumber())),
+				TxID:     &txID, CreatedAt: now,
+			}
+			if err := s.historyRepo.CreateInTx(ctx, tx, item); err != nil {
+				return err
+			}
+		}
+
+		closed := *source
+		closed.StatusID = duplicateStatus.ID
+		closed.CompletedAt = &now
+		closed.UpdatedAt = now
+		closed.DuplicateOfID = &target.ID
+		if err := s.orderRepo.Update(ctx, tx, &closed); err != nil {
+			return err
+		}
+		if err := s.orderRepo.MarkDuplicateInTx(ctx, tx, source.ID, target.ID); err != nil {
+			return err
+		}
+
+		oldStatus := strconv.FormatUint(source.StatusID, 10)
+		newStatus := strconv.FormatUint(duplicateStatus.ID, 10)
+		if err := s.logHistoryEvent(ctx, tx, source.ID, actor, "STATUS_CHANGE", &newStatus, &oldStatus, nil, txID, closed); err != nil {
+			return err
+		}
+		targetIDText := strconv.FormatUint(target.ID, 10)
+		if err := s.logHistoryEvent(ctx, tx, source.ID, actor, "MARKED_DUPLICATE", &targetIDText, nil, comment, txID, closed); err != nil {
+			return err
+		}
+		sourceIDText := strconv.FormatUint(source.ID, 10)
+		if err := s.logHistoryEvent(ctx, tx, target.ID, actor, "MERGED_FROM", &sourceIDText, nil, comment, txID, *target); err != nil {
+			return err
+		}
+
+		return s.orderRepo.Update(ctx, tx, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Заявка закрыта как дубликат",
+		zap.Uint64("order_id", source.ID),
+		zap.Uint64("target_id", target.ID),
+		zap.Uint64("actor_id", actor.ID),
+		zap.Int("participants", len(participants)))
+
+	s.invalidateDashboardCache(ctx, true, true)
+	return s.FindOrderByID(ctx, target.ID)
+}
+
+// participantsToCarryOver — участники дубликата, которых ещё нет в основной заявке.
+// Авторы комментариев и вложений попадут туда вместе со своими записями, актор — с записью об объединении.
+func (s *OrderService) participantsToCarryOver(ctx context.Context, source, target *entities.Order, sourceHistory []repositories.OrderHistoryItem, actorID uint64) []uint64 {
+	skip := map[uint64]bool{0: true, actorID: true, target.CreatorID: true}
+	if target.ExecutorID != nil {
+		skip[*target.ExecutorID] = true
+	}
+	for _, h := range sourceHistory {
+		if h.EventType == "COMMENT" && strings.TrimSpace(h.Comment.String) != "" {
+			skip[h.UserID] = true
+		}
+	}
+	if attachments, err := s.attachRepo.FindAllByOrderID(ctx, source.ID, 1000, 0); err == nil {
+		for _, a := range attachments {
+			skip[a.UserID] = true
+		}
+	}
+
+	candidates := []uint64{source.CreatorID}
+	if source.ExecutorID != nil {
+		candidates = append(candidates, *source.ExecutorID)
+	}
+	for _, h := range sourceHistory {
+		candidates = append(candidates, h.UserID)
+	}
+
+	result := make([]uint64, 0, len(candidates))
+	for _, userID := range candidates {
+		if skip[userID] {
+			continue
+		}
+		skip[userID] = true
+		if already, err := s.historyRepo.IsUserParticipant(ctx, target.ID, userID); err == nil && already {
+			continue
+		}
+		result = append(result, userID)
+	}
+	return result
+}
+
+// FindPossibleDuplicates ищет свежие заявки по тому же оборудованию с похожим названием,
+// среди тех, что пользователь может видеть. Используется как подсказка, не как запрет.
+func (s *OrderService) FindPossibleDuplicates(ctx context.Context, name string, equipmentID, excludeID uint64) ([]dto.OrderDuplicateCandidateDTO, error) {
+	result := []dto.OrderDuplicateCandidateDTO{}
+	if s.duplicateHintWindow <= 0 || equipmentID == 0 || strings.TrimSpace(name) == "" {
+		return result, nil
+	}
+
+	securityBuilder, visible, err := s.orderListSecurity(ctx, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return result, nil
+	}
+
+	since := time.Now().Add(-s.duplicateHintWindow)
+	orders, err := s.orderRepo.FindRecentByEquipment(ctx, equipmentID, since, excludeID, duplicateHintScanLimit, securityBuilder)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range orders {
+		similarity := orderNameSimilarity(name, o.Name)
+		if similarity < duplicateHintMinSimilarity {
+			continue
+		}
+		result = append(result, dto.OrderDuplicateCandidateDTO{
+			ID:          o.ID,
+			Name:        o.Name,
+			StatusID:    o.StatusID,
+			CreatorName: o.CreatorName,
+			CreatedAt:   o.CreatedAt.Format(time.RFC3339),
+			Similarity:  float64(int(similarity*100)) / 100,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Similarity > result[j].Similarity })
+	if len(result) > duplicateHintMaxResults {
+		result = result[:duplicateHintMaxResults]
+	}
+	return result, nil
+}
+
+// orderNameSimilarity сравнивает названия по триграммам слов так же, как similarity() в pg_trgm:
+// доля общих триграмм от всех различных триграмм обоих названий.
+func orderNameSimilarity(a, b string) float64 {
+	ta, tb := nameTrigrams(a), nameTrigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func nameTrigrams(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	trigrams := make(map[string]bool)
+	for _, w := range words {
+		runes := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams[string(runes[i:i+3])] = true
+		}
+	}
+	return trigrams
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"request-system/internal/entities"
+	pkgconstants "request-system/pkg/constants"
+)
+
+func TestOrderNameSimilarity(t *testing.T) {
+	if got := orderNameSimilarity("Не печатает принтер", "не печатает Принтер!"); got != 1 {
+		t.Fatalf("expected identical names after normalization, got %v", got)
+	}
+	if got := orderNameSimilarity("Не печатает принтер", "Принтер не печатает, замятие"); got < duplicateHintMinSimilarity {
+		t.Fatalf("expected reordered name to pass threshold, got %v", got)
+	}
+	if got := orderNameSimilarity("Не печатает принтер", "Замена картриджа в сканере"); got >= duplicateHintMinSimilarity {
+		t.Fatalf("expected unrelated names below threshold, got %v", got)
+	}
+	if got := orderNameSimilarity("", "Принтер"); got != 0 {
+		t.Fatalf("expected 0 for empty name, got %v", got)
+	}
+}
+
+func TestIsOrderLocked(t *testing.T) {
+	code := func(c string) *entities.Status { return &entities.Status{Code: &c} }
+
+	if !isOrderLocked(code(pkgconstants.StatusClosed)) || !isOrderLocked(code(pkgconstants.StatusDuplicate)) {
+		t.Fatal("expected CLOSED and DUPLICATE orders to be locked")
+	}
+	if isOrderLocked(code(pkgconstants.StatusInProgress)) || isOrderLocked(nil) {
+		t.Fatal("expected IN_PROGRESS and unknown status not to be locked")
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"request-system/internal/dto"
 	"request-system/internal/entities"
+	"request-system/pkg/businesshours"
 	pkgconstants "request-system/pkg/constants"
 	"request-system/pkg/utils"
 )
@@ -78,12 +79,12 @@ func (s *OrderService) detectPersistedMetricChanges(orderID uint64, currentOrder
 }
 
 func (s *OrderService) calculateMetrics(ctx context.Context, newOrder, oldOrder *entities.Order, dto dto.UpdateOrderDTO, actorID uint64, now time.Time) {
-	newStatus, _ := s.statusRepo.FindStatus(ctx, newOrder.StatusID)
+	newStatus, _ := s.statuses.FindByID(ctx, newOrder.StatusID)
 	newCode := ""
 	if newStatus != nil && newStatus.Code != nil {
 		newCode = *newStatus.Code
 	}
-	oldStatus, _ := s.statusRepo.FindStatus(ctx, oldOrder.StatusID)
+	oldStatus, _ := s.statuses.FindByID(ctx, oldOrder.StatusID)
 	oldCode := ""
 	if oldStatus != nil && oldStatus.Code != nil {
 		oldCode = *oldStatus.Code
@@ -93,15 +94,14 @@ func (s *OrderService) calculateMetrics(ctx context.Context, newOrder, oldOrder
 	createdAt := oldOrder.CreatedAt.In(loc)
 	nowAt := now.In(loc)
 
-	diff := int64(nowAt.Sub(createdAt).Seconds())
-	if diff < 0 {
+	if nowAt.Before(createdAt) {
 		s.logger.Warn("Отрицательная разница времени",
 			zap.Time("created", createdAt),
-			zap.Time("now", nowAt),
-			zap.Int64("diff", diff))
-		diff = 0
+			zap.Time("now", nowAt))
 	}
-	seconds := uint64(diff)
+	// Время отклика и решения идёт только в рабочие часы; без календаря — круглосуточно.
+	calendar := s.slaCalendar(ctx)
+	seconds := uint64(calendar.Between(createdAt, nowAt).Seconds())
 
 	// Подробный лог нужен для разборов расчёта SLA и FCR на спорных сценариях.
 	s.logger.Info("Расчёт метрик времени",
@@ -109,6 +109,7 @@ func (s *OrderService) calculateMetrics(ctx context.Context, newOrder, oldOrder
 		zap.Time("created_at", createdAt),
 		zap.Time("now", nowAt),
 		zap.Uint64("diff_seconds", seconds),
+		zap.Bool("business_hours", calendar != nil),
 		zap.Uint64("actor_id", actorID),
 		zap.Uint64("creator_id", oldOrder.CreatorID),
 		zap.String("old_status", oldCode),
@@ -158,6 +159,47 @@ func (s *OrderService) calculateMetrics(ctx context.Context, newOrder, oldOrder
 	}
 }
 
+// slaCalendar — рабочий календарь для метрик; nil, если календарь не подключён или выключен.
+func (s *OrderService) slaCalendar(ctx context.Context) *businesshours.Calendar {
+	if s.businessCalendar == nil {
+		return nil
+	}
+	return s.businessCalendar.Calendar(ctx)
+}
+
+// firstResponseDeadline — когда истекает срок первого отклика по приоритету заявки.
+// false — у приоритета нет срока или исполнитель уже откликнулся.
+func firstResponseDeadline(o *entities.Order, calendar *businesshours.Calendar) (time.Time, bool) {
+	if o.PriorityFirstResponseMinutes == nil || *o.PriorityFirstResponseMinutes <= 0 || o.FirstResponseTimeSeconds != nil {
+		return time.Time{}, false
+	}
+	return calendar.Add(o.CreatedAt, time.Duration(*o.PriorityFirstResponseMinutes)*time.Minute), true
+}
+
+// firstResponseSecondsLeft — рабочие секунды до срока; после срока — со знаком минус.
+func firstResponseSecondsLeft(due, now time.Time, calendar *businesshours.Calendar) int64 {
+	if now.Before(due) {
+		return int64(calendar.Between(now, due) / time.Second)
+	}
+	return -int64(calendar.Between(due, now) / time.Second)
+}
+
+// applyFirstResponseCountdown заполняет обратный отсчёт до срока первого отклика, пока
+// заявка открыта и отклика не было.
+func (s *OrderService) applyFirstResponseCountdown(ctx context.Context, d *dto.OrderResponseDTO, o *entities.Order, calendar *businesshours.Calendar, now time.Time) {
+	due, ok := firstResponseDeadline(o, calendar)
+	if !ok || o.CompletedAt != nil {
+		return
+	}
+	if s.statuses != nil {
+		if status, err := s.statuses.FindByID(ctx, o.StatusID); err == nil && status.Code != nil && pkgconstants.IsFinalStatus(*status.Code) {
+			return
+		}
+	}
+	left := firstResponseSecondsLeft(due, now, calendar)
+	d.FirstResponseDueAt, d.FirstResponseSecondsLeft = &due, &left
+}
+
 func isOrderResolvedStatus(code string) bool {
 	return code == pkgconstants.StatusCompleted || code == pkgconstants.StatusClosed
 }
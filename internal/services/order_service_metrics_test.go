@@ -45,8 +45,8 @@ func TestCalculateMetrics_SetsResolutionMetricsForCompletedStatus(t *testing.T)
 		},
 	}
 	service := &OrderService{
-		statusRepo: statusRepo,
-		logger:     zap.NewNop(),
+		statuses: NewStatusDirectory(statusRepo, nil, zap.NewNop()),
+		logger:   zap.NewNop(),
 	}
 
 	createdAt := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
@@ -132,5 +132,9 @@ func (s *statusRepositoryStub) FindIDByCode(context.Context, string) (uint64, er
 }
 
 func (s *statusRepositoryStub) FindAll(context.Context) ([]entities.Status, error) {
-	return nil, nil
+	statuses := make([]entities.Status, 0, len(s.codesByID))
+	for id, code := range s.codesByID {
+		statuses = append(statuses, entities.Status{ID: id, Code: &code})
+	}
+	return statuses, nil
 }
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// priorityLevelLabels — уровни влияния и срочности для истории и уведомлений.
+var priorityLevelLabels = map[string]string{
+	entities.PriorityLevelLow:    "Низкое",
+	entities.PriorityLevelMedium: "Среднее",
+	entities.PriorityLevelHigh:   "Высокое",
+}
+
+func priorityLevelLabel(level string) string {
+	if label, ok := priorityLevelLabels[level]; ok {
+		return label
+	}
+	return level
+}
+
+// resolveCreatePriority считает приоритет новой заявки по матрице «влияние × срочность».
+// Недостающий уровень считается средним. Приоритет, выбранный вручную (право
+// order:create:priority_id проверено раньше), важнее матрицы; без влияния и срочности
+// приоритет остаётся таким, как его прислали.
+func (s *OrderService) resolveCreatePriority(ctx context.Context, d dto.CreateOrderDTO) (priorityID *uint64, impact, urgency *string, err error) {
+	if d.Impact == nil && d.Urgency == nil {
+		return d.PriorityID, nil, nil, nil
+	}
+	impactLevel := priorityLevelOrDefault(d.Impact)
+	urgencyLevel := priorityLevelOrDefault(d.Urgency)
+	if d.PriorityID != nil {
+		return d.PriorityID, &impactLevel, &urgencyLevel, nil
+	}
+
+	id, err := s.priorityFromMatrix(ctx, impactLevel, urgencyLevel)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &id, &impactLevel, &urgencyLevel, nil
+}
+
+// applyUpdatePriorityMatrix пересчитывает приоритет, если в запросе поменяли влияние или
+// срочность, а сам priority_id не прислали. Очистка обоих уровней приоритет не трогает.
+func (s *OrderService) applyUpdatePriorityMatrix(ctx context.Context, current, updated *entities.Order, explicitFields map[string]interface{}) error {
+	_, impactSent := explicitFields["impact"]
+	_, urgencySent := explicitFields["urgency"]
+	if !impactSent && !urgencySent {
+		return nil
+	}
+	if _, manual := explicitFields["priority_id"]; manual {
+		return nil
+	}
+	if updated.Impact == nil && updated.Urgency == nil {
+		return nil
+	}
+	if utils.StringPtrEqual(current.Impact, updated.Impact) && utils.StringPtrEqual(current.Urgency, updated.Urgency) {
+		return nil
+	}
+
+	impactLevel := priorityLevelOrDefault(updated.Impact)
+	urgencyLevel := priorityLevelOrDefault(updated.Urgency)
+	id, err := s.priorityFromMatrix(ctx, impactLevel, urgencyLevel)
+	if err != nil {
+		return err
+	}
+	updated.Impact, updated.Urgency = &impactLevel, &urgencyLevel
+	updated.PriorityID = &id
+	return nil
+}
+
+// logPriorityLevels записывает в историю влияние и срочность, с которыми создана заявка.
+func (s *OrderService) logPriorityLevels(ctx context.Context, tx pgx.Tx, order *entities.Order, actor *entities.User, txID uuid.UUID) error {
+	if order.Impact != nil {
+		if err := s.logHistoryEvent(ctx, tx, order.ID, actor, "IMPACT_CHANGE", order.Impact, nil, nil, txID, *order); err != nil {
+			return err
+		}
+	}
+	if order.Urgency != nil {
+		return s.logHistoryEvent(ctx, tx, order.ID, actor, "URGENCY_CHANGE", order.Urgency, nil, nil, txID, *order)
+	}
+	return nil
+}
+
+func (s *OrderService) priorityFromMatrix(ctx context.Context, impact, urgency string) (uint64, error) {
+	id, err := s.priorityRepo.FindIDByMatrix(ctx, impact, urgency)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return 0, apperrors.NewBadRequestError(fmt.Sprintf(
+			"В матрице приоритетов не задан приоритет для влияния «%s» и срочности «%s». Обратитесь к администратору справочников.",
+			priorityLevelLabel(impact), priorityLevelLabel(urgency)))
+	}
+	return id, err
+}
+
+func priorityLevelOrDefault(level *string) string {
+	if level == nil || *level == "" {
+		return entities.PriorityLevelMedium
+	}
+	return *level
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+type priorityMatrixRepoStub struct {
+	repositories.PriorityRepositoryInterface
+	matrix map[[2]string]uint64
+}
+
+func (s *priorityMatrixRepoStub) FindIDByMatrix(_ context.Context, impact, urgency string) (uint64, error) {
+	if id, ok := s.matrix[[2]string{impact, urgency}]; ok {
+		return id, nil
+	}
+	return 0, apperrors.ErrNotFound
+}
+
+func TestResolveCreatePriority(t *testing.T) {
+	service := &OrderService{priorityRepo: &priorityMatrixRepoStub{matrix: map[[2]string]uint64{
+		{entities.PriorityLevelHigh, entities.PriorityLevelMedium}: 3,
+	}}}
+	high := entities.PriorityLevelHigh
+	manual := uint64(9)
+
+	priorityID, impact, urgency, err := service.resolveCreatePriority(context.Background(), dto.CreateOrderDTO{Impact: &high})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if priorityID == nil || *priorityID != 3 || *impact != high || *urgency != entities.PriorityLevelMedium {
+		t.Fatalf("приоритет не взят из матрицы (срочность по умолчанию — средняя): %v %v %v", priorityID, impact, urgency)
+	}
+
+	priorityID, _, _, err = service.resolveCreatePriority(context.Background(), dto.CreateOrderDTO{Impact: &high, PriorityID: &manual})
+	if err != nil || priorityID == nil || *priorityID != manual {
+		t.Fatalf("ручной приоритет должен быть важнее матрицы: %v %v", priorityID, err)
+	}
+
+	if _, _, _, err := service.resolveCreatePriority(context.Background(), dto.CreateOrderDTO{Urgency: &high}); err == nil {
+		t.Fatal("ожидалась ошибка для ненастроенной клетки матрицы")
+	}
+}
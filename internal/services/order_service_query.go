@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/orderquery"
+	"request-system/pkg/types"
+	"request-system/pkg/utils"
+)
+
+// orderQueryFilterKeys — ключи filter.Filter репозитория заявок для полей строки поиска.
+var orderQueryFilterKeys = map[string]string{
+	"status":     "status_id",
+	"priority":   "priority_id",
+	"type":       "order_type_id",
+	"executor":   "executor_id",
+	"creator":    "creator_id",
+	"id":         "id",
+	"department": "department_id",
+	"branch":     "branch_id",
+	"otdel":      "otdel_id",
+	"office":     "office_id",
+	"equipment":  "equipment_id",
+	"team":       "team_id",
+	"overdue":    "overdue",
+}
+
+// ApplyOrderQuery разбирает строку поиска (см. pkg/orderquery) и дополняет ею filter:
+// коды справочников заменяются на ID, me — на текущего пользователя. Условия строки
+// перекрывают одноимённые filter[...]; текст добавляется к filter.Search.
+func (s *OrderService) ApplyOrderQuery(ctx context.Context, filter *types.Filter, query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	parsed, err := orderquery.Parse(query, utils.LocationFromCtx(ctx))
+	if err != nil {
+		return err
+	}
+	if filter.Filter == nil {
+		filter.Filter = make(map[string]interface{})
+	}
+
+	for _, term := range parsed.Terms {
+		switch term.Field {
+		case "created", "due":
+			prefix := "created"
+			if term.Field == "due" {
+				prefix = "duration"
+			}
+			if term.From != nil {
+				filter.Filter[prefix+"_from"] = *term.From
+			}
+			if term.To != nil {
+				filter.Filter[prefix+"_to"] = *term.To
+			}
+			continue
+		case "overdue":
+			filter.Filter["overdue"] = term.Values[0] == "true"
+			continue
+		}
+
+		if strings.HasPrefix(term.Field, orderquery.CustomFieldPrefix) {
+			filter.Filter[term.Field] = strings.Join(term.Values, ",")
+			continue
+		}
+
+		ids, isNull, err := s.resolveOrderQueryValues(ctx, term)
+		if err != nil {
+			return err
+		}
+		key := orderQueryFilterKeys[term.Field]
+		switch {
+		case isNull:
+			filter.Filter[key] = nil
+		case len(ids) == 1:
+			filter.Filter[key] = ids[0]
+		default:
+			filter.Filter[key] = ids
+		}
+	}
+
+	if parsed.Text != "" {
+		filter.Search = strings.TrimSpace(filter.Search + " " + parsed.Text)
+	}
+	return nil
+}
+
+// resolveOrderQueryValues переводит значения условия в ID. isNull — executor:none,
+// «исполнитель не назначен»; вместе с другими значениями none не сочетается.
+func (s *OrderService) resolveOrderQueryValues(ctx context.Context, term orderquery.Term) (ids []uint64, isNull bool, err error) {
+	for _, value := range term.Values {
+		var id uint64
+		switch {
+		case value == orderquery.UserNone:
+			if len(term.Values) > 1 {
+				return nil, false, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_user", strings.Join(term.Values, ","), term.Field)
+			}
+			return nil, true, nil
+		case value == orderquery.UserMe:
+			id, err = utils.GetUserIDFromCtx(ctx)
+			if err != nil {
+				return nil, false, apperrors.ErrUnauthorized
+			}
+		case term.Field == "status":
+			id, err = s.findStatusIDByCode(ctx, value)
+		case term.Field == "priority":
+			id, err = s.findPriorityIDByCode(ctx, value)
+		case term.Field == "type":
+			id, err = s.orderTypeRepo.FindIDByCode(ctx, value)
+			if errors.Is(err, apperrors.ErrNotFound) {
+				err = apperrors.NewLocalizedError(http.StatusBadRequest, "query.unknown_type", value)
+			}
+		default:
+			id, err = strconv.ParseUint(value, 10, 64)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, false, nil
+}
+
+func (s *OrderService) findStatusIDByCode(ctx context.Context, code string) (uint64, error) {
+	statuses, err := s.statuses.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, status := range statuses {
+		if status.Code != nil && strings.EqualFold(*status.Code, code) {
+			return status.ID, nil
+		}
+	}
+	return 0, apperrors.NewLocalizedError(http.StatusBadRequest, "query.unknown_status", code)
+}
+
+func (s *OrderService) findPriorityIDByCode(ctx context.Context, code string) (uint64, error) {
+	priority, err := s.priorityRepo.FindByCode(ctx, code)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return 0, apperrors.NewLocalizedError(http.StatusBadRequest, "query.unknown_priority", code)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return priority.ID, nil
+}
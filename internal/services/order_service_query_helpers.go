@@ -15,7 +15,7 @@ import (
 )
 
 func (s *OrderService) GetStatusByID(ctx context.Context, id uint64) (*entities.Status, error) {
-	return s.statusRepo.FindStatus(ctx, id)
+	return s.statuses.FindByID(ctx, id)
 }
 
 func (s *OrderService) GetPriorityByID(ctx context.Context, id uint64) (*entities.Priority, error) {
@@ -26,6 +26,22 @@ func (s *OrderService) GetUserStats(ctx context.Context, userID uint64) (*types.
 	return s.orderRepo.GetUserOrderStats(ctx, userID, time.Now().AddDate(0, 0, -30))
 }
 
+// GetUnitStats — сводка за 30 дней для руководителя: по его отделу, а без отдела — по департаменту.
+func (s *OrderService) GetUnitStats(ctx context.Context) (*types.UserOrderStats, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsHead == nil || !*user.IsHead || user.DepartmentID == nil {
+		return nil, apperrors.ErrForbidden
+	}
+	return s.orderRepo.GetUnitOrderStats(ctx, *user.DepartmentID, user.OtdelID, time.Now().AddDate(0, 0, -30))
+}
+
 func (s *OrderService) mapOrdersToDTOs(ctx context.Context, orders []entities.Order, includeAttachments bool) []dto.OrderResponseDTO {
 	attachMap := make(map[uint64][]entities.Attachment)
 
@@ -42,10 +58,12 @@ func (s *OrderService) mapOrdersToDTOs(ctx context.Context, orders []entities.Or
 		}
 	}
 
+	calendar, now := s.slaCalendar(ctx), time.Now()
 	res := make([]dto.OrderResponseDTO, len(orders))
 	for i, o := range orders {
 		atts := attachMap[o.ID]
 		res[i] = *s.toResponseDTO(&o, nil, nil, atts)
+		s.applyFirstResponseCountdown(ctx, &res[i], &o, calendar, now)
 	}
 	return res
 }
@@ -63,6 +81,7 @@ func (s *OrderService) loadOrderAttachments(ctx context.Context, orderID uint64,
 func (s *OrderService) toResponseDTO(o *entities.Order, cr, ex *entities.User, atts []entities.Attachment) *dto.OrderResponseDTO {
 	d := &dto.OrderResponseDTO{
 		ID:                       o.ID,
+		Number:                   o.DisplayNumber(),
 		Name:                     o.Name,
 		StatusID:                 o.StatusID,
 		CreatedAt:                o.CreatedAt.Format(time.RFC3339),
@@ -76,18 +95,35 @@ func (s *OrderService) toResponseDTO(o *entities.Order, cr, ex *entities.User, a
 		EquipmentID:              o.EquipmentID,
 		EquipmentTypeID:          o.EquipmentTypeID,
 		PriorityID:               o.PriorityID,
+		PriorityName:             o.PriorityName,
+		PriorityColor:            o.PriorityColor,
+		PrioritySortOrder:        o.PrioritySortOrder,
+		Impact:                   o.Impact,
+		Urgency:                  o.Urgency,
 		Duration:                 o.Duration,
 		CompletedAt:              o.CompletedAt,
+		DuplicateOfID:            o.DuplicateOfID,
+		ResolutionCodeID:         o.ResolutionCodeID,
 		ResolutionTimeSeconds:    o.ResolutionTimeSeconds,
 		FirstResponseTimeSeconds: o.FirstResponseTimeSeconds,
 		CreatorID:                o.CreatorID,
 		CreatorName:              o.CreatorName,
+		CustomFields:             customFieldsValueOrEmpty(o.CustomFields),
 	}
 
 	if o.ExecutorID != nil {
 		d.ExecutorID = o.ExecutorID
 		d.ExecutorName = o.ExecutorName
 	}
+	if o.TeamID != nil {
+		d.TeamID = o.TeamID
+		d.TeamName = o.TeamName
+	}
+	if view := o.ListView; view != nil {
+		attachmentsCount := view.AttachmentsCount
+		d.StatusName, d.LastComment, d.LastCommentAt = view.StatusName, view.LastComment, view.LastCommentAt
+		d.AttachmentsCount = &attachmentsCount
+	}
 
 	if o.ResolutionTimeSeconds != nil {
 		d.ResolutionTimeFormatted = utils.FormatSecondsToHumanReadable(*o.ResolutionTimeSeconds)
@@ -117,7 +153,7 @@ func (s *OrderService) buildAuthzContext(ctx context.Context, orderID uint64) (*
 		if err != nil {
 			return nil, apperrors.ErrUserNotFound
 		}
-		return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+		return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 	}
 
 	target, err := s.orderRepo.FindByID(ctx, orderID)
@@ -141,8 +177,13 @@ func (s *OrderService) buildAuthzContextWithTarget(ctx context.Context, target *
 		return nil, apperrors.ErrUserNotFound
 	}
 
-	ctxAuth := &authz.Context{Actor: actor, Permissions: permissionsMap, Target: target}
+	ctxAuth := &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx), Target: target}
 	wasParticipant, _ := s.historyRepo.IsUserParticipant(ctx, target.ID, userID)
 	ctxAuth.IsParticipant = (target.CreatorID == userID) || (target.ExecutorID != nil && *target.ExecutorID == userID) || wasParticipant
+	if !ctxAuth.IsParticipant && target.TeamID != nil {
+		// Участники команды видят заявки своей очереди, чтобы забрать их
+		ctxAuth.IsParticipant, _ = s.orderRepo.IsTeamMember(ctx, *target.TeamID, userID)
+	}
+	ctxAuth.AccessGrant, _ = s.accessGrantRepo.FindActiveLevel(ctx, target.ID, userID)
 	return ctxAuth, nil
 }
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
+	"request-system/pkg/types"
+)
+
+type queryPriorityRepoStub struct {
+	repositories.PriorityRepositoryInterface
+	ids map[string]uint64
+}
+
+func (s *queryPriorityRepoStub) FindByCode(_ context.Context, code string) (*entities.Priority, error) {
+	if id, ok := s.ids[code]; ok {
+		return &entities.Priority{ID: id}, nil
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+type queryOrderTypeRepoStub struct {
+	repositories.OrderTypeRepositoryInterface
+}
+
+func (s *queryOrderTypeRepoStub) FindIDByCode(_ context.Context, code string) (uint64, error) {
+	if code == "EQUIPMENT" {
+		return 3, nil
+	}
+	return 0, apperrors.ErrNotFound
+}
+
+func newOrderQueryService() *OrderService {
+	return &OrderService{
+		statuses:      NewStatusDirectory(&statusRepositoryStub{codesByID: map[uint64]string{1: pkgconstants.StatusOpen, 2: pkgconstants.StatusCompleted}}, nil, zap.NewNop()),
+		priorityRepo:  &queryPriorityRepoStub{ids: map[string]uint64{"CRITICAL": 7}},
+		orderTypeRepo: &queryOrderTypeRepoStub{},
+	}
+}
+
+func TestApplyOrderQuery_BuildsFilter(t *testing.T) {
+	service := newOrderQueryService()
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(42))
+	filter := types.Filter{Search: "картридж", Filter: map[string]interface{}{"status_id": "2"}}
+
+	query := `status:open,completed priority:CRITICAL executor:me creator:none type:equipment ` +
+		`created:>2024-01-01 created:<=2024-01-31 overdue:yes cf.Floor:2,3 text:"не печатает" принтер`
+	if err := service.ApplyOrderQuery(ctx, &filter, query); err != nil {
+		t.Fatalf("ApplyOrderQuery: %v", err)
+	}
+
+	statuses, ok := filter.Filter["status_id"].([]uint64)
+	if !ok || len(statuses) != 2 || statuses[0] != 1 || statuses[1] != 2 {
+		t.Errorf("status_id = %#v, want [1 2] overriding filter[status_id]", filter.Filter["status_id"])
+	}
+	if filter.Filter["priority_id"] != uint64(7) || filter.Filter["order_type_id"] != uint64(3) {
+		t.Errorf("priority/type = %#v / %#v", filter.Filter["priority_id"], filter.Filter["order_type_id"])
+	}
+	if filter.Filter["executor_id"] != uint64(42) {
+		t.Errorf("executor:me = %#v", filter.Filter["executor_id"])
+	}
+	if value, ok := filter.Filter["creator_id"]; !ok || value != nil {
+		t.Errorf("creator:none must filter IS NULL, got %#v", value)
+	}
+	if filter.Filter["overdue"] != true || filter.Filter["cf.Floor"] != "2,3" {
+		t.Errorf("overdue/cf = %#v / %#v", filter.Filter["overdue"], filter.Filter["cf.Floor"])
+	}
+
+	from, _ := filter.Filter["created_from"].(time.Time)
+	to, _ := filter.Filter["created_to"].(time.Time)
+	if !from.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("created:>2024-01-01 must start on the next day, got %s", from)
+	}
+	if !to.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond)) {
+		t.Errorf("created:<=2024-01-31 must include the whole day, got %s", to)
+	}
+	if filter.Search != "картридж не печатает принтер" {
+		t.Errorf("search = %q", filter.Search)
+	}
+}
+
+func TestApplyOrderQuery_ReportsLocalizedErrors(t *testing.T) {
+	service := newOrderQueryService()
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(42))
+
+	cases := []struct {
+		query string
+		key   string
+	}{
+		{`state:OPEN`, "query.unknown_field"},
+		{`text:"принтер`, "query.unclosed_quote"},
+		{`created:2024-13-01`, "query.bad_date"},
+		{`created:2024-02-01..2024-01-01`, "query.bad_range"},
+		{`priority:>HIGH`, "query.bad_operator"},
+		{`executor:petrov`, "query.bad_user"},
+		{`executor:none,me`, "query.bad_user"},
+		{`department:abc`, "query.bad_id"},
+		{`overdue:maybe`, "query.bad_bool"},
+		{`status:`, "query.empty_value"},
+		{`status:LOST`, "query.unknown_status"},
+		{`priority:LOW`, "query.unknown_priority"},
+		{`type:OTHER`, "query.unknown_type"},
+	}
+	for _, tc := range cases {
+		filter := types.Filter{}
+		err := service.ApplyOrderQuery(ctx, &filter, tc.query)
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != 400 || httpErr.MessageKey != tc.key {
+			t.Errorf("%q: want 400 %s, got %v", tc.query, tc.key, err)
+		}
+	}
+
+	err := service.ApplyOrderQuery(ctx, &types.Filter{}, "state:OPEN")
+	var httpErr *apperrors.HttpError
+	errors.As(err, &httpErr)
+	if msg := httpErr.LocalizedMessage(i18n.LangEN); !strings.HasPrefix(msg, "unknown field 'state', available: branch,") {
+		t.Errorf("english message = %q", msg)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"request-system/internal/dto"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/sanitize"
+)
+
+func TestOrderDTOSanitize(t *testing.T) {
+	address := "  ул. Рудаки,\n 12 "
+	comment := "Не печатает\r\nстраница 2\u202Egnp.exe\x00 "
+	d := dto.CreateOrderDTO{Name: " Принтер\u200B\tв 305 ", Address: &address, Comment: &comment}
+	d.Sanitize()
+	if d.Name != "Принтер в 305" || *d.Address != "ул. Рудаки,  12" {
+		t.Errorf("single-line fields: %q / %q", d.Name, *d.Address)
+	}
+	if *d.Comment != "Не печатает\nстраница 2gnp.exe" {
+		t.Errorf("comment = %q", *d.Comment)
+	}
+
+	name := "<b>Картридж</b>\n"
+	update := dto.UpdateOrderDTO{Name: &name}
+	patch := map[string]interface{}{"name": name, "comment": "ok\r\n", "status_id": float64(2)}
+	update.Sanitize(patch)
+	if *update.Name != "<b>Картридж</b>" || patch["name"] != "<b>Картридж</b>" || patch["comment"] != "ok" || patch["status_id"] != float64(2) {
+		t.Errorf("patch = %#v, dto name = %q", patch, *update.Name)
+	}
+}
+
+func TestOrderService_RejectsBlankNameAfterSanitize(t *testing.T) {
+	service := &OrderService{}
+	_, err := service.CreateOrder(context.Background(), dto.CreateOrderDTO{Name: " \u200B\n"}, nil)
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Fatalf("create with an invisible name: want 400, got %v", err)
+	}
+
+	_, err = service.UpdateOrder(context.Background(), 1, dto.UpdateOrderDTO{}, nil, map[string]interface{}{"name": "\u2066\u2069"})
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Fatalf("update with an invisible name: want 400, got %v", err)
+	}
+}
+
+func TestSanitizeEscapingPerChannel(t *testing.T) {
+	name := `<img src=x onerror=alert(1)> *Иванов_И.* [x](http://evil)`
+
+	if got := sanitize.HTML(name); got != `&lt;img src=x onerror=alert(1)&gt; *Иванов_И.* [x](http://evil)` {
+		t.Errorf("HTML = %q", got)
+	}
+	if got := sanitize.MarkdownV2(name); got != `<img src\=x onerror\=alert\(1\)\> \*Иванов\_И\.\* \[x\]\(http://evil\)` {
+		t.Errorf("MarkdownV2 = %q", got)
+	}
+	if got := sanitize.Slack("<!channel> & <http://evil|link>"); got != "&lt;!channel&gt; &amp; &lt;http://evil|link&gt;" {
+		t.Errorf("Slack = %q", got)
+	}
+	if got := sanitize.Truncate("Заявка на ремонт принтера в кабинете", 10); got != "Заявка на…" {
+		t.Errorf("Truncate = %q", got)
+	}
+}
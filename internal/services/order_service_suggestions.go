@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+const (
+	// Сколько сотрудников подразделения оценивать и сколько подсказок отдавать по умолчанию.
+	executorSuggestionScanLimit    = 200
+	executorSuggestionDefaultLimit = 10
+	executorSuggestionMaxLimit     = 50
+
+	// Веса показателей в итоговой оценке кандидата (в сумме 1).
+	suggestionSkillWeight = 0.5
+	suggestionLoadWeight  = 0.3
+	suggestionSpeedWeight = 0.2
+	// Оценка скорости для тех, кто ещё ничего не закрыл: не лучше и не хуже среднего.
+	suggestionUnknownSpeed = 0.5
+)
+
+// SuggestExecutors подбирает кандидатов в исполнители заявки из её подразделения:
+// сначала те, у кого есть навыки для типа заявки, затем менее загруженные и быстрее решающие.
+func (s *OrderService) SuggestExecutors(ctx context.Context, orderID uint64, limit int) ([]dto.ExecutorSuggestionDTO, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	authCtx, err := s.buildAuthzContextWithTarget(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.OrdersView, *authCtx) || !authz.CanDo(authz.OrdersUpdateExecutorID, *authCtx) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	if limit <= 0 {
+		limit = executorSuggestionDefaultLimit
+	}
+	limit = min(limit, executorSuggestionMaxLimit)
+
+	orderCtx := buildOrderRoutingContext(order.OrderTypeID, order.DepartmentID, order.OtdelID, order.BranchID, order.OfficeID)
+	candidates, err := s.orderRepo.FindExecutorCandidates(ctx, executorStructureCondition(orderCtx), orderCtx.OrderTypeID, executorSuggestionScanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := rankExecutorCandidates(candidates)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	for i := range ranked {
+		ranked[i].IsCurrentExecutor = order.ExecutorID != nil && *order.ExecutorID == ranked[i].UserID
+	}
+	return ranked, nil
+}
+
+// rankExecutorCandidates считает оценку каждого кандидата и сортирует по убыванию.
+// Нагрузка и скорость нормируются относительно остальных кандидатов, поэтому оценка
+// сравнима только внутри одной выборки.
+func rankExecutorCandidates(candidates []entities.ExecutorCandidate) []dto.ExecutorSuggestionDTO {
+	maxOpen := 0
+	var bestAvg float64
+	for _, c := range candidates {
+		maxOpen = max(maxOpen, c.OpenOrders)
+		if c.AvgResolutionSeconds != nil && *c.AvgResolutionSeconds > 0 && (bestAvg == 0 || *c.AvgResolutionSeconds < bestAvg) {
+			bestAvg = *c.AvgResolutionSeconds
+		}
+	}
+
+	result := make([]dto.ExecutorSuggestionDTO, 0, len(candidates))
+	for _, c := range candidates {
+		skillScore := 1.0
+		if c.RequiredSkills > 0 {
+			skillScore = float64(c.MatchedSkills) / float64(c.RequiredSkills)
+		}
+		loadScore := 1.0
+		if maxOpen > 0 {
+			loadScore = 1 - float64(c.OpenOrders)/float64(maxOpen)
+		}
+		speedScore := suggestionUnknownSpeed
+		item := dto.ExecutorSuggestionDTO{
+			UserID:         c.UserID,
+			Fio:            c.Fio,
+			PositionName:   c.PositionName,
+			OpenOrders:     c.OpenOrders,
+			MatchedSkills:  c.MatchedSkills,
+			RequiredSkills: c.RequiredSkills,
+		}
+		if c.AvgResolutionSeconds != nil && *c.AvgResolutionSeconds > 0 {
+			speedScore = bestAvg / *c.AvgResolutionSeconds
+			avg := uint64(math.Round(*c.AvgResolutionSeconds))
+			item.AvgResolutionSeconds = &avg
+			item.AvgResolutionFormatted = utils.FormatSecondsToHumanReadable(avg)
+		}
+
+		score := suggestionSkillWeight*skillScore + suggestionLoadWeight*loadScore + suggestionSpeedWeight*speedScore
+		item.Score = math.Round(score*1000) / 1000
+		result = append(result, item)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].OpenOrders < result[j].OpenOrders
+	})
+	return result
+}
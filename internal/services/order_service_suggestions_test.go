@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+
+	"request-system/internal/entities"
+)
+
+func TestRankExecutorCandidatesPrefersSkilledAndLessLoaded(t *testing.T) {
+	fast, slow := 3600.0, 7200.0
+	candidates := []entities.ExecutorCandidate{
+		{UserID: 1, Fio: "Занятой", OpenOrders: 8, AvgResolutionSeconds: &fast, RequiredSkills: 1},
+		{UserID: 2, Fio: "Свободный", OpenOrders: 1, AvgResolutionSeconds: &slow, RequiredSkills: 1},
+		{UserID: 3, Fio: "С навыком", OpenOrders: 8, AvgResolutionSeconds: &slow, MatchedSkills: 1, RequiredSkills: 1},
+	}
+
+	ranked := rankExecutorCandidates(candidates)
+	if len(ranked) != 3 {
+		t.Fatalf("ожидалось 3 кандидата, получено %d", len(ranked))
+	}
+	got := []uint64{ranked[0].UserID, ranked[1].UserID, ranked[2].UserID}
+	want := []uint64{3, 2, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("порядок кандидатов %v, ожидался %v", got, want)
+		}
+	}
+	if ranked[2].AvgResolutionSeconds == nil || *ranked[2].AvgResolutionSeconds != 3600 {
+		t.Fatalf("среднее время решения не перенесено в DTO: %+v", ranked[2])
+	}
+}
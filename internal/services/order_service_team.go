@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// ClaimOrder назначает текущего пользователя исполнителем заявки, стоящей в очереди команды.
+// Забрать заявку может любой участник команды; если двое нажали одновременно, второй получит 409.
+func (s *OrderService) ClaimOrder(ctx context.Context, orderID uint64) (*dto.OrderResponseDTO, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	authCtx, err := s.buildAuthzContextWithTarget(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	actor := authCtx.Actor
+
+	if order.TeamID == nil {
+		return nil, apperrors.NewBadRequestError("Заявка не назначена на команду.")
+	}
+	if order.ExecutorID != nil {
+		return nil, claimedOrderError(order)
+	}
+	if status, _ := s.statuses.FindByID(ctx, order.StatusID); isOrderLocked(status) {
+		return nil, apperrors.NewBadRequestError("Заявка закрыта. Забрать её нельзя.")
+	}
+
+	isMember, err := s.orderRepo.IsTeamMember(ctx, *order.TeamID, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, apperrors.NewHttpError(http.StatusForbidden, "Забрать заявку может только участник команды.", nil, nil)
+	}
+
+	err = s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		claimed, err := s.orderRepo.ClaimTeamOrderInTx(ctx, tx, order.ID, actor.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			current, findErr := s.orderRepo.FindByID(ctx, order.ID)
+			if findErr != nil {
+				return findErr
+			}
+			return claimedOrderError(current)
+		}
+
+		updated := *order
+		updated.ExecutorID = &actor.ID
+		comment := "Заявку из очереди команды забрал: " + actor.Fio
+		if order.TeamName != nil {
+			comment = fmt.Sprintf("Заявку из очереди команды «%s» забрал: %s", *order.TeamName, actor.Fio)
+		}
+		executorIDText := fmt.Sprintf("%d", actor.ID)
+		txID := uuid.New()
+
+		item := &repositories.OrderHistoryItem{
+			OrderID: order.ID, UserID: actor.ID, EventType: "DELEGATION",
+			NewValue: s.toNullStr(executorIDText),
+			Comment:  s.toNullStr(comment), TxID: &txID, CreatedAt: time.Now(),
+			ExecutorFio:  s.toNullStr(actor.Fio),
+			DelegatorFio: s.toNullStr(actor.Fio),
+		}
+		return s.addHistoryAndPublish(ctx, tx, item, updated, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateDashboardCache(ctx, true, true)
+	return s.FindOrderByID(ctx, order.ID)
+}
+
+// ClaimNextOrder — работа «по запросу»: сотрудник сам берёт следующую заявку из очереди департамента.
+func (s *OrderService) ClaimNextOrder(ctx context.Context) (*dto.OrderResponseDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := s.resolveActorFromContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.DepartmentID == nil {
+		return nil, apperrors.NewBadRequestError("Пользователь не привязан к департаменту. Брать заявки из общей очереди нельзя.")
+	}
+
+	var order *entities.Order
+	err = s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		order, err = s.orderRepo.ClaimNextOrderInTx(ctx, tx, actor.ID, *actor.DepartmentID)
+		if err != nil || order == nil {
+			return err
+		}
+
+		executorIDText := fmt.Sprintf("%d", actor.ID)
+		txID := uuid.New()
+		item := &repositories.OrderHistoryItem{
+			OrderID: order.ID, UserID: actor.ID, EventType: "DELEGATION",
+			NewValue: s.toNullStr(executorIDText),
+			Comment:  s.toNullStr("Заявку из общей очереди департамента забрал: " + actor.Fio),
+			TxID:     &txID, CreatedAt: time.Now(),
+			ExecutorFio:  s.toNullStr(actor.Fio),
+			DelegatorFio: s.toNullStr(actor.Fio),
+		}
+		return s.addHistoryAndPublish(ctx, tx, item, *order, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, nil
+	}
+
+	s.invalidateDashboardCache(ctx, true, true)
+	// Заявка уже прочитана транзакцией захвата, повторно её не запрашиваем
+	attachments := s.loadOrderAttachments(ctx, order.ID, 100, 0)
+	result := s.toResponseDTO(order, nil, nil, attachments)
+	s.applyFirstResponseCountdown(ctx, result, order, s.slaCalendar(ctx), time.Now())
+	return result, nil
+}
+
+func claimedOrderError(order *entities.Order) error {
+	if order.ExecutorName != nil && *order.ExecutorName != "" {
+		return apperrors.NewHttpError(http.StatusConflict, fmt.Sprintf("Заявку уже забрал: %s.", *order.ExecutorName), nil, nil)
+	}
+	return apperrors.NewHttpError(http.StatusConflict, "Заявку уже забрал другой сотрудник.", nil, nil)
+}
+
+// logRoutingAssignment пишет в историю, куда маршрутизация направила новую заявку:
+// конкретному исполнителю (DELEGATION) или в очередь команды (TEAM_ASSIGN).
+func (s *OrderService) logRoutingAssignment(ctx context.Context, tx pgx.Tx, order *entities.Order, actor *entities.User, routing *RoutingResult, txID uuid.UUID) error {
+	if routing.Team != nil {
+		order.TeamName = &routing.Team.Name
+		return s.logTeamAssignment(ctx, tx, order, actor, txID)
+	}
+
+	delegationText := "Назначено на: " + routing.Executor.Fio
+	executorIDText := fmt.Sprintf("%d", routing.Executor.ID)
+	return s.logHistoryEvent(ctx, tx, order.ID, actor, "DELEGATION", &executorIDText, nil, &delegationText, txID, *order)
+}
+
+func (s *OrderService) logTeamAssignment(ctx context.Context, tx pgx.Tx, order *entities.Order, actor *entities.User, txID uuid.UUID) error {
+	teamName := ""
+	if order.TeamName != nil {
+		teamName = *order.TeamName
+	}
+	text := fmt.Sprintf("Назначено на команду «%s»", teamName)
+	if teamName == "" {
+		text = "Назначено на команду"
+	}
+	teamIDText := fmt.Sprintf("%d", *order.TeamID)
+	return s.logHistoryEvent(ctx, tx, order.ID, actor, "TEAM_ASSIGN", &teamIDText, nil, &text, txID, *order)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type claimNextTxManagerStub struct{}
+
+func (claimNextTxManagerStub) RunInTransaction(_ context.Context, fn func(tx pgx.Tx) error) error {
+	return fn(nil)
+}
+
+type claimNextOrderRepoStub struct {
+	repositories.OrderRepositoryInterface
+	calls        int
+	userID       uint64
+	departmentID uint64
+	// next — заявка в очереди; nil — очередь пуста
+	next *entities.Order
+}
+
+func (s *claimNextOrderRepoStub) ClaimNextOrderInTx(_ context.Context, _ pgx.Tx, userID, departmentID uint64) (*entities.Order, error) {
+	s.calls++
+	s.userID, s.departmentID = userID, departmentID
+	if s.next == nil {
+		return nil, nil
+	}
+	claimed := *s.next
+	claimed.ExecutorID = &userID
+	return &claimed, nil
+}
+
+// FindByID не должен вызываться: заявку возвращает сам захват
+func (s *claimNextOrderRepoStub) FindByID(context.Context, uint64) (*entities.Order, error) {
+	return nil, errors.New("unexpected FindByID after claim")
+}
+
+type claimNextHistoryRepoStub struct {
+	repositories.OrderHistoryRepositoryInterface
+	items []repositories.OrderHistoryItem
+}
+
+func (s *claimNextHistoryRepoStub) CreateInTx(_ context.Context, _ pgx.Tx, item *repositories.OrderHistoryItem) error {
+	s.items = append(s.items, *item)
+	return nil
+}
+
+type claimNextOutboxStub struct {
+	repositories.EventOutboxRepositoryInterface
+	aggregateIDs []uint64
+}
+
+func (s *claimNextOutboxStub) CreateInTx(_ context.Context, _ pgx.Tx, _ string, aggregateID *uint64, _ []byte) error {
+	s.aggregateIDs = append(s.aggregateIDs, *aggregateID)
+	return nil
+}
+
+type claimNextAttachmentRepoStub struct {
+	repositories.AttachmentRepositoryInterface
+}
+
+func (claimNextAttachmentRepoStub) FindAllByOrderID(context.Context, uint64, int, int) ([]entities.Attachment, error) {
+	return nil, nil
+}
+
+func claimNextTestContext(actor *entities.User) context.Context {
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, actor.ID)
+	return context.WithValue(ctx, contextkeys.UserEntityKey, actor)
+}
+
+func TestClaimNextOrder_EmptyQueueReturnsNil(t *testing.T) {
+	repo := &claimNextOrderRepoStub{}
+	// historyRepo не задан: при пустой очереди история писаться не должна.
+	service := &OrderService{orderRepo: repo, txManager: claimNextTxManagerStub{}, logger: zap.NewNop()}
+
+	departmentID := uint64(7)
+	res, err := service.ClaimNextOrder(claimNextTestContext(&entities.User{ID: 3, Fio: "Исполнитель", DepartmentID: &departmentID}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res != nil {
+		t.Fatalf("expected nil when queue is empty, got %+v", res)
+	}
+	if repo.calls != 1 || repo.userID != 3 || repo.departmentID != 7 {
+		t.Fatalf("unexpected claim call: calls=%d user=%d department=%d", repo.calls, repo.userID, repo.departmentID)
+	}
+}
+
+func TestClaimNextOrder_RequiresDepartment(t *testing.T) {
+	repo := &claimNextOrderRepoStub{}
+	service := &OrderService{orderRepo: repo, txManager: claimNextTxManagerStub{}, logger: zap.NewNop()}
+
+	_, err := service.ClaimNextOrder(claimNextTestContext(&entities.User{ID: 3}))
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %v", err)
+	}
+	if repo.calls != 0 {
+		t.Fatalf("queue must not be touched without department, got %d calls", repo.calls)
+	}
+}
+
+func TestClaimNextOrder_AssignsExecutorAndWritesHistory(t *testing.T) {
+	departmentID := uint64(7)
+	repo := &claimNextOrderRepoStub{next: &entities.Order{ID: 42, Name: "Не работает принтер", DepartmentID: &departmentID}}
+	history := &claimNextHistoryRepoStub{}
+	outbox := &claimNextOutboxStub{}
+	service := &OrderService{
+		orderRepo: repo, historyRepo: history, eventOutbox: outbox, attachRepo: claimNextAttachmentRepoStub{},
+		txManager: claimNextTxManagerStub{}, logger: zap.NewNop(),
+	}
+
+	res, err := service.ClaimNextOrder(claimNextTestContext(&entities.User{ID: 3, Fio: "Исполнитель", DepartmentID: &departmentID}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res == nil || res.ID != 42 || res.ExecutorID == nil || *res.ExecutorID != 3 {
+		t.Fatalf("expected order 42 assigned to user 3, got %+v", res)
+	}
+	if len(history.items) != 1 {
+		t.Fatalf("expected one history entry, got %d", len(history.items))
+	}
+	item := history.items[0]
+	if item.OrderID != 42 || item.UserID != 3 || item.EventType != "DELEGATION" || item.NewValue.String != "3" {
+		t.Fatalf("unexpected history entry: %+v", item)
+	}
+	if len(outbox.aggregateIDs) != 1 || outbox.aggregateIDs[0] != 42 {
+		t.Fatalf("expected the history event in the outbox for order 42, got %v", outbox.aggregateIDs)
+	}
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"mime/multipart"
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,11 @@ import (
 )
 
 func (s *OrderService) UpdateOrder(ctx context.Context, orderID uint64, updateDTO dto.UpdateOrderDTO, file *multipart.FileHeader, explicitFields map[string]interface{}) (*dto.OrderResponseDTO, error) {
+	updateDTO.Sanitize(explicitFields)
+	if name, ok := explicitFields["name"]; ok && (name == nil || name == "") {
+		return nil, apperrors.NewBadRequestError("Название заявки не может быть пустым.")
+	}
+
 	currentOrder, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
 		return nil, err
@@ -26,8 +33,8 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID uint64, updateDT
 		invalidateActivity bool
 	)
 
-	status, _ := s.statusRepo.FindStatus(ctx, currentOrder.StatusID)
-	if status != nil && status.Code != nil && *status.Code == "CLOSED" {
+	status, _ := s.statuses.FindByID(ctx, currentOrder.StatusID)
+	if isOrderLocked(status) {
 		return nil, apperrors.NewBadRequestError("Заявка закрыта. Редактирование запрещено.")
 	}
 
@@ -41,7 +48,10 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID uint64, updateDT
 	if err := s.validateUpdateFieldPermissions(authCtx, explicitFields, file); err != nil {
 		return nil, err
 	}
-	if err := s.validateUpdateCommentRequirement(ctx, currentOrder, updateDTO); err != nil {
+	if err := s.validateUpdateRules(ctx, currentOrder, updateDTO, explicitFields); err != nil {
+		return nil, err
+	}
+	if err := s.validateResolutionCode(ctx, updateDTO, explicitFields); err != nil {
 		return nil, err
 	}
 
@@ -58,6 +68,16 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID uint64, updateDT
 		fieldsChanged := utils.SmartUpdate(&updated, explicitFields)
 		updated.UpdatedAt = now
 
+		customFieldDefs, customFieldsChanged, err := s.applyUpdateCustomFields(ctx, currentOrder, &updated, explicitFields)
+		if err != nil {
+			return err
+		}
+		fieldsChanged = fieldsChanged || customFieldsChanged
+
+		if err := s.applyUpdatePriorityMatrix(ctx, currentOrder, &updated, explicitFields); err != nil {
+			return err
+		}
+
 		routingChanged, err := s.applyUpdateExecutorRouting(ctx, tx, orderID, currentOrder, &updated, updateDTO, explicitFields, authCtx)
 		if err != nil {
 			return err
@@ -75,6 +95,12 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID uint64, updateDT
 		if err != nil {
 			return err
 		}
+		if customFieldsChanged {
+			if err := s.logCustomFieldChanges(ctx, tx, currentOrder, &updated, customFieldDefs, authCtx.Actor, txID); err != nil {
+				return err
+			}
+			historyChanged = true
+		}
 
 		if file != nil {
 			if _, err := s.attachFileToOrderInTx(ctx, tx, orderID, authCtx.Actor.ID, file, &txID, &updated); err != nil {
@@ -102,3 +128,53 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID uint64, updateDT
 	s.invalidateDashboardCache(ctx, invalidateSummary, invalidateActivity)
 	return s.FindOrderByID(ctx, orderID)
 }
+
+// AttachFileToOrder прикрепляет уже загруженный в память файл к заявке (например, фото из Telegram).
+func (s *OrderService) AttachFileToOrder(ctx context.Context, orderID uint64, fileName, fileType string, content []byte) (*dto.OrderResponseDTO, error) {
+	if len(content) == 0 {
+		return nil, apperrors.NewBadRequestError("Файл пустой.")
+	}
+
+	currentOrder, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, _ := s.statuses.FindByID(ctx, currentOrder.StatusID)
+	if isOrderLocked(status) {
+		return nil, apperrors.NewBadRequestError("Заявка закрыта. Редактирование запрещено.")
+	}
+
+	authCtx, err := s.buildAuthzContextWithTarget(ctx, currentOrder)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.OrdersUpdate, *authCtx) {
+		return nil, apperrors.ErrForbidden
+	}
+	if !authz.CanDo(authz.OrdersUpdateFile, *authCtx) {
+		return nil, apperrors.NewHttpError(
+			http.StatusForbidden,
+			"У вас нет прав добавлять файл к заявке.",
+			nil,
+			map[string]interface{}{"field": "file", "permission": authz.OrdersUpdateFile},
+		)
+	}
+
+	err = s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		txID := uuid.New()
+		updated := *currentOrder
+		updated.UpdatedAt = time.Now().In(time.Local)
+
+		if _, err := s.attachReaderToOrderInTx(ctx, tx, orderID, authCtx.Actor.ID, bytes.NewReader(content), fileName, fileType, int64(len(content)), &txID, &updated); err != nil {
+			return err
+		}
+		return s.orderRepo.Update(ctx, tx, &updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateDashboardCache(ctx, false, true)
+	return s.FindOrderByID(ctx, orderID)
+}
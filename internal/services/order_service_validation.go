@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"mime/multipart"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
@@ -14,10 +15,19 @@ import (
 	"request-system/internal/authz"
 	"request-system/internal/dto"
 	"request-system/internal/entities"
+	pkgconstants "request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/utils"
 )
 
+// isOrderLocked — заявка закрыта (в том числе как дубликат) и больше не редактируется.
+func isOrderLocked(status *entities.Status) bool {
+	if status == nil || status.Code == nil {
+		return false
+	}
+	return *status.Code == pkgconstants.StatusClosed || *status.Code == pkgconstants.StatusDuplicate
+}
+
 func buildOrderRoutingContext(orderTypeID, departmentID, otdelID, branchID, officeID *uint64) OrderContext {
 	return OrderContext{
 		OrderTypeID:  utils.SafeDeref(orderTypeID),
@@ -28,17 +38,60 @@ func buildOrderRoutingContext(orderTypeID, departmentID, otdelID, branchID, offi
 	}
 }
 
-func (s *OrderService) validateUpdateCommentRequirement(ctx context.Context, currentOrder *entities.Order, updateDTO dto.UpdateOrderDTO) error {
-	orderTypeCode, _ := s.orderTypeRepo.FindCodeByID(ctx, *currentOrder.OrderTypeID)
-	if orderTypeCode == "EQUIPMENT" {
+// validateUpdateRules проверяет правила типа заявки с on_update: комментарий нужен к каждому
+// изменению, остальные обязательные поля нельзя очистить.
+func (s *OrderService) validateUpdateRules(ctx context.Context, currentOrder *entities.Order, updateDTO dto.UpdateOrderDTO, explicitFields map[string]interface{}) error {
+	if currentOrder.OrderTypeID == nil {
 		return nil
 	}
-	if updateDTO.Comment == nil || strings.TrimSpace(*updateDTO.Comment) == "" {
-		return apperrors.NewBadRequestError("Для сохранения изменений необходимо добавить комментарий с описанием действий.")
+	rules, err := s.validationRules.ValidationRules(ctx, *currentOrder.OrderTypeID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if !rule.OnUpdate {
+			continue
+		}
+		if rule.FieldName == "comment" {
+			if updateDTO.Comment == nil || strings.TrimSpace(*updateDTO.Comment) == "" {
+				return apperrors.NewBadRequestError(rule.ErrorMessage)
+			}
+			continue
+		}
+		if value, ok := explicitFields[rule.FieldName]; ok && isEmptyPatchValue(value) {
+			return apperrors.NewBadRequestError(rule.ErrorMessage)
+		}
 	}
 	return nil
 }
 
+// validateResolutionCode — код решения в патче должен быть из справочника и действовать;
+// null снимает код с заявки.
+func (s *OrderService) validateResolutionCode(ctx context.Context, updateDTO dto.UpdateOrderDTO, explicitFields map[string]interface{}) error {
+	if _, ok := explicitFields["resolution_code_id"]; !ok || updateDTO.ResolutionCodeID == nil {
+		return nil
+	}
+	code, err := s.resolutionCodeRepo.FindByID(ctx, *updateDTO.ResolutionCodeID)
+	if errors.Is(err, apperrors.ErrNotFound) || (err == nil && !code.IsActive) {
+		return apperrors.NewBadRequestError("Код решения не найден или отключён.")
+	}
+	return err
+}
+
+// isEmptyPatchValue — значение патча очищает поле: null, пустая строка или нулевой ID.
+func isEmptyPatchValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case float64:
+		return v == 0
+	default:
+		return false
+	}
+}
+
 func (s *OrderService) applyUpdateExecutorRouting(
 	ctx context.Context,
 	tx pgx.Tx,
@@ -88,7 +141,13 @@ func (s *OrderService) applyUpdateExecutorRouting(
 			if err != nil {
 				return false, s.wrapExecutorResolutionError(err, updated)
 			}
-			updated.ExecutorID = &res.Executor.ID
+			if res.Team != nil {
+				updated.TeamID = &res.Team.ID
+				updated.TeamName = &res.Team.Name
+				updated.ExecutorID = nil
+			} else {
+				updated.ExecutorID = &res.Executor.ID
+			}
 		}
 		routingChanged = true
 	}
@@ -104,19 +163,40 @@ func (s *OrderService) wrapExecutorResolutionError(err error, order *entities.Or
 	return err
 }
 
+// validateUpdateFieldPermissions проверяет каждое поле патча отдельным правом order:update:<поле>.
+// Поля вне orderUpdateFieldPermissions (кроме custom_fields) изменить нельзя: иначе через патч
+// можно было бы переписать автора, тип, команду или метрики заявки.
 func (s *OrderService) validateUpdateFieldPermissions(authCtx *authz.Context, explicitFields map[string]interface{}, file *multipart.FileHeader) error {
-	for fieldName, spec := range orderUpdateFieldPermissions {
-		if _, exists := explicitFields[fieldName]; !exists {
+	fieldNames := make([]string, 0, len(explicitFields))
+	for fieldName := range explicitFields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+
+	for _, fieldName := range fieldNames {
+		if fieldName == orderCustomFieldsPatchKey {
 			continue
 		}
+		spec, known := orderUpdateFieldPermissions[fieldName]
+		if !known {
+			return apperrors.NewHttpErrorWithDetails(
+				http.StatusBadRequest,
+				fmt.Sprintf("Поле «%s» нельзя изменить.", fieldName),
+				nil,
+				nil,
+				map[string]interface{}{"field": fieldName},
+			)
+		}
 		if authz.CanDo(spec.Permission, *authCtx) {
 			continue
 		}
-		return apperrors.NewHttpError(
+		details := map[string]interface{}{"field": fieldName, "permission": spec.Permission}
+		return apperrors.NewHttpErrorWithDetails(
 			http.StatusForbidden,
 			fmt.Sprintf("У вас нет прав изменять поле «%s».", spec.Label),
 			nil,
-			map[string]interface{}{"field": fieldName, "permission": spec.Permission},
+			details,
+			details,
 		)
 	}
 
@@ -192,43 +272,64 @@ func buildMissingResponsibleError(order *entities.Order) string {
 }
 
 func (s *OrderService) GetValidationConfigForOrderType(ctx context.Context, orderTypeID uint64) (map[string]interface{}, error) {
-	code, err := s.orderTypeRepo.FindCodeByID(ctx, orderTypeID)
+	if _, err := s.orderTypeRepo.FindByID(ctx, orderTypeID); err != nil {
+		return nil, err
+	}
+	rules, err := s.validationRules.ValidationRules(ctx, orderTypeID)
 	if err != nil {
 		return nil, err
 	}
-	if rules, ok := ValidationRegistry[code]; ok {
-		m := make(map[string]interface{})
-		for _, r := range rules {
+	m := make(map[string]interface{})
+	for _, r := range rules {
+		if r.OnCreate {
 			m[r.FieldName] = r.ErrorMessage
 		}
-		return m, nil
 	}
-	return map[string]interface{}{}, nil
+	return m, nil
 }
 
-func (s *OrderService) validateOrderRules(ctx context.Context, d dto.CreateOrderDTO) error {
-	if d.OrderTypeID == nil {
+func (s *OrderService) IsCommentRequiredOnUpdate(ctx context.Context, orderTypeID uint64) (bool, error) {
+	rules, err := s.validationRules.ValidationRules(ctx, orderTypeID)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range rules {
+		if r.FieldName == "comment" && r.OnUpdate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkOrderTypeAvailable — тип заявки должен быть активен и виден департаменту или филиалу автора.
+func (s *OrderService) checkOrderTypeAvailable(ctx context.Context, actor *entities.User, orderTypeID *uint64) error {
+	if orderTypeID == nil {
 		return nil
 	}
-	code, err := s.orderTypeRepo.FindCodeByID(ctx, *d.OrderTypeID)
+	available, err := s.orderTypeRepo.IsAvailable(ctx, *orderTypeID, actor.DepartmentID, actor.BranchID)
 	if err != nil {
-		return nil
+		return err
 	}
-
-	if rules, ok := OrderValidationRules[code]; ok {
-		for _, field := range rules {
-			if !s.checkFieldPresence(d, field) {
-				return apperrors.NewBadRequestError(fmt.Sprintf("Поле %s обязательно.", field))
-			}
-		}
+	if !available {
+		return apperrors.NewBadRequestError("Этот тип заявки недоступен для создания.")
 	}
+	return nil
+}
 
-	if code != "EQUIPMENT" {
-		if !s.checkFieldPresence(d, "comment") {
-			return apperrors.NewBadRequestError("Для данного типа заявки необходимо заполнить поле 'Комментарий'.")
+// validateOrderRules проверяет обязательные при создании поля типа заявки (order_type_validation_rules).
+func (s *OrderService) validateOrderRules(ctx context.Context, d dto.CreateOrderDTO) error {
+	if d.OrderTypeID == nil {
+		return nil
+	}
+	rules, err := s.validationRules.ValidationRules(ctx, *d.OrderTypeID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if rule.OnCreate && !s.checkFieldPresence(d, rule.FieldName) {
+			return apperrors.NewBadRequestError(rule.ErrorMessage)
 		}
 	}
-
 	return nil
 }
 
@@ -239,9 +340,25 @@ func (s *OrderService) checkFieldPresence(d dto.CreateOrderDTO, field string) bo
 	case "equipment_type_id":
 		return d.EquipmentTypeID != nil && *d.EquipmentTypeID != 0
 	case "priority_id":
+		// Приоритет посчитается по матрице
+		if d.Impact != nil || d.Urgency != nil {
+			return true
+		}
 		return d.PriorityID != nil && *d.PriorityID != 0
 	case "comment":
 		return d.Comment != nil && strings.TrimSpace(*d.Comment) != ""
+	case "address":
+		return d.Address != nil && strings.TrimSpace(*d.Address) != ""
+	case "duration":
+		return d.Duration != nil && !d.Duration.IsZero()
+	case "department_id":
+		return d.DepartmentID != nil && *d.DepartmentID != 0
+	case "otdel_id":
+		return d.OtdelID != nil && *d.OtdelID != 0
+	case "branch_id":
+		return d.BranchID != nil && *d.BranchID != 0
+	case "office_id":
+		return d.OfficeID != nil && *d.OfficeID != 0
 	default:
 		return true
 	}
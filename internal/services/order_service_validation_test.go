@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+func TestValidateUpdateFieldPermissions(t *testing.T) {
+	service := &OrderService{}
+	authCtx := &authz.Context{
+		Actor: &entities.User{ID: 1},
+		Permissions: map[string]bool{
+			authz.OrdersUpdate:         true,
+			authz.OrdersUpdateStatusID: true,
+			authz.OrdersUpdateComment:  true,
+		},
+	}
+
+	allowed := map[string]interface{}{"status_id": float64(3), "comment": "взял в работу", "custom_fields": map[string]interface{}{"floor": "2"}}
+	if err := service.validateUpdateFieldPermissions(authCtx, allowed, nil); err != nil {
+		t.Fatalf("permitted fields rejected: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		fields map[string]interface{}
+		code   int
+		field  string
+	}{
+		{"executor without permission", map[string]interface{}{"status_id": float64(3), "executor_id": float64(7)}, http.StatusForbidden, "executor_id"},
+		{"clearing the deadline", map[string]interface{}{"duration": nil}, http.StatusForbidden, "duration"},
+		{"matrix priority", map[string]interface{}{"impact": "high"}, http.StatusForbidden, "impact"},
+		{"creator is not editable", map[string]interface{}{"user_id": float64(9)}, http.StatusBadRequest, "user_id"},
+		{"metrics are not editable", map[string]interface{}{"resolution_time_seconds": float64(1)}, http.StatusBadRequest, "resolution_time_seconds"},
+		{"first field in name order is reported", map[string]interface{}{"priority_id": float64(1), "executor_id": float64(7)}, http.StatusForbidden, "executor_id"},
+	}
+	for _, tc := range cases {
+		err := service.validateUpdateFieldPermissions(authCtx, tc.fields, nil)
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != tc.code {
+			t.Errorf("%s: want %d, got %v", tc.name, tc.code, err)
+			continue
+		}
+		details, _ := httpErr.Details.(map[string]interface{})
+		if details["field"] != tc.field {
+			t.Errorf("%s: details = %v, want field %s", tc.name, httpErr.Details, tc.field)
+		}
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"request-system/internal/dto"
 	"request-system/internal/entities"
 	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/sanitize"
 	"request-system/pkg/utils"
 
 	"github.com/jackc/pgx/v5"
@@ -28,11 +30,14 @@ type OrderTypeServiceInterface interface {
 	GetByID(ctx context.Context, id uint64) (*dto.OrderTypeResponseDTO, error)
 	GetAll(ctx context.Context, limit, offset uint64, search string) (*dto.PaginatedResponse[dto.OrderTypeResponseDTO], error)
 	GetConfig(ctx context.Context, orderTypeID uint64) (map[string]interface{}, error)
+	// GetAvailable — активные типы, которые текущий пользователь может выбрать при создании заявки.
+	GetAvailable(ctx context.Context) ([]dto.OrderTypeResponseDTO, error)
 }
 
 type OrderTypeService struct {
 	repo       repositories.OrderTypeRepositoryInterface
 	userRepo   repositories.UserRepositoryInterface
+	statusRepo repositories.StatusRepositoryInterface
 	txManager  repositories.TxManagerInterface
 	ruleEngine RuleEngineServiceInterface
 	logger     *zap.Logger
@@ -41,6 +46,7 @@ type OrderTypeService struct {
 func NewOrderTypeService(
 	repo repositories.OrderTypeRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
+	statusRepo repositories.StatusRepositoryInterface,
 	txManager repositories.TxManagerInterface,
 	ruleEngine RuleEngineServiceInterface,
 	logger *zap.Logger,
@@ -48,6 +54,7 @@ func NewOrderTypeService(
 	return &OrderTypeService{
 		repo:       repo,
 		userRepo:   userRepo,
+		statusRepo: statusRepo,
 		txManager:  txManager,
 		ruleEngine: ruleEngine,
 		logger:     logger,
@@ -68,7 +75,7 @@ func (s *OrderTypeService) buildAuthzContext(ctx context.Context) (*authz.Contex
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap, Target: nil}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx), Target: nil}, nil
 }
 
 func toResponseDTO(entity *entities.OrderType) *dto.OrderTypeResponseDTO {
@@ -77,11 +84,22 @@ func toResponseDTO(entity *entities.OrderType) *dto.OrderTypeResponseDTO {
 	}
 
 	resp := &dto.OrderTypeResponseDTO{
-		ID:        uint64(entity.ID),
-		Name:      entity.Name,
-		StatusID:  entity.StatusID,
-		CreatedAt: entity.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: entity.UpdatedAt.Format(time.RFC3339),
+		ID:            uint64(entity.ID),
+		Name:          entity.Name,
+		StatusID:      entity.StatusID,
+		IsActive:      entity.IsActive,
+		Icon:          entity.Icon,
+		Description:   entity.Description,
+		DepartmentIDs: entity.DepartmentIDs,
+		BranchIDs:     entity.BranchIDs,
+		CreatedAt:     entity.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     entity.UpdatedAt.Format(time.RFC3339),
+	}
+	if resp.DepartmentIDs == nil {
+		resp.DepartmentIDs = []uint64{}
+	}
+	if resp.BranchIDs == nil {
+		resp.BranchIDs = []uint64{}
 	}
 
 	if entity.Code != nil {
@@ -116,10 +134,24 @@ func (s *OrderTypeService) Create(ctx context.Context, createDTO dto.CreateOrder
 
 	codePtr := &finalCode
 
+	statusID, err := s.resolveStatusID(ctx, createDTO.StatusID, createDTO.IsActive)
+	if err != nil {
+		return nil, err
+	}
 	entity := &entities.OrderType{
-		Name:     createDTO.Name,
-		Code:     codePtr,
-		StatusID: createDTO.StatusID,
+		Name:        createDTO.Name,
+		Code:        codePtr,
+		StatusID:    statusID,
+		Icon:        nilIfEmpty(sanitize.LinePtr(createDTO.Icon)),
+		Description: nilIfEmpty(sanitize.TextPtr(createDTO.Description)),
+	}
+	departmentIDs := createDTO.DepartmentIDs
+	if departmentIDs == nil {
+		departmentIDs = []uint64{}
+	}
+	branchIDs := createDTO.BranchIDs
+	if branchIDs == nil {
+		branchIDs = []uint64{}
 	}
 
 	// 4. Транзакция
@@ -151,7 +183,7 @@ func (s *OrderTypeService) Create(ctx context.Context, createDTO dto.CreateOrder
 		}
 
 		entity.ID = int(id)
-		return nil
+		return s.repo.SetVisibility(ctx, tx, id, departmentIDs, branchIDs)
 	})
 	if err != nil {
 		s.logger.Error("Ошибка при создании типа заявки", zap.Error(err))
@@ -174,16 +206,37 @@ func (s *OrderTypeService) Update(ctx context.Context, id uint64, updateDTO dto.
 	if err != nil {
 		return nil, err
 	}
-	nameChanged := false
-	codeChanged := false
+	nameChanged := updateDTO.Name != nil && *updateDTO.Name != existingEntity.Name
+	codeChanged := updateDTO.Code != nil && (existingEntity.Code == nil || *updateDTO.Code != *existingEntity.Code)
 	if updateDTO.Name != nil {
 		existingEntity.Name = *updateDTO.Name
 	}
 	if updateDTO.Code != nil {
 		existingEntity.Code = updateDTO.Code
 	}
-	if updateDTO.StatusID != nil {
-		existingEntity.StatusID = *updateDTO.StatusID
+	if updateDTO.StatusID != nil || updateDTO.IsActive != nil {
+		statusID := 0
+		if updateDTO.StatusID != nil {
+			statusID = *updateDTO.StatusID
+		}
+		statusID, err := s.resolveStatusID(ctx, statusID, updateDTO.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		existingEntity.StatusID = statusID
+	}
+	if updateDTO.Icon != nil {
+		existingEntity.Icon = nilIfEmpty(sanitize.LinePtr(updateDTO.Icon))
+	}
+	if updateDTO.Description != nil {
+		existingEntity.Description = nilIfEmpty(sanitize.TextPtr(updateDTO.Description))
+	}
+	var departmentIDs, branchIDs []uint64
+	if updateDTO.DepartmentIDs != nil {
+		departmentIDs = append([]uint64{}, *updateDTO.DepartmentIDs...)
+	}
+	if updateDTO.BranchIDs != nil {
+		branchIDs = append([]uint64{}, *updateDTO.BranchIDs...)
 	}
 	now := time.Now()
 	existingEntity.UpdatedAt = &now
@@ -210,14 +263,41 @@ func (s *OrderTypeService) Update(ctx context.Context, id uint64, updateDTO dto.
 			}
 		}
 
-		return s.repo.Update(ctx, tx, existingEntity)
+		if err := s.repo.Update(ctx, tx, existingEntity); err != nil {
+			return err
+		}
+		return s.repo.SetVisibility(ctx, tx, id, departmentIDs, branchIDs)
 	})
 	if err != nil {
 		s.logger.Error("Ошибка при обновлении типа заявки", zap.Uint64("id", id), zap.Error(err))
 		return nil, err
 	}
 
-	return toResponseDTO(existingEntity), nil
+	return s.GetByID(ctx, id)
+}
+
+// resolveStatusID — is_active важнее status_id; без обоих тип активен.
+func (s *OrderTypeService) resolveStatusID(ctx context.Context, statusID int, isActive *bool) (int, error) {
+	if isActive == nil && statusID != 0 {
+		return statusID, nil
+	}
+	code := pkgconstants.StatusActive
+	if isActive != nil && !*isActive {
+		code = pkgconstants.StatusInactive
+	}
+	id, err := s.statusRepo.FindIDByCode(ctx, code)
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// nilIfEmpty — пустая строка очищает необязательное текстовое поле.
+func nilIfEmpty(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
 }
 
 func (s *OrderTypeService) Delete(ctx context.Context, id uint64) error {
@@ -313,3 +393,24 @@ func (s *OrderTypeService) GetConfig(ctx context.Context, orderTypeID uint64) (m
 		},
 	}, nil
 }
+
+func (s *OrderTypeService) GetAvailable(ctx context.Context) ([]dto.OrderTypeResponseDTO, error) {
+	authContext, err := s.buildAuthzContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.OrdersCreate, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	orderTypes, err := s.repo.FindAvailable(ctx, authContext.Actor.DepartmentID, authContext.Actor.BranchID)
+	if err != nil {
+		s.logger.Error("Ошибка при получении доступных типов заявок", zap.Error(err))
+		return nil, err
+	}
+	result := make([]dto.OrderTypeResponseDTO, 0, len(orderTypes))
+	for _, orderType := range orderTypes {
+		result = append(result, *toResponseDTO(orderType))
+	}
+	return result, nil
+}
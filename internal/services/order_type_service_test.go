@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type orderTypeRepoStub struct {
+	repositories.OrderTypeRepositoryInterface
+	types          map[uint64]*entities.OrderType
+	visibility     map[uint64][2][]uint64
+	availableFor   [2]*uint64
+	availableTypes map[uint64]bool
+}
+
+func (s *orderTypeRepoStub) Create(_ context.Context, _ pgx.Tx, orderType *entities.OrderType) (uint64, error) {
+	id := uint64(len(s.types) + 1)
+	now := time.Now()
+	copied := *orderType
+	copied.ID = int(id)
+	copied.CreatedAt, copied.UpdatedAt = &now, &now
+	s.types[id] = &copied
+	return id, nil
+}
+
+func (s *orderTypeRepoStub) Update(_ context.Context, _ pgx.Tx, orderType *entities.OrderType) error {
+	copied := *orderType
+	s.types[uint64(orderType.ID)] = &copied
+	return nil
+}
+
+func (s *orderTypeRepoStub) FindByID(_ context.Context, id uint64) (*entities.OrderType, error) {
+	orderType, ok := s.types[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	copied := *orderType
+	copied.DepartmentIDs, copied.BranchIDs = s.visibility[id][0], s.visibility[id][1]
+	return &copied, nil
+}
+
+func (s *orderTypeRepoStub) ExistsByName(context.Context, pgx.Tx, string, uint64) (bool, error) {
+	return false, nil
+}
+
+func (s *orderTypeRepoStub) ExistsByCode(context.Context, pgx.Tx, *string, uint64) (bool, error) {
+	return false, nil
+}
+
+func (s *orderTypeRepoStub) SetVisibility(_ context.Context, _ pgx.Tx, id uint64, departmentIDs, branchIDs []uint64) error {
+	current := s.visibility[id]
+	if departmentIDs != nil {
+		current[0] = departmentIDs
+	}
+	if branchIDs != nil {
+		current[1] = branchIDs
+	}
+	s.visibility[id] = current
+	return nil
+}
+
+func (s *orderTypeRepoStub) FindAvailable(_ context.Context, departmentID, branchID *uint64) ([]*entities.OrderType, error) {
+	s.availableFor = [2]*uint64{departmentID, branchID}
+	return []*entities.OrderType{s.types[1]}, nil
+}
+
+func (s *orderTypeRepoStub) IsAvailable(_ context.Context, id uint64, _, _ *uint64) (bool, error) {
+	return s.availableTypes[id], nil
+}
+
+type orderTypeStatusRepoStub struct {
+	repositories.StatusRepositoryInterface
+}
+
+func (orderTypeStatusRepoStub) FindIDByCode(_ context.Context, code string) (uint64, error) {
+	if code == pkgconstants.StatusInactive {
+		return 21, nil
+	}
+	return 20, nil
+}
+
+type orderTypeUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	user entities.User
+}
+
+func (s *orderTypeUserRepoStub) FindUserByID(context.Context, uint64) (*entities.User, error) {
+	user := s.user
+	return &user, nil
+}
+
+func newOrderTypeServiceStubs(permissions ...string) (*orderTypeRepoStub, OrderTypeServiceInterface, context.Context) {
+	departmentID, branchID := uint64(4), uint64(7)
+	repo := &orderTypeRepoStub{types: map[uint64]*entities.OrderType{}, visibility: map[uint64][2][]uint64{}}
+	users := &orderTypeUserRepoStub{user: entities.User{ID: 1, DepartmentID: &departmentID, BranchID: &branchID}}
+	service := NewOrderTypeService(repo, users, orderTypeStatusRepoStub{}, avatarTxManagerStub{}, nil, zap.NewNop())
+
+	granted := make(map[string]bool)
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	return repo, service, context.WithValue(ctx, contextkeys.UserPermissionsMapKey, granted)
+}
+
+func TestOrderTypeService_CreateAndUpdateKeepsVisibility(t *testing.T) {
+	repo, service, ctx := newOrderTypeServiceStubs(authz.OrderTypesCreate, authz.OrderTypesUpdate, authz.OrderTypesView)
+
+	inactive := false
+	icon, description := " 🖨 ", "Картриджи,\r\nбумага"
+	created, err := service.Create(ctx, dto.CreateOrderTypeDTO{
+		Name: "Принтеры", IsActive: &inactive, Icon: &icon, Description: &description, DepartmentIDs: []uint64{4},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.StatusID != 21 || *created.Icon != "🖨" || *created.Description != "Картриджи,\nбумага" {
+		t.Errorf("created = %+v", created)
+	}
+	if len(created.DepartmentIDs) != 1 || created.BranchIDs == nil || len(created.BranchIDs) != 0 {
+		t.Errorf("visibility = %v / %v, want [4] / []", created.DepartmentIDs, created.BranchIDs)
+	}
+
+	empty := ""
+	active := true
+	updated, err := service.Update(ctx, created.ID, dto.UpdateOrderTypeDTO{IsActive: &active, Icon: &empty, BranchIDs: &[]uint64{7}})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.StatusID != 20 || updated.Icon != nil {
+		t.Errorf("updated = %+v, want active status and cleared icon", updated)
+	}
+	if vis := repo.visibility[created.ID]; len(vis[0]) != 1 || len(vis[1]) != 1 {
+		t.Errorf("departments must stay when only branches are sent, got %v", vis)
+	}
+}
+
+func TestOrderTypeService_GetAvailableUsesActorStructure(t *testing.T) {
+	repo, service, ctx := newOrderTypeServiceStubs(authz.OrdersCreate)
+	now := time.Now()
+	repo.types[1] = &entities.OrderType{ID: 1, Name: "Оборудование", IsActive: true}
+	repo.types[1].CreatedAt, repo.types[1].UpdatedAt = &now, &now
+
+	result, err := service.GetAvailable(ctx)
+	if err != nil || len(result) != 1 {
+		t.Fatalf("GetAvailable = %v, %v", result, err)
+	}
+	if repo.availableFor[0] == nil || *repo.availableFor[0] != 4 || repo.availableFor[1] == nil || *repo.availableFor[1] != 7 {
+		t.Errorf("available types must be filtered by the actor's department and branch, got %v", repo.availableFor)
+	}
+
+	_, withoutCreate, viewCtx := newOrderTypeServiceStubs(authz.OrderTypesView)
+	if _, err := withoutCreate.GetAvailable(viewCtx); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("without order:create want 403, got %v", err)
+	}
+}
+
+func TestOrderService_RejectsUnavailableOrderType(t *testing.T) {
+	repo := &orderTypeRepoStub{availableTypes: map[uint64]bool{1: true}}
+	service := &OrderService{orderTypeRepo: repo}
+	actor := &entities.User{ID: 1}
+
+	available, hidden := uint64(1), uint64(2)
+	if err := service.checkOrderTypeAvailable(context.Background(), actor, &available); err != nil {
+		t.Errorf("available type rejected: %v", err)
+	}
+	err := service.checkOrderTypeAvailable(context.Background(), actor, &hidden)
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Errorf("hidden or inactive type: want 400, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+// orderValidationRulesTTL — как долго реплика берёт правила из памяти; изменения на этой
+// реплике сбрасывают их сразу, на остальных — через TTL.
+const orderValidationRulesTTL = time.Minute
+
+// orderValidationRuleFields — поля заявки, которые можно сделать обязательными. Исполнитель
+// назначается маршрутизацией, поэтому его в списке нет.
+var orderValidationRuleFields = map[string]bool{
+	"address":           true,
+	"comment":           true,
+	"duration":          true,
+	"department_id":     true,
+	"otdel_id":          true,
+	"branch_id":         true,
+	"office_id":         true,
+	"equipment_id":      true,
+	"equipment_type_id": true,
+	"priority_id":       true,
+}
+
+// OrderValidationRulesProvider отдаёт обязательные поля типа заявки для проверок OrderService.
+type OrderValidationRulesProvider interface {
+	ValidationRules(ctx context.Context, orderTypeID uint64) ([]entities.OrderTypeValidationRule, error)
+}
+
+type OrderTypeValidationServiceInterface interface {
+	OrderValidationRulesProvider
+
+	GetRules(ctx context.Context, orderTypeID uint64) ([]dto.OrderTypeValidationRuleDTO, error)
+	CreateRule(ctx context.Context, orderTypeID uint64, payload dto.CreateOrderTypeValidationRuleDTO) (*dto.OrderTypeValidationRuleDTO, error)
+	UpdateRule(ctx context.Context, orderTypeID, ruleID uint64, payload dto.UpdateOrderTypeValidationRuleDTO) (*dto.OrderTypeValidationRuleDTO, error)
+	DeleteRule(ctx context.Context, orderTypeID, ruleID uint64) error
+}
+
+// OrderTypeValidationService ведёт обязательные поля типов заявок. Правила всех типов
+// держатся в памяти: они читаются при каждом создании и изменении заявки.
+type OrderTypeValidationService struct {
+	repo          repositories.OrderTypeValidationRuleRepositoryInterface
+	orderTypeRepo repositories.OrderTypeRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	logger        *zap.Logger
+
+	mu       sync.Mutex
+	rules    map[uint64][]entities.OrderTypeValidationRule
+	loadedAt time.Time
+}
+
+func NewOrderTypeValidationService(
+	repo repositories.OrderTypeValidationRuleRepositoryInterface,
+	orderTypeRepo repositories.OrderTypeRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) OrderTypeValidationServiceInterface {
+	return &OrderTypeValidationService{repo: repo, orderTypeRepo: orderTypeRepo, userRepo: userRepo, logger: logger}
+}
+
+func (s *OrderTypeValidationService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+// ValidationRules читает правила из памяти и перечитывает их раз в orderValidationRulesTTL.
+// Если база недоступна, используются прежние правила; если их ещё не было — ошибка, чтобы
+// заявка не прошла без проверки.
+func (s *OrderTypeValidationService) ValidationRules(ctx context.Context, orderTypeID uint64) ([]entities.OrderTypeValidationRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rules == nil || time.Since(s.loadedAt) >= orderValidationRulesTTL {
+		all, err := s.repo.FindAll(ctx)
+		if err != nil {
+			if s.rules == nil {
+				return nil, err
+			}
+			s.logger.Warn("Не удалось загрузить правила проверки заявок, используются прежние", zap.Error(err))
+			return s.rules[orderTypeID], nil
+		}
+		s.rules = make(map[uint64][]entities.OrderTypeValidationRule)
+		for _, rule := range all {
+			s.rules[rule.OrderTypeID] = append(s.rules[rule.OrderTypeID], rule)
+		}
+		s.loadedAt = time.Now()
+	}
+	return s.rules[orderTypeID], nil
+}
+
+func (s *OrderTypeValidationService) resetRules() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *OrderTypeValidationService) GetRules(ctx context.Context, orderTypeID uint64) ([]dto.OrderTypeValidationRuleDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OrderTypesView); err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.FindByOrderType(ctx, orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.OrderTypeValidationRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, toOrderTypeValidationRuleDTO(rule))
+	}
+	return result, nil
+}
+
+func (s *OrderTypeValidationService) CreateRule(ctx context.Context, orderTypeID uint64, payload dto.CreateOrderTypeValidationRuleDTO) (*dto.OrderTypeValidationRuleDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.OrderTypesUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.orderTypeRepo.FindByID(ctx, orderTypeID); err != nil {
+		return nil, err
+	}
+
+	rule := &entities.OrderTypeValidationRule{
+		OrderTypeID:  orderTypeID,
+		FieldName:    strings.ToLower(strings.TrimSpace(payload.FieldName)),
+		ErrorMessage: strings.TrimSpace(payload.ErrorMessage),
+		OnCreate:     payload.OnCreate == nil || *payload.OnCreate,
+		OnUpdate:     payload.OnUpdate,
+	}
+	if !orderValidationRuleFields[rule.FieldName] {
+		return nil, apperrors.NewHttpErrorWithDetails(http.StatusBadRequest, "Это поле заявки нельзя сделать обязательным", nil, nil,
+			map[string]interface{}{"field": rule.FieldName, "allowed": orderValidationRuleFieldNames()})
+	}
+	if err := validateOrderTypeValidationRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.resetRules()
+	s.logger.Info("Добавлено правило проверки типа заявки",
+		zap.Uint64("orderTypeID", orderTypeID), zap.String("field", rule.FieldName), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toOrderTypeValidationRuleDTO(*rule)
+	return &result, nil
+}
+
+func (s *OrderTypeValidationService) UpdateRule(ctx context.Context, orderTypeID, ruleID uint64, payload dto.UpdateOrderTypeValidationRuleDTO) (*dto.OrderTypeValidationRuleDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OrderTypesUpdate); err != nil {
+		return nil, err
+	}
+	rule, err := s.findOwnRule(ctx, orderTypeID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.ErrorMessage != nil {
+		rule.ErrorMessage = strings.TrimSpace(*payload.ErrorMessage)
+	}
+	if payload.OnCreate != nil {
+		rule.OnCreate = *payload.OnCreate
+	}
+	if payload.OnUpdate != nil {
+		rule.OnUpdate = *payload.OnUpdate
+	}
+	if err := validateOrderTypeValidationRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.resetRules()
+	result := toOrderTypeValidationRuleDTO(*rule)
+	return &result, nil
+}
+
+func (s *OrderTypeValidationService) DeleteRule(ctx context.Context, orderTypeID, ruleID uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.OrderTypesUpdate)
+	if err != nil {
+		return err
+	}
+	rule, err := s.findOwnRule(ctx, orderTypeID, ruleID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, rule.ID); err != nil {
+		return err
+	}
+	s.resetRules()
+	s.logger.Info("Удалено правило проверки типа заявки",
+		zap.Uint64("orderTypeID", orderTypeID), zap.String("field", rule.FieldName), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+// findOwnRule — правило другого типа заявки для этого URL «не существует».
+func (s *OrderTypeValidationService) findOwnRule(ctx context.Context, orderTypeID, ruleID uint64) (*entities.OrderTypeValidationRule, error) {
+	rule, err := s.repo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.OrderTypeID != orderTypeID {
+		return nil, apperrors.ErrNotFound
+	}
+	return rule, nil
+}
+
+func validateOrderTypeValidationRule(rule *entities.OrderTypeValidationRule) error {
+	if rule.ErrorMessage == "" {
+		return apperrors.NewHttpError(http.StatusBadRequest, "Не указан текст ошибки для незаполненного поля", nil, nil)
+	}
+	if !rule.OnCreate && !rule.OnUpdate {
+		return apperrors.NewHttpError(http.StatusBadRequest, "Правило должно проверяться при создании, при изменении или в обоих случаях", nil, nil)
+	}
+	return nil
+}
+
+func orderValidationRuleFieldNames() []string {
+	names := make([]string, 0, len(orderValidationRuleFields))
+	for name := range orderValidationRuleFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toOrderTypeValidationRuleDTO(rule entities.OrderTypeValidationRule) dto.OrderTypeValidationRuleDTO {
+	return dto.OrderTypeValidationRuleDTO{
+		ID:           rule.ID,
+		OrderTypeID:  rule.OrderTypeID,
+		FieldName:    rule.FieldName,
+		ErrorMessage: rule.ErrorMessage,
+		OnCreate:     rule.OnCreate,
+		OnUpdate:     rule.OnUpdate,
+		CreatedAt:    rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
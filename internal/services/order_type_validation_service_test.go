@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type validationRuleRepoStub struct {
+	repositories.OrderTypeValidationRuleRepositoryInterface
+	rules   []entities.OrderTypeValidationRule
+	loads   int
+	failing bool
+}
+
+func (s *validationRuleRepoStub) FindAll(context.Context) ([]entities.OrderTypeValidationRule, error) {
+	s.loads++
+	if s.failing {
+		return nil, errors.New("db is down")
+	}
+	return append([]entities.OrderTypeValidationRule(nil), s.rules...), nil
+}
+
+func (s *validationRuleRepoStub) Create(_ context.Context, rule *entities.OrderTypeValidationRule) error {
+	rule.ID = uint64(len(s.rules) + 1)
+	s.rules = append(s.rules, *rule)
+	return nil
+}
+
+type validationOrderTypeRepoStub struct {
+	repositories.OrderTypeRepositoryInterface
+}
+
+func (s *validationOrderTypeRepoStub) FindByID(_ context.Context, id uint64) (*entities.OrderType, error) {
+	if id > 2 {
+		return nil, apperrors.ErrNotFound
+	}
+	return &entities.OrderType{ID: int(id)}, nil
+}
+
+func orderTypeValidationCtx() context.Context {
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	return context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.OrderTypesUpdate: true})
+}
+
+func newOrderTypeValidationStubs() (*validationRuleRepoStub, OrderTypeValidationServiceInterface) {
+	repo := &validationRuleRepoStub{rules: []entities.OrderTypeValidationRule{
+		{ID: 1, OrderTypeID: 1, FieldName: "equipment_id", ErrorMessage: "Пожалуйста, укажите оборудование.", OnCreate: true},
+		{ID: 2, OrderTypeID: 2, FieldName: "comment", ErrorMessage: "Нужен комментарий.", OnCreate: true, OnUpdate: true},
+	}}
+	service := NewOrderTypeValidationService(repo, &validationOrderTypeRepoStub{}, &businessCalendarUserRepoStub{}, zap.NewNop())
+	return repo, service
+}
+
+func validationErrorMessage(err error) string {
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		return ""
+	}
+	return httpErr.Message
+}
+
+func TestOrderTypeValidation_CachesRulesUntilChanged(t *testing.T) {
+	repo, service := newOrderTypeValidationStubs()
+	ctx := orderTypeValidationCtx()
+
+	rules, err := service.ValidationRules(ctx, 1)
+	if err != nil || len(rules) != 1 || rules[0].FieldName != "equipment_id" {
+		t.Fatalf("rules of type 1 = %v, %v", rules, err)
+	}
+	if _, err := service.ValidationRules(ctx, 2); err != nil || repo.loads != 1 {
+		t.Fatalf("rules must be read once per TTL, loads = %d, err = %v", repo.loads, err)
+	}
+
+	onCreate := false
+	created, err := service.CreateRule(ctx, 1, dto.CreateOrderTypeValidationRuleDTO{FieldName: " Address ", ErrorMessage: "Укажите адрес.", OnCreate: &onCreate, OnUpdate: true})
+	if err != nil || created.FieldName != "address" || created.OnCreate || !created.OnUpdate {
+		t.Fatalf("CreateRule = %+v, %v", created, err)
+	}
+	if rules, _ := service.ValidationRules(ctx, 1); len(rules) != 2 || repo.loads != 2 {
+		t.Errorf("a change must reset the cache, rules = %v, loads = %d", rules, repo.loads)
+	}
+
+	repo.failing = true
+	if rules, err := service.ValidationRules(ctx, 1); err != nil || len(rules) != 2 {
+		t.Errorf("ValidationRules within TTL = %v, %v", rules, err)
+	}
+}
+
+func TestOrderTypeValidation_RejectsInvalidRules(t *testing.T) {
+	_, service := newOrderTypeValidationStubs()
+	ctx := orderTypeValidationCtx()
+	off := false
+
+	cases := []struct {
+		name        string
+		orderTypeID uint64
+		payload     dto.CreateOrderTypeValidationRuleDTO
+		code        int
+	}{
+		{"executor is routed automatically", 1, dto.CreateOrderTypeValidationRuleDTO{FieldName: "executor_id", ErrorMessage: "x"}, http.StatusBadRequest},
+		{"unknown field", 1, dto.CreateOrderTypeValidationRuleDTO{FieldName: "user_id", ErrorMessage: "x"}, http.StatusBadRequest},
+		{"blank message", 1, dto.CreateOrderTypeValidationRuleDTO{FieldName: "address", ErrorMessage: "  "}, http.StatusBadRequest},
+		{"no stage", 1, dto.CreateOrderTypeValidationRuleDTO{FieldName: "address", ErrorMessage: "x", OnCreate: &off}, http.StatusBadRequest},
+		{"unknown order type", 9, dto.CreateOrderTypeValidationRuleDTO{FieldName: "address", ErrorMessage: "x"}, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		_, err := service.CreateRule(ctx, tc.orderTypeID, tc.payload)
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != tc.code {
+			t.Errorf("%s: want %d, got %v", tc.name, tc.code, err)
+		}
+	}
+
+	forbidden := context.WithValue(orderTypeValidationCtx(), contextkeys.UserPermissionsMapKey, map[string]bool{authz.OrderTypesView: true})
+	if _, err := service.CreateRule(forbidden, 1, dto.CreateOrderTypeValidationRuleDTO{FieldName: "address", ErrorMessage: "x"}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("order_type:view must not change rules, got %v", err)
+	}
+}
+
+func TestOrderService_AppliesValidationRulesFromProvider(t *testing.T) {
+	_, rules := newOrderTypeValidationStubs()
+	service := &OrderService{validationRules: rules, orderTypeRepo: &validationOrderTypeRepoStub{}}
+	ctx := context.Background()
+	equipmentType, otherType := uint64(1), uint64(2)
+
+	err := service.validateOrderRules(ctx, dto.CreateOrderDTO{Name: "Принтер", OrderTypeID: &equipmentType})
+	if validationErrorMessage(err) != "Пожалуйста, укажите оборудование." {
+		t.Errorf("create without equipment: %v", err)
+	}
+	equipmentID := uint64(5)
+	if err := service.validateOrderRules(ctx, dto.CreateOrderDTO{Name: "Принтер", OrderTypeID: &equipmentType, EquipmentID: &equipmentID}); err != nil {
+		t.Errorf("create with equipment: %v", err)
+	}
+
+	config, err := service.GetValidationConfigForOrderType(ctx, otherType)
+	if err != nil || config["comment"] != "Нужен комментарий." || len(config) != 1 {
+		t.Errorf("config = %v, %v", config, err)
+	}
+	if required, _ := service.IsCommentRequiredOnUpdate(ctx, otherType); !required {
+		t.Error("comment rule with on_update must require a comment on update")
+	}
+	if required, _ := service.IsCommentRequiredOnUpdate(ctx, equipmentType); required {
+		t.Error("equipment orders must not require a comment on update")
+	}
+
+	order := &entities.Order{OrderTypeID: &otherType}
+	if err := service.validateUpdateRules(ctx, order, dto.UpdateOrderDTO{}, map[string]interface{}{"status_id": float64(3)}); err == nil {
+		t.Error("update without a comment must be rejected")
+	}
+	comment := "взял в работу"
+	if err := service.validateUpdateRules(ctx, order, dto.UpdateOrderDTO{Comment: &comment}, map[string]interface{}{"comment": comment}); err != nil {
+		t.Errorf("update with a comment: %v", err)
+	}
+}
+
+func TestOrderService_UpdateRulesForbidClearingRequiredFields(t *testing.T) {
+	orderTypeID := uint64(1)
+	repo := &validationRuleRepoStub{rules: []entities.OrderTypeValidationRule{
+		{OrderTypeID: orderTypeID, FieldName: "equipment_id", ErrorMessage: "Оборудование нельзя убрать.", OnUpdate: true},
+	}}
+	service := &OrderService{validationRules: NewOrderTypeValidationService(repo, nil, nil, zap.NewNop())}
+	order := &entities.Order{OrderTypeID: &orderTypeID}
+
+	for _, value := range []interface{}{nil, float64(0), ""} {
+		err := service.validateUpdateRules(context.Background(), order, dto.UpdateOrderDTO{}, map[string]interface{}{"equipment_id": value})
+		if validationErrorMessage(err) != "Оборудование нельзя убрать." {
+			t.Errorf("equipment_id = %#v: %v", value, err)
+		}
+	}
+	if err := service.validateUpdateRules(context.Background(), order, dto.UpdateOrderDTO{}, map[string]interface{}{"equipment_id": float64(4)}); err != nil {
+		t.Errorf("replacing equipment: %v", err)
+	}
+}
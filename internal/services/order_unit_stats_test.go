@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+)
+
+type unitStatsUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	user entities.User
+}
+
+func (s *unitStatsUserRepoStub) FindUserByID(context.Context, uint64) (*entities.User, error) {
+	user := s.user
+	return &user, nil
+}
+
+type unitStatsOrderRepoStub struct {
+	repositories.OrderRepositoryInterface
+	departmentID uint64
+	otdelID      *uint64
+	calls        int
+}
+
+func (s *unitStatsOrderRepoStub) GetUnitOrderStats(_ context.Context, departmentID uint64, otdelID *uint64, _ time.Time) (*types.UserOrderStats, error) {
+	s.calls++
+	s.departmentID, s.otdelID = departmentID, otdelID
+	return &types.UserOrderStats{TotalCount: 4}, nil
+}
+
+func TestGetUnitStatsScopesToHeadsUnit(t *testing.T) {
+	id := func(v uint64) *uint64 { return &v }
+	yes, no := true, false
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(5))
+
+	cases := []struct {
+		name      string
+		user      entities.User
+		wantErr   bool
+		wantOtdel *uint64
+	}{
+		{"otdel head", entities.User{IsHead: &yes, DepartmentID: id(2), OtdelID: id(7)}, false, id(7)},
+		{"department head", entities.User{IsHead: &yes, DepartmentID: id(2)}, false, nil},
+		{"not a head", entities.User{IsHead: &no, DepartmentID: id(2), OtdelID: id(7)}, true, nil},
+		{"head without department", entities.User{IsHead: &yes}, true, nil},
+	}
+	for _, tc := range cases {
+		repo := &unitStatsOrderRepoStub{}
+		service := &OrderService{orderRepo: repo, userRepo: &unitStatsUserRepoStub{user: tc.user}}
+
+		stats, err := service.GetUnitStats(ctx)
+		if tc.wantErr {
+			if !errors.Is(err, apperrors.ErrForbidden) || repo.calls != 0 {
+				t.Errorf("%s: want forbidden without a query, got %v (calls %d)", tc.name, err, repo.calls)
+			}
+			continue
+		}
+		if err != nil || stats.TotalCount != 4 {
+			t.Fatalf("%s: unexpected result %+v, %v", tc.name, stats, err)
+		}
+		if repo.departmentID != 2 {
+			t.Errorf("%s: department = %d, want 2", tc.name, repo.departmentID)
+		}
+		if (repo.otdelID == nil) != (tc.wantOtdel == nil) || (repo.otdelID != nil && *repo.otdelID != *tc.wantOtdel) {
+			t.Errorf("%s: otdel = %v, want %v", tc.name, repo.otdelID, tc.wantOtdel)
+		}
+	}
+}
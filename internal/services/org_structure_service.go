@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+// OrgStructureServiceInterface отдаёт оргструктуру одним деревом вместо четырёх плоских справочников.
+type OrgStructureServiceInterface interface {
+	GetTree(ctx context.Context) (*dto.OrgStructureDTO, error)
+}
+
+type OrgStructureService struct {
+	repo     repositories.OrgStructureRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewOrgStructureService(
+	repo repositories.OrgStructureRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) OrgStructureServiceInterface {
+	return &OrgStructureService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+// GetTree — в дерево попадают только те виды подразделений, справочники которых доступны пользователю.
+func (s *OrgStructureService) GetTree(ctx context.Context) (*dto.OrgStructureDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	visible := map[string]bool{
+		entities.OrgUnitDepartment: authz.CanDo(authz.DepartmentsView, *authContext),
+		entities.OrgUnitOtdel:      authz.CanDo(authz.OtdelsView, *authContext),
+		entities.OrgUnitBranch:     authz.CanDo(authz.BranchesView, *authContext),
+		entities.OrgUnitOffice:     authz.CanDo(authz.OfficesView, *authContext),
+	}
+	if !visible[entities.OrgUnitDepartment] && !visible[entities.OrgUnitOtdel] &&
+		!visible[entities.OrgUnitBranch] && !visible[entities.OrgUnitOffice] {
+		return nil, apperrors.ErrForbidden
+	}
+
+	units, err := s.repo.FindUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]entities.OrgUnit, 0, len(units))
+	for _, u := range units {
+		if visible[u.Kind] {
+			allowed = append(allowed, u)
+		}
+	}
+	return buildOrgTree(allowed), nil
+}
+
+type orgNodeKey struct {
+	kind string
+	id   uint64
+}
+
+// buildOrgTree раскладывает плоский список по уровням: отдел вкладывается в родительский отдел,
+// иначе в департамент, иначе в филиал; офис — в родительский офис, иначе в филиал.
+// Узлы, чьего родителя нет в списке, выводятся на верхний уровень своей ветки.
+func buildOrgTree(units []entities.OrgUnit) *dto.OrgStructureDTO {
+	nodes := make(map[orgNodeKey]*dto.OrgNodeDTO, len(units))
+	for _, u := range units {
+		node := &dto.OrgNodeDTO{
+			ID:           u.ID,
+			Type:         u.Kind,
+			Name:         u.Name,
+			StatusID:     u.StatusID,
+			ActiveOrders: u.ActiveOrders,
+			Children:     []*dto.OrgNodeDTO{},
+		}
+		if u.HeadID != nil && u.HeadFio != nil {
+			node.Head = &dto.OrgHeadDTO{UserID: *u.HeadID, Fio: *u.HeadFio}
+		}
+		nodes[orgNodeKey{u.Kind, u.ID}] = node
+	}
+
+	parentOf := func(u entities.OrgUnit) *dto.OrgNodeDTO {
+		var candidates []orgNodeKey
+		switch u.Kind {
+		case entities.OrgUnitOtdel:
+			if u.ParentID != nil && *u.ParentID != u.ID {
+				candidates = append(candidates, orgNodeKey{entities.OrgUnitOtdel, *u.ParentID})
+			}
+			if u.DepartmentID != nil {
+				candidates = append(candidates, orgNodeKey{entities.OrgUnitDepartment, *u.DepartmentID})
+			}
+			if u.BranchID != nil {
+				candidates = append(candidates, orgNodeKey{entities.OrgUnitBranch, *u.BranchID})
+			}
+		case entities.OrgUnitOffice:
+			if u.ParentID != nil && *u.ParentID != u.ID {
+				candidates = append(candidates, orgNodeKey{entities.OrgUnitOffice, *u.ParentID})
+			}
+			if u.BranchID != nil {
+				candidates = append(candidates, orgNodeKey{entities.OrgUnitBranch, *u.BranchID})
+			}
+		}
+		for _, key := range candidates {
+			if parent, ok := nodes[key]; ok {
+				return parent
+			}
+		}
+		return nil
+	}
+
+	result := &dto.OrgStructureDTO{Departments: []*dto.OrgNodeDTO{}, Branches: []*dto.OrgNodeDTO{}}
+	for _, u := range units {
+		node := nodes[orgNodeKey{u.Kind, u.ID}]
+		if parent := parentOf(u); parent != nil {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		switch u.Kind {
+		case entities.OrgUnitDepartment, entities.OrgUnitOtdel:
+			result.Departments = append(result.Departments, node)
+		default:
+			result.Branches = append(result.Branches, node)
+		}
+	}
+	return result
+}
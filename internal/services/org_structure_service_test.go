@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"request-system/internal/entities"
+)
+
+func TestBuildOrgTreeNestsUnits(t *testing.T) {
+	u := func(v uint64) *uint64 { return &v }
+	headFio := "Иванов И.И."
+	units := []entities.OrgUnit{
+		{Kind: entities.OrgUnitBranch, ID: 1, Name: "Худжанд", ActiveOrders: 4},
+		{Kind: entities.OrgUnitDepartment, ID: 1, Name: "ИТ", HeadID: u(10), HeadFio: &headFio, ActiveOrders: 7},
+		{Kind: entities.OrgUnitOffice, ID: 5, Name: "ЦБО-1", BranchID: u(1)},
+		{Kind: entities.OrgUnitOffice, ID: 6, Name: "Мини-офис", BranchID: u(1), ParentID: u(5)},
+		{Kind: entities.OrgUnitOtdel, ID: 2, Name: "Сеть", DepartmentID: u(1)},
+		{Kind: entities.OrgUnitOtdel, ID: 3, Name: "Группа Wi-Fi", DepartmentID: u(1), ParentID: u(2)},
+		{Kind: entities.OrgUnitOtdel, ID: 4, Name: "Отдел филиала", BranchID: u(1)},
+		{Kind: entities.OrgUnitOtdel, ID: 9, Name: "Сирота", DepartmentID: u(99)},
+	}
+
+	tree := buildOrgTree(units)
+
+	if len(tree.Departments) != 2 || tree.Departments[0].Name != "ИТ" || tree.Departments[1].Name != "Сирота" {
+		t.Fatalf("unexpected department roots: %+v", tree.Departments)
+	}
+	it := tree.Departments[0]
+	if it.Head == nil || it.Head.UserID != 10 || it.ActiveOrders != 7 {
+		t.Fatalf("department head or counter lost: %+v", it)
+	}
+	if len(it.Children) != 1 || it.Children[0].ID != 2 || len(it.Children[0].Children) != 1 || it.Children[0].Children[0].ID != 3 {
+		t.Fatalf("otdels are not nested under the department: %+v", it.Children)
+	}
+
+	if len(tree.Branches) != 1 {
+		t.Fatalf("unexpected branch roots: %+v", tree.Branches)
+	}
+	branch := tree.Branches[0]
+	if len(branch.Children) != 2 || branch.Children[0].ID != 5 || branch.Children[1].ID != 4 {
+		t.Fatalf("unexpected branch children: %+v", branch.Children)
+	}
+	if len(branch.Children[0].Children) != 1 || branch.Children[0].Children[0].ID != 6 {
+		t.Fatalf("nested office is not under its parent: %+v", branch.Children[0].Children)
+	}
+}
@@ -220,5 +220,5 @@ func (s *OtdelService) buildAuthzContext(ctx context.Context) (*authz.Context, e
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissions}, nil
+	return &authz.Context{Actor: actor, Permissions: permissions, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
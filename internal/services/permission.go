@@ -5,8 +5,10 @@ import (
 
 	"request-system/internal/authz"
 	"request-system/internal/dto"
+	"request-system/internal/events"
 	"request-system/internal/repositories"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/eventbus"
 	"request-system/pkg/utils"
 
 	"go.uber.org/zap"
@@ -20,23 +22,31 @@ type PermissionServiceInterface interface {
 	UpdatePermission(ctx context.Context, id uint64, dto dto.UpdatePermissionDTO) (*dto.PermissionDTO, error)
 	DeletePermission(ctx context.Context, id uint64) error
 	FindPermissionByName(ctx context.Context, name string) (*dto.PermissionDTO, error)
+	// FlushPermissionsCache сразу сбрасывает кэш прав указанных пользователей и ролей либо всех.
+	FlushPermissionsCache(ctx context.Context, payload dto.FlushPermissionsCacheDTO) (*dto.FlushPermissionsCacheResultDTO, error)
 }
 
 type PermissionService struct {
-	permissionRepository repositories.PermissionRepositoryInterface
-	userRepo             repositories.UserRepositoryInterface
-	logger               *zap.Logger
+	permissionRepository  repositories.PermissionRepositoryInterface
+	userRepo              repositories.UserRepositoryInterface
+	authPermissionService AuthPermissionServiceInterface
+	bus                   *eventbus.Bus
+	logger                *zap.Logger
 }
 
 func NewPermissionService(
 	permissionRepository repositories.PermissionRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
+	authPermissionService AuthPermissionServiceInterface,
+	bus *eventbus.Bus,
 	logger *zap.Logger,
 ) PermissionServiceInterface {
 	return &PermissionService{
-		permissionRepository: permissionRepository,
-		userRepo:             userRepo,
-		logger:               logger,
+		permissionRepository:  permissionRepository,
+		userRepo:              userRepo,
+		authPermissionService: authPermissionService,
+		bus:                   bus,
+		logger:                logger,
 	}
 }
 
@@ -53,7 +63,7 @@ func (s *PermissionService) buildAuthzContext(ctx context.Context) (*authz.Conte
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap, Target: nil}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx), Target: nil}, nil
 }
 
 func (s *PermissionService) GetPermissions(ctx context.Context, limit uint64, offset uint64, search string) ([]dto.PermissionDTO, uint64, error) {
@@ -101,7 +111,15 @@ func (s *PermissionService) UpdatePermission(ctx context.Context, id uint64, dto
 	if !authz.CanDo(authz.PermissionsUpdate, *authContext) {
 		return nil, apperrors.ErrForbidden
 	}
-	return s.permissionRepository.UpdatePermission(ctx, id, dto)
+	updated, err := s.permissionRepository.UpdatePermission(ctx, id, dto)
+	if err != nil {
+		return nil, err
+	}
+	// В кэше права хранятся по имени, а переименованная привилегия может быть у кого угодно
+	if dto.Name != "" {
+		publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "permission.updated", All: true})
+	}
+	return updated, nil
 }
 
 func (s *PermissionService) DeletePermission(ctx context.Context, id uint64) error {
@@ -112,7 +130,47 @@ func (s *PermissionService) DeletePermission(ctx context.Context, id uint64) err
 	if !authz.CanDo(authz.PermissionsDelete, *authContext) {
 		return apperrors.ErrForbidden
 	}
-	return s.permissionRepository.DeletePermission(ctx, id)
+	if err := s.permissionRepository.DeletePermission(ctx, id); err != nil {
+		return err
+	}
+	publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "permission.deleted", All: true})
+	return nil
+}
+
+func (s *PermissionService) FlushPermissionsCache(ctx context.Context, payload dto.FlushPermissionsCacheDTO) (*dto.FlushPermissionsCacheResultDTO, error) {
+	authContext, err := s.buildAuthzContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.PermissionsFlushCache, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	if len(payload.UserIDs) == 0 && len(payload.RoleIDs) == 0 {
+		if err := s.authPermissionService.FlushPermissionsCache(ctx); err != nil {
+			return nil, apperrors.ErrInternalServer
+		}
+		s.logger.Warn("Кэш прав сброшен вручную для всех", zap.Uint64("by", authContext.Actor.ID))
+		return &dto.FlushPermissionsCacheResultDTO{All: true}, nil
+	}
+
+	result := &dto.FlushPermissionsCacheResultDTO{}
+	for _, roleID := range payload.RoleIDs {
+		count, err := s.authPermissionService.InvalidateRolePermissionsCache(ctx, roleID)
+		if err != nil {
+			return nil, apperrors.ErrInternalServer
+		}
+		result.Users += count
+	}
+	for _, userID := range payload.UserIDs {
+		if err := s.authPermissionService.InvalidateUserPermissionsCache(ctx, userID); err != nil {
+			return nil, apperrors.ErrInternalServer
+		}
+		result.Users++
+	}
+	s.logger.Info("Кэш прав сброшен вручную",
+		zap.Uint64s("roleIDs", payload.RoleIDs), zap.Uint64s("userIDs", payload.UserIDs), zap.Uint64("by", authContext.Actor.ID))
+	return result, nil
 }
 
 func (s *PermissionService) FindPermissionByName(ctx context.Context, name string) (*dto.PermissionDTO, error) {
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+)
+
+const portalCaptchaKey = "portal:captcha:%s"
+
+var captchaVerifyURLs = map[string]string{
+	config.CaptchaProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	config.CaptchaProviderReCaptcha: "https://www.google.com/recaptcha/api/siteverify",
+}
+
+var errCaptchaFailed = errors.New("captcha check failed")
+
+// portalCaptcha выдаёт и проверяет капчу портала. Встроенная капча — пример на сложение/вычитание,
+// нарисованный сегментами в SVG (без <text>, чтобы ответ нельзя было просто прочитать из разметки).
+// Ответ хранится в Redis и годится для одной попытки.
+type portalCaptcha struct {
+	cfg        config.PortalConfig
+	cache      repositories.CacheRepositoryInterface
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+func newPortalCaptcha(cfg config.PortalConfig, cache repositories.CacheRepositoryInterface, logger *zap.Logger) *portalCaptcha {
+	return &portalCaptcha{
+		cfg:        cfg,
+		cache:      cache,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}
+}
+
+func (c *portalCaptcha) issue(ctx context.Context) (string, string, error) {
+	a, b := 10+rand.IntN(40), 1+rand.IntN(9)
+	op, answer := "+", a+b
+	if rand.IntN(2) == 0 {
+		op, answer = "-", a-b
+	}
+
+	id := uuid.NewString()
+	if err := c.cache.Set(ctx, fmt.Sprintf(portalCaptchaKey, id), strconv.Itoa(answer), c.cfg.CaptchaTTL); err != nil {
+		return "", "", err
+	}
+	return id, renderCaptchaSVG(fmt.Sprintf("%d%s%d=", a, op, b)), nil
+}
+
+// verify возвращает errCaptchaFailed, если ответ неверный, устарел или токен не подтверждён провайдером.
+func (c *portalCaptcha) verify(ctx context.Context, captchaID, answer, token, clientIP string) error {
+	if verifyURL, ok := captchaVerifyURLs[c.cfg.CaptchaProvider]; ok {
+		return c.verifyRemote(ctx, verifyURL, token, clientIP)
+	}
+
+	captchaID, answer = strings.TrimSpace(captchaID), strings.TrimSpace(answer)
+	if captchaID == "" || answer == "" {
+		return errCaptchaFailed
+	}
+	key := fmt.Sprintf(portalCaptchaKey, captchaID)
+	expected, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return errCaptchaFailed
+	}
+	// Одна попытка на картинку: иначе ответ легко подобрать перебором.
+	_ = c.cache.Del(ctx, key)
+	if expected != answer {
+		return errCaptchaFailed
+	}
+	return nil
+}
+
+func (c *portalCaptcha) verifyRemote(ctx context.Context, verifyURL, token, clientIP string) error {
+	if strings.TrimSpace(token) == "" {
+		return errCaptchaFailed
+	}
+	form := url.Values{"secret": {c.cfg.CaptchaSecret}, "response": {token}}
+	if clientIP != "" {
+		form.Set("remoteip", clientIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Сервис капчи недоступен", zap.String("provider", c.cfg.CaptchaProvider), zap.Error(err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.Success {
+		c.logger.Info("Капча не пройдена", zap.String("provider", c.cfg.CaptchaProvider), zap.Strings("errors", result.ErrorCodes))
+		return errCaptchaFailed
+	}
+	return nil
+}
+
+// Сегменты символа в ячейке 20x36: a — верх, b/c — справа, d — низ, e/f — слева, g — середина.
+var captchaSegments = map[byte][4]float64{
+	'a': {0, 0, 20, 0},
+	'b': {20, 0, 20, 18},
+	'c': {20, 18, 20, 36},
+	'd': {0, 36, 20, 36},
+	'e': {0, 18, 0, 36},
+	'f': {0, 0, 0, 18},
+	'g': {0, 18, 20, 18},
+	'+': {10, 8, 10, 28},
+	'=': {2, 24, 18, 24},
+}
+
+var captchaGlyphs = map[rune]string{
+	'0': "abcdef", '1': "bc", '2': "abged", '3': "abgcd", '4': "fgbc",
+	'5': "afgcd", '6': "afgedc", '7': "abc", '8': "abcdefg", '9': "abcdfg",
+	'+': "g+", '-': "g", '=': "g=",
+}
+
+func renderCaptchaSVG(text string) string {
+	const cellW, height = 34, 60
+	width := cellW*len(text) + 20
+	jitter := func(v float64) float64 { return v + rand.Float64()*4 - 2 }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`, width, height, width, height)
+	sb.WriteString(`<rect width="100%" height="100%" fill="#f4f4f4"/>`)
+
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#9aa" stroke-width="1.5"/>`,
+			rand.Float64()*float64(width), rand.Float64()*height, rand.Float64()*float64(width), rand.Float64()*height)
+	}
+
+	for i, ch := range text {
+		x := 12 + float64(i*cellW) + rand.Float64()*4
+		y := 10 + rand.Float64()*6
+		angle := rand.Float64()*24 - 12
+		fmt.Fprintf(&sb, `<g transform="translate(%.1f %.1f) rotate(%.1f 10 18)" stroke="#234" stroke-width="3.5" stroke-linecap="round" fill="none">`, x, y, angle)
+		segments := captchaGlyphs[ch]
+		for j := 0; j < len(segments); j++ {
+			s := captchaSegments[segments[j]]
+			if ch == '=' && segments[j] == 'g' {
+				s = [4]float64{2, 12, 18, 12}
+			}
+			fmt.Fprintf(&sb, `<path d="M%.1f %.1fL%.1f %.1f"/>`, jitter(s[0]), jitter(s[1]), jitter(s[2]), jitter(s[3]))
+		}
+		sb.WriteString(`</g>`)
+	}
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+const (
+	portalTrackingCodeLength = 10
+	// Без 0/O и 1/I, чтобы код можно было продиктовать по телефону.
+	portalTrackingAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	portalOrderNameMaxLen  = 80
+)
+
+type PortalServiceInterface interface {
+	NewCaptcha(ctx context.Context) (*dto.PortalCaptchaDTO, error)
+	SubmitRequest(ctx context.Context, req dto.PortalSubmitDTO, clientIP string) (*dto.PortalRequestStatusDTO, error)
+	GetRequestStatus(ctx context.Context, trackingCode string) (*dto.PortalRequestStatusDTO, error)
+}
+
+// PortalService принимает обращения клиентов без авторизации и превращает их в заявки
+// служебного пользователя портала. Права этого пользователя определяют, какие поля можно заполнить.
+type PortalService struct {
+	cfg                   config.PortalConfig
+	orderService          OrderServiceInterface
+	portalRepo            repositories.PortalRequestRepositoryInterface
+	userRepo              repositories.UserRepositoryInterface
+	orderTypeRepo         repositories.OrderTypeRepositoryInterface
+	authPermissionService AuthPermissionServiceInterface
+	captcha               *portalCaptcha
+	logger                *zap.Logger
+
+	orderTypeMu sync.Mutex
+	orderTypeID uint64
+}
+
+func NewPortalService(
+	cfg config.PortalConfig,
+	orderService OrderServiceInterface,
+	portalRepo repositories.PortalRequestRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	orderTypeRepo repositories.OrderTypeRepositoryInterface,
+	authPermissionService AuthPermissionServiceInterface,
+	cacheRepo repositories.CacheRepositoryInterface,
+	logger *zap.Logger,
+) PortalServiceInterface {
+	return &PortalService{
+		cfg:                   cfg,
+		orderService:          orderService,
+		portalRepo:            portalRepo,
+		userRepo:              userRepo,
+		orderTypeRepo:         orderTypeRepo,
+		authPermissionService: authPermissionService,
+		captcha:               newPortalCaptcha(cfg, cacheRepo, logger),
+		logger:                logger,
+	}
+}
+
+func (s *PortalService) NewCaptcha(ctx context.Context) (*dto.PortalCaptchaDTO, error) {
+	if _, remote := captchaVerifyURLs[s.cfg.CaptchaProvider]; remote {
+		return &dto.PortalCaptchaDTO{Provider: s.cfg.CaptchaProvider, SiteKey: s.cfg.CaptchaSiteKey}, nil
+	}
+
+	id, image, err := s.captcha.issue(ctx)
+	if err != nil {
+		s.logger.Error("Не удалось выдать капчу портала", zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
+	return &dto.PortalCaptchaDTO{
+		Provider:  config.CaptchaProviderBuiltin,
+		CaptchaID: id,
+		Image:     image,
+		ExpiresIn: int(s.cfg.CaptchaTTL.Seconds()),
+	}, nil
+}
+
+func (s *PortalService) SubmitRequest(ctx context.Context, req dto.PortalSubmitDTO, clientIP string) (*dto.PortalRequestStatusDTO, error) {
+	if err := s.captcha.verify(ctx, req.CaptchaID, req.CaptchaAnswer, req.CaptchaToken, clientIP); err != nil {
+		if errors.Is(err, errCaptchaFailed) {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest, "Проверка «я не робот» не пройдена. Обновите картинку и попробуйте ещё раз.", nil, nil)
+		}
+		return nil, apperrors.NewHttpError(http.StatusServiceUnavailable, "Сервис проверки временно недоступен. Попробуйте позже.", err, nil)
+	}
+
+	customerName := strings.TrimSpace(req.Name)
+	description := strings.TrimSpace(req.Description)
+	phone, ok := normalizePortalPhone(req.Phone)
+	if !ok {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Укажите номер телефона полностью, с кодом.", nil, nil)
+	}
+	if customerName == "" || description == "" {
+		return nil, apperrors.NewBadRequestError("Заполните имя и описание обращения.")
+	}
+	if req.BranchID == nil && s.cfg.DepartmentID == 0 {
+		return nil, apperrors.NewBadRequestError("Выберите филиал.")
+	}
+	if req.BranchID != nil {
+		active, err := s.portalRepo.IsBranchActive(ctx, *req.BranchID)
+		if err != nil {
+			s.logger.Error("Не удалось проверить филиал обращения с портала", zap.Uint64("branch_id", *req.BranchID), zap.Error(err))
+			return nil, apperrors.ErrInternalServer
+		}
+		if !active {
+			return nil, apperrors.NewBadRequestError("Филиал не найден. Выберите филиал из списка.")
+		}
+	}
+
+	orderTypeID, err := s.portalOrderTypeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	portalCtx, err := s.portalUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := fmt.Sprintf("Клиент: %s\nТелефон: %s\n\n%s", customerName, phone, description)
+	createDTO := dto.CreateOrderDTO{
+		Name:        portalOrderName(description),
+		OrderTypeID: &orderTypeID,
+		Comment:     &comment,
+		BranchID:    req.BranchID,
+	}
+	if s.cfg.DepartmentID != 0 {
+		departmentID := s.cfg.DepartmentID
+		createDTO.DepartmentID = &departmentID
+	}
+
+	order, err := s.orderService.CreateOrder(portalCtx, createDTO, nil)
+	if err != nil {
+		s.logger.Error("Не удалось создать заявку с портала", zap.Error(err))
+		return nil, err
+	}
+
+	record := &entities.PortalRequest{
+		OrderID:      order.ID,
+		TrackingCode: newPortalTrackingCode(),
+		CustomerName: customerName,
+		Phone:        phone,
+	}
+	if clientIP != "" {
+		record.ClientIP = &clientIP
+	}
+	if err := s.portalRepo.Create(ctx, record); err != nil {
+		s.logger.Error("Заявка с портала создана, но код отслеживания не сохранён",
+			zap.Uint64("order_id", order.ID), zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
+
+	s.logger.Info("Принято обращение с портала", zap.Uint64("order_id", order.ID), zap.String("tracking_code", record.TrackingCode))
+	return s.GetRequestStatus(ctx, record.TrackingCode)
+}
+
+func (s *PortalService) GetRequestStatus(ctx context.Context, trackingCode string) (*dto.PortalRequestStatusDTO, error) {
+	trackingCode = strings.ToUpper(strings.TrimSpace(trackingCode))
+	if len(trackingCode) != portalTrackingCodeLength {
+		return nil, apperrors.ErrNotFound
+	}
+
+	st, err := s.portalRepo.FindStatusByTrackingCode(ctx, trackingCode)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PortalRequestStatusDTO{
+		TrackingCode: st.TrackingCode,
+		Status:       st.StatusName,
+		StatusCode:   st.StatusCode,
+		IsClosed:     pkgconstants.IsFinalStatus(st.StatusCode),
+		CreatedAt:    st.CreatedAt,
+		UpdatedAt:    st.UpdatedAt,
+		CompletedAt:  st.CompletedAt,
+	}, nil
+}
+
+func (s *PortalService) portalOrderTypeID(ctx context.Context) (uint64, error) {
+	s.orderTypeMu.Lock()
+	defer s.orderTypeMu.Unlock()
+	if s.orderTypeID != 0 {
+		return s.orderTypeID, nil
+	}
+
+	id, err := s.orderTypeRepo.FindIDByCode(ctx, s.cfg.OrderTypeCode)
+	if err != nil {
+		s.logger.Error("Тип заявки портала не найден", zap.String("code", s.cfg.OrderTypeCode), zap.Error(err))
+		return 0, apperrors.ErrInternalServer
+	}
+	s.orderTypeID = id
+	return id, nil
+}
+
+// portalUserContext подставляет в контекст служебного пользователя портала с его правами,
+// как это делает middleware авторизации для обычных запросов.
+func (s *PortalService) portalUserContext(ctx context.Context) (context.Context, error) {
+	user, err := s.userRepo.FindUserByID(ctx, s.cfg.UserID)
+	if err != nil {
+		s.logger.Error("Служебный пользователь портала не найден", zap.Uint64("user_id", s.cfg.UserID), zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
+	perms, err := s.authPermissionService.GetAllUserPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	permMap := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		permMap[p] = true
+	}
+	conditions, err := s.authPermissionService.GetUserPermissionConditions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	userCtx := context.WithValue(ctx, contextkeys.UserIDKey, user.ID)
+	userCtx = utils.WithTenantID(userCtx, user.TenantID)
+	userCtx = context.WithValue(userCtx, contextkeys.UserPermissionsMapKey, permMap)
+	userCtx = utils.WithPermissionConditions(userCtx, conditions)
+	return context.WithValue(userCtx, contextkeys.UserEntityKey, user), nil
+}
+
+// normalizePortalPhone оставляет цифры и ведущий «+»; допустимо 9–15 цифр (E.164).
+func normalizePortalPhone(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	var sb strings.Builder
+	digits := 0
+	for i, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			sb.WriteRune(r)
+			digits++
+		case r == '+' && i == 0:
+			sb.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+	return sb.String(), digits >= 9 && digits <= 15
+}
+
+// portalOrderName — первая строка описания, обрезанная до разумной длины названия заявки.
+func portalOrderName(description string) string {
+	name := strings.TrimSpace(strings.SplitN(description, "\n", 2)[0])
+	if utf8.RuneCountInString(name) > portalOrderNameMaxLen {
+		name = strings.TrimSpace(string([]rune(name)[:portalOrderNameMaxLen-1])) + "…"
+	}
+	return "Портал: " + name
+}
+
+func newPortalTrackingCode() string {
+	buf := make([]byte, portalTrackingCodeLength)
+	_, _ = rand.Read(buf)
+	for i, b := range buf {
+		buf[i] = portalTrackingAlphabet[int(b)%len(portalTrackingAlphabet)]
+	}
+	return string(buf)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+)
+
+type memoryCache struct {
+	values map[string]string
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) (string, error) {
+	v, ok := m.values[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func (m *memoryCache) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	m.values[key] = value.(string)
+	return nil
+}
+
+func (m *memoryCache) Del(_ context.Context, keys ...string) error {
+	for _, k := range keys {
+		delete(m.values, k)
+	}
+	return nil
+}
+
+func (m *memoryCache) GetDel(ctx context.Context, key string) (string, error) {
+	v, err := m.Get(ctx, key)
+	delete(m.values, key)
+	return v, err
+}
+
+func (m *memoryCache) Incr(_ context.Context, key string) (int64, error) {
+	n, _ := strconv.ParseInt(m.values[key], 10, 64)
+	n++
+	m.values[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (m *memoryCache) Expire(context.Context, string, time.Duration) (bool, error) { return true, nil }
+
+func TestPortalBuiltinCaptchaIsSingleUse(t *testing.T) {
+	cache := &memoryCache{values: map[string]string{}}
+	captcha := newPortalCaptcha(config.PortalConfig{CaptchaProvider: config.CaptchaProviderBuiltin, CaptchaTTL: time.Minute}, cache, zap.NewNop())
+	ctx := context.Background()
+
+	id, image, err := captcha.issue(ctx)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if !strings.HasPrefix(image, "<svg") || strings.Contains(image, "<text") {
+		t.Fatalf("captcha image must be an svg without plain text: %.60s", image)
+	}
+
+	answer := cache.values[fmt.Sprintf(portalCaptchaKey, id)]
+	if err := captcha.verify(ctx, id, "not-a-number", "", ""); !errors.Is(err, errCaptchaFailed) {
+		t.Fatalf("expected wrong answer to fail, got %v", err)
+	}
+	// Неверная попытка сжигает картинку — правильный ответ после неё уже не принимается.
+	if err := captcha.verify(ctx, id, answer, "", ""); !errors.Is(err, errCaptchaFailed) {
+		t.Fatalf("expected captcha to be single-use, got %v", err)
+	}
+
+	id, _, _ = captcha.issue(ctx)
+	if err := captcha.verify(ctx, id, " "+cache.values[fmt.Sprintf(portalCaptchaKey, id)]+" ", "", ""); err != nil {
+		t.Fatalf("expected correct answer to pass, got %v", err)
+	}
+}
+
+func TestNormalizePortalPhone(t *testing.T) {
+	cases := map[string]struct {
+		want string
+		ok   bool
+	}{
+		"+992 (93) 123-45-67": {"+992931234567", true},
+		"931234567":           {"931234567", true},
+		"12345":               {"12345", false},
+		"93 123 45 67; DROP":  {"", false},
+		"9+92931234567":       {"", false},
+	}
+	for in, tc := range cases {
+		got, ok := normalizePortalPhone(in)
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Fatalf("normalizePortalPhone(%q) = %q, %v; want %q, %v", in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestPortalOrderNameAndTrackingCode(t *testing.T) {
+	if got := portalOrderName("Не работает банкомат\nВ отделении на Рудаки"); got != "Портал: Не работает банкомат" {
+		t.Fatalf("unexpected order name %q", got)
+	}
+	if got := portalOrderName(strings.Repeat("я", 200)); len([]rune(got)) != len([]rune("Портал: "))+portalOrderNameMaxLen {
+		t.Fatalf("expected long name to be truncated, got %d runes", len([]rune(got)))
+	}
+
+	code := newPortalTrackingCode()
+	if len(code) != portalTrackingCodeLength || strings.Trim(code, portalTrackingAlphabet) != "" {
+		t.Fatalf("unexpected tracking code %q", code)
+	}
+}
+
+type portalRepoStub struct {
+	repositories.PortalRequestRepositoryInterface
+	activeBranches map[uint64]bool
+}
+
+func (s *portalRepoStub) IsBranchActive(_ context.Context, branchID uint64) (bool, error) {
+	return s.activeBranches[branchID], nil
+}
+
+type portalOrderServiceStub struct {
+	OrderServiceInterface
+	created int
+}
+
+func (s *portalOrderServiceStub) CreateOrder(context.Context, dto.CreateOrderDTO, *multipart.FileHeader) (*dto.OrderResponseDTO, error) {
+	s.created++
+	return nil, errors.New("not expected")
+}
+
+func TestPortalSubmitRejectsUnknownOrInactiveBranch(t *testing.T) {
+	cache := &memoryCache{values: map[string]string{}}
+	cfg := config.PortalConfig{CaptchaProvider: config.CaptchaProviderBuiltin, CaptchaTTL: time.Minute}
+	orders := &portalOrderServiceStub{}
+	service := NewPortalService(cfg, orders, &portalRepoStub{activeBranches: map[uint64]bool{1: true}}, nil, nil, nil, cache, zap.NewNop())
+
+	for _, branchID := range []uint64{2, 404} {
+		captcha := service.(*PortalService).captcha
+		id, _, _ := captcha.issue(context.Background())
+		req := dto.PortalSubmitDTO{
+			Name: "Клиент", Phone: "+992 (93) 123-45-67", Description: "Не работает банкомат",
+			BranchID: &branchID, CaptchaID: id, CaptchaAnswer: cache.values[fmt.Sprintf(portalCaptchaKey, id)],
+		}
+		_, err := service.SubmitRequest(context.Background(), req, "10.0.0.1")
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Fatalf("branch %d: expected 400, got %v", branchID, err)
+		}
+	}
+	if orders.created != 0 {
+		t.Fatalf("no order must be created for an unknown branch, got %d", orders.created)
+	}
+}
@@ -3,10 +3,14 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"regexp"
 	"strings" // Добавляем импорт для работы со строками
 
 	"request-system/internal/authz"
 	"request-system/internal/dto"
+	"request-system/internal/entities"
 	"request-system/internal/repositories"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/utils"
@@ -14,12 +18,17 @@ import (
 	"go.uber.org/zap"
 )
 
+var priorityColorPattern = regexp.MustCompile(`^#[0-9A-F]{6}$`)
+
 type PriorityServiceInterface interface {
 	GetPriorities(ctx context.Context, limit, offset uint64, search string) (*dto.PaginatedResponse[dto.PriorityDTO], error)
 	FindPriority(ctx context.Context, id uint64) (*dto.PriorityDTO, error)
 	CreatePriority(ctx context.Context, createDTO dto.CreatePriorityDTO) (*dto.PriorityDTO, error)
 	UpdatePriority(ctx context.Context, id uint64, updateDTO dto.UpdatePriorityDTO) (*dto.PriorityDTO, error)
 	DeletePriority(ctx context.Context, id uint64) error
+
+	GetMatrix(ctx context.Context) (*dto.PriorityMatrixDTO, error)
+	UpdateMatrix(ctx context.Context, updateDTO dto.UpdatePriorityMatrixDTO) (*dto.PriorityMatrixDTO, error)
 }
 
 type PriorityService struct {
@@ -49,7 +58,7 @@ func (s *PriorityService) buildAuthzContext(ctx context.Context) (*authz.Context
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
 }
 
 func (s *PriorityService) GetPriorities(ctx context.Context, limit, offset uint64, search string) (*dto.PaginatedResponse[dto.PriorityDTO], error) {
@@ -101,6 +110,13 @@ func (s *PriorityService) CreatePriority(ctx context.Context, createDTO dto.Crea
 		createDTO.Code = strings.ToUpper(strings.ReplaceAll(createDTO.Name, " ", "_"))
 		s.logger.Debug("Поле 'code' не было предоставлено, сгенерировано автоматически", zap.String("generated_code", createDTO.Code))
 	}
+	if createDTO.Color != nil {
+		color, err := normalizePriorityColor(*createDTO.Color)
+		if err != nil {
+			return nil, err
+		}
+		createDTO.Color = nilIfEmpty(&color)
+	}
 
 	return s.repo.CreatePriority(ctx, createDTO)
 }
@@ -114,10 +130,22 @@ func (s *PriorityService) UpdatePriority(ctx context.Context, id uint64, updateD
 		return nil, apperrors.ErrForbidden
 	}
 
-	if _, err := s.repo.FindPriority(ctx, id); err != nil {
+	current, err := s.repo.FindPriority(ctx, id)
+	if err != nil {
 		s.logger.Warn("Попытка обновить несуществующий приоритет", zap.Uint64("id", id), zap.Error(err))
 		return nil, err
 	}
+	if current.Protected && updateDTO.Code != nil && *updateDTO.Code != current.Code {
+		return nil, apperrors.NewHttpErrorWithDetails(http.StatusBadRequest, "Код этого приоритета используется системой и не может быть изменён", nil, nil,
+			map[string]interface{}{"code": current.Code})
+	}
+	if updateDTO.Color != nil {
+		color, err := normalizePriorityColor(*updateDTO.Color)
+		if err != nil {
+			return nil, err
+		}
+		updateDTO.Color = &color
+	}
 
 	return s.repo.UpdatePriority(ctx, id, updateDTO)
 }
@@ -131,5 +159,94 @@ func (s *PriorityService) DeletePriority(ctx context.Context, id uint64) error {
 		return apperrors.ErrForbidden
 	}
 
+	current, err := s.repo.FindPriority(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.Protected {
+		return apperrors.NewHttpErrorWithDetails(http.StatusBadRequest, "Этот приоритет используется системой и не может быть удалён", nil, nil,
+			map[string]interface{}{"code": current.Code})
+	}
+
 	return s.repo.DeletePriority(ctx, id)
 }
+
+// normalizePriorityColor приводит цвет к виду #RRGGBB; пустая строка означает «без цвета».
+func normalizePriorityColor(color string) (string, error) {
+	color = strings.ToUpper(strings.TrimSpace(color))
+	if color == "" {
+		return "", nil
+	}
+	if !priorityColorPattern.MatchString(color) {
+		return "", apperrors.NewBadRequestError("Цвет приоритета должен быть в формате #RRGGBB.")
+	}
+	return color, nil
+}
+
+func (s *PriorityService) GetMatrix(ctx context.Context) (*dto.PriorityMatrixDTO, error) {
+	authContext, err := s.buildAuthzContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.PrioritiesView, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return s.loadMatrix(ctx)
+}
+
+// UpdateMatrix заменяет матрицу целиком: пропущенная клетка оставила бы заявки без приоритета.
+func (s *PriorityService) UpdateMatrix(ctx context.Context, updateDTO dto.UpdatePriorityMatrixDTO) (*dto.PriorityMatrixDTO, error) {
+	authContext, err := s.buildAuthzContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.PrioritiesUpdate, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	cells, err := buildPriorityMatrixCells(updateDTO.Cells)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceMatrix(ctx, cells); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Матрица приоритетов обновлена", zap.Uint64("by", authContext.Actor.ID))
+	return s.loadMatrix(ctx)
+}
+
+func (s *PriorityService) loadMatrix(ctx context.Context) (*dto.PriorityMatrixDTO, error) {
+	cells, err := s.repo.FindMatrix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.PriorityMatrixDTO{Levels: entities.PriorityLevels, Cells: make([]dto.PriorityMatrixCellDTO, 0, len(cells))}
+	for _, cell := range cells {
+		result.Cells = append(result.Cells, dto.PriorityMatrixCellDTO{
+			Impact:       cell.Impact,
+			Urgency:      cell.Urgency,
+			PriorityID:   cell.PriorityID,
+			PriorityName: cell.PriorityName,
+		})
+	}
+	return result, nil
+}
+
+// buildPriorityMatrixCells проверяет, что каждое сочетание влияния и срочности задано ровно один раз.
+func buildPriorityMatrixCells(input []dto.UpdatePriorityMatrixCellDTO) ([]entities.PriorityMatrixCell, error) {
+	seen := make(map[[2]string]bool, len(input))
+	cells := make([]entities.PriorityMatrixCell, 0, len(input))
+	for _, c := range input {
+		key := [2]string{c.Impact, c.Urgency}
+		if seen[key] {
+			return nil, apperrors.NewBadRequestError(fmt.Sprintf("Сочетание влияния «%s» и срочности «%s» указано дважды.", c.Impact, c.Urgency))
+		}
+		seen[key] = true
+		cells = append(cells, entities.PriorityMatrixCell{Impact: c.Impact, Urgency: c.Urgency, PriorityID: c.PriorityID})
+	}
+	levels := len(entities.PriorityLevels)
+	if len(cells) != levels*levels {
+		return nil, apperrors.NewBadRequestError(fmt.Sprintf("Матрица должна содержать все %d сочетаний влияния и срочности.", levels*levels))
+	}
+	return cells, nil
+}
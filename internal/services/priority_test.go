@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type priorityRepoStub struct {
+	repositories.PriorityRepositoryInterface
+	priorities map[uint64]dto.PriorityDTO
+	created    *dto.CreatePriorityDTO
+	updated    *dto.UpdatePriorityDTO
+	deleted    []uint64
+}
+
+func (s *priorityRepoStub) FindPriority(_ context.Context, id uint64) (*dto.PriorityDTO, error) {
+	priority, ok := s.priorities[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &priority, nil
+}
+
+func (s *priorityRepoStub) CreatePriority(_ context.Context, payload dto.CreatePriorityDTO) (*dto.PriorityDTO, error) {
+	s.created = &payload
+	return &dto.PriorityDTO{ID: 10, Name: payload.Name, Code: payload.Code, Color: payload.Color}, nil
+}
+
+func (s *priorityRepoStub) UpdatePriority(_ context.Context, id uint64, payload dto.UpdatePriorityDTO) (*dto.PriorityDTO, error) {
+	s.updated = &payload
+	priority := s.priorities[id]
+	return &priority, nil
+}
+
+func (s *priorityRepoStub) DeletePriority(_ context.Context, id uint64) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func newPriorityServiceStubs() (*priorityRepoStub, PriorityServiceInterface, context.Context) {
+	repo := &priorityRepoStub{priorities: map[uint64]dto.PriorityDTO{
+		1: {ID: 1, Code: pkgconstants.PriorityCritical, Protected: true},
+		2: {ID: 2, Code: "URGENT_VIP"},
+	}}
+	service := NewPriorityService(repo, &businessCalendarUserRepoStub{}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{
+		authz.PrioritiesCreate: true, authz.PrioritiesUpdate: true, authz.PrioritiesDelete: true,
+	})
+	return repo, service, ctx
+}
+
+func TestPriorityService_ProtectsSystemCodes(t *testing.T) {
+	repo, service, ctx := newPriorityServiceStubs()
+
+	renamed := "SUPER_CRITICAL"
+	_, err := service.UpdatePriority(ctx, 1, dto.UpdatePriorityDTO{Code: &renamed})
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Errorf("changing a protected code: want 400, got %v", err)
+	}
+	same, name := pkgconstants.PriorityCritical, "Пожар"
+	if _, err := service.UpdatePriority(ctx, 1, dto.UpdatePriorityDTO{Code: &same, Name: &name}); err != nil {
+		t.Errorf("renaming a protected priority without changing its code: %v", err)
+	}
+
+	if err := service.DeletePriority(ctx, 1); !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Errorf("deleting a protected priority: want 400, got %v", err)
+	}
+	if err := service.DeletePriority(ctx, 2); err != nil || len(repo.deleted) != 1 || repo.deleted[0] != 2 {
+		t.Errorf("deleting a custom priority: %v, deleted = %v", err, repo.deleted)
+	}
+}
+
+func TestPriorityService_NormalizesColor(t *testing.T) {
+	repo, service, ctx := newPriorityServiceStubs()
+
+	color := " #c62828 "
+	if _, err := service.CreatePriority(ctx, dto.CreatePriorityDTO{Name: "Срочно VIP", Color: &color}); err != nil {
+		t.Fatalf("CreatePriority: %v", err)
+	}
+	if repo.created.Code != "СРОЧНО_VIP" || repo.created.Color == nil || *repo.created.Color != "#C62828" {
+		t.Errorf("created = %+v, color = %v", repo.created, repo.created.Color)
+	}
+
+	empty := ""
+	if _, err := service.CreatePriority(ctx, dto.CreatePriorityDTO{Name: "Без цвета", Color: &empty}); err != nil || repo.created.Color != nil {
+		t.Errorf("an empty color must be stored as NULL: %v, %v", repo.created.Color, err)
+	}
+	if _, err := service.UpdatePriority(ctx, 2, dto.UpdatePriorityDTO{Color: &empty}); err != nil || repo.updated.Color == nil || *repo.updated.Color != "" {
+		t.Errorf("an empty color must clear the field on update: %v", err)
+	}
+
+	for _, invalid := range []string{"red", "#FFF", "#12345G"} {
+		if _, err := service.CreatePriority(ctx, dto.CreatePriorityDTO{Name: "x", Color: &invalid}); validationErrorMessage(err) == "" {
+			t.Errorf("color %q: want 400, got %v", invalid, err)
+		}
+	}
+}
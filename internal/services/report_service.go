@@ -55,18 +55,17 @@ func (s *reportService) getAndAuthorizeReport(ctx context.Context, filter entiti
 	}
 
 	// 2. Проверяем базовое право на просмотр отчета
-	authContext := authz.Context{Actor: actor, Permissions: permissionsMap}
+	authContext := authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}
 	if !authz.CanDo(authz.ReportView, authContext) {
 		s.logger.Warn("Попытка доступа к отчету без права report:view", zap.Uint64("userID", userID))
 		return nil, 0, apperrors.ErrForbidden
 	}
 
-	// 3. Обогащаем фильтр информацией об акторе и его правах
-	filter.Actor = actor
-	filter.PermissionsMap = permissionsMap
+	// 3. Ограничиваем отчёт заявками, которые пользователь видит
+	securityCondition := authz.NewScopeQueryBuilder("o").WithParticipation().Build(authContext, authz.ReportView, time.Now())
 
-	// 4. Вызываем репозиторий с полным контекстом для фильтрации
-	return s.reportRepo.GetReport(ctx, filter)
+	// 4. Вызываем репозиторий с условием видимости
+	return s.reportRepo.GetReport(ctx, filter, securityCondition)
 }
 
 func (s *reportService) GetReportForExcel(ctx context.Context, filter entities.ReportFilter) ([]entities.ReportItem, uint64, error) {
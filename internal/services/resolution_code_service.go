@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+// resolutionStatsDefaultDays — период отчёта по кодам решения, если даты не заданы.
+const resolutionStatsDefaultDays = 30
+
+// ResolutionCodeServiceInterface ведёт справочник кодов решения и считает, с какими кодами
+// выполнялись заявки.
+type ResolutionCodeServiceInterface interface {
+	ListResolutionCodes(ctx context.Context, includeInactive bool) ([]dto.ResolutionCodeDTO, error)
+	CreateResolutionCode(ctx context.Context, payload dto.CreateResolutionCodeDTO) (*dto.ResolutionCodeDTO, error)
+	UpdateResolutionCode(ctx context.Context, id uint64, payload dto.UpdateResolutionCodeDTO) (*dto.ResolutionCodeDTO, error)
+	DeleteResolutionCode(ctx context.Context, id uint64) error
+	// GetResolutionStats — даты в формате 2006-01-02 по часовому поясу пользователя, to включительно.
+	GetResolutionStats(ctx context.Context, from, to string) (*dto.ResolutionCodeStatsDTO, error)
+}
+
+type ResolutionCodeService struct {
+	repo     repositories.ResolutionCodeRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewResolutionCodeService(
+	repo repositories.ResolutionCodeRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) ResolutionCodeServiceInterface {
+	return &ResolutionCodeService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+func (s *ResolutionCodeService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func (s *ResolutionCodeService) ListResolutionCodes(ctx context.Context, includeInactive bool) ([]dto.ResolutionCodeDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.ResolutionCodesView); err != nil {
+		return nil, err
+	}
+	codes, err := s.repo.FindAll(ctx, !includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.ResolutionCodeDTO, 0, len(codes))
+	for _, code := range codes {
+		result = append(result, toResolutionCodeDTO(code))
+	}
+	return result, nil
+}
+
+func (s *ResolutionCodeService) CreateResolutionCode(ctx context.Context, payload dto.CreateResolutionCodeDTO) (*dto.ResolutionCodeDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.ResolutionCodesManage)
+	if err != nil {
+		return nil, err
+	}
+	code := &entities.ResolutionCode{
+		Code:      strings.ToUpper(strings.TrimSpace(payload.Code)),
+		Name:      strings.TrimSpace(payload.Name),
+		SortOrder: payload.SortOrder,
+		IsActive:  payload.IsActive == nil || *payload.IsActive,
+	}
+	if code.Code == "" || code.Name == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Укажите код и название кода решения", nil, nil)
+	}
+	if err := s.repo.Create(ctx, code); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Добавлен код решения", zap.String("code", code.Code), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toResolutionCodeDTO(*code)
+	return &result, nil
+}
+
+func (s *ResolutionCodeService) UpdateResolutionCode(ctx context.Context, id uint64, payload dto.UpdateResolutionCodeDTO) (*dto.ResolutionCodeDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.ResolutionCodesManage)
+	if err != nil {
+		return nil, err
+	}
+	code, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Code != nil {
+		code.Code = strings.ToUpper(strings.TrimSpace(*payload.Code))
+	}
+	if payload.Name != nil {
+		code.Name = strings.TrimSpace(*payload.Name)
+	}
+	if payload.SortOrder != nil {
+		code.SortOrder = *payload.SortOrder
+	}
+	if payload.IsActive != nil {
+		code.IsActive = *payload.IsActive
+	}
+	if code.Code == "" || code.Name == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Укажите код и название кода решения", nil, nil)
+	}
+	if err := s.repo.Update(ctx, code); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Изменён код решения", zap.Uint64("resolutionCodeID", id), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toResolutionCodeDTO(*code)
+	return &result, nil
+}
+
+func (s *ResolutionCodeService) DeleteResolutionCode(ctx context.Context, id uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.ResolutionCodesManage)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалён код решения", zap.Uint64("resolutionCodeID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *ResolutionCodeService) GetResolutionStats(ctx context.Context, from, to string) (*dto.ResolutionCodeStatsDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.ReportView); err != nil {
+		return nil, err
+	}
+
+	start, end, err := resolutionStatsPeriod(from, to, time.Now(), utils.LocationFromCtx(ctx))
+	if err != nil {
+		return nil, err
+	}
+	stats, withoutCode, err := s.repo.Stats(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ResolutionCodeStatsDTO{
+		From:        start.Format(time.DateOnly),
+		To:          end.AddDate(0, 0, -1).Format(time.DateOnly),
+		WithoutCode: withoutCode,
+		Total:       withoutCode,
+		Codes:       make([]dto.ResolutionCodeStatDTO, 0, len(stats)),
+	}
+	// Доля считается от всех выполненных заявок, включая закрытые без кода.
+	for _, stat := range stats {
+		result.Total += stat.Orders
+	}
+	for _, stat := range stats {
+		result.Codes = append(result.Codes, dto.ResolutionCodeStatDTO{
+			ID:     stat.ID,
+			Code:   stat.Code,
+			Name:   stat.Name,
+			Orders: stat.Orders,
+			Share:  float64(stat.Orders) / float64(result.Total),
+		})
+	}
+	return result, nil
+}
+
+// resolutionStatsPeriod переводит даты отчёта в полуинтервал [start, end) в часовом поясе loc.
+// Без дат берутся последние resolutionStatsDefaultDays дней, включая сегодняшний.
+func resolutionStatsPeriod(from, to string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	today := now.In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if to = strings.TrimSpace(to); to != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperrors.NewBadRequestError("Неверный формат даты to, ожидается ГГГГ-ММ-ДД")
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+
+	start := end.AddDate(0, 0, -resolutionStatsDefaultDays)
+	if from = strings.TrimSpace(from); from != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperrors.NewBadRequestError("Неверный формат даты from, ожидается ГГГГ-ММ-ДД")
+		}
+		start = parsed
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, apperrors.NewBadRequestError("Дата from должна быть не позже to")
+	}
+	return start, end, nil
+}
+
+func toResolutionCodeDTO(code entities.ResolutionCode) dto.ResolutionCodeDTO {
+	return dto.ResolutionCodeDTO{
+		ID:        code.ID,
+		Code:      code.Code,
+		Name:      code.Name,
+		SortOrder: code.SortOrder,
+		IsActive:  code.IsActive,
+		CreatedAt: code.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: code.UpdatedAt.Format(time.RFC3339),
+	}
+}
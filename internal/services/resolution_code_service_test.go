@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+)
+
+type resolutionCodeRepoStub struct {
+	repositories.ResolutionCodeRepositoryInterface
+	codes       map[uint64]entities.ResolutionCode
+	stats       []entities.ResolutionCodeStat
+	withoutCode uint64
+	from, to    time.Time
+}
+
+func (s *resolutionCodeRepoStub) FindByID(_ context.Context, id uint64) (*entities.ResolutionCode, error) {
+	code, ok := s.codes[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &code, nil
+}
+
+func (s *resolutionCodeRepoStub) Stats(_ context.Context, from, to time.Time) ([]entities.ResolutionCodeStat, uint64, error) {
+	s.from, s.to = from, to
+	return s.stats, s.withoutCode, nil
+}
+
+func TestResolutionStatsPeriod(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	now := time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC) // в UTC+5 уже 17 октября
+
+	start, end, err := resolutionStatsPeriod("", "", now, loc)
+	if err != nil {
+		t.Fatalf("default period: %v", err)
+	}
+	if want := time.Date(2026, 10, 18, 0, 0, 0, 0, loc); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+	if want := time.Date(2026, 9, 18, 0, 0, 0, 0, loc); !start.Equal(want) {
+		t.Errorf("start = %v, want %v", start, want)
+	}
+
+	start, end, err = resolutionStatsPeriod("2026-10-01", "2026-10-01", now, loc)
+	if err != nil {
+		t.Fatalf("single day: %v", err)
+	}
+	if end.Sub(start) != 24*time.Hour {
+		t.Errorf("single day period = %v", end.Sub(start))
+	}
+
+	if _, _, err := resolutionStatsPeriod("01.10.2026", "", now, loc); err == nil {
+		t.Error("expected an error for a non-ISO date")
+	}
+	if _, _, err := resolutionStatsPeriod("2026-10-05", "2026-10-01", now, loc); err == nil {
+		t.Error("expected an error when from is after to")
+	}
+}
+
+func TestGetResolutionStatsShares(t *testing.T) {
+	repo := &resolutionCodeRepoStub{
+		stats: []entities.ResolutionCodeStat{
+			{ID: 1, Code: "FIXED", Name: "Исправлено", Orders: 6},
+			{ID: 3, Code: "USER_ERROR", Name: "Ошибка пользователя", Orders: 2},
+		},
+		withoutCode: 2,
+	}
+	service := NewResolutionCodeService(repo, &teamUserRepoStub{}, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.ReportView: true})
+
+	result, err := service.GetResolutionStats(ctx, "2026-10-01", "2026-10-31")
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if result.Total != 10 || result.WithoutCode != 2 || len(result.Codes) != 2 {
+		t.Fatalf("unexpected totals %+v", result)
+	}
+	if math.Abs(result.Codes[0].Share-0.6) > 1e-9 || math.Abs(result.Codes[1].Share-0.2) > 1e-9 {
+		t.Errorf("unexpected shares %+v", result.Codes)
+	}
+	if result.To != "2026-10-31" || repo.to.Format(time.DateOnly) != "2026-11-01" {
+		t.Errorf("to = %s, repo end = %v", result.To, repo.to)
+	}
+
+	noReports := context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.ResolutionCodesView: true})
+	if _, err := service.GetResolutionStats(noReports, "", ""); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("expected forbidden without report:view, got %v", err)
+	}
+}
+
+func TestValidateResolutionCode(t *testing.T) {
+	service := &OrderService{resolutionCodeRepo: &resolutionCodeRepoStub{codes: map[uint64]entities.ResolutionCode{
+		1: {ID: 1, Code: "FIXED", IsActive: true},
+		2: {ID: 2, Code: "LEGACY", IsActive: false},
+	}}}
+	id := func(v uint64) *uint64 { return &v }
+
+	cases := []struct {
+		name    string
+		code    *uint64
+		fields  map[string]interface{}
+		wantErr bool
+	}{
+		{"not in patch", nil, map[string]interface{}{"status_id": float64(5)}, false},
+		{"cleared", nil, map[string]interface{}{"resolution_code_id": nil}, false},
+		{"active code", id(1), map[string]interface{}{"resolution_code_id": float64(1)}, false},
+		{"disabled code", id(2), map[string]interface{}{"resolution_code_id": float64(2)}, true},
+		{"unknown code", id(9), map[string]interface{}{"resolution_code_id": float64(9)}, true},
+	}
+	for _, tc := range cases {
+		err := service.validateResolutionCode(context.Background(), dto.UpdateOrderDTO{ResolutionCodeID: tc.code}, tc.fields)
+		if !tc.wantErr {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("%s: want 400, got %v", tc.name, err)
+		}
+	}
+}
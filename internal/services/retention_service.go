@@ -0,0 +1,554 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/filestorage"
+	"request-system/pkg/jobs"
+	"request-system/pkg/types"
+)
+
+// RetentionJobType — задание очереди pkg/jobs, выполняющее активные правила хранения.
+const RetentionJobType = "retention.run"
+
+const (
+	// RetentionJobTimeout — сколько даётся одному запуску; столько же другой запуск считается идущим.
+	RetentionJobTimeout = 2 * time.Hour
+	// retentionReportItemsLimit — сколько заявок правила перечисляется в отчёте.
+	retentionReportItemsLimit = 1000
+)
+
+// retentionJobQueue — часть *jobs.Queue, нужная для ручного запуска.
+type retentionJobQueue interface {
+	Enqueue(ctx context.Context, jobType string, payload any, opts ...jobs.EnqueueOption) (*jobs.Job, error)
+}
+
+type retentionJobPayload struct {
+	TriggeredBy *uint64 `json:"triggered_by,omitempty"`
+}
+
+type RetentionServiceInterface interface {
+	GetRules(ctx context.Context) ([]dto.RetentionRuleDTO, error)
+	CreateRule(ctx context.Context, d dto.CreateRetentionRuleDTO) (*dto.RetentionRuleDTO, error)
+	UpdateRule(ctx context.Context, id uint64, d dto.UpdateRetentionRuleDTO) (*dto.RetentionRuleDTO, error)
+	DeleteRule(ctx context.Context, id uint64) error
+
+	GetHolds(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.RetentionHoldDTO], error)
+	SetHold(ctx context.Context, d dto.CreateRetentionHoldDTO) (*dto.RetentionHoldDTO, error)
+	RemoveHold(ctx context.Context, orderID uint64) error
+
+	GetRuns(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.RetentionRunDTO], error)
+	GetRun(ctx context.Context, id uint64) (*dto.RetentionRunDTO, error)
+	// Preview выполняет активные правила без изменений и сразу возвращает отчёт.
+	Preview(ctx context.Context) (*dto.RetentionRunDTO, error)
+	// Trigger ставит настоящий запуск в очередь фоновых заданий.
+	Trigger(ctx context.Context) (*dto.BackgroundJobDTO, error)
+
+	// HandleJob — обработчик задания RetentionJobType.
+	HandleJob(ctx context.Context, job jobs.Job) error
+}
+
+// RetentionService применяет правила хранения к закрытым заявкам: удаляет вложения и сжимает
+// историю. Заявки из списка удержания не трогаются. Цепочки хэшей истории затронутых заявок
+// пересчитываются, прежние хэши остаются в отчёте запуска.
+type RetentionService struct {
+	repo        repositories.RetentionRepositoryInterface
+	historyRepo repositories.OrderHistoryRepositoryInterface
+	txManager   repositories.TxManagerInterface
+	userRepo    repositories.UserRepositoryInterface
+	fileStorage filestorage.FileStorageInterface
+	queue       retentionJobQueue
+	batchSize   int
+	logger      *zap.Logger
+}
+
+func NewRetentionService(
+	repo repositories.RetentionRepositoryInterface,
+	historyRepo repositories.OrderHistoryRepositoryInterface,
+	txManager repositories.TxManagerInterface,
+	userRepo repositories.UserRepositoryInterface,
+	fileStorage filestorage.FileStorageInterface,
+	queue retentionJobQueue,
+	batchSize int,
+	logger *zap.Logger,
+) RetentionServiceInterface {
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	return &RetentionService{
+		repo:        repo,
+		historyRepo: historyRepo,
+		txManager:   txManager,
+		userRepo:    userRepo,
+		fileStorage: fileStorage,
+		queue:       queue,
+		batchSize:   batchSize,
+		logger:      logger,
+	}
+}
+
+func (s *RetentionService) checkManage(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.RetentionManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func toRetentionRuleDTO(e *entities.RetentionRule) dto.RetentionRuleDTO {
+	return dto.RetentionRuleDTO{
+		ID:                  e.ID,
+		Name:                e.Name,
+		Action:              e.Action,
+		ClosedOlderThanDays: e.ClosedOlderThanDays,
+		IsActive:            e.IsActive,
+		CreatedBy:           e.CreatedBy,
+		CreatedAt:           e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           e.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toRetentionHoldDTO(e *entities.RetentionHold) dto.RetentionHoldDTO {
+	return dto.RetentionHoldDTO{
+		OrderID:   e.OrderID,
+		Reason:    e.Reason,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toRetentionRunDTO(e *entities.RetentionRun) (dto.RetentionRunDTO, error) {
+	result := dto.RetentionRunDTO{
+		ID:          e.ID,
+		DryRun:      e.DryRun,
+		Status:      e.Status,
+		TriggeredBy: e.TriggeredBy,
+		Error:       e.Error,
+		StartedAt:   e.StartedAt.Format(time.RFC3339),
+	}
+	if len(e.Report) > 0 {
+		if err := json.Unmarshal(e.Report, &result.Report); err != nil {
+			return result, err
+		}
+	}
+	if e.FinishedAt != nil {
+		finishedAt := e.FinishedAt.Format(time.RFC3339)
+		result.FinishedAt = &finishedAt
+	}
+	return result, nil
+}
+
+func (s *RetentionService) GetRules(ctx context.Context) ([]dto.RetentionRuleDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.FindRules(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.RetentionRuleDTO, 0, len(rules))
+	for i := range rules {
+		result = append(result, toRetentionRuleDTO(&rules[i]))
+	}
+	return result, nil
+}
+
+func (s *RetentionService) CreateRule(ctx context.Context, d dto.CreateRetentionRuleDTO) (*dto.RetentionRuleDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rule := &entities.RetentionRule{
+		Name:                d.Name,
+		Action:              d.Action,
+		ClosedOlderThanDays: d.ClosedOlderThanDays,
+		IsActive:            d.IsActive == nil || *d.IsActive,
+		CreatedBy:           &authContext.Actor.ID,
+	}
+	if err := s.repo.CreateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Создано правило хранения", zap.Uint64("ruleID", rule.ID), zap.String("action", rule.Action),
+		zap.Int("days", rule.ClosedOlderThanDays), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toRetentionRuleDTO(rule)
+	return &result, nil
+}
+
+func (s *RetentionService) UpdateRule(ctx context.Context, id uint64, d dto.UpdateRetentionRuleDTO) (*dto.RetentionRuleDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := s.repo.FindRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Name != nil {
+		rule.Name = *d.Name
+	}
+	if d.Action != nil {
+		rule.Action = *d.Action
+	}
+	if d.ClosedOlderThanDays != nil {
+		rule.ClosedOlderThanDays = *d.ClosedOlderThanDays
+	}
+	if d.IsActive != nil {
+		rule.IsActive = *d.IsActive
+	}
+	if err := s.repo.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Обновлено правило хранения", zap.Uint64("ruleID", id), zap.Bool("active", rule.IsActive), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toRetentionRuleDTO(rule)
+	return &result, nil
+}
+
+func (s *RetentionService) DeleteRule(ctx context.Context, id uint64) error {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалено правило хранения", zap.Uint64("ruleID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *RetentionService) GetHolds(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.RetentionHoldDTO], error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	holds, total, err := s.repo.FindHolds(ctx, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dto.RetentionHoldDTO, 0, len(holds))
+	for i := range holds {
+		list = append(list, toRetentionHoldDTO(&holds[i]))
+	}
+	return retentionPage(list, total, filter), nil
+}
+
+func (s *RetentionService) SetHold(ctx context.Context, d dto.CreateRetentionHoldDTO) (*dto.RetentionHoldDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hold := &entities.RetentionHold{OrderID: d.OrderID, Reason: d.Reason, CreatedBy: &authContext.Actor.ID}
+	if err := s.repo.SaveHold(ctx, hold); err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.NewHttpError(http.StatusNotFound, "Заявка не найдена", err, nil)
+		}
+		return nil, err
+	}
+	s.logger.Info("Заявка поставлена на удержание", zap.Uint64("orderID", d.OrderID), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toRetentionHoldDTO(hold)
+	return &result, nil
+}
+
+func (s *RetentionService) RemoveHold(ctx context.Context, orderID uint64) error {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteHold(ctx, orderID); err != nil {
+		return err
+	}
+	s.logger.Info("Удержание заявки снято", zap.Uint64("orderID", orderID), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *RetentionService) GetRuns(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.RetentionRunDTO], error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	runs, total, err := s.repo.FindRuns(ctx, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dto.RetentionRunDTO, 0, len(runs))
+	for i := range runs {
+		run, err := toRetentionRunDTO(&runs[i])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, run)
+	}
+	return retentionPage(list, total, filter), nil
+}
+
+func (s *RetentionService) GetRun(ctx context.Context, id uint64) (*dto.RetentionRunDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	run, err := s.repo.FindRunByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := toRetentionRunDTO(run)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *RetentionService) Preview(ctx context.Context) (*dto.RetentionRunDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, true, &authContext.Actor.ID)
+}
+
+func (s *RetentionService) Trigger(ctx context.Context) (*dto.BackgroundJobDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.queue.Enqueue(ctx, RetentionJobType, retentionJobPayload{TriggeredBy: &authContext.Actor.ID})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Запуск правил хранения поставлен в очередь", zap.String("jobID", job.ID), zap.Uint64("by", authContext.Actor.ID))
+	result := toBackgroundJobDTO(job)
+	return &result, nil
+}
+
+func (s *RetentionService) HandleJob(ctx context.Context, job jobs.Job) error {
+	var payload retentionJobPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	run, err := s.run(ctx, false, payload.TriggeredBy)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		s.logger.Info("Правила хранения уже выполняются, запуск пропущен", zap.String("jobID", job.ID))
+	}
+	return nil
+}
+
+// run выполняет активные правила и сохраняет отчёт. Возвращает nil без ошибки, если настоящий
+// запуск уже идёт в другой реплике.
+func (s *RetentionService) run(ctx context.Context, dryRun bool, triggeredBy *uint64) (*dto.RetentionRunDTO, error) {
+	run, err := s.repo.CreateRun(ctx, dryRun, triggeredBy, RetentionJobTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, nil
+	}
+
+	reports := make([]dto.RetentionRuleReportDTO, 0)
+	rules, runErr := s.repo.FindRules(ctx, true)
+	now := time.Now()
+	for i := 0; runErr == nil && i < len(rules); i++ {
+		var report *dto.RetentionRuleReportDTO
+		report, runErr = s.applyRule(ctx, rules[i], now, dryRun)
+		if report != nil {
+			reports = append(reports, *report)
+		}
+	}
+
+	run.Status = entities.RetentionRunCompleted
+	var errMsg *string
+	if runErr != nil {
+		run.Status = entities.RetentionRunFailed
+		msg := runErr.Error()
+		errMsg = &msg
+	}
+	run.Report, _ = json.Marshal(reports)
+	run.Error = errMsg
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	// Отчёт сохраняется и тогда, когда контекст запуска истёк: удалённое должно попасть в журнал.
+	if err := s.repo.FinishRun(context.WithoutCancel(ctx), run.ID, run.Status, run.Report, errMsg); err != nil {
+		s.logger.Error("Не удалось сохранить отчёт правил хранения", zap.Uint64("runID", run.ID), zap.Error(err))
+	}
+
+	fields := []zap.Field{zap.Uint64("runID", run.ID), zap.Bool("dryRun", dryRun), zap.Int("rules", len(reports))}
+	if runErr != nil {
+		s.logger.Error("Запуск правил хранения прерван", append(fields, zap.Error(runErr))...)
+		if !dryRun {
+			return nil, runErr
+		}
+	} else {
+		s.logger.Info("Правила хранения выполнены", fields...)
+	}
+
+	result, err := toRetentionRunDTO(run)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// applyRule обходит подходящие заявки страницами по id. Ошибка одной заявки попадает в отчёт
+// и не останавливает правило; ошибка выборки прерывает запуск.
+func (s *RetentionService) applyRule(ctx context.Context, rule entities.RetentionRule, now time.Time, dryRun bool) (*dto.RetentionRuleReportDTO, error) {
+	cutoff := now.AddDate(0, 0, -rule.ClosedOlderThanDays)
+	report := &dto.RetentionRuleReportDTO{
+		RuleID:       rule.ID,
+		Name:         rule.Name,
+		Action:       rule.Action,
+		ClosedBefore: cutoff.Format(time.RFC3339),
+		Items:        []dto.RetentionOrderResultDTO{},
+	}
+
+	held, err := s.repo.CountHeldOrders(ctx, rule.Action, cutoff)
+	if err != nil {
+		return report, err
+	}
+	report.HeldOrders = held
+
+	var afterID uint64
+	for {
+		orderIDs, err := s.repo.FindExpiredOrders(ctx, rule.Action, cutoff, afterID, s.batchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(orderIDs) == 0 {
+			return report, nil
+		}
+		afterID = orderIDs[len(orderIDs)-1]
+
+		if dryRun {
+			items, err := s.repo.PreviewOrders(ctx, rule.Action, orderIDs)
+			if err != nil {
+				return report, err
+			}
+			for _, item := range items {
+				addRetentionItem(report, item)
+			}
+			continue
+		}
+
+		for _, orderID := range orderIDs {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			item, err := s.applyToOrder(ctx, rule.Action, orderID)
+			if err != nil {
+				report.Failed++
+				report.Errors = append(report.Errors, fmt.Sprintf("заявка %d: %v", orderID, err))
+				s.logger.Error("Правило хранения не применено к заявке", zap.Uint64("ruleID", rule.ID), zap.Uint64("orderID", orderID), zap.Error(err))
+				continue
+			}
+			if !item.ChainValid {
+				report.BrokenChainOrders = append(report.BrokenChainOrders, orderID)
+			}
+			addRetentionItem(report, *item)
+		}
+	}
+}
+
+func addRetentionItem(report *dto.RetentionRuleReportDTO, item dto.RetentionOrderResultDTO) {
+	report.Orders++
+	report.Attachments += item.Attachments
+	report.Bytes += item.Bytes
+	report.HistoryRows += item.HistoryRows
+	if len(report.Items) < retentionReportItemsLimit {
+		report.Items = append(report.Items, item)
+	} else {
+		report.Truncated = true
+	}
+}
+
+// applyToOrder применяет действие к одной заявке в своей транзакции. Файлы вложений удаляются
+// после фиксации: файл без записи безвреден, запись без файла — нет.
+func (s *RetentionService) applyToOrder(ctx context.Context, action string, orderID uint64) (*dto.RetentionOrderResultDTO, error) {
+	item := &dto.RetentionOrderResultDTO{OrderID: orderID}
+	var attachments []entities.Attachment
+	err := s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		// Цепочка проверяется до удаления: после него подмену в истории уже не отличить от работы правила.
+		check, err := s.historyRepo.VerifyChainInTx(ctx, tx, orderID)
+		if err != nil {
+			return fmt.Errorf("не удалось проверить цепочку истории: %w", err)
+		}
+		item.ChainValid = check.Valid
+		if !check.Valid {
+			item.ChainBroken = fmt.Sprintf("запись %d: %s", check.FirstBrokenID, check.Reason)
+		}
+
+		switch action {
+		case entities.RetentionPurgeAttachments:
+			if attachments, err = s.repo.PurgeAttachmentsInTx(ctx, tx, orderID); err != nil {
+				return err
+			}
+			item.Attachments = len(attachments)
+			for _, attachment := range attachments {
+				item.Bytes += attachment.FileSize
+			}
+		case entities.RetentionCompactHistory:
+			rows, err := s.repo.CompactHistoryInTx(ctx, tx, orderID)
+			if err != nil {
+				return err
+			}
+			item.HistoryRows = rows
+		default:
+			return fmt.Errorf("неизвестное действие правила хранения: %s", action)
+		}
+
+		if !check.Valid {
+			// Срок хранения соблюдается, но нарушенную цепочку не подписываем заново — она остаётся в отчёте.
+			s.logger.Warn("Цепочка истории заявки нарушена, пересчёт после правила хранения пропущен",
+				zap.Uint64("orderID", orderID),
+				zap.Uint64("firstBrokenID", check.FirstBrokenID),
+				zap.String("reason", check.Reason))
+			return nil
+		}
+		prevHead, err := s.historyRepo.ResealChainInTx(ctx, tx, orderID, check)
+		if err != nil {
+			return fmt.Errorf("не удалось пересчитать цепочку истории: %w", err)
+		}
+		item.PrevHistoryHash = prevHead
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range attachments {
+		fileURL := "/uploads/" + attachment.FilePath
+		if err := s.fileStorage.Delete(fileURL); err != nil {
+			s.logger.Warn("Не удалось удалить файл вложения по правилу хранения",
+				zap.Uint64("attachmentID", attachment.ID), zap.String("path", fileURL), zap.Error(err))
+		}
+	}
+	return item, nil
+}
+
+func retentionPage[T any](list []T, total uint64, filter types.Filter) *dto.PaginatedResponse[T] {
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+	return &dto.PaginatedResponse[T]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/jobs"
+)
+
+type retentionRepoStub struct {
+	repositories.RetentionRepositoryInterface
+	rules       []entities.RetentionRule
+	expired     map[string][]uint64
+	held        int64
+	attachments map[uint64][]entities.Attachment
+	failOrder   uint64
+	purged      []uint64
+	compacted   []uint64
+	finished    *entities.RetentionRun
+	busy        bool
+}
+
+func (s *retentionRepoStub) FindRules(context.Context, bool) ([]entities.RetentionRule, error) {
+	return s.rules, nil
+}
+
+func (s *retentionRepoStub) CreateRun(_ context.Context, dryRun bool, triggeredBy *uint64, _ time.Duration) (*entities.RetentionRun, error) {
+	if s.busy && !dryRun {
+		return nil, nil
+	}
+	return &entities.RetentionRun{ID: 1, DryRun: dryRun, Status: entities.RetentionRunRunning, TriggeredBy: triggeredBy, StartedAt: time.Now()}, nil
+}
+
+func (s *retentionRepoStub) FinishRun(_ context.Context, id uint64, status string, report json.RawMessage, errMsg *string) error {
+	s.finished = &entities.RetentionRun{ID: id, Status: status, Report: report, Error: errMsg}
+	return nil
+}
+
+func (s *retentionRepoStub) CountHeldOrders(context.Context, string, time.Time) (int64, error) {
+	return s.held, nil
+}
+
+func (s *retentionRepoStub) FindExpiredOrders(_ context.Context, action string, _ time.Time, afterID uint64, limit int) ([]uint64, error) {
+	result := make([]uint64, 0, limit)
+	for _, id := range s.expired[action] {
+		if id > afterID && len(result) < limit {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+func (s *retentionRepoStub) PreviewOrders(_ context.Context, action string, orderIDs []uint64) ([]dto.RetentionOrderResultDTO, error) {
+	result := make([]dto.RetentionOrderResultDTO, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		result = append(result, dto.RetentionOrderResultDTO{OrderID: id, Attachments: len(s.attachments[id]), Bytes: 100})
+	}
+	return result, nil
+}
+
+func (s *retentionRepoStub) PurgeAttachmentsInTx(_ context.Context, _ pgx.Tx, orderID uint64) ([]entities.Attachment, error) {
+	if orderID == s.failOrder {
+		return nil, errors.New("нет доступа к таблице")
+	}
+	s.purged = append(s.purged, orderID)
+	return s.attachments[orderID], nil
+}
+
+func (s *retentionRepoStub) CompactHistoryInTx(_ context.Context, _ pgx.Tx, orderID uint64) (int64, error) {
+	s.compacted = append(s.compacted, orderID)
+	return 7, nil
+}
+
+type retentionQueueStub struct {
+	payload any
+}
+
+func (q *retentionQueueStub) Enqueue(_ context.Context, jobType string, payload any, _ ...jobs.EnqueueOption) (*jobs.Job, error) {
+	q.payload = payload
+	return &jobs.Job{ID: "job-1", Type: jobType, State: jobs.StateScheduled}, nil
+}
+
+func newRetentionServiceForTest(repo *retentionRepoStub, history *orderHistoryRepoStub, files *avatarFileStorageStub, queue *retentionQueueStub) RetentionServiceInterface {
+	return NewRetentionService(repo, history, avatarTxManagerStub{}, &replayUserRepoStub{}, files, queue, 2, zap.NewNop())
+}
+
+func retentionCtx() context.Context {
+	return jobsAdminCtx(map[string]bool{authz.RetentionManage: true})
+}
+
+func TestRetention_RequiresPermissionAndQueuesRun(t *testing.T) {
+	queue := &retentionQueueStub{}
+	service := newRetentionServiceForTest(&retentionRepoStub{}, &orderHistoryRepoStub{}, &avatarFileStorageStub{}, queue)
+
+	if _, err := service.Trigger(jobsAdminCtx(map[string]bool{})); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.RetentionManage, err)
+	}
+	job, err := service.Trigger(retentionCtx())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, ok := queue.payload.(retentionJobPayload)
+	if job.Type != RetentionJobType || !ok || payload.TriggeredBy == nil || *payload.TriggeredBy != 1 {
+		t.Fatalf("unexpected job %+v with payload %+v", job, queue.payload)
+	}
+}
+
+func TestRetention_PreviewChangesNothing(t *testing.T) {
+	repo := &retentionRepoStub{
+		rules:       []entities.RetentionRule{{ID: 1, Name: "Вложения", Action: entities.RetentionPurgeAttachments, ClosedOlderThanDays: 1095}},
+		expired:     map[string][]uint64{entities.RetentionPurgeAttachments: {3, 5, 8}},
+		held:        2,
+		attachments: map[uint64][]entities.Attachment{3: {{ID: 1}}, 5: {{ID: 2}, {ID: 3}}, 8: {{ID: 4}}},
+	}
+	history := &orderHistoryRepoStub{}
+	service := newRetentionServiceForTest(repo, history, &avatarFileStorageStub{}, &retentionQueueStub{})
+
+	run, err := service.Preview(retentionCtx())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.purged) != 0 || len(history.resealed) != 0 {
+		t.Fatal("preview must not change anything")
+	}
+	if !run.DryRun || run.Status != entities.RetentionRunCompleted || len(run.Report) != 1 {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	report := run.Report[0]
+	// Три заявки прошли двумя страницами по две
+	if report.Orders != 3 || report.Attachments != 4 || report.Bytes != 300 || report.HeldOrders != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if repo.finished == nil || repo.finished.Status != entities.RetentionRunCompleted {
+		t.Fatal("preview report must be saved")
+	}
+}
+
+func TestRetention_JobPurgesAndCompactsAndReportsFailures(t *testing.T) {
+	repo := &retentionRepoStub{
+		rules: []entities.RetentionRule{
+			{ID: 1, Action: entities.RetentionPurgeAttachments, ClosedOlderThanDays: 1095},
+			{ID: 2, Action: entities.RetentionCompactHistory, ClosedOlderThanDays: 1095},
+		},
+		expired: map[string][]uint64{
+			entities.RetentionPurgeAttachments: {3, 4},
+			entities.RetentionCompactHistory:   {3},
+		},
+		attachments: map[uint64][]entities.Attachment{3: {{ID: 1, FilePath: "orders/3/act.pdf", FileSize: 1024}}},
+		failOrder:   4,
+	}
+	history := &orderHistoryRepoStub{head: sql.NullString{String: "old-head", Valid: true}}
+	files := &avatarFileStorageStub{}
+	service := newRetentionServiceForTest(repo, history, files, &retentionQueueStub{})
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-1", Type: RetentionJobType}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.purged) != 1 || len(repo.compacted) != 1 || len(history.resealed) != 2 {
+		t.Fatalf("unexpected changes: purged=%v compacted=%v resealed=%v", repo.purged, repo.compacted, history.resealed)
+	}
+	if len(files.deleted) != 1 || files.deleted[0] != "/uploads/orders/3/act.pdf" {
+		t.Fatalf("expected the attachment file to be deleted, got %v", files.deleted)
+	}
+
+	var report []dto.RetentionRuleReportDTO
+	if err := json.Unmarshal(repo.finished.Report, &report); err != nil {
+		t.Fatal(err)
+	}
+	purge, compact := report[0], report[1]
+	if purge.Orders != 1 || purge.Bytes != 1024 || purge.Failed != 1 || len(purge.Errors) != 1 {
+		t.Fatalf("unexpected purge report: %+v", purge)
+	}
+	if purge.Items[0].PrevHistoryHash != "old-head" || compact.HistoryRows != 7 {
+		t.Fatalf("unexpected report items: %+v / %+v", purge.Items, compact)
+	}
+}
+
+func TestRetention_JobSkipsWhileAnotherRunIsActive(t *testing.T) {
+	repo := &retentionRepoStub{busy: true, rules: []entities.RetentionRule{{ID: 1, Action: entities.RetentionPurgeAttachments, ClosedOlderThanDays: 1}}}
+	service := newRetentionServiceForTest(repo, &orderHistoryRepoStub{}, &avatarFileStorageStub{}, &retentionQueueStub{})
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.finished != nil {
+		t.Fatal("a skipped run must not write a report")
+	}
+}
+
+func TestRetention_JobReportsBrokenChainAndDoesNotResealIt(t *testing.T) {
+	repo := &retentionRepoStub{
+		rules:       []entities.RetentionRule{{ID: 1, Action: entities.RetentionCompactHistory, ClosedOlderThanDays: 1095}},
+		expired:     map[string][]uint64{entities.RetentionCompactHistory: {3, 5}},
+		attachments: map[uint64][]entities.Attachment{},
+	}
+	history := &orderHistoryRepoStub{head: sql.NullString{String: "old-head", Valid: true}, broken: map[uint64]bool{5: true}}
+	service := newRetentionServiceForTest(repo, history, &avatarFileStorageStub{}, &retentionQueueStub{})
+
+	if err := service.HandleJob(context.Background(), jobs.Job{ID: "job-3", Type: RetentionJobType}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.compacted) != 2 || len(history.resealed) != 1 || history.resealed[0] != 3 {
+		t.Fatalf("expected both orders compacted and only the intact one resealed: compacted=%v resealed=%v", repo.compacted, history.resealed)
+	}
+
+	var report []dto.RetentionRuleReportDTO
+	if err := json.Unmarshal(repo.finished.Report, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report[0].BrokenChainOrders) != 1 || report[0].BrokenChainOrders[0] != 5 {
+		t.Fatalf("expected order 5 reported as broken, got %+v", report[0])
+	}
+	if !report[0].Items[0].ChainValid || report[0].Items[1].ChainValid || report[0].Items[1].ChainBroken == "" {
+		t.Fatalf("unexpected chain results in items: %+v", report[0].Items)
+	}
+}
@@ -3,13 +3,17 @@ package services
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 	"time"
 
 	"request-system/internal/authz"
 	"request-system/internal/dto"
 	"request-system/internal/entities"
+	"request-system/internal/events"
 	"request-system/internal/repositories"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/eventbus"
 	"request-system/pkg/types"
 	"request-system/pkg/utils"
 
@@ -25,26 +29,26 @@ type RoleServiceInterface interface {
 }
 
 type RoleService struct {
-	repo                  repositories.RoleRepositoryInterface
-	userRepo              repositories.UserRepositoryInterface
-	statusRepo            repositories.StatusRepositoryInterface
-	authPermissionService AuthPermissionServiceInterface
-	logger                *zap.Logger
+	repo       repositories.RoleRepositoryInterface
+	userRepo   repositories.UserRepositoryInterface
+	statusRepo repositories.StatusRepositoryInterface
+	bus        *eventbus.Bus
+	logger     *zap.Logger
 }
 
 func NewRoleService(
 	repo repositories.RoleRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	statusRepo repositories.StatusRepositoryInterface,
-	authPermissionService AuthPermissionServiceInterface,
+	bus *eventbus.Bus,
 	logger *zap.Logger,
 ) RoleServiceInterface {
 	return &RoleService{
-		repo:                  repo,
-		userRepo:              userRepo,
-		statusRepo:            statusRepo,
-		authPermissionService: authPermissionService,
-		logger:                logger,
+		repo:       repo,
+		userRepo:   userRepo,
+		statusRepo: statusRepo,
+		bus:        bus,
+		logger:     logger,
 	}
 }
 
@@ -95,7 +99,15 @@ func (s *RoleService) FindRole(ctx context.Context, id uint64) (*dto.RoleDTO, er
 	if err != nil {
 		return nil, err
 	}
-	return roleEntityToDTO(entity, permissions), nil
+	conditions, err := s.repo.FindPermissionConditions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := roleEntityToDTO(entity, permissions)
+	if len(conditions) > 0 {
+		result.PermissionConditions = conditions
+	}
+	return result, nil
 }
 
 func (s *RoleService) CreateRole(ctx context.Context, dto dto.CreateRoleDTO) (*dto.RoleDTO, error) {
@@ -106,6 +118,9 @@ func (s *RoleService) CreateRole(ctx context.Context, dto dto.CreateRoleDTO) (*d
 	if !authz.CanDo(authz.RolesCreate, *authCtx) {
 		return nil, apperrors.ErrForbidden
 	}
+	if err := validatePermissionConditions(dto.PermissionConditions, dto.PermissionIDs); err != nil {
+		return nil, err
+	}
 
 	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
@@ -140,6 +155,11 @@ func (s *RoleService) CreateRole(ctx context.Context, dto dto.CreateRoleDTO) (*d
 			return nil, err
 		}
 	}
+	for permissionID, conditions := range dto.PermissionConditions {
+		if err = s.repo.SetPermissionConditionsInTx(ctx, tx, newRoleID, permissionID, conditions); err != nil {
+			return nil, err
+		}
+	}
 
 	if err = tx.Commit(ctx); err != nil {
 		return nil, err
@@ -157,20 +177,33 @@ func (s *RoleService) UpdateRole(ctx context.Context, id uint64, dto dto.UpdateR
 		return nil, apperrors.ErrForbidden
 	}
 
-	// Инвалидируем кеш ДО всех изменений
-	s.invalidateAffectedUsersCache(ctx, id)
-
 	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
-	existingEntity, _, err := s.repo.FindRoleByID(ctx, id)
+	existingEntity, currentPermissionIDs, err := s.repo.FindRoleByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// Условия привязаны к связям роли с привилегиями: при замене списка привилегий
+	// переносим условия оставшихся и применяем присланные поверх
+	conditions := make(map[uint64][]authz.Condition)
+	if dto.PermissionIDs != nil {
+		if conditions, err = s.repo.FindPermissionConditions(ctx, id); err != nil {
+			return nil, err
+		}
+		currentPermissionIDs = *dto.PermissionIDs
+	}
+	if err := validatePermissionConditions(dto.PermissionConditions, currentPermissionIDs); err != nil {
+		return nil, err
+	}
+	for permissionID, list := range dto.PermissionConditions {
+		conditions[permissionID] = list
+	}
+
 	if dto.Name != "" {
 		existingEntity.Name = dto.Name
 	}
@@ -194,10 +227,19 @@ func (s *RoleService) UpdateRole(ctx context.Context, id uint64, dto dto.UpdateR
 			}
 		}
 	}
+	for permissionID, list := range conditions {
+		if !slices.Contains(currentPermissionIDs, permissionID) {
+			continue
+		}
+		if err := s.repo.SetPermissionConditionsInTx(ctx, tx, id, permissionID, list); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "role.updated", RoleIDs: []uint64{id}})
 
 	return s.FindRole(ctx, id)
 }
@@ -211,28 +253,16 @@ func (s *RoleService) DeleteRole(ctx context.Context, id uint64) error {
 		return apperrors.ErrForbidden
 	}
 
-	// Сначала инвалидируем кеш всех, у кого была эта роль...
-	s.invalidateAffectedUsersCache(ctx, id)
-
-	// ...а потом удаляем саму роль
-	return s.repo.DeleteRole(ctx, id)
-}
-
-func (s *RoleService) invalidateAffectedUsersCache(ctx context.Context, roleID uint64) {
-	userIDs, err := s.userRepo.FindUserIDsByRoleID(ctx, roleID)
+	// Пользователей роли запоминаем до удаления: после него связи user_roles исчезнут
+	userIDs, err := s.userRepo.FindUserIDsByRoleID(ctx, id)
 	if err != nil {
-		s.logger.Error("Не удалось получить ID пользователей для инвалидации кеша", zap.Uint64("roleID", roleID), zap.Error(err))
-		return
+		return err
 	}
-
-	if len(userIDs) > 0 {
-		s.logger.Info("Инвалидация кеша для пользователей, затронутых изменением роли", zap.Uint64("roleID", roleID), zap.Int("userCount", len(userIDs)))
-		for _, userID := range userIDs {
-			if err := s.authPermissionService.InvalidateUserPermissionsCache(ctx, userID); err != nil {
-				s.logger.Error("Ошибка при инвалидации кеша для конкретного пользователя", zap.Uint64("userID", userID), zap.Error(err))
-			}
-		}
+	if err := s.repo.DeleteRole(ctx, id); err != nil {
+		return err
 	}
+	publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "role.deleted", UserIDs: userIDs})
+	return nil
 }
 
 func (s *RoleService) buildAuthzContext(ctx context.Context) (*authz.Context, error) {
@@ -249,7 +279,27 @@ func (s *RoleService) buildAuthzContext(ctx context.Context) (*authz.Context, er
 		return nil, apperrors.ErrUserNotFound
 	}
 
-	return &authz.Context{Actor: actor, Permissions: permissionsMap}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx)}, nil
+}
+
+// validatePermissionConditions проверяет условия прав роли: привилегия должна быть в роли,
+// а каждое условие — задавать хотя бы одно корректное ограничение.
+func validatePermissionConditions(conditions map[uint64][]authz.Condition, permissionIDs []uint64) error {
+	for permissionID, list := range conditions {
+		if !slices.Contains(permissionIDs, permissionID) {
+			return apperrors.NewHttpError(http.StatusBadRequest,
+				fmt.Sprintf("Условия заданы для привилегии %d, которой нет в роли", permissionID), nil,
+				map[string]interface{}{"field": "permission_conditions"})
+		}
+		for _, condition := range list {
+			if err := condition.Validate(); err != nil {
+				return apperrors.NewHttpError(http.StatusBadRequest,
+					fmt.Sprintf("Условие привилегии %d: %v", permissionID, err), nil,
+					map[string]interface{}{"field": "permission_conditions"})
+			}
+		}
+	}
+	return nil
 }
 
 func roleEntityToDTO(entity *entities.Role, permissions []uint64) *dto.RoleDTO {
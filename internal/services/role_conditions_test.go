@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+)
+
+func TestPermissionConditions(t *testing.T) {
+	branchID, otherBranchID := uint64(5), uint64(6)
+	high, critical := uint64(2), uint64(1)
+	authCtx := authz.Context{
+		Actor:       &entities.User{ID: 7, BranchID: &branchID},
+		Permissions: map[string]bool{authz.OrdersView: true, authz.ScopeAllView: true},
+		Conditions: map[string][]authz.Condition{
+			authz.OrdersView: {{MaxPriority: "HIGH", PriorityIDs: []uint64{high, 3, 4}, SameBranch: true}},
+		},
+	}
+
+	authCtx.Target = &entities.Order{ID: 1, PriorityID: &high, BranchID: &branchID}
+	if !authz.CanDo(authz.OrdersView, authCtx) {
+		t.Fatal("заявка своего филиала с приоритетом HIGH должна быть доступна")
+	}
+	authCtx.Target = &entities.Order{ID: 2, PriorityID: &critical, BranchID: &branchID}
+	if authz.CanDo(authz.OrdersView, authCtx) {
+		t.Fatal("критическая заявка не проходит условие max_priority")
+	}
+	authCtx.Target = &entities.Order{ID: 3, PriorityID: &high, BranchID: &otherBranchID}
+	if authz.CanDo(authz.OrdersView, authCtx) {
+		t.Fatal("заявка чужого филиала не проходит условие same_branch")
+	}
+
+	sql, args, err := authz.NewScopeQueryBuilder("o").Conditions(authCtx, authz.OrdersView, time.Now()).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "((o.priority_id IN (?,?,?) AND o.branch_id = ?))"; sql != want || len(args) != 4 {
+		t.Fatalf("фильтр списка: %s %v, ожидалось %s", sql, args, want)
+	}
+
+	if err := validatePermissionConditions(map[uint64][]authz.Condition{10: {{SameBranch: true}}}, []uint64{11}); err == nil {
+		t.Fatal("условия для привилегии вне роли должны отклоняться")
+	}
+	if err := validatePermissionConditions(map[uint64][]authz.Condition{10: {{BusinessHours: &authz.BusinessHours{From: "9", To: "18:00"}}}}, []uint64{10}); err == nil {
+		t.Fatal("время вне формата ЧЧ:ММ должно отклоняться")
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"request-system/internal/dto"
+	"request-system/internal/events"
 	"request-system/internal/repositories"
+	"request-system/pkg/eventbus"
 
 	"go.uber.org/zap"
 )
@@ -19,23 +21,20 @@ type RolePermissionServiceInterface interface {
 }
 
 type RolePermissionService struct {
-	rpRepository          repositories.RolePermissionRepositoryInterface
-	userRepository        repositories.UserRepositoryInterface
-	authPermissionService AuthPermissionServiceInterface
-	logger                *zap.Logger
+	rpRepository repositories.RolePermissionRepositoryInterface
+	bus          *eventbus.Bus
+	logger       *zap.Logger
 }
 
 func NewRolePermissionService(
 	rpRepository repositories.RolePermissionRepositoryInterface,
-	userRepository repositories.UserRepositoryInterface,
-	authPermissionService AuthPermissionServiceInterface,
+	bus *eventbus.Bus,
 	logger *zap.Logger,
 ) RolePermissionServiceInterface {
 	return &RolePermissionService{
-		rpRepository:          rpRepository,
-		userRepository:        userRepository,
-		authPermissionService: authPermissionService,
-		logger:                logger,
+		rpRepository: rpRepository,
+		bus:          bus,
+		logger:       logger,
 	}
 }
 
@@ -67,7 +66,7 @@ func (s *RolePermissionService) CreateRolePermission(ctx context.Context, dto dt
 		return nil, err
 	}
 
-	s.invalidateAffectedUsersCache(ctx, dto.RoleID)
+	publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "role_permission.created", RoleIDs: []uint64{dto.RoleID}})
 
 	return createdRP, nil
 }
@@ -83,10 +82,11 @@ func (s *RolePermissionService) UpdateRolePermission(ctx context.Context, id uin
 		return nil, err
 	}
 
+	roleIDs := []uint64{updatedRP.RoleID}
 	if oldRP.RoleID != updatedRP.RoleID {
-		s.invalidateAffectedUsersCache(ctx, oldRP.RoleID)
+		roleIDs = append(roleIDs, oldRP.RoleID)
 	}
-	s.invalidateAffectedUsersCache(ctx, updatedRP.RoleID)
+	publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "role_permission.updated", RoleIDs: roleIDs})
 
 	return updatedRP, nil
 }
@@ -102,24 +102,7 @@ func (s *RolePermissionService) DeleteRolePermission(ctx context.Context, id uin
 		return err
 	}
 
-	s.invalidateAffectedUsersCache(ctx, rpToDelete.RoleID)
+	publishPermissionsChanged(ctx, s.bus, events.PermissionsChangedEvent{Reason: "role_permission.deleted", RoleIDs: []uint64{rpToDelete.RoleID}})
 
 	return nil
 }
-
-func (s *RolePermissionService) invalidateAffectedUsersCache(ctx context.Context, roleID uint64) {
-	userIDs, err := s.userRepository.FindUserIDsByRoleID(ctx, roleID)
-	if err != nil {
-		s.logger.Error("Не удалось получить ID пользователей для инвалидации кеша", zap.Uint64("roleID", roleID), zap.Error(err))
-		return
-	}
-
-	if len(userIDs) > 0 {
-		s.logger.Info("Инвалидация кеша для пользователей, затронутых изменением роли", zap.Uint64("roleID", roleID), zap.Int("userCount", len(userIDs)))
-		for _, userID := range userIDs {
-			if err := s.authPermissionService.InvalidateUserPermissionsCache(ctx, userID); err != nil {
-				s.logger.Error("Ошибка при инвалидации кеша для конкретного пользователя", zap.Uint64("userID", userID), zap.Error(err))
-			}
-		}
-	}
-}
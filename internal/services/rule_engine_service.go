@@ -11,7 +11,9 @@ import (
 	"request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
 	"strings"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
@@ -31,6 +33,10 @@ type RoutingResult struct {
 	StatusID  int
 	RuleFound bool
 
+	// Team заполняется, когда правило направляет заявку на команду: Executor тогда пуст,
+	// исполнителя назначит участник команды, забрав заявку
+	Team *entities.Team
+
 	// Для конфига
 	DepartmentID *int
 	OtdelID      *int
@@ -42,20 +48,23 @@ type RuleEngineServiceInterface interface {
 }
 
 type RuleEngineService struct {
-	repo     repositories.OrderRoutingRuleRepositoryInterface
-	userRepo repositories.UserRepositoryInterface
-	logger   *zap.Logger
+	repo       repositories.OrderRoutingRuleRepositoryInterface
+	userRepo   repositories.UserRepositoryInterface
+	onCallRepo repositories.OnCallRepositoryInterface
+	logger     *zap.Logger
 }
 
 func NewRuleEngineService(
 	repo repositories.OrderRoutingRuleRepositoryInterface,
 	userRepo repositories.UserRepositoryInterface,
+	onCallRepo repositories.OnCallRepositoryInterface,
 	logger *zap.Logger,
 ) RuleEngineServiceInterface {
 	return &RuleEngineService{
-		repo:     repo,
-		userRepo: userRepo,
-		logger:   logger,
+		repo:       repo,
+		userRepo:   userRepo,
+		onCallRepo: onCallRepo,
+		logger:     logger,
 	}
 }
 
@@ -72,8 +81,11 @@ func (s *RuleEngineService) ResolveExecutor(ctx context.Context, tx pgx.Tx, orde
 
 	// 2. Ищем ПРАВИЛО в БД
 	query := `
-		SELECT assign_to_position_id, status_id, department_id, otdel_id, branch_id, office_id
-		FROM order_routing_rules
+		SELECT r.assign_to_position_id, r.assign_to_team_id, t.name, r.status_id, r.department_id, r.otdel_id, r.branch_id, r.office_id,
+			EXISTS(SELECT 1 FROM team_members tm WHERE tm.team_id = r.assign_to_team_id) AS team_has_members,
+			r.assign_to_on_call_schedule_id
+		FROM order_routing_rules r
+		LEFT JOIN teams t ON t.id = r.assign_to_team_id
 		WHERE (order_type_id IS NULL OR order_type_id = $1)
 			AND (department_id IS NULL OR department_id = $2)
 			AND (otdel_id IS NULL OR otdel_id = $3)
@@ -83,25 +95,58 @@ func (s *RuleEngineService) ResolveExecutor(ctx context.Context, tx pgx.Tx, orde
 		LIMIT 1
 	`
 	var targetPositionID *int
+	var targetTeamID *uint64
+	var targetTeamName *string
 	var targetStatusID int
 	var ruleDept, ruleOtdel, ruleBranch, ruleOffice *uint64
+	var teamHasMembers bool
+	var targetOnCallScheduleID *uint64
 
 	err := tx.QueryRow(ctx, query, orderCtx.OrderTypeID, orderCtx.DepartmentID, orderCtx.OtdelID, orderCtx.BranchID, orderCtx.OfficeID).
-		Scan(&targetPositionID, &targetStatusID, &ruleDept, &ruleOtdel, &ruleBranch, &ruleOffice)
+		Scan(&targetPositionID, &targetTeamID, &targetTeamName, &targetStatusID, &ruleDept, &ruleOtdel, &ruleBranch, &ruleOffice, &teamHasMembers,
+			&targetOnCallScheduleID)
 
-	// 3. Если правила НЕТ вообще — идем в стандартный Waterfall
+	// 3. Если правила НЕТ вообще — сначала исполнитель с нужными навыками, затем стандартный Waterfall
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			if user := s.findUserBySkills(ctx, tx, orderCtx); user != nil {
+				return &RoutingResult{Executor: *user, RuleFound: false}, nil
+			}
 			return s.resolveByHierarchy(ctx, tx, orderCtx)
 		}
 		return nil, fmt.Errorf("ошибка SQL правил: %w", err)
 	}
 
-	// 4. ПРАВИЛО ЕСТЬ — пробуем найти по нему конкретного человека
-	foundUser, err := s.findUserByPositionAndStructure(ctx, tx, *targetPositionID, orderCtx)
+	// 4. ПРАВИЛО ЕСТЬ — заявка уходит текущему дежурному, в очередь команды или конкретному человеку по должности
+	if targetOnCallScheduleID != nil {
+		if user := s.findOnCallUser(ctx, tx, *targetOnCallScheduleID); user != nil {
+			return &RoutingResult{Executor: *user, StatusID: targetStatusID, RuleFound: true}, nil
+		}
+		s.logger.Warn("По графику из правила сейчас некому дежурить, заявка уйдёт по команде, должности или иерархии",
+			zap.Uint64("on_call_schedule_id", *targetOnCallScheduleID))
+	}
 
-	if err == nil {
-		return &RoutingResult{Executor: *foundUser, StatusID: targetStatusID, RuleFound: true}, nil
+	if targetTeamID != nil {
+		if teamHasMembers {
+			team := &entities.Team{ID: *targetTeamID}
+			if targetTeamName != nil {
+				team.Name = *targetTeamName
+			}
+			return &RoutingResult{Team: team, StatusID: targetStatusID, RuleFound: true}, nil
+		}
+		s.logger.Warn("В команде из правила нет участников, заявка уйдёт по должности или иерархии", zap.Uint64("team_id", *targetTeamID))
+	}
+
+	// Навыки, которые требует тип заявки, важнее должности из правила
+	if user := s.findUserBySkills(ctx, tx, orderCtx); user != nil {
+		return &RoutingResult{Executor: *user, StatusID: targetStatusID, RuleFound: true}, nil
+	}
+
+	if targetPositionID != nil {
+		foundUser, err := s.findUserByPositionAndStructure(ctx, tx, *targetPositionID, orderCtx)
+		if err == nil {
+			return &RoutingResult{Executor: *foundUser, StatusID: targetStatusID, RuleFound: true}, nil
+		}
 	}
 
 	// 5. 🔥 САМОЕ ВАЖНОЕ: Если по правилу человека НЕ НАШЛИ (позиция пуста),
@@ -192,7 +237,7 @@ func (s *RuleEngineService) resolveByHierarchy(ctx context.Context, tx pgx.Tx, d
 		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Для заявки не выбрана структура. Укажите подразделение или выберите исполнителя вручную.", nil, nil)
 	}
 
-	// 3. ПОИСК В БАЗЕ (С ПОДДЕРЖКОЙ ЗАМЕСТИТЕЛЯ)
+	// 3. ПОИСК В БАЗЕ (С ПОДДЕРЖКОЙ ЗАМЕСТИТЕЛЯ); кто не на смене или в отпуске — пропускаем
 	for _, role := range targetRoles {
 		query := `
 			SELECT DISTINCT u.id, u.fio, u.email, u.position_id, u.department_id, u.branch_id
@@ -203,6 +248,7 @@ func (s *RuleEngineService) resolveByHierarchy(ctx context.Context, tx pgx.Tx, d
 			WHERE u.deleted_at IS NULL 
 			  AND UPPER(s.code) = 'ACTIVE' 
 			  AND p.type = $1 
+			  AND ` + repositories.UserOnDutyCondition + `
 		`
 		args := []interface{}{role}
 		argIdx := 2
@@ -261,7 +307,7 @@ func (s *RuleEngineService) resolveByHierarchy(ctx context.Context, tx pgx.Tx, d
 
 	return nil, apperrors.NewHttpError(
 		http.StatusBadRequest,
-		fmt.Sprintf("В подразделении '%s' не найден ни '%s', ни '%s'. Выберите исполнителя вручную или настройте маршрутизацию.", searchScopeName, roleName1, roleName2),
+		fmt.Sprintf("В подразделении '%s' не найден ни '%s', ни '%s' (или они сейчас не на смене). Выберите исполнителя вручную или настройте маршрутизацию.", searchScopeName, roleName1, roleName2),
 		nil,
 		nil,
 	)
@@ -336,6 +382,7 @@ func (s *RuleEngineService) findUserByPositionAndStructure(ctx context.Context,
 		WHERE up.position_id = $1 
 		  AND u.deleted_at IS NULL
 		  AND UPPER(s.code) = 'ACTIVE'
+		  AND ` + repositories.UserOnDutyCondition + `
 	`
 	args := []interface{}{positionID}
 	argIdx := 2
@@ -366,6 +413,96 @@ func (s *RuleEngineService) findUserByPositionAndStructure(ctx context.Context,
 	return &u, nil
 }
 
+// findUserBySkills ищет активного сотрудника на смене из структуры заявки, у которого есть все навыки,
+// обязательные для её типа. nil — у типа нет обязательных навыков или подходящих людей нет.
+func (s *RuleEngineService) findUserBySkills(ctx context.Context, tx pgx.Tx, orderCtx OrderContext) *entities.User {
+	if orderCtx.OrderTypeID == 0 {
+		return nil
+	}
+
+	query, args, err := sq.Select("u.id", "u.fio", "u.email", "u.position_id", "u.department_id", "u.branch_id").
+		From("users u").
+		Join("statuses s ON u.status_id = s.id").
+		Where("u.deleted_at IS NULL").
+		Where("UPPER(s.code) = 'ACTIVE'").
+		Where(repositories.UserOnDutyCondition).
+		Where("EXISTS (SELECT 1 FROM order_type_skills ots WHERE ots.order_type_id = ?)", orderCtx.OrderTypeID).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM order_type_skills ots
+			WHERE ots.order_type_id = ?
+			  AND NOT EXISTS (SELECT 1 FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = ots.skill_id)
+		)`, orderCtx.OrderTypeID).
+		Where(executorStructureCondition(orderCtx)).
+		OrderBy("u.id ASC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		s.logger.Error("Ошибка построения запроса поиска по навыкам", zap.Error(err))
+		return nil
+	}
+
+	var u entities.User
+	err = tx.QueryRow(ctx, query, args...).Scan(&u.ID, &u.Fio, &u.Email, &u.PositionID, &u.DepartmentID, &u.BranchID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Ошибка поиска исполнителя по навыкам", zap.Uint64("orderTypeID", orderCtx.OrderTypeID), zap.Error(err))
+		}
+		return nil
+	}
+	s.logger.Info("Исполнитель найден по навыкам", zap.Uint64("orderTypeID", orderCtx.OrderTypeID), zap.String("fio", u.Fio))
+	return &u
+}
+
+// executorStructureCondition ограничивает поиск исполнителей подразделением заявки
+// с тем же приоритетом, что и matchesExecutorToStructure.
+func executorStructureCondition(orderCtx OrderContext) sq.Eq {
+	switch {
+	case orderCtx.DepartmentID != 0:
+		return sq.Eq{"u.department_id": orderCtx.DepartmentID}
+	case orderCtx.OtdelID != nil && orderCtx.BranchID != nil:
+		return sq.Eq{"u.otdel_id": *orderCtx.OtdelID, "u.branch_id": *orderCtx.BranchID}
+	case orderCtx.OtdelID != nil:
+		return sq.Eq{"u.otdel_id": *orderCtx.OtdelID}
+	case orderCtx.BranchID != nil:
+		return sq.Eq{"u.branch_id": *orderCtx.BranchID}
+	case orderCtx.OfficeID != nil:
+		return sq.Eq{"u.office_id": *orderCtx.OfficeID}
+	default:
+		return sq.Eq{}
+	}
+}
+
+// findOnCallUser возвращает того, кто дежурит по графику прямо сейчас. Смена и отпуск
+// здесь не проверяются: дежурство и есть работа вне смены, а отсутствие дежурного
+// оформляют заменой в графике. nil — график выключен, пуст или дежурный неактивен.
+func (s *RuleEngineService) findOnCallUser(ctx context.Context, tx pgx.Tx, scheduleID uint64) *entities.User {
+	schedule, err := s.onCallRepo.FindScheduleByID(ctx, scheduleID)
+	if err != nil {
+		s.logger.Warn("Не удалось загрузить график дежурств", zap.Uint64("scheduleID", scheduleID), zap.Error(err))
+		return nil
+	}
+	if !schedule.IsActive {
+		return nil
+	}
+	shift, ok, err := findOnCall(ctx, s.onCallRepo, *schedule, time.Now())
+	if err != nil {
+		s.logger.Warn("Не удалось определить дежурного", zap.Uint64("scheduleID", scheduleID), zap.Error(err))
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	user, err := s.userRepo.FindUserByIDInTx(ctx, tx, shift.UserID)
+	if err != nil || !strings.EqualFold(user.StatusCode, "ACTIVE") {
+		s.logger.Warn("Дежурный по графику не найден или неактивен", zap.Uint64("scheduleID", scheduleID), zap.Uint64("userID", shift.UserID), zap.Error(err))
+		return nil
+	}
+	s.logger.Info("Исполнитель назначен по графику дежурств", zap.Uint64("scheduleID", scheduleID), zap.String("fio", user.Fio))
+	return user
+}
+
 func (s *RuleEngineService) GetPredefinedRoute(ctx context.Context, tx pgx.Tx, orderTypeID uint64) (*RoutingResult, error) {
 	query := `SELECT department_id, otdel_id FROM order_routing_rules WHERE order_type_id = $1 LIMIT 1`
 	var res RoutingResult
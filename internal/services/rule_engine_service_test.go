@@ -0,0 +1,30 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func TestExecutorStructureConditionFollowsOrderStructure(t *testing.T) {
+	otdel, branch, office := uint64(4), uint64(7), uint64(9)
+
+	cases := []struct {
+		name     string
+		orderCtx OrderContext
+		want     sq.Eq
+	}{
+		{"department wins", OrderContext{DepartmentID: 2, OtdelID: &otdel, BranchID: &branch}, sq.Eq{"u.department_id": uint64(2)}},
+		{"otdel inside branch", OrderContext{OtdelID: &otdel, BranchID: &branch}, sq.Eq{"u.otdel_id": otdel, "u.branch_id": branch}},
+		{"branch", OrderContext{BranchID: &branch, OfficeID: &office}, sq.Eq{"u.branch_id": branch}},
+		{"office", OrderContext{OfficeID: &office}, sq.Eq{"u.office_id": office}},
+		{"no structure", OrderContext{}, sq.Eq{}},
+	}
+
+	for _, tc := range cases {
+		if got := executorStructureCondition(tc.orderCtx); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
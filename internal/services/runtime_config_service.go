@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+)
+
+// runtimeConfigReloadChannel — канал Redis, по которому реплики узнают о ручном перечитывании настроек.
+const runtimeConfigReloadChannel = "config:reload"
+
+type RuntimeConfigServiceInterface interface {
+	Get(ctx context.Context) (*dto.RuntimeConfigDTO, error)
+	Reload(ctx context.Context) (*dto.RuntimeConfigReloadDTO, error)
+	// Start следит за .env (если задан интервал) и за перечитыванием на других репликах.
+	Start(ctx context.Context)
+}
+
+// runtimeSettingsSource — часть *config.Runtime, нужная сервису.
+type runtimeSettingsSource interface {
+	Settings() config.RuntimeSettings
+	Reload() (config.RuntimeChange, error)
+	EnvFileChanged() bool
+}
+
+// RuntimeConfigService перечитывает настройки из .env без перезапуска и пишет изменения в журнал аудита.
+type RuntimeConfigService struct {
+	runtime       runtimeSettingsSource
+	watchInterval time.Duration
+	auditService  AuditServiceInterface
+	userRepo      repositories.UserRepositoryInterface
+	redis         *redis.Client
+	logger        *zap.Logger
+}
+
+func NewRuntimeConfigService(
+	runtime runtimeSettingsSource,
+	watchInterval time.Duration,
+	auditService AuditServiceInterface,
+	userRepo repositories.UserRepositoryInterface,
+	redisClient *redis.Client,
+	logger *zap.Logger,
+) RuntimeConfigServiceInterface {
+	return &RuntimeConfigService{
+		runtime:       runtime,
+		watchInterval: watchInterval,
+		auditService:  auditService,
+		userRepo:      userRepo,
+		redis:         redisClient,
+		logger:        logger,
+	}
+}
+
+func (s *RuntimeConfigService) Get(ctx context.Context) (*dto.RuntimeConfigDTO, error) {
+	if _, err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return &dto.RuntimeConfigDTO{
+		Settings:             s.runtime.Settings().Values(),
+		WatchIntervalSeconds: int(s.watchInterval.Seconds()),
+	}, nil
+}
+
+func (s *RuntimeConfigService) Reload(ctx context.Context) (*dto.RuntimeConfigReloadDTO, error) {
+	authContext, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.runtime.Reload()
+	if err != nil {
+		return nil, apperrors.NewHttpError(http.StatusInternalServerError, "Не удалось перечитать настройки", err, nil)
+	}
+	actorID := authContext.Actor.ID
+	s.record(ctx, change, &actorID, "POST", "/api/admin/config/reload")
+
+	// Остальные реплики перечитывают свой .env сами; в журнал пишет только эта
+	if s.redis != nil {
+		if err := s.redis.Publish(ctx, runtimeConfigReloadChannel, actorID).Err(); err != nil {
+			s.logger.Warn("Не удалось оповестить реплики о перечитывании настроек", zap.Error(err))
+		}
+	}
+
+	result := &dto.RuntimeConfigReloadDTO{
+		Settings: s.runtime.Settings().Values(),
+		Changed:  make(map[string]dto.RuntimeConfigChangeDTO, len(change.After)),
+	}
+	for _, key := range change.Keys() {
+		result.Changed[key] = dto.RuntimeConfigChangeDTO{Old: change.Before[key], New: change.After[key]}
+	}
+	return result, nil
+}
+
+func (s *RuntimeConfigService) Start(ctx context.Context) {
+	if s.redis != nil {
+		go s.listenReloads(ctx)
+	}
+	if s.watchInterval <= 0 {
+		return
+	}
+
+	s.logger.Info("Слежение за .env запущено", zap.Duration("interval", s.watchInterval))
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.runtime.EnvFileChanged() {
+				continue
+			}
+			change, err := s.runtime.Reload()
+			if err != nil {
+				s.logger.Error("Не удалось перечитать изменившийся .env", zap.Error(err))
+				continue
+			}
+			s.record(ctx, change, nil, "WATCH", ".env")
+		}
+	}
+}
+
+// listenReloads перечитывает настройки, когда их перечитали через API на другой реплике.
+func (s *RuntimeConfigService) listenReloads(ctx context.Context) {
+	for ctx.Err() == nil {
+		pubsub := s.redis.Subscribe(ctx, runtimeConfigReloadChannel)
+		for msg := range pubsub.Channel() {
+			change, err := s.runtime.Reload()
+			if err != nil {
+				s.logger.Error("Не удалось перечитать настройки по сигналу другой реплики", zap.Error(err))
+				continue
+			}
+			if !change.Empty() {
+				s.logger.Info("Настройки перечитаны по сигналу другой реплики",
+					zap.Strings("changed", change.Keys()), zap.String("by", msg.Payload))
+			}
+		}
+		_ = pubsub.Close()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// record пишет изменившиеся настройки в журнал аудита; actorID nil — изменение замечено по .env.
+func (s *RuntimeConfigService) record(ctx context.Context, change config.RuntimeChange, actorID *uint64, method, path string) {
+	if change.Empty() {
+		return
+	}
+	s.logger.Info("Настройки изменены без перезапуска", zap.Strings("changed", change.Keys()), zap.String("source", method))
+
+	before, _ := json.Marshal(change.Before)
+	after, _ := json.Marshal(change.After)
+	s.auditService.Record(ctx, &entities.AuditLogEntry{
+		ActorID:    actorID,
+		Action:     entities.AuditActionUpdate,
+		Entity:     "config",
+		Method:     method,
+		Path:       path,
+		StatusCode: http.StatusOK,
+		Before:     before,
+		After:      after,
+	})
+}
+
+func (s *RuntimeConfigService) authorize(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.ConfigManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+)
+
+type runtimeSourceStub struct {
+	settings config.RuntimeSettings
+	next     config.RuntimeSettings
+}
+
+func (r *runtimeSourceStub) Settings() config.RuntimeSettings { return r.settings }
+
+func (r *runtimeSourceStub) Reload() (config.RuntimeChange, error) {
+	before, after := r.settings.Values(), r.next.Values()
+	change := config.RuntimeChange{Before: map[string]string{}, After: map[string]string{}}
+	for key, value := range after {
+		if before[key] != value {
+			change.Before[key] = before[key]
+			change.After[key] = value
+		}
+	}
+	r.settings = r.next
+	return change, nil
+}
+
+func (r *runtimeSourceStub) EnvFileChanged() bool { return false }
+
+type configAuditStub struct {
+	entries []*entities.AuditLogEntry
+}
+
+func (a *configAuditStub) Record(_ context.Context, entry *entities.AuditLogEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+func (a *configAuditStub) Snapshot(context.Context, string, uint64) json.RawMessage { return nil }
+
+func (a *configAuditStub) GetAuditLog(context.Context, repositories.AuditLogFilter) (*dto.PaginatedResponse[dto.AuditLogEntryDTO], error) {
+	return nil, nil
+}
+
+func TestRuntimeConfigReload_ReportsAndAuditsChanges(t *testing.T) {
+	runtime := &runtimeSourceStub{
+		settings: config.RuntimeSettings{NotifyTelegram: true},
+		next:     config.RuntimeSettings{NotifyTelegram: false},
+	}
+	audit := &configAuditStub{}
+	service := NewRuntimeConfigService(runtime, time.Minute, audit, &replayUserRepoStub{}, nil, zap.NewNop())
+
+	if _, err := service.Reload(jobsAdminCtx(map[string]bool{})); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.ConfigManage, err)
+	}
+
+	ctx := jobsAdminCtx(map[string]bool{authz.ConfigManage: true})
+	result, err := service.Reload(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	change, ok := result.Changed["NOTIFY_TELEGRAM_ENABLED"]
+	if len(result.Changed) != 1 || !ok || change.Old != "true" || change.New != "false" {
+		t.Fatalf("unexpected changes: %+v", result.Changed)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Entity != "config" || *audit.entries[0].ActorID != 1 {
+		t.Fatalf("expected one config audit entry, got %+v", audit.entries)
+	}
+
+	// Повторное перечитывание без изменений в журнал не пишется
+	if _, err := service.Reload(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(audit.entries) != 1 {
+		t.Fatalf("expected no audit entry for an unchanged reload, got %d", len(audit.entries))
+	}
+
+	current, err := service.Get(ctx)
+	if err != nil || current.Settings["NOTIFY_TELEGRAM_ENABLED"] != "false" || current.WatchIntervalSeconds != 60 {
+		t.Fatalf("unexpected settings: %+v, %v", current, err)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"github.com/pressly/goose/v3"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+type SchemaServiceInterface interface {
+	GetVersion(ctx context.Context) (*dto.SchemaVersionDTO, error)
+}
+
+// schemaMigrator — часть *goose.Provider, нужная для чтения состояния миграций.
+type schemaMigrator interface {
+	Status(ctx context.Context) ([]*goose.MigrationStatus, error)
+}
+
+// SchemaService показывает версию схемы БД: при DB_AUTO_MIGRATE=false по ней видно,
+// применён ли уже шаг миграции перед выкаткой новых реплик.
+type SchemaService struct {
+	migrator    schemaMigrator
+	autoMigrate bool
+	userRepo    repositories.UserRepositoryInterface
+	logger      *zap.Logger
+}
+
+func NewSchemaService(migrator schemaMigrator, autoMigrate bool, userRepo repositories.UserRepositoryInterface, logger *zap.Logger) SchemaServiceInterface {
+	return &SchemaService{migrator: migrator, autoMigrate: autoMigrate, userRepo: userRepo, logger: logger}
+}
+
+func (s *SchemaService) GetVersion(ctx context.Context) (*dto.SchemaVersionDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.ConfigManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	statuses, err := s.migrator.Status(ctx)
+	if err != nil {
+		s.logger.Error("Не удалось получить состояние миграций", zap.Error(err))
+		return nil, err
+	}
+
+	result := &dto.SchemaVersionDTO{AutoMigrate: s.autoMigrate, Pending: []dto.SchemaMigrationDTO{}}
+	for _, st := range statuses {
+		migration := dto.SchemaMigrationDTO{Version: st.Source.Version, File: st.Source.Path}
+		result.LatestVersion = max(result.LatestVersion, st.Source.Version)
+		if st.State != goose.StateApplied {
+			result.Pending = append(result.Pending, migration)
+			continue
+		}
+
+		appliedAt := st.AppliedAt
+		migration.AppliedAt = &appliedAt
+		if st.Source.Version > result.CurrentVersion {
+			result.CurrentVersion = st.Source.Version
+			result.LastApplied = &migration
+		}
+	}
+	result.UpToDate = len(result.Pending) == 0
+	return result, nil
+}
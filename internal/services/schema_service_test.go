@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	apperrors "request-system/pkg/errors"
+)
+
+type schemaMigratorStub struct {
+	statuses []*goose.MigrationStatus
+}
+
+func (m *schemaMigratorStub) Status(context.Context) ([]*goose.MigrationStatus, error) {
+	return m.statuses, nil
+}
+
+func TestSchemaGetVersion_ReportsPendingMigrations(t *testing.T) {
+	appliedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	migrator := &schemaMigratorStub{statuses: []*goose.MigrationStatus{
+		{Source: &goose.Source{Version: 10, Path: "10_a.sql"}, State: goose.StateApplied, AppliedAt: appliedAt},
+		{Source: &goose.Source{Version: 20, Path: "20_b.sql"}, State: goose.StateApplied, AppliedAt: appliedAt},
+		{Source: &goose.Source{Version: 30, Path: "30_c.sql"}, State: goose.StatePending},
+	}}
+	service := NewSchemaService(migrator, false, &replayUserRepoStub{}, zap.NewNop())
+
+	if _, err := service.GetVersion(jobsAdminCtx(map[string]bool{})); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.ConfigManage, err)
+	}
+
+	result, err := service.GetVersion(jobsAdminCtx(map[string]bool{authz.ConfigManage: true}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.CurrentVersion != 20 || result.LatestVersion != 30 || result.UpToDate || result.AutoMigrate {
+		t.Fatalf("unexpected versions: %+v", result)
+	}
+	if len(result.Pending) != 1 || result.Pending[0].File != "30_c.sql" {
+		t.Fatalf("unexpected pending migrations: %+v", result.Pending)
+	}
+	if result.LastApplied == nil || result.LastApplied.Version != 20 || !result.LastApplied.AppliedAt.Equal(appliedAt) {
+		t.Fatalf("unexpected last applied migration: %+v", result.LastApplied)
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	"request-system/pkg/types"
+)
+
+func TestScopeQueryBuilderSharedByListAndDashboard(t *testing.T) {
+	departmentID, branchID := uint64(3), uint64(5)
+	actor := &entities.User{ID: 7, DepartmentID: &departmentID, BranchID: &branchID}
+	authCtx := authz.Context{
+		Actor:       actor,
+		Permissions: map[string]bool{authz.ScopeDepartment: true, authz.ScopeOffice: true, authz.ScopeOwn: true},
+	}
+
+	// Офиса у пользователя нет, поэтому область office в условие не попадает
+	sql, args, err := authz.NewScopeQueryBuilder("o").Build(authCtx, authz.OrdersView, time.Now()).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(o.department_id = ? OR (o.user_id = ? OR o.executor_id = ?))"; sql != want || len(args) != 3 {
+		t.Fatalf("условие списка: %s %v, ожидалось %s", sql, args, want)
+	}
+
+	sql, _, err = authz.NewScopeQueryBuilder("h").WithParticipation().ScopeCondition(authz.ScopeOwn, actor).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(h.user_id = ? OR h.executor_id = ? OR h.id IN (SELECT DISTINCT order_id FROM order_history WHERE user_id = ?) OR h.team_id IN (SELECT team_id FROM team_members WHERE user_id = ?))"; sql != want {
+		t.Fatalf("область own с участием: %s", sql)
+	}
+
+	req := dashboardRequest{}
+	condition := resolveDashboardSecurity(&authCtx, actor, &req)
+	if req.effectiveScope != types.DashboardScopeDepartment || condition == nil {
+		t.Fatalf("дашборд должен взять область департамента, получено %q", req.effectiveScope)
+	}
+	if sql, _, _ := condition.ToSql(); sql != "o.department_id = ?" {
+		t.Fatalf("условие дашборда: %s", sql)
+	}
+
+	if authz.NewScopeQueryBuilder("o").Scopes(authz.Context{Actor: actor, Permissions: map[string]bool{authz.ScopeAllView: true}}) != nil {
+		t.Fatal("scope:all_view не должен ограничивать заявки")
+	}
+	if sql, _, _ := authz.NewScopeQueryBuilder("o").Scopes(authz.Context{Actor: actor, Permissions: map[string]bool{}}).(sq.Or).ToSql(); sql != "(1=0)" {
+		t.Fatalf("без областей не должно быть видно ни одной заявки: %s", sql)
+	}
+}
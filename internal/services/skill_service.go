@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+// SkillServiceInterface ведёт справочник навыков, навыки пользователей и навыки,
+// обязательные для типов заявок. RuleEngine по ним выбирает подходящего исполнителя.
+type SkillServiceInterface interface {
+	ListSkills(ctx context.Context) ([]dto.SkillDTO, error)
+	CreateSkill(ctx context.Context, payload dto.CreateSkillDTO) (*dto.SkillDTO, error)
+	DeleteSkill(ctx context.Context, id uint64) error
+
+	GetUserSkills(ctx context.Context, userID uint64) ([]dto.SkillDTO, error)
+	SetUserSkills(ctx context.Context, userID uint64, payload dto.SetSkillsDTO) ([]dto.SkillDTO, error)
+	GetOrderTypeSkills(ctx context.Context, orderTypeID uint64) ([]dto.SkillDTO, error)
+	SetOrderTypeSkills(ctx context.Context, orderTypeID uint64, payload dto.SetSkillsDTO) ([]dto.SkillDTO, error)
+}
+
+type SkillService struct {
+	repo          repositories.SkillRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	orderTypeRepo repositories.OrderTypeRepositoryInterface
+	logger        *zap.Logger
+}
+
+func NewSkillService(
+	repo repositories.SkillRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	orderTypeRepo repositories.OrderTypeRepositoryInterface,
+	logger *zap.Logger,
+) SkillServiceInterface {
+	return &SkillService{repo: repo, userRepo: userRepo, orderTypeRepo: orderTypeRepo, logger: logger}
+}
+
+func (s *SkillService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func (s *SkillService) ListSkills(ctx context.Context) ([]dto.SkillDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.SkillsView); err != nil {
+		return nil, err
+	}
+	skills, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toSkillDTOs(skills), nil
+}
+
+func (s *SkillService) CreateSkill(ctx context.Context, payload dto.CreateSkillDTO) (*dto.SkillDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.SkillsManage)
+	if err != nil {
+		return nil, err
+	}
+	skill := &entities.Skill{Code: strings.TrimSpace(payload.Code), Name: strings.TrimSpace(payload.Name)}
+	if skill.Code == "" || skill.Name == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Укажите код и название навыка", nil, nil)
+	}
+	if err := s.repo.Create(ctx, skill); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Добавлен навык", zap.String("code", skill.Code), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toSkillDTO(*skill)
+	return &result, nil
+}
+
+func (s *SkillService) DeleteSkill(ctx context.Context, id uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.SkillsManage)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалён навык", zap.Uint64("skillID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *SkillService) GetUserSkills(ctx context.Context, userID uint64) ([]dto.SkillDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.UsersView); err != nil {
+		return nil, err
+	}
+	skills, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toSkillDTOs(skills), nil
+}
+
+func (s *SkillService) SetUserSkills(ctx context.Context, userID uint64, payload dto.SetSkillsDTO) ([]dto.SkillDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.UsersUpdate); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.FindUserByID(ctx, userID); err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if err := s.repo.SetUserSkills(ctx, userID, uniqueSkillIDs(payload.SkillIDs)); err != nil {
+		return nil, err
+	}
+	skills, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toSkillDTOs(skills), nil
+}
+
+func (s *SkillService) GetOrderTypeSkills(ctx context.Context, orderTypeID uint64) ([]dto.SkillDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OrderTypesView); err != nil {
+		return nil, err
+	}
+	skills, err := s.repo.FindByOrderType(ctx, orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return toSkillDTOs(skills), nil
+}
+
+func (s *SkillService) SetOrderTypeSkills(ctx context.Context, orderTypeID uint64, payload dto.SetSkillsDTO) ([]dto.SkillDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.OrderTypesUpdate); err != nil {
+		return nil, err
+	}
+	if _, err := s.orderTypeRepo.FindByID(ctx, orderTypeID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetOrderTypeSkills(ctx, orderTypeID, uniqueSkillIDs(payload.SkillIDs)); err != nil {
+		return nil, err
+	}
+	skills, err := s.repo.FindByOrderType(ctx, orderTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return toSkillDTOs(skills), nil
+}
+
+func uniqueSkillIDs(ids []uint64) []uint64 {
+	result := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 && !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func toSkillDTO(skill entities.Skill) dto.SkillDTO {
+	return dto.SkillDTO{
+		ID:        skill.ID,
+		Code:      skill.Code,
+		Name:      skill.Name,
+		CreatedAt: skill.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toSkillDTOs(skills []entities.Skill) []dto.SkillDTO {
+	result := make([]dto.SkillDTO, 0, len(skills))
+	for _, skill := range skills {
+		result = append(result, toSkillDTO(skill))
+	}
+	return result
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/businesshours"
+	"request-system/pkg/config"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
+	"request-system/pkg/sanitize"
+	"request-system/pkg/telegram"
+	"request-system/pkg/websocket"
+)
+
+const (
+	// slaEscalationLookback — нарушения старше не записываются, чтобы первое включение
+	// не подняло эскалации по давно забытым заявкам.
+	slaEscalationLookback  = 24 * time.Hour
+	slaEscalationBatchSize = 500
+	// slaEscalationMaxOrders — сколько заявок перечислять в сводке руководителю.
+	slaEscalationMaxOrders = 10
+)
+
+type SLAEscalationServiceInterface interface {
+	GetSettings(ctx context.Context) (*dto.SLAEscalationSettingsDTO, error)
+	GetDepartmentSetting(ctx context.Context, departmentID uint64) (*dto.SLAEscalationSettingDTO, error)
+	UpdateDepartmentSetting(ctx context.Context, departmentID uint64, payload dto.UpdateSLAEscalationSettingDTO) (*dto.SLAEscalationSettingDTO, error)
+	// ResetDepartmentSetting удаляет порог департамента — снова действуют значения по умолчанию.
+	ResetDepartmentSetting(ctx context.Context, departmentID uint64) error
+
+	Start(ctx context.Context)
+	// RunOnce записывает новые нарушения срока и отправляет эскалации по исполнителям,
+	// набравшим порог своего департамента. Возвращает число отправленных эскалаций.
+	RunOnce(ctx context.Context) (int, error)
+}
+
+// SLAEscalationService следит за повторными нарушениями срока. Когда один исполнитель
+// нарушает срок N раз за окно, руководители его департамента (и его отдела) получают
+// сводку с заявками и просрочкой в Telegram и в центр уведомлений. Каждое нарушение
+// входит не больше чем в одну эскалацию.
+type SLAEscalationService struct {
+	repo               repositories.SLAEscalationRepositoryInterface
+	userRepo           repositories.UserRepositoryInterface
+	outbox             NotificationOutboxServiceInterface
+	notificationCenter NotificationCenterServiceInterface
+	calendar           BusinessCalendarProvider
+	notifyCfg          config.NotificationsConfig
+	frontendBaseURL    string
+	logger             *zap.Logger
+	now                func() time.Time
+}
+
+func NewSLAEscalationService(
+	repo repositories.SLAEscalationRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	outbox NotificationOutboxServiceInterface,
+	notificationCenter NotificationCenterServiceInterface,
+	calendar BusinessCalendarProvider,
+	notifyCfg config.NotificationsConfig,
+	frontendCfg config.FrontendConfig,
+	logger *zap.Logger,
+) SLAEscalationServiceInterface {
+	return &SLAEscalationService{
+		repo:               repo,
+		userRepo:           userRepo,
+		outbox:             outbox,
+		notificationCenter: notificationCenter,
+		calendar:           calendar,
+		notifyCfg:          notifyCfg,
+		frontendBaseURL:    strings.TrimRight(frontendCfg.BaseURL, "/"),
+		logger:             logger,
+		now:                time.Now,
+	}
+}
+
+func (s *SLAEscalationService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+// defaultRule — порог для департамента без своей настройки.
+func (s *SLAEscalationService) defaultRule(departmentID uint64) entities.SLAEscalationSetting {
+	return entities.SLAEscalationSetting{
+		DepartmentID:    departmentID,
+		BreachThreshold: s.notifyCfg.EscalationThreshold,
+		WindowHours:     int(s.notifyCfg.EscalationWindow / time.Hour),
+		IsEnabled:       s.notifyCfg.EscalationThreshold > 0 && s.notifyCfg.EscalationWindow >= time.Hour,
+	}
+}
+
+func toSLAEscalationSettingDTO(e entities.SLAEscalationSetting, isDefault bool) dto.SLAEscalationSettingDTO {
+	result := dto.SLAEscalationSettingDTO{
+		DepartmentID:    e.DepartmentID,
+		BreachThreshold: e.BreachThreshold,
+		WindowHours:     e.WindowHours,
+		IsEnabled:       e.IsEnabled,
+		IsDefault:       isDefault,
+	}
+	if !isDefault {
+		updatedAt := e.UpdatedAt.Format(time.RFC3339)
+		result.UpdatedAt = &updatedAt
+	}
+	return result
+}
+
+func (s *SLAEscalationService) GetSettings(ctx context.Context) (*dto.SLAEscalationSettingsDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.DepartmentsView); err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.FindSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.SLAEscalationSettingsDTO{
+		Default:     toSLAEscalationSettingDTO(s.defaultRule(0), true),
+		Departments: make([]dto.SLAEscalationSettingDTO, 0, len(settings)),
+	}
+	for _, setting := range settings {
+		result.Departments = append(result.Departments, toSLAEscalationSettingDTO(setting, false))
+	}
+	return result, nil
+}
+
+func (s *SLAEscalationService) GetDepartmentSetting(ctx context.Context, departmentID uint64) (*dto.SLAEscalationSettingDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.DepartmentsView); err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.FindSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		if setting.DepartmentID == departmentID {
+			result := toSLAEscalationSettingDTO(setting, false)
+			return &result, nil
+		}
+	}
+	result := toSLAEscalationSettingDTO(s.defaultRule(departmentID), true)
+	return &result, nil
+}
+
+func (s *SLAEscalationService) UpdateDepartmentSetting(ctx context.Context, departmentID uint64, payload dto.UpdateSLAEscalationSettingDTO) (*dto.SLAEscalationSettingDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.DepartmentsUpdate)
+	if err != nil {
+		return nil, err
+	}
+	setting := &entities.SLAEscalationSetting{
+		DepartmentID:    departmentID,
+		BreachThreshold: payload.BreachThreshold,
+		WindowHours:     payload.WindowHours,
+		IsEnabled:       payload.IsEnabled == nil || *payload.IsEnabled,
+		UpdatedBy:       &authContext.Actor.ID,
+	}
+	if err := s.repo.UpsertSetting(ctx, setting); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Порог эскалации департамента изменён",
+		zap.Uint64("department_id", departmentID),
+		zap.Int("threshold", setting.BreachThreshold),
+		zap.Int("window_hours", setting.WindowHours),
+		zap.Bool("enabled", setting.IsEnabled),
+		zap.Uint64("by", authContext.Actor.ID))
+	result := toSLAEscalationSettingDTO(*setting, false)
+	return &result, nil
+}
+
+func (s *SLAEscalationService) ResetDepartmentSetting(ctx context.Context, departmentID uint64) error {
+	if _, err := s.checkPermission(ctx, authz.DepartmentsUpdate); err != nil {
+		return err
+	}
+	return s.repo.DeleteSetting(ctx, departmentID)
+}
+
+// Start проверяет нарушения раз в EscalationInterval; 0 — эскалации не отправляются.
+func (s *SLAEscalationService) Start(ctx context.Context) {
+	interval := s.notifyCfg.EscalationInterval
+	if interval <= 0 {
+		s.logger.Info("Эскалация повторных нарушений срока выключена")
+		return
+	}
+	s.logger.Info("Эскалация повторных нарушений срока запущена", zap.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Эскалация повторных нарушений срока остановлена")
+			return
+		case <-ticker.C:
+		}
+
+		escalated, err := s.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Ошибка проверки повторных нарушений срока", zap.Error(err))
+			}
+			continue
+		}
+		if escalated > 0 {
+			s.logger.Info("Отправлены эскалации руководителям", zap.Int("count", escalated))
+		}
+	}
+}
+
+func (s *SLAEscalationService) RunOnce(ctx context.Context) (int, error) {
+	now := s.now()
+	for {
+		recorded, err := s.repo.RecordBreaches(ctx, now.Add(-slaEscalationLookback), slaEscalationBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		if recorded < slaEscalationBatchSize {
+			break
+		}
+	}
+
+	settings, err := s.repo.FindSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rules := make(map[uint64]entities.SLAEscalationSetting, len(settings))
+	longest := s.notifyCfg.EscalationWindow
+	for _, setting := range settings {
+		rules[setting.DepartmentID] = setting
+		if window := time.Duration(setting.WindowHours) * time.Hour; setting.IsEnabled && window > longest {
+			longest = window
+		}
+	}
+	if longest <= 0 {
+		return 0, nil
+	}
+
+	// Нарушения приходят по исполнителям и по возрастанию срока.
+	breaches, err := s.repo.FindPendingBreaches(ctx, now.Add(-longest))
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for start := 0; start < len(breaches); {
+		end := start + 1
+		for end < len(breaches) && breaches[end].ExecutorID == breaches[start].ExecutorID {
+			end++
+		}
+		group := breaches[start:end]
+		start = end
+
+		departmentID := group[0].ExecutorDepartmentID
+		if departmentID == nil {
+			continue
+		}
+		rule, ok := rules[*departmentID]
+		if !ok {
+			rule = s.defaultRule(*departmentID)
+		}
+		if !rule.IsEnabled || rule.BreachThreshold <= 0 {
+			continue
+		}
+		cutoff := now.Add(-time.Duration(rule.WindowHours) * time.Hour)
+		recent := group
+		for len(recent) > 0 && recent[0].Duration.Before(cutoff) {
+			recent = recent[1:]
+		}
+		if len(recent) < rule.BreachThreshold {
+			continue
+		}
+
+		sent, err := s.escalate(ctx, *departmentID, rule, recent, now)
+		if err != nil {
+			return escalated, err
+		}
+		if sent {
+			escalated++
+		}
+	}
+	return escalated, nil
+}
+
+// escalate записывает эскалацию и только затем ставит уведомления в очередь: сбой отправки
+// не должен приводить к повторной эскалации тех же нарушений на следующей проверке.
+func (s *SLAEscalationService) escalate(ctx context.Context, departmentID uint64, rule entities.SLAEscalationSetting, breaches []entities.SLABreach, now time.Time) (bool, error) {
+	executor := breaches[0]
+	heads, err := s.userRepo.FindUnitHeads(ctx, departmentID, executor.ExecutorOtdelID)
+	if err != nil {
+		return false, err
+	}
+	recipients := make([]entities.User, 0, len(heads))
+	escalation := &entities.SLAEscalation{
+		ExecutorID:   executor.ExecutorID,
+		DepartmentID: departmentID,
+		BreachCount:  len(breaches),
+		LastBreachAt: breaches[len(breaches)-1].Duration,
+		NotifiedIDs:  []uint64{},
+	}
+	for _, head := range heads {
+		if head.ID == executor.ExecutorID {
+			continue
+		}
+		recipients = append(recipients, head)
+		escalation.NotifiedIDs = append(escalation.NotifiedIDs, head.ID)
+	}
+
+	created, err := s.repo.CreateEscalation(ctx, escalation)
+	if err != nil || !created {
+		return false, err
+	}
+	if len(recipients) == 0 {
+		s.logger.Warn("Некому отправить эскалацию: у подразделения исполнителя нет руководителя",
+			zap.Uint64("executor_id", executor.ExecutorID), zap.Uint64("department_id", departmentID))
+		return true, nil
+	}
+
+	var calendar *businesshours.Calendar
+	if s.calendar != nil {
+		calendar = s.calendar.Calendar(ctx)
+	}
+	delays := make([]time.Duration, len(breaches))
+	for i, breach := range breaches {
+		end := now
+		if breach.CompletedAt != nil && breach.CompletedAt.After(breach.Duration) {
+			end = *breach.CompletedAt
+		}
+		delays[i] = calendar.Between(breach.Duration, end)
+	}
+
+	for i := range recipients {
+		s.notifyHead(ctx, &recipients[i], rule.WindowHours, breaches, delays, now)
+	}
+	s.logger.Info("Эскалация повторных нарушений срока отправлена",
+		zap.Uint64("executor_id", executor.ExecutorID),
+		zap.Uint64("department_id", departmentID),
+		zap.Int("breaches", len(breaches)),
+		zap.Uint64s("heads", escalation.NotifiedIDs))
+	return true, nil
+}
+
+func (s *SLAEscalationService) notifyHead(ctx context.Context, head *entities.User, windowHours int, breaches []entities.SLABreach, delays []time.Duration, now time.Time) {
+	lang := head.Language
+	executorName := breaches[0].ExecutorName
+	// Свежие нарушения важнее: в сводку попадают последние slaEscalationMaxOrders.
+	shown := breaches
+	shownDelays := delays
+	if len(shown) > slaEscalationMaxOrders {
+		shown = shown[len(shown)-slaEscalationMaxOrders:]
+		shownDelays = shownDelays[len(shownDelays)-slaEscalationMaxOrders:]
+	}
+
+	if s.notifyCfg.TelegramActive() && head.TelegramChatID.Valid {
+		escape := telegram.EscapeTextForMarkdownV2
+		var sb strings.Builder
+		sb.WriteString(i18n.T(lang, "notify.escalation_header", escape(executorName), len(breaches), windowHours))
+		for i, breach := range shown {
+			link := fmt.Sprintf("%s/orders/%d", s.frontendBaseURL, breach.OrderID)
+			sb.WriteString("\n")
+			sb.WriteString(i18n.T(lang, "notify.escalation_order", escape(breach.DisplayNumber()), link,
+				escape(breach.OrderName), formatEscalationDelay(lang, shownDelays[i])))
+		}
+		if hidden := len(breaches) - len(shown); hidden > 0 {
+			sb.WriteString(fmt.Sprintf("\n… \\+%d", hidden))
+		}
+		if err := s.outbox.EnqueueTelegram(ctx, head.ID, head.TelegramChatID.Int64, sb.String()); err != nil {
+			s.logger.Error("Не удалось поставить в очередь эскалацию в Telegram", zap.Uint64("userID", head.ID), zap.Error(err))
+		}
+	}
+
+	escape := sanitize.HTML
+	latest := breaches[len(breaches)-1]
+	payload := &websocket.NotificationPayload{
+		EventID:   uuid.New().String(),
+		Type:      "SLA_ESCALATION",
+		Actor:     websocket.ActorInfo{Name: executorName},
+		Message:   i18n.T(lang, "notify.ws.escalation", escape(executorName), len(breaches), windowHours),
+		Links:     websocket.LinkInfo{Primary: fmt.Sprintf("/orders/%d", latest.OrderID)},
+		CreatedAt: now,
+	}
+	for i, breach := range shown {
+		payload.Changes = append(payload.Changes, websocket.ChangeInfo{
+			Type: "SLA_BREACH",
+			Text: i18n.T(lang, "notify.ws.escalation_order", escape(breach.DisplayNumber()), escape(breach.OrderName),
+				formatEscalationDelay(lang, shownDelays[i])),
+		})
+	}
+	if err := s.notificationCenter.Save(ctx, head.ID, &latest.OrderID, payload); err != nil {
+		s.logger.Error("Не удалось сохранить эскалацию в центр уведомлений", zap.Uint64("userID", head.ID), zap.Error(err))
+	}
+	if !s.notifyCfg.WebSocketActive() {
+		return
+	}
+	if err := s.outbox.EnqueueWebSocket(ctx, head.ID, payload, "notification"); err != nil {
+		s.logger.Error("Не удалось поставить в очередь WebSocket-эскалацию", zap.Uint64("userID", head.ID), zap.Error(err))
+	}
+}
+
+// formatEscalationDelay — просрочка в часах и минутах; время считается по рабочему календарю.
+func formatEscalationDelay(lang string, d time.Duration) string {
+	d = d.Round(time.Minute)
+	return i18n.T(lang, "notify.duration", int(d.Hours()), int(d.Minutes())%60)
+}
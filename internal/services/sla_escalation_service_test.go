@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/websocket"
+)
+
+type escalationRepoStub struct {
+	repositories.SLAEscalationRepositoryInterface
+	settings    []entities.SLAEscalationSetting
+	breaches    []entities.SLABreach
+	duplicate   bool
+	escalations []entities.SLAEscalation
+}
+
+func (s *escalationRepoStub) RecordBreaches(context.Context, time.Time, int) (int, error) {
+	return 0, nil
+}
+
+func (s *escalationRepoStub) FindSettings(context.Context) ([]entities.SLAEscalationSetting, error) {
+	return s.settings, nil
+}
+
+func (s *escalationRepoStub) FindPendingBreaches(_ context.Context, since time.Time) ([]entities.SLABreach, error) {
+	var result []entities.SLABreach
+	for _, b := range s.breaches {
+		if !b.Duration.Before(since) {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func (s *escalationRepoStub) CreateEscalation(_ context.Context, e *entities.SLAEscalation) (bool, error) {
+	if s.duplicate {
+		return false, nil
+	}
+	s.escalations = append(s.escalations, *e)
+	return true, nil
+}
+
+type escalationUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	heads map[uint64][]entities.User
+}
+
+func (s *escalationUserRepoStub) FindUnitHeads(_ context.Context, departmentID uint64, _ *uint64) ([]entities.User, error) {
+	return s.heads[departmentID], nil
+}
+
+type escalationOutboxStub struct {
+	NotificationOutboxServiceInterface
+	telegram map[uint64][]string
+}
+
+func (s *escalationOutboxStub) EnqueueTelegram(_ context.Context, userID uint64, _ int64, text string) error {
+	s.telegram[userID] = append(s.telegram[userID], text)
+	return nil
+}
+
+func (s *escalationOutboxStub) EnqueueWebSocket(context.Context, uint64, interface{}, string) error {
+	return nil
+}
+
+type escalationCenterStub struct {
+	NotificationCenterServiceInterface
+	saved map[uint64]int
+}
+
+func (s *escalationCenterStub) Save(_ context.Context, userID uint64, _ *uint64, _ *websocket.NotificationPayload) error {
+	s.saved[userID]++
+	return nil
+}
+
+func TestSLAEscalationUsesDepartmentThresholds(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	id := func(v uint64) *uint64 { return &v }
+	breach := func(orderID, executorID, departmentID uint64, ago time.Duration) entities.SLABreach {
+		return entities.SLABreach{OrderID: orderID, OrderName: "Принтер", Duration: now.Add(-ago),
+			ExecutorID: executorID, ExecutorName: "Исполнитель", ExecutorDepartmentID: id(departmentID)}
+	}
+	chat := sql.NullInt64{Int64: 500, Valid: true}
+
+	repo := &escalationRepoStub{
+		settings: []entities.SLAEscalationSetting{
+			{DepartmentID: 2, BreachThreshold: 5, WindowHours: 168, IsEnabled: true},
+			{DepartmentID: 3, BreachThreshold: 2, WindowHours: 3, IsEnabled: true},
+			{DepartmentID: 4, BreachThreshold: 1, WindowHours: 168, IsEnabled: false},
+		},
+		breaches: []entities.SLABreach{
+			// Департамент 1 без своей настройки: порог по умолчанию 3 за неделю.
+			breach(1, 7, 1, 100*time.Hour), breach(2, 7, 1, 30*time.Hour), breach(3, 7, 1, time.Hour),
+			// Департамент 2: свой порог 5, трёх нарушений мало.
+			breach(4, 8, 2, 10*time.Hour), breach(5, 8, 2, 5*time.Hour), breach(6, 8, 2, time.Hour),
+			// Департамент 3: окно 3 часа, в него попадает одно нарушение из двух.
+			breach(7, 9, 3, 20*time.Hour), breach(8, 9, 3, time.Hour),
+			// Департамент 4: эскалация выключена.
+			breach(9, 11, 4, time.Hour),
+		},
+	}
+	heads := map[uint64][]entities.User{
+		1: {{ID: 7, TelegramChatID: chat}, {ID: 20, TelegramChatID: chat}},
+		2: {{ID: 21, TelegramChatID: chat}},
+		3: {{ID: 22, TelegramChatID: chat}},
+		4: {{ID: 23, TelegramChatID: chat}},
+	}
+	outbox := &escalationOutboxStub{telegram: map[uint64][]string{}}
+	center := &escalationCenterStub{saved: map[uint64]int{}}
+	service := &SLAEscalationService{
+		repo:               repo,
+		userRepo:           &escalationUserRepoStub{heads: heads},
+		outbox:             outbox,
+		notificationCenter: center,
+		notifyCfg: config.NotificationsConfig{TelegramEnabled: true, EscalationThreshold: 3,
+			EscalationWindow: 168 * time.Hour},
+		frontendBaseURL: "https://sd.example",
+		logger:          zap.NewNop(),
+		now:             func() time.Time { return now },
+	}
+
+	escalated, err := service.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if escalated != 1 || len(repo.escalations) != 1 {
+		t.Fatalf("escalated = %d (%d recorded), want 1", escalated, len(repo.escalations))
+	}
+	got := repo.escalations[0]
+	if got.ExecutorID != 7 || got.DepartmentID != 1 || got.BreachCount != 3 || !got.LastBreachAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("unexpected escalation %+v", got)
+	}
+	// Руководитель, сам нарушивший сроки, о себе не уведомляется.
+	if len(got.NotifiedIDs) != 1 || got.NotifiedIDs[0] != 20 {
+		t.Errorf("notified = %v, want [20]", got.NotifiedIDs)
+	}
+	if len(outbox.telegram) != 1 || len(outbox.telegram[20]) != 1 || center.saved[20] != 1 {
+		t.Fatalf("only head 20 must be notified: telegram %v, center %v", outbox.telegram, center.saved)
+	}
+	if text := outbox.telegram[20][0]; !strings.Contains(text, "https://sd.example/orders/3") || !strings.Contains(text, "1 ч 0 мин") {
+		t.Errorf("summary lacks the order link or delay: %q", text)
+	}
+
+	// Вторая реплика записала ту же эскалацию раньше — повторно не уведомляем.
+	repo.duplicate = true
+	outbox.telegram = map[uint64][]string{}
+	if escalated, err := service.RunOnce(context.Background()); err != nil || escalated != 0 || len(outbox.telegram) != 0 {
+		t.Fatalf("a duplicate escalation must be skipped: %d, %v, %v", escalated, err, outbox.telegram)
+	}
+}
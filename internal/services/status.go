@@ -31,6 +31,7 @@ type StatusServiceInterface interface {
 
 type StatusService struct {
 	repo        repositories.StatusRepositoryInterface
+	directory   StatusDirectoryInterface
 	userRepo    repositories.UserRepositoryInterface
 	fileStorage filestorage.FileStorageInterface
 	logger      *zap.Logger
@@ -38,11 +39,12 @@ type StatusService struct {
 
 func NewStatusService(
 	repo repositories.StatusRepositoryInterface,
+	directory StatusDirectoryInterface,
 	userRepo repositories.UserRepositoryInterface,
 	fileStorage filestorage.FileStorageInterface,
 	logger *zap.Logger,
 ) StatusServiceInterface {
-	return &StatusService{repo: repo, userRepo: userRepo, fileStorage: fileStorage, logger: logger}
+	return &StatusService{repo: repo, directory: directory, userRepo: userRepo, fileStorage: fileStorage, logger: logger}
 }
 
 func statusEntityToDTO(entity *entities.Status) *dto.StatusDTO {
@@ -77,7 +79,7 @@ func (s *StatusService) buildAuthzContext(ctx context.Context) (*authz.Context,
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
-	return &authz.Context{Actor: actor, Permissions: permissionsMap, Target: nil}, nil
+	return &authz.Context{Actor: actor, Permissions: permissionsMap, Conditions: utils.GetPermissionConditionsFromCtx(ctx), Target: nil}, nil
 }
 
 func (s *StatusService) GetStatuses(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.StatusDTO], error) {
@@ -186,7 +188,12 @@ func (s *StatusService) CreateStatus(
 		bigIconPath = urlPrefix + path
 	}
 
-	return s.repo.CreateStatus(ctx, createDTO, smallIconPath, bigIconPath)
+	created, err := s.repo.CreateStatus(ctx, createDTO, smallIconPath, bigIconPath)
+	if err != nil {
+		return nil, err
+	}
+	s.directory.Invalidate(ctx)
+	return created, nil
 }
 
 func (s *StatusService) UpdateStatus(ctx context.Context, id uint64, updateDTO dto.UpdateStatusDTO, iconSmallHeader, iconBigHeader *multipart.FileHeader) (*dto.StatusDTO, error) {
@@ -234,7 +241,12 @@ func (s *StatusService) UpdateStatus(ctx context.Context, id uint64, updateDTO d
 		fullPath := urlPrefix + path
 		bigIconPath = &fullPath
 	}
-	return s.repo.UpdateStatus(ctx, id, updateDTO, smallIconPath, bigIconPath)
+	updated, err := s.repo.UpdateStatus(ctx, id, updateDTO, smallIconPath, bigIconPath)
+	if err != nil {
+		return nil, err
+	}
+	s.directory.Invalidate(ctx)
+	return updated, nil
 }
 
 func (s *StatusService) DeleteStatus(ctx context.Context, id uint64) error {
@@ -245,5 +257,9 @@ func (s *StatusService) DeleteStatus(ctx context.Context, id uint64) error {
 	if !authz.CanDo(authz.StatusesDelete, *authContext) {
 		return apperrors.ErrForbidden
 	}
-	return s.repo.DeleteStatus(ctx, id)
+	if err := s.repo.DeleteStatus(ctx, id); err != nil {
+		return err
+	}
+	s.directory.Invalidate(ctx)
+	return nil
 }
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+const (
+	// statusDirectoryChannel — канал Redis, по которому реплики узнают об изменении справочника статусов.
+	statusDirectoryChannel = "statuses:changed"
+	// statusDirectoryTTL — страховка на случай потерянного сообщения Redis.
+	statusDirectoryTTL = 10 * time.Minute
+	// statusDirectoryMissReload — не чаще этого справочник перечитывается из-за неизвестного ID или кода.
+	statusDirectoryMissReload = 5 * time.Second
+)
+
+// StatusDirectoryInterface — справочник статусов в памяти процесса для бота, слушателей и OrderService.
+type StatusDirectoryInterface interface {
+	List(ctx context.Context) ([]entities.Status, error)
+	// FindByID и FindByCode возвращают ErrNotFound, если статуса нет.
+	FindByID(ctx context.Context, id uint64) (*entities.Status, error)
+	FindByCode(ctx context.Context, code string) (*entities.Status, error)
+	// Invalidate сбрасывает справочник на этой реплике и оповещает остальные через Redis.
+	Invalidate(ctx context.Context)
+	// Start слушает оповещения других реплик до отмены ctx.
+	Start(ctx context.Context)
+}
+
+// StatusDirectory держит все статусы в памяти: они читаются почти при каждом действии в боте
+// и при каждом событии заявки, а меняются редко.
+type StatusDirectory struct {
+	repo   repositories.StatusRepositoryInterface
+	redis  *redis.Client
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	statuses []entities.Status
+	byID     map[uint64]int
+	byCode   map[string]int
+	loadedAt time.Time
+}
+
+func NewStatusDirectory(repo repositories.StatusRepositoryInterface, redisClient *redis.Client, logger *zap.Logger) StatusDirectoryInterface {
+	return &StatusDirectory{repo: repo, redis: redisClient, logger: logger}
+}
+
+func (d *StatusDirectory) List(ctx context.Context) ([]entities.Status, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.ensureLoaded(ctx, statusDirectoryTTL); err != nil {
+		return nil, err
+	}
+	return append([]entities.Status(nil), d.statuses...), nil
+}
+
+func (d *StatusDirectory) FindByID(ctx context.Context, id uint64) (*entities.Status, error) {
+	return d.find(ctx, func() (int, bool) {
+		i, ok := d.byID[id]
+		return i, ok
+	})
+}
+
+func (d *StatusDirectory) FindByCode(ctx context.Context, code string) (*entities.Status, error) {
+	return d.find(ctx, func() (int, bool) {
+		i, ok := d.byCode[code]
+		return i, ok
+	})
+}
+
+// find при промахе один раз перечитывает справочник: статус могли создать на другой реплике,
+// а оповещение ещё не дошло.
+func (d *StatusDirectory) find(ctx context.Context, lookup func() (int, bool)) (*entities.Status, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.ensureLoaded(ctx, statusDirectoryTTL); err != nil {
+		return nil, err
+	}
+	i, ok := lookup()
+	if !ok {
+		if err := d.ensureLoaded(ctx, statusDirectoryMissReload); err != nil {
+			return nil, err
+		}
+		if i, ok = lookup(); !ok {
+			return nil, apperrors.ErrNotFound
+		}
+	}
+	status := d.statuses[i]
+	return &status, nil
+}
+
+// ensureLoaded перечитывает справочник, если он старше maxAge. Если база недоступна, остаётся
+// прежний справочник; ошибка возвращается, только когда его ещё не было.
+func (d *StatusDirectory) ensureLoaded(ctx context.Context, maxAge time.Duration) error {
+	if d.statuses != nil && time.Since(d.loadedAt) < maxAge {
+		return nil
+	}
+	statuses, err := d.repo.FindAll(ctx)
+	if err != nil {
+		if d.statuses == nil {
+			return err
+		}
+		d.logger.Warn("Не удалось перечитать справочник статусов, используется прежний", zap.Error(err))
+		return nil
+	}
+
+	d.statuses = statuses
+	d.byID = make(map[uint64]int, len(statuses))
+	d.byCode = make(map[string]int, len(statuses))
+	for i, status := range statuses {
+		d.byID[status.ID] = i
+		if status.Code != nil {
+			d.byCode[*status.Code] = i
+		}
+	}
+	d.loadedAt = time.Now()
+	return nil
+}
+
+func (d *StatusDirectory) reset() {
+	d.mu.Lock()
+	d.loadedAt = time.Time{}
+	d.mu.Unlock()
+}
+
+func (d *StatusDirectory) Invalidate(ctx context.Context) {
+	d.reset()
+	if d.redis == nil {
+		return
+	}
+	if err := d.redis.Publish(ctx, statusDirectoryChannel, "").Err(); err != nil {
+		d.logger.Warn("Не удалось оповестить реплики об изменении статусов", zap.Error(err))
+	}
+}
+
+func (d *StatusDirectory) Start(ctx context.Context) {
+	if d.redis == nil {
+		return
+	}
+	for ctx.Err() == nil {
+		pubsub := d.redis.Subscribe(ctx, statusDirectoryChannel)
+		// Пока подписки не было, оповещения могли потеряться
+		d.reset()
+		for range pubsub.Channel() {
+			d.reset()
+		}
+		_ = pubsub.Close()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	pkgconstants "request-system/pkg/constants"
+	apperrors "request-system/pkg/errors"
+)
+
+type statusDirectoryRepoStub struct {
+	repositories.StatusRepositoryInterface
+	statuses []entities.Status
+	loads    int
+	failing  bool
+}
+
+func (s *statusDirectoryRepoStub) FindAll(context.Context) ([]entities.Status, error) {
+	s.loads++
+	if s.failing {
+		return nil, errors.New("db is down")
+	}
+	return append([]entities.Status(nil), s.statuses...), nil
+}
+
+func testStatus(id uint64, code string) entities.Status {
+	return entities.Status{ID: id, Name: code, Code: &code}
+}
+
+func TestStatusDirectory_ReadsOnceUntilInvalidated(t *testing.T) {
+	repo := &statusDirectoryRepoStub{statuses: []entities.Status{testStatus(1, pkgconstants.StatusOpen), testStatus(2, pkgconstants.StatusClosed)}}
+	directory := NewStatusDirectory(repo, nil, zap.NewNop())
+	ctx := context.Background()
+
+	if status, err := directory.FindByID(ctx, 2); err != nil || *status.Code != pkgconstants.StatusClosed {
+		t.Fatalf("FindByID(2) = %v, %v", status, err)
+	}
+	if status, err := directory.FindByCode(ctx, pkgconstants.StatusOpen); err != nil || status.ID != 1 {
+		t.Fatalf("FindByCode(OPEN) = %v, %v", status, err)
+	}
+	if list, err := directory.List(ctx); err != nil || len(list) != 2 || repo.loads != 1 {
+		t.Fatalf("List = %v, %v; loads = %d, want a single read", list, err, repo.loads)
+	}
+
+	status, _ := directory.FindByID(ctx, 1)
+	status.Name = "изменён вызывающим"
+	if again, _ := directory.FindByID(ctx, 1); again.Name != pkgconstants.StatusOpen {
+		t.Error("callers must get copies, not the cached status")
+	}
+
+	repo.statuses = append(repo.statuses, testStatus(3, pkgconstants.StatusDuplicate))
+	directory.Invalidate(ctx)
+	if status, err := directory.FindByCode(ctx, pkgconstants.StatusDuplicate); err != nil || status.ID != 3 || repo.loads != 2 {
+		t.Errorf("after Invalidate: %v, %v; loads = %d", status, err, repo.loads)
+	}
+}
+
+func TestStatusDirectory_MissesAndFailures(t *testing.T) {
+	repo := &statusDirectoryRepoStub{statuses: []entities.Status{testStatus(1, pkgconstants.StatusOpen)}}
+	directory := NewStatusDirectory(repo, nil, zap.NewNop()).(*StatusDirectory)
+	ctx := context.Background()
+
+	if _, err := directory.FindByID(ctx, 9); !errors.Is(err, apperrors.ErrNotFound) || repo.loads != 1 {
+		t.Fatalf("a fresh directory must not reread on a miss: %v, loads = %d", err, repo.loads)
+	}
+
+	// Статус создали на другой реплике, а оповещение не дошло
+	repo.statuses = append(repo.statuses, testStatus(9, pkgconstants.StatusService))
+	directory.loadedAt = time.Now().Add(-statusDirectoryMissReload)
+	if status, err := directory.FindByID(ctx, 9); err != nil || status.ID != 9 || repo.loads != 2 {
+		t.Fatalf("a stale miss must reread: %v, %v; loads = %d", status, err, repo.loads)
+	}
+
+	repo.failing = true
+	directory.Invalidate(ctx)
+	if status, err := directory.FindByID(ctx, 1); err != nil || status.ID != 1 {
+		t.Errorf("a failed reread must keep the previous statuses: %v, %v", status, err)
+	}
+	if _, err := NewStatusDirectory(repo, nil, zap.NewNop()).List(ctx); err == nil {
+		t.Error("without previous statuses the error must be returned")
+	}
+}
@@ -2,13 +2,15 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 
 	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
 	"request-system/internal/sync"
 )
 
@@ -25,59 +27,237 @@ func LoggerFromContext(ctx context.Context) *zap.Logger {
 	return zap.L()
 }
 
+const (
+	SyncSource1C = "1c"
+	// SyncSource1CPull — задания периодической загрузки из 1С; у них свои курсоры.
+	SyncSource1CPull = "1c_pull"
+
+	syncPollInterval      = 5 * time.Second
+	syncHeartbeatInterval = 5 * time.Second
+	// Задание без heartbeat дольше этого времени считается брошенным и запускается заново.
+	syncStaleAfter = 2 * time.Minute
+)
+
 type SyncServiceInterface interface {
-	Enqueue1CReferences(ctx context.Context, payload dto.Webhook1CPayloadDTO) (bool, error)
+	Enqueue1CReferences(ctx context.Context, payload dto.Webhook1CPayloadDTO, opts SyncJobOptions) (*dto.SyncJobDTO, error)
+	GetJob(ctx context.Context, id uint64) (*dto.SyncJobDTO, error)
 	Process1CReferences(ctx context.Context, payload dto.Webhook1CPayloadDTO) error
+	StartWorker(ctx context.Context)
+	// StartPuller раз в interval забирает изменения справочников из source и ставит их в очередь.
+	StartPuller(ctx context.Context, source sync.PullSourceInterface, interval time.Duration)
+}
+
+// SyncJobOptions — режимы задания: DryRun только показывает изменения, Snapshot считает выгрузку
+// полной и деактивирует отсутствующие в ней записи 1С, BestEffort сохраняет всё, кроме ошибочных записей.
+type SyncJobOptions struct {
+	DryRun     bool
+	Snapshot   bool
+	BestEffort bool
 }
 
+// SyncService ставит выгрузки 1С в очередь sync_jobs и обрабатывает их по одной в фоновом воркере.
 type SyncService struct {
 	handler sync.HandlerInterface
+	jobRepo repositories.SyncJobRepositoryInterface
 	logger  *zap.Logger
-	running atomic.Bool
-	runSeq  atomic.Uint64
+	wakeup  chan struct{}
 }
 
-func NewSyncService(handler sync.HandlerInterface, logger *zap.Logger) SyncServiceInterface {
+func NewSyncService(handler sync.HandlerInterface, jobRepo repositories.SyncJobRepositoryInterface, logger *zap.Logger) SyncServiceInterface {
 	return &SyncService{
 		handler: handler,
+		jobRepo: jobRepo,
 		logger:  logger.Named("sync_1c"),
+		wakeup:  make(chan struct{}, 1),
+	}
+}
+
+func (s *SyncService) Enqueue1CReferences(ctx context.Context, payload dto.Webhook1CPayloadDTO, opts SyncJobOptions) (*dto.SyncJobDTO, error) {
+	return s.enqueue(ctx, entities.SyncJob{
+		Source:     SyncSource1C,
+		DryRun:     opts.DryRun,
+		Snapshot:   opts.Snapshot,
+		BestEffort: opts.BestEffort,
+	}, payload)
+}
+
+func (s *SyncService) enqueue(ctx context.Context, job entities.SyncJob, payload dto.Webhook1CPayloadDTO) (*dto.SyncJobDTO, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = raw
+	created, err := s.jobRepo.Create(ctx, job)
+	if err != nil {
+		return nil, err
 	}
+
+	s.logger.Info("Синхронизация 1С поставлена в очередь", append([]zap.Field{
+		zap.Uint64("job_id", created.ID),
+		zap.String("source", job.Source),
+		zap.Bool("dry_run", job.DryRun),
+		zap.Bool("snapshot", job.Snapshot),
+		zap.Bool("best_effort", job.BestEffort),
+	}, syncPayloadFields(payload)...)...)
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+	return toSyncJobDTO(created), nil
 }
 
-func (s *SyncService) Enqueue1CReferences(ctx context.Context, payload dto.Webhook1CPayloadDTO) (bool, error) {
-	_ = ctx
+func (s *SyncService) GetJob(ctx context.Context, id uint64) (*dto.SyncJobDTO, error) {
+	job, err := s.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSyncJobDTO(job), nil
+}
 
-	if !s.running.CompareAndSwap(false, true) {
-		s.logger.Warn("Синхронизация 1С уже выполняется, новый запуск отклонен", syncPayloadFields(payload)...)
-		return false, nil
+func toSyncJobDTO(job *entities.SyncJob) *dto.SyncJobDTO {
+	result := &dto.SyncJobDTO{
+		ID:         job.ID,
+		Source:     job.Source,
+		Status:     job.Status,
+		DryRun:     job.DryRun,
+		Snapshot:   job.Snapshot,
+		BestEffort: job.BestEffort,
+		Progress:   map[string]dto.SyncEntityStatsDTO{},
+		Errors:     []dto.SyncRecordErrorDTO{},
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt.Format(time.RFC3339),
+	}
+	_ = json.Unmarshal(job.Progress, &result.Progress)
+	_ = json.Unmarshal(job.Summary, &result.Summary)
+	_ = json.Unmarshal(job.Errors, &result.Errors)
+	_ = json.Unmarshal(job.Changes, &result.Changes)
+	_ = json.Unmarshal(job.Deactivated, &result.Deactivated)
+	if job.StartedAt != nil {
+		startedAt := job.StartedAt.Format(time.RFC3339)
+		result.StartedAt = &startedAt
 	}
+	if job.FinishedAt != nil {
+		finishedAt := job.FinishedAt.Format(time.RFC3339)
+		result.FinishedAt = &finishedAt
+	}
+	return result
+}
 
-	runID := s.runSeq.Add(1)
-	fields := append([]zap.Field{zap.Uint64("run_id", runID)}, syncPayloadFields(payload)...)
-	s.logger.Info("Запуск синхронизации 1С принят в обработку", fields...)
+func (s *SyncService) StartWorker(ctx context.Context) {
+	s.logger.Info("Воркер заданий синхронизации запущен")
+	ticker := time.NewTicker(syncPollInterval)
+	defer ticker.Stop()
 
-	go s.run1CReferences(runID, payload)
-	return true, nil
+	for {
+		for {
+			job, err := s.jobRepo.ClaimNext(ctx, syncStaleAfter)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Error("Не удалось взять задание синхронизации", zap.Error(err))
+				}
+				break
+			}
+			if job == nil {
+				break
+			}
+			s.runJob(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Воркер заданий синхронизации остановлен")
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+	}
 }
 
-func (s *SyncService) run1CReferences(runID uint64, payload dto.Webhook1CPayloadDTO) {
+func (s *SyncService) runJob(job *entities.SyncJob) {
 	startedAt := time.Now()
-	logger := s.logger.With(append([]zap.Field{zap.Uint64("run_id", runID)}, syncPayloadFields(payload)...)...)
-	bgCtx := NewWebhookContext(logger)
-	defer s.running.Store(false)
+	logger := s.logger.With(zap.Uint64("job_id", job.ID), zap.Bool("dry_run", job.DryRun), zap.Bool("snapshot", job.Snapshot), zap.Bool("best_effort", job.BestEffort))
 
+	var payload dto.Webhook1CPayloadDTO
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		logger.Error("Не удалось прочитать выгрузку задания синхронизации", zap.Error(err))
+		s.finishJob(job, sync.NewReport(), err, logger)
+		return
+	}
+	logger = logger.With(syncPayloadFields(payload)...)
 	logger.Info("Фоновая синхронизация 1С запущена")
 
-	if err := s.Process1CReferences(bgCtx, payload); err != nil {
-		logger.Error(
-			"Фоновая синхронизация 1С завершилась с ошибкой",
-			zap.Duration("duration", time.Since(startedAt)),
-			zap.Error(err),
-		)
-		return
+	report := sync.NewReport()
+	if job.DryRun {
+		report = sync.NewDryRunReport()
+	}
+	ctx := sync.WithReport(NewWebhookContext(logger), report)
+	if job.Snapshot {
+		ctx = sync.WithSnapshot(ctx)
+	}
+	if job.BestEffort {
+		ctx = sync.WithBestEffort(ctx)
+	}
+
+	// Прогресс пишется в задание по таймеру: обработчик держит транзакцию всё время работы.
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(syncHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.jobRepo.SaveProgress(context.Background(), job.ID, marshalSyncReport(report)); err != nil {
+					logger.Warn("Не удалось сохранить прогресс синхронизации", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	var err error
+	if job.DryRun {
+		err = s.handler.DryRun(ctx, func(ctx context.Context) error {
+			return s.Process1CReferences(ctx, payload)
+		})
+	} else {
+		err = s.Process1CReferences(ctx, payload)
+	}
+	close(done)
+
+	if err != nil {
+		logger.Error("Фоновая синхронизация 1С завершилась с ошибкой", zap.Duration("duration", time.Since(startedAt)), zap.Error(err))
+	} else {
+		logger.Info("Фоновая синхронизация 1С завершена успешно", zap.Duration("duration", time.Since(startedAt)))
+	}
+	s.finishJob(job, report, err, logger)
+}
+
+func (s *SyncService) finishJob(job *entities.SyncJob, report *sync.Report, jobErr error, logger *zap.Logger) {
+	status := entities.SyncJobCompleted
+	var errMsg *string
+	if jobErr != nil {
+		status = entities.SyncJobFailed
+		msg := jobErr.Error()
+		errMsg = &msg
 	}
+	if err := s.jobRepo.Finish(context.Background(), job.ID, status, marshalSyncReport(report), errMsg); err != nil {
+		logger.Error("Не удалось сохранить итог задания синхронизации", zap.Error(err))
+	}
+	if status == entities.SyncJobCompleted {
+		s.commitCursors(job, report, logger)
+	}
+}
 
-	logger.Info("Фоновая синхронизация 1С завершена успешно", zap.Duration("duration", time.Since(startedAt)))
+func marshalSyncReport(report *sync.Report) entities.SyncJobResult {
+	progress, errs, summary := report.Snapshot()
+	var result entities.SyncJobResult
+	result.Progress, _ = json.Marshal(progress)
+	result.Errors, _ = json.Marshal(errs)
+	result.Summary, _ = json.Marshal(summary)
+	result.Changes, _ = json.Marshal(report.Changes())
+	result.Deactivated, _ = json.Marshal(report.Deactivations())
+	return result
 }
 
 func (s *SyncService) Process1CReferences(ctx context.Context, payload dto.Webhook1CPayloadDTO) error {
@@ -142,3 +322,107 @@ func syncPayloadFields(payload dto.Webhook1CPayloadDTO) []zap.Field {
 		zap.Int("users", len(payload.Users)),
 	}
 }
+
+// pullEntities — порядок загрузки совпадает с порядком обработки: сначала структура, потом пользователи.
+var pullEntities = []string{
+	sync.EntityDepartments,
+	sync.EntityBranches,
+	sync.EntityOtdels,
+	sync.EntityOffices,
+	sync.EntityPositions,
+	sync.EntityUsers,
+}
+
+func (s *SyncService) StartPuller(ctx context.Context, source sync.PullSourceInterface, interval time.Duration) {
+	s.logger.Info("Периодическая загрузка из 1С запущена", zap.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.pullOnce(ctx, source); err != nil && ctx.Err() == nil {
+			s.logger.Error("Не удалось загрузить изменения из 1С", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Периодическая загрузка из 1С остановлена")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pullOnce забирает изменения после сохранённых курсоров и ставит их одним заданием. Новые курсоры
+// едут в задании и сохраняются только после его успешного завершения, поэтому упавшая загрузка
+// повторится с тех же отметок.
+func (s *SyncService) pullOnce(ctx context.Context, source sync.PullSourceInterface) error {
+	pending, err := s.jobRepo.HasPending(ctx, SyncSource1CPull)
+	if err != nil {
+		return err
+	}
+	if pending {
+		s.logger.Debug("Предыдущая загрузка из 1С ещё не обработана, пропуск")
+		return nil
+	}
+
+	cursors, err := s.jobRepo.FindCursors(ctx, SyncSource1CPull)
+	if err != nil {
+		return err
+	}
+
+	var payload dto.Webhook1CPayloadDTO
+	targets := map[string]any{
+		sync.EntityDepartments: &payload.Departments,
+		sync.EntityBranches:    &payload.Branches,
+		sync.EntityOtdels:      &payload.Otdels,
+		sync.EntityOffices:     &payload.Offices,
+		sync.EntityPositions:   &payload.Positions,
+		sync.EntityUsers:       &payload.Users,
+	}
+	newCursors := make(map[string]string)
+	total := 0
+	for _, entity := range pullEntities {
+		items, cursor, err := source.FetchChanges(ctx, entity, cursors[entity])
+		if err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			raw, _ := json.Marshal(items)
+			if err := json.Unmarshal(raw, targets[entity]); err != nil {
+				return fmt.Errorf("записи справочника %s из 1С не соответствуют формату выгрузки: %w", entity, err)
+			}
+			total += len(items)
+		}
+		if cursor != "" && cursor != cursors[entity] {
+			newCursors[entity] = cursor
+		}
+	}
+
+	if total == 0 {
+		// Изменений нет, но курсор мог сдвинуться — сохраняем сразу, задание не нужно.
+		return s.jobRepo.SaveCursors(ctx, SyncSource1CPull, newCursors)
+	}
+
+	rawCursors, _ := json.Marshal(newCursors)
+	_, err = s.enqueue(ctx, entities.SyncJob{Source: SyncSource1CPull, Cursors: rawCursors}, payload)
+	return err
+}
+
+// commitCursors сдвигает курсоры загрузки после успешного задания. Если часть записей не сохранилась,
+// курсоры остаются на месте, и следующая загрузка запросит эти изменения снова.
+func (s *SyncService) commitCursors(job *entities.SyncJob, report *sync.Report, logger *zap.Logger) {
+	if job.DryRun || len(job.Cursors) == 0 {
+		return
+	}
+	var cursors map[string]string
+	if err := json.Unmarshal(job.Cursors, &cursors); err != nil || len(cursors) == 0 {
+		return
+	}
+	if _, _, summary := report.Snapshot(); summary.Failed > 0 {
+		logger.Warn("Курсоры загрузки из 1С не сдвинуты: часть записей не сохранена", zap.Int("failed", summary.Failed))
+		return
+	}
+	if err := s.jobRepo.SaveCursors(context.Background(), job.Source, cursors); err != nil {
+		logger.Error("Не удалось сохранить курсоры загрузки из 1С", zap.Error(err))
+	}
+}
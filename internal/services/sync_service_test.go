@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/internal/sync"
+)
+
+type syncHandlerStub struct {
+	sync.HandlerInterface
+	departments int
+	usersErr    error
+	dryRuns     int
+}
+
+func (h *syncHandlerStub) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	h.dryRuns++
+	return fn(ctx)
+}
+
+func (h *syncHandlerStub) ProcessDepartments(_ context.Context, data []dto.Department1CDTO) error {
+	h.departments += len(data)
+	return nil
+}
+
+func (h *syncHandlerStub) ProcessUsers(context.Context, []dto.User1CDTO) error { return h.usersErr }
+
+type syncJobRepoStub struct {
+	repositories.SyncJobRepositoryInterface
+	queued   []*entities.SyncJob
+	status   string
+	errMsg   *string
+	changes  json.RawMessage
+	finished int
+	cursors  map[string]string
+}
+
+func (r *syncJobRepoStub) HasPending(context.Context, string) (bool, error) {
+	return len(r.queued) > 0, nil
+}
+
+func (r *syncJobRepoStub) FindCursors(context.Context, string) (map[string]string, error) {
+	return r.cursors, nil
+}
+
+func (r *syncJobRepoStub) SaveCursors(_ context.Context, _ string, cursors map[string]string) error {
+	if r.cursors == nil {
+		r.cursors = map[string]string{}
+	}
+	for entity, cursor := range cursors {
+		r.cursors[entity] = cursor
+	}
+	return nil
+}
+
+type pullSourceStub struct {
+	items   map[string][]json.RawMessage
+	cursors map[string]string
+	asked   map[string]string
+}
+
+func (p *pullSourceStub) FetchChanges(_ context.Context, entity, cursor string) ([]json.RawMessage, string, error) {
+	p.asked[entity] = cursor
+	return p.items[entity], p.cursors[entity], nil
+}
+
+func (r *syncJobRepoStub) Create(_ context.Context, job entities.SyncJob) (*entities.SyncJob, error) {
+	job.ID = uint64(len(r.queued) + 1)
+	job.Status = entities.SyncJobQueued
+	job.CreatedAt = time.Now()
+	r.queued = append(r.queued, &job)
+	return &job, nil
+}
+
+func (r *syncJobRepoStub) ClaimNext(context.Context, time.Duration) (*entities.SyncJob, error) {
+	if len(r.queued) == 0 {
+		return nil, nil
+	}
+	job := r.queued[0]
+	r.queued = r.queued[1:]
+	return job, nil
+}
+
+func (r *syncJobRepoStub) Finish(_ context.Context, _ uint64, status string, result entities.SyncJobResult, errMsg *string) error {
+	r.status, r.errMsg, r.changes = status, errMsg, result.Changes
+	r.finished++
+	return nil
+}
+
+func TestSyncJobIsProcessedByWorker(t *testing.T) {
+	handler := &syncHandlerStub{usersErr: errors.New("conflict")}
+	repo := &syncJobRepoStub{}
+	s := NewSyncService(handler, repo, zap.NewNop()).(*SyncService)
+
+	job, err := s.Enqueue1CReferences(context.Background(), dto.Webhook1CPayloadDTO{
+		Departments: []dto.Department1CDTO{{ExternalID: "D1", Name: "ИТ", IsActive: true}},
+		Users:       []dto.User1CDTO{{ExternalID: "U1"}},
+	}, SyncJobOptions{})
+	if err != nil || job.Status != entities.SyncJobQueued {
+		t.Fatalf("unexpected enqueue result %+v, %v", job, err)
+	}
+
+	claimed, _ := repo.ClaimNext(context.Background(), syncStaleAfter)
+	s.runJob(claimed)
+
+	if handler.departments != 1 || handler.dryRuns != 0 {
+		t.Fatalf("expected departments from the stored payload to be processed, got %d", handler.departments)
+	}
+	if repo.finished != 1 || repo.status != entities.SyncJobFailed || repo.errMsg == nil || !strings.Contains(*repo.errMsg, "пользователей") {
+		t.Fatalf("expected job to finish as failed with users error, got %q %v", repo.status, repo.errMsg)
+	}
+}
+
+func TestSyncDryRunJobGoesThroughHandlerDryRun(t *testing.T) {
+	handler := &syncHandlerStub{}
+	repo := &syncJobRepoStub{}
+	s := NewSyncService(handler, repo, zap.NewNop()).(*SyncService)
+
+	job, err := s.Enqueue1CReferences(context.Background(), dto.Webhook1CPayloadDTO{
+		Departments: []dto.Department1CDTO{{ExternalID: "D1", Name: "ИТ", IsActive: true}},
+	}, SyncJobOptions{DryRun: true})
+	if err != nil || !job.DryRun {
+		t.Fatalf("expected dry run job, got %+v, %v", job, err)
+	}
+
+	claimed, _ := repo.ClaimNext(context.Background(), syncStaleAfter)
+	s.runJob(claimed)
+
+	if handler.dryRuns != 1 || handler.departments != 1 {
+		t.Fatalf("expected payload to be processed inside DryRun, got dryRuns=%d departments=%d", handler.dryRuns, handler.departments)
+	}
+	if repo.status != entities.SyncJobCompleted || string(repo.changes) != "[]" {
+		t.Fatalf("unexpected finish: status %q, changes %s", repo.status, repo.changes)
+	}
+}
+
+func TestSyncPullMovesCursorsOnlyAfterJobSucceeds(t *testing.T) {
+	handler := &syncHandlerStub{}
+	repo := &syncJobRepoStub{cursors: map[string]string{"departments": "v1"}}
+	s := NewSyncService(handler, repo, zap.NewNop()).(*SyncService)
+	source := &pullSourceStub{
+		items:   map[string][]json.RawMessage{"departments": {json.RawMessage(`{"externalId":"D1","name":"ИТ","isActive":true}`)}},
+		cursors: map[string]string{"departments": "v2", "users": "u1"},
+		asked:   map[string]string{},
+	}
+
+	if err := s.pullOnce(context.Background(), source); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	if source.asked["departments"] != "v1" {
+		t.Fatalf("expected changes to be requested after stored cursor, got %q", source.asked["departments"])
+	}
+	if len(repo.queued) != 1 || repo.cursors["departments"] != "v1" {
+		t.Fatalf("expected one queued job and unchanged cursors, got %d jobs, cursors %v", len(repo.queued), repo.cursors)
+	}
+
+	// Пока задание в очереди, новая загрузка не ставит дубль.
+	if err := s.pullOnce(context.Background(), source); err != nil || len(repo.queued) != 1 {
+		t.Fatalf("expected pending job to block the next pull, got %d jobs, %v", len(repo.queued), err)
+	}
+
+	claimed, _ := repo.ClaimNext(context.Background(), syncStaleAfter)
+	s.runJob(claimed)
+
+	if handler.departments != 1 || repo.status != entities.SyncJobCompleted {
+		t.Fatalf("expected pulled departments to be processed, got %d, status %q", handler.departments, repo.status)
+	}
+	if repo.cursors["departments"] != "v2" || repo.cursors["users"] != "u1" {
+		t.Fatalf("expected cursors to move after success, got %v", repo.cursors)
+	}
+}
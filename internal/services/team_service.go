@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+// TeamServiceInterface управляет командами исполнителей. Заявку, назначенную на команду
+// правилом маршрутизации, забирает себе любой её участник.
+type TeamServiceInterface interface {
+	GetTeams(ctx context.Context) ([]dto.TeamDTO, error)
+	GetTeam(ctx context.Context, id uint64) (*dto.TeamDetailsDTO, error)
+	CreateTeam(ctx context.Context, payload dto.CreateTeamDTO) (*dto.TeamDetailsDTO, error)
+	UpdateTeam(ctx context.Context, id uint64, payload dto.UpdateTeamDTO) (*dto.TeamDetailsDTO, error)
+	DeleteTeam(ctx context.Context, id uint64) error
+	AddMembers(ctx context.Context, id uint64, payload dto.TeamMembersDTO) (*dto.TeamDetailsDTO, error)
+	// RemoveMember исключает участника; руководителя сначала нужно сменить.
+	RemoveMember(ctx context.Context, id, userID uint64) (*dto.TeamDetailsDTO, error)
+}
+
+type TeamService struct {
+	repo     repositories.TeamRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewTeamService(
+	repo repositories.TeamRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) TeamServiceInterface {
+	return &TeamService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+func (s *TeamService) checkPermission(ctx context.Context, permission string) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(permission, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func (s *TeamService) GetTeams(ctx context.Context) ([]dto.TeamDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.TeamsView); err != nil {
+		return nil, err
+	}
+	teams, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.TeamDTO, 0, len(teams))
+	for _, t := range teams {
+		result = append(result, toTeamDTO(t))
+	}
+	return result, nil
+}
+
+func (s *TeamService) GetTeam(ctx context.Context, id uint64) (*dto.TeamDetailsDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.TeamsView); err != nil {
+		return nil, err
+	}
+	return s.loadDetails(ctx, id)
+}
+
+func (s *TeamService) CreateTeam(ctx context.Context, payload dto.CreateTeamDTO) (*dto.TeamDetailsDTO, error) {
+	authContext, err := s.checkPermission(ctx, authz.TeamsManage)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указано название команды", nil, nil)
+	}
+
+	team := &entities.Team{Name: name, Description: payload.Description, LeadID: normalizeTeamLead(payload.LeadID)}
+	if err := s.repo.Create(ctx, team, payload.MemberIDs); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Создана команда", zap.Uint64("teamID", team.ID), zap.String("name", name), zap.Uint64("by", authContext.Actor.ID))
+	return s.loadDetails(ctx, team.ID)
+}
+
+func (s *TeamService) UpdateTeam(ctx context.Context, id uint64, payload dto.UpdateTeamDTO) (*dto.TeamDetailsDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.TeamsManage); err != nil {
+		return nil, err
+	}
+	team, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Name != nil {
+		name := strings.TrimSpace(*payload.Name)
+		if name == "" {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest, "Не указано название команды", nil, nil)
+		}
+		team.Name = name
+	}
+	if payload.Description != nil {
+		team.Description = payload.Description
+	}
+	if payload.LeadID != nil {
+		team.LeadID = normalizeTeamLead(payload.LeadID)
+	}
+
+	if err := s.repo.Update(ctx, team); err != nil {
+		return nil, err
+	}
+	return s.loadDetails(ctx, id)
+}
+
+func (s *TeamService) DeleteTeam(ctx context.Context, id uint64) error {
+	authContext, err := s.checkPermission(ctx, authz.TeamsManage)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалена команда", zap.Uint64("teamID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *TeamService) AddMembers(ctx context.Context, id uint64, payload dto.TeamMembersDTO) (*dto.TeamDetailsDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.TeamsManage); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddMembers(ctx, id, payload.UserIDs); err != nil {
+		return nil, err
+	}
+	return s.loadDetails(ctx, id)
+}
+
+func (s *TeamService) RemoveMember(ctx context.Context, id, userID uint64) (*dto.TeamDetailsDTO, error) {
+	if _, err := s.checkPermission(ctx, authz.TeamsManage); err != nil {
+		return nil, err
+	}
+	team, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if team.LeadID != nil && *team.LeadID == userID {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Нельзя исключить руководителя команды. Сначала назначьте другого руководителя.", nil, nil)
+	}
+	if err := s.repo.RemoveMember(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.loadDetails(ctx, id)
+}
+
+func (s *TeamService) loadDetails(ctx context.Context, id uint64) (*dto.TeamDetailsDTO, error) {
+	team, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.FindMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TeamDetailsDTO{TeamDTO: toTeamDTO(*team), Members: make([]dto.TeamMemberDTO, 0, len(members))}
+	for _, m := range members {
+		result.Members = append(result.Members, dto.TeamMemberDTO{
+			UserID:   m.UserID,
+			Fio:      m.Fio,
+			Email:    m.Email,
+			IsLead:   m.IsLead,
+			JoinedAt: m.JoinedAt.Format(time.RFC3339),
+		})
+	}
+	return result, nil
+}
+
+// normalizeTeamLead — lead_id = 0 означает «без руководителя».
+func normalizeTeamLead(leadID *uint64) *uint64 {
+	if leadID == nil || *leadID == 0 {
+		return nil
+	}
+	return leadID
+}
+
+func toTeamDTO(t entities.Team) dto.TeamDTO {
+	return dto.TeamDTO{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		LeadID:      t.LeadID,
+		LeadName:    t.LeadName,
+		MemberCount: t.MemberCount,
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   t.UpdatedAt.Format(time.RFC3339),
+	}
+}
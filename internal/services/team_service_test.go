@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+)
+
+type teamUserRepoStub struct {
+	repositories.UserRepositoryInterface
+}
+
+func (s *teamUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	return &entities.User{ID: id}, nil
+}
+
+type teamRepoStub struct {
+	repositories.TeamRepositoryInterface
+	team    entities.Team
+	removed []uint64
+}
+
+func (s *teamRepoStub) FindByID(_ context.Context, id uint64) (*entities.Team, error) {
+	team := s.team
+	return &team, nil
+}
+
+func (s *teamRepoStub) FindMembers(context.Context, uint64) ([]entities.TeamMember, error) {
+	return nil, nil
+}
+
+func (s *teamRepoStub) RemoveMember(_ context.Context, _ uint64, userID uint64) error {
+	s.removed = append(s.removed, userID)
+	return nil
+}
+
+func TestRemoveMemberKeepsTeamLead(t *testing.T) {
+	leadID := uint64(5)
+	repo := &teamRepoStub{team: entities.Team{ID: 1, Name: "Сеть", LeadID: &leadID}}
+	service := NewTeamService(repo, &teamUserRepoStub{}, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(1))
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.TeamsManage: true})
+
+	if _, err := service.RemoveMember(ctx, 1, leadID); err == nil {
+		t.Fatal("expected an error when removing the team lead")
+	}
+	if _, err := service.RemoveMember(ctx, 1, 7); err != nil {
+		t.Fatalf("remove member failed: %v", err)
+	}
+	if len(repo.removed) != 1 || repo.removed[0] != 7 {
+		t.Fatalf("unexpected removed members %v", repo.removed)
+	}
+
+	viewOnly := context.WithValue(ctx, contextkeys.UserPermissionsMapKey, map[string]bool{authz.TeamsView: true})
+	if _, err := service.RemoveMember(viewOnly, 1, 7); err == nil {
+		t.Fatal("expected forbidden without team:manage")
+	}
+}
@@ -3,6 +3,9 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -23,6 +26,8 @@ type TelegramIntegrationServiceInterface interface {
 	ValidateWebhookRequest(r *http.Request) error
 	RegisterWebhook(ctx context.Context, baseURL string) (*TelegramWebhookInfo, error)
 	GetWebhookInfo(ctx context.Context) (*TelegramWebhookInfo, error)
+	DeleteWebhook(ctx context.Context) error
+	GetUpdates(ctx context.Context, offset int, timeout time.Duration) ([]json.RawMessage, error)
 }
 
 type TelegramWebhookInfo struct {
@@ -40,6 +45,7 @@ type TelegramIntegrationService struct {
 	webhookSecretToken string
 	logger             *zap.Logger
 	httpClient         *http.Client
+	pollingClient      *http.Client
 }
 
 type telegramAPIResponse[T any] struct {
@@ -49,6 +55,12 @@ type telegramAPIResponse[T any] struct {
 	Result      T      `json:"result"`
 }
 
+type telegramGetUpdatesRequest struct {
+	Offset         int      `json:"offset,omitempty"`
+	Timeout        int      `json:"timeout"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
 type telegramSetWebhookRequest struct {
 	URL            string   `json:"url"`
 	SecretToken    string   `json:"secret_token,omitempty"`
@@ -64,6 +76,8 @@ func NewTelegramIntegrationService(cfg config.TelegramConfig, logger *zap.Logger
 		webhookSecretToken: strings.TrimSpace(cfg.WebhookSecretToken),
 		logger:             logger,
 		httpClient:         &http.Client{Timeout: 15 * time.Second},
+		// Long-polling держит соединение до PollingTimeout, поэтому клиенту нужен запас.
+		pollingClient: &http.Client{Timeout: cfg.PollingTimeout + 15*time.Second},
 	}
 
 	if service.Enabled() && service.botUsername == "" {
@@ -71,7 +85,10 @@ func NewTelegramIntegrationService(cfg config.TelegramConfig, logger *zap.Logger
 	}
 
 	if service.Enabled() && service.webhookSecretToken == "" {
-		logger.Warn("Telegram webhook request validation disabled: TELEGRAM_WEBHOOK_SECRET_TOKEN is empty")
+		// Без секрета любой, кто узнал адрес webhook, может подсовывать фальшивые обновления.
+		// Выводим секрет из токена бота: он одинаков на всех инстансах и не известен снаружи.
+		service.webhookSecretToken = deriveTelegramWebhookSecret(service.botToken)
+		logger.Warn("TELEGRAM_WEBHOOK_SECRET_TOKEN is empty: using secret derived from bot token")
 	}
 
 	return service
@@ -91,16 +108,27 @@ func (s *TelegramIntegrationService) BuildBotStartLink(token string) string {
 
 func (s *TelegramIntegrationService) ValidateWebhookRequest(r *http.Request) error {
 	if strings.TrimSpace(s.webhookSecretToken) == "" {
-		return nil
+		return fmt.Errorf("telegram webhook secret token is not configured")
 	}
 
-	if got := strings.TrimSpace(r.Header.Get(telegramWebhookSecretHeader)); got != s.webhookSecretToken {
+	got := strings.TrimSpace(r.Header.Get(telegramWebhookSecretHeader))
+	if got == "" {
+		return fmt.Errorf("telegram webhook secret token header is missing")
+	}
+	if subtle.ConstantTimeCompare([]byte(got), []byte(s.webhookSecretToken)) != 1 {
 		return fmt.Errorf("telegram webhook secret token mismatch")
 	}
 
 	return nil
 }
 
+// deriveTelegramWebhookSecret строит secret_token из токена бота.
+// Telegram допускает только символы A-Z, a-z, 0-9, _ и -, поэтому используем hex.
+func deriveTelegramWebhookSecret(botToken string) string {
+	sum := sha256.Sum256([]byte("telegram-webhook:" + botToken))
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *TelegramIntegrationService) RegisterWebhook(ctx context.Context, baseURL string) (*TelegramWebhookInfo, error) {
 	if !s.Enabled() {
 		return nil, fmt.Errorf("telegram bot token is not configured")
@@ -151,7 +179,40 @@ func (s *TelegramIntegrationService) GetWebhookInfo(ctx context.Context) (*Teleg
 	return &info, nil
 }
 
+// DeleteWebhook снимает webhook: пока он установлен, Telegram не отдаёт обновления через getUpdates.
+func (s *TelegramIntegrationService) DeleteWebhook(ctx context.Context) error {
+	if !s.Enabled() {
+		return fmt.Errorf("telegram bot token is not configured")
+	}
+
+	var result bool
+	return s.callTelegramAPI(ctx, "deleteWebhook", map[string]bool{"drop_pending_updates": false}, &result)
+}
+
+func (s *TelegramIntegrationService) GetUpdates(ctx context.Context, offset int, timeout time.Duration) ([]json.RawMessage, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("telegram bot token is not configured")
+	}
+
+	payload := telegramGetUpdatesRequest{
+		Offset:         offset,
+		Timeout:        int(timeout / time.Second),
+		AllowedUpdates: []string{"message", "callback_query"},
+	}
+
+	var updates []json.RawMessage
+	if err := s.doTelegramAPI(ctx, s.pollingClient, "getUpdates", payload, &updates); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
 func (s *TelegramIntegrationService) callTelegramAPI(ctx context.Context, method string, payload any, out any) error {
+	return s.doTelegramAPI(ctx, s.httpClient, method, payload, out)
+}
+
+func (s *TelegramIntegrationService) doTelegramAPI(ctx context.Context, client *http.Client, method string, payload any, out any) error {
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", s.botToken, method)
 
 	var bodyReader *bytes.Reader
@@ -171,7 +232,7 @@ func (s *TelegramIntegrationService) callTelegramAPI(ctx context.Context, method
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("telegram %s: request failed: %w", method, err)
 	}
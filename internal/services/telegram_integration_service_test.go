@@ -0,0 +1,46 @@
+package services
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/pkg/config"
+)
+
+func TestValidateWebhookRequest_RejectsMissingOrWrongSecret(t *testing.T) {
+	service := NewTelegramIntegrationService(config.TelegramConfig{
+		BotToken:           "123:token",
+		WebhookSecretToken: "expected-secret",
+	}, zap.NewNop())
+
+	req := httptest.NewRequest("POST", "/api/webhooks/telegram", nil)
+	if err := service.ValidateWebhookRequest(req); err == nil {
+		t.Fatal("expected error for missing secret header")
+	}
+
+	req.Header.Set(telegramWebhookSecretHeader, "wrong-secret")
+	if err := service.ValidateWebhookRequest(req); err == nil {
+		t.Fatal("expected error for wrong secret header")
+	}
+
+	req.Header.Set(telegramWebhookSecretHeader, "expected-secret")
+	if err := service.ValidateWebhookRequest(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestValidateWebhookRequest_DerivesSecretWhenNotConfigured(t *testing.T) {
+	service := NewTelegramIntegrationService(config.TelegramConfig{BotToken: "123:token"}, zap.NewNop())
+
+	req := httptest.NewRequest("POST", "/api/webhooks/telegram", nil)
+	if err := service.ValidateWebhookRequest(req); err == nil {
+		t.Fatal("expected unsigned request to be rejected")
+	}
+
+	req.Header.Set(telegramWebhookSecretHeader, deriveTelegramWebhookSecret("123:token"))
+	if err := service.ValidateWebhookRequest(req); err != nil {
+		t.Fatalf("expected derived secret to be accepted, got %v", err)
+	}
+}
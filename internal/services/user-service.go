@@ -21,12 +21,14 @@ import (
 	"request-system/internal/dto"
 	"request-system/internal/entities"
 	"request-system/internal/repositories"
+	"request-system/pkg/config"
 	"request-system/pkg/constants"
 	apperrors "request-system/pkg/errors"
 	"request-system/pkg/types"
 	"request-system/pkg/utils"
 )
 
+// telegramLinkTokenTTL — срок жизни кода привязки, если TELEGRAM_LINK_TOKEN_TTL_MINUTES не задан.
 const telegramLinkTokenTTL = 10 * time.Minute
 const telegramLinkShortCodeLength = 6
 const telegramLinkShortCodeAttempts = 10
@@ -37,6 +39,16 @@ type telegramLinkCachePayload struct {
 	ShortCode string `json:"short_code,omitempty"`
 }
 
+// TelegramRebindRequiredError — чат уже привязан к другому пользователю. Код привязки при этом
+// не расходуется: бот спрашивает, заменить ли привязку, и повторяет вызов с replace=true.
+type TelegramRebindRequiredError struct {
+	LinkedUserFio string
+}
+
+func (e *TelegramRebindRequiredError) Error() string {
+	return "telegram chat is already linked to another user"
+}
+
 type UserServiceInterface interface {
 	GetUsers(ctx context.Context, filter types.Filter) ([]dto.UserDTO, uint64, error)
 	GetUsersForADBinding(ctx context.Context, filter types.Filter) ([]dto.UserDTO, error)
@@ -55,7 +67,12 @@ type UserServiceInterface interface {
 	GetTelegramLinkStatus(ctx context.Context) (*dto.TelegramLinkStatusDTO, error)
 	GenerateTelegramLinkToken(ctx context.Context) (*dto.TelegramLinkTokenDTO, error)
 	UnlinkTelegram(ctx context.Context) error
-	ConfirmTelegramLink(ctx context.Context, token string, chatID int64) error
+	// ForceUnlinkTelegram отвязывает чат пользователя по решению администратора и отзывает его
+	// коды привязки. Возвращает отвязанный чат (0, если привязки не было).
+	ForceUnlinkTelegram(ctx context.Context, userID uint64) (int64, error)
+	// ConfirmTelegramLink привязывает чат по одноразовому коду. Если чат уже привязан к другому
+	// пользователю, без replace возвращается *TelegramRebindRequiredError.
+	ConfirmTelegramLink(ctx context.Context, token string, chatID int64, replace bool) error
 	FindUserByTelegramChatID(ctx context.Context, chatID int64) (*entities.User, error)
 }
 
@@ -68,6 +85,7 @@ type UserService struct {
 	statusRepository      repositories.StatusRepositoryInterface
 	cacheRepository       repositories.CacheRepositoryInterface
 	authPermissionService AuthPermissionServiceInterface
+	telegramLinkTTL       time.Duration
 	logger                *zap.Logger
 }
 
@@ -80,8 +98,13 @@ func NewUserService(
 	statusRepository repositories.StatusRepositoryInterface,
 	cacheRepository repositories.CacheRepositoryInterface,
 	authPermissionService AuthPermissionServiceInterface,
+	telegramCfg config.TelegramConfig,
 	logger *zap.Logger,
 ) UserServiceInterface {
+	linkTTL := telegramCfg.LinkTokenTTL
+	if linkTTL <= 0 {
+		linkTTL = telegramLinkTokenTTL
+	}
 	return &UserService{
 		txManager:             txManager,
 		userRepository:        userRepository,
@@ -91,6 +114,7 @@ func NewUserService(
 		statusRepository:      statusRepository,
 		cacheRepository:       cacheRepository,
 		authPermissionService: authPermissionService,
+		telegramLinkTTL:       linkTTL,
 		logger:                logger,
 	}
 }
@@ -644,15 +668,17 @@ func (s *UserService) UpdateUserPermissions(ctx context.Context, userID uint64,
 		}
 	}
 
-	return s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err = s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
 		if err := s.userRepository.SyncUserDirectPermissions(ctx, tx, userID, add); err != nil {
 			return err
 		}
-		if err := s.userRepository.SyncUserDeniedPermissions(ctx, tx, userID, deny); err != nil {
-			return err
-		}
-		return s.authPermissionService.InvalidateUserPermissionsCache(ctx, userID)
+		return s.userRepository.SyncUserDeniedPermissions(ctx, tx, userID, deny)
 	})
+	if err != nil {
+		return err
+	}
+	// Кэш сбрасываем после коммита, иначе параллельный запрос успеет закэшировать старые права
+	return s.authPermissionService.InvalidateUserPermissionsCache(ctx, userID)
 }
 
 func telegramLinkTokenCacheKey(token string) string {
@@ -663,6 +689,31 @@ func telegramLinkShortCodeCacheKey(code string) string {
 	return fmt.Sprintf("telegram-link-short:%s", code)
 }
 
+// telegramLinkUserCacheKey — действующий код пользователя; по нему отзываются прежние коды.
+func telegramLinkUserCacheKey(userID uint64) string {
+	return fmt.Sprintf("telegram-link-user:%d", userID)
+}
+
+// revokeTelegramLinkTokens удаляет выданный пользователю код привязки, если он ещё не использован.
+func (s *UserService) revokeTelegramLinkTokens(ctx context.Context, userID uint64) {
+	raw, err := s.cacheRepository.Get(ctx, telegramLinkUserCacheKey(userID))
+	if err != nil || strings.TrimSpace(raw) == "" {
+		return
+	}
+	keys := []string{telegramLinkUserCacheKey(userID)}
+	if payload, err := parseTelegramLinkCachePayload(raw); err == nil {
+		if payload.Token != "" {
+			keys = append(keys, telegramLinkTokenCacheKey(payload.Token))
+		}
+		if payload.ShortCode != "" {
+			keys = append(keys, telegramLinkShortCodeCacheKey(payload.ShortCode))
+		}
+	}
+	if err := s.cacheRepository.Del(ctx, keys...); err != nil {
+		s.logger.Warn("Failed to revoke telegram link tokens", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+}
+
 func parseTelegramLinkCachePayload(raw string) (*telegramLinkCachePayload, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -754,21 +805,32 @@ func (s *UserService) GenerateTelegramLinkToken(ctx context.Context) (*dto.Teleg
 		return nil, apperrors.ErrInternalServer
 	}
 
-	if err := s.cacheRepository.Set(ctx, telegramLinkTokenCacheKey(token), string(payloadJSON), telegramLinkTokenTTL); err != nil {
+	// Действует только последний выданный код: прежний отзывается, даже если его срок не истёк.
+	s.revokeTelegramLinkTokens(ctx, uid)
+
+	ttl := s.telegramLinkTTL
+	expiresAt := time.Now().Add(ttl)
+	if err := s.cacheRepository.Set(ctx, telegramLinkTokenCacheKey(token), string(payloadJSON), ttl); err != nil {
 		s.logger.Error("Failed to store telegram link token", zap.Uint64("user_id", uid), zap.Error(err))
 		return nil, apperrors.ErrInternalServer
 	}
-	if err := s.cacheRepository.Set(ctx, telegramLinkShortCodeCacheKey(shortCode), string(payloadJSON), telegramLinkTokenTTL); err != nil {
+	if err := s.cacheRepository.Set(ctx, telegramLinkShortCodeCacheKey(shortCode), string(payloadJSON), ttl); err != nil {
 		_ = s.cacheRepository.Del(ctx, telegramLinkTokenCacheKey(token))
 		s.logger.Error("Failed to store telegram short code", zap.Uint64("user_id", uid), zap.Error(err))
 		return nil, apperrors.ErrInternalServer
 	}
+	if err := s.cacheRepository.Set(ctx, telegramLinkUserCacheKey(uid), string(payloadJSON), ttl); err != nil {
+		_ = s.cacheRepository.Del(ctx, telegramLinkTokenCacheKey(token), telegramLinkShortCodeCacheKey(shortCode))
+		s.logger.Error("Failed to store telegram link owner", zap.Uint64("user_id", uid), zap.Error(err))
+		return nil, apperrors.ErrInternalServer
+	}
 
-	s.logger.Info("Telegram link token generated", zap.Uint64("user_id", uid), zap.String("short_code", shortCode))
+	s.logger.Info("Telegram link token generated", zap.Uint64("user_id", uid), zap.String("short_code", shortCode), zap.Time("expires_at", expiresAt))
 	return &dto.TelegramLinkTokenDTO{
 		Token:            token,
 		ShortCode:        shortCode,
-		ExpiresInSeconds: int(telegramLinkTokenTTL / time.Second),
+		ExpiresInSeconds: int(ttl / time.Second),
+		ExpiresAt:        expiresAt,
 	}, nil
 }
 
@@ -786,8 +848,10 @@ func (s *UserService) GetTelegramLinkStatus(ctx context.Context) (*dto.TelegramL
 		return nil, err
 	}
 
+	channel := telegramChannelOf(user)
 	result := &dto.TelegramLinkStatusDTO{
-		Linked: user.TelegramChatID.Valid,
+		Linked:            channel.Linked,
+		LastInteractionAt: channel.LastInteractionAt,
 	}
 	if user.TelegramChatID.Valid {
 		chatID := user.TelegramChatID.Int64
@@ -810,6 +874,9 @@ func (s *UserService) UnlinkTelegram(ctx context.Context) error {
 		}
 		return err
 	}
+
+	// Неиспользованный код привязки после отвязки тоже не должен сработать.
+	s.revokeTelegramLinkTokens(ctx, uid)
 	if !user.TelegramChatID.Valid {
 		return nil
 	}
@@ -819,7 +886,43 @@ func (s *UserService) UnlinkTelegram(ctx context.Context) error {
 	})
 }
 
-func (s *UserService) ConfirmTelegramLink(ctx context.Context, token string, chatID int64) error {
+func (s *UserService) ForceUnlinkTelegram(ctx context.Context, userID uint64) (int64, error) {
+	user, err := s.userRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || apperrors.IsNotFound(err) {
+			return 0, apperrors.ErrUserNotFound
+		}
+		return 0, err
+	}
+	authContext, err := s.checkAccess(ctx, authz.UsersUpdate, user)
+	if err != nil {
+		return 0, err
+	}
+
+	s.revokeTelegramLinkTokens(ctx, userID)
+	if !user.TelegramChatID.Valid {
+		return 0, nil
+	}
+
+	chatID := user.TelegramChatID.Int64
+	if err := s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		return s.userRepository.ClearTelegramChatID(ctx, tx, userID)
+	}); err != nil {
+		return 0, err
+	}
+
+	var actorID uint64
+	if authContext.Actor != nil {
+		actorID = authContext.Actor.ID
+	}
+	s.logger.Warn("Telegram chat unlinked by administrator",
+		zap.Uint64("user_id", userID),
+		zap.Int64("chat_id", chatID),
+		zap.Uint64("by", actorID))
+	return chatID, nil
+}
+
+func (s *UserService) ConfirmTelegramLink(ctx context.Context, token string, chatID int64, replace bool) error {
 	token = strings.TrimSpace(token)
 	if token == "" {
 		return apperrors.NewBadRequestError("Код привязки не указан")
@@ -858,30 +961,42 @@ func (s *UserService) ConfirmTelegramLink(ctx context.Context, token string, cha
 	}
 	uid := payload.UserID
 
-	cleanupKeys := map[string]struct{}{
-		cacheKey: {},
-	}
-	if payload.Token != "" {
-		cleanupKeys[telegramLinkTokenCacheKey(payload.Token)] = struct{}{}
-	}
-	if payload.ShortCode != "" {
-		cleanupKeys[telegramLinkShortCodeCacheKey(payload.ShortCode)] = struct{}{}
-	}
-	cleanup := func() {
-		for key := range cleanupKeys {
-			_ = s.cacheRepository.Del(ctx, key)
+	// consume расходует код: GetDel отдаёт значение только одному вызову, поэтому по одному коду
+	// нельзя привязать два чата, даже если сообщения пришли одновременно.
+	consume := func() error {
+		taken, err := s.cacheRepository.GetDel(ctx, cacheKey)
+		if err != nil || strings.TrimSpace(taken) == "" {
+			s.logger.Warn("Telegram link token already used", zap.String("cache_key", cacheKey), zap.Int64("chat_id", chatID))
+			return apperrors.NewBadRequestError("Код уже использован или срок его действия истек. Получите новый код на сайте")
+		}
+		keys := []string{telegramLinkUserCacheKey(uid)}
+		if payload.Token != "" {
+			keys = append(keys, telegramLinkTokenCacheKey(payload.Token))
 		}
+		if payload.ShortCode != "" {
+			keys = append(keys, telegramLinkShortCodeCacheKey(payload.ShortCode))
+		}
+		_ = s.cacheRepository.Del(ctx, keys...)
+		return nil
 	}
 
 	existingUser, err := s.userRepository.FindUserByTelegramChatID(ctx, chatID)
 	if err == nil && existingUser != nil {
 		if existingUser.ID == uid {
-			cleanup()
+			if err := consume(); err != nil {
+				return err
+			}
 			s.logger.Info("Telegram already linked to the same user",
 				zap.Uint64("user_id", uid),
 				zap.Int64("chat_id", chatID))
 			return nil
 		}
+		if !replace {
+			return &TelegramRebindRequiredError{LinkedUserFio: existingUser.Fio}
+		}
+		if err := consume(); err != nil {
+			return err
+		}
 
 		if err := s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
 			if err := s.userRepository.ClearTelegramChatID(ctx, tx, existingUser.ID); err != nil {
@@ -897,7 +1012,6 @@ func (s *UserService) ConfirmTelegramLink(ctx context.Context, token string, cha
 			return err
 		}
 
-		cleanup()
 		s.logger.Warn("Telegram chat reassigned to another user",
 			zap.Int64("chat_id", chatID),
 			zap.Uint64("from_user_id", existingUser.ID),
@@ -910,6 +1024,9 @@ func (s *UserService) ConfirmTelegramLink(ctx context.Context, token string, cha
 			zap.Error(err))
 		return apperrors.ErrInternalServer
 	}
+	if err := consume(); err != nil {
+		return err
+	}
 	if err := s.userRepository.UpdateTelegramChatID(ctx, uid, chatID); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -927,7 +1044,6 @@ func (s *UserService) ConfirmTelegramLink(ctx context.Context, token string, cha
 			zap.Error(err))
 		return err
 	}
-	cleanup()
 	s.logger.Info("Telegram account linked successfully",
 		zap.Uint64("user_id", uid),
 		zap.Int64("chat_id", chatID))
@@ -941,7 +1057,7 @@ func (s *UserService) checkAccess(ctx context.Context, perm string, target inter
 	actorID, _ := utils.GetUserIDFromCtx(ctx)
 	actor, _ := s.userRepository.FindUserByID(ctx, actorID)
 	perms, _ := utils.GetPermissionsMapFromCtx(ctx)
-	ac := &authz.Context{Actor: actor, Permissions: perms, Target: target}
+	ac := &authz.Context{Actor: actor, Permissions: perms, Conditions: utils.GetPermissionConditionsFromCtx(ctx), Target: target}
 	if !authz.CanDo(perm, *ac) {
 		return nil, apperrors.ErrForbidden
 	}
@@ -968,6 +1084,8 @@ func userEntityToUserDTO(e *entities.User) *dto.UserDTO {
 		DepartmentName: e.DepartmentName,
 		OtdelName:      e.OtdelName,
 		OfficeName:     e.OfficeName,
+
+		Telegram: telegramChannelOf(e),
 	}
 	if e.IsHead != nil {
 		d.IsHead = *e.IsHead
@@ -980,6 +1098,15 @@ func userEntityToUserDTO(e *entities.User) *dto.UserDTO {
 	}
 	return d
 }
+
+// telegramChannelOf описывает привязку бота; время последнего обращения без чата не показываем.
+func telegramChannelOf(e *entities.User) dto.TelegramChannelDTO {
+	if !e.TelegramChatID.Valid {
+		return dto.TelegramChannelDTO{}
+	}
+	return dto.TelegramChannelDTO{Linked: true, LastInteractionAt: e.TelegramLastSeenAt}
+}
+
 func (s *UserService) validateHierarchy(ctx context.Context, deptID *uint64, mainOtdelID *uint64, extraOtdelIDs []uint64) error {
 	if deptID == nil || *deptID == 0 {
 		return nil
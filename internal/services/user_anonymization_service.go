@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/filestorage"
+)
+
+// anonymizedUserLabel — чем обезличивание заменяет ФИО в профиле и истории заявок.
+const anonymizedUserLabel = "Бывший сотрудник #%d"
+
+type UserAnonymizationServiceInterface interface {
+	// Anonymize стирает личные данные пользователя. С dryRun только показывает, что будет затронуто;
+	// без него confirm должен совпасть с текущим ФИО — действие необратимо.
+	Anonymize(ctx context.Context, userID uint64, confirm string, dryRun bool) (*dto.UserAnonymizationDTO, error)
+}
+
+// UserAnonymizationService обезличивает уволенных сотрудников. Заявки, история и счётчики продолжают
+// ссылаться на пользователя по id, поэтому статистика не меняется; ФИО в истории заменяется на
+// «Бывший сотрудник #id», а цепочки хэшей затронутых заявок пересчитываются с записью в аудит.
+type UserAnonymizationService struct {
+	txManager             repositories.TxManagerInterface
+	anonRepo              repositories.UserAnonymizationRepositoryInterface
+	userRepo              repositories.UserRepositoryInterface
+	statusRepo            repositories.StatusRepositoryInterface
+	historyRepo           repositories.OrderHistoryRepositoryInterface
+	authPermissionService AuthPermissionServiceInterface
+	auditService          AuditServiceInterface
+	fileStorage           filestorage.FileStorageInterface
+	logger                *zap.Logger
+}
+
+func NewUserAnonymizationService(
+	txManager repositories.TxManagerInterface,
+	anonRepo repositories.UserAnonymizationRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	statusRepo repositories.StatusRepositoryInterface,
+	historyRepo repositories.OrderHistoryRepositoryInterface,
+	authPermissionService AuthPermissionServiceInterface,
+	auditService AuditServiceInterface,
+	fileStorage filestorage.FileStorageInterface,
+	logger *zap.Logger,
+) UserAnonymizationServiceInterface {
+	return &UserAnonymizationService{
+		txManager:             txManager,
+		anonRepo:              anonRepo,
+		userRepo:              userRepo,
+		statusRepo:            statusRepo,
+		historyRepo:           historyRepo,
+		authPermissionService: authPermissionService,
+		auditService:          auditService,
+		fileStorage:           fileStorage,
+		logger:                logger,
+	}
+}
+
+func (s *UserAnonymizationService) Anonymize(ctx context.Context, userID uint64, confirm string, dryRun bool) (*dto.UserAnonymizationDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.UsersAnonymize, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	if userID == authContext.Actor.ID {
+		return nil, apperrors.NewBadRequestError("Нельзя обезличить собственную учётную запись")
+	}
+
+	// Уволенные обычно уже удалены — их тоже можно обезличить
+	user, err := s.userRepo.FindAnyUserByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) || apperrors.IsNotFound(err) {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	anonymized, err := s.anonRepo.IsAnonymized(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if anonymized {
+		return nil, apperrors.NewHttpError(http.StatusConflict, "Пользователь уже обезличен", nil, nil)
+	}
+
+	label := fmt.Sprintf(anonymizedUserLabel, user.ID)
+	result := &dto.UserAnonymizationDTO{
+		UserID:           user.ID,
+		DryRun:           dryRun,
+		Label:            label,
+		PhotoRemoved:     user.PhotoURL != nil && *user.PhotoURL != "",
+		TelegramUnlinked: user.TelegramChatID.Valid,
+	}
+
+	if dryRun {
+		refs, err := s.anonRepo.FindHistoryReferences(ctx, user.ID, user.Fio)
+		if err != nil {
+			return nil, err
+		}
+		result.Fio = user.Fio
+		result.HistoryRows, result.Orders = refs.Rows, len(refs.OrderIDs)
+		return result, nil
+	}
+
+	if strings.TrimSpace(confirm) != strings.TrimSpace(user.Fio) {
+		return nil, apperrors.NewBadRequestError("Обезличивание необратимо: для подтверждения передайте в confirm текущее ФИО пользователя")
+	}
+
+	now := time.Now()
+	resealed := make(map[string]string)
+	brokenChains := make(map[string]string)
+	err = s.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		inactive, err := s.statusRepo.FindByCodeInTx(ctx, tx, "INACTIVE")
+		if err != nil {
+			return err
+		}
+
+		// Цепочки проверяются до правки: пересчёт уже нарушенной цепочки скрыл бы чужую подмену.
+		before, err := s.anonRepo.FindHistoryReferencesInTx(ctx, tx, user.ID, user.Fio)
+		if err != nil {
+			return err
+		}
+		checks := make(map[uint64]*repositories.HistoryChainCheck, len(before.OrderIDs))
+		for _, orderID := range before.OrderIDs {
+			check, err := s.historyRepo.VerifyChainInTx(ctx, tx, orderID)
+			if err != nil {
+				return fmt.Errorf("не удалось проверить цепочку истории заявки %d: %w", orderID, err)
+			}
+			checks[orderID] = check
+		}
+
+		refs, err := s.anonRepo.RelabelHistoryInTx(ctx, tx, user.ID, user.Fio, label)
+		if err != nil {
+			return err
+		}
+		result.HistoryRows, result.Orders = refs.Rows, len(refs.OrderIDs)
+		for _, orderID := range refs.OrderIDs {
+			check, ok := checks[orderID]
+			if !ok {
+				return apperrors.NewHttpError(http.StatusConflict, "История пользователя изменилась во время обезличивания, повторите запрос", nil, nil)
+			}
+			key := strconv.FormatUint(orderID, 10)
+			if !check.Valid {
+				// Обезличивание не откладывается, но нарушенная цепочка остаётся как есть и попадает в аудит.
+				brokenChains[key] = fmt.Sprintf("запись %d: %s", check.FirstBrokenID, check.Reason)
+				s.logger.Warn("Цепочка истории заявки нарушена до обезличивания, пересчёт пропущен",
+					zap.Uint64("orderID", orderID),
+					zap.Uint64("firstBrokenID", check.FirstBrokenID),
+					zap.String("reason", check.Reason))
+				continue
+			}
+			oldHead, err := s.historyRepo.ResealChainInTx(ctx, tx, orderID, check)
+			if err != nil {
+				return fmt.Errorf("не удалось пересчитать цепочку истории заявки %d: %w", orderID, err)
+			}
+			resealed[key] = oldHead
+		}
+		return s.anonRepo.AnonymizeUserInTx(ctx, tx, user.ID, label, inactive.ID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.AnonymizedAt = &now
+
+	if result.PhotoRemoved {
+		s.deletePhoto(user.ID, *user.PhotoURL)
+	}
+	if err := s.authPermissionService.InvalidateUserPermissionsCache(ctx, user.ID); err != nil {
+		s.logger.Warn("Не удалось сбросить кэш прав обезличенного пользователя", zap.Uint64("userID", user.ID), zap.Error(err))
+	}
+
+	// В журнал не попадает ничего из личных данных; прежние хэши заявок сохраняют след пересчёта цепочек.
+	actorID := authContext.Actor.ID
+	entityID := strconv.FormatUint(user.ID, 10)
+	after, _ := json.Marshal(map[string]any{
+		"label":           label,
+		"history_rows":    result.HistoryRows,
+		"resealed_orders": resealed,
+		"broken_chains":   brokenChains,
+	})
+	s.auditService.Record(ctx, &entities.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     entities.AuditActionUpdate,
+		Entity:     "users",
+		EntityID:   &entityID,
+		Method:     http.MethodPost,
+		Path:       fmt.Sprintf("/api/admin/users/%d/anonymize", user.ID),
+		StatusCode: http.StatusOK,
+		After:      after,
+	})
+
+	s.logger.Info("Пользователь обезличен",
+		zap.Uint64("userID", user.ID),
+		zap.Int64("historyRows", result.HistoryRows),
+		zap.Int("orders", result.Orders),
+		zap.Uint64("by", actorID))
+	return result, nil
+}
+
+// deletePhoto удаляет фото вместе с уменьшенными копиями; файлы удаляются после фиксации транзакции.
+func (s *UserAnonymizationService) deletePhoto(userID uint64, photoURL string) {
+	urls := []string{photoURL}
+	for _, size := range avatarVariantSizes {
+		urls = append(urls, filestorage.VariantPath(photoURL, strconv.Itoa(size)))
+	}
+	for _, url := range urls {
+		if err := s.fileStorage.Delete(url); err != nil {
+			s.logger.Warn("Не удалось удалить фото обезличенного пользователя", zap.Uint64("userID", userID), zap.String("photo_url", url), zap.Error(err))
+		}
+	}
+}
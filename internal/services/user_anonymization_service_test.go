@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+type anonymizationRepoStub struct {
+	anonymized bool
+	refs       repositories.UserHistoryReferences
+	relabeled  string
+	label      string
+}
+
+func (s *anonymizationRepoStub) IsAnonymized(context.Context, uint64) (bool, error) {
+	return s.anonymized, nil
+}
+
+func (s *anonymizationRepoStub) FindHistoryReferences(context.Context, uint64, string) (*repositories.UserHistoryReferences, error) {
+	refs := s.refs
+	return &refs, nil
+}
+
+func (s *anonymizationRepoStub) FindHistoryReferencesInTx(ctx context.Context, _ pgx.Tx, userID uint64, fio string) (*repositories.UserHistoryReferences, error) {
+	return s.FindHistoryReferences(ctx, userID, fio)
+}
+
+func (s *anonymizationRepoStub) RelabelHistoryInTx(_ context.Context, _ pgx.Tx, _ uint64, fio, _ string) (*repositories.UserHistoryReferences, error) {
+	s.relabeled = fio
+	refs := s.refs
+	return &refs, nil
+}
+
+func (s *anonymizationRepoStub) AnonymizeUserInTx(_ context.Context, _ pgx.Tx, _ uint64, label string, _ uint64, _ time.Time) error {
+	s.label = label
+	return nil
+}
+
+type anonymizationUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	user *entities.User
+}
+
+func (s *anonymizationUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	return &entities.User{ID: id}, nil
+}
+
+func (s *anonymizationUserRepoStub) FindAnyUserByID(context.Context, uint64) (*entities.User, error) {
+	return s.user, nil
+}
+
+type anonymizationStatusRepoStub struct {
+	repositories.StatusRepositoryInterface
+}
+
+func (anonymizationStatusRepoStub) FindByCodeInTx(_ context.Context, _ pgx.Tx, code string) (*entities.Status, error) {
+	return &entities.Status{ID: 2}, nil
+}
+
+type anonymizationPermissionsStub struct {
+	AuthPermissionServiceInterface
+	invalidated []uint64
+}
+
+func (s *anonymizationPermissionsStub) InvalidateUserPermissionsCache(_ context.Context, userID uint64) error {
+	s.invalidated = append(s.invalidated, userID)
+	return nil
+}
+
+type anonymizationAuditStub struct {
+	AuditServiceInterface
+	entries []*entities.AuditLogEntry
+}
+
+func (s *anonymizationAuditStub) Record(_ context.Context, entry *entities.AuditLogEntry) {
+	s.entries = append(s.entries, entry)
+}
+
+type anonymizationFixture struct {
+	repo    *anonymizationRepoStub
+	history *orderHistoryRepoStub
+	perms   *anonymizationPermissionsStub
+	audit   *anonymizationAuditStub
+	files   *avatarFileStorageStub
+	service UserAnonymizationServiceInterface
+}
+
+func newAnonymizationFixture(user *entities.User) *anonymizationFixture {
+	f := &anonymizationFixture{
+		repo:    &anonymizationRepoStub{refs: repositories.UserHistoryReferences{Rows: 5, OrderIDs: []uint64{10, 11}}},
+		history: &orderHistoryRepoStub{head: sql.NullString{String: "old-head", Valid: true}},
+		perms:   &anonymizationPermissionsStub{},
+		audit:   &anonymizationAuditStub{},
+		files:   &avatarFileStorageStub{files: map[string][]byte{}},
+	}
+	f.service = NewUserAnonymizationService(avatarTxManagerStub{}, f.repo, &anonymizationUserRepoStub{user: user},
+		anonymizationStatusRepoStub{}, f.history, f.perms, f.audit, f.files, zap.NewNop())
+	return f
+}
+
+func anonymizationCtx() context.Context {
+	return jobsAdminCtx(map[string]bool{authz.UsersAnonymize: true})
+}
+
+func TestAnonymize_ChecksPermissionAndTarget(t *testing.T) {
+	f := newAnonymizationFixture(&entities.User{ID: 7, Fio: "Иванов Иван"})
+
+	if _, err := f.service.Anonymize(jobsAdminCtx(map[string]bool{}), 7, "", true); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Fatalf("expected forbidden without %s, got %v", authz.UsersAnonymize, err)
+	}
+	if _, err := f.service.Anonymize(anonymizationCtx(), 1, "", true); err == nil {
+		t.Fatal("expected an error when anonymizing yourself")
+	}
+
+	f.repo.anonymized = true
+	_, err := f.service.Anonymize(anonymizationCtx(), 7, "Иванов Иван", false)
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an already anonymized user, got %v", err)
+	}
+}
+
+func TestAnonymize_DryRunChangesNothing(t *testing.T) {
+	photo := "avatars/7.png"
+	f := newAnonymizationFixture(&entities.User{ID: 7, Fio: "Иванов Иван", PhotoURL: &photo})
+
+	result, err := f.service.Anonymize(anonymizationCtx(), 7, "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DryRun || result.Fio != "Иванов Иван" || result.Label != "Бывший сотрудник #7" ||
+		result.HistoryRows != 5 || result.Orders != 2 || !result.PhotoRemoved || result.AnonymizedAt != nil {
+		t.Fatalf("unexpected preview: %+v", result)
+	}
+	if f.repo.label != "" || len(f.history.resealed) != 0 || len(f.files.deleted) != 0 || len(f.audit.entries) != 0 {
+		t.Fatal("dry run must not change anything")
+	}
+}
+
+func TestAnonymize_RequiresConfirmationAndReseals(t *testing.T) {
+	photo := "avatars/7.png"
+	f := newAnonymizationFixture(&entities.User{ID: 7, Fio: "Иванов Иван", PhotoURL: &photo})
+
+	if _, err := f.service.Anonymize(anonymizationCtx(), 7, "Иванов", false); err == nil {
+		t.Fatal("expected an error when confirm does not match the FIO")
+	}
+	if f.repo.label != "" {
+		t.Fatal("user must not be anonymized without confirmation")
+	}
+
+	result, err := f.service.Anonymize(anonymizationCtx(), 7, " Иванов Иван ", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Fio != "" || result.AnonymizedAt == nil || f.repo.relabeled != "Иванов Иван" || f.repo.label != "Бывший сотрудник #7" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(f.history.resealed) != 2 || f.history.resealed[0] != 10 || f.history.resealed[1] != 11 {
+		t.Fatalf("expected both orders to be resealed, got %v", f.history.resealed)
+	}
+	if len(f.files.deleted) != 1+len(avatarVariantSizes) || f.files.deleted[0] != photo {
+		t.Fatalf("expected the photo and its variants to be deleted, got %v", f.files.deleted)
+	}
+	if len(f.perms.invalidated) != 1 || len(f.audit.entries) != 1 {
+		t.Fatal("expected permissions cache invalidation and one audit entry")
+	}
+	if after := string(f.audit.entries[0].After); !strings.Contains(after, "old-head") || strings.Contains(after, "Иванов") {
+		t.Fatalf("audit entry must keep old chain heads and no personal data: %s", after)
+	}
+}
+
+func TestAnonymize_SkipsResealOfBrokenChainAndAuditsIt(t *testing.T) {
+	f := newAnonymizationFixture(&entities.User{ID: 7, Fio: "Иванов Иван"})
+	f.history.broken = map[uint64]bool{11: true}
+
+	if _, err := f.service.Anonymize(anonymizationCtx(), 7, "Иванов Иван", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(f.history.resealed) != 1 || f.history.resealed[0] != 10 {
+		t.Fatalf("expected only the intact chain to be resealed, got %v", f.history.resealed)
+	}
+	if len(f.audit.entries) != 1 || !strings.Contains(string(f.audit.entries[0].After), `"broken_chains":{"11"`) {
+		t.Fatalf("expected the broken chain in the audit entry, got %+v", f.audit.entries)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
+	"request-system/pkg/utils"
+)
+
+// userLanguageCacheTTL — сколько живёт язык пользователя в кэше. Язык, выбранный в Telegram-боте,
+// пишется в БД напрямую, поэтому в веб-ответах он применяется не позже чем через это время.
+const userLanguageCacheTTL = 5 * time.Minute
+
+type UserLanguageServiceInterface interface {
+	// Resolve возвращает сохранённый язык пользователя; используется на каждый запрос.
+	Resolve(ctx context.Context, userID uint64) (string, error)
+	GetMyLanguage(ctx context.Context) (*dto.UserLanguageDTO, error)
+	UpdateMyLanguage(ctx context.Context, d dto.UpdateUserLanguageDTO) (*dto.UserLanguageDTO, error)
+}
+
+// UserLanguageService хранит выбор языка ответов API. Язык общий с Telegram-ботом (users.language).
+type UserLanguageService struct {
+	userRepo  repositories.UserRepositoryInterface
+	cacheRepo repositories.CacheRepositoryInterface
+	logger    *zap.Logger
+}
+
+func NewUserLanguageService(
+	userRepo repositories.UserRepositoryInterface,
+	cacheRepo repositories.CacheRepositoryInterface,
+	logger *zap.Logger,
+) UserLanguageServiceInterface {
+	return &UserLanguageService{userRepo: userRepo, cacheRepo: cacheRepo, logger: logger}
+}
+
+func userLanguageCacheKey(userID uint64) string {
+	return fmt.Sprintf("user:language:%d", userID)
+}
+
+func (s *UserLanguageService) Resolve(ctx context.Context, userID uint64) (string, error) {
+	key := userLanguageCacheKey(userID)
+	if cached, err := s.cacheRepo.Get(ctx, key); err == nil && i18n.IsSupported(cached) {
+		return cached, nil
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	lang := i18n.Normalize(user.Language)
+	if err := s.cacheRepo.Set(ctx, key, lang, userLanguageCacheTTL); err != nil {
+		s.logger.Warn("Не удалось закэшировать язык пользователя", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+	return lang, nil
+}
+
+func (s *UserLanguageService) GetMyLanguage(ctx context.Context) (*dto.UserLanguageDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := s.Resolve(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserLanguageDTO(lang), nil
+}
+
+func (s *UserLanguageService) UpdateMyLanguage(ctx context.Context, d dto.UpdateUserLanguageDTO) (*dto.UserLanguageDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !i18n.IsSupported(d.Language) {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Неподдерживаемый язык", nil, nil)
+	}
+
+	if err := s.userRepo.UpdateLanguage(ctx, userID, d.Language); err != nil {
+		return nil, err
+	}
+	if err := s.cacheRepo.Del(ctx, userLanguageCacheKey(userID)); err != nil {
+		s.logger.Warn("Не удалось сбросить кэш языка пользователя", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+	return toUserLanguageDTO(d.Language), nil
+}
+
+func toUserLanguageDTO(lang string) *dto.UserLanguageDTO {
+	return &dto.UserLanguageDTO{Language: lang, Supported: i18n.LangNames}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
+	"request-system/pkg/validation"
+)
+
+type languageUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	language string
+	loads    int
+}
+
+func (s *languageUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	s.loads++
+	return &entities.User{ID: id, Language: s.language}, nil
+}
+
+func (s *languageUserRepoStub) UpdateLanguage(_ context.Context, _ uint64, lang string) error {
+	s.language = lang
+	return nil
+}
+
+func TestUserLanguage_ResolveIsCachedAndResetOnUpdate(t *testing.T) {
+	repo := &languageUserRepoStub{language: "tg"}
+	service := NewUserLanguageService(repo, &memoryCache{values: map[string]string{}}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(7))
+
+	for i := 0; i < 2; i++ {
+		lang, err := service.Resolve(ctx, 7)
+		if err != nil || lang != i18n.LangTG {
+			t.Fatalf("resolve = %q, %v; want tg", lang, err)
+		}
+	}
+	if repo.loads != 1 {
+		t.Fatalf("user loaded %d times, want 1 (cached)", repo.loads)
+	}
+
+	if _, err := service.UpdateMyLanguage(ctx, dto.UpdateUserLanguageDTO{Language: i18n.LangEN}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if lang, _ := service.Resolve(ctx, 7); lang != i18n.LangEN {
+		t.Fatalf("after update resolve = %q, want en", lang)
+	}
+}
+
+func TestUserLanguage_RejectsUnsupportedLanguage(t *testing.T) {
+	service := NewUserLanguageService(&languageUserRepoStub{language: "ru"}, &memoryCache{values: map[string]string{}}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(7))
+
+	_, err := service.UpdateMyLanguage(ctx, dto.UpdateUserLanguageDTO{Language: "fr"})
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != 400 {
+		t.Fatalf("want 400, got %v", err)
+	}
+	if got := httpErr.LocalizedMessage(i18n.LangEN); got != "Unsupported language" {
+		t.Fatalf("english message = %q", got)
+	}
+}
+
+func TestUserLanguage_ValidationErrorIsLocalized(t *testing.T) {
+	err := validation.New().Validate(&dto.UpdateUserLanguageDTO{})
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != 400 {
+		t.Fatalf("validation must return 400, got %v", err)
+	}
+
+	want := map[string]string{
+		i18n.LangRU: "поле 'Язык' обязательно для заполнения",
+		i18n.LangTG: "майдони 'Забон' бояд пур карда шавад",
+		i18n.LangEN: "field 'Language' is required",
+	}
+	for lang, text := range want {
+		if got := httpErr.LocalizedMessage(lang); got != text {
+			t.Errorf("%s: got %q, want %q", lang, got, text)
+		}
+	}
+}
+
+func TestUserLanguage_AcceptLanguagePicksBestSupported(t *testing.T) {
+	cases := map[string]string{
+		"en-US,en;q=0.9":         i18n.LangEN,
+		"de, ru;q=0.5, tg;q=0.8": i18n.LangTG,
+		"tg-TJ;q=0.2, fr":        i18n.LangTG,
+		"de,fr":                  "",
+		"ru;q=0, en;q=0.1":       i18n.LangEN,
+		"":                       "",
+	}
+	for header, want := range cases {
+		if got := i18n.ParseAcceptLanguage(header); got != want {
+			t.Errorf("%q: got %q, want %q", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/contextkeys"
+)
+
+type telegramLinkUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	users map[uint64]*entities.User
+}
+
+func (s *telegramLinkUserRepoStub) FindUserByTelegramChatID(_ context.Context, chatID int64) (*entities.User, error) {
+	for _, user := range s.users {
+		if user.TelegramChatID.Valid && user.TelegramChatID.Int64 == chatID {
+			return user, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (s *telegramLinkUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (s *telegramLinkUserRepoStub) UpdateTelegramChatID(_ context.Context, userID uint64, chatID int64) error {
+	s.users[userID].TelegramChatID = sql.NullInt64{Int64: chatID, Valid: true}
+	return nil
+}
+
+func (s *telegramLinkUserRepoStub) UpdateTelegramChatIDTx(ctx context.Context, _ pgx.Tx, userID uint64, chatID int64) error {
+	return s.UpdateTelegramChatID(ctx, userID, chatID)
+}
+
+func (s *telegramLinkUserRepoStub) ClearTelegramChatID(_ context.Context, _ pgx.Tx, userID uint64) error {
+	s.users[userID].TelegramChatID = sql.NullInt64{}
+	s.users[userID].TelegramLastSeenAt = nil
+	return nil
+}
+
+func newTelegramLinkTestService(users map[uint64]*entities.User) (UserServiceInterface, *memoryCache) {
+	cache := &memoryCache{values: map[string]string{}}
+	service := NewUserService(avatarTxManagerStub{}, &telegramLinkUserRepoStub{users: users}, nil, nil, nil, nil, cache, nil,
+		config.TelegramConfig{LinkTokenTTL: 3 * time.Minute}, zap.NewNop())
+	return service, cache
+}
+
+func TestTelegramLinkTokenIsSingleUseAndRevokedByNewToken(t *testing.T) {
+	service, _ := newTelegramLinkTestService(map[uint64]*entities.User{7: {ID: 7}, 8: {ID: 8}})
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(7))
+
+	first, err := service.GenerateTelegramLinkToken(ctx)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if first.ExpiresInSeconds != 180 {
+		t.Errorf("expires_in_seconds = %d, want 180", first.ExpiresInSeconds)
+	}
+	second, err := service.GenerateTelegramLinkToken(ctx)
+	if err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+
+	if err := service.ConfirmTelegramLink(context.Background(), first.ShortCode, 100, false); err == nil {
+		t.Fatal("the previous code must be revoked once a new one is issued")
+	}
+	if err := service.ConfirmTelegramLink(context.Background(), second.Token, 100, false); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	// Короткий код и UUID — один и тот же код: после использования не работает ни один из них.
+	if err := service.ConfirmTelegramLink(context.Background(), second.ShortCode, 200, false); err == nil {
+		t.Fatal("a used code must not link another chat")
+	}
+}
+
+func TestTelegramLinkAsksBeforeReplacingAnotherUser(t *testing.T) {
+	users := map[uint64]*entities.User{
+		7: {ID: 7, Fio: "Иванов И.И.", TelegramChatID: sql.NullInt64{Int64: 100, Valid: true}},
+		8: {ID: 8},
+	}
+	service, _ := newTelegramLinkTestService(users)
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(8))
+
+	link, err := service.GenerateTelegramLinkToken(ctx)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	err = service.ConfirmTelegramLink(context.Background(), link.ShortCode, 100, false)
+	var rebindErr *TelegramRebindRequiredError
+	if !errors.As(err, &rebindErr) || rebindErr.LinkedUserFio != "Иванов И.И." {
+		t.Fatalf("expected a rebind confirmation, got %v", err)
+	}
+	if users[8].TelegramChatID.Valid || !users[7].TelegramChatID.Valid {
+		t.Fatal("the link must not change before confirmation")
+	}
+
+	if err := service.ConfirmTelegramLink(context.Background(), link.ShortCode, 100, true); err != nil {
+		t.Fatalf("confirmed replace: %v", err)
+	}
+	if users[7].TelegramChatID.Valid || users[8].TelegramChatID.Int64 != 100 {
+		t.Fatalf("chat was not moved: %+v %+v", users[7].TelegramChatID, users[8].TelegramChatID)
+	}
+}
+
+func TestUnlinkTelegramRevokesCodeAndResetsChannel(t *testing.T) {
+	seen := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	users := map[uint64]*entities.User{
+		7: {ID: 7, TelegramChatID: sql.NullInt64{Int64: 100, Valid: true}, TelegramLastSeenAt: &seen},
+	}
+	service, _ := newTelegramLinkTestService(users)
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(7))
+
+	status, err := service.GetTelegramLinkStatus(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !status.Linked || status.LastInteractionAt == nil || !status.LastInteractionAt.Equal(seen) {
+		t.Fatalf("unexpected status before unlink %+v", status)
+	}
+
+	link, err := service.GenerateTelegramLinkToken(ctx)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := service.UnlinkTelegram(ctx); err != nil {
+		t.Fatalf("unlink: %v", err)
+	}
+	if channel := telegramChannelOf(users[7]); channel.Linked || channel.LastInteractionAt != nil {
+		t.Fatalf("channel must be reset after unlink: %+v", channel)
+	}
+	if err := service.ConfirmTelegramLink(context.Background(), link.ShortCode, 200, false); err == nil {
+		t.Fatal("a code issued before unlink must not link the account again")
+	}
+}
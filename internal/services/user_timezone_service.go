@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type UserTimezoneServiceInterface interface {
+	// Resolve возвращает имя пояса пользователя; пустая строка — пояс сервера.
+	Resolve(ctx context.Context, userID uint64) (string, error)
+	GetMyTimezone(ctx context.Context) (*dto.UserTimezoneDTO, error)
+	UpdateMyTimezone(ctx context.Context, d dto.UpdateUserTimezoneDTO) (*dto.UserTimezoneDTO, error)
+}
+
+// UserTimezoneService хранит часовой пояс пользователя (users.timezone). В нём форматируются
+// сроки в боте, уведомлениях и выгрузках и разбираются даты, введённые в боте.
+type UserTimezoneService struct {
+	userRepo  repositories.UserRepositoryInterface
+	cacheRepo repositories.CacheRepositoryInterface
+	logger    *zap.Logger
+}
+
+func NewUserTimezoneService(
+	userRepo repositories.UserRepositoryInterface,
+	cacheRepo repositories.CacheRepositoryInterface,
+	logger *zap.Logger,
+) UserTimezoneServiceInterface {
+	return &UserTimezoneService{userRepo: userRepo, cacheRepo: cacheRepo, logger: logger}
+}
+
+func userTimezoneCacheKey(userID uint64) string {
+	return fmt.Sprintf("user:timezone:%d", userID)
+}
+
+func (s *UserTimezoneService) Resolve(ctx context.Context, userID uint64) (string, error) {
+	key := userTimezoneCacheKey(userID)
+	if cached, err := s.cacheRepo.Get(ctx, key); err == nil {
+		return cached, nil
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	var name string
+	if user.Timezone != nil {
+		name = *user.Timezone
+	}
+	// Кэш живёт столько же, сколько кэш языка: пояс меняется только через этот сервис.
+	if err := s.cacheRepo.Set(ctx, key, name, userLanguageCacheTTL); err != nil {
+		s.logger.Warn("Не удалось закэшировать часовой пояс пользователя", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+	return name, nil
+}
+
+func (s *UserTimezoneService) GetMyTimezone(ctx context.Context) (*dto.UserTimezoneDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := s.Resolve(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return toUserTimezoneDTO(nil), nil
+	}
+	return toUserTimezoneDTO(&name), nil
+}
+
+func (s *UserTimezoneService) UpdateMyTimezone(ctx context.Context, d dto.UpdateUserTimezoneDTO) (*dto.UserTimezoneDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var timezone *string
+	if d.Timezone != nil && strings.TrimSpace(*d.Timezone) != "" {
+		name := strings.TrimSpace(*d.Timezone)
+		if !utils.IsValidTimezone(name) {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest, "Неизвестный часовой пояс", nil, map[string]interface{}{"timezone": name})
+		}
+		timezone = &name
+	}
+
+	if err := s.userRepo.UpdateTimezone(ctx, userID, timezone); err != nil {
+		return nil, err
+	}
+	if err := s.cacheRepo.Del(ctx, userTimezoneCacheKey(userID)); err != nil {
+		s.logger.Warn("Не удалось сбросить кэш часового пояса пользователя", zap.Uint64("user_id", userID), zap.Error(err))
+	}
+	return toUserTimezoneDTO(timezone), nil
+}
+
+func toUserTimezoneDTO(timezone *string) *dto.UserTimezoneDTO {
+	return &dto.UserTimezoneDTO{Timezone: timezone, Effective: utils.UserLocation(timezone).String()}
+}
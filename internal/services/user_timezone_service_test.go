@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+type timezoneUserRepoStub struct {
+	repositories.UserRepositoryInterface
+	timezone *string
+	loads    int
+}
+
+func (s *timezoneUserRepoStub) FindUserByID(_ context.Context, id uint64) (*entities.User, error) {
+	s.loads++
+	return &entities.User{ID: id, Timezone: s.timezone}, nil
+}
+
+func (s *timezoneUserRepoStub) UpdateTimezone(_ context.Context, _ uint64, timezone *string) error {
+	s.timezone = timezone
+	return nil
+}
+
+func TestUserTimezone_UpdateValidatesAndResetsCache(t *testing.T) {
+	repo := &timezoneUserRepoStub{}
+	service := NewUserTimezoneService(repo, &memoryCache{values: map[string]string{}}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(3))
+
+	if name, err := service.Resolve(ctx, 3); err != nil || name != "" {
+		t.Fatalf("without a timezone resolve = %q, %v; want server zone", name, err)
+	}
+
+	bad := "Mars/Olympus"
+	_, err := service.UpdateMyTimezone(ctx, dto.UpdateUserTimezoneDTO{Timezone: &bad})
+	var httpErr *apperrors.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Code != 400 {
+		t.Fatalf("unknown zone must be rejected with 400, got %v", err)
+	}
+
+	zone := " Asia/Dushanbe "
+	res, err := service.UpdateMyTimezone(ctx, dto.UpdateUserTimezoneDTO{Timezone: &zone})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if res.Timezone == nil || *res.Timezone != "Asia/Dushanbe" || res.Effective != "Asia/Dushanbe" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if name, _ := service.Resolve(ctx, 3); name != "Asia/Dushanbe" {
+		t.Fatalf("cache must be reset after update, resolve = %q", name)
+	}
+
+	if res, err = service.UpdateMyTimezone(ctx, dto.UpdateUserTimezoneDTO{}); err != nil || res.Timezone != nil {
+		t.Fatalf("null must reset the zone: %+v, %v", res, err)
+	}
+	if repo.loads != 2 {
+		t.Fatalf("user loaded %d times, want 2", repo.loads)
+	}
+}
+
+func TestUserTimezone_LocationAppliesToDeadlines(t *testing.T) {
+	zone := "Asia/Dushanbe"
+	deadline := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	if got := deadline.In(utils.UserLocation(&zone)).Format("02.01.2006 15:04"); got != "16.10.2026 15:00" {
+		t.Fatalf("deadline in Dushanbe = %s", got)
+	}
+
+	unknown := "Nowhere/Zone"
+	if utils.UserLocation(&unknown) != time.Local || utils.UserLocation(nil) != time.Local {
+		t.Fatal("unknown or empty zone must fall back to the server zone")
+	}
+	if loc := utils.LocationFromCtx(context.Background()); loc != time.Local {
+		t.Fatalf("context without a zone must use the server zone, got %s", loc)
+	}
+}
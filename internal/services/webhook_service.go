@@ -0,0 +1,586 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/types"
+)
+
+const (
+	webhookPollInterval   = 5 * time.Second
+	webhookBatchSize      = 20
+	webhookLease          = 2 * time.Minute
+	webhookDefaultRetries = 10
+	webhookRequestTimeout = 10 * time.Second
+	// Сколько байт ответа сохраняем в журнал попытки.
+	webhookResponseLogLimit = 1024
+)
+
+// Заголовки исходящего запроса. Подпись: hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+// webhookEventsByHistoryType — какое внешнее событие соответствует записи истории заявки.
+var webhookEventsByHistoryType = map[string]string{
+	"CREATE":          "order.created",
+	"STATUS_CHANGE":   "order.status_changed",
+	"PRIORITY_CHANGE": "order.priority_changed",
+	"DELEGATION":      "order.delegated",
+	"COMMENT":         "order.commented",
+	"DURATION_CHANGE": "order.duration_changed",
+	"ATTACHMENT_ADD":  "order.attachment_added",
+	"MERGED_FROM":     "order.merged",
+}
+
+// WebhookEventTypes — события, на которые можно подписаться.
+var WebhookEventTypes = []string{
+	"order.created", "order.status_changed", "order.priority_changed", "order.delegated",
+	"order.commented", "order.duration_changed", "order.attachment_added", "order.merged",
+}
+
+// WebhookEventForHistory возвращает имя внешнего события для типа записи истории.
+func WebhookEventForHistory(historyEventType string) (string, bool) {
+	name, ok := webhookEventsByHistoryType[historyEventType]
+	return name, ok
+}
+
+// SignWebhookPayload считает подпись тела запроса, которую получатель сверяет со своим секретом.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type webhookEnvelope struct {
+	ID        uuid.UUID   `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookServiceInterface interface {
+	CreateSubscription(ctx context.Context, d dto.CreateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionDTO, error)
+	UpdateSubscription(ctx context.Context, id uint64, d dto.UpdateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionDTO, error)
+	DeleteSubscription(ctx context.Context, id uint64) error
+	GetSubscriptions(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.WebhookSubscriptionDTO], error)
+	GetSubscription(ctx context.Context, id uint64) (*dto.WebhookSubscriptionDTO, error)
+	GetDeliveries(ctx context.Context, subscriptionID uint64, status string, filter types.Filter) (*dto.PaginatedResponse[dto.WebhookDeliveryDTO], error)
+	GetDelivery(ctx context.Context, id uint64) (*dto.WebhookDeliveryDetailsDTO, error)
+	Redeliver(ctx context.Context, id uint64) error
+
+	EnqueueEvent(ctx context.Context, eventID uuid.UUID, eventType string, data interface{}) error
+	StartWorker(ctx context.Context)
+	ProcessDue(ctx context.Context) (int, error)
+}
+
+type WebhookService struct {
+	repo       repositories.WebhookRepositoryInterface
+	userRepo   repositories.UserRepositoryInterface
+	httpClient *http.Client
+	logger     *zap.Logger
+	wakeup     chan struct{}
+
+	// allowedNetworks — внутренние адреса, на которые всё же разрешено слать webhook
+	allowedNetworks []*net.IPNet
+}
+
+func NewWebhookService(
+	repo repositories.WebhookRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	allowedNetworks []*net.IPNet,
+	logger *zap.Logger,
+) WebhookServiceInterface {
+	return &WebhookService{
+		repo:       repo,
+		userRepo:   userRepo,
+		httpClient: newWebhookHTTPClient(allowedNetworks),
+		logger:     logger,
+		wakeup:     make(chan struct{}, 1),
+
+		allowedNetworks: allowedNetworks,
+	}
+}
+
+func (s *WebhookService) checkManage(ctx context.Context) (*authz.Context, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.WebhooksManage, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+	return authContext, nil
+}
+
+func validateWebhookEventTypes(eventTypes []string) ([]string, error) {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return nil, apperrors.NewHttpError(http.StatusBadRequest,
+				fmt.Sprintf("Неизвестный тип события: %s", eventType), nil, nil)
+		}
+		if !slices.Contains(result, eventType) {
+			result = append(result, eventType)
+		}
+	}
+	return result, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func toWebhookSubscriptionDTO(e *entities.WebhookSubscription, withSecret bool) dto.WebhookSubscriptionDTO {
+	result := dto.WebhookSubscriptionDTO{
+		ID:         e.ID,
+		Name:       e.Name,
+		URL:        e.URL,
+		EventTypes: e.EventTypes,
+		IsActive:   e.IsActive,
+		CreatedBy:  e.CreatedBy,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  e.UpdatedAt.Format(time.RFC3339),
+	}
+	if withSecret {
+		result.Secret = e.Secret
+	}
+	return result
+}
+
+func toWebhookDeliveryDTO(e *entities.WebhookDelivery) dto.WebhookDeliveryDTO {
+	result := dto.WebhookDeliveryDTO{
+		ID:             e.ID,
+		SubscriptionID: e.SubscriptionID,
+		EventID:        e.EventID.String(),
+		EventType:      e.EventType,
+		Payload:        e.Payload,
+		Status:         e.Status,
+		Attempts:       e.Attempts,
+		MaxAttempts:    e.MaxAttempts,
+		NextAttemptAt:  e.NextAttemptAt.Format(time.RFC3339),
+		LastStatusCode: e.LastStatusCode,
+		LastError:      e.LastError,
+		CreatedAt:      e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.DeliveredAt != nil {
+		deliveredAt := e.DeliveredAt.Format(time.RFC3339)
+		result.DeliveredAt = &deliveredAt
+	}
+	return result
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, d dto.CreateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateWebhookURL(ctx, d.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateWebhookEventTypes(d.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	secret := d.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	sub := &entities.WebhookSubscription{
+		Name:       d.Name,
+		URL:        d.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		IsActive:   d.IsActive == nil || *d.IsActive,
+		CreatedBy:  &authContext.Actor.ID,
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Создана webhook-подписка", zap.Uint64("subscriptionID", sub.ID), zap.String("url", sub.URL), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toWebhookSubscriptionDTO(sub, true)
+	return &result, nil
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id uint64, d dto.UpdateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionDTO, error) {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.repo.FindSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Name != nil {
+		sub.Name = *d.Name
+	}
+	if d.URL != nil {
+		if err := s.validateWebhookURL(ctx, *d.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *d.URL
+	}
+	if d.EventTypes != nil {
+		if sub.EventTypes, err = validateWebhookEventTypes(*d.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if d.IsActive != nil {
+		sub.IsActive = *d.IsActive
+	}
+	if d.RotateSecret {
+		if sub.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Обновлена webhook-подписка", zap.Uint64("subscriptionID", id), zap.Bool("secretRotated", d.RotateSecret), zap.Uint64("by", authContext.Actor.ID))
+
+	result := toWebhookSubscriptionDTO(sub, d.RotateSecret)
+	return &result, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uint64) error {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Удалена webhook-подписка", zap.Uint64("subscriptionID", id), zap.Uint64("by", authContext.Actor.ID))
+	return nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context, filter types.Filter) (*dto.PaginatedResponse[dto.WebhookSubscriptionDTO], error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+
+	subs, total, err := s.repo.FindSubscriptions(ctx, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dto.WebhookSubscriptionDTO, 0, len(subs))
+	for i := range subs {
+		list = append(list, toWebhookSubscriptionDTO(&subs[i], false))
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+	return &dto.PaginatedResponse[dto.WebhookSubscriptionDTO]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id uint64) (*dto.WebhookSubscriptionDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.FindSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := toWebhookSubscriptionDTO(sub, false)
+	return &result, nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID uint64, status string, filter types.Filter) (*dto.PaginatedResponse[dto.WebhookDeliveryDTO], error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.repo.FindDeliveries(ctx, subscriptionID, status, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dto.WebhookDeliveryDTO, 0, len(deliveries))
+	for i := range deliveries {
+		list = append(list, toWebhookDeliveryDTO(&deliveries[i]))
+	}
+
+	var currentPage uint64 = 1
+	if filter.Limit > 0 {
+		currentPage = (uint64(filter.Offset) / uint64(filter.Limit)) + 1
+	}
+	return &dto.PaginatedResponse[dto.WebhookDeliveryDTO]{
+		List: list,
+		Pagination: dto.PaginationObject{
+			TotalCount: total,
+			Page:       currentPage,
+			Limit:      uint64(filter.Limit),
+		},
+	}, nil
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, id uint64) (*dto.WebhookDeliveryDetailsDTO, error) {
+	if _, err := s.checkManage(ctx); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.FindDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.FindAttempts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.WebhookDeliveryDetailsDTO{
+		WebhookDeliveryDTO: toWebhookDeliveryDTO(delivery),
+		AttemptLog:         make([]dto.WebhookDeliveryAttemptDTO, 0, len(attempts)),
+	}
+	for _, a := range attempts {
+		result.AttemptLog = append(result.AttemptLog, dto.WebhookDeliveryAttemptDTO{
+			Attempt:      a.Attempt,
+			StatusCode:   a.StatusCode,
+			Error:        a.Error,
+			ResponseBody: a.ResponseBody,
+			DurationMs:   a.DurationMs,
+			CreatedAt:    a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return result, nil
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, id uint64) error {
+	authContext, err := s.checkManage(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Redeliver(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Webhook-доставка поставлена в очередь повторно", zap.Uint64("deliveryID", id), zap.Uint64("by", authContext.Actor.ID))
+	s.notify()
+	return nil
+}
+
+func (s *WebhookService) EnqueueEvent(ctx context.Context, eventID uuid.UUID, eventType string, data interface{}) error {
+	payload, err := json.Marshal(webhookEnvelope{ID: eventID, Event: eventType, CreatedAt: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	created, err := s.repo.EnqueueForEvent(ctx, eventID, eventType, payload, webhookDefaultRetries)
+	if err != nil {
+		return err
+	}
+	if created > 0 {
+		s.notify()
+	}
+	return nil
+}
+
+// notify будит воркер, чтобы событие ушло сразу, а не на следующем тике.
+func (s *WebhookService) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *WebhookService) StartWorker(ctx context.Context) {
+	s.logger.Info("Воркер webhook-доставок запущен")
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Воркер webhook-доставок остановлен")
+			return
+		case <-ticker.C:
+		case <-s.wakeup:
+		}
+
+		for {
+			processed, err := s.ProcessDue(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Error("Ошибка обработки webhook-доставок", zap.Error(err))
+				}
+				break
+			}
+			if processed < webhookBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// webhookDeliveryError — неуспешная попытка; Permanent означает, что повторять бессмысленно.
+type webhookDeliveryError struct {
+	StatusCode *int
+	Message    string
+	Permanent  bool
+}
+
+func (e *webhookDeliveryError) Error() string { return e.Message }
+
+func (s *WebhookService) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[uint64]*entities.WebhookSubscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = s.repo.FindSubscriptionByID(ctx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+				s.logger.Error("Не удалось загрузить webhook-подписку", zap.Uint64("subscriptionID", delivery.SubscriptionID), zap.Error(err))
+				continue
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+
+		var deliverErr *webhookDeliveryError
+		var statusCode int
+		if sub == nil || !sub.IsActive {
+			deliverErr = &webhookDeliveryError{Message: "подписка отключена", Permanent: true}
+		} else {
+			statusCode, deliverErr = s.deliver(ctx, sub, delivery)
+		}
+
+		if deliverErr == nil {
+			if err := s.repo.MarkDelivered(ctx, delivery.ID, statusCode); err != nil {
+				s.logger.Error("Не удалось отметить webhook доставленным", zap.Uint64("deliveryID", delivery.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		if deliverErr.Permanent || delivery.Attempts >= delivery.MaxAttempts {
+			s.logger.Warn("Webhook не доставлен",
+				zap.Uint64("deliveryID", delivery.ID),
+				zap.Uint64("subscriptionID", delivery.SubscriptionID),
+				zap.Int("attempts", delivery.Attempts),
+				zap.String("error", deliverErr.Message))
+			if err := s.repo.MarkFailed(ctx, delivery.ID, deliverErr.StatusCode, deliverErr.Message); err != nil {
+				s.logger.Error("Не удалось отметить webhook недоставленным", zap.Uint64("deliveryID", delivery.ID), zap.Error(err))
+			}
+			continue
+		}
+
+		next := time.Now().Add(outboxBackoff(delivery.Attempts))
+		if err := s.repo.MarkRetry(ctx, delivery.ID, next, deliverErr.StatusCode, deliverErr.Message); err != nil {
+			s.logger.Error("Не удалось запланировать повтор webhook", zap.Uint64("deliveryID", delivery.ID), zap.Error(err))
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// deliver отправляет одну попытку и пишет её в журнал.
+func (s *WebhookService) deliver(ctx context.Context, sub *entities.WebhookSubscription, delivery *entities.WebhookDelivery) (int, *webhookDeliveryError) {
+	started := time.Now()
+	statusCode, responseBody, deliverErr := s.send(ctx, sub, delivery)
+
+	attempt := &entities.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		DurationMs: int(time.Since(started).Milliseconds()),
+	}
+	if statusCode > 0 {
+		attempt.StatusCode = &statusCode
+	}
+	if responseBody != "" {
+		attempt.ResponseBody = &responseBody
+	}
+	if deliverErr != nil {
+		attempt.Error = &deliverErr.Message
+	}
+	if err := s.repo.CreateAttempt(ctx, attempt); err != nil {
+		s.logger.Error("Не удалось записать попытку webhook-доставки", zap.Uint64("deliveryID", delivery.ID), zap.Error(err))
+	}
+	return statusCode, deliverErr
+}
+
+func (s *WebhookService) send(ctx context.Context, sub *entities.WebhookSubscription, delivery *entities.WebhookDelivery) (int, string, *webhookDeliveryError) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", &webhookDeliveryError{Message: err.Error(), Permanent: true}
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "request-system-webhooks/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	req.Header.Set(WebhookHeaderDelivery, delivery.EventID.String())
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		// Хост подписки стал указывать во внутреннюю сеть — повторы не помогут
+		return 0, "", &webhookDeliveryError{Message: err.Error(), Permanent: errors.Is(err, errWebhookTargetBlocked)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLogLimit))
+	statusCode := resp.StatusCode
+	if statusCode >= 200 && statusCode < 300 {
+		return statusCode, string(body), nil
+	}
+
+	// 4xx (кроме таймаута и rate limit) — ошибка конфигурации получателя, повторы не помогут.
+	permanent := statusCode >= 400 && statusCode < 500 &&
+		statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests
+	return statusCode, string(body), &webhookDeliveryError{
+		StatusCode: &statusCode,
+		Message:    fmt.Sprintf("получатель ответил HTTP %d", statusCode),
+		Permanent:  permanent,
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+type webhookRepoStub struct {
+	repositories.WebhookRepositoryInterface
+	sub       *entities.WebhookSubscription
+	due       []entities.WebhookDelivery
+	delivered []uint64
+	retried   []uint64
+	failed    []uint64
+	attempts  []entities.WebhookDeliveryAttempt
+}
+
+func (r *webhookRepoStub) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error) {
+	items := r.due
+	r.due = nil
+	return items, nil
+}
+
+func (r *webhookRepoStub) FindSubscriptionByID(ctx context.Context, id uint64) (*entities.WebhookSubscription, error) {
+	return r.sub, nil
+}
+
+func (r *webhookRepoStub) MarkDelivered(ctx context.Context, id uint64, statusCode int) error {
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *webhookRepoStub) MarkRetry(ctx context.Context, id uint64, nextAttemptAt time.Time, statusCode *int, lastError string) error {
+	r.retried = append(r.retried, id)
+	return nil
+}
+
+func (r *webhookRepoStub) MarkFailed(ctx context.Context, id uint64, statusCode *int, lastError string) error {
+	r.failed = append(r.failed, id)
+	return nil
+}
+
+func (r *webhookRepoStub) CreateAttempt(ctx context.Context, attempt *entities.WebhookDeliveryAttempt) error {
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func TestWebhookProcessDue_SignsAndClassifiesResponses(t *testing.T) {
+	const secret = "0123456789abcdef"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(WebhookHeaderTimestamp), 10, 64)
+		if r.Header.Get(WebhookHeaderSignature) != SignWebhookPayload(secret, ts, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Header.Get(WebhookHeaderEvent) {
+		case "order.created":
+			w.WriteHeader(http.StatusOK)
+		case "order.commented":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}))
+	defer server.Close()
+
+	repo := &webhookRepoStub{
+		sub: &entities.WebhookSubscription{ID: 1, URL: server.URL, Secret: secret, IsActive: true},
+		due: []entities.WebhookDelivery{
+			{ID: 1, SubscriptionID: 1, EventID: uuid.New(), EventType: "order.created", Payload: []byte(`{"a":1}`), Attempts: 1, MaxAttempts: 10},
+			{ID: 2, SubscriptionID: 1, EventID: uuid.New(), EventType: "order.commented", Payload: []byte(`{"a":2}`), Attempts: 1, MaxAttempts: 10},
+			{ID: 3, SubscriptionID: 1, EventID: uuid.New(), EventType: "order.delegated", Payload: []byte(`{"a":3}`), Attempts: 1, MaxAttempts: 10},
+			{ID: 4, SubscriptionID: 1, EventID: uuid.New(), EventType: "order.commented", Payload: []byte(`{"a":4}`), Attempts: 10, MaxAttempts: 10},
+		},
+	}
+	// httptest слушает loopback, его нужно разрешить явно
+	loopback, _ := ParseWebhookAllowedNetworks([]string{"127.0.0.1"})
+	service := NewWebhookService(repo, nil, loopback, zap.NewNop())
+
+	processed, err := service.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDue returned error: %v", err)
+	}
+	if processed != 4 {
+		t.Fatalf("expected 4 processed deliveries, got %d", processed)
+	}
+	if len(repo.delivered) != 1 || repo.delivered[0] != 1 {
+		t.Fatalf("expected delivery 1 to be delivered, got %v", repo.delivered)
+	}
+	if len(repo.retried) != 1 || repo.retried[0] != 2 {
+		t.Fatalf("expected delivery 2 to be retried, got %v", repo.retried)
+	}
+	if len(repo.failed) != 2 || repo.failed[0] != 3 || repo.failed[1] != 4 {
+		t.Fatalf("expected deliveries 3 and 4 to fail, got %v", repo.failed)
+	}
+	if len(repo.attempts) != 4 {
+		t.Fatalf("expected every attempt to be logged, got %d", len(repo.attempts))
+	}
+}
+
+func TestWebhookValidateURL_RejectsInternalTargets(t *testing.T) {
+	service := NewWebhookService(nil, nil, nil, zap.NewNop()).(*WebhookService)
+	rejected := []string{
+		"ftp://example.com/hook",
+		"file:///etc/passwd",
+		"gopher://93.184.216.34/",
+		"http://127.0.0.1:8091/api",
+		"http://localhost/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:192.168.1.10]/hook",
+	}
+	for _, rawURL := range rejected {
+		err := service.validateWebhookURL(context.Background(), rawURL)
+		var httpErr *apperrors.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %v", rawURL, err)
+		}
+	}
+
+	for _, rawURL := range []string{"https://93.184.216.34/hook", "http://[2606:2800:220:1::1]:8443/hook"} {
+		if err := service.validateWebhookURL(context.Background(), rawURL); err != nil {
+			t.Errorf("%s: public address must be accepted, got %v", rawURL, err)
+		}
+	}
+
+	allowed, err := ParseWebhookAllowedNetworks([]string{"10.0.0.0/8", " 192.168.1.10 "})
+	if err != nil {
+		t.Fatalf("ParseWebhookAllowedNetworks failed: %v", err)
+	}
+	service = NewWebhookService(nil, nil, allowed, zap.NewNop()).(*WebhookService)
+	for _, rawURL := range []string{"http://10.20.30.40/hook", "http://192.168.1.10/hook"} {
+		if err := service.validateWebhookURL(context.Background(), rawURL); err != nil {
+			t.Errorf("%s: allowlisted address must be accepted, got %v", rawURL, err)
+		}
+	}
+	if err := service.validateWebhookURL(context.Background(), "http://192.168.1.11/hook"); err == nil {
+		t.Error("address outside the allowlist must be rejected")
+	}
+
+	if _, err := ParseWebhookAllowedNetworks([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("invalid CIDR must fail")
+	}
+	if _, err := ParseWebhookAllowedNetworks([]string{"internal.host"}); err == nil {
+		t.Error("host names must not be accepted as networks")
+	}
+}
+
+func TestWebhookProcessDue_DoesNotReachInternalTargetsOrFollowRedirects(t *testing.T) {
+	internalHits := 0
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHits++
+		w.Write([]byte(`{"secret":"metadata"}`))
+	}))
+	defer internal.Close()
+
+	// Подписка, созданная до смены DNS или правки в БД, указывает на loopback
+	repo := &webhookRepoStub{
+		sub: &entities.WebhookSubscription{ID: 1, URL: internal.URL, Secret: "0123456789abcdef", IsActive: true},
+		due: []entities.WebhookDelivery{
+			{ID: 1, SubscriptionID: 1, EventID: uuid.New(), EventType: "order.created", Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 10},
+		},
+	}
+	if _, err := NewWebhookService(repo, nil, nil, zap.NewNop()).ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue returned error: %v", err)
+	}
+	if internalHits != 0 {
+		t.Fatalf("internal target must not be contacted, got %d requests", internalHits)
+	}
+	if len(repo.failed) != 1 || repo.failed[0] != 1 {
+		t.Fatalf("blocked target must fail the delivery without retries, failed=%v retried=%v", repo.failed, repo.retried)
+	}
+
+	// Разрешённый получатель отвечает редиректом — клиент не идёт по нему
+	redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			internalHits++
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	defer redirecting.Close()
+
+	loopback, _ := ParseWebhookAllowedNetworks([]string{"127.0.0.1"})
+	repo = &webhookRepoStub{
+		sub: &entities.WebhookSubscription{ID: 1, URL: redirecting.URL, Secret: "0123456789abcdef", IsActive: true},
+		due: []entities.WebhookDelivery{
+			{ID: 2, SubscriptionID: 1, EventID: uuid.New(), EventType: "order.created", Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 10},
+		},
+	}
+	if _, err := NewWebhookService(repo, nil, loopback, zap.NewNop()).ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue returned error: %v", err)
+	}
+	if internalHits != 0 {
+		t.Fatalf("redirect must not be followed, got %d requests", internalHits)
+	}
+	if len(repo.attempts) != 1 || repo.attempts[0].StatusCode == nil || *repo.attempts[0].StatusCode != http.StatusFound {
+		t.Fatalf("expected the 302 response to be logged, got %+v", repo.attempts)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	apperrors "request-system/pkg/errors"
+)
+
+// webhookCarrierGradeNAT — общий адресный блок провайдеров (RFC 6598), снаружи так же недоступен, как частные сети.
+var webhookCarrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
+
+// errWebhookTargetBlocked — адрес получателя относится к внутренней сети и не разрешён WEBHOOK_ALLOWED_NETWORKS.
+var errWebhookTargetBlocked = errors.New("адрес получателя webhook во внутренней сети запрещён")
+
+// ParseWebhookAllowedNetworks разбирает WEBHOOK_ALLOWED_NETWORKS: адреса и подсети (CIDR) внутренних
+// получателей, которым всё же можно слать webhook. Некорректная запись — ошибка конфигурации.
+func ParseWebhookAllowedNetworks(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("WEBHOOK_ALLOWED_NETWORKS: некорректный адрес %q", entry)
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("WEBHOOK_ALLOWED_NETWORKS: некорректная подсеть %q: %w", entry, err)
+		}
+		networks = append(networks, ipNet)
+	}
+	return networks, nil
+}
+
+// webhookTargetAllowed — можно ли слать запрос на ip: loopback, частные, link-local и служебные
+// адреса (в том числе метаданные облака 169.254.169.254) пропускаются только из allowlist.
+func webhookTargetAllowed(ip net.IP, allowed []*net.IPNet) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, network := range allowed {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || webhookCarrierGradeNAT.Contains(ip))
+}
+
+// newWebhookHTTPClient — клиент доставки. Адрес проверяется при каждом соединении, уже после
+// DNS, чтобы имя не могло указать на внутреннюю сеть после проверки URL. Редиректы не выполняются:
+// 3xx записывается в журнал как ответ получателя.
+func newWebhookHTTPClient(allowed []*net.IPNet) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookRequestTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !webhookTargetAllowed(ip, allowed) {
+				return fmt.Errorf("%w: %s", errWebhookTargetBlocked, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   webhookRequestTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// validateWebhookURL принимает только http(s)-адреса, чей хост не ведёт во внутреннюю сеть.
+func (s *WebhookService) validateWebhookURL(ctx context.Context, rawURL string) error {
+	badRequest := func(message string) error {
+		return apperrors.NewHttpError(http.StatusBadRequest, message, nil, nil)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return badRequest("Некорректный URL webhook")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return badRequest("URL webhook должен начинаться с http:// или https://")
+	}
+
+	host := parsed.Hostname()
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		addrs, err := net.DefaultResolver.LookupIPAddr(lookupCtx, host)
+		if err != nil || len(addrs) == 0 {
+			return badRequest(fmt.Sprintf("Не удалось найти адрес хоста webhook: %s", host))
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if !webhookTargetAllowed(ip, s.allowedNetworks) {
+			return badRequest("URL webhook указывает во внутреннюю сеть. Разрешите адрес в WEBHOOK_ALLOWED_NETWORKS, если это нужно")
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 // Интерфейс, чтобы можно было легко подменять в тестах
 type WebSocketNotificationServiceInterface interface {
 	SendNotification(userID uint64, payload interface{}, messageType string) error
+	SendToOrderRoom(orderID uint64, payload interface{}, messageType string) error
 }
 
 // Конкретная реализация
@@ -35,3 +36,8 @@ func (s *WebSocketNotificationService) SendNotification(userID uint64, payload i
 	)
 	return s.hub.SendMessageToUser(userID, payload, messageType)
 }
+
+// SendToOrderRoom отправляет сообщение всем, у кого сейчас открыта карточка заявки
+func (s *WebSocketNotificationService) SendToOrderRoom(orderID uint64, payload interface{}, messageType string) error {
+	return s.hub.SendToRoom(websocket.OrderRoom(orderID), payload, messageType)
+}
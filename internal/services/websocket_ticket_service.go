@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"request-system/internal/dto"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/utils"
+)
+
+const (
+	webSocketTicketKeyPrefix  = "ws:ticket:"
+	webSocketTicketDefaultTTL = 30 * time.Second
+)
+
+type WebSocketTicketServiceInterface interface {
+	// IssueTicket выпускает билет для текущего пользователя.
+	IssueTicket(ctx context.Context) (*dto.WebSocketTicketDTO, error)
+	// ConsumeTicket возвращает владельца билета и гасит билет: второй раз он не сработает.
+	// impersonatorID не ноль, если билет выдан в режиме имперсонации.
+	ConsumeTicket(ctx context.Context, ticket string) (userID, impersonatorID uint64, err error)
+}
+
+// webSocketTicketPayload — значение билета в Redis. Администратор, работающий под пользователем,
+// сохраняется вместе с ним, чтобы соединение по билету было видно так же, как запросы по HTTP.
+type webSocketTicketPayload struct {
+	UserID         uint64 `json:"user_id"`
+	ImpersonatorID uint64 `json:"impersonator_id,omitempty"`
+}
+
+// WebSocketTicketService выдаёт короткоживущие одноразовые билеты для WebSocket. Браузер не может
+// передать заголовок Authorization при подключении, а JWT в адресе оседает в журналах прокси;
+// билет в адресе бесполезен сразу после подключения. Билеты лежат в Redis, поэтому подключиться
+// можно к любой реплике.
+type WebSocketTicketService struct {
+	cache  repositories.CacheRepositoryInterface
+	ttl    time.Duration
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+func NewWebSocketTicketService(cache repositories.CacheRepositoryInterface, ttl time.Duration, logger *zap.Logger) WebSocketTicketServiceInterface {
+	if ttl <= 0 {
+		ttl = webSocketTicketDefaultTTL
+	}
+	return &WebSocketTicketService{cache: cache, ttl: ttl, logger: logger, now: time.Now}
+}
+
+func (s *WebSocketTicketService) IssueTicket(ctx context.Context) (*dto.WebSocketTicketDTO, error) {
+	userID, err := utils.GetUserIDFromCtx(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	ticket := hex.EncodeToString(buf)
+	payload := webSocketTicketPayload{UserID: userID}
+	payload.ImpersonatorID, _ = utils.GetImpersonatorIDFromCtx(ctx)
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, webSocketTicketKeyPrefix+ticket, string(value), s.ttl); err != nil {
+		s.logger.Error("Не удалось сохранить билет WebSocket", zap.Uint64("userID", userID), zap.Error(err))
+		return nil, err
+	}
+	return &dto.WebSocketTicketDTO{
+		Ticket:           ticket,
+		ExpiresAt:        s.now().Add(s.ttl).UTC(),
+		ExpiresInSeconds: int(s.ttl / time.Second),
+	}, nil
+}
+
+func (s *WebSocketTicketService) ConsumeTicket(ctx context.Context, ticket string) (uint64, uint64, error) {
+	if ticket == "" {
+		return 0, 0, apperrors.ErrUnauthorized
+	}
+	// GetDel отдаёт значение только одному вызову: билет нельзя использовать дважды,
+	// даже если два подключения пришли одновременно.
+	value, err := s.cache.GetDel(ctx, webSocketTicketKeyPrefix+ticket)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.logger.Error("Не удалось проверить билет WebSocket", zap.Error(err))
+		}
+		return 0, 0, apperrors.ErrUnauthorized
+	}
+	var payload webSocketTicketPayload
+	if err := json.Unmarshal([]byte(value), &payload); err != nil || payload.UserID == 0 {
+		return 0, 0, apperrors.ErrUnauthorized
+	}
+	return payload.UserID, payload.ImpersonatorID, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+)
+
+type ticketCacheStub struct {
+	repositories.CacheRepositoryInterface
+	values map[string]string
+	ttl    time.Duration
+}
+
+func (s *ticketCacheStub) Set(_ context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.values[key] = value.(string)
+	s.ttl = expiration
+	return nil
+}
+
+func (s *ticketCacheStub) GetDel(_ context.Context, key string) (string, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	delete(s.values, key)
+	return value, nil
+}
+
+func TestWebSocketTicketIsOneTime(t *testing.T) {
+	cache := &ticketCacheStub{values: map[string]string{}}
+	service := NewWebSocketTicketService(cache, 0, zap.NewNop())
+
+	if _, err := service.IssueTicket(context.Background()); err == nil {
+		t.Fatalf("a ticket must not be issued without a user")
+	}
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(42))
+	ticket, err := service.IssueTicket(ctx)
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+	if len(ticket.Ticket) != 64 || ticket.ExpiresInSeconds != 30 || cache.ttl != 30*time.Second {
+		t.Fatalf("unexpected ticket: %+v, ttl %s", ticket, cache.ttl)
+	}
+	other, _ := service.IssueTicket(ctx)
+	if other.Ticket == ticket.Ticket {
+		t.Fatalf("tickets must be random")
+	}
+
+	userID, impersonatorID, err := service.ConsumeTicket(context.Background(), ticket.Ticket)
+	if err != nil || userID != 42 || impersonatorID != 0 {
+		t.Fatalf("consume = %d, %d, %v; want user 42 without an impersonator", userID, impersonatorID, err)
+	}
+	if _, _, err := service.ConsumeTicket(context.Background(), ticket.Ticket); err == nil {
+		t.Fatalf("a ticket must not work twice")
+	}
+	if _, _, err := service.ConsumeTicket(context.Background(), ""); err == nil {
+		t.Fatalf("an empty ticket must be rejected")
+	}
+}
+
+func TestWebSocketTicketKeepsImpersonator(t *testing.T) {
+	cache := &ticketCacheStub{values: map[string]string{}}
+	service := NewWebSocketTicketService(cache, 0, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, uint64(42))
+	ctx = context.WithValue(ctx, contextkeys.ImpersonatorIDKey, uint64(7))
+	ticket, err := service.IssueTicket(ctx)
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+	userID, impersonatorID, err := service.ConsumeTicket(context.Background(), ticket.Ticket)
+	if err != nil || userID != 42 || impersonatorID != 7 {
+		t.Fatalf("consume = %d, %d, %v; want user 42 impersonated by 7", userID, impersonatorID, err)
+	}
+
+	cache.values[webSocketTicketKeyPrefix+"broken"] = "42"
+	if _, _, err := service.ConsumeTicket(context.Background(), "broken"); err == nil {
+		t.Fatalf("a malformed ticket must be rejected")
+	}
+}
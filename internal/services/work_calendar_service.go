@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+const (
+	// По умолчанию календарь показывается на месяц вперёд от начала текущего дня.
+	workCalendarDefaultDays = 31
+	// Самый длинный период — и для выборки, и для одной записи (отпуск, больничный).
+	workCalendarMaxPeriod = 366 * 24 * time.Hour
+)
+
+// WorkCalendarServiceInterface ведёт рабочий календарь участников команды: смены, выходные,
+// отпуска и больничные. Менять календарь может руководитель команды или владелец team:manage.
+type WorkCalendarServiceInterface interface {
+	GetTeamCalendar(ctx context.Context, teamID uint64, from, to *time.Time) ([]dto.WorkCalendarEntryDTO, error)
+	AddTeamEntry(ctx context.Context, teamID uint64, payload dto.CreateWorkCalendarEntryDTO) (*dto.WorkCalendarEntryDTO, error)
+	DeleteTeamEntry(ctx context.Context, teamID, entryID uint64) error
+}
+
+type WorkCalendarService struct {
+	repo     repositories.WorkCalendarRepositoryInterface
+	teamRepo repositories.TeamRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *zap.Logger
+}
+
+func NewWorkCalendarService(
+	repo repositories.WorkCalendarRepositoryInterface,
+	teamRepo repositories.TeamRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *zap.Logger,
+) WorkCalendarServiceInterface {
+	return &WorkCalendarService{repo: repo, teamRepo: teamRepo, userRepo: userRepo, logger: logger}
+}
+
+func (s *WorkCalendarService) GetTeamCalendar(ctx context.Context, teamID uint64, from, to *time.Time) ([]dto.WorkCalendarEntryDTO, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanDo(authz.TeamsView, *authContext) {
+		return nil, apperrors.ErrForbidden
+	}
+
+	periodFrom, periodTo, err := workCalendarPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.teamRepo.FindMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint64, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+
+	entries, err := s.repo.FindByUsers(ctx, userIDs, periodFrom, periodTo)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.WorkCalendarEntryDTO, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, toWorkCalendarEntryDTO(e))
+	}
+	return result, nil
+}
+
+func (s *WorkCalendarService) AddTeamEntry(ctx context.Context, teamID uint64, payload dto.CreateWorkCalendarEntryDTO) (*dto.WorkCalendarEntryDTO, error) {
+	actor, err := s.checkCanManage(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !payload.EndsAt.After(payload.StartsAt) {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Окончание периода должно быть позже начала", nil, nil)
+	}
+	if payload.EndsAt.Sub(payload.StartsAt) > workCalendarMaxPeriod {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Период не может быть длиннее года", nil, nil)
+	}
+	isMember, err := s.teamRepo.IsMember(ctx, teamID, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, apperrors.NewHttpError(http.StatusBadRequest, "Сотрудник не состоит в этой команде", nil, nil)
+	}
+
+	entry := &entities.WorkCalendarEntry{
+		UserID:    payload.UserID,
+		Kind:      payload.Kind,
+		StartsAt:  payload.StartsAt,
+		EndsAt:    payload.EndsAt,
+		Comment:   trimmedComment(payload.Comment),
+		CreatedBy: &actor.ID,
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Добавлена запись рабочего календаря",
+		zap.Uint64("teamID", teamID), zap.Uint64("userID", entry.UserID),
+		zap.String("kind", entry.Kind), zap.Uint64("by", actor.ID))
+
+	created, err := s.repo.FindByID(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := toWorkCalendarEntryDTO(*created)
+	return &result, nil
+}
+
+func (s *WorkCalendarService) DeleteTeamEntry(ctx context.Context, teamID, entryID uint64) error {
+	actor, err := s.checkCanManage(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	entry, err := s.repo.FindByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	// Запись чужого сотрудника для этой команды «не существует»
+	isMember, err := s.teamRepo.IsMember(ctx, teamID, entry.UserID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return apperrors.ErrNotFound
+	}
+	if err := s.repo.Delete(ctx, entryID); err != nil {
+		return err
+	}
+	s.logger.Info("Удалена запись рабочего календаря", zap.Uint64("entryID", entryID), zap.Uint64("by", actor.ID))
+	return nil
+}
+
+// checkCanManage пропускает руководителя команды и владельцев team:manage.
+func (s *WorkCalendarService) checkCanManage(ctx context.Context, teamID uint64) (*entities.User, error) {
+	authContext, err := buildAuthzContext(ctx, s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.teamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	isLead := team.LeadID != nil && *team.LeadID == authContext.Actor.ID
+	if !isLead && !authz.CanDo(authz.TeamsManage, *authContext) {
+		return nil, apperrors.NewHttpError(http.StatusForbidden, "Календарь команды может менять только её руководитель", nil, nil)
+	}
+	return authContext.Actor, nil
+}
+
+func workCalendarPeriod(from, to *time.Time) (time.Time, time.Time, error) {
+	now := time.Now()
+	periodFrom := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if from != nil {
+		periodFrom = *from
+	}
+	periodTo := periodFrom.AddDate(0, 0, workCalendarDefaultDays)
+	if to != nil {
+		periodTo = *to
+	}
+	if !periodTo.After(periodFrom) {
+		return time.Time{}, time.Time{}, apperrors.NewHttpError(http.StatusBadRequest, "date_to должна быть позже date_from", nil, nil)
+	}
+	if periodTo.Sub(periodFrom) > workCalendarMaxPeriod {
+		return time.Time{}, time.Time{}, apperrors.NewHttpError(http.StatusBadRequest, "Период не может быть длиннее года", nil, nil)
+	}
+	return periodFrom, periodTo, nil
+}
+
+func trimmedComment(comment *string) *string {
+	if comment == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*comment)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func toWorkCalendarEntryDTO(e entities.WorkCalendarEntry) dto.WorkCalendarEntryDTO {
+	return dto.WorkCalendarEntryDTO{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		UserFio:   e.UserFio,
+		Kind:      e.Kind,
+		StartsAt:  e.StartsAt.Format(time.RFC3339),
+		EndsAt:    e.EndsAt.Format(time.RFC3339),
+		Comment:   e.Comment,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/internal/authz"
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/contextkeys"
+)
+
+type calendarTeamRepoStub struct {
+	repositories.TeamRepositoryInterface
+	team    entities.Team
+	members []uint64
+}
+
+func (s *calendarTeamRepoStub) FindByID(context.Context, uint64) (*entities.Team, error) {
+	team := s.team
+	return &team, nil
+}
+
+func (s *calendarTeamRepoStub) IsMember(_ context.Context, _ uint64, userID uint64) (bool, error) {
+	for _, id := range s.members {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type workCalendarRepoStub struct {
+	repositories.WorkCalendarRepositoryInterface
+	created []entities.WorkCalendarEntry
+}
+
+func (s *workCalendarRepoStub) Create(_ context.Context, entry *entities.WorkCalendarEntry) error {
+	entry.ID = uint64(len(s.created) + 1)
+	s.created = append(s.created, *entry)
+	return nil
+}
+
+func (s *workCalendarRepoStub) FindByID(_ context.Context, id uint64) (*entities.WorkCalendarEntry, error) {
+	entry := s.created[id-1]
+	return &entry, nil
+}
+
+func TestAddTeamEntryAllowedForTeamLead(t *testing.T) {
+	leadID := uint64(5)
+	teamRepo := &calendarTeamRepoStub{team: entities.Team{ID: 1, LeadID: &leadID}, members: []uint64{5, 7}}
+	repo := &workCalendarRepoStub{}
+	service := NewWorkCalendarService(repo, teamRepo, &teamUserRepoStub{}, zap.NewNop())
+
+	start := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	payload := dto.CreateWorkCalendarEntryDTO{UserID: 7, Kind: entities.WorkCalendarVacation, StartsAt: start, EndsAt: start.AddDate(0, 0, 14)}
+	perms := map[string]bool{authz.TeamsView: true}
+
+	leadCtx := context.WithValue(context.Background(), contextkeys.UserIDKey, leadID)
+	leadCtx = context.WithValue(leadCtx, contextkeys.UserPermissionsMapKey, perms)
+	entry, err := service.AddTeamEntry(leadCtx, 1, payload)
+	if err != nil {
+		t.Fatalf("team lead should manage the calendar: %v", err)
+	}
+	if entry.UserID != 7 || entry.CreatedBy == nil || *entry.CreatedBy != leadID {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	memberCtx := context.WithValue(leadCtx, contextkeys.UserIDKey, uint64(7))
+	if _, err := service.AddTeamEntry(memberCtx, 1, payload); err == nil {
+		t.Fatal("expected forbidden for a regular member")
+	}
+
+	outsider := payload
+	outsider.UserID = 9
+	if _, err := service.AddTeamEntry(leadCtx, 1, outsider); err == nil {
+		t.Fatal("expected an error for a user outside the team")
+	}
+
+	reversed := payload
+	reversed.EndsAt = start.Add(-time.Hour)
+	if _, err := service.AddTeamEntry(leadCtx, 1, reversed); err == nil {
+		t.Fatal("expected an error for an empty period")
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected one stored entry, got %d", len(repo.created))
+	}
+}
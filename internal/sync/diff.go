@@ -0,0 +1,203 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"request-system/internal/dto"
+	"request-system/internal/entities"
+	apperrors "request-system/pkg/errors"
+)
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+)
+
+type dryRunContextKey struct{}
+
+var errDryRunRollback = errors.New("dry run: изменения откатываются")
+
+// DryRun выполняет fn в общей транзакции, которая в конце всегда откатывается. Обработчики внутри
+// видят изменения друг друга (пользователи — только что «созданные» отделы), как при настоящей
+// синхронизации, но ничего не сохраняется.
+func (h *DBHandler) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	var runErr error
+	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+		runErr = fn(context.WithValue(ctx, dryRunContextKey{}, tx))
+		return errDryRunRollback
+	})
+	if runErr != nil {
+		return runErr
+	}
+	if errors.Is(err, errDryRunRollback) {
+		return nil
+	}
+	return err
+}
+
+// runInTx — транзакция справочника. В режиме dry run это точка сохранения общей транзакции:
+// ошибка справочника откатывает только его изменения.
+func (h *DBHandler) runInTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, ok := ctx.Value(dryRunContextKey{}).(pgx.Tx)
+	if !ok {
+		return h.txManager.RunInTransaction(ctx, fn)
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(savepoint); err != nil {
+		_ = savepoint.Rollback(ctx)
+		return apperrors.WrapDBError(err)
+	}
+	return savepoint.Commit(ctx)
+}
+
+// changeSet — отличающиеся поля записи: старое значение из базы и новое из выгрузки.
+type changeSet map[string]dto.SyncFieldChangeDTO
+
+func (c changeSet) field(name string, oldValue, newValue any) changeSet {
+	o, n := plainValue(oldValue), plainValue(newValue)
+	if !reflect.DeepEqual(o, n) {
+		c[name] = dto.SyncFieldChangeDTO{Old: o, New: n}
+	}
+	return c
+}
+
+// deactivates — запись из активной становится неактивной.
+func (c changeSet) deactivates() bool {
+	change, ok := c["status"]
+	return ok && change.Old != nil && change.New == "INACTIVE"
+}
+
+// plainValue разыменовывает указатели и приводит пустые значения к nil, чтобы "" и NULL не
+// считались изменением.
+func plainValue(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format("2006-01-02")
+	}
+	if rv.IsZero() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// statusCode показывает статус в предпросмотре кодом, а не id.
+func statusCode(id uint64, active, inactive *entities.Status) any {
+	switch {
+	case id == 0:
+		return nil
+	case active != nil && id == active.ID:
+		return "ACTIVE"
+	case inactive != nil && id == inactive.ID:
+		return "INACTIVE"
+	default:
+		return id
+	}
+}
+
+func departmentChanges(old *entities.Department, updated entities.Department, active, inactive *entities.Status) changeSet {
+	if old == nil {
+		old = &entities.Department{}
+	}
+	return changeSet{}.
+		field("name", old.Name, updated.Name).
+		field("status", statusCode(old.StatusID, active, inactive), statusCode(updated.StatusID, active, inactive))
+}
+
+func branchChanges(old *entities.Branch, updated entities.Branch, active, inactive *entities.Status) changeSet {
+	if old == nil {
+		old = &entities.Branch{}
+	}
+	return changeSet{}.
+		field("name", old.Name, updated.Name).
+		field("short_name", old.ShortName, updated.ShortName).
+		field("address", old.Address, updated.Address).
+		field("phone_number", old.PhoneNumber, updated.PhoneNumber).
+		field("email", old.Email, updated.Email).
+		field("email_index", old.EmailIndex, updated.EmailIndex).
+		field("open_date", old.OpenDate, updated.OpenDate).
+		field("status", statusCode(old.StatusID, active, inactive), statusCode(updated.StatusID, active, inactive))
+}
+
+func otdelChanges(old *entities.Otdel, updated entities.Otdel, active, inactive *entities.Status) changeSet {
+	if old == nil {
+		old = &entities.Otdel{}
+	}
+	return changeSet{}.
+		field("name", old.Name, updated.Name).
+		field("department_id", old.DepartmentsID, updated.DepartmentsID).
+		field("branch_id", old.BranchID, updated.BranchID).
+		field("parent_id", old.ParentID, updated.ParentID).
+		field("status", statusCode(old.StatusID, active, inactive), statusCode(updated.StatusID, active, inactive))
+}
+
+func officeChanges(old *entities.Office, updated entities.Office, active, inactive *entities.Status) changeSet {
+	if old == nil {
+		old = &entities.Office{}
+	}
+	return changeSet{}.
+		field("name", old.Name, updated.Name).
+		field("address", old.Address, updated.Address).
+		field("open_date", old.OpenDate, updated.OpenDate).
+		field("branch_id", old.BranchID, updated.BranchID).
+		field("parent_id", old.ParentID, updated.ParentID).
+		field("status", statusCode(old.StatusID, active, inactive), statusCode(updated.StatusID, active, inactive))
+}
+
+func positionChanges(old *entities.Position, updated entities.Position, active, inactive *entities.Status) changeSet {
+	if old == nil {
+		old = &entities.Position{}
+	}
+	return changeSet{}.
+		field("name", old.Name, updated.Name).
+		field("type", old.Type, updated.Type).
+		field("department_id", old.DepartmentID, updated.DepartmentID).
+		field("otdel_id", old.OtdelID, updated.OtdelID).
+		field("branch_id", old.BranchID, updated.BranchID).
+		field("office_id", old.OfficeID, updated.OfficeID).
+		field("status", statusCode(derefID(old.StatusID), active, inactive), statusCode(derefID(updated.StatusID), active, inactive))
+}
+
+func userChanges(old *entities.User, updated entities.User, active, inactive *entities.Status) changeSet {
+	if old == nil {
+		old = &entities.User{}
+	}
+	return changeSet{}.
+		field("fio", old.Fio, updated.Fio).
+		field("email", old.Email, updated.Email).
+		field("phone_number", old.PhoneNumber, updated.PhoneNumber).
+		field("username", old.Username, updated.Username).
+		field("position_id", old.PositionID, updated.PositionID).
+		field("department_id", old.DepartmentID, updated.DepartmentID).
+		field("otdel_id", old.OtdelID, updated.OtdelID).
+		field("branch_id", old.BranchID, updated.BranchID).
+		field("office_id", old.OfficeID, updated.OfficeID).
+		field("status", statusCode(old.StatusID, active, inactive), statusCode(updated.StatusID, active, inactive))
+}
+
+func derefID(id *uint64) uint64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -30,6 +30,8 @@ type HandlerInterface interface {
 	ProcessOffices(ctx context.Context, offices []dto.Office1CDTO) error
 	ProcessPositions(ctx context.Context, positions []dto.Position1CDTO) error
 	ProcessUsers(ctx context.Context, users []dto.User1CDTO) error
+	// DryRun выполняет fn без сохранения изменений: Process* внутри работают в откатываемой транзакции.
+	DryRun(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type DBHandler struct {
@@ -134,10 +136,12 @@ func (h *DBHandler) ProcessUsers(ctx context.Context, data []dto.User1CDTO) erro
 	duplicateEmailAssignments := buildDuplicateEmailAssignments(data)
 	incomingPhoneAssignments := buildIncomingPhoneAssignments(data)
 	var validationErr *userSyncValidationError
+	report := ReportFromContext(ctx)
 
 	h.logger.Info("Processing users from 1C (partial update mode)", zap.Int("incoming", countTotal))
 
-	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err := h.runInTx(ctx, func(tx pgx.Tx) error {
+		report.begin(EntityUsers, countTotal)
 		activeStatus, err := h.statusRepo.FindByCodeInTx(ctx, tx, "ACTIVE")
 		if err != nil {
 			return err
@@ -161,270 +165,291 @@ func (h *DBHandler) ProcessUsers(ctx context.Context, data []dto.User1CDTO) erro
 			}
 		}
 
-		for _, item := range data {
+		for i, item := range data {
 			externalID := strings.TrimSpace(item.ExternalID)
 			if externalID == "" {
+				report.recordFailed(EntityUsers, i, "", "externalId", "не указан externalId")
 				continue
 			}
 
-			existing, err := h.userRepo.FindByExternalID(ctx, tx, externalID, sourceSystem1C)
-			if err != nil && !isNotFound(err) {
-				return fmt.Errorf("DB Error User %s: %w", externalID, err)
-			}
-
-			userFound := err == nil && existing != nil && existing.ID != 0
-			entity := entities.User{
-				Fio:          fmt.Sprintf("1c_user_%s", externalID),
-				Email:        fmt.Sprintf("no_email_%s@1c.local", externalID),
-				PhoneNumber:  fmt.Sprintf("N%s", externalID),
-				StatusID:     activeStatus.ID,
-				ExternalID:   stringToPtr(externalID),
-				SourceSystem: stringToPtr(sourceSystem1C),
-			}
-			if userFound {
-				entity = *existing
-			}
-
-			if fio := trimOptionalString(item.Fio); fio != "" {
-				entity.Fio = fio
-			}
-
-			if item.IsActive != nil {
-				if *item.IsActive {
-					entity.StatusID = activeStatus.ID
-				} else {
-					entity.StatusID = inactiveStatus.ID
+			if err := h.eachRecord(ctx, tx, EntityUsers, i, externalID, func(tx pgx.Tx) error {
+				existing, err := h.userRepo.FindByExternalID(ctx, tx, externalID, sourceSystem1C)
+				if err != nil && !isNotFound(err) {
+					return fmt.Errorf("DB Error User %s: %w", externalID, err)
 				}
-			}
 
-			incomingActive := entity.StatusID == activeStatus.ID
+				userFound := err == nil && existing != nil && existing.ID != 0
+				entity := entities.User{
+					Fio:          fmt.Sprintf("1c_user_%s", externalID),
+					Email:        fmt.Sprintf("no_email_%s@1c.local", externalID),
+					PhoneNumber:  fmt.Sprintf("N%s", externalID),
+					StatusID:     activeStatus.ID,
+					ExternalID:   stringToPtr(externalID),
+					SourceSystem: stringToPtr(sourceSystem1C),
+				}
+				if userFound {
+					entity = *existing
+				}
 
-			var resolvedPosition *entities.Position
-			positionFromPayload := false
+				if fio := trimOptionalString(item.Fio); fio != "" {
+					entity.Fio = fio
+				}
 
-			positionExternalID := trimOptionalString(item.PositionExternalID)
-			if positionExternalID != "" {
-				pos, err := h.positionRepo.FindByExternalID(ctx, tx, positionExternalID, sourceSystem1C)
-				if err != nil {
-					if !isNotFound(err) {
-						return fmt.Errorf("DB Error Position %s for user %s: %w", positionExternalID, externalID, err)
+				if item.IsActive != nil {
+					if *item.IsActive {
+						entity.StatusID = activeStatus.ID
+					} else {
+						entity.StatusID = inactiveStatus.ID
 					}
-					h.logger.Warn("Position from 1C not found, position_id is not changed", zap.String("user_external_id", externalID), zap.String("position_external_id", positionExternalID))
-				} else if pos != nil {
-					resolvedPosition = pos
-					entity.PositionID = &pos.ID
-					positionFromPayload = true
 				}
-			}
 
-			otdelFromPayload := false
-
-			if depExternalID := trimOptionalString(item.DepartmentExternalID); depExternalID != "" {
-				dep, err := h.departmentRepo.FindByExternalID(ctx, tx, depExternalID, sourceSystem1C)
-				if err != nil {
-					if !isNotFound(err) {
-						return fmt.Errorf("DB Error Department %s for user %s: %w", depExternalID, externalID, err)
+				incomingActive := entity.StatusID == activeStatus.ID
+
+				var resolvedPosition *entities.Position
+				positionFromPayload := false
+
+				positionExternalID := trimOptionalString(item.PositionExternalID)
+				if positionExternalID != "" {
+					pos, err := h.positionRepo.FindByExternalID(ctx, tx, positionExternalID, sourceSystem1C)
+					if err != nil {
+						if !isNotFound(err) {
+							return fmt.Errorf("DB Error Position %s for user %s: %w", positionExternalID, externalID, err)
+						}
+						h.logger.Warn("Position from 1C not found, position_id is not changed", zap.String("user_external_id", externalID), zap.String("position_external_id", positionExternalID))
+					} else if pos != nil {
+						resolvedPosition = pos
+						entity.PositionID = &pos.ID
+						positionFromPayload = true
 					}
-					h.logger.Warn("Department from 1C not found, department_id is not changed", zap.String("user_external_id", externalID), zap.String("department_external_id", depExternalID))
-				} else if dep != nil {
-					entity.DepartmentID = &dep.ID
 				}
-			}
 
-			if otdelExternalID := trimOptionalString(item.OtdelExternalID); otdelExternalID != "" {
-				otdel, err := h.otdelRepo.FindByExternalID(ctx, tx, otdelExternalID, sourceSystem1C)
-				if err != nil {
-					if !isNotFound(err) {
-						return fmt.Errorf("DB Error Otdel %s for user %s: %w", otdelExternalID, externalID, err)
+				otdelFromPayload := false
+
+				if depExternalID := trimOptionalString(item.DepartmentExternalID); depExternalID != "" {
+					dep, err := h.departmentRepo.FindByExternalID(ctx, tx, depExternalID, sourceSystem1C)
+					if err != nil {
+						if !isNotFound(err) {
+							return fmt.Errorf("DB Error Department %s for user %s: %w", depExternalID, externalID, err)
+						}
+						h.logger.Warn("Department from 1C not found, department_id is not changed", zap.String("user_external_id", externalID), zap.String("department_external_id", depExternalID))
+					} else if dep != nil {
+						entity.DepartmentID = &dep.ID
 					}
-					h.logger.Warn("Otdel from 1C not found, otdel_id is not changed", zap.String("user_external_id", externalID), zap.String("otdel_external_id", otdelExternalID))
-				} else if otdel != nil {
-					entity.OtdelID = &otdel.ID
-					otdelFromPayload = true
 				}
-			}
 
-			if branchExternalID := trimOptionalString(item.BranchExternalID); branchExternalID != "" {
-				branch, err := h.branchRepo.FindByExternalID(ctx, tx, branchExternalID, sourceSystem1C)
-				if err != nil {
-					if !isNotFound(err) {
-						return fmt.Errorf("DB Error Branch %s for user %s: %w", branchExternalID, externalID, err)
+				if otdelExternalID := trimOptionalString(item.OtdelExternalID); otdelExternalID != "" {
+					otdel, err := h.otdelRepo.FindByExternalID(ctx, tx, otdelExternalID, sourceSystem1C)
+					if err != nil {
+						if !isNotFound(err) {
+							return fmt.Errorf("DB Error Otdel %s for user %s: %w", otdelExternalID, externalID, err)
+						}
+						h.logger.Warn("Otdel from 1C not found, otdel_id is not changed", zap.String("user_external_id", externalID), zap.String("otdel_external_id", otdelExternalID))
+					} else if otdel != nil {
+						entity.OtdelID = &otdel.ID
+						otdelFromPayload = true
 					}
-					h.logger.Warn("Branch from 1C not found, branch_id is not changed", zap.String("user_external_id", externalID), zap.String("branch_external_id", branchExternalID))
-				} else if branch != nil {
-					entity.BranchID = &branch.ID
 				}
-			}
 
-			if officeExternalID := trimOptionalString(item.OfficeExternalID); officeExternalID != "" {
-				office, err := h.officeRepo.FindByExternalID(ctx, tx, officeExternalID, sourceSystem1C)
-				if err != nil {
-					if !isNotFound(err) {
-						return fmt.Errorf("DB Error Office %s for user %s: %w", officeExternalID, externalID, err)
+				if branchExternalID := trimOptionalString(item.BranchExternalID); branchExternalID != "" {
+					branch, err := h.branchRepo.FindByExternalID(ctx, tx, branchExternalID, sourceSystem1C)
+					if err != nil {
+						if !isNotFound(err) {
+							return fmt.Errorf("DB Error Branch %s for user %s: %w", branchExternalID, externalID, err)
+						}
+						h.logger.Warn("Branch from 1C not found, branch_id is not changed", zap.String("user_external_id", externalID), zap.String("branch_external_id", branchExternalID))
+					} else if branch != nil {
+						entity.BranchID = &branch.ID
 					}
-					h.logger.Warn("Office from 1C not found, office_id is not changed", zap.String("user_external_id", externalID), zap.String("office_external_id", officeExternalID))
-				} else if office != nil {
-					entity.OfficeID = &office.ID
 				}
-			}
 
-			if !userFound && resolvedPosition != nil {
-				if entity.DepartmentID == nil {
-					entity.DepartmentID = resolvedPosition.DepartmentID
-				}
-				if entity.OtdelID == nil {
-					entity.OtdelID = resolvedPosition.OtdelID
-				}
-				if entity.BranchID == nil {
-					entity.BranchID = resolvedPosition.BranchID
-				}
-				if entity.OfficeID == nil {
-					entity.OfficeID = resolvedPosition.OfficeID
+				if officeExternalID := trimOptionalString(item.OfficeExternalID); officeExternalID != "" {
+					office, err := h.officeRepo.FindByExternalID(ctx, tx, officeExternalID, sourceSystem1C)
+					if err != nil {
+						if !isNotFound(err) {
+							return fmt.Errorf("DB Error Office %s for user %s: %w", officeExternalID, externalID, err)
+						}
+						h.logger.Warn("Office from 1C not found, office_id is not changed", zap.String("user_external_id", externalID), zap.String("office_external_id", officeExternalID))
+					} else if office != nil {
+						entity.OfficeID = &office.ID
+					}
 				}
-			}
 
-			targetUserID := uint64(0)
-			if userFound {
-				targetUserID = existing.ID
-			}
+				if !userFound && resolvedPosition != nil {
+					if entity.DepartmentID == nil {
+						entity.DepartmentID = resolvedPosition.DepartmentID
+					}
+					if entity.OtdelID == nil {
+						entity.OtdelID = resolvedPosition.OtdelID
+					}
+					if entity.BranchID == nil {
+						entity.BranchID = resolvedPosition.BranchID
+					}
+					if entity.OfficeID == nil {
+						entity.OfficeID = resolvedPosition.OfficeID
+					}
+				}
 
-			cleanEmail := trimOptionalString(item.Email)
-			if numberedEmail, ok := duplicateEmailAssignments[externalID]; ok {
-				h.logger.Warn(
-					"Дублирующийся email в выгрузке 1С нормализован нумерацией",
-					zap.String("incoming_external_id", externalID),
-					zap.String("source_email", cleanEmail),
-					zap.String("normalized_email", numberedEmail),
-				)
-				cleanEmail = numberedEmail
-			}
-			cleanPhone := trimOptionalString(item.PhoneNumber)
-			cleanUsername := trimOptionalString(item.Username)
+				targetUserID := uint64(0)
+				if userFound {
+					targetUserID = existing.ID
+				}
 
-			if userFound && cleanPhone == "" {
-				technicalPhone := buildTechnicalPhoneValue(existing.ID, externalID)
-				if entity.PhoneNumber != technicalPhone {
+				cleanEmail := trimOptionalString(item.Email)
+				if numberedEmail, ok := duplicateEmailAssignments[externalID]; ok {
 					h.logger.Warn(
-						"Телефон пользователя очищен по актуальным данным выгрузки 1С, назначено техническое значение",
+						"Дублирующийся email в выгрузке 1С нормализован нумерацией",
 						zap.String("incoming_external_id", externalID),
-						zap.Uint64("user_id", existing.ID),
-						zap.String("old_phone", entity.PhoneNumber),
-						zap.String("technical_phone", technicalPhone),
+						zap.String("source_email", cleanEmail),
+						zap.String("normalized_email", numberedEmail),
 					)
+					cleanEmail = numberedEmail
 				}
-				entity.PhoneNumber = technicalPhone
-			}
-
-			conflicts, err := h.collectIncomingContactConflicts(
-				ctx,
-				tx,
-				targetUserID,
-				externalID,
-				entity.Fio,
-				incomingActive,
-				cleanEmail,
-				cleanPhone,
-				cleanUsername,
-				incomingPhoneAssignments,
-			)
-			if err != nil {
-				return err
-			}
-			if len(conflicts) > 0 {
-				if validationErr == nil {
-					validationErr = &userSyncValidationError{}
+				cleanPhone := trimOptionalString(item.PhoneNumber)
+				cleanUsername := trimOptionalString(item.Username)
+
+				if userFound && cleanPhone == "" {
+					technicalPhone := buildTechnicalPhoneValue(existing.ID, externalID)
+					if entity.PhoneNumber != technicalPhone {
+						h.logger.Warn(
+							"Телефон пользователя очищен по актуальным данным выгрузки 1С, назначено техническое значение",
+							zap.String("incoming_external_id", externalID),
+							zap.Uint64("user_id", existing.ID),
+							zap.String("old_phone", entity.PhoneNumber),
+							zap.String("technical_phone", technicalPhone),
+						)
+					}
+					entity.PhoneNumber = technicalPhone
 				}
-				validationErr.Conflicts = append(validationErr.Conflicts, conflicts...)
-				continue
-			}
 
-			if cleanEmail != "" {
-				resolvedEmail, applyResolvedEmail, err := h.resolveIncomingEmailConflict(ctx, tx, targetUserID, externalID, cleanEmail, incomingActive)
+				conflicts, err := h.collectIncomingContactConflicts(
+					ctx,
+					tx,
+					targetUserID,
+					externalID,
+					entity.Fio,
+					incomingActive,
+					cleanEmail,
+					cleanPhone,
+					cleanUsername,
+					incomingPhoneAssignments,
+				)
 				if err != nil {
 					return err
 				}
-				if applyResolvedEmail {
-					entity.Email = resolvedEmail
-				} else {
-					entity.Email = cleanEmail
+				if len(conflicts) > 0 {
+					if validationErr == nil {
+						validationErr = &userSyncValidationError{}
+					}
+					validationErr.Conflicts = append(validationErr.Conflicts, conflicts...)
+					report.recordFailed(EntityUsers, i, externalID, conflicts[0].Field, conflicts[0].Message())
+					for _, conflict := range conflicts[1:] {
+						report.addError(EntityUsers, i, externalID, conflict.Field, conflict.Message())
+					}
+					return nil
 				}
-			}
 
-			normalizePhoneAfterCreate := false
-			if cleanPhone != "" {
-				resolvedPhone, applyResolvedPhone, deferPhoneNormalization, err := h.resolveIncomingPhoneConflict(ctx, tx, targetUserID, externalID, cleanPhone, incomingActive, incomingPhoneAssignments)
-				if err != nil {
-					return err
-				}
-				if applyResolvedPhone {
-					entity.PhoneNumber = resolvedPhone
-				} else {
-					entity.PhoneNumber = cleanPhone
+				if cleanEmail != "" {
+					resolvedEmail, applyResolvedEmail, err := h.resolveIncomingEmailConflict(ctx, tx, targetUserID, externalID, cleanEmail, incomingActive)
+					if err != nil {
+						return err
+					}
+					if applyResolvedEmail {
+						entity.Email = resolvedEmail
+					} else {
+						entity.Email = cleanEmail
+					}
 				}
-				normalizePhoneAfterCreate = deferPhoneNormalization
-			}
 
-			if cleanUsername != "" {
-				resolvedUsername, applyResolvedUsername, err := h.resolveIncomingUsernameConflict(ctx, tx, targetUserID, externalID, cleanUsername, incomingActive)
-				if err != nil {
-					return err
-				}
-				if applyResolvedUsername {
-					entity.Username = resolvedUsername
-				} else {
-					entity.Username = &cleanUsername
+				normalizePhoneAfterCreate := false
+				if cleanPhone != "" {
+					resolvedPhone, applyResolvedPhone, deferPhoneNormalization, err := h.resolveIncomingPhoneConflict(ctx, tx, targetUserID, externalID, cleanPhone, incomingActive, incomingPhoneAssignments)
+					if err != nil {
+						return err
+					}
+					if applyResolvedPhone {
+						entity.PhoneNumber = resolvedPhone
+					} else {
+						entity.PhoneNumber = cleanPhone
+					}
+					normalizePhoneAfterCreate = deferPhoneNormalization
 				}
-			}
 
-			var userID uint64
-			if userFound {
-				_, _ = tx.Exec(ctx, "UPDATE users SET deleted_at = NULL WHERE id = $1", existing.ID)
-				if err := h.userRepo.UpdateFromSync(ctx, tx, existing.ID, entity); err != nil {
-					return fmt.Errorf("Update Error User %s: %w", externalID, err)
-				}
-				userID = existing.ID
-				countUpdated++
-			} else {
-				entity.Password = "SYNC_USER_NO_PASSWORD"
-				newID, err := h.userRepo.CreateFromSync(ctx, tx, entity)
-				if err != nil {
-					return fmt.Errorf("Create Error User %s: %w", externalID, err)
+				if cleanUsername != "" {
+					resolvedUsername, applyResolvedUsername, err := h.resolveIncomingUsernameConflict(ctx, tx, targetUserID, externalID, cleanUsername, incomingActive)
+					if err != nil {
+						return err
+					}
+					if applyResolvedUsername {
+						entity.Username = resolvedUsername
+					} else {
+						entity.Username = &cleanUsername
+					}
 				}
 
-				userID = newID
-				if normalizePhoneAfterCreate {
-					entity.PhoneNumber = buildTechnicalPhoneValue(newID, externalID)
-					if _, err := tx.Exec(ctx, "UPDATE users SET phone_number = $1, updated_at = NOW() WHERE id = $2", entity.PhoneNumber, newID); err != nil {
-						return fmt.Errorf("failed to normalize technical phone for user %d: %w", newID, err)
+				var userID uint64
+				if userFound {
+					_, _ = tx.Exec(ctx, "UPDATE users SET deleted_at = NULL WHERE id = $1", existing.ID)
+					if err := h.userRepo.UpdateFromSync(ctx, tx, existing.ID, entity); err != nil {
+						return fmt.Errorf("Update Error User %s: %w", externalID, err)
+					}
+					userID = existing.ID
+				} else {
+					entity.Password = "SYNC_USER_NO_PASSWORD"
+					newID, err := h.userRepo.CreateFromSync(ctx, tx, entity)
+					if err != nil {
+						return fmt.Errorf("Create Error User %s: %w", externalID, err)
+					}
+
+					userID = newID
+					if normalizePhoneAfterCreate {
+						entity.PhoneNumber = buildTechnicalPhoneValue(newID, externalID)
+						if _, err := tx.Exec(ctx, "UPDATE users SET phone_number = $1, updated_at = NOW() WHERE id = $2", entity.PhoneNumber, newID); err != nil {
+							return fmt.Errorf("failed to normalize technical phone for user %d: %w", newID, err)
+						}
+					}
+					for _, rID := range defaultRoleIDs {
+						if _, err := tx.Exec(ctx, "INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", newID, rID); err != nil {
+							return err
+						}
 					}
 				}
-				for _, rID := range defaultRoleIDs {
-					if _, err := tx.Exec(ctx, "INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", newID, rID); err != nil {
-						return err
+
+				// Keep manual assignments: do not delete links, only ensure links from 1C exist.
+				if positionFromPayload && entity.PositionID != nil {
+					if _, err := tx.Exec(ctx, "INSERT INTO user_positions (user_id, position_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, *entity.PositionID); err != nil {
+						return fmt.Errorf("Sync user_positions failed for user %d: %w", userID, err)
 					}
 				}
-				countCreated++
-			}
 
-			// Keep manual assignments: do not delete links, only ensure links from 1C exist.
-			if positionFromPayload && entity.PositionID != nil {
-				if _, err := tx.Exec(ctx, "INSERT INTO user_positions (user_id, position_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, *entity.PositionID); err != nil {
-					return fmt.Errorf("Sync user_positions failed for user %d: %w", userID, err)
+				if otdelFromPayload && entity.OtdelID != nil {
+					if _, err := tx.Exec(ctx, "INSERT INTO user_otdels (user_id, otdel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, *entity.OtdelID); err != nil {
+						return fmt.Errorf("Sync user_otdels failed for user %d: %w", userID, err)
+					}
 				}
-			}
 
-			if otdelFromPayload && entity.OtdelID != nil {
-				if _, err := tx.Exec(ctx, "INSERT INTO user_otdels (user_id, otdel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, *entity.OtdelID); err != nil {
-					return fmt.Errorf("Sync user_otdels failed for user %d: %w", userID, err)
+				// Запись учитывается в отчёте после всех изменений: в режиме best effort ошибка связей
+				// откатывает пользователя целиком.
+				if userFound {
+					countUpdated++
+					report.updated(EntityUsers)
+					report.change(EntityUsers, i, externalID, ChangeUpdate, userChanges(existing, entity, activeStatus, inactiveStatus))
+				} else {
+					countCreated++
+					report.created(EntityUsers)
+					report.change(EntityUsers, i, externalID, ChangeCreate, userChanges(nil, entity, activeStatus, inactiveStatus))
 				}
+				return nil
+			}); err != nil {
+				return err
 			}
 		}
 
-		if validationErr != nil && len(validationErr.Conflicts) > 0 {
+		// В режиме best effort конфликтующие записи уже пропущены и попали в отчёт.
+		if validationErr != nil && len(validationErr.Conflicts) > 0 && !isBestEffort(ctx) {
 			return validationErr
 		}
 
-		return nil
+		return h.deactivateMissing(ctx, tx, EntityUsers, externalIDsOf(data, func(d dto.User1CDTO) string { return strings.TrimSpace(d.ExternalID) }), inactiveStatus)
 	})
 
 	if err != nil {
@@ -445,6 +470,7 @@ func (h *DBHandler) ProcessUsers(ctx context.Context, data []dto.User1CDTO) erro
 			}
 		}
 		h.logger.Error("Critical user sync error", zap.Error(err))
+		report.failed(EntityUsers, err)
 		return err
 	}
 
@@ -916,8 +942,10 @@ func (h *DBHandler) ProcessDepartments(ctx context.Context, data []dto.Departmen
 	countTotal := len(data)
 	countCreated := 0
 	countUpdated := 0
+	report := ReportFromContext(ctx)
 
-	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err := h.runInTx(ctx, func(tx pgx.Tx) error {
+		report.begin(EntityDepartments, countTotal)
 		activeStatus, err := h.statusRepo.FindByCodeInTx(ctx, tx, "ACTIVE")
 		if err != nil {
 			return err
@@ -927,39 +955,49 @@ func (h *DBHandler) ProcessDepartments(ctx context.Context, data []dto.Departmen
 			return err
 		}
 
-		for _, item := range data {
-			statusID := activeStatus.ID
-			if !item.IsActive {
-				statusID = inactiveStatus.ID
-			}
-
-			entity := entities.Department{
-				Name:         item.Name,
-				StatusID:     statusID,
-				ExternalID:   stringToPtr(item.ExternalID),
-				SourceSystem: stringToPtr(sourceSystem1C),
-			}
+		for i, item := range data {
+			if err := h.eachRecord(ctx, tx, EntityDepartments, i, item.ExternalID, func(tx pgx.Tx) error {
+				statusID := activeStatus.ID
+				if !item.IsActive {
+					statusID = inactiveStatus.ID
+				}
 
-			existing, err := h.departmentRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
-			if err != nil && !isNotFound(err) {
-				return fmt.Errorf("DB Error Dept %s: %w", item.ExternalID, err)
-			}
+				entity := entities.Department{
+					Name:         item.Name,
+					StatusID:     statusID,
+					ExternalID:   stringToPtr(item.ExternalID),
+					SourceSystem: stringToPtr(sourceSystem1C),
+				}
 
-			if err == nil {
-				if err := h.departmentRepo.Update(ctx, tx, existing.ID, entity); err != nil {
-					return fmt.Errorf("Update Error Dept %s: %w", item.Name, err)
+				existing, err := h.departmentRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
+				if err != nil && !isNotFound(err) {
+					return fmt.Errorf("DB Error Dept %s: %w", item.ExternalID, err)
 				}
-				countUpdated++
-			} else {
-				if _, err := h.departmentRepo.Create(ctx, tx, entity); err != nil {
-					return fmt.Errorf("Create Error Dept %s: %w", item.Name, err)
+
+				if err == nil {
+					if err := h.departmentRepo.Update(ctx, tx, existing.ID, entity); err != nil {
+						return fmt.Errorf("Update Error Dept %s: %w", item.Name, err)
+					}
+					countUpdated++
+					report.updated(EntityDepartments)
+					report.change(EntityDepartments, i, item.ExternalID, ChangeUpdate, departmentChanges(existing, entity, activeStatus, inactiveStatus))
+				} else {
+					if _, err := h.departmentRepo.Create(ctx, tx, entity); err != nil {
+						return fmt.Errorf("Create Error Dept %s: %w", item.Name, err)
+					}
+					countCreated++
+					report.created(EntityDepartments)
+					report.change(EntityDepartments, i, item.ExternalID, ChangeCreate, departmentChanges(nil, entity, activeStatus, inactiveStatus))
 				}
-				countCreated++
+				return nil
+			}); err != nil {
+				return err
 			}
 		}
-		return nil
+		return h.deactivateMissing(ctx, tx, EntityDepartments, externalIDsOf(data, func(d dto.Department1CDTO) string { return d.ExternalID }), inactiveStatus)
 	})
 
+	report.failed(EntityDepartments, err)
 	if err == nil {
 		h.logger.Info("📊 ДЕПАРТАМЕНТЫ", zap.Int("Всего", countTotal), zap.Int("Создано", countCreated), zap.Int("Обновлено", countUpdated))
 	}
@@ -970,50 +1008,64 @@ func (h *DBHandler) ProcessBranches(ctx context.Context, data []dto.Branch1CDTO)
 	countTotal := len(data)
 	countCreated := 0
 	countUpdated := 0
+	report := ReportFromContext(ctx)
 
-	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err := h.runInTx(ctx, func(tx pgx.Tx) error {
+		report.begin(EntityBranches, countTotal)
 		activeStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "ACTIVE")
 		inactiveStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "INACTIVE")
 
-		for _, item := range data {
-			statusID := activeStatus.ID
-			if !item.IsActive {
-				statusID = inactiveStatus.ID
-			}
-
-			entity := entities.Branch{
-				Name:         item.Name,
-				ShortName:    item.ShortName,
-				Address:      utils.StringToPtr(item.Address),
-				PhoneNumber:  utils.StringToPtr(item.PhoneNumber),
-				Email:        utils.StringToPtr(item.Email),
-				EmailIndex:   utils.StringToPtr(item.EmailIndex),
-				OpenDate:     utils.TimeToPtr(item.OpenDate),
-				StatusID:     statusID,
-				ExternalID:   utils.StringToPtr(item.ExternalID),
-				SourceSystem: utils.StringToPtr(sourceSystem1C),
-			}
+		for i, item := range data {
+			if err := h.eachRecord(ctx, tx, EntityBranches, i, item.ExternalID, func(tx pgx.Tx) error {
+				statusID := activeStatus.ID
+				if !item.IsActive {
+					statusID = inactiveStatus.ID
+				}
 
-			existing, err := h.branchRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
-			if err != nil && !isNotFound(err) {
-				return fmt.Errorf("DB Error Branch %s: %w", item.ExternalID, err)
-			}
+				entity := entities.Branch{
+					Name:         item.Name,
+					ShortName:    item.ShortName,
+					Address:      utils.StringToPtr(item.Address),
+					PhoneNumber:  utils.StringToPtr(item.PhoneNumber),
+					Email:        utils.StringToPtr(item.Email),
+					EmailIndex:   utils.StringToPtr(item.EmailIndex),
+					OpenDate:     utils.TimeToPtr(item.OpenDate),
+					StatusID:     statusID,
+					ExternalID:   utils.StringToPtr(item.ExternalID),
+					SourceSystem: utils.StringToPtr(sourceSystem1C),
+				}
 
-			if err == nil {
-				if err := h.branchRepo.UpdateBranch(ctx, tx, existing.ID, entity); err != nil {
-					return fmt.Errorf("Update Error Branch %s: %w", item.Name, err)
+				existing, err := h.branchRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
+				if err != nil && !isNotFound(err) {
+					return fmt.Errorf("DB Error Branch %s: %w", item.ExternalID, err)
 				}
-				countUpdated++
-			} else {
-				if _, err := h.branchRepo.CreateBranch(ctx, tx, entity); err != nil {
-					return fmt.Errorf("Create Error Branch %s: %w", item.Name, err)
+
+				if err == nil {
+					// Префикс номеров заявок настраивается в системе, 1С его не передаёт
+					entity.OrderPrefix = existing.OrderPrefix
+					if err := h.branchRepo.UpdateBranch(ctx, tx, existing.ID, entity); err != nil {
+						return fmt.Errorf("Update Error Branch %s: %w", item.Name, err)
+					}
+					countUpdated++
+					report.updated(EntityBranches)
+					report.change(EntityBranches, i, item.ExternalID, ChangeUpdate, branchChanges(existing, entity, activeStatus, inactiveStatus))
+				} else {
+					if _, err := h.branchRepo.CreateBranch(ctx, tx, entity); err != nil {
+						return fmt.Errorf("Create Error Branch %s: %w", item.Name, err)
+					}
+					countCreated++
+					report.created(EntityBranches)
+					report.change(EntityBranches, i, item.ExternalID, ChangeCreate, branchChanges(nil, entity, activeStatus, inactiveStatus))
 				}
-				countCreated++
+				return nil
+			}); err != nil {
+				return err
 			}
 		}
-		return nil
+		return h.deactivateMissing(ctx, tx, EntityBranches, externalIDsOf(data, func(d dto.Branch1CDTO) string { return d.ExternalID }), inactiveStatus)
 	})
 
+	report.failed(EntityBranches, err)
 	if err == nil {
 		h.logger.Info("📊 ФИЛИАЛЫ", zap.Int("Всего", countTotal), zap.Int("Создано", countCreated), zap.Int("Обновлено", countUpdated))
 	}
@@ -1024,62 +1076,74 @@ func (h *DBHandler) ProcessOtdels(ctx context.Context, data []dto.Otdel1CDTO) er
 	countTotal := len(data)
 	countCreated := 0
 	countUpdated := 0
+	report := ReportFromContext(ctx)
 
-	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err := h.runInTx(ctx, func(tx pgx.Tx) error {
+		report.begin(EntityOtdels, countTotal)
 		activeStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "ACTIVE")
 		inactiveStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "INACTIVE")
 
-		for _, item := range data {
-			statusID := activeStatus.ID
-			if !item.IsActive {
-				statusID = inactiveStatus.ID
-			}
-
-			var depID, branchID, parentID *uint64
-			if item.ParentExternalID != "" {
-				if p, _ := h.otdelRepo.FindByExternalID(ctx, tx, item.ParentExternalID, sourceSystem1C); p != nil {
-					parentID = &p.ID
-				}
-			} else if item.DepartmentExternalID != "" {
-				if p, _ := h.departmentRepo.FindByExternalID(ctx, tx, item.DepartmentExternalID, sourceSystem1C); p != nil {
-					depID = &p.ID
-				}
-			} else if item.BranchExternalID != "" {
-				if p, _ := h.branchRepo.FindByExternalID(ctx, tx, item.BranchExternalID, sourceSystem1C); p != nil {
-					branchID = &p.ID
+		for i, item := range data {
+			if err := h.eachRecord(ctx, tx, EntityOtdels, i, item.ExternalID, func(tx pgx.Tx) error {
+				statusID := activeStatus.ID
+				if !item.IsActive {
+					statusID = inactiveStatus.ID
 				}
-			}
 
-			entity := entities.Otdel{
-				Name:          item.Name,
-				StatusID:      statusID,
-				DepartmentsID: depID,
-				BranchID:      branchID,
-				ParentID:      parentID,
-				ExternalID:    stringToPtr(item.ExternalID),
-				SourceSystem:  stringToPtr(sourceSystem1C),
-			}
+				var depID, branchID, parentID *uint64
+				if item.ParentExternalID != "" {
+					if p, _ := h.otdelRepo.FindByExternalID(ctx, tx, item.ParentExternalID, sourceSystem1C); p != nil {
+						parentID = &p.ID
+					}
+				} else if item.DepartmentExternalID != "" {
+					if p, _ := h.departmentRepo.FindByExternalID(ctx, tx, item.DepartmentExternalID, sourceSystem1C); p != nil {
+						depID = &p.ID
+					}
+				} else if item.BranchExternalID != "" {
+					if p, _ := h.branchRepo.FindByExternalID(ctx, tx, item.BranchExternalID, sourceSystem1C); p != nil {
+						branchID = &p.ID
+					}
+				}
 
-			existing, err := h.otdelRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
-			if err != nil && !isNotFound(err) {
-				return fmt.Errorf("DB Error Otdel %s: %w", item.ExternalID, err)
-			}
+				entity := entities.Otdel{
+					Name:          item.Name,
+					StatusID:      statusID,
+					DepartmentsID: depID,
+					BranchID:      branchID,
+					ParentID:      parentID,
+					ExternalID:    stringToPtr(item.ExternalID),
+					SourceSystem:  stringToPtr(sourceSystem1C),
+				}
 
-			if err == nil {
-				if err := h.otdelRepo.UpdateOtdel(ctx, tx, existing.ID, entity); err != nil {
-					return fmt.Errorf("Update Error Otdel %s: %w", item.Name, err)
+				existing, err := h.otdelRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
+				if err != nil && !isNotFound(err) {
+					return fmt.Errorf("DB Error Otdel %s: %w", item.ExternalID, err)
 				}
-				countUpdated++
-			} else {
-				if _, err := h.otdelRepo.CreateOtdel(ctx, tx, entity); err != nil {
-					return fmt.Errorf("Create Error Otdel %s: %w", item.Name, err)
+
+				if err == nil {
+					if err := h.otdelRepo.UpdateOtdel(ctx, tx, existing.ID, entity); err != nil {
+						return fmt.Errorf("Update Error Otdel %s: %w", item.Name, err)
+					}
+					countUpdated++
+					report.updated(EntityOtdels)
+					report.change(EntityOtdels, i, item.ExternalID, ChangeUpdate, otdelChanges(existing, entity, activeStatus, inactiveStatus))
+				} else {
+					if _, err := h.otdelRepo.CreateOtdel(ctx, tx, entity); err != nil {
+						return fmt.Errorf("Create Error Otdel %s: %w", item.Name, err)
+					}
+					countCreated++
+					report.created(EntityOtdels)
+					report.change(EntityOtdels, i, item.ExternalID, ChangeCreate, otdelChanges(nil, entity, activeStatus, inactiveStatus))
 				}
-				countCreated++
+				return nil
+			}); err != nil {
+				return err
 			}
 		}
-		return nil
+		return h.deactivateMissing(ctx, tx, EntityOtdels, externalIDsOf(data, func(d dto.Otdel1CDTO) string { return d.ExternalID }), inactiveStatus)
 	})
 
+	report.failed(EntityOtdels, err)
 	if err == nil {
 		h.logger.Info("📊 ОТДЕЛЫ", zap.Int("Всего", countTotal), zap.Int("Создано", countCreated), zap.Int("Обновлено", countUpdated))
 	}
@@ -1090,59 +1154,71 @@ func (h *DBHandler) ProcessOffices(ctx context.Context, data []dto.Office1CDTO)
 	countTotal := len(data)
 	countCreated := 0
 	countUpdated := 0
+	report := ReportFromContext(ctx)
 
-	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err := h.runInTx(ctx, func(tx pgx.Tx) error {
+		report.begin(EntityOffices, countTotal)
 		activeStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "ACTIVE")
 		inactiveStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "INACTIVE")
 
-		for _, item := range data {
-			statusID := activeStatus.ID
-			if !item.IsActive {
-				statusID = inactiveStatus.ID
-			}
-
-			var branchID, parentID *uint64
-			if item.ParentExternalID != "" {
-				if p, _ := h.officeRepo.FindByExternalID(ctx, tx, item.ParentExternalID, sourceSystem1C); p != nil {
-					parentID = &p.ID
-				}
-			} else if item.BranchExternalID != "" {
-				if p, _ := h.branchRepo.FindByExternalID(ctx, tx, item.BranchExternalID, sourceSystem1C); p != nil {
-					branchID = &p.ID
+		for i, item := range data {
+			if err := h.eachRecord(ctx, tx, EntityOffices, i, item.ExternalID, func(tx pgx.Tx) error {
+				statusID := activeStatus.ID
+				if !item.IsActive {
+					statusID = inactiveStatus.ID
 				}
-			}
 
-			entity := entities.Office{
-				Name:         item.Name,
-				Address:      item.Address,
-				OpenDate:     item.OpenDate,
-				StatusID:     statusID,
-				BranchID:     branchID,
-				ParentID:     parentID,
-				ExternalID:   stringToPtr(item.ExternalID),
-				SourceSystem: stringToPtr(sourceSystem1C),
-			}
+				var branchID, parentID *uint64
+				if item.ParentExternalID != "" {
+					if p, _ := h.officeRepo.FindByExternalID(ctx, tx, item.ParentExternalID, sourceSystem1C); p != nil {
+						parentID = &p.ID
+					}
+				} else if item.BranchExternalID != "" {
+					if p, _ := h.branchRepo.FindByExternalID(ctx, tx, item.BranchExternalID, sourceSystem1C); p != nil {
+						branchID = &p.ID
+					}
+				}
 
-			existing, err := h.officeRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
-			if err != nil && !isNotFound(err) {
-				return fmt.Errorf("DB Error Office %s: %w", item.ExternalID, err)
-			}
+				entity := entities.Office{
+					Name:         item.Name,
+					Address:      item.Address,
+					OpenDate:     item.OpenDate,
+					StatusID:     statusID,
+					BranchID:     branchID,
+					ParentID:     parentID,
+					ExternalID:   stringToPtr(item.ExternalID),
+					SourceSystem: stringToPtr(sourceSystem1C),
+				}
 
-			if err == nil {
-				if err := h.officeRepo.UpdateOffice(ctx, tx, existing.ID, entity); err != nil {
-					return fmt.Errorf("Update Error Office %s: %w", item.Name, err)
+				existing, err := h.officeRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
+				if err != nil && !isNotFound(err) {
+					return fmt.Errorf("DB Error Office %s: %w", item.ExternalID, err)
 				}
-				countUpdated++
-			} else {
-				if _, err := h.officeRepo.CreateOffice(ctx, tx, entity); err != nil {
-					return fmt.Errorf("Create Error Office %s: %w", item.Name, err)
+
+				if err == nil {
+					if err := h.officeRepo.UpdateOffice(ctx, tx, existing.ID, entity); err != nil {
+						return fmt.Errorf("Update Error Office %s: %w", item.Name, err)
+					}
+					countUpdated++
+					report.updated(EntityOffices)
+					report.change(EntityOffices, i, item.ExternalID, ChangeUpdate, officeChanges(existing, entity, activeStatus, inactiveStatus))
+				} else {
+					if _, err := h.officeRepo.CreateOffice(ctx, tx, entity); err != nil {
+						return fmt.Errorf("Create Error Office %s: %w", item.Name, err)
+					}
+					countCreated++
+					report.created(EntityOffices)
+					report.change(EntityOffices, i, item.ExternalID, ChangeCreate, officeChanges(nil, entity, activeStatus, inactiveStatus))
 				}
-				countCreated++
+				return nil
+			}); err != nil {
+				return err
 			}
 		}
-		return nil
+		return h.deactivateMissing(ctx, tx, EntityOffices, externalIDsOf(data, func(d dto.Office1CDTO) string { return d.ExternalID }), inactiveStatus)
 	})
 
+	report.failed(EntityOffices, err)
 	if err == nil {
 		h.logger.Info("📊 ОФИСЫ", zap.Int("Всего", countTotal), zap.Int("Создано", countCreated), zap.Int("Обновлено", countUpdated))
 	}
@@ -1153,71 +1229,83 @@ func (h *DBHandler) ProcessPositions(ctx context.Context, data []dto.Position1CD
 	countTotal := len(data)
 	countCreated := 0
 	countUpdated := 0
+	report := ReportFromContext(ctx)
 
-	err := h.txManager.RunInTransaction(ctx, func(tx pgx.Tx) error {
+	err := h.runInTx(ctx, func(tx pgx.Tx) error {
+		report.begin(EntityPositions, countTotal)
 		activeStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "ACTIVE")
 		inactiveStatus, _ := h.statusRepo.FindByCodeInTx(ctx, tx, "INACTIVE")
 
-		for _, item := range data {
-			var depID, otdelID, branchID, officeID *uint64
-			if id := item.DepartmentExternalID; id != nil && *id != "" {
-				if p, _ := h.departmentRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
-					depID = &p.ID
+		for i, item := range data {
+			if err := h.eachRecord(ctx, tx, EntityPositions, i, item.ExternalID, func(tx pgx.Tx) error {
+				var depID, otdelID, branchID, officeID *uint64
+				if id := item.DepartmentExternalID; id != nil && *id != "" {
+					if p, _ := h.departmentRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
+						depID = &p.ID
+					}
 				}
-			}
-			if id := item.OtdelExternalID; id != nil && *id != "" {
-				if p, _ := h.otdelRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
-					otdelID = &p.ID
+				if id := item.OtdelExternalID; id != nil && *id != "" {
+					if p, _ := h.otdelRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
+						otdelID = &p.ID
+					}
 				}
-			}
-			if id := item.BranchExternalID; id != nil && *id != "" {
-				if p, _ := h.branchRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
-					branchID = &p.ID
+				if id := item.BranchExternalID; id != nil && *id != "" {
+					if p, _ := h.branchRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
+						branchID = &p.ID
+					}
 				}
-			}
-			if id := item.OfficeExternalID; id != nil && *id != "" {
-				if p, _ := h.officeRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
-					officeID = &p.ID
+				if id := item.OfficeExternalID; id != nil && *id != "" {
+					if p, _ := h.officeRepo.FindByExternalID(ctx, tx, *id, sourceSystem1C); p != nil {
+						officeID = &p.ID
+					}
 				}
-			}
 
-			statusID := activeStatus.ID
-			if !item.IsActive {
-				statusID = inactiveStatus.ID
-			}
-
-			entity := entities.Position{
-				Name:         item.Name,
-				StatusID:     &statusID,
-				Type:         item.PositionType,
-				DepartmentID: depID,
-				OtdelID:      otdelID,
-				BranchID:     branchID,
-				OfficeID:     officeID,
-				ExternalID:   stringToPtr(item.ExternalID),
-				SourceSystem: stringToPtr(sourceSystem1C),
-			}
+				statusID := activeStatus.ID
+				if !item.IsActive {
+					statusID = inactiveStatus.ID
+				}
 
-			existing, err := h.positionRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
-			if err != nil && !isNotFound(err) {
-				return fmt.Errorf("DB Error Position %s: %w", item.ExternalID, err)
-			}
+				entity := entities.Position{
+					Name:         item.Name,
+					StatusID:     &statusID,
+					Type:         item.PositionType,
+					DepartmentID: depID,
+					OtdelID:      otdelID,
+					BranchID:     branchID,
+					OfficeID:     officeID,
+					ExternalID:   stringToPtr(item.ExternalID),
+					SourceSystem: stringToPtr(sourceSystem1C),
+				}
 
-			if err == nil {
-				if err := h.positionRepo.Update(ctx, tx, existing.ID, entity); err != nil {
-					return fmt.Errorf("Update Error Pos %s: %w", item.Name, err)
+				existing, err := h.positionRepo.FindByExternalID(ctx, tx, item.ExternalID, sourceSystem1C)
+				if err != nil && !isNotFound(err) {
+					return fmt.Errorf("DB Error Position %s: %w", item.ExternalID, err)
 				}
-				countUpdated++
-			} else {
-				if _, err := h.positionRepo.Create(ctx, tx, entity); err != nil {
-					return fmt.Errorf("Create Error Pos %s: %w", item.Name, err)
+
+				if err == nil {
+					if err := h.positionRepo.Update(ctx, tx, existing.ID, entity); err != nil {
+						return fmt.Errorf("Update Error Pos %s: %w", item.Name, err)
+					}
+					countUpdated++
+					report.updated(EntityPositions)
+					report.change(EntityPositions, i, item.ExternalID, ChangeUpdate, positionChanges(existing, entity, activeStatus, inactiveStatus))
+				} else {
+					if _, err := h.positionRepo.Create(ctx, tx, entity); err != nil {
+						return fmt.Errorf("Create Error Pos %s: %w", item.Name, err)
+					}
+					countCreated++
+					report.created(EntityPositions)
+					report.change(EntityPositions, i, item.ExternalID, ChangeCreate, positionChanges(nil, entity, activeStatus, inactiveStatus))
 				}
-				countCreated++
+				return nil
+			}); err != nil {
+				return err
 			}
 		}
-		return nil
+		return h.deactivateMissing(ctx, tx, EntityPositions, externalIDsOf(data, func(d dto.Position1CDTO) string { return d.ExternalID }), inactiveStatus)
 	})
 
+	report.failed(EntityPositions, err)
 	if err == nil {
 		h.logger.Info("📊 ДОЛЖНОСТИ", zap.Int("Всего", countTotal), zap.Int("Создано", countCreated), zap.Int("Обновлено", countUpdated))
 	}
@@ -0,0 +1,112 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"request-system/pkg/config"
+)
+
+// maxPullPages защищает от зацикленного nextLink на стороне 1С.
+const maxPullPages = 1000
+
+// PullSourceInterface — источник изменений справочника для периодической загрузки.
+type PullSourceInterface interface {
+	// FetchChanges возвращает записи справочника, изменённые после cursor, и новый курсор.
+	// Пустой cursor — первая загрузка, выгружается весь справочник.
+	FetchChanges(ctx context.Context, entity, cursor string) ([]json.RawMessage, string, error)
+}
+
+// pullPage — страница ответа сервиса 1С. Формат совместим с OData: записи в value, следующая
+// страница в @odata.nextLink; cursor — отметка, с которой запрашивать изменения в следующий раз.
+type pullPage struct {
+	Value    []json.RawMessage `json:"value"`
+	Cursor   string            `json:"cursor"`
+	NextLink string            `json:"@odata.nextLink"`
+}
+
+// PullClient запрашивает GET {URL}/{entity}?cursor=...&limit=... с basic-авторизацией.
+// Записи должны быть в том же формате, что и выгрузка в POST /api/sync/1c.
+type PullClient struct {
+	httpClient *http.Client
+	cfg        config.OneCPullConfig
+	logger     *zap.Logger
+}
+
+func NewPullClient(cfg config.OneCPullConfig, logger *zap.Logger) PullSourceInterface {
+	return &PullClient{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		cfg:        cfg,
+		logger:     logger.Named("1c_pull_client"),
+	}
+}
+
+func (c *PullClient) FetchChanges(ctx context.Context, entity, cursor string) ([]json.RawMessage, string, error) {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if c.cfg.PageSize > 0 {
+		query.Set("limit", strconv.Itoa(c.cfg.PageSize))
+	}
+	next := c.cfg.URL + "/" + entity
+	if len(query) > 0 {
+		next += "?" + query.Encode()
+	}
+
+	var items []json.RawMessage
+	newCursor := cursor
+	for pages := 0; next != ""; pages++ {
+		if pages >= maxPullPages {
+			return nil, "", fmt.Errorf("1С вернула больше %d страниц справочника %s", maxPullPages, entity)
+		}
+		page, err := c.fetchPage(ctx, next)
+		if err != nil {
+			return nil, "", fmt.Errorf("ошибка загрузки справочника %s из 1С: %w", entity, err)
+		}
+		items = append(items, page.Value...)
+		if page.Cursor != "" {
+			newCursor = page.Cursor
+		}
+		next = page.NextLink
+	}
+
+	c.logger.Debug("Изменения справочника получены из 1С", zap.String("entity", entity), zap.Int("count", len(items)), zap.String("cursor", newCursor))
+	return items, newCursor, nil
+}
+
+func (c *PullClient) fetchPage(ctx context.Context, pageURL string) (*pullPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания GET-запроса: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("сервис 1С вернул статус %s: %s", resp.Status, body)
+	}
+
+	var page pullPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("ошибка разбора ответа 1С: %w", err)
+	}
+	return &page, nil
+}
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type bestEffortContextKey struct{}
+
+// WithBestEffort включает обработку «по возможности»: каждая запись идёт в своей точке сохранения,
+// ошибочная откатывается и попадает в отчёт, остальные сохраняются.
+func WithBestEffort(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bestEffortContextKey{}, true)
+}
+
+func isBestEffort(ctx context.Context) bool {
+	bestEffort, _ := ctx.Value(bestEffortContextKey{}).(bool)
+	return bestEffort
+}
+
+// eachRecord обрабатывает одну запись выгрузки. Без best effort ошибка записи прерывает
+// транзакцию справочника, как раньше.
+func (h *DBHandler) eachRecord(ctx context.Context, tx pgx.Tx, entity string, index int, externalID string, fn func(tx pgx.Tx) error) error {
+	if !isBestEffort(ctx) {
+		return fn(tx)
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(savepoint); err != nil {
+		_ = savepoint.Rollback(ctx)
+		// Отмена контекста — не ошибка записи: продолжать обработку бессмысленно.
+		if ctx.Err() != nil {
+			return err
+		}
+		ReportFromContext(ctx).recordFailed(entity, index, externalID, recordErrorField(err), err.Error())
+		return nil
+	}
+	return savepoint.Commit(ctx)
+}
+
+// recordErrorField достаёт из ошибки Postgres колонку или ограничение, на котором споткнулась запись.
+func recordErrorField(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+	return pgErr.ConstraintName
+}
@@ -0,0 +1,219 @@
+package sync
+
+import (
+	"context"
+	stdsync "sync"
+
+	"request-system/internal/dto"
+)
+
+// Имена справочников в отчёте совпадают с ключами выгрузки 1С.
+const (
+	EntityDepartments = "departments"
+	EntityBranches    = "branches"
+	EntityOtdels      = "otdels"
+	EntityOffices     = "offices"
+	EntityPositions   = "positions"
+	EntityUsers       = "users"
+)
+
+// maxReportErrors ограничивает отчёт: при сломанном маппинге ошибка будет в каждой записи,
+// а для исправления достаточно первых. Так же ограничен список изменений предпросмотра.
+const (
+	maxReportErrors  = 1000
+	maxReportChanges = 1000
+)
+
+type reportContextKey struct{}
+
+// Report собирает прогресс и ошибки записей во время синхронизации. Обработчик находит его
+// в контексте; без отчёта (nil) все методы ничего не делают.
+type Report struct {
+	mu       stdsync.Mutex
+	entities map[string]*dto.SyncEntityStatsDTO
+	errors   []dto.SyncRecordErrorDTO
+	dropped  int
+
+	// dryRun — отчёт предпросмотра: кроме счётчиков собирает изменения полей по записям.
+	dryRun         bool
+	changes        []dto.SyncChangeDTO
+	changesDropped int
+
+	deactivations []dto.SyncDeactivatedDTO
+}
+
+func NewReport() *Report {
+	return &Report{entities: make(map[string]*dto.SyncEntityStatsDTO)}
+}
+
+func NewDryRunReport() *Report {
+	r := NewReport()
+	r.dryRun = true
+	return r
+}
+
+func WithReport(ctx context.Context, r *Report) context.Context {
+	return context.WithValue(ctx, reportContextKey{}, r)
+}
+
+func ReportFromContext(ctx context.Context) *Report {
+	r, _ := ctx.Value(reportContextKey{}).(*Report)
+	return r
+}
+
+func (r *Report) stats(entity string) *dto.SyncEntityStatsDTO {
+	s, ok := r.entities[entity]
+	if !ok {
+		s = &dto.SyncEntityStatsDTO{}
+		r.entities[entity] = s
+	}
+	return s
+}
+
+// begin обнуляет счётчики справочника: транзакция обработчика могла откатиться и начаться заново.
+func (r *Report) begin(entity string, total int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entities[entity] = &dto.SyncEntityStatsDTO{Total: total}
+}
+
+func (r *Report) created(entity string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats(entity)
+	s.Processed++
+	s.Created++
+}
+
+func (r *Report) updated(entity string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats(entity)
+	s.Processed++
+	s.Updated++
+}
+
+// change запоминает, что именно изменится в записи. Обновление без отличий в предпросмотр не попадает.
+func (r *Report) change(entity string, index int, externalID, action string, changes changeSet) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if action == ChangeUpdate && changes.deactivates() {
+		r.stats(entity).Deactivated++
+	}
+	if !r.dryRun || (action == ChangeUpdate && len(changes) == 0) {
+		return
+	}
+	if len(r.changes) >= maxReportChanges {
+		r.changesDropped++
+		return
+	}
+	r.changes = append(r.changes, dto.SyncChangeDTO{
+		Entity: entity, Index: index, ExternalID: externalID, Action: action, Fields: changes,
+	})
+}
+
+// deactivatedMissing — запись отсутствует в полной выгрузке и переведена в INACTIVE.
+func (r *Report) deactivatedMissing(entity string, id uint64, externalID, name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(entity).Deactivated++
+	if len(r.deactivations) < maxReportChanges {
+		r.deactivations = append(r.deactivations, dto.SyncDeactivatedDTO{
+			Entity: entity, ID: id, ExternalID: externalID, Name: name,
+		})
+	}
+}
+
+// recordFailed — запись пропущена из-за ошибки.
+func (r *Report) recordFailed(entity string, index int, externalID, field, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats(entity)
+	s.Processed++
+	s.Failed++
+	r.appendError(entity, index, externalID, field, reason)
+}
+
+// addError — ещё одна ошибка уже учтённой записи (например, второе занятое поле).
+func (r *Report) addError(entity string, index int, externalID, field, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appendError(entity, index, externalID, field, reason)
+}
+
+func (r *Report) appendError(entity string, index int, externalID, field, reason string) {
+	if len(r.errors) >= maxReportErrors {
+		r.dropped++
+		return
+	}
+	r.errors = append(r.errors, dto.SyncRecordErrorDTO{
+		Entity: entity, Index: index, ExternalID: externalID, Field: field, Reason: reason,
+	})
+}
+
+// failed отмечает, что справочник целиком не сохранён: транзакция откатилась.
+func (r *Report) failed(entity string, err error) {
+	if r == nil || err == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msg := err.Error()
+	r.stats(entity).Error = &msg
+}
+
+// Snapshot — копия текущего состояния для сохранения в задание.
+func (r *Report) Snapshot() (map[string]dto.SyncEntityStatsDTO, []dto.SyncRecordErrorDTO, dto.SyncJobSummaryDTO) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	progress := make(map[string]dto.SyncEntityStatsDTO, len(r.entities))
+	var summary dto.SyncJobSummaryDTO
+	for name, s := range r.entities {
+		progress[name] = *s
+		summary.Total += s.Total
+		summary.Processed += s.Processed
+		summary.Created += s.Created
+		summary.Updated += s.Updated
+		summary.Failed += s.Failed
+		summary.Deactivated += s.Deactivated
+	}
+	summary.Errors = len(r.errors) + r.dropped
+	summary.Changes = len(r.changes) + r.changesDropped
+	return progress, append([]dto.SyncRecordErrorDTO{}, r.errors...), summary
+}
+
+// Changes — изменения, собранные в режиме dry run.
+func (r *Report) Changes() []dto.SyncChangeDTO {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]dto.SyncChangeDTO{}, r.changes...)
+}
+
+// Deactivations — записи, деактивированные режимом полной выгрузки.
+func (r *Report) Deactivations() []dto.SyncDeactivatedDTO {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]dto.SyncDeactivatedDTO{}, r.deactivations...)
+}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+)
+
+type snapshotContextKey struct{}
+
+// WithSnapshot включает режим полной выгрузки: после обработки справочника записи 1С, которых
+// нет в выгрузке, переводятся в INACTIVE. Справочники, не переданные в выгрузке, не трогаются.
+func WithSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, snapshotContextKey{}, true)
+}
+
+func isSnapshot(ctx context.Context) bool {
+	snapshot, _ := ctx.Value(snapshotContextKey{}).(bool)
+	return snapshot
+}
+
+type snapshotTable struct {
+	table      string
+	nameColumn string
+	filter     string
+}
+
+var snapshotTables = map[string]snapshotTable{
+	EntityDepartments: {table: "departments", nameColumn: "name"},
+	EntityBranches:    {table: "branches", nameColumn: "name"},
+	EntityOtdels:      {table: "otdels", nameColumn: "name"},
+	EntityOffices:     {table: "offices", nameColumn: "name"},
+	EntityPositions:   {table: "positions", nameColumn: "name"},
+	EntityUsers:       {table: "users", nameColumn: "fio", filter: " AND deleted_at IS NULL"},
+}
+
+// deactivateMissing переводит в INACTIVE записи 1С справочника, external_id которых нет в выгрузке.
+// Записи не удаляются: на них ссылаются заявки и история.
+func (h *DBHandler) deactivateMissing(ctx context.Context, tx pgx.Tx, entity string, externalIDs []string, inactiveStatus *entities.Status) error {
+	if !isSnapshot(ctx) {
+		return nil
+	}
+	if inactiveStatus == nil {
+		return fmt.Errorf("статус INACTIVE не найден, деактивация %s невозможна", entity)
+	}
+	t, ok := snapshotTables[entity]
+	if !ok {
+		return fmt.Errorf("неизвестный справочник синхронизации: %s", entity)
+	}
+
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		UPDATE %s SET status_id = $1, updated_at = NOW()
+		WHERE source_system = $2
+			AND external_id IS NOT NULL
+			AND NOT (external_id = ANY($3))
+			AND status_id IS DISTINCT FROM $1%s
+		RETURNING id, external_id, %s`, t.table, t.filter, t.nameColumn),
+		inactiveStatus.ID, sourceSystem1C, externalIDs)
+	if err != nil {
+		return fmt.Errorf("Deactivate missing %s failed: %w", entity, err)
+	}
+	defer rows.Close()
+
+	report := ReportFromContext(ctx)
+	count := 0
+	for rows.Next() {
+		var (
+			id         uint64
+			externalID string
+			name       string
+		)
+		if err := rows.Scan(&id, &externalID, &name); err != nil {
+			return err
+		}
+		report.deactivatedMissing(entity, id, externalID, name)
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		h.logger.Info("Записи 1С, отсутствующие в полной выгрузке, деактивированы", zap.String("entity", entity), zap.Int("count", count))
+	}
+	return nil
+}
+
+// externalIDsOf — external_id всех записей выгрузки, включая пропущенные из-за ошибок:
+// запись есть в 1С, значит деактивировать её нельзя.
+func externalIDsOf[T any](data []T, externalID func(T) string) []string {
+	ids := make([]string, 0, len(data))
+	for _, item := range data {
+		if id := externalID(item); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"errors"
+
 	"github.com/labstack/echo/v4"
 
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
 )
 
 type Response[T any] struct {
@@ -28,7 +31,7 @@ type PaginationMeta struct {
 func SuccessOne[T any](c echo.Context, code int, message string, data T) error {
 	return c.JSON(code, Response[T]{
 		Status:  true,
-		Message: message,
+		Message: i18n.Message(i18n.FromContext(c.Request().Context()), message),
 		Body:    data,
 	})
 }
@@ -55,18 +58,20 @@ func SuccessList[T any](c echo.Context, message string, list []T, total uint64,
 
 	return c.JSON(200, Response[ListBody[T]]{
 		Status:  true,
-		Message: message,
+		Message: i18n.Message(i18n.FromContext(c.Request().Context()), message),
 		Body:    body,
 	})
 }
 
 func ErrorResponse(c echo.Context, err error) error {
+	lang := i18n.FromContext(c.Request().Context())
 	code := 500
-	msg := "Внутренняя ошибка сервера"
+	msg := i18n.Message(lang, "Внутренняя ошибка сервера")
 
-	if httpErr, ok := err.(*apperrors.HttpError); ok {
+	var httpErr *apperrors.HttpError
+	if errors.As(err, &httpErr) {
 		code = httpErr.Code
-		msg = httpErr.Message
+		msg = httpErr.LocalizedMessage(lang)
 	}
 
 	return c.JSON(code, Response[any]{
@@ -0,0 +1,153 @@
+// Package businesshours считает длительности и сроки только в рабочее время: по рабочим дням
+// недели, в пределах рабочего дня и без праздников.
+package businesshours
+
+import "time"
+
+// maxScanDays ограничивает перебор дней, если в календаре почти нет рабочих дней.
+const maxScanDays = 20 * 366
+
+const (
+	dateLayout      = "2006-01-02"
+	monthDayLayout  = "01-02"
+	minutesInDayMax = 24 * 60
+)
+
+// Calendar — рабочий календарь организации. Nil-календарь означает круглосуточный отсчёт:
+// Between и Add тогда работают с обычным временем.
+type Calendar struct {
+	// Location — пояс, в котором заданы рабочие часы и даты праздников.
+	Location *time.Location
+	// WorkDays — рабочие дни недели.
+	WorkDays map[time.Weekday]bool
+	// DayStart и DayEnd — начало и конец рабочего дня в минутах от полуночи.
+	DayStart int
+	DayEnd   int
+	// Holidays — нерабочие даты (ГГГГ-ММ-ДД), RecurringHolidays — ежегодные (ММ-ДД).
+	Holidays          map[string]bool
+	RecurringHolidays map[string]bool
+	// WorkingDays — перенесённые рабочие дни: дата рабочая, даже если выпадает на выходной.
+	WorkingDays map[string]bool
+}
+
+// New собирает календарь; workDays — дни недели по ISO (1 — понедельник, 7 — воскресенье).
+func New(loc *time.Location, workDays []int, dayStart, dayEnd int) *Calendar {
+	if loc == nil {
+		loc = time.Local
+	}
+	days := make(map[time.Weekday]bool, len(workDays))
+	for _, d := range workDays {
+		days[time.Weekday(d%7)] = true
+	}
+	return &Calendar{
+		Location:          loc,
+		WorkDays:          days,
+		DayStart:          dayStart,
+		DayEnd:            dayEnd,
+		Holidays:          make(map[string]bool),
+		RecurringHolidays: make(map[string]bool),
+		WorkingDays:       make(map[string]bool),
+	}
+}
+
+// AddHoliday отмечает нерабочую дату; recurring — каждый год в тот же день.
+func (c *Calendar) AddHoliday(date time.Time, recurring bool) {
+	if recurring {
+		c.RecurringHolidays[date.Format(monthDayLayout)] = true
+		return
+	}
+	c.Holidays[date.Format(dateLayout)] = true
+}
+
+// AddWorkingDay отмечает перенесённый рабочий день.
+func (c *Calendar) AddWorkingDay(date time.Time) {
+	c.WorkingDays[date.Format(dateLayout)] = true
+}
+
+// IsWorkingDay — рабочий ли день, в который попадает t (в поясе календаря).
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	t = t.In(c.Location)
+	date := t.Format(dateLayout)
+	if c.WorkingDays[date] {
+		return true
+	}
+	if c.Holidays[date] || c.RecurringHolidays[t.Format(monthDayLayout)] {
+		return false
+	}
+	return c.WorkDays[t.Weekday()]
+}
+
+// Between возвращает рабочее время между from и to; если to раньше from — ноль.
+func (c *Calendar) Between(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	if c == nil {
+		return to.Sub(from)
+	}
+
+	var total time.Duration
+	day := c.startOfDay(from)
+	for i := 0; i < maxScanDays && day.Before(to); i++ {
+		if c.IsWorkingDay(day) {
+			start, end := c.workWindow(day)
+			if from.After(start) {
+				start = from
+			}
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// Add возвращает момент, когда от from пройдёт d рабочего времени. Если рабочих дней
+// в календаре не нашлось, срок считается по обычному времени.
+func (c *Calendar) Add(from time.Time, d time.Duration) time.Time {
+	if c == nil || d <= 0 {
+		return from.Add(d)
+	}
+
+	remaining := d
+	day := c.startOfDay(from)
+	for i := 0; i < maxScanDays; i++ {
+		if c.IsWorkingDay(day) {
+			start, end := c.workWindow(day)
+			if from.After(start) {
+				start = from
+			}
+			if end.After(start) {
+				available := end.Sub(start)
+				if available >= remaining {
+					return start.Add(remaining)
+				}
+				remaining -= available
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return from.Add(d)
+}
+
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	t = t.In(c.Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location)
+}
+
+// workWindow — начало и конец рабочего дня day. Конец 24:00 — полночь следующего дня.
+func (c *Calendar) workWindow(day time.Time) (time.Time, time.Time) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), c.DayStart/60, c.DayStart%60, 0, 0, c.Location)
+	end := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.Location).AddDate(0, 0, 1)
+	if c.DayEnd < minutesInDayMax {
+		end = time.Date(day.Year(), day.Month(), day.Day(), c.DayEnd/60, c.DayEnd%60, 0, 0, c.Location)
+	}
+	return start, end
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
@@ -8,19 +9,35 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+
+	"request-system/pkg/ratelimit"
+	"request-system/pkg/telegram"
 )
 
 type Config struct {
-	Server       ServerConfig
-	Postgres     PostgresConfig
-	Redis        RedisConfig
-	JWT          JWTConfig
-	Auth         AuthConfig
-	Integrations IntegrationsConfig
-	Telegram     TelegramConfig
-	Frontend     FrontendConfig
-	LDAP         LDAPConfig
-	Seeder       SeederConfig
+	Server        ServerConfig
+	Postgres      PostgresConfig
+	Redis         RedisConfig
+	RateLimit     RateLimitConfig
+	WebSocket     WebSocketConfig
+	EventStream   EventStreamConfig
+	JWT           JWTConfig
+	Auth          AuthConfig
+	Integrations  IntegrationsConfig
+	Telegram      TelegramConfig
+	Frontend      FrontendConfig
+	LDAP          LDAPConfig
+	Seeder        SeederConfig
+	Docs          DocsConfig
+	GRPC          GRPCConfig
+	Orders        OrdersConfig
+	Portal        PortalConfig
+	Notifications NotificationsConfig
+	Retention     RetentionConfig
+	Backup        BackupConfig
+	Reports       ReportsConfig
+	// Runtime — настройки, перечитываемые без перезапуска (POST /api/admin/config/reload или слежение за .env)
+	Runtime *Runtime
 }
 
 type ServerConfig struct {
@@ -30,10 +47,42 @@ type ServerConfig struct {
 	CertFile       string
 	KeyFile        string
 	Timezone       string
+	// MetricsEnabled открывает GET /metrics (время запросов к БД и вызовы Telegram в формате Prometheus)
+	MetricsEnabled bool
+	// ConfigWatchInterval — как часто проверять, не изменился ли .env; 0 — только по запросу
+	ConfigWatchInterval time.Duration
+
+	// TrustedProxies — адреса и подсети своих обратных прокси. Пусто — IP клиента берётся из соединения
+	TrustedProxies []string
 }
 
+// PostgresConfig — ReplicaDSN задаёт реплику для тяжёлых чтений (список заявок, выгрузка,
+// дашборд). Если реплика недоступна или отстаёт больше ReplicaMaxLag, чтения идут в основную БД.
 type PostgresConfig struct {
-	DSN string
+	DSN                  string
+	ReplicaDSN           string
+	ReplicaMaxLag        time.Duration
+	ReplicaCheckInterval time.Duration
+	Pool                 PoolConfig
+	// Запросы дольше порога пишутся в лог с типами параметров вместо значений; 0 — не писать
+	SlowQueryThreshold time.Duration
+	// AutoMigrate — применять миграции при старте. В кластере из нескольких реплик его
+	// выключают и мигрируют отдельным шагом деплоя (seed migrate up).
+	AutoMigrate bool
+}
+
+// PoolConfig — настройки пула pgx. StatementTimeout — statement_timeout сессии на сервере
+// (0 — без ограничения), HeavyQueryTimeout — таймаут контекста для тяжёлых чтений в
+// репозиториях (список и выгрузка заявок, дашборд, отчёт), чтобы один долгий запрос
+// не держал соединение из пула.
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	StatementTimeout  time.Duration
+	HeavyQueryTimeout time.Duration
 }
 
 type RedisConfig struct {
@@ -41,6 +90,52 @@ type RedisConfig struct {
 	Password string
 }
 
+// RateLimitConfig — лимиты API в формате "<запросов>/<период>" (token bucket в Redis).
+// Auth действует на /api/auth/* по IP, Read/Write — на остальные защищённые маршруты по пользователю.
+type RateLimitConfig struct {
+	Enabled bool
+	Auth    ratelimit.Rule
+	Read    ratelimit.Rule
+	Write   ratelimit.Rule
+
+	runtime *Runtime
+}
+
+// Active — действующие лимиты с учётом перечитанных настроек.
+func (c RateLimitConfig) Active() RateLimitConfig {
+	if c.runtime == nil {
+		return c
+	}
+	return c.runtime.Settings().RateLimit
+}
+
+// WebSocketConfig — при RedisFanout сообщения хаба рассылаются через Redis pub/sub,
+// чтобы клиент, подключённый к другой реплике, тоже получил уведомление.
+// ReplayMaxEvents/ReplayTTL — сколько последних сообщений пользователя и как долго хранится для дочитывания после переподключения.
+type WebSocketConfig struct {
+	RedisFanout     bool
+	RedisChannel    string
+	ReplayMaxEvents int64
+	ReplayTTL       time.Duration
+
+	// TicketTTL — срок одноразового билета POST /api/ws-ticket для подключения к /api/ws
+	TicketTTL time.Duration
+	// MaxConnectionsPerUser — сколько соединений одного пользователя держит реплика; 0 — без ограничения
+	MaxConnectionsPerUser int
+}
+
+// EventStreamConfig — зеркалирование событий шины во внешний брокер.
+// Driver: "" (выключено), "kafka" или "nats". Топики/subject'ы: <TopicPrefix>.<имя события>.
+type EventStreamConfig struct {
+	Driver                string
+	TopicPrefix           string
+	BufferSize            int
+	KafkaBrokers          []string
+	KafkaAutoCreateTopics bool
+	NATSURL               string
+	NATSStream            string
+}
+
 type JWTConfig struct {
 	SecretKey       string
 	AccessTokenTTL  time.Duration
@@ -54,13 +149,30 @@ type AuthConfig struct {
 	MaxLoginAttempts    int
 	LockoutDuration     time.Duration
 	SystemRootLogin     string
+	// ImpersonationTTL — срок жизни токена входа под другим пользователем.
+	ImpersonationTTL time.Duration
 }
 
 type IntegrationsConfig struct {
 	ActiveProvider         string
 	OneCApiKey             string
+	AnalyticsApiKeys       []string
 	DefaultRolesFor1CUsers []string
 	OnlineBank             OnlineBankConfig
+	OneCPull               OneCPullConfig
+
+	// WebhookAllowedNetworks — внутренние адреса и подсети, на которые разрешено слать webhook.
+	// Без них loopback, частные и link-local адреса отклоняются.
+	WebhookAllowedNetworks []string
+}
+
+// OneCPullConfig — периодическая загрузка справочников из HTTP/OData-сервиса 1С. Пустой URL выключает загрузку.
+type OneCPullConfig struct {
+	URL      string
+	Username string
+	Password string
+	Interval time.Duration
+	PageSize int
 }
 
 type OnlineBankConfig struct {
@@ -74,18 +186,64 @@ type TelegramConfig struct {
 	BotUsername        string
 	WebhookSecretToken string
 	AdvancedMode       bool
+	// UpdateMode — способ получения обновлений: "webhook" (по умолчанию) или "polling".
+	UpdateMode     string
+	PollingTimeout time.Duration
+	// LinkTokenTTL — сколько живёт код привязки Telegram с сайта.
+	LinkTokenTTL time.Duration
+	// Send — лимиты и автомат исходящих вызовов Bot API.
+	Send telegram.Limits
+
+	runtime *Runtime
+}
+
+// AdvancedModeActive — включён ли расширенный режим бота с учётом перечитанных настроек.
+func (c TelegramConfig) AdvancedModeActive() bool {
+	if c.runtime == nil {
+		return c.AdvancedMode
+	}
+	return c.runtime.Settings().TelegramAdvancedMode
+}
+
+const (
+	TelegramUpdateModeWebhook = "webhook"
+	TelegramUpdateModePolling = "polling"
+)
+
+// PollingEnabled сообщает, что обновления нужно забирать через getUpdates вместо webhook.
+func (c TelegramConfig) PollingEnabled() bool {
+	return c.UpdateMode == TelegramUpdateModePolling
 }
 
 type FrontendConfig struct {
 	BaseURL string
 }
 
+const (
+	LDAPTLSModeNone     = "none"
+	LDAPTLSModeLDAPS    = "ldaps"
+	LDAPTLSModeStartTLS = "starttls"
+)
+
 type LDAPConfig struct {
 	Enabled bool
 	Host    string
 	Port    int
 	Domain  string
 
+	// Резервные контроллеры домена (host или host:port, без порта берётся Port).
+	// Недоступный сервер пропускается на ServerCooldown.
+	FallbackHosts  []string
+	ServerCooldown time.Duration
+	PoolSize       int
+
+	// TLSMode — none, ldaps или starttls. CACertFile — PEM с корневыми сертификатами
+	// для проверки контроллеров домена; без него используется системное хранилище.
+	TLSMode            string
+	CACertFile         string
+	TLSServerName      string
+	InsecureSkipVerify bool
+
 	SearchEnabled bool
 
 	BindDN              string
@@ -96,20 +254,202 @@ type LDAPConfig struct {
 	SearchAttributes    []string
 	UsernameAttribute   string
 	FIOAttribute        string
+
+	// Сопоставление групп AD с ролями: при входе и раз в сутки в GroupSyncHour.
+	// ProvisionUsers — создавать локальных пользователей из AD, если у них есть
+	// хотя бы одна сопоставленная группа.
+	GroupSyncEnabled bool
+	ProvisionUsers   bool
+	GroupAttribute   string
+	EmailAttribute   string
+	GroupSyncHour    int
+
+	runtime *Runtime
+}
+
+// Active — включена ли авторизация через LDAP с учётом перечитанных настроек.
+func (c *LDAPConfig) Active() bool {
+	if c.runtime == nil {
+		return c.Enabled
+	}
+	return c.runtime.Settings().LDAPEnabled
+}
+
+// NotificationsConfig — общие выключатели каналов уведомлений о заявках.
+type NotificationsConfig struct {
+	TelegramEnabled  bool
+	WebSocketEnabled bool
+	// GroupingWindow — сколько ждать остальные события той же правки заявки, чтобы отправить
+	// их одним сообщением; 0 отправляет каждое событие сразу.
+	GroupingWindow time.Duration
+	// ImmediateEvents — типы событий истории, которые уходят без ожидания окна.
+	ImmediateEvents []string
+	// ImmediatePriorities — коды приоритетов: смена приоритета на них тоже уходит сразу.
+	ImmediatePriorities []string
+	// EscalationThreshold — столько нарушений срока одним исполнителем за EscalationWindow
+	// приводят к уведомлению руководителя; департамент может задать свой порог, 0 выключает
+	// эскалацию там, где своего порога нет.
+	EscalationThreshold int
+	EscalationWindow    time.Duration
+	// EscalationInterval — как часто искать повторные нарушения; 0 выключает проверку.
+	EscalationInterval time.Duration
+	// FirstResponseRemindPercent — после такой доли срока первого отклика без действий
+	// исполнителю приходит напоминание; 0 выключает напоминания.
+	FirstResponseRemindPercent int
+	// FirstResponseInterval — как часто искать заявки без отклика; 0 выключает проверку.
+	FirstResponseInterval time.Duration
+
+	runtime *Runtime
+}
+
+// TelegramActive — отправлять ли уведомления о заявках в Telegram с учётом перечитанных настроек.
+func (c NotificationsConfig) TelegramActive() bool {
+	if c.runtime == nil {
+		return c.TelegramEnabled
+	}
+	return c.runtime.Settings().NotifyTelegram
+}
+
+// WebSocketActive — отправлять ли уведомления о заявках по WebSocket с учётом перечитанных настроек.
+func (c NotificationsConfig) WebSocketActive() bool {
+	if c.runtime == nil {
+		return c.WebSocketEnabled
+	}
+	return c.runtime.Settings().NotifyWebSocket
+}
+
+// DocsConfig — /api/docs (Swagger UI) и /api/docs/openapi.json. Если у сервера нет выхода
+// в интернет, swagger-ui-dist можно положить рядом и указать его адрес в SwaggerUIAssetsURL.
+type DocsConfig struct {
+	Enabled            bool
+	SwaggerUIAssetsURL string
+}
+
+// GRPCConfig — gRPC-сервер для внутренних сервисов (чтение заявок и пользователей).
+// ServiceTokens — пары "ключ:userID": вызов по ключу выполняется с правами этого пользователя.
+type GRPCConfig struct {
+	Enabled       bool
+	Port          string
+	TLS           bool
+	ServiceTokens []string
 }
 
+// OrdersConfig — подсказка о возможных дублях: при создании заявки ищутся заявки по тому же
+// оборудованию с похожим названием за последние DuplicateHintDays дней (0 — выключено).
+// ListViewReconcileInterval — как часто сверять проекцию списка заявок order_list_view с
+// исходными таблицами (переименованные статусы, приоритеты, сотрудники); 0 выключает сверку.
+type OrdersConfig struct {
+	DuplicateHintDays         int
+	ListViewReconcileInterval time.Duration
+
+	// Печатный акт заявки: свой шаблон и шрифты TrueType вместо встроенных; пусто — встроенные
+	ActTemplatePath string
+	ActFontPath     string
+	ActBoldFontPath string
+}
+
+// RetentionConfig — фоновый запуск правил хранения. Interval 0 выключает запуск по расписанию,
+// правила по-прежнему можно запустить вручную.
+type RetentionConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// BackupConfig — резервные копии важных таблиц через pg_dump. Копии сохраняются в хранилище
+// с корнем Dir, который не раздаётся как /uploads. Interval 0 выключает запуск по расписанию;
+// Verify — после каждой копии проверять её восстановление во временную схему; Keep — сколько
+// последних успешных копий хранить, файлы более старых удаляются.
+type BackupConfig struct {
+	Interval      time.Duration
+	Dir           string
+	Tables        []string
+	PgDumpPath    string
+	PgRestorePath string
+	Verify        bool
+	Keep          int
+}
+
+// ReportsConfig — оформление отчёта по дашборду (PDF/PNG): название и цвет организации
+// в шапке, логотип PNG или JPEG и шрифты TrueType; пустые пути — без логотипа и со встроенными шрифтами.
+type ReportsConfig struct {
+	BrandName    string
+	BrandColor   string
+	LogoPath     string
+	FontPath     string
+	BoldFontPath string
+}
+
+// backupDefaultTables — заявки, их история и всё, без чего заявки не восстановить.
+const backupDefaultTables = "users,roles,permissions,role_permissions,user_roles,user_permissions,user_permission_denials," +
+	"statuses,priorities,order_types,departments,otdels,branches,offices,positions,orders,order_history,attachments"
+
+// PortalConfig — публичный портал обращений клиентов филиалов (без авторизации).
+// Заявки создаются от имени служебного пользователя UserID с типом OrderTypeCode
+// и попадают в DepartmentID, если клиент не выбрал филиал.
+// CaptchaProvider: builtin — своя картинка-пример в Redis; hcaptcha/recaptcha — проверка токена через siteverify.
+type PortalConfig struct {
+	Enabled         bool
+	UserID          uint64
+	OrderTypeCode   string
+	DepartmentID    uint64
+	SubmitLimit     ratelimit.Rule
+	ReadLimit       ratelimit.Rule
+	CaptchaProvider string
+	CaptchaSiteKey  string
+	CaptchaSecret   string
+	CaptchaTTL      time.Duration
+
+	// CaptchaAllowBuiltin разрешает встроенную капчу. Она только для разработки: цифры в SVG бот
+	// читает прямо из path-данных, поэтому в работе нужен hcaptcha или recaptcha.
+	CaptchaAllowBuiltin bool
+}
+
+// Validate не даёт включить портал без настоящей капчи: это публичная форма без авторизации.
+func (c PortalConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.CaptchaProvider {
+	case CaptchaProviderHCaptcha, CaptchaProviderReCaptcha:
+		if c.CaptchaSiteKey == "" || c.CaptchaSecret == "" {
+			return fmt.Errorf("PORTAL_CAPTCHA_PROVIDER=%s: нужны PORTAL_CAPTCHA_SITE_KEY и PORTAL_CAPTCHA_SECRET", c.CaptchaProvider)
+		}
+	case CaptchaProviderBuiltin:
+		if !c.CaptchaAllowBuiltin {
+			return fmt.Errorf("PORTAL_ENABLED: встроенная капча только для разработки; настройте PORTAL_CAPTCHA_PROVIDER=hcaptcha|recaptcha или включите PORTAL_CAPTCHA_ALLOW_BUILTIN")
+		}
+	default:
+		return fmt.Errorf("PORTAL_CAPTCHA_PROVIDER: неизвестный провайдер %q", c.CaptchaProvider)
+	}
+	return nil
+}
+
+const (
+	CaptchaProviderBuiltin   = "builtin"
+	CaptchaProviderHCaptcha  = "hcaptcha"
+	CaptchaProviderReCaptcha = "recaptcha"
+)
+
 type SeederConfig struct {
 	AdminEmail    string
 	AdminPassword string
 }
 
 func New() *Config {
-	if err := godotenv.Load(); err != nil {
+	// Что задано в окружении процесса, главнее .env — запоминаем до загрузки файла
+	processEnv := make(map[string]bool, len(runtimeKeys))
+	for _, key := range runtimeKeys {
+		_, processEnv[key] = os.LookupEnv(key)
+	}
+
+	if err := godotenv.Load(envFile); err != nil {
 		log.Println("⚠️  Файл .env не найден или не может быть загружен. Используются системные переменные окружения.")
 	} else {
 		log.Println("✅ Файл .env загружен.")
 	}
 
+	settings := loadRuntimeSettings()
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:           getEnv("SERVER_PORT", "8091"),
@@ -118,14 +458,52 @@ func New() *Config {
 			CertFile:       getEnv("SSL_CERT_PATH", "./certs/server.crt"),
 			KeyFile:        getEnv("SSL_KEY_PATH", "./certs/server.key"),
 			Timezone:       getEnv("APP_TIMEZONE", "Asia/Tashkent"),
+			MetricsEnabled: getEnvAsBool("METRICS_ENABLED", true),
+
+			ConfigWatchInterval: time.Duration(getEnvAsInt("CONFIG_WATCH_INTERVAL_SECONDS", 0)) * time.Second,
+
+			TrustedProxies: parseList(getEnv("TRUSTED_PROXIES", "")),
 		},
 		Postgres: PostgresConfig{
-			DSN: getRequiredEnv("DATABASE_URL"),
+			DSN:                  getRequiredEnv("DATABASE_URL"),
+			ReplicaDSN:           getEnv("DATABASE_REPLICA_URL", ""),
+			ReplicaMaxLag:        time.Duration(getEnvAsInt("DB_REPLICA_MAX_LAG_SECONDS", 10)) * time.Second,
+			ReplicaCheckInterval: time.Duration(getEnvAsInt("DB_REPLICA_CHECK_INTERVAL_SECONDS", 5)) * time.Second,
+			SlowQueryThreshold:   time.Duration(getEnvAsInt("DB_SLOW_QUERY_MS", 500)) * time.Millisecond,
+			AutoMigrate:          getEnvAsBool("DB_AUTO_MIGRATE", true),
+			Pool: PoolConfig{
+				MaxConns:          int32(getEnvAsInt("DB_POOL_MAX_CONNS", 30)),
+				MinConns:          int32(getEnvAsInt("DB_POOL_MIN_CONNS", 5)),
+				MaxConnLifetime:   time.Duration(getEnvAsInt("DB_POOL_MAX_CONN_LIFETIME_MINUTES", 30)) * time.Minute,
+				MaxConnIdleTime:   time.Duration(getEnvAsInt("DB_POOL_MAX_CONN_IDLE_MINUTES", 5)) * time.Minute,
+				HealthCheckPeriod: time.Duration(getEnvAsInt("DB_POOL_HEALTH_CHECK_PERIOD_SECONDS", 30)) * time.Second,
+				StatementTimeout:  time.Duration(getEnvAsInt("DB_STATEMENT_TIMEOUT_SECONDS", 60)) * time.Second,
+				HeavyQueryTimeout: time.Duration(getEnvAsInt("DB_HEAVY_QUERY_TIMEOUT_SECONDS", 20)) * time.Second,
+			},
 		},
 		Redis: RedisConfig{
 			Address:  getEnv("REDIS_ADDRESS", "localhost:6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 		},
+		RateLimit: settings.RateLimit,
+		WebSocket: WebSocketConfig{
+			RedisFanout:     getEnvAsBool("WS_REDIS_FANOUT_ENABLED", false),
+			RedisChannel:    getEnv("WS_REDIS_CHANNEL", "ws:fanout"),
+			ReplayMaxEvents: int64(getEnvAsInt("WS_REPLAY_MAX_EVENTS", 200)),
+			ReplayTTL:       time.Duration(getEnvAsInt("WS_REPLAY_TTL_MINUTES", 60)) * time.Minute,
+
+			TicketTTL:             time.Duration(getEnvAsInt("WS_TICKET_TTL_SECONDS", 30)) * time.Second,
+			MaxConnectionsPerUser: getEnvAsInt("WS_MAX_CONNECTIONS_PER_USER", 10),
+		},
+		EventStream: EventStreamConfig{
+			Driver:                strings.ToLower(getEnvNormalized("EVENT_STREAM_DRIVER", "")),
+			TopicPrefix:           getEnvNormalized("EVENT_STREAM_TOPIC_PREFIX", "request-system"),
+			BufferSize:            getEnvAsInt("EVENT_STREAM_BUFFER_SIZE", 1000),
+			KafkaBrokers:          parseList(getEnv("EVENT_STREAM_KAFKA_BROKERS", "localhost:9092")),
+			KafkaAutoCreateTopics: getEnvAsBool("EVENT_STREAM_KAFKA_AUTO_CREATE_TOPICS", false),
+			NATSURL:               getEnvNormalized("EVENT_STREAM_NATS_URL", "nats://localhost:4222"),
+			NATSStream:            getEnvNormalized("EVENT_STREAM_NATS_STREAM", "REQUEST_SYSTEM"),
+		},
 		JWT: JWTConfig{
 			SecretKey:       getRequiredEnv("JWT_SECRET_KEY"),
 			AccessTokenTTL:  time.Hour * 24,
@@ -138,6 +516,7 @@ func New() *Config {
 			ResetTokenTTL:       15 * time.Minute,
 			VerificationCodeTTL: 15 * time.Minute,
 			SystemRootLogin:     strings.ToLower(getEnv("SEED_ADMIN_EMAIL", "admin@local")),
+			ImpersonationTTL:    time.Duration(getEnvAsInt("IMPERSONATION_TTL_MINUTES", 15)) * time.Minute,
 		},
 		Seeder: SeederConfig{
 			AdminEmail:    getEnv("SEED_ADMIN_EMAIL", ""),
@@ -146,28 +525,107 @@ func New() *Config {
 		Integrations: IntegrationsConfig{
 			ActiveProvider:         getEnv("INTEGRATION_ACTIVE_PROVIDER", "mock"),
 			OneCApiKey:             getEnv("ONE_C_API_KEY", ""),
+			AnalyticsApiKeys:       parseList(getEnv("ANALYTICS_API_KEYS", "")),
 			DefaultRolesFor1CUsers: parseList(getEnv("DEFAULT_ROLES_FOR_1C_USERS", "USER")),
 			OnlineBank: OnlineBankConfig{
 				BaseURL:  getEnv("ONLINEBANK_BASE_URL", ""),
 				Username: getEnv("ONLINEBANK_USERNAME", ""),
 				Password: getEnv("ONLINEBANK_PASSWORD", ""),
 			},
+			OneCPull: OneCPullConfig{
+				URL:      strings.TrimRight(getEnvNormalized("ONE_C_PULL_URL", ""), "/"),
+				Username: getEnv("ONE_C_PULL_USERNAME", ""),
+				Password: getEnv("ONE_C_PULL_PASSWORD", ""),
+				Interval: time.Duration(getEnvAsInt("ONE_C_PULL_INTERVAL_MINUTES", 15)) * time.Minute,
+				PageSize: getEnvAsInt("ONE_C_PULL_PAGE_SIZE", 500),
+			},
+
+			WebhookAllowedNetworks: parseList(getEnv("WEBHOOK_ALLOWED_NETWORKS", "")),
 		},
 		Telegram: TelegramConfig{
 			BotToken:           getEnvNormalized("TELEGRAM_BOT_TOKEN", ""),
 			BotUsername:        strings.TrimPrefix(getEnvNormalized("TELEGRAM_BOT_USERNAME", ""), "@"),
 			WebhookSecretToken: getEnvNormalized("TELEGRAM_WEBHOOK_SECRET_TOKEN", ""),
-			AdvancedMode:       getEnvAsBool("TELEGRAM_ADVANCED_MODE_ENABLED", false),
+			AdvancedMode:       settings.TelegramAdvancedMode,
+			UpdateMode:         strings.ToLower(getEnvNormalized("TELEGRAM_UPDATE_MODE", TelegramUpdateModeWebhook)),
+			PollingTimeout:     time.Duration(getEnvAsInt("TELEGRAM_POLLING_TIMEOUT_SECONDS", 30)) * time.Second,
+			LinkTokenTTL:       time.Duration(getEnvAsInt("TELEGRAM_LINK_TOKEN_TTL_MINUTES", 10)) * time.Minute,
+			Send: telegram.Limits{
+				GlobalPerSecond:  float64(getEnvAsInt("TELEGRAM_SEND_RATE_PER_SECOND", 25)),
+				ChatInterval:     time.Duration(getEnvAsInt("TELEGRAM_CHAT_SEND_INTERVAL_MS", 1000)) * time.Millisecond,
+				MaxWait:          time.Duration(getEnvAsInt("TELEGRAM_SEND_MAX_WAIT_SECONDS", 30)) * time.Second,
+				BreakerThreshold: getEnvAsInt("TELEGRAM_BREAKER_THRESHOLD", 5),
+				BreakerCooldown:  time.Duration(getEnvAsInt("TELEGRAM_BREAKER_COOLDOWN_SECONDS", 30)) * time.Second,
+			},
 		},
 		Frontend: FrontendConfig{
 			BaseURL: getEnvNormalized("FRONTEND_BASE_URL", "http://localhost:3000"),
 		},
+		Docs: DocsConfig{
+			Enabled:            getEnvAsBool("API_DOCS_ENABLED", true),
+			SwaggerUIAssetsURL: strings.TrimRight(getEnvNormalized("API_DOCS_SWAGGER_UI_URL", "https://unpkg.com/swagger-ui-dist@5"), "/"),
+		},
+		GRPC: GRPCConfig{
+			Enabled:       getEnvAsBool("GRPC_ENABLED", false),
+			Port:          getEnv("GRPC_PORT", "9091"),
+			TLS:           getEnvAsBool("GRPC_TLS_ENABLED", true),
+			ServiceTokens: parseList(getEnv("GRPC_SERVICE_TOKENS", "")),
+		},
+		Orders: OrdersConfig{
+			DuplicateHintDays:         getEnvAsInt("ORDER_DUPLICATE_HINT_DAYS", 7),
+			ListViewReconcileInterval: time.Duration(getEnvAsInt("ORDER_LIST_VIEW_RECONCILE_MINUTES", 60)) * time.Minute,
+
+			ActTemplatePath: getEnv("ORDER_ACT_TEMPLATE", ""),
+			ActFontPath:     getEnv("ORDER_ACT_FONT", ""),
+			ActBoldFontPath: getEnv("ORDER_ACT_BOLD_FONT", ""),
+		},
+		Retention: RetentionConfig{
+			Interval:  time.Duration(getEnvAsInt("RETENTION_INTERVAL_HOURS", 24)) * time.Hour,
+			BatchSize: getEnvAsInt("RETENTION_BATCH_SIZE", 200),
+		},
+		Backup: BackupConfig{
+			Interval:      time.Duration(getEnvAsInt("BACKUP_INTERVAL_HOURS", 24)) * time.Hour,
+			Dir:           getEnv("BACKUP_DIR", "backups"),
+			Tables:        parseList(getEnv("BACKUP_TABLES", backupDefaultTables)),
+			PgDumpPath:    getEnv("BACKUP_PG_DUMP_PATH", "pg_dump"),
+			PgRestorePath: getEnv("BACKUP_PG_RESTORE_PATH", "pg_restore"),
+			Verify:        getEnvAsBool("BACKUP_VERIFY", true),
+			Keep:          getEnvAsInt("BACKUP_KEEP", 14),
+		},
+		Reports: ReportsConfig{
+			BrandName:    getEnv("REPORT_BRAND_NAME", "Request System"),
+			BrandColor:   getEnv("REPORT_BRAND_COLOR", "#1F4E79"),
+			LogoPath:     getEnv("REPORT_LOGO", ""),
+			FontPath:     getEnv("REPORT_FONT", ""),
+			BoldFontPath: getEnv("REPORT_BOLD_FONT", ""),
+		},
+		Portal: PortalConfig{
+			Enabled:         getEnvAsBool("PORTAL_ENABLED", false),
+			UserID:          uint64(getEnvAsInt("PORTAL_USER_ID", 0)),
+			OrderTypeCode:   getEnv("PORTAL_ORDER_TYPE_CODE", "PORTAL"),
+			DepartmentID:    uint64(getEnvAsInt("PORTAL_DEPARTMENT_ID", 0)),
+			SubmitLimit:     getEnvAsRateLimitRule("PORTAL_RATE_LIMIT_SUBMIT", "5/10m"),
+			ReadLimit:       getEnvAsRateLimitRule("PORTAL_RATE_LIMIT_READ", "30/1m"),
+			CaptchaProvider: strings.ToLower(getEnvNormalized("PORTAL_CAPTCHA_PROVIDER", CaptchaProviderBuiltin)),
+			CaptchaSiteKey:  getEnv("PORTAL_CAPTCHA_SITE_KEY", ""),
+			CaptchaSecret:   getEnv("PORTAL_CAPTCHA_SECRET", ""),
+			CaptchaTTL:      time.Duration(getEnvAsInt("PORTAL_CAPTCHA_TTL_SECONDS", 300)) * time.Second,
+
+			CaptchaAllowBuiltin: getEnvAsBool("PORTAL_CAPTCHA_ALLOW_BUILTIN", false),
+		},
 		LDAP: LDAPConfig{
-			Enabled:             getEnvAsBool("LDAP_ENABLED", false),
+			Enabled:             settings.LDAPEnabled,
 			SearchEnabled:       getEnvAsBool("LDAP_SEARCH_ENABLED", false),
 			Host:                getEnv("LDAP_HOST", "ldap.local"),
 			Port:                getEnvAsInt("LDAP_PORT", 389),
 			Domain:              getEnv("LDAP_DOMAIN", ""),
+			FallbackHosts:       parseList(getEnv("LDAP_FALLBACK_HOSTS", "")),
+			ServerCooldown:      time.Duration(getEnvAsInt("LDAP_SERVER_COOLDOWN_SECONDS", 30)) * time.Second,
+			PoolSize:            getEnvAsInt("LDAP_POOL_SIZE", 5),
+			TLSMode:             strings.ToLower(getEnvNormalized("LDAP_TLS_MODE", LDAPTLSModeNone)),
+			CACertFile:          getEnv("LDAP_CA_CERT_FILE", ""),
+			TLSServerName:       getEnv("LDAP_TLS_SERVER_NAME", ""),
+			InsecureSkipVerify:  getEnvAsBool("LDAP_TLS_INSECURE_SKIP_VERIFY", false),
 			BindDN:              getEnv("LDAP_BIND_DN", ""),
 			BindPassword:        getEnv("LDAP_BIND_PASSWORD", ""),
 			Timeout:             time.Duration(getEnvAsInt("LDAP_TIMEOUT_SECONDS", 10)) * time.Second,
@@ -176,9 +634,34 @@ func New() *Config {
 			SearchAttributes:    parseList(getEnv("LDAP_SEARCH_ATTRIBUTES", "sAMAccountName,displayName,mail")),
 			UsernameAttribute:   getEnv("LDAP_SEARCH_ATTR_USERNAME", "sAMAccountName"),
 			FIOAttribute:        getEnv("LDAP_SEARCH_ATTR_FIO", "displayName"),
+			GroupSyncEnabled:    getEnvAsBool("LDAP_GROUP_SYNC_ENABLED", false),
+			ProvisionUsers:      getEnvAsBool("LDAP_PROVISION_USERS", false),
+			GroupAttribute:      getEnv("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+			EmailAttribute:      getEnv("LDAP_EMAIL_ATTRIBUTE", "mail"),
+			GroupSyncHour:       getEnvAsInt("LDAP_GROUP_SYNC_HOUR", 2),
+		},
+		Notifications: NotificationsConfig{
+			TelegramEnabled:     settings.NotifyTelegram,
+			WebSocketEnabled:    settings.NotifyWebSocket,
+			GroupingWindow:      time.Duration(getEnvAsInt("NOTIFY_GROUPING_WINDOW_MS", 2000)) * time.Millisecond,
+			ImmediateEvents:     parseList(strings.ToUpper(getEnv("NOTIFY_IMMEDIATE_EVENTS", "SLA_BREACH"))),
+			ImmediatePriorities: parseList(strings.ToUpper(getEnv("NOTIFY_IMMEDIATE_PRIORITIES", "CRITICAL"))),
+
+			EscalationThreshold: getEnvAsInt("SLA_ESCALATION_THRESHOLD", 3),
+			EscalationWindow:    time.Duration(getEnvAsInt("SLA_ESCALATION_WINDOW_HOURS", 168)) * time.Hour,
+			EscalationInterval:  time.Duration(getEnvAsInt("SLA_ESCALATION_CHECK_MINUTES", 5)) * time.Minute,
+
+			FirstResponseRemindPercent: getEnvAsInt("FIRST_RESPONSE_REMIND_PERCENT", 75),
+			FirstResponseInterval:      time.Duration(getEnvAsInt("FIRST_RESPONSE_CHECK_MINUTES", 1)) * time.Minute,
 		},
 	}
 
+	cfg.Runtime = newRuntime(processEnv, settings)
+	cfg.RateLimit.runtime = cfg.Runtime
+	cfg.Telegram.runtime = cfg.Runtime
+	cfg.LDAP.runtime = cfg.Runtime
+	cfg.Notifications.runtime = cfg.Runtime
+
 	return cfg
 }
 
@@ -226,6 +709,15 @@ func getEnvAsInt(key string, fallback int) int {
 	return val
 }
 
+func getEnvAsRateLimitRule(key, fallback string) ratelimit.Rule {
+	rule, err := ratelimit.ParseRule(getEnv(key, fallback))
+	if err != nil {
+		log.Printf("⚠️  %s: %v. Используется значение по умолчанию %s.", key, err, fallback)
+		rule, _ = ratelimit.ParseRule(fallback)
+	}
+	return rule
+}
+
 func parseList(s string) []string {
 	if s == "" {
 		return nil
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"request-system/pkg/ratelimit"
+)
+
+// envFile — файл, из которого New и Runtime.Reload читают переменные.
+const envFile = ".env"
+
+// runtimeKeys — переменные, которые перечитываются без перезапуска.
+var runtimeKeys = []string{
+	"TELEGRAM_ADVANCED_MODE_ENABLED",
+	"LDAP_ENABLED",
+	"NOTIFY_TELEGRAM_ENABLED",
+	"NOTIFY_WEBSOCKET_ENABLED",
+	"RATE_LIMIT_ENABLED",
+	"RATE_LIMIT_AUTH",
+	"RATE_LIMIT_READ",
+	"RATE_LIMIT_WRITE",
+}
+
+// RuntimeSettings — настройки, которые можно поменять в .env и перечитать на ходу.
+type RuntimeSettings struct {
+	TelegramAdvancedMode bool
+	LDAPEnabled          bool
+	NotifyTelegram       bool
+	NotifyWebSocket      bool
+	RateLimit            RateLimitConfig
+}
+
+func loadRuntimeSettings() RuntimeSettings {
+	return RuntimeSettings{
+		TelegramAdvancedMode: getEnvAsBool("TELEGRAM_ADVANCED_MODE_ENABLED", false),
+		LDAPEnabled:          getEnvAsBool("LDAP_ENABLED", false),
+		NotifyTelegram:       getEnvAsBool("NOTIFY_TELEGRAM_ENABLED", true),
+		NotifyWebSocket:      getEnvAsBool("NOTIFY_WEBSOCKET_ENABLED", true),
+		RateLimit: RateLimitConfig{
+			Enabled: getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Auth:    getEnvAsRateLimitRule("RATE_LIMIT_AUTH", "10/1m"),
+			Read:    getEnvAsRateLimitRule("RATE_LIMIT_READ", "300/1m"),
+			Write:   getEnvAsRateLimitRule("RATE_LIMIT_WRITE", "60/1m"),
+		},
+	}
+}
+
+// Values — настройки в виде «переменная окружения → значение» для ответа API и журнала аудита.
+func (s RuntimeSettings) Values() map[string]string {
+	return map[string]string{
+		"TELEGRAM_ADVANCED_MODE_ENABLED": strconv.FormatBool(s.TelegramAdvancedMode),
+		"LDAP_ENABLED":                   strconv.FormatBool(s.LDAPEnabled),
+		"NOTIFY_TELEGRAM_ENABLED":        strconv.FormatBool(s.NotifyTelegram),
+		"NOTIFY_WEBSOCKET_ENABLED":       strconv.FormatBool(s.NotifyWebSocket),
+		"RATE_LIMIT_ENABLED":             strconv.FormatBool(s.RateLimit.Enabled),
+		"RATE_LIMIT_AUTH":                ruleValue(s.RateLimit.Auth),
+		"RATE_LIMIT_READ":                ruleValue(s.RateLimit.Read),
+		"RATE_LIMIT_WRITE":               ruleValue(s.RateLimit.Write),
+	}
+}
+
+func ruleValue(rule ratelimit.Rule) string {
+	if !rule.Enabled() {
+		return "0"
+	}
+	return rule.String()
+}
+
+// RuntimeChange — изменившиеся при перечитывании настройки: старое и новое значение по имени переменной.
+type RuntimeChange struct {
+	Before map[string]string
+	After  map[string]string
+}
+
+func (c RuntimeChange) Empty() bool {
+	return len(c.After) == 0
+}
+
+// Keys — имена изменившихся переменных по алфавиту.
+func (c RuntimeChange) Keys() []string {
+	keys := make([]string, 0, len(c.After))
+	for key := range c.After {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func diffRuntimeSettings(before, after RuntimeSettings) RuntimeChange {
+	change := RuntimeChange{Before: map[string]string{}, After: map[string]string{}}
+	oldValues, newValues := before.Values(), after.Values()
+	for key, value := range newValues {
+		if oldValues[key] != value {
+			change.Before[key] = oldValues[key]
+			change.After[key] = value
+		}
+	}
+	return change
+}
+
+// Runtime хранит действующие RuntimeSettings. Переменные, заданные в окружении процесса при
+// старте, главнее .env и при перечитывании не меняются — как и при обычном запуске.
+type Runtime struct {
+	current    atomic.Pointer[RuntimeSettings]
+	mu         sync.Mutex
+	processEnv map[string]bool
+	envModTime time.Time
+}
+
+func newRuntime(processEnv map[string]bool, settings RuntimeSettings) *Runtime {
+	r := &Runtime{processEnv: processEnv}
+	r.current.Store(&settings)
+	if info, err := os.Stat(envFile); err == nil {
+		r.envModTime = info.ModTime()
+	}
+	return r
+}
+
+// NewStaticRuntime — Runtime с неизменяемыми настройками (для тестов и сидеров).
+func NewStaticRuntime(settings RuntimeSettings) *Runtime {
+	r := &Runtime{processEnv: map[string]bool{}}
+	r.current.Store(&settings)
+	return r
+}
+
+// Settings возвращает действующие настройки.
+func (r *Runtime) Settings() RuntimeSettings {
+	return *r.current.Load()
+}
+
+// Reload перечитывает .env и применяет изменившиеся настройки.
+func (r *Runtime) Reload() (RuntimeChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values, err := godotenv.Read(envFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return RuntimeChange{}, err
+	}
+	if info, statErr := os.Stat(envFile); statErr == nil {
+		r.envModTime = info.ModTime()
+	}
+
+	for _, key := range runtimeKeys {
+		if r.processEnv[key] {
+			continue
+		}
+		if value, ok := values[key]; ok {
+			os.Setenv(key, value)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+
+	before := r.Settings()
+	after := loadRuntimeSettings()
+	r.current.Store(&after)
+	return diffRuntimeSettings(before, after), nil
+}
+
+// EnvFileChanged сообщает, что .env изменился после последнего чтения.
+func (r *Runtime) EnvFileChanged() bool {
+	info, err := os.Stat(envFile)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !info.ModTime().Equal(r.envModTime)
+}
@@ -4,3 +4,11 @@ const (
 	DashboardCacheVersionSummaryKey  = "dashboard:version:summary"
 	DashboardCacheVersionActivityKey = "dashboard:version:activity"
 )
+
+// PermissionsCacheVersionKey входит в ключи кэша прав пользователей; его увеличение
+// сбрасывает кэш прав всех пользователей разом.
+const PermissionsCacheVersionKey = "auth:permissions:version"
+
+// OrderListCacheVersionKey входит в ключи кэша первой страницы списка заявок; его увеличивает
+// каждое событие истории заявки.
+const OrderListCacheVersionKey = "orders:list:version"
@@ -16,6 +16,12 @@ func (uc UploadContext) String() string {
 	return string(uc)
 }
 
+//============== TENANTS ==============
+
+// DefaultTenantID — организация по умолчанию: к ней относятся данные, созданные до разделения
+// на организации, и записи фоновых задач без организации в контексте.
+const DefaultTenantID uint64 = 1
+
 //============== USER STATUSES ==============
 
 // ID статусов пользователей. Используются для записи в БД, но не для логики.
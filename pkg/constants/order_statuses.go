@@ -13,6 +13,7 @@ const (
 	StatusClarification = "CLARIFICATION"
 	StatusConfirmed     = "CONFIRMED"
 	StatusService       = "SERVICE"
+	StatusDuplicate     = "DUPLICATE"
 )
 
 // Финальные статусы
@@ -20,6 +21,7 @@ var FinalStatuses = []string{
 	StatusClosed,
 	StatusCompleted,
 	StatusRejected,
+	StatusDuplicate,
 }
 
 // Функция-проверка
@@ -0,0 +1,24 @@
+package constants
+
+// --- ПРИОРИТЕТЫ ЗАЯВОК (Совпадает с кодами в БД) ---
+const (
+	PriorityLow      = "LOW"
+	PriorityMedium   = "MEDIUM"
+	PriorityHigh     = "HIGH"
+	PriorityCritical = "CRITICAL"
+)
+
+// ProtectedPriorityCodes — коды, на которые опираются дашборд и коннекторы чатов (счётчик
+// критических заявок, фильтр «только критические»). Такой приоритет нельзя удалить или сменить ему код.
+var ProtectedPriorityCodes = []string{
+	PriorityCritical,
+}
+
+func IsProtectedPriorityCode(code string) bool {
+	for _, c := range ProtectedPriorityCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -9,4 +9,11 @@ const (
 	RoleIDKey             contextKey = "RoleID"
 	UserPermissionsMapKey contextKey = "userPermissionsMap"
 	UserEntityKey         contextKey = "userEntity"
+	ImpersonatorIDKey     contextKey = "ImpersonatorID"
+	// Условия прав (ABAC) из ролей пользователя: map[string][]authz.Condition
+	UserPermissionConditionsKey contextKey = "userPermissionConditions"
+	// Организация (tenant), в которой работает пользователь: uint64
+	TenantIDKey contextKey = "tenantID"
+	// Часовой пояс пользователя: *time.Location
+	LocationKey contextKey = "location"
 )
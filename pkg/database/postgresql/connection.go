@@ -4,15 +4,18 @@ import (
 	"context"
 	"log"
 	"net/url"
-	"os"
 	"strconv"
-	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"request-system/pkg/config"
 )
 
-func ConnectDB(dsn string) *pgxpool.Pool {
+// ConnectDB подключается к основной БД; tracer (может быть nil) получает все запросы пула.
+func ConnectDB(cfg config.PostgresConfig, tracer pgx.QueryTracer) *pgxpool.Pool {
+	dsn := cfg.DSN
 	log.Printf("ℹ️ Попытка подключения к БД для приложения: %s", sanitizeDSNForLog(dsn))
 
 	poolConfig, err := pgxpool.ParseConfig(dsn)
@@ -20,7 +23,8 @@ func ConnectDB(dsn string) *pgxpool.Pool {
 		log.Fatalf("Ошибка парсинга DSN: %v", err)
 	}
 
-	applyPoolConfig(poolConfig)
+	applyPoolConfig(poolConfig, cfg.Pool)
+	poolConfig.ConnConfig.Tracer = tracer
 
 	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
@@ -33,12 +37,13 @@ func ConnectDB(dsn string) *pgxpool.Pool {
 
 	log.Println("✅ Успешное подключение к PostgreSQL для приложения")
 	log.Printf(
-		"Пул PostgreSQL настроен: max_conns=%d, min_conns=%d, max_lifetime=%s, max_idle=%s, health_check=%s",
+		"Пул PostgreSQL настроен: max_conns=%d, min_conns=%d, max_lifetime=%s, max_idle=%s, health_check=%s, statement_timeout=%s",
 		poolConfig.MaxConns,
 		poolConfig.MinConns,
 		poolConfig.MaxConnLifetime,
 		poolConfig.MaxConnIdleTime,
 		poolConfig.HealthCheckPeriod,
+		cfg.Pool.StatementTimeout,
 	)
 
 	return dbpool
@@ -60,38 +65,26 @@ func sanitizeDSNForLog(dsn string) string {
 	return parsed.String()
 }
 
-func applyPoolConfig(poolConfig *pgxpool.Config) {
-	poolConfig.MaxConns = readEnvInt32("DB_POOL_MAX_CONNS", 30)
-	poolConfig.MinConns = readEnvInt32("DB_POOL_MIN_CONNS", 5)
-	poolConfig.MaxConnLifetime = readEnvDuration("DB_POOL_MAX_CONN_LIFETIME_MINUTES", 30*time.Minute, time.Minute)
-	poolConfig.MaxConnIdleTime = readEnvDuration("DB_POOL_MAX_CONN_IDLE_MINUTES", 5*time.Minute, time.Minute)
-	poolConfig.HealthCheckPeriod = readEnvDuration("DB_POOL_HEALTH_CHECK_PERIOD_SECONDS", 30*time.Second, time.Second)
-}
-
-func readEnvInt32(name string, fallback int32) int32 {
-	raw := os.Getenv(name)
-	if raw == "" {
-		return fallback
+// applyPoolConfig переносит настройки пула в pgx. Неположительные значения оставляют
+// значения pgx по умолчанию; statement_timeout задаётся параметром сессии, поэтому сервер
+// сам прерывает запрос, даже если клиент перестал ждать.
+func applyPoolConfig(poolConfig *pgxpool.Config, pool config.PoolConfig) {
+	if pool.MaxConns > 0 {
+		poolConfig.MaxConns = pool.MaxConns
 	}
-
-	value, err := strconv.ParseInt(raw, 10, 32)
-	if err != nil || value <= 0 {
-		return fallback
+	if pool.MinConns > 0 && pool.MinConns <= poolConfig.MaxConns {
+		poolConfig.MinConns = pool.MinConns
 	}
-
-	return int32(value)
-}
-
-func readEnvDuration(name string, fallback time.Duration, unit time.Duration) time.Duration {
-	raw := os.Getenv(name)
-	if raw == "" {
-		return fallback
+	if pool.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = pool.MaxConnLifetime
 	}
-
-	value, err := strconv.Atoi(raw)
-	if err != nil || value <= 0 {
-		return fallback
+	if pool.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = pool.MaxConnIdleTime
+	}
+	if pool.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = pool.HealthCheckPeriod
+	}
+	if pool.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(pool.StatementTimeout.Milliseconds(), 10)
 	}
-
-	return time.Duration(value) * unit
 }
@@ -0,0 +1,24 @@
+package postgresql
+
+import (
+	"database/sql"
+	"os"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// MigrationsDir — каталог миграций goose относительно рабочего каталога приложения.
+const MigrationsDir = "./database/migrations"
+
+// NewMigrator создаёт goose-провайдер для миграций из dir. Применение и откат идут под
+// advisory-блокировкой Postgres, так что реплики, стартующие одновременно, мигрируют по очереди,
+// а не наперегонки. db лучше открывать отдельно от пула приложения: у пула есть
+// statement_timeout, которого долгой миграции может не хватить.
+func NewMigrator(db *sql.DB, dir string) (*goose.Provider, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+	return goose.NewProvider(goose.DialectPostgres, db, os.DirFS(dir), goose.WithSessionLocker(locker))
+}
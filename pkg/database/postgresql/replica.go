@@ -0,0 +1,227 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/pkg/config"
+)
+
+// Reader — запросы только на чтение; его реализуют *pgxpool.Pool, pgx.Tx и ReadRouter.
+type Reader interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// replicaLagQuery — отставание реплики в секундах. Если всё полученное WAL уже применено,
+// отставания нет, даже когда последняя транзакция была давно (на простаивающей основной БД).
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// ReadRouter направляет тяжёлые чтения на реплику, пока она доступна и отстаёт не больше
+// maxLag, иначе — в основную БД. Состояние реплики обновляет фоновая проверка (Start);
+// ошибка соединения при запросе сразу переключает чтения на основную БД до следующей проверки.
+type ReadRouter struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+	maxLag  time.Duration
+	healthy atomic.Bool
+	logger  *zap.Logger
+}
+
+// NewReadRouter создаёт маршрутизатор. Без реплики (replica == nil) все чтения идут в primary.
+func NewReadRouter(primary, replica *pgxpool.Pool, maxLag time.Duration, logger *zap.Logger) *ReadRouter {
+	return &ReadRouter{primary: primary, replica: replica, maxLag: maxLag, logger: logger}
+}
+
+// ConnectReplica подключается к реплике. В отличие от ConnectDB не завершает процесс:
+// приложение работает и без реплики.
+func ConnectReplica(ctx context.Context, cfg config.PostgresConfig, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
+	dsn := cfg.ReplicaDSN
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга DSN реплики: %w", err)
+	}
+	applyPoolConfig(poolConfig, cfg.Pool)
+	poolConfig.ConnConfig.Tracer = tracer
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания пула реплики %s: %w", sanitizeDSNForLog(dsn), err)
+	}
+	return pool, nil
+}
+
+// Start проверяет реплику сразу и затем каждые interval, пока не отменён ctx.
+func (r *ReadRouter) Start(ctx context.Context, interval time.Duration) {
+	if r.replica == nil {
+		return
+	}
+	r.check(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.check(ctx)
+		}
+	}
+}
+
+func (r *ReadRouter) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var lagSeconds float64
+	err := r.replica.QueryRow(checkCtx, replicaLagQuery).Scan(&lagSeconds)
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	healthy := err == nil && lag <= r.maxLag
+
+	if previous := r.healthy.Swap(healthy); previous != healthy {
+		if healthy {
+			r.logger.Info("Реплика БД доступна, тяжёлые чтения идут на неё", zap.Duration("lag", lag))
+		} else {
+			r.logger.Warn("Реплика БД недоступна или отстаёт, чтения переключены на основную БД",
+				zap.Duration("lag", lag), zap.Duration("max_lag", r.maxLag), zap.Error(err))
+		}
+	}
+}
+
+// UsingReplica — идут ли сейчас чтения на реплику.
+func (r *ReadRouter) UsingReplica() bool {
+	return r.replica != nil && r.healthy.Load()
+}
+
+func (r *ReadRouter) pick() (*pgxpool.Pool, bool) {
+	if r.UsingReplica() {
+		return r.replica, true
+	}
+	return r.primary, false
+}
+
+// Query выполняет запрос на реплике, а при ошибке соединения с ней — повторяет в основной БД.
+func (r *ReadRouter) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	pool, onReplica := r.pick()
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil && onReplica && r.fallbackOn(ctx, err) {
+		return r.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+// QueryRow — то же для одной строки; ошибка становится известна только при Scan.
+func (r *ReadRouter) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	pool, onReplica := r.pick()
+	row := pool.QueryRow(ctx, sql, args...)
+	if !onReplica {
+		return row
+	}
+	return &fallbackRow{row: row, router: r, ctx: ctx, sql: sql, args: args}
+}
+
+// fallbackOn решает, повторять ли запрос в основной БД: при сбое соединения с репликой
+// и при отмене запроса из-за конфликта с восстановлением (40001 на hot standby), но не при
+// ошибке самого запроса, отсутствии строк или отмене контекста.
+func (r *ReadRouter) fallbackOn(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001"
+	}
+	if r.healthy.Swap(false) {
+		r.logger.Warn("Ошибка соединения с репликой БД, чтения переключены на основную БД", zap.Error(err))
+	}
+	return true
+}
+
+type fallbackRow struct {
+	row    pgx.Row
+	router *ReadRouter
+	ctx    context.Context
+	sql    string
+	args   []any
+}
+
+func (f *fallbackRow) Scan(dest ...any) error {
+	err := f.row.Scan(dest...)
+	if err != nil && f.router.fallbackOn(f.ctx, err) {
+		return f.router.primary.QueryRow(f.ctx, f.sql, f.args...).Scan(dest...)
+	}
+	return err
+}
+
+// WithQueryTimeout ограничивает каждый запрос через reader таймаутом d, чтобы долгий запрос
+// (например, дашборд за большой период) не занимал соединение из пула бесконечно. Контекст
+// отменяется при закрытии строк или после Scan; d <= 0 — без ограничения.
+func WithQueryTimeout(reader Reader, d time.Duration) Reader {
+	if d <= 0 {
+		return reader
+	}
+	return &timeoutReader{reader: reader, timeout: d}
+}
+
+type timeoutReader struct {
+	reader  Reader
+	timeout time.Duration
+}
+
+func (t *timeoutReader) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	rows, err := t.reader.Query(queryCtx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (t *timeoutReader) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	queryCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	return &timeoutRow{row: t.reader.QueryRow(queryCtx, sql, args...), cancel: cancel}
+}
+
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// Next отменяет контекст, как только строки закончились: не все вызывающие закрывают rows явно.
+func (r *timeoutRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.cancel()
+	return false
+}
+
+type timeoutRow struct {
+	row    pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.row.Scan(dest...)
+}
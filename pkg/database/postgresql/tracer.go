@@ -0,0 +1,229 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// queryDurationBuckets — границы гистограммы времени запроса в секундах.
+var queryDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// slowQuerySQLMaxLen — сколько символов SQL попадает в лог медленного запроса.
+const slowQuerySQLMaxLen = 2000
+
+// QueryTracer — pgx.QueryTracer, который пишет медленные запросы в лог и копит время
+// запросов по репозиториям. Значения параметров в лог не попадают — только их типы.
+type QueryTracer struct {
+	slowThreshold time.Duration
+	metrics       *QueryMetrics
+	logger        *zap.Logger
+}
+
+// NewQueryTracer создаёт трассировщик; slowThreshold <= 0 отключает лог медленных запросов.
+func NewQueryTracer(slowThreshold time.Duration, metrics *QueryMetrics, logger *zap.Logger) *QueryTracer {
+	return &QueryTracer{slowThreshold: slowThreshold, metrics: metrics, logger: logger}
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	start  time.Time
+	sql    string
+	args   []any
+	source querySource
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{
+		start:  time.Now(),
+		sql:    data.SQL,
+		args:   data.Args,
+		source: callerSource(),
+	})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(trace.start)
+	slow := t.slowThreshold > 0 && elapsed >= t.slowThreshold
+	if t.metrics != nil {
+		t.metrics.observe(trace.source.repository, elapsed, data.Err != nil, slow)
+	}
+	if !slow {
+		return
+	}
+	t.logger.Warn("Медленный запрос к БД",
+		zap.String("repository", trace.source.repository),
+		zap.String("method", trace.source.method),
+		zap.Duration("duration", elapsed),
+		zap.String("sql", compactSQL(trace.sql)),
+		zap.Strings("args", redactArgs(trace.args)),
+		zap.Error(data.Err),
+	)
+}
+
+// querySource — откуда вызван запрос: тип репозитория (метка метрик) и метод (для лога).
+type querySource struct {
+	repository string
+	method     string
+}
+
+const (
+	modulePrefix       = "request-system/"
+	repositoriesPrefix = "request-system/internal/repositories."
+)
+
+// callerSource ищет в стеке первый метод репозитория. Если запрос выполнен не из
+// репозитория (транзакция в сервисе, сидер), берётся первая функция приложения.
+func callerSource() querySource {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var fallback *querySource
+	for {
+		frame, more := frames.Next()
+		name := frame.Function
+		if strings.HasPrefix(name, modulePrefix) && !strings.HasPrefix(name, modulePrefix+"pkg/database/") {
+			source := sourceFromFunction(name)
+			if strings.HasPrefix(name, repositoriesPrefix) && source.method != "" {
+				return source
+			}
+			if fallback == nil {
+				fallback = &source
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	if fallback != nil {
+		return *fallback
+	}
+	return querySource{repository: "other"}
+}
+
+// sourceFromFunction разбирает имя вида request-system/internal/repositories.(*OrderRepository).GetOrders.
+// У функций без получателя method пуст, а метка — «пакет.функция».
+func sourceFromFunction(name string) querySource {
+	name = name[strings.LastIndex(name, "/")+1:]
+	pkg, rest, _ := strings.Cut(name, ".")
+	if strings.HasPrefix(rest, "(") {
+		receiver, method, _ := strings.Cut(rest, ").")
+		receiver = strings.TrimPrefix(strings.TrimPrefix(receiver, "("), "*")
+		method, _, _ = strings.Cut(method, ".")
+		return querySource{repository: receiver, method: method}
+	}
+	function, _, _ := strings.Cut(rest, ".")
+	return querySource{repository: pkg + "." + function}
+}
+
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > slowQuerySQLMaxLen {
+		return sql[:slowQuerySQLMaxLen] + "…"
+	}
+	return sql
+}
+
+// redactArgs заменяет значения параметров их типами: в запросах бывают ФИО, телефоны и хеши паролей.
+func redactArgs(args []any) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		redacted[i] = fmt.Sprintf("$%d=%T", i+1, arg)
+	}
+	return redacted
+}
+
+// QueryMetrics — гистограммы времени запросов по репозиториям с выводом в формате Prometheus.
+type QueryMetrics struct {
+	mu     sync.Mutex
+	series map[string]*queryHistogram
+}
+
+type queryHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+	errors  uint64
+	slow    uint64
+}
+
+func NewQueryMetrics() *QueryMetrics {
+	return &QueryMetrics{series: make(map[string]*queryHistogram)}
+}
+
+func (m *QueryMetrics) observe(repository string, elapsed time.Duration, failed, slow bool) {
+	seconds := elapsed.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.series[repository]
+	if !ok {
+		h = &queryHistogram{buckets: make([]uint64, len(queryDurationBuckets))}
+		m.series[repository] = h
+	}
+	for i, bound := range queryDurationBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+	if failed {
+		h.errors++
+	}
+	if slow {
+		h.slow++
+	}
+}
+
+// WritePrometheus выводит накопленные метрики в текстовом формате Prometheus.
+func (m *QueryMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	repositories := make([]string, 0, len(m.series))
+	for repository := range m.series {
+		repositories = append(repositories, repository)
+	}
+	sort.Strings(repositories)
+
+	var b strings.Builder
+	b.WriteString("# HELP db_query_duration_seconds Время запросов к PostgreSQL по репозиториям.\n")
+	b.WriteString("# TYPE db_query_duration_seconds histogram\n")
+	for _, repository := range repositories {
+		h := m.series[repository]
+		for i, bound := range queryDurationBuckets {
+			fmt.Fprintf(&b, "db_query_duration_seconds_bucket{repository=%q,le=\"%g\"} %d\n", repository, bound, h.buckets[i])
+		}
+		fmt.Fprintf(&b, "db_query_duration_seconds_bucket{repository=%q,le=\"+Inf\"} %d\n", repository, h.count)
+		fmt.Fprintf(&b, "db_query_duration_seconds_sum{repository=%q} %g\n", repository, h.sum)
+		fmt.Fprintf(&b, "db_query_duration_seconds_count{repository=%q} %d\n", repository, h.count)
+	}
+	b.WriteString("# HELP db_query_errors_total Запросы к PostgreSQL, завершившиеся ошибкой.\n")
+	b.WriteString("# TYPE db_query_errors_total counter\n")
+	for _, repository := range repositories {
+		fmt.Fprintf(&b, "db_query_errors_total{repository=%q} %d\n", repository, m.series[repository].errors)
+	}
+	b.WriteString("# HELP db_slow_queries_total Запросы к PostgreSQL дольше порога медленного запроса.\n")
+	b.WriteString("# TYPE db_slow_queries_total counter\n")
+	for _, repository := range repositories {
+		fmt.Fprintf(&b, "db_slow_queries_total{repository=%q} %d\n", repository, m.series[repository].slow)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
+
+	"request-system/pkg/i18n"
 )
 
 type HttpError struct {
@@ -15,6 +17,10 @@ type HttpError struct {
 	Details interface{}            `json:"details,omitempty"`
 	Err     error                  `json:"-"`
 	Context map[string]interface{} `json:"-"`
+	// MessageKey и MessageArgs — ключ каталога pkg/i18n для сообщений с подстановками;
+	// Message в этом случае хранит русский вариант для логов.
+	MessageKey  string        `json:"-"`
+	MessageArgs []interface{} `json:"-"`
 }
 
 func (e *HttpError) Error() string {
@@ -24,6 +30,15 @@ func (e *HttpError) Error() string {
 	return fmt.Sprintf("code: %d, message: %s", e.Code, e.Message)
 }
 
+// LocalizedMessage возвращает текст ошибки на языке lang: по ключу каталога, если он задан,
+// иначе перевод готового русского текста.
+func (e *HttpError) LocalizedMessage(lang string) string {
+	if e.MessageKey != "" {
+		return i18n.T(lang, e.MessageKey, e.MessageArgs...)
+	}
+	return i18n.Message(lang, e.Message)
+}
+
 func NewHttpError(code int, message string, err error, context map[string]interface{}) *HttpError {
 	return &HttpError{
 		Code:    code,
@@ -43,6 +58,16 @@ func NewHttpErrorWithDetails(code int, message string, err error, context map[st
 	}
 }
 
+// NewLocalizedError создаёт ошибку, текст которой берётся из каталога pkg/i18n по ключу.
+func NewLocalizedError(code int, key string, args ...interface{}) *HttpError {
+	return &HttpError{
+		Code:        code,
+		Message:     i18n.T(i18n.DefaultLang, key, args...),
+		MessageKey:  key,
+		MessageArgs: args,
+	}
+}
+
 func NewBadRequestError(message string) *HttpError {
 	if message == "" {
 		return ErrBadRequest
@@ -71,6 +96,7 @@ var (
 	ErrTokenIsNotAccess     = NewHttpError(http.StatusUnauthorized, "Токен не является access токеном", nil, nil)
 	ErrInvalidAuthHeader    = NewHttpError(http.StatusUnauthorized, "Недействительный заголовок авторизации", nil, nil)
 	ErrEmptyAuthHeader      = NewHttpError(http.StatusUnauthorized, "Отсутствует заголовок авторизации", nil, nil)
+	ErrTooManyRequests      = NewHttpError(http.StatusTooManyRequests, "Слишком много запросов, повторите позже", nil, nil)
 
 	ErrChangePasswordWithToken = NewHttpErrorWithDetails(http.StatusAccepted, "Требуется смена пароля", nil, nil, nil)
 	ErrNoChanges               = NewHttpError(http.StatusBadRequest, "Нет изменений в запросе", nil, nil)
@@ -131,6 +157,13 @@ var exactConstraintSpecs = map[string]dbConstraintSpec{
 	"unique_order_type_id_in_rules":             {statusCode: http.StatusBadRequest, message: "Правило маршрутизации для этого типа заявки уже существует."},
 	"ux_role_permissions_role_id_permission_id": {statusCode: http.StatusBadRequest, message: "Это право уже назначено данной роли."},
 	"idx_users_username_unique":                 {statusCode: http.StatusConflict, message: "Этот логин AD уже привязан к другому пользователю."},
+	"idx_equipments_serial_number_unique":       {statusCode: http.StatusConflict, message: "Оборудование с таким серийным номером уже существует."},
+	"uq_teams_name":                             {statusCode: http.StatusConflict, message: "Команда с таким названием уже существует."},
+	"uq_skills_code":                            {statusCode: http.StatusConflict, message: "Навык с таким кодом уже существует."},
+	"uq_resolution_codes_code":                  {statusCode: http.StatusConflict, message: "Код решения с таким кодом уже существует."},
+	"fk_orders_resolution_code_id":              {statusCode: http.StatusConflict, message: "Код решения уже указан в заявках. Отключите его вместо удаления."},
+	"uq_custom_field_definitions_code":          {statusCode: http.StatusConflict, message: "У этого типа заявки уже есть поле с таким кодом."},
+	"uq_order_type_validation_rules_field":      {statusCode: http.StatusConflict, message: "Для этого поля у типа заявки уже есть правило."},
 }
 
 var prefixConstraintSpecs = map[string]dbConstraintSpec{
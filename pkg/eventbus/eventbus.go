@@ -2,6 +2,7 @@ package eventbus
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time" // Убедитесь, что этот импорт есть, он нужен для WithTimeout
 
@@ -31,6 +32,9 @@ func New(logger *zap.Logger) *Bus {
 	}
 }
 
+// AllEvents — имя подписки, получающей все события шины (см. SubscribeAll).
+const AllEvents = "*"
+
 // Subscribe подписывает слушателя на определенное событие.
 func (b *Bus) Subscribe(eventName string, listener Listener) {
 	b.mu.Lock()
@@ -38,13 +42,22 @@ func (b *Bus) Subscribe(eventName string, listener Listener) {
 	b.listeners[eventName] = append(b.listeners[eventName], listener)
 }
 
+// SubscribeAll подписывает слушателя на все события, например для зеркалирования во внешний брокер.
+func (b *Bus) SubscribeAll(listener Listener) {
+	b.Subscribe(AllEvents, listener)
+}
+
 // Publish публикует событие. Все подписчики будут вызваны.
 func (b *Bus) Publish(ctx context.Context, event Event) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
 	eventName := event.Name()
-	if listeners, ok := b.listeners[eventName]; ok {
+	listeners := b.listeners[eventName]
+	if all := b.listeners[AllEvents]; len(all) > 0 && eventName != AllEvents {
+		listeners = append(slices.Clip(listeners), all...)
+	}
+	if len(listeners) > 0 {
 		for _, listener := range listeners {
 			go func(l Listener) {
 				// Создаем контекст с таймаутом, чтобы избежать "вечных" горутин.
@@ -0,0 +1,150 @@
+// Package eventstream зеркалирует события внутренней шины во внешний брокер (Kafka или NATS JetStream),
+// чтобы аналитика и ESB банка получали жизненный цикл заявок без опроса REST API.
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"request-system/pkg/eventbus"
+)
+
+const (
+	DriverKafka = "kafka"
+	DriverNATS  = "nats"
+
+	publishTimeout = 10 * time.Second
+	retryBaseDelay = time.Second
+	retryMaxDelay  = time.Minute
+)
+
+// Publisher отправляет одно сообщение в топик (Kafka) или subject (NATS).
+// id используется брокером для дедупликации, key — для партиционирования.
+type Publisher interface {
+	Publish(ctx context.Context, topic, id, key string, data []byte) error
+	Close() error
+}
+
+// Describer — необязательный интерфейс события: стабильный id, ключ партиционирования
+// и тело, которое можно отдавать наружу (без внутренних сущностей вроде пользователя с паролем).
+type Describer interface {
+	StreamID() string
+	StreamKey() string
+	StreamPayload() interface{}
+}
+
+// Envelope — формат сообщения во внешнем брокере.
+type Envelope struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Key        string      `json:"key,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type message struct {
+	topic string
+	id    string
+	key   string
+	data  []byte
+}
+
+// Mirror подписывается на все события шины и по одному публикует их в брокер.
+// Сообщения копятся в ограниченной очереди: пока брокер недоступен, публикация повторяется,
+// а при переполнении очереди новые события отбрасываются с записью в лог.
+type Mirror struct {
+	publisher   Publisher
+	topicPrefix string
+	queue       chan message
+	logger      *zap.Logger
+}
+
+func NewMirror(publisher Publisher, topicPrefix string, bufferSize int, logger *zap.Logger) *Mirror {
+	if bufferSize <= 0 {
+		bufferSize = 1000
+	}
+	return &Mirror{
+		publisher:   publisher,
+		topicPrefix: topicPrefix,
+		queue:       make(chan message, bufferSize),
+		logger:      logger,
+	}
+}
+
+func (m *Mirror) Register(bus *eventbus.Bus) {
+	bus.SubscribeAll(m.handle)
+	m.logger.Info("Зеркалирование событий во внешний брокер включено", zap.String("topicPrefix", m.topicPrefix))
+}
+
+// Topic возвращает имя топика/subject для события: <prefix>.<имя события>.
+func (m *Mirror) Topic(eventName string) string {
+	if m.topicPrefix == "" {
+		return eventName
+	}
+	return m.topicPrefix + "." + eventName
+}
+
+func (m *Mirror) handle(ctx context.Context, event eventbus.Event) error {
+	envelope := Envelope{ID: uuid.NewString(), Name: event.Name(), OccurredAt: time.Now(), Data: event}
+	if d, ok := event.(Describer); ok {
+		envelope.ID = d.StreamID()
+		envelope.Key = d.StreamKey()
+		envelope.Data = d.StreamPayload()
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case m.queue <- message{topic: m.Topic(envelope.Name), id: envelope.ID, key: envelope.Key, data: data}:
+	default:
+		m.logger.Error("Очередь зеркалирования событий переполнена, событие отброшено",
+			zap.String("event", envelope.Name), zap.String("id", envelope.ID))
+	}
+	return nil
+}
+
+// Run публикует сообщения из очереди до отмены контекста, после чего закрывает соединение с брокером.
+func (m *Mirror) Run(ctx context.Context) {
+	defer func() {
+		if err := m.publisher.Close(); err != nil {
+			m.logger.Warn("Ошибка при закрытии соединения с брокером событий", zap.Error(err))
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-m.queue:
+			m.publishWithRetry(ctx, msg)
+		}
+	}
+}
+
+func (m *Mirror) publishWithRetry(ctx context.Context, msg message) {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		pubCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+		err := m.publisher.Publish(pubCtx, msg.topic, msg.id, msg.key, msg.data)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		m.logger.Warn("Не удалось опубликовать событие во внешний брокер, повтор",
+			zap.String("topic", msg.topic), zap.String("id", msg.id), zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
@@ -0,0 +1,39 @@
+package eventstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher создаёт публикатор в Kafka. Сообщения с одним ключом (ID заявки) попадают
+// в одну партицию, поэтому потребитель видит их в порядке публикации.
+func NewKafkaPublisher(brokers []string, autoCreateTopics bool) Publisher {
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: autoCreateTopics,
+			BatchTimeout:           50 * time.Millisecond,
+		},
+	}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, topic, id, key string, data []byte) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   topic,
+		Key:     []byte(key),
+		Value:   data,
+		Headers: []kafka.Header{{Key: "event-id", Value: []byte(id)}},
+	})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,51 @@
+package eventstream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type natsPublisher struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+// NewNATSPublisher подключается к NATS и создаёт (или обновляет) JetStream-стрим,
+// который хранит все subject'ы вида <subjectPrefix>.>.
+func NewNATSPublisher(ctx context.Context, url, streamName, subjectPrefix string) (Publisher, error) {
+	conn, err := nats.Connect(url, nats.Name("request-system"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("подключение к NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("инициализация JetStream: %w", err)
+	}
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamName,
+		Subjects: []string{subjectPrefix + ".>"},
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("создание стрима %s: %w", streamName, err)
+	}
+	return &natsPublisher{conn: conn, js: js}, nil
+}
+
+// Publish передаёт id как Nats-Msg-Id, поэтому повторная отправка того же события отбрасывается JetStream.
+func (p *natsPublisher) Publish(ctx context.Context, subject, id, key string, data []byte) error {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	if key != "" {
+		msg.Header.Set("Event-Key", key)
+	}
+	_, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(id))
+	return err
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,84 @@
+package filestorage
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+const jpegQuality = 85
+
+// ResizeToFit уменьшает изображение так, чтобы большая сторона не превышала maxSide.
+// Изображения меньше maxSide возвращаются как есть. Пиксели усредняются по площади —
+// для уменьшения аватаров этого достаточно и не нужны внешние библиотеки.
+func ResizeToFit(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if maxSide <= 0 || (w <= maxSide && h <= maxSide) {
+		return src
+	}
+
+	dw, dh := maxSide, max(1, h*maxSide/w)
+	if h > w {
+		dw, dh = max(1, w*maxSide/h), maxSide
+	}
+
+	rgba := toRGBA(src)
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0 := y * h / dh
+		sy1 := max((y+1)*h/dh, sy0+1)
+		for x := 0; x < dw; x++ {
+			sx0 := x * w / dw
+			sx1 := max((x+1)*w/dw, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				off := rgba.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(rgba.Pix[off])
+					g += uint64(rgba.Pix[off+1])
+					b += uint64(rgba.Pix[off+2])
+					a += uint64(rgba.Pix[off+3])
+					n++
+					off += 4
+				}
+			}
+
+			d := dst.PixOffset(x, y)
+			dst.Pix[d] = uint8(r / n)
+			dst.Pix[d+1] = uint8(g / n)
+			dst.Pix[d+2] = uint8(b / n)
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	return rgba
+}
+
+// EncodeImage кодирует изображение в PNG (сохраняет прозрачность) или JPEG
+// и возвращает расширение файла.
+func EncodeImage(img image.Image, asPNG bool) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if asPNG {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), ".png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), ".jpg", nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -16,9 +17,21 @@ import (
 // Интерфейс теперь требует `prefix`
 type FileStorageInterface interface {
 	Save(file io.Reader, originalFileName string, prefix string) (filePath string, err error)
+	// SaveVariant сохраняет производный файл (например, уменьшенную копию) рядом с filePath,
+	// который вернул Save. Путь строится через VariantPath.
+	SaveVariant(file io.Reader, filePath string, variant string) (variantPath string, err error)
+	// Open открывает файл по пути, который вернул Save.
+	Open(filePath string) (io.ReadCloser, error)
 	Delete(filePath string) error
 }
 
+// VariantPath: "avatars/2024/08/21/name.jpg" + "256" -> "avatars/2024/08/21/name_256.jpg".
+// Работает и для URL вида "/uploads/...".
+func VariantPath(filePath, variant string) string {
+	ext := path.Ext(filePath)
+	return strings.TrimSuffix(filePath, ext) + "_" + variant + ext
+}
+
 type LocalFileStorage struct {
 	basePath string
 }
@@ -56,6 +69,32 @@ func (s *LocalFileStorage) Save(file io.Reader, originalFileName string, prefix
 	return filepath.ToSlash(filepath.Join(prefix, datePath, uniqueFileName)), nil
 }
 
+func (s *LocalFileStorage) SaveVariant(file io.Reader, filePath string, variant string) (string, error) {
+	variantPath := VariantPath(filePath, variant)
+	if !filepath.IsLocal(filepath.FromSlash(variantPath)) {
+		return "", fmt.Errorf("недопустимый путь файла: %s", filePath)
+	}
+
+	dst, err := os.Create(filepath.Join(s.basePath, filepath.FromSlash(variantPath)))
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err = io.Copy(dst, file); err != nil {
+		return "", err
+	}
+	return variantPath, nil
+}
+
+func (s *LocalFileStorage) Open(filePath string) (io.ReadCloser, error) {
+	relativePath := filepath.FromSlash(strings.TrimPrefix(filePath, "/uploads/"))
+	if !filepath.IsLocal(relativePath) {
+		return nil, fmt.Errorf("недопустимый путь файла: %s", filePath)
+	}
+	return os.Open(filepath.Join(s.basePath, relativePath))
+}
+
 func (s *LocalFileStorage) Delete(fileURL string) error {
 	// fileURL приходит в виде "/uploads/prefix/2024/08/21/file.jpg"
 	// Нам нужно отсечь "/uploads/" чтобы получить путь относительно s.basePath,
@@ -0,0 +1,1089 @@
+// Контракт gRPC для внутренних сервисов банка: чтение заявок и пользователей.
+// Права и область видимости те же, что в HTTP API: вызов выполняется от имени пользователя,
+// чей JWT передан в метаданных authorization, или сервисного пользователя из GRPC_SERVICE_TOKENS.
+//
+// После правки файла пересоберите код: см. README, раздел про gRPC.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: requestsystem/v1/requestsystem.proto
+
+package requestsystemv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetOrderRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Страница с 1; по умолчанию 1.
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Размер страницы; по умолчанию и максимум — как в HTTP API.
+	Limit  int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Search string `protobuf:"bytes,3,opt,name=search,proto3" json:"search,omitempty"`
+	// Фильтры по полям, как filter[...] в HTTP API: ключ status_id соответствует filter[status_id].
+	Filters       map[string]string `protobuf:"bytes,4,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OnlyCreated   bool              `protobuf:"varint,5,opt,name=only_created,json=onlyCreated,proto3" json:"only_created,omitempty"`
+	OnlyAssigned  bool              `protobuf:"varint,6,opt,name=only_assigned,json=onlyAssigned,proto3" json:"only_assigned,omitempty"`
+	OnlyInvolved  bool              `protobuf:"varint,7,opt,name=only_involved,json=onlyInvolved,proto3" json:"only_involved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListOrdersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetOnlyCreated() bool {
+	if x != nil {
+		return x.OnlyCreated
+	}
+	return false
+}
+
+func (x *ListOrdersRequest) GetOnlyAssigned() bool {
+	if x != nil {
+		return x.OnlyAssigned
+	}
+	return false
+}
+
+func (x *ListOrdersRequest) GetOnlyInvolved() bool {
+	if x != nil {
+		return x.OnlyInvolved
+	}
+	return false
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	TotalCount    uint64                 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetTotalCount() uint64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type Order struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StatusId        uint64                 `protobuf:"varint,3,opt,name=status_id,json=statusId,proto3" json:"status_id,omitempty"`
+	PriorityId      *uint64                `protobuf:"varint,4,opt,name=priority_id,json=priorityId,proto3,oneof" json:"priority_id,omitempty"`
+	OrderTypeId     *uint64                `protobuf:"varint,5,opt,name=order_type_id,json=orderTypeId,proto3,oneof" json:"order_type_id,omitempty"`
+	Address         *string                `protobuf:"bytes,6,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	CreatorId       uint64                 `protobuf:"varint,7,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`
+	CreatorName     string                 `protobuf:"bytes,8,opt,name=creator_name,json=creatorName,proto3" json:"creator_name,omitempty"`
+	ExecutorId      *uint64                `protobuf:"varint,9,opt,name=executor_id,json=executorId,proto3,oneof" json:"executor_id,omitempty"`
+	ExecutorName    *string                `protobuf:"bytes,10,opt,name=executor_name,json=executorName,proto3,oneof" json:"executor_name,omitempty"`
+	DepartmentId    *uint64                `protobuf:"varint,11,opt,name=department_id,json=departmentId,proto3,oneof" json:"department_id,omitempty"`
+	OtdelId         *uint64                `protobuf:"varint,12,opt,name=otdel_id,json=otdelId,proto3,oneof" json:"otdel_id,omitempty"`
+	BranchId        *uint64                `protobuf:"varint,13,opt,name=branch_id,json=branchId,proto3,oneof" json:"branch_id,omitempty"`
+	OfficeId        *uint64                `protobuf:"varint,14,opt,name=office_id,json=officeId,proto3,oneof" json:"office_id,omitempty"`
+	EquipmentId     *uint64                `protobuf:"varint,15,opt,name=equipment_id,json=equipmentId,proto3,oneof" json:"equipment_id,omitempty"`
+	EquipmentTypeId *uint64                `protobuf:"varint,16,opt,name=equipment_type_id,json=equipmentTypeId,proto3,oneof" json:"equipment_type_id,omitempty"`
+	// Срок выполнения.
+	Duration                 *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=duration,proto3" json:"duration,omitempty"`
+	CreatedAt                *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt                *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CompletedAt              *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	ResolutionTimeSeconds    *uint64                `protobuf:"varint,21,opt,name=resolution_time_seconds,json=resolutionTimeSeconds,proto3,oneof" json:"resolution_time_seconds,omitempty"`
+	FirstResponseTimeSeconds *uint64                `protobuf:"varint,22,opt,name=first_response_time_seconds,json=firstResponseTimeSeconds,proto3,oneof" json:"first_response_time_seconds,omitempty"`
+	Attachments              []*Attachment          `protobuf:"bytes,23,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Order) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Order) GetStatusId() uint64 {
+	if x != nil {
+		return x.StatusId
+	}
+	return 0
+}
+
+func (x *Order) GetPriorityId() uint64 {
+	if x != nil && x.PriorityId != nil {
+		return *x.PriorityId
+	}
+	return 0
+}
+
+func (x *Order) GetOrderTypeId() uint64 {
+	if x != nil && x.OrderTypeId != nil {
+		return *x.OrderTypeId
+	}
+	return 0
+}
+
+func (x *Order) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+func (x *Order) GetCreatorId() uint64 {
+	if x != nil {
+		return x.CreatorId
+	}
+	return 0
+}
+
+func (x *Order) GetCreatorName() string {
+	if x != nil {
+		return x.CreatorName
+	}
+	return ""
+}
+
+func (x *Order) GetExecutorId() uint64 {
+	if x != nil && x.ExecutorId != nil {
+		return *x.ExecutorId
+	}
+	return 0
+}
+
+func (x *Order) GetExecutorName() string {
+	if x != nil && x.ExecutorName != nil {
+		return *x.ExecutorName
+	}
+	return ""
+}
+
+func (x *Order) GetDepartmentId() uint64 {
+	if x != nil && x.DepartmentId != nil {
+		return *x.DepartmentId
+	}
+	return 0
+}
+
+func (x *Order) GetOtdelId() uint64 {
+	if x != nil && x.OtdelId != nil {
+		return *x.OtdelId
+	}
+	return 0
+}
+
+func (x *Order) GetBranchId() uint64 {
+	if x != nil && x.BranchId != nil {
+		return *x.BranchId
+	}
+	return 0
+}
+
+func (x *Order) GetOfficeId() uint64 {
+	if x != nil && x.OfficeId != nil {
+		return *x.OfficeId
+	}
+	return 0
+}
+
+func (x *Order) GetEquipmentId() uint64 {
+	if x != nil && x.EquipmentId != nil {
+		return *x.EquipmentId
+	}
+	return 0
+}
+
+func (x *Order) GetEquipmentTypeId() uint64 {
+	if x != nil && x.EquipmentTypeId != nil {
+		return *x.EquipmentTypeId
+	}
+	return 0
+}
+
+func (x *Order) GetDuration() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Order) GetResolutionTimeSeconds() uint64 {
+	if x != nil && x.ResolutionTimeSeconds != nil {
+		return *x.ResolutionTimeSeconds
+	}
+	return 0
+}
+
+func (x *Order) GetFirstResponseTimeSeconds() uint64 {
+	if x != nil && x.FirstResponseTimeSeconds != nil {
+		return *x.FirstResponseTimeSeconds
+	}
+	return 0
+}
+
+func (x *Order) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Attachment) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Attachment) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Attachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUserRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Search        string                 `protobuf:"bytes,3,opt,name=search,proto3" json:"search,omitempty"`
+	Filters       map[string]string      `protobuf:"bytes,4,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	TotalCount    uint64                 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetTotalCount() uint64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type User struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Fio            string                 `protobuf:"bytes,2,opt,name=fio,proto3" json:"fio,omitempty"`
+	Email          string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	PhoneNumber    string                 `protobuf:"bytes,4,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Username       *string                `protobuf:"bytes,5,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	StatusId       uint64                 `protobuf:"varint,6,opt,name=status_id,json=statusId,proto3" json:"status_id,omitempty"`
+	StatusCode     string                 `protobuf:"bytes,7,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	PositionId     *uint64                `protobuf:"varint,8,opt,name=position_id,json=positionId,proto3,oneof" json:"position_id,omitempty"`
+	PositionName   *string                `protobuf:"bytes,9,opt,name=position_name,json=positionName,proto3,oneof" json:"position_name,omitempty"`
+	BranchId       *uint64                `protobuf:"varint,10,opt,name=branch_id,json=branchId,proto3,oneof" json:"branch_id,omitempty"`
+	BranchName     *string                `protobuf:"bytes,11,opt,name=branch_name,json=branchName,proto3,oneof" json:"branch_name,omitempty"`
+	DepartmentId   *uint64                `protobuf:"varint,12,opt,name=department_id,json=departmentId,proto3,oneof" json:"department_id,omitempty"`
+	DepartmentName *string                `protobuf:"bytes,13,opt,name=department_name,json=departmentName,proto3,oneof" json:"department_name,omitempty"`
+	OtdelId        *uint64                `protobuf:"varint,14,opt,name=otdel_id,json=otdelId,proto3,oneof" json:"otdel_id,omitempty"`
+	OtdelName      *string                `protobuf:"bytes,15,opt,name=otdel_name,json=otdelName,proto3,oneof" json:"otdel_name,omitempty"`
+	OfficeId       *uint64                `protobuf:"varint,16,opt,name=office_id,json=officeId,proto3,oneof" json:"office_id,omitempty"`
+	OfficeName     *string                `protobuf:"bytes,17,opt,name=office_name,json=officeName,proto3,oneof" json:"office_name,omitempty"`
+	RoleIds        []uint64               `protobuf:"varint,18,rep,packed,name=role_ids,json=roleIds,proto3" json:"role_ids,omitempty"`
+	IsHead         bool                   `protobuf:"varint,19,opt,name=is_head,json=isHead,proto3" json:"is_head,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_requestsystem_v1_requestsystem_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_requestsystem_v1_requestsystem_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *User) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetFio() string {
+	if x != nil {
+		return x.Fio
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *User) GetStatusId() uint64 {
+	if x != nil {
+		return x.StatusId
+	}
+	return 0
+}
+
+func (x *User) GetStatusCode() string {
+	if x != nil {
+		return x.StatusCode
+	}
+	return ""
+}
+
+func (x *User) GetPositionId() uint64 {
+	if x != nil && x.PositionId != nil {
+		return *x.PositionId
+	}
+	return 0
+}
+
+func (x *User) GetPositionName() string {
+	if x != nil && x.PositionName != nil {
+		return *x.PositionName
+	}
+	return ""
+}
+
+func (x *User) GetBranchId() uint64 {
+	if x != nil && x.BranchId != nil {
+		return *x.BranchId
+	}
+	return 0
+}
+
+func (x *User) GetBranchName() string {
+	if x != nil && x.BranchName != nil {
+		return *x.BranchName
+	}
+	return ""
+}
+
+func (x *User) GetDepartmentId() uint64 {
+	if x != nil && x.DepartmentId != nil {
+		return *x.DepartmentId
+	}
+	return 0
+}
+
+func (x *User) GetDepartmentName() string {
+	if x != nil && x.DepartmentName != nil {
+		return *x.DepartmentName
+	}
+	return ""
+}
+
+func (x *User) GetOtdelId() uint64 {
+	if x != nil && x.OtdelId != nil {
+		return *x.OtdelId
+	}
+	return 0
+}
+
+func (x *User) GetOtdelName() string {
+	if x != nil && x.OtdelName != nil {
+		return *x.OtdelName
+	}
+	return ""
+}
+
+func (x *User) GetOfficeId() uint64 {
+	if x != nil && x.OfficeId != nil {
+		return *x.OfficeId
+	}
+	return 0
+}
+
+func (x *User) GetOfficeName() string {
+	if x != nil && x.OfficeName != nil {
+		return *x.OfficeName
+	}
+	return ""
+}
+
+func (x *User) GetRoleIds() []uint64 {
+	if x != nil {
+		return x.RoleIds
+	}
+	return nil
+}
+
+func (x *User) GetIsHead() bool {
+	if x != nil {
+		return x.IsHead
+	}
+	return false
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_requestsystem_v1_requestsystem_proto protoreflect.FileDescriptor
+
+const file_requestsystem_v1_requestsystem_proto_rawDesc = "" +
+	"\n" +
+	"$requestsystem/v1/requestsystem.proto\x12\x10requestsystem.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"!\n" +
+	"\x0fGetOrderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xca\x02\n" +
+	"\x11ListOrdersRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06search\x18\x03 \x01(\tR\x06search\x12J\n" +
+	"\afilters\x18\x04 \x03(\v20.requestsystem.v1.ListOrdersRequest.FiltersEntryR\afilters\x12!\n" +
+	"\fonly_created\x18\x05 \x01(\bR\vonlyCreated\x12#\n" +
+	"\ronly_assigned\x18\x06 \x01(\bR\fonlyAssigned\x12#\n" +
+	"\ronly_involved\x18\a \x01(\bR\fonlyInvolved\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"f\n" +
+	"\x12ListOrdersResponse\x12/\n" +
+	"\x06orders\x18\x01 \x03(\v2\x17.requestsystem.v1.OrderR\x06orders\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x04R\n" +
+	"totalCount\"\xcb\t\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tstatus_id\x18\x03 \x01(\x04R\bstatusId\x12$\n" +
+	"\vpriority_id\x18\x04 \x01(\x04H\x00R\n" +
+	"priorityId\x88\x01\x01\x12'\n" +
+	"\rorder_type_id\x18\x05 \x01(\x04H\x01R\vorderTypeId\x88\x01\x01\x12\x1d\n" +
+	"\aaddress\x18\x06 \x01(\tH\x02R\aaddress\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"creator_id\x18\a \x01(\x04R\tcreatorId\x12!\n" +
+	"\fcreator_name\x18\b \x01(\tR\vcreatorName\x12$\n" +
+	"\vexecutor_id\x18\t \x01(\x04H\x03R\n" +
+	"executorId\x88\x01\x01\x12(\n" +
+	"\rexecutor_name\x18\n" +
+	" \x01(\tH\x04R\fexecutorName\x88\x01\x01\x12(\n" +
+	"\rdepartment_id\x18\v \x01(\x04H\x05R\fdepartmentId\x88\x01\x01\x12\x1e\n" +
+	"\botdel_id\x18\f \x01(\x04H\x06R\aotdelId\x88\x01\x01\x12 \n" +
+	"\tbranch_id\x18\r \x01(\x04H\aR\bbranchId\x88\x01\x01\x12 \n" +
+	"\toffice_id\x18\x0e \x01(\x04H\bR\bofficeId\x88\x01\x01\x12&\n" +
+	"\fequipment_id\x18\x0f \x01(\x04H\tR\vequipmentId\x88\x01\x01\x12/\n" +
+	"\x11equipment_type_id\x18\x10 \x01(\x04H\n" +
+	"R\x0fequipmentTypeId\x88\x01\x01\x126\n" +
+	"\bduration\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\bduration\x129\n" +
+	"\n" +
+	"created_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
+	"\fcompleted_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12;\n" +
+	"\x17resolution_time_seconds\x18\x15 \x01(\x04H\vR\x15resolutionTimeSeconds\x88\x01\x01\x12B\n" +
+	"\x1bfirst_response_time_seconds\x18\x16 \x01(\x04H\fR\x18firstResponseTimeSeconds\x88\x01\x01\x12>\n" +
+	"\vattachments\x18\x17 \x03(\v2\x1c.requestsystem.v1.AttachmentR\vattachmentsB\x0e\n" +
+	"\f_priority_idB\x10\n" +
+	"\x0e_order_type_idB\n" +
+	"\n" +
+	"\b_addressB\x0e\n" +
+	"\f_executor_idB\x10\n" +
+	"\x0e_executor_nameB\x10\n" +
+	"\x0e_department_idB\v\n" +
+	"\t_otdel_idB\f\n" +
+	"\n" +
+	"_branch_idB\f\n" +
+	"\n" +
+	"_office_idB\x0f\n" +
+	"\r_equipment_idB\x14\n" +
+	"\x12_equipment_type_idB\x1a\n" +
+	"\x18_resolution_time_secondsB\x1e\n" +
+	"\x1c_first_response_time_seconds\"K\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\" \n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xdb\x01\n" +
+	"\x10ListUsersRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06search\x18\x03 \x01(\tR\x06search\x12I\n" +
+	"\afilters\x18\x04 \x03(\v2/.requestsystem.v1.ListUsersRequest.FiltersEntryR\afilters\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"b\n" +
+	"\x11ListUsersResponse\x12,\n" +
+	"\x05users\x18\x01 \x03(\v2\x16.requestsystem.v1.UserR\x05users\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x04R\n" +
+	"totalCount\"\x93\a\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03fio\x18\x02 \x01(\tR\x03fio\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12!\n" +
+	"\fphone_number\x18\x04 \x01(\tR\vphoneNumber\x12\x1f\n" +
+	"\busername\x18\x05 \x01(\tH\x00R\busername\x88\x01\x01\x12\x1b\n" +
+	"\tstatus_id\x18\x06 \x01(\x04R\bstatusId\x12\x1f\n" +
+	"\vstatus_code\x18\a \x01(\tR\n" +
+	"statusCode\x12$\n" +
+	"\vposition_id\x18\b \x01(\x04H\x01R\n" +
+	"positionId\x88\x01\x01\x12(\n" +
+	"\rposition_name\x18\t \x01(\tH\x02R\fpositionName\x88\x01\x01\x12 \n" +
+	"\tbranch_id\x18\n" +
+	" \x01(\x04H\x03R\bbranchId\x88\x01\x01\x12$\n" +
+	"\vbranch_name\x18\v \x01(\tH\x04R\n" +
+	"branchName\x88\x01\x01\x12(\n" +
+	"\rdepartment_id\x18\f \x01(\x04H\x05R\fdepartmentId\x88\x01\x01\x12,\n" +
+	"\x0fdepartment_name\x18\r \x01(\tH\x06R\x0edepartmentName\x88\x01\x01\x12\x1e\n" +
+	"\botdel_id\x18\x0e \x01(\x04H\aR\aotdelId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"otdel_name\x18\x0f \x01(\tH\bR\totdelName\x88\x01\x01\x12 \n" +
+	"\toffice_id\x18\x10 \x01(\x04H\tR\bofficeId\x88\x01\x01\x12$\n" +
+	"\voffice_name\x18\x11 \x01(\tH\n" +
+	"R\n" +
+	"officeName\x88\x01\x01\x12\x19\n" +
+	"\brole_ids\x18\x12 \x03(\x04R\aroleIds\x12\x17\n" +
+	"\ais_head\x18\x13 \x01(\bR\x06isHead\x129\n" +
+	"\n" +
+	"created_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\v\n" +
+	"\t_usernameB\x0e\n" +
+	"\f_position_idB\x10\n" +
+	"\x0e_position_nameB\f\n" +
+	"\n" +
+	"_branch_idB\x0e\n" +
+	"\f_branch_nameB\x10\n" +
+	"\x0e_department_idB\x12\n" +
+	"\x10_department_nameB\v\n" +
+	"\t_otdel_idB\r\n" +
+	"\v_otdel_nameB\f\n" +
+	"\n" +
+	"_office_idB\x0e\n" +
+	"\f_office_name2\xaf\x01\n" +
+	"\fOrderService\x12F\n" +
+	"\bGetOrder\x12!.requestsystem.v1.GetOrderRequest\x1a\x17.requestsystem.v1.Order\x12W\n" +
+	"\n" +
+	"ListOrders\x12#.requestsystem.v1.ListOrdersRequest\x1a$.requestsystem.v1.ListOrdersResponse2\xa8\x01\n" +
+	"\vUserService\x12C\n" +
+	"\aGetUser\x12 .requestsystem.v1.GetUserRequest\x1a\x16.requestsystem.v1.User\x12T\n" +
+	"\tListUsers\x12\".requestsystem.v1.ListUsersRequest\x1a#.requestsystem.v1.ListUsersResponseB=Z;request-system/pkg/grpcapi/requestsystem/v1;requestsystemv1b\x06proto3"
+
+var (
+	file_requestsystem_v1_requestsystem_proto_rawDescOnce sync.Once
+	file_requestsystem_v1_requestsystem_proto_rawDescData []byte
+)
+
+func file_requestsystem_v1_requestsystem_proto_rawDescGZIP() []byte {
+	file_requestsystem_v1_requestsystem_proto_rawDescOnce.Do(func() {
+		file_requestsystem_v1_requestsystem_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_requestsystem_v1_requestsystem_proto_rawDesc), len(file_requestsystem_v1_requestsystem_proto_rawDesc)))
+	})
+	return file_requestsystem_v1_requestsystem_proto_rawDescData
+}
+
+var file_requestsystem_v1_requestsystem_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_requestsystem_v1_requestsystem_proto_goTypes = []any{
+	(*GetOrderRequest)(nil),       // 0: requestsystem.v1.GetOrderRequest
+	(*ListOrdersRequest)(nil),     // 1: requestsystem.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),    // 2: requestsystem.v1.ListOrdersResponse
+	(*Order)(nil),                 // 3: requestsystem.v1.Order
+	(*Attachment)(nil),            // 4: requestsystem.v1.Attachment
+	(*GetUserRequest)(nil),        // 5: requestsystem.v1.GetUserRequest
+	(*ListUsersRequest)(nil),      // 6: requestsystem.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),     // 7: requestsystem.v1.ListUsersResponse
+	(*User)(nil),                  // 8: requestsystem.v1.User
+	nil,                           // 9: requestsystem.v1.ListOrdersRequest.FiltersEntry
+	nil,                           // 10: requestsystem.v1.ListUsersRequest.FiltersEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_requestsystem_v1_requestsystem_proto_depIdxs = []int32{
+	9,  // 0: requestsystem.v1.ListOrdersRequest.filters:type_name -> requestsystem.v1.ListOrdersRequest.FiltersEntry
+	3,  // 1: requestsystem.v1.ListOrdersResponse.orders:type_name -> requestsystem.v1.Order
+	11, // 2: requestsystem.v1.Order.duration:type_name -> google.protobuf.Timestamp
+	11, // 3: requestsystem.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	11, // 4: requestsystem.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 5: requestsystem.v1.Order.completed_at:type_name -> google.protobuf.Timestamp
+	4,  // 6: requestsystem.v1.Order.attachments:type_name -> requestsystem.v1.Attachment
+	10, // 7: requestsystem.v1.ListUsersRequest.filters:type_name -> requestsystem.v1.ListUsersRequest.FiltersEntry
+	8,  // 8: requestsystem.v1.ListUsersResponse.users:type_name -> requestsystem.v1.User
+	11, // 9: requestsystem.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	11, // 10: requestsystem.v1.User.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 11: requestsystem.v1.OrderService.GetOrder:input_type -> requestsystem.v1.GetOrderRequest
+	1,  // 12: requestsystem.v1.OrderService.ListOrders:input_type -> requestsystem.v1.ListOrdersRequest
+	5,  // 13: requestsystem.v1.UserService.GetUser:input_type -> requestsystem.v1.GetUserRequest
+	6,  // 14: requestsystem.v1.UserService.ListUsers:input_type -> requestsystem.v1.ListUsersRequest
+	3,  // 15: requestsystem.v1.OrderService.GetOrder:output_type -> requestsystem.v1.Order
+	2,  // 16: requestsystem.v1.OrderService.ListOrders:output_type -> requestsystem.v1.ListOrdersResponse
+	8,  // 17: requestsystem.v1.UserService.GetUser:output_type -> requestsystem.v1.User
+	7,  // 18: requestsystem.v1.UserService.ListUsers:output_type -> requestsystem.v1.ListUsersResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_requestsystem_v1_requestsystem_proto_init() }
+func file_requestsystem_v1_requestsystem_proto_init() {
+	if File_requestsystem_v1_requestsystem_proto != nil {
+		return
+	}
+	file_requestsystem_v1_requestsystem_proto_msgTypes[3].OneofWrappers = []any{}
+	file_requestsystem_v1_requestsystem_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_requestsystem_v1_requestsystem_proto_rawDesc), len(file_requestsystem_v1_requestsystem_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_requestsystem_v1_requestsystem_proto_goTypes,
+		DependencyIndexes: file_requestsystem_v1_requestsystem_proto_depIdxs,
+		MessageInfos:      file_requestsystem_v1_requestsystem_proto_msgTypes,
+	}.Build()
+	File_requestsystem_v1_requestsystem_proto = out.File
+	file_requestsystem_v1_requestsystem_proto_goTypes = nil
+	file_requestsystem_v1_requestsystem_proto_depIdxs = nil
+}
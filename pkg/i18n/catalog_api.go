@@ -0,0 +1,189 @@
+package i18n
+
+// Строки HTTP API: ошибки валидации, названия полей и веб-уведомления.
+// Добавляются в общий каталог, поэтому ключи не должны совпадать с ключами catalog_telegram.go.
+var apiCatalog = map[string]map[string]string{
+	// --- Ошибки валидации (%s — название поля, второй %s — параметр правила) ---
+	"validation.required":         {LangRU: "поле '%s' обязательно для заполнения", LangTG: "майдони '%s' бояд пур карда шавад", LangEN: "field '%s' is required"},
+	"validation.min":              {LangRU: "поле '%s' должно содержать минимум %s символов", LangTG: "майдони '%s' бояд на камтар аз %s аломат дошта бошад", LangEN: "field '%s' must be at least %s characters long"},
+	"validation.max":              {LangRU: "поле '%s' должно содержать максимум %s символов", LangTG: "майдони '%s' бояд на зиёда аз %s аломат дошта бошад", LangEN: "field '%s' must be at most %s characters long"},
+	"validation.len":              {LangRU: "поле '%s' должно содержать ровно %s символов", LangTG: "майдони '%s' бояд маҳз %s аломат дошта бошад", LangEN: "field '%s' must be exactly %s characters long"},
+	"validation.email":            {LangRU: "поле '%s' должно содержать корректный email", LangTG: "майдони '%s' бояд email-и дуруст дошта бошад", LangEN: "field '%s' must be a valid email"},
+	"validation.e164_TJ":          {LangRU: "поле '%s' должно быть в формате +992XXXXXXXXX", LangTG: "майдони '%s' бояд дар шакли +992XXXXXXXXX бошад", LangEN: "field '%s' must be in the +992XXXXXXXXX format"},
+	"validation.duration_format":  {LangRU: "поле '%s' должно быть в формате '2h30m'", LangTG: "майдони '%s' бояд дар шакли '2h30m' бошад", LangEN: "field '%s' must be in the '2h30m' format"},
+	"validation.address_logic":    {LangRU: "укажите адрес или выберите подразделение", LangTG: "суроғаро нависед ё воҳидро интихоб кунед", LangEN: "enter an address or choose a unit"},
+	"validation.uppercase":        {LangRU: "поле '%s' должно быть в верхнем регистре", LangTG: "майдони '%s' бояд бо ҳарфҳои калон бошад", LangEN: "field '%s' must be upper case"},
+	"validation.numeric":          {LangRU: "поле '%s' должно содержать только цифры", LangTG: "майдони '%s' бояд танҳо рақам дошта бошад", LangEN: "field '%s' must contain digits only"},
+	"validation.gt":               {LangRU: "поле '%s' должно быть больше %s", LangTG: "майдони '%s' бояд аз %s зиёд бошад", LangEN: "field '%s' must be greater than %s"},
+	"validation.gte":              {LangRU: "поле '%s' должно быть не менее %s", LangTG: "майдони '%s' бояд на камтар аз %s бошад", LangEN: "field '%s' must be at least %s"},
+	"validation.lt":               {LangRU: "поле '%s' должно быть меньше %s", LangTG: "майдони '%s' бояд аз %s кам бошад", LangEN: "field '%s' must be less than %s"},
+	"validation.lte":              {LangRU: "поле '%s' должно быть не более %s", LangTG: "майдони '%s' бояд на зиёда аз %s бошад", LangEN: "field '%s' must be at most %s"},
+	"validation.datetime":         {LangRU: "поле '%s' должно быть в формате дата (например: 2024-01-31)", LangTG: "майдони '%s' бояд сана бошад (масалан: 2024-01-31)", LangEN: "field '%s' must be a date (for example 2024-01-31)"},
+	"validation.required_without": {LangRU: "поле '%s' обязательно если не указано другое подразделение", LangTG: "агар воҳиди дигар нишон дода нашуда бошад, майдони '%s' ҳатмист", LangEN: "field '%s' is required when no other unit is given"},
+	"validation.oneof":            {LangRU: "поле '%s' содержит недопустимое значение", LangTG: "майдони '%s' қимати номувофиқ дорад", LangEN: "field '%s' has an invalid value"},
+	"validation.url":              {LangRU: "поле '%s' должно содержать корректный URL", LangTG: "майдони '%s' бояд URL-и дуруст дошта бошад", LangEN: "field '%s' must be a valid URL"},
+	"validation.invalid":          {LangRU: "поле '%s' не прошло проверку", LangTG: "майдони '%s' аз санҷиш нагузашт", LangEN: "field '%s' is invalid"},
+
+	// --- Ошибки строки поиска заявок (pkg/orderquery) ---
+	"query.too_long":         {LangRU: "строка поиска длиннее %d символов", LangTG: "сатри ҷустуҷӯ аз %d аломат дарозтар аст", LangEN: "the search query is longer than %d characters"},
+	"query.unclosed_quote":   {LangRU: "в строке поиска не закрыта кавычка", LangTG: "дар сатри ҷустуҷӯ нохунак баста нашудааст", LangEN: "the search query has an unclosed quote"},
+	"query.unknown_field":    {LangRU: "неизвестное поле '%s', доступны: %s", LangTG: "майдони номаълум '%s', дастрасанд: %s", LangEN: "unknown field '%s', available: %s"},
+	"query.empty_value":      {LangRU: "для поля '%s' не указано значение", LangTG: "барои майдони '%s' қимат нишон дода нашудааст", LangEN: "no value given for field '%s'"},
+	"query.bad_operator":     {LangRU: "оператор '%s' нельзя использовать с полем '%s'", LangTG: "амалгари '%s'-ро бо майдони '%s' истифода бурдан мумкин нест", LangEN: "operator '%s' cannot be used with field '%s'"},
+	"query.bad_date":         {LangRU: "'%s' в поле '%s' — не дата в формате ГГГГ-ММ-ДД", LangTG: "'%s' дар майдони '%s' санаи шакли СССС-ММ-РР нест", LangEN: "'%s' in field '%s' is not a YYYY-MM-DD date"},
+	"query.bad_range":        {LangRU: "в периоде '%s' поля '%s' начало позже конца", LangTG: "дар давраи '%s'-и майдони '%s' оғоз аз анҷом дертар аст", LangEN: "period '%s' in field '%s' starts after it ends"},
+	"query.bad_id":           {LangRU: "'%s' в поле '%s' — не ID", LangTG: "'%s' дар майдони '%s' ID нест", LangEN: "'%s' in field '%s' is not an ID"},
+	"query.bad_user":         {LangRU: "'%s' в поле '%s': укажите me, none или ID пользователя", LangTG: "'%s' дар майдони '%s': me, none ё ID-и корбарро нишон диҳед", LangEN: "'%s' in field '%s': use me, none or a user ID"},
+	"query.bad_bool":         {LangRU: "поле '%s' принимает только true или false", LangTG: "майдони '%s' танҳо true ё false қабул мекунад", LangEN: "field '%s' accepts only true or false"},
+	"query.unknown_status":   {LangRU: "статус с кодом '%s' не найден", LangTG: "ҳолат бо рамзи '%s' ёфт нашуд", LangEN: "status with code '%s' not found"},
+	"query.unknown_priority": {LangRU: "приоритет с кодом '%s' не найден", LangTG: "афзалият бо рамзи '%s' ёфт нашуд", LangEN: "priority with code '%s' not found"},
+	"query.unknown_type":     {LangRU: "тип заявки с кодом '%s' не найден", LangTG: "навъи дархост бо рамзи '%s' ёфт нашуд", LangEN: "request type with code '%s' not found"},
+
+	// --- Названия полей для ошибок валидации ---
+	"field.Fio":             {LangRU: "ФИО", LangTG: "Ному насаб", LangEN: "Full name"},
+	"field.Email":           {LangRU: "Email", LangTG: "Email", LangEN: "Email"},
+	"field.PhoneNumber":     {LangRU: "Номер телефона", LangTG: "Рақами телефон", LangEN: "Phone number"},
+	"field.Password":        {LangRU: "Пароль", LangTG: "Парол", LangEN: "Password"},
+	"field.NewPassword":     {LangRU: "Новый пароль", LangTG: "Пароли нав", LangEN: "New password"},
+	"field.Login":           {LangRU: "Логин", LangTG: "Логин", LangEN: "Login"},
+	"field.Username":        {LangRU: "Имя пользователя", LangTG: "Номи корбар", LangEN: "Username"},
+	"field.Token":           {LangRU: "Токен", LangTG: "Токен", LangEN: "Token"},
+	"field.Code":            {LangRU: "Код", LangTG: "Рамз", LangEN: "Code"},
+	"field.RoleIDs":         {LangRU: "Роли", LangTG: "Нақшҳо", LangEN: "Roles"},
+	"field.PositionID":      {LangRU: "Должность", LangTG: "Вазифа", LangEN: "Position"},
+	"field.StatusID":        {LangRU: "Статус", LangTG: "Ҳолат", LangEN: "Status"},
+	"field.BranchID":        {LangRU: "Филиал", LangTG: "Филиал", LangEN: "Branch"},
+	"field.DepartmentID":    {LangRU: "Департамент", LangTG: "Департамент", LangEN: "Department"},
+	"field.OtdelID":         {LangRU: "Отдел", LangTG: "Шуъба", LangEN: "Division"},
+	"field.OfficeID":        {LangRU: "Офис", LangTG: "Дафтар", LangEN: "Office"},
+	"field.Name":            {LangRU: "Название", LangTG: "Ном", LangEN: "Name"},
+	"field.Address":         {LangRU: "Адрес", LangTG: "Суроға", LangEN: "Address"},
+	"field.Comment":         {LangRU: "Комментарий", LangTG: "Шарҳ", LangEN: "Comment"},
+	"field.OrderTypeID":     {LangRU: "Тип заявки", LangTG: "Навъи дархост", LangEN: "Request type"},
+	"field.PriorityID":      {LangRU: "Приоритет", LangTG: "Афзалият", LangEN: "Priority"},
+	"field.ExecutorID":      {LangRU: "Исполнитель", LangTG: "Иҷрокунанда", LangEN: "Executor"},
+	"field.EquipmentID":     {LangRU: "Оборудование", LangTG: "Таҷҳизот", LangEN: "Equipment"},
+	"field.EquipmentTypeID": {LangRU: "Тип оборудования", LangTG: "Навъи таҷҳизот", LangEN: "Equipment type"},
+	"field.Duration":        {LangRU: "Срок выполнения", LangTG: "Мӯҳлати иҷро", LangEN: "Deadline"},
+	"field.OrderID":         {LangRU: "Заявка", LangTG: "Дархост", LangEN: "Request"},
+	"field.UserID":          {LangRU: "Пользователь", LangTG: "Корбар", LangEN: "User"},
+	"field.EventType":       {LangRU: "Тип события", LangTG: "Навъи рӯйдод", LangEN: "Event type"},
+	"field.Type":            {LangRU: "Тип", LangTG: "Навъ", LangEN: "Type"},
+	"field.Description":     {LangRU: "Описание", LangTG: "Тавсиф", LangEN: "Description"},
+	"field.Rate":            {LangRU: "Рейтинг", LangTG: "Рейтинг", LangEN: "Rating"},
+	"field.OpenDate":        {LangRU: "Дата открытия", LangTG: "Санаи кушодашавӣ", LangEN: "Opening date"},
+	"field.Path":            {LangRU: "Путь", LangTG: "Роҳ", LangEN: "Path"},
+	"field.FileName":        {LangRU: "Имя файла", LangTG: "Номи файл", LangEN: "File name"},
+	"field.FilePath":        {LangRU: "Путь к файлу", LangTG: "Роҳи файл", LangEN: "File path"},
+	"field.FileType":        {LangRU: "Тип файла", LangTG: "Навъи файл", LangEN: "File type"},
+	"field.Message":         {LangRU: "Сообщение", LangTG: "Паём", LangEN: "Message"},
+	"field.RuleID":          {LangRU: "Правило", LangTG: "Қоида", LangEN: "Rule"},
+	"field.RuleName":        {LangRU: "Название правила", LangTG: "Номи қоида", LangEN: "Rule name"},
+	"field.PositionType":    {LangRU: "Тип должности", LangTG: "Навъи вазифа", LangEN: "Position type"},
+	"field.PermissionID":    {LangRU: "Право", LangTG: "Ҳуқуқ", LangEN: "Permission"},
+	"field.PermissionIDs":   {LangRU: "Права", LangTG: "Ҳуқуқҳо", LangEN: "Permissions"},
+	"field.RoleID":          {LangRU: "Роль", LangTG: "Нақш", LangEN: "Role"},
+	"field.ParentID":        {LangRU: "Родительский элемент", LangTG: "Унсури волидайн", LangEN: "Parent item"},
+	"field.DepartmentsID":   {LangRU: "Департамент", LangTG: "Департамент", LangEN: "Department"},
+	"field.Language":        {LangRU: "Язык", LangTG: "Забон", LangEN: "Language"},
+	"field.Timezone":        {LangRU: "Часовой пояс", LangTG: "Минтақаи вақт", LangEN: "Time zone"},
+
+	// --- Веб-уведомления (HTML для колокольчика) ---
+	"notify.ws.created":         {LangRU: "<strong>%s</strong> создал(а) новую заявку <strong>%s №%s</strong>", LangTG: "<strong>%s</strong> дархости нави <strong>%s №%s</strong> эҷод кард", LangEN: "<strong>%s</strong> created a new request <strong>%s %s</strong>"},
+	"notify.ws.updated":         {LangRU: "<strong>%s</strong> обновил(а) заявку <strong>%s №%s</strong>", LangTG: "<strong>%s</strong> дархости <strong>%s №%s</strong>-ро навсозӣ кард", LangEN: "<strong>%s</strong> updated request <strong>%s %s</strong>"},
+	"notify.ws.field":           {LangRU: "%s: <strong>%s</strong>", LangTG: "%s: <strong>%s</strong>", LangEN: "%s: <strong>%s</strong>"},
+	"notify.ws.comment":         {LangRU: "Комментарий: \"%s\"", LangTG: "Шарҳ: \"%s\"", LangEN: "Comment: \"%s\""},
+	"notify.ws.assigned_to_you": {LangRU: "Заявка назначена на <strong>Вас</strong>", LangTG: "Дархост ба <strong>Шумо</strong> супорида шуд", LangEN: "The request is assigned to <strong>you</strong>"},
+	"notify.ws.attachment":      {LangRU: "Прикреплен файл: %s", LangTG: "Файл замима шуд: %s", LangEN: "File attached: %s"},
+
+	// --- Эскалация повторных нарушений срока ---
+	"notify.ws.escalation":       {LangRU: "<strong>%[1]s</strong> нарушил(а) срок заявок %[2]d раз за %[3]d ч", LangTG: "<strong>%[1]s</strong> дар %[3]d соат %[2]d маротиба мӯҳлати дархостҳоро вайрон кард", LangEN: "<strong>%[1]s</strong> missed request deadlines %[2]d times in %[3]d h"},
+	"notify.ws.escalation_order": {LangRU: "№%s %s — просрочка %s", LangTG: "№%s %s — таъхир %s", LangEN: "%s %s — overdue by %s"},
+
+	// --- Напоминание о первом отклике ---
+	"notify.ws.first_response_reminder": {LangRU: "Заявка №%s %s ждёт вашего первого отклика: осталось %s", LangTG: "Дархости №%s %s вокуниши аввали шуморо интизор аст: %s монд", LangEN: "Request %s %s is waiting for your first response: %s left"},
+	"notify.ws.first_response_overdue":  {LangRU: "Срок первого отклика по заявке №%s %s истёк %s назад", LangTG: "Мӯҳлати вокуниши аввал барои дархости №%s %s %s пеш гузашт", LangEN: "The first response deadline for request %s %s passed %s ago"},
+}
+
+// messages — переводы готовых русских текстов ответов API (см. Message). Русский текст
+// здесь должен совпадать с текстом в коде символ в символ, иначе он уйдёт без перевода.
+var messages = map[string]map[string]string{
+	// --- apperrors ---
+	"Неверный запрос":                                                    {LangTG: "Дархости нодуруст", LangEN: "Bad request"},
+	"Ошибка валидации данных":                                            {LangTG: "Хатои санҷиши маълумот", LangEN: "Data validation error"},
+	"Необходима авторизация":                                             {LangTG: "Ворид шудан лозим аст", LangEN: "Authorization required"},
+	"Доступ запрещен":                                                    {LangTG: "Дастрасӣ манъ аст", LangEN: "Access denied"},
+	"Запрашиваемый ресурс не найден":                                     {LangTG: "Манбаи дархостшуда ёфт нашуд", LangEN: "The requested resource was not found"},
+	"Внутренняя ошибка сервера":                                          {LangTG: "Хатои дохилии сервер", LangEN: "Internal server error"},
+	"Недействительный токен":                                             {LangTG: "Токени нодуруст", LangEN: "Invalid token"},
+	"Срок действия токена истек":                                         {LangTG: "Мӯҳлати амали токен гузаштааст", LangEN: "The token has expired"},
+	"Недействительный метод подписи токена":                              {LangTG: "Усули имзои токен нодуруст аст", LangEN: "Invalid token signing method"},
+	"Ресурс уже существует":                                              {LangTG: "Чунин манбаъ аллакай мавҷуд аст", LangEN: "The resource already exists"},
+	"Пользователь не найден":                                             {LangTG: "Корбар ёфт нашуд", LangEN: "User not found"},
+	"Приоритет используется и не может быть удалён":                      {LangTG: "Афзалият истифода мешавад ва наметавонад нест карда шавад", LangEN: "The priority is in use and cannot be deleted"},
+	"Этот приоритет используется системой и не может быть удалён":        {LangTG: "Ин афзалиятро система истифода мебарад ва онро нест кардан мумкин нест", LangEN: "This priority is used by the system and cannot be deleted"},
+	"Код этого приоритета используется системой и не может быть изменён": {LangTG: "Рамзи ин афзалиятро система истифода мебарад ва онро тағйир додан мумкин нест", LangEN: "This priority's code is used by the system and cannot be changed"},
+	"Цвет приоритета должен быть в формате #RRGGBB.":                     {LangTG: "Ранги афзалият бояд дар формати #RRGGBB бошад.", LangEN: "The priority color must be in #RRGGBB format."},
+	"Статус используется и не может быть удалён":                         {LangTG: "Ҳолат истифода мешавад ва наметавонад нест карда шавад", LangEN: "The status is in use and cannot be deleted"},
+	"Неверные учетные данные":                                            {LangTG: "Маълумоти воридшавӣ нодуруст аст", LangEN: "Invalid credentials"},
+	"Аккаунт заблокирован":                                               {LangTG: "Ҳисоб баста шудааст", LangEN: "The account is locked"},
+	"Аккаунт неактивен":                                                  {LangTG: "Ҳисоб ғайрифаъол аст", LangEN: "The account is inactive"},
+	"Токен не является access токеном":                                   {LangTG: "Токен access-токен нест", LangEN: "The token is not an access token"},
+	"Недействительный заголовок авторизации":                             {LangTG: "Сарлавҳаи авторизатсия нодуруст аст", LangEN: "Invalid authorization header"},
+	"Отсутствует заголовок авторизации":                                  {LangTG: "Сарлавҳаи авторизатсия мавҷуд нест", LangEN: "Authorization header is missing"},
+	"Слишком много запросов, повторите позже":                            {LangTG: "Дархостҳо аз ҳад зиёданд, баъдтар такрор кунед", LangEN: "Too many requests, try again later"},
+	"Требуется смена пароля":                                             {LangTG: "Иваз кардани парол лозим аст", LangEN: "Password change required"},
+	"Нет изменений в запросе":                                            {LangTG: "Дар дархост тағйирот нест", LangEN: "The request contains no changes"},
+
+	// --- Ошибки базы данных ---
+	"Не заполнено обязательное поле.":                            {LangTG: "Майдони ҳатмӣ пур карда нашудааст.", LangEN: "A required field is empty."},
+	"Одно из полей содержит слишком длинное значение.":           {LangTG: "Яке аз майдонҳо қимати аз ҳад дароз дорад.", LangEN: "One of the fields is too long."},
+	"Один из выбранных элементов был удалён. Обновите страницу.": {LangTG: "Яке аз унсурҳои интихобшуда нест карда шудааст. Саҳифаро навсозӣ кунед.", LangEN: "One of the selected items was deleted. Refresh the page."},
+	"Такая запись уже существует.":                               {LangTG: "Чунин сабт аллакай мавҷуд аст.", LangEN: "Such a record already exists."},
+	"Ошибка соединения с базой данных. Попробуйте позже.":        {LangTG: "Хатои пайвастшавӣ ба пойгоҳи додаҳо. Баъдтар кӯшиш кунед.", LangEN: "Database connection error. Try again later."},
+	"Пользователь с таким email уже существует.":                 {LangTG: "Корбар бо чунин email аллакай мавҷуд аст.", LangEN: "A user with this email already exists."},
+	"Пользователь с таким номером телефона уже существует.":      {LangTG: "Корбар бо чунин рақами телефон аллакай мавҷуд аст.", LangEN: "A user with this phone number already exists."},
+
+	// --- Частые ошибки контроллеров ---
+	"Неверные данные":                           {LangTG: "Маълумоти нодуруст", LangEN: "Invalid data"},
+	"Неверные данные в теле запроса":            {LangTG: "Маълумоти нодуруст дар дархост", LangEN: "Invalid data in the request body"},
+	"Неверный формат ID":                        {LangTG: "Шакли ID нодуруст аст", LangEN: "Invalid ID format"},
+	"Неверный ID":                               {LangTG: "ID нодуруст", LangEN: "Invalid ID"},
+	"Неверный ID в URL":                         {LangTG: "ID дар URL нодуруст аст", LangEN: "Invalid ID in the URL"},
+	"Неверный формат ID пользователя":           {LangTG: "Шакли ID-и корбар нодуруст аст", LangEN: "Invalid user ID format"},
+	"Неверный формат ID заявки":                 {LangTG: "Шакли ID-и дархост нодуруст аст", LangEN: "Invalid request ID format"},
+	"Неверный формат запроса":                   {LangTG: "Шакли дархост нодуруст аст", LangEN: "Invalid request format"},
+	"Неверный формат JSON":                      {LangTG: "Шакли JSON нодуруст аст", LangEN: "Invalid JSON format"},
+	"Некорректный JSON":                         {LangTG: "JSON-и нодуруст", LangEN: "Invalid JSON"},
+	"Некорректный JSON в теле запроса":          {LangTG: "JSON-и нодуруст дар дархост", LangEN: "Invalid JSON in the request body"},
+	"Неверный JSON в 'data'":                    {LangTG: "JSON-и нодуруст дар 'data'", LangEN: "Invalid JSON in 'data'"},
+	"Неверный формат даты":                      {LangTG: "Шакли сана нодуруст аст", LangEN: "Invalid date format"},
+	"Неверный формат date_from":                 {LangTG: "Шакли date_from нодуруст аст", LangEN: "Invalid date_from format"},
+	"Неверный формат date_to":                   {LangTG: "Шакли date_to нодуруст аст", LangEN: "Invalid date_to format"},
+	"Файл не прошел валидацию":                  {LangTG: "Файл аз санҷиш нагузашт", LangEN: "The file failed validation"},
+	"Ошибка чтения тела запроса":                {LangTG: "Хатои хондани дархост", LangEN: "Failed to read the request body"},
+	"Не удалось прочитать тело запроса":         {LangTG: "Хондани дархост муяссар нашуд", LangEN: "Could not read the request body"},
+	"Заявка закрыта. Редактирование запрещено.": {LangTG: "Дархост баста шудааст. Таҳрир манъ аст.", LangEN: "The request is closed. Editing is not allowed."},
+	"Неизвестный часовой пояс":                  {LangTG: "Минтақаи вақти номаълум", LangEN: "Unknown time zone"},
+	"Неподдерживаемый язык":                     {LangTG: "Забони дастгиринашаванда", LangEN: "Unsupported language"},
+	"Название заявки не может быть пустым.":     {LangTG: "Номи дархост холӣ буда наметавонад.", LangEN: "The request name cannot be empty."},
+	"Этот тип заявки недоступен для создания.":  {LangTG: "Ин навъи дархост барои эҷод дастрас нест.", LangEN: "This request type is not available for new requests."},
+
+	// --- Частые сообщения об успехе ---
+	"Успешно":                       {LangTG: "Бомуваффақият", LangEN: "Success"},
+	"Успешно создан":                {LangTG: "Бомуваффақият эҷод шуд", LangEN: "Created successfully"},
+	"Успешно обновлен":              {LangTG: "Бомуваффақият навсозӣ шуд", LangEN: "Updated successfully"},
+	"Успешно удален":                {LangTG: "Бомуваффақият нест карда шуд", LangEN: "Deleted successfully"},
+	"Запрос принят в обработку":     {LangTG: "Дархост барои коркард қабул шуд", LangEN: "The request has been accepted for processing"},
+	"Список заявок успешно получен": {LangTG: "Рӯйхати дархостҳо гирифта шуд", LangEN: "Requests loaded"},
+	"Язык интерфейса получен":       {LangTG: "Забони интерфейс гирифта шуд", LangEN: "Interface language loaded"},
+	"Часовой пояс получен":          {LangTG: "Минтақаи вақт гирифта шуд", LangEN: "Time zone loaded"},
+	"Часовой пояс сохранён":         {LangTG: "Минтақаи вақт нигоҳ дошта шуд", LangEN: "Time zone saved"},
+	"Язык интерфейса сохранён":      {LangTG: "Забони интерфейс нигоҳ дошта шуд", LangEN: "Interface language saved"},
+}
+
+func init() {
+	for key, translations := range apiCatalog {
+		catalog[key] = translations
+	}
+}
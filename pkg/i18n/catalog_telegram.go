@@ -0,0 +1,317 @@
+package i18n
+
+// Строки Telegram-бота. Тексты, которые уходят с parse_mode=MarkdownV2,
+// хранятся уже экранированными; подставляемые через %s значения экранирует вызывающий код.
+var catalog = map[string]map[string]string{
+	// --- Кнопки главного меню ---
+	"tg.btn.all_orders": {LangRU: "📚 Все заявки", LangTG: "📚 Ҳамаи дархостҳо", LangEN: "📚 All requests"},
+	"tg.btn.my_tasks":   {LangRU: "📋 Мои заявки", LangTG: "📋 Дархостҳои ман", LangEN: "📋 My requests"},
+	"tg.btn.assigned":   {LangRU: "👨‍💼 Назначены мне", LangTG: "👨‍💼 Ба ман таъиншуда", LangEN: "👨‍💼 Assigned to me"},
+	"tg.btn.involved":   {LangRU: "🗂 Участвовал", LangTG: "🗂 Иштирок кардам", LangEN: "🗂 Involved"},
+	"tg.btn.today":      {LangRU: "⏰ На сегодня", LangTG: "⏰ Барои имрӯз", LangEN: "⏰ Today"},
+	"tg.btn.overdue":    {LangRU: "🔴 Просроченные", LangTG: "🔴 Мӯҳлаташ гузашта", LangEN: "🔴 Overdue"},
+	"tg.btn.search":     {LangRU: "🔍 Поиск", LangTG: "🔍 Ҷустуҷӯ", LangEN: "🔍 Search"},
+	"tg.btn.stats":      {LangRU: "📊 Статистика", LangTG: "📊 Омор", LangEN: "📊 Statistics"},
+	"tg.btn.status":     {LangRU: "🔐 Статус", LangTG: "🔐 Ҳолат", LangEN: "🔐 Link status"},
+	"tg.btn.help":       {LangRU: "📖 Справка", LangTG: "📖 Маълумотнома", LangEN: "📖 Help"},
+	"tg.btn.language":   {LangRU: "🌐 Язык", LangTG: "🌐 Забон", LangEN: "🌐 Language"},
+	"tg.btn.back":       {LangRU: "◀️ Назад", LangTG: "◀️ Бозгашт", LangEN: "◀️ Back"},
+	"tg.btn.to_list":    {LangRU: "◀️ К списку", LangTG: "◀️ Ба рӯйхат", LangEN: "◀️ To list"},
+
+	"tg.btn.main_menu": {LangRU: "🏠 Главное меню", LangTG: "🏠 Менюи асосӣ", LangEN: "🏠 Main menu"},
+
+	// --- Кнопки главного меню, зависящие от прав ---
+	"tg.btn.new_order":   {LangRU: "➕ Новая заявка", LangTG: "➕ Дархости нав", LangEN: "➕ New request"},
+	"tg.btn.unit_report": {LangRU: "📊 Отчет по отделу", LangTG: "📊 Ҳисобот оид ба шуъба", LangEN: "📊 Department report"},
+
+	// --- Кнопки карточки заявки ---
+	"tg.btn.edit_status":   {LangRU: "🔄 Статус", LangTG: "🔄 Ҳолат", LangEN: "🔄 Status"},
+	"tg.btn.edit_duration": {LangRU: "⏰ Срок", LangTG: "⏰ Мӯҳлат", LangEN: "⏰ Deadline"},
+	"tg.btn.edit_comment":  {LangRU: "💬 Комментарий", LangTG: "💬 Шарҳ", LangEN: "💬 Comment"},
+	"tg.btn.edit_delegate": {LangRU: "👤 Делегировать", LangTG: "👤 Супоридан", LangEN: "👤 Delegate"},
+	"tg.btn.save":          {LangRU: "✅ Сохранить", LangTG: "✅ Нигоҳ доштан", LangEN: "✅ Save"},
+
+	// --- Сканирование наклейки оборудования ---
+	"tg.btn.create_order":            {LangRU: "📝 Создать заявку", LangTG: "📝 Эҷоди дархост", LangEN: "📝 Create request"},
+	"tg.equipment.state.in_service":  {LangRU: "🟢 В работе", LangTG: "🟢 Дар кор", LangEN: "🟢 In service"},
+	"tg.equipment.state.repair":      {LangRU: "🛠 В ремонте", LangTG: "🛠 Дар таъмир", LangEN: "🛠 In repair"},
+	"tg.equipment.state.written_off": {LangRU: "⛔️ Списано", LangTG: "⛔️ Аз ҳисоб бароварда шуд", LangEN: "⛔️ Written off"},
+	"tg.equipment.serial":            {LangRU: "Серийный номер: %s", LangTG: "Рақами силсилавӣ: %s", LangEN: "Serial number: %s"},
+	"tg.equipment.open_orders": {
+		LangRU: "*Открытые заявки \\(%d\\):*",
+		LangTG: "*Дархостҳои кушода \\(%d\\):*",
+		LangEN: "*Open requests \\(%d\\):*",
+	},
+	"tg.equipment.no_open_orders": {
+		LangRU: "Открытых заявок по этому оборудованию нет\\.",
+		LangTG: "Барои ин таҷҳизот дархости кушода нест\\.",
+		LangEN: "There are no open requests for this equipment\\.",
+	},
+	"tg.equipment.written_off": {
+		LangRU: "Оборудование списано, новую заявку по нему создать нельзя\\.",
+		LangTG: "Таҷҳизот аз ҳисоб бароварда шудааст, барои он дархости нав эҷод кардан мумкин нест\\.",
+		LangEN: "This equipment is written off, a new request cannot be created for it\\.",
+	},
+	"tg.equipment.create_link": {
+		LangRU: "Создать заявку: %s",
+		LangTG: "Эҷоди дархост: %s",
+		LangEN: "Create a request: %s",
+	},
+	"tg.equipment.not_found": {
+		LangRU: "❌ Оборудование по этому QR\\-коду не найдено или у вас нет к нему доступа\\.",
+		LangTG: "❌ Таҷҳизот бо ин рамзи QR ёфт нашуд ё шумо ба он дастрасӣ надоред\\.",
+		LangEN: "❌ Equipment for this QR code was not found or you have no access to it\\.",
+	},
+
+	// --- Команды и экраны ---
+	"tg.unknown_command": {
+		LangRU: "❌ Неизвестная команда\\. Используйте /menu или /help\\.",
+		LangTG: "❌ Фармони номаълум\\. Аз /menu ё /help истифода баред\\.",
+		LangEN: "❌ Unknown command\\. Use /menu or /help\\.",
+	},
+	"tg.not_linked": {
+		LangRU: "❌ *Аккаунт не привязан*\n\nИспользуйте /start для получения инструкций\\.",
+		LangTG: "❌ *Ҳисоб пайваст нашудааст*\n\nБарои гирифтани дастур аз /start истифода баред\\.",
+		LangEN: "❌ *Account is not linked*\n\nUse /start to get instructions\\.",
+	},
+	"tg.connection_active": {
+		LangRU: "✅ Подключение к боту активно\\.",
+		LangTG: "✅ Пайвастшавӣ ба бот фаъол аст\\.",
+		LangEN: "✅ Bot connection is active\\.",
+	},
+	"tg.commands_footer": {
+		LangRU: "*Команды:*\n/status \\- показать, к какому аккаунту привязан этот Telegram\n/unlink \\- отвязать этот Telegram от текущего аккаунта\n/language \\- сменить язык бота",
+		LangTG: "*Фармонҳо:*\n/status \\- нишон додан, ки ин Telegram ба кадом ҳисоб пайваст аст\n/unlink \\- ҷудо кардани ин Telegram аз ҳисоби ҷорӣ\n/language \\- иваз кардани забони бот",
+		LangEN: "*Commands:*\n/status \\- show which account this Telegram is linked to\n/unlink \\- unlink this Telegram from the current account\n/language \\- change the bot language",
+	},
+	"tg.menu.title": {
+		LangRU: "🏠 *Главное меню*\n\nСистема заявок банка\\.\nВыберите действие из меню ниже\\.",
+		LangTG: "🏠 *Менюи асосӣ*\n\nСистемаи дархостҳои бонк\\.\nАз менюи зер амалро интихоб кунед\\.",
+		LangEN: "🏠 *Main menu*\n\nBank request system\\.\nChoose an action below\\.",
+	},
+	"tg.start.linked": {
+		LangRU: "👤 *Вы уже авторизованы как:* %s\n\nИспользуйте меню ниже для работы с заявками\\.",
+		LangTG: "👤 *Шумо аллакай ворид шудаед ҳамчун:* %s\n\nБарои кор бо дархостҳо аз менюи зер истифода баред\\.",
+		LangEN: "👤 *You are signed in as:* %s\n\nUse the menu below to work with requests\\.",
+	},
+	"tg.start.welcome": {
+		LangRU: "Добро пожаловать в Telegram-бот HelpDesk.\n\n" +
+			"Этот бот позволяет работать с заявками банка прямо со смартфона.\n\n" +
+			"Как привязать Telegram:\n" +
+			"1. Откройте сайт HelpDesk и зайдите в профиль.\n" +
+			"2. Нажмите «Привязать Telegram».\n" +
+			"3. На сайте появятся ссылка, QR-код и короткий одноразовый код.\n" +
+			"4. Откройте бота со смартфона через QR-код или ссылку.\n" +
+			"5. Отправьте короткий код прямо сообщением в этот чат.\n" +
+			"6. Также работает команда /start <код>.\n\n" +
+			"Код действует ограниченное время. После привязки вам будут доступны список заявок, поиск, статистика, комментарии, изменение срока и делегирование.\n\n" +
+			"Сменить язык: /language",
+		LangTG: "Хуш омадед ба Telegram-боти HelpDesk.\n\n" +
+			"Ин бот имкон медиҳад, ки бо дархостҳои бонк бевосита аз смартфон кор кунед.\n\n" +
+			"Чӣ тавр Telegram-ро пайваст кардан мумкин аст:\n" +
+			"1. Сомонаи HelpDesk-ро кушоед ва ба профил ворид шавед.\n" +
+			"2. «Пайваст кардани Telegram»-ро пахш кунед.\n" +
+			"3. Дар сомона истинод, QR-код ва рамзи кӯтоҳи яккарата пайдо мешаванд.\n" +
+			"4. Ботро аз смартфон тавассути QR-код ё истинод кушоед.\n" +
+			"5. Рамзи кӯтоҳро бо паём ба ҳамин чат фиристед.\n" +
+			"6. Фармони /start <рамз> низ кор мекунад.\n\n" +
+			"Рамз муддати маҳдуд амал мекунад. Пас аз пайвастшавӣ рӯйхати дархостҳо, ҷустуҷӯ, омор, шарҳҳо, тағйири мӯҳлат ва супоридан дастрас мешаванд.\n\n" +
+			"Иваз кардани забон: /language",
+		LangEN: "Welcome to the HelpDesk Telegram bot.\n\n" +
+			"This bot lets you work with bank requests right from your phone.\n\n" +
+			"How to link Telegram:\n" +
+			"1. Open the HelpDesk website and go to your profile.\n" +
+			"2. Click \"Link Telegram\".\n" +
+			"3. The site shows a link, a QR code and a short one-time code.\n" +
+			"4. Open the bot on your phone via the QR code or the link.\n" +
+			"5. Send the short code as a message to this chat.\n" +
+			"6. The /start <code> command also works.\n\n" +
+			"The code is valid for a limited time. After linking you get the request list, search, statistics, comments, deadline changes and delegation.\n\n" +
+			"Change language: /language",
+	},
+	"tg.help": {
+		LangRU: "📖 *Справка по боту*\n\n" +
+			"Бот работает с теми же правами доступа, что и веб\\-проект\\. Если у вас нет доступа к заявке на сайте, бот тоже её не покажет\\.\n\n" +
+			"*Основные команды:*\n" +
+			"/start \\- начало работы и привязка аккаунта по коду из профиля\n" +
+			"/menu \\- открыть главное меню\n" +
+			"/my\\_tasks \\- показать ваши последние заявки\n" +
+			"/stats \\- показать личную статистику за последние 30 дней\n" +
+			"/status \\- показать, к какому аккаунту привязан этот Telegram\n" +
+			"/unlink \\- отвязать этот Telegram от текущего аккаунта\n" +
+			"/language \\- сменить язык бота\n" +
+			"/help \\- открыть эту справку\n\n" +
+			"*Кнопки меню:*\n" +
+			"📋 *Мои заявки* \\- ваши последние активные заявки\n" +
+			"👨‍💼 *Назначены мне* \\- заявки, где вы указаны исполнителем\n" +
+			"🗂 *Участвовал* \\- заявки, где вы участвовали в истории, но не являетесь создателем или текущим исполнителем\n" +
+			"⏰ *На сегодня* \\- заявки, созданные сегодня\n" +
+			"🔴 *Просроченные* \\- заявки с просроченным сроком\n" +
+			"🔍 *Поиск* \\- найти заявку по номеру или по тексту\n" +
+			"📊 *Статистика* \\- ваша краткая сводка по заявкам\n" +
+			"🔐 *Статус* \\- проверить текущую привязку Telegram\n" +
+			"📖 *Справка* \\- снова открыть эту подсказку\n\n" +
+			"*Что можно делать в карточке заявки:*\n" +
+			"• открыть заявку из списка\n" +
+			"• изменить статус \\(если у вас есть права\\)\n" +
+			"• изменить срок\n" +
+			"• добавить комментарий\n" +
+			"• прикрепить фото или документ \\(отправьте файл в чат\\)\n" +
+			"• делегировать другому сотруднику\n" +
+			"• сохранить изменения\n\n" +
+			"*Важно:*\n" +
+			"• все действия зависят от ваших прав и текущего статуса заявки\n" +
+			"• критические действия требуют подтверждения\n" +
+			"• если потеряли навигацию, используйте /menu",
+		LangTG: "📖 *Маълумотнома оид ба бот*\n\n" +
+			"Бот бо ҳамон ҳуқуқҳои дастрасӣ кор мекунад, ки веб\\-лоиҳа\\. Агар шумо дар сомона ба дархост дастрасӣ надошта бошед, бот низ онро нишон намедиҳад\\.\n\n" +
+			"*Фармонҳои асосӣ:*\n" +
+			"/start \\- оғози кор ва пайваст кардани ҳисоб бо рамз аз профил\n" +
+			"/menu \\- кушодани менюи асосӣ\n" +
+			"/my\\_tasks \\- нишон додани дархостҳои охирини шумо\n" +
+			"/stats \\- омори шахсӣ барои 30 рӯзи охир\n" +
+			"/status \\- нишон додан, ки ин Telegram ба кадом ҳисоб пайваст аст\n" +
+			"/unlink \\- ҷудо кардани ин Telegram аз ҳисоби ҷорӣ\n" +
+			"/language \\- иваз кардани забони бот\n" +
+			"/help \\- кушодани ҳамин маълумотнома\n\n" +
+			"*Дар корти дархост шумо метавонед:*\n" +
+			"• ҳолатро иваз кунед \\(агар ҳуқуқ дошта бошед\\)\n" +
+			"• мӯҳлатро иваз кунед\n" +
+			"• шарҳ илова кунед\n" +
+			"• акс ё ҳуҷҷат замима кунед \\(файлро ба чат фиристед\\)\n" +
+			"• ба корманди дигар супоред\n" +
+			"• тағйиротро нигоҳ доред\n\n" +
+			"*Муҳим:*\n" +
+			"• ҳамаи амалҳо аз ҳуқуқҳои шумо ва ҳолати ҷории дархост вобастаанд\n" +
+			"• агар роҳро гум кардед, аз /menu истифода баред",
+		LangEN: "📖 *Bot help*\n\n" +
+			"The bot uses the same access rights as the web app\\. If you cannot see a request on the website, the bot will not show it either\\.\n\n" +
+			"*Main commands:*\n" +
+			"/start \\- get started and link your account with the code from your profile\n" +
+			"/menu \\- open the main menu\n" +
+			"/my\\_tasks \\- show your latest requests\n" +
+			"/stats \\- personal statistics for the last 30 days\n" +
+			"/status \\- show which account this Telegram is linked to\n" +
+			"/unlink \\- unlink this Telegram from the current account\n" +
+			"/language \\- change the bot language\n" +
+			"/help \\- open this help\n\n" +
+			"*In a request card you can:*\n" +
+			"• change the status \\(if you have the rights\\)\n" +
+			"• change the deadline\n" +
+			"• add a comment\n" +
+			"• attach a photo or document \\(send the file to the chat\\)\n" +
+			"• delegate to another employee\n" +
+			"• save the changes\n\n" +
+			"*Note:*\n" +
+			"• every action depends on your rights and the request status\n" +
+			"• if you get lost, use /menu",
+	},
+
+	// --- Привязка аккаунта ---
+	"tg.link.error": {
+		LangRU: "❌ *Ошибка привязки*\n\n%s",
+		LangTG: "❌ *Хатогии пайвастшавӣ*\n\n%s",
+		LangEN: "❌ *Linking failed*\n\n%s",
+	},
+	// Текст без разметки: вызывающий код экранирует его вместе с сообщением сервиса.
+	"tg.link.invalid_code": {
+		LangRU: "Код неверный или устарел. Получите новый код на сайте.",
+		LangTG: "Рамз нодуруст аст ё мӯҳлаташ гузаштааст. Дар сомона рамзи нав гиред.",
+		LangEN: "The code is invalid or expired. Get a new code on the website.",
+	},
+
+	// --- Списки заявок ---
+	"tg.list.load_error":     {LangRU: "❌ Ошибка загрузки заявок\\.", LangTG: "❌ Хатогӣ ҳангоми боргирии дархостҳо\\.", LangEN: "❌ Failed to load requests\\."},
+	"tg.list.all.title":      {LangRU: "📚 *Все заявки*", LangTG: "📚 *Ҳамаи дархостҳо*", LangEN: "📚 *All requests*"},
+	"tg.list.all.empty":      {LangRU: "✅ Нет заявок, доступных для просмотра\\.", LangTG: "✅ Дархосте барои дидан нест\\.", LangEN: "✅ There are no requests you can view\\."},
+	"tg.list.my_tasks.title": {LangRU: "📋 *Мои заявки*", LangTG: "📋 *Дархостҳои ман*", LangEN: "📋 *My requests*"},
+	"tg.list.my_tasks.empty": {LangRU: "✅ У вас нет активных заявок\\.", LangTG: "✅ Шумо дархости фаъол надоред\\.", LangEN: "✅ You have no active requests\\."},
+	"tg.list.assigned.title": {LangRU: "👨‍💼 *Назначены мне*", LangTG: "👨‍💼 *Ба ман таъиншуда*", LangEN: "👨‍💼 *Assigned to me*"},
+	"tg.list.assigned.empty": {LangRU: "✅ На вас сейчас нет назначенных заявок\\.", LangTG: "✅ Ҳоло ба шумо ягон дархост таъин нашудааст\\.", LangEN: "✅ No requests are assigned to you right now\\."},
+	"tg.list.involved.title": {LangRU: "🗂 *Участвовал*", LangTG: "🗂 *Иштирок кардам*", LangEN: "🗂 *Involved*"},
+	"tg.list.involved.empty": {
+		LangRU: "✅ У вас нет заявок, где вы участвовали отдельно от роли создателя или исполнителя\\.",
+		LangTG: "✅ Дархосте нест, ки шумо дар он на ҳамчун эҷодкунанда ё иҷрокунанда иштирок карда бошед\\.",
+		LangEN: "✅ There are no requests you took part in other than as the creator or executor\\.",
+	},
+	"tg.list.today.title":     {LangRU: "⏰ *На сегодня*", LangTG: "⏰ *Барои имрӯз*", LangEN: "⏰ *Today*"},
+	"tg.list.today.empty":     {LangRU: "✅ Сегодня новых заявок нет\\.", LangTG: "✅ Имрӯз дархости нав нест\\.", LangEN: "✅ No new requests today\\."},
+	"tg.list.overdue.title":   {LangRU: "🔴 *Просроченные заявки*", LangTG: "🔴 *Дархостҳои мӯҳлаташ гузашта*", LangEN: "🔴 *Overdue requests*"},
+	"tg.list.overdue.empty":   {LangRU: "✅ Просроченных заявок нет\\.", LangTG: "✅ Дархости мӯҳлаташ гузашта нест\\.", LangEN: "✅ No overdue requests\\."},
+	"tg.list.page":            {LangRU: "_Страница %d из %d • всего %d_", LangTG: "_Саҳифаи %d аз %d • ҳамагӣ %d_", LangEN: "_Page %d of %d • %d total_"},
+	"tg.list.tap_order":       {LangRU: "_Нажмите на заявку:_", LangTG: "_Дархостро пахш кунед:_", LangEN: "_Tap a request:_"},
+	"tg.list.prev":            {LangRU: "⬅️ Назад", LangTG: "⬅️ Бозгашт", LangEN: "⬅️ Back"},
+	"tg.list.next":            {LangRU: "Вперёд ➡️", LangTG: "Пеш ➡️", LangEN: "Next ➡️"},
+	"tg.menu.already_updated": {LangRU: "Меню уже обновлено", LangTG: "Меню аллакай нав шудааст", LangEN: "The menu has already been updated"},
+
+	// --- Статистика ---
+	"tg.stats.error":          {LangRU: "❌ Ошибка получения статистики\\.", LangTG: "❌ Хатогӣ ҳангоми гирифтани омор\\.", LangEN: "❌ Failed to load statistics\\."},
+	"tg.stats.title":          {LangRU: "📊 *Ваша статистика за 30 дней*", LangTG: "📊 *Омори шумо барои 30 рӯз*", LangEN: "📊 *Your statistics for 30 days*"},
+	"tg.stats.total":          {LangRU: "📌 *Всего заявок:* %d", LangTG: "📌 *Ҳамагӣ дархостҳо:* %d", LangEN: "📌 *Total requests:* %d"},
+	"tg.stats.in_progress":    {LangRU: "⚙️ *В работе:* %d", LangTG: "⚙️ *Дар иҷро:* %d", LangEN: "⚙️ *In progress:* %d"},
+	"tg.stats.completed":      {LangRU: "✅ *Выполнено:* %d", LangTG: "✅ *Иҷро шуд:* %d", LangEN: "✅ *Completed:* %d"},
+	"tg.stats.overdue":        {LangRU: "🔴 *Просрочено:* %d", LangTG: "🔴 *Мӯҳлаташ гузашта:* %d", LangEN: "🔴 *Overdue:* %d"},
+	"tg.stats.closed":         {LangRU: "📁 *Закрыто:* %d", LangTG: "📁 *Баста шуд:* %d", LangEN: "📁 *Closed:* %d"},
+	"tg.stats.avg_resolution": {LangRU: "⏱ *Среднее время решения:* %s", LangTG: "⏱ *Вақти миёнаи ҳал:* %s", LangEN: "⏱ *Average resolution time:* %s"},
+
+	// --- Отчет по отделу и новая заявка ---
+	"tg.unit_report.forbidden":        {LangRU: "⛔️ Отчет по отделу доступен только руководителям\\.", LangTG: "⛔️ Ҳисобот аз рӯи шуъба танҳо барои роҳбарон дастрас аст\\.", LangEN: "⛔️ The unit report is available to managers only\\."},
+	"tg.unit_report.title":            {LangRU: "📊 *Отчет по отделу за 30 дней*", LangTG: "📊 *Ҳисобот аз рӯи шуъба барои 30 рӯз*", LangEN: "📊 *Unit report for 30 days*"},
+	"tg.unit_report.title_otdel":      {LangRU: "📊 *Отчет по отделу «%s» за 30 дней*", LangTG: "📊 *Ҳисобот аз рӯи шуъбаи «%s» барои 30 рӯз*", LangEN: "📊 *Report for unit «%s» for 30 days*"},
+	"tg.unit_report.title_department": {LangRU: "📊 *Отчет по департаменту «%s» за 30 дней*", LangTG: "📊 *Ҳисобот аз рӯи департаменти «%s» барои 30 рӯз*", LangEN: "📊 *Report for department «%s» for 30 days*"},
+	"tg.new_order.forbidden":          {LangRU: "⛔️ У вас нет права создавать заявки\\.", LangTG: "⛔️ Шумо ҳуқуқи эҷоди дархост надоред\\.", LangEN: "⛔️ You are not allowed to create requests\\."},
+	"tg.new_order.text": {
+		LangRU: "➕ *Новая заявка*\n\nЗаявка создается на сайте: там можно выбрать тип, оборудование и приложить файлы\\.",
+		LangTG: "➕ *Дархости нав*\n\nДархост дар сайт эҷод мешавад: дар он ҷо метавонед навъ ва таҷҳизотро интихоб кунед ва файлҳо замима намоед\\.",
+		LangEN: "➕ *New request*\n\nRequests are created on the website: there you can choose the type and equipment and attach files\\.",
+	},
+
+	// --- Выбор языка ---
+	"tg.language.prompt": {
+		LangRU: "🌐 *Выберите язык бота:*",
+		LangTG: "🌐 *Забони ботро интихоб кунед:*",
+		LangEN: "🌐 *Choose the bot language:*",
+	},
+	"tg.language.changed": {
+		LangRU: "Язык изменён",
+		LangTG: "Забон иваз шуд",
+		LangEN: "Language changed",
+	},
+
+	// --- Карточка заявки ---
+	"tg.order.title":          {LangRU: "📋 *Заявка №%s*", LangTG: "📋 *Дархости №%s*", LangEN: "📋 *Request %s*"},
+	"tg.order.description":    {LangRU: "📝 *Описание:*", LangTG: "📝 *Тавсиф:*", LangEN: "📝 *Description:*"},
+	"tg.order.status":         {LangRU: "*Статус:*", LangTG: "*Ҳолат:*", LangEN: "*Status:*"},
+	"tg.order.creator":        {LangRU: "👤 *Создатель:*", LangTG: "👤 *Эҷодкунанда:*", LangEN: "👤 *Creator:*"},
+	"tg.order.executor":       {LangRU: "👨‍💼 *Исполнитель:*", LangTG: "👨‍💼 *Иҷрокунанда:*", LangEN: "👨‍💼 *Executor:*"},
+	"tg.order.not_assigned":   {LangRU: "_не назначен_", LangTG: "_таъин нашудааст_", LangEN: "_not assigned_"},
+	"tg.order.deadline":       {LangRU: "⏰ *Срок:*", LangTG: "⏰ *Мӯҳлат:*", LangEN: "⏰ *Deadline:*"},
+	"tg.order.overdue":        {LangRU: "_просрочено_", LangTG: "_мӯҳлат гузаштааст_", LangEN: "_overdue_"},
+	"tg.order.not_set":        {LangRU: "_не задан_", LangTG: "_муқаррар нашудааст_", LangEN: "_not set_"},
+	"tg.order.last_comment":   {LangRU: "💬 *Последний комментарий:*", LangTG: "💬 *Шарҳи охирин:*", LangEN: "💬 *Last comment:*"},
+	"tg.order.choose_action":  {LangRU: "_Выберите действие:_", LangTG: "_Амалро интихоб кунед:_", LangEN: "_Choose an action:_"},
+	"tg.order.closed":         {LangRU: "🔒 *Заявка закрыта*\\.\n_Карточка доступна только для просмотра\\. Редактирование недоступно\\._", LangTG: "🔒 *Дархост баста шудааст*\\.\n_Корт танҳо барои дидан дастрас аст\\. Таҳрир имконнопазир аст\\._", LangEN: "🔒 *Request is closed*\\.\n_The card is read\\-only\\. Editing is not available\\._"},
+	"tg.order.view_only":      {LangRU: "👁️ *Режим просмотра*\\.\n_У вас есть доступ к просмотру заявки, но нет прав на её редактирование\\._", LangTG: "👁️ *Ҳолати дидан*\\.\n_Шумо дархостро дида метавонед, аммо ҳуқуқи таҳрир надоред\\._", LangEN: "👁️ *View mode*\\.\n_You can view this request but cannot edit it\\._"},
+	"tg.order.attach_no_card": {LangRU: "📎 Чтобы прикрепить файл, сначала откройте заявку, а затем отправьте фото или документ\\.", LangTG: "📎 Барои замима кардани файл аввал дархостро кушоед, баъд акс ё ҳуҷҷат фиристед\\.", LangEN: "📎 To attach a file, open a request first and then send a photo or document\\."},
+
+	// --- Уведомления ---
+	"notify.created":         {LangRU: "✅ %s создал\\(а\\) новую заявку №%s\n*%s*", LangTG: "✅ %s дархости нави №%s эҷод кард\n*%s*", LangEN: "✅ %s created a new request %s\n*%s*"},
+	"notify.updated":         {LangRU: "🔄 %s обновил\\(а\\) заявку №%s\n*%s*", LangTG: "🔄 %s дархости №%s\\-ро навсозӣ кард\n*%s*", LangEN: "🔄 %s updated request %s\n*%s*"},
+	"notify.status":          {LangRU: "Статус", LangTG: "Ҳолат", LangEN: "Status"},
+	"notify.priority":        {LangRU: "Приоритет", LangTG: "Афзалият", LangEN: "Priority"},
+	"notify.executor":        {LangRU: "Исполнитель", LangTG: "Иҷрокунанда", LangEN: "Executor"},
+	"notify.deadline":        {LangRU: "Срок выполнения", LangTG: "Мӯҳлати иҷро", LangEN: "Deadline"},
+	"notify.assigned_to_you": {LangRU: "Вам", LangTG: "Ба шумо", LangEN: "You"},
+	"notify.attachment":      {LangRU: "📎 Прикреплен файл: [%s](%s)", LangTG: "📎 Файл замима шуд: [%s](%s)", LangEN: "📎 File attached: [%s](%s)"},
+	"notify.view_orders":     {LangRU: "[Посмотреть мои заявки](%s/order?participant=me)", LangTG: "[Дидани дархостҳои ман](%s/order?participant=me)", LangEN: "[View my requests](%s/order?participant=me)"},
+	"notify.digest_header":   {LangRU: "🗂 *Сводка уведомлений* \\(%d\\)", LangTG: "🗂 *Хулосаи огоҳиномаҳо* \\(%d\\)", LangEN: "🗂 *Notification digest* \\(%d\\)"},
+
+	// --- Эскалация повторных нарушений срока ---
+	"notify.escalation_header": {LangRU: "⚠️ *Повторные нарушения срока*\n%[1]s нарушил\\(а\\) срок заявок %[2]d раз за %[3]d ч:", LangTG: "⚠️ *Вайронкунии такрории мӯҳлат*\n%[1]s дар %[3]d соат %[2]d маротиба мӯҳлати дархостҳоро вайрон кард:", LangEN: "⚠️ *Repeated deadline breaches*\n%[1]s missed request deadlines %[2]d times in %[3]d h:"},
+	"notify.escalation_order":  {LangRU: "• [№%s](%s) %s — просрочка %s", LangTG: "• [№%s](%s) %s — таъхир %s", LangEN: "• [%s](%s) %s — overdue by %s"},
+	"notify.duration":          {LangRU: "%d ч %d мин", LangTG: "%d соат %d дақ", LangEN: "%dh %dm"},
+
+	// --- Напоминание о первом отклике ---
+	"notify.first_response_reminder": {LangRU: "⏰ *Ждёт вашего первого отклика*\n[№%[1]s](%[2]s) %[3]s\nДо срока отклика осталось %[4]s", LangTG: "⏰ *Вокуниши аввали шуморо интизор аст*\n[№%[1]s](%[2]s) %[3]s\nТо мӯҳлати вокуниш %[4]s монд", LangEN: "⏰ *Waiting for your first response*\n[%[1]s](%[2]s) %[3]s\nTime left to respond: %[4]s"},
+	"notify.first_response_overdue":  {LangRU: "⏰ *Срок первого отклика истёк*\n[№%[1]s](%[2]s) %[3]s\nПросрочка %[4]s", LangTG: "⏰ *Мӯҳлати вокуниши аввал гузашт*\n[№%[1]s](%[2]s) %[3]s\nТаъхир %[4]s", LangEN: "⏰ *First response overdue*\n[%[1]s](%[2]s) %[3]s\nOverdue by %[4]s"},
+}
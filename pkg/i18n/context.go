@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type langContextKey struct{}
+
+// WithLang сохраняет язык ответа в контексте запроса.
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langContextKey{}, Normalize(lang))
+}
+
+// FromContext возвращает язык запроса или язык по умолчанию, если он не выбран.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(langContextKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLang
+}
+
+// ParseAcceptLanguage возвращает самый предпочтительный поддерживаемый язык из заголовка
+// Accept-Language («tg, ru;q=0.8, en;q=0.5») или пустую строку, если подходящего нет.
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if idx := strings.IndexAny(tag, "-_"); idx > 0 {
+			tag = tag[:idx]
+		}
+		if !IsSupported(tag) {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: tag, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
@@ -0,0 +1,98 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- ПОДДЕРЖИВАЕМЫЕ ЯЗЫКИ ---
+const (
+	LangRU = "ru"
+	LangTG = "tg"
+	LangEN = "en"
+
+	DefaultLang = LangRU
+)
+
+// SupportedLangs — порядок важен: в таком виде языки показываются пользователю.
+var SupportedLangs = []string{LangTG, LangRU, LangEN}
+
+// LangNames — название языка на самом этом языке.
+var LangNames = map[string]string{
+	LangTG: "🇹🇯 Тоҷикӣ",
+	LangRU: "🇷🇺 Русский",
+	LangEN: "🇬🇧 English",
+}
+
+func IsSupported(lang string) bool {
+	_, ok := LangNames[lang]
+	return ok
+}
+
+// Normalize приводит код языка к поддерживаемому ("ru-RU" -> "ru"), иначе возвращает язык по умолчанию.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if idx := strings.IndexAny(lang, "-_"); idx > 0 {
+		lang = lang[:idx]
+	}
+	if IsSupported(lang) {
+		return lang
+	}
+	return DefaultLang
+}
+
+// Key — аргумент T, который сам является ключом каталога: он переводится на тот же язык
+// перед подстановкой (например, название поля в тексте ошибки валидации).
+type Key string
+
+// T возвращает строку каталога для языка. Если перевода нет — берётся русский вариант,
+// если нет и его — сам ключ. args подставляются через fmt.Sprintf.
+func T(lang, key string, args ...interface{}) string {
+	lang = Normalize(lang)
+	text := lookup(lang, key)
+	if len(args) == 0 {
+		return text
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		if nested, ok := arg.(Key); ok {
+			arg = lookup(lang, string(nested))
+		}
+		values[i] = arg
+	}
+	return fmt.Sprintf(text, values...)
+}
+
+// Has сообщает, есть ли ключ в каталоге.
+func Has(key string) bool {
+	_, ok := catalog[key]
+	return ok
+}
+
+// Message переводит готовый русский текст ответа API (сообщения apperrors, контроллеров).
+// Такие тексты собираются в коде по месту, поэтому ключом служит сам русский текст;
+// если перевода нет, текст возвращается как есть.
+func Message(lang, text string) string {
+	lang = Normalize(lang)
+	if lang == DefaultLang {
+		return text
+	}
+	if translated, ok := messages[text][lang]; ok {
+		return translated
+	}
+	return text
+}
+
+func lookup(lang, key string) string {
+	translations, ok := catalog[key]
+	if !ok {
+		return key
+	}
+	if text, ok := translations[lang]; ok {
+		return text
+	}
+	if text, ok := translations[DefaultLang]; ok {
+		return text
+	}
+	return key
+}
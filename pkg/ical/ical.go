@@ -0,0 +1,129 @@
+// Package ical формирует календари в формате iCalendar (RFC 5545) для подписки из Outlook и Google Calendar.
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Длина строки без CRLF; длинные строки переносятся с пробелом в начале продолжения (RFC 5545, 3.1).
+const maxLineOctets = 75
+
+const timeLayout = "20060102T150405Z"
+
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Updated     time.Time
+	Categories  []string
+}
+
+type Calendar struct {
+	ProductID string
+	Name      string
+	// RefreshInterval — как часто клиенту перечитывать подписку (REFRESH-INTERVAL и X-PUBLISHED-TTL).
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Bytes сериализует календарь. Время пишется в UTC, поэтому VTIMEZONE не нужен.
+func (c *Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	w := func(name, value string) { writeLine(&buf, name+":"+value) }
+
+	w("BEGIN", "VCALENDAR")
+	w("VERSION", "2.0")
+	w("PRODID", escapeText(c.ProductID))
+	w("CALSCALE", "GREGORIAN")
+	w("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		d := formatDuration(c.RefreshInterval)
+		writeLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:"+d)
+		w("X-PUBLISHED-TTL", d)
+	}
+
+	for _, e := range c.Events {
+		w("BEGIN", "VEVENT")
+		w("UID", e.UID)
+		stamp := e.Updated
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		w("DTSTAMP", stamp.UTC().Format(timeLayout))
+		w("DTSTART", e.Start.UTC().Format(timeLayout))
+		w("DTEND", e.End.UTC().Format(timeLayout))
+		w("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			w("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.Location != "" {
+			w("LOCATION", escapeText(e.Location))
+		}
+		if e.URL != "" {
+			w("URL", e.URL)
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, cat := range e.Categories {
+				cats[i] = escapeText(cat)
+			}
+			w("CATEGORIES", strings.Join(cats, ","))
+		}
+		w("TRANSP", "TRANSPARENT")
+		w("END", "VEVENT")
+	}
+
+	w("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// writeLine переносит строку по 75 октетов, не разрывая многобайтные символы.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Продолжение начинается с пробела, который тоже занимает октет.
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes%60 == 0 {
+		return "PT" + strconv.Itoa(minutes/60) + "H"
+	}
+	return "PT" + strconv.Itoa(minutes) + "M"
+}
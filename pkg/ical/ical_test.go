@@ -0,0 +1,141 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestBytes_KnownCalendar(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	cal := Calendar{
+		ProductID:       "-//request-system//RU",
+		Name:            "Заявки; отдел ИТ",
+		RefreshInterval: time.Hour,
+		Events: []Event{{
+			UID:        "order-42@request-system",
+			Summary:    "Заявка №42, срочно",
+			Location:   `Каб. 305\2`,
+			URL:        "https://example.com/orders/42?tab=a,b",
+			Start:      time.Date(2026, 3, 1, 12, 0, 0, 0, msk),
+			End:        time.Date(2026, 3, 1, 13, 30, 0, 0, msk),
+			Updated:    time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC),
+			Categories: []string{"Сеть", "VIP, 1 линия"},
+		}},
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//request-system//RU",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Заявки\; отдел ИТ`,
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+		"BEGIN:VEVENT",
+		"UID:order-42@request-system",
+		"DTSTAMP:20260228T090000Z",
+		"DTSTART:20260301T090000Z",
+		"DTEND:20260301T103000Z",
+		`SUMMARY:Заявка №42\, срочно`,
+		`LOCATION:Каб. 305\\2`,
+		"URL:https://example.com/orders/42?tab=a,b",
+		`CATEGORIES:Сеть,VIP\, 1 линия`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := string(cal.Bytes()); got != want {
+		t.Fatalf("unexpected calendar:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	cases := map[string]string{
+		"a;b,c":                `a\;b\,c`,
+		`C:\tmp`:               `C:\\tmp`,
+		"строка 1\r\nстрока 2": `строка 1\nстрока 2`,
+		"a\nb\rc":              `a\nbc`,
+		`уже \n`:               `уже \\n`,
+		"":                     "",
+	}
+	for in, want := range cases {
+		if got := escapeText(in); got != want {
+			t.Errorf("escapeText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteLine_FoldsByOctets(t *testing.T) {
+	cases := []string{
+		"SUMMARY:" + strings.Repeat("a", 67),
+		"SUMMARY:" + strings.Repeat("a", 68),
+		"DESCRIPTION:" + strings.Repeat("Заявка на ремонт принтера. ", 20),
+		"SUMMARY:" + strings.Repeat("🖨", 40),
+		"X:" + strings.Repeat("я", 37) + "a",
+	}
+	for _, line := range cases {
+		var buf bytes.Buffer
+		writeLine(&buf, line)
+		out := buf.String()
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Fatalf("line must end with CRLF: %q", out)
+		}
+
+		physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		for i, p := range physical {
+			if len(p) > maxLineOctets {
+				t.Errorf("physical line %d is %d octets: %q", i, len(p), p)
+			}
+			if i > 0 && !strings.HasPrefix(p, " ") {
+				t.Errorf("continuation %d must start with a space: %q", i, p)
+			}
+			if !utf8.ValidString(p) {
+				t.Errorf("physical line %d splits a character: %q", i, p)
+			}
+		}
+		if len(line) <= maxLineOctets && len(physical) != 1 {
+			t.Errorf("a line of %d octets must not be folded", len(line))
+		}
+
+		// Разворачивание по RFC 5545 (CRLF и один пробел) возвращает исходную строку
+		if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != line {
+			t.Errorf("unfolded line differs:\n%q\n%q", unfolded, line)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		time.Hour:        "PT1H",
+		6 * time.Hour:    "PT6H",
+		90 * time.Minute: "PT90M",
+		15 * time.Minute: "PT15M",
+		10 * time.Second: "PT1M",
+		89 * time.Second: "PT1M",
+		90 * time.Second: "PT2M",
+	}
+	for d, want := range cases {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestBytes_OmitsEmptyFields(t *testing.T) {
+	cal := Calendar{ProductID: "p", Events: []Event{{UID: "1", Summary: "s"}}}
+	out := string(cal.Bytes())
+	for _, name := range []string{"X-WR-CALNAME", "REFRESH-INTERVAL", "DESCRIPTION", "LOCATION", "URL", "CATEGORIES"} {
+		if strings.Contains(out, "\r\n"+name) {
+			t.Errorf("empty %s must be omitted:\n%s", name, out)
+		}
+	}
+	// Без Updated DTSTAMP всё равно обязателен
+	if !strings.Contains(out, "\r\nDTSTAMP:") {
+		t.Errorf("DTSTAMP is required:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+// Package jobs — фоновые задания в Redis: постановка в очередь, обработчики с ограничением
+// параллельности, повторы с паузой, отложенный и периодический запуск.
+//
+// Доставка «хотя бы один раз»: задание, взятое упавшей репликой, по истечении таймаута
+// возвращается в очередь, поэтому обработчики должны быть идемпотентными.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type State string
+
+const (
+	StateScheduled State = "scheduled"
+	StateRunning   State = "running"
+	StateDead      State = "dead"
+)
+
+var (
+	ErrJobNotFound    = errors.New("задание не найдено")
+	ErrNotRetryable   = errors.New("повторить можно только задание, исчерпавшее попытки или ожидающее запуска")
+	ErrUnknownJobType = errors.New("неизвестный тип задания")
+)
+
+// Job — задание и его состояние; Payload разбирается обработчиком через Decode.
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	State       State           `json:"state"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Decode разбирает Payload в v.
+func (j Job) Decode(v any) error {
+	if len(j.Payload) == 0 {
+		return nil
+	}
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Handler выполняет задание. Ошибка означает повтор (или «мёртвое» задание после MaxAttempts).
+type Handler func(ctx context.Context, job Job) error
+
+// Options — настройки типа задания. Нулевые значения заменяются значениями по умолчанию.
+type Options struct {
+	// Сколько заданий этого типа одна реплика выполняет одновременно
+	Concurrency int
+	// Сколько всего попыток, включая первую
+	MaxAttempts int
+	// Сколько даётся на одну попытку; после этого задание считается зависшим
+	Timeout time.Duration
+	// Пауза перед повтором после attempt неудачных попыток
+	Backoff func(attempt int) time.Duration
+}
+
+const (
+	defaultConcurrency = 1
+	defaultMaxAttempts = 5
+	defaultTimeout     = 5 * time.Minute
+	maxBackoff         = time.Hour
+)
+
+func (o Options) withDefaults() Options {
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultConcurrency
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = defaultMaxAttempts
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultTimeout
+	}
+	if o.Backoff == nil {
+		o.Backoff = exponentialBackoff
+	}
+	return o
+}
+
+// exponentialBackoff — 10с, 20с, 40с... но не больше часа.
+func exponentialBackoff(attempt int) time.Duration {
+	delay := 10 * time.Second
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,388 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const keyPrefix = "jobs"
+
+// Ключи Redis:
+//
+//	jobs:job:<id>      — JSON задания
+//	jobs:queue:<type>  — ZSET ожидающих заданий типа, score — время запуска (мс)
+//	jobs:running       — ZSET выполняемых заданий, score — срок, после которого задание считается зависшим
+//	jobs:dead          — ZSET заданий, исчерпавших попытки, score — время последней ошибки
+//	jobs:types         — SET типов, по которым есть очереди
+func jobKey(id string) string        { return keyPrefix + ":job:" + id }
+func queueKey(jobType string) string { return keyPrefix + ":queue:" + jobType }
+func periodicKey(jobType string, slot int64) string {
+	return keyPrefix + ":periodic:" + jobType + ":" + strconv.FormatInt(slot, 10)
+}
+
+var (
+	runningKey = keyPrefix + ":running"
+	deadKey    = keyPrefix + ":dead"
+	typesKey   = keyPrefix + ":types"
+)
+
+// claimScript атомарно забирает самое раннее готовое задание из очереди типа в running.
+var claimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 1)
+if #ids == 0 then
+	return false
+end
+redis.call('ZREM', KEYS[1], ids[1])
+redis.call('ZADD', KEYS[2], ARGV[2], ids[1])
+return ids[1]
+`)
+
+// moveScript переносит задание из одного ZSET в другой, только если оно всё ещё в исходном
+// (защита от гонки реплик при возврате зависших заданий).
+var moveScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[1])
+return 1
+`)
+
+const (
+	defaultPollInterval = time.Second
+	reapInterval        = 30 * time.Second
+)
+
+type registration struct {
+	handler Handler
+	opts    Options
+}
+
+type periodic struct {
+	jobType  string
+	interval time.Duration
+	payload  any
+}
+
+// Queue — очередь заданий и её обработчики. Все реплики должны регистрировать одни и те же типы:
+// задание неизвестного типа не ставится в очередь.
+type Queue struct {
+	redis  *redis.Client
+	logger *zap.Logger
+
+	mu           sync.RWMutex
+	handlers     map[string]registration
+	periodic     []periodic
+	pollInterval time.Duration
+
+	started bool
+	wg      sync.WaitGroup
+}
+
+func New(redisClient *redis.Client, logger *zap.Logger) *Queue {
+	return &Queue{
+		redis:        redisClient,
+		logger:       logger,
+		handlers:     make(map[string]registration),
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Register регистрирует обработчик типа задания. Вызывается до Start.
+func (q *Queue) Register(jobType string, handler Handler, opts Options) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		panic("jobs: Register после Start")
+	}
+	q.handlers[jobType] = registration{handler: handler, opts: opts.withDefaults()}
+}
+
+// Every ставит задание типа jobType раз в interval. Интервалы выровнены по времени Unix,
+// поэтому при нескольких репликах задание ставится один раз за интервал.
+func (q *Queue) Every(jobType string, interval time.Duration, payload any) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		panic("jobs: Every после Start")
+	}
+	q.periodic = append(q.periodic, periodic{jobType: jobType, interval: interval, payload: payload})
+}
+
+// EnqueueOption — параметры постановки отдельного задания.
+type EnqueueOption func(*Job)
+
+// At откладывает запуск задания до t.
+func At(t time.Time) EnqueueOption {
+	return func(j *Job) { j.RunAt = t }
+}
+
+// After откладывает запуск задания на d.
+func After(d time.Duration) EnqueueOption {
+	return func(j *Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// Enqueue ставит задание в очередь. payload сериализуется в JSON.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, opts ...EnqueueOption) (*Job, error) {
+	q.mu.RLock()
+	reg, ok := q.handlers[jobType]
+	q.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("сериализация задания %s: %w", jobType, err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.NewString(),
+		Type:        jobType,
+		Payload:     raw,
+		State:       StateScheduled,
+		MaxAttempts: reg.opts.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, jobKey(job.ID), data, 0)
+		pipe.SAdd(ctx, typesKey, jobType)
+		pipe.ZAdd(ctx, queueKey(jobType), &redis.Z{Score: scoreOf(job.RunAt), Member: job.ID})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("постановка задания %s в очередь: %w", jobType, err)
+	}
+	return job, nil
+}
+
+// Get возвращает задание по ID. Успешно выполненные задания удаляются и не находятся.
+func (q *Queue) Get(ctx context.Context, id string) (*Job, error) {
+	data, err := q.redis.Get(ctx, jobKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("разбор задания %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+// List возвращает задания в состоянии state (по времени запуска или ошибки) и их общее число.
+func (q *Queue) List(ctx context.Context, state State, limit, offset int) ([]Job, uint64, error) {
+	var ids []string
+	var total int64
+	switch state {
+	case StateRunning, StateDead:
+		key := runningKey
+		if state == StateDead {
+			key = deadKey
+		}
+		var err error
+		if total, err = q.redis.ZCard(ctx, key).Result(); err != nil {
+			return nil, 0, err
+		}
+		stop := int64(-1)
+		if limit > 0 {
+			stop = int64(offset + limit - 1)
+		}
+		if ids, err = q.redis.ZRange(ctx, key, int64(offset), stop).Result(); err != nil {
+			return nil, 0, err
+		}
+	case StateScheduled:
+		// Очереди разбиты по типам, поэтому ожидающие задания собираются из всех очередей
+		types, err := q.redis.SMembers(ctx, typesKey).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		var all []redis.Z
+		for _, jobType := range types {
+			items, err := q.redis.ZRangeWithScores(ctx, queueKey(jobType), 0, -1).Result()
+			if err != nil {
+				return nil, 0, err
+			}
+			all = append(all, items...)
+		}
+		sort.SliceStable(all, func(i, j int) bool { return all[i].Score < all[j].Score })
+		total = int64(len(all))
+		end := len(all)
+		if limit > 0 && offset+limit < end {
+			end = offset + limit
+		}
+		for i := offset; i < end; i++ {
+			ids = append(ids, all[i].Member.(string))
+		}
+	default:
+		return nil, 0, fmt.Errorf("неизвестное состояние задания: %s", state)
+	}
+
+	list := make([]Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := q.Get(ctx, id)
+		if errors.Is(err, ErrJobNotFound) {
+			// Задание завершилось между чтением индекса и записи
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, *job)
+	}
+	return list, uint64(total), nil
+}
+
+// Retry запускает «мёртвое» задание заново с обнулёнными попытками или переносит
+// ожидающее задание на сейчас.
+func (q *Queue) Retry(ctx context.Context, id string) (*Job, error) {
+	job, err := q.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := queueKey(job.Type)
+	switch job.State {
+	case StateDead:
+		from = deadKey
+		job.Attempts = 0
+	case StateScheduled:
+	default:
+		return nil, ErrNotRetryable
+	}
+
+	job.State = StateScheduled
+	job.RunAt = time.Now()
+	if err := q.save(ctx, job); err != nil {
+		return nil, err
+	}
+	moved, err := moveScript.Run(ctx, q.redis, []string{from, queueKey(job.Type)}, job.ID, scoreOf(job.RunAt)).Int()
+	if err != nil {
+		return nil, err
+	}
+	if moved == 0 {
+		return nil, ErrNotRetryable
+	}
+	return job, nil
+}
+
+// claim забирает готовое задание типа и помечает его выполняемым.
+func (q *Queue) claim(ctx context.Context, jobType string, timeout time.Duration) (*Job, error) {
+	now := time.Now()
+	id, err := claimScript.Run(ctx, q.redis, []string{queueKey(jobType), runningKey}, scoreOf(now), scoreOf(now.Add(timeout))).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := q.Get(ctx, id)
+	if errors.Is(err, ErrJobNotFound) {
+		q.redis.ZRem(ctx, runningKey, id)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.State = StateRunning
+	job.Attempts++
+	if err := q.save(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (q *Queue) complete(ctx context.Context, job *Job) error {
+	_, err := q.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, runningKey, job.ID)
+		pipe.Del(ctx, jobKey(job.ID))
+		return nil
+	})
+	return err
+}
+
+// fail откладывает повтор задания или, если попытки кончились, переносит его в dead.
+func (q *Queue) fail(ctx context.Context, job *Job, cause error, backoff func(int) time.Duration) error {
+	job.LastError = cause.Error()
+	to, score := queueKey(job.Type), 0.0
+	if job.Attempts >= job.MaxAttempts {
+		job.State = StateDead
+		to, score = deadKey, scoreOf(time.Now())
+	} else {
+		job.State = StateScheduled
+		job.RunAt = time.Now().Add(backoff(job.Attempts))
+		score = scoreOf(job.RunAt)
+	}
+	if err := q.save(ctx, job); err != nil {
+		return err
+	}
+	return moveScript.Run(ctx, q.redis, []string{runningKey, to}, job.ID, score).Err()
+}
+
+// reap возвращает в очередь задания, чья реплика не уложилась в таймаут (упала или зависла).
+func (q *Queue) reap(ctx context.Context) {
+	ids, err := q.redis.ZRangeByScore(ctx, runningKey, &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatFloat(scoreOf(time.Now()), 'f', 0, 64)}).Result()
+	if err != nil {
+		q.logger.Error("Не удалось прочитать зависшие задания", zap.Error(err))
+		return
+	}
+	for _, id := range ids {
+		job, err := q.Get(ctx, id)
+		if errors.Is(err, ErrJobNotFound) {
+			q.redis.ZRem(ctx, runningKey, id)
+			continue
+		}
+		if err != nil {
+			q.logger.Error("Не удалось прочитать зависшее задание", zap.String("jobID", id), zap.Error(err))
+			continue
+		}
+		q.mu.RLock()
+		reg, ok := q.handlers[job.Type]
+		q.mu.RUnlock()
+		backoff := exponentialBackoff
+		if ok {
+			backoff = reg.opts.Backoff
+		}
+		if err := q.fail(ctx, job, errors.New("превышено время выполнения"), backoff); err != nil {
+			q.logger.Error("Не удалось вернуть зависшее задание", zap.String("jobID", id), zap.Error(err))
+			continue
+		}
+		q.logger.Warn("Зависшее задание возвращено в очередь", zap.String("jobID", id), zap.String("type", job.Type), zap.Int("attempts", job.Attempts))
+	}
+}
+
+func (q *Queue) save(ctx context.Context, job *Job) error {
+	job.UpdatedAt = time.Now()
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.redis.Set(ctx, jobKey(job.ID), data, 0).Err()
+}
+
+func scoreOf(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
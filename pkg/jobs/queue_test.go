@@ -0,0 +1,221 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(client, zap.NewNop())
+}
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestEnqueue_RejectsUnknownType(t *testing.T) {
+	q := newTestQueue(t)
+	if _, err := q.Enqueue(context.Background(), "missing", nil); !errors.Is(err, ErrUnknownJobType) {
+		t.Fatalf("expected ErrUnknownJobType, got %v", err)
+	}
+}
+
+func TestClaim_TakesEarliestReadyJob(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+	q.Register("mail", func(context.Context, Job) error { return nil }, Options{})
+
+	later, _ := q.Enqueue(ctx, "mail", map[string]int{"n": 3}, After(time.Hour))
+	second, _ := q.Enqueue(ctx, "mail", map[string]int{"n": 2}, At(time.Now().Add(-time.Second)))
+	first, _ := q.Enqueue(ctx, "mail", map[string]int{"n": 1}, At(time.Now().Add(-time.Minute)))
+
+	for _, want := range []*Job{first, second} {
+		job, err := q.claim(ctx, "mail", time.Minute)
+		if err != nil || job == nil {
+			t.Fatalf("claim: %v, %v", job, err)
+		}
+		if job.ID != want.ID || job.State != StateRunning || job.Attempts != 1 {
+			t.Fatalf("unexpected claimed job: %+v", job)
+		}
+	}
+	// Отложенное задание ещё не готово
+	if job, err := q.claim(ctx, "mail", time.Minute); err != nil || job != nil {
+		t.Fatalf("delayed job must not be claimed: %+v, %v", job, err)
+	}
+
+	running, total, err := q.List(ctx, StateRunning, 0, 0)
+	if err != nil || total != 2 || len(running) != 2 {
+		t.Fatalf("expected two running jobs, got %d (%v)", total, err)
+	}
+	scheduled, _, _ := q.List(ctx, StateScheduled, 0, 0)
+	if len(scheduled) != 1 || scheduled[0].ID != later.ID {
+		t.Fatalf("only the delayed job must stay scheduled: %+v", scheduled)
+	}
+	var payload struct{ N int }
+	if err := scheduled[0].Decode(&payload); err != nil || payload.N != 3 {
+		t.Fatalf("payload must survive the queue: %+v, %v", payload, err)
+	}
+}
+
+func TestFail_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+	q.Register("sync", func(context.Context, Job) error { return nil }, Options{MaxAttempts: 2})
+	enqueued, _ := q.Enqueue(ctx, "sync", nil)
+
+	job, _ := q.claim(ctx, "sync", time.Minute)
+	before := time.Now()
+	if err := q.fail(ctx, job, errors.New("timeout"), func(attempt int) time.Duration { return time.Duration(attempt) * time.Hour }); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	stored, _ := q.Get(ctx, enqueued.ID)
+	if stored.State != StateScheduled || stored.LastError != "timeout" || stored.RunAt.Before(before.Add(time.Hour-time.Second)) {
+		t.Fatalf("first failure must schedule a retry after the backoff: %+v", stored)
+	}
+	if job, _ := q.claim(ctx, "sync", time.Minute); job != nil {
+		t.Fatal("retry must wait for the backoff")
+	}
+
+	if _, err := q.Retry(ctx, enqueued.ID); err != nil {
+		t.Fatalf("Retry of a scheduled job: %v", err)
+	}
+	job, _ = q.claim(ctx, "sync", time.Minute)
+	if job == nil || job.Attempts != 2 {
+		t.Fatalf("expected the second attempt, got %+v", job)
+	}
+	if err := q.fail(ctx, job, errors.New("still down"), noBackoff); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+
+	dead, total, err := q.List(ctx, StateDead, 10, 0)
+	if err != nil || total != 1 || dead[0].ID != enqueued.ID || dead[0].LastError != "still down" || dead[0].Attempts != 2 {
+		t.Fatalf("exhausted job must be dead-lettered: %+v (%v)", dead, err)
+	}
+	if running, total, _ := q.List(ctx, StateRunning, 0, 0); total != 0 {
+		t.Fatalf("dead job must leave running: %+v", running)
+	}
+	if job, _ := q.claim(ctx, "sync", time.Minute); job != nil {
+		t.Fatal("dead job must not be claimed")
+	}
+}
+
+func TestRetry_RestartsDeadJobOnly(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+	q.Register("sync", func(context.Context, Job) error { return nil }, Options{MaxAttempts: 1})
+	enqueued, _ := q.Enqueue(ctx, "sync", nil)
+
+	job, _ := q.claim(ctx, "sync", time.Minute)
+	if _, err := q.Retry(ctx, job.ID); !errors.Is(err, ErrNotRetryable) {
+		t.Fatalf("running job must not be retried, got %v", err)
+	}
+	q.fail(ctx, job, errors.New("boom"), noBackoff)
+
+	retried, err := q.Retry(ctx, enqueued.ID)
+	if err != nil || retried.State != StateScheduled || retried.Attempts != 0 {
+		t.Fatalf("dead job must restart from zero attempts: %+v, %v", retried, err)
+	}
+	if _, total, _ := q.List(ctx, StateDead, 0, 0); total != 0 {
+		t.Fatal("retried job must leave the dead letters")
+	}
+	if job, _ := q.claim(ctx, "sync", time.Minute); job == nil || job.Attempts != 1 {
+		t.Fatalf("retried job must be claimable again: %+v", job)
+	}
+	if _, err := q.Retry(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestReap_ReturnsStuckJobs(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+	q.Register("report", func(context.Context, Job) error { return nil }, Options{MaxAttempts: 3, Backoff: noBackoff})
+	stuck, _ := q.Enqueue(ctx, "report", nil)
+	alive, _ := q.Enqueue(ctx, "report", nil)
+
+	// Срок первого задания уже прошёл: его реплика упала
+	q.claim(ctx, "report", -time.Second)
+	q.claim(ctx, "report", time.Hour)
+	q.reap(ctx)
+
+	job, _ := q.Get(ctx, stuck.ID)
+	if job.State != StateScheduled || job.Attempts != 1 || !strings.Contains(job.LastError, "время") {
+		t.Fatalf("stuck job must return to the queue: %+v", job)
+	}
+	if job, _ := q.Get(ctx, alive.ID); job.State != StateRunning {
+		t.Fatalf("job within its timeout must stay running: %+v", job)
+	}
+	if job, _ := q.claim(ctx, "report", time.Hour); job == nil || job.ID != stuck.ID || job.Attempts != 2 {
+		t.Fatalf("reaped job must be claimed again: %+v", job)
+	}
+}
+
+func TestRun_CompletesAndRecoversPanics(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t)
+	q.Register("ok", func(context.Context, Job) error { return nil }, Options{})
+	q.Register("panic", func(context.Context, Job) error { panic("nil map") }, Options{MaxAttempts: 1})
+	okJob, _ := q.Enqueue(ctx, "ok", nil)
+	panicJob, _ := q.Enqueue(ctx, "panic", nil)
+
+	for _, jobType := range []string{"ok", "panic"} {
+		job, _ := q.claim(ctx, jobType, time.Minute)
+		q.run(ctx, job, q.handlers[jobType], zap.NewNop())
+	}
+
+	if _, err := q.Get(ctx, okJob.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("completed job must be removed, got %v", err)
+	}
+	job, _ := q.Get(ctx, panicJob.ID)
+	if job.State != StateDead || !strings.Contains(job.LastError, "panic: nil map") {
+		t.Fatalf("panic must fail the job: %+v", job)
+	}
+}
+
+func TestStart_ProcessesJobsUntilShutdown(t *testing.T) {
+	q := newTestQueue(t)
+	q.pollInterval = 5 * time.Millisecond
+	done := make(chan string, 1)
+	q.Register("ping", func(_ context.Context, job Job) error {
+		done <- job.ID
+		return nil
+	}, Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.Start(ctx)
+	job, _ := q.Enqueue(ctx, "ping", nil)
+
+	select {
+	case id := <-done:
+		if id != job.ID {
+			t.Fatalf("unexpected job %s", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not processed")
+	}
+
+	cancel()
+	shutdownCtx, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	if err := q.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 9: 42*time.Minute + 40*time.Second, 10: time.Hour, 100: time.Hour}
+	for attempt, want := range cases {
+		if got := exponentialBackoff(attempt); got != want {
+			t.Errorf("attempt %d: got %s, want %s", attempt, got, want)
+		}
+	}
+}
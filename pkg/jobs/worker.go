@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Start запускает обработчики зарегистрированных типов, периодические задания и возврат
+// зависших заданий. Новые задания перестают забираться после отмены ctx; уже начатые
+// дорабатываются — дождаться их можно через Shutdown.
+func (q *Queue) Start(ctx context.Context) {
+	q.mu.Lock()
+	q.started = true
+	handlers := make(map[string]registration, len(q.handlers))
+	for jobType, reg := range q.handlers {
+		handlers[jobType] = reg
+	}
+	periodicJobs := append([]periodic(nil), q.periodic...)
+	q.mu.Unlock()
+
+	for jobType, reg := range handlers {
+		for i := 0; i < reg.opts.Concurrency; i++ {
+			q.wg.Add(1)
+			go q.work(ctx, jobType, reg)
+		}
+	}
+	for _, p := range periodicJobs {
+		q.wg.Add(1)
+		go q.schedule(ctx, p)
+	}
+	q.wg.Add(1)
+	go q.reapLoop(ctx)
+
+	q.logger.Info("Фоновые задания запущены", zap.Int("types", len(handlers)), zap.Int("periodic", len(periodicJobs)))
+}
+
+// Shutdown ждёт завершения начатых заданий, но не дольше ctx. Незавершённые задания
+// останутся в running и будут возвращены в очередь по таймауту.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("не все фоновые задания завершились: %w", ctx.Err())
+	}
+}
+
+func (q *Queue) work(ctx context.Context, jobType string, reg registration) {
+	defer q.wg.Done()
+	logger := q.logger.With(zap.String("type", jobType))
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		job, err := q.claim(ctx, jobType, reg.opts.Timeout)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Не удалось забрать задание", zap.Error(err))
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(q.pollInterval):
+			}
+			continue
+		}
+		q.run(ctx, job, reg, logger)
+	}
+}
+
+// run выполняет одну попытку. Контекст попытки не отменяется вместе с приложением,
+// чтобы при остановке начатое задание успело завершиться в пределах своего таймаута.
+func (q *Queue) run(ctx context.Context, job *Job, reg registration, logger *zap.Logger) {
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reg.opts.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	err := q.safeCall(runCtx, reg.handler, *job)
+	logger = logger.With(zap.String("jobID", job.ID), zap.Int("attempt", job.Attempts), zap.Duration("took", time.Since(started)))
+
+	if err == nil {
+		if err := q.complete(runCtx, job); err != nil {
+			logger.Error("Не удалось отметить задание выполненным", zap.Error(err))
+		}
+		return
+	}
+
+	if err := q.fail(runCtx, job, err, reg.opts.Backoff); err != nil {
+		logger.Error("Не удалось сохранить ошибку задания", zap.Error(err))
+		return
+	}
+	if job.State == StateDead {
+		logger.Error("Задание исчерпало попытки", zap.String("lastError", job.LastError))
+	} else {
+		logger.Warn("Задание завершилось с ошибкой, будет повтор", zap.Time("runAt", job.RunAt), zap.String("lastError", job.LastError))
+	}
+}
+
+func (q *Queue) safeCall(ctx context.Context, handler Handler, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// schedule ставит периодическое задание в начале каждого интервала. Слот интервала
+// занимается через SETNX, так что из нескольких реплик задание ставит одна.
+func (q *Queue) schedule(ctx context.Context, p periodic) {
+	defer q.wg.Done()
+	tick := min(p.interval, 30*time.Second)
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		slot := time.Now().UnixNano() / int64(p.interval)
+		acquired, err := q.redis.SetNX(ctx, periodicKey(p.jobType, slot), 1, 2*p.interval).Result()
+		if err != nil && ctx.Err() == nil {
+			q.logger.Error("Не удалось занять слот периодического задания", zap.String("type", p.jobType), zap.Error(err))
+		}
+		if acquired {
+			if _, err := q.Enqueue(ctx, p.jobType, p.payload); err != nil {
+				q.logger.Error("Не удалось поставить периодическое задание", zap.String("type", p.jobType), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) reapLoop(ctx context.Context) {
+	defer q.wg.Done()
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.reap(ctx)
+		}
+	}
+}
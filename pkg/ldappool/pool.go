@@ -0,0 +1,241 @@
+// Package ldappool держит открытые соединения с контроллерами домена и переключается
+// на резервные серверы, когда основной недоступен.
+package ldappool
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ldap "github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+
+	"request-system/pkg/config"
+)
+
+// ErrNoServers — ни один контроллер домена не ответил.
+var ErrNoServers = errors.New("ldappool: нет доступных LDAP-серверов")
+
+const (
+	defaultTimeout  = 10 * time.Second
+	defaultCooldown = 30 * time.Second
+)
+
+type server struct {
+	addr      string
+	host      string
+	downUntil time.Time
+}
+
+// Pool выдаёт соединения без привязки к учётной записи: каждый пользователь соединения
+// сам выполняет Bind, поэтому одно соединение годится и для поиска, и для проверки пароля.
+type Pool struct {
+	servers   []*server
+	next      int
+	mu        sync.Mutex
+	idle      chan *ldap.Conn
+	tlsMode   string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	cooldown  time.Duration
+	logger    *zap.Logger
+}
+
+func New(cfg *config.LDAPConfig, logger *zap.Logger) (*Pool, error) {
+	tlsMode := strings.ToLower(strings.TrimSpace(cfg.TLSMode))
+	switch tlsMode {
+	case "":
+		tlsMode = config.LDAPTLSModeNone
+	case config.LDAPTLSModeNone, config.LDAPTLSModeLDAPS, config.LDAPTLSModeStartTLS:
+	default:
+		return nil, fmt.Errorf("ldappool: неизвестный LDAP_TLS_MODE %q", cfg.TLSMode)
+	}
+
+	p := &Pool{
+		tlsMode:  tlsMode,
+		timeout:  cfg.Timeout,
+		cooldown: cfg.ServerCooldown,
+		idle:     make(chan *ldap.Conn, max(cfg.PoolSize, 0)),
+		logger:   logger,
+	}
+	if p.timeout <= 0 {
+		p.timeout = defaultTimeout
+	}
+	if p.cooldown <= 0 {
+		p.cooldown = defaultCooldown
+	}
+
+	for _, host := range append([]string{cfg.Host}, cfg.FallbackHosts...) {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		addr := host
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			addr = net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+		}
+		name, _, _ := net.SplitHostPort(addr)
+		p.servers = append(p.servers, &server{addr: addr, host: name})
+	}
+
+	if tlsMode != config.LDAPTLSModeNone {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         strings.TrimSpace(cfg.TLSServerName),
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		}
+		if cfg.CACertFile != "" {
+			pem, err := os.ReadFile(cfg.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("ldappool: не удалось прочитать LDAP_CA_CERT_FILE: %w", err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("ldappool: в %s нет PEM-сертификатов", cfg.CACertFile)
+			}
+			tlsConfig.RootCAs = roots
+		}
+		p.tlsConfig = tlsConfig
+	}
+
+	return p, nil
+}
+
+// Get возвращает соединение из пула или открывает новое. Соединение нужно вернуть
+// через Put; после ошибки сети его достаточно закрыть.
+func (p *Pool) Get() (*ldap.Conn, error) {
+	for {
+		select {
+		case conn := <-p.idle:
+			if conn.IsClosing() {
+				conn.Close()
+				continue
+			}
+			return conn, nil
+		default:
+			return p.dial()
+		}
+	}
+}
+
+// Put возвращает соединение в пул; лишние и закрытые соединения закрываются.
+func (p *Pool) Put(conn *ldap.Conn) {
+	if conn == nil {
+		return
+	}
+	if conn.IsClosing() {
+		conn.Close()
+		return
+	}
+	select {
+	case p.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// IsNetworkError — соединение оборвалось, операцию стоит повторить на новом соединении.
+func IsNetworkError(err error) bool {
+	return ldap.IsErrorWithCode(err, ldap.ErrorNetwork)
+}
+
+// candidates — серверы по кругу, начиная со следующего; недоступные в конце списка,
+// чтобы при падении всех DC всё равно попробовать каждый.
+func (p *Pool) candidates() []*server {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]*server, 0, len(p.servers))
+	down := make([]*server, 0)
+	for i := range p.servers {
+		srv := p.servers[(p.next+i)%len(p.servers)]
+		if now.Before(srv.downUntil) {
+			down = append(down, srv)
+		} else {
+			healthy = append(healthy, srv)
+		}
+	}
+	if len(p.servers) > 0 {
+		p.next = (p.next + 1) % len(p.servers)
+	}
+	return append(healthy, down...)
+}
+
+func (p *Pool) markDown(srv *server, err error) {
+	p.mu.Lock()
+	srv.downUntil = time.Now().Add(p.cooldown)
+	p.mu.Unlock()
+	p.logger.Warn("LDAP-сервер недоступен, переключаемся на следующий",
+		zap.String("server", srv.addr), zap.Duration("cooldown", p.cooldown), zap.Error(err))
+}
+
+func (p *Pool) markUp(srv *server) {
+	p.mu.Lock()
+	wasDown := !srv.downUntil.IsZero()
+	srv.downUntil = time.Time{}
+	p.mu.Unlock()
+	if wasDown {
+		p.logger.Info("LDAP-сервер снова доступен", zap.String("server", srv.addr))
+	}
+}
+
+func (p *Pool) dial() (*ldap.Conn, error) {
+	var lastErr error
+	for _, srv := range p.candidates() {
+		conn, err := p.dialServer(srv)
+		if err != nil {
+			p.markDown(srv, err)
+			lastErr = err
+			continue
+		}
+		p.markUp(srv)
+		return conn, nil
+	}
+	if lastErr == nil {
+		return nil, ErrNoServers
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNoServers, lastErr)
+}
+
+func (p *Pool) serverTLSConfig(srv *server) *tls.Config {
+	cfg := p.tlsConfig.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = srv.host
+	}
+	return cfg
+}
+
+func (p *Pool) dialServer(srv *server) (*ldap.Conn, error) {
+	dialer := ldap.DialWithDialer(&net.Dialer{Timeout: p.timeout})
+
+	var (
+		conn *ldap.Conn
+		err  error
+	)
+	switch p.tlsMode {
+	case config.LDAPTLSModeLDAPS:
+		conn, err = ldap.DialURL("ldaps://"+srv.addr, dialer, ldap.DialWithTLSConfig(p.serverTLSConfig(srv)))
+	default:
+		conn, err = ldap.DialURL("ldap://"+srv.addr, dialer)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetTimeout(p.timeout)
+	if p.tlsMode == config.LDAPTLSModeStartTLS {
+		if err := conn.StartTLS(p.serverTLSConfig(srv)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"strconv"
 	"strings"
 
 	apperrors "request-system/pkg/errors"
@@ -15,6 +17,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// ImpersonatedByHeader — заголовок ответа с ID администратора, если запрос сделан
+// по токену имперсонации; по нему фронтенд показывает плашку «вы вошли как …».
+const ImpersonatedByHeader = "X-Impersonated-By"
+
 type AuthMiddleware struct {
 	jwtService            service.JWTService
 	authPermissionService services.AuthPermissionServiceInterface
@@ -48,6 +54,18 @@ func (m *AuthMiddleware) Auth(next echo.HandlerFunc) echo.HandlerFunc {
 			return utils.ErrorResponse(c, apperrors.ErrTokenIsNotAccess, m.logger)
 		}
 
+		// Токен имперсонации живёт до истечения, поэтому администратора проверяем на каждом запросе:
+		// после блокировки или отзыва права его сеанс от чужого имени прекращается сразу.
+		if claims.ImpersonatorID != 0 {
+			if err := m.authPermissionService.CheckImpersonator(c.Request().Context(), claims.ImpersonatorID); err != nil {
+				m.logger.Warn("Токен имперсонации отклонён: администратор заблокирован или лишён права",
+					zap.Uint64("impersonatorID", claims.ImpersonatorID),
+					zap.Uint64("userID", claims.UserID),
+					zap.Error(err))
+				return utils.ErrorResponse(c, apperrors.ErrUnauthorized, m.logger)
+			}
+		}
+
 		permissions, err := m.authPermissionService.GetAllUserPermissions(c.Request().Context(), claims.UserID)
 		if err != nil {
 			m.logger.Error("Ошибка получения прав пользователя",
@@ -57,22 +75,69 @@ func (m *AuthMiddleware) Auth(next echo.HandlerFunc) echo.HandlerFunc {
 			return utils.ErrorResponse(c, apperrors.ErrInternalServer, m.logger)
 		}
 
-		permissionsMap := make(map[string]bool)
-		for _, p := range permissions {
-			permissionsMap[p] = true
+		conditions, err := m.authPermissionService.GetUserPermissionConditions(c.Request().Context(), claims.UserID)
+		if err != nil {
+			m.logger.Error("Не удалось загрузить условия привилегий пользователя", zap.Uint64("userID", claims.UserID), zap.Error(err))
+			return utils.ErrorResponse(c, apperrors.ErrInternalServer, m.logger)
 		}
 
-		ctx := c.Request().Context()
-		newCtx := context.WithValue(ctx, contextkeys.UserIDKey, claims.UserID)
-		newCtx = context.WithValue(newCtx, contextkeys.UserRoleIDKey, claims.RoleID)
-		newCtx = context.WithValue(newCtx, contextkeys.UserPermissionsKey, permissions)
-		newCtx = context.WithValue(newCtx, contextkeys.UserPermissionsMapKey, permissionsMap)
+		newCtx := utils.WithUserContext(c.Request().Context(), claims.UserID, claims.RoleID, permissions)
+		newCtx = utils.WithPermissionConditions(newCtx, conditions)
+		newCtx = utils.WithTenantID(newCtx, claims.TenantID)
+		if claims.ImpersonatorID != 0 {
+			newCtx = utils.WithImpersonator(newCtx, claims.ImpersonatorID)
+			c.Response().Header().Set(ImpersonatedByHeader, strconv.FormatUint(claims.ImpersonatorID, 10))
+		}
 		c.SetRequest(c.Request().WithContext(newCtx))
 
 		return next(c)
 	}
 }
 
+// APIKeyOrAuth пропускает запрос по ключу из заголовка X-API-Key либо, если ключа нет,
+// по обычному JWT. Запросу с верным ключом выдаются только права grants — пользователя за ним нет.
+func (m *AuthMiddleware) APIKeyOrAuth(keys []string, grants ...string) echo.MiddlewareFunc {
+	validKeys := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			validKeys = append(validKeys, []byte(key))
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		jwtNext := m.Auth(next)
+		return func(c echo.Context) error {
+			provided := strings.TrimSpace(c.Request().Header.Get("X-API-Key"))
+			if provided == "" {
+				return jwtNext(c)
+			}
+
+			matched := false
+			for _, key := range validKeys {
+				if subtle.ConstantTimeCompare([]byte(provided), key) == 1 {
+					matched = true
+				}
+			}
+			if !matched {
+				m.logger.Warn("Неверный API-ключ", zap.String("path", c.Path()), zap.String("ip", c.RealIP()))
+				return utils.ErrorResponse(c, apperrors.ErrUnauthorized, m.logger)
+			}
+
+			permissionsMap := make(map[string]bool, len(grants))
+			for _, grant := range grants {
+				permissionsMap[grant] = true
+			}
+			ctx := context.WithValue(c.Request().Context(), contextkeys.UserPermissionsKey, append([]string(nil), grants...))
+			ctx = context.WithValue(ctx, contextkeys.UserPermissionsMapKey, permissionsMap)
+			// Ключи интеграций работают в организации по умолчанию
+			ctx = utils.WithTenantID(ctx, 0)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
 func (m *AuthMiddleware) handleAuthError(c echo.Context, err error) error {
 	m.logger.Warn("Ошибка аутентификации", zap.Error(err))
 	if !c.Response().Committed {
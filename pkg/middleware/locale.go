@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"request-system/pkg/i18n"
+	"request-system/pkg/utils"
+)
+
+// ContentLanguageHeader — заголовок ответа с языком, на котором отданы сообщения.
+const ContentLanguageHeader = "Content-Language"
+
+// Language выбирает язык ответа по заголовку Accept-Language; без него — язык по умолчанию.
+// Ставится глобально, чтобы ошибки до авторизации (логин, 401) тоже были переведены.
+func Language() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			lang := i18n.ParseAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+			if lang == "" {
+				lang = i18n.DefaultLang
+			}
+			SetLanguage(c, lang)
+			return next(c)
+		}
+	}
+}
+
+// UserLanguage ставится после Auth: сохранённый язык пользователя важнее Accept-Language,
+// чтобы выбор в профиле или Telegram-боте действовал на любом устройстве.
+// Если язык получить не удалось, остаётся язык из Accept-Language.
+func UserLanguage(resolve func(ctx context.Context, userID uint64) (string, error), logger *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, err := utils.GetUserIDFromCtx(c.Request().Context())
+			if err != nil || userID == 0 {
+				return next(c)
+			}
+			lang, err := resolve(c.Request().Context(), userID)
+			if err != nil {
+				logger.Warn("Не удалось определить язык пользователя", zap.Uint64("user_id", userID), zap.Error(err))
+				return next(c)
+			}
+			SetLanguage(c, lang)
+			return next(c)
+		}
+	}
+}
+
+// SetLanguage задаёт язык ответа на текущий запрос; контроллер смены языка вызывает его,
+// чтобы подтверждение пришло уже на новом языке.
+func SetLanguage(c echo.Context, lang string) {
+	lang = i18n.Normalize(lang)
+	c.SetRequest(c.Request().WithContext(i18n.WithLang(c.Request().Context(), lang)))
+	c.Response().Header().Set(ContentLanguageHeader, lang)
+}
+
+// UserTimezone ставится после Auth и кладёт в контекст часовой пояс пользователя; без него
+// даты форматируются в поясе сервера (см. utils.LocationFromCtx).
+func UserTimezone(resolve func(ctx context.Context, userID uint64) (string, error), logger *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, err := utils.GetUserIDFromCtx(c.Request().Context())
+			if err != nil || userID == 0 {
+				return next(c)
+			}
+			name, err := resolve(c.Request().Context(), userID)
+			if err != nil {
+				logger.Warn("Не удалось определить часовой пояс пользователя", zap.Uint64("user_id", userID), zap.Error(err))
+				return next(c)
+			}
+			if name != "" {
+				c.SetRequest(c.Request().WithContext(utils.WithLocation(c.Request().Context(), utils.UserLocation(&name))))
+			}
+			return next(c)
+		}
+	}
+}
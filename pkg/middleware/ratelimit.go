@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/ratelimit"
+	"request-system/pkg/utils"
+)
+
+// RateLimitPolicy — лимиты группы маршрутов. Чтение (GET/HEAD/OPTIONS) и запись
+// считаются в разных вёдрах, чтобы активный просмотр списков не блокировал сохранение.
+type RateLimitPolicy struct {
+	Name  string
+	Read  ratelimit.Rule
+	Write ratelimit.Rule
+	// Rules, если задан, вызывается на каждый запрос вместо Read/Write — для лимитов,
+	// которые перечитываются без перезапуска. Пустое правило выключает лимит.
+	Rules func() (read, write ratelimit.Rule)
+}
+
+// RateLimit ограничивает частоту запросов: авторизованных — по пользователю, остальных — по IP.
+// Для /api/auth/* ставится до Auth, поэтому там всегда работает ведро по IP.
+// Если Redis недоступен, запрос пропускается: лимитер не должен ронять API.
+func RateLimit(limiter *ratelimit.Limiter, policy RateLimitPolicy, logger *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if limiter == nil {
+			return next
+		}
+		return func(c echo.Context) error {
+			read, write := policy.Read, policy.Write
+			if policy.Rules != nil {
+				read, write = policy.Rules()
+			}
+			rule, kind := write, "write"
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				rule, kind = read, "read"
+			}
+			if !rule.Enabled() {
+				return next(c)
+			}
+
+			subject := "ip:" + c.RealIP()
+			if userID, err := utils.GetUserIDFromCtx(c.Request().Context()); err == nil && userID != 0 {
+				subject = "user:" + strconv.FormatUint(userID, 10)
+			}
+
+			result, err := limiter.Allow(c.Request().Context(), "http:"+policy.Name+":"+kind+":"+subject, rule)
+			if err != nil {
+				logger.Warn("Лимитер запросов недоступен, запрос пропущен", zap.String("policy", policy.Name), zap.Error(err))
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(rule.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+				logger.Warn("Превышен лимит запросов",
+					zap.String("policy", policy.Name),
+					zap.String("subject", subject),
+					zap.String("path", c.Path()),
+					zap.String("rule", rule.String()))
+				return utils.ErrorResponse(c, apperrors.ErrTooManyRequests, logger)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// Package orderquery разбирает компактную строку поиска заявок, например
+//
+//	status:OPEN,IN_PROGRESS priority:CRITICAL executor:me created:>2024-01-01 text:"принтер"
+//
+// Условия разделяются пробелами и объединяются через И; значения через запятую — через ИЛИ.
+// Слова без поля и поле text уходят в полнотекстовый поиск. Пакет проверяет только синтаксис:
+// коды статусов, приоритетов и типов превращает в ID сервис заявок.
+package orderquery
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	apperrors "request-system/pkg/errors"
+)
+
+// MaxLength ограничивает длину строки запроса.
+const MaxLength = 500
+
+// CustomFieldPrefix — условие по дополнительному полю заявки: cf.<код>:значение.
+const CustomFieldPrefix = "cf."
+
+const dateLayout = "2006-01-02"
+
+// Kind — вид значения поля.
+type Kind int
+
+const (
+	// KindCode — коды справочника через запятую (статус, приоритет, тип).
+	KindCode Kind = iota
+	// KindUser — me, none или ID пользователей через запятую.
+	KindUser
+	// KindID — ID через запятую.
+	KindID
+	// KindDate — дата с оператором сравнения (>, >=, <, <=) или диапазон ГГГГ-ММ-ДД..ГГГГ-ММ-ДД.
+	KindDate
+	// KindBool — true/false (yes/no, да/нет).
+	KindBool
+	// KindText — свободный текст.
+	KindText
+	// KindCustom — значения дополнительного поля через запятую.
+	KindCustom
+)
+
+// Значения для полей исполнителя и автора.
+const (
+	UserMe   = "me"
+	UserNone = "none"
+)
+
+// Fields — поддерживаемые поля и их виды. Синонимы приводятся к основному имени через aliases.
+var Fields = map[string]Kind{
+	"status":     KindCode,
+	"priority":   KindCode,
+	"type":       KindCode,
+	"executor":   KindUser,
+	"creator":    KindUser,
+	"id":         KindID,
+	"department": KindID,
+	"branch":     KindID,
+	"otdel":      KindID,
+	"office":     KindID,
+	"equipment":  KindID,
+	"team":       KindID,
+	"created":    KindDate,
+	"due":        KindDate,
+	"overdue":    KindBool,
+	"text":       KindText,
+}
+
+var aliases = map[string]string{
+	"assignee": "executor",
+	"author":   "creator",
+	"deadline": "due",
+}
+
+// Term — одно условие запроса.
+type Term struct {
+	Field string
+	// Values — значения списка; для KindBool — одно значение "true" или "false".
+	Values []string
+	// From и To — границы для дат включительно; nil — граница не задана.
+	From *time.Time
+	To   *time.Time
+}
+
+// Query — разобранная строка: условия по полям и текст для полнотекстового поиска.
+type Query struct {
+	Terms []Term
+	Text  string
+}
+
+// token — пара «поле:значение»; для слова без поля key пустой.
+type token struct {
+	key   string
+	op    string
+	value string
+}
+
+// Parse разбирает строку; даты без времени трактуются в поясе loc.
+// Ошибки — HttpError 400 с ключом перевода query.*.
+func Parse(input string, loc *time.Location) (*Query, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if len([]rune(input)) > MaxLength {
+		return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.too_long", MaxLength)
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	var text []string
+	dates := make(map[string]int) // поле даты → индекс условия в q.Terms
+	for _, tok := range tokens {
+		if tok.key == "" {
+			text = append(text, tok.value)
+			continue
+		}
+		field, kind, err := resolveField(tok.key)
+		if err != nil {
+			return nil, err
+		}
+		if tok.op != "" && kind != KindDate {
+			return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_operator", tok.op, field)
+		}
+		if strings.TrimSpace(tok.value) == "" {
+			return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.empty_value", field)
+		}
+
+		switch kind {
+		case KindText:
+			text = append(text, tok.value)
+		case KindDate:
+			from, to, err := parseDateRange(field, tok.op, tok.value, loc)
+			if err != nil {
+				return nil, err
+			}
+			// Повторные условия по одной дате сужают период: created:>2024-01-01 created:<2024-02-01.
+			idx, ok := dates[field]
+			if !ok {
+				q.Terms = append(q.Terms, Term{Field: field})
+				idx = len(q.Terms) - 1
+				dates[field] = idx
+			}
+			term := &q.Terms[idx]
+			if from != nil && (term.From == nil || from.After(*term.From)) {
+				term.From = from
+			}
+			if to != nil && (term.To == nil || to.Before(*term.To)) {
+				term.To = to
+			}
+		case KindBool:
+			value, ok := parseBool(tok.value)
+			if !ok {
+				return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_bool", field)
+			}
+			q.Terms = append(q.Terms, Term{Field: field, Values: []string{strconv.FormatBool(value)}})
+		default:
+			values, err := splitValues(field, kind, tok.value)
+			if err != nil {
+				return nil, err
+			}
+			q.Terms = append(q.Terms, Term{Field: field, Values: values})
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// LooksLikeQuery — есть ли в строке хотя бы одно условие «известное_поле:значение».
+// Так поиск в Telegram отличает запрос от обычного текста с двоеточием.
+func LooksLikeQuery(input string) bool {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return strings.Contains(input, ":")
+	}
+	for _, tok := range tokens {
+		if tok.key == "" {
+			continue
+		}
+		if _, _, err := resolveField(tok.key); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldNames — имена полей для подсказок, по алфавиту.
+func FieldNames() []string {
+	names := make([]string, 0, len(Fields)+1)
+	for name := range Fields {
+		names = append(names, name)
+	}
+	names = append(names, CustomFieldPrefix+"<code>")
+	sort.Strings(names)
+	return names
+}
+
+func resolveField(key string) (string, Kind, error) {
+	field := strings.ToLower(key)
+	if code, ok := strings.CutPrefix(field, CustomFieldPrefix); ok && code != "" {
+		// Код дополнительного поля чувствителен к регистру — берём его как написан.
+		return CustomFieldPrefix + key[len(CustomFieldPrefix):], KindCustom, nil
+	}
+	if alias, ok := aliases[field]; ok {
+		field = alias
+	}
+	kind, ok := Fields[field]
+	if !ok {
+		return "", 0, apperrors.NewLocalizedError(http.StatusBadRequest, "query.unknown_field", key, strings.Join(FieldNames(), ", "))
+	}
+	return field, kind, nil
+}
+
+// tokenize делит строку по пробелам; значения в кавычках могут содержать пробелы, \" — кавычка.
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var tok token
+		if runes[i] != '"' {
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ':' && runes[i] != '"' {
+				i++
+			}
+			if i < len(runes) && runes[i] == ':' {
+				tok.key = string(runes[start:i])
+				i++
+				for _, op := range []string{">=", "<=", ">", "<"} {
+					if strings.HasPrefix(string(runes[i:min(i+2, len(runes))]), op) {
+						tok.op = op
+						i += len(op)
+						break
+					}
+				}
+			} else {
+				i = start
+			}
+		}
+
+		if i < len(runes) && runes[i] == '"' {
+			value, next, ok := readQuoted(runes, i)
+			if !ok {
+				return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.unclosed_quote")
+			}
+			tok.value, i = value, next
+		} else {
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+			tok.value = string(runes[start:i])
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+func readQuoted(runes []rune, start int) (string, int, bool) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == '"':
+			b.WriteRune('"')
+			i++
+		case runes[i] == '"':
+			return b.String(), i + 1, true
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", len(runes), false
+}
+
+func splitValues(field string, kind Kind, raw string) ([]string, error) {
+	var values []string
+	for _, part := range strings.Split(raw, ",") {
+		value := strings.TrimSpace(part)
+		if value == "" {
+			continue
+		}
+		switch kind {
+		case KindCode:
+			value = strings.ToUpper(value)
+		case KindUser:
+			lower := strings.ToLower(value)
+			if lower == UserMe || lower == UserNone {
+				value = lower
+				break
+			}
+			if !isID(value) {
+				return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_user", value, field)
+			}
+		case KindID:
+			if !isID(value) {
+				return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_id", value, field)
+			}
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.empty_value", field)
+	}
+	return values, nil
+}
+
+func isID(value string) bool {
+	id, err := strconv.ParseUint(value, 10, 64)
+	return err == nil && id > 0
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1", "да":
+		return true, true
+	case "false", "no", "0", "нет":
+		return false, true
+	}
+	return false, false
+}
+
+// parseDateRange переводит условие по дате в границы [from, to] включительно:
+// «2024-01-01» — весь день, «>2024-01-01» — со следующего дня, «<=2024-01-31» — до конца дня.
+func parseDateRange(field, op, value string, loc *time.Location) (*time.Time, *time.Time, error) {
+	parseDay := func(s string) (time.Time, error) {
+		day, err := time.ParseInLocation(dateLayout, strings.TrimSpace(s), loc)
+		if err != nil {
+			return time.Time{}, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_date", s, field)
+		}
+		return day, nil
+	}
+	endOf := func(day time.Time) *time.Time {
+		end := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		return &end
+	}
+
+	if fromRaw, toRaw, isRange := strings.Cut(value, ".."); isRange {
+		if op != "" {
+			return nil, nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_operator", op, field)
+		}
+		from, err := parseDay(fromRaw)
+		if err != nil {
+			return nil, nil, err
+		}
+		to, err := parseDay(toRaw)
+		if err != nil {
+			return nil, nil, err
+		}
+		if to.Before(from) {
+			return nil, nil, apperrors.NewLocalizedError(http.StatusBadRequest, "query.bad_range", value, field)
+		}
+		return &from, endOf(to), nil
+	}
+
+	day, err := parseDay(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch op {
+	case ">":
+		next := day.AddDate(0, 0, 1)
+		return &next, nil, nil
+	case ">=":
+		return &day, nil, nil
+	case "<":
+		before := day.Add(-time.Nanosecond)
+		return nil, &before, nil
+	case "<=":
+		return nil, endOf(day), nil
+	default:
+		return &day, endOf(day), nil
+	}
+}
@@ -0,0 +1,178 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+var ErrNotTrueType = errors.New("pdf: нужен шрифт TrueType (.ttf), шрифты CFF/OTF не поддерживаются")
+
+// Font — шрифт TrueType, встраиваемый в документ целиком с кодировкой Identity-H:
+// в тексте пишутся номера глифов, поэтому кириллица и любые другие буквы шрифта
+// выводятся без таблиц кодировок. Глифы, которых в шрифте нет, печатаются пустыми.
+// Font копит использованные глифы и не защищён от гонок: на каждый документ — свой ParseFont.
+type Font struct {
+	data []byte
+	sfnt *sfnt.Font
+	buf  sfnt.Buffer
+	name string
+
+	unitsPerEm      int
+	ascent, descent int
+	capHeight       int
+	bbox            [4]int
+	glyphs          map[rune]sfnt.GlyphIndex
+	advances        map[sfnt.GlyphIndex]int
+	used            map[sfnt.GlyphIndex]rune
+}
+
+// ParseFont разбирает TrueType-файл.
+func ParseFont(data []byte) (*Font, error) {
+	if bytes.HasPrefix(data, []byte("OTTO")) {
+		return nil, ErrNotTrueType
+	}
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("pdf: не удалось разобрать шрифт: %w", err)
+	}
+	result := &Font{
+		data:       data,
+		sfnt:       f,
+		unitsPerEm: int(f.UnitsPerEm()),
+		glyphs:     make(map[rune]sfnt.GlyphIndex),
+		advances:   make(map[sfnt.GlyphIndex]int),
+		used:       make(map[sfnt.GlyphIndex]rune),
+	}
+
+	name, err := f.Name(&result.buf, sfnt.NameIDPostScript)
+	if err != nil || name == "" {
+		name = "EmbeddedFont"
+	}
+	result.name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	// Метрики в единицах шрифта: ppem, равный unitsPerEm, даёт значения без масштабирования.
+	ppem := fixed.I(result.unitsPerEm)
+	if m, err := f.Metrics(&result.buf, ppem, font.HintingNone); err == nil {
+		result.ascent = m.Ascent.Round()
+		result.descent = -m.Descent.Round()
+		result.capHeight = m.CapHeight.Round()
+	}
+	if b, err := f.Bounds(&result.buf, ppem, font.HintingNone); err == nil {
+		// sfnt отдаёт ось Y вниз, в PDF она смотрит вверх.
+		result.bbox = [4]int{b.Min.X.Round(), -b.Max.Y.Round(), b.Max.X.Round(), -b.Min.Y.Round()}
+	}
+	return result, nil
+}
+
+func (f *Font) glyph(r rune) sfnt.GlyphIndex {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	g, err := f.sfnt.GlyphIndex(&f.buf, r)
+	if err != nil {
+		g = 0
+	}
+	f.glyphs[r] = g
+	return g
+}
+
+func (f *Font) advance(g sfnt.GlyphIndex) int {
+	if a, ok := f.advances[g]; ok {
+		return a
+	}
+	a, err := f.sfnt.GlyphAdvance(&f.buf, g, fixed.I(f.unitsPerEm), font.HintingNone)
+	width := 0
+	if err == nil {
+		width = a.Round()
+	}
+	f.advances[g] = width
+	return width
+}
+
+// toPDFUnits переводит единицы шрифта в тысячные доли кегля, принятые в PDF.
+func (f *Font) toPDFUnits(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// Width — ширина строки в пунктах при кегле size.
+func (f *Font) Width(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		total += f.advance(f.glyph(r))
+	}
+	return float64(total) * size / float64(f.unitsPerEm)
+}
+
+// encode переводит строку в шестнадцатеричную строку PDF из номеров глифов
+// и запоминает, какие глифы понадобятся в таблицах ширин и ToUnicode.
+func (f *Font) encode(text string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range text {
+		g := f.glyph(r)
+		if _, ok := f.used[g]; !ok {
+			f.used[g] = r
+		}
+		fmt.Fprintf(&b, "%04X", uint16(g))
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+func (f *Font) usedGlyphs() []sfnt.GlyphIndex {
+	glyphs := make([]sfnt.GlyphIndex, 0, len(f.used))
+	for g := range f.used {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
+
+// widthsArray — массив /W для использованных глифов.
+func (f *Font) widthsArray() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for _, g := range f.usedGlyphs() {
+		fmt.Fprintf(&b, "%d [%d] ", g, f.toPDFUnits(f.advance(g)))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// toUnicodeCMap — обратная таблица глиф → символ, чтобы текст из PDF копировался и искался.
+func (f *Font) toUnicodeCMap() []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	glyphs := f.usedGlyphs()
+	for start := 0; start < len(glyphs); start += 100 {
+		end := min(start+100, len(glyphs))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, g := range glyphs[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", uint16(g))
+			for _, unit := range utf16.Encode([]rune{f.used[g]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
@@ -0,0 +1,276 @@
+// Package pdf — минимальный генератор PDF для печатных форм: страницы A4, текст встроенными
+// TrueType-шрифтами, линии, залитые прямоугольники и растровые картинки. Своя реализация, как и pkg/qrcode: ради акта и отчёта не тянем
+// библиотеку вёрстки.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Размер страницы A4 в пунктах.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Document — документ из страниц A4. Координаты отсчитываются от левого верхнего угла
+// страницы вниз; у текста y — базовая линия.
+type Document struct {
+	title  string
+	fonts  []*Font
+	images []image.Image
+	pages  []*bytes.Buffer
+	page   int
+}
+
+func New() *Document {
+	return &Document{page: -1}
+}
+
+// SetTitle задаёт заголовок документа, который просмотрщики показывают вместо имени файла.
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// AddPage добавляет страницу и делает её текущей.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.page = len(d.pages) - 1
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage делает текущей страницу n (с нуля) — например, чтобы дописать колонтитулы.
+func (d *Document) SetPage(n int) {
+	if n >= 0 && n < len(d.pages) {
+		d.page = n
+	}
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if d.page < 0 {
+		d.AddPage()
+	}
+	return d.pages[d.page]
+}
+
+func (d *Document) fontName(f *Font) string {
+	for i, known := range d.fonts {
+		if known == f {
+			return "F" + strconv.Itoa(i+1)
+		}
+	}
+	d.fonts = append(d.fonts, f)
+	return "F" + strconv.Itoa(len(d.fonts))
+}
+
+// Text выводит строку шрифтом f кеглем size; (x, y) — начало базовой линии.
+func (d *Document) Text(f *Font, size, x, y float64, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(d.current(), "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		d.fontName(f), num(size), num(x), num(A4Height-y), f.encode(text))
+}
+
+// Line рисует отрезок толщиной width пунктов.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(A4Height-y1), num(x2), num(A4Height-y2))
+}
+
+// SetColor задаёт цвет текста, заливки и линий для дальнейшего вывода на текущей странице.
+// Новая страница начинается с чёрного.
+func (d *Document) SetColor(c color.Color) {
+	r, g, b := rgb(c)
+	fmt.Fprintf(d.current(), "%s %s %s rg %s %s %s RG\n", r, g, b, r, g, b)
+}
+
+// Rect заливает прямоугольник текущим цветом; (x, y) — левый верхний угол.
+func (d *Document) Rect(x, y, width, height float64) {
+	fmt.Fprintf(d.current(), "%s %s %s %s re f\n", num(x), num(A4Height-y-height), num(width), num(height))
+}
+
+// Image выводит картинку в прямоугольник (x, y, width, height). Прозрачность не сохраняется:
+// картинка накладывается на белый фон.
+func (d *Document) Image(img image.Image, x, y, width, height float64) {
+	name := d.imageName(img)
+	fmt.Fprintf(d.current(), "q %s 0 0 %s %s %s cm /%s Do Q\n",
+		num(width), num(height), num(x), num(A4Height-y-height), name)
+}
+
+func (d *Document) imageName(img image.Image) string {
+	for i, known := range d.images {
+		if known == img {
+			return "Im" + strconv.Itoa(i+1)
+		}
+	}
+	d.images = append(d.images, img)
+	return "Im" + strconv.Itoa(len(d.images))
+}
+
+// Write собирает документ. Шрифты встраиваются целиком, содержимое страниц сжимается.
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Номера объектов: 1 — каталог, 2 — дерево страниц, 3 — сведения о документе,
+	// затем по пять объектов на шрифт, по одному на картинку и по два на страницу.
+	const fontObjects, pageObjects = 5, 2
+	firstFont := 4
+	firstImage := firstFont + fontObjects*len(d.fonts)
+	firstPage := firstImage + len(d.images)
+	total := firstPage + pageObjects*len(d.pages) - 1
+
+	out := &countingWriter{w: w}
+	offsets := make([]int64, total+1)
+	object := func(n int, body string) {
+		offsets[n] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", n, body)
+	}
+	stream := func(n int, dict string, data []byte) error {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		offsets[n] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n<< %s /Filter /FlateDecode /Length %d >>\nstream\n", n, dict, buf.Len())
+		out.Write(buf.Bytes())
+		io.WriteString(out, "\nendstream\nendobj\n")
+		return nil
+	}
+
+	io.WriteString(out, "%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+	object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]byte, 0, 8*len(d.pages))
+	for i := range d.pages {
+		kids = fmt.Appendf(kids, "%d 0 R ", firstPage+pageObjects*i)
+	}
+	object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(d.pages)))
+	object(3, fmt.Sprintf("<< /Producer (request-system) /Title %s >>", textString(d.title)))
+
+	fontResources := make([]byte, 0, 16*len(d.fonts))
+	for i, f := range d.fonts {
+		n := firstFont + fontObjects*i
+		fontResources = fmt.Appendf(fontResources, "/F%d %d 0 R ", i+1, n)
+
+		object(n, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			f.name, n+1, n+3))
+		object(n+1, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %d /W %s /CIDToGIDMap /Identity >>",
+			f.name, n+2, f.toPDFUnits(f.advance(0)), f.widthsArray()))
+		object(n+2, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			f.name, f.toPDFUnits(f.bbox[0]), f.toPDFUnits(f.bbox[1]), f.toPDFUnits(f.bbox[2]), f.toPDFUnits(f.bbox[3]),
+			f.toPDFUnits(f.ascent), f.toPDFUnits(f.descent), f.toPDFUnits(f.capHeight), n+4))
+		if err := stream(n+3, "", f.toUnicodeCMap()); err != nil {
+			return err
+		}
+		if err := stream(n+4, fmt.Sprintf("/Length1 %d", len(f.data)), f.data); err != nil {
+			return err
+		}
+	}
+
+	imageResources := make([]byte, 0, 16*len(d.images))
+	for i, img := range d.images {
+		n := firstImage + i
+		imageResources = fmt.Appendf(imageResources, "/Im%d %d 0 R ", i+1, n)
+		bounds := img.Bounds()
+		if err := stream(n, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8",
+			bounds.Dx(), bounds.Dy()), rgbPixels(img)); err != nil {
+			return err
+		}
+	}
+
+	for i, content := range d.pages {
+		n := firstPage + pageObjects*i
+		object(n, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> /XObject << %s>> >> /Contents %d 0 R >>",
+			num(A4Width), num(A4Height), fontResources, imageResources, n+1))
+		if err := stream(n+1, "", content.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", total+1)
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", total+1, xref)
+	return out.err
+}
+
+// Bytes собирает документ в память.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// rgb — компоненты цвета от 0 до 1 для операторов rg и RG.
+func rgb(c color.Color) (string, string, string) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	component := func(v uint8) string { return num(float64(v) / 255) }
+	return component(n.R), component(n.G), component(n.B)
+}
+
+// rgbPixels — пиксели картинки построчно по три байта, полупрозрачные смешиваются с белым.
+func rgbPixels(img image.Image) []byte {
+	bounds := img.Bounds()
+	pixels := make([]byte, 0, 3*bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// RGBA отдаёт компоненты, уже умноженные на альфу: белый фон добавляет недостающее.
+			r, g, b, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			pixels = append(pixels, uint8((r+white)>>8), uint8((g+white)>>8), uint8((b+white)>>8))
+		}
+	}
+	return pixels
+}
+
+// textString — строка PDF в UTF-16BE с BOM: так заголовок с кириллицей читается любым просмотрщиком.
+func textString(s string) string {
+	b := []byte("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		b = fmt.Appendf(b, "%04X", unit)
+	}
+	return string(append(b, '>'))
+}
+
+// countingWriter считает записанные байты для таблицы xref и запоминает первую ошибку.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
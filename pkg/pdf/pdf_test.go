@@ -0,0 +1,251 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func newTestFont(t *testing.T) *Font {
+	t.Helper()
+	f, err := ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("ParseFont: %v", err)
+	}
+	return f
+}
+
+func buildTestDocument(t *testing.T, title string, lines ...string) []byte {
+	t.Helper()
+	f := newTestFont(t)
+	doc := New()
+	doc.SetTitle(title)
+	for i, line := range lines {
+		doc.Text(f, 12, 40, 60+float64(i)*16, line)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	doc.Image(img, 40, 200, 20, 20)
+	doc.AddPage()
+	doc.Line(10, 10, 100, 10, 1)
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	return data
+}
+
+// xrefOffsets разбирает таблицу xref, на которую указывает startxref. Последний элемент —
+// смещение самой таблицы: объекты пишутся по порядку, и каждый кончается там, где начинается следующий.
+func xrefOffsets(t *testing.T, data []byte) []int {
+	t.Helper()
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("document must end with the EOF marker")
+	}
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if match == nil {
+		t.Fatal("startxref not found")
+	}
+	start, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(data[start:], []byte("xref\n0 ")) {
+		t.Fatalf("startxref %d does not point to the xref table", start)
+	}
+
+	rest := data[start+len("xref\n0 "):]
+	header, rest, _ := bytes.Cut(rest, []byte("\n"))
+	count, err := strconv.Atoi(string(header))
+	if err != nil {
+		t.Fatalf("bad xref header %q", header)
+	}
+	offsets := make([]int, count)
+	for i := 0; i < count; i++ {
+		// Каждая запись — ровно 20 байт вместе с переводом строки
+		entry := string(rest[i*20 : (i+1)*20])
+		if i == 0 {
+			if entry != "0000000000 65535 f \n" {
+				t.Fatalf("bad free entry %q", entry)
+			}
+			continue
+		}
+		if !strings.HasSuffix(entry, " 00000 n \n") {
+			t.Fatalf("bad xref entry %d: %q", i, entry)
+		}
+		offsets[i], _ = strconv.Atoi(entry[:10])
+	}
+
+	trailer := string(rest[count*20:])
+	wantTrailer := fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", count, start)
+	if trailer != wantTrailer {
+		t.Fatalf("unexpected trailer %q", trailer)
+	}
+	return append(offsets, start)
+}
+
+// objectAt возвращает тело объекта n по смещению из xref.
+func objectAt(t *testing.T, data []byte, offsets []int, n int) []byte {
+	t.Helper()
+	header := fmt.Sprintf("%d 0 obj\n", n)
+	body := data[offsets[n]:]
+	if !bytes.HasPrefix(body, []byte(header)) {
+		t.Fatalf("xref offset of object %d points to %q", n, body[:min(len(body), 20)])
+	}
+	body = data[offsets[n]+len(header) : offsets[n+1]]
+	if !bytes.HasSuffix(body, []byte("\nendobj\n")) {
+		t.Fatalf("object %d does not end where object %d starts", n, n+1)
+	}
+	return bytes.TrimSuffix(body, []byte("\nendobj\n"))
+}
+
+var streamLength = regexp.MustCompile(`/Length (\d+) >>\nstream\n`)
+
+// streamData проверяет /Length потока и возвращает распакованные данные.
+func streamData(t *testing.T, object []byte) []byte {
+	t.Helper()
+	match := streamLength.FindSubmatchIndex(object)
+	if match == nil {
+		t.Fatalf("not a stream: %q", object[:min(len(object), 60)])
+	}
+	length, _ := strconv.Atoi(string(object[match[2]:match[3]]))
+	raw := object[match[1]:]
+	if !bytes.HasSuffix(raw, []byte("\nendstream")) || len(raw)-len("\nendstream") != length {
+		t.Fatalf("/Length %d does not match the stream of %d bytes", length, len(raw)-len("\nendstream"))
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(raw[:length]))
+	if err != nil {
+		t.Fatalf("zlib: %v", err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("zlib: %v", err)
+	}
+	return plain
+}
+
+func TestWrite_XrefPointsToEveryObject(t *testing.T) {
+	data := buildTestDocument(t, "Отчёт", "Заявка №1")
+	if !bytes.HasPrefix(data, []byte("%PDF-1.7\n")) {
+		t.Fatal("missing PDF header")
+	}
+	offsets := xrefOffsets(t, data)
+
+	// Каталог, дерево страниц, сведения, пять объектов шрифта, картинка и по два на страницу
+	if len(offsets)-1 != 1+3+5+1+2*2 {
+		t.Fatalf("unexpected object count %d", len(offsets))
+	}
+	for n := 1; n < len(offsets)-1; n++ {
+		object := objectAt(t, data, offsets, n)
+		if bytes.Contains(object, []byte("\nstream\n")) {
+			streamData(t, object)
+		}
+	}
+	if !bytes.Contains(objectAt(t, data, offsets, 1), []byte("/Type /Catalog /Pages 2 0 R")) {
+		t.Fatal("object 1 must be the catalog")
+	}
+	if !bytes.Contains(objectAt(t, data, offsets, 2), []byte("/Kids [10 0 R 12 0 R ] /Count 2")) {
+		t.Fatalf("unexpected page tree: %s", objectAt(t, data, offsets, 2))
+	}
+}
+
+func TestWrite_OffsetsSurviveBinaryStreams(t *testing.T) {
+	// Сжатые потоки содержат любые байты, в том числе переводы строк и «endobj» не в начале
+	long := strings.Repeat("Проверка смещений (xref) \\ ", 200)
+	data := buildTestDocument(t, long, long, long)
+	offsets := xrefOffsets(t, data)
+	for n := 1; n < len(offsets)-1; n++ {
+		objectAt(t, data, offsets, n)
+	}
+}
+
+func TestTitle_EscapesCyrillicAndDelimiters(t *testing.T) {
+	title := `Акт (копия) \ №1`
+	data := buildTestDocument(t, title)
+	info := objectAt(t, data, xrefOffsets(t, data), 3)
+
+	match := regexp.MustCompile(`/Title <FEFF([0-9A-F]*)>`).FindSubmatch(info)
+	if match == nil {
+		t.Fatalf("title must be a UTF-16BE hex string: %s", info)
+	}
+	if got := decodeUTF16Hex(t, string(match[1])); got != title {
+		t.Fatalf("title decoded as %q, want %q", got, title)
+	}
+	if bytes.ContainsAny(info[bytes.Index(info, []byte("/Title")):], `()\`) {
+		t.Fatalf("title must not contain raw delimiters: %s", info)
+	}
+}
+
+func TestText_EscapesCyrillicAndDelimiters(t *testing.T) {
+	line := `Заявка (срочно) \ путь C:\tmp\)`
+	data := buildTestDocument(t, "", line)
+	offsets := xrefOffsets(t, data)
+
+	content := string(streamData(t, objectAt(t, data, offsets, 11)))
+	match := regexp.MustCompile(`^BT /F1 12 Tf 40 781.89 Td <([0-9A-F]+)> Tj ET\n`).FindStringSubmatch(content)
+	if match == nil {
+		t.Fatalf("unexpected page content: %q", content)
+	}
+	if strings.ContainsAny(match[0], `()\`) {
+		t.Fatalf("text must be written as a hex string: %q", match[0])
+	}
+
+	// По ToUnicode текст восстанавливается символ в символ
+	cmap := string(streamData(t, objectAt(t, data, offsets, 7)))
+	_, cmap, _ = strings.Cut(cmap, "endcodespacerange\n")
+	toUnicode := map[string]string{}
+	for _, m := range regexp.MustCompile(`<([0-9A-F]{4})> <([0-9A-F]+)>`).FindAllStringSubmatch(cmap, -1) {
+		toUnicode[m[1]] = decodeUTF16Hex(t, m[2])
+	}
+	var got strings.Builder
+	for i := 0; i < len(match[1]); i += 4 {
+		r, ok := toUnicode[match[1][i:i+4]]
+		if !ok {
+			t.Fatalf("glyph %s is missing from ToUnicode", match[1][i:i+4])
+		}
+		got.WriteString(r)
+	}
+	if got.String() != line {
+		t.Fatalf("text decoded as %q, want %q", got.String(), line)
+	}
+
+	// Все глифы строки есть в таблице ширин
+	widths := string(objectAt(t, data, offsets, 5))
+	for code := range toUnicode {
+		gid, _ := strconv.ParseUint(code, 16, 16)
+		if !strings.Contains(widths, fmt.Sprintf(" %d [", gid)) && !strings.Contains(widths, fmt.Sprintf("[%d [", gid)) {
+			t.Fatalf("glyph %d is missing from /W", gid)
+		}
+	}
+}
+
+func TestNum_RoundsToHundredths(t *testing.T) {
+	cases := map[float64]string{0: "0", 12: "12", 841.89: "841.89", 1.005: "1", 0.125: "0.13", -3.5: "-3.5"}
+	for v, want := range cases {
+		if got := num(v); got != want {
+			t.Errorf("num(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func decodeUTF16Hex(t *testing.T, s string) string {
+	t.Helper()
+	raw, err := hex.DecodeString(s)
+	if err != nil || len(raw)%2 != 0 {
+		t.Fatalf("bad UTF-16 hex %q", s)
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = uint16(raw[2*i])<<8 | uint16(raw[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
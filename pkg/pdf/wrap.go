@@ -0,0 +1,45 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// Wrap разбивает текст на строки не шире width пунктов. Переносы строк в тексте
+// сохраняются; слово длиннее строки режется по символам.
+func Wrap(f *Font, size, width float64, text string) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if f.Width(candidate, size) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+			for f.Width(line, size) > width {
+				head, tail := splitToWidth(f, size, width, line)
+				lines = append(lines, head)
+				line = tail
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitToWidth отрезает от слова столько символов, сколько помещается в width (хотя бы один).
+func splitToWidth(f *Font, size, width float64, word string) (string, string) {
+	runes := []rune(word)
+	n := 1
+	for n < len(runes) && f.Width(string(runes[:n+1]), size) <= width {
+		n++
+	}
+	return string(runes[:n]), string(runes[n:])
+}
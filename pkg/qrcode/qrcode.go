@@ -0,0 +1,498 @@
+// Package qrcode — минимальный кодировщик QR (ISO/IEC 18004) в байтовом режиме.
+// Своя реализация, чтобы не тянуть внешнюю зависимость ради наклеек на оборудование.
+package qrcode
+
+import (
+	"errors"
+)
+
+// Level — уровень коррекции ошибок.
+type Level int
+
+const (
+	LevelL Level = iota // ~7%
+	LevelM              // ~15%
+	LevelQ              // ~25%
+	LevelH              // ~30%
+)
+
+// formatBits — биты уровня коррекции в строке формата (L=01, M=00, Q=11, H=10).
+var formatBits = [...]int{LevelL: 1, LevelM: 0, LevelQ: 3, LevelH: 2}
+
+var ErrTooLong = errors.New("qrcode: данные не помещаются в QR-код версии 40")
+
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code — готовая матрица QR-кода. true — тёмный модуль.
+type Code struct {
+	Size    int
+	modules [][]bool
+	// isFunction помечает служебные модули, которые не маскируются.
+	isFunction [][]bool
+}
+
+// Black сообщает, тёмный ли модуль (x — столбец, y — строка).
+// За пределами матрицы — светлый, это удобно для рисования отступа.
+func (c *Code) Black(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.modules[y][x]
+}
+
+// Encode кодирует данные в байтовом режиме с минимально подходящей версией и лучшей маской.
+func Encode(data []byte, level Level) (*Code, error) {
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if len(data) <= (numDataCodewords(v, level)*8-4-charCountBits(v))/8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	var bb bitBuffer
+	bb.append(0x4, 4) // байтовый режим
+	bb.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	capacity := numDataCodewords(version, level) * 8
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	dataCodewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		dataCodewords[i>>3] |= bit << (7 - uint(i&7))
+	}
+
+	c := newCode(version)
+	c.drawFunctionPatterns(version, level)
+	c.drawCodewords(addECCAndInterleave(dataCodewords, version, level))
+
+	bestMask, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(level, mask)
+		if p := c.penaltyScore(); minPenalty < 0 || p < minPenalty {
+			bestMask, minPenalty = mask, p
+		}
+		c.applyMask(mask) // XOR снимает маску обратно
+	}
+	c.applyMask(bestMask)
+	c.drawFormatBits(level, bestMask)
+	c.isFunction = nil
+	return c, nil
+}
+
+type bitBuffer []byte
+
+func (bb *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bb = append(*bb, byte(value>>uint(i)&1))
+	}
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{Size: size, modules: make([][]bool, size), isFunction: make([][]bool, size)}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.isFunction[i] = make([]bool, size)
+	}
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(version int, level Level) {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	positions := alignmentPositions(version)
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// Углы заняты поисковыми узорами.
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			c.drawAlignment(positions[i], positions[j])
+		}
+	}
+
+	// Резервируем место под формат (маска подставится позже) и рисуем версию.
+	c.drawFormatBits(level, 0)
+	c.drawVersion(version)
+}
+
+func (c *Code) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// formatInfo — 15 бит строки формата с кодом БЧХ и XOR-маской.
+func formatInfo(level Level, mask int) int {
+	data := formatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(level Level, mask int) {
+	bits := formatInfo(level, mask)
+	bit := func(i int) bool { return bits>>uint(i)&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true) // всегда тёмный модуль
+}
+
+// versionInfo — 18 бит блока версии (с 7-й) с кодом БЧХ.
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+func (c *Code) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+	bits := versionInfo(version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>uint(i)&1 != 0
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+func addECCAndInterleave(data []byte, version int, level Level) []byte {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	blockEccLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, 0, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		datLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		dat := data[k : k+datLen]
+		k += datLen
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, dat...)
+		if i < numShortBlocks {
+			block = append(block, 0) // выравнивание, при чередовании пропускается
+		}
+		block = append(block, reedSolomonRemainder(dat, divisor)...)
+		blocks = append(blocks, block)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortBlockLen; i++ {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply — умножение в GF(2^8) по модулю x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int(y>>uint(i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunction[y][x] && i < len(data)*8 {
+					c.modules[y][x] = data[i>>3]>>(7-uint(i&7))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunction[y][x] {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penaltyScore считает штраф по четырём правилам стандарта для выбора маски.
+func (c *Code) penaltyScore() int {
+	const (
+		penaltyN1 = 3
+		penaltyN2 = 3
+		penaltyN3 = 40
+		penaltyN4 = 10
+	)
+	result := 0
+	line := func(get func(i int) bool) {
+		runColor := false
+		runLen := 0
+		var history [7]int
+		for i := 0; i < c.Size; i++ {
+			if get(i) == runColor {
+				runLen++
+				if runLen == 5 {
+					result += penaltyN1
+				} else if runLen > 5 {
+					result++
+				}
+			} else {
+				c.finderPenaltyAddHistory(runLen, &history)
+				if !runColor {
+					result += c.finderPenaltyCountPatterns(&history) * penaltyN3
+				}
+				runColor = get(i)
+				runLen = 1
+			}
+		}
+		result += c.finderPenaltyTerminateAndCount(runColor, runLen, &history) * penaltyN3
+	}
+	for y := 0; y < c.Size; y++ {
+		line(func(x int) bool { return c.modules[y][x] })
+	}
+	for x := 0; x < c.Size; x++ {
+		line(func(y int) bool { return c.modules[y][x] })
+	}
+
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			color := c.modules[y][x]
+			if color == c.modules[y][x+1] && color == c.modules[y+1][x] && color == c.modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	dark := 0
+	for _, row := range c.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+	return result
+}
+
+func (c *Code) finderPenaltyCountPatterns(history *[7]int) int {
+	n := history[1]
+	core := n > 0 && history[2] == n && history[3] == n*3 && history[4] == n && history[5] == n
+	count := 0
+	if core && history[0] >= n*4 && history[6] >= n {
+		count++
+	}
+	if core && history[6] >= n*4 && history[0] >= n {
+		count++
+	}
+	return count
+}
+
+func (c *Code) finderPenaltyTerminateAndCount(runColor bool, runLen int, history *[7]int) int {
+	if runColor {
+		c.finderPenaltyAddHistory(runLen, history)
+		runLen = 0
+	}
+	runLen += c.Size
+	c.finderPenaltyAddHistory(runLen, history)
+	return c.finderPenaltyCountPatterns(history)
+}
+
+func (c *Code) finderPenaltyAddHistory(runLen int, history *[7]int) {
+	if history[0] == 0 {
+		runLen += c.Size // светлая граница перед первой серией
+	}
+	copy(history[1:], history[:6])
+	history[0] = runLen
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
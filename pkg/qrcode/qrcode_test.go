@@ -0,0 +1,395 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// Строки формата из приложения C ISO/IEC 18004: [уровень][маска].
+var knownFormatInfo = [4][8]int{
+	LevelL: {0x77C4, 0x72F3, 0x7DAA, 0x789D, 0x662F, 0x6318, 0x6C41, 0x6976},
+	LevelM: {0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0},
+	LevelQ: {0x355F, 0x3068, 0x3F31, 0x3A06, 0x24B4, 0x2183, 0x2EDA, 0x2BED},
+	LevelH: {0x1689, 0x13BE, 0x1CE7, 0x19D0, 0x0762, 0x0255, 0x0D0C, 0x083B},
+}
+
+func TestFormatInfo_KnownAnswers(t *testing.T) {
+	for level := LevelL; level <= LevelH; level++ {
+		for mask := 0; mask < 8; mask++ {
+			if got := formatInfo(level, mask); got != knownFormatInfo[level][mask] {
+				t.Errorf("level %d mask %d: got %#04x, want %#04x", level, mask, got, knownFormatInfo[level][mask])
+			}
+		}
+	}
+}
+
+func TestVersionInfo_KnownAnswers(t *testing.T) {
+	// Приложение D ISO/IEC 18004
+	known := map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3, 20: 0x149A6, 40: 0x28C69}
+	for version, want := range known {
+		if got := versionInfo(version); got != want {
+			t.Errorf("version %d: got %#05x, want %#05x", version, got, want)
+		}
+	}
+}
+
+func TestAlignmentPositions_KnownAnswers(t *testing.T) {
+	known := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		14: {6, 26, 46, 66},
+		32: {6, 34, 60, 86, 112, 138},
+		36: {6, 24, 50, 76, 102, 128, 154},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for version, want := range known {
+		got := alignmentPositions(version)
+		if len(got) != len(want) {
+			t.Errorf("version %d: got %v, want %v", version, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("version %d: got %v, want %v", version, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestReedSolomon_StandardExample(t *testing.T) {
+	// Пример приложения I ISO/IEC 18004: «01234567», версия 1-M
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Fatalf("got % X, want % X", got, want)
+	}
+}
+
+func TestEncode_ChoosesSmallestVersion(t *testing.T) {
+	// Ёмкость байтового режима по таблице 7 ISO/IEC 18004
+	cases := []struct {
+		level    Level
+		version  int
+		capacity int
+	}{
+		{LevelL, 1, 17}, {LevelM, 1, 14}, {LevelQ, 1, 11}, {LevelH, 1, 7},
+		{LevelL, 2, 32}, {LevelM, 2, 26}, {LevelQ, 2, 20}, {LevelH, 2, 14},
+		{LevelL, 9, 230}, {LevelM, 10, 213}, {LevelQ, 10, 151}, {LevelH, 10, 119},
+		{LevelL, 40, 2953}, {LevelM, 40, 2331}, {LevelQ, 40, 1663}, {LevelH, 40, 1273},
+	}
+	for _, tc := range cases {
+		code, err := Encode(bytes.Repeat([]byte{'a'}, tc.capacity), tc.level)
+		if err != nil {
+			t.Fatalf("level %d, %d bytes: %v", tc.level, tc.capacity, err)
+		}
+		if code.Size != tc.version*4+17 {
+			t.Errorf("level %d, %d bytes: got size %d, want version %d", tc.level, tc.capacity, code.Size, tc.version)
+		}
+
+		code, err = Encode(bytes.Repeat([]byte{'a'}, tc.capacity+1), tc.level)
+		if tc.version == 40 {
+			if !errors.Is(err, ErrTooLong) {
+				t.Errorf("level %d, %d bytes: expected ErrTooLong, got %v", tc.level, tc.capacity+1, err)
+			}
+			continue
+		}
+		if err != nil || code.Size != (tc.version+1)*4+17 {
+			t.Errorf("level %d, %d bytes must take version %d", tc.level, tc.capacity+1, tc.version+1)
+		}
+	}
+}
+
+func TestApplyMask_MatchesStandardPatterns(t *testing.T) {
+	// Условия масок из таблицы 10 ISO/IEC 18004: i — строка, j — столбец
+	patterns := [8]func(i, j int) bool{
+		func(i, j int) bool { return (i+j)%2 == 0 },
+		func(i, j int) bool { return i%2 == 0 },
+		func(i, j int) bool { return j%3 == 0 },
+		func(i, j int) bool { return (i+j)%3 == 0 },
+		func(i, j int) bool { return (i/2+j/3)%2 == 0 },
+		func(i, j int) bool { return i*j%2+i*j%3 == 0 },
+		func(i, j int) bool { return (i*j%2+i*j%3)%2 == 0 },
+		func(i, j int) bool { return ((i+j)%2+i*j%3)%2 == 0 },
+	}
+	for mask, pattern := range patterns {
+		c := newCode(1)
+		c.applyMask(mask)
+		for i := 0; i < c.Size; i++ {
+			for j := 0; j < c.Size; j++ {
+				if c.modules[i][j] != pattern(i, j) {
+					t.Fatalf("mask %d: module row %d col %d differs from the standard", mask, i, j)
+				}
+			}
+		}
+	}
+}
+
+func TestEncode_DecodesBack(t *testing.T) {
+	cases := []struct {
+		data  string
+		level Level
+	}{
+		{"https://example.com/assets/42", LevelM},
+		{"Инв. № 000123, кабинет 305", LevelQ},
+		{string(bytes.Repeat([]byte("0123456789"), 30)), LevelH},
+		{string(bytes.Repeat([]byte("ABCDEFGHIJ"), 100)), LevelL},
+	}
+	for _, tc := range cases {
+		code, err := Encode([]byte(tc.data), tc.level)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		level, got := decodeForTest(t, code)
+		if level != tc.level {
+			t.Errorf("format level: got %d, want %d", level, tc.level)
+		}
+		if string(got) != tc.data {
+			t.Errorf("decoded %q, want %q", got, tc.data)
+		}
+	}
+}
+
+func TestEncode_DrawsFunctionPatterns(t *testing.T) {
+	code, err := Encode([]byte("hello"), LevelM)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	finder := []string{
+		"#######.",
+		"#.....#.",
+		"#.###.#.",
+		"#.###.#.",
+		"#.###.#.",
+		"#.....#.",
+		"#######.",
+		"........",
+	}
+	for y, row := range finder {
+		for x, ch := range row {
+			want := ch == '#'
+			// Все три поисковых узора одинаковы с разделителем со стороны данных
+			if code.Black(x, y) != want || code.Black(code.Size-1-x, y) != want || code.Black(x, code.Size-1-y) != want {
+				t.Fatalf("finder pattern differs at %d,%d", x, y)
+			}
+		}
+	}
+	for i := 8; i < code.Size-8; i++ {
+		if code.Black(i, 6) != (i%2 == 0) || code.Black(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern differs at %d", i)
+		}
+	}
+	if !code.Black(8, code.Size-8) {
+		t.Fatal("dark module must be set")
+	}
+	if code.Black(-1, 0) || code.Black(0, code.Size) {
+		t.Fatal("modules outside the matrix must be light")
+	}
+}
+
+// decodeForTest читает код так, как его читал бы сканер: уровень и маска по таблице формата,
+// служебные области по стандарту, проверка блоков по синдромам Рида — Соломона.
+func decodeForTest(t *testing.T, c *Code) (Level, []byte) {
+	t.Helper()
+	version := (c.Size - 17) / 4
+
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= bitOf(c.Black(8, i)) << i
+	}
+	first |= bitOf(c.Black(8, 7))<<6 | bitOf(c.Black(8, 8))<<7 | bitOf(c.Black(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		first |= bitOf(c.Black(14-i, 8)) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= bitOf(c.Black(c.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= bitOf(c.Black(8, c.Size-15+i)) << i
+	}
+	if first != second {
+		t.Fatalf("format copies differ: %#04x and %#04x", first, second)
+	}
+	level, mask := Level(-1), -1
+	for l := range knownFormatInfo {
+		for m, info := range knownFormatInfo[l] {
+			if info == first {
+				level, mask = Level(l), m
+			}
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format %#04x is not a valid format string", first)
+	}
+	if version >= 7 {
+		var bits int
+		for i := 0; i < 18; i++ {
+			bits |= bitOf(c.Black(c.Size-11+i%3, i/3)) << i
+		}
+		if bits != versionInfo(version) {
+			t.Fatalf("version block %#05x does not match version %d", bits, version)
+		}
+	}
+
+	reserved := functionAreaForTest(c.Size, version)
+	var codewords []byte
+	var cur, n int
+	upward := true
+	for right := c.Size - 1; right >= 1; right, upward = right-2, !upward {
+		if right == 6 {
+			right--
+		}
+		for k := 0; k < c.Size; k++ {
+			y := k
+			if upward {
+				y = c.Size - 1 - k
+			}
+			for x := right; x >= right-1; x-- {
+				if reserved[y][x] {
+					continue
+				}
+				bit := c.Black(x, y) != maskForTest(mask, y, x)
+				cur = cur<<1 | bitOf(bit)
+				if n++; n%8 == 0 {
+					codewords = append(codewords, byte(cur))
+					cur = 0
+				}
+			}
+		}
+	}
+
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	eccLen := eccCodewordsPerBlock[level][version]
+	total := numRawDataModules(version) / 8
+	if len(codewords) != total {
+		t.Fatalf("read %d codewords, version %d holds %d", len(codewords), version, total)
+	}
+	numShort := numBlocks - total%numBlocks
+	shortData := total/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortData; i++ {
+		for b := range blocks {
+			if i < shortData || b >= numShort {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[k])
+			k++
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		if !syndromesZero(block, eccLen) {
+			t.Fatalf("block %d fails the Reed-Solomon check", b)
+		}
+		data = append(data, block[:len(block)-eccLen]...)
+	}
+
+	readBits := func(pos, length int) int {
+		v := 0
+		for i := pos; i < pos+length; i++ {
+			v = v<<1 | int(data[i/8]>>(7-uint(i%8))&1)
+		}
+		return v
+	}
+	if mode := readBits(0, 4); mode != 0x4 {
+		t.Fatalf("mode %04b, want byte mode", mode)
+	}
+	countBits := 8
+	if version > 9 {
+		countBits = 16
+	}
+	length := readBits(4, countBits)
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = byte(readBits(4+countBits+i*8, 8))
+	}
+	return level, result
+}
+
+func bitOf(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func maskForTest(mask, i, j int) bool {
+	switch mask {
+	case 0:
+		return (i+j)%2 == 0
+	case 1:
+		return i%2 == 0
+	case 2:
+		return j%3 == 0
+	case 3:
+		return (i+j)%3 == 0
+	case 4:
+		return (i/2+j/3)%2 == 0
+	case 5:
+		return i*j%2+i*j%3 == 0
+	case 6:
+		return (i*j%2+i*j%3)%2 == 0
+	}
+	return ((i+j)%2+i*j%3)%2 == 0
+}
+
+// functionAreaForTest размечает служебные модули по описанию стандарта: поисковые узоры
+// с разделителями и форматом, синхронизация, выравнивающие узоры, блоки версии.
+func functionAreaForTest(size, version int) [][]bool {
+	area := make([][]bool, size)
+	for i := range area {
+		area[i] = make([]bool, size)
+	}
+	fill := func(x0, y0, w, h int) {
+		for y := y0; y < y0+h; y++ {
+			for x := x0; x < x0+w; x++ {
+				area[y][x] = true
+			}
+		}
+	}
+	fill(0, 0, 9, 9)
+	fill(size-8, 0, 8, 9)
+	fill(0, size-8, 9, 8)
+	positions := alignmentPositions(version)
+	for _, cy := range positions {
+		for _, cx := range positions {
+			// Выравнивающий узор не ставится поверх поисковых
+			if area[cy][cx] {
+				continue
+			}
+			fill(cx-2, cy-2, 5, 5)
+		}
+	}
+	fill(6, 0, 1, size)
+	fill(0, 6, size, 1)
+	if version >= 7 {
+		fill(size-11, 0, 3, 6)
+		fill(0, size-11, 6, 3)
+	}
+	return area
+}
+
+// syndromesZero проверяет, что блок делится на порождающий многочлен: его значения
+// в корнях α^0…α^(ecc-1) равны нулю.
+func syndromesZero(block []byte, eccLen int) bool {
+	root := byte(1)
+	for i := 0; i < eccLen; i++ {
+		var v byte
+		for _, b := range block {
+			v = gfMultiply(v, root) ^ b
+		}
+		if v != 0 {
+			return false
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return true
+}
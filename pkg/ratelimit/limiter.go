@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Rule — ведро на Limit запросов, которое полностью восполняется за Period.
+// Limit заодно задаёт допустимый всплеск.
+type Rule struct {
+	Limit  int
+	Period time.Duration
+}
+
+func (r Rule) Enabled() bool {
+	return r.Limit > 0 && r.Period > 0
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Period)
+}
+
+// ParseRule разбирает правило вида "10/1m" или "300/60s". Пустая строка и "0" выключают лимит.
+func ParseRule(raw string) (Rule, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "0" {
+		return Rule{}, nil
+	}
+	limitPart, periodPart, ok := strings.Cut(raw, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("лимит %q должен иметь вид <число>/<период>", raw)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(limitPart))
+	if err != nil || limit < 0 {
+		return Rule{}, fmt.Errorf("некорректное число запросов в лимите %q", raw)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(periodPart))
+	if err != nil || period <= 0 {
+		return Rule{}, fmt.Errorf("некорректный период в лимите %q", raw)
+	}
+	return Rule{Limit: limit, Period: period}, nil
+}
+
+// Result — итог попытки взять токен.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// tokenBucketScript хранит в хэше остаток токенов и момент последнего пополнения.
+// Время берётся из Redis, чтобы часы реплик не влияли на счёт.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local period_ms = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local rate = capacity / period_ms
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+if now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) * rate)
+end
+
+local allowed = 0
+local retry_ms = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_ms = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], period_ms + 1000)
+return {allowed, math.floor(tokens), retry_ms}
+`)
+
+// Limiter — token bucket в Redis, общий для всех реплик приложения.
+type Limiter struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewLimiter(client *redis.Client, prefix string) *Limiter {
+	return &Limiter{client: client, prefix: prefix}
+}
+
+// Allow списывает один токен из ведра key. Выключенное правило пропускает всё.
+func (l *Limiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	if !rule.Enabled() {
+		return Result{Allowed: true}, nil
+	}
+
+	raw, err := tokenBucketScript.Run(ctx, l.client, []string{l.prefix + ":" + key}, rule.Limit, rule.Period.Milliseconds()).Result()
+	if err != nil {
+		return Result{}, err
+	}
+	values, ok := raw.([]interface{})
+	if !ok || len(values) != 3 {
+		return Result{}, fmt.Errorf("неожиданный ответ скрипта лимитера: %v", raw)
+	}
+
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	retryMs, _ := values[2].(int64)
+	return Result{
+		Allowed:    allowed == 1,
+		Remaining:  int(remaining),
+		RetryAfter: time.Duration(retryMs) * time.Millisecond,
+	}, nil
+}
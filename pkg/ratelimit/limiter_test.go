@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestLimiter(t *testing.T) (*Limiter, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	// Скрипт берёт время из Redis: фиксируем его, чтобы пополнение было предсказуемым
+	server.SetTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewLimiter(client, "rl"), server
+}
+
+func allowN(t *testing.T, l *Limiter, key string, rule Rule, n int) []Result {
+	t.Helper()
+	results := make([]Result, n)
+	for i := range results {
+		res, err := l.Allow(context.Background(), key, rule)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		results[i] = res
+	}
+	return results
+}
+
+func TestParseRule(t *testing.T) {
+	valid := map[string]Rule{
+		"10/1m":    {Limit: 10, Period: time.Minute},
+		"300/60s":  {Limit: 300, Period: time.Minute},
+		" 5 / 2s ": {Limit: 5, Period: 2 * time.Second},
+		"":         {},
+		"0":        {},
+		"0/1m":     {Period: time.Minute},
+		"1/1500ms": {Limit: 1, Period: 1500 * time.Millisecond},
+	}
+	for raw, want := range valid {
+		got, err := ParseRule(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseRule(%q) = %+v, %v; want %+v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"10", "x/1m", "-1/1m", "10/0s", "10/-1s", "10/abc", "10/"} {
+		if _, err := ParseRule(raw); err == nil {
+			t.Errorf("ParseRule(%q) must fail", raw)
+		}
+	}
+	if rule, _ := ParseRule("0/1m"); rule.Enabled() {
+		t.Error("a zero limit must disable the rule")
+	}
+}
+
+func TestAllow_DisabledRuleSkipsRedis(t *testing.T) {
+	// Без клиента: выключенное правило не должно обращаться к Redis
+	l := NewLimiter(nil, "rl")
+	res, err := l.Allow(context.Background(), "ip:1", Rule{})
+	if err != nil || !res.Allowed {
+		t.Fatalf("disabled rule must allow: %+v, %v", res, err)
+	}
+}
+
+func TestAllow_BurstThenDeny(t *testing.T) {
+	l, _ := newTestLimiter(t)
+	rule := Rule{Limit: 3, Period: 3 * time.Second}
+
+	results := allowN(t, l, "ip:1", rule, 4)
+	for i, want := range []int{2, 1, 0} {
+		if !results[i].Allowed || results[i].Remaining != want || results[i].RetryAfter != 0 {
+			t.Fatalf("request %d: %+v", i+1, results[i])
+		}
+	}
+	// Токен восполняется раз в Period/Limit
+	if denied := results[3]; denied.Allowed || denied.Remaining != 0 || denied.RetryAfter != time.Second {
+		t.Fatalf("request over the burst must be denied for 1s: %+v", denied)
+	}
+}
+
+func TestAllow_RefillsOverTime(t *testing.T) {
+	l, server := newTestLimiter(t)
+	rule := Rule{Limit: 3, Period: 3 * time.Second}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	allowN(t, l, "ip:1", rule, 3)
+
+	// За половину интервала токен ещё не накопился
+	server.SetTime(start.Add(500 * time.Millisecond))
+	if res := allowN(t, l, "ip:1", rule, 1)[0]; res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("half a token is not enough: %+v", res)
+	}
+
+	server.SetTime(start.Add(time.Second))
+	results := allowN(t, l, "ip:1", rule, 2)
+	if !results[0].Allowed || results[1].Allowed {
+		t.Fatalf("exactly one token must be refilled: %+v", results)
+	}
+
+	// Долгий простой не даёт больше Limit токенов
+	server.SetTime(start.Add(time.Hour))
+	results = allowN(t, l, "ip:1", rule, 4)
+	if !results[2].Allowed || results[3].Allowed {
+		t.Fatalf("refill must be capped by the limit: %+v", results)
+	}
+}
+
+func TestAllow_ClockGoingBackDoesNotRefill(t *testing.T) {
+	l, server := newTestLimiter(t)
+	rule := Rule{Limit: 2, Period: time.Minute}
+	allowN(t, l, "ip:1", rule, 2)
+
+	server.SetTime(time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC))
+	if res := allowN(t, l, "ip:1", rule, 1)[0]; res.Allowed {
+		t.Fatalf("time going back must not add tokens: %+v", res)
+	}
+}
+
+func TestAllow_KeysAreIndependentAndExpire(t *testing.T) {
+	l, server := newTestLimiter(t)
+	rule := Rule{Limit: 1, Period: 10 * time.Second}
+
+	if res := allowN(t, l, "ip:1", rule, 2); !res[0].Allowed || res[1].Allowed {
+		t.Fatalf("unexpected results for ip:1: %+v", res)
+	}
+	if res := allowN(t, l, "ip:2", rule, 1)[0]; !res.Allowed {
+		t.Fatal("another key must have its own bucket")
+	}
+
+	if ttl := server.TTL("rl:ip:1"); ttl != 11*time.Second {
+		t.Fatalf("bucket must expire a second after a full refill, got %s", ttl)
+	}
+	server.FastForward(11 * time.Second)
+	if server.Exists("rl:ip:1") {
+		t.Fatal("idle bucket must be removed")
+	}
+}
@@ -0,0 +1,110 @@
+// Package sanitize — единое место для очистки пользовательского ввода и экранирования текста
+// под канал вывода. Ввод чистится один раз при сохранении (Text, Line), а экранируется каждый раз
+// при выводе: HTML — для веб-уведомлений, MarkdownV2 — для Telegram, Slack — для mrkdwn.
+package sanitize
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var markdownV2Replacer = strings.NewReplacer(
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]",
+	"(", "\\(", ")", "\\)", "\\", "\\\\",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+",
+	"-", "\\-", "=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
+
+var slackReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Text очищает многострочный ввод (комментарии, описания): некорректный UTF-8 заменяется,
+// \r\n и \r становятся \n, управляющие и невидимые символы направления письма удаляются
+// (ими подменяют порядок символов в ФИО и ссылках), пробелы по краям обрезаются.
+func Text(s string) string {
+	return clean(s, true)
+}
+
+// Line — Text для однострочных полей (название заявки, адрес, ФИО): переводы строк
+// и табуляция заменяются пробелом.
+func Line(s string) string {
+	return clean(s, false)
+}
+
+// TextPtr и LinePtr — то же для необязательных полей DTO; nil остаётся nil.
+func TextPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	cleaned := Text(*s)
+	return &cleaned
+}
+
+func LinePtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	cleaned := Line(*s)
+	return &cleaned
+}
+
+// HTML экранирует текст для вставки в HTML-шаблон (<strong>%s</strong> веб-уведомлений).
+func HTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// MarkdownV2 экранирует текст для Telegram parse_mode=MarkdownV2, в том числе внутри `кода`.
+func MarkdownV2(s string) string {
+	return markdownV2Replacer.Replace(s)
+}
+
+// Slack экранирует управляющие символы mrkdwn, чтобы текст не превратился в ссылки и упоминания.
+func Slack(s string) string {
+	return slackReplacer.Replace(s)
+}
+
+// Truncate обрезает текст до max символов (не байт), добавляя «…»; UTF-8 не разрезается.
+func Truncate(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRightFunc(string(runes[:max-1]), unicode.IsSpace) + "…"
+}
+
+func clean(s string, multiline bool) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\r':
+			if multiline {
+				b.WriteRune('\n')
+			} else {
+				b.WriteRune(' ')
+			}
+		case r == '\t':
+			if multiline {
+				b.WriteRune('\t')
+			} else {
+				b.WriteRune(' ')
+			}
+		case isBidiControl(r) || r == '\u200B' || r == '\uFEFF':
+			// невидимые символы не несут смысла в заявке
+		case unicode.IsControl(r):
+			// прочие управляющие символы отбрасываются
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// isBidiControl — символы, меняющие направление письма (U+202A–U+202E, U+2066–U+2069).
+func isBidiControl(r rune) bool {
+	return (r >= '\u202A' && r <= '\u202E') || (r >= '\u2066' && r <= '\u2069')
+}
@@ -0,0 +1,117 @@
+package sanitize
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestText(t *testing.T) {
+	cases := map[string]string{
+		"  Не работает принтер  ":                "Не работает принтер",
+		"строка 1\r\nстрока 2\rстрока 3":         "строка 1\nстрока 2\nстрока 3",
+		"отступ\tтабуляцией":                     "отступ\tтабуляцией",
+		"звонок\x07 и \x00нуль\x1b[31m":          "звонок и нуль[31m",
+		"счёт\u202Etxt.exe":                      "счётtxt.exe",
+		"\u2066Иванов\u2069 \u200BИ.\uFEFF":      "Иванов И.",
+		"битый \xff\xfe байт":                    "битый \uFFFD байт",
+		"\n\n  \t":                               "",
+		"<b>HTML не трогается</b> & [ссылки](x)": "<b>HTML не трогается</b> & [ссылки](x)",
+	}
+	for in, want := range cases {
+		if got := Text(in); got != want {
+			t.Errorf("Text(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+	cases := map[string]string{
+		"Замена\r\nкартриджа\tв 305": "Замена картриджа в 305",
+		"ул.\u202AЛенина\u202C, 1\n": "ул.Ленина, 1",
+		"\x7fПетров\u0085":           "Петров",
+	}
+	for in, want := range cases {
+		if got := Line(in); got != want {
+			t.Errorf("Line(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPtrKeepsNil(t *testing.T) {
+	if TextPtr(nil) != nil || LinePtr(nil) != nil {
+		t.Fatal("nil must stay nil")
+	}
+	s := " a\r\nb "
+	if got := *TextPtr(&s); got != "a\nb" {
+		t.Errorf("TextPtr = %q", got)
+	}
+	if got := *LinePtr(&s); got != "a b" {
+		t.Errorf("LinePtr = %q", got)
+	}
+	if s != " a\r\nb " {
+		t.Error("the original value must not change")
+	}
+}
+
+func TestHTML(t *testing.T) {
+	in := `<script>alert("x")</script> & 'Иванов'`
+	want := "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &#39;Иванов&#39;"
+	if got := HTML(in); got != want {
+		t.Errorf("HTML = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownV2(t *testing.T) {
+	// Все символы, которые Telegram требует экранировать, и сама обратная косая черта
+	in := "_*[]()~`>#+-=|{}.!\\"
+	want := `\_\*\[\]\(\)\~\` + "`" + `\>\#\+\-\=\|\{\}\.\!\\`
+	if got := MarkdownV2(in); got != want {
+		t.Errorf("MarkdownV2(%q) = %q, want %q", in, got, want)
+	}
+	if got := MarkdownV2("Заявка №12 готова"); got != "Заявка №12 готова" {
+		t.Errorf("plain text must not change, got %q", got)
+	}
+	// Уже экранированный текст экранируется ещё раз, а не пропускается
+	if got := MarkdownV2(`\.`); got != `\\\.` {
+		t.Errorf("MarkdownV2 must escape backslashes first, got %q", got)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	in := "<!channel> <@U123|admin> a & b > c"
+	want := "&lt;!channel&gt; &lt;@U123|admin&gt; a &amp; b &gt; c"
+	if got := Slack(in); got != want {
+		t.Errorf("Slack = %q, want %q", got, want)
+	}
+	if got := Slack("&amp;"); got != "&amp;amp;" {
+		t.Errorf("ampersands must always be escaped, got %q", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"Короткий", 10, "Короткий"},
+		{"Ровно", 5, "Ровно"},
+		{"Принтер не печатает", 9, "Принтер…"},
+		{"Принтер не печатает", 11, "Принтер не…"},
+		{"абв", 1, "…"},
+		{"без ограничения", 0, "без ограничения"},
+		{"без ограничения", -1, "без ограничения"},
+	}
+	for _, tc := range cases {
+		got := Truncate(tc.in, tc.max)
+		if got != tc.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tc.in, tc.max, got, tc.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("Truncate(%q, %d) cut a rune", tc.in, tc.max)
+		}
+		if tc.max > 0 && utf8.RuneCountInString(got) > tc.max {
+			t.Errorf("Truncate(%q, %d) is longer than the limit: %q", tc.in, tc.max, got)
+		}
+	}
+}
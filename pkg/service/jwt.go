@@ -14,11 +14,19 @@ type JwtCustomClaim struct {
 	UserID         uint64 `json:"userID"`
 	RoleID         uint64 `json:"roleID,omitempty"` // roleID может быть 0, поэтому omitempty
 	IsRefreshToken bool
+	// ImpersonatorID — администратор, выпустивший токен для входа под этим пользователем.
+	ImpersonatorID uint64 `json:"impersonatorID,omitempty"`
+	// TenantID — организация пользователя. В токенах, выпущенных до разделения на организации,
+	// его нет: такие запросы относятся к организации по умолчанию.
+	TenantID uint64 `json:"tenantID,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTService interface {
-	GenerateTokens(userID uint64, roleID uint64, accessTokenTTL, refreshTokenTTL time.Duration) (string, string, error)
+	GenerateTokens(userID, roleID, tenantID uint64, accessTokenTTL, refreshTokenTTL time.Duration) (string, string, error)
+	// GenerateImpersonationToken выпускает access-токен пользователя userID для администратора
+	// impersonatorID. Refresh-токен не выдаётся: по истечении ttl сессия заканчивается.
+	GenerateImpersonationToken(userID, impersonatorID, tenantID uint64, ttl time.Duration) (string, time.Time, error)
 	ValidateToken(tokenString string) (*JwtCustomClaim, error)
 	ValidateRefreshToken(tokenString string) (uint64, error)
 	GetAccessTokenTTL() time.Duration
@@ -41,7 +49,7 @@ func NewJWTService(secretKey string, accessTokenExp, refreshTokenExp time.Durati
 	}
 }
 
-func (s *jwtService) GenerateTokens(userID uint64, roleID uint64, accessTokenTTL, refreshTokenTTL time.Duration) (string, string, error) {
+func (s *jwtService) GenerateTokens(userID, roleID, tenantID uint64, accessTokenTTL, refreshTokenTTL time.Duration) (string, string, error) {
 	accessTokenExp := time.Now().UTC().Add(accessTokenTTL)
 	refreshTokenExp := time.Now().UTC().Add(refreshTokenTTL)
 	issuedAt := time.Now().UTC()
@@ -49,6 +57,7 @@ func (s *jwtService) GenerateTokens(userID uint64, roleID uint64, accessTokenTTL
 	accessTokenClaims := &JwtCustomClaim{
 		UserID:         userID,
 		RoleID:         roleID, // roleID может быть 0, это нормально
+		TenantID:       tenantID,
 		IsRefreshToken: false,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessTokenExp),
@@ -59,6 +68,7 @@ func (s *jwtService) GenerateTokens(userID uint64, roleID uint64, accessTokenTTL
 	refreshTokenClaims := &JwtCustomClaim{
 		UserID:         userID,
 		RoleID:         roleID, // roleID может быть 0
+		TenantID:       tenantID,
 		IsRefreshToken: true,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(refreshTokenExp),
@@ -81,6 +91,27 @@ func (s *jwtService) GenerateTokens(userID uint64, roleID uint64, accessTokenTTL
 	return accessTokenString, refreshTokenString, nil
 }
 
+func (s *jwtService) GenerateImpersonationToken(userID, impersonatorID, tenantID uint64, ttl time.Duration) (string, time.Time, error) {
+	issuedAt := time.Now().UTC()
+	expiresAt := issuedAt.Add(ttl)
+
+	claims := &JwtCustomClaim{
+		UserID:         userID,
+		ImpersonatorID: impersonatorID,
+		TenantID:       tenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte(s.SecretKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
 func (s *jwtService) ValidateToken(tokenString string) (*JwtCustomClaim, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JwtCustomClaim{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
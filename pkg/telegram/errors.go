@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError — ошибка, которую вернул сам Telegram Bot API (ok=false).
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	// RetryAfter — сколько Telegram просит подождать после 429 (parameters.retry_after).
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error (%s): code %d, description: %s", e.Method, e.Code, e.Description)
+}
+
+// IsPermanentError сообщает, что повтор запроса не поможет: бот заблокирован пользователем,
+// чат не найден или сообщение некорректно. Сетевые ошибки, 429 и 5xx считаются временными.
+func IsPermanentError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// IsThrottled сообщает, что вызов упёрся в лимиты бота: Telegram ответил 429, очередь
+// переполнена или цепь разомкнута. Повтор позже поможет, а повтор сразу — нет.
+func IsThrottled(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrSendQueueFull) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests
+}
@@ -2,15 +2,14 @@
 package telegram
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"request-system/pkg/sanitize"
 )
 
 // --- ОСНОВНОЙ ИНТЕРФЕЙС СЕРВИСА ---
@@ -26,6 +25,9 @@ type ServiceInterface interface {
 	EditMessageText(ctx context.Context, chatID int64, messageID int, text string, options ...MessageOption) error
 	EditOrSendMessage(ctx context.Context, chatID int64, messageID int, text string, options ...MessageOption) error
 	DeleteMessage(ctx context.Context, chatID int64, messageID int) error
+
+	GetFile(ctx context.Context, fileID string) (*File, error)
+	DownloadFile(ctx context.Context, filePath string, maxBytes int64) ([]byte, error)
 }
 
 // --- СТРУКТУРА СЕРВИСА ---
@@ -34,15 +36,19 @@ type Service struct {
 	botToken   string
 	httpClient *http.Client
 	debug      bool
+	gate       *sendGate
 }
 
-func NewService(botToken string) ServiceInterface {
+// NewService создаёт клиент Bot API. Экземпляры с одним токеном делят очередь и автомат;
+// limits применяются при первом создании клиента для токена.
+func NewService(botToken string, limits Limits) ServiceInterface {
 	debug := strings.Contains(strings.ToLower(os.Getenv("DEBUG")), "telegram")
 
 	return &Service{
 		botToken:   botToken,
 		httpClient: &http.Client{Timeout: 15 * time.Second},
 		debug:      debug,
+		gate:       gateFor(botToken, limits),
 	}
 }
 
@@ -61,7 +67,9 @@ type inlineKeyboardMarkup struct {
 
 type InlineKeyboardButton struct {
 	Text         string `json:"text"`
-	CallbackData string `json:"callback_data"`
+	CallbackData string `json:"callback_data,omitempty"`
+	// URL — кнопка-ссылка; Telegram требует ровно одно из CallbackData/URL.
+	URL string `json:"url,omitempty"`
 }
 type ReplyKeyboardButton struct {
 	Text string `json:"text"`
@@ -197,64 +205,14 @@ func (s *Service) AnswerCallbackQuery(ctx context.Context, callbackQueryID strin
 // --- ВСПОМОГАТЕЛЬНЫЕ ФУНКЦИИ ---
 
 func (s *Service) sendRequest(ctx context.Context, methodName string, payload interface{}) error {
-	if s.botToken == "" {
-		return fmt.Errorf("токен Telegram-бота не установлен")
-	}
-
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", s.botToken, methodName)
-
-	reqBody, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("ошибка сериализации JSON: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return fmt.Errorf("ошибка создания запроса: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("ошибка отправки запроса в Telegram: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if s.debug {
-		fmt.Printf("[telegram] %s → %s\nRequest: %s\nResponse: %s\n\n", methodName, apiURL, string(reqBody), string(body))
-	}
-
-	// Telegram всегда возвращает 200 OK, даже при ошибках
-	var telegramResp struct {
-		OK          bool            `json:"ok"`
-		Description string          `json:"description,omitempty"`
-		ErrorCode   int             `json:"error_code,omitempty"`
-		Result      json.RawMessage `json:"result,omitempty"`
-	}
-
-	if err := json.Unmarshal(body, &telegramResp); err != nil {
-		return fmt.Errorf("ошибка декодирования ответа Telegram API: %w", err)
-	}
-
-	if !telegramResp.OK {
-		return fmt.Errorf("telegram API ошибка (%s): код %d, описание: %s", methodName, telegramResp.ErrorCode, telegramResp.Description)
-	}
-
-	return nil
+	return s.sendRequestForResult(ctx, methodName, payload, nil)
 }
 
 // --- ЭКРАНИРОВАНИЕ ДЛЯ MARKDOWNV2 ---
 
+// EscapeTextForMarkdownV2 — экранирование пользовательского текста; правила живут в pkg/sanitize.
 func EscapeTextForMarkdownV2(text string) string {
-	replacer := strings.NewReplacer(
-		"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]",
-		"(", "\\(", ")", "\\)", "\\", "\\\\",
-		"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+",
-		"-", "\\-", "=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
-	)
-	return replacer.Replace(text)
+	return sanitize.MarkdownV2(text)
 }
 
 func (s *Service) EditOrSendMessage(ctx context.Context, chatID int64, messageID int, text string, options ...MessageOption) error {
@@ -0,0 +1,75 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// File описывает файл на серверах Telegram, полученный через getFile.
+type File struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FilePath     string `json:"file_path,omitempty"`
+}
+
+type getFileRequest struct {
+	FileID string `json:"file_id"`
+}
+
+func (s *Service) GetFile(ctx context.Context, fileID string) (*File, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return nil, fmt.Errorf("telegram file id is empty")
+	}
+
+	var file File
+	if err := s.sendRequestForResult(ctx, "getFile", getFileRequest{FileID: fileID}, &file); err != nil {
+		return nil, err
+	}
+	if file.FilePath == "" {
+		return nil, fmt.Errorf("telegram getFile returned empty file_path for %s", fileID)
+	}
+
+	return &file, nil
+}
+
+// DownloadFile скачивает файл по file_path из getFile. Файлы больше maxBytes отклоняются.
+func (s *Service) DownloadFile(ctx context.Context, filePath string, maxBytes int64) ([]byte, error) {
+	if s.botToken == "" {
+		return nil, fmt.Errorf("telegram bot token is not configured")
+	}
+
+	fileURL := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", s.botToken, strings.TrimLeft(filePath, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Telegram file request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download Telegram file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("telegram file download failed: http %d", resp.StatusCode)
+	}
+
+	reader := io.Reader(resp.Body)
+	if maxBytes > 0 {
+		reader = io.LimitReader(resp.Body, maxBytes+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Telegram file: %w", err)
+	}
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("telegram file exceeds %d bytes", maxBytes)
+	}
+
+	return data, nil
+}
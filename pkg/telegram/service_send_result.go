@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 func (s *Service) SendMessageWithID(ctx context.Context, chatID int64, text string, options ...MessageOption) (int, error) {
@@ -31,10 +32,20 @@ func (s *Service) SendMessageWithID(ctx context.Context, chatID int64, text stri
 	return result.MessageID, nil
 }
 
+// sendRequestForResult вызывает метод Bot API через общую очередь бота (см. sendGate).
 func (s *Service) sendRequestForResult(ctx context.Context, methodName string, payload interface{}, out interface{}) error {
 	if s.botToken == "" {
 		return fmt.Errorf("telegram bot token is not configured")
 	}
+	if s.gate == nil {
+		return s.doRequest(ctx, methodName, payload, out)
+	}
+	return s.gate.do(ctx, methodName, payloadChatID(payload), func() error {
+		return s.doRequest(ctx, methodName, payload, out)
+	})
+}
+
+func (s *Service) doRequest(ctx context.Context, methodName string, payload interface{}, out interface{}) error {
 
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", s.botToken, methodName)
 
@@ -66,6 +77,9 @@ func (s *Service) sendRequestForResult(ctx context.Context, methodName string, p
 		Description string          `json:"description,omitempty"`
 		ErrorCode   int             `json:"error_code,omitempty"`
 		Result      json.RawMessage `json:"result,omitempty"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after,omitempty"`
+		} `json:"parameters,omitempty"`
 	}
 
 	if err := json.Unmarshal(body, &telegramResp); err != nil {
@@ -73,7 +87,12 @@ func (s *Service) sendRequestForResult(ctx context.Context, methodName string, p
 	}
 
 	if !telegramResp.OK {
-		return fmt.Errorf("telegram API error (%s): code %d, description: %s", methodName, telegramResp.ErrorCode, telegramResp.Description)
+		return &APIError{
+			Method:      methodName,
+			Code:        telegramResp.ErrorCode,
+			Description: telegramResp.Description,
+			RetryAfter:  time.Duration(telegramResp.Parameters.RetryAfter) * time.Second,
+		}
 	}
 
 	if out != nil && len(telegramResp.Result) > 0 {
@@ -0,0 +1,356 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits — ограничения исходящих вызовов Bot API. Telegram пропускает около 30 сообщений
+// в секунду на бота и примерно одно в секунду в один чат, сверх этого отвечает 429.
+type Limits struct {
+	// GlobalPerSecond — сообщений и правок в секунду на бота; 0 выключает общий лимит.
+	GlobalPerSecond float64
+	// ChatInterval — минимальный промежуток между новыми сообщениями в один чат.
+	ChatInterval time.Duration
+	// MaxWait — дольше вызов в очереди не ждёт и завершается ErrSendQueueFull.
+	MaxWait time.Duration
+	// BreakerThreshold — столько сбоев подряд размыкают цепь; 0 выключает автомат.
+	BreakerThreshold int
+	// BreakerCooldown — сколько цепь остаётся разомкнутой до пробного вызова.
+	BreakerCooldown time.Duration
+}
+
+var (
+	// ErrCircuitOpen — Telegram недавно не отвечал несколько раз подряд, вызов не отправлялся.
+	ErrCircuitOpen = errors.New("telegram API circuit is open after repeated failures")
+	// ErrSendQueueFull — очередь к лимиту длиннее Limits.MaxWait, вызов не отправлялся.
+	ErrSendQueueFull = errors.New("telegram send queue wait exceeds the limit")
+)
+
+// sendGate — очередь и автомат для одного бота. Вызовы занимают слоты по порядку:
+// общий раз в 1/GlobalPerSecond и в своём чате раз в ChatInterval, после 429 все
+// ждут retry_after. Сбои сети и 5xx подряд размыкают цепь на BreakerCooldown.
+type sendGate struct {
+	limits Limits
+	now    func() time.Time
+
+	mu          sync.Mutex
+	nextGlobal  time.Time
+	nextByChat  map[int64]time.Time
+	pausedUntil time.Time
+	failures    int
+	openUntil   time.Time
+	probing     bool
+}
+
+func newSendGate(limits Limits) *sendGate {
+	return &sendGate{limits: limits, now: time.Now, nextByChat: make(map[int64]time.Time)}
+}
+
+// Лимит Telegram действует на бота, поэтому все Service с одним токеном делят одну очередь.
+var gates = struct {
+	sync.Mutex
+	byToken map[string]*sendGate
+}{byToken: make(map[string]*sendGate)}
+
+func gateFor(botToken string, limits Limits) *sendGate {
+	gates.Lock()
+	defer gates.Unlock()
+	if g, ok := gates.byToken[botToken]; ok {
+		return g
+	}
+	g := newSendGate(limits)
+	gates.byToken[botToken] = g
+	return g
+}
+
+// methodLimits — какие лимиты касаются метода. Удаление сообщений, ответы на кнопки и
+// getFile не ограничиваются: они не создают сообщений, а задержка в них заметна в боте.
+func methodLimits(method string) (global, perChat bool) {
+	switch method {
+	case "sendMessage":
+		return true, true
+	case "editMessageText":
+		return true, false
+	default:
+		return false, false
+	}
+}
+
+func payloadChatID(payload interface{}) int64 {
+	if req, ok := payload.(*sendMessageRequest); ok {
+		return req.ChatID
+	}
+	return 0
+}
+
+// do выполняет вызов с учётом очереди и автомата. На 429 вызов повторяется один раз,
+// если retry_after укладывается в MaxWait.
+func (g *sendGate) do(ctx context.Context, method string, chatID int64, call func() error) error {
+	global, perChat := methodLimits(method)
+	if !perChat {
+		chatID = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		wait, err := g.reserve(global, chatID)
+		if err != nil {
+			sendStats.observe(method, resultFor(err), 0)
+			return err
+		}
+		if wait > 0 {
+			sendStats.waiting(1)
+			err = sleepContext(ctx, wait)
+			sendStats.waiting(-1)
+			if err != nil {
+				g.release()
+				return err
+			}
+		}
+
+		err = call()
+		g.finish(err)
+		sendStats.observe(method, resultFor(err), wait)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != 429 {
+			return err
+		}
+		g.pause(apiErr.RetryAfter)
+		if attempt > 0 || apiErr.RetryAfter > g.limits.MaxWait {
+			return err
+		}
+	}
+}
+
+func (g *sendGate) reserve(global bool, chatID int64) (time.Duration, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+
+	if g.limits.BreakerThreshold > 0 && g.failures >= g.limits.BreakerThreshold {
+		if now.Before(g.openUntil) || g.probing {
+			return 0, ErrCircuitOpen
+		}
+		// Пауза прошла: пропускаем один пробный вызов, остальные ждут его итога.
+		g.probing = true
+	}
+	if !global && chatID == 0 {
+		return 0, nil
+	}
+
+	at := latest(now, g.pausedUntil)
+	if global && g.limits.GlobalPerSecond > 0 {
+		at = latest(at, g.nextGlobal)
+	}
+	if chatID != 0 {
+		at = latest(at, g.nextByChat[chatID])
+	}
+	wait := at.Sub(now)
+	if g.limits.MaxWait > 0 && wait > g.limits.MaxWait {
+		g.probing = false
+		return 0, ErrSendQueueFull
+	}
+
+	if global && g.limits.GlobalPerSecond > 0 {
+		g.nextGlobal = at.Add(time.Duration(float64(time.Second) / g.limits.GlobalPerSecond))
+	}
+	if chatID != 0 && g.limits.ChatInterval > 0 {
+		if len(g.nextByChat) > 10000 {
+			for id, next := range g.nextByChat {
+				if next.Before(now) {
+					delete(g.nextByChat, id)
+				}
+			}
+		}
+		g.nextByChat[chatID] = at.Add(g.limits.ChatInterval)
+	}
+	return wait, nil
+}
+
+// finish учитывает итог вызова в автомате. Ответ Telegram с ошибкой запроса (4xx, 429)
+// значит, что API доступен, и сбрасывает счёт сбоев; отмена вызывающим его не меняет.
+func (g *sendGate) finish(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.probing = false
+
+	if !isOutage(err) {
+		if !errors.Is(err, context.Canceled) {
+			g.failures = 0
+		}
+		return
+	}
+	g.failures++
+	if g.limits.BreakerThreshold > 0 && g.failures >= g.limits.BreakerThreshold {
+		if !g.now().Before(g.openUntil) {
+			sendStats.circuitOpened()
+		}
+		g.openUntil = g.now().Add(g.limits.BreakerCooldown)
+	}
+}
+
+// release освобождает пробный слот вызова, который так и не был отправлен.
+func (g *sendGate) release() {
+	g.mu.Lock()
+	g.probing = false
+	g.mu.Unlock()
+}
+
+func (g *sendGate) pause(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pausedUntil = latest(g.pausedUntil, g.now().Add(retryAfter))
+}
+
+func (g *sendGate) isOpen() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.limits.BreakerThreshold > 0 && g.failures >= g.limits.BreakerThreshold && g.now().Before(g.openUntil)
+}
+
+// isOutage — сеть, таймаут ответа или 5xx: признаки того, что Telegram недоступен.
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= 500
+	}
+	return true
+}
+
+func resultFor(err error) string {
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, ErrSendQueueFull):
+		return "queue_full"
+	case errors.As(err, &apiErr) && apiErr.Code == 429:
+		return "rate_limited"
+	default:
+		return "error"
+	}
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sendMetrics — счётчики исходящих вызовов Bot API для /metrics.
+type sendMetrics struct {
+	mu        sync.Mutex
+	results   map[string]map[string]uint64
+	waitSum   float64
+	waitCount uint64
+	inQueue   int64
+	opened    uint64
+}
+
+var sendStats = &sendMetrics{results: make(map[string]map[string]uint64)}
+
+func (m *sendMetrics) observe(method, result string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	byResult, ok := m.results[method]
+	if !ok {
+		byResult = make(map[string]uint64)
+		m.results[method] = byResult
+	}
+	byResult[result]++
+	if result != "circuit_open" && result != "queue_full" {
+		m.waitSum += wait.Seconds()
+		m.waitCount++
+	}
+}
+
+func (m *sendMetrics) waiting(delta int64) {
+	m.mu.Lock()
+	m.inQueue += delta
+	m.mu.Unlock()
+}
+
+func (m *sendMetrics) circuitOpened() {
+	m.mu.Lock()
+	m.opened++
+	m.mu.Unlock()
+}
+
+// WritePrometheus выводит метрики исходящих вызовов Telegram в текстовом формате Prometheus.
+func WritePrometheus(w io.Writer) error {
+	open := 0
+	gates.Lock()
+	for _, g := range gates.byToken {
+		if g.isOpen() {
+			open = 1
+		}
+	}
+	gates.Unlock()
+
+	m := sendStats
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	methods := make([]string, 0, len(m.results))
+	for method := range m.results {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var b strings.Builder
+	b.WriteString("# HELP telegram_api_requests_total Вызовы Telegram Bot API по методам и итогам.\n")
+	b.WriteString("# TYPE telegram_api_requests_total counter\n")
+	for _, method := range methods {
+		results := make([]string, 0, len(m.results[method]))
+		for result := range m.results[method] {
+			results = append(results, result)
+		}
+		sort.Strings(results)
+		for _, result := range results {
+			fmt.Fprintf(&b, "telegram_api_requests_total{method=%q,result=%q} %d\n", method, result, m.results[method][result])
+		}
+	}
+	b.WriteString("# HELP telegram_send_queue_wait_seconds Ожидание своей очереди перед вызовом Telegram.\n")
+	b.WriteString("# TYPE telegram_send_queue_wait_seconds summary\n")
+	fmt.Fprintf(&b, "telegram_send_queue_wait_seconds_sum %g\n", m.waitSum)
+	fmt.Fprintf(&b, "telegram_send_queue_wait_seconds_count %d\n", m.waitCount)
+	b.WriteString("# HELP telegram_send_queue_waiting Вызовы, которые сейчас ждут очереди.\n")
+	b.WriteString("# TYPE telegram_send_queue_waiting gauge\n")
+	fmt.Fprintf(&b, "telegram_send_queue_waiting %d\n", m.inQueue)
+	b.WriteString("# HELP telegram_circuit_open Цепь вызовов Telegram разомкнута (1) или замкнута (0).\n")
+	b.WriteString("# TYPE telegram_circuit_open gauge\n")
+	fmt.Fprintf(&b, "telegram_circuit_open %d\n", open)
+	b.WriteString("# HELP telegram_circuit_opened_total Сколько раз цепь размыкалась после серии сбоев.\n")
+	b.WriteString("# TYPE telegram_circuit_opened_total counter\n")
+	fmt.Fprintf(&b, "telegram_circuit_opened_total %d\n", m.opened)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// AnalyticsOrderFact — плоская строка заявки для BI-систем: справочники уже развёрнуты в названия,
+// метрики посчитаны.
+type AnalyticsOrderFact struct {
+	OrderID                  uint64     `json:"order_id" db:"order_id"`
+	OrderName                string     `json:"order_name" db:"order_name"`
+	StatusCode               string     `json:"status_code" db:"status_code"`
+	StatusName               string     `json:"status_name" db:"status_name"`
+	PriorityCode             string     `json:"priority_code" db:"priority_code"`
+	PriorityName             string     `json:"priority_name" db:"priority_name"`
+	OrderTypeName            string     `json:"order_type_name" db:"order_type_name"`
+	DepartmentID             *uint64    `json:"department_id" db:"department_id"`
+	DepartmentName           string     `json:"department_name" db:"department_name"`
+	OtdelID                  *uint64    `json:"otdel_id" db:"otdel_id"`
+	OtdelName                string     `json:"otdel_name" db:"otdel_name"`
+	BranchID                 *uint64    `json:"branch_id" db:"branch_id"`
+	BranchName               string     `json:"branch_name" db:"branch_name"`
+	OfficeID                 *uint64    `json:"office_id" db:"office_id"`
+	OfficeName               string     `json:"office_name" db:"office_name"`
+	EquipmentTypeName        string     `json:"equipment_type_name" db:"equipment_type_name"`
+	EquipmentName            string     `json:"equipment_name" db:"equipment_name"`
+	CreatorID                uint64     `json:"creator_id" db:"creator_id"`
+	CreatorName              string     `json:"creator_name" db:"creator_name"`
+	ExecutorID               *uint64    `json:"executor_id" db:"executor_id"`
+	ExecutorName             string     `json:"executor_name" db:"executor_name"`
+	CreatedAt                time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt                time.Time  `json:"updated_at" db:"updated_at"`
+	CompletedAt              *time.Time `json:"completed_at" db:"completed_at"`
+	Deadline                 *time.Time `json:"deadline" db:"deadline"`
+	IsClosed                 bool       `json:"is_closed" db:"is_closed"`
+	IsOverdue                bool       `json:"is_overdue" db:"is_overdue"`
+	SLAMet                   *bool      `json:"sla_met" db:"sla_met"`
+	FirstResponseTimeSeconds *int64     `json:"first_response_time_seconds" db:"first_response_time_seconds"`
+	ResolutionTimeSeconds    *int64     `json:"resolution_time_seconds" db:"resolution_time_seconds"`
+	IsFirstContactResolution *bool      `json:"is_first_contact_resolution" db:"is_first_contact_resolution"`
+}
@@ -32,6 +32,9 @@ type DashboardQuery struct {
 	PreviousRange DashboardDateRange
 	Granularity   string
 	UserID        uint64
+	// RollupUntil — до какого момента (исключительно) можно читать суточные агрегаты
+	// daily_order_stats. Нулевое значение — только живые запросы.
+	RollupUntil time.Time
 }
 
 type DashboardMeta struct {
@@ -94,6 +97,44 @@ type DashboardExecutorCount struct {
 	UserID    *uint64 `json:"user_id,omitempty" db:"user_id"`
 }
 
+// DashboardExecutorStat — показатели исполнителя за период и его текущая нагрузка.
+type DashboardExecutorStat struct {
+	ExecutorID           uint64  `json:"executor_id" db:"executor_id"`
+	ExecutorName         string  `json:"executor_name" db:"executor_name"`
+	ClosedCount          int64   `json:"closed_count" db:"closed_count"`
+	AvgResolutionSeconds float64 `json:"avg_resolution_seconds" db:"avg_resolution_seconds"`
+	SLAEligible          int64   `json:"sla_eligible" db:"sla_eligible"`
+	SLAOnTime            int64   `json:"sla_on_time" db:"sla_on_time"`
+	ReopenedCount        int64   `json:"reopened_count" db:"reopened_count"`
+	OpenCount            int64   `json:"open_count" db:"open_count"`
+	OverdueOpenCount     int64   `json:"overdue_open_count" db:"overdue_open_count"`
+
+	AvgResolutionFormatted string  `json:"avg_resolution_formatted" db:"-"`
+	SLAPercent             float64 `json:"sla_percent" db:"-"`
+	ReopenRate             float64 `json:"reopen_rate" db:"-"`
+}
+
+// DashboardAgingBuckets — открытые заявки, разложенные по возрасту с момента создания.
+type DashboardAgingBuckets struct {
+	LessThanDay    int64 `json:"lt_1d" db:"lt_1d"`
+	OneToThreeDays int64 `json:"d1_3" db:"d1_3"`
+	ThreeToSeven   int64 `json:"d3_7" db:"d3_7"`
+	OverSevenDays  int64 `json:"gt_7d" db:"gt_7d"`
+	Total          int64 `json:"total" db:"total"`
+}
+
+type DashboardAgingGroup struct {
+	ID   uint64 `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	DashboardAgingBuckets
+}
+
+type DashboardBacklogAging struct {
+	Overall     DashboardAgingBuckets `json:"overall"`
+	Departments []DashboardAgingGroup `json:"departments"`
+	Branches    []DashboardAgingGroup `json:"branches"`
+}
+
 type DashboardChartData struct {
 	Label string `json:"label" db:"label"`
 	Value int64  `json:"value" db:"value"`
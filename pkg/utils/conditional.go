@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BuildETag собирает слабый ETag из частей версии ресурса. Слабый — потому что одинаковые
+// данные могут сериализоваться в JSON побайтно по-разному.
+func BuildETag(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// IsNotModified проверяет условные заголовки запроса. If-None-Match важнее If-Modified-Since
+// (RFC 9110, 13.2.2); при нулевом lastModified If-Modified-Since игнорируется.
+func IsNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagListMatches(inm, etag)
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// SetValidators выставляет ETag/Last-Modified. Ответ зависит от пользователя, поэтому
+// кешировать его можно только клиенту и только с перепроверкой.
+func SetValidators(h http.Header, etag string, lastModified time.Time) {
+	h.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", "private, no-cache")
+}
+
+// RespondNotModified отвечает 304, если клиент прислал актуальную версию, иначе
+// выставляет валидаторы для успешного ответа и возвращает false.
+func RespondNotModified(c echo.Context, etag string, lastModified time.Time) (bool, error) {
+	if IsNotModified(c.Request(), etag, lastModified) {
+		SetValidators(c.Response().Header(), etag, lastModified)
+		return true, c.NoContent(http.StatusNotModified)
+	}
+	c.Response().Before(func() {
+		if c.Response().Status == http.StatusOK {
+			SetValidators(c.Response().Header(), etag, lastModified)
+		}
+	})
+	return false, nil
+}
+
+func etagListMatches(header, etag string) bool {
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
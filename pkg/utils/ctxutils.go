@@ -4,7 +4,10 @@ package utils
 
 import (
 	"context"
+	"time"
 
+	"request-system/internal/authz"
+	"request-system/pkg/constants"
 	"request-system/pkg/contextkeys"
 	apperrors "request-system/pkg/errors"
 )
@@ -32,3 +35,76 @@ func GetPermissionsMapFromCtx(ctx context.Context) (map[string]bool, error) {
 	}
 	return permissions, nil
 }
+
+// WithUserContext кладёт в контекст пользователя и его права так же, как это делает HTTP-авторизация.
+// Используется всеми входами в приложение (HTTP, gRPC), чтобы сервисы читали одни и те же ключи.
+func WithUserContext(ctx context.Context, userID, roleID uint64, permissions []string) context.Context {
+	permissionsMap := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		permissionsMap[p] = true
+	}
+
+	ctx = context.WithValue(ctx, contextkeys.UserIDKey, userID)
+	ctx = context.WithValue(ctx, contextkeys.UserRoleIDKey, roleID)
+	ctx = context.WithValue(ctx, contextkeys.UserPermissionsKey, permissions)
+	return context.WithValue(ctx, contextkeys.UserPermissionsMapKey, permissionsMap)
+}
+
+// WithPermissionConditions кладёт в контекст условия прав пользователя рядом с самими правами.
+func WithPermissionConditions(ctx context.Context, conditions map[string][]authz.Condition) context.Context {
+	return context.WithValue(ctx, contextkeys.UserPermissionConditionsKey, conditions)
+}
+
+// GetPermissionConditionsFromCtx возвращает условия прав; nil — условий нет.
+func GetPermissionConditionsFromCtx(ctx context.Context) map[string][]authz.Condition {
+	conditions, _ := ctx.Value(contextkeys.UserPermissionConditionsKey).(map[string][]authz.Condition)
+	return conditions
+}
+
+// WithImpersonator отмечает, что запрос выполняется под чужой учётной записью от имени impersonatorID.
+func WithImpersonator(ctx context.Context, impersonatorID uint64) context.Context {
+	return context.WithValue(ctx, contextkeys.ImpersonatorIDKey, impersonatorID)
+}
+
+// GetImpersonatorIDFromCtx возвращает ID администратора, если запрос сделан в режиме имперсонации.
+func GetImpersonatorIDFromCtx(ctx context.Context) (uint64, bool) {
+	id, ok := ctx.Value(contextkeys.ImpersonatorIDKey).(uint64)
+	return id, ok && id != 0
+}
+
+// WithTenantID кладёт в контекст организацию пользователя; по ней репозитории ограничивают выборки.
+func WithTenantID(ctx context.Context, tenantID uint64) context.Context {
+	if tenantID == 0 {
+		tenantID = constants.DefaultTenantID
+	}
+	return context.WithValue(ctx, contextkeys.TenantIDKey, tenantID)
+}
+
+// GetTenantIDFromCtx возвращает организацию из контекста. ok = false для фоновых задач и
+// запросов до входа в систему: у них организации нет, и выборки не ограничиваются.
+func GetTenantIDFromCtx(ctx context.Context) (uint64, bool) {
+	id, ok := ctx.Value(contextkeys.TenantIDKey).(uint64)
+	return id, ok && id != 0
+}
+
+// TenantIDOrDefault — организация из контекста или организация по умолчанию, для записи новых строк.
+func TenantIDOrDefault(ctx context.Context) uint64 {
+	if id, ok := GetTenantIDFromCtx(ctx); ok {
+		return id
+	}
+	return constants.DefaultTenantID
+}
+
+// WithLocation кладёт в контекст часовой пояс пользователя: в нём форматируются даты ответа
+// и разбираются даты, введённые вручную.
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, contextkeys.LocationKey, loc)
+}
+
+// LocationFromCtx возвращает часовой пояс из контекста или пояс сервера (APP_TIMEZONE).
+func LocationFromCtx(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(contextkeys.LocationKey).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.Local
+}
@@ -11,6 +11,7 @@ import (
 
 	"request-system/internal/entities"
 	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
 	"request-system/pkg/types"
 
 	"github.com/go-playground/validator/v10"
@@ -205,7 +206,7 @@ func ParseFilterFromQuery(values url.Values) types.Filter {
 }
 
 func SuccessResponse(ctx echo.Context, body interface{}, message string, code int, total ...uint64) error {
-	response := &HTTPResponse{Status: true, Message: message}
+	response := &HTTPResponse{Status: true, Message: i18n.Message(i18n.FromContext(ctx.Request().Context()), message)}
 
 	// Проверяем наличие параметра withPagination в URL
 	withPagination, _ := strconv.ParseBool(ctx.QueryParam("withPagination"))
@@ -233,7 +234,11 @@ func SuccessResponse(ctx echo.Context, body interface{}, message string, code in
 
 	return ctx.JSON(code, response)
 }
+
+// ErrorResponse отправляет ошибку клиенту. Текст переводится на язык запроса (см. pkg/i18n),
+// в лог пишется русский вариант.
 func ErrorResponse(c echo.Context, err error, logger *zap.Logger) error {
+	lang := i18n.FromContext(c.Request().Context())
 	var httpErr *apperrors.HttpError
 	if errors.As(err, &httpErr) {
 		if httpErr.Err != nil {
@@ -247,7 +252,7 @@ func ErrorResponse(c echo.Context, err error, logger *zap.Logger) error {
 
 		response := map[string]interface{}{
 			"status":  false,
-			"message": httpErr.Message,
+			"message": httpErr.LocalizedMessage(lang),
 		}
 
 		if httpErr.Details != nil {
@@ -269,7 +274,7 @@ func ErrorResponse(c echo.Context, err error, logger *zap.Logger) error {
 	logger.Error("Unexpected Error", zap.Error(err))
 	return c.JSON(http.StatusInternalServerError, map[string]interface{}{
 		"status":  false,
-		"message": "Внутренняя ошибка сервера",
+		"message": i18n.Message(lang, "Внутренняя ошибка сервера"),
 	})
 }
 
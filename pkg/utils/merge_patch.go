@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// MIMEMergePatchJSON — тип тела RFC 7386 JSON Merge Patch.
+const MIMEMergePatchJSON = "application/merge-patch+json"
+
+var ErrMergePatchNotObject = errors.New("merge patch: тело должно быть JSON-объектом")
+
+// ParseMergePatch разбирает RFC 7386 JSON Merge Patch: заполняет dst и возвращает присланные поля.
+// Ключ есть в карте — поле меняется, значение null — поле очищается, ключа нет — поле не трогаем.
+func ParseMergePatch(body []byte, dst interface{}) (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	// "null" тоже разбирается в nil-карту, но патчем заявки быть не может.
+	if fields == nil {
+		return nil, ErrMergePatchNotObject
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// CreateMergePatch строит патч, который переводит original в modified: изменённые и новые ключи
+// со значениями, пропавшие — с null, вложенные объекты — рекурсивно.
+func CreateMergePatch(original, modified interface{}) ([]byte, error) {
+	originalDoc, err := toJSONObject(original)
+	if err != nil {
+		return nil, err
+	}
+	modifiedDoc, err := toJSONObject(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(mergePatchDiff(originalDoc, modifiedDoc)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func mergePatchDiff(original, modified map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key := range original {
+		if _, ok := modified[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, newValue := range modified {
+		oldValue, ok := original[key]
+		if !ok {
+			patch[key] = newValue
+			continue
+		}
+		oldObject, oldIsObject := oldValue.(map[string]interface{})
+		newObject, newIsObject := newValue.(map[string]interface{})
+		if oldIsObject && newIsObject {
+			if nested := mergePatchDiff(oldObject, newObject); len(nested) > 0 {
+				patch[key] = nested
+			}
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			patch[key] = newValue
+		}
+	}
+	return patch
+}
+
+func toJSONObject(value interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, ErrMergePatchNotObject
+	}
+	return doc, nil
+}
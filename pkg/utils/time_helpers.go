@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
+	"time"
 )
 
 // FormatSecondsToHumanReadable преобразует секунды (целое число) в строку вида "1д 2ч 3м 4с".
@@ -46,3 +48,32 @@ func FormatFloatSecondsToHumanReadable(totalSeconds float64) string {
 	// Просто округляем до ближайшей секунды и используем уже существующую функцию
 	return FormatSecondsToHumanReadable(uint64(math.Round(totalSeconds)))
 }
+
+var userLocations sync.Map
+
+// UserLocation возвращает часовой пояс пользователя по имени IANA ("Asia/Dushanbe").
+// Пустое или неизвестное имя — пояс сервера (APP_TIMEZONE), как до появления настройки.
+func UserLocation(name *string) *time.Location {
+	if name == nil || *name == "" {
+		return time.Local
+	}
+	if loc, ok := userLocations.Load(*name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(*name)
+	if err != nil {
+		return time.Local
+	}
+	userLocations.Store(*name, loc)
+	return loc
+}
+
+// IsValidTimezone сообщает, что name — пояс из базы IANA. "Local" и "" не принимаются:
+// они означают пояс сервера, а не выбор пользователя.
+func IsValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
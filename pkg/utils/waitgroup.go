@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// WaitWithContext ждёт wg, но не дольше ctx. Возвращает ctx.Err(), если горутины не успели завершиться.
+func WaitWithContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -57,6 +57,39 @@ func ValidateFile(fileHeader *multipart.FileHeader, file io.ReadSeeker, contextN
 	return nil
 }
 
+// ValidateContent проверяет уже загруженный в память файл (например, скачанный из Telegram)
+// и возвращает определённый по содержимому MIME-тип.
+func ValidateContent(content []byte, declaredMimeType string, contextName string) (string, error) {
+	rules, ok := config.UploadContexts[contextName]
+	if !ok {
+		return "", fmt.Errorf("внутренняя ошибка: неизвестный контекст загрузки '%s'", contextName)
+	}
+
+	if rules.MaxSizeMB > 0 {
+		maxSizeBytes := int64(rules.MaxSizeMB) * 1024 * 1024
+		if int64(len(content)) > maxSizeBytes {
+			return "", fmt.Errorf("размер файла (%.2f MB) превышает лимит в %d MB", float64(len(content))/1024/1024, rules.MaxSizeMB)
+		}
+	}
+
+	mimeType := http.DetectContentType(content)
+	if isPossibleXml(mimeType) && isSvgSignature(content) {
+		mimeType = "image/svg+xml"
+	}
+
+	// Офисные документы (docx, odt и т.п.) по сигнатуре определяются как zip,
+	// поэтому для них доверяем заявленному типу, если он разрешён.
+	if mimeType == "application/zip" && declaredMimeType != "" && slices.Contains(rules.AllowedMimeTypes, declaredMimeType) {
+		mimeType = declaredMimeType
+	}
+
+	if !slices.Contains(rules.AllowedMimeTypes, mimeType) {
+		return "", fmt.Errorf("недопустимый формат файла: %s", mimeType)
+	}
+
+	return mimeType, nil
+}
+
 // Хелперы
 func isPossibleXml(mime string) bool {
 	return mime == "text/plain; charset=utf-8" ||
@@ -1,9 +1,12 @@
 package validation
 
 import (
-	"fmt"
+	"net/http"
 
 	"github.com/go-playground/validator/v10"
+
+	apperrors "request-system/pkg/errors"
+	"request-system/pkg/i18n"
 )
 
 type CustomValidator struct {
@@ -33,108 +36,36 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
+// translateValidationError превращает ошибку правила в ошибку 400 с ключом каталога pkg/i18n:
+// текст переводится на язык запроса при отправке ответа.
 func translateValidationError(e validator.FieldError) error {
-	fieldName := translateFieldName(e.Field())
+	field := fieldNameArg(e.Field())
 
 	switch e.Tag() {
-	case "required":
-		return fmt.Errorf("поле '%s' обязательно для заполнения", fieldName)
-	case "min":
-		return fmt.Errorf("поле '%s' должно содержать минимум %s символов", fieldName, e.Param())
-	case "max":
-		return fmt.Errorf("поле '%s' должно содержать максимум %s символов", fieldName, e.Param())
-	case "len":
-		return fmt.Errorf("поле '%s' должно содержать ровно %s символов", fieldName, e.Param())
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		return validationError("validation."+e.Tag(), field, e.Param())
+	case "required", "e164_TJ", "duration_format", "uppercase", "numeric", "datetime", "required_without", "oneof", "url":
+		return validationError("validation."+e.Tag(), field)
 	case "email", "custom_email":
-		return fmt.Errorf("поле '%s' должно содержать корректный email", fieldName)
-	case "e164_TJ":
-		return fmt.Errorf("поле '%s' должно быть в формате +992XXXXXXXXX", fieldName)
-	case "duration_format":
-		return fmt.Errorf("поле '%s' должно быть в формате '2h30m'", fieldName)
+		return validationError("validation.email", field)
 	case "address_logic":
-		return fmt.Errorf("укажите адрес или выберите подразделение")
-	case "uppercase":
-		return fmt.Errorf("поле '%s' должно быть в верхнем регистре", fieldName)
-	case "numeric":
-		return fmt.Errorf("поле '%s' должно содержать только цифры", fieldName)
-	case "gt":
-		return fmt.Errorf("поле '%s' должно быть больше %s", fieldName, e.Param())
-	case "gte":
-		return fmt.Errorf("поле '%s' должно быть не менее %s", fieldName, e.Param())
-	case "lt":
-		return fmt.Errorf("поле '%s' должно быть меньше %s", fieldName, e.Param())
-	case "lte":
-		return fmt.Errorf("поле '%s' должно быть не более %s", fieldName, e.Param())
-	case "datetime":
-		return fmt.Errorf("поле '%s' должно быть в формате дата (например: 2024-01-31)", fieldName)
-	case "required_without":
-		return fmt.Errorf("поле '%s' обязательно если не указано другое подразделение", fieldName)
+		return validationError("validation.address_logic")
 	case "dive":
-		return fmt.Errorf("поле '%s' содержит недопустимое значение", fieldName)
-	case "oneof":
-		return fmt.Errorf("поле '%s' содержит недопустимое значение", fieldName)
-	case "url":
-		return fmt.Errorf("поле '%s' должно содержать корректный URL", fieldName)
+		return validationError("validation.oneof", field)
 	default:
-		return fmt.Errorf("поле '%s' не прошло проверку", fieldName)
+		return validationError("validation.invalid", field)
 	}
 }
 
-func translateFieldName(field string) string {
-	names := map[string]string{
-		// Пользователь
-		"Fio":          "ФИО",
-		"Email":        "Email",
-		"PhoneNumber":  "Номер телефона",
-		"Password":     "Пароль",
-		"NewPassword":  "Новый пароль",
-		"Login":        "Логин",
-		"Username":     "Имя пользователя",
-		"Token":        "Токен",
-		"Code":         "Код",
-		"RoleIDs":      "Роли",
-		"PositionID":   "Должность",
-		"StatusID":     "Статус",
-		"BranchID":     "Филиал",
-		"DepartmentID": "Департамент",
-		"OtdelID":      "Отдел",
-		"OfficeID":     "Офис",
-
-		// Заявка
-		"Name":            "Название",
-		"Address":         "Адрес",
-		"Comment":         "Комментарий",
-		"OrderTypeID":     "Тип заявки",
-		"PriorityID":      "Приоритет",
-		"ExecutorID":      "Исполнитель",
-		"EquipmentID":     "Оборудование",
-		"EquipmentTypeID": "Тип оборудования",
-		"Duration":        "Срок выполнения",
-		"OrderID":         "Заявка",
-		"UserID":          "Пользователь",
-		"EventType":       "Тип события",
+func validationError(key string, args ...interface{}) error {
+	return apperrors.NewLocalizedError(http.StatusBadRequest, key, args...)
+}
 
-		// Справочники
-		"Type":          "Тип",
-		"Description":   "Описание",
-		"Rate":          "Рейтинг",
-		"OpenDate":      "Дата открытия",
-		"Path":          "Путь",
-		"FileName":      "Имя файла",
-		"FilePath":      "Путь к файлу",
-		"FileType":      "Тип файла",
-		"Message":       "Сообщение",
-		"RuleID":        "Правило",
-		"RuleName":      "Название правила",
-		"PositionType":  "Тип должности",
-		"PermissionID":  "Право",
-		"PermissionIDs": "Права",
-		"RoleID":        "Роль",
-		"ParentID":      "Родительский элемент",
-		"DepartmentsID": "Департамент",
-	}
-	if translated, ok := names[field]; ok {
-		return translated
+// fieldNameArg — название поля для подстановки: ключ каталога, если поле переведено,
+// иначе имя поля структуры как есть.
+func fieldNameArg(field string) interface{} {
+	if key := "field." + field; i18n.Has(key) {
+		return i18n.Key(key)
 	}
 	return field
 }
@@ -4,8 +4,10 @@ package websocket
 
 import (
 	"log"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
@@ -23,15 +25,33 @@ type Client struct {
 	Conn   *websocket.Conn
 	Send   chan []byte
 	UserID uint64
+	// Profile — имя и аватар для списка "кто сейчас смотрит заявку"
+	Profile *ActorInfo
+
+	// ID, время подключения, адрес и браузер — для списка соединений у администратора
+	ID          string
+	ConnectedAt time.Time
+	RemoteAddr  string
+	UserAgent   string
+
+	// ImpersonatorID — администратор, подключившийся под пользователем; 0 — обычный вход
+	ImpersonatorID uint64
+
+	rooms   map[string]struct{} // защищено Hub.mu
+	sent    atomic.Uint64
+	dropped atomic.Uint64
 }
 
 // --- ИЗМЕНЕНИЕ №2: ДОБАВИЛИ ПУБЛИЧНЫЙ КОНСТРУКТОР ---
 func NewClient(hub *Hub, conn *websocket.Conn, userID uint64) *Client {
 	return &Client{
-		Hub:    hub,
-		Conn:   conn,
-		Send:   make(chan []byte, 256),
-		UserID: userID,
+		Hub:         hub,
+		Conn:        conn,
+		Send:        make(chan []byte, 256),
+		UserID:      userID,
+		ID:          uuid.New().String(),
+		ConnectedAt: time.Now(),
+		rooms:       make(map[string]struct{}),
 	}
 }
 
@@ -46,13 +66,16 @@ func (c *Client) ReadPump() {
 	_ = c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.Conn.SetPongHandler(func(string) error { _ = c.Conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
 	for {
-		_, _, err := c.Conn.ReadMessage()
+		messageType, message, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("error: %v", err)
 			}
 			break
 		}
+		if messageType == websocket.TextMessage {
+			c.Hub.handleClientMessage(c, message)
+		}
 	}
 }
 
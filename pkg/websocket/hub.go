@@ -6,6 +6,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // Hub управляет всеми клиентами и рассылкой сообщений
@@ -16,6 +18,15 @@ type Hub struct {
 	Register    chan *Client
 	unregister  chan *Client
 	mu          sync.RWMutex
+	fanout      *redisFanout
+	replay      *replayStore
+
+	rooms          map[string]map[*Client]struct{}
+	roomAuthorizer RoomAuthorizer
+
+	// maxPerUser — предел соединений одного пользователя на этой реплике; 0 — без предела
+	maxPerUser int
+	counters   hubCounters
 }
 
 func NewHub() *Hub {
@@ -25,10 +36,32 @@ func NewHub() *Hub {
 		broadcast:   make(chan []byte),
 		Register:    make(chan *Client),
 		unregister:  make(chan *Client),
+		rooms:       make(map[string]map[*Client]struct{}),
+	}
+}
+
+// SetMaxConnectionsPerUser ограничивает число соединений одного пользователя. Вызывать до Run.
+func (h *Hub) SetMaxConnectionsPerUser(limit int) {
+	h.maxPerUser = limit
+}
+
+// CanConnect — есть ли у пользователя место для ещё одного соединения. Проверка до upgrade,
+// чтобы ответить 429; окончательно предел соблюдает Run при регистрации.
+func (h *Hub) CanConnect(userID uint64) bool {
+	if h.maxPerUser <= 0 {
+		return true
 	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.userClients[userID]) < h.maxPerUser
 }
 
 func (h *Hub) Run(ctx context.Context) {
+	if h.fanout != nil {
+		go h.runFanoutSubscriber(ctx)
+		go h.refreshPresence(ctx)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -41,12 +74,20 @@ func (h *Hub) Run(ctx context.Context) {
 			return
 		case client := <-h.Register:
 			h.mu.Lock()
+			if h.maxPerUser > 0 && len(h.userClients[client.UserID]) >= h.maxPerUser {
+				h.mu.Unlock()
+				h.counters.rejected.Add(1)
+				h.reject(client)
+				continue
+			}
 			h.clients[client] = true
 			h.userClients[client.UserID] = append(h.userClients[client.UserID], client)
 			h.mu.Unlock()
 		case client := <-h.unregister:
 			h.mu.Lock()
+			var left []roomLeft
 			if _, ok := h.clients[client]; ok {
+				left = h.leaveAllRoomsLocked(client)
 				delete(h.clients, client)
 				close(client.Send)
 				clients := h.userClients[client.UserID]
@@ -61,12 +102,15 @@ func (h *Hub) Run(ctx context.Context) {
 				}
 			}
 			h.mu.Unlock()
+			// Redis и рассылка presence не должны тормозить цикл хаба.
+			for _, l := range left {
+				go h.onRoomLeft(l.room, client.UserID, l.stillPresent)
+			}
 		case message := <-h.broadcast:
 			h.mu.Lock()
 			for client := range h.clients {
-				select {
-				case client.Send <- message:
-				default:
+				if !h.offer(client, message) {
+					h.counters.slowConsumers.Add(1)
 					close(client.Send)
 					delete(h.clients, client)
 				}
@@ -75,12 +119,26 @@ func (h *Hub) Run(ctx context.Context) {
 		}
 	}
 }
+
+// reject закрывает соединение сверх предела. Send не закрывается: в него ещё может писать
+// повторная доставка, а незарегистрированный клиент хаб при unregister пропустит.
+func (h *Hub) reject(client *Client) {
+	log.Printf("WebSocket: превышен предел соединений для userID %d, соединение закрыто", client.UserID)
+	if client.Conn == nil {
+		return
+	}
+	message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many connections")
+	_ = client.Conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait))
+	_ = client.Conn.Close()
+}
+
 func (h *Hub) SendMessageToUser(userID uint64, payload interface{}, messageType string) error {
 	envelope := Envelope{
 		Type:      messageType,
 		Payload:   payload,
 		Timestamp: time.Now().UTC(),
 	}
+	envelope.Seq = h.appendForReplay(userID, envelope)
 
 	messageBytes, err := json.Marshal(envelope)
 	if err != nil {
@@ -88,12 +146,36 @@ func (h *Hub) SendMessageToUser(userID uint64, payload interface{}, messageType
 		return err
 	}
 
+	h.deliverToUser(userID, messageBytes)
+	h.publish(userID, messageBytes)
+	return nil
+}
+
+// Broadcast отправляет сообщение всем клиентам, в том числе подключённым к другим репликам.
+func (h *Hub) Broadcast(payload interface{}, messageType string) error {
+	envelope := Envelope{
+		Type:      messageType,
+		Payload:   payload,
+		Timestamp: time.Now().UTC(),
+	}
+
+	messageBytes, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	h.deliverToAll(messageBytes)
+	h.publish(0, messageBytes)
+	return nil
+}
+
+// deliverToUser отправляет готовое сообщение локальным соединениям пользователя.
+func (h *Hub) deliverToUser(userID uint64, messageBytes []byte) {
 	h.mu.RLock()
 	clients, ok := h.userClients[userID]
 	if !ok {
 		h.mu.RUnlock()
-		log.Printf("Для userID %d не найдено активных соединений", userID)
-		return nil
+		return
 	}
 	// Копируем срез чтобы отпустить мьютекс
 	clientsCopy := make([]*Client, len(clients))
@@ -103,12 +185,18 @@ func (h *Hub) SendMessageToUser(userID uint64, payload interface{}, messageType
 	log.Printf("Найдено %d активных соединений для userID %d", len(clientsCopy), userID)
 
 	for _, client := range clientsCopy {
-		select {
-		case client.Send <- messageBytes:
-		default:
+		if !h.offer(client, messageBytes) {
 			log.Printf("Канал клиента userID %d заполнен, пропускаем", userID)
 		}
 	}
+}
 
-	return nil
+func (h *Hub) deliverToAll(messageBytes []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.clients {
+		if !h.offer(client, messageBytes) {
+			log.Printf("Канал клиента userID %d заполнен, пропускаем", client.UserID)
+		}
+	}
 }
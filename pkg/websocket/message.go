@@ -4,8 +4,10 @@ import "time"
 
 // Envelope — это "конверт", в котором мы отправляем наши сообщения.
 // Он содержит тип сообщения, что позволяет фронтенду понять, что делать.
+// Seq — номер сообщения пользователя, по нему клиент запрашивает пропущенное (?since=) после переподключения.
 type Envelope struct {
 	Type      string      `json:"type"`
+	Seq       uint64      `json:"seq,omitempty"`
 	Payload   interface{} `json:"payload"`
 	Timestamp time.Time   `json:"timestamp"`
 }
@@ -13,6 +15,7 @@ type Envelope struct {
 // NotificationPayload — это структура нашего уведомления из "колокольчика".
 // Это наш DTO для фронтенда.
 type NotificationPayload struct {
+	ID        uint64       `json:"id,omitempty"` // ID записи в центре уведомлений, нужен для отметки "прочитано"
 	EventID   string       `json:"eventId"`
 	Type      string       `json:"type"`
 	IsRead    bool         `json:"isRead"`
@@ -36,3 +39,25 @@ type LinkInfo struct {
 	Primary    string  `json:"primary"`
 	Attachment *string `json:"attachment,omitempty"`
 }
+
+// OrderPatchPayload — точечное изменение заявки для тех, кто открыл её карточку (комната order:<id>).
+// Field — имя поля заявки в API (status_id, executor_id, ...), пустое для комментариев и вложений.
+type OrderPatchPayload struct {
+	OrderID    uint64          `json:"order_id"`
+	HistoryID  uint64          `json:"history_id"`
+	EventType  string          `json:"event_type"`
+	Field      string          `json:"field,omitempty"`
+	OldValue   *string         `json:"old_value,omitempty"`
+	NewValue   *string         `json:"new_value,omitempty"`
+	Comment    *string         `json:"comment,omitempty"`
+	Attachment *AttachmentInfo `json:"attachment,omitempty"`
+	ActorID    uint64          `json:"actor_id"`
+	Actor      *ActorInfo      `json:"actor,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AttachmentInfo struct {
+	ID       uint64 `json:"id"`
+	FileName string `json:"file_name"`
+	URL      string `json:"url"`
+}
@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	presenceTTL             = 60 * time.Second
+	presenceRefreshInterval = 20 * time.Second
+	presenceKeyPrefix       = "ws:presence:"
+)
+
+// PresenceUser — пользователь, у которого сейчас открыта комната (карточка заявки).
+type PresenceUser struct {
+	UserID    uint64  `json:"user_id"`
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatarUrl,omitempty"`
+}
+
+// PresencePayload рассылается участникам комнаты при каждом входе/выходе.
+type PresencePayload struct {
+	OrderID uint64         `json:"order_id"`
+	Viewers []PresenceUser `json:"viewers"`
+}
+
+type presenceEntry struct {
+	PresenceUser
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+func (c *Client) presenceUser() PresenceUser {
+	user := PresenceUser{UserID: c.UserID}
+	if c.Profile != nil {
+		user.Name = c.Profile.Name
+		user.AvatarURL = c.Profile.AvatarURL
+	}
+	return user
+}
+
+// RoomPresence возвращает уникальных пользователей комнаты. При включённом Redis fanout
+// учитываются и клиенты других реплик, иначе — только локальные соединения.
+func (h *Hub) RoomPresence(ctx context.Context, room string) ([]PresenceUser, error) {
+	if h.fanout == nil {
+		return h.localPresence(room), nil
+	}
+
+	key := presenceKeyPrefix + room
+	fields, err := h.fanout.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	byUser := make(map[uint64]PresenceUser)
+	var expired []string
+	for field, raw := range fields {
+		var entry presenceEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil || entry.ExpiresAt < now {
+			expired = append(expired, field)
+			continue
+		}
+		byUser[entry.UserID] = entry.PresenceUser
+	}
+	if len(expired) > 0 {
+		_ = h.fanout.client.HDel(ctx, key, expired...).Err()
+	}
+	// Свои соединения добавляем всегда — запись в Redis могла ещё не успеть появиться.
+	for _, user := range h.localPresence(room) {
+		byUser[user.UserID] = user
+	}
+
+	return sortedPresence(byUser), nil
+}
+
+func (h *Hub) localPresence(room string) []PresenceUser {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	byUser := make(map[uint64]PresenceUser)
+	for client := range h.rooms[room] {
+		byUser[client.UserID] = client.presenceUser()
+	}
+	return sortedPresence(byUser)
+}
+
+func sortedPresence(byUser map[uint64]PresenceUser) []PresenceUser {
+	result := make([]PresenceUser, 0, len(byUser))
+	for _, user := range byUser {
+		result = append(result, user)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result
+}
+
+func (h *Hub) presenceField(userID uint64) string {
+	return fmt.Sprintf("%s:%d", h.fanout.instanceID, userID)
+}
+
+// storePresence записывает присутствие пользователя этой реплики в Redis.
+func (h *Hub) storePresence(ctx context.Context, room string, user PresenceUser) {
+	if h.fanout == nil {
+		return
+	}
+	raw, err := json.Marshal(presenceEntry{PresenceUser: user, ExpiresAt: time.Now().Add(presenceTTL).Unix()})
+	if err != nil {
+		return
+	}
+	key := presenceKeyPrefix + room
+	pipe := h.fanout.client.TxPipeline()
+	pipe.HSet(ctx, key, h.presenceField(user.UserID), raw)
+	pipe.Expire(ctx, key, presenceTTL+30*time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("WebSocket presence: не удалось сохранить присутствие в %s: %v", room, err)
+	}
+}
+
+func (h *Hub) removePresence(ctx context.Context, room string, userID uint64) {
+	if h.fanout == nil {
+		return
+	}
+	if err := h.fanout.client.HDel(ctx, presenceKeyPrefix+room, h.presenceField(userID)).Err(); err != nil {
+		log.Printf("WebSocket presence: не удалось удалить присутствие из %s: %v", room, err)
+	}
+}
+
+// userInRoomLocked — есть ли у пользователя ещё соединения в комнате (вкладки браузера).
+func (h *Hub) userInRoomLocked(room string, userID uint64) bool {
+	for client := range h.rooms[room] {
+		if client.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// onRoomJoined/onRoomLeft обновляют присутствие и рассылают актуальный список участников комнаты.
+func (h *Hub) onRoomJoined(room string, user PresenceUser) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	h.storePresence(ctx, room, user)
+	h.broadcastPresence(ctx, room)
+}
+
+func (h *Hub) onRoomLeft(room string, userID uint64, stillPresent bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if !stillPresent {
+		h.removePresence(ctx, room, userID)
+	}
+	h.broadcastPresence(ctx, room)
+}
+
+func (h *Hub) broadcastPresence(ctx context.Context, room string) {
+	orderID, ok := parseOrderRoom(room)
+	if !ok {
+		return
+	}
+	viewers, err := h.RoomPresence(ctx, room)
+	if err != nil {
+		log.Printf("WebSocket presence: не удалось получить участников %s: %v", room, err)
+		viewers = h.localPresence(room)
+	}
+	_ = h.SendToRoom(room, PresencePayload{OrderID: orderID, Viewers: viewers}, "presence")
+}
+
+// refreshPresence продлевает записи присутствия локальных клиентов, пока они в комнатах.
+func (h *Hub) refreshPresence(ctx context.Context) {
+	ticker := time.NewTicker(presenceRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			type membership struct {
+				room string
+				user PresenceUser
+			}
+			h.mu.RLock()
+			var memberships []membership
+			for room, members := range h.rooms {
+				seen := make(map[uint64]struct{})
+				for client := range members {
+					if _, ok := seen[client.UserID]; ok {
+						continue
+					}
+					seen[client.UserID] = struct{}{}
+					memberships = append(memberships, membership{room: room, user: client.presenceUser()})
+				}
+			}
+			h.mu.RUnlock()
+
+			for _, m := range memberships {
+				h.storePresence(ctx, m.room, m.user)
+			}
+		}
+	}
+}
+
+func parseOrderRoom(room string) (uint64, bool) {
+	rest, ok := strings.CutPrefix(room, "order:")
+	if !ok {
+		return 0, false
+	}
+	var orderID uint64
+	if _, err := fmt.Sscanf(rest, "%d", &orderID); err != nil {
+		return 0, false
+	}
+	return orderID, true
+}
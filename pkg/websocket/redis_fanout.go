@@ -0,0 +1,132 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// fanoutMessage — сообщение между репликами. Data — уже сериализованный Envelope.
+// Если задан Room — сообщение для комнаты; иначе UserID == 0 означает рассылку всем подключённым клиентам.
+// Disconnect — не сообщение, а команда закрыть соединения (Hub.Disconnect).
+type fanoutMessage struct {
+	Origin     string            `json:"origin"`
+	UserID     uint64            `json:"user_id"`
+	Room       string            `json:"room,omitempty"`
+	Data       json.RawMessage   `json:"data"`
+	Disconnect *fanoutDisconnect `json:"disconnect,omitempty"`
+}
+
+type fanoutDisconnect struct {
+	ConnectionID string `json:"connection_id,omitempty"`
+	UserID       uint64 `json:"user_id,omitempty"`
+}
+
+// redisFanout публикует сообщения хаба в Redis и доставляет локально сообщения других реплик.
+type redisFanout struct {
+	client     *redis.Client
+	channel    string
+	instanceID string
+}
+
+// EnableRedisFanout включает рассылку через Redis pub/sub. Вызывать до Run.
+// Сообщение всегда доставляется локально сразу, а в Redis уходит для остальных реплик;
+// своё же сообщение, вернувшееся из Redis, повторно не доставляется.
+func (h *Hub) EnableRedisFanout(client *redis.Client, channel string) {
+	h.fanout = &redisFanout{
+		client:     client,
+		channel:    channel,
+		instanceID: uuid.New().String(),
+	}
+}
+
+func (h *Hub) publish(userID uint64, data []byte) {
+	if h.fanout == nil {
+		return
+	}
+	h.publishFanout(fanoutMessage{Origin: h.fanout.instanceID, UserID: userID, Data: data})
+}
+
+func (h *Hub) publishToRoom(room string, data []byte) {
+	if h.fanout == nil {
+		return
+	}
+	h.publishFanout(fanoutMessage{Origin: h.fanout.instanceID, Room: room, Data: data})
+}
+
+func (h *Hub) publishFanout(m fanoutMessage) {
+	msg, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("WebSocket fanout: ошибка сериализации: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := h.fanout.client.Publish(ctx, h.fanout.channel, msg).Err(); err != nil {
+		log.Printf("WebSocket fanout: не удалось опубликовать сообщение в Redis: %v", err)
+	}
+}
+
+// runFanoutSubscriber слушает канал Redis и переподписывается при обрыве соединения.
+func (h *Hub) runFanoutSubscriber(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		pubsub := h.fanout.client.Subscribe(ctx, h.fanout.channel)
+		if _, err := pubsub.Receive(ctx); err != nil {
+			_ = pubsub.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("WebSocket fanout: не удалось подписаться на %s: %v, повтор через %s", h.fanout.channel, err, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+			continue
+		}
+
+		backoff = time.Second
+		h.consumeFanout(ctx, pubsub.Channel())
+		_ = pubsub.Close()
+	}
+}
+
+func (h *Hub) consumeFanout(ctx context.Context, messages <-chan *redis.Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			var msg fanoutMessage
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				log.Printf("WebSocket fanout: некорректное сообщение: %v", err)
+				continue
+			}
+			if msg.Origin == h.fanout.instanceID {
+				continue
+			}
+			switch {
+			case msg.Disconnect != nil:
+				h.disconnectLocal(msg.Disconnect.ConnectionID, msg.Disconnect.UserID)
+			case msg.Room != "":
+				h.deliverToRoom(msg.Room, msg.Data)
+			case msg.UserID == 0:
+				h.deliverToAll(msg.Data)
+			default:
+				h.deliverToUser(msg.UserID, msg.Data)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	replaySeqKeyPattern    = "ws:seq:%d"
+	replayStreamKeyPattern = "ws:stream:%d"
+)
+
+// appendScript атомарно выдаёт следующий номер сообщения пользователя и пишет сообщение в стрим
+// с ID "<seq>-0", чтобы при переподключении можно было дочитать всё, что идёт после since.
+var appendScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[2], seq .. '-0', 'data', ARGV[1])
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+return seq
+`)
+
+// replayStore хранит последние сообщения каждого пользователя в Redis Stream.
+type replayStore struct {
+	client *redis.Client
+	maxLen int64
+	ttl    time.Duration
+}
+
+// ReplayGapPayload — клиент отсутствовал дольше, чем хранится стрим: часть событий потеряна,
+// фронтенду нужно перезагрузить данные целиком.
+type ReplayGapPayload struct {
+	Since    uint64 `json:"since"`
+	FirstSeq uint64 `json:"first_seq"`
+}
+
+type ReplayCompletePayload struct {
+	LastSeq  uint64 `json:"last_seq"`
+	Replayed int    `json:"replayed"`
+}
+
+// EnableReplay включает нумерацию сообщений пользователя (Envelope.Seq) и их хранение для повторной
+// доставки после переподключения. Вызывать до Run.
+func (h *Hub) EnableReplay(client *redis.Client, maxLen int64, ttl time.Duration) {
+	h.replay = &replayStore{client: client, maxLen: maxLen, ttl: ttl}
+}
+
+// appendForReplay сохраняет сообщение и возвращает его номер. 0 — нумерация выключена или Redis недоступен.
+func (h *Hub) appendForReplay(userID uint64, envelope Envelope) uint64 {
+	if h.replay == nil {
+		return 0
+	}
+
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	keys := []string{fmt.Sprintf(replaySeqKeyPattern, userID), fmt.Sprintf(replayStreamKeyPattern, userID)}
+	seq, err := appendScript.Run(ctx, h.replay.client, keys, raw, h.replay.maxLen, int64(h.replay.ttl.Seconds())).Int64()
+	if err != nil {
+		log.Printf("WebSocket replay: не удалось сохранить сообщение userID %d: %v", userID, err)
+		return 0
+	}
+	return uint64(seq)
+}
+
+// Replay досылает клиенту сообщения с номером больше since. Живые сообщения могут прийти
+// вперемешку с повторными — фронтенд отбрасывает дубликаты по seq.
+func (h *Hub) Replay(ctx context.Context, client *Client, since uint64) error {
+	if h.replay == nil {
+		return nil
+	}
+
+	streamKey := fmt.Sprintf(replayStreamKeyPattern, client.UserID)
+
+	first, err := h.replay.client.XRangeN(ctx, streamKey, "-", "+", 1).Result()
+	if err != nil {
+		return err
+	}
+	if len(first) > 0 {
+		if firstSeq := streamSeq(first[0].ID); firstSeq > since+1 {
+			h.reply(client, "replay_gap", ReplayGapPayload{Since: since, FirstSeq: firstSeq})
+		}
+	}
+
+	entries, err := h.replay.client.XRange(ctx, streamKey, fmt.Sprintf("%d-1", since), "+").Result()
+	if err != nil {
+		return err
+	}
+
+	lastSeq := since
+	for _, entry := range entries {
+		seq := streamSeq(entry.ID)
+		data, _ := entry.Values["data"].(string)
+
+		var envelope struct {
+			Type      string          `json:"type"`
+			Seq       uint64          `json:"seq"`
+			Payload   json.RawMessage `json:"payload"`
+			Timestamp time.Time       `json:"timestamp"`
+		}
+		if err := json.Unmarshal([]byte(data), &envelope); err != nil {
+			continue
+		}
+		envelope.Seq = seq
+
+		messageBytes, err := json.Marshal(envelope)
+		if err != nil {
+			continue
+		}
+		if !h.sendToClient(client, messageBytes) {
+			break
+		}
+		lastSeq = seq
+	}
+
+	h.reply(client, "replay_complete", ReplayCompletePayload{LastSeq: lastSeq, Replayed: len(entries)})
+	return nil
+}
+
+// sendToClient кладёт сообщение в очередь клиента, если он ещё подключён.
+func (h *Hub) sendToClient(client *Client, messageBytes []byte) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if _, ok := h.clients[client]; !ok {
+		return false
+	}
+	return h.offer(client, messageBytes)
+}
+
+func streamSeq(id string) uint64 {
+	part, _, _ := strings.Cut(id, "-")
+	seq, _ := strconv.ParseUint(part, 10, 64)
+	return seq
+}
@@ -0,0 +1,192 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// maxRoomsPerClient — защита от подписки одного соединения на тысячи заявок.
+	maxRoomsPerClient    = 20
+	roomAuthorizeTimeout = 5 * time.Second
+
+	ClientMessageSubscribe   = "subscribe"
+	ClientMessageUnsubscribe = "unsubscribe"
+)
+
+// ClientMessage — входящее сообщение от фронтенда. Сейчас поддерживается только подписка на заявку:
+// {"type":"subscribe","order_id":123} / {"type":"unsubscribe","order_id":123}.
+type ClientMessage struct {
+	Type    string `json:"type"`
+	OrderID uint64 `json:"order_id"`
+}
+
+// RoomAuthorizer проверяет, может ли пользователь подписаться на комнату. nil — доступ разрешён.
+type RoomAuthorizer func(ctx context.Context, userID uint64, orderID uint64) error
+
+type roomAck struct {
+	OrderID uint64 `json:"order_id"`
+	Error   string `json:"error,omitempty"`
+}
+
+// OrderRoom — имя комнаты для карточки заявки.
+func OrderRoom(orderID uint64) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
+
+// SetRoomAuthorizer задаёт проверку доступа к комнатам. Без неё подписки отклоняются.
+func (h *Hub) SetRoomAuthorizer(authorizer RoomAuthorizer) {
+	h.mu.Lock()
+	h.roomAuthorizer = authorizer
+	h.mu.Unlock()
+}
+
+func (h *Hub) handleClientMessage(client *Client, raw []byte) {
+	var msg ClientMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return
+	}
+
+	switch msg.Type {
+	case ClientMessageSubscribe:
+		if err := h.subscribe(client, msg.OrderID); err != nil {
+			h.reply(client, "subscribe_error", roomAck{OrderID: msg.OrderID, Error: err.Error()})
+			return
+		}
+		h.reply(client, "subscribed", roomAck{OrderID: msg.OrderID})
+	case ClientMessageUnsubscribe:
+		h.leaveRoom(client, OrderRoom(msg.OrderID))
+		h.reply(client, "unsubscribed", roomAck{OrderID: msg.OrderID})
+	}
+}
+
+func (h *Hub) subscribe(client *Client, orderID uint64) error {
+	if orderID == 0 {
+		return fmt.Errorf("order_id не указан")
+	}
+
+	h.mu.RLock()
+	authorizer := h.roomAuthorizer
+	subscribed := len(client.rooms)
+	h.mu.RUnlock()
+
+	if authorizer == nil {
+		return fmt.Errorf("подписки недоступны")
+	}
+	if subscribed >= maxRoomsPerClient {
+		return fmt.Errorf("превышено число подписок на соединение (%d)", maxRoomsPerClient)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), roomAuthorizeTimeout)
+	defer cancel()
+	if err := authorizer(ctx, client.UserID, orderID); err != nil {
+		return fmt.Errorf("нет доступа к заявке")
+	}
+
+	room := OrderRoom(orderID)
+	if h.joinRoom(client, room) {
+		h.onRoomJoined(room, client.presenceUser())
+	}
+	return nil
+}
+
+func (h *Hub) joinRoom(client *Client, room string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[client]; !ok {
+		return false
+	}
+	members, ok := h.rooms[room]
+	if !ok {
+		members = make(map[*Client]struct{})
+		h.rooms[room] = members
+	}
+	members[client] = struct{}{}
+	client.rooms[room] = struct{}{}
+	return true
+}
+
+func (h *Hub) leaveRoom(client *Client, room string) {
+	h.mu.Lock()
+	if _, ok := client.rooms[room]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	h.leaveRoomLocked(client, room)
+	stillPresent := h.userInRoomLocked(room, client.UserID)
+	h.mu.Unlock()
+
+	h.onRoomLeft(room, client.UserID, stillPresent)
+}
+
+// roomLeft — комната, которую покинул отключившийся клиент.
+type roomLeft struct {
+	room         string
+	stillPresent bool
+}
+
+func (h *Hub) leaveRoomLocked(client *Client, room string) {
+	delete(client.rooms, room)
+	if members, ok := h.rooms[room]; ok {
+		delete(members, client)
+		if len(members) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+}
+
+func (h *Hub) leaveAllRoomsLocked(client *Client) []roomLeft {
+	left := make([]roomLeft, 0, len(client.rooms))
+	for room := range client.rooms {
+		h.leaveRoomLocked(client, room)
+		left = append(left, roomLeft{room: room, stillPresent: h.userInRoomLocked(room, client.UserID)})
+	}
+	return left
+}
+
+// SendToRoom отправляет сообщение всем, кто сейчас открыл комнату, включая клиентов других реплик.
+func (h *Hub) SendToRoom(room string, payload interface{}, messageType string) error {
+	envelope := Envelope{
+		Type:      messageType,
+		Payload:   payload,
+		Timestamp: time.Now().UTC(),
+	}
+
+	messageBytes, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	h.deliverToRoom(room, messageBytes)
+	h.publishToRoom(room, messageBytes)
+	return nil
+}
+
+func (h *Hub) deliverToRoom(room string, messageBytes []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.rooms[room] {
+		if !h.offer(client, messageBytes) {
+			log.Printf("Канал клиента userID %d заполнен, пропускаем сообщение комнаты %s", client.UserID, room)
+		}
+	}
+}
+
+// reply отправляет служебный ответ только этому соединению.
+func (h *Hub) reply(client *Client, messageType string, payload interface{}) {
+	messageBytes, err := json.Marshal(Envelope{Type: messageType, Payload: payload, Timestamp: time.Now().UTC()})
+	if err != nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	h.offer(client, messageBytes)
+}
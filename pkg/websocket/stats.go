@@ -0,0 +1,209 @@
+package websocket
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// rateWindowSeconds — за сколько последних секунд считается средняя скорость отправки.
+const rateWindowSeconds = 60
+
+// hubCounters — счётчики хаба с момента запуска реплики.
+type hubCounters struct {
+	sent              atomic.Uint64
+	dropped           atomic.Uint64
+	slowConsumers     atomic.Uint64
+	rejected          atomic.Uint64
+	forcedDisconnects atomic.Uint64
+	rate              rateWindow
+}
+
+// rateWindow считает события по секундам в кольце, чтобы отдавать среднее за последнюю минуту.
+type rateWindow struct {
+	mu      sync.Mutex
+	buckets [rateWindowSeconds]uint64
+	seconds [rateWindowSeconds]int64
+}
+
+func (w *rateWindow) add(now time.Time) {
+	second := now.Unix()
+	i := second % rateWindowSeconds
+	w.mu.Lock()
+	if w.seconds[i] != second {
+		w.seconds[i] = second
+		w.buckets[i] = 0
+	}
+	w.buckets[i]++
+	w.mu.Unlock()
+}
+
+func (w *rateWindow) perSecond(now time.Time) float64 {
+	from := now.Unix() - rateWindowSeconds
+	var total uint64
+	w.mu.Lock()
+	for i, second := range w.seconds {
+		if second > from {
+			total += w.buckets[i]
+		}
+	}
+	w.mu.Unlock()
+	return float64(total) / rateWindowSeconds
+}
+
+// offer кладёт сообщение в очередь клиента без ожидания. Переполненная очередь значит,
+// что клиент не успевает читать: сообщение теряется и учитывается как отброшенное.
+func (h *Hub) offer(client *Client, message []byte) bool {
+	select {
+	case client.Send <- message:
+		h.counters.sent.Add(1)
+		h.counters.rate.add(time.Now())
+		client.sent.Add(1)
+		return true
+	default:
+		h.counters.dropped.Add(1)
+		client.dropped.Add(1)
+		return false
+	}
+}
+
+// HubStats — состояние хаба этой реплики.
+type HubStats struct {
+	Clients               int     `json:"clients"`
+	Users                 int     `json:"users"`
+	Rooms                 int     `json:"rooms"`
+	MessagesSent          uint64  `json:"messages_sent"`
+	MessagesPerSecond     float64 `json:"messages_per_second"`
+	MessagesDropped       uint64  `json:"messages_dropped"`
+	SlowConsumersDropped  uint64  `json:"slow_consumers_dropped"`
+	ConnectionsRejected   uint64  `json:"connections_rejected"`
+	ForcedDisconnects     uint64  `json:"forced_disconnects"`
+	MaxConnectionsPerUser int     `json:"max_connections_per_user"`
+}
+
+func (h *Hub) Stats() HubStats {
+	h.mu.RLock()
+	stats := HubStats{Clients: len(h.clients), Users: len(h.userClients), Rooms: len(h.rooms)}
+	h.mu.RUnlock()
+
+	stats.MessagesSent = h.counters.sent.Load()
+	stats.MessagesPerSecond = h.counters.rate.perSecond(time.Now())
+	stats.MessagesDropped = h.counters.dropped.Load()
+	stats.SlowConsumersDropped = h.counters.slowConsumers.Load()
+	stats.ConnectionsRejected = h.counters.rejected.Load()
+	stats.ForcedDisconnects = h.counters.forcedDisconnects.Load()
+	stats.MaxConnectionsPerUser = h.maxPerUser
+	return stats
+}
+
+// ConnectionInfo — одно WebSocket-соединение для администратора.
+type ConnectionInfo struct {
+	ID              string    `json:"id"`
+	UserID          uint64    `json:"user_id"`
+	ImpersonatorID  uint64    `json:"impersonator_id,omitempty"`
+	ConnectedAt     time.Time `json:"connected_at"`
+	RemoteAddr      string    `json:"remote_addr"`
+	UserAgent       string    `json:"user_agent"`
+	Rooms           []string  `json:"rooms"`
+	QueueLength     int       `json:"queue_length"`
+	MessagesSent    uint64    `json:"messages_sent"`
+	MessagesDropped uint64    `json:"messages_dropped"`
+}
+
+// Connections — соединения пользователя на этой реплике, от новых к старым; userID 0 — все соединения.
+func (h *Hub) Connections(userID uint64) []ConnectionInfo {
+	h.mu.RLock()
+	result := make([]ConnectionInfo, 0)
+	for client := range h.clients {
+		if userID != 0 && client.UserID != userID {
+			continue
+		}
+		rooms := make([]string, 0, len(client.rooms))
+		for room := range client.rooms {
+			rooms = append(rooms, room)
+		}
+		sort.Strings(rooms)
+		result = append(result, ConnectionInfo{
+			ID:              client.ID,
+			UserID:          client.UserID,
+			ImpersonatorID:  client.ImpersonatorID,
+			ConnectedAt:     client.ConnectedAt,
+			RemoteAddr:      client.RemoteAddr,
+			UserAgent:       client.UserAgent,
+			Rooms:           rooms,
+			QueueLength:     len(client.Send),
+			MessagesSent:    client.sent.Load(),
+			MessagesDropped: client.dropped.Load(),
+		})
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ConnectedAt.After(result[j].ConnectedAt) })
+	return result
+}
+
+// Disconnect закрывает соединение по ID, а при userID != 0 — все соединения пользователя.
+// Другие реплики получают ту же команду через Redis. Возвращает, сколько соединений закрыто здесь.
+func (h *Hub) Disconnect(connectionID string, userID uint64) int {
+	closed := h.disconnectLocal(connectionID, userID)
+	if h.fanout != nil {
+		h.publishFanout(fanoutMessage{
+			Origin:     h.fanout.instanceID,
+			Disconnect: &fanoutDisconnect{ConnectionID: connectionID, UserID: userID},
+		})
+	}
+	return closed
+}
+
+func (h *Hub) disconnectLocal(connectionID string, userID uint64) int {
+	if connectionID == "" && userID == 0 {
+		return 0
+	}
+	h.mu.RLock()
+	var targets []*Client
+	for client := range h.clients {
+		if (connectionID != "" && client.ID == connectionID) || (userID != 0 && client.UserID == userID) {
+			targets = append(targets, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	// Хаб уберёт клиента сам, когда ReadPump увидит закрытое соединение.
+	message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "disconnected by administrator")
+	for _, client := range targets {
+		if client.Conn == nil {
+			continue
+		}
+		_ = client.Conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait))
+		_ = client.Conn.Close()
+	}
+	h.counters.forcedDisconnects.Add(uint64(len(targets)))
+	return len(targets)
+}
+
+// WritePrometheus выводит метрики хаба этой реплики в текстовом формате Prometheus.
+func (h *Hub) WritePrometheus(w io.Writer) error {
+	stats := h.Stats()
+
+	var b strings.Builder
+	metric := func(name, kind, help string, value any) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, value)
+	}
+	metric("ws_connected_clients", "gauge", "Открытые WebSocket-соединения.", stats.Clients)
+	metric("ws_connected_users", "gauge", "Пользователи хотя бы с одним WebSocket-соединением.", stats.Users)
+	metric("ws_rooms", "gauge", "Комнаты заявок, в которых есть подписчики.", stats.Rooms)
+	metric("ws_messages_sent_total", "counter", "Сообщения, поставленные в очередь клиентам.", stats.MessagesSent)
+	metric("ws_messages_dropped_total", "counter", "Сообщения, отброшенные из-за переполненной очереди клиента.", stats.MessagesDropped)
+	metric("ws_slow_consumers_dropped_total", "counter", "Клиенты, отключённые за то, что не успевали читать рассылку.", stats.SlowConsumersDropped)
+	metric("ws_connections_rejected_total", "counter", "Подключения, отклонённые по пределу соединений пользователя.", stats.ConnectionsRejected)
+	metric("ws_forced_disconnects_total", "counter", "Соединения, закрытые администратором.", stats.ForcedDisconnects)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
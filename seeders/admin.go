@@ -0,0 +1,92 @@
+package seeders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"request-system/pkg/utils"
+)
+
+// adminRoles — роли, которые получает локальный администратор.
+var adminRoles = []string{"Базовые привилегии", "Администратор Системы"}
+
+// ErrUserExists — пользователь с таким email, логином или телефоном уже есть.
+var ErrUserExists = errors.New("пользователь с таким email, логином или телефоном уже существует")
+
+// LocalAdmin — данные локального (не из LDAP) администратора.
+type LocalAdmin struct {
+	FIO      string
+	Email    string
+	Username string
+	Phone    string
+	Password string
+}
+
+// CreateLocalAdmin создаёт локального администратора с ролями adminRoles. При первом входе
+// он должен сменить пароль.
+func CreateLocalAdmin(ctx context.Context, db *pgxpool.Pool, admin LocalAdmin) (uint64, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 OR username = $2 OR phone_number = $3)`,
+		admin.Email, admin.Username, admin.Phone).Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, ErrUserExists
+	}
+
+	userID, err := insertLocalAdmin(ctx, tx, admin)
+	if err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit(ctx)
+}
+
+func insertLocalAdmin(ctx context.Context, tx pgx.Tx, admin LocalAdmin) (uint64, error) {
+	var statusID uint64
+	if err := tx.QueryRow(ctx, "SELECT id FROM statuses WHERE code = 'ACTIVE'").Scan(&statusID); err != nil {
+		return 0, fmt.Errorf("сначала запустите наполнение статусов (--core)")
+	}
+
+	hashedPassword, err := utils.HashPassword(admin.Password)
+	if err != nil {
+		return 0, err
+	}
+
+	var userID uint64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (
+			fio, email, phone_number, password,
+			status_id, must_change_password, source_system, username
+		) VALUES ($1, $2, $3, $4, $5, true, 'LOCAL', $6)
+		RETURNING id`,
+		admin.FIO, admin.Email, admin.Phone, hashedPassword, statusID, admin.Username,
+	).Scan(&userID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка SQL при создании %s: %w", admin.Username, err)
+	}
+
+	for _, rName := range adminRoles {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO user_roles (user_id, role_id)
+			SELECT $1, id FROM roles WHERE name = $2
+			ON CONFLICT DO NOTHING
+		`, userID, rName)
+		if err != nil {
+			log.Printf("      [!] Не удалось выдать роль %s: %v", rName, err)
+		}
+	}
+	return userID, nil
+}
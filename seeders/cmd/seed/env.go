@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	"request-system/pkg/config"
+	"request-system/pkg/database/postgresql"
+)
+
+// adminEnv — конфигурация и подключения, общие для команд. Открываются при первом обращении,
+// чтобы --help и ошибки в аргументах не требовали доступа к базе.
+type adminEnv struct {
+	cfg   *config.Config
+	db    *pgxpool.Pool
+	redis *redis.Client
+}
+
+func (e *adminEnv) config() *config.Config {
+	if e.cfg == nil {
+		e.cfg = config.New()
+	}
+	return e.cfg
+}
+
+func (e *adminEnv) database() *pgxpool.Pool {
+	if e.db == nil {
+		cfg := e.config()
+		log.Println("📦 Используется DSN:", cfg.Postgres.DSN)
+		e.db = postgresql.ConnectDB(cfg.Postgres, nil)
+	}
+	return e.db
+}
+
+func (e *adminEnv) cache() repositories.CacheRepositoryInterface {
+	if e.redis == nil {
+		cfg := e.config()
+		e.redis = redis.NewClient(&redis.Options{Addr: cfg.Redis.Address, Password: cfg.Redis.Password})
+	}
+	return repositories.NewRedisCacheRepository(e.redis)
+}
+
+func (e *adminEnv) close() {
+	if e.db != nil {
+		e.db.Close()
+	}
+	if e.redis != nil {
+		_ = e.redis.Close()
+	}
+}
+
+// audit пишет операцию в журнал аудита: без автора, method = CLI, path — имя команды.
+func (e *adminEnv) audit(ctx context.Context, command, action, entity string, entityID uint64, before, after any) {
+	id := strconv.FormatUint(entityID, 10)
+	entry := &entities.AuditLogEntry{
+		Action:     action,
+		Entity:     entity,
+		EntityID:   &id,
+		Method:     "CLI",
+		Path:       "seed " + command,
+		StatusCode: http.StatusOK,
+	}
+	if before != nil {
+		entry.Before, _ = json.Marshal(before)
+	}
+	if after != nil {
+		entry.After, _ = json.Marshal(after)
+	}
+	if err := repositories.NewAuditLogRepository(e.database(), zap.NewNop()).Create(ctx, entry); err != nil {
+		log.Printf("[!] Не удалось записать операцию в журнал аудита: %v", err)
+	}
+}
+
+type userRef struct {
+	ID       uint64
+	FIO      string
+	Email    string
+	Username *string
+}
+
+// findUser ищет активного пользователя по ID, email или логину.
+func (e *adminEnv) findUser(ctx context.Context, ref string) (*userRef, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, errors.New("не указан пользователь (--user)")
+	}
+
+	query := `SELECT id, fio, email, username FROM users WHERE deleted_at IS NULL AND `
+	var arg any = ref
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		query += "id = $1"
+		arg = id
+	} else {
+		query += "(LOWER(email) = LOWER($1) OR LOWER(username) = LOWER($1))"
+	}
+
+	var user userRef
+	err := e.database().QueryRow(ctx, query, arg).Scan(&user.ID, &user.FIO, &user.Email, &user.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("пользователь %q не найден", ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (u *userRef) String() string {
+	return fmt.Sprintf("#%d %s <%s>", u.ID, u.FIO, u.Email)
+}
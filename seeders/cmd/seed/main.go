@@ -1,62 +1,114 @@
 package main
 
 import (
-	"flag"
+	"errors"
 	"log"
+	"os"
+
+	"github.com/spf13/cobra"
 
-	"request-system/pkg/config"
-	"request-system/pkg/database/postgresql"
 	"request-system/seeders"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 func main() {
-	log.Println("======================================================")
-	log.Println("       🌱 СИСТЕМА СИДЕРОВ (Наполнение БД)           ")
-	log.Println("======================================================")
+	root := newRootCmd()
+	root.SetArgs(normalizeLegacyArgs(os.Args[1:]))
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
 
-	// --- Определяем флаги ---
-	runCore := flag.Bool("core", false, "Запустить наполнение базовых справочников (статусы, права и т.д.)")
-	runRoles := flag.Bool("roles", false, "Запустить создание ролей и Супер-Администратора")
-	runAll := flag.Bool("all", false, "Запустить все базовые сидеры (core + roles)")
-
-	flag.Parse()
-
-	// ИСПРАВЛЕНИЕ: Убрали !*runEquipment из проверки ниже
-	if !*runCore && !*runRoles && !*runAll {
-		log.Println("❌ Не выбран ни один сидер для запуска.")
-		log.Println("")
-		log.Println("Доступные флаги:")
-		flag.PrintDefaults()
-		log.Println("")
-		log.Println("Примеры использования:")
-		log.Println("  go run ./seeders/cmd/seed/main.go -core")
-		log.Println("  go run ./seeders/cmd/seed/main.go -roles")
-		log.Println("  go run ./seeders/cmd/seed/main.go -all")
-		log.Println("======================================================")
-		return
+func newRootCmd() *cobra.Command {
+	env := &adminEnv{}
+
+	var runCore, runRoles, runAll, runDemo bool
+	demo := seeders.DemoOptions{}
+	root := &cobra.Command{
+		Use:   "seed",
+		Short: "Сидеры и служебные операции над базой без доступа через psql",
+		Example: "  go run ./seeders/cmd/seed --all\n" +
+			"  go run ./seeders/cmd/seed reset-password --user admin_test",
+		SilenceUsage:      true,
+		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !runCore && !runRoles && !runAll && !runDemo {
+				return cmd.Help()
+			}
+			if runDemo && demo.Users < 2 {
+				return errors.New("--demo-users: нужно хотя бы 2 пользователя")
+			}
+			var demoOpts *seeders.DemoOptions
+			if runDemo {
+				demoOpts = &demo
+			}
+			return runSeeders(env, runCore || runAll, runRoles || runAll, demoOpts)
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			env.close()
+		},
 	}
 
-	// Подключаемся к БД
-	cfg := config.New()
-	log.Println("📦 Используется DSN:", cfg.Postgres.DSN)
-	dbPool := postgresql.ConnectDB(cfg.Postgres.DSN)
-	defer dbPool.Close()
+	root.Flags().BoolVar(&runCore, "core", false, "Запустить наполнение базовых справочников (статусы, права и т.д.)")
+	root.Flags().BoolVar(&runRoles, "roles", false, "Запустить создание ролей и Супер-Администратора")
+	root.Flags().BoolVar(&runAll, "all", false, "Запустить все базовые сидеры (core + roles)")
+	root.Flags().BoolVar(&runDemo, "demo", false, "Наполнить базу демо-данными (не для production)")
+	root.Flags().IntVar(&demo.Users, "demo-users", 1000, "Сколько демо-пользователей должно быть в базе")
+	root.Flags().IntVar(&demo.Orders, "demo-orders", 20000, "Сколько демо-заявок добавить")
+	root.Flags().IntVar(&demo.Months, "demo-months", 6, "За сколько последних месяцев распределить заявки")
+	root.Flags().Uint64Var(&demo.Seed, "demo-seed", 1, "Зерно генератора: один и тот же seed даёт те же данные")
+
+	root.AddCommand(
+		newCreateAdminCmd(env),
+		newResetPasswordCmd(env),
+		newGrantRoleCmd(env),
+		newRelinkTelegramCmd(env),
+		newReindexSearchCmd(env),
+		newRequeueNotificationsCmd(env),
+		newMigrateCmd(env),
+		newHistoryPartitionsCmd(env),
+	)
+	return root
+}
+
+func runSeeders(env *adminEnv, core, roles bool, demo *seeders.DemoOptions) error {
+	log.Println("======================================================")
+	log.Println("       🌱 СИСТЕМА СИДЕРОВ (Наполнение БД)           ")
+	log.Println("======================================================")
 
+	dbPool := env.database()
 	log.Println("======================================================")
 
-	// Запуск сидеров
-	if *runAll || *runCore {
+	if core {
 		seeders.SeedCoreDictionaries(dbPool)
 		log.Println("======================================================")
 	}
-
-	if *runAll || *runRoles {
-		seeders.SeedRolesAndAdmin(dbPool, cfg)
+	if roles {
+		seeders.SeedRolesAndAdmin(dbPool, env.config())
+		log.Println("======================================================")
+	}
+	if demo != nil {
+		seeders.SeedDemo(dbPool, *demo)
 		log.Println("======================================================")
 	}
 
 	log.Println("✅ Все операции сидирования успешно завершены.")
 	log.Println("======================================================")
+	return nil
+}
+
+// normalizeLegacyArgs переводит флаги старого вида (-core) в --core, чтобы прежние
+// команды из документации и скриптов продолжали работать.
+func normalizeLegacyArgs(args []string) []string {
+	result := make([]string, len(args))
+	for i, arg := range args {
+		switch arg {
+		case "-core", "-roles", "-all", "-demo":
+			result[i] = "-" + arg
+		default:
+			result[i] = arg
+		}
+	}
+	return result
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/repositories"
+	apperrors "request-system/pkg/errors"
+)
+
+func newReindexSearchCmd(env *adminEnv) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex-search",
+		Short: "Перестроить индексы полнотекстового поиска (pg_trgm) и обновить статистику",
+		Long: "Перестраивает GIN-индексы gin_trgm_ops через REINDEX CONCURRENTLY — без блокировки записи —\n" +
+			"и выполняет ANALYZE их таблиц. Помогает, когда поиск по заявкам замедлился из-за разросшихся индексов.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			db := env.database()
+
+			rows, err := db.Query(ctx, `
+				SELECT tablename, indexname FROM pg_indexes
+				WHERE schemaname = 'public' AND indexdef LIKE '%gin_trgm_ops%'
+				ORDER BY tablename, indexname`)
+			if err != nil {
+				return err
+			}
+			type searchIndex struct{ table, name string }
+			indexes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (searchIndex, error) {
+				var idx searchIndex
+				return idx, row.Scan(&idx.table, &idx.name)
+			})
+			if err != nil {
+				return err
+			}
+			if len(indexes) == 0 {
+				cmd.Println("Индексы поиска не найдены.")
+				return nil
+			}
+
+			tables := map[string]bool{}
+			for _, idx := range indexes {
+				started := time.Now()
+				if _, err := db.Exec(ctx, "REINDEX INDEX CONCURRENTLY "+pgx.Identifier{"public", idx.name}.Sanitize()); err != nil {
+					return fmt.Errorf("индекс %s: %w", idx.name, err)
+				}
+				tables[idx.table] = true
+				cmd.Printf("  ✔ %s (%s) — %s\n", idx.name, idx.table, time.Since(started).Round(time.Millisecond))
+			}
+			for table := range tables {
+				if _, err := db.Exec(ctx, "ANALYZE "+pgx.Identifier{"public", table}.Sanitize()); err != nil {
+					return fmt.Errorf("ANALYZE %s: %w", table, err)
+				}
+			}
+			cmd.Printf("✅ Перестроено индексов: %d.\n", len(indexes))
+			return nil
+		},
+	}
+}
+
+func newRequeueNotificationsCmd(env *adminEnv) *cobra.Command {
+	var id uint64
+	var channel string
+	var since time.Duration
+	cmd := &cobra.Command{
+		Use:   "requeue-notifications",
+		Short: "Вернуть в очередь уведомления из dead-letter",
+		Long: "Возвращает уведомления со статусом dead в очередь с обнулённым счётчиком попыток.\n" +
+			"Работающее приложение отправит их в течение нескольких секунд.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch channel {
+			case "", entities.NotificationChannelTelegram, entities.NotificationChannelWebSocket:
+			default:
+				return fmt.Errorf("--channel: допустимы %s и %s", entities.NotificationChannelTelegram, entities.NotificationChannelWebSocket)
+			}
+
+			ctx := cmd.Context()
+			repo := repositories.NewNotificationOutboxRepository(env.database(), zap.NewNop())
+
+			if id != 0 {
+				if err := repo.Requeue(ctx, id); err != nil {
+					if errors.Is(err, apperrors.ErrNotFound) {
+						return fmt.Errorf("уведомление #%d не найдено среди dead-letter", id)
+					}
+					return err
+				}
+				cmd.Printf("✅ Уведомление #%d возвращено в очередь.\n", id)
+				return nil
+			}
+
+			var from time.Time
+			if since > 0 {
+				from = time.Now().Add(-since)
+			}
+			count, err := repo.RequeueDead(ctx, channel, from)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("✅ Возвращено в очередь уведомлений: %d.\n", count)
+			return nil
+		},
+	}
+	cmd.Flags().Uint64Var(&id, "id", 0, "Вернуть одно уведомление по ID")
+	cmd.Flags().StringVar(&channel, "channel", "", "Только канал telegram или websocket")
+	cmd.Flags().DurationVar(&since, "since", 0, "Только упавшие за этот период, например 24h")
+	return cmd
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/spf13/cobra"
+
+	"request-system/pkg/database/postgresql"
+)
+
+func newMigrateCmd(env *adminEnv) *cobra.Command {
+	var dir string
+	var confirmed bool
+
+	// withMigrator открывает отдельное от пула соединение: у пула есть statement_timeout
+	withMigrator := func(cmd *cobra.Command, fn func(ctx context.Context, migrator *goose.Provider) error) error {
+		db, err := sql.Open("pgx", env.config().Postgres.DSN)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		migrator, err := postgresql.NewMigrator(db, dir)
+		if err != nil {
+			return err
+		}
+		return fn(cmd.Context(), migrator)
+	}
+	requireConfirmation := func(action string) error {
+		if !confirmed {
+			return fmt.Errorf("%s откатывает схему БД; повторите с --yes", action)
+		}
+		return nil
+	}
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Миграции схемы БД: статус, применение и контролируемый откат",
+		Long: "Управляет миграциями goose из --dir. Применение и откат идут под advisory-блокировкой,\n" +
+			"поэтому команда безопасна при работающих репликах с DB_AUTO_MIGRATE=true.",
+	}
+	cmd.PersistentFlags().StringVar(&dir, "dir", postgresql.MigrationsDir, "Каталог миграций")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Показать применённые и ожидающие миграции",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(cmd, func(ctx context.Context, migrator *goose.Provider) error {
+				statuses, err := migrator.Status(ctx)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ПРИМЕНЕНА\tСОСТОЯНИЕ\tМИГРАЦИЯ")
+				pending := 0
+				for _, st := range statuses {
+					appliedAt := "—"
+					if st.State == goose.StateApplied {
+						appliedAt = st.AppliedAt.In(time.Local).Format("2006-01-02 15:04:05")
+					} else {
+						pending++
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", appliedAt, st.State, st.Source.Path)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+
+				current, err := migrator.GetDBVersion(ctx)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("\nВерсия схемы: %d, ожидают применения: %d\n", current, pending)
+				return nil
+			})
+		},
+	}
+
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Применить все ожидающие миграции",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(cmd, func(ctx context.Context, migrator *goose.Provider) error {
+				results, err := migrator.Up(ctx)
+				printMigrationResults(cmd, results)
+				return err
+			})
+		},
+	}
+
+	upTo := &cobra.Command{
+		Use:   "up-to VERSION",
+		Short: "Применить ожидающие миграции до версии VERSION включительно",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("версия должна быть числом: %w", err)
+			}
+			return withMigrator(cmd, func(ctx context.Context, migrator *goose.Provider) error {
+				results, err := migrator.UpTo(ctx, version)
+				printMigrationResults(cmd, results)
+				return err
+			})
+		},
+	}
+
+	downOne := &cobra.Command{
+		Use:   "down-one",
+		Short: "Откатить последнюю применённую миграцию",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireConfirmation("down-one"); err != nil {
+				return err
+			}
+			return withMigrator(cmd, func(ctx context.Context, migrator *goose.Provider) error {
+				result, err := migrator.Down(ctx)
+				if errors.Is(err, goose.ErrNoNextVersion) {
+					cmd.Println("Нет применённых миграций.")
+					return nil
+				}
+				printMigrationResults(cmd, []*goose.MigrationResult{result})
+				return err
+			})
+		},
+	}
+
+	redo := &cobra.Command{
+		Use:   "redo",
+		Short: "Откатить и заново применить последнюю миграцию",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireConfirmation("redo"); err != nil {
+				return err
+			}
+			return withMigrator(cmd, func(ctx context.Context, migrator *goose.Provider) error {
+				down, err := migrator.Down(ctx)
+				printMigrationResults(cmd, []*goose.MigrationResult{down})
+				if err != nil {
+					return err
+				}
+				result, err := migrator.ApplyVersion(ctx, down.Source.Version, true)
+				printMigrationResults(cmd, []*goose.MigrationResult{result})
+				return err
+			})
+		},
+	}
+
+	for _, c := range []*cobra.Command{downOne, redo} {
+		c.Flags().BoolVar(&confirmed, "yes", false, "Подтвердить откат")
+	}
+	cmd.AddCommand(status, up, upTo, downOne, redo)
+	return cmd
+}
+
+func printMigrationResults(cmd *cobra.Command, results []*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil || result.Source == nil {
+			continue
+		}
+		mark := "✔"
+		if result.Error != nil {
+			mark = "✖"
+		}
+		cmd.Printf("  %s %s %s (%s)\n", mark, result.Direction, result.Source.Path, result.Duration.Round(time.Millisecond))
+	}
+	if len(results) == 0 {
+		cmd.Println("Нет миграций для применения.")
+	}
+}
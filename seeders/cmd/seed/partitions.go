@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/internal/services"
+)
+
+func newHistoryPartitionsCmd(env *adminEnv) *cobra.Command {
+	repo := func() repositories.OrderHistoryPartitionRepositoryInterface {
+		return repositories.NewOrderHistoryPartitionRepository(env.database(), zap.NewNop())
+	}
+
+	cmd := &cobra.Command{
+		Use:   "history-partitions",
+		Short: "Годовые секции order_history: состояние, создание и перенос старой истории",
+		Long: "order_history секционирована по годам created_at (по UTC). История до секционирования лежит\n" +
+			"в order_history_legacy, пока move-legacy не разнесёт её по годам.",
+	}
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Показать секции и годы, оставшиеся в order_history_legacy",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			partitions, err := repo().FindPartitions(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "СЕКЦИЯ\tДИАПАЗОН\tСТРОК (ОЦЕНКА)")
+			for _, p := range partitions {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", p.Name, p.Bound, p.Rows)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			years, err := repo().LegacyYears(ctx)
+			if err != nil {
+				return err
+			}
+			if len(years) == 0 {
+				cmd.Println("\nВся история разнесена по годовым секциям.")
+				return nil
+			}
+			cmd.Printf("\nВ %s остались годы: %v\n", repositories.OrderHistoryLegacyPartition, years)
+			return nil
+		},
+	}
+
+	ensure := &cobra.Command{
+		Use:   "ensure",
+		Short: "Создать секции текущего и следующего года (приложение делает это само раз в сутки)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := services.NewOrderHistoryPartitionService(repo(), zap.NewNop()).EnsureUpcoming(cmd.Context()); err != nil {
+				return err
+			}
+			cmd.Println("✅ Секции текущего и следующего года на месте.")
+			return nil
+		},
+	}
+
+	var confirmed bool
+	var limit int
+	moveLegacy := &cobra.Command{
+		Use:   "move-legacy",
+		Short: "Разнести историю из order_history_legacy по годовым секциям, начиная с последнего года",
+		Long: "Каждый год переносится своей транзакцией. Записи копируются без блокировки order_history,\n" +
+			"но на время переподключения order_history_legacy (проверка оставшихся в ней строк) запись\n" +
+			"истории ждёт — запускайте в нерабочее время. Прерванный перенос можно просто повторить.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !confirmed {
+				return errors.New("move-legacy перестраивает секции order_history; повторите с --yes")
+			}
+			return moveLegacyHistory(cmd, repo(), limit)
+		},
+	}
+	moveLegacy.Flags().BoolVar(&confirmed, "yes", false, "Подтвердить перенос")
+	moveLegacy.Flags().IntVar(&limit, "years", 0, "Перенести не больше стольких лет (0 — все)")
+
+	cmd.AddCommand(status, ensure, moveLegacy)
+	return cmd
+}
+
+func moveLegacyHistory(cmd *cobra.Command, repo repositories.OrderHistoryPartitionRepositoryInterface, limit int) error {
+	ctx := cmd.Context()
+	years, err := repo.LegacyYears(ctx)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(years) > limit {
+		years = years[:limit]
+	}
+	if len(years) == 0 {
+		cmd.Println("Переносить нечего.")
+		return nil
+	}
+
+	for _, year := range years {
+		started := time.Now()
+		moved, err := repo.MoveLegacyYear(ctx, year)
+		if err != nil {
+			return fmt.Errorf("год %d: %w", year, err)
+		}
+		cmd.Printf("  ✔ %s: %d записей — %s\n", repositories.OrderHistoryYearPartition(year), moved, time.Since(started).Round(time.Millisecond))
+	}
+	cmd.Printf("✅ Перенесено лет: %d.\n", len(years))
+	return nil
+}
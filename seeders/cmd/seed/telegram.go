@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+
+	"request-system/internal/entities"
+)
+
+func newRelinkTelegramCmd(env *adminEnv) *cobra.Command {
+	var userRef string
+	var chatID int64
+	var unlink bool
+	cmd := &cobra.Command{
+		Use:   "relink-telegram",
+		Short: "Привязать Telegram-чат к пользователю или отвязать его",
+		Long: "Привязывает чат --chat-id к пользователю. Если чат был привязан к другому пользователю,\n" +
+			"он отвязывается от него: один чат — один пользователь. С --unlink отвязывает чат пользователя.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if unlink == (chatID != 0) {
+				return errors.New("укажите либо --chat-id, либо --unlink")
+			}
+
+			ctx := cmd.Context()
+			user, err := env.findUser(ctx, userRef)
+			if err != nil {
+				return err
+			}
+
+			tx, err := env.database().Begin(ctx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback(ctx)
+
+			var previousChatID *int64
+			if err := tx.QueryRow(ctx, `SELECT telegram_chat_id FROM users WHERE id = $1 FOR UPDATE`, user.ID).Scan(&previousChatID); err != nil {
+				return err
+			}
+
+			var newChatID *int64
+			var detachedFrom *uint64
+			if !unlink {
+				newChatID = &chatID
+				err := tx.QueryRow(ctx, `
+					UPDATE users SET telegram_chat_id = NULL, updated_at = NOW()
+					WHERE telegram_chat_id = $1 AND id <> $2
+					RETURNING id`, chatID, user.ID).Scan(&detachedFrom)
+				if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+					return err
+				}
+			}
+			if _, err := tx.Exec(ctx, `UPDATE users SET telegram_chat_id = $1, updated_at = NOW() WHERE id = $2`, newChatID, user.ID); err != nil {
+				return err
+			}
+			if err := tx.Commit(ctx); err != nil {
+				return err
+			}
+
+			env.audit(ctx, "relink-telegram", entities.AuditActionUpdate, "user", user.ID,
+				map[string]any{"telegram_chat_id": previousChatID},
+				map[string]any{"telegram_chat_id": newChatID})
+			if detachedFrom != nil {
+				env.audit(ctx, "relink-telegram", entities.AuditActionUpdate, "user", *detachedFrom,
+					map[string]any{"telegram_chat_id": chatID},
+					map[string]any{"telegram_chat_id": nil})
+				cmd.Printf("Чат %d отвязан от пользователя #%d.\n", chatID, *detachedFrom)
+			}
+
+			if unlink {
+				cmd.Printf("✅ Telegram отвязан от %s.\n", user)
+			} else {
+				cmd.Printf("✅ Чат %d привязан к %s.\n", chatID, user)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&userRef, "user", "", "ID, email или логин пользователя")
+	cmd.Flags().Int64Var(&chatID, "chat-id", 0, "ID Telegram-чата")
+	cmd.Flags().BoolVar(&unlink, "unlink", false, "Отвязать Telegram от пользователя")
+	_ = cmd.MarkFlagRequired("user")
+	return cmd
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"request-system/internal/entities"
+	"request-system/internal/services"
+	"request-system/pkg/constants"
+	"request-system/pkg/utils"
+	"request-system/seeders"
+)
+
+const generatedPasswordLength = 16
+
+func newCreateAdminCmd(env *adminEnv) *cobra.Command {
+	var admin seeders.LocalAdmin
+	cmd := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Создать локального администратора (без LDAP)",
+		Long: "Создаёт локального пользователя с ролями «Базовые привилегии» и «Администратор Системы».\n" +
+			"Если --password не задан, пароль генерируется и печатается. При первом входе пароль нужно сменить.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			generated := admin.Password == ""
+			if generated {
+				admin.Password = generatePassword()
+			}
+
+			userID, err := seeders.CreateLocalAdmin(ctx, env.database(), admin)
+			if err != nil {
+				return err
+			}
+			env.audit(ctx, "create-admin", entities.AuditActionCreate, "user", userID, nil,
+				map[string]any{"email": admin.Email, "username": admin.Username, "source_system": "LOCAL"})
+
+			cmd.Printf("✅ Администратор #%d создан. Логин: %s\n", userID, admin.Username)
+			if generated {
+				cmd.Printf("   Временный пароль: %s\n", admin.Password)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&admin.Email, "email", "", "Email администратора")
+	cmd.Flags().StringVar(&admin.Username, "username", "", "Логин для входа")
+	cmd.Flags().StringVar(&admin.Phone, "phone", "", "Телефон (уникальный, до 12 символов)")
+	cmd.Flags().StringVar(&admin.FIO, "fio", "Администратор", "ФИО")
+	cmd.Flags().StringVar(&admin.Password, "password", "", "Пароль (по умолчанию генерируется)")
+	for _, name := range []string{"email", "username", "phone"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+	return cmd
+}
+
+func newResetPasswordCmd(env *adminEnv) *cobra.Command {
+	var userRef, password string
+	cmd := &cobra.Command{
+		Use:   "reset-password",
+		Short: "Сбросить пароль пользователя и снять блокировку входа",
+		Long: "Задаёт новый пароль (или генерирует его), требует сменить его при следующем входе\n" +
+			"и сбрасывает счётчик неудачных попыток и блокировку входа.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			user, err := env.findUser(ctx, userRef)
+			if err != nil {
+				return err
+			}
+
+			generated := password == ""
+			if generated {
+				password = generatePassword()
+			}
+			hashedPassword, err := utils.HashPassword(password)
+			if err != nil {
+				return err
+			}
+			_, err = env.database().Exec(ctx, `
+				UPDATE users SET password = $1, must_change_password = true, updated_at = NOW()
+				WHERE id = $2`, hashedPassword, user.ID)
+			if err != nil {
+				return err
+			}
+
+			if err := env.cache().Del(ctx,
+				fmt.Sprintf(constants.CacheKeyLockout, user.ID),
+				fmt.Sprintf(constants.CacheKeyLoginAttempts, user.ID),
+			); err != nil {
+				cmd.PrintErrf("[!] Пароль изменён, но блокировку входа снять не удалось: %v\n", err)
+			}
+			env.audit(ctx, "reset-password", entities.AuditActionUpdate, "user", user.ID, nil,
+				map[string]any{"must_change_password": true})
+
+			cmd.Printf("✅ Пароль пользователя %s сброшен.\n", user)
+			if generated {
+				cmd.Printf("   Временный пароль: %s\n", password)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&userRef, "user", "", "ID, email или логин пользователя")
+	cmd.Flags().StringVar(&password, "password", "", "Новый пароль (по умолчанию генерируется)")
+	_ = cmd.MarkFlagRequired("user")
+	return cmd
+}
+
+func newGrantRoleCmd(env *adminEnv) *cobra.Command {
+	var userRef, roleRef string
+	var revoke bool
+	cmd := &cobra.Command{
+		Use:   "grant-role",
+		Short: "Выдать пользователю роль (или отозвать её с --revoke)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			user, err := env.findUser(ctx, userRef)
+			if err != nil {
+				return err
+			}
+			roleID, roleName, err := findRole(ctx, env, roleRef)
+			if err != nil {
+				return err
+			}
+
+			query := `INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+			if revoke {
+				query = `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`
+			}
+			tag, err := env.database().Exec(ctx, query, user.ID, roleID)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				if revoke {
+					cmd.Printf("У пользователя %s нет роли «%s».\n", user, roleName)
+				} else {
+					cmd.Printf("У пользователя %s уже есть роль «%s».\n", user, roleName)
+				}
+				return nil
+			}
+
+			// Права кэшируются в Redis: без сброса изменение вступит в силу только по истечении кэша
+			permissionService := services.NewAuthPermissionService(nil, nil, env.cache(), zap.NewNop(), 0)
+			if err := permissionService.InvalidateUserPermissionsCache(ctx, user.ID); err != nil {
+				cmd.PrintErrf("[!] Не удалось сбросить кэш прав, изменение вступит в силу с истечением кэша: %v\n", err)
+			}
+
+			env.audit(ctx, "grant-role", entities.AuditActionUpdate, "user", user.ID, nil,
+				map[string]any{"role_id": roleID, "role": roleName, "granted": !revoke})
+
+			if revoke {
+				cmd.Printf("✅ Роль «%s» отозвана у %s.\n", roleName, user)
+			} else {
+				cmd.Printf("✅ Роль «%s» выдана %s.\n", roleName, user)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&userRef, "user", "", "ID, email или логин пользователя")
+	cmd.Flags().StringVar(&roleRef, "role", "", "ID или название роли")
+	cmd.Flags().BoolVar(&revoke, "revoke", false, "Отозвать роль вместо выдачи")
+	_ = cmd.MarkFlagRequired("user")
+	_ = cmd.MarkFlagRequired("role")
+	return cmd
+}
+
+func findRole(ctx context.Context, env *adminEnv, ref string) (uint64, string, error) {
+	query := `SELECT id, name FROM roles WHERE name = $1`
+	var arg any = ref
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		query = `SELECT id, name FROM roles WHERE id = $1`
+		arg = id
+	}
+
+	var id uint64
+	var name string
+	err := env.database().QueryRow(ctx, query, arg).Scan(&id, &name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", fmt.Errorf("роль %q не найдена", ref)
+	}
+	return id, name, err
+}
+
+// generatePassword — случайный пароль из букв и цифр для временной выдачи пользователю.
+func generatePassword() string {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+	password := make([]byte, generatedPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			panic(err)
+		}
+		password[i] = alphabet[n.Int64()]
+	}
+	return string(password)
+}
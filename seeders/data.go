@@ -46,6 +46,8 @@ var permissionsData = []struct {
 	{"order:update:comment", "Добавление 'Комментария'"},
 	{"order:update:file", "Прикрепление файла"},
 	{"order:update:reopen", "Переоткрытие закрытой заявки"},
+	{"order:merge", "Объединение заявки-дубликата с основной заявкой"},
+	{"order:grant_access", "Выдача и отзыв разового доступа к заявке"},
 	{"user:create", "Создание пользователя"},
 	{"user:view", "Просмотр пользователя"},
 	{"user:update", "Обновление пользователя"},
@@ -61,6 +63,7 @@ var permissionsData = []struct {
 	{"permission:update", "Обновление системной привилегии"},
 	{"permission:delete", "Удаление системной привилегии"},
 	{"permission:view", "Просмотр системной привилегии"},
+	{"permission:flush_cache", "Сброс кэша прав пользователей"},
 	{"status:create", "Создание статуса"},
 	{"status:view", "Просмотр статуса"},
 	{"status:update", "Обновление статуса"},
@@ -105,13 +108,35 @@ var permissionsData = []struct {
 	{"order_rule:view", "Просмотр правила маршрутизации"},
 	{"order_rule:update", "Обновление правила маршрутизации"},
 	{"order_rule:delete", "Удаление правила маршрутизации"},
+	{"team:view", "Просмотр команд исполнителей"},
+	{"team:manage", "Управление командами исполнителей и их участниками"},
+	{"on_call:view", "Просмотр графиков дежурств и текущего дежурного"},
+	{"on_call:manage", "Управление графиками дежурств и заменами"},
+	{"skill:view", "Просмотр справочника навыков"},
+	{"skill:manage", "Управление справочником навыков"},
+	{"resolution_code:view", "Просмотр справочника кодов решения"},
+	{"resolution_code:manage", "Управление справочником кодов решения"},
 	{"report:view", "Просмотр отчета"},
 	{"dashboard:view", "Просмотр дашборда"},
+	{"notification:manage", "Просмотр и повторная отправка недоставленных уведомлений"},
 	{"order:create:order_type_id", "Создание заявки: Поле 'Тип заявки'"},
 	{"order:create:status_id", "Создание заявки: Поле 'Статус'"},
 	{"integration:view", "Позволяет просматривать статус и информацию по интеграциям"},
 	{"integration:sync:run", "Даёт право запускать ручную синхронизацию данных"},
 	{"integration:update", "Позволяет изменять настройки интеграций (адреса, ключи)"},
+	{"webhook:manage", "Управление webhook-подписками и просмотр журнала доставок"},
+	{"job:manage", "Просмотр и перезапуск фоновых заданий"},
+	{"config:manage", "Просмотр и перечитывание настроек без перезапуска"},
+	{"import:run", "Импорт пользователей и справочников из CSV"},
+	{"event:replay", "Повторная публикация событий истории заявок"},
+	{"audit:view", "Просмотр журнала аудита"},
+	{"user:impersonate", "Вход под другим пользователем (все запросы помечаются в журнале аудита)"},
+	{"user:anonymize", "Обезличивание уволенных сотрудников"},
+	{"retention:manage", "Правила хранения вложений и истории, удержание заявок"},
+	{"backup:manage", "Резервные копии базы данных и проверка их восстановления"},
+	{"business_calendar:manage", "Рабочий календарь для SLA: рабочие дни, часы и праздники"},
+	{"websocket:manage", "Статистика WebSocket, соединения пользователей и их принудительное отключение"},
+	{"analytics:read", "Чтение выгрузки заявок для BI-систем"},
 	{"user:manage_ad_link", "Управление привязкой логина Active Directory"},
 }
 
@@ -130,16 +155,28 @@ var statusesData = []struct {
 	{"Уточнение", "CLARIFICATION", 1},
 	{"Подтвержден", "CONFIRMED", 1},
 	{"Сервис", "SERVICE", 1},
+	{"Дубликат", "DUPLICATE", 3},
 }
 
+// prioritiesData — SortOrder задаёт порядок в списках, SLAHours — подсказку срока выполнения.
 var prioritiesData = []struct {
-	Name, Code string
-	Rate       int
+	Name, Code, Color string
+	Rate, SortOrder   int
+	SLAHours          int
+}{
+	{"Низкий", "LOW", "#2E7D32", 4, 1, 168},
+	{"Средний", "MEDIUM", "#F9A825", 3, 2, 72},
+	{"Высокий", "HIGH", "#EF6C00", 2, 3, 24},
+	{"Критический", "CRITICAL", "#C62828", 1, 4, 4},
+}
+
+// priorityMatrixData — приоритет по влиянию и срочности (коды из prioritiesData).
+var priorityMatrixData = []struct {
+	Impact, Urgency, PriorityCode string
 }{
-	{"Низкий", "LOW", 4},
-	{"Средний", "MEDIUM", 3},
-	{"Высокий", "HIGH", 2},
-	{"Критический", "CRITICAL", 1},
+	{"high", "high", "CRITICAL"}, {"high", "medium", "HIGH"}, {"high", "low", "MEDIUM"},
+	{"medium", "high", "HIGH"}, {"medium", "medium", "MEDIUM"}, {"medium", "low", "LOW"},
+	{"low", "high", "MEDIUM"}, {"low", "medium", "LOW"}, {"low", "low", "LOW"},
 }
 
 var rolesData = []struct {
@@ -177,4 +214,24 @@ var orderTypesData = []struct {
 }{
 	{"Оборудование", "EQUIPMENT"},
 	{"Простая заявка", "ADMINISTRATIVE"},
+	{"Обращение с портала", "PORTAL"},
+}
+
+// orderTypeValidationRulesData — обязательные поля новых типов заявок (order_type_validation_rules).
+var orderTypeValidationRulesData = map[string][]struct {
+	Field    string
+	Message  string
+	OnUpdate bool
+}{
+	"EQUIPMENT": {
+		{"equipment_id", "Пожалуйста, укажите оборудование.", false},
+		{"equipment_type_id", "Пожалуйста, выберите тип оборудования.", false},
+		{"priority_id", "Пожалуйста, укажите приоритет.", false},
+	},
+	"ADMINISTRATIVE": {
+		{"comment", "Для данного типа заявки необходимо заполнить поле «Комментарий».", true},
+	},
+	"PORTAL": {
+		{"comment", "Для данного типа заявки необходимо заполнить поле «Комментарий».", true},
+	},
 }
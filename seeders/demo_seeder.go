@@ -0,0 +1,577 @@
+package seeders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"request-system/internal/repositories"
+	"request-system/pkg/constants"
+	"request-system/pkg/utils"
+)
+
+const (
+	// demoEmailDomain — по нему демо-пользователи отличаются от настоящих.
+	demoEmailDomain = "demo.local"
+	// demoPassword — общий пароль демо-пользователей: bcrypt на каждого сделал бы сидер минутным.
+	demoPassword = "Demo12345!"
+	// demoAttachmentPath — файл-заглушка в uploads, на который ссылаются все демо-вложения.
+	demoAttachmentPath = "demo/placeholder.txt"
+	// demoOrderChunk — заявок в одной транзакции.
+	demoOrderChunk = 500
+)
+
+// DemoOptions — объём демо-данных.
+type DemoOptions struct {
+	Users  int
+	Orders int
+	Months int
+	// Seed делает данные воспроизводимыми: один и тот же Seed даёт те же заявки.
+	Seed uint64
+}
+
+var demoDepartments = []struct {
+	Name   string
+	Otdels []string
+}{
+	{"Демо: Департамент ИТ", []string{"Сопровождение", "Сети и связь", "Разработка"}},
+	{"Демо: Административно-хозяйственный департамент", []string{"Ремонт", "Транспорт", "Снабжение"}},
+	{"Демо: Операционный департамент", []string{"Кассовые операции", "Обслуживание клиентов"}},
+	{"Демо: Департамент безопасности", []string{"Физическая охрана", "Информационная безопасность"}},
+}
+
+var (
+	demoLastNames  = []string{"Рахимов", "Саидов", "Каримов", "Назаров", "Шарипов", "Юсупов", "Иванов", "Мирзоев", "Холов", "Одинаев", "Сафаров", "Ахмедов"}
+	demoFirstNames = []string{"Фаррух", "Бахтиёр", "Шахзод", "Алишер", "Рустам", "Дилшод", "Сергей", "Фируз", "Умед", "Джамшед", "Парвиз", "Манучехр"}
+	demoPatronyms  = []string{"Абдуллоевич", "Саидович", "Рахимович", "Каримович", "Иванович", "Назарович"}
+
+	demoProblems = []string{
+		"Не печатает принтер", "Не работает банкомат", "Нет доступа к сети", "Сломался стул",
+		"Не включается компьютер", "Замена картриджа", "Не работает кондиционер", "Протекает кран",
+		"Нет доступа к АБС", "Сбой кассового модуля", "Установка ПО", "Не работает телефон",
+		"Замена замка", "Пропуск для сотрудника", "Перегорела лампа", "Медленно работает почта",
+	}
+	demoComments = []string{
+		"Принято в работу", "Выехал на место", "Нужна запчасть, ожидаем поставку", "Уточните номер кабинета",
+		"Проблема воспроизводится", "Проверил, всё работает", "Передал смежному отделу", "Ждём подтверждения от заявителя",
+	}
+	demoAttachments = []struct{ Name, Type string }{
+		{"фото_проблемы.jpg", "image/jpeg"}, {"скриншот.png", "image/png"}, {"акт.pdf", "application/pdf"},
+	}
+)
+
+// demoPriority — код приоритета, доля заявок с ним в процентах и срок выполнения.
+type demoPriority struct {
+	Code   string
+	Weight int
+	SLA    time.Duration
+}
+
+var demoPriorities = []demoPriority{
+	{"LOW", 30, 7 * 24 * time.Hour},
+	{"MEDIUM", 45, 3 * 24 * time.Hour},
+	{"HIGH", 20, 24 * time.Hour},
+	{"CRITICAL", 5, 4 * time.Hour},
+}
+
+type demoUser struct {
+	ID           uint64
+	FIO          string
+	DepartmentID uint64
+	OtdelID      uint64
+}
+
+type demoOtdel struct {
+	ID           uint64
+	DepartmentID uint64
+}
+
+type demoDictionaries struct {
+	activeStatusID uint64
+	statuses       map[string]uint64
+	priorities     map[string]uint64
+	orderTypes     []uint64
+}
+
+// SeedDemo наполняет базу демо-данными: оргструктурой, пользователями и заявками с историей
+// за последние месяцы — для проверки производительности и дашбордов. Пользователей добавляется
+// столько, чтобы их стало opts.Users; заявки добавляются при каждом запуске.
+func SeedDemo(db *pgxpool.Pool, opts DemoOptions) {
+	ctx := context.Background()
+	log.Println("▶️  Запуск наполнения демо-данными...")
+	started := time.Now()
+	rnd := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+
+	dict, err := loadDemoDictionaries(ctx, db)
+	if err != nil {
+		log.Fatalf("❌ Ошибка чтения справочников (сначала запустите --core): %v", err)
+	}
+	otdels, err := seedDemoOrgStructure(ctx, db, dict)
+	if err != nil {
+		log.Fatalf("❌ Ошибка создания демо-оргструктуры: %v", err)
+	}
+	users, err := seedDemoUsers(ctx, db, dict, otdels, opts.Users, rnd)
+	if err != nil {
+		log.Fatalf("❌ Ошибка создания демо-пользователей: %v", err)
+	}
+	if err := writeDemoAttachmentFile(); err != nil {
+		log.Printf("    [!] Не удалось создать файл-заглушку вложений: %v", err)
+	}
+
+	from := time.Now().AddDate(0, -opts.Months, 0)
+	for done := 0; done < opts.Orders; done += demoOrderChunk {
+		n := min(demoOrderChunk, opts.Orders-done)
+		if err := seedDemoOrderChunk(ctx, db, dict, users, n, from, rnd); err != nil {
+			log.Fatalf("❌ Ошибка создания демо-заявок: %v", err)
+		}
+		log.Printf("    - Заявок создано: %d из %d", done+n, opts.Orders)
+	}
+
+	if opts.Orders > 0 {
+		if err := rebuildDemoDailyStats(ctx, db, from); err != nil {
+			log.Fatalf("❌ Ошибка пересчёта daily_order_stats: %v", err)
+		}
+	}
+	log.Printf("✅ Демо-данные готовы за %s. Пароль демо-пользователей: %s", time.Since(started).Round(time.Second), demoPassword)
+}
+
+func loadDemoDictionaries(ctx context.Context, db *pgxpool.Pool) (*demoDictionaries, error) {
+	dict := &demoDictionaries{statuses: map[string]uint64{}, priorities: map[string]uint64{}}
+
+	rows, err := db.Query(ctx, `SELECT code, id FROM statuses WHERE code IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	if err := collectCodeIDs(rows, dict.statuses); err != nil {
+		return nil, err
+	}
+	rows, err = db.Query(ctx, `SELECT code, id FROM priorities WHERE code IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	if err := collectCodeIDs(rows, dict.priorities); err != nil {
+		return nil, err
+	}
+
+	for _, code := range []string{"ACTIVE", constants.StatusOpen, constants.StatusInProgress, constants.StatusCompleted, constants.StatusClosed} {
+		if _, ok := dict.statuses[code]; !ok {
+			return nil, fmt.Errorf("нет статуса %s", code)
+		}
+	}
+	for _, p := range demoPriorities {
+		if _, ok := dict.priorities[p.Code]; !ok {
+			return nil, fmt.Errorf("нет приоритета %s", p.Code)
+		}
+	}
+	dict.activeStatusID = dict.statuses["ACTIVE"]
+
+	rows, err = db.Query(ctx, `SELECT id FROM order_types WHERE code IN ('EQUIPMENT', 'ADMINISTRATIVE') ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	if dict.orderTypes, err = pgx.CollectRows(rows, pgx.RowTo[uint64]); err != nil {
+		return nil, err
+	}
+	if len(dict.orderTypes) == 0 {
+		return nil, fmt.Errorf("нет типов заявок EQUIPMENT и ADMINISTRATIVE")
+	}
+	return dict, nil
+}
+
+func collectCodeIDs(rows pgx.Rows, into map[string]uint64) error {
+	defer rows.Close()
+	for rows.Next() {
+		var code string
+		var id uint64
+		if err := rows.Scan(&code, &id); err != nil {
+			return err
+		}
+		into[code] = id
+	}
+	return rows.Err()
+}
+
+// seedDemoOrgStructure создаёт демо-департаменты и отделы, если их ещё нет.
+func seedDemoOrgStructure(ctx context.Context, db *pgxpool.Pool, dict *demoDictionaries) ([]demoOtdel, error) {
+	log.Println("  - Демо-оргструктура...")
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var otdels []demoOtdel
+	for _, d := range demoDepartments {
+		var departmentID uint64
+		err := tx.QueryRow(ctx, `SELECT id FROM departments WHERE name = $1`, d.Name).Scan(&departmentID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = tx.QueryRow(ctx, `INSERT INTO departments (name, status_id, tenant_id) VALUES ($1, $2, 1) RETURNING id`,
+				d.Name, dict.activeStatusID).Scan(&departmentID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range d.Otdels {
+			var otdelID uint64
+			err := tx.QueryRow(ctx, `SELECT id FROM otdels WHERE name = $1 AND department_id = $2`, name, departmentID).Scan(&otdelID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = tx.QueryRow(ctx, `INSERT INTO otdels (name, status_id, department_id, tenant_id) VALUES ($1, $2, $3, 1) RETURNING id`,
+					name, dict.activeStatusID, departmentID).Scan(&otdelID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			otdels = append(otdels, demoOtdel{ID: otdelID, DepartmentID: departmentID})
+		}
+	}
+	return otdels, tx.Commit(ctx)
+}
+
+// seedDemoUsers добавляет демо-пользователей до total и возвращает всех демо-пользователей.
+func seedDemoUsers(ctx context.Context, db *pgxpool.Pool, dict *demoDictionaries, otdels []demoOtdel, total int, rnd *rand.Rand) ([]demoUser, error) {
+	log.Println("  - Демо-пользователи...")
+	var existing int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE email LIKE '%@' || $1`, demoEmailDomain).Scan(&existing); err != nil {
+		return nil, err
+	}
+
+	if missing := total - existing; missing > 0 {
+		hashedPassword, err := utils.HashPassword(demoPassword)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback(ctx)
+
+		ids, err := reserveIDs(ctx, tx, "users", missing)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([][]any, 0, missing)
+		for i, id := range ids {
+			n := existing + i + 1
+			otdel := otdels[n%len(otdels)]
+			fio := fmt.Sprintf("%s %s %s",
+				demoLastNames[rnd.IntN(len(demoLastNames))],
+				demoFirstNames[rnd.IntN(len(demoFirstNames))],
+				demoPatronyms[rnd.IntN(len(demoPatronyms))])
+			rows = append(rows, []any{
+				id, fio, fmt.Sprintf("demo.user%05d@%s", n, demoEmailDomain), fmt.Sprintf("99290%07d", n),
+				hashedPassword, dict.activeStatusID, otdel.DepartmentID, otdel.ID,
+				fmt.Sprintf("demo.user%05d", n), "LOCAL",
+			})
+		}
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"users"},
+			[]string{"id", "fio", "email", "phone_number", "password", "status_id", "department_id", "otdel_id", "username", "source_system"},
+			pgx.CopyFromRows(rows))
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO user_roles (user_id, role_id)
+			SELECT u.id, r.id FROM users u CROSS JOIN roles r
+			WHERE u.id = ANY($1) AND r.name IN ('Базовые привилегии', 'Создатель')
+			ON CONFLICT DO NOTHING`, ids)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		log.Printf("    - Добавлено пользователей: %d", missing)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, fio, department_id, otdel_id FROM users
+		WHERE email LIKE '%@' || $1 AND deleted_at IS NULL AND department_id IS NOT NULL AND otdel_id IS NOT NULL
+		ORDER BY id`, demoEmailDomain)
+	if err != nil {
+		return nil, err
+	}
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (demoUser, error) {
+		var u demoUser
+		return u, row.Scan(&u.ID, &u.FIO, &u.DepartmentID, &u.OtdelID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) < 2 {
+		return nil, fmt.Errorf("для заявок нужно хотя бы два демо-пользователя")
+	}
+	return users, nil
+}
+
+// reserveIDs забирает n значений из последовательности id таблицы, чтобы вставлять строки
+// через COPY и сразу ссылаться на них.
+func reserveIDs(ctx context.Context, tx pgx.Tx, table string, n int) ([]uint64, error) {
+	rows, err := tx.Query(ctx, `SELECT nextval(pg_get_serial_sequence($1, 'id')) FROM generate_series(1, $2)`, "public."+table, n)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uint64])
+}
+
+func writeDemoAttachmentFile() error {
+	path := filepath.Join("uploads", filepath.FromSlash(demoAttachmentPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte("Демо-вложение, создано сидером --demo.\n"), 0o644)
+}
+
+// demoHistory — события одной заявки по порядку; хэши считаются при вставке.
+type demoHistory []*repositories.OrderHistoryItem
+
+func (h *demoHistory) add(item repositories.OrderHistoryItem) {
+	*h = append(*h, &item)
+}
+
+func seedDemoOrderChunk(ctx context.Context, db *pgxpool.Pool, dict *demoDictionaries, users []demoUser, n int, from time.Time, rnd *rand.Rand) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	orderIDs, err := reserveIDs(ctx, tx, "orders", n)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	span := now.Sub(from)
+	var orderRows, attachmentRows, historyRows [][]any
+	for _, orderID := range orderIDs {
+		creator := users[rnd.IntN(len(users))]
+		executor := users[rnd.IntN(len(users))]
+		for executor.ID == creator.ID {
+			executor = users[rnd.IntN(len(users))]
+		}
+
+		createdAt := demoWorkingTime(from.Add(time.Duration(rnd.Int64N(int64(span)))), rnd)
+		if createdAt.After(now) {
+			createdAt = now.Add(-time.Duration(rnd.Int64N(int64(time.Hour))))
+		}
+		priority := demoPickPriority(rnd)
+		deadline := createdAt.Add(priority.SLA)
+		name := demoProblems[rnd.IntN(len(demoProblems))]
+		address := fmt.Sprintf("Офис №%d, каб. %d", 1+rnd.IntN(40), 100+rnd.IntN(400))
+
+		txID := uuid.New()
+		history := demoHistory{}
+		history.add(repositories.OrderHistoryItem{
+			UserID: creator.ID, EventType: "CREATE", NewValue: nullString(name),
+			CreatedAt: createdAt, TxID: &txID, CreatorFio: nullString(creator.FIO),
+		})
+		history.add(repositories.OrderHistoryItem{
+			UserID: creator.ID, EventType: "STATUS_CHANGE", NewValue: nullString(idString(dict.statuses[constants.StatusOpen])),
+			CreatedAt: createdAt, TxID: &txID, CreatorFio: nullString(creator.FIO),
+		})
+
+		// Назначение исполнителя и первый ответ — от минут до нескольких часов
+		assignedAt := createdAt.Add(time.Duration(5+rnd.IntN(240)) * time.Minute)
+		status := constants.StatusOpen
+		var firstResponse, resolution *uint64
+		var completedAt *time.Time
+		var fcr *bool
+
+		if assignedAt.Before(now) {
+			history.add(repositories.OrderHistoryItem{
+				UserID: creator.ID, EventType: "DELEGATION", NewValue: nullString(idString(executor.ID)),
+				Comment: nullString("Назначено на: " + executor.FIO), CreatedAt: assignedAt, TxID: &txID,
+				ExecutorFio: nullString(executor.FIO), DelegatorFio: nullString(creator.FIO),
+			})
+
+			// Около 15% заявок закрываются дольше срока
+			work := time.Duration(float64(priority.SLA) * (0.1 + rnd.Float64()*1.05))
+			resolvedAt := assignedAt.Add(work)
+			firstContact := rnd.IntN(5) == 0
+			respondedAt := assignedAt.Add(time.Duration(1+rnd.IntN(60)) * time.Minute)
+
+			if !firstContact && respondedAt.Before(now) {
+				status = constants.StatusInProgress
+				seconds := uint64(respondedAt.Sub(createdAt).Seconds())
+				firstResponse = &seconds
+				history.add(repositories.OrderHistoryItem{
+					UserID: executor.ID, EventType: "STATUS_CHANGE",
+					OldValue: nullString(idString(dict.statuses[constants.StatusOpen])), NewValue: nullString(idString(dict.statuses[constants.StatusInProgress])),
+					CreatedAt: respondedAt, TxID: &txID, CreatorFio: nullString(executor.FIO),
+				})
+				for c, comments := 0, rnd.IntN(4); c < comments; c++ {
+					at := respondedAt.Add(time.Duration(rnd.Int64N(int64(max(resolvedAt.Sub(respondedAt), time.Minute)))))
+					if at.After(now) {
+						break
+					}
+					author := executor
+					if rnd.IntN(3) == 0 {
+						author = creator
+					}
+					history.add(repositories.OrderHistoryItem{
+						UserID: author.ID, EventType: "COMMENT", Comment: nullString(demoComments[rnd.IntN(len(demoComments))]),
+						CreatedAt: at, TxID: &txID, CreatorFio: nullString(author.FIO),
+					})
+				}
+			}
+
+			if rnd.IntN(10) < 3 {
+				file := demoAttachments[rnd.IntN(len(demoAttachments))]
+				ids, err := reserveIDs(ctx, tx, "attachments", 1)
+				if err != nil {
+					return err
+				}
+				attachedAt := createdAt.Add(time.Duration(rnd.IntN(30)) * time.Second)
+				attachmentRows = append(attachmentRows, []any{
+					ids[0], orderID, creator.ID, file.Name, demoAttachmentPath, file.Type, int64(20_000 + rnd.IntN(2_000_000)), attachedAt,
+				})
+				history.add(repositories.OrderHistoryItem{
+					UserID: creator.ID, EventType: "ATTACHMENT_ADD", NewValue: nullString(file.Name),
+					AttachmentID: sql.NullInt64{Int64: int64(ids[0]), Valid: true},
+					CreatedAt:    attachedAt, TxID: &txID, CreatorFio: nullString(creator.FIO),
+				})
+			}
+
+			if resolvedAt.Before(now) {
+				previous := status
+				status = constants.StatusCompleted
+				seconds := uint64(resolvedAt.Sub(createdAt).Seconds())
+				resolution = &seconds
+				completedAt = &resolvedAt
+				isFCR := firstResponse == nil
+				fcr = &isFCR
+				history.add(repositories.OrderHistoryItem{
+					UserID: executor.ID, EventType: "STATUS_CHANGE",
+					OldValue: nullString(idString(dict.statuses[previous])), NewValue: nullString(idString(dict.statuses[status])),
+					CreatedAt: resolvedAt, TxID: &txID, CreatorFio: nullString(executor.FIO),
+				})
+
+				// Большинство выполненных заявок заявитель подтверждает и закрывает
+				closedAt := resolvedAt.Add(time.Duration(10+rnd.IntN(24*60)) * time.Minute)
+				if rnd.IntN(10) < 7 && closedAt.Before(now) {
+					status = constants.StatusClosed
+					history.add(repositories.OrderHistoryItem{
+						UserID: creator.ID, EventType: "STATUS_CHANGE",
+						OldValue: nullString(idString(dict.statuses[constants.StatusCompleted])), NewValue: nullString(idString(dict.statuses[status])),
+						CreatedAt: closedAt, TxID: &txID, CreatorFio: nullString(creator.FIO),
+					})
+				}
+			}
+		}
+
+		historyHash := demoHistoryRows(orderID, history, &historyRows)
+		updatedAt := history[len(history)-1].CreatedAt
+		orderRows = append(orderRows, []any{
+			orderID, name, address, executor.DepartmentID, executor.OtdelID, dict.orderTypes[rnd.IntN(len(dict.orderTypes))],
+			dict.statuses[status], dict.priorities[priority.Code], creator.ID, executor.ID, deadline,
+			createdAt, updatedAt, completedAt, resolution, firstResponse, fcr, historyHash,
+		})
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"orders"}, []string{
+		"id", "name", "address", "department_id", "otdel_id", "order_type_id",
+		"status_id", "priority_id", "user_id", "executor_id", "duration",
+		"created_at", "updated_at", "completed_at", "resolution_time_seconds", "first_response_time_seconds",
+		"is_first_contact_resolution", "history_hash",
+	}, pgx.CopyFromRows(orderRows)); err != nil {
+		return err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"attachments"}, []string{
+		"id", "order_id", "user_id", "file_name", "file_path", "file_type", "file_size", "created_at",
+	}, pgx.CopyFromRows(attachmentRows)); err != nil {
+		return err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"order_history"}, []string{
+		"order_id", "user_id", "event_type", "old_value", "new_value", "comment", "attachment_id",
+		"created_at", "tx_id", "creator_fio", "delegator_fio", "executor_fio", "prev_hash", "hash",
+	}, pgx.CopyFromRows(historyRows)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// demoHistoryRows сортирует события заявки по времени, выстраивает из них цепочку хэшей так же,
+// как OrderHistoryRepository.CreateInTx, и возвращает хэш последнего события.
+func demoHistoryRows(orderID uint64, history demoHistory, into *[][]any) string {
+	for i := 1; i < len(history); i++ {
+		for j := i; j > 0 && history[j].CreatedAt.Before(history[j-1].CreatedAt); j-- {
+			history[j], history[j-1] = history[j-1], history[j]
+		}
+	}
+
+	prevHash := sql.NullString{}
+	for _, item := range history {
+		item.OrderID = orderID
+		item.CreatedAt = item.CreatedAt.Truncate(time.Microsecond)
+		item.PrevHash = prevHash
+		item.Hash = sql.NullString{String: repositories.ComputeHistoryHash(prevHash.String, item), Valid: true}
+		*into = append(*into, []any{
+			item.OrderID, item.UserID, item.EventType, item.OldValue, item.NewValue, item.Comment, item.AttachmentID,
+			item.CreatedAt, item.TxID, item.CreatorFio, item.DelegatorFio, item.ExecutorFio, item.PrevHash, item.Hash,
+		})
+		prevHash = item.Hash
+	}
+	return prevHash.String
+}
+
+// demoWorkingTime сдвигает время в рабочие часы будней: так заявки распределены как настоящие.
+func demoWorkingTime(t time.Time, rnd *rand.Rand) time.Time {
+	t = t.In(time.Local)
+	for t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		t = t.AddDate(0, 0, -1)
+	}
+	if t.Hour() < 8 || t.Hour() >= 18 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 8+rnd.IntN(10), rnd.IntN(60), rnd.IntN(60), 0, time.Local)
+	}
+	return t
+}
+
+func demoPickPriority(rnd *rand.Rand) demoPriority {
+	roll := rnd.IntN(100)
+	for _, p := range demoPriorities {
+		if roll < p.Weight {
+			return p
+		}
+		roll -= p.Weight
+	}
+	return demoPriorities[len(demoPriorities)-1]
+}
+
+// rebuildDemoDailyStats пересчитывает daily_order_stats за период демо-заявок: иначе дашборд
+// по прошлым дням покажет их только после ночного пересчёта, и то за последние дни.
+func rebuildDemoDailyStats(ctx context.Context, db *pgxpool.Pool, from time.Time) error {
+	log.Println("  - Пересчёт daily_order_stats...")
+	repo := repositories.NewDailyOrderStatsRepository(db, zap.NewNop())
+	now := time.Now().In(time.Local)
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+	for day := from.In(time.Local); !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		if _, err := repo.RebuildDay(ctx, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: true}
+}
+
+func idString(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
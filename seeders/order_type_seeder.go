@@ -2,8 +2,10 @@ package seeders
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -36,12 +38,25 @@ func seedOrderTypes(ctx context.Context, db *pgxpool.Pool) error {
 	}
 
 	query := `INSERT INTO order_types (name, code, status_id) VALUES ($1, $2, $3) 
-			  ON CONFLICT (code) DO NOTHING`
+			  ON CONFLICT (code) DO NOTHING RETURNING id`
+	ruleQuery := `INSERT INTO order_type_validation_rules (order_type_id, field_name, error_message, on_create, on_update)
+			  VALUES ($1, $2, $3, TRUE, $4) ON CONFLICT (order_type_id, field_name) DO NOTHING`
 
 	for _, ot := range orderTypesData {
-		if _, err := tx.Exec(ctx, query, ot.Name, ot.Code, activeStatusID); err != nil {
+		var orderTypeID uint64
+		err := tx.QueryRow(ctx, query, ot.Name, ot.Code, activeStatusID).Scan(&orderTypeID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// тип уже есть: его правила мог изменить администратор
+			continue
+		}
+		if err != nil {
 			return err
 		}
+		for _, rule := range orderTypeValidationRulesData[ot.Code] {
+			if _, err := tx.Exec(ctx, ruleQuery, orderTypeID, rule.Field, rule.Message, rule.OnUpdate); err != nil {
+				return err
+			}
+		}
 	}
 
 	return tx.Commit(ctx)
@@ -7,7 +7,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// КЛЮЧИК: true - обновить имя/rate, если приоритет с таким CODE уже существует.
+// КЛЮЧИК: true - обновить имя/rate/оформление, если приоритет с таким CODE уже существует.
 // false - пропустить, если приоритет с таким CODE уже существует.
 const updateIfExists_Priorities = false
 
@@ -16,11 +16,12 @@ func seedPriorities(ctx context.Context, db *pgxpool.Pool) error {
 
 	var query string
 	if updateIfExists_Priorities {
-		query = `INSERT INTO priorities (name, rate, code) VALUES ($1, $2, $3) 
-				 ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, rate = EXCLUDED.rate;`
+		query = `INSERT INTO priorities (name, rate, code, color, sort_order, sla_hours) VALUES ($1, $2, $3, $4, $5, $6)
+				 ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, rate = EXCLUDED.rate, color = EXCLUDED.color,
+				 sort_order = EXCLUDED.sort_order, sla_hours = EXCLUDED.sla_hours;`
 		log.Println("    - Стратегия: Обновление существующих приоритетов (UPSERT)")
 	} else {
-		query = `INSERT INTO priorities (name, rate, code) VALUES ($1, $2, $3) 
+		query = `INSERT INTO priorities (name, rate, code, color, sort_order, sla_hours) VALUES ($1, $2, $3, $4, $5, $6)
 				 ON CONFLICT (code) DO NOTHING;`
 		log.Println("    - Стратегия: Пропуск существующих приоритетов (IGNORE)")
 	}
@@ -32,7 +33,18 @@ func seedPriorities(ctx context.Context, db *pgxpool.Pool) error {
 	defer tx.Rollback(ctx)
 
 	for _, s := range prioritiesData {
-		if _, err := tx.Exec(ctx, query, s.Name, s.Rate, s.Code); err != nil {
+		if _, err := tx.Exec(ctx, query, s.Name, s.Rate, s.Code, s.Color, s.SortOrder, s.SLAHours); err != nil {
+			return err
+		}
+	}
+
+	// Уже настроенные клетки матрицы не перезаписываются.
+	for _, m := range priorityMatrixData {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO priority_matrix (impact, urgency, priority_id)
+			 SELECT $1, $2, id FROM priorities WHERE code = $3
+			 ON CONFLICT (impact, urgency) DO NOTHING;`,
+			m.Impact, m.Urgency, m.PriorityCode); err != nil {
 			return err
 		}
 	}
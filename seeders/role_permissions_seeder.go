@@ -20,11 +20,11 @@ func getRolePermissionsMap() map[string][]string {
 		"Филиал | Контроль":          {"scope:branch", "order:update_in_branch_scope", "order:update:executor_id", "order:update:duration"},
 		"Создатель":                  {"order:create", "order:create:name", "order:create:address", "order:create:department_id", "order:create:otdel_id", "order:create:branch_id", "order:create:office_id", "order:create:equipment_id", "order:create:equipment_type_id", "order:create:priority_id", "order:create:file", "order:create:comment", "order:create:order_type_id"},
 		"Отдел | Контроль":           {"scope:otdel", "order:update_in_otdel_scope", "order:update:executor_id", "order:update:duration"},
-		"Базовые привилегии":         {"scope:own", "order:view", "order:update", "order:update:status_id", "order:update:comment", "order:update:file", "user:view", "profile:update", "password:update", "role:view", "permission:view", "status:view", "priority:view", "department:view", "otdel:view", "branch:view", "office:view", "equipment:view", "equipment_type:view", "order_type:view", "position:view", "order_rule:view", "dashboard:view"},
+		"Базовые привилегии":         {"scope:own", "order:view", "order:update", "order:update:status_id", "order:update:comment", "order:update:file", "user:view", "profile:update", "password:update", "role:view", "permission:view", "status:view", "priority:view", "department:view", "otdel:view", "branch:view", "office:view", "equipment:view", "equipment_type:view", "order_type:view", "position:view", "order_rule:view", "team:view", "on_call:view", "skill:view", "resolution_code:view", "dashboard:view"},
 		"Аудитор":                    {"scope:all_view"},
 		"Департамент | Контроль":     {"scope:department", "order:update_in_department_scope", "order:update:executor_id", "order:update:duration"},
-		"Администратор справочников": {"status:create", "status:update", "status:delete", "priority:create", "priority:update", "priority:delete", "department:create", "department:update", "department:delete", "otdel:create", "otdel:update", "otdel:delete", "branch:create", "branch:update", "branch:delete", "office:create", "office:update", "office:delete", "equipment:create", "equipment:update", "equipment:delete", "equipment_type:create", "equipment_type:update", "equipment_type:delete", "order_type:create", "order_type:update", "order_type:delete", "position:create", "position:update", "position:delete"},
-		"Администратор Системы":      {"scope:all", "role:create", "role:update", "role:delete", "permission:create", "permission:update", "permission:delete", "order_rule:create", "order_rule:update", "order_rule:delete", "integration:view", "integration:sync:run", "integration:update"},
+		"Администратор справочников": {"status:create", "status:update", "status:delete", "priority:create", "priority:update", "priority:delete", "department:create", "department:update", "department:delete", "otdel:create", "otdel:update", "otdel:delete", "branch:create", "branch:update", "branch:delete", "office:create", "office:update", "office:delete", "equipment:create", "equipment:update", "equipment:delete", "equipment_type:create", "equipment_type:update", "equipment_type:delete", "order_type:create", "order_type:update", "order_type:delete", "position:create", "position:update", "position:delete", "skill:manage", "resolution_code:manage"},
+		"Администратор Системы":      {"scope:all", "order:merge", "order:grant_access", "role:create", "role:update", "role:delete", "permission:create", "permission:update", "permission:delete", "permission:flush_cache", "order_rule:create", "order_rule:update", "order_rule:delete", "integration:view", "integration:sync:run", "integration:update", "notification:manage", "webhook:manage", "job:manage", "config:manage", "import:run", "event:replay", "audit:view", "analytics:read", "user:impersonate", "user:anonymize", "retention:manage", "backup:manage", "business_calendar:manage", "team:manage", "on_call:manage", "websocket:manage"},
 		"Управление доступом":        {"scope:all_view", "user:create", "user:update", "user:delete", "user:password:reset"},
 	}
 }
@@ -2,11 +2,9 @@ package seeders
 
 import (
 	"context"
-	"fmt"
 	"log"
 
 	"request-system/pkg/config"
-	"request-system/pkg/utils"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -39,51 +37,17 @@ func SeedSuperAdmin(db *pgxpool.Pool, cfg *config.Config) error {
 		return tx.Commit(ctx)
 	}
 
-	// 3. Получаем ID статуса 'ACTIVE'
-	var statusID uint64
-	err = tx.QueryRow(ctx, "SELECT id FROM statuses WHERE code = 'ACTIVE'").Scan(&statusID)
-	if err != nil {
-		return fmt.Errorf("сначала запустите наполнение статусов (-core)")
-	}
-
-	// 4. Хешируем пароль
-	hashedPassword, _ := utils.HashPassword(password)
-
-	// 5. Вставляем запись.
+	// 3. Создаём локального администратора с ролями администратора.
 	// ВАЖНО: username ставим 'admin_test' (можно взять часть email)
-	query := `
-		INSERT INTO users (
-			fio, email, phone_number, password,
-			status_id, must_change_password, source_system, username
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id
-	`
-	err = tx.QueryRow(ctx, query,
-		"Test Administrator",
-		email,
-		"992-000-TEST", // Заглушка номера
-		hashedPassword,
-		statusID,
-		true,         // Требуем сменить пароль при входе
-		"LOCAL",      // Пометка для БД, что он локальный
-		"admin_test", // Тот самый логин для входа
-	).Scan(&userID)
-
+	_, err = insertLocalAdmin(ctx, tx, LocalAdmin{
+		FIO:      "Test Administrator",
+		Email:    email,
+		Username: "admin_test",   // Тот самый логин для входа
+		Phone:    "992-000-TEST", // Заглушка номера
+		Password: password,
+	})
 	if err != nil {
-		return fmt.Errorf("ошибка SQL при создании admin_test: %w", err)
-	}
-
-	// 6. Даем права (роли)
-	roles := []string{"Базовые привилегии", "Администратор Системы"}
-	for _, rName := range roles {
-		_, err := tx.Exec(ctx, `
-			INSERT INTO user_roles (user_id, role_id)
-			SELECT $1, id FROM roles WHERE name = $2
-			ON CONFLICT DO NOTHING
-		`, userID, rName)
-		if err != nil {
-			log.Printf("      [!] Не удалось выдать роль %s: %v", rName, err)
-		}
+		return err
 	}
 
 	log.Printf("    ✅ УСПЕХ: Пользователь %s создан. Логин для входа: admin_test", email)
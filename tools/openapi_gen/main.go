@@ -0,0 +1,711 @@
+// openapi_gen собирает OpenAPI 3 спецификацию из аннотаций контроллеров.
+//
+// Аннотации пишутся в doc-комментарии обработчика (подмножество формата swag):
+//
+//	// @Summary     Список статусов
+//	// @Description Поддерживает фильтр и пагинацию (?withPagination=true).
+//	// @Tags        statuses
+//	// @Param       id   path  int                  true  "ID статуса"
+//	// @Param       body body  dto.CreateStatusDTO  true  "Данные статуса"
+//	// @Success     200  {list}   dto.StatusDTO
+//	// @Failure     404  "Статус не найден"
+//	// @Permission  status:view
+//	// @Security    BearerAuth
+//	// @Router      /status/{id} [get]
+//
+// @Router можно повторить, если обработчик висит на нескольких маршрутах. @Accept задаёт
+// MIME-типы тела (body), по умолчанию application/json; тело и formData можно сочетать.
+//
+// Виды ответа: {object}, {array}, {list} (body = {list, pagination}) — внутри конверта
+// {status, message, body}; {raw} — без конверта; {file} <mime> — двоичный файл.
+// Схемы строятся из структур пакетов dto, entities и types по тегам json/validate.
+// Запуск: go generate ./internal/apidocs
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaPackages — пакеты, на типы которых можно ссылаться в аннотациях.
+var schemaPackages = map[string]string{
+	"dto":      "internal/dto",
+	"entities": "internal/entities",
+	"types":    "pkg/types",
+}
+
+type schema = map[string]interface{}
+
+func main() {
+	root := flag.String("root", ".", "корень репозитория")
+	out := flag.String("out", "internal/apidocs/openapi.json", "куда записать спецификацию")
+	version := flag.String("version", "1.0", "версия API в info.version")
+	flag.Parse()
+
+	gen := newGenerator(*root)
+	for alias, dir := range schemaPackages {
+		if err := gen.indexTypes(alias, filepath.Join(*root, dir)); err != nil {
+			log.Fatalf("openapi_gen: %v", err)
+		}
+	}
+	if err := gen.parseControllers(filepath.Join(*root, "internal/controllers")); err != nil {
+		log.Fatalf("openapi_gen: %v", err)
+	}
+
+	data, err := json.MarshalIndent(gen.document(*version), "", "  ")
+	if err != nil {
+		log.Fatalf("openapi_gen: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("openapi_gen: %v", err)
+	}
+	log.Printf("openapi_gen: %d путей, %d схем -> %s", len(gen.paths), len(gen.components), *out)
+}
+
+type generator struct {
+	root       string
+	fset       *token.FileSet
+	types      map[string]map[string]*ast.TypeSpec
+	components map[string]schema
+	paths      map[string]map[string]schema
+}
+
+func newGenerator(root string) *generator {
+	return &generator{
+		root:       root,
+		fset:       token.NewFileSet(),
+		types:      make(map[string]map[string]*ast.TypeSpec),
+		components: make(map[string]schema),
+		paths:      make(map[string]map[string]schema),
+	}
+}
+
+func (g *generator) parseDir(dir string, mode parser.Mode) ([]*ast.File, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	files := make([]*ast.File, 0, len(matches))
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(g.fset, path, nil, mode)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (g *generator) indexTypes(alias, dir string) error {
+	files, err := g.parseDir(dir, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	index := make(map[string]*ast.TypeSpec)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.TypeParams == nil {
+					index[ts.Name.Name] = ts
+				}
+			}
+		}
+	}
+	g.types[alias] = index
+	return nil
+}
+
+// ---- Операции ----
+
+var (
+	routerRe  = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+	paramRe   = regexp.MustCompile(`^(\S+)\s+(path|query|header|body|formData)\s+(\S+)\s+(true|false)(?:\s+"(.*)")?$`)
+	successRe = regexp.MustCompile(`^(\d{3})(?:\s+\{(\w+)\}\s*(\S+)?)?(?:\s+"(.*)")?$`)
+)
+
+func (g *generator) parseControllers(dir string) error {
+	files, err := g.parseDir(dir, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			if err := g.parseOperation(fn); err != nil {
+				return fmt.Errorf("%s: %s: %w", g.fset.Position(fn.Pos()), fn.Name.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) parseOperation(fn *ast.FuncDecl) error {
+	op := schema{}
+	var (
+		routes       [][2]string
+		accept       = []string{"application/json"}
+		bodySchema   schema
+		description  []string
+		permissions  []string
+		parameters   []interface{}
+		security     []interface{}
+		formProps    = schema{}
+		formRequired []string
+		responses    = schema{}
+	)
+
+	for _, comment := range fn.Doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		tag, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		switch tag {
+		case "@Summary":
+			op["summary"] = rest
+		case "@Description":
+			description = append(description, rest)
+		case "@Tags":
+			op["tags"] = splitTrim(rest)
+		case "@Permission":
+			permissions = append(permissions, splitTrim(rest)...)
+		case "@Security":
+			if rest != "none" {
+				security = append(security, map[string][]string{rest: {}})
+			} else {
+				security = []interface{}{}
+			}
+		case "@Router":
+			m := routerRe.FindStringSubmatch(rest)
+			if m == nil {
+				return fmt.Errorf("некорректный @Router %q", rest)
+			}
+			routes = append(routes, [2]string{m[1], strings.ToLower(m[2])})
+		case "@Accept":
+			accept = splitTrim(rest)
+		case "@Param":
+			m := paramRe.FindStringSubmatch(rest)
+			if m == nil {
+				return fmt.Errorf("некорректный @Param %q", rest)
+			}
+			name, in, typ, required, desc := m[1], m[2], m[3], m[4] == "true", m[5]
+			switch in {
+			case "body":
+				s, err := g.annotationSchema(typ)
+				if err != nil {
+					return err
+				}
+				bodySchema = schema{"schema": s}
+				op["requestBody"] = schema{"required": required, "description": desc}
+			case "formData":
+				s, err := g.annotationSchema(typ)
+				if err != nil {
+					return err
+				}
+				if desc != "" {
+					s = withDescription(s, desc)
+				}
+				formProps[name] = s
+				if required {
+					formRequired = append(formRequired, name)
+				}
+			default:
+				s, err := g.annotationSchema(typ)
+				if err != nil {
+					return err
+				}
+				param := schema{"name": name, "in": in, "required": required || in == "path", "schema": s}
+				if desc != "" {
+					param["description"] = desc
+				}
+				parameters = append(parameters, param)
+			}
+		case "@Success", "@Failure":
+			m := successRe.FindStringSubmatch(rest)
+			if m == nil {
+				return fmt.Errorf("некорректный %s %q", tag, rest)
+			}
+			resp, err := g.response(tag == "@Success", m[1], m[2], m[3], m[4])
+			if err != nil {
+				return err
+			}
+			responses[m[1]] = resp
+		default:
+			return fmt.Errorf("неизвестная аннотация %s", tag)
+		}
+	}
+
+	if len(routes) == 0 {
+		if len(op) > 0 || len(responses) > 0 {
+			return fmt.Errorf("нет @Router")
+		}
+		return nil
+	}
+
+	if len(permissions) > 0 {
+		description = append(description, "Права: `"+strings.Join(permissions, "`, `")+"`.")
+		op["x-permissions"] = permissions
+	}
+	if len(description) > 0 {
+		op["description"] = strings.Join(description, "\n\n")
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+	content := schema{}
+	if bodySchema != nil {
+		for _, mimeType := range accept {
+			content[mimeType] = bodySchema
+		}
+	}
+	if len(formProps) > 0 {
+		form := schema{"type": "object", "properties": formProps}
+		if len(formRequired) > 0 {
+			form["required"] = formRequired
+		}
+		content["multipart/form-data"] = schema{"schema": form}
+	}
+	if len(content) > 0 {
+		body, _ := op["requestBody"].(schema)
+		if body == nil {
+			body = schema{}
+		}
+		body["content"] = content
+		op["requestBody"] = body
+	}
+	if security != nil {
+		op["security"] = security
+	}
+	if len(responses) == 0 {
+		responses["200"] = schema{"description": "OK"}
+	}
+	op["responses"] = responses
+
+	for i, route := range routes {
+		path, method := route[0], route[1]
+		routeOp := make(schema, len(op)+1)
+		for key, value := range op {
+			routeOp[key] = value
+		}
+		// operationId должен быть уникальным: у второго и следующих маршрутов к имени добавляется метод.
+		routeOp["operationId"] = fn.Name.Name
+		if i > 0 {
+			routeOp["operationId"] = fn.Name.Name + strings.ToUpper(method[:1]) + method[1:]
+		}
+
+		if g.paths[path] == nil {
+			g.paths[path] = make(map[string]schema)
+		}
+		if _, exists := g.paths[path][method]; exists {
+			return fmt.Errorf("операция %s %s описана дважды", method, path)
+		}
+		g.paths[path][method] = routeOp
+	}
+	return nil
+}
+
+// response оборачивает полезную нагрузку в общий конверт {status, message, body}.
+func (g *generator) response(success bool, code, kind, typ, desc string) (schema, error) {
+	if desc == "" {
+		desc = "OK"
+		if !success {
+			desc = "Ошибка"
+		}
+	}
+	resp := schema{"description": desc}
+	if !success {
+		resp["content"] = schema{"application/json": schema{"schema": schema{"$ref": "#/components/schemas/ErrorResponse"}}}
+		return resp, nil
+	}
+
+	var body schema
+	switch kind {
+	case "":
+		return resp, nil
+	case "file":
+		mime := typ
+		if mime == "" {
+			mime = "application/octet-stream"
+		}
+		resp["content"] = schema{mime: schema{"schema": schema{"type": "string", "format": "binary"}}}
+		return resp, nil
+	case "object", "array", "list", "raw":
+		s, err := g.annotationSchema(typ)
+		if err != nil {
+			return nil, err
+		}
+		body = s
+		switch kind {
+		case "array":
+			body = schema{"type": "array", "items": s}
+		case "list":
+			body = schema{
+				"type": "object",
+				"properties": schema{
+					"list":       schema{"type": "array", "items": s},
+					"pagination": schema{"$ref": "#/components/schemas/Pagination"},
+				},
+			}
+		}
+		if kind == "raw" {
+			resp["content"] = schema{"application/json": schema{"schema": body}}
+			return resp, nil
+		}
+	default:
+		return nil, fmt.Errorf("неизвестный вид ответа {%s}", kind)
+	}
+
+	envelope := schema{
+		"type": "object",
+		"properties": schema{
+			"status":  schema{"type": "boolean"},
+			"message": schema{"type": "string"},
+			"body":    body,
+		},
+	}
+	resp["content"] = schema{"application/json": schema{"schema": envelope}}
+	return resp, nil
+}
+
+func (g *generator) annotationSchema(typ string) (schema, error) {
+	switch typ {
+	case "", "object":
+		return schema{"type": "object"}, nil
+	case "string":
+		return schema{"type": "string"}, nil
+	case "int", "integer", "uint64", "int64":
+		return schema{"type": "integer", "format": "int64"}, nil
+	case "number":
+		return schema{"type": "number"}, nil
+	case "bool", "boolean":
+		return schema{"type": "boolean"}, nil
+	case "file":
+		return schema{"type": "string", "format": "binary"}, nil
+	case "date":
+		return schema{"type": "string", "format": "date"}, nil
+	}
+	pkg, name, ok := strings.Cut(typ, ".")
+	if !ok || g.types[pkg] == nil || g.types[pkg][name] == nil {
+		return nil, fmt.Errorf("неизвестный тип %q", typ)
+	}
+	return g.ref(pkg, name), nil
+}
+
+// ---- Схемы ----
+
+func (g *generator) ref(pkg, name string) schema {
+	key := pkg + "." + name
+	if _, ok := g.components[key]; !ok {
+		g.components[key] = schema{} // защита от рекурсии
+		spec := g.types[pkg][name]
+		s := g.schemaFor(pkg, spec.Type)
+		if spec.Doc != nil {
+			s = withDescription(s, strings.TrimSpace(spec.Doc.Text()))
+		}
+		g.components[key] = s
+	}
+	return schema{"$ref": "#/components/schemas/" + key}
+}
+
+func (g *generator) schemaFor(pkg string, expr ast.Expr) schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if s, ok := builtinSchema(t.Name); ok {
+			return s
+		}
+		if g.types[pkg][t.Name] != nil {
+			return g.ref(pkg, t.Name)
+		}
+		return schema{}
+	case *ast.StarExpr:
+		s := g.schemaFor(pkg, t.X)
+		if _, isRef := s["$ref"]; !isRef && len(s) > 0 {
+			s["nullable"] = true
+		}
+		return s
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" && t.Len == nil {
+			return schema{"type": "string", "format": "byte"}
+		}
+		return schema{"type": "array", "items": g.schemaFor(pkg, t.Elt)}
+	case *ast.MapType:
+		return schema{"type": "object", "additionalProperties": g.schemaFor(pkg, t.Value)}
+	case *ast.StructType:
+		return g.structSchema(pkg, t)
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if s, ok := externalSchema(x.Name + "." + t.Sel.Name); ok {
+				return s
+			}
+			if g.types[x.Name][t.Sel.Name] != nil {
+				return g.ref(x.Name, t.Sel.Name)
+			}
+		}
+		return schema{}
+	default:
+		return schema{}
+	}
+}
+
+func (g *generator) structSchema(pkg string, st *ast.StructType) schema {
+	props := schema{}
+	var required []string
+	g.collectFields(pkg, st, props, &required)
+
+	s := schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+func (g *generator) collectFields(pkg string, st *ast.StructType, props schema, required *[]string) {
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			if raw, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(raw)
+			}
+		}
+		jsonName, jsonOpts, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" && jsonOpts == "" {
+			continue
+		}
+
+		// Встроенная структура без json-имени раскрывается в поля родителя.
+		if len(field.Names) == 0 && jsonName == "" {
+			if embPkg, embSpec := g.resolveEmbedded(pkg, field.Type); embSpec != nil {
+				if embStruct, ok := embSpec.Type.(*ast.StructType); ok {
+					g.collectFields(embPkg, embStruct, props, required)
+				}
+			}
+			continue
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			if name.IsExported() {
+				names = append(names, name.Name)
+			}
+		}
+		if len(field.Names) == 0 {
+			names = append(names, jsonName)
+		}
+
+		for _, goName := range names {
+			name := jsonName
+			if name == "" {
+				name = goName
+			}
+			s := g.schemaFor(pkg, field.Type)
+			if strings.Contains(jsonOpts, "string") {
+				s = schema{"type": "string"}
+			}
+			validate := tag.Get("validate")
+			if enum := oneOf(validate); len(enum) > 0 {
+				if _, isRef := s["$ref"]; !isRef {
+					s["enum"] = enum
+				}
+			}
+			if doc := fieldDoc(field); doc != "" {
+				s = withDescription(s, doc)
+			}
+			props[name] = s
+			if hasRule(validate, "required") {
+				*required = append(*required, name)
+			}
+		}
+	}
+}
+
+func (g *generator) resolveEmbedded(pkg string, expr ast.Expr) (string, *ast.TypeSpec) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return g.resolveEmbedded(pkg, t.X)
+	case *ast.Ident:
+		return pkg, g.types[pkg][t.Name]
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return x.Name, g.types[x.Name][t.Sel.Name]
+		}
+	}
+	return "", nil
+}
+
+func (g *generator) document(version string) schema {
+	components := schema{
+		"Pagination": schema{
+			"type": "object",
+			"properties": schema{
+				"total_count": schema{"type": "integer", "format": "int64"},
+				"total_pages": schema{"type": "integer"},
+				"page":        schema{"type": "integer"},
+				"limit":       schema{"type": "integer"},
+			},
+		},
+		"ErrorResponse": schema{
+			"type": "object",
+			"properties": schema{
+				"status":  schema{"type": "boolean", "example": false},
+				"message": schema{"type": "string"},
+				"body":    schema{"description": "Подробности ошибки, если есть"},
+			},
+		},
+	}
+	for key, s := range g.components {
+		components[key] = s
+	}
+
+	paths := schema{}
+	for path, ops := range g.paths {
+		item := schema{}
+		for method, op := range ops {
+			item[method] = op
+		}
+		paths[path] = item
+	}
+
+	return schema{
+		"openapi": "3.0.3",
+		"info": schema{
+			"title":       "Request System API",
+			"version":     version,
+			"description": "Сгенерировано tools/openapi_gen из аннотаций контроллеров. Все ответы обёрнуты в {status, message, body}.",
+		},
+		"servers":  []schema{{"url": "/api"}},
+		"security": []schema{{"BearerAuth": []string{}}},
+		"paths":    paths,
+		"components": schema{
+			"schemas": components,
+			"securitySchemes": schema{
+				"BearerAuth": schema{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"ApiKeyAuth": schema{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"OneCKeyAuth": schema{
+					"type": "http", "scheme": "bearer",
+					"description": "Ключ ONE_C_API_KEY в заголовке Authorization: Bearer <ключ>",
+				},
+			},
+		},
+	}
+}
+
+// ---- Вспомогательные ----
+
+func builtinSchema(name string) (schema, bool) {
+	switch name {
+	case "string":
+		return schema{"type": "string"}, true
+	case "bool":
+		return schema{"type": "boolean"}, true
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return schema{"type": "integer", "format": "int32"}, true
+	case "int64", "uint64":
+		return schema{"type": "integer", "format": "int64"}, true
+	case "float32", "float64":
+		return schema{"type": "number"}, true
+	case "any", "interface{}":
+		return schema{}, true
+	}
+	return nil, false
+}
+
+func externalSchema(name string) (schema, bool) {
+	switch name {
+	case "time.Time", "null.Time", "sql.NullTime":
+		s := schema{"type": "string", "format": "date-time"}
+		if name != "time.Time" {
+			s["nullable"] = true
+		}
+		return s, true
+	case "time.Duration":
+		return schema{"type": "integer", "format": "int64", "description": "Наносекунды"}, true
+	case "null.String", "sql.NullString":
+		return schema{"type": "string", "nullable": true}, true
+	case "null.Int", "null.Int64", "null.Uint64", "sql.NullInt64", "sql.NullInt32":
+		return schema{"type": "integer", "nullable": true}, true
+	case "null.Bool", "sql.NullBool":
+		return schema{"type": "boolean", "nullable": true}, true
+	case "null.Float64", "sql.NullFloat64":
+		return schema{"type": "number", "nullable": true}, true
+	case "json.RawMessage":
+		return schema{}, true
+	case "multipart.FileHeader":
+		return schema{"type": "string", "format": "binary"}, true
+	}
+	return nil, false
+}
+
+func withDescription(s schema, desc string) schema {
+	if desc == "" {
+		return s
+	}
+	if _, isRef := s["$ref"]; isRef {
+		// В OpenAPI 3.0 соседние с $ref ключи игнорируются.
+		return schema{"allOf": []schema{s}, "description": desc}
+	}
+	s["description"] = desc
+	return s
+}
+
+func fieldDoc(field *ast.Field) string {
+	for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if group != nil {
+			return strings.TrimSpace(group.Text())
+		}
+	}
+	return ""
+}
+
+func hasRule(validate, rule string) bool {
+	for _, part := range strings.Split(validate, ",") {
+		if part == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func oneOf(validate string) []string {
+	for _, part := range strings.Split(validate, ",") {
+		if values, ok := strings.CutPrefix(part, "oneof="); ok {
+			return strings.Fields(values)
+		}
+	}
+	return nil
+}
+
+func splitTrim(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}